@@ -0,0 +1,205 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+
+	"github.com/kazemisoroush/assistant/pkg/agent"
+	"github.com/kazemisoroush/assistant/pkg/ai"
+	"github.com/kazemisoroush/assistant/pkg/config"
+	"github.com/kazemisoroush/assistant/pkg/records/discovery"
+	"github.com/kazemisoroush/assistant/pkg/records/knowledgebase"
+	"github.com/kazemisoroush/assistant/pkg/records/storage"
+	"github.com/kazemisoroush/assistant/pkg/redact"
+	"github.com/kazemisoroush/assistant/pkg/sqlite"
+	"github.com/kazemisoroush/assistant/pkg/tokens"
+)
+
+// newAIProvider builds the provider chain: the default provider first, then the
+// configured fallbacks. Responses are cached in the database of the cache
+// path. The returned function releases the trace file.
+func newAIProvider(cfg config.Config, httpClient *http.Client, usage ai.UsageStore, open func(path string) (*sqlite.DB, error)) (ai.Provider, func(), error) {
+	awsConfig := bedrockAWSConfig(cfg)
+
+	var llmCache ai.Cache
+	if cfg.AI.Cache.Enabled {
+		db, err := open(cfg.AI.Cache.Path)
+		if err != nil {
+			return nil, nil, err
+		}
+		sqliteCache, err := ai.NewSQLiteCache(db, cfg.AI.Cache.TTL, cfg.AI.Cache.MaxEntries)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to initialize LLM cache: %w", err)
+		}
+		llmCache = sqliteCache
+	}
+
+	routes, err := taskRoutes(cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	provider, err := ai.NewProviderChain(append([]string{cfg.AI.DefaultProvider}, cfg.AI.FallbackProviders...), ai.Config{
+		HTTPClient: httpClient,
+		AWSConfig:  awsConfig,
+		Ollama:     ai.ModelConfig{URL: cfg.AI.Ollama.URL, Model: cfg.AI.Ollama.Model, EmbeddingModel: cfg.AI.Embeddings.Model},
+		Bedrock:    ai.ModelConfig{Model: cfg.AI.Bedrock.FoundationModel, EmbeddingModel: cfg.AI.Embeddings.Model},
+		OpenAI:     ai.ModelConfig{URL: cfg.AI.OpenAI.URL, APIKey: cfg.AI.OpenAI.APIKey, Model: cfg.AI.OpenAI.Model, EmbeddingModel: cfg.AI.Embeddings.Model},
+		Anthropic:  ai.ModelConfig{URL: cfg.AI.Anthropic.URL, APIKey: cfg.AI.Anthropic.APIKey, Model: cfg.AI.Anthropic.Model, MaxTokens: cfg.AI.Anthropic.MaxTokens},
+		LlamaCpp: ai.LlamaCppConfig{
+			ModelPath:          cfg.AI.LlamaCpp.ModelPath,
+			EmbeddingModelPath: cfg.AI.LlamaCpp.EmbeddingModelPath,
+			ContextSize:        cfg.AI.LlamaCpp.ContextSize,
+			Threads:            cfg.AI.LlamaCpp.Threads,
+			GPULayers:          cfg.AI.LlamaCpp.GPULayers,
+			MaxTokens:          cfg.AI.LlamaCpp.MaxTokens,
+		},
+		Retry:      ai.RetryConfig{MaxAttempts: cfg.AI.Retry.MaxAttempts, BaseDelay: cfg.AI.Retry.BaseDelay, MaxDelay: cfg.AI.Retry.MaxDelay},
+		Cache:      llmCache,
+		Usage:      usage,
+		Breaker:    breakerConfig(cfg),
+		Guardrails: guardrails(cfg),
+		Routes:     routes,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return withTracing(cfg, provider)
+}
+
+// withTracing wraps the provider so LLM interactions are logged and, when a
+// trace path is configured, appended to the trace file for replay
+func withTracing(cfg config.Config, provider ai.Provider) (ai.Provider, func(), error) {
+	if !cfg.AI.Trace.Enabled {
+		return provider, func() {}, nil
+	}
+
+	traceCfg := ai.TraceConfig{
+		Redactor: redact.NewPatternRedactor(),
+		MaxChars: cfg.AI.Trace.MaxChars,
+	}
+	if cfg.AI.Trace.Path == "" {
+		return ai.NewTracingProvider(provider, traceCfg), func() {}, nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(cfg.AI.Trace.Path), 0755); err != nil {
+		return nil, nil, fmt.Errorf("failed to create trace directory: %w", err)
+	}
+	file, err := os.OpenFile(cfg.AI.Trace.Path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open trace file: %w", err)
+	}
+	traceCfg.Recorder = file
+	return ai.NewTracingProvider(provider, traceCfg), func() { _ = file.Close() }, nil
+}
+
+// guardrails returns the PII redaction and record type allowlists applied to cloud providers
+func guardrails(cfg config.Config) ai.GuardrailConfig {
+	var redactor redact.Redactor
+	if cfg.AI.RedactPII {
+		redactor = redact.NewPatternRedactor()
+	}
+	return ai.GuardrailConfig{
+		Redactor: redactor,
+		AllowedRecordTypes: map[string][]string{
+			ai.ProviderBedrock:   cfg.AI.Bedrock.AllowedRecordTypes,
+			ai.ProviderOpenAI:    cfg.AI.OpenAI.AllowedRecordTypes,
+			ai.ProviderAnthropic: cfg.AI.Anthropic.AllowedRecordTypes,
+		},
+	}
+}
+
+// taskRoutes parses the per-task provider and model configuration
+func taskRoutes(cfg config.Config) (map[string]ai.Route, error) {
+	routes := make(map[string]ai.Route, len(cfg.AI.TaskModels))
+	for task, spec := range cfg.AI.TaskModels {
+		route, err := ai.ParseRoute(spec)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse model for task %s: %w", task, err)
+		}
+		routes[task] = route
+	}
+	return routes, nil
+}
+
+// newUsageStore creates the AI usage store in the database of the usage path,
+// or returns nil when usage tracking is disabled
+func newUsageStore(cfg config.Config, open func(path string) (*sqlite.DB, error)) (ai.UsageStore, error) {
+	if !cfg.AI.Usage.Enabled {
+		return nil, nil
+	}
+	db, err := open(cfg.AI.Usage.Path)
+	if err != nil {
+		return nil, err
+	}
+	store, err := ai.NewSQLiteUsageStore(db)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize AI usage store: %w", err)
+	}
+	return store, nil
+}
+
+// newBudgeter sizes the prompt budget for the default model
+func newBudgeter(cfg config.Config) *tokens.Budgeter {
+	contextWindow := cfg.AI.ContextWindow
+	if contextWindow == 0 {
+		contextWindow = tokens.ContextWindow(cfg.AI.DefaultModel())
+	}
+	return tokens.NewBudgeter(contextWindow, cfg.AI.PromptReserveTokens)
+}
+
+// newAgent builds the Bedrock agent with the records action group, or returns
+// nil when no agent service role is configured
+func newAgent(cfg config.Config, recordDiscovery discovery.Discovery, recordStorage storage.Storage) agent.Agent {
+	if cfg.AI.Bedrock.AgentServiceRoleARN == "" {
+		return nil
+	}
+	client := agent.NewInlineAgentClient(bedrockAWSConfig(cfg), cfg.AI.Bedrock.AgentServiceRoleARN)
+	return agent.NewBedrockAgent(client, cfg.AI.Bedrock.FoundationModel, agent.NewRecordsActionGroup(recordDiscovery, recordStorage))
+}
+
+// withAWSConfig loads the AWS configuration into cfg when Bedrock is used
+func withAWSConfig(ctx context.Context, cfg config.Config) (config.Config, error) {
+	if !usesBedrock(cfg) {
+		return cfg, nil
+	}
+	awsConfig, err := config.LoadAWSConfig(ctx)
+	if err != nil {
+		return cfg, err
+	}
+	cfg.AWSConfig = awsConfig
+	return cfg, nil
+}
+
+// usesBedrock reports whether a provider, task route, the agent or the
+// embedder calls Bedrock
+func usesBedrock(cfg config.Config) bool {
+	if cfg.AI.Bedrock.AgentServiceRoleARN != "" || cfg.AI.Embeddings.Provider == knowledgebase.EmbedderProviderBedrock {
+		return true
+	}
+	if slices.Contains(append([]string{cfg.AI.DefaultProvider}, cfg.AI.FallbackProviders...), ai.ProviderBedrock) {
+		return true
+	}
+	for _, spec := range cfg.AI.TaskModels {
+		if route, err := ai.ParseRoute(spec); err == nil && route.Provider == ai.ProviderBedrock {
+			return true
+		}
+	}
+	return false
+}
+
+// bedrockAWSConfig returns the AWS configuration with the Bedrock region override applied
+func bedrockAWSConfig(cfg config.Config) aws.Config {
+	awsConfig := cfg.AWSConfig
+	if cfg.AI.Bedrock.Region != "" {
+		awsConfig.Region = cfg.AI.Bedrock.Region
+	}
+	return awsConfig
+}
@@ -0,0 +1,1137 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"expvar"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/bedrockruntime"
+	"github.com/kazemisoroush/assistant/pkg/agent"
+	"github.com/kazemisoroush/assistant/pkg/ai"
+	"github.com/kazemisoroush/assistant/pkg/analytics"
+	"github.com/kazemisoroush/assistant/pkg/answerer"
+	"github.com/kazemisoroush/assistant/pkg/breaker"
+	"github.com/kazemisoroush/assistant/pkg/budgets"
+	"github.com/kazemisoroush/assistant/pkg/calendar"
+	"github.com/kazemisoroush/assistant/pkg/claims"
+	"github.com/kazemisoroush/assistant/pkg/config"
+	"github.com/kazemisoroush/assistant/pkg/dashboard"
+	"github.com/kazemisoroush/assistant/pkg/digest"
+	"github.com/kazemisoroush/assistant/pkg/duplicates"
+	"github.com/kazemisoroush/assistant/pkg/entities"
+	"github.com/kazemisoroush/assistant/pkg/gdrive"
+	"github.com/kazemisoroush/assistant/pkg/health"
+	"github.com/kazemisoroush/assistant/pkg/household"
+	"github.com/kazemisoroush/assistant/pkg/httpclient"
+	"github.com/kazemisoroush/assistant/pkg/notifications"
+	"github.com/kazemisoroush/assistant/pkg/obsidian"
+	"github.com/kazemisoroush/assistant/pkg/peersync"
+	"github.com/kazemisoroush/assistant/pkg/plugins"
+	"github.com/kazemisoroush/assistant/pkg/prompts"
+	"github.com/kazemisoroush/assistant/pkg/records/checkpoint"
+	"github.com/kazemisoroush/assistant/pkg/records/chunker"
+	"github.com/kazemisoroush/assistant/pkg/records/discovery"
+	"github.com/kazemisoroush/assistant/pkg/records/extractor"
+	"github.com/kazemisoroush/assistant/pkg/records/ingestor"
+	"github.com/kazemisoroush/assistant/pkg/records/knowledgebase"
+	"github.com/kazemisoroush/assistant/pkg/records/ledger"
+	"github.com/kazemisoroush/assistant/pkg/records/pipeline"
+	"github.com/kazemisoroush/assistant/pkg/records/source"
+	"github.com/kazemisoroush/assistant/pkg/records/storage"
+	"github.com/kazemisoroush/assistant/pkg/records/typestore"
+	"github.com/kazemisoroush/assistant/pkg/relations"
+	"github.com/kazemisoroush/assistant/pkg/reminders"
+	"github.com/kazemisoroush/assistant/pkg/slack"
+	"github.com/kazemisoroush/assistant/pkg/sqlite"
+	"github.com/kazemisoroush/assistant/pkg/summaries"
+	"github.com/kazemisoroush/assistant/pkg/taxreport"
+	"github.com/kazemisoroush/assistant/pkg/telegram"
+	"github.com/kazemisoroush/assistant/pkg/thumbnails"
+	"github.com/kazemisoroush/assistant/pkg/trips"
+	"github.com/kazemisoroush/assistant/pkg/vehicles"
+	"github.com/kazemisoroush/assistant/pkg/warranty"
+	"github.com/kazemisoroush/assistant/pkg/webdav"
+)
+
+// app wires the services used by the CLI commands. Every service is built on
+// first use by its getter, which returns the same service on every call, so a
+// command builds only the services it uses: reading records opens the
+// records database alone, without discovering plugins or building the AI
+// providers.
+type app struct {
+	storage       func() (storage.Storage, error)
+	vectorStorage func() (knowledgebase.VectorStorage, error)
+	ingestor      func() (ingestor.Ingestor, error)
+	summarizer    func() (summaries.Summarizer, error)
+	sources       func() ([]source.Source, error)
+	watch         func() (source.Source, error)
+	extractor     func() (extractor.ContentExtractor, error)
+	thumbnails    func() (thumbnails.Store, error) // nil unless thumbnails are enabled
+	checkpoints   func() (*checkpoint.SQLiteStore, error)
+	discovery     func() (discovery.Discovery, error)
+	answerer      func() (answerer.Answerer, error)
+	chat          func() (*answerer.Chat, error)
+	agent         func() (agent.Agent, error)   // nil when no agent service role is configured
+	usage         func() (ai.UsageStore, error) // nil when usage tracking is disabled
+	notifier      func() (notifications.Notifier, error)
+	reminders     func() (reminders.Engine, error)
+	analytics     func() (analytics.Analyzer, error)
+	recurring     func() (analytics.SubscriptionDetector, error)
+	health        func() (health.TimelineBuilder, error)
+	warranty      func() (warranty.Checker, error)
+	entities      func() (entities.Browser, error)
+	claims        func() (claims.Matcher, error)
+	trips         func() (trips.Clusterer, error)
+	budgets       func() (budgets.Checker, error)
+	budgetStore   func() (*budgets.SQLiteStore, error)
+	household     func() (*household.SQLiteStore, error)
+	relations     func() (relations.Service, error)
+	vehicles      func() (vehicles.Tracker, error)
+	dashboard     func() (dashboard.Builder, error)
+	digest        func() (digest.Composer, error)
+	calendar      func() (calendar.Builder, error)
+
+	duplicates func() (duplicates.Detector, error)
+	merger     func() (duplicates.Merger, error)
+	types      func() (*typestore.SQLiteStore, error)
+
+	taxReport    func() (taxreport.Generator, error)
+	taxReportDir string
+
+	obsidian         func() (obsidian.Exporter, error)
+	obsidianVaultDir string
+
+	slack     func() (*slack.Handler, error) // nil unless the Slack bot token and signing secret are set
+	slackAddr string
+	telegram  func() (*telegram.Bot, error) // nil unless the Telegram bot token and allowed chats are set
+
+	sync func() (*syncService, error) // nil unless the sync token is set
+
+	buffers source.Buffers
+	ingest  pipeline.StageConfig
+	batch   ingestor.BatchConfig
+
+	api           config.APIConfig
+	calendarToken string
+
+	// Services the services above are built from
+	configWithAWS func() (config.Config, error) // configuration with the AWS configuration loaded when Bedrock is used
+	httpClient    func() (*http.Client, error)
+	recordsDB     func() (*sqlite.DB, error)
+	provider      func() (ai.Provider, error)
+	prompts       func() (*prompts.Registry, error)
+	plugins       func() ([]plugins.Plugin, error)
+	entityIndex   func() (*entities.SQLiteIndex, error)
+	scans         func() (*ledger.SQLiteStore, error)
+	changes       func() (*peersync.SQLiteLog, error)
+	links         func() (*relations.SQLiteStore, error)
+
+	mu        sync.Mutex
+	databases databases // SQLite databases opened
+	closers   []func()  // Release the resources of the services built
+}
+
+// stageMetrics times the extract and ingest stages of every record scraped.
+// It is published as the "pipeline" expvar.
+var stageMetrics = pipeline.NewMetrics()
+
+func init() {
+	expvar.Publish("pipeline", stageMetrics)
+}
+
+// wiringError is the failure to build a service a command uses
+type wiringError struct {
+	err error
+}
+
+// Error implements error
+func (e wiringError) Error() string {
+	return e.err.Error()
+}
+
+// Unwrap returns the error building the service
+func (e wiringError) Unwrap() error {
+	return e.err
+}
+
+// lazy returns the getter of a service, building it on first use and
+// returning the service, or the error building it, on every call. Errors are
+// reported as wiringError.
+func lazy[T any](build func() (T, error)) func() (T, error) {
+	return sync.OnceValues(func() (T, error) {
+		service, err := build()
+		if wiring := (wiringError{}); err != nil && !errors.As(err, &wiring) {
+			err = wiringError{err: err}
+		}
+		return service, err
+	})
+}
+
+// newApp wires the services from the configuration. Services are built when
+// a command first uses them; closing the app releases the resources they
+// hold.
+func newApp(cfg config.Config) *app {
+	a := &app{
+		buffers:          source.Buffers{Records: cfg.Sources.RecordBuffer, Errors: cfg.Sources.ErrorBuffer},
+		ingest:           pipeline.StageConfig{Workers: cfg.Pipeline.IngestWorkers, Queue: cfg.Pipeline.IngestQueue, Metrics: stageMetrics},
+		batch:            ingestor.BatchConfig{Size: cfg.Pipeline.IndexBatchSize, Timeout: cfg.Pipeline.IndexBatchTimeout},
+		taxReportDir:     cfg.TaxReport.OutputDir,
+		obsidianVaultDir: cfg.Obsidian.VaultDir,
+		slackAddr:        cfg.Slack.Addr,
+		api:              cfg.API,
+		calendarToken:    cfg.Calendar.FeedToken,
+		databases:        databases{},
+	}
+	a.wireStorage(cfg)
+	a.wireAI(cfg)
+	a.wireIngestion(cfg)
+	a.wireServices(cfg)
+	return a
+}
+
+// open returns the SQLite database at the given path, shared by the stores
+// keeping their tables in it
+func (a *app) open(path string) (*sqlite.DB, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.databases.open(path)
+}
+
+// onClose releases a resource of a service when the app is closed
+func (a *app) onClose(release func()) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.closers = append(a.closers, release)
+}
+
+// close releases the resources of the services built, then the databases
+func (a *app) close() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for i := len(a.closers) - 1; i >= 0; i-- {
+		a.closers[i]()
+	}
+	a.closers = nil
+	a.databases.close()
+}
+
+// wireStorage wires the record and vector storage and the stores kept next
+// to the records in the records database
+func (a *app) wireStorage(cfg config.Config) {
+	// Shared outbound HTTP client, keeping connections alive across AI, notification and remote storage calls
+	a.httpClient = lazy(func() (*http.Client, error) {
+		return newHTTPClient(cfg)
+	})
+	a.recordsDB = lazy(func() (*sqlite.DB, error) {
+		return a.open(cfg.SQLitePath)
+	})
+
+	// User-defined record types must be registered before records are classified or validated
+	a.types = lazy(func() (*typestore.SQLiteStore, error) {
+		db, err := a.recordsDB()
+		if err != nil {
+			return nil, err
+		}
+		return newTypeStore(db)
+	})
+	a.storage = lazy(func() (storage.Storage, error) {
+		if _, err := a.types(); err != nil {
+			return nil, err
+		}
+		httpClient, err := a.httpClient()
+		if err != nil {
+			return nil, err
+		}
+		db, err := a.recordsDB()
+		if err != nil {
+			return nil, err
+		}
+		recordStorage, err := newRecordStorage(cfg, httpClient, db)
+		if err != nil || cfg.Sync.Token == "" {
+			return recordStorage, err
+		}
+		// Changes to records are logged for peers from here on
+		changes, err := a.changes()
+		if err != nil {
+			return nil, err
+		}
+		return trackChanges(cfg, recordStorage, changes), nil
+	})
+	a.vectorStorage = lazy(func() (knowledgebase.VectorStorage, error) {
+		cfgWithAWS, err := a.configWithAWS()
+		if err != nil {
+			return nil, err
+		}
+		httpClient, err := a.httpClient()
+		if err != nil {
+			return nil, err
+		}
+		recordStorage, err := a.storage()
+		if err != nil {
+			return nil, err
+		}
+		vectorStorage, err := newVectorStorage(cfgWithAWS, httpClient, recordStorage, a.open)
+		if err != nil {
+			return nil, err
+		}
+		if closer, ok := vectorStorage.(io.Closer); ok {
+			a.onClose(func() { _ = closer.Close() })
+		}
+		return vectorStorage, nil
+	})
+
+	a.entityIndex = inRecordsDB(a, "entity index", entities.NewSQLiteIndex)
+	a.budgetStore = inRecordsDB(a, "budget store", budgets.NewSQLiteStore)
+	a.household = inRecordsDB(a, "household store", household.NewSQLiteStore)
+	a.checkpoints = inRecordsDB(a, "checkpoint store", checkpoint.NewSQLiteStore)
+	a.scans = inRecordsDB(a, "ledger store", ledger.NewSQLiteStore)
+	a.changes = inRecordsDB(a, "change log", peersync.NewSQLiteLog)
+	a.links = inRecordsDB(a, "relations store", relations.NewSQLiteStore)
+}
+
+// inRecordsDB returns the getter of a store kept in the records database
+func inRecordsDB[T any](a *app, name string, newStore func(*sqlite.DB) (T, error)) func() (T, error) {
+	return lazy(func() (T, error) {
+		db, err := a.recordsDB()
+		if err != nil {
+			var store T
+			return store, err
+		}
+		store, err := newStore(db)
+		if err != nil {
+			return store, fmt.Errorf("failed to initialize %s: %w", name, err)
+		}
+		return store, nil
+	})
+}
+
+// wireAI wires the AI provider chain, the prompt templates and the extraction
+// of records from documents
+func (a *app) wireAI(cfg config.Config) {
+	// The AWS configuration is loaded only when Bedrock is used
+	a.configWithAWS = lazy(func() (config.Config, error) {
+		return withAWSConfig(context.Background(), cfg)
+	})
+
+	// AI usage tracking
+	a.usage = lazy(func() (ai.UsageStore, error) {
+		return newUsageStore(cfg, a.open)
+	})
+
+	// AI provider chain
+	a.provider = lazy(func() (ai.Provider, error) {
+		cfgWithAWS, err := a.configWithAWS()
+		if err != nil {
+			return nil, err
+		}
+		httpClient, err := a.httpClient()
+		if err != nil {
+			return nil, err
+		}
+		usageStore, err := a.usage()
+		if err != nil {
+			return nil, err
+		}
+		provider, closeProvider, err := newAIProvider(cfgWithAWS, httpClient, usageStore, a.open)
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize AI provider: %w", err)
+		}
+		a.onClose(closeProvider)
+		return provider, nil
+	})
+
+	// Prompt templates, optionally overridden from the user prompts directory
+	a.prompts = lazy(func() (*prompts.Registry, error) {
+		promptRegistry, err := prompts.NewRegistry(cfg.AI.PromptsDir)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load prompt templates: %w", err)
+		}
+		return promptRegistry, nil
+	})
+
+	// Thumbnails are generated at ingest time and served by the API
+	a.thumbnails = lazy(func() (thumbnails.Store, error) {
+		return newThumbnailStore(cfg)
+	})
+
+	// Plugins adding extractors and sources
+	a.plugins = lazy(func() ([]plugins.Plugin, error) {
+		return plugins.Discover(context.Background(), cfg.Plugins.Dir)
+	})
+
+	a.extractor = lazy(func() (extractor.ContentExtractor, error) {
+		provider, err := a.provider()
+		if err != nil {
+			return nil, err
+		}
+		promptRegistry, err := a.prompts()
+		if err != nil {
+			return nil, err
+		}
+		thumbnailStore, err := a.thumbnails()
+		if err != nil {
+			return nil, err
+		}
+		installed, err := a.plugins()
+		if err != nil {
+			return nil, err
+		}
+		contentExtractor, closeExtractor := newContentExtractor(cfg, provider, promptRegistry, thumbnailStore, installed)
+		a.onClose(closeExtractor)
+		return contentExtractor, nil
+	})
+	a.summarizer = lazy(func() (summaries.Summarizer, error) {
+		provider, err := a.provider()
+		if err != nil {
+			return nil, err
+		}
+		promptRegistry, err := a.prompts()
+		if err != nil {
+			return nil, err
+		}
+		return summaries.NewLLMSummarizer(provider, promptRegistry, newBudgeter(cfg), cfg.AI.Summaries.MinLength), nil
+	})
+}
+
+// wireIngestion wires the ingestion chain, the sources it ingests records
+// from and the search of the records ingested
+func (a *app) wireIngestion(cfg config.Config) {
+	a.ingestor = lazy(func() (ingestor.Ingestor, error) {
+		recordStorage, err := a.storage()
+		if err != nil {
+			return nil, err
+		}
+		vectorStorage, err := a.vectorStorage()
+		if err != nil {
+			return nil, err
+		}
+		provider, err := a.provider()
+		if err != nil {
+			return nil, err
+		}
+		promptRegistry, err := a.prompts()
+		if err != nil {
+			return nil, err
+		}
+		summarizer, err := a.summarizer()
+		if err != nil {
+			return nil, err
+		}
+		entityIndex, err := a.entityIndex()
+		if err != nil {
+			return nil, err
+		}
+		householdStore, err := a.household()
+		if err != nil {
+			return nil, err
+		}
+		scans, err := a.scans()
+		if err != nil {
+			return nil, err
+		}
+		return newIngestor(cfg, recordStorage, vectorStorage, entities.NewLLMExtractor(provider, promptRegistry), summarizer, entityIndex, householdStore, scans), nil
+	})
+	a.sources = lazy(func() ([]source.Source, error) {
+		httpClient, err := a.httpClient()
+		if err != nil {
+			return nil, err
+		}
+		contentExtractor, err := a.extractor()
+		if err != nil {
+			return nil, err
+		}
+		installed, err := a.plugins()
+		if err != nil {
+			return nil, err
+		}
+		scans, err := a.scans()
+		if err != nil {
+			return nil, err
+		}
+		recordIngestor, err := a.ingestor()
+		if err != nil {
+			return nil, err
+		}
+		return newSources(cfg, httpClient, contentExtractor, plugins.OfKind(installed, plugins.KindSource), scans, recordIngestor), nil
+	})
+	a.watch = lazy(func() (source.Source, error) {
+		contentExtractor, err := a.extractor()
+		if err != nil {
+			return nil, err
+		}
+		scans, err := a.scans()
+		if err != nil {
+			return nil, err
+		}
+		return newWatchSource(cfg, contentExtractor, scans), nil
+	})
+
+	// Keyword and vector hits are fused, degrading to keyword search alone
+	// while the vector store is unavailable
+	a.discovery = lazy(func() (discovery.Discovery, error) {
+		recordStorage, err := a.storage()
+		if err != nil {
+			return nil, err
+		}
+		vectorStorage, err := a.vectorStorage()
+		if err != nil {
+			return nil, err
+		}
+		keywordDiscovery := discovery.NewKeywordDiscovery(recordStorage)
+		return discovery.NewDegradingDiscovery(
+			discovery.NewHybridDiscovery(keywordDiscovery, discovery.NewSimpleDiscovery(vectorStorage)),
+			keywordDiscovery,
+		), nil
+	})
+	a.answerer = lazy(func() (answerer.Answerer, error) {
+		recordDiscovery, err := a.discovery()
+		if err != nil {
+			return nil, err
+		}
+		recordStorage, err := a.storage()
+		if err != nil {
+			return nil, err
+		}
+		provider, err := a.provider()
+		if err != nil {
+			return nil, err
+		}
+		promptRegistry, err := a.prompts()
+		if err != nil {
+			return nil, err
+		}
+		return answerer.NewRAGAnswerer(recordDiscovery, recordStorage, provider, promptRegistry, newBudgeter(cfg), cfg.AI.Answers.TopK), nil
+	})
+	a.chat = lazy(func() (*answerer.Chat, error) {
+		recordDiscovery, err := a.discovery()
+		if err != nil {
+			return nil, err
+		}
+		recordStorage, err := a.storage()
+		if err != nil {
+			return nil, err
+		}
+		provider, err := a.provider()
+		if err != nil {
+			return nil, err
+		}
+		promptRegistry, err := a.prompts()
+		if err != nil {
+			return nil, err
+		}
+		return answerer.NewChat(recordDiscovery, recordStorage, provider, promptRegistry, newBudgeter(cfg), cfg.AI.Answers.TopK, cfg.AI.Answers.HistoryTurns), nil
+	})
+	a.agent = lazy(func() (agent.Agent, error) {
+		cfgWithAWS, err := a.configWithAWS()
+		if err != nil {
+			return nil, err
+		}
+		recordDiscovery, err := a.discovery()
+		if err != nil {
+			return nil, err
+		}
+		recordStorage, err := a.storage()
+		if err != nil {
+			return nil, err
+		}
+		return newAgent(cfgWithAWS, recordDiscovery, recordStorage), nil
+	})
+}
+
+// wireServices wires the services built on the records, and the bots and
+// replica serving them
+func (a *app) wireServices(cfg config.Config) {
+	// Notification channels shared by reminders, budget alerts and scrape failure reports
+	a.notifier = lazy(func() (notifications.Notifier, error) {
+		httpClient, err := a.httpClient()
+		if err != nil {
+			return nil, err
+		}
+		return newNotifier(cfg, httpClient)
+	})
+
+	// Services reading the records alone
+	a.analytics = onStorage(a, analytics.NewReceiptAnalyzer)
+	a.recurring = onStorage(a, analytics.NewReceiptSubscriptionDetector)
+	a.health = onStorage(a, health.NewRecordTimelineBuilder)
+	a.warranty = onStorage(a, warranty.NewRecordChecker)
+	a.claims = onStorage(a, func(recordStorage storage.Storage) claims.Matcher {
+		return claims.NewRecordMatcher(recordStorage, claims.Config{
+			MedicalCategories: cfg.Claims.MedicalCategories,
+			WindowDays:        cfg.Claims.WindowDays,
+		})
+	})
+	a.trips = onStorage(a, func(recordStorage storage.Storage) trips.Clusterer {
+		return trips.NewRecordClusterer(recordStorage, trips.Config{
+			GapDays:     cfg.Trips.GapDays,
+			HomeCountry: cfg.Trips.HomeCountry,
+		})
+	})
+	a.calendar = onStorage(a, func(recordStorage storage.Storage) calendar.Builder {
+		return calendar.NewRecordBuilder(recordStorage, cfg.Calendar.ReminderDays)
+	})
+	a.taxReport = onStorage(a, func(recordStorage storage.Storage) taxreport.Generator {
+		return taxreport.NewRecordGenerator(recordStorage, taxreport.Config{
+			FiscalYearStartMonth: time.Month(cfg.TaxReport.FiscalYearStartMonth),
+			Deductions:           cfg.TaxReport.Deductions,
+		})
+	})
+	a.obsidian = onStorage(a, func(recordStorage storage.Storage) obsidian.Exporter {
+		return obsidian.NewRecordExporter(recordStorage, obsidian.Config{Attachments: cfg.Obsidian.Attachments})
+	})
+
+	// Services notifying of what they find in the records
+	a.reminders = lazy(func() (reminders.Engine, error) {
+		recordStorage, err := a.storage()
+		if err != nil {
+			return nil, err
+		}
+		notifier, err := a.notifier()
+		if err != nil {
+			return nil, err
+		}
+		return reminders.NewLeadTimeEngine(recordStorage, cfg.Reminders.LeadDays, reminders.NewEventNotifier(notifier)), nil
+	})
+	a.vehicles = lazy(func() (vehicles.Tracker, error) {
+		recordStorage, err := a.storage()
+		if err != nil {
+			return nil, err
+		}
+		notifier, err := a.notifier()
+		if err != nil {
+			return nil, err
+		}
+		return vehicles.NewRecordTracker(recordStorage, vehicles.Config{
+			Intervals: serviceIntervals(cfg),
+			LeadDays:  cfg.Vehicles.LeadDays,
+		}, reminders.NewEventNotifier(notifier)), nil
+	})
+	a.budgets = lazy(func() (budgets.Checker, error) {
+		budgetStore, err := a.budgetStore()
+		if err != nil {
+			return nil, err
+		}
+		recordStorage, err := a.storage()
+		if err != nil {
+			return nil, err
+		}
+		notifier, err := a.notifier()
+		if err != nil {
+			return nil, err
+		}
+		return budgets.NewThresholdChecker(budgetStore, recordStorage, cfg.Budgets.Thresholds, budgets.NewEventNotifier(notifier)), nil
+	})
+
+	// The dashboard gathers what these services report
+	a.dashboard = lazy(func() (dashboard.Builder, error) {
+		recordStorage, err := a.storage()
+		if err != nil {
+			return nil, err
+		}
+		reminderEngine, err := a.reminders()
+		if err != nil {
+			return nil, err
+		}
+		vehicleTracker, err := a.vehicles()
+		if err != nil {
+			return nil, err
+		}
+		spendAnalyzer, err := a.analytics()
+		if err != nil {
+			return nil, err
+		}
+		subscriptionDetector, err := a.recurring()
+		if err != nil {
+			return nil, err
+		}
+		return dashboard.NewOverviewBuilder(recordStorage, reminderEngine, vehicleTracker, spendAnalyzer, subscriptionDetector, dashboard.Config{
+			WindowDays:  cfg.Dashboard.WindowDays,
+			RecentLimit: cfg.Dashboard.RecentLimit,
+		}), nil
+	})
+	a.digest = lazy(func() (digest.Composer, error) {
+		overview, err := a.dashboard()
+		if err != nil {
+			return nil, err
+		}
+		recordStorage, err := a.storage()
+		if err != nil {
+			return nil, err
+		}
+		return digest.NewDashboardComposer(overview, recordStorage), nil
+	})
+
+	// Services of the stores kept next to the records
+	a.entities = lazy(func() (entities.Browser, error) {
+		entityIndex, err := a.entityIndex()
+		if err != nil {
+			return nil, err
+		}
+		recordStorage, err := a.storage()
+		if err != nil {
+			return nil, err
+		}
+		return entities.NewIndexBrowser(entityIndex, recordStorage), nil
+	})
+	a.relations = lazy(func() (relations.Service, error) {
+		links, err := a.links()
+		if err != nil {
+			return nil, err
+		}
+		recordStorage, err := a.storage()
+		if err != nil {
+			return nil, err
+		}
+		return relations.NewRecordService(links, recordStorage), nil
+	})
+
+	// Services comparing records with the vector index
+	a.duplicates = lazy(func() (duplicates.Detector, error) {
+		recordStorage, err := a.storage()
+		if err != nil {
+			return nil, err
+		}
+		vectorStorage, err := a.vectorStorage()
+		if err != nil {
+			return nil, err
+		}
+		return duplicates.NewRecordDetector(recordStorage, vectorStorage, duplicates.Config{
+			TextThreshold:      cfg.Duplicates.TextThreshold,
+			EmbeddingThreshold: cfg.Duplicates.EmbeddingThreshold,
+		}), nil
+	})
+	a.merger = lazy(func() (duplicates.Merger, error) {
+		recordStorage, err := a.storage()
+		if err != nil {
+			return nil, err
+		}
+		recordIngestor, err := a.ingestor()
+		if err != nil {
+			return nil, err
+		}
+		return duplicates.NewRecordMerger(recordStorage, recordIngestor), nil
+	})
+
+	// Bots and the replica serving the records
+	a.slack = lazy(func() (*slack.Handler, error) {
+		httpClient, err := a.httpClient()
+		if err != nil {
+			return nil, err
+		}
+		recordDiscovery, err := a.discovery()
+		if err != nil {
+			return nil, err
+		}
+		contentExtractor, err := a.extractor()
+		if err != nil {
+			return nil, err
+		}
+		recordIngestor, err := a.ingestor()
+		if err != nil {
+			return nil, err
+		}
+		return newSlackHandler(cfg, httpClient, recordDiscovery, contentExtractor, recordIngestor), nil
+	})
+	a.telegram = lazy(func() (*telegram.Bot, error) {
+		httpClient, err := a.httpClient()
+		if err != nil {
+			return nil, err
+		}
+		recordDiscovery, err := a.discovery()
+		if err != nil {
+			return nil, err
+		}
+		recordAgent, err := a.agent()
+		if err != nil {
+			return nil, err
+		}
+		recordStorage, err := a.storage()
+		if err != nil {
+			return nil, err
+		}
+		return newTelegramBot(cfg, httpClient, recordDiscovery, recordAgent, recordStorage), nil
+	})
+	a.sync = lazy(func() (*syncService, error) {
+		httpClient, err := a.httpClient()
+		if err != nil {
+			return nil, err
+		}
+		recordStorage, err := a.storage()
+		if err != nil {
+			return nil, err
+		}
+		recordIngestor, err := a.ingestor()
+		if err != nil {
+			return nil, err
+		}
+		changes, err := a.changes()
+		if err != nil {
+			return nil, err
+		}
+		return newSyncService(cfg, httpClient, recordStorage, recordIngestor, changes), nil
+	})
+}
+
+// onStorage returns the getter of a service reading the records alone
+func onStorage[T any](a *app, newService func(storage.Storage) T) func() (T, error) {
+	return lazy(func() (T, error) {
+		recordStorage, err := a.storage()
+		if err != nil {
+			var service T
+			return service, err
+		}
+		return newService(recordStorage), nil
+	})
+}
+
+// newHTTPClient creates the outbound HTTP client from the configuration
+func newHTTPClient(cfg config.Config) (*http.Client, error) {
+	httpClient, err := httpclient.New(httpclient.Config{
+		Timeout:             cfg.HTTP.Timeout,
+		ProxyURL:            cfg.HTTP.ProxyURL,
+		MaxRetries:          cfg.HTTP.MaxRetries,
+		InsecureSkipVerify:  cfg.HTTP.InsecureSkipVerify,
+		MaxIdleConns:        cfg.HTTP.MaxIdleConns,
+		MaxIdleConnsPerHost: cfg.HTTP.MaxIdleConnsPerHost,
+		IdleConnTimeout:     cfg.HTTP.IdleConnTimeout,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize HTTP client: %w", err)
+	}
+	return httpClient, nil
+}
+
+// newRecordStorage initializes the record storage, kept in the records
+// database by the sqlite backend. Records kept on a remote server are
+// encrypted with the at-rest key.
+func newRecordStorage(cfg config.Config, httpClient *http.Client, db *sqlite.DB) (storage.Storage, error) {
+	recordStorage, err := storage.NewStorage(storage.Config{
+		Backend:         cfg.StorageBackend,
+		SQLite:          db,
+		JSONPath:        cfg.JSONPath,
+		CompressContent: cfg.CompressContent,
+		RemoteURL:       cfg.Remote.URL,
+		RemoteToken:     cfg.Remote.Token,
+		HTTPClient:      httpClient,
+		Keys:            newKeyManager(cfg),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize storage: %w", err)
+	}
+	return recordStorage, nil
+}
+
+// newVectorStorage initializes the vector storage indexing the records,
+// locally for records kept on a remote server. The sqlite index and the chunk
+// counts are kept in the databases open returns.
+func newVectorStorage(cfg config.Config, httpClient *http.Client, recordStorage storage.Storage, open func(path string) (*sqlite.DB, error)) (knowledgebase.VectorStorage, error) {
+	embedder, err := newEmbedder(cfg, httpClient)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize embedder: %w", err)
+	}
+	recordsDB, err := open(cfg.SQLitePath)
+	if err != nil {
+		return nil, err
+	}
+	// The sqlite index is kept in the records database unless given a path
+	var vectorDB *sqlite.DB
+	if cfg.VectorProvider() == knowledgebase.VectorBackendSQLite {
+		vectorIndexPath := cfg.VectorIndexPath
+		if vectorIndexPath == "" {
+			vectorIndexPath = cfg.SQLitePath
+		}
+		if vectorDB, err = open(vectorIndexPath); err != nil {
+			return nil, err
+		}
+	}
+	vectorStorage, err := knowledgebase.NewVectorStorage(knowledgebase.VectorStorageConfig{
+		Backend:     cfg.VectorProvider(),
+		Path:        cfg.VectorIndexPath,
+		Database:    vectorDB,
+		Records:     recordStorage,
+		Breaker:     breakerConfig(cfg),
+		Chunks:      chunker.Config{Size: cfg.Vector.Chunk.Size, Overlap: cfg.Vector.Chunk.Overlap},
+		ChunkCounts: recordsDB,
+		Embedder:    embedder,
+		Chroma: knowledgebase.ChromaConfig{
+			URL:        cfg.Vector.Chroma.URL,
+			Token:      cfg.Vector.Chroma.Token,
+			Tenant:     cfg.Vector.Chroma.Tenant,
+			Database:   cfg.Vector.Chroma.Database,
+			Collection: cfg.Vector.Chroma.Collection,
+		},
+		Qdrant: knowledgebase.QdrantConfig{
+			URL:        cfg.Vector.Qdrant.URL,
+			APIKey:     cfg.Vector.Qdrant.APIKey,
+			Collection: cfg.Vector.Qdrant.Collection,
+		},
+		HTTPClient: httpClient,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize vector storage: %w", err)
+	}
+	return vectorStorage, nil
+}
+
+// newEmbedder builds the embedder of the persisted local vector index. It is
+// nil for the local provider, whose hashed terms the index computes itself.
+// Ollama is reached at its configured URL unless an endpoint is given, and
+// Bedrock in the region of the Bedrock provider.
+func newEmbedder(cfg config.Config, httpClient *http.Client) (knowledgebase.Embedder, error) {
+	embeddings := cfg.AI.Embeddings
+	if embeddings.Provider == "" || embeddings.Provider == knowledgebase.EmbedderProviderLocal {
+		return nil, nil
+	}
+	endpoint := embeddings.Endpoint
+	if endpoint == "" && embeddings.Provider == knowledgebase.EmbedderProviderOllama {
+		endpoint = cfg.AI.Ollama.URL
+	}
+	var bedrockClient knowledgebase.BedrockInvoker
+	if embeddings.Provider == knowledgebase.EmbedderProviderBedrock {
+		bedrockClient = bedrockruntime.NewFromConfig(bedrockAWSConfig(cfg))
+	}
+	return knowledgebase.NewEmbedder(knowledgebase.EmbedderConfig{
+		Provider:      embeddings.Provider,
+		Model:         embeddings.Model,
+		Endpoint:      endpoint,
+		Dimensions:    embeddings.Dimensions,
+		BatchSize:     embeddings.BatchSize,
+		HTTPClient:    httpClient,
+		BedrockClient: bedrockClient,
+	})
+}
+
+// newThumbnailStore creates the store of record thumbnails, nil unless
+// thumbnails are enabled
+func newThumbnailStore(cfg config.Config) (thumbnails.Store, error) {
+	if !cfg.Thumbnails.Enabled {
+		return nil, nil
+	}
+	store, err := thumbnails.NewFileStore(cfg.Thumbnails.Dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize thumbnail store: %w", err)
+	}
+	return store, nil
+}
+
+// newContentExtractor builds the extraction chain, generating thumbnails into
+// the store unless it is nil. Documents of the media types the installed
+// extractor plugins declare are extracted by the plugins. The returned
+// function releases the Tesseract clients.
+func newContentExtractor(cfg config.Config, provider ai.Provider, promptRegistry prompts.Renderer, thumbnailStore thumbnails.Store, installed []plugins.Plugin) (extractor.ContentExtractor, func()) {
+	typeExtractor := extractor.NewLLMTypeExtractor(provider, promptRegistry)
+	metadataExtractor := extractor.NewLLMMetadataExtractor(provider, promptRegistry)
+	transcriber, closeTranscriber := newTranscriber(cfg, provider, promptRegistry)
+	pdfs := extractor.NewPopplerConverter(extractor.PopplerConfig{
+		TextExtractor: cfg.PDF.TextExtractor,
+		Renderer:      cfg.PDF.Renderer,
+		DPI:           cfg.PDF.DPI,
+		MaxPages:      cfg.PDF.MaxPages,
+	})
+	contentExtractor := extractor.NewOCRContentExtractor(transcriber, pdfs, typeExtractor, metadataExtractor, newBudgeter(cfg))
+	if extractorPlugins := plugins.OfKind(installed, plugins.KindExtractor); len(extractorPlugins) > 0 {
+		contentExtractor = plugins.NewPluginExtractor(extractorPlugins, contentExtractor)
+	}
+	if thumbnailStore == nil {
+		return contentExtractor, closeTranscriber
+	}
+
+	generator := thumbnails.NewImageGenerator(cfg.Thumbnails.MaxSize, cfg.Thumbnails.PDFRenderer)
+	return extractor.NewThumbnailContentExtractor(contentExtractor, generator, thumbnailStore), closeTranscriber
+}
+
+// newSources returns the local source, whose files are read, extracted and
+// ingested in stages with their own workers, followed by the Google Drive and
+// WebDAV sources when enabled and the source plugins. The local and remote
+// sources skip the files whose version is in the scan ledger.
+func newSources(cfg config.Config, httpClient *http.Client, contentExtractor extractor.ContentExtractor, sourcePlugins []plugins.Plugin, scans ledger.Store, recordIngestor ingestor.Ingestor) []source.Source {
+	buffers := source.Buffers{Records: cfg.Sources.RecordBuffer, Errors: cfg.Sources.ErrorBuffer}
+	extract := pipeline.StageConfig{Workers: cfg.Pipeline.ExtractWorkers, Queue: cfg.Pipeline.ExtractQueue}
+	sources := []source.Source{source.NewLocalSource(contentExtractor, scans, recordIngestor, source.LocalConfig{
+		BasePath: cfg.Sources.Local.BasePath,
+		Read:     pipeline.StageConfig{Workers: cfg.Pipeline.ReadWorkers, Queue: cfg.Pipeline.ReadQueue},
+		Extract:  pipeline.StageConfig{Workers: cfg.Pipeline.ExtractWorkers, Queue: cfg.Pipeline.ExtractQueue, Metrics: stageMetrics},
+		Buffers:  buffers,
+	})}
+	if gdriveCfg := cfg.Sources.GDrive; gdriveCfg.Enabled {
+		client := gdrive.NewAPIClient(httpClient, newGDriveAuthorizer(cfg, httpClient), "")
+		sources = append(sources, source.NewGoogleDriveSource(client, contentExtractor, scans, recordIngestor, source.GoogleDriveConfig{
+			FolderID:  gdriveCfg.FolderID,
+			StatePath: gdriveCfg.StatePath,
+			Extract:   extract,
+			Buffers:   buffers,
+		}))
+	}
+	if webdavCfg := cfg.Sources.WebDAV; webdavCfg.Enabled {
+		client := webdav.NewHTTPClient(httpClient, webdav.Config{
+			URL:      webdavCfg.URL,
+			Username: webdavCfg.Username,
+			Password: webdavCfg.Password,
+			Token:    webdavCfg.Token,
+		})
+		sources = append(sources, source.NewWebDAVSource(client, contentExtractor, scans, source.WebDAVConfig{
+			Extract: extract,
+			Buffers: buffers,
+		}))
+	}
+	for _, plugin := range sourcePlugins {
+		sources = append(sources, plugins.NewPluginSource(plugin, buffers))
+	}
+	return sources
+}
+
+// newTranscriber transcribes images with the model when vision extraction is
+// enabled, or with a pool of Tesseract clients
+func newTranscriber(cfg config.Config, provider ai.Provider, promptRegistry prompts.Renderer) (extractor.ImageTranscriber, func()) {
+	if cfg.AI.VisionExtraction {
+		return extractor.NewLLMImageTranscriber(provider, promptRegistry), func() {}
+	}
+	tesseract := extractor.NewTesseractTranscriber(extractor.TesseractConfig{
+		Clients:   cfg.OCR.Clients,
+		Languages: cfg.OCR.Languages,
+	})
+	return tesseract, func() { _ = tesseract.Close() }
+}
+
+// newIngestor builds the ingestion chain, summarizing records and indexing
+// entities when enabled, attributing records to household members and
+// recording the files scraped in the scan ledger
+func newIngestor(cfg config.Config, recordStorage storage.Storage, vectorStorage knowledgebase.VectorStorage, extractor entities.Extractor, summarizer summaries.Summarizer, entityIndex entities.Index, householdStore household.Store, scans ledger.Store) ingestor.Ingestor {
+	recordIngestor := ingestor.NewWarrantyIngestor(ingestor.NewRecordIngestor(recordStorage, vectorStorage, ingestor.BatchConfig{
+		Size:    cfg.Pipeline.IndexBatchSize,
+		Timeout: cfg.Pipeline.IndexBatchTimeout,
+	}))
+	if cfg.AI.Summaries.Mode == summaries.ModeIngest {
+		recordIngestor = ingestor.NewSummaryIngestor(recordIngestor, summarizer)
+	}
+	if cfg.AI.EntityExtraction {
+		recordIngestor = ingestor.NewEntityIngestor(recordIngestor, extractor, entityIndex)
+	}
+	recordIngestor = ingestor.NewPersonIngestor(recordIngestor, household.NewNameDetector(householdStore))
+	// Duplicates are caught before the stages calling models
+	if action := duplicates.Action(cfg.Duplicates.OnIngest); action != duplicates.ActionOff {
+		recordIngestor = duplicates.NewDedupIngestor(recordIngestor, recordStorage, vectorStorage, duplicates.Config{
+			TextThreshold:      cfg.Duplicates.TextThreshold,
+			EmbeddingThreshold: cfg.Duplicates.EmbeddingThreshold,
+		}, action, cfg.Pipeline.IndexBatchSize)
+	}
+	return ingestor.NewLedgerIngestor(recordIngestor, scans)
+}
+
+// newTypeStore opens the user-defined record type store in the records database and registers its types
+func newTypeStore(db *sqlite.DB) (*typestore.SQLiteStore, error) {
+	store, err := typestore.NewSQLiteStore(db)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize record type store: %w", err)
+	}
+	if err := typestore.Load(context.Background(), store); err != nil {
+		return nil, err
+	}
+	return store, nil
+}
+
+// databases opens each SQLite database once by path, so the stores keeping
+// their tables in it share its writer connection
+type databases map[string]*sqlite.DB
+
+// open returns the database at the given path, opening it on first use
+func (d databases) open(path string) (*sqlite.DB, error) {
+	if db, ok := d[path]; ok {
+		return db, nil
+	}
+	db, err := sqlite.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database %s: %w", path, err)
+	}
+	d[path] = db
+	return db, nil
+}
+
+// close closes the databases opened
+func (d databases) close() {
+	for _, db := range d {
+		_ = db.Close()
+	}
+}
+
+// newNotifier routes notifications to the configured channels
+func newNotifier(cfg config.Config, httpClient *http.Client) (notifications.Notifier, error) {
+	templates, err := notifications.NewTemplates(cfg.Notifications.TemplatesDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load notification templates: %w", err)
+	}
+
+	var routes []notifications.Route
+	route := func(channel notifications.Channel, events []string) {
+		r := notifications.Route{Channel: channel}
+		for _, event := range events {
+			r.Events = append(r.Events, notifications.Event(event))
+		}
+		routes = append(routes, r)
+	}
+	if email := cfg.Notifications.Email; email.To != "" {
+		route(newEmailChannel(email.EmailConfig), email.Events)
+	}
+	if ntfy := cfg.Notifications.Ntfy; ntfy.Topic != "" {
+		route(notifications.NewNtfyChannel(httpClient, notifications.NtfyConfig{URL: ntfy.URL, Topic: ntfy.Topic, Token: ntfy.Token}), ntfy.Events)
+	}
+	if pushover := cfg.Notifications.Pushover; pushover.User != "" {
+		route(notifications.NewPushoverChannel(httpClient, notifications.PushoverConfig{Token: pushover.Token, User: pushover.User}), pushover.Events)
+	}
+	if webhook := cfg.Notifications.Webhook; webhook.URL != "" {
+		route(notifications.NewWebhookChannel(httpClient, webhook.URL), webhook.Events)
+	}
+	// Reminders and budget alerts are also emailed with the reminders SMTP
+	// settings, and posted to the reminders and budgets webhooks
+	if cfg.Reminders.Email.To != "" {
+		route(newEmailChannel(cfg.Reminders.Email), []string{string(notifications.EventReminders), string(notifications.EventBudgetAlerts)})
+	}
+	if cfg.Reminders.WebhookURL != "" {
+		route(notifications.NewWebhookChannel(httpClient, cfg.Reminders.WebhookURL), []string{string(notifications.EventReminders)})
+	}
+	if cfg.Budgets.WebhookURL != "" {
+		route(notifications.NewWebhookChannel(httpClient, cfg.Budgets.WebhookURL), []string{string(notifications.EventBudgetAlerts)})
+	}
+	return notifications.NewChannelNotifier(templates, routes...), nil
+}
+
+// newEmailChannel creates an email channel with the SMTP settings
+func newEmailChannel(email config.EmailConfig) notifications.Channel {
+	return notifications.NewEmailChannel(notifications.EmailConfig{
+		Host:     email.SMTPHost,
+		Port:     email.SMTPPort,
+		Username: email.Username,
+		Password: email.Password,
+		From:     email.From,
+		To:       email.To,
+	})
+}
+
+// serviceIntervals merges the configured service months and distances by service type
+func serviceIntervals(cfg config.Config) map[string]vehicles.Interval {
+	intervals := map[string]vehicles.Interval{}
+	for serviceType, months := range cfg.Vehicles.ServiceMonths {
+		interval := intervals[serviceType]
+		interval.Months = months
+		intervals[serviceType] = interval
+	}
+	for serviceType, distance := range cfg.Vehicles.ServiceDistance {
+		interval := intervals[serviceType]
+		interval.Distance = float64(distance)
+		intervals[serviceType] = interval
+	}
+	return intervals
+}
+
+// breakerConfig returns the circuit breaker settings for remote dependencies
+func breakerConfig(cfg config.Config) breaker.Config {
+	return breaker.Config{
+		FailureThreshold: cfg.Breaker.FailureThreshold,
+		OpenTimeout:      cfg.Breaker.OpenTimeout,
+	}
+}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/kazemisoroush/assistant/pkg/config"
+	"github.com/kazemisoroush/assistant/pkg/handler"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRun_List_BuildsOnlyStorage(t *testing.T) {
+	// Arrange
+	dir := t.TempDir()
+	t.Chdir(dir)
+	cfg, err := config.LoadConfig()
+	require.NoError(t, err, "LoadConfig() error should be nil")
+	// Plugins are discovered from a file, failing every command extracting records
+	cfg.Plugins.Dir = filepath.Join(dir, "plugins")
+	require.NoError(t, os.WriteFile(cfg.Plugins.Dir, nil, 0600), "failed to write plugins file")
+
+	// Act
+	listErr := run(context.Background(), cfg, handler.ListCommandType, nil)
+	scrapeErr := run(context.Background(), cfg, handler.ScrapeCommandType, nil)
+
+	// Assert
+	assert.NoError(t, listErr, "run() should list the records without discovering plugins")
+	assert.ErrorContains(t, scrapeErr, "plugins", "run() should discover plugins for a scrape")
+}
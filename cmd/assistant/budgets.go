@@ -0,0 +1,103 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/kazemisoroush/assistant/pkg/budgets"
+	"github.com/kazemisoroush/assistant/pkg/handler"
+)
+
+func init() {
+	commands[handler.BudgetsCommandType] = runBudgets
+}
+
+// runBudgets lists spend against budgets, sends threshold alerts, or sets or removes a budget
+func runBudgets(ctx context.Context, a *app, command string, args []string) error {
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		return checkBudgets(ctx, a, command, args)
+	}
+	budgetStore, err := a.budgetStore()
+	if err != nil {
+		return err
+	}
+
+	var (
+		hand handler.Handler
+		data any
+	)
+	switch {
+	case args[0] == handler.BudgetsSetSubcommand:
+		hand = handler.NewSetBudgetHandler(budgetStore)
+		data, err = parseBudget(command, args[1:])
+	case len(args) == 2 && args[0] == handler.BudgetsRemoveSubcommand:
+		hand = handler.NewRemoveBudgetHandler(budgetStore)
+		data = args[1]
+	default:
+		fmt.Fprintf(os.Stderr, "Usage: %s %s [--check | %s --category NAME --limit AMOUNT --currency CODE | %s CATEGORY]\n",
+			os.Args[0], command, handler.BudgetsSetSubcommand, handler.BudgetsRemoveSubcommand)
+		return fmt.Errorf("invalid budgets arguments")
+	}
+	if err != nil {
+		return err
+	}
+
+	resp, err := hand.Handle(ctx, handler.Request{
+		Command: handler.BudgetsCommandType,
+		Data:    data,
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "Budgets command failed", "error", err)
+		return err
+	}
+
+	return printJSON(resp.Data, "budgets")
+}
+
+// checkBudgets prints the spend against every budget, or with --check sends
+// and prints the alerts that became due since the last run
+func checkBudgets(ctx context.Context, a *app, command string, args []string) error {
+	flags := flag.NewFlagSet(command, flag.ContinueOnError)
+	check := flags.Bool("check", false, "send alerts for budgets that crossed a threshold since the last run")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	checker, err := a.budgets()
+	if err != nil {
+		return err
+	}
+
+	hand := handler.NewBudgetsHandler(checker)
+	resp, err := hand.Handle(ctx, handler.Request{
+		Command: handler.BudgetsCommandType,
+		Data:    *check,
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "Budgets command failed", "error", err)
+		return err
+	}
+
+	statuses, _ := resp.Data.([]budgets.Status)
+	if *check {
+		return budgets.NewWriterNotifier(os.Stdout).Notify(ctx, statuses)
+	}
+	return printJSON(statuses, "budgets")
+}
+
+// parseBudget reads a budget from the set flags
+func parseBudget(command string, args []string) (budgets.Budget, error) {
+	var budget budgets.Budget
+	flags := flag.NewFlagSet(command, flag.ContinueOnError)
+	flags.StringVar(&budget.Category, "category", "", "receipt category the budget applies to, e.g. groceries")
+	flags.Float64Var(&budget.Limit, "limit", 0, "monthly spending limit")
+	flags.StringVar(&budget.Currency, "currency", "", "ISO 4217 code of the limit; receipts in other currencies are not counted")
+	if err := flags.Parse(args); err != nil {
+		return budgets.Budget{}, err
+	}
+	return budget, nil
+}
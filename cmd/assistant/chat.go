@@ -0,0 +1,62 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+)
+
+// chatCommand opens a conversation about the records until the user leaves
+const chatCommand = "chat"
+
+// chatPrompt is printed before each question
+const chatPrompt = "> "
+
+func init() {
+	commands[chatCommand] = runChat
+	serverCommands[chatCommand] = true
+}
+
+// runChat answers the questions typed on stdin one turn at a time, streaming
+// each answer as it is generated. Typing exit or quit, or closing stdin, ends
+// the chat.
+func runChat(ctx context.Context, a *app, _ string, _ []string) error {
+	chat, err := a.chat()
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("Ask about your records. Type exit to leave.")
+	lines := bufio.NewScanner(os.Stdin)
+	for {
+		fmt.Print(chatPrompt)
+		if !lines.Scan() {
+			fmt.Println()
+			return lines.Err()
+		}
+		question := strings.TrimSpace(lines.Text())
+		switch question {
+		case "":
+			continue
+		case "exit", "quit":
+			return nil
+		}
+
+		answer, err := chat.Ask(ctx, question, func(token string) { fmt.Print(token) })
+		fmt.Println()
+		if ctx.Err() != nil {
+			return nil
+		}
+		if err != nil {
+			slog.ErrorContext(ctx, "Chat turn failed", "error", err)
+			fmt.Fprintln(os.Stderr, "Sorry, that question could not be answered; try again.")
+			continue
+		}
+		for _, citation := range answer.Citations {
+			fmt.Printf("  [%s] %s\n", citation.RecordID, citation.Description)
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/kazemisoroush/assistant/pkg/duplicates"
+	"github.com/kazemisoroush/assistant/pkg/handler"
+	"github.com/kazemisoroush/assistant/pkg/records"
+)
+
+// previewLength is how much of each record's text is shown while reviewing duplicates
+const previewLength = 120
+
+func init() {
+	commands[handler.DuplicatesCommandType] = runDuplicates
+}
+
+// runDuplicates lists likely duplicates, merges one pair, or reviews every pair interactively
+func runDuplicates(ctx context.Context, a *app, command string, args []string) error {
+	switch {
+	case len(args) == 0:
+		candidates, err := findDuplicates(ctx, a)
+		if err != nil {
+			return err
+		}
+		return printJSON(candidates, "duplicates")
+	case args[0] == handler.DuplicatesReviewSubcommand:
+		return reviewDuplicates(ctx, a, os.Stdin)
+	case args[0] == handler.DuplicatesMergeSubcommand && len(args) == 3:
+		merged, err := mergeDuplicate(ctx, a, duplicates.Candidate{KeepID: args[1], DuplicateID: args[2]})
+		if err != nil {
+			return err
+		}
+		return printJSON(merged, "merged record")
+	default:
+		fmt.Fprintf(os.Stderr, "Usage: %s %s [%s | %s <keep-id> <duplicate-id>]\n", os.Args[0], command, handler.DuplicatesReviewSubcommand, handler.DuplicatesMergeSubcommand)
+		return fmt.Errorf("invalid duplicates arguments")
+	}
+}
+
+// findDuplicates returns the likely duplicate pairs
+func findDuplicates(ctx context.Context, a *app) ([]duplicates.Candidate, error) {
+	detector, err := a.duplicates()
+	if err != nil {
+		return nil, err
+	}
+
+	hand := handler.NewDuplicatesHandler(detector)
+	resp, err := hand.Handle(ctx, handler.Request{
+		Command: handler.DuplicatesCommandType,
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "Duplicates command failed", "error", err)
+		return nil, err
+	}
+	candidates, _ := resp.Data.([]duplicates.Candidate)
+	return candidates, nil
+}
+
+// mergeDuplicate merges the duplicate of a candidate into the record it keeps
+func mergeDuplicate(ctx context.Context, a *app, candidate duplicates.Candidate) (any, error) {
+	merger, err := a.merger()
+	if err != nil {
+		return nil, err
+	}
+
+	hand := handler.NewMergeHandler(merger)
+	resp, err := hand.Handle(ctx, handler.Request{
+		Command: handler.DuplicatesCommandType,
+		Data:    candidate,
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "Merge command failed", "error", err)
+		return nil, err
+	}
+	return resp.Data, nil
+}
+
+// reviewDuplicates asks for each likely duplicate pair whether to merge it
+func reviewDuplicates(ctx context.Context, a *app, in io.Reader) error {
+	candidates, err := findDuplicates(ctx, a)
+	if err != nil {
+		return err
+	}
+	if len(candidates) == 0 {
+		fmt.Println("No duplicates found")
+		return nil
+	}
+
+	answers := bufio.NewScanner(in)
+	merged := map[string]bool{}
+	for i, candidate := range candidates {
+		if merged[candidate.KeepID] || merged[candidate.DuplicateID] {
+			continue
+		}
+		fmt.Printf("\n[%d/%d] %s (%.2f)\n", i+1, len(candidates), candidate.Reason, candidate.Score)
+		answer := askMerge(ctx, a, answers, candidate)
+		if answer == "q" {
+			return answers.Err()
+		}
+		if answer == "s" {
+			candidate.KeepID, candidate.DuplicateID = candidate.DuplicateID, candidate.KeepID
+		} else if answer != "y" {
+			continue
+		}
+
+		if _, err := mergeDuplicate(ctx, a, candidate); err != nil {
+			return err
+		}
+		merged[candidate.DuplicateID] = true
+		fmt.Printf("Merged %s into %s\n", candidate.DuplicateID, candidate.KeepID)
+	}
+	return nil
+}
+
+// askMerge shows both records of a candidate and reads the answer; the input ending counts as quitting
+func askMerge(ctx context.Context, a *app, answers *bufio.Scanner, candidate duplicates.Candidate) string {
+	printPreview(ctx, a, "keep", candidate.KeepID)
+	printPreview(ctx, a, "duplicate", candidate.DuplicateID)
+	fmt.Print("Merge? [y]es, [s]wap and merge, [n]o, [q]uit: ")
+	if !answers.Scan() {
+		return "q"
+	}
+	return strings.ToLower(strings.TrimSpace(answers.Text()))
+}
+
+// printPreview prints the type, date and start of the text of a record
+func printPreview(ctx context.Context, a *app, label, id string) {
+	var rec records.Record
+	recordStorage, err := a.storage()
+	if err == nil {
+		rec, err = recordStorage.Get(ctx, id)
+	}
+	if err != nil {
+		fmt.Printf("  %-10s %s (unavailable: %v)\n", label+":", id, err)
+		return
+	}
+	preview := strings.Join(strings.Fields(rec.Content), " ")
+	if len(preview) > previewLength {
+		preview = preview[:previewLength] + "..."
+	}
+	fmt.Printf("  %-10s %s [%s, %s] %s\n", label+":", id, rec.Type, rec.CreatedAt.Format(time.DateOnly), preview)
+}
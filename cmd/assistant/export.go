@@ -0,0 +1,99 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/kazemisoroush/assistant/pkg/calendar"
+	"github.com/kazemisoroush/assistant/pkg/handler"
+)
+
+func init() {
+	commands[handler.ExportCommandType] = runExport
+}
+
+// runExport exports records for other applications
+func runExport(ctx context.Context, a *app, command string, args []string) error {
+	switch {
+	case len(args) > 0 && args[0] == handler.ExportICSSubcommand:
+		return exportICS(ctx, a, command, args[1:])
+	case len(args) > 0 && args[0] == handler.ExportObsidianSubcommand:
+		return exportObsidian(ctx, a, command, args[1:])
+	default:
+		fmt.Fprintf(os.Stderr, "Usage: %s %s [%s [--out FILE] | %s [--vault DIR]]\n", os.Args[0], command, handler.ExportICSSubcommand, handler.ExportObsidianSubcommand)
+		return fmt.Errorf("invalid export arguments")
+	}
+}
+
+// exportObsidian syncs the records as Markdown notes into an Obsidian vault.
+// Running it again only rewrites the notes that changed.
+func exportObsidian(ctx context.Context, a *app, command string, args []string) error {
+	flags := flag.NewFlagSet(command, flag.ContinueOnError)
+	vault := flags.String("vault", a.obsidianVaultDir, "vault directory to write the notes to")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	exporter, err := a.obsidian()
+	if err != nil {
+		return err
+	}
+
+	hand := handler.NewExportObsidianHandler(exporter)
+	resp, err := hand.Handle(ctx, handler.Request{
+		Command: handler.ExportCommandType,
+		Data:    *vault,
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "Export command failed", "error", err)
+		return err
+	}
+	slog.InfoContext(ctx, "Export command completed", "vault", *vault, "result", resp.Data)
+	return nil
+}
+
+// exportICS writes document expirations, renewal reminders and health
+// follow-ups as an iCalendar file, or to stdout without --out
+func exportICS(ctx context.Context, a *app, command string, args []string) (err error) {
+	flags := flag.NewFlagSet(command, flag.ContinueOnError)
+	out := flags.String("out", "", "file to write the calendar to instead of stdout")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	builder, err := a.calendar()
+	if err != nil {
+		return err
+	}
+
+	hand := handler.NewExportICSHandler(builder)
+	resp, err := hand.Handle(ctx, handler.Request{
+		Command: handler.ExportCommandType,
+		Data:    handler.ExportICSSubcommand,
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "Export command failed", "error", err)
+		return err
+	}
+	events, _ := resp.Data.([]calendar.Event)
+
+	var w io.Writer = os.Stdout
+	if *out != "" {
+		f, err := os.Create(*out)
+		if err != nil {
+			return fmt.Errorf("failed to create %s: %w", *out, err)
+		}
+		defer func() {
+			if closeErr := f.Close(); err == nil {
+				err = closeErr
+			}
+		}()
+		w = f
+	}
+	return calendar.WriteICS(w, events, time.Now())
+}
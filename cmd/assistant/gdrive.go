@@ -0,0 +1,59 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+
+	"github.com/kazemisoroush/assistant/pkg/config"
+	"github.com/kazemisoroush/assistant/pkg/gdrive"
+)
+
+// gdriveLoginCommand signs the Google Drive source in with the loopback flow
+const gdriveLoginCommand = "gdrive-login"
+
+func init() {
+	setupCommands[gdriveLoginCommand] = runGDriveLogin
+	serverCommands[gdriveLoginCommand] = true
+}
+
+// newGDriveAuthorizer builds the authorizer keeping the Google Drive token
+func newGDriveAuthorizer(cfg config.Config, httpClient *http.Client) *gdrive.Authorizer {
+	return gdrive.NewAuthorizer(httpClient, gdrive.OAuthConfig{
+		ClientID:     cfg.Sources.GDrive.ClientID,
+		ClientSecret: cfg.Sources.GDrive.ClientSecret,
+		Scope:        cfg.Sources.GDrive.Scope,
+		TokenPath:    cfg.Sources.GDrive.TokenPath,
+	})
+}
+
+// runGDriveLogin prints Google's consent page and waits until the browser is
+// redirected back to it, saving the token the source syncs with.
+// It waits for the user, so it runs without the command timeout.
+func runGDriveLogin(ctx context.Context, cfg config.Config, command string, _ []string) error {
+	if cfg.Sources.GDrive.ClientID == "" || cfg.Sources.GDrive.ClientSecret == "" {
+		fmt.Fprintf(os.Stderr, "The %s command requires SOURCES_GDRIVE_CLIENT_ID and SOURCES_GDRIVE_CLIENT_SECRET to be set\n", command)
+		return fmt.Errorf("google drive is not configured")
+	}
+	httpClient, err := newHTTPClient(cfg)
+	if err != nil {
+		return err
+	}
+	authorizer := newGDriveAuthorizer(cfg, httpClient)
+
+	// Google redirects the browser back to a loopback address once signed in
+	listener, err := net.Listen("tcp", fmt.Sprintf("127.0.0.1:%d", cfg.Sources.GDrive.LoginPort))
+	if err != nil {
+		return fmt.Errorf("failed to listen for the google drive sign-in: %w", err)
+	}
+	defer func() { _ = listener.Close() }()
+	if err := authorizer.Authorize(ctx, listener, func(authURL string) {
+		fmt.Printf("Open this page in a browser on this machine to sign in:\n%s\n", authURL)
+	}); err != nil {
+		return err
+	}
+	fmt.Printf("Signed in to Google Drive; the token was saved to %s\n", cfg.Sources.GDrive.TokenPath)
+	return nil
+}
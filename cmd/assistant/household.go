@@ -0,0 +1,84 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/kazemisoroush/assistant/pkg/handler"
+	"github.com/kazemisoroush/assistant/pkg/household"
+)
+
+func init() {
+	commands[handler.HouseholdCommandType] = runHousehold
+}
+
+// runHousehold lists, adds or removes household members, or attributes a record to one
+func runHousehold(ctx context.Context, a *app, command string, args []string) error {
+	householdStore, err := a.household()
+	if err != nil {
+		return err
+	}
+
+	var (
+		hand handler.Handler
+		data any
+	)
+	switch {
+	case len(args) == 0:
+		hand = handler.NewHouseholdHandler(householdStore)
+	case args[0] == handler.HouseholdAddSubcommand:
+		hand = handler.NewAddMemberHandler(householdStore)
+		data, err = parseMember(command, args[1:])
+	case args[0] == handler.HouseholdRemoveSubcommand && len(args) == 2:
+		hand = handler.NewRemoveMemberHandler(householdStore)
+		data = args[1]
+	case args[0] == handler.HouseholdAssignSubcommand && len(args) == 3:
+		recordStorage, storageErr := a.storage()
+		if storageErr != nil {
+			return storageErr
+		}
+		hand = handler.NewAssignPersonHandler(householdStore, recordStorage)
+		data = household.Assignment{RecordID: args[1], Person: args[2]}
+	default:
+		fmt.Fprintf(os.Stderr, "Usage: %s %s [%s --name NAME [--relationship REL] [--aliases A,B] | %s NAME | %s RECORD_ID NAME]\n",
+			os.Args[0], command, handler.HouseholdAddSubcommand, handler.HouseholdRemoveSubcommand, handler.HouseholdAssignSubcommand)
+		return fmt.Errorf("invalid household arguments")
+	}
+	if err != nil {
+		return err
+	}
+
+	resp, err := hand.Handle(ctx, handler.Request{
+		Command: handler.HouseholdCommandType,
+		Data:    data,
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "Household command failed", "error", err)
+		return err
+	}
+
+	return printJSON(resp.Data, "household")
+}
+
+// parseMember reads a household member from the add flags
+func parseMember(command string, args []string) (household.Member, error) {
+	var member household.Member
+	flags := flag.NewFlagSet(command, flag.ContinueOnError)
+	flags.StringVar(&member.Name, "name", "", "full name of the member as it appears on documents")
+	flags.StringVar(&member.Relationship, "relationship", "", "relationship to you, e.g. self, spouse or child")
+	aliases := flags.String("aliases", "", "comma-separated other names the member appears under")
+	if err := flags.Parse(args); err != nil {
+		return household.Member{}, err
+	}
+
+	for _, alias := range strings.Split(*aliases, ",") {
+		if alias = strings.TrimSpace(alias); alias != "" {
+			member.Aliases = append(member.Aliases, alias)
+		}
+	}
+	return member, nil
+}
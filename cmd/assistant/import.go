@@ -0,0 +1,63 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/kazemisoroush/assistant/pkg/handler"
+	"github.com/kazemisoroush/assistant/pkg/imports"
+	"github.com/kazemisoroush/assistant/pkg/records/source"
+)
+
+const (
+	// importCommand imports notes exported from note-taking applications
+	importCommand = "import"
+
+	// importENEXSubcommand imports an Evernote ENEX export file
+	importENEXSubcommand = "enex"
+
+	// importAppleNotesSubcommand imports a directory of notes exported from Apple Notes
+	importAppleNotesSubcommand = "apple-notes"
+)
+
+func init() {
+	commands[importCommand] = runImport
+}
+
+// runImport extracts the notes of an export into records and ingests them
+// like scraped records. Importing the same export again updates its records.
+func runImport(ctx context.Context, a *app, command string, args []string) error {
+	var reader imports.Reader
+	switch {
+	case len(args) == 2 && args[0] == importENEXSubcommand:
+		reader = imports.NewENEXReader(args[1])
+	case len(args) == 2 && args[0] == importAppleNotesSubcommand:
+		reader = imports.NewAppleNotesReader(args[1])
+	default:
+		fmt.Fprintf(os.Stderr, "Usage: %s %s [%s FILE.enex | %s DIR]\n", os.Args[0], command, importENEXSubcommand, importAppleNotesSubcommand)
+		return fmt.Errorf("invalid import arguments")
+	}
+
+	contentExtractor, err := a.extractor()
+	if err != nil {
+		return err
+	}
+	recordIngestor, err := a.ingestor()
+	if err != nil {
+		return err
+	}
+
+	src := imports.NewNoteSource(reader, contentExtractor, a.buffers)
+	hand := handler.NewLocalScraperHandler(recordIngestor, []source.Source{src}, a.ingest, a.batch, nil)
+	resp, err := hand.Handle(ctx, handler.Request{
+		Command: handler.ScrapeCommandType,
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "Import command failed", "error", err)
+		return err
+	}
+	slog.InfoContext(ctx, "Import command completed", "response", resp)
+	return nil
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/kazemisoroush/assistant/pkg/config"
+	"github.com/kazemisoroush/assistant/pkg/keys"
+	"github.com/kazemisoroush/assistant/pkg/records"
+	"github.com/kazemisoroush/assistant/pkg/records/storage"
+)
+
+// rotateKeyCommand replaces the at-rest encryption key, re-encrypting the
+// records of the remote storage backend with the new one
+const rotateKeyCommand = "rotate-key"
+
+// resealBatchSize is the number of records re-encrypted per write
+const resealBatchSize = 100
+
+func init() {
+	setupCommands[rotateKeyCommand] = runRotateKey
+}
+
+// newKeyManager builds the manager of the at-rest encryption key
+func newKeyManager(cfg config.Config) keys.KeyManager {
+	return keys.NewKeyManager(cfg.Security.KeyringService, cfg.Security.KeyringAccount, cfg.Security.Passphrase, cfg.Security.SaltPath)
+}
+
+// runRotateKey rotates the encryption key. Records are read with the current
+// or the previous key, and the rotation replaces the previous one, so the
+// records are re-encrypted with the current key first, finishing a rotation
+// that was interrupted, and with the new key once it is rotated.
+func runRotateKey(ctx context.Context, cfg config.Config, _ string, _ []string) error {
+	httpClient, err := newHTTPClient(cfg)
+	if err != nil {
+		return err
+	}
+	keyManager := newKeyManager(cfg)
+
+	if err := reseal(ctx, cfg, httpClient, keyManager); err != nil {
+		return err
+	}
+	if _, err := keyManager.Rotate(ctx); err != nil {
+		return fmt.Errorf("failed to rotate encryption key: %w", err)
+	}
+	if err := reseal(ctx, cfg, httpClient, keyManager); err != nil {
+		return err
+	}
+	slog.InfoContext(ctx, "Encryption key rotated")
+	return nil
+}
+
+// reseal stores every record of the remote storage backend again, encrypted
+// with the current key. Records are kept encrypted by no other backend.
+func reseal(ctx context.Context, cfg config.Config, httpClient *http.Client, keyManager keys.KeyManager) error {
+	if cfg.StorageBackend != storage.BackendRemote {
+		return nil
+	}
+	// A new storage loads the keys again, as they are cached on first use
+	recordStorage, err := storage.NewStorage(storage.Config{
+		Backend:         cfg.StorageBackend,
+		CompressContent: cfg.CompressContent,
+		RemoteURL:       cfg.Remote.URL,
+		RemoteToken:     cfg.Remote.Token,
+		HTTPClient:      httpClient,
+		Keys:            keyManager,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to initialize storage: %w", err)
+	}
+
+	batch := make([]records.Record, 0, resealBatchSize)
+	err = recordStorage.Each(ctx, "", func(rec records.Record) error {
+		batch = append(batch, rec)
+		if len(batch) < resealBatchSize {
+			return nil
+		}
+		err := recordStorage.StoreBatch(ctx, batch)
+		batch = batch[:0]
+		return err
+	})
+	if err == nil && len(batch) > 0 {
+		err = recordStorage.StoreBatch(ctx, batch)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to re-encrypt records: %w", err)
+	}
+	return nil
+}
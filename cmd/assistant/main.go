@@ -3,85 +3,666 @@ package main
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"flag"
 	"fmt"
 	"log/slog"
 	"os"
+	"os/signal"
+	"strings"
+	"time"
 
+	"github.com/kazemisoroush/assistant/pkg/analytics"
+	"github.com/kazemisoroush/assistant/pkg/answerer"
+	"github.com/kazemisoroush/assistant/pkg/claims"
 	"github.com/kazemisoroush/assistant/pkg/config"
+	"github.com/kazemisoroush/assistant/pkg/digest"
+	"github.com/kazemisoroush/assistant/pkg/entities"
 	"github.com/kazemisoroush/assistant/pkg/handler"
-	"github.com/kazemisoroush/assistant/pkg/records/discovery"
-	"github.com/kazemisoroush/assistant/pkg/records/extractor"
-	"github.com/kazemisoroush/assistant/pkg/records/ingestor"
-	"github.com/kazemisoroush/assistant/pkg/records/knowledgebase"
-	"github.com/kazemisoroush/assistant/pkg/records/source"
-	"github.com/kazemisoroush/assistant/pkg/records/storage"
+	"github.com/kazemisoroush/assistant/pkg/notifications"
+	"github.com/kazemisoroush/assistant/pkg/reminders"
+	"github.com/kazemisoroush/assistant/pkg/requestid"
+	"github.com/kazemisoroush/assistant/pkg/warranty"
 )
 
 func main() {
-	if len(os.Args) < 2 {
-		fmt.Fprintf(os.Stderr, "Usage: %s <command>\n", os.Args[0])
+	// Global flags precede the command
+	global := flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+	configPath := global.String("config", "", "file of KEY=VALUE configuration variables; the environment overrides them")
+	if err := global.Parse(os.Args[1:]); err != nil {
+		os.Exit(2)
+	}
+	if global.NArg() == 0 {
+		printUsage(os.Stderr)
 		os.Exit(1)
 	}
-
-	command := os.Args[1]
+	command := global.Arg(0)
 
 	// Load configuration
+	if *configPath != "" {
+		if err := config.LoadEnvFile(*configPath); err != nil {
+			slog.Error("Failed to load configuration", "error", err)
+			os.Exit(1)
+		}
+	}
 	cfg, err := config.LoadConfig()
 	if err != nil {
 		slog.Error("Failed to load configuration", "error", err)
 		os.Exit(1)
 	}
 
-	// Initialize storage
-	sqliteStorage, err := storage.NewSQLiteStorage(cfg.SQLitePath)
+	// Interrupting cancels the command, so it can stop cleanly, e.g. a scrape saves its checkpoint
+	ctx, stop := signal.NotifyContext(requestid.WithID(context.Background(), requestid.New()), os.Interrupt)
+	// Servers run until interrupted; other commands are bounded by the command timeout
+	cancel := context.CancelFunc(func() {})
+	if !serverCommands[command] {
+		ctx, cancel = context.WithTimeout(ctx, cfg.Timeout)
+	}
+
+	err = run(ctx, cfg, command, global.Args()[1:])
+	cancel()
+	stop()
 	if err != nil {
-		slog.Error("Failed to initialize local storage", "error", err)
 		os.Exit(1)
 	}
+}
+
+// commandFunc runs a CLI command with its arguments
+type commandFunc func(ctx context.Context, a *app, command string, args []string) error
 
-	// Initialize vector store (using local implementation for POC)
-	localVectorStorage := knowledgebase.NewLocalVectorStorage()
+// commands maps each CLI command to its runner. Commands defined outside
+// this file add themselves from their file's init, so adding a command
+// leaves main.go untouched.
+var commands = map[string]commandFunc{
+	handler.ScrapeCommandType:        runScrape,
+	handler.AskCommandType:           runAsk,
+	handler.StatsCommandType:         runStats,
+	handler.RemindersCommandType:     runReminders,
+	handler.SpendCommandType:         runSpend,
+	handler.HealthCommandType:        runHealth,
+	handler.TaxReportCommandType:     runTaxReport,
+	handler.WarrantyCommandType:      runWarranty,
+	handler.SubscriptionsCommandType: runSubscriptions,
+	handler.EntitiesCommandType:      runEntities,
+	handler.ClaimsCommandType:        runClaims,
+	handler.TripsCommandType:         runTrips,
+	handler.VehiclesCommandType:      runVehicles,
+	handler.DashboardCommandType:     runDashboard,
+	handler.SummarizeCommandType:     runSummarize,
+	handler.DigestCommandType:        runDigest,
+}
 
-	// Initialize service
-	recordService := ingestor.NewRecordIngestor(sqliteStorage, localVectorStorage)
+// serverCommands run until interrupted, so the command timeout does not
+// apply to them. Server commands add themselves when registering.
+var serverCommands = map[string]bool{}
 
-	// Extractors
-	typeExtractor := extractor.NewLlamaTypeExtractor(cfg.AI.Ollama.URL, cfg.AI.Ollama.Model)
-	extractor := extractor.NewOCRContentExtractor(typeExtractor)
+// run executes a single CLI command. The command builds only the services it
+// uses, released once it returns.
+func run(ctx context.Context, cfg config.Config, command string, args []string) error {
+	if command == helpCommand {
+		printUsage(os.Stdout)
+		return nil
+	}
+	if runSetup, ok := setupCommands[command]; ok {
+		return runSetup(ctx, cfg, command, args)
+	}
+	runCommand, ok := commands[command]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "Unknown command: %s\n\n", command)
+		printUsage(os.Stderr)
+		return fmt.Errorf("unknown command: %s", command)
+	}
 
-	// Initialize sources
-	localSource := source.NewLocalSource(extractor, cfg.Sources.Local.BasePath)
+	a := newApp(cfg)
+	defer a.close()
 
-	// Initialize discovery service
-	discoveryService := discovery.NewSimpleDiscovery(localVectorStorage)
+	err := runCommand(ctx, a, command, args)
+	if wiring := (wiringError{}); errors.As(err, &wiring) {
+		slog.ErrorContext(ctx, "Failed to initialize application", "error", wiring.err)
+	}
+	return err
+}
 
-	ctx, cancel := context.WithTimeout(context.Background(), cfg.Timeout)
-	defer cancel()
+// runScrape ingests records from all sources, drawing the progress of
+// resumable sources on the terminal
+func runScrape(ctx context.Context, a *app, command string, args []string) error {
+	flags := flag.NewFlagSet(command, flag.ContinueOnError)
+	resume := flags.Bool("resume", false, "continue after the files an interrupted scrape already processed")
+	keepGoing := flags.Bool("keep-going", false, "scrape the remaining files when some fail, reporting the failures once done")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
 
-	switch command {
-	case handler.ScrapeCommandType:
-		hand := handler.NewLocalScraperHandler(recordService, []source.Source{localSource})
-		resp, err := hand.Handle(ctx, handler.Request{
-			Command: handler.ScrapeCommandType,
-		})
-		if err != nil {
-			slog.Error("Scrape command failed", "error", err)
-			os.Exit(1)
+	recordIngestor, err := a.ingestor()
+	if err != nil {
+		return err
+	}
+	sources, err := a.sources()
+	if err != nil {
+		return err
+	}
+	checkpoints, err := a.checkpoints()
+	if err != nil {
+		return err
+	}
+	notifier, err := a.notifier()
+	if err != nil {
+		return err
+	}
+
+	req := handler.ScrapeRequest{Resume: *resume, KeepGoing: *keepGoing}
+	// The progress bar is drawn only on a terminal
+	bar := newProgressBar(os.Stderr)
+	if bar != nil {
+		req.Progress = bar.report
+	}
+	hand := handler.NewLocalScraperHandler(recordIngestor, sources, a.ingest, a.batch, checkpoints)
+	resp, err := hand.Handle(ctx, handler.Request{
+		Command: handler.ScrapeCommandType,
+		Data:    req,
+	})
+	if bar != nil {
+		bar.finish()
+	}
+	if err != nil {
+		slog.ErrorContext(ctx, "Scrape command failed", "error", err)
+		// The request ID finds the failure's log lines
+		report := fmt.Sprintf("%v\nRequest ID: %s", err, requestid.FromContext(ctx))
+		if notifyErr := notifier.Notify(ctx, notifications.EventScrapeFailure, report); notifyErr != nil {
+			slog.WarnContext(ctx, "Failed to report scrape failure", "error", notifyErr)
 		}
-		slog.Info("Scrape command completed", "response", resp)
-	case handler.SimpleSearchCommandType:
-		hand := handler.NewSimpleSearchHandler(discoveryService)
-		resp, err := hand.Handle(ctx, handler.Request{
-			Command: handler.SimpleSearchCommandType,
-			Data:    os.Args[2],
-		})
-		if err != nil {
-			slog.Error("Search command failed", "error", err)
-			os.Exit(1)
+		return err
+	}
+	slog.InfoContext(ctx, "Scrape command completed", "response", resp)
+	return nil
+}
+
+// runAsk answers a question from the records search finds, citing them.
+// With --agent, the Bedrock agent answers instead, running the multi-step
+// record queries the question needs.
+func runAsk(ctx context.Context, a *app, command string, args []string) error {
+	flags := flag.NewFlagSet(command, flag.ContinueOnError)
+	useAgent := flags.Bool("agent", false, "answer with the Bedrock agent, which can run several record queries")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	question := strings.Join(flags.Args(), " ")
+	if question == "" {
+		fmt.Fprintf(os.Stderr, "Usage: %s %s [--agent] <question>\n", os.Args[0], command)
+		return fmt.Errorf("question is required")
+	}
+	if *useAgent {
+		return askAgent(ctx, a, command, question)
+	}
+
+	recordAnswerer, err := a.answerer()
+	if err != nil {
+		return err
+	}
+
+	hand := handler.NewAnswerHandler(recordAnswerer)
+	resp, err := hand.Handle(ctx, handler.Request{
+		Command: handler.AskCommandType,
+		Data:    question,
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "Ask command failed", "error", err)
+		return err
+	}
+
+	answer, _ := resp.Data.(answerer.Answer)
+	fmt.Println(answer.Text)
+	if len(answer.Citations) > 0 {
+		fmt.Println("\nSources:")
+	}
+	for _, citation := range answer.Citations {
+		fmt.Printf("  [%s] %s\n", citation.RecordID, citation.Description)
+	}
+	return nil
+}
+
+// askAgent answers a question with the Bedrock agent
+func askAgent(ctx context.Context, a *app, command, question string) error {
+	recordAgent, err := a.agent()
+	if err != nil {
+		return err
+	}
+	if recordAgent == nil {
+		fmt.Fprintf(os.Stderr, "The %s --agent command requires AI_BEDROCK_AGENT_SERVICE_ROLE_ARN to be set\n", command)
+		return fmt.Errorf("agent is not configured")
+	}
+	hand := handler.NewAskHandler(recordAgent)
+	resp, err := hand.Handle(ctx, handler.Request{
+		Command: handler.AskCommandType,
+		Data:    question,
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "Ask command failed", "error", err)
+		return err
+	}
+	slog.InfoContext(ctx, "Ask command completed", "response", resp)
+	return nil
+}
+
+// runStats prints usage statistics. Only AI usage is tracked so far, so the
+// --ai flag is required.
+func runStats(ctx context.Context, a *app, command string, args []string) error {
+	flags := flag.NewFlagSet(command, flag.ContinueOnError)
+	aiUsage := flags.Bool("ai", false, "report LLM and embedding usage and estimated cost")
+	days := flags.Int("days", handler.DefaultStatsDays, "number of days to report on")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if !*aiUsage {
+		fmt.Fprintf(os.Stderr, "Usage: %s %s --ai [--days N]\n", os.Args[0], command)
+		return fmt.Errorf("stats type is required")
+	}
+	usage, err := a.usage()
+	if err != nil {
+		return err
+	}
+	if usage == nil {
+		fmt.Fprintf(os.Stderr, "The %s command requires AI_USAGE_ENABLED to be true\n", command)
+		return fmt.Errorf("AI usage tracking is disabled")
+	}
+
+	hand := handler.NewAIUsageHandler(usage)
+	resp, err := hand.Handle(ctx, handler.Request{
+		Command: handler.StatsCommandType,
+		Data:    *days,
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "Stats command failed", "error", err)
+		return err
+	}
+
+	return printJSON(resp.Data, "AI usage")
+}
+
+// runReminders prints a summary of expiring documents. With --send, only the
+// reminders that became due are printed and delivered to the configured
+// email and webhook notifiers.
+func runReminders(ctx context.Context, a *app, command string, args []string) error {
+	flags := flag.NewFlagSet(command, flag.ContinueOnError)
+	send := flags.Bool("send", false, "send the reminders that became due since the last run")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	reminderEngine, err := a.reminders()
+	if err != nil {
+		return err
+	}
+
+	hand := handler.NewRemindersHandler(reminderEngine)
+	resp, err := hand.Handle(ctx, handler.Request{
+		Command: handler.RemindersCommandType,
+		Data:    *send,
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "Reminders command failed", "error", err)
+		return err
+	}
+
+	due, _ := resp.Data.([]reminders.Reminder)
+	return reminders.NewWriterNotifier(os.Stdout).Notify(ctx, due)
+}
+
+// runSpend prints receipt spend aggregated by month, category and vendor
+func runSpend(ctx context.Context, a *app, command string, args []string) error {
+	flags := flag.NewFlagSet(command, flag.ContinueOnError)
+	year := flags.Int("year", 0, "only include receipts from this year")
+	category := flags.String("category", "", "only include receipts in this category")
+	vendor := flags.String("vendor", "", "only include receipts from vendors matching this name")
+	person := flags.String("person", "", "only include receipts attributed to this household member")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	spendAnalyzer, err := a.analytics()
+	if err != nil {
+		return err
+	}
+
+	hand := handler.NewSpendHandler(spendAnalyzer)
+	resp, err := hand.Handle(ctx, handler.Request{
+		Command: handler.SpendCommandType,
+		Data:    analytics.SpendFilter{Year: *year, Category: *category, Vendor: *vendor, Person: *person},
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "Spend command failed", "error", err)
+		return err
+	}
+
+	return printJSON(resp.Data, "spend")
+}
+
+// runHealth prints health views; timeline is the only one so far
+func runHealth(ctx context.Context, a *app, command string, args []string) error {
+	if len(args) == 0 || args[0] != handler.HealthTimelineSubcommand {
+		fmt.Fprintf(os.Stderr, "Usage: %s %s %s [--person NAME]\n", os.Args[0], command, handler.HealthTimelineSubcommand)
+		return fmt.Errorf("health view is required")
+	}
+	flags := flag.NewFlagSet(command, flag.ContinueOnError)
+	person := flags.String("person", "", "only show the timeline of this person or household member")
+	if err := flags.Parse(args[1:]); err != nil {
+		return err
+	}
+
+	timelineBuilder, err := a.health()
+	if err != nil {
+		return err
+	}
+
+	hand := handler.NewHealthTimelineHandler(timelineBuilder)
+	resp, err := hand.Handle(ctx, handler.Request{
+		Command: handler.HealthCommandType,
+		Data:    *person,
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "Health command failed", "error", err)
+		return err
+	}
+
+	return printJSON(resp.Data, "health timeline")
+}
+
+// runTaxReport writes the tax report bundle of a fiscal year
+func runTaxReport(ctx context.Context, a *app, command string, args []string) error {
+	flags := flag.NewFlagSet(command, flag.ContinueOnError)
+	year := flags.Int("year", 0, "fiscal year to report on, named by the calendar year it ends in")
+	out := flags.String("out", a.taxReportDir, "directory to write the report bundle to")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *year <= 0 {
+		fmt.Fprintf(os.Stderr, "Usage: %s %s --year YYYY [--out DIR]\n", os.Args[0], command)
+		return fmt.Errorf("fiscal year is required")
+	}
+
+	generator, err := a.taxReport()
+	if err != nil {
+		return err
+	}
+
+	hand := handler.NewTaxReportHandler(generator, *out)
+	resp, err := hand.Handle(ctx, handler.Request{
+		Command: handler.TaxReportCommandType,
+		Data:    *year,
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "Tax report command failed", "error", err)
+		return err
+	}
+	slog.InfoContext(ctx, "Tax report command completed", "manifest", resp.Data)
+	return nil
+}
+
+// runWarranty prints whether the products matching the query are still under warranty
+func runWarranty(ctx context.Context, a *app, _ string, args []string) error {
+	checker, err := a.warranty()
+	if err != nil {
+		return err
+	}
+
+	hand := handler.NewWarrantyHandler(checker)
+	resp, err := hand.Handle(ctx, handler.Request{
+		Command: handler.WarrantyCommandType,
+		Data:    strings.Join(args, " "),
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "Warranty command failed", "error", err)
+		return err
+	}
+
+	statuses, _ := resp.Data.([]warranty.Status)
+	if len(statuses) == 0 {
+		fmt.Println("No matching warranty found")
+		return nil
+	}
+	for _, status := range statuses {
+		expiry := status.ExpiresAt.Format(time.DateOnly)
+		if status.Active {
+			fmt.Printf("%s: under warranty until %s (%d days left)\n", status.Product, expiry, status.DaysLeft)
+		} else {
+			fmt.Printf("%s: warranty ended on %s\n", status.Product, expiry)
 		}
-		slog.Info("Search command completed", "response", resp)
-	default:
-		fmt.Fprintf(os.Stderr, "Unknown command: %s\n\n", command)
-		os.Exit(1)
 	}
+	return nil
+}
+
+// runSubscriptions prints recurring bills and subscriptions with their monthly totals
+func runSubscriptions(ctx context.Context, a *app, _ string, _ []string) error {
+	subscriptionDetector, err := a.recurring()
+	if err != nil {
+		return err
+	}
+
+	hand := handler.NewSubscriptionsHandler(subscriptionDetector)
+	resp, err := hand.Handle(ctx, handler.Request{
+		Command: handler.SubscriptionsCommandType,
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "Subscriptions command failed", "error", err)
+		return err
+	}
+
+	return printJSON(resp.Data, "subscriptions")
+}
+
+// runEntities lists indexed entities, or the records naming one of them
+func runEntities(ctx context.Context, a *app, command string, args []string) error {
+	flags := flag.NewFlagSet(command, flag.ContinueOnError)
+	kind := flags.String("kind", "", "only list entities of this kind: doctor, clinic, vendor, employer or insurer")
+	name := flags.String("name", "", "list the records naming this entity")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	entityKind, err := entities.ParseKind(*kind)
+	if err != nil {
+		return err
+	}
+
+	browser, err := a.entities()
+	if err != nil {
+		return err
+	}
+
+	hand := handler.NewEntitiesHandler(browser)
+	resp, err := hand.Handle(ctx, handler.Request{
+		Command: handler.EntitiesCommandType,
+		Data:    entities.Entity{Name: *name, Kind: entityKind},
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "Entities command failed", "error", err)
+		return err
+	}
+
+	return printJSON(resp.Data, "entities")
+}
+
+// runClaims prints medical expenses with their insurance claims and what is still unreimbursed
+func runClaims(ctx context.Context, a *app, command string, args []string) error {
+	flags := flag.NewFlagSet(command, flag.ContinueOnError)
+	status := flags.String("status", "", "only show expenses with this status: unclaimed, pending or reimbursed")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	claimStatus, err := claims.ParseStatus(*status)
+	if err != nil {
+		return err
+	}
+
+	matcher, err := a.claims()
+	if err != nil {
+		return err
+	}
+
+	hand := handler.NewClaimsHandler(matcher)
+	resp, err := hand.Handle(ctx, handler.Request{
+		Command: handler.ClaimsCommandType,
+		Data:    claimStatus,
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "Claims command failed", "error", err)
+		return err
+	}
+
+	return printJSON(resp.Data, "claims")
+}
+
+// runTrips prints travel bookings grouped into trips with their receipts, visas and spend
+func runTrips(ctx context.Context, a *app, _ string, _ []string) error {
+	clusterer, err := a.trips()
+	if err != nil {
+		return err
+	}
+
+	hand := handler.NewTripsHandler(clusterer)
+	resp, err := hand.Handle(ctx, handler.Request{
+		Command: handler.TripsCommandType,
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "Trips command failed", "error", err)
+		return err
+	}
+
+	return printJSON(resp.Data, "trips")
+}
+
+// runVehicles prints vehicles with their service history and upcoming
+// services. With --send, only the service reminders that became due are
+// printed and delivered to the configured reminder channels.
+func runVehicles(ctx context.Context, a *app, command string, args []string) error {
+	flags := flag.NewFlagSet(command, flag.ContinueOnError)
+	send := flags.Bool("send", false, "send the service reminders that became due since the last run")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	tracker, err := a.vehicles()
+	if err != nil {
+		return err
+	}
+
+	hand := handler.NewVehiclesHandler(tracker)
+	resp, err := hand.Handle(ctx, handler.Request{
+		Command: handler.VehiclesCommandType,
+		Data:    *send,
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "Vehicles command failed", "error", err)
+		return err
+	}
+
+	if !*send {
+		return printJSON(resp.Data, "vehicles")
+	}
+	due, _ := resp.Data.([]reminders.Reminder)
+	return reminders.NewWriterNotifier(os.Stdout).Notify(ctx, due)
+}
+
+// runDashboard prints expiring documents, upcoming obligations, the review
+// queue, recent ingests and month-to-date spend
+func runDashboard(ctx context.Context, a *app, _ string, _ []string) error {
+	builder, err := a.dashboard()
+	if err != nil {
+		return err
+	}
+
+	hand := handler.NewDashboardHandler(builder)
+	resp, err := hand.Handle(ctx, handler.Request{
+		Command: handler.DashboardCommandType,
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "Dashboard command failed", "error", err)
+		return err
+	}
+
+	return printJSON(resp.Data, "dashboard")
+}
+
+// runDigest prints the digest of vault activity over the last week or
+// month, or sends it when run from cron with --send
+func runDigest(ctx context.Context, a *app, command string, args []string) error {
+	flags := flag.NewFlagSet(command, flag.ContinueOnError)
+	periodName := flags.String("period", string(digest.PeriodWeekly), "period the digest covers: weekly or monthly")
+	send := flags.Bool("send", false, "send the digest through the notification channels")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	period, err := digest.ParsePeriod(*periodName)
+	if err != nil {
+		return err
+	}
+
+	composer, err := a.digest()
+	if err != nil {
+		return err
+	}
+	notifier, err := a.notifier()
+	if err != nil {
+		return err
+	}
+
+	hand := handler.NewDigestHandler(composer, notifier)
+	resp, err := hand.Handle(ctx, handler.Request{
+		Command: handler.DigestCommandType,
+		Data:    handler.DigestRequest{Period: period, Send: *send},
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "Digest command failed", "error", err)
+		return err
+	}
+	if *send {
+		slog.InfoContext(ctx, "Digest sent", "period", period)
+		return nil
+	}
+	return printJSON(resp.Data, "digest")
+}
+
+// runSummarize summarizes records ingested without a summary, for lazy
+// summarization run from cron
+func runSummarize(ctx context.Context, a *app, command string, args []string) error {
+	flags := flag.NewFlagSet(command, flag.ContinueOnError)
+	limit := flags.Int("limit", 0, "maximum number of records to summarize, 0 for all")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	summarizer, err := a.summarizer()
+	if err != nil {
+		return err
+	}
+	recordStorage, err := a.storage()
+	if err != nil {
+		return err
+	}
+	vectorStorage, err := a.vectorStorage()
+	if err != nil {
+		return err
+	}
+
+	hand := handler.NewSummarizeHandler(summarizer, recordStorage, vectorStorage)
+	resp, err := hand.Handle(ctx, handler.Request{
+		Command: handler.SummarizeCommandType,
+		Data:    *limit,
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "Summarize command failed", "error", err)
+		return err
+	}
+
+	return printJSON(resp.Data, "summarized records")
+}
+
+// printJSON prints command output as indented JSON
+func printJSON(data any, what string) error {
+	out, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to format %s: %w", what, err)
+	}
+	fmt.Println(string(out))
+	return nil
 }
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/kazemisoroush/assistant/pkg/records/checkpoint"
+)
+
+const (
+	// progressWidth is how many characters the progress bar spans
+	progressWidth = 30
+
+	// progressInterval is how often the progress bar is redrawn at most
+	progressInterval = 100 * time.Millisecond
+)
+
+// progressBar draws the progress of a scrape on one terminal line. The
+// tracker reports progress in order, so it needs no locking.
+type progressBar struct {
+	out   io.Writer
+	last  checkpoint.Progress
+	drawn time.Time
+}
+
+// newProgressBar creates a progress bar drawn on out, or returns nil when out
+// is not a terminal, so redirected output is not filled with redraws
+func newProgressBar(out *os.File) *progressBar {
+	info, err := out.Stat()
+	if err != nil || info.Mode()&os.ModeCharDevice == 0 {
+		return nil
+	}
+	return &progressBar{out: out}
+}
+
+// report redraws the bar with the progress, unless it was redrawn moments ago
+func (p *progressBar) report(progress checkpoint.Progress) {
+	p.last = progress
+	if time.Since(p.drawn) < progressInterval {
+		return
+	}
+	p.drawn = time.Now()
+	p.draw()
+}
+
+// finish draws the last progress reported and ends its line
+func (p *progressBar) finish() {
+	if p.last.Discovered == 0 {
+		return
+	}
+	p.draw()
+	fmt.Fprintln(p.out)
+}
+
+// draw clears the bar's line and draws it again. Files are discovered as
+// the scrape walks, so the bar fills towards the files discovered so far.
+func (p *progressBar) draw() {
+	filled := progressWidth * p.last.Done() / max(p.last.Discovered, 1)
+	fmt.Fprintf(p.out, "\r\033[K%s [%s%s] %d/%d files (%d ingested, %d skipped, %d failed)",
+		p.last.Source, strings.Repeat("#", filled), strings.Repeat(".", progressWidth-filled),
+		p.last.Done(), p.last.Discovered, p.last.Ingested, p.last.Skipped, p.last.Failed)
+}
@@ -0,0 +1,195 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/kazemisoroush/assistant/pkg/handler"
+	"github.com/kazemisoroush/assistant/pkg/records"
+	"github.com/kazemisoroush/assistant/pkg/records/discovery"
+)
+
+// Output formats of the record commands
+const (
+	outputText = "text"
+	outputJSON = "json"
+)
+
+func init() {
+	commands[handler.SimpleSearchCommandType] = runSearch
+	commands[handler.GetCommandType] = runGet
+	commands[handler.ListCommandType] = runList
+	commands[handler.DeleteCommandType] = runDelete
+}
+
+// outputFlag adds the output format flag to the command's flags
+func outputFlag(flags *flag.FlagSet) *string {
+	return flags.String("output", outputText, "output format: "+outputText+" or "+outputJSON)
+}
+
+// runSearch finds records matching a prompt
+func runSearch(ctx context.Context, a *app, command string, args []string) error {
+	flags := flag.NewFlagSet(command, flag.ContinueOnError)
+	limit := flags.Int("limit", handler.DefaultSearchLimit, "maximum number of records to find")
+	output := outputFlag(flags)
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() == 0 {
+		fmt.Fprintf(os.Stderr, "Usage: %s %s [--limit N] [--output FORMAT] <prompt>\n", os.Args[0], command)
+		return fmt.Errorf("search prompt is required")
+	}
+
+	recordDiscovery, err := a.discovery()
+	if err != nil {
+		return err
+	}
+
+	hand := handler.NewSimpleSearchHandler(recordDiscovery)
+	resp, err := hand.Handle(ctx, handler.Request{
+		Command: handler.SimpleSearchCommandType,
+		Data:    handler.SearchRequest{Prompt: strings.Join(flags.Args(), " "), Limit: *limit},
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "Search command failed", "error", err)
+		return err
+	}
+
+	hits, _ := resp.Data.([]discovery.Hit)
+	return printOutput(*output, hits, "search results", func(w io.Writer) {
+		for _, hit := range hits {
+			fmt.Fprintf(w, "%s\t%.2f\t%s\n", hit.RecordID, hit.Score, firstLine(hit.Description))
+		}
+	})
+}
+
+// runGet prints a record
+func runGet(ctx context.Context, a *app, command string, args []string) error {
+	flags := flag.NewFlagSet(command, flag.ContinueOnError)
+	output := outputFlag(flags)
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		fmt.Fprintf(os.Stderr, "Usage: %s %s [--output FORMAT] <record-id>\n", os.Args[0], command)
+		return fmt.Errorf("record ID is required")
+	}
+
+	recordStorage, err := a.storage()
+	if err != nil {
+		return err
+	}
+
+	hand := handler.NewGetRecordHandler(recordStorage)
+	resp, err := hand.Handle(ctx, handler.Request{
+		Command: handler.GetCommandType,
+		Data:    flags.Arg(0),
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "Get command failed", "error", err)
+		return err
+	}
+
+	rec, _ := resp.Data.(records.Record)
+	return printOutput(*output, rec, "record", func(w io.Writer) {
+		fmt.Fprintf(w, "ID:\t%s\nType:\t%s\nCreated:\t%s\nUpdated:\t%s\n", rec.ID, rec.Type, rec.CreatedAt.Format(time.DateTime), rec.UpdatedAt.Format(time.DateTime))
+		if len(rec.Tags) > 0 {
+			fmt.Fprintf(w, "Tags:\t%s\n", strings.Join(rec.Tags, ", "))
+		}
+		for key, value := range rec.Metadata {
+			fmt.Fprintf(w, "%s:\t%v\n", key, value)
+		}
+		fmt.Fprintf(w, "\n%s\n", rec.Content)
+	})
+}
+
+// runList prints stored records, newest last
+func runList(ctx context.Context, a *app, command string, args []string) error {
+	flags := flag.NewFlagSet(command, flag.ContinueOnError)
+	recType := flags.String("type", "", "list only records of this type")
+	limit := flags.Int("limit", 0, "maximum number of records to list, 0 for all")
+	output := outputFlag(flags)
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	recordStorage, err := a.storage()
+	if err != nil {
+		return err
+	}
+
+	hand := handler.NewListRecordsHandler(recordStorage)
+	resp, err := hand.Handle(ctx, handler.Request{
+		Command: handler.ListCommandType,
+		Data:    handler.ListRequest{Type: records.RecordType(*recType), Limit: *limit},
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "List command failed", "error", err)
+		return err
+	}
+
+	recs, _ := resp.Data.([]records.Record)
+	return printOutput(*output, recs, "records", func(w io.Writer) {
+		for _, rec := range recs {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", rec.ID, rec.Type, rec.UpdatedAt.Format(time.DateOnly), firstLine(rec.Content))
+		}
+	})
+}
+
+// runDelete deletes a record
+func runDelete(ctx context.Context, a *app, command string, args []string) error {
+	if len(args) != 1 {
+		fmt.Fprintf(os.Stderr, "Usage: %s %s <record-id>\n", os.Args[0], command)
+		return fmt.Errorf("record ID is required")
+	}
+
+	recordStorage, err := a.storage()
+	if err != nil {
+		return err
+	}
+	recordIngestor, err := a.ingestor()
+	if err != nil {
+		return err
+	}
+
+	hand := handler.NewDeleteRecordHandler(recordStorage, recordIngestor)
+	if _, err := hand.Handle(ctx, handler.Request{
+		Command: handler.DeleteCommandType,
+		Data:    args[0],
+	}); err != nil {
+		slog.ErrorContext(ctx, "Delete command failed", "error", err)
+		return err
+	}
+	fmt.Printf("Deleted %s\n", args[0])
+	return nil
+}
+
+// printOutput prints command output as JSON, or as aligned text written by text
+func printOutput(format string, data any, what string, text func(w io.Writer)) error {
+	switch format {
+	case outputJSON:
+		return printJSON(data, what)
+	case outputText:
+		w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		text(w)
+		return w.Flush()
+	default:
+		return fmt.Errorf("unknown output format %q", format)
+	}
+}
+
+// firstLine returns the first line of the text, shortened for listing
+func firstLine(text string) string {
+	line, _, _ := strings.Cut(strings.TrimSpace(text), "\n")
+	if runes := []rune(line); len(runes) > 80 {
+		return string(runes[:79]) + "…"
+	}
+	return line
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+
+	"github.com/kazemisoroush/assistant/pkg/api"
+	"github.com/kazemisoroush/assistant/pkg/config"
+	"github.com/kazemisoroush/assistant/pkg/records/storage"
+	"github.com/kazemisoroush/assistant/pkg/requestid"
+	"github.com/kazemisoroush/assistant/pkg/sqlite"
+)
+
+// remoteServerCommand serves the storage API for clients of the remote storage backend until interrupted
+const remoteServerCommand = "remote-server"
+
+func init() {
+	setupCommands[remoteServerCommand] = runRemoteServer
+	serverCommands[remoteServerCommand] = true
+}
+
+// runRemoteServer keeps the records of remote storage clients in the records
+// database. Clients encrypt records before sending them, so the server needs
+// neither keys nor AI providers and only wires the storage.
+func runRemoteServer(ctx context.Context, cfg config.Config, command string, _ []string) error {
+	if cfg.Remote.Token == "" {
+		fmt.Fprintf(os.Stderr, "The %s command requires REMOTE_TOKEN to be set\n", command)
+		return fmt.Errorf("remote server is not configured")
+	}
+	// Records are stored as received: their metadata is sealed, so it cannot be validated
+	db, err := sqlite.Open(cfg.SQLitePath)
+	if err != nil {
+		return fmt.Errorf("failed to open database: %w", err)
+	}
+	defer func() { _ = db.Close() }()
+	recordStorage, err := storage.NewSQLiteStorage(db)
+	if err != nil {
+		return fmt.Errorf("failed to initialize storage: %w", err)
+	}
+
+	storageHandler := api.RequireToken(cfg.Remote.Token, api.NewStorageHandler(recordStorage))
+	mux := http.NewServeMux()
+	mux.Handle(api.StoragePath, storageHandler)
+	mux.Handle(api.StorageRecordPath, storageHandler)
+	server := &http.Server{Addr: cfg.Remote.Addr, Handler: requestid.Middleware(mux), ReadHeaderTimeout: shutdownTimeout}
+	slog.InfoContext(ctx, "Serving storage API", "addr", cfg.Remote.Addr, "path", api.StoragePath)
+	return serve(ctx, "storage API", server)
+}
+
+// serve runs the server until ctx is done, then stops it once the requests
+// in flight are answered
+func serve(ctx context.Context, name string, server *http.Server) error {
+	serveErr := make(chan error, 1)
+	go func() { serveErr <- server.ListenAndServe() }()
+
+	select {
+	case err := <-serveErr:
+		slog.ErrorContext(ctx, "Server failed", "server", name, "error", err)
+		return err
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("failed to stop %s server: %w", name, err)
+	}
+	slog.InfoContext(ctx, "Server stopped", "server", name)
+	return nil
+}
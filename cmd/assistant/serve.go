@@ -0,0 +1,171 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+
+	"github.com/kazemisoroush/assistant/pkg/api"
+	"github.com/kazemisoroush/assistant/pkg/requestid"
+)
+
+// serveCommand serves the HTTP API until interrupted
+const serveCommand = "serve"
+
+func init() {
+	commands[serveCommand] = runServe
+	serverCommands[serveCommand] = true
+}
+
+// runServe serves the records API and the endpoints of the other services.
+// Every route requires the API token except the calendar feed, which
+// calendar applications subscribe to with the feed token in its URL.
+func runServe(ctx context.Context, a *app, command string, _ []string) error {
+	if a.api.Token == "" {
+		fmt.Fprintf(os.Stderr, "The %s command requires API_TOKEN to be set\n", command)
+		return fmt.Errorf("api is not configured")
+	}
+
+	handler, err := apiHandler(a)
+	if err != nil {
+		return err
+	}
+	server := &http.Server{Addr: a.api.Addr, Handler: handler, ReadHeaderTimeout: shutdownTimeout}
+	slog.InfoContext(ctx, "Serving API", "addr", a.api.Addr, "records", api.RecordsPath)
+	return serve(ctx, "API", server)
+}
+
+// apiHandler routes the API requests, requiring the API token on every route
+// but the calendar feed
+func apiHandler(a *app) (http.Handler, error) {
+	mux := http.NewServeMux()
+	if a.calendarToken != "" {
+		builder, err := a.calendar()
+		if err != nil {
+			return nil, err
+		}
+		mux.Handle(api.CalendarPath, api.RequireFeedToken(a.calendarToken, api.NewCalendarHandler(builder)))
+	}
+	routes, err := apiRoutes(a)
+	if err != nil {
+		return nil, err
+	}
+	for path, handler := range routes {
+		mux.Handle(path, api.RequireToken(a.api.Token, handler))
+	}
+	return requestid.Middleware(mux), nil
+}
+
+// apiRoutes maps the routes requiring the API token to their handlers
+func apiRoutes(a *app) (map[string]http.Handler, error) {
+	recordStorage, err := a.storage()
+	if err != nil {
+		return nil, err
+	}
+	recordIngestor, err := a.ingestor()
+	if err != nil {
+		return nil, err
+	}
+	recordDiscovery, err := a.discovery()
+	if err != nil {
+		return nil, err
+	}
+	relationService, err := a.relations()
+	if err != nil {
+		return nil, err
+	}
+	contentExtractor, err := a.extractor()
+	if err != nil {
+		return nil, err
+	}
+	overview, err := a.dashboard()
+	if err != nil {
+		return nil, err
+	}
+	spendAnalyzer, err := a.analytics()
+	if err != nil {
+		return nil, err
+	}
+	subscriptionDetector, err := a.recurring()
+	if err != nil {
+		return nil, err
+	}
+	timelineBuilder, err := a.health()
+	if err != nil {
+		return nil, err
+	}
+	browser, err := a.entities()
+	if err != nil {
+		return nil, err
+	}
+	matcher, err := a.claims()
+	if err != nil {
+		return nil, err
+	}
+	clusterer, err := a.trips()
+	if err != nil {
+		return nil, err
+	}
+	checker, err := a.budgets()
+	if err != nil {
+		return nil, err
+	}
+	tracker, err := a.vehicles()
+	if err != nil {
+		return nil, err
+	}
+	detector, err := a.duplicates()
+	if err != nil {
+		return nil, err
+	}
+	merger, err := a.merger()
+	if err != nil {
+		return nil, err
+	}
+	recordAnswerer, err := a.answerer()
+	if err != nil {
+		return nil, err
+	}
+	usage, err := a.usage()
+	if err != nil {
+		return nil, err
+	}
+	thumbnailStore, err := a.thumbnails()
+	if err != nil {
+		return nil, err
+	}
+
+	links := api.NewRelationsHandler(relationService)
+	records := api.NewRecordsHandler(recordStorage, recordIngestor, recordDiscovery, relationService)
+	routes := map[string]http.Handler{
+		api.RecordsPath:        records,
+		api.RecordPath:         records,
+		api.RelationsPath:      links,
+		api.RelationPath:       links,
+		api.UploadPath:         api.NewUploadHandler(contentExtractor, recordIngestor),
+		api.DashboardPath:      api.NewDashboardHandler(overview),
+		api.SpendPath:          api.NewSpendHandler(spendAnalyzer),
+		api.SubscriptionsPath:  api.NewSubscriptionsHandler(subscriptionDetector),
+		api.HealthTimelinePath: api.NewHealthTimelineHandler(timelineBuilder),
+		api.EntitiesPath:       api.NewEntitiesHandler(browser),
+		api.ClaimsPath:         api.NewClaimsHandler(matcher),
+		api.TripsPath:          api.NewTripsHandler(clusterer),
+		api.BudgetsPath:        api.NewBudgetsHandler(checker),
+		api.VehiclesPath:       api.NewVehiclesHandler(tracker),
+		api.DuplicatesPath:     api.NewDuplicatesHandler(detector),
+		api.MergePath:          api.NewMergeHandler(merger),
+		api.AskPath:            api.NewAskHandler(recordAnswerer),
+	}
+	if usage != nil {
+		routes[api.UsagePath] = api.NewUsageHandler(usage)
+	}
+	if thumbnailStore != nil {
+		routes[api.ThumbnailPath] = api.NewThumbnailHandler(thumbnailStore)
+	}
+	if a.api.Debug {
+		routes[api.DebugPath] = api.NewDebugHandler()
+	}
+	return routes, nil
+}
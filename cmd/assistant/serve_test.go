@@ -0,0 +1,132 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kazemisoroush/assistant/pkg/ai"
+	"github.com/kazemisoroush/assistant/pkg/config"
+	"github.com/kazemisoroush/assistant/pkg/thumbnails"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestApp wires an app serving the API with the token "secret", keeping
+// its databases in a temporary working directory
+func newTestApp(t *testing.T) *app {
+	t.Helper()
+	t.Chdir(t.TempDir())
+	cfg, err := config.LoadConfig()
+	require.NoError(t, err, "LoadConfig() error should be nil")
+	cfg.AI.DefaultProvider = ai.ProviderOllama
+	cfg.API = config.APIConfig{Token: "secret"}
+	a := newApp(cfg)
+	t.Cleanup(a.close)
+	return a
+}
+
+func TestAPIHandler_Thumbnail(t *testing.T) {
+	// Arrange
+	store, err := thumbnails.NewFileStore(t.TempDir())
+	require.NoError(t, err)
+	require.NoError(t, store.Put(context.Background(), "ocr-1", []byte("jpeg")))
+	a := newTestApp(t)
+	a.thumbnails = func() (thumbnails.Store, error) { return store, nil }
+	handler, err := apiHandler(a)
+	require.NoError(t, err, "apiHandler() error should be nil")
+
+	// Act
+	anonymous := httptest.NewRecorder()
+	handler.ServeHTTP(anonymous, httptest.NewRequest(http.MethodGet, "/api/v1/records/ocr-1/thumbnail", nil))
+	authorized := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/records/ocr-1/thumbnail", nil)
+	req.Header.Set("Authorization", "Bearer secret")
+	handler.ServeHTTP(authorized, req)
+
+	// Assert
+	assert.Equal(t, http.StatusUnauthorized, anonymous.Code, "apiHandler() should require the API token for thumbnails")
+	assert.Equal(t, http.StatusOK, authorized.Code, "apiHandler() should serve thumbnails")
+	assert.Equal(t, thumbnails.MediaType, authorized.Header().Get("Content-Type"), "apiHandler() should serve a JPEG")
+	assert.Equal(t, "jpeg", authorized.Body.String(), "apiHandler() should return the stored thumbnail")
+}
+
+func TestAPIHandler_Thumbnail_Disabled(t *testing.T) {
+	// Arrange
+	handler, err := apiHandler(newTestApp(t))
+	require.NoError(t, err, "apiHandler() error should be nil")
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/records/ocr-1/thumbnail", nil)
+	req.Header.Set("Authorization", "Bearer secret")
+
+	// Act
+	handler.ServeHTTP(rec, req)
+
+	// Assert
+	assert.NotEqual(t, http.StatusOK, rec.Code, "apiHandler() should not serve thumbnails while they are disabled")
+}
+
+func TestAPIHandler_Debug(t *testing.T) {
+	tests := []struct {
+		name  string
+		debug bool
+		token string
+		want  int
+	}{
+		{name: "enabled", debug: true, token: "secret", want: http.StatusOK},
+		{name: "enabled without token", debug: true, want: http.StatusUnauthorized},
+		{name: "disabled", token: "secret", want: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			a := newTestApp(t)
+			a.api.Debug = tt.debug
+			handler, err := apiHandler(a)
+			require.NoError(t, err, "apiHandler() error should be nil")
+			rec := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/debug/vars", nil)
+			if tt.token != "" {
+				req.Header.Set("Authorization", "Bearer "+tt.token)
+			}
+
+			// Act
+			handler.ServeHTTP(rec, req)
+
+			// Assert
+			assert.Equal(t, tt.want, rec.Code, "apiHandler() should serve the debug endpoints behind the API token only when enabled")
+		})
+	}
+}
+
+func TestAPIHandler_Calendar_Rejected(t *testing.T) {
+	tests := []struct {
+		name          string
+		calendarToken string
+		target        string
+		want          int
+	}{
+		{name: "wrong token", calendarToken: "feed", target: "/api/v1/calendar.ics?token=guess", want: http.StatusUnauthorized},
+		{name: "api token", calendarToken: "feed", target: "/api/v1/calendar.ics?token=secret", want: http.StatusUnauthorized},
+		{name: "disabled", target: "/api/v1/calendar.ics?token=", want: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			a := newTestApp(t)
+			a.calendarToken = tt.calendarToken
+			handler, err := apiHandler(a)
+			require.NoError(t, err, "apiHandler() error should be nil")
+			rec := httptest.NewRecorder()
+
+			// Act
+			handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.target, nil))
+
+			// Assert
+			assert.Equal(t, tt.want, rec.Code, "apiHandler() should serve the calendar feed only with the feed token")
+		})
+	}
+}
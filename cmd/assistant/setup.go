@@ -0,0 +1,146 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/kazemisoroush/assistant/pkg/config"
+)
+
+// Commands run from the configuration alone
+const (
+	helpCommand   = "help"
+	configCommand = "config"
+	doctorCommand = "doctor"
+)
+
+// setupFunc runs a CLI command that needs the configuration but none of the services
+type setupFunc func(ctx context.Context, cfg config.Config, command string, args []string) error
+
+// setupCommands run without wiring the app, so they start without opening
+// the stores or loading the AWS configuration. The help command, listing
+// every command, is run by run itself.
+var setupCommands = map[string]setupFunc{
+	configCommand: runConfig,
+	doctorCommand: runDoctor,
+}
+
+// printUsage writes the usage line and the available commands
+func printUsage(w io.Writer) {
+	names := []string{helpCommand}
+	for name := range commands {
+		names = append(names, name)
+	}
+	for name := range setupCommands {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	fmt.Fprintf(w, "Usage: %s [--config FILE] <command> [arguments]\n\nCommands:\n", os.Args[0])
+	for _, name := range names {
+		fmt.Fprintf(w, "  %s\n", name)
+	}
+}
+
+// runConfig prints every configuration variable with its effective value, masking secrets
+func runConfig(_ context.Context, _ config.Config, _ string, _ []string) error {
+	settings, err := config.Settings()
+	if err != nil {
+		return err
+	}
+	for _, setting := range settings {
+		source := ""
+		if setting.Default {
+			source = " (default)"
+		}
+		fmt.Printf("%s=%s%s\n", setting.Key, setting.Value, source)
+	}
+	return nil
+}
+
+// doctorCheck verifies part of the environment the commands run in
+type doctorCheck struct {
+	name string
+	run  func(ctx context.Context, cfg config.Config) error
+}
+
+// doctorChecks are run by the doctor command in order
+var doctorChecks = []doctorCheck{
+	{name: "database directory is writable", run: checkDatabaseDir},
+	{name: "local source is readable", run: checkLocalSource},
+	{name: "template directories exist", run: checkTemplateDirs},
+	{name: "AWS configuration loads", run: checkAWS},
+}
+
+// runDoctor checks the configuration and the environment, reporting every
+// failed check instead of stopping at the first
+func runDoctor(ctx context.Context, cfg config.Config, _ string, _ []string) error {
+	failed := 0
+	for _, check := range doctorChecks {
+		if err := check.run(ctx, cfg); err != nil {
+			failed++
+			fmt.Printf("FAIL %s: %v\n", check.name, err)
+			continue
+		}
+		fmt.Printf("ok   %s\n", check.name)
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d checks failed", failed, len(doctorChecks))
+	}
+	return nil
+}
+
+// checkDatabaseDir verifies the records database can be created next to its siblings
+func checkDatabaseDir(_ context.Context, cfg config.Config) error {
+	dir := filepath.Dir(cfg.SQLitePath)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	file, err := os.CreateTemp(dir, ".doctor-*")
+	if err != nil {
+		return err
+	}
+	_ = file.Close()
+	return os.Remove(file.Name())
+}
+
+// checkLocalSource verifies the base path of the local source is a readable directory
+func checkLocalSource(_ context.Context, cfg config.Config) error {
+	if !cfg.Sources.Local.Enabled {
+		return nil
+	}
+	_, err := os.ReadDir(cfg.Sources.Local.BasePath)
+	return err
+}
+
+// checkTemplateDirs verifies the configured prompt and notification template directories exist
+func checkTemplateDirs(_ context.Context, cfg config.Config) error {
+	for _, dir := range []string{cfg.AI.PromptsDir, cfg.Notifications.TemplatesDir} {
+		if dir == "" {
+			continue
+		}
+		if _, err := os.ReadDir(dir); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkAWS verifies the AWS configuration loads with a region when Bedrock is used
+func checkAWS(ctx context.Context, cfg config.Config) error {
+	if !usesBedrock(cfg) {
+		return nil
+	}
+	awsConfig, err := config.LoadAWSConfig(ctx)
+	if err != nil {
+		return err
+	}
+	if awsConfig.Region == "" && cfg.AI.Bedrock.Region == "" {
+		return fmt.Errorf("no AWS region is configured")
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/kazemisoroush/assistant/pkg/config"
+	"github.com/kazemisoroush/assistant/pkg/records/discovery"
+	"github.com/kazemisoroush/assistant/pkg/records/extractor"
+	"github.com/kazemisoroush/assistant/pkg/records/ingestor"
+	"github.com/kazemisoroush/assistant/pkg/requestid"
+	"github.com/kazemisoroush/assistant/pkg/slack"
+)
+
+// slackCommand serves the Slack app until interrupted
+const slackCommand = "slack"
+
+// shutdownTimeout bounds how long a stopping server waits for requests in flight
+const shutdownTimeout = 10 * time.Second
+
+// newSlackHandler builds the Slack app handler, or returns nil when the bot
+// token or signing secret is not configured
+func newSlackHandler(cfg config.Config, httpClient *http.Client, recordDiscovery discovery.Discovery, contentExtractor extractor.ContentExtractor, recordIngestor ingestor.Ingestor) *slack.Handler {
+	if cfg.Slack.BotToken == "" || cfg.Slack.SigningSecret == "" {
+		return nil
+	}
+	client := slack.NewAPIClient(httpClient, slack.APIConfig{Token: cfg.Slack.BotToken})
+	return slack.NewHandler(slack.Config{
+		SigningSecret: cfg.Slack.SigningSecret,
+		Channel:       cfg.Slack.Channel,
+		RecordURL:     cfg.Slack.RecordURL,
+		Timeout:       cfg.Timeout,
+	}, client, recordDiscovery, contentExtractor, recordIngestor)
+}
+
+func init() {
+	commands[slackCommand] = runSlack
+	serverCommands[slackCommand] = true
+}
+
+// runSlack serves the slash command and event endpoints of the Slack app.
+// Interrupting stops the server once the searches and uploads it
+// acknowledged are answered.
+func runSlack(ctx context.Context, a *app, command string, _ []string) error {
+	slackHandler, err := a.slack()
+	if err != nil {
+		return err
+	}
+	if slackHandler == nil {
+		fmt.Fprintf(os.Stderr, "The %s command requires SLACK_BOT_TOKEN and SLACK_SIGNING_SECRET to be set\n", command)
+		return fmt.Errorf("slack is not configured")
+	}
+
+	server := &http.Server{Addr: a.slackAddr, Handler: requestid.Middleware(slackHandler), ReadHeaderTimeout: shutdownTimeout}
+	serveErr := make(chan error, 1)
+	go func() { serveErr <- server.ListenAndServe() }()
+	slog.InfoContext(ctx, "Serving Slack app", "addr", a.slackAddr, "commands", slack.CommandsPath, "events", slack.EventsPath)
+
+	select {
+	case err := <-serveErr:
+		slog.ErrorContext(ctx, "Slack server failed", "error", err)
+		return err
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	err = server.Shutdown(shutdownCtx)
+	slackHandler.Wait()
+	if err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("failed to stop slack server: %w", err)
+	}
+	slog.InfoContext(ctx, "Slack app stopped")
+	return nil
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+
+	"github.com/kazemisoroush/assistant/pkg/api"
+	"github.com/kazemisoroush/assistant/pkg/config"
+	"github.com/kazemisoroush/assistant/pkg/handler"
+	"github.com/kazemisoroush/assistant/pkg/peersync"
+	"github.com/kazemisoroush/assistant/pkg/records/ingestor"
+	"github.com/kazemisoroush/assistant/pkg/records/storage"
+	"github.com/kazemisoroush/assistant/pkg/requestid"
+)
+
+// syncServerCommand serves this instance's changes to its peers until interrupted
+const syncServerCommand = "sync-server"
+
+// syncService holds what the sync commands use
+type syncService struct {
+	replica *peersync.LocalReplica
+	syncer  peersync.Syncer
+	peers   []peersync.Peer
+	token   string
+	addr    string
+}
+
+// syncNode returns the ID the instance logs its changes under
+func syncNode(cfg config.Config) string {
+	if cfg.Sync.Node != "" {
+		return cfg.Sync.Node
+	}
+	host, err := os.Hostname()
+	if err != nil {
+		return "assistant"
+	}
+	return host
+}
+
+// trackChanges logs every change to the records for peers to pull, unless
+// the sync token is not configured
+func trackChanges(cfg config.Config, recordStorage storage.Storage, changes peersync.Log) storage.Storage {
+	if cfg.Sync.Token == "" {
+		return recordStorage
+	}
+	return peersync.NewTrackingStorage(recordStorage, changes, syncNode(cfg))
+}
+
+// newSyncService builds the replica of the records and the configured peers,
+// or returns nil when the sync token is not configured
+func newSyncService(cfg config.Config, httpClient *http.Client, recordStorage storage.Storage, recordIngestor ingestor.Ingestor, changes peersync.Log) *syncService {
+	if cfg.Sync.Token == "" {
+		return nil
+	}
+	replica := peersync.NewLocalReplica(recordStorage, recordIngestor, changes, syncNode(cfg))
+	service := &syncService{
+		replica: replica,
+		syncer:  peersync.NewReplicaSyncer(replica, changes, cfg.Sync.BatchSize),
+		token:   cfg.Sync.Token,
+		addr:    cfg.Sync.Addr,
+	}
+	for _, peer := range cfg.Sync.Peers {
+		service.peers = append(service.peers, peersync.Peer{Name: peer, Replica: peersync.NewHTTPPeer(httpClient, peer, cfg.Sync.Token)})
+	}
+	return service
+}
+
+func init() {
+	commands[handler.SyncCommandType] = runSync
+	commands[syncServerCommand] = runSyncServer
+	serverCommands[syncServerCommand] = true
+}
+
+// runSync pulls the changes of every peer, then pushes the local ones
+func runSync(ctx context.Context, a *app, command string, _ []string) error {
+	service, err := a.sync()
+	if err != nil {
+		return err
+	}
+	if service == nil {
+		fmt.Fprintf(os.Stderr, "The %s command requires SYNC_TOKEN and SYNC_PEERS to be set\n", command)
+		return fmt.Errorf("sync is not configured")
+	}
+	if _, err := service.replica.Backfill(ctx); err != nil {
+		slog.ErrorContext(ctx, "Sync command failed", "error", err)
+		return err
+	}
+
+	hand := handler.NewSyncHandler(service.syncer, service.peers)
+	resp, err := hand.Handle(ctx, handler.Request{Command: handler.SyncCommandType})
+	if err != nil {
+		slog.ErrorContext(ctx, "Sync command failed", "error", err, "reports", resp.Data)
+		return err
+	}
+	slog.InfoContext(ctx, "Sync command completed", "reports", resp.Data)
+	return nil
+}
+
+// runSyncServer serves the changes of this instance to its peers and
+// applies theirs until interrupted
+func runSyncServer(ctx context.Context, a *app, command string, _ []string) error {
+	service, err := a.sync()
+	if err != nil {
+		return err
+	}
+	if service == nil {
+		fmt.Fprintf(os.Stderr, "The %s command requires SYNC_TOKEN to be set\n", command)
+		return fmt.Errorf("sync is not configured")
+	}
+	logged, err := service.replica.Backfill(ctx)
+	if err != nil {
+		return err
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle(api.SyncPath, api.RequireToken(service.token, api.NewSyncHandler(service.replica)))
+	server := &http.Server{Addr: service.addr, Handler: requestid.Middleware(mux), ReadHeaderTimeout: shutdownTimeout}
+	slog.InfoContext(ctx, "Serving sync", "addr", service.addr, "path", api.SyncPath, "backfilled", logged)
+	return serve(ctx, "sync", server)
+}
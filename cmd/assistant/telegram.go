@@ -0,0 +1,56 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+
+	"github.com/kazemisoroush/assistant/pkg/agent"
+	"github.com/kazemisoroush/assistant/pkg/config"
+	"github.com/kazemisoroush/assistant/pkg/records/discovery"
+	"github.com/kazemisoroush/assistant/pkg/records/storage"
+	"github.com/kazemisoroush/assistant/pkg/telegram"
+)
+
+// telegramCommand runs the Telegram bot until interrupted
+const telegramCommand = "telegram"
+
+// newTelegramBot builds the Telegram bot, or returns nil when the bot token
+// or the allowed chats are not configured
+func newTelegramBot(cfg config.Config, httpClient *http.Client, recordDiscovery discovery.Discovery, recordAgent agent.Agent, recordStorage storage.Storage) *telegram.Bot {
+	if cfg.Telegram.BotToken == "" || len(cfg.Telegram.AllowedChats) == 0 {
+		return nil
+	}
+	client := telegram.NewBotAPIClient(httpClient, telegram.APIConfig{Token: cfg.Telegram.BotToken})
+	return telegram.NewBot(telegram.Config{
+		AllowedChats: cfg.Telegram.AllowedChats,
+		PollTimeout:  cfg.Telegram.PollTimeout,
+		Timeout:      cfg.Timeout,
+	}, client, recordDiscovery, recordAgent, recordStorage)
+}
+
+func init() {
+	commands[telegramCommand] = runTelegram
+	serverCommands[telegramCommand] = true
+}
+
+// runTelegram answers the searches and questions sent to the Telegram bot
+func runTelegram(ctx context.Context, a *app, command string, _ []string) error {
+	bot, err := a.telegram()
+	if err != nil {
+		return err
+	}
+	if bot == nil {
+		fmt.Fprintf(os.Stderr, "The %s command requires TELEGRAM_BOT_TOKEN and TELEGRAM_ALLOWED_CHATS to be set\n", command)
+		return fmt.Errorf("telegram is not configured")
+	}
+	slog.InfoContext(ctx, "Running Telegram bot")
+	if err := bot.Run(ctx); err != nil {
+		slog.ErrorContext(ctx, "Telegram bot failed", "error", err)
+		return err
+	}
+	slog.InfoContext(ctx, "Telegram bot stopped")
+	return nil
+}
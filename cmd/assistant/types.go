@@ -0,0 +1,132 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/kazemisoroush/assistant/pkg/handler"
+	"github.com/kazemisoroush/assistant/pkg/records"
+)
+
+func init() {
+	commands[handler.TypesCommandType] = runTypes
+}
+
+// runTypes lists, adds or removes user-defined record types, or attaches a metadata schema to a record type
+func runTypes(ctx context.Context, a *app, command string, args []string) error {
+	typeStore, err := a.types()
+	if err != nil {
+		return err
+	}
+
+	var (
+		hand handler.Handler
+		data any
+	)
+	switch {
+	case len(args) == 0:
+		hand = handler.NewTypesHandler(typeStore)
+	case args[0] == handler.TypesAddSubcommand:
+		hand = handler.NewDefineTypeHandler(typeStore)
+		data, err = parseTypeDefinition(command, args[1:])
+	case args[0] == handler.TypesRemoveSubcommand && len(args) == 2:
+		hand = handler.NewRemoveTypeHandler(typeStore)
+		data = args[1]
+	case args[0] == handler.TypesSchemaSubcommand && len(args) >= 2:
+		hand = handler.NewSetSchemaHandler(typeStore)
+		data, err = parseSchema(command, records.RecordType(args[1]), args[2:])
+	default:
+		fmt.Fprintf(os.Stderr, "Usage: %s %s [%s --name NAME --description TEXT [--hints A,B] [--field NAME:TYPE[:DESCRIPTION]]... | %s NAME | %s TYPE [--field NAME:TYPE[:DESCRIPTION]]... [--required A,B] [--enum NAME=A|B]...]\n",
+			os.Args[0], command, handler.TypesAddSubcommand, handler.TypesRemoveSubcommand, handler.TypesSchemaSubcommand)
+		return fmt.Errorf("invalid types arguments")
+	}
+	if err != nil {
+		return err
+	}
+
+	resp, err := hand.Handle(ctx, handler.Request{
+		Command: handler.TypesCommandType,
+		Data:    data,
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "Types command failed", "error", err)
+		return err
+	}
+
+	return printJSON(resp.Data, "record types")
+}
+
+// parseTypeDefinition reads a record type definition from the add flags
+func parseTypeDefinition(command string, args []string) (records.TypeDefinition, error) {
+	var def records.TypeDefinition
+	flags := flag.NewFlagSet(command, flag.ContinueOnError)
+	name := flags.String("name", "", "name of the record type, e.g. pet")
+	flags.StringVar(&def.Description, "description", "", "what records of the type are, used during classification")
+	hints := flags.String("hints", "", "comma-separated words or phrases that identify the type")
+	flags.Func("field", fieldUsage, fieldFlag(&def.Fields))
+	if err := flags.Parse(args); err != nil {
+		return records.TypeDefinition{}, err
+	}
+
+	def.Name = records.RecordType(*name)
+	for _, hint := range strings.Split(*hints, ",") {
+		if hint = strings.TrimSpace(hint); hint != "" {
+			def.Hints = append(def.Hints, hint)
+		}
+	}
+	return def, nil
+}
+
+// fieldUsage describes the repeatable field flag
+const fieldUsage = "metadata field as NAME:TYPE[:DESCRIPTION] with TYPE string, number, date or bool; repeatable"
+
+// fieldFlag parses a field flag value and appends the field to fields
+func fieldFlag(fields *[]records.FieldSchema) func(string) error {
+	return func(value string) error {
+		parts := strings.SplitN(value, ":", 3)
+		if len(parts) < 2 {
+			return fmt.Errorf("field must be NAME:TYPE[:DESCRIPTION]: %q", value)
+		}
+		field := records.FieldSchema{Name: parts[0], Type: records.FieldType(parts[1])}
+		if len(parts) == 3 {
+			field.Description = parts[2]
+		}
+		*fields = append(*fields, field)
+		return nil
+	}
+}
+
+// parseSchema reads the metadata schema of a record type from the schema
+// flags. Without fields the schema is removed.
+func parseSchema(command string, recordType records.RecordType, args []string) (records.Schema, error) {
+	schema := records.Schema{Type: recordType}
+	enums := map[string][]string{}
+	flags := flag.NewFlagSet(command, flag.ContinueOnError)
+	flags.Func("field", fieldUsage, fieldFlag(&schema.Fields))
+	required := flags.String("required", "", "comma-separated fields that must be present and not empty")
+	flags.Func("enum", "allowed values of a string field as NAME=A|B; repeatable", func(value string) error {
+		name, values, ok := strings.Cut(value, "=")
+		if !ok || values == "" {
+			return fmt.Errorf("enum must be NAME=A|B: %q", value)
+		}
+		enums[name] = strings.Split(values, "|")
+		return nil
+	})
+	if err := flags.Parse(args); err != nil {
+		return records.Schema{}, err
+	}
+
+	names := map[string]bool{}
+	for _, name := range strings.Split(*required, ",") {
+		names[strings.TrimSpace(name)] = true
+	}
+	for i, field := range schema.Fields {
+		schema.Fields[i].Required = names[field.Name]
+		schema.Fields[i].Enum = enums[field.Name]
+	}
+	return schema, nil
+}
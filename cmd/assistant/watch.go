@@ -0,0 +1,66 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/kazemisoroush/assistant/pkg/config"
+	"github.com/kazemisoroush/assistant/pkg/handler"
+	"github.com/kazemisoroush/assistant/pkg/records/extractor"
+	"github.com/kazemisoroush/assistant/pkg/records/ledger"
+	"github.com/kazemisoroush/assistant/pkg/records/source"
+	"github.com/kazemisoroush/assistant/pkg/records/watcher"
+)
+
+// watchCommand ingests files as they are added or modified until interrupted
+const watchCommand = "watch"
+
+func init() {
+	commands[watchCommand] = runWatch
+	serverCommands[watchCommand] = true
+}
+
+// newWatchSource builds the source extracting records from the watched
+// directories, which default to the local source's base path. Files are
+// checked against the scan ledger the local source keeps.
+func newWatchSource(cfg config.Config, contentExtractor extractor.ContentExtractor, scans ledger.Store) source.Source {
+	paths := cfg.Watch.Paths
+	if len(paths) == 0 {
+		paths = []string{cfg.Sources.Local.BasePath}
+	}
+	fileWatcher := watcher.NewPollingWatcher(watcher.PollingConfig{
+		Paths:    paths,
+		Exclude:  []string{cfg.Watch.QuarantineDir},
+		Interval: cfg.Watch.Interval,
+		Debounce: cfg.Watch.Debounce,
+	})
+	return source.NewWatchSource(fileWatcher, contentExtractor, scans, source.WatchConfig{
+		QuarantineDir: cfg.Watch.QuarantineDir,
+		Buffers:       source.Buffers{Records: cfg.Sources.RecordBuffer, Errors: cfg.Sources.ErrorBuffer},
+	})
+}
+
+// runWatch ingests the files added to or modified in the watched directories
+// through the scrape's ingest stage and ingestor, which replaces the record
+// of a modified file in the scan ledger. Files present when it starts are
+// left to the scrape command.
+func runWatch(ctx context.Context, a *app, _ string, _ []string) error {
+	recordIngestor, err := a.ingestor()
+	if err != nil {
+		return err
+	}
+	watch, err := a.watch()
+	if err != nil {
+		return err
+	}
+
+	slog.InfoContext(ctx, "Watching for new files")
+	hand := handler.NewLocalScraperHandler(recordIngestor, []source.Source{watch}, a.ingest, a.batch, nil)
+	resp, err := hand.Handle(ctx, handler.Request{Command: watchCommand})
+	if err != nil {
+		slog.ErrorContext(ctx, "Watch command failed", "error", err)
+		return err
+	}
+	slog.InfoContext(ctx, "Watch command stopped", "response", resp)
+	return nil
+}
@@ -0,0 +1,54 @@
+// Package agent answers multi-step questions by letting an LLM agent call back
+// into the records service through action groups.
+package agent
+
+import "context"
+
+// Agent answers a natural-language question, orchestrating action group calls as needed
+//
+//go:generate mockgen -destination=./mocks/mock_agent.go -mock_names=Agent=MockAgent -package=mocks . Agent
+type Agent interface {
+	// Ask returns the agent's final answer to the question
+	Ask(ctx context.Context, question string) (string, error)
+}
+
+// ActionGroup is a set of functions the agent may invoke
+//
+//go:generate mockgen -destination=./mocks/mock_actiongroup.go -mock_names=ActionGroup=MockActionGroup -package=mocks . ActionGroup
+type ActionGroup interface {
+	// Name returns the action group name
+	Name() string
+
+	// Description tells the agent when to use the action group
+	Description() string
+
+	// Functions returns the function definitions exposed to the agent
+	Functions() []Function
+
+	// Invoke runs a function with the parameters chosen by the agent and returns its result body
+	Invoke(ctx context.Context, function string, params map[string]string) (string, error)
+}
+
+// ParameterType is the type of a function parameter
+type ParameterType string
+
+// Parameter type constants
+const (
+	ParameterTypeString  ParameterType = "string"
+	ParameterTypeNumber  ParameterType = "number"
+	ParameterTypeInteger ParameterType = "integer"
+)
+
+// Function describes a function the agent may call
+type Function struct {
+	Name        string
+	Description string
+	Parameters  map[string]Parameter
+}
+
+// Parameter describes a single function parameter
+type Parameter struct {
+	Type        ParameterType
+	Description string
+	Required    bool
+}
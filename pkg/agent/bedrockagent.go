@@ -0,0 +1,235 @@
+package agent
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockagentruntime"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockagentruntime/types"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+)
+
+const (
+	// maxTurns bounds the number of return-control round trips for a single question
+	maxTurns = 10
+
+	// instruction is the agent's system instruction
+	instruction = "You are a personal records assistant. Answer the user's question using only the records " +
+		"returned by the available functions. Search before answering, use aggregation for totals and counts, " +
+		"and say so when the records do not contain the answer."
+)
+
+// InlineAgentClient invokes a Bedrock inline agent
+//
+//go:generate mockgen -destination=./mocks/mock_inlineagentclient.go -mock_names=InlineAgentClient=MockInlineAgentClient -package=mocks . InlineAgentClient
+type InlineAgentClient interface {
+	InvokeInlineAgent(ctx context.Context, input *bedrockagentruntime.InvokeInlineAgentInput) (EventStream, error)
+}
+
+// EventStream is the response event stream of an inline agent invocation
+//
+//go:generate mockgen -destination=./mocks/mock_eventstream.go -mock_names=EventStream=MockEventStream -package=mocks . EventStream
+type EventStream interface {
+	Events() <-chan types.InlineAgentResponseStream
+	Close() error
+	Err() error
+}
+
+// sdkInlineAgentClient adapts the Bedrock agent runtime client to InlineAgentClient
+type sdkInlineAgentClient struct {
+	client *bedrockagentruntime.Client
+}
+
+// NewInlineAgentClient creates an inline agent client whose calls run under the
+// given service role
+func NewInlineAgentClient(cfg aws.Config, serviceRoleARN string) InlineAgentClient {
+	cfg.Credentials = aws.NewCredentialsCache(stscreds.NewAssumeRoleProvider(sts.NewFromConfig(cfg), serviceRoleARN))
+	return &sdkInlineAgentClient{client: bedrockagentruntime.NewFromConfig(cfg)}
+}
+
+// InvokeInlineAgent implements InlineAgentClient
+func (c *sdkInlineAgentClient) InvokeInlineAgent(ctx context.Context, input *bedrockagentruntime.InvokeInlineAgentInput) (EventStream, error) {
+	output, err := c.client.InvokeInlineAgent(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+	return output.GetStream(), nil
+}
+
+// BedrockAgent answers questions with a Bedrock inline agent. Action groups use
+// return control, so their functions run locally against the records service.
+type BedrockAgent struct {
+	client       InlineAgentClient
+	model        string
+	actionGroups []ActionGroup
+}
+
+// NewBedrockAgent creates a new Bedrock agent
+func NewBedrockAgent(client InlineAgentClient, model string, actionGroups ...ActionGroup) Agent {
+	return &BedrockAgent{
+		client:       client,
+		model:        model,
+		actionGroups: actionGroups,
+	}
+}
+
+// Ask returns the agent's final answer to the question
+func (b *BedrockAgent) Ask(ctx context.Context, question string) (string, error) {
+	sessionID, err := newSessionID()
+	if err != nil {
+		return "", err
+	}
+
+	input := b.input(sessionID)
+	input.InputText = aws.String(question)
+
+	for range maxTurns {
+		answer, control, err := b.invoke(ctx, input)
+		if err != nil {
+			return "", err
+		}
+		if control == nil {
+			return answer, nil
+		}
+
+		input = b.input(sessionID)
+		input.InlineSessionState = &types.InlineSessionState{
+			InvocationId:                   control.InvocationId,
+			ReturnControlInvocationResults: b.runFunctions(ctx, control.InvocationInputs),
+		}
+	}
+
+	return "", fmt.Errorf("agent did not answer within %d turns", maxTurns)
+}
+
+// input builds the invocation input shared by every turn of a session
+func (b *BedrockAgent) input(sessionID string) *bedrockagentruntime.InvokeInlineAgentInput {
+	return &bedrockagentruntime.InvokeInlineAgentInput{
+		FoundationModel: aws.String(b.model),
+		Instruction:     aws.String(instruction),
+		SessionId:       aws.String(sessionID),
+		ActionGroups:    b.agentActionGroups(),
+	}
+}
+
+// invoke runs one turn and returns either the answer text or the functions the agent wants called
+func (b *BedrockAgent) invoke(ctx context.Context, input *bedrockagentruntime.InvokeInlineAgentInput) (string, *types.InlineAgentReturnControlPayload, error) {
+	stream, err := b.client.InvokeInlineAgent(ctx, input)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to invoke Bedrock agent: %w", err)
+	}
+	defer func() {
+		if err := stream.Close(); err != nil {
+			slog.WarnContext(ctx, "Failed to close Bedrock agent stream", "error", err)
+		}
+	}()
+
+	var answer strings.Builder
+	var control *types.InlineAgentReturnControlPayload
+	for event := range stream.Events() {
+		switch e := event.(type) {
+		case *types.InlineAgentResponseStreamMemberChunk:
+			answer.Write(e.Value.Bytes)
+		case *types.InlineAgentResponseStreamMemberReturnControl:
+			control = &e.Value
+		}
+	}
+	if err := stream.Err(); err != nil {
+		return "", nil, fmt.Errorf("failed to read Bedrock agent stream: %w", err)
+	}
+
+	return answer.String(), control, nil
+}
+
+// runFunctions executes the requested functions. Failures are reported back to
+// the agent so it can correct its parameters instead of aborting the question.
+func (b *BedrockAgent) runFunctions(ctx context.Context, inputs []types.InvocationInputMember) []types.InvocationResultMember {
+	results := make([]types.InvocationResultMember, 0, len(inputs))
+	for _, input := range inputs {
+		call, ok := input.(*types.InvocationInputMemberMemberFunctionInvocationInput)
+		if !ok {
+			continue
+		}
+
+		groupName := aws.ToString(call.Value.ActionGroup)
+		function := aws.ToString(call.Value.Function)
+		result := types.FunctionResult{
+			ActionGroup: call.Value.ActionGroup,
+			Function:    call.Value.Function,
+		}
+
+		body, err := b.runFunction(ctx, groupName, function, call.Value.Parameters)
+		if err != nil {
+			slog.WarnContext(ctx, "Agent function call failed", "action_group", groupName, "function", function, "error", err)
+			body = err.Error()
+			result.ResponseState = types.ResponseStateReprompt
+		}
+		result.ResponseBody = map[string]types.ContentBody{"TEXT": {Body: aws.String(body)}}
+
+		results = append(results, &types.InvocationResultMemberMemberFunctionResult{Value: result})
+	}
+	return results
+}
+
+func (b *BedrockAgent) runFunction(ctx context.Context, groupName, function string, parameters []types.FunctionParameter) (string, error) {
+	idx := slices.IndexFunc(b.actionGroups, func(g ActionGroup) bool { return g.Name() == groupName })
+	if idx < 0 {
+		return "", fmt.Errorf("unknown action group %s", groupName)
+	}
+	group := b.actionGroups[idx]
+
+	params := make(map[string]string, len(parameters))
+	for _, p := range parameters {
+		params[aws.ToString(p.Name)] = aws.ToString(p.Value)
+	}
+	return group.Invoke(ctx, function, params)
+}
+
+// agentActionGroups converts the action groups to their return-control Bedrock definitions
+func (b *BedrockAgent) agentActionGroups() []types.AgentActionGroup {
+	groups := make([]types.AgentActionGroup, 0, len(b.actionGroups))
+	for _, group := range b.actionGroups {
+		functions := make([]types.FunctionDefinition, 0, len(group.Functions()))
+		for _, fn := range group.Functions() {
+			functions = append(functions, functionDefinition(fn))
+		}
+		groups = append(groups, types.AgentActionGroup{
+			ActionGroupName:     aws.String(group.Name()),
+			Description:         aws.String(group.Description()),
+			ActionGroupExecutor: &types.ActionGroupExecutorMemberCustomControl{Value: types.CustomControlMethodReturnControl},
+			FunctionSchema:      &types.FunctionSchemaMemberFunctions{Value: functions},
+		})
+	}
+	return groups
+}
+
+func functionDefinition(fn Function) types.FunctionDefinition {
+	params := make(map[string]types.ParameterDetail, len(fn.Parameters))
+	for name, p := range fn.Parameters {
+		params[name] = types.ParameterDetail{
+			Type:        types.ParameterType(p.Type),
+			Description: aws.String(p.Description),
+			Required:    aws.Bool(p.Required),
+		}
+	}
+	return types.FunctionDefinition{
+		Name:        aws.String(fn.Name),
+		Description: aws.String(fn.Description),
+		Parameters:  params,
+	}
+}
+
+func newSessionID() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate session ID: %w", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
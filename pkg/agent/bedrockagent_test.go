@@ -0,0 +1,149 @@
+package agent_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockagentruntime"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockagentruntime/types"
+	"github.com/kazemisoroush/assistant/pkg/agent"
+	"github.com/kazemisoroush/assistant/pkg/agent/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func newStream(ctrl *gomock.Controller, events ...types.InlineAgentResponseStream) agent.EventStream {
+	ch := make(chan types.InlineAgentResponseStream, len(events))
+	for _, e := range events {
+		ch <- e
+	}
+	close(ch)
+
+	stream := mocks.NewMockEventStream(ctrl)
+	stream.EXPECT().Events().Return(ch)
+	stream.EXPECT().Err().Return(nil)
+	stream.EXPECT().Close().Return(nil)
+	return stream
+}
+
+func chunk(text string) types.InlineAgentResponseStream {
+	return &types.InlineAgentResponseStreamMemberChunk{Value: types.InlineAgentPayloadPart{Bytes: []byte(text)}}
+}
+
+func returnControl(group, function string, params map[string]string) types.InlineAgentResponseStream {
+	parameters := make([]types.FunctionParameter, 0, len(params))
+	for name, value := range params {
+		parameters = append(parameters, types.FunctionParameter{Name: aws.String(name), Value: aws.String(value)})
+	}
+	return &types.InlineAgentResponseStreamMemberReturnControl{Value: types.InlineAgentReturnControlPayload{
+		InvocationId: aws.String("inv-1"),
+		InvocationInputs: []types.InvocationInputMember{
+			&types.InvocationInputMemberMemberFunctionInvocationInput{Value: types.FunctionInvocationInput{
+				ActionGroup: aws.String(group),
+				Function:    aws.String(function),
+				Parameters:  parameters,
+			}},
+		},
+	}}
+}
+
+func TestBedrockAgent_Ask_DirectAnswer(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	client := mocks.NewMockInlineAgentClient(ctrl)
+	group := mocks.NewMockActionGroup(ctrl)
+	group.EXPECT().Name().Return("records").AnyTimes()
+	group.EXPECT().Description().Return("records").AnyTimes()
+	group.EXPECT().Functions().Return(nil).AnyTimes()
+	client.EXPECT().InvokeInlineAgent(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, input *bedrockagentruntime.InvokeInlineAgentInput) (agent.EventStream, error) {
+			assert.Equal(t, "hello?", aws.ToString(input.InputText), "first turn should carry the question")
+			assert.Equal(t, "model-x", aws.ToString(input.FoundationModel), "input should carry the model")
+			require.Len(t, input.ActionGroups, 1, "input should carry the action groups")
+			assert.IsType(t, &types.ActionGroupExecutorMemberCustomControl{}, input.ActionGroups[0].ActionGroupExecutor, "action groups should return control")
+			return newStream(ctrl, chunk("Hi "), chunk("there")), nil
+		})
+	a := agent.NewBedrockAgent(client, "model-x", group)
+
+	// Act
+	answer, err := a.Ask(context.Background(), "hello?")
+
+	// Assert
+	require.NoError(t, err, "Ask() error should be nil")
+	assert.Equal(t, "Hi there", answer, "Ask() should join the answer chunks")
+}
+
+func TestBedrockAgent_Ask_ReturnsControlToActionGroup(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	client := mocks.NewMockInlineAgentClient(ctrl)
+	group := mocks.NewMockActionGroup(ctrl)
+	group.EXPECT().Name().Return("records").AnyTimes()
+	group.EXPECT().Description().Return("records").AnyTimes()
+	group.EXPECT().Functions().Return(nil).AnyTimes()
+	group.EXPECT().Invoke(gomock.Any(), "aggregate_records", map[string]string{"type": "receipt"}).Return(`{"value":42}`, nil)
+
+	gomock.InOrder(
+		client.EXPECT().InvokeInlineAgent(gomock.Any(), gomock.Any()).
+			Return(newStream(ctrl, returnControl("records", "aggregate_records", map[string]string{"type": "receipt"})), nil),
+		client.EXPECT().InvokeInlineAgent(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, input *bedrockagentruntime.InvokeInlineAgentInput) (agent.EventStream, error) {
+				require.NotNil(t, input.InlineSessionState, "second turn should carry the session state")
+				assert.Equal(t, "inv-1", aws.ToString(input.InlineSessionState.InvocationId), "second turn should reference the invocation")
+				require.Len(t, input.InlineSessionState.ReturnControlInvocationResults, 1, "second turn should carry one result")
+				result := input.InlineSessionState.ReturnControlInvocationResults[0].(*types.InvocationResultMemberMemberFunctionResult)
+				assert.Equal(t, `{"value":42}`, aws.ToString(result.Value.ResponseBody["TEXT"].Body), "result should carry the function output")
+				return newStream(ctrl, chunk("You spent 42.")), nil
+			}),
+	)
+	a := agent.NewBedrockAgent(client, "model-x", group)
+
+	// Act
+	answer, err := a.Ask(context.Background(), "How much did I spend?")
+
+	// Assert
+	require.NoError(t, err, "Ask() error should be nil")
+	assert.Equal(t, "You spent 42.", answer, "Ask() should return the final answer")
+}
+
+func TestBedrockAgent_Ask_FunctionErrorReprompts(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	client := mocks.NewMockInlineAgentClient(ctrl)
+
+	gomock.InOrder(
+		client.EXPECT().InvokeInlineAgent(gomock.Any(), gomock.Any()).
+			Return(newStream(ctrl, returnControl("unknown", "fn", nil)), nil),
+		client.EXPECT().InvokeInlineAgent(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, input *bedrockagentruntime.InvokeInlineAgentInput) (agent.EventStream, error) {
+				result := input.InlineSessionState.ReturnControlInvocationResults[0].(*types.InvocationResultMemberMemberFunctionResult)
+				assert.Equal(t, types.ResponseStateReprompt, result.Value.ResponseState, "failed calls should ask the agent to reprompt")
+				return newStream(ctrl, chunk("Sorry.")), nil
+			}),
+	)
+	a := agent.NewBedrockAgent(client, "model-x")
+
+	// Act
+	answer, err := a.Ask(context.Background(), "question")
+
+	// Assert
+	require.NoError(t, err, "Ask() error should be nil")
+	assert.Equal(t, "Sorry.", answer, "Ask() should return the final answer")
+}
+
+func TestBedrockAgent_Ask_InvokeError(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	client := mocks.NewMockInlineAgentClient(ctrl)
+	client.EXPECT().InvokeInlineAgent(gomock.Any(), gomock.Any()).Return(nil, errors.New("access denied"))
+	a := agent.NewBedrockAgent(client, "model-x")
+
+	// Act
+	_, err := a.Ask(context.Background(), "question")
+
+	// Assert
+	assert.ErrorContains(t, err, "access denied", "Ask() should surface the invocation error")
+}
@@ -0,0 +1,262 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/kazemisoroush/assistant/pkg/records"
+	"github.com/kazemisoroush/assistant/pkg/records/discovery"
+	"github.com/kazemisoroush/assistant/pkg/records/storage"
+)
+
+const (
+	// RecordsActionGroupName is the name under which the records functions are exposed
+	RecordsActionGroupName = "records"
+
+	// FunctionSearchRecords finds records relevant to a free-text query
+	FunctionSearchRecords = "search_records"
+
+	// FunctionAggregateRecords computes an aggregate over a metadata field
+	FunctionAggregateRecords = "aggregate_records"
+
+	// defaultSearchLimit is used when the agent does not pass a limit
+	defaultSearchLimit = 10
+
+	// snippetLength caps the record content returned to the agent per hit
+	snippetLength = 300
+)
+
+// Aggregation operations supported by aggregate_records
+const (
+	OperationCount = "count"
+	OperationSum   = "sum"
+	OperationAvg   = "avg"
+	OperationMin   = "min"
+	OperationMax   = "max"
+)
+
+// RecordsActionGroup exposes record search and aggregation to the agent
+type RecordsActionGroup struct {
+	discovery discovery.Discovery
+	storage   storage.Storage
+}
+
+// NewRecordsActionGroup creates a new records action group
+func NewRecordsActionGroup(discovery discovery.Discovery, storage storage.Storage) ActionGroup {
+	return &RecordsActionGroup{
+		discovery: discovery,
+		storage:   storage,
+	}
+}
+
+// Name returns the action group name
+func (g *RecordsActionGroup) Name() string {
+	return RecordsActionGroupName
+}
+
+// Description tells the agent when to use the action group
+func (g *RecordsActionGroup) Description() string {
+	return "Search the user's personal records (receipts, health, insurance, travel, tax and more) and compute totals over them."
+}
+
+// Functions returns the function definitions exposed to the agent
+func (g *RecordsActionGroup) Functions() []Function {
+	return []Function{
+		{
+			Name:        FunctionSearchRecords,
+			Description: "Find records relevant to a free-text query. Returns record IDs, types, metadata and a content snippet.",
+			Parameters: map[string]Parameter{
+				"query": {Type: ParameterTypeString, Description: "What to look for", Required: true},
+				"limit": {Type: ParameterTypeInteger, Description: "Maximum number of records to return"},
+			},
+		},
+		{
+			Name:        FunctionAggregateRecords,
+			Description: "Aggregate a numeric metadata field over all records of a type, e.g. the sum of receipt totals.",
+			Parameters: map[string]Parameter{
+				"type":      {Type: ParameterTypeString, Description: "Record type: " + strings.Join(records.AllRecordTypesAsStrings(), ", "), Required: true},
+				"operation": {Type: ParameterTypeString, Description: "One of count, sum, avg, min, max", Required: true},
+				"field":     {Type: ParameterTypeString, Description: "Metadata field to aggregate, e.g. total; not needed for count"},
+			},
+		},
+	}
+}
+
+// Invoke runs a records function
+func (g *RecordsActionGroup) Invoke(ctx context.Context, function string, params map[string]string) (string, error) {
+	switch function {
+	case FunctionSearchRecords:
+		return g.search(ctx, params)
+	case FunctionAggregateRecords:
+		return g.aggregate(ctx, params)
+	default:
+		return "", fmt.Errorf("unknown function %s in action group %s", function, RecordsActionGroupName)
+	}
+}
+
+// searchHit is a single search_records result
+type searchHit struct {
+	ID       string         `json:"id"`
+	Type     string         `json:"type"`
+	Score    float64        `json:"score"`
+	Metadata map[string]any `json:"metadata,omitempty"`
+	Snippet  string         `json:"snippet,omitempty"`
+}
+
+func (g *RecordsActionGroup) search(ctx context.Context, params map[string]string) (string, error) {
+	query := params["query"]
+	if query == "" {
+		return "", fmt.Errorf("query is required")
+	}
+
+	limit := defaultSearchLimit
+	if raw := params["limit"]; raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed <= 0 {
+			return "", fmt.Errorf("invalid limit %q", raw)
+		}
+		limit = parsed
+	}
+
+	resp, err := g.discovery.Discover(ctx, discovery.DiscoverRequest{Prompt: query, Limit: limit})
+	if err != nil {
+		return "", fmt.Errorf("failed to search records: %w", err)
+	}
+
+	hits := make([]searchHit, 0, len(resp.Hits))
+	for _, hit := range resp.Hits {
+		rec, err := g.storage.Get(ctx, hit.RecordID)
+		if err != nil {
+			slog.WarnContext(ctx, "Skipping search hit that could not be loaded", "record_id", hit.RecordID, "error", err)
+			continue
+		}
+		hits = append(hits, searchHit{
+			ID:       rec.ID,
+			Type:     string(rec.Type),
+			Score:    hit.Score,
+			Metadata: rec.Metadata,
+			Snippet:  snippet(rec.Content),
+		})
+	}
+
+	return marshal(hits)
+}
+
+// aggregateResult is the aggregate_records result
+type aggregateResult struct {
+	Type      string  `json:"type"`
+	Operation string  `json:"operation"`
+	Field     string  `json:"field,omitempty"`
+	Value     float64 `json:"value"`
+	Count     int     `json:"count"`
+}
+
+func (g *RecordsActionGroup) aggregate(ctx context.Context, params map[string]string) (string, error) {
+	recType := records.RecordType(params["type"])
+	if !recType.IsValid() {
+		return "", fmt.Errorf("invalid record type %q", params["type"])
+	}
+	operation := params["operation"]
+	if !slices.Contains([]string{OperationCount, OperationSum, OperationAvg, OperationMin, OperationMax}, operation) {
+		return "", fmt.Errorf("unsupported operation %q", operation)
+	}
+	field := params["field"]
+	if operation != OperationCount && field == "" {
+		return "", fmt.Errorf("field is required for %s", operation)
+	}
+
+	recs, err := g.storage.List(ctx, recType)
+	if err != nil {
+		return "", fmt.Errorf("failed to list records: %w", err)
+	}
+
+	if operation == OperationCount {
+		return marshal(aggregateResult{Type: string(recType), Operation: operation, Value: float64(len(recs)), Count: len(recs)})
+	}
+
+	values := numericValues(recs, field)
+	value, err := reduce(operation, values)
+	if err != nil {
+		return "", err
+	}
+	return marshal(aggregateResult{Type: string(recType), Operation: operation, Field: field, Value: value, Count: len(values)})
+}
+
+// numericValues collects the numeric values of a metadata field, skipping records without one
+func numericValues(recs []records.Record, field string) []float64 {
+	values := make([]float64, 0, len(recs))
+	for _, rec := range recs {
+		if value, ok := toFloat(rec.Metadata[field]); ok {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+func reduce(operation string, values []float64) (float64, error) {
+	if len(values) == 0 {
+		return 0, nil
+	}
+
+	result := values[0]
+	switch operation {
+	case OperationSum, OperationAvg:
+		result = 0
+		for _, v := range values {
+			result += v
+		}
+		if operation == OperationAvg {
+			result /= float64(len(values))
+		}
+	case OperationMin:
+		for _, v := range values[1:] {
+			result = math.Min(result, v)
+		}
+	case OperationMax:
+		for _, v := range values[1:] {
+			result = math.Max(result, v)
+		}
+	default:
+		return 0, fmt.Errorf("unsupported operation %q", operation)
+	}
+	return result, nil
+}
+
+func toFloat(value any) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case int:
+		return float64(v), true
+	case json.Number:
+		f, err := v.Float64()
+		return f, err == nil
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		return f, err == nil
+	default:
+		return 0, false
+	}
+}
+
+func snippet(content string) string {
+	runes := []rune(content)
+	if len(runes) <= snippetLength {
+		return content
+	}
+	return string(runes[:snippetLength]) + "..."
+}
+
+func marshal(v any) (string, error) {
+	body, err := json.Marshal(v)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal result: %w", err)
+	}
+	return string(body), nil
+}
@@ -0,0 +1,87 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/kazemisoroush/assistant/pkg/records"
+	"github.com/kazemisoroush/assistant/pkg/records/discovery"
+	discoverymocks "github.com/kazemisoroush/assistant/pkg/records/discovery/mocks"
+	storagemocks "github.com/kazemisoroush/assistant/pkg/records/storage/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestRecordsActionGroup_SearchRecords(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	disc := discoverymocks.NewMockDiscovery(ctrl)
+	store := storagemocks.NewMockStorage(ctrl)
+	disc.EXPECT().Discover(gomock.Any(), discovery.DiscoverRequest{Prompt: "dentist", Limit: 2}).
+		Return(discovery.DiscoverResponse{Hits: []discovery.Hit{{RecordID: "r1", Score: 0.9}, {RecordID: "missing", Score: 0.5}}}, nil)
+	store.EXPECT().Get(gomock.Any(), "r1").Return(records.Record{ID: "r1", Type: records.RecordTypeHealthVisit, Content: "Dental checkup"}, nil)
+	store.EXPECT().Get(gomock.Any(), "missing").Return(records.Record{}, errors.New("not found"))
+	group := NewRecordsActionGroup(disc, store)
+
+	// Act
+	body, err := group.Invoke(context.Background(), FunctionSearchRecords, map[string]string{"query": "dentist", "limit": "2"})
+
+	// Assert
+	require.NoError(t, err, "Invoke() error should be nil")
+	assert.JSONEq(t, `[{"id":"r1","type":"health_visit","score":0.9,"snippet":"Dental checkup"}]`, body, "Invoke() should return loadable hits only")
+}
+
+func TestRecordsActionGroup_AggregateRecords(t *testing.T) {
+	receipts := []records.Record{
+		{ID: "a", Metadata: map[string]interface{}{"total": 10.5}},
+		{ID: "b", Metadata: map[string]interface{}{"total": "4.5"}},
+		{ID: "c", Metadata: map[string]interface{}{}},
+	}
+
+	tests := []struct {
+		name      string
+		operation string
+		field     string
+		want      string
+	}{
+		{name: "sum", operation: OperationSum, field: "total", want: `{"type":"receipt","operation":"sum","field":"total","value":15,"count":2}`},
+		{name: "avg", operation: OperationAvg, field: "total", want: `{"type":"receipt","operation":"avg","field":"total","value":7.5,"count":2}`},
+		{name: "max", operation: OperationMax, field: "total", want: `{"type":"receipt","operation":"max","field":"total","value":10.5,"count":2}`},
+		{name: "count", operation: OperationCount, want: `{"type":"receipt","operation":"count","value":3,"count":3}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			ctrl := gomock.NewController(t)
+			store := storagemocks.NewMockStorage(ctrl)
+			store.EXPECT().List(gomock.Any(), records.RecordTypeReceipt).Return(receipts, nil)
+			group := NewRecordsActionGroup(discoverymocks.NewMockDiscovery(ctrl), store)
+
+			// Act
+			body, err := group.Invoke(context.Background(), FunctionAggregateRecords, map[string]string{"type": "receipt", "operation": tt.operation, "field": tt.field})
+
+			// Assert
+			require.NoError(t, err, "Invoke() error should be nil")
+			assert.JSONEq(t, tt.want, body, "Invoke() should aggregate the numeric field")
+		})
+	}
+}
+
+func TestRecordsActionGroup_AggregateRecords_InvalidParams(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	group := NewRecordsActionGroup(discoverymocks.NewMockDiscovery(ctrl), storagemocks.NewMockStorage(ctrl))
+
+	// Act
+	_, typeErr := group.Invoke(context.Background(), FunctionAggregateRecords, map[string]string{"type": "spaceship", "operation": "count"})
+	_, opErr := group.Invoke(context.Background(), FunctionAggregateRecords, map[string]string{"type": "receipt", "operation": "median", "field": "total"})
+	_, fieldErr := group.Invoke(context.Background(), FunctionAggregateRecords, map[string]string{"type": "receipt", "operation": "sum"})
+
+	// Assert
+	assert.ErrorContains(t, typeErr, "invalid record type", "Invoke() should reject unknown record types")
+	assert.ErrorContains(t, opErr, "unsupported operation", "Invoke() should reject unknown operations")
+	assert.ErrorContains(t, fieldErr, "field is required", "Invoke() should require a field for sum")
+}
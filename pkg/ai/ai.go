@@ -0,0 +1,188 @@
+// Package ai provides a provider-agnostic abstraction over language model backends.
+//
+// It is the one client every language model feature goes through: type
+// classification, metadata and entity extraction, summaries and answers all
+// take a Provider. Backends such as Ollama and Bedrock implement generation,
+// streaming and embedding, and NewProviderChain wraps them with retries,
+// circuit breakers, guardrails and fallbacks. Timeouts come from the context
+// and the HTTP client of the Config.
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Provider generates text and embeddings with a language model backend
+//
+//go:generate mockgen -destination=./mocks/mock_provider.go -mock_names=Provider=MockProvider -package=mocks . Provider
+type Provider interface {
+	// Name returns the name/identifier of this provider
+	Name() string
+
+	// Model returns the generation model used by this provider
+	Model() string
+
+	// Generate returns the model completion for the given request
+	Generate(ctx context.Context, req Request) (Response, error)
+
+	// GenerateJSON requests JSON output and decodes it into out
+	GenerateJSON(ctx context.Context, req Request, out any) error
+
+	// Stream returns the completion incrementally as it is generated
+	// The token channel is closed when generation finishes; at most one error is sent
+	Stream(ctx context.Context, req Request) (<-chan string, <-chan error)
+
+	// Embed generates a vector embedding for text
+	Embed(ctx context.Context, text string) ([]float32, error)
+}
+
+// Request represents a single generation request
+type Request struct {
+	// System is an optional system prompt
+	System string
+
+	// Prompt is the user prompt
+	Prompt string
+
+	// JSON asks the provider to constrain its output to JSON where supported
+	JSON bool
+
+	// Images are sent alongside the prompt to vision-capable models
+	Images []Image
+
+	// Task names the purpose of the request for usage tracking and model routing
+	Task string
+
+	// RecordType is the type of the record the prompt content belongs to,
+	// empty while it is unknown; guardrails use it to keep types off cloud models
+	RecordType string
+
+	// RecordTypes are the types of the records the prompt content draws on
+	// when it spans several, such as the records an answer is grounded in;
+	// guardrails check each of them like RecordType
+	RecordTypes []string
+}
+
+// Task names attached to requests
+const (
+	TaskClassification     = "classification"
+	TaskMetadataExtraction = "metadata_extraction"
+	TaskEntityExtraction   = "entity_extraction"
+	TaskTranscription      = "transcription"
+	TaskReranking          = "reranking"
+	TaskAnswering          = "answering"
+	TaskEmbedding          = "embedding"
+	TaskSummarization      = "summarization"
+)
+
+// Image represents an image attached to a request
+type Image struct {
+	// MediaType is the image MIME type, e.g. image/png
+	MediaType string
+
+	// Data is the raw image content
+	Data []byte
+}
+
+// Response represents the result of a generation request
+type Response struct {
+	Text  string
+	Usage Usage
+}
+
+// Usage represents the token usage reported by the provider
+type Usage struct {
+	InputTokens  int
+	OutputTokens int
+}
+
+// maxJSONAttempts bounds how often GenerateJSON asks the model to correct invalid output
+const maxJSONAttempts = 3
+
+// Validator is implemented by GenerateJSON targets that can check their own contents
+type Validator interface {
+	// Validate returns an error describing why the decoded value is unacceptable
+	Validate() error
+}
+
+// generateJSON implements GenerateJSON on top of a provider's Generate. The
+// response is decoded into out and validated when out implements Validator;
+// on failure the model is re-prompted with the error so it can correct itself.
+func generateJSON(ctx context.Context, p Provider, req Request, out any) error {
+	req.JSON = true
+	prompt := req.Prompt
+
+	var lastErr error
+	for attempt := 1; attempt <= maxJSONAttempts; attempt++ {
+		resp, err := p.Generate(ctx, req)
+		if err != nil {
+			return err
+		}
+
+		lastErr = decodeAndValidate(resp.Text, out)
+		if lastErr == nil {
+			return nil
+		}
+
+		req.Prompt = fmt.Sprintf("%s\n\nYour previous response was:\n%s\n\nIt was rejected because: %v\nRespond again with corrected JSON only.", prompt, resp.Text, lastErr)
+	}
+
+	return fmt.Errorf("%s returned invalid JSON after %d attempts: %w", p.Name(), maxJSONAttempts, lastErr)
+}
+
+func decodeAndValidate(text string, out any) error {
+	if err := json.Unmarshal([]byte(extractJSON(text)), out); err != nil {
+		return fmt.Errorf("invalid JSON: %w", err)
+	}
+
+	if v, ok := out.(Validator); ok {
+		if err := v.Validate(); err != nil {
+			return fmt.Errorf("invalid value: %w", err)
+		}
+	}
+	return nil
+}
+
+// forwardStream copies tokens streamed by p into out and reports whether any token was delivered
+func forwardStream(ctx context.Context, p Provider, req Request, out chan<- string) (bool, error) {
+	tokens, errs := p.Stream(ctx, req)
+	started := false
+
+	for tokens != nil || errs != nil {
+		select {
+		case token, ok := <-tokens:
+			if !ok {
+				tokens = nil
+				continue
+			}
+			started = true
+			select {
+			case out <- token:
+			case <-ctx.Done():
+				return started, ctx.Err()
+			}
+		case err, ok := <-errs:
+			if !ok {
+				errs = nil
+				continue
+			}
+			return started, err
+		}
+	}
+
+	return started, nil
+}
+
+// extractJSON strips Markdown code fences and surrounding prose some models add around JSON
+func extractJSON(text string) string {
+	text = strings.TrimSpace(text)
+	start := strings.IndexAny(text, "{[")
+	end := strings.LastIndexAny(text, "}]")
+	if start < 0 || end < start {
+		return text
+	}
+	return text[start : end+1]
+}
@@ -0,0 +1,66 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// scriptedProvider returns the given responses in order from Generate
+type scriptedProvider struct {
+	Provider
+	responses []string
+	prompts   []string
+}
+
+func (s *scriptedProvider) Name() string { return "scripted" }
+
+func (s *scriptedProvider) Model() string { return "scripted-model" }
+
+func (s *scriptedProvider) Generate(_ context.Context, req Request) (Response, error) {
+	s.prompts = append(s.prompts, req.Prompt)
+	text := s.responses[0]
+	s.responses = s.responses[1:]
+	return Response{Text: text}, nil
+}
+
+type amount struct {
+	Total float64 `json:"total"`
+}
+
+func (a amount) Validate() error {
+	if a.Total <= 0 {
+		return errors.New("total must be positive")
+	}
+	return nil
+}
+
+func TestGenerateJSON_CorrectsInvalidOutput(t *testing.T) {
+	// Arrange
+	provider := &scriptedProvider{responses: []string{`{"total": 0}`, `{"total": 12.5}`}}
+	var out amount
+
+	// Act
+	err := generateJSON(context.Background(), provider, Request{Prompt: "extract"}, &out)
+
+	// Assert
+	require.NoError(t, err, "generateJSON() error should be nil")
+	assert.Equal(t, 12.5, out.Total, "generateJSON() should decode the corrected response")
+	require.Len(t, provider.prompts, 2, "generateJSON() should re-prompt once")
+	assert.Contains(t, provider.prompts[1], "total must be positive", "corrective prompt should include the validation error")
+}
+
+func TestGenerateJSON_GivesUp(t *testing.T) {
+	// Arrange
+	provider := &scriptedProvider{responses: []string{"nope", "still nope", "never"}}
+	var out amount
+
+	// Act
+	err := generateJSON(context.Background(), provider, Request{Prompt: "extract"}, &out)
+
+	// Assert
+	require.Error(t, err, "generateJSON() should fail after exhausting attempts")
+}
@@ -0,0 +1,257 @@
+package ai
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+)
+
+const (
+	// ProviderAnthropic is the name of the Anthropic provider
+	ProviderAnthropic = "anthropic"
+
+	// anthropicVersion is the Messages API version sent with every request
+	anthropicVersion = "2023-06-01"
+
+	// DefaultAnthropicMaxTokens is used when no output token limit is configured
+	DefaultAnthropicMaxTokens = 4096
+)
+
+// AnthropicProvider talks to the Anthropic Messages API
+type AnthropicProvider struct {
+	httpClient *http.Client
+	url        string
+	apiKey     string
+	model      string
+	maxTokens  int
+}
+
+// NewAnthropicProvider creates a new Anthropic provider
+func NewAnthropicProvider(httpClient *http.Client, url, apiKey, model string, maxTokens int) Provider {
+	if maxTokens <= 0 {
+		maxTokens = DefaultAnthropicMaxTokens
+	}
+	return &AnthropicProvider{
+		httpClient: httpClient,
+		url:        url,
+		apiKey:     apiKey,
+		model:      model,
+		maxTokens:  maxTokens,
+	}
+}
+
+// Name returns the provider name
+func (a *AnthropicProvider) Name() string {
+	return ProviderAnthropic
+}
+
+// Model returns the generation model
+func (a *AnthropicProvider) Model() string {
+	return a.model
+}
+
+// Generate returns the model completion for the given request
+func (a *AnthropicProvider) Generate(ctx context.Context, req Request) (Response, error) {
+	var result struct {
+		Content []struct {
+			Type string `json:"type"`
+			Text string `json:"text"`
+		} `json:"content"`
+		Usage struct {
+			InputTokens  int `json:"input_tokens"`
+			OutputTokens int `json:"output_tokens"`
+		} `json:"usage"`
+	}
+	if err := a.post(ctx, a.messagesBody(req, false), &result); err != nil {
+		return Response{}, err
+	}
+
+	var text strings.Builder
+	for _, block := range result.Content {
+		if block.Type == "text" {
+			text.WriteString(block.Text)
+		}
+	}
+
+	return Response{
+		Text: text.String(),
+		Usage: Usage{
+			InputTokens:  result.Usage.InputTokens,
+			OutputTokens: result.Usage.OutputTokens,
+		},
+	}, nil
+}
+
+// Stream returns the completion incrementally from server-sent events
+func (a *AnthropicProvider) Stream(ctx context.Context, req Request) (<-chan string, <-chan error) {
+	tokenChan := make(chan string)
+	errChan := make(chan error, 1)
+
+	go func() {
+		defer close(tokenChan)
+		defer close(errChan)
+
+		resp, err := a.do(ctx, a.messagesBody(req, true))
+		if err != nil {
+			errChan <- err
+			return
+		}
+		defer a.closeBody(resp)
+
+		scanner := bufio.NewScanner(resp.Body)
+		for scanner.Scan() {
+			data, ok := strings.CutPrefix(scanner.Text(), "data: ")
+			if !ok {
+				continue
+			}
+
+			text, done, err := parseAnthropicEvent(data)
+			if err != nil {
+				errChan <- err
+				return
+			}
+			if done {
+				return
+			}
+			if text == "" {
+				continue
+			}
+
+			select {
+			case tokenChan <- text:
+			case <-ctx.Done():
+				errChan <- ctx.Err()
+				return
+			}
+		}
+
+		if err := scanner.Err(); err != nil {
+			errChan <- fmt.Errorf("failed to read Anthropic stream: %w", err)
+		}
+	}()
+
+	return tokenChan, errChan
+}
+
+// GenerateJSON requests JSON output and decodes it into out
+func (a *AnthropicProvider) GenerateJSON(ctx context.Context, req Request, out any) error {
+	return generateJSON(ctx, a, req, out)
+}
+
+// Embed is not supported; Anthropic does not offer an embeddings API
+func (a *AnthropicProvider) Embed(_ context.Context, _ string) ([]float32, error) {
+	return nil, fmt.Errorf("anthropic does not provide an embeddings API")
+}
+
+// parseAnthropicEvent returns the text delta carried by a stream event and
+// whether the event ends the message
+func parseAnthropicEvent(data string) (string, bool, error) {
+	var event struct {
+		Type  string `json:"type"`
+		Delta struct {
+			Type string `json:"type"`
+			Text string `json:"text"`
+		} `json:"delta"`
+		Error struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal([]byte(data), &event); err != nil {
+		return "", false, fmt.Errorf("failed to decode Anthropic stream: %w", err)
+	}
+
+	switch {
+	case event.Type == "message_stop":
+		return "", true, nil
+	case event.Type == "error":
+		return "", false, fmt.Errorf("anthropic stream failed: %s", event.Error.Message)
+	case event.Type == "content_block_delta" && event.Delta.Type == "text_delta":
+		return event.Delta.Text, false, nil
+	default:
+		return "", false, nil
+	}
+}
+
+func (a *AnthropicProvider) messagesBody(req Request, stream bool) map[string]any {
+	prompt := req.Prompt
+	if req.JSON {
+		// The Messages API has no JSON mode, so ask for it in the prompt instead
+		prompt += "\n\nRespond with valid JSON only."
+	}
+
+	content := make([]map[string]any, 0, len(req.Images)+1)
+	for _, image := range req.Images {
+		content = append(content, map[string]any{
+			"type": "image",
+			"source": map[string]string{
+				"type":       "base64",
+				"media_type": image.MediaType,
+				"data":       base64.StdEncoding.EncodeToString(image.Data),
+			},
+		})
+	}
+	content = append(content, map[string]any{"type": "text", "text": prompt})
+
+	body := map[string]any{
+		"model":      a.model,
+		"max_tokens": a.maxTokens,
+		"messages":   []map[string]any{{"role": "user", "content": content}},
+		"stream":     stream,
+	}
+	if req.System != "" {
+		body["system"] = req.System
+	}
+	return body
+}
+
+func (a *AnthropicProvider) post(ctx context.Context, body any, out any) error {
+	resp, err := a.do(ctx, body)
+	if err != nil {
+		return err
+	}
+	defer a.closeBody(resp)
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode Anthropic response: %w", err)
+	}
+	return nil
+}
+
+// do sends the request and returns the response for the caller to read and close
+func (a *AnthropicProvider) do(ctx context.Context, body any) (*http.Response, error) {
+	jsonData, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request body: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, a.url+"/messages", bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Api-Key", a.apiKey)
+	req.Header.Set("Anthropic-Version", anthropicVersion)
+
+	resp, err := a.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call Anthropic API at %s: %w", a.url, err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		a.closeBody(resp)
+		return nil, &StatusError{Provider: ProviderAnthropic, StatusCode: resp.StatusCode}
+	}
+	return resp, nil
+}
+
+func (a *AnthropicProvider) closeBody(resp *http.Response) {
+	if err := resp.Body.Close(); err != nil {
+		slog.Warn("Failed to close response body", "error", err)
+	}
+}
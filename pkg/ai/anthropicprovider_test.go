@@ -0,0 +1,100 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnthropicProvider_Generate_WithImage(t *testing.T) {
+	// Arrange
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/messages", r.URL.Path, "request should go to the messages endpoint")
+		assert.Equal(t, "sk-ant", r.Header.Get("X-Api-Key"), "request should carry the API key")
+		assert.Equal(t, anthropicVersion, r.Header.Get("Anthropic-Version"), "request should carry the API version")
+
+		var body struct {
+			Model     string `json:"model"`
+			MaxTokens int    `json:"max_tokens"`
+			System    string `json:"system"`
+			Messages  []struct {
+				Content []struct {
+					Type   string            `json:"type"`
+					Text   string            `json:"text"`
+					Source map[string]string `json:"source"`
+				} `json:"content"`
+			} `json:"messages"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body), "request body should be JSON")
+		assert.Equal(t, "claude", body.Model, "request should carry the model")
+		assert.Equal(t, DefaultAnthropicMaxTokens, body.MaxTokens, "request should default max_tokens")
+		assert.Equal(t, "be brief", body.System, "request should carry the system prompt")
+		require.Len(t, body.Messages, 1, "request should carry one message")
+		require.Len(t, body.Messages[0].Content, 2, "message should carry the image and the prompt")
+		assert.Equal(t, "image", body.Messages[0].Content[0].Type, "image should come first")
+		assert.Equal(t, "image/png", body.Messages[0].Content[0].Source["media_type"], "image should carry its media type")
+		assert.Equal(t, "aW1n", body.Messages[0].Content[0].Source["data"], "image should be base64 encoded")
+		assert.Equal(t, "read this", body.Messages[0].Content[1].Text, "prompt should follow the image")
+
+		_, _ = w.Write([]byte(`{"content":[{"type":"text","text":"Total: 12.50"}],"usage":{"input_tokens":30,"output_tokens":4}}`))
+	}))
+	defer server.Close()
+	provider := NewAnthropicProvider(server.Client(), server.URL, "sk-ant", "claude", 0)
+
+	// Act
+	resp, err := provider.Generate(context.Background(), Request{
+		System: "be brief",
+		Prompt: "read this",
+		Images: []Image{{MediaType: "image/png", Data: []byte("img")}},
+	})
+
+	// Assert
+	require.NoError(t, err, "Generate() error should be nil")
+	assert.Equal(t, "Total: 12.50", resp.Text, "Generate() should return the text blocks")
+	assert.Equal(t, Usage{InputTokens: 30, OutputTokens: 4}, resp.Usage, "Generate() should report token usage")
+}
+
+func TestAnthropicProvider_Generate_StatusError(t *testing.T) {
+	// Arrange
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+	provider := NewAnthropicProvider(server.Client(), server.URL, "sk-ant", "claude", 0)
+
+	// Act
+	_, err := provider.Generate(context.Background(), Request{Prompt: "hi"})
+
+	// Assert
+	assert.True(t, IsTransient(err), "Generate() should report rate limiting as transient")
+}
+
+func TestAnthropicProvider_Stream(t *testing.T) {
+	// Arrange
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(
+			"event: message_start\ndata: {\"type\":\"message_start\"}\n\n" +
+				"event: content_block_delta\ndata: {\"type\":\"content_block_delta\",\"delta\":{\"type\":\"text_delta\",\"text\":\"Hel\"}}\n\n" +
+				"event: ping\ndata: {\"type\":\"ping\"}\n\n" +
+				"event: content_block_delta\ndata: {\"type\":\"content_block_delta\",\"delta\":{\"type\":\"text_delta\",\"text\":\"lo\"}}\n\n" +
+				"event: message_stop\ndata: {\"type\":\"message_stop\"}\n\n"))
+	}))
+	defer server.Close()
+	provider := NewAnthropicProvider(server.Client(), server.URL, "sk-ant", "claude", 0)
+
+	// Act
+	tokens, errs := provider.Stream(context.Background(), Request{Prompt: "greet"})
+	var text string
+	for token := range tokens {
+		text += token
+	}
+
+	// Assert
+	require.NoError(t, <-errs, "Stream() should not report an error")
+	assert.Equal(t, "Hello", text, "Stream() should deliver every text delta in order")
+}
@@ -0,0 +1,211 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockruntime"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockruntime/types"
+)
+
+const (
+	// ProviderBedrock is the name of the AWS Bedrock provider
+	ProviderBedrock = "bedrock"
+
+	// DefaultBedrockEmbeddingModel is used when no embedding model is configured
+	DefaultBedrockEmbeddingModel = "amazon.titan-embed-text-v2:0"
+)
+
+// BedrockClient is the subset of the Bedrock runtime API used by BedrockProvider
+//
+//go:generate mockgen -destination=./mocks/mock_bedrockclient.go -mock_names=BedrockClient=MockBedrockClient -package=mocks . BedrockClient
+type BedrockClient interface {
+	Converse(ctx context.Context, params *bedrockruntime.ConverseInput, optFns ...func(*bedrockruntime.Options)) (*bedrockruntime.ConverseOutput, error)
+	ConverseStream(ctx context.Context, params *bedrockruntime.ConverseStreamInput, optFns ...func(*bedrockruntime.Options)) (*bedrockruntime.ConverseStreamOutput, error)
+	InvokeModel(ctx context.Context, params *bedrockruntime.InvokeModelInput, optFns ...func(*bedrockruntime.Options)) (*bedrockruntime.InvokeModelOutput, error)
+}
+
+// BedrockProvider uses AWS Bedrock foundation models through the Converse API
+type BedrockProvider struct {
+	client         BedrockClient
+	model          string
+	embeddingModel string
+}
+
+// NewBedrockProvider creates a new Bedrock provider
+func NewBedrockProvider(client BedrockClient, model, embeddingModel string) Provider {
+	if embeddingModel == "" {
+		embeddingModel = DefaultBedrockEmbeddingModel
+	}
+	return &BedrockProvider{
+		client:         client,
+		model:          model,
+		embeddingModel: embeddingModel,
+	}
+}
+
+// Name returns the provider name
+func (b *BedrockProvider) Name() string {
+	return ProviderBedrock
+}
+
+// Model returns the generation model
+func (b *BedrockProvider) Model() string {
+	return b.model
+}
+
+// EmbeddingModel returns the embedding model
+func (b *BedrockProvider) EmbeddingModel() string {
+	return b.embeddingModel
+}
+
+// Generate returns the model completion for the given request
+func (b *BedrockProvider) Generate(ctx context.Context, req Request) (Response, error) {
+	output, err := b.client.Converse(ctx, &bedrockruntime.ConverseInput{
+		ModelId:  aws.String(b.model),
+		Messages: b.messages(req),
+		System:   b.system(req),
+	})
+	if err != nil {
+		return Response{}, fmt.Errorf("failed to call Bedrock Converse API: %w", err)
+	}
+
+	message, ok := output.Output.(*types.ConverseOutputMemberMessage)
+	if !ok {
+		return Response{}, fmt.Errorf("unexpected Bedrock output type: %T", output.Output)
+	}
+
+	var text strings.Builder
+	for _, block := range message.Value.Content {
+		if textBlock, ok := block.(*types.ContentBlockMemberText); ok {
+			text.WriteString(textBlock.Value)
+		}
+	}
+
+	resp := Response{Text: text.String()}
+	if output.Usage != nil {
+		resp.Usage = Usage{
+			InputTokens:  int(aws.ToInt32(output.Usage.InputTokens)),
+			OutputTokens: int(aws.ToInt32(output.Usage.OutputTokens)),
+		}
+	}
+	return resp, nil
+}
+
+// Stream returns the completion incrementally through the ConverseStream API
+func (b *BedrockProvider) Stream(ctx context.Context, req Request) (<-chan string, <-chan error) {
+	tokenChan := make(chan string)
+	errChan := make(chan error, 1)
+
+	go func() {
+		defer close(tokenChan)
+		defer close(errChan)
+
+		output, err := b.client.ConverseStream(ctx, &bedrockruntime.ConverseStreamInput{
+			ModelId:  aws.String(b.model),
+			Messages: b.messages(req),
+			System:   b.system(req),
+		})
+		if err != nil {
+			errChan <- fmt.Errorf("failed to call Bedrock ConverseStream API: %w", err)
+			return
+		}
+
+		stream := output.GetStream()
+		defer func() {
+			if err := stream.Close(); err != nil {
+				slog.WarnContext(ctx, "Failed to close Bedrock stream", "error", err)
+			}
+		}()
+
+		for event := range stream.Events() {
+			delta, ok := event.(*types.ConverseStreamOutputMemberContentBlockDelta)
+			if !ok {
+				continue
+			}
+			text, ok := delta.Value.Delta.(*types.ContentBlockDeltaMemberText)
+			if !ok {
+				continue
+			}
+
+			select {
+			case tokenChan <- text.Value:
+			case <-ctx.Done():
+				errChan <- ctx.Err()
+				return
+			}
+		}
+
+		if err := stream.Err(); err != nil {
+			errChan <- fmt.Errorf("bedrock stream failed: %w", err)
+		}
+	}()
+
+	return tokenChan, errChan
+}
+
+// GenerateJSON requests JSON output and decodes it into out
+func (b *BedrockProvider) GenerateJSON(ctx context.Context, req Request, out any) error {
+	return generateJSON(ctx, b, req, out)
+}
+
+func (b *BedrockProvider) messages(req Request) []types.Message {
+	prompt := req.Prompt
+	if req.JSON {
+		// Converse has no JSON mode, so ask for it in the prompt instead
+		prompt += "\n\nRespond with valid JSON only."
+	}
+
+	content := make([]types.ContentBlock, 0, len(req.Images)+1)
+	for _, image := range req.Images {
+		content = append(content, &types.ContentBlockMemberImage{Value: types.ImageBlock{
+			Format: types.ImageFormat(strings.TrimPrefix(image.MediaType, "image/")),
+			Source: &types.ImageSourceMemberBytes{Value: image.Data},
+		}})
+	}
+	content = append(content, &types.ContentBlockMemberText{Value: prompt})
+
+	return []types.Message{
+		{
+			Role:    types.ConversationRoleUser,
+			Content: content,
+		},
+	}
+}
+
+func (b *BedrockProvider) system(req Request) []types.SystemContentBlock {
+	if req.System == "" {
+		return nil
+	}
+	return []types.SystemContentBlock{&types.SystemContentBlockMemberText{Value: req.System}}
+}
+
+// Embed generates a vector embedding for text using a Titan embedding model
+func (b *BedrockProvider) Embed(ctx context.Context, text string) ([]float32, error) {
+	body, err := json.Marshal(map[string]string{"inputText": text})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal embedding request: %w", err)
+	}
+
+	output, err := b.client.InvokeModel(ctx, &bedrockruntime.InvokeModelInput{
+		ModelId:     aws.String(b.embeddingModel),
+		ContentType: aws.String("application/json"),
+		Body:        body,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to call Bedrock InvokeModel API: %w", err)
+	}
+
+	var result struct {
+		Embedding []float32 `json:"embedding"`
+	}
+	if err := json.Unmarshal(output.Body, &result); err != nil {
+		return nil, fmt.Errorf("failed to decode Bedrock embedding response: %w", err)
+	}
+
+	return result.Embedding, nil
+}
@@ -0,0 +1,89 @@
+package ai
+
+import (
+	"context"
+
+	"github.com/kazemisoroush/assistant/pkg/breaker"
+)
+
+// BreakerProvider stops calling a provider that keeps failing with transient
+// errors, failing fast with breaker.ErrOpen until the circuit half-opens
+type BreakerProvider struct {
+	provider Provider
+	breaker  *breaker.Breaker
+}
+
+// NewBreakerProvider wraps a provider with a circuit breaker
+func NewBreakerProvider(provider Provider, b *breaker.Breaker) Provider {
+	return &BreakerProvider{
+		provider: provider,
+		breaker:  b,
+	}
+}
+
+// Name returns the wrapped provider name
+func (b *BreakerProvider) Name() string {
+	return b.provider.Name()
+}
+
+// Model returns the wrapped provider model
+func (b *BreakerProvider) Model() string {
+	return b.provider.Model()
+}
+
+// Generate calls the wrapped provider unless the circuit is open
+func (b *BreakerProvider) Generate(ctx context.Context, req Request) (Response, error) {
+	if err := b.breaker.Allow(); err != nil {
+		return Response{}, err
+	}
+	resp, err := b.provider.Generate(ctx, req)
+	b.breaker.Record(IsTransient(err))
+	return resp, err
+}
+
+// GenerateJSON calls the wrapped provider unless the circuit is open
+func (b *BreakerProvider) GenerateJSON(ctx context.Context, req Request, out any) error {
+	if err := b.breaker.Allow(); err != nil {
+		return err
+	}
+	err := b.provider.GenerateJSON(ctx, req, out)
+	b.breaker.Record(IsTransient(err))
+	return err
+}
+
+// Stream calls the wrapped provider unless the circuit is open. The outcome
+// is recorded once the stream finishes.
+func (b *BreakerProvider) Stream(ctx context.Context, req Request) (<-chan string, <-chan error) {
+	tokenChan := make(chan string)
+	errChan := make(chan error, 1)
+
+	if err := b.breaker.Allow(); err != nil {
+		close(tokenChan)
+		errChan <- err
+		close(errChan)
+		return tokenChan, errChan
+	}
+
+	go func() {
+		defer close(tokenChan)
+		defer close(errChan)
+
+		_, err := forwardStream(ctx, b.provider, req, tokenChan)
+		b.breaker.Record(IsTransient(err))
+		if err != nil {
+			errChan <- err
+		}
+	}()
+
+	return tokenChan, errChan
+}
+
+// Embed calls the wrapped provider unless the circuit is open
+func (b *BreakerProvider) Embed(ctx context.Context, text string) ([]float32, error) {
+	if err := b.breaker.Allow(); err != nil {
+		return nil, err
+	}
+	embedding, err := b.provider.Embed(ctx, text)
+	b.breaker.Record(IsTransient(err))
+	return embedding, err
+}
@@ -0,0 +1,49 @@
+package ai_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/kazemisoroush/assistant/pkg/ai"
+	"github.com/kazemisoroush/assistant/pkg/ai/mocks"
+	"github.com/kazemisoroush/assistant/pkg/breaker"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestBreakerProvider_Generate_FailsFastWhenOpen(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	inner := mocks.NewMockProvider(ctrl)
+	inner.EXPECT().Generate(gomock.Any(), gomock.Any()).Return(ai.Response{}, &ai.StatusError{Provider: "ollama", StatusCode: http.StatusServiceUnavailable}).Times(2)
+	provider := ai.NewBreakerProvider(inner, breaker.New("ollama", breaker.Config{FailureThreshold: 2, OpenTimeout: time.Minute}))
+	ctx := context.Background()
+	_, _ = provider.Generate(ctx, ai.Request{Prompt: "a"})
+	_, _ = provider.Generate(ctx, ai.Request{Prompt: "b"})
+
+	// Act
+	_, err := provider.Generate(ctx, ai.Request{Prompt: "c"})
+
+	// Assert
+	assert.ErrorIs(t, err, breaker.ErrOpen, "Generate() should not call a provider whose circuit is open")
+}
+
+func TestBreakerProvider_Generate_IgnoresPermanentErrors(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	inner := mocks.NewMockProvider(ctrl)
+	inner.EXPECT().Generate(gomock.Any(), gomock.Any()).Return(ai.Response{}, errors.New("invalid model")).Times(3)
+	provider := ai.NewBreakerProvider(inner, breaker.New("ollama", breaker.Config{FailureThreshold: 1, OpenTimeout: time.Minute}))
+	ctx := context.Background()
+	_, _ = provider.Generate(ctx, ai.Request{Prompt: "a"})
+	_, _ = provider.Generate(ctx, ai.Request{Prompt: "b"})
+
+	// Act
+	_, err := provider.Generate(ctx, ai.Request{Prompt: "c"})
+
+	// Assert
+	assert.EqualError(t, err, "invalid model", "Generate() should keep calling a provider that answers with permanent errors")
+}
@@ -0,0 +1,87 @@
+package ai
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/kazemisoroush/assistant/pkg/sqlite"
+)
+
+// Cache stores generated responses by key
+//
+//go:generate mockgen -destination=./mocks/mock_cache.go -mock_names=Cache=MockCache -package=mocks . Cache
+type Cache interface {
+	// Get returns the cached response and whether it was found
+	Get(ctx context.Context, key string) (string, bool, error)
+
+	// Set stores the response under the key
+	Set(ctx context.Context, key, response string) error
+}
+
+// SQLiteCache is a Cache backed by SQLite with a TTL and a maximum number of entries
+type SQLiteCache struct {
+	db         *sqlite.DB
+	ttl        time.Duration
+	maxEntries int
+}
+
+// NewSQLiteCache creates a new SQLite response cache in the given database
+func NewSQLiteCache(db *sqlite.DB, ttl time.Duration, maxEntries int) (*SQLiteCache, error) {
+	schema := `
+    CREATE TABLE IF NOT EXISTS llm_cache (
+        key TEXT PRIMARY KEY,
+        response TEXT NOT NULL,
+        created_at DATETIME NOT NULL
+    );
+
+    CREATE INDEX IF NOT EXISTS idx_llm_cache_created_at ON llm_cache(created_at);
+    `
+	if _, err := db.Exec(schema); err != nil {
+		return nil, fmt.Errorf("failed to initialize cache schema: %w", err)
+	}
+
+	return &SQLiteCache{
+		db:         db,
+		ttl:        ttl,
+		maxEntries: maxEntries,
+	}, nil
+}
+
+// Get returns the cached response and whether it was found and not expired
+func (c *SQLiteCache) Get(ctx context.Context, key string) (string, bool, error) {
+	var response string
+	err := c.db.QueryRowContext(ctx,
+		`SELECT response FROM llm_cache WHERE key = ? AND created_at > ?`,
+		key, time.Now().Add(-c.ttl),
+	).Scan(&response)
+	if err == sql.ErrNoRows {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, fmt.Errorf("failed to read cache: %w", err)
+	}
+	return response, true, nil
+}
+
+// Set stores the response and evicts expired and excess entries
+func (c *SQLiteCache) Set(ctx context.Context, key, response string) error {
+	now := time.Now()
+	if _, err := c.db.ExecContext(ctx,
+		`INSERT OR REPLACE INTO llm_cache (key, response, created_at) VALUES (?, ?, ?)`,
+		key, response, now,
+	); err != nil {
+		return fmt.Errorf("failed to write cache: %w", err)
+	}
+
+	if _, err := c.db.ExecContext(ctx,
+		`DELETE FROM llm_cache WHERE created_at <= ? OR key NOT IN (
+            SELECT key FROM llm_cache ORDER BY created_at DESC LIMIT ?
+        )`,
+		now.Add(-c.ttl), c.maxEntries,
+	); err != nil {
+		return fmt.Errorf("failed to evict cache entries: %w", err)
+	}
+	return nil
+}
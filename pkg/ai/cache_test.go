@@ -0,0 +1,53 @@
+package ai
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/kazemisoroush/assistant/pkg/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// openDB opens the database at path, closed once the test is done
+func openDB(t testing.TB, path string) *sqlite.DB {
+	t.Helper()
+	db, err := sqlite.Open(path)
+	require.NoError(t, err, "Open() error should be nil")
+	t.Cleanup(func() { _ = db.Close() })
+	return db
+}
+
+func TestSQLiteCache_SetGet(t *testing.T) {
+	// Arrange
+	cache, err := NewSQLiteCache(openDB(t, filepath.Join(t.TempDir(), "cache.db")), time.Hour, 10)
+	require.NoError(t, err, "NewSQLiteCache() error should be nil")
+	ctx := context.Background()
+	require.NoError(t, cache.Set(ctx, "key", "receipt"), "Set() error should be nil")
+
+	// Act
+	response, found, err := cache.Get(ctx, "key")
+
+	// Assert
+	require.NoError(t, err, "Get() error should be nil")
+	assert.True(t, found, "Get() should find the cached response")
+	assert.Equal(t, "receipt", response, "Get() should return the cached response")
+}
+
+func TestSQLiteCache_EvictsBeyondMaxEntries(t *testing.T) {
+	// Arrange
+	cache, err := NewSQLiteCache(openDB(t, filepath.Join(t.TempDir(), "cache.db")), time.Hour, 1)
+	require.NoError(t, err, "NewSQLiteCache() error should be nil")
+	ctx := context.Background()
+	require.NoError(t, cache.Set(ctx, "old", "a"), "Set() error should be nil")
+	require.NoError(t, cache.Set(ctx, "new", "b"), "Set() error should be nil")
+
+	// Act
+	_, found, err := cache.Get(ctx, "old")
+
+	// Assert
+	require.NoError(t, err, "Get() error should be nil")
+	assert.False(t, found, "Get() should not find an evicted entry")
+}
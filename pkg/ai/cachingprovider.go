@@ -0,0 +1,85 @@
+package ai
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"log/slog"
+)
+
+// CachingProvider serves repeated generation requests from a Cache, keyed by
+// provider, model and a hash of the prompt. Streams and embeddings are not cached.
+type CachingProvider struct {
+	provider Provider
+	cache    Cache
+}
+
+// NewCachingProvider wraps a provider with a response cache
+func NewCachingProvider(provider Provider, cache Cache) Provider {
+	return &CachingProvider{
+		provider: provider,
+		cache:    cache,
+	}
+}
+
+// Name returns the wrapped provider name
+func (c *CachingProvider) Name() string {
+	return c.provider.Name()
+}
+
+// Model returns the wrapped provider model
+func (c *CachingProvider) Model() string {
+	return c.provider.Model()
+}
+
+// Generate returns the cached completion or generates and caches a new one
+func (c *CachingProvider) Generate(ctx context.Context, req Request) (Response, error) {
+	key := c.key(req)
+
+	// A broken cache must never fail generation, so cache errors are only logged
+	text, found, err := c.cache.Get(ctx, key)
+	if err != nil {
+		slog.WarnContext(ctx, "Failed to read LLM cache", "error", err)
+	}
+	if found {
+		return Response{Text: text}, nil
+	}
+
+	resp, err := c.provider.Generate(ctx, req)
+	if err != nil {
+		return Response{}, err
+	}
+
+	if err := c.cache.Set(ctx, key, resp.Text); err != nil {
+		slog.WarnContext(ctx, "Failed to write LLM cache", "error", err)
+	}
+	return resp, nil
+}
+
+// GenerateJSON requests JSON output through the cache and decodes it into out
+func (c *CachingProvider) GenerateJSON(ctx context.Context, req Request, out any) error {
+	return generateJSON(ctx, c, req, out)
+}
+
+// Stream passes through to the wrapped provider
+func (c *CachingProvider) Stream(ctx context.Context, req Request) (<-chan string, <-chan error) {
+	return c.provider.Stream(ctx, req)
+}
+
+// Embed passes through to the wrapped provider
+func (c *CachingProvider) Embed(ctx context.Context, text string) ([]float32, error) {
+	return c.provider.Embed(ctx, text)
+}
+
+func (c *CachingProvider) key(req Request) string {
+	// Marshalling a struct of strings, bools and byte slices cannot fail
+	data, _ := json.Marshal(struct {
+		Provider string
+		Model    string
+		Request  Request
+	}{c.provider.Name(), c.provider.Model(), req})
+
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,48 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"syscall"
+)
+
+// StatusError is returned when a provider answers with a non-success HTTP status
+type StatusError struct {
+	Provider   string
+	StatusCode int
+}
+
+// Error implements error
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("%s API returned non-200 status: %d", e.Provider, e.StatusCode)
+}
+
+// HTTPStatusCode returns the HTTP status code of the failed call
+func (e *StatusError) HTTPStatusCode() int {
+	return e.StatusCode
+}
+
+// IsTransient reports whether err is worth retrying: connection refused,
+// timeouts, throttling (429) and server errors (5xx)
+func IsTransient(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return false
+	}
+
+	// Covers StatusError as well as AWS SDK response errors
+	var statusErr interface{ HTTPStatusCode() int }
+	if errors.As(err, &statusErr) {
+		code := statusErr.HTTPStatusCode()
+		return code == http.StatusTooManyRequests || code >= http.StatusInternalServerError
+	}
+
+	if errors.Is(err, syscall.ECONNREFUSED) || errors.Is(err, syscall.ECONNRESET) {
+		return true
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
@@ -0,0 +1,190 @@
+package ai
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockruntime"
+
+	"github.com/kazemisoroush/assistant/pkg/breaker"
+)
+
+// Config represents the settings needed to build any of the supported providers
+type Config struct {
+	HTTPClient *http.Client
+	AWSConfig  aws.Config
+
+	Ollama    ModelConfig
+	Bedrock   ModelConfig
+	OpenAI    ModelConfig
+	Anthropic ModelConfig
+	LlamaCpp  LlamaCppConfig
+
+	// Retry applies to every provider in a chain before falling back to the next one
+	Retry RetryConfig
+
+	// Cache, when set, serves repeated generation requests without calling the provider
+	Cache Cache
+
+	// Usage, when set, records every call made to a provider, including retries
+	Usage UsageStore
+
+	// Breaker opens a circuit around each remote provider after repeated transient failures
+	Breaker breaker.Config
+
+	// Guardrails apply to cloud providers before anything leaves the machine
+	Guardrails GuardrailConfig
+
+	// Routes send the requests of a task to a specific provider and model
+	// instead of the default chain, keyed by task name
+	Routes map[string]Route
+}
+
+// ModelConfig represents the endpoint and models of a single provider
+type ModelConfig struct {
+	URL            string // Ignored by Bedrock, which uses the AWS configuration
+	APIKey         string // Only used by OpenAI-compatible endpoints and Anthropic
+	Model          string
+	EmbeddingModel string
+	MaxTokens      int // Only used by Anthropic, which requires an output limit
+}
+
+// withModel returns the configuration with the generation or embedding model replaced
+func (m ModelConfig) withModel(model string, embedding bool) ModelConfig {
+	switch {
+	case model == "":
+	case embedding:
+		m.EmbeddingModel = model
+	default:
+		m.Model = model
+	}
+	return m
+}
+
+// NewProvider creates the provider registered under the given name
+func NewProvider(name string, cfg Config) (Provider, error) {
+	switch name {
+	case ProviderOllama:
+		return NewOllamaProvider(cfg.HTTPClient, cfg.Ollama.URL, cfg.Ollama.Model, cfg.Ollama.EmbeddingModel), nil
+	case ProviderBedrock:
+		client := bedrockruntime.NewFromConfig(cfg.AWSConfig)
+		return NewBedrockProvider(client, cfg.Bedrock.Model, cfg.Bedrock.EmbeddingModel), nil
+	case ProviderOpenAI:
+		return NewOpenAIProvider(cfg.HTTPClient, cfg.OpenAI.URL, cfg.OpenAI.APIKey, cfg.OpenAI.Model, cfg.OpenAI.EmbeddingModel), nil
+	case ProviderAnthropic:
+		return NewAnthropicProvider(cfg.HTTPClient, cfg.Anthropic.URL, cfg.Anthropic.APIKey, cfg.Anthropic.Model, cfg.Anthropic.MaxTokens), nil
+	case ProviderLlamaCpp:
+		provider, err := NewLlamaCppProvider(cfg.LlamaCpp)
+		if err != nil {
+			return nil, err
+		}
+		return provider, nil
+	default:
+		return nil, fmt.Errorf("unknown AI provider: %s", name)
+	}
+}
+
+// NewProviderChain creates a provider that tries the named providers in order.
+// Tasks with a route are sent to their routed provider first.
+func NewProviderChain(names []string, cfg Config) (Provider, error) {
+	if len(names) == 0 {
+		return nil, fmt.Errorf("at least one AI provider is required")
+	}
+
+	providers := make([]Provider, 0, len(names))
+	for _, name := range names {
+		p, err := newWrappedProvider(name, cfg)
+		if err != nil {
+			return nil, err
+		}
+		providers = append(providers, p)
+	}
+
+	chain := providers[0]
+	if len(providers) > 1 {
+		chain = NewFallbackProvider(providers...)
+	}
+	if len(cfg.Routes) == 0 {
+		return chain, nil
+	}
+	return newRouter(chain, cfg)
+}
+
+// newRouter routes tasks to their configured providers. Routed generation
+// falls back to the default chain; routed embeddings do not, because vectors
+// from different models cannot be compared.
+func newRouter(chain Provider, cfg Config) (Provider, error) {
+	routes := make(map[string]Provider, len(cfg.Routes))
+	for task, route := range cfg.Routes {
+		if !slices.Contains(routableTasks, task) {
+			return nil, fmt.Errorf("unknown AI task %q, expected one of %s", task, strings.Join(routableTasks, ", "))
+		}
+		p, err := newWrappedProvider(route.Provider, cfg.withRoute(route, task == TaskEmbedding))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create provider for task %s: %w", task, err)
+		}
+		if task != TaskEmbedding {
+			p = NewFallbackProvider(p, chain)
+		}
+		routes[task] = p
+	}
+	return NewRoutingProvider(chain, routes), nil
+}
+
+// routableTasks are the tasks that can be routed to a specific model
+var routableTasks = []string{
+	TaskClassification,
+	TaskMetadataExtraction,
+	TaskEntityExtraction,
+	TaskTranscription,
+	TaskReranking,
+	TaskAnswering,
+	TaskEmbedding,
+	TaskSummarization,
+}
+
+// withRoute returns the configuration with the routed model set on the routed provider
+func (c Config) withRoute(route Route, embedding bool) Config {
+	switch route.Provider {
+	case ProviderOllama:
+		c.Ollama = c.Ollama.withModel(route.Model, embedding)
+	case ProviderBedrock:
+		c.Bedrock = c.Bedrock.withModel(route.Model, embedding)
+	case ProviderOpenAI:
+		c.OpenAI = c.OpenAI.withModel(route.Model, embedding)
+	case ProviderAnthropic:
+		c.Anthropic = c.Anthropic.withModel(route.Model, embedding)
+	case ProviderLlamaCpp:
+		c.LlamaCpp = c.LlamaCpp.withModel(route.Model, embedding)
+	}
+	return c
+}
+
+// newWrappedProvider creates the named provider with metering, guardrails,
+// retries, a circuit breaker and caching applied as configured. The breaker
+// sits outside the retries so an exhausted retry counts as one failure.
+func newWrappedProvider(name string, cfg Config) (Provider, error) {
+	p, err := NewProvider(name, cfg)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Usage != nil {
+		p = NewMeteringProvider(p, cfg.Usage)
+	}
+	if !isLocalProvider(name) {
+		p = NewGuardrailProvider(p, cfg.Guardrails.Redactor, cfg.Guardrails.AllowedRecordTypes[name])
+	}
+	if cfg.Retry.MaxAttempts > 1 {
+		p = NewRetryProvider(p, cfg.Retry)
+	}
+	if cfg.Breaker.FailureThreshold > 0 && name != ProviderLlamaCpp {
+		p = NewBreakerProvider(p, breaker.New(name, cfg.Breaker))
+	}
+	if cfg.Cache != nil {
+		p = NewCachingProvider(p, cfg.Cache)
+	}
+	return p, nil
+}
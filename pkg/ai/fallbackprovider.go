@@ -0,0 +1,112 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+)
+
+// FallbackProvider tries each provider in order until one succeeds,
+// e.g. a local model first with a cloud model as backup.
+type FallbackProvider struct {
+	providers []Provider
+}
+
+// NewFallbackProvider creates a provider that falls back through the given providers in order
+func NewFallbackProvider(providers ...Provider) Provider {
+	return &FallbackProvider{
+		providers: providers,
+	}
+}
+
+// Name returns the names of the chained providers
+func (f *FallbackProvider) Name() string {
+	names := make([]string, len(f.providers))
+	for i, p := range f.providers {
+		names[i] = p.Name()
+	}
+	return strings.Join(names, ">")
+}
+
+// Model returns the models of the chained providers
+func (f *FallbackProvider) Model() string {
+	models := make([]string, len(f.providers))
+	for i, p := range f.providers {
+		models[i] = p.Model()
+	}
+	return strings.Join(models, ">")
+}
+
+// Generate returns the completion from the first provider that succeeds
+func (f *FallbackProvider) Generate(ctx context.Context, req Request) (Response, error) {
+	var errs []error
+	for _, p := range f.providers {
+		resp, err := p.Generate(ctx, req)
+		if err == nil {
+			return resp, nil
+		}
+		errs = append(errs, f.failed(ctx, p, err))
+	}
+	return Response{}, errors.Join(errs...)
+}
+
+// GenerateJSON decodes JSON output from the first provider that succeeds
+func (f *FallbackProvider) GenerateJSON(ctx context.Context, req Request, out any) error {
+	var errs []error
+	for _, p := range f.providers {
+		err := p.GenerateJSON(ctx, req, out)
+		if err == nil {
+			return nil
+		}
+		errs = append(errs, f.failed(ctx, p, err))
+	}
+	return errors.Join(errs...)
+}
+
+// Stream returns the completion from the first provider that starts streaming.
+// A provider that fails after delivering tokens is not replaced mid-answer.
+func (f *FallbackProvider) Stream(ctx context.Context, req Request) (<-chan string, <-chan error) {
+	tokenChan := make(chan string)
+	errChan := make(chan error, 1)
+
+	go func() {
+		defer close(tokenChan)
+		defer close(errChan)
+
+		var errs []error
+		for _, p := range f.providers {
+			started, err := forwardStream(ctx, p, req, tokenChan)
+			if err == nil {
+				return
+			}
+			if started {
+				errChan <- err
+				return
+			}
+			errs = append(errs, f.failed(ctx, p, err))
+		}
+		errChan <- errors.Join(errs...)
+	}()
+
+	return tokenChan, errChan
+}
+
+// Embed returns the embedding from the first provider that succeeds
+func (f *FallbackProvider) Embed(ctx context.Context, text string) ([]float32, error) {
+	var errs []error
+	for _, p := range f.providers {
+		embedding, err := p.Embed(ctx, text)
+		if err == nil {
+			return embedding, nil
+		}
+		errs = append(errs, f.failed(ctx, p, err))
+	}
+	return nil, errors.Join(errs...)
+}
+
+func (f *FallbackProvider) failed(ctx context.Context, p Provider, err error) error {
+	slog.WarnContext(ctx, "AI provider failed, trying next", "provider", p.Name(), "error", err)
+	return fmt.Errorf("%s: %w", p.Name(), err)
+}
@@ -0,0 +1,52 @@
+package ai_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/kazemisoroush/assistant/pkg/ai"
+	"github.com/kazemisoroush/assistant/pkg/ai/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestFallbackProvider_Generate_FallsBackOnError(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	local := mocks.NewMockProvider(ctrl)
+	cloud := mocks.NewMockProvider(ctrl)
+	req := ai.Request{Prompt: "hello"}
+	local.EXPECT().Name().Return("ollama").AnyTimes()
+	local.EXPECT().Generate(gomock.Any(), req).Return(ai.Response{}, errors.New("connection refused"))
+	cloud.EXPECT().Generate(gomock.Any(), req).Return(ai.Response{Text: "hi"}, nil)
+	provider := ai.NewFallbackProvider(local, cloud)
+
+	// Act
+	resp, err := provider.Generate(context.Background(), req)
+
+	// Assert
+	require.NoError(t, err, "Generate() error should be nil")
+	assert.Equal(t, "hi", resp.Text, "Generate() should return the fallback response")
+}
+
+func TestFallbackProvider_Generate_AllFail(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	local := mocks.NewMockProvider(ctrl)
+	cloud := mocks.NewMockProvider(ctrl)
+	local.EXPECT().Name().Return("ollama").AnyTimes()
+	cloud.EXPECT().Name().Return("bedrock").AnyTimes()
+	local.EXPECT().Generate(gomock.Any(), gomock.Any()).Return(ai.Response{}, errors.New("local down"))
+	cloud.EXPECT().Generate(gomock.Any(), gomock.Any()).Return(ai.Response{}, errors.New("cloud down"))
+	provider := ai.NewFallbackProvider(local, cloud)
+
+	// Act
+	_, err := provider.Generate(context.Background(), ai.Request{Prompt: "hello"})
+
+	// Assert
+	require.Error(t, err, "Generate() should fail when every provider fails")
+	assert.Contains(t, err.Error(), "local down", "error should include the first failure")
+	assert.Contains(t, err.Error(), "cloud down", "error should include the last failure")
+}
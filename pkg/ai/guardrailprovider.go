@@ -0,0 +1,109 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/kazemisoroush/assistant/pkg/redact"
+)
+
+// ErrRecordTypeNotAllowed is returned when a request carries content of a
+// record type that may not be sent to the provider
+var ErrRecordTypeNotAllowed = errors.New("record type not allowed for provider")
+
+// GuardrailConfig controls what leaves the machine for cloud providers
+type GuardrailConfig struct {
+	// Redactor, when set, removes PII from prompts and embedding input
+	Redactor redact.Redactor
+
+	// AllowedRecordTypes lists, by provider name, the record types whose content
+	// may be sent to that provider; providers without an entry accept every type
+	AllowedRecordTypes map[string][]string
+}
+
+// GuardrailProvider redacts PII from requests and rejects record types that
+// are not allowed before they reach the wrapped provider. Requests with an
+// unknown record type, such as classification, are redacted but not rejected.
+type GuardrailProvider struct {
+	provider     Provider
+	redactor     redact.Redactor
+	allowedTypes []string
+}
+
+// NewGuardrailProvider wraps a provider with PII redaction and a record type
+// allowlist. A nil redactor disables redaction and a nil allowlist allows every type.
+func NewGuardrailProvider(provider Provider, redactor redact.Redactor, allowedTypes []string) Provider {
+	return &GuardrailProvider{
+		provider:     provider,
+		redactor:     redactor,
+		allowedTypes: allowedTypes,
+	}
+}
+
+// Name returns the wrapped provider name
+func (g *GuardrailProvider) Name() string {
+	return g.provider.Name()
+}
+
+// Model returns the wrapped provider model
+func (g *GuardrailProvider) Model() string {
+	return g.provider.Model()
+}
+
+// Generate checks and redacts the request before generating
+func (g *GuardrailProvider) Generate(ctx context.Context, req Request) (Response, error) {
+	req, err := g.guard(req)
+	if err != nil {
+		return Response{}, err
+	}
+	return g.provider.Generate(ctx, req)
+}
+
+// GenerateJSON checks and redacts the request before generating
+func (g *GuardrailProvider) GenerateJSON(ctx context.Context, req Request, out any) error {
+	req, err := g.guard(req)
+	if err != nil {
+		return err
+	}
+	return g.provider.GenerateJSON(ctx, req, out)
+}
+
+// Stream checks and redacts the request before streaming
+func (g *GuardrailProvider) Stream(ctx context.Context, req Request) (<-chan string, <-chan error) {
+	req, err := g.guard(req)
+	if err != nil {
+		tokenChan := make(chan string)
+		errChan := make(chan error, 1)
+		close(tokenChan)
+		errChan <- err
+		close(errChan)
+		return tokenChan, errChan
+	}
+	return g.provider.Stream(ctx, req)
+}
+
+// Embed redacts the text before embedding
+func (g *GuardrailProvider) Embed(ctx context.Context, text string) ([]float32, error) {
+	return g.provider.Embed(ctx, g.redact(text))
+}
+
+// guard rejects disallowed record types and returns the redacted request
+func (g *GuardrailProvider) guard(req Request) (Request, error) {
+	for _, recordType := range append([]string{req.RecordType}, req.RecordTypes...) {
+		if recordType != "" && g.allowedTypes != nil && !slices.Contains(g.allowedTypes, recordType) {
+			return Request{}, fmt.Errorf("%w: %s records may not be sent to %s", ErrRecordTypeNotAllowed, recordType, g.provider.Name())
+		}
+	}
+	req.System = g.redact(req.System)
+	req.Prompt = g.redact(req.Prompt)
+	return req, nil
+}
+
+func (g *GuardrailProvider) redact(text string) string {
+	if g.redactor == nil {
+		return text
+	}
+	return g.redactor.Redact(text)
+}
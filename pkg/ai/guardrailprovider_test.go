@@ -0,0 +1,74 @@
+package ai_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kazemisoroush/assistant/pkg/ai"
+	"github.com/kazemisoroush/assistant/pkg/ai/mocks"
+	"github.com/kazemisoroush/assistant/pkg/redact"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestGuardrailProvider_Generate_RedactsPrompt(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	inner := mocks.NewMockProvider(ctrl)
+	inner.EXPECT().Generate(gomock.Any(), ai.Request{Prompt: "Receipt for [EMAIL]", RecordType: "receipt"}).Return(ai.Response{Text: "ok"}, nil)
+	provider := ai.NewGuardrailProvider(inner, redact.NewPatternRedactor(), []string{"receipt"})
+
+	// Act
+	resp, err := provider.Generate(context.Background(), ai.Request{Prompt: "Receipt for jane@example.com", RecordType: "receipt"})
+
+	// Assert
+	require.NoError(t, err, "Generate() error should be nil")
+	assert.Equal(t, "ok", resp.Text, "Generate() should return the wrapped response")
+}
+
+func TestGuardrailProvider_GenerateJSON_RejectsDisallowedRecordType(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	inner := mocks.NewMockProvider(ctrl)
+	inner.EXPECT().Name().Return(ai.ProviderBedrock).AnyTimes()
+	provider := ai.NewGuardrailProvider(inner, nil, []string{"receipt"})
+
+	// Act
+	err := provider.GenerateJSON(context.Background(), ai.Request{Prompt: "Lab results", RecordType: "health_lab"}, &map[string]any{})
+
+	// Assert
+	assert.ErrorIs(t, err, ai.ErrRecordTypeNotAllowed, "GenerateJSON() should keep disallowed record types off the provider")
+}
+
+func TestGuardrailProvider_Generate_RejectsDisallowedRecordTypes(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	inner := mocks.NewMockProvider(ctrl)
+	inner.EXPECT().Name().Return(ai.ProviderBedrock).AnyTimes()
+	provider := ai.NewGuardrailProvider(inner, nil, []string{"receipt"})
+
+	// Act
+	_, err := provider.Generate(context.Background(), ai.Request{Prompt: "What did the lab find?", RecordTypes: []string{"receipt", "health_lab"}})
+
+	// Assert
+	assert.ErrorIs(t, err, ai.ErrRecordTypeNotAllowed, "Generate() should check every record type the prompt draws on")
+}
+
+func TestGuardrailProvider_Stream_AllowsUnknownRecordType(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	inner := mocks.NewMockProvider(ctrl)
+	tokens := make(chan string)
+	errs := make(chan error)
+	close(tokens)
+	close(errs)
+	inner.EXPECT().Stream(gomock.Any(), ai.Request{Prompt: "classify"}).Return(tokens, errs)
+	provider := ai.NewGuardrailProvider(inner, nil, []string{})
+
+	// Act
+	_, errChan := provider.Stream(context.Background(), ai.Request{Prompt: "classify"})
+
+	// Assert
+	assert.NoError(t, <-errChan, "Stream() should allow requests whose record type is not known yet")
+}
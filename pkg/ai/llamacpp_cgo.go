@@ -0,0 +1,243 @@
+//go:build llamacpp && cgo
+
+package ai
+
+/*
+#cgo LDFLAGS: -lllama
+#include <stdlib.h>
+#include <llama.h>
+*/
+import "C"
+
+import (
+	"fmt"
+	"math"
+	"sync"
+	"unsafe"
+)
+
+// pieceBufferSize is large enough for any single token piece
+const pieceBufferSize = 256
+
+var llamaBackendOnce sync.Once
+
+// cgoLlamaModel is a llama.cpp model with one inference context
+type cgoLlamaModel struct {
+	model *C.struct_llama_model
+	ctx   *C.struct_llama_context
+	vocab *C.struct_llama_vocab
+}
+
+// loadLlamaModelImpl loads a GGUF model and creates a generation or embedding context
+func loadLlamaModelImpl(path string, cfg LlamaCppConfig, embeddings bool) (llamaModel, error) {
+	llamaBackendOnce.Do(func() { C.llama_backend_init() })
+
+	cPath := C.CString(path)
+	defer C.free(unsafe.Pointer(cPath))
+
+	modelParams := C.llama_model_default_params()
+	modelParams.n_gpu_layers = C.int32_t(cfg.GPULayers)
+	model := C.llama_model_load_from_file(cPath, modelParams)
+	if model == nil {
+		return nil, fmt.Errorf("llama.cpp could not load %s", path)
+	}
+
+	ctxParams := C.llama_context_default_params()
+	ctxParams.n_ctx = C.uint32_t(cfg.ContextSize)
+	ctxParams.n_batch = C.uint32_t(cfg.ContextSize)
+	ctxParams.embeddings = C.bool(embeddings)
+	if embeddings {
+		// Pooled embeddings need the whole input in a single micro-batch
+		ctxParams.n_ubatch = C.uint32_t(cfg.ContextSize)
+	}
+	if cfg.Threads > 0 {
+		ctxParams.n_threads = C.int32_t(cfg.Threads)
+		ctxParams.n_threads_batch = C.int32_t(cfg.Threads)
+	}
+	ctx := C.llama_init_from_model(model, ctxParams)
+	if ctx == nil {
+		C.llama_model_free(model)
+		return nil, fmt.Errorf("llama.cpp could not create a context for %s", path)
+	}
+
+	return &cgoLlamaModel{
+		model: model,
+		ctx:   ctx,
+		vocab: C.llama_model_get_vocab(model),
+	}, nil
+}
+
+// generate runs the chat-formatted prompt with greedy sampling
+func (m *cgoLlamaModel) generate(system, prompt string, maxTokens int, onToken func(string) error) (Usage, error) {
+	C.llama_memory_clear(C.llama_get_memory(m.ctx), C.bool(true))
+
+	tokens, n, err := m.tokenize(m.applyChatTemplate(system, prompt))
+	if err != nil {
+		return Usage{}, err
+	}
+	defer C.free(unsafe.Pointer(tokens))
+
+	if C.llama_decode(m.ctx, C.llama_batch_get_one(tokens, n)) != 0 {
+		return Usage{}, fmt.Errorf("llama.cpp failed to evaluate the prompt")
+	}
+
+	sampler := C.llama_sampler_chain_init(C.llama_sampler_chain_default_params())
+	defer C.llama_sampler_free(sampler)
+	C.llama_sampler_chain_add(sampler, C.llama_sampler_init_greedy())
+
+	// Generated tokens are fed back one at a time through C memory
+	next := (*C.llama_token)(C.malloc(C.size_t(unsafe.Sizeof(C.llama_token(0)))))
+	defer C.free(unsafe.Pointer(next))
+	piece := (*C.char)(C.malloc(pieceBufferSize))
+	defer C.free(unsafe.Pointer(piece))
+
+	usage := Usage{InputTokens: int(n)}
+	for usage.OutputTokens < maxTokens {
+		*next = C.llama_sampler_sample(sampler, m.ctx, -1)
+		if C.llama_vocab_is_eog(m.vocab, *next) {
+			break
+		}
+		usage.OutputTokens++
+
+		length := C.llama_token_to_piece(m.vocab, *next, piece, pieceBufferSize, 0, C.bool(false))
+		if length > 0 {
+			if err := onToken(C.GoStringN(piece, length)); err != nil {
+				return usage, err
+			}
+		}
+
+		if C.llama_decode(m.ctx, C.llama_batch_get_one(next, 1)) != 0 {
+			return usage, fmt.Errorf("llama.cpp failed to decode token %d", usage.OutputTokens)
+		}
+	}
+	return usage, nil
+}
+
+// embed returns the pooled embedding of text, normalized to unit length
+func (m *cgoLlamaModel) embed(text string) ([]float32, error) {
+	C.llama_memory_clear(C.llama_get_memory(m.ctx), C.bool(true))
+
+	tokens, n, err := m.tokenize(text)
+	if err != nil {
+		return nil, err
+	}
+	defer C.free(unsafe.Pointer(tokens))
+
+	batch := C.llama_batch_get_one(tokens, n)
+	var rc C.int32_t
+	if C.llama_model_has_encoder(m.model) && !C.llama_model_has_decoder(m.model) {
+		rc = C.llama_encode(m.ctx, batch)
+	} else {
+		rc = C.llama_decode(m.ctx, batch)
+	}
+	if rc != 0 {
+		return nil, fmt.Errorf("llama.cpp failed to evaluate the embedding input")
+	}
+
+	values := C.llama_get_embeddings_seq(m.ctx, 0)
+	if values == nil {
+		values = C.llama_get_embeddings_ith(m.ctx, -1)
+	}
+	if values == nil {
+		return nil, fmt.Errorf("llama.cpp returned no embedding")
+	}
+
+	dims := int(C.llama_model_n_embd(m.model))
+	raw := unsafe.Slice((*float32)(unsafe.Pointer(values)), dims)
+	return normalize(raw), nil
+}
+
+// close releases the context and the model
+func (m *cgoLlamaModel) close() {
+	C.llama_free(m.ctx)
+	C.llama_model_free(m.model)
+}
+
+// tokenize converts text to tokens held in C memory, which the caller must free
+func (m *cgoLlamaModel) tokenize(text string) (*C.llama_token, C.int32_t, error) {
+	cText := C.CString(text)
+	defer C.free(unsafe.Pointer(cText))
+
+	// A first call without a buffer returns the negated token count
+	n := -C.llama_tokenize(m.vocab, cText, C.int32_t(len(text)), nil, 0, C.bool(true), C.bool(true))
+	if n <= 0 {
+		return nil, 0, fmt.Errorf("llama.cpp produced no tokens")
+	}
+	if uint32(n) > uint32(C.llama_n_ctx(m.ctx)) {
+		return nil, 0, fmt.Errorf("input of %d tokens exceeds the context size of %d", n, C.llama_n_ctx(m.ctx))
+	}
+
+	tokens := (*C.llama_token)(C.malloc(C.size_t(n) * C.size_t(unsafe.Sizeof(C.llama_token(0)))))
+	C.llama_tokenize(m.vocab, cText, C.int32_t(len(text)), tokens, n, C.bool(true), C.bool(true))
+	return tokens, n, nil
+}
+
+// applyChatTemplate formats the messages with the model's built-in chat
+// template, falling back to plain concatenation when the model has none
+func (m *cgoLlamaModel) applyChatTemplate(system, prompt string) string {
+	tmpl := C.llama_model_chat_template(m.model, nil)
+	if tmpl == nil {
+		return plainPrompt(system, prompt)
+	}
+
+	type message struct{ role, content string }
+	messages := []message{{"user", prompt}}
+	if system != "" {
+		messages = append([]message{{"system", system}}, messages...)
+	}
+
+	chat := (*C.struct_llama_chat_message)(C.malloc(C.size_t(len(messages)) * C.size_t(unsafe.Sizeof(C.struct_llama_chat_message{}))))
+	defer C.free(unsafe.Pointer(chat))
+	entries := unsafe.Slice(chat, len(messages))
+	for i, msg := range messages {
+		entries[i].role = C.CString(msg.role)
+		entries[i].content = C.CString(msg.content)
+	}
+	defer func() {
+		for _, entry := range entries {
+			C.free(unsafe.Pointer(entry.role))
+			C.free(unsafe.Pointer(entry.content))
+		}
+	}()
+
+	size := C.int32_t(2 * (len(system) + len(prompt) + 64))
+	for {
+		buf := (*C.char)(C.malloc(C.size_t(size)))
+		n := C.llama_chat_apply_template(tmpl, chat, C.size_t(len(messages)), C.bool(true), buf, size)
+		if n < 0 {
+			// The template is not one llama.cpp knows how to apply
+			C.free(unsafe.Pointer(buf))
+			return plainPrompt(system, prompt)
+		}
+		if n <= size {
+			formatted := C.GoStringN(buf, n)
+			C.free(unsafe.Pointer(buf))
+			return formatted
+		}
+		C.free(unsafe.Pointer(buf))
+		size = n
+	}
+}
+
+func plainPrompt(system, prompt string) string {
+	if system == "" {
+		return prompt
+	}
+	return system + "\n\n" + prompt
+}
+
+func normalize(values []float32) []float32 {
+	var sum float64
+	for _, v := range values {
+		sum += float64(v) * float64(v)
+	}
+
+	out := make([]float32, len(values))
+	norm := math.Sqrt(sum)
+	for i, v := range values {
+		if norm > 0 {
+			out[i] = float32(float64(v) / norm)
+		}
+	}
+	return out
+}
@@ -0,0 +1,8 @@
+//go:build !llamacpp || !cgo
+
+package ai
+
+// loadLlamaModelImpl reports that the binary was built without llama.cpp support
+func loadLlamaModelImpl(_ string, _ LlamaCppConfig, _ bool) (llamaModel, error) {
+	return nil, ErrLlamaCppUnavailable
+}
@@ -0,0 +1,223 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+const (
+	// ProviderLlamaCpp is the name of the in-process llama.cpp provider
+	ProviderLlamaCpp = "llamacpp"
+
+	// DefaultLlamaCppContextSize is used when no context size is configured
+	DefaultLlamaCppContextSize = 4096
+
+	// DefaultLlamaCppMaxTokens is used when no output token limit is configured
+	DefaultLlamaCppMaxTokens = 512
+)
+
+// ErrLlamaCppUnavailable is returned when the binary was built without llama.cpp support
+var ErrLlamaCppUnavailable = errors.New("llama.cpp support is not compiled in; rebuild with -tags llamacpp and libllama installed")
+
+// LlamaCppConfig represents the settings of the in-process llama.cpp provider
+type LlamaCppConfig struct {
+	// ModelPath is the GGUF model used for generation
+	ModelPath string
+
+	// EmbeddingModelPath is the GGUF model used for embeddings; ModelPath is used when empty
+	EmbeddingModelPath string
+
+	// ContextSize is the context window in tokens
+	ContextSize int
+
+	// Threads is the number of CPU threads; 0 lets llama.cpp decide
+	Threads int
+
+	// GPULayers is the number of layers offloaded to the GPU
+	GPULayers int
+
+	// MaxTokens caps the number of generated tokens
+	MaxTokens int
+}
+
+// withModel returns the configuration with the generation or embedding model path replaced
+func (c LlamaCppConfig) withModel(path string, embedding bool) LlamaCppConfig {
+	switch {
+	case path == "":
+	case embedding:
+		c.EmbeddingModelPath = path
+	default:
+		c.ModelPath = path
+	}
+	return c
+}
+
+// llamaModel is a loaded GGUF model and its inference context
+type llamaModel interface {
+	// generate runs the chat-formatted prompt and calls onToken for every generated piece
+	generate(system, prompt string, maxTokens int, onToken func(string) error) (Usage, error)
+
+	// embed returns the pooled, normalized embedding of text
+	embed(text string) ([]float32, error)
+
+	// close releases the model and context
+	close()
+}
+
+// loadLlamaModel is implemented by the llama.cpp binding, or by a stub when built without it
+var loadLlamaModel = loadLlamaModelImpl
+
+// LlamaCppProvider runs GGUF models in-process through llama.cpp, removing the
+// need for an external model server. Model memory is held until Close is called.
+type LlamaCppProvider struct {
+	// mu serializes inference; a llama.cpp context is not safe for concurrent use
+	mu        sync.Mutex
+	model     string
+	maxTokens int
+	generator llamaModel
+	embedder  llamaModel
+}
+
+// NewLlamaCppProvider loads the configured GGUF models
+func NewLlamaCppProvider(cfg LlamaCppConfig) (*LlamaCppProvider, error) {
+	if cfg.ModelPath == "" {
+		return nil, fmt.Errorf("llama.cpp model path is required")
+	}
+	if cfg.ContextSize <= 0 {
+		cfg.ContextSize = DefaultLlamaCppContextSize
+	}
+	if cfg.MaxTokens <= 0 {
+		cfg.MaxTokens = DefaultLlamaCppMaxTokens
+	}
+
+	generator, err := loadLlamaModel(cfg.ModelPath, cfg, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load GGUF model %s: %w", cfg.ModelPath, err)
+	}
+
+	embeddingModelPath := cfg.EmbeddingModelPath
+	if embeddingModelPath == "" {
+		embeddingModelPath = cfg.ModelPath
+	}
+	embedder, err := loadLlamaModel(embeddingModelPath, cfg, true)
+	if err != nil {
+		generator.close()
+		return nil, fmt.Errorf("failed to load GGUF embedding model %s: %w", embeddingModelPath, err)
+	}
+
+	return &LlamaCppProvider{
+		model:     strings.TrimSuffix(filepath.Base(cfg.ModelPath), filepath.Ext(cfg.ModelPath)),
+		maxTokens: cfg.MaxTokens,
+		generator: generator,
+		embedder:  embedder,
+	}, nil
+}
+
+// Name returns the provider name
+func (l *LlamaCppProvider) Name() string {
+	return ProviderLlamaCpp
+}
+
+// Model returns the generation model, named after its GGUF file
+func (l *LlamaCppProvider) Model() string {
+	return l.model
+}
+
+// Generate returns the model completion for the given request
+func (l *LlamaCppProvider) Generate(ctx context.Context, req Request) (Response, error) {
+	var text strings.Builder
+	usage, err := l.run(ctx, req, func(piece string) error {
+		text.WriteString(piece)
+		return nil
+	})
+	if err != nil {
+		return Response{}, err
+	}
+	return Response{Text: text.String(), Usage: usage}, nil
+}
+
+// Stream returns the completion incrementally as llama.cpp generates it
+func (l *LlamaCppProvider) Stream(ctx context.Context, req Request) (<-chan string, <-chan error) {
+	tokenChan := make(chan string)
+	errChan := make(chan error, 1)
+
+	go func() {
+		defer close(tokenChan)
+		defer close(errChan)
+
+		_, err := l.run(ctx, req, func(piece string) error {
+			select {
+			case tokenChan <- piece:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		})
+		if err != nil {
+			errChan <- err
+		}
+	}()
+
+	return tokenChan, errChan
+}
+
+// GenerateJSON requests JSON output and decodes it into out
+func (l *LlamaCppProvider) GenerateJSON(ctx context.Context, req Request, out any) error {
+	return generateJSON(ctx, l, req, out)
+}
+
+// Embed generates a vector embedding for text
+func (l *LlamaCppProvider) Embed(ctx context.Context, text string) ([]float32, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	embedding, err := l.embedder.embed(text)
+	if err != nil {
+		return nil, fmt.Errorf("failed to embed text with llama.cpp: %w", err)
+	}
+	return embedding, nil
+}
+
+// Close releases the loaded models
+func (l *LlamaCppProvider) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.generator.close()
+	l.embedder.close()
+	return nil
+}
+
+func (l *LlamaCppProvider) run(ctx context.Context, req Request, onToken func(string) error) (Usage, error) {
+	if len(req.Images) > 0 {
+		return Usage{}, fmt.Errorf("llama.cpp provider does not support images")
+	}
+
+	prompt := req.Prompt
+	if req.JSON {
+		// No grammar is configured, so ask for JSON in the prompt instead
+		prompt += "\n\nRespond with valid JSON only."
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	usage, err := l.generator.generate(req.System, prompt, l.maxTokens, func(piece string) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		return onToken(piece)
+	})
+	if err != nil {
+		return Usage{}, fmt.Errorf("failed to generate with llama.cpp: %w", err)
+	}
+	return usage, nil
+}
@@ -0,0 +1,114 @@
+package ai
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeLlamaModel replays fixed pieces and records what it was asked
+type fakeLlamaModel struct {
+	pieces    []string
+	embedding []float32
+	system    string
+	prompt    string
+	maxTokens int
+	closed    bool
+}
+
+func (f *fakeLlamaModel) generate(system, prompt string, maxTokens int, onToken func(string) error) (Usage, error) {
+	f.system, f.prompt, f.maxTokens = system, prompt, maxTokens
+	for _, piece := range f.pieces {
+		if err := onToken(piece); err != nil {
+			return Usage{}, err
+		}
+	}
+	return Usage{InputTokens: 7, OutputTokens: len(f.pieces)}, nil
+}
+
+func (f *fakeLlamaModel) embed(_ string) ([]float32, error) {
+	return f.embedding, nil
+}
+
+func (f *fakeLlamaModel) close() {
+	f.closed = true
+}
+
+// withFakeLlama replaces the llama.cpp binding with fakes for the duration of the test
+func withFakeLlama(t *testing.T, generator, embedder *fakeLlamaModel) {
+	original := loadLlamaModel
+	t.Cleanup(func() { loadLlamaModel = original })
+	loadLlamaModel = func(_ string, _ LlamaCppConfig, embeddings bool) (llamaModel, error) {
+		if embeddings {
+			return embedder, nil
+		}
+		return generator, nil
+	}
+}
+
+func TestLlamaCppProvider_Generate(t *testing.T) {
+	// Arrange
+	generator := &fakeLlamaModel{pieces: []string{"{\"a\"", ": 1}"}}
+	withFakeLlama(t, generator, &fakeLlamaModel{})
+	provider, err := NewLlamaCppProvider(LlamaCppConfig{ModelPath: "/models/qwen2.5-1.5b.gguf"})
+	require.NoError(t, err, "NewLlamaCppProvider() error should be nil")
+
+	// Act
+	resp, err := provider.Generate(context.Background(), Request{System: "sys", Prompt: "classify", JSON: true})
+
+	// Assert
+	require.NoError(t, err, "Generate() error should be nil")
+	assert.Equal(t, `{"a": 1}`, resp.Text, "Generate() should join the generated pieces")
+	assert.Equal(t, Usage{InputTokens: 7, OutputTokens: 2}, resp.Usage, "Generate() should report token usage")
+	assert.Equal(t, "sys", generator.system, "Generate() should pass the system prompt")
+	assert.Contains(t, generator.prompt, "Respond with valid JSON only.", "Generate() should ask for JSON in the prompt")
+	assert.Equal(t, DefaultLlamaCppMaxTokens, generator.maxTokens, "Generate() should default the output limit")
+	assert.Equal(t, "qwen2.5-1.5b", provider.Model(), "Model() should be named after the GGUF file")
+}
+
+func TestLlamaCppProvider_Stream(t *testing.T) {
+	// Arrange
+	withFakeLlama(t, &fakeLlamaModel{pieces: []string{"Hel", "lo"}}, &fakeLlamaModel{})
+	provider, err := NewLlamaCppProvider(LlamaCppConfig{ModelPath: "model.gguf"})
+	require.NoError(t, err, "NewLlamaCppProvider() error should be nil")
+
+	// Act
+	tokens, errs := provider.Stream(context.Background(), Request{Prompt: "greet"})
+	var text string
+	for token := range tokens {
+		text += token
+	}
+
+	// Assert
+	require.NoError(t, <-errs, "Stream() should not report an error")
+	assert.Equal(t, "Hello", text, "Stream() should deliver every piece in order")
+}
+
+func TestLlamaCppProvider_EmbedAndClose(t *testing.T) {
+	// Arrange
+	generator := &fakeLlamaModel{}
+	embedder := &fakeLlamaModel{embedding: []float32{0.6, 0.8}}
+	withFakeLlama(t, generator, embedder)
+	provider, err := NewLlamaCppProvider(LlamaCppConfig{ModelPath: "model.gguf"})
+	require.NoError(t, err, "NewLlamaCppProvider() error should be nil")
+
+	// Act
+	embedding, err := provider.Embed(context.Background(), "text")
+	closeErr := provider.Close()
+
+	// Assert
+	require.NoError(t, err, "Embed() error should be nil")
+	assert.Equal(t, []float32{0.6, 0.8}, embedding, "Embed() should return the embedding model's vector")
+	require.NoError(t, closeErr, "Close() error should be nil")
+	assert.True(t, generator.closed && embedder.closed, "Close() should release both models")
+}
+
+func TestNewLlamaCppProvider_RequiresModelPath(t *testing.T) {
+	// Act
+	_, err := NewLlamaCppProvider(LlamaCppConfig{})
+
+	// Assert
+	assert.ErrorContains(t, err, "model path is required", "NewLlamaCppProvider() should require a model path")
+}
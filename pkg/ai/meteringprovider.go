@@ -0,0 +1,149 @@
+package ai
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// MeteringProvider records the provider, model, token counts, latency and
+// estimated cost of every call in a UsageStore
+type MeteringProvider struct {
+	provider Provider
+	store    UsageStore
+}
+
+// NewMeteringProvider wraps a provider so every call is recorded in the usage store
+func NewMeteringProvider(provider Provider, store UsageStore) Provider {
+	return &MeteringProvider{
+		provider: provider,
+		store:    store,
+	}
+}
+
+// Name returns the wrapped provider name
+func (m *MeteringProvider) Name() string {
+	return m.provider.Name()
+}
+
+// Model returns the wrapped provider model
+func (m *MeteringProvider) Model() string {
+	return m.provider.Model()
+}
+
+// Generate records the usage reported by the wrapped provider
+func (m *MeteringProvider) Generate(ctx context.Context, req Request) (Response, error) {
+	start := time.Now()
+	resp, err := m.provider.Generate(ctx, req)
+	m.record(ctx, UsageEvent{
+		Time:         start,
+		Task:         req.Task,
+		Operation:    OperationGenerate,
+		InputTokens:  resp.Usage.InputTokens,
+		OutputTokens: resp.Usage.OutputTokens,
+		Latency:      time.Since(start),
+		Failed:       err != nil,
+	})
+	return resp, err
+}
+
+// GenerateJSON records every generation attempt made while producing valid JSON
+func (m *MeteringProvider) GenerateJSON(ctx context.Context, req Request, out any) error {
+	return generateJSON(ctx, m, req, out)
+}
+
+// Stream records the call once the stream finishes. Streams carry no usage,
+// so token counts are estimated from the prompt and output length.
+func (m *MeteringProvider) Stream(ctx context.Context, req Request) (<-chan string, <-chan error) {
+	start := time.Now()
+	tokens, errs := m.provider.Stream(ctx, req)
+
+	tokenChan := make(chan string)
+	errChan := make(chan error, 1)
+	go func() {
+		defer close(tokenChan)
+		defer close(errChan)
+
+		var output int
+		for token := range tokens {
+			output += len(token)
+			select {
+			case tokenChan <- token:
+			case <-ctx.Done():
+				// Keep draining so the wrapped stream can finish and be recorded
+			}
+		}
+		err := <-errs
+
+		m.record(ctx, UsageEvent{
+			Time:         start,
+			Task:         req.Task,
+			Operation:    OperationStream,
+			InputTokens:  estimateTokens(len(req.System) + len(req.Prompt)),
+			OutputTokens: estimateTokens(output),
+			Latency:      time.Since(start),
+			Failed:       err != nil,
+		})
+		if err != nil {
+			errChan <- err
+		}
+	}()
+
+	return tokenChan, errChan
+}
+
+// Embed records the call with the input tokens estimated from the text length
+func (m *MeteringProvider) Embed(ctx context.Context, text string) ([]float32, error) {
+	start := time.Now()
+	embedding, err := m.provider.Embed(ctx, text)
+	m.record(ctx, UsageEvent{
+		Time:        start,
+		Task:        TaskEmbedding,
+		Operation:   OperationEmbed,
+		InputTokens: estimateTokens(len(text)),
+		Latency:     time.Since(start),
+		Failed:      err != nil,
+	})
+	return embedding, err
+}
+
+// record fills in the provider details and stores the event. Usage tracking
+// must never fail a call, so store errors are only logged.
+func (m *MeteringProvider) record(ctx context.Context, event UsageEvent) {
+	event.Provider = m.provider.Name()
+	event.Model = m.provider.Model()
+	if event.Operation == OperationEmbed {
+		event.Model = embeddingModel(m.provider)
+	}
+	if !isLocalProvider(event.Provider) {
+		event.Cost = EstimateCost(event.Model, event.InputTokens, event.OutputTokens)
+	}
+
+	// The call's context may already be cancelled; the record should still be written
+	if err := m.store.Record(context.WithoutCancel(ctx), event); err != nil {
+		slog.WarnContext(ctx, "Failed to record AI usage", "error", err)
+	}
+}
+
+// embeddingModel returns the embedding model of providers that expose one
+func embeddingModel(p Provider) string {
+	if e, ok := p.(embeddingModeler); ok {
+		return e.EmbeddingModel()
+	}
+	return p.Model()
+}
+
+// embeddingModeler is implemented by providers that embed with a separate model
+type embeddingModeler interface {
+	EmbeddingModel() string
+}
+
+// isLocalProvider reports whether the provider runs models on the user's own hardware
+func isLocalProvider(name string) bool {
+	return name == ProviderOllama || name == ProviderLlamaCpp
+}
+
+// estimateTokens approximates the token count of text of the given length
+func estimateTokens(chars int) int {
+	return (chars + 3) / 4
+}
@@ -0,0 +1,65 @@
+package ai_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/kazemisoroush/assistant/pkg/ai"
+	"github.com/kazemisoroush/assistant/pkg/ai/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestMeteringProvider_Generate_RecordsUsage(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	inner := mocks.NewMockProvider(ctrl)
+	inner.EXPECT().Name().Return(ai.ProviderOpenAI).AnyTimes()
+	inner.EXPECT().Model().Return("gpt-4o-mini").AnyTimes()
+	inner.EXPECT().Generate(gomock.Any(), gomock.Any()).Return(ai.Response{Text: "ok", Usage: ai.Usage{InputTokens: 1000, OutputTokens: 100}}, nil)
+	store := mocks.NewMockUsageStore(ctrl)
+	var recorded ai.UsageEvent
+	store.EXPECT().Record(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, event ai.UsageEvent) error {
+		recorded = event
+		return nil
+	})
+	provider := ai.NewMeteringProvider(inner, store)
+
+	// Act
+	resp, err := provider.Generate(context.Background(), ai.Request{Prompt: "classify", Task: ai.TaskClassification})
+
+	// Assert
+	require.NoError(t, err, "Generate() error should be nil")
+	assert.Equal(t, "ok", resp.Text, "Generate() should return the wrapped response")
+	assert.Equal(t, ai.ProviderOpenAI, recorded.Provider, "Generate() should record the provider")
+	assert.Equal(t, "gpt-4o-mini", recorded.Model, "Generate() should record the model")
+	assert.Equal(t, ai.TaskClassification, recorded.Task, "Generate() should record the task")
+	assert.Equal(t, 1000, recorded.InputTokens, "Generate() should record input tokens")
+	assert.Equal(t, 100, recorded.OutputTokens, "Generate() should record output tokens")
+	assert.Positive(t, recorded.Cost, "Generate() should estimate the cost of a hosted model")
+}
+
+func TestMeteringProvider_Embed_RecordsFailureAndIgnoresStoreErrors(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	inner := mocks.NewMockProvider(ctrl)
+	inner.EXPECT().Name().Return(ai.ProviderOllama).AnyTimes()
+	inner.EXPECT().Model().Return("llama3.2").AnyTimes()
+	inner.EXPECT().Embed(gomock.Any(), "text").Return(nil, errors.New("model not found"))
+	store := mocks.NewMockUsageStore(ctrl)
+	store.EXPECT().Record(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, event ai.UsageEvent) error {
+		assert.True(t, event.Failed, "Embed() should record the failure")
+		assert.Equal(t, ai.TaskEmbedding, event.Task, "Embed() should record the embedding task")
+		assert.Zero(t, event.Cost, "Embed() should not charge for a local provider")
+		return errors.New("disk full")
+	})
+	provider := ai.NewMeteringProvider(inner, store)
+
+	// Act
+	_, err := provider.Embed(context.Background(), "text")
+
+	// Assert
+	assert.EqualError(t, err, "model not found", "Embed() should return the provider error, not the store error")
+}
@@ -0,0 +1,210 @@
+package ai
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+)
+
+const (
+	// ProviderOllama is the name of the Ollama provider
+	ProviderOllama = "ollama"
+
+	// DefaultOllamaEmbeddingModel is used when no embedding model is configured
+	DefaultOllamaEmbeddingModel = "nomic-embed-text"
+)
+
+// OllamaProvider talks to a local or self-hosted Ollama server
+type OllamaProvider struct {
+	httpClient     *http.Client
+	url            string
+	model          string
+	embeddingModel string
+}
+
+// NewOllamaProvider creates a new Ollama provider
+func NewOllamaProvider(httpClient *http.Client, url, model, embeddingModel string) Provider {
+	if embeddingModel == "" {
+		embeddingModel = DefaultOllamaEmbeddingModel
+	}
+	return &OllamaProvider{
+		httpClient:     httpClient,
+		url:            url,
+		model:          model,
+		embeddingModel: embeddingModel,
+	}
+}
+
+// Name returns the provider name
+func (o *OllamaProvider) Name() string {
+	return ProviderOllama
+}
+
+// Model returns the generation model
+func (o *OllamaProvider) Model() string {
+	return o.model
+}
+
+// EmbeddingModel returns the embedding model
+func (o *OllamaProvider) EmbeddingModel() string {
+	return o.embeddingModel
+}
+
+// Generate returns the model completion for the given request
+func (o *OllamaProvider) Generate(ctx context.Context, req Request) (Response, error) {
+	var result struct {
+		Response        string `json:"response"`
+		PromptEvalCount int    `json:"prompt_eval_count"`
+		EvalCount       int    `json:"eval_count"`
+	}
+	if err := o.post(ctx, "/api/generate", o.generateBody(req, false), &result); err != nil {
+		return Response{}, err
+	}
+
+	return Response{
+		Text: result.Response,
+		Usage: Usage{
+			InputTokens:  result.PromptEvalCount,
+			OutputTokens: result.EvalCount,
+		},
+	}, nil
+}
+
+// Stream returns the completion incrementally as Ollama generates it
+func (o *OllamaProvider) Stream(ctx context.Context, req Request) (<-chan string, <-chan error) {
+	tokenChan := make(chan string)
+	errChan := make(chan error, 1)
+
+	go func() {
+		defer close(tokenChan)
+		defer close(errChan)
+
+		resp, err := o.do(ctx, "/api/generate", o.generateBody(req, true))
+		if err != nil {
+			errChan <- err
+			return
+		}
+		defer o.closeBody(resp)
+
+		// Ollama streams one JSON object per line
+		decoder := json.NewDecoder(resp.Body)
+		for {
+			var chunk struct {
+				Response string `json:"response"`
+				Done     bool   `json:"done"`
+			}
+			if err := decoder.Decode(&chunk); err != nil {
+				errChan <- fmt.Errorf("failed to decode Ollama stream: %w", err)
+				return
+			}
+
+			select {
+			case tokenChan <- chunk.Response:
+			case <-ctx.Done():
+				errChan <- ctx.Err()
+				return
+			}
+
+			if chunk.Done {
+				return
+			}
+		}
+	}()
+
+	return tokenChan, errChan
+}
+
+// GenerateJSON requests JSON output and decodes it into out
+func (o *OllamaProvider) GenerateJSON(ctx context.Context, req Request, out any) error {
+	return generateJSON(ctx, o, req, out)
+}
+
+// Embed generates a vector embedding for text
+func (o *OllamaProvider) Embed(ctx context.Context, text string) ([]float32, error) {
+	body := map[string]any{
+		"model": o.embeddingModel,
+		"input": text,
+	}
+
+	var result struct {
+		Embeddings [][]float32 `json:"embeddings"`
+	}
+	if err := o.post(ctx, "/api/embed", body, &result); err != nil {
+		return nil, err
+	}
+	if len(result.Embeddings) == 0 {
+		return nil, fmt.Errorf("ollama returned no embeddings")
+	}
+
+	return result.Embeddings[0], nil
+}
+
+func (o *OllamaProvider) generateBody(req Request, stream bool) map[string]any {
+	body := map[string]any{
+		"model":  o.model,
+		"prompt": req.Prompt,
+		"stream": stream,
+	}
+	if req.System != "" {
+		body["system"] = req.System
+	}
+	if req.JSON {
+		body["format"] = "json"
+	}
+	if len(req.Images) > 0 {
+		images := make([]string, len(req.Images))
+		for i, image := range req.Images {
+			images[i] = base64.StdEncoding.EncodeToString(image.Data)
+		}
+		body["images"] = images
+	}
+	return body
+}
+
+func (o *OllamaProvider) post(ctx context.Context, path string, body any, out any) error {
+	resp, err := o.do(ctx, path, body)
+	if err != nil {
+		return err
+	}
+	defer o.closeBody(resp)
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode Ollama response: %w", err)
+	}
+	return nil
+}
+
+// do sends the request and returns the response for the caller to read and close
+func (o *OllamaProvider) do(ctx context.Context, path string, body any) (*http.Response, error) {
+	jsonData, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request body: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, o.url+path, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := o.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call Ollama API (check if Ollama is running at %s): %w", o.url, err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		o.closeBody(resp)
+		return nil, &StatusError{Provider: ProviderOllama, StatusCode: resp.StatusCode}
+	}
+	return resp, nil
+}
+
+func (o *OllamaProvider) closeBody(resp *http.Response) {
+	if err := resp.Body.Close(); err != nil {
+		slog.Warn("Failed to close response body", "error", err)
+	}
+}
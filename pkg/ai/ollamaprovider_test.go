@@ -0,0 +1,58 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOllamaProvider_GenerateJSON(t *testing.T) {
+	// Arrange
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		assert.Equal(t, "json", body["format"], "request should ask for JSON output")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"response":          "```json\n{\"merchant\": \"Shell\"}\n```",
+			"prompt_eval_count": 12,
+			"eval_count":        5,
+		})
+	}))
+	defer server.Close()
+	provider := NewOllamaProvider(server.Client(), server.URL, "llama3", "")
+	var out struct {
+		Merchant string `json:"merchant"`
+	}
+
+	// Act
+	err := provider.GenerateJSON(context.Background(), Request{Prompt: "extract"}, &out)
+
+	// Assert
+	require.NoError(t, err, "GenerateJSON() error should be nil")
+	assert.Equal(t, "Shell", out.Merchant, "GenerateJSON() should decode the fenced JSON")
+}
+
+func TestOllamaProvider_Stream(t *testing.T) {
+	// Arrange
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"response":"Hel","done":false}` + "\n" + `{"response":"lo","done":true}` + "\n"))
+	}))
+	defer server.Close()
+	provider := NewOllamaProvider(server.Client(), server.URL, "llama3", "")
+
+	// Act
+	tokens, errs := provider.Stream(context.Background(), Request{Prompt: "greet"})
+	var text string
+	for token := range tokens {
+		text += token
+	}
+
+	// Assert
+	require.NoError(t, <-errs, "Stream() should not report an error")
+	assert.Equal(t, "Hello", text, "Stream() should deliver every token in order")
+}
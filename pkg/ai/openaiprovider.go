@@ -0,0 +1,251 @@
+package ai
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+)
+
+const (
+	// ProviderOpenAI is the name of the OpenAI-compatible provider
+	ProviderOpenAI = "openai"
+
+	// DefaultOpenAIEmbeddingModel is used when no embedding model is configured
+	DefaultOpenAIEmbeddingModel = "text-embedding-3-small"
+)
+
+// OpenAIProvider talks to any endpoint implementing the OpenAI chat completions and embeddings APIs
+type OpenAIProvider struct {
+	httpClient     *http.Client
+	url            string
+	apiKey         string
+	model          string
+	embeddingModel string
+}
+
+// NewOpenAIProvider creates a new OpenAI-compatible provider
+func NewOpenAIProvider(httpClient *http.Client, url, apiKey, model, embeddingModel string) Provider {
+	if embeddingModel == "" {
+		embeddingModel = DefaultOpenAIEmbeddingModel
+	}
+	return &OpenAIProvider{
+		httpClient:     httpClient,
+		url:            url,
+		apiKey:         apiKey,
+		model:          model,
+		embeddingModel: embeddingModel,
+	}
+}
+
+// Name returns the provider name
+func (o *OpenAIProvider) Name() string {
+	return ProviderOpenAI
+}
+
+// Model returns the generation model
+func (o *OpenAIProvider) Model() string {
+	return o.model
+}
+
+// EmbeddingModel returns the embedding model
+func (o *OpenAIProvider) EmbeddingModel() string {
+	return o.embeddingModel
+}
+
+// Generate returns the model completion for the given request
+func (o *OpenAIProvider) Generate(ctx context.Context, req Request) (Response, error) {
+	var result struct {
+		Choices []struct {
+			Message struct {
+				Content string `json:"content"`
+			} `json:"message"`
+		} `json:"choices"`
+		Usage struct {
+			PromptTokens     int `json:"prompt_tokens"`
+			CompletionTokens int `json:"completion_tokens"`
+		} `json:"usage"`
+	}
+	if err := o.post(ctx, "/chat/completions", o.chatBody(req, false), &result); err != nil {
+		return Response{}, err
+	}
+	if len(result.Choices) == 0 {
+		return Response{}, fmt.Errorf("openai returned no choices")
+	}
+
+	return Response{
+		Text: result.Choices[0].Message.Content,
+		Usage: Usage{
+			InputTokens:  result.Usage.PromptTokens,
+			OutputTokens: result.Usage.CompletionTokens,
+		},
+	}, nil
+}
+
+// Stream returns the completion incrementally from server-sent events
+func (o *OpenAIProvider) Stream(ctx context.Context, req Request) (<-chan string, <-chan error) {
+	tokenChan := make(chan string)
+	errChan := make(chan error, 1)
+
+	go func() {
+		defer close(tokenChan)
+		defer close(errChan)
+
+		resp, err := o.do(ctx, "/chat/completions", o.chatBody(req, true))
+		if err != nil {
+			errChan <- err
+			return
+		}
+		defer o.closeBody(resp)
+
+		scanner := bufio.NewScanner(resp.Body)
+		for scanner.Scan() {
+			data, ok := strings.CutPrefix(scanner.Text(), "data: ")
+			if !ok {
+				continue
+			}
+			if data == "[DONE]" {
+				return
+			}
+
+			var chunk struct {
+				Choices []struct {
+					Delta struct {
+						Content string `json:"content"`
+					} `json:"delta"`
+				} `json:"choices"`
+			}
+			if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+				errChan <- fmt.Errorf("failed to decode OpenAI stream: %w", err)
+				return
+			}
+			if len(chunk.Choices) == 0 {
+				continue
+			}
+
+			select {
+			case tokenChan <- chunk.Choices[0].Delta.Content:
+			case <-ctx.Done():
+				errChan <- ctx.Err()
+				return
+			}
+		}
+
+		if err := scanner.Err(); err != nil {
+			errChan <- fmt.Errorf("failed to read OpenAI stream: %w", err)
+		}
+	}()
+
+	return tokenChan, errChan
+}
+
+// GenerateJSON requests JSON output and decodes it into out
+func (o *OpenAIProvider) GenerateJSON(ctx context.Context, req Request, out any) error {
+	return generateJSON(ctx, o, req, out)
+}
+
+// Embed generates a vector embedding for text
+func (o *OpenAIProvider) Embed(ctx context.Context, text string) ([]float32, error) {
+	body := map[string]any{
+		"model": o.embeddingModel,
+		"input": text,
+	}
+
+	var result struct {
+		Data []struct {
+			Embedding []float32 `json:"embedding"`
+		} `json:"data"`
+	}
+	if err := o.post(ctx, "/embeddings", body, &result); err != nil {
+		return nil, err
+	}
+	if len(result.Data) == 0 {
+		return nil, fmt.Errorf("openai returned no embeddings")
+	}
+
+	return result.Data[0].Embedding, nil
+}
+
+func (o *OpenAIProvider) chatBody(req Request, stream bool) map[string]any {
+	messages := []map[string]any{}
+	if req.System != "" {
+		messages = append(messages, map[string]any{"role": "system", "content": req.System})
+	}
+	messages = append(messages, map[string]any{"role": "user", "content": userContent(req)})
+
+	body := map[string]any{
+		"model":    o.model,
+		"messages": messages,
+		"stream":   stream,
+	}
+	if req.JSON {
+		body["response_format"] = map[string]string{"type": "json_object"}
+	}
+	return body
+}
+
+// userContent returns the prompt as plain text, or as content parts when images are attached
+func userContent(req Request) any {
+	if len(req.Images) == 0 {
+		return req.Prompt
+	}
+
+	parts := make([]map[string]any, 0, len(req.Images)+1)
+	for _, image := range req.Images {
+		url := "data:" + image.MediaType + ";base64," + base64.StdEncoding.EncodeToString(image.Data)
+		parts = append(parts, map[string]any{"type": "image_url", "image_url": map[string]string{"url": url}})
+	}
+	return append(parts, map[string]any{"type": "text", "text": req.Prompt})
+}
+
+func (o *OpenAIProvider) post(ctx context.Context, path string, body any, out any) error {
+	resp, err := o.do(ctx, path, body)
+	if err != nil {
+		return err
+	}
+	defer o.closeBody(resp)
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode OpenAI response: %w", err)
+	}
+	return nil
+}
+
+// do sends the request and returns the response for the caller to read and close
+func (o *OpenAIProvider) do(ctx context.Context, path string, body any) (*http.Response, error) {
+	jsonData, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request body: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, o.url+path, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if o.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+o.apiKey)
+	}
+
+	resp, err := o.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call OpenAI-compatible API at %s: %w", o.url, err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		o.closeBody(resp)
+		return nil, &StatusError{Provider: ProviderOpenAI, StatusCode: resp.StatusCode}
+	}
+	return resp, nil
+}
+
+func (o *OpenAIProvider) closeBody(resp *http.Response) {
+	if err := resp.Body.Close(); err != nil {
+		slog.Warn("Failed to close response body", "error", err)
+	}
+}
@@ -0,0 +1,48 @@
+package ai
+
+import "strings"
+
+// modelPrice is the list price in USD per million tokens
+type modelPrice struct {
+	pattern string
+	input   float64
+	output  float64
+}
+
+// modelPrices is matched in order against the model ID, so more specific
+// patterns must come before the patterns they contain. Models without an
+// entry, such as local Ollama and llama.cpp models, are free.
+var modelPrices = []modelPrice{
+	// Anthropic, directly or through Bedrock
+	{pattern: "claude-3-5-haiku", input: 0.80, output: 4.00},
+	{pattern: "claude-3-5-sonnet", input: 3.00, output: 15.00},
+	{pattern: "claude-3-7-sonnet", input: 3.00, output: 15.00},
+	{pattern: "claude-3-haiku", input: 0.25, output: 1.25},
+	{pattern: "claude-3-sonnet", input: 3.00, output: 15.00},
+	{pattern: "claude-3-opus", input: 15.00, output: 75.00},
+	{pattern: "claude-haiku-4", input: 1.00, output: 5.00},
+	{pattern: "claude-sonnet-4", input: 3.00, output: 15.00},
+	{pattern: "claude-opus-4", input: 15.00, output: 75.00},
+
+	// Amazon models on Bedrock
+	{pattern: "titan-embed-text", input: 0.02},
+	{pattern: "nova-micro", input: 0.035, output: 0.14},
+	{pattern: "nova-lite", input: 0.06, output: 0.24},
+	{pattern: "nova-pro", input: 0.80, output: 3.20},
+
+	// OpenAI
+	{pattern: "gpt-4o-mini", input: 0.15, output: 0.60},
+	{pattern: "gpt-4o", input: 2.50, output: 10.00},
+	{pattern: "text-embedding-3-small", input: 0.02},
+	{pattern: "text-embedding-3-large", input: 0.13},
+}
+
+// EstimateCost returns the estimated cost in USD of a call to the model
+func EstimateCost(model string, inputTokens, outputTokens int) float64 {
+	for _, price := range modelPrices {
+		if strings.Contains(model, price.pattern) {
+			return (float64(inputTokens)*price.input + float64(outputTokens)*price.output) / 1_000_000
+		}
+	}
+	return 0
+}
@@ -0,0 +1,102 @@
+package ai
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// ProviderReplay is the name of the provider that replays recorded interactions
+const ProviderReplay = "replay"
+
+// ErrNoRecording is returned when no interaction was recorded for a request
+var ErrNoRecording = errors.New("no recorded interaction for request")
+
+// ReplayProvider answers requests with the responses recorded by a
+// TracingProvider, so extraction can be tested without a model
+type ReplayProvider struct {
+	responses map[string]Interaction
+}
+
+// NewReplayProvider loads the JSON lines written by a TracingProvider recorder.
+// When a prompt was recorded more than once, the last successful response wins.
+func NewReplayProvider(r io.Reader) (Provider, error) {
+	responses := make(map[string]Interaction)
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		var interaction Interaction
+		if err := json.Unmarshal(scanner.Bytes(), &interaction); err != nil {
+			return nil, fmt.Errorf("failed to decode recorded interaction: %w", err)
+		}
+		if interaction.Error == "" {
+			responses[interaction.PromptHash] = interaction
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read recorded interactions: %w", err)
+	}
+
+	return &ReplayProvider{responses: responses}, nil
+}
+
+// Name returns the provider name
+func (r *ReplayProvider) Name() string {
+	return ProviderReplay
+}
+
+// Model returns the provider model
+func (r *ReplayProvider) Model() string {
+	return ProviderReplay
+}
+
+// Generate returns the recorded response for the request
+func (r *ReplayProvider) Generate(_ context.Context, req Request) (Response, error) {
+	interaction, err := r.lookup(req)
+	if err != nil {
+		return Response{}, err
+	}
+	return Response{Text: interaction.Response}, nil
+}
+
+// GenerateJSON decodes the recorded response into out
+func (r *ReplayProvider) GenerateJSON(_ context.Context, req Request, out any) error {
+	interaction, err := r.lookup(req)
+	if err != nil {
+		return err
+	}
+	return decodeAndValidate(interaction.Response, out)
+}
+
+// Stream delivers the recorded response as a single token
+func (r *ReplayProvider) Stream(_ context.Context, req Request) (<-chan string, <-chan error) {
+	tokenChan := make(chan string, 1)
+	errChan := make(chan error, 1)
+	defer close(tokenChan)
+	defer close(errChan)
+
+	interaction, err := r.lookup(req)
+	if err != nil {
+		errChan <- err
+		return tokenChan, errChan
+	}
+	tokenChan <- interaction.Response
+	return tokenChan, errChan
+}
+
+// Embed is not supported because embeddings are not recorded
+func (r *ReplayProvider) Embed(_ context.Context, _ string) ([]float32, error) {
+	return nil, fmt.Errorf("%s provider does not support embeddings", ProviderReplay)
+}
+
+func (r *ReplayProvider) lookup(req Request) (Interaction, error) {
+	interaction, ok := r.responses[PromptHash(req)]
+	if !ok {
+		return Interaction{}, fmt.Errorf("%w: task %q", ErrNoRecording, req.Task)
+	}
+	return interaction, nil
+}
@@ -0,0 +1,137 @@
+package ai
+
+import (
+	"context"
+	"log/slog"
+	"math/rand/v2"
+	"time"
+)
+
+// RetryConfig represents the retry policy for transient provider failures
+type RetryConfig struct {
+	MaxAttempts int           // Total attempts including the first call
+	BaseDelay   time.Duration // Delay before the first retry; doubles on each attempt
+	MaxDelay    time.Duration // Upper bound for a single delay
+}
+
+// RetryProvider retries transient failures of the wrapped provider using
+// exponential backoff with full jitter. Retries stop early when the next
+// delay would run past the context deadline.
+type RetryProvider struct {
+	provider Provider
+	cfg      RetryConfig
+}
+
+// NewRetryProvider wraps a provider with retry logic
+func NewRetryProvider(provider Provider, cfg RetryConfig) Provider {
+	return &RetryProvider{
+		provider: provider,
+		cfg:      cfg,
+	}
+}
+
+// Name returns the wrapped provider name
+func (r *RetryProvider) Name() string {
+	return r.provider.Name()
+}
+
+// Model returns the wrapped provider model
+func (r *RetryProvider) Model() string {
+	return r.provider.Model()
+}
+
+// Generate returns the model completion, retrying transient failures
+func (r *RetryProvider) Generate(ctx context.Context, req Request) (Response, error) {
+	var resp Response
+	err := r.retry(ctx, "generate", IsTransient, func() error {
+		var err error
+		resp, err = r.provider.Generate(ctx, req)
+		return err
+	})
+	return resp, err
+}
+
+// GenerateJSON decodes JSON output, retrying transient failures
+func (r *RetryProvider) GenerateJSON(ctx context.Context, req Request, out any) error {
+	return r.retry(ctx, "generate_json", IsTransient, func() error {
+		return r.provider.GenerateJSON(ctx, req, out)
+	})
+}
+
+// Embed returns the embedding, retrying transient failures
+func (r *RetryProvider) Embed(ctx context.Context, text string) ([]float32, error) {
+	var embedding []float32
+	err := r.retry(ctx, "embed", IsTransient, func() error {
+		var err error
+		embedding, err = r.provider.Embed(ctx, text)
+		return err
+	})
+	return embedding, err
+}
+
+// Stream returns the completion incrementally, retrying failures that happen
+// before the first token; once tokens have been delivered the stream cannot be replayed
+func (r *RetryProvider) Stream(ctx context.Context, req Request) (<-chan string, <-chan error) {
+	tokenChan := make(chan string)
+	errChan := make(chan error, 1)
+
+	go func() {
+		defer close(tokenChan)
+		defer close(errChan)
+
+		started := false
+		retryable := func(err error) bool {
+			return !started && IsTransient(err)
+		}
+		err := r.retry(ctx, "stream", retryable, func() error {
+			var err error
+			started, err = forwardStream(ctx, r.provider, req, tokenChan)
+			return err
+		})
+		if err != nil {
+			errChan <- err
+		}
+	}()
+
+	return tokenChan, errChan
+}
+
+func (r *RetryProvider) retry(ctx context.Context, operation string, retryable func(error) bool, call func() error) error {
+	for attempt := 1; ; attempt++ {
+		err := call()
+		if err == nil || attempt >= r.cfg.MaxAttempts || !retryable(err) {
+			return err
+		}
+
+		delay := r.backoff(attempt)
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < delay {
+			return err
+		}
+
+		slog.WarnContext(ctx, "AI call failed, retrying",
+			"provider", r.provider.Name(),
+			"operation", operation,
+			"attempt", attempt,
+			"delay", delay,
+			"error", err,
+		)
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay):
+		}
+	}
+}
+
+// backoff returns a random delay in [0, min(MaxDelay, BaseDelay*2^(attempt-1))]
+func (r *RetryProvider) backoff(attempt int) time.Duration {
+	ceiling := r.cfg.BaseDelay << (attempt - 1)
+	if ceiling <= 0 || ceiling > r.cfg.MaxDelay {
+		ceiling = r.cfg.MaxDelay
+	}
+	if ceiling <= 0 {
+		return 0
+	}
+	return rand.N(ceiling + 1)
+}
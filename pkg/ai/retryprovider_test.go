@@ -0,0 +1,66 @@
+package ai_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/kazemisoroush/assistant/pkg/ai"
+	"github.com/kazemisoroush/assistant/pkg/ai/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestRetryProvider_Generate_RetriesTransientErrors(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	inner := mocks.NewMockProvider(ctrl)
+	inner.EXPECT().Name().Return("ollama").AnyTimes()
+	gomock.InOrder(
+		inner.EXPECT().Generate(gomock.Any(), gomock.Any()).Return(ai.Response{}, &ai.StatusError{Provider: "ollama", StatusCode: http.StatusServiceUnavailable}),
+		inner.EXPECT().Generate(gomock.Any(), gomock.Any()).Return(ai.Response{Text: "ok"}, nil),
+	)
+	provider := ai.NewRetryProvider(inner, ai.RetryConfig{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond})
+
+	// Act
+	resp, err := provider.Generate(context.Background(), ai.Request{Prompt: "hello"})
+
+	// Assert
+	require.NoError(t, err, "Generate() error should be nil")
+	assert.Equal(t, "ok", resp.Text, "Generate() should return the retried response")
+}
+
+func TestRetryProvider_Generate_DoesNotRetryPermanentErrors(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	inner := mocks.NewMockProvider(ctrl)
+	inner.EXPECT().Generate(gomock.Any(), gomock.Any()).Return(ai.Response{}, errors.New("invalid model")).Times(1)
+	provider := ai.NewRetryProvider(inner, ai.RetryConfig{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond})
+
+	// Act
+	_, err := provider.Generate(context.Background(), ai.Request{Prompt: "hello"})
+
+	// Assert
+	require.Error(t, err, "Generate() should return the permanent error")
+}
+
+func TestIsTransient(t *testing.T) {
+	// Arrange
+	cases := map[error]bool{
+		&ai.StatusError{StatusCode: http.StatusTooManyRequests}: true,
+		&ai.StatusError{StatusCode: http.StatusBadGateway}:      true,
+		&ai.StatusError{StatusCode: http.StatusBadRequest}:      false,
+		context.Canceled: false,
+	}
+
+	for err, expected := range cases {
+		// Act
+		actual := ai.IsTransient(err)
+
+		// Assert
+		assert.Equal(t, expected, actual, "IsTransient(%v)", err)
+	}
+}
@@ -0,0 +1,76 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// Route names the provider and model that handle a task
+type Route struct {
+	Provider string
+	Model    string
+}
+
+// ParseRoute parses a "provider:model" route. Only the first colon separates
+// the two, so model names such as qwen2.5:0.5b are kept whole. The model may
+// be omitted to use the provider's configured model.
+func ParseRoute(spec string) (Route, error) {
+	provider, model, _ := strings.Cut(spec, ":")
+	if provider == "" {
+		return Route{}, fmt.Errorf("invalid route %q: provider is required", spec)
+	}
+	return Route{Provider: provider, Model: model}, nil
+}
+
+// RoutingProvider sends each request to the provider routed for its task,
+// and requests without a route to the default provider
+type RoutingProvider struct {
+	defaultProvider Provider
+	routes          map[string]Provider
+}
+
+// NewRoutingProvider creates a provider that dispatches requests by task
+func NewRoutingProvider(defaultProvider Provider, routes map[string]Provider) Provider {
+	return &RoutingProvider{
+		defaultProvider: defaultProvider,
+		routes:          routes,
+	}
+}
+
+// Name returns the default provider name
+func (r *RoutingProvider) Name() string {
+	return r.defaultProvider.Name()
+}
+
+// Model returns the default provider model
+func (r *RoutingProvider) Model() string {
+	return r.defaultProvider.Model()
+}
+
+// Generate sends the request to the provider routed for its task
+func (r *RoutingProvider) Generate(ctx context.Context, req Request) (Response, error) {
+	return r.route(req.Task).Generate(ctx, req)
+}
+
+// GenerateJSON sends the request to the provider routed for its task
+func (r *RoutingProvider) GenerateJSON(ctx context.Context, req Request, out any) error {
+	return r.route(req.Task).GenerateJSON(ctx, req, out)
+}
+
+// Stream sends the request to the provider routed for its task
+func (r *RoutingProvider) Stream(ctx context.Context, req Request) (<-chan string, <-chan error) {
+	return r.route(req.Task).Stream(ctx, req)
+}
+
+// Embed uses the provider routed for the embedding task
+func (r *RoutingProvider) Embed(ctx context.Context, text string) ([]float32, error) {
+	return r.route(TaskEmbedding).Embed(ctx, text)
+}
+
+func (r *RoutingProvider) route(task string) Provider {
+	if p, ok := r.routes[task]; ok {
+		return p
+	}
+	return r.defaultProvider
+}
@@ -0,0 +1,79 @@
+package ai_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kazemisoroush/assistant/pkg/ai"
+	"github.com/kazemisoroush/assistant/pkg/ai/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestRoutingProvider_RoutesByTask(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defaultProvider := mocks.NewMockProvider(ctrl)
+	classifier := mocks.NewMockProvider(ctrl)
+	embedder := mocks.NewMockProvider(ctrl)
+	classifier.EXPECT().Generate(gomock.Any(), gomock.Any()).Return(ai.Response{Text: "receipt"}, nil)
+	defaultProvider.EXPECT().Generate(gomock.Any(), gomock.Any()).Return(ai.Response{Text: "answer"}, nil)
+	embedder.EXPECT().Embed(gomock.Any(), "text").Return([]float32{1}, nil)
+	provider := ai.NewRoutingProvider(defaultProvider, map[string]ai.Provider{
+		ai.TaskClassification: classifier,
+		ai.TaskEmbedding:      embedder,
+	})
+	ctx := context.Background()
+
+	// Act
+	classified, classifyErr := provider.Generate(ctx, ai.Request{Prompt: "classify", Task: ai.TaskClassification})
+	answered, answerErr := provider.Generate(ctx, ai.Request{Prompt: "answer", Task: ai.TaskAnswering})
+	embedding, embedErr := provider.Embed(ctx, "text")
+
+	// Assert
+	require.NoError(t, classifyErr, "Generate() error should be nil for a routed task")
+	require.NoError(t, answerErr, "Generate() error should be nil for an unrouted task")
+	require.NoError(t, embedErr, "Embed() error should be nil")
+	assert.Equal(t, "receipt", classified.Text, "Generate() should use the provider routed for the task")
+	assert.Equal(t, "answer", answered.Text, "Generate() should use the default provider for unrouted tasks")
+	assert.Equal(t, []float32{1}, embedding, "Embed() should use the provider routed for embeddings")
+}
+
+func TestParseRoute(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    string
+		want    ai.Route
+		wantErr bool
+	}{
+		{name: "provider and model", spec: "ollama:qwen2.5:0.5b", want: ai.Route{Provider: "ollama", Model: "qwen2.5:0.5b"}},
+		{name: "provider only", spec: "bedrock", want: ai.Route{Provider: "bedrock"}},
+		{name: "missing provider", spec: ":gpt-4o", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			got, err := ai.ParseRoute(tt.spec)
+
+			// Assert
+			if tt.wantErr {
+				assert.Error(t, err, "ParseRoute() should reject the route")
+				return
+			}
+			require.NoError(t, err, "ParseRoute() error should be nil")
+			assert.Equal(t, tt.want, got, "ParseRoute() should split on the first colon")
+		})
+	}
+}
+
+func TestNewProviderChain_RejectsUnknownTask(t *testing.T) {
+	// Act
+	_, err := ai.NewProviderChain([]string{ai.ProviderOllama}, ai.Config{
+		Routes: map[string]ai.Route{"summarize": {Provider: ai.ProviderOllama}},
+	})
+
+	// Assert
+	assert.ErrorContains(t, err, `unknown AI task "summarize"`, "NewProviderChain() should reject routes for unknown tasks")
+}
@@ -0,0 +1,213 @@
+package ai
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/kazemisoroush/assistant/pkg/redact"
+	"github.com/kazemisoroush/assistant/pkg/requestid"
+)
+
+// DefaultTraceMaxChars caps the logged prompt and response when no cap is configured
+const DefaultTraceMaxChars = 2000
+
+// Interaction is a traced prompt and response pair
+type Interaction struct {
+	Time       time.Time `json:"time"`
+	RequestID  string    `json:"request_id,omitempty"`
+	Provider   string    `json:"provider"`
+	Model      string    `json:"model"`
+	Task       string    `json:"task,omitempty"`
+	RecordType string    `json:"record_type,omitempty"`
+	Operation  string    `json:"operation"`
+	PromptHash string    `json:"prompt_hash"` // Hash of the unredacted prompt, used to replay the response
+	System     string    `json:"system,omitempty"`
+	Prompt     string    `json:"prompt"`
+	Response   string    `json:"response"`
+	LatencyMs  int64     `json:"latency_ms"`
+	Error      string    `json:"error,omitempty"`
+}
+
+// TraceConfig controls how LLM interactions are traced
+type TraceConfig struct {
+	// Redactor, when set, removes PII from traced prompts and responses
+	Redactor redact.Redactor
+
+	// MaxChars caps the logged prompt and response; 0 uses DefaultTraceMaxChars
+	MaxChars int
+
+	// Recorder, when set, receives every interaction as a JSON line for replay.
+	// Recorded responses are redacted but not capped.
+	Recorder io.Writer
+}
+
+// TracingProvider logs every prompt and response with the request ID and task,
+// and optionally records them for replay with a ReplayProvider
+type TracingProvider struct {
+	provider Provider
+	cfg      TraceConfig
+	mu       sync.Mutex // Serializes writes to the recorder
+}
+
+// NewTracingProvider wraps a provider so every generation is traced
+func NewTracingProvider(provider Provider, cfg TraceConfig) Provider {
+	if cfg.MaxChars <= 0 {
+		cfg.MaxChars = DefaultTraceMaxChars
+	}
+	return &TracingProvider{
+		provider: provider,
+		cfg:      cfg,
+	}
+}
+
+// Name returns the wrapped provider name
+func (t *TracingProvider) Name() string {
+	return t.provider.Name()
+}
+
+// Model returns the wrapped provider model
+func (t *TracingProvider) Model() string {
+	return t.provider.Model()
+}
+
+// Generate traces the request and the generated text
+func (t *TracingProvider) Generate(ctx context.Context, req Request) (Response, error) {
+	start := time.Now()
+	resp, err := t.provider.Generate(ctx, req)
+	t.trace(ctx, start, OperationGenerate, req, resp.Text, err)
+	return resp, err
+}
+
+// GenerateJSON traces the request and the decoded value re-encoded as JSON
+func (t *TracingProvider) GenerateJSON(ctx context.Context, req Request, out any) error {
+	start := time.Now()
+	err := t.provider.GenerateJSON(ctx, req, out)
+
+	var text string
+	if err == nil {
+		data, marshalErr := json.Marshal(out)
+		if marshalErr == nil {
+			text = string(data)
+		}
+	}
+	t.trace(ctx, start, OperationGenerate, req, text, err)
+	return err
+}
+
+// Stream traces the request and the streamed text once the stream finishes
+func (t *TracingProvider) Stream(ctx context.Context, req Request) (<-chan string, <-chan error) {
+	start := time.Now()
+	tokens, errs := t.provider.Stream(ctx, req)
+
+	tokenChan := make(chan string)
+	errChan := make(chan error, 1)
+	go func() {
+		defer close(tokenChan)
+		defer close(errChan)
+
+		var text strings.Builder
+		for token := range tokens {
+			text.WriteString(token)
+			select {
+			case tokenChan <- token:
+			case <-ctx.Done():
+				// Keep draining so the wrapped stream can finish and be traced
+			}
+		}
+		err := <-errs
+
+		t.trace(ctx, start, OperationStream, req, text.String(), err)
+		if err != nil {
+			errChan <- err
+		}
+	}()
+
+	return tokenChan, errChan
+}
+
+// Embed passes through to the wrapped provider untraced
+func (t *TracingProvider) Embed(ctx context.Context, text string) ([]float32, error) {
+	return t.provider.Embed(ctx, text)
+}
+
+// trace logs the interaction and records it when a recorder is configured
+func (t *TracingProvider) trace(ctx context.Context, start time.Time, operation string, req Request, response string, err error) {
+	interaction := Interaction{
+		Time:       start,
+		RequestID:  requestid.FromContext(ctx),
+		Provider:   t.provider.Name(),
+		Model:      t.provider.Model(),
+		Task:       req.Task,
+		RecordType: req.RecordType,
+		Operation:  operation,
+		PromptHash: PromptHash(req),
+		System:     t.redact(req.System),
+		Prompt:     t.redact(req.Prompt),
+		Response:   t.redact(response),
+		LatencyMs:  time.Since(start).Milliseconds(),
+	}
+	if err != nil {
+		interaction.Error = err.Error()
+	}
+
+	slog.InfoContext(ctx, "LLM interaction",
+		"provider", interaction.Provider,
+		"model", interaction.Model,
+		"task", interaction.Task,
+		"record_type", interaction.RecordType,
+		"operation", interaction.Operation,
+		"system", truncate(interaction.System, t.cfg.MaxChars),
+		"prompt", truncate(interaction.Prompt, t.cfg.MaxChars),
+		"response", truncate(interaction.Response, t.cfg.MaxChars),
+		"latency_ms", interaction.LatencyMs,
+		"error", interaction.Error,
+	)
+
+	if t.cfg.Recorder == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if err := json.NewEncoder(t.cfg.Recorder).Encode(interaction); err != nil {
+		slog.WarnContext(ctx, "Failed to record LLM interaction", "error", err)
+	}
+}
+
+func (t *TracingProvider) redact(text string) string {
+	if t.cfg.Redactor == nil {
+		return text
+	}
+	return t.cfg.Redactor.Redact(text)
+}
+
+// PromptHash identifies a request by its system prompt, prompt and images
+func PromptHash(req Request) string {
+	h := sha256.New()
+	h.Write([]byte(req.System))
+	h.Write([]byte{0})
+	h.Write([]byte(req.Prompt))
+	for _, image := range req.Images {
+		h.Write([]byte{0})
+		h.Write(image.Data)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// truncate caps text at maxChars bytes without splitting a character, marking that it was cut
+func truncate(text string, maxChars int) string {
+	if len(text) <= maxChars {
+		return text
+	}
+	for maxChars > 0 && !utf8.RuneStart(text[maxChars]) {
+		maxChars--
+	}
+	return text[:maxChars] + "...[truncated]"
+}
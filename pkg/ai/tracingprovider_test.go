@@ -0,0 +1,69 @@
+package ai_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/kazemisoroush/assistant/pkg/ai"
+	"github.com/kazemisoroush/assistant/pkg/ai/mocks"
+	"github.com/kazemisoroush/assistant/pkg/redact"
+	"github.com/kazemisoroush/assistant/pkg/requestid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestTracingProvider_Generate_RecordsRedactedInteraction(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	inner := mocks.NewMockProvider(ctrl)
+	inner.EXPECT().Name().Return(ai.ProviderOllama).AnyTimes()
+	inner.EXPECT().Model().Return("llama3.2").AnyTimes()
+	inner.EXPECT().Generate(gomock.Any(), gomock.Any()).Return(ai.Response{Text: "receipt"}, nil)
+	var recorded bytes.Buffer
+	provider := ai.NewTracingProvider(inner, ai.TraceConfig{Redactor: redact.NewPatternRedactor(), Recorder: &recorded})
+	ctx := requestid.WithID(context.Background(), "req-1")
+	req := ai.Request{Prompt: "Invoice sent to jane@example.com", Task: ai.TaskClassification}
+
+	// Act
+	_, err := provider.Generate(ctx, req)
+
+	// Assert
+	require.NoError(t, err, "Generate() error should be nil")
+	var interaction ai.Interaction
+	require.NoError(t, json.Unmarshal(recorded.Bytes(), &interaction), "Generate() should record the interaction as JSON")
+	assert.Equal(t, "req-1", interaction.RequestID, "Generate() should tag the interaction with the request ID")
+	assert.Equal(t, ai.TaskClassification, interaction.Task, "Generate() should tag the interaction with the task")
+	assert.Equal(t, "Invoice sent to [EMAIL]", interaction.Prompt, "Generate() should redact the recorded prompt")
+	assert.Equal(t, "receipt", interaction.Response, "Generate() should record the response")
+	assert.Equal(t, ai.PromptHash(req), interaction.PromptHash, "Generate() should record the hash of the unredacted prompt")
+}
+
+func TestReplayProvider_ReplaysRecordedResponses(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	inner := mocks.NewMockProvider(ctrl)
+	inner.EXPECT().Name().Return(ai.ProviderOllama).AnyTimes()
+	inner.EXPECT().Model().Return("llama3.2").AnyTimes()
+	inner.EXPECT().GenerateJSON(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, _ ai.Request, out any) error {
+		return json.Unmarshal([]byte(`{"type":"receipt"}`), out)
+	})
+	var recorded bytes.Buffer
+	req := ai.Request{Prompt: "classify", Task: ai.TaskClassification}
+	var original map[string]string
+	require.NoError(t, ai.NewTracingProvider(inner, ai.TraceConfig{Recorder: &recorded}).GenerateJSON(context.Background(), req, &original), "GenerateJSON() error should be nil")
+	replay, err := ai.NewReplayProvider(&recorded)
+	require.NoError(t, err, "NewReplayProvider() error should be nil")
+
+	// Act
+	var replayed map[string]string
+	replayErr := replay.GenerateJSON(context.Background(), req, &replayed)
+	_, missingErr := replay.Generate(context.Background(), ai.Request{Prompt: "unknown"})
+
+	// Assert
+	require.NoError(t, replayErr, "GenerateJSON() error should be nil for a recorded request")
+	assert.Equal(t, original, replayed, "GenerateJSON() should replay the recorded response")
+	assert.ErrorIs(t, missingErr, ai.ErrNoRecording, "Generate() should fail for requests that were not recorded")
+}
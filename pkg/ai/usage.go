@@ -0,0 +1,160 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/kazemisoroush/assistant/pkg/sqlite"
+)
+
+// Operation names recorded with each usage event
+const (
+	OperationGenerate = "generate"
+	OperationStream   = "stream"
+	OperationEmbed    = "embed"
+)
+
+// UsageEvent represents a single metered LLM or embedding call
+type UsageEvent struct {
+	Time         time.Time
+	Provider     string
+	Model        string
+	Task         string
+	Operation    string
+	InputTokens  int
+	OutputTokens int
+	Latency      time.Duration
+	Cost         float64 // Estimated cost in USD
+	Failed       bool
+}
+
+// UsageTotals aggregates usage events sharing a key (a day, task or provider)
+type UsageTotals struct {
+	Key          string  `json:"key"`
+	Calls        int     `json:"calls"`
+	Failures     int     `json:"failures"`
+	InputTokens  int     `json:"input_tokens"`
+	OutputTokens int     `json:"output_tokens"`
+	AvgLatencyMs float64 `json:"avg_latency_ms"`
+	Cost         float64 `json:"cost_usd"`
+}
+
+// UsageReport summarizes usage since a point in time
+type UsageReport struct {
+	Since      time.Time     `json:"since"`
+	Total      UsageTotals   `json:"total"`
+	ByDay      []UsageTotals `json:"by_day"`
+	ByTask     []UsageTotals `json:"by_task"`
+	ByProvider []UsageTotals `json:"by_provider"`
+}
+
+// UsageStore persists usage events and summarizes them
+//
+//go:generate mockgen -destination=./mocks/mock_usagestore.go -mock_names=UsageStore=MockUsageStore -package=mocks . UsageStore
+type UsageStore interface {
+	// Record stores a usage event
+	Record(ctx context.Context, event UsageEvent) error
+
+	// Report summarizes the events recorded since the given time
+	Report(ctx context.Context, since time.Time) (UsageReport, error)
+}
+
+// SQLiteUsageStore is a UsageStore backed by SQLite
+type SQLiteUsageStore struct {
+	db *sqlite.DB
+}
+
+// NewSQLiteUsageStore creates a new SQLite usage store in the given database
+func NewSQLiteUsageStore(db *sqlite.DB) (*SQLiteUsageStore, error) {
+	schema := `
+    CREATE TABLE IF NOT EXISTS ai_usage (
+        id INTEGER PRIMARY KEY AUTOINCREMENT,
+        created_at DATETIME NOT NULL,
+        day TEXT NOT NULL,
+        provider TEXT NOT NULL,
+        model TEXT NOT NULL,
+        task TEXT NOT NULL,
+        operation TEXT NOT NULL,
+        input_tokens INTEGER NOT NULL,
+        output_tokens INTEGER NOT NULL,
+        latency_ms INTEGER NOT NULL,
+        cost REAL NOT NULL,
+        failed BOOLEAN NOT NULL
+    );
+
+    CREATE INDEX IF NOT EXISTS idx_ai_usage_created_at ON ai_usage(created_at);
+    `
+	if _, err := db.Exec(schema); err != nil {
+		return nil, fmt.Errorf("failed to initialize usage schema: %w", err)
+	}
+
+	return &SQLiteUsageStore{db: db}, nil
+}
+
+// Record stores a usage event
+func (s *SQLiteUsageStore) Record(ctx context.Context, event UsageEvent) error {
+	if _, err := s.db.ExecContext(ctx,
+		`INSERT INTO ai_usage (created_at, day, provider, model, task, operation, input_tokens, output_tokens, latency_ms, cost, failed)
+         VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		event.Time, event.Time.Format(time.DateOnly), event.Provider, event.Model, event.Task, event.Operation,
+		event.InputTokens, event.OutputTokens, event.Latency.Milliseconds(), event.Cost, event.Failed,
+	); err != nil {
+		return fmt.Errorf("failed to record usage: %w", err)
+	}
+	return nil
+}
+
+// Report summarizes the events recorded since the given time
+func (s *SQLiteUsageStore) Report(ctx context.Context, since time.Time) (UsageReport, error) {
+	report := UsageReport{Since: since}
+
+	total, err := s.totals(ctx, "'total'", since)
+	if err != nil {
+		return UsageReport{}, err
+	}
+	if len(total) > 0 {
+		report.Total = total[0]
+	}
+
+	if report.ByDay, err = s.totals(ctx, "day", since); err != nil {
+		return UsageReport{}, err
+	}
+	if report.ByTask, err = s.totals(ctx, "task", since); err != nil {
+		return UsageReport{}, err
+	}
+	if report.ByProvider, err = s.totals(ctx, "provider", since); err != nil {
+		return UsageReport{}, err
+	}
+	return report, nil
+}
+
+// totals groups events by the given column expression, which must be a trusted constant
+func (s *SQLiteUsageStore) totals(ctx context.Context, groupBy string, since time.Time) ([]UsageTotals, error) {
+	query := fmt.Sprintf(`
+        SELECT %[1]s, COUNT(*), COALESCE(SUM(failed), 0), COALESCE(SUM(input_tokens), 0), COALESCE(SUM(output_tokens), 0),
+               COALESCE(AVG(latency_ms), 0), COALESCE(SUM(cost), 0)
+        FROM ai_usage
+        WHERE created_at >= ?
+        GROUP BY %[1]s
+        ORDER BY %[1]s`, groupBy)
+
+	rows, err := s.db.QueryContext(ctx, query, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query usage: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var totals []UsageTotals
+	for rows.Next() {
+		var t UsageTotals
+		if err := rows.Scan(&t.Key, &t.Calls, &t.Failures, &t.InputTokens, &t.OutputTokens, &t.AvgLatencyMs, &t.Cost); err != nil {
+			return nil, fmt.Errorf("failed to scan usage: %w", err)
+		}
+		totals = append(totals, t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read usage: %w", err)
+	}
+	return totals, nil
+}
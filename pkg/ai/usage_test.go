@@ -0,0 +1,64 @@
+package ai
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSQLiteUsageStore_Report(t *testing.T) {
+	// Arrange
+	store, err := NewSQLiteUsageStore(openDB(t, filepath.Join(t.TempDir(), "usage.db")))
+	require.NoError(t, err, "NewSQLiteUsageStore() error should be nil")
+	ctx := context.Background()
+	now := time.Now()
+	events := []UsageEvent{
+		{Time: now.AddDate(0, 0, -40), Provider: ProviderBedrock, Task: TaskClassification, InputTokens: 1000, Cost: 1},
+		{Time: now.AddDate(0, 0, -1), Provider: ProviderBedrock, Task: TaskClassification, InputTokens: 100, OutputTokens: 10, Latency: 200 * time.Millisecond, Cost: 0.5},
+		{Time: now, Provider: ProviderOllama, Task: TaskEmbedding, InputTokens: 50, Latency: 100 * time.Millisecond, Failed: true},
+	}
+	for _, event := range events {
+		require.NoError(t, store.Record(ctx, event), "Record() error should be nil")
+	}
+
+	// Act
+	report, err := store.Report(ctx, now.AddDate(0, 0, -30))
+
+	// Assert
+	require.NoError(t, err, "Report() error should be nil")
+	assert.Equal(t, 2, report.Total.Calls, "Report() should only count events since the given time")
+	assert.Equal(t, 1, report.Total.Failures, "Report() should count failed calls")
+	assert.Equal(t, 150, report.Total.InputTokens, "Report() should sum input tokens")
+	assert.InDelta(t, 150, report.Total.AvgLatencyMs, 0.001, "Report() should average latency")
+	assert.InDelta(t, 0.5, report.Total.Cost, 0.001, "Report() should sum the estimated cost")
+	assert.Len(t, report.ByDay, 2, "Report() should group by day")
+	require.Len(t, report.ByTask, 2, "Report() should group by task")
+	assert.Equal(t, TaskClassification, report.ByTask[0].Key, "Report() should key task totals by task")
+	assert.Len(t, report.ByProvider, 2, "Report() should group by provider")
+}
+
+func TestEstimateCost(t *testing.T) {
+	tests := []struct {
+		name  string
+		model string
+		want  float64
+	}{
+		{name: "bedrock model ID", model: "anthropic.claude-3-5-haiku-20241022-v1:0", want: 0.80 + 4.00},
+		{name: "more specific pattern first", model: "gpt-4o-mini", want: 0.15 + 0.60},
+		{name: "unknown model is free", model: "llama3.2", want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			got := EstimateCost(tt.model, 1_000_000, 1_000_000)
+
+			// Assert
+			assert.InDelta(t, tt.want, got, 0.0001, "EstimateCost() should price per million tokens")
+		})
+	}
+}
@@ -0,0 +1,73 @@
+// Package analytics aggregates spending extracted from receipt records.
+package analytics
+
+import (
+	"context"
+	"time"
+)
+
+// SpendFilter narrows the receipts included in a spend report. Zero values match everything.
+type SpendFilter struct {
+	Year     int    `json:"year,omitempty"`
+	Category string `json:"category,omitempty"`
+	Vendor   string `json:"vendor,omitempty"`
+	Person   string `json:"person,omitempty"` // Household member the receipts are attributed to
+}
+
+// Bucket represents the spend of one month, category or vendor in one currency
+type Bucket struct {
+	Key      string  `json:"key"`
+	Currency string  `json:"currency"`
+	Total    float64 `json:"total"`
+	Count    int     `json:"count"`
+}
+
+// SpendReport represents aggregated spend. Months is a time series keyed
+// YYYY-MM, zero-filled across the filtered year so it can be charted directly.
+type SpendReport struct {
+	Filter     SpendFilter `json:"filter"`
+	Totals     []Bucket    `json:"totals"` // Keyed "total", one per currency
+	Months     []Bucket    `json:"months"`
+	Categories []Bucket    `json:"categories"`
+	Vendors    []Bucket    `json:"vendors"`
+}
+
+// Analyzer reports on spending
+//
+//go:generate mockgen -destination=./mocks/mock_analyzer.go -mock_names=Analyzer=MockAnalyzer -package=mocks . Analyzer
+type Analyzer interface {
+	// Spend aggregates receipt totals by month, category and vendor
+	Spend(ctx context.Context, filter SpendFilter) (SpendReport, error)
+}
+
+// Subscription represents a recurring charge from one vendor
+type Subscription struct {
+	Vendor         string    `json:"vendor"`
+	Currency       string    `json:"currency"`
+	Period         string    `json:"period"` // weekly, monthly, quarterly or yearly
+	Amount         float64   `json:"amount"` // Latest charge
+	PreviousAmount float64   `json:"previous_amount"`
+	MonthlyCost    float64   `json:"monthly_cost"`
+	Charges        int       `json:"charges"`
+	FirstCharge    time.Time `json:"first_charge"`
+	LastCharge     time.Time `json:"last_charge"`
+	NextCharge     time.Time `json:"next_charge"`
+	Active         bool      `json:"active"`    // Charged within the last period
+	New            bool      `json:"new"`       // First charged recently
+	Increased      bool      `json:"increased"` // Latest charge is higher than the previous one
+	RecordIDs      []string  `json:"record_ids"`
+}
+
+// SubscriptionReport represents the detected subscriptions and what the active ones cost per month
+type SubscriptionReport struct {
+	Subscriptions []Subscription `json:"subscriptions"`
+	MonthlyTotals []Bucket       `json:"monthly_totals"` // One per currency, active subscriptions only
+}
+
+// SubscriptionDetector finds recurring bills and subscriptions
+//
+//go:generate mockgen -destination=./mocks/mock_subscriptiondetector.go -mock_names=SubscriptionDetector=MockSubscriptionDetector -package=mocks . SubscriptionDetector
+type SubscriptionDetector interface {
+	// Subscriptions detects recurring charges as of now
+	Subscriptions(ctx context.Context, now time.Time) (SubscriptionReport, error)
+}
@@ -0,0 +1,165 @@
+package analytics
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/kazemisoroush/assistant/pkg/records"
+	"github.com/kazemisoroush/assistant/pkg/records/storage"
+)
+
+// uncategorized is the category of receipts the model could not categorize
+const uncategorized = "uncategorized"
+
+// ReceiptAnalyzer aggregates the metadata extracted from receipt records
+type ReceiptAnalyzer struct {
+	storage storage.Storage
+}
+
+// NewReceiptAnalyzer creates a new receipt analyzer
+func NewReceiptAnalyzer(storage storage.Storage) Analyzer {
+	return &ReceiptAnalyzer{
+		storage: storage,
+	}
+}
+
+// Spend implements Analyzer. Receipts without usable metadata are skipped.
+func (a *ReceiptAnalyzer) Spend(ctx context.Context, filter SpendFilter) (SpendReport, error) {
+	totals, months, categories, vendors := aggregate{}, aggregate{}, aggregate{}, aggregate{}
+
+	// Receipts are aggregated as they are read, so only the totals are kept
+	err := a.storage.Each(ctx, records.RecordTypeReceipt, func(rec records.Record) error {
+		receipt, date, ok := parseReceipt(rec)
+		if !ok {
+			return nil
+		}
+		category := strings.ToLower(receipt.Category)
+		if category == "" {
+			category = uncategorized
+		}
+		if !matches(filter, date, category, receipt.Merchant, rec.Person()) {
+			return nil
+		}
+
+		currency := strings.ToUpper(receipt.Currency)
+		totals.add("total", currency, receipt.Total)
+		months.add(date.Format("2006-01"), currency, receipt.Total)
+		categories.add(category, currency, receipt.Total)
+		vendors.add(receipt.Merchant, currency, receipt.Total)
+		return nil
+	})
+	if err != nil {
+		return SpendReport{}, fmt.Errorf("failed to list receipts: %w", err)
+	}
+
+	if filter.Year != 0 {
+		months.fillYear(filter.Year, totals.currencies())
+	}
+
+	return SpendReport{
+		Filter:     filter,
+		Totals:     totals.buckets(byKey),
+		Months:     months.buckets(byKey),
+		Categories: categories.buckets(byTotal),
+		Vendors:    vendors.buckets(byTotal),
+	}, nil
+}
+
+// parseReceipt decodes the receipt metadata, reporting whether it is complete enough to aggregate
+func parseReceipt(rec records.Record) (records.ReceiptMetadata, time.Time, bool) {
+	var receipt records.ReceiptMetadata
+	if err := rec.DecodeMetadata(&receipt); err != nil {
+		slog.Warn("Skipping receipt with malformed metadata", "record_id", rec.ID, "error", err)
+		return receipt, time.Time{}, false
+	}
+	date, err := time.Parse(time.DateOnly, receipt.Date)
+	if err != nil || receipt.Currency == "" {
+		return receipt, time.Time{}, false
+	}
+	return receipt, date, true
+}
+
+// matches reports whether a receipt passes the filter
+func matches(filter SpendFilter, date time.Time, category, vendor, person string) bool {
+	if filter.Year != 0 && date.Year() != filter.Year {
+		return false
+	}
+	if filter.Category != "" && !strings.EqualFold(category, filter.Category) {
+		return false
+	}
+	if filter.Vendor != "" && !strings.Contains(strings.ToLower(vendor), strings.ToLower(filter.Vendor)) {
+		return false
+	}
+	if filter.Person != "" && !strings.EqualFold(person, filter.Person) {
+		return false
+	}
+	return true
+}
+
+// aggregate sums amounts per key and currency
+type aggregate map[[2]string]*Bucket
+
+// add counts a receipt amount
+func (a aggregate) add(key, currency string, amount float64) {
+	bucket := a.bucket(key, currency)
+	bucket.Total += amount
+	bucket.Count++
+}
+
+// bucket returns the bucket for the key and currency, creating an empty one if needed
+func (a aggregate) bucket(key, currency string) *Bucket {
+	id := [2]string{key, currency}
+	if _, ok := a[id]; !ok {
+		a[id] = &Bucket{Key: key, Currency: currency}
+	}
+	return a[id]
+}
+
+// fillYear ensures a bucket exists for every month of the year in each currency
+func (a aggregate) fillYear(year int, currencies []string) {
+	for _, currency := range currencies {
+		for month := time.January; month <= time.December; month++ {
+			a.bucket(fmt.Sprintf("%04d-%02d", year, month), currency)
+		}
+	}
+}
+
+// currencies returns the distinct currencies, sorted
+func (a aggregate) currencies() []string {
+	var currencies []string
+	for id := range a {
+		currencies = append(currencies, id[1])
+	}
+	sort.Strings(currencies)
+	return currencies
+}
+
+// byKey orders buckets chronologically for month keys
+func byKey(x, y Bucket) bool {
+	if x.Key != y.Key {
+		return x.Key < y.Key
+	}
+	return x.Currency < y.Currency
+}
+
+// byTotal orders buckets by descending spend
+func byTotal(x, y Bucket) bool {
+	if x.Total != y.Total {
+		return x.Total > y.Total
+	}
+	return byKey(x, y)
+}
+
+// buckets returns the buckets in the given order
+func (a aggregate) buckets(less func(x, y Bucket) bool) []Bucket {
+	buckets := make([]Bucket, 0, len(a))
+	for _, bucket := range a {
+		buckets = append(buckets, *bucket)
+	}
+	sort.Slice(buckets, func(i, j int) bool { return less(buckets[i], buckets[j]) })
+	return buckets
+}
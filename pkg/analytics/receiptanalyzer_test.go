@@ -0,0 +1,89 @@
+package analytics_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kazemisoroush/assistant/pkg/analytics"
+	"github.com/kazemisoroush/assistant/pkg/records"
+	"github.com/kazemisoroush/assistant/pkg/records/storage/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func receipt(id, merchant, date, category string, total float64) records.Record {
+	return records.Record{
+		ID:   id,
+		Type: records.RecordTypeReceipt,
+		Metadata: map[string]interface{}{
+			"merchant": merchant,
+			"date":     date,
+			"total":    total,
+			"currency": "EUR",
+			"category": category,
+		},
+	}
+}
+
+// eachOf returns an Each implementation passing recs to the callback
+func eachOf(recs []records.Record) func(context.Context, records.RecordType, func(records.Record) error) error {
+	return func(_ context.Context, _ records.RecordType, fn func(records.Record) error) error {
+		for _, rec := range recs {
+			if err := fn(rec); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+func TestReceiptAnalyzer_Spend(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	storage := mocks.NewMockStorage(ctrl)
+	storage.EXPECT().Each(gomock.Any(), records.RecordTypeReceipt, gomock.Any()).DoAndReturn(eachOf([]records.Record{
+		receipt("r1", "Rewe", "2024-01-05", "groceries", 40),
+		receipt("r2", "Rewe", "2024-01-20", "Groceries", 10),
+		receipt("r3", "Aldi", "2024-03-02", "groceries", 25),
+		receipt("r4", "Cafe Luna", "2024-03-03", "dining", 12),
+		receipt("r5", "Rewe", "2023-12-30", "groceries", 99),
+		{ID: "r6", Type: records.RecordTypeReceipt, Metadata: map[string]interface{}{"needs_review": true}},
+	}))
+	analyzer := analytics.NewReceiptAnalyzer(storage)
+
+	// Act
+	report, err := analyzer.Spend(context.Background(), analytics.SpendFilter{Year: 2024, Category: "groceries"})
+
+	// Assert
+	require.NoError(t, err, "Spend() error should be nil")
+	require.Len(t, report.Totals, 1, "Spend() should total per currency")
+	assert.Equal(t, 75.0, report.Totals[0].Total, "Spend() should only total receipts matching the filter")
+	assert.Equal(t, 3, report.Totals[0].Count, "Spend() should count matching receipts")
+	require.Len(t, report.Months, 12, "Spend() should zero-fill every month of the year")
+	assert.Equal(t, analytics.Bucket{Key: "2024-01", Currency: "EUR", Total: 50, Count: 2}, report.Months[0], "Spend() should aggregate by month")
+	assert.Equal(t, 0.0, report.Months[1].Total, "Spend() should report months without receipts as zero")
+	require.Len(t, report.Vendors, 2, "Spend() should aggregate by vendor")
+	assert.Equal(t, "Rewe", report.Vendors[0].Key, "Spend() should order vendors by spend")
+}
+
+func TestReceiptAnalyzer_Spend_Person(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	storage := mocks.NewMockStorage(ctrl)
+	school := receipt("r1", "School Shop", "2024-09-01", "education", 60)
+	school.Metadata[records.MetaPerson] = "Lena Schmidt"
+	storage.EXPECT().Each(gomock.Any(), records.RecordTypeReceipt, gomock.Any()).DoAndReturn(eachOf([]records.Record{
+		school,
+		receipt("r2", "Rewe", "2024-09-02", "groceries", 40),
+	}))
+	analyzer := analytics.NewReceiptAnalyzer(storage)
+
+	// Act
+	report, err := analyzer.Spend(context.Background(), analytics.SpendFilter{Person: "lena schmidt"})
+
+	// Assert
+	require.NoError(t, err, "Spend() error should be nil")
+	require.Len(t, report.Totals, 1, "Spend() should total per currency")
+	assert.Equal(t, 60.0, report.Totals[0].Total, "Spend() should only total receipts attributed to the person")
+}
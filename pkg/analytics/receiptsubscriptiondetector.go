@@ -0,0 +1,179 @@
+package analytics
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/kazemisoroush/assistant/pkg/records"
+	"github.com/kazemisoroush/assistant/pkg/records/storage"
+)
+
+const (
+	// minCharges is the number of charges needed before a vendor counts as recurring
+	minCharges = 3
+
+	// amountTolerance is how far a charge may deviate from the typical amount and still belong to the subscription
+	amountTolerance = 0.25
+
+	// newWithin is how recently the first charge must be for a subscription to be flagged as new
+	newWithin = 90 * 24 * time.Hour
+
+	// daysPerMonth converts per-period amounts into monthly costs
+	daysPerMonth = 365.25 / 12
+)
+
+// period describes a billing period and the intervals in days accepted as it
+type period struct {
+	name             string
+	days             int
+	minDays, maxDays int
+}
+
+var periods = []period{
+	{name: "weekly", days: 7, minDays: 6, maxDays: 8},
+	{name: "monthly", days: 30, minDays: 26, maxDays: 35},
+	{name: "quarterly", days: 91, minDays: 84, maxDays: 98},
+	{name: "yearly", days: 365, minDays: 350, maxDays: 380},
+}
+
+// charge is one receipt of a vendor
+type charge struct {
+	recordID string
+	date     time.Time
+	amount   float64
+}
+
+// ReceiptSubscriptionDetector clusters receipts by vendor and currency and
+// reports the clusters charged at a regular period with a stable amount
+type ReceiptSubscriptionDetector struct {
+	storage storage.Storage
+}
+
+// NewReceiptSubscriptionDetector creates a new subscription detector
+func NewReceiptSubscriptionDetector(storage storage.Storage) SubscriptionDetector {
+	return &ReceiptSubscriptionDetector{
+		storage: storage,
+	}
+}
+
+// Subscriptions implements SubscriptionDetector
+func (d *ReceiptSubscriptionDetector) Subscriptions(ctx context.Context, now time.Time) (SubscriptionReport, error) {
+	recs, err := d.storage.List(ctx, records.RecordTypeReceipt)
+	if err != nil {
+		return SubscriptionReport{}, fmt.Errorf("failed to list receipts: %w", err)
+	}
+
+	clusters := map[[2]string][]charge{}
+	vendors := map[[2]string]string{}
+	for _, rec := range recs {
+		receipt, date, ok := parseReceipt(rec)
+		if !ok || receipt.Merchant == "" {
+			continue
+		}
+		key := [2]string{strings.ToLower(strings.TrimSpace(receipt.Merchant)), strings.ToUpper(receipt.Currency)}
+		clusters[key] = append(clusters[key], charge{recordID: rec.ID, date: date, amount: receipt.Total})
+		vendors[key] = receipt.Merchant
+	}
+
+	report := SubscriptionReport{Subscriptions: []Subscription{}}
+	totals := aggregate{}
+	for key, charges := range clusters {
+		subscription, ok := detect(vendors[key], key[1], charges, now)
+		if !ok {
+			continue
+		}
+		report.Subscriptions = append(report.Subscriptions, subscription)
+		if subscription.Active {
+			totals.add("monthly", subscription.Currency, subscription.MonthlyCost)
+		}
+	}
+
+	sort.Slice(report.Subscriptions, func(i, j int) bool {
+		return report.Subscriptions[i].MonthlyCost > report.Subscriptions[j].MonthlyCost
+	})
+	report.MonthlyTotals = totals.buckets(byKey)
+	return report, nil
+}
+
+// detect reports the vendor's charges as a subscription when they recur at a known period
+func detect(vendor, currency string, charges []charge, now time.Time) (Subscription, bool) {
+	charges = typicalCharges(charges)
+	if len(charges) < minCharges {
+		return Subscription{}, false
+	}
+	sort.Slice(charges, func(i, j int) bool { return charges[i].date.Before(charges[j].date) })
+
+	billing, ok := billingPeriod(charges)
+	if !ok {
+		return Subscription{}, false
+	}
+
+	first, last := charges[0], charges[len(charges)-1]
+	previous := charges[len(charges)-2]
+	subscription := Subscription{
+		Vendor:         vendor,
+		Currency:       currency,
+		Period:         billing.name,
+		Amount:         last.amount,
+		PreviousAmount: previous.amount,
+		MonthlyCost:    last.amount * daysPerMonth / float64(billing.days),
+		Charges:        len(charges),
+		FirstCharge:    first.date,
+		LastCharge:     last.date,
+		NextCharge:     last.date.AddDate(0, 0, billing.days),
+		Active:         now.Sub(last.date) <= time.Duration(billing.maxDays)*24*time.Hour,
+		New:            now.Sub(first.date) <= newWithin,
+		Increased:      last.amount > previous.amount,
+	}
+	for _, c := range charges {
+		subscription.RecordIDs = append(subscription.RecordIDs, c.recordID)
+	}
+	return subscription, true
+}
+
+// typicalCharges drops one-off purchases whose amount is far from the median charge
+func typicalCharges(charges []charge) []charge {
+	amounts := make([]float64, len(charges))
+	for i, c := range charges {
+		amounts[i] = c.amount
+	}
+	sort.Float64s(amounts)
+	median := amounts[len(amounts)/2]
+
+	typical := make([]charge, 0, len(charges))
+	for _, c := range charges {
+		if c.amount >= median*(1-amountTolerance) && c.amount <= median*(1+amountTolerance) {
+			typical = append(typical, c)
+		}
+	}
+	return typical
+}
+
+// billingPeriod returns the period matching the median interval between
+// charges, provided most intervals match it
+func billingPeriod(charges []charge) (period, bool) {
+	intervals := make([]int, 0, len(charges)-1)
+	for i := 1; i < len(charges); i++ {
+		intervals = append(intervals, int(charges[i].date.Sub(charges[i-1].date).Hours()/24))
+	}
+	sorted := append([]int(nil), intervals...)
+	sort.Ints(sorted)
+	median := sorted[len(sorted)/2]
+
+	for _, p := range periods {
+		if median < p.minDays || median > p.maxDays {
+			continue
+		}
+		regular := 0
+		for _, interval := range intervals {
+			if interval >= p.minDays && interval <= p.maxDays {
+				regular++
+			}
+		}
+		return p, regular*4 >= len(intervals)*3
+	}
+	return period{}, false
+}
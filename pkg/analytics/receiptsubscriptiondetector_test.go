@@ -0,0 +1,46 @@
+package analytics_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/kazemisoroush/assistant/pkg/analytics"
+	"github.com/kazemisoroush/assistant/pkg/records"
+	"github.com/kazemisoroush/assistant/pkg/records/storage/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestReceiptSubscriptionDetector_Subscriptions(t *testing.T) {
+	// Arrange
+	now := time.Date(2024, time.May, 20, 0, 0, 0, 0, time.UTC)
+	ctrl := gomock.NewController(t)
+	storage := mocks.NewMockStorage(ctrl)
+	storage.EXPECT().List(gomock.Any(), records.RecordTypeReceipt).Return([]records.Record{
+		receipt("n1", "Netflix", "2024-02-15", "entertainment", 12.99),
+		receipt("n2", "Netflix", "2024-03-15", "entertainment", 12.99),
+		receipt("n3", "Netflix", "2024-04-15", "entertainment", 12.99),
+		receipt("n4", "netflix", "2024-05-15", "entertainment", 15.49),
+		receipt("g1", "Rewe", "2024-03-02", "groceries", 40),
+		receipt("g2", "Rewe", "2024-03-05", "groceries", 38),
+		receipt("g3", "Rewe", "2024-04-20", "groceries", 45),
+	}, nil)
+	detector := analytics.NewReceiptSubscriptionDetector(storage)
+
+	// Act
+	report, err := detector.Subscriptions(context.Background(), now)
+
+	// Assert
+	require.NoError(t, err, "Subscriptions() error should be nil")
+	require.Len(t, report.Subscriptions, 1, "Subscriptions() should ignore irregular purchases")
+	subscription := report.Subscriptions[0]
+	assert.Equal(t, "monthly", subscription.Period, "Subscriptions() should detect the billing period")
+	assert.Equal(t, 4, subscription.Charges, "Subscriptions() should match vendors case-insensitively")
+	assert.True(t, subscription.Active, "Subscriptions() should report a recently charged subscription as active")
+	assert.True(t, subscription.Increased, "Subscriptions() should flag an increased charge")
+	assert.False(t, subscription.New, "Subscriptions() should not flag a long-running subscription as new")
+	require.Len(t, report.MonthlyTotals, 1, "Subscriptions() should total per currency")
+	assert.InDelta(t, 15.7, report.MonthlyTotals[0].Total, 0.1, "Subscriptions() should total the monthly cost")
+}
@@ -0,0 +1,29 @@
+// Package answerer answers questions about the vault from the records search
+// finds, citing the records each answer is drawn from.
+package answerer
+
+import "context"
+
+// NoRecordsAnswer is the answer to questions no record matches
+const NoRecordsAnswer = "I couldn't find any records about that."
+
+// Answer is the answer to a question with the records it cites
+type Answer struct {
+	Text      string     `json:"answer"`
+	Citations []Citation `json:"citations"`
+}
+
+// Citation names a record an answer is drawn from
+type Citation struct {
+	RecordID    string  `json:"record_id"`
+	Description string  `json:"description,omitempty"` // Summary of the record, empty if it was not summarized
+	Score       float64 `json:"score"`                 // Relevance of the record to the question (0-1)
+}
+
+// Answerer answers natural-language questions about the records
+//
+//go:generate mockgen -destination=./mocks/mock_answerer.go -mock_names=Answerer=MockAnswerer -package=mocks . Answerer
+type Answerer interface {
+	// Answer returns the answer to the question, citing the records it used
+	Answer(ctx context.Context, question string) (Answer, error)
+}
@@ -0,0 +1,139 @@
+package answerer
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/kazemisoroush/assistant/pkg/ai"
+	"github.com/kazemisoroush/assistant/pkg/prompts"
+	"github.com/kazemisoroush/assistant/pkg/records"
+	"github.com/kazemisoroush/assistant/pkg/records/discovery"
+	"github.com/kazemisoroush/assistant/pkg/records/storage"
+	"github.com/kazemisoroush/assistant/pkg/tokens"
+)
+
+// Turn is a question asked in a chat with its answer
+type Turn struct {
+	Question string `json:"question"`
+	Answer   Answer `json:"answer"`
+}
+
+// Chat is a conversation about the records. Each question is answered from
+// the records discovery finds for it and the records the previous answer
+// cited, with the earlier turns in the prompt, so follow-ups such as "show me
+// the receipt from that visit" resolve. Answers are streamed as the model
+// generates them. A Chat is not safe for concurrent use.
+type Chat struct {
+	answerer *RAGAnswerer
+	history  []Turn
+	maxTurns int
+}
+
+// NewChat creates a new chat grounding each answer in the top topK records
+// and keeping the last maxTurns turns in the prompt
+func NewChat(discovery discovery.Discovery, storage storage.Storage, provider ai.Provider, prompts prompts.Renderer, budgeter *tokens.Budgeter, topK, maxTurns int) *Chat {
+	return &Chat{
+		answerer: &RAGAnswerer{
+			discovery: discovery,
+			storage:   storage,
+			provider:  provider,
+			prompts:   prompts,
+			budgeter:  budgeter,
+			topK:      topK,
+		},
+		maxTurns: maxTurns,
+	}
+}
+
+// History returns the turns of the conversation kept for the prompt, oldest first
+func (c *Chat) History() []Turn {
+	return c.history
+}
+
+// Ask answers the question, calling onToken with each piece of the answer
+// as it is generated. The turn is added to the history once answered.
+func (c *Chat) Ask(ctx context.Context, question string, onToken func(string)) (Answer, error) {
+	var pinned []Citation
+	if len(c.history) > 0 {
+		pinned = c.history[len(c.history)-1].Answer.Citations
+	}
+	hits, recs, err := c.answerer.ground(ctx, question, pinned)
+	if err != nil {
+		return Answer{}, err
+	}
+
+	var answer Answer
+	if len(recs) == 0 && len(c.history) == 0 {
+		onToken(NoRecordsAnswer)
+		answer = Answer{Text: NoRecordsAnswer}
+	} else if answer, err = c.generate(ctx, question, hits, recs, onToken); err != nil {
+		return Answer{}, err
+	}
+
+	c.history = append(c.history, Turn{Question: question, Answer: answer})
+	if c.maxTurns > 0 && len(c.history) > c.maxTurns {
+		c.history = c.history[len(c.history)-c.maxTurns:]
+	}
+	return answer, nil
+}
+
+// generate streams the answer to the question from the records and history
+func (c *Chat) generate(ctx context.Context, question string, hits []discovery.Hit, recs []records.Record, onToken func(string)) (Answer, error) {
+	text, _ := c.answerer.budgeter.Fit(recordContext(recs))
+	prompt, err := c.answerer.prompts.Render(prompts.Chatting, map[string]any{
+		"History":  transcript(c.history),
+		"Context":  text,
+		"Question": question,
+	})
+	if err != nil {
+		return Answer{}, err
+	}
+
+	req := ai.Request{Prompt: prompt, Task: ai.TaskAnswering, RecordTypes: recordTypes(recs)}
+	generated, err := stream(ctx, c.answerer.provider, req, onToken)
+	if err != nil {
+		return Answer{}, fmt.Errorf("failed to answer question: %w", err)
+	}
+	answer := strings.TrimSpace(generated)
+	return Answer{Text: answer, Citations: citations(answer, hits)}, nil
+}
+
+// transcript renders the turns for the prompt
+func transcript(turns []Turn) string {
+	if len(turns) == 0 {
+		return "(none)"
+	}
+	var b strings.Builder
+	for _, turn := range turns {
+		fmt.Fprintf(&b, "User: %s\nAssistant: %s\n", turn.Question, turn.Answer.Text)
+	}
+	return b.String()
+}
+
+// stream returns the completion the provider streams, calling onToken with
+// each piece as it arrives
+func stream(ctx context.Context, provider ai.Provider, req ai.Request, onToken func(string)) (string, error) {
+	tokens, errs := provider.Stream(ctx, req)
+	var b strings.Builder
+	for tokens != nil || errs != nil {
+		select {
+		case token, ok := <-tokens:
+			if !ok {
+				tokens = nil
+				continue
+			}
+			b.WriteString(token)
+			onToken(token)
+		case err, ok := <-errs:
+			if !ok {
+				errs = nil
+				continue
+			}
+			return "", err
+		case <-ctx.Done():
+			return "", ctx.Err()
+		}
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,150 @@
+package answerer_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/kazemisoroush/assistant/pkg/ai"
+	aimocks "github.com/kazemisoroush/assistant/pkg/ai/mocks"
+	"github.com/kazemisoroush/assistant/pkg/answerer"
+	"github.com/kazemisoroush/assistant/pkg/prompts"
+	"github.com/kazemisoroush/assistant/pkg/records"
+	"github.com/kazemisoroush/assistant/pkg/records/discovery"
+	discoverymocks "github.com/kazemisoroush/assistant/pkg/records/discovery/mocks"
+	"github.com/kazemisoroush/assistant/pkg/records/storage"
+	storagemocks "github.com/kazemisoroush/assistant/pkg/records/storage/mocks"
+	"github.com/kazemisoroush/assistant/pkg/tokens"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+// newChat creates a chat over the mocks with the built-in prompts
+func newChat(t *testing.T, finder discovery.Discovery, store storage.Storage, provider ai.Provider, maxTurns int) *answerer.Chat {
+	t.Helper()
+	registry, err := prompts.NewRegistry("")
+	require.NoError(t, err, "NewRegistry() error should be nil")
+	return answerer.NewChat(finder, store, provider, registry, tokens.NewBudgeter(8000, 1000), 3, maxTurns)
+}
+
+// streamed returns channels streaming the tokens, as a provider does
+func streamed(tokens ...string) (<-chan string, <-chan error) {
+	tokenChan := make(chan string, len(tokens))
+	errChan := make(chan error, 1)
+	for _, token := range tokens {
+		tokenChan <- token
+	}
+	close(tokenChan)
+	close(errChan)
+	return tokenChan, errChan
+}
+
+// getRecord returns a receipt with the requested ID
+func getRecord(_ context.Context, id string) (records.Record, error) {
+	return records.Record{ID: id, Type: records.RecordTypeReceipt, Content: "content of " + id}, nil
+}
+
+func TestChat_Ask_FollowUp(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	finder := discoverymocks.NewMockDiscovery(ctrl)
+	store := storagemocks.NewMockStorage(ctrl)
+	provider := aimocks.NewMockProvider(ctrl)
+	finder.EXPECT().Discover(gomock.Any(), gomock.Any()).Return(discovery.DiscoverResponse{Hits: []discovery.Hit{{RecordID: "visit", Description: "Dentist checkup"}}}, nil)
+	finder.EXPECT().Discover(gomock.Any(), gomock.Any()).Return(discovery.DiscoverResponse{Hits: []discovery.Hit{{RecordID: "receipt"}}}, nil)
+	store.EXPECT().Get(gomock.Any(), gomock.Any()).DoAndReturn(getRecord).Times(3)
+	var sent []string
+	provider.EXPECT().Stream(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, r ai.Request) (<-chan string, <-chan error) {
+		sent = append(sent, r.Prompt)
+		if len(sent) == 1 {
+			return streamed("You saw the dentist ", "in March [visit].")
+		}
+		return streamed("The receipt is [receipt].")
+	}).Times(2)
+	chat := newChat(t, finder, store, provider, 10)
+	var streamedText strings.Builder
+
+	// Act
+	first, firstErr := chat.Ask(context.Background(), "When did I see the dentist?", func(token string) { streamedText.WriteString(token) })
+	second, secondErr := chat.Ask(context.Background(), "Show me the receipt from that visit", func(string) {})
+
+	// Assert
+	require.NoError(t, firstErr, "Ask() error should be nil")
+	require.NoError(t, secondErr, "Ask() error should be nil")
+	assert.Equal(t, "You saw the dentist in March [visit].", streamedText.String(), "Ask() should stream the answer")
+	assert.Equal(t, []answerer.Citation{{RecordID: "visit", Description: "Dentist checkup"}}, first.Citations, "Ask() should cite the records the answer names")
+	assert.Equal(t, "receipt", second.Citations[0].RecordID, "Ask() should cite the follow-up's records")
+	assert.Contains(t, sent[1], "User: When did I see the dentist?\nAssistant: You saw the dentist in March [visit].", "Ask() should put the earlier turns in the prompt")
+	assert.Contains(t, sent[1], "content of visit", "Ask() should ground follow-ups in the records cited before")
+	assert.Len(t, chat.History(), 2, "Ask() should keep each turn")
+}
+
+func TestChat_Ask_KeepsLastTurns(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	finder := discoverymocks.NewMockDiscovery(ctrl)
+	store := storagemocks.NewMockStorage(ctrl)
+	provider := aimocks.NewMockProvider(ctrl)
+	finder.EXPECT().Discover(gomock.Any(), gomock.Any()).Return(discovery.DiscoverResponse{Hits: []discovery.Hit{{RecordID: "a"}}}, nil).Times(3)
+	store.EXPECT().Get(gomock.Any(), gomock.Any()).DoAndReturn(getRecord).Times(3)
+	provider.EXPECT().Stream(gomock.Any(), gomock.Any()).DoAndReturn(func(context.Context, ai.Request) (<-chan string, <-chan error) {
+		return streamed("ok")
+	}).Times(3)
+	chat := newChat(t, finder, store, provider, 2)
+
+	// Act
+	for _, question := range []string{"one", "two", "three"} {
+		_, err := chat.Ask(context.Background(), question, func(string) {})
+		require.NoError(t, err, "Ask() error should be nil")
+	}
+
+	// Assert
+	history := chat.History()
+	require.Len(t, history, 2, "Ask() should keep at most maxTurns turns")
+	assert.Equal(t, "two", history[0].Question, "Ask() should drop the oldest turns")
+}
+
+func TestChat_Ask_NoRecords(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	finder := discoverymocks.NewMockDiscovery(ctrl)
+	finder.EXPECT().Discover(gomock.Any(), gomock.Any()).Return(discovery.DiscoverResponse{}, nil)
+	chat := newChat(t, finder, storagemocks.NewMockStorage(ctrl), aimocks.NewMockProvider(ctrl), 10)
+	var streamedText string
+
+	// Act
+	answer, err := chat.Ask(context.Background(), "Where is my passport?", func(token string) { streamedText += token })
+
+	// Assert
+	require.NoError(t, err, "Ask() error should be nil")
+	assert.Equal(t, answerer.NoRecordsAnswer, answer.Text, "Ask() should not ask the model without records")
+	assert.Equal(t, answerer.NoRecordsAnswer, streamedText, "Ask() should stream the answer")
+}
+
+func TestChat_Ask_StreamFails(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	finder := discoverymocks.NewMockDiscovery(ctrl)
+	store := storagemocks.NewMockStorage(ctrl)
+	provider := aimocks.NewMockProvider(ctrl)
+	finder.EXPECT().Discover(gomock.Any(), gomock.Any()).Return(discovery.DiscoverResponse{Hits: []discovery.Hit{{RecordID: "a"}}}, nil)
+	store.EXPECT().Get(gomock.Any(), gomock.Any()).DoAndReturn(getRecord)
+	provider.EXPECT().Stream(gomock.Any(), gomock.Any()).DoAndReturn(func(context.Context, ai.Request) (<-chan string, <-chan error) {
+		tokenChan, errChan := make(chan string), make(chan error, 1)
+		errChan <- errors.New("model unavailable")
+		close(tokenChan)
+		close(errChan)
+		return tokenChan, errChan
+	})
+	chat := newChat(t, finder, store, provider, 10)
+
+	// Act
+	_, err := chat.Ask(context.Background(), "receipts", func(string) {})
+
+	// Assert
+	require.Error(t, err, "Ask() should fail when the model fails")
+	assert.Contains(t, err.Error(), "model unavailable", "Ask() should wrap the model error")
+	assert.Empty(t, chat.History(), "Ask() should not keep failed turns")
+}
@@ -0,0 +1,164 @@
+package answerer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/kazemisoroush/assistant/pkg/ai"
+	"github.com/kazemisoroush/assistant/pkg/prompts"
+	"github.com/kazemisoroush/assistant/pkg/records"
+	"github.com/kazemisoroush/assistant/pkg/records/discovery"
+	"github.com/kazemisoroush/assistant/pkg/records/storage"
+	"github.com/kazemisoroush/assistant/pkg/tokens"
+)
+
+// bracketed captures the text of the brackets answers cite record IDs in
+var bracketed = regexp.MustCompile(`\[([^\[\]]+)\]`)
+
+// maxExcerpt bounds the characters of a record's content in the context, so
+// one long record does not crowd out the others
+const maxExcerpt = 4000
+
+// RAGAnswerer answers questions with a language model, grounding the answer
+// in the top records discovery finds for the question. The model is asked to
+// cite the IDs of the records it used in [brackets].
+type RAGAnswerer struct {
+	discovery discovery.Discovery
+	storage   storage.Storage
+	provider  ai.Provider
+	prompts   prompts.Renderer
+	budgeter  *tokens.Budgeter
+	topK      int
+}
+
+// NewRAGAnswerer creates a new answerer grounding answers in the top topK records
+func NewRAGAnswerer(discovery discovery.Discovery, storage storage.Storage, provider ai.Provider, prompts prompts.Renderer, budgeter *tokens.Budgeter, topK int) Answerer {
+	return &RAGAnswerer{
+		discovery: discovery,
+		storage:   storage,
+		provider:  provider,
+		prompts:   prompts,
+		budgeter:  budgeter,
+		topK:      topK,
+	}
+}
+
+// Answer implements Answerer. Questions no record matches are answered
+// without asking the model.
+func (a *RAGAnswerer) Answer(ctx context.Context, question string) (Answer, error) {
+	hits, recs, err := a.ground(ctx, question, nil)
+	if err != nil {
+		return Answer{}, err
+	}
+	if len(recs) == 0 {
+		return Answer{Text: NoRecordsAnswer}, nil
+	}
+
+	text, _ := a.budgeter.Fit(recordContext(recs))
+	prompt, err := a.prompts.Render(prompts.Answering, map[string]any{
+		"Context":  text,
+		"Question": question,
+	})
+	if err != nil {
+		return Answer{}, err
+	}
+	gen, err := a.provider.Generate(ctx, ai.Request{Prompt: prompt, Task: ai.TaskAnswering, RecordTypes: recordTypes(recs)})
+	if err != nil {
+		return Answer{}, fmt.Errorf("failed to answer question: %w", err)
+	}
+
+	answer := strings.TrimSpace(gen.Text)
+	return Answer{Text: answer, Citations: citations(answer, hits)}, nil
+}
+
+// ground returns the records discovery finds for the question, followed by
+// the pinned records it did not find, with their hits
+func (a *RAGAnswerer) ground(ctx context.Context, question string, pinned []Citation) ([]discovery.Hit, []records.Record, error) {
+	resp, err := a.discovery.Discover(ctx, discovery.DiscoverRequest{Prompt: question, Limit: a.topK})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to find records: %w", err)
+	}
+	hits := resp.Hits
+	for _, citation := range pinned {
+		if !slices.ContainsFunc(hits, func(hit discovery.Hit) bool { return hit.RecordID == citation.RecordID }) {
+			hits = append(hits, discovery.Hit{RecordID: citation.RecordID, Score: citation.Score, Description: citation.Description})
+		}
+	}
+	return a.records(ctx, hits)
+}
+
+// records reads the records of the hits, skipping hits whose record was
+// deleted since it was indexed
+func (a *RAGAnswerer) records(ctx context.Context, hits []discovery.Hit) ([]discovery.Hit, []records.Record, error) {
+	found := make([]discovery.Hit, 0, len(hits))
+	recs := make([]records.Record, 0, len(hits))
+	for _, hit := range hits {
+		rec, err := a.storage.Get(ctx, hit.RecordID)
+		if errors.Is(err, storage.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read record %s: %w", hit.RecordID, err)
+		}
+		found = append(found, hit)
+		recs = append(recs, rec)
+	}
+	return found, recs, nil
+}
+
+// recordContext renders the records for the prompt, each headed by its ID
+// in the brackets the model cites it with
+func recordContext(recs []records.Record) string {
+	var b strings.Builder
+	for _, rec := range recs {
+		fmt.Fprintf(&b, "[%s] %s, %s", rec.ID, strings.ReplaceAll(string(rec.Type), "_", " "), rec.CreatedAt.Format("2006-01-02"))
+		if rec.Title != "" {
+			fmt.Fprintf(&b, ": %s", rec.Title)
+		}
+		b.WriteString("\n")
+		if description := rec.Description(); description != "" {
+			b.WriteString(description + "\n")
+		}
+		content := []rune(strings.TrimSpace(rec.Content))
+		if len(content) > maxExcerpt {
+			content = append(content[:maxExcerpt], '…')
+		}
+		b.WriteString(string(content) + "\n\n")
+	}
+	return b.String()
+}
+
+// recordTypes returns the distinct types of the records, for the guardrails
+// to check
+func recordTypes(recs []records.Record) []string {
+	var types []string
+	for _, rec := range recs {
+		if !slices.Contains(types, string(rec.Type)) {
+			types = append(types, string(rec.Type))
+		}
+	}
+	return types
+}
+
+// citations returns the hits whose record ID the answer cites, in the order
+// discovery ranked them. Several IDs may share brackets, as in [a, b].
+func citations(answer string, hits []discovery.Hit) []Citation {
+	ids := map[string]bool{}
+	for _, match := range bracketed.FindAllStringSubmatch(answer, -1) {
+		for _, id := range strings.FieldsFunc(match[1], func(r rune) bool { return r == ',' || r == ';' || r == ' ' }) {
+			ids[id] = true
+		}
+	}
+
+	var cited []Citation
+	for _, hit := range hits {
+		if ids[hit.RecordID] {
+			cited = append(cited, Citation{RecordID: hit.RecordID, Description: hit.Description, Score: hit.Score})
+		}
+	}
+	return cited
+}
@@ -0,0 +1,118 @@
+package answerer_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/kazemisoroush/assistant/pkg/ai"
+	aimocks "github.com/kazemisoroush/assistant/pkg/ai/mocks"
+	"github.com/kazemisoroush/assistant/pkg/answerer"
+	"github.com/kazemisoroush/assistant/pkg/prompts"
+	"github.com/kazemisoroush/assistant/pkg/records"
+	"github.com/kazemisoroush/assistant/pkg/records/discovery"
+	discoverymocks "github.com/kazemisoroush/assistant/pkg/records/discovery/mocks"
+	"github.com/kazemisoroush/assistant/pkg/records/storage"
+	storagemocks "github.com/kazemisoroush/assistant/pkg/records/storage/mocks"
+	"github.com/kazemisoroush/assistant/pkg/tokens"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+// newAnswerer creates an answerer over the mocks with the built-in prompts
+func newAnswerer(t *testing.T, finder discovery.Discovery, store storage.Storage, provider ai.Provider) answerer.Answerer {
+	t.Helper()
+	registry, err := prompts.NewRegistry("")
+	require.NoError(t, err, "NewRegistry() error should be nil")
+	return answerer.NewRAGAnswerer(finder, store, provider, registry, tokens.NewBudgeter(8000, 1000), 3)
+}
+
+func TestRAGAnswerer_Answer(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	finder := discoverymocks.NewMockDiscovery(ctrl)
+	store := storagemocks.NewMockStorage(ctrl)
+	provider := aimocks.NewMockProvider(ctrl)
+	created := time.Date(2026, 2, 3, 0, 0, 0, 0, time.UTC)
+	finder.EXPECT().Discover(gomock.Any(), discovery.DiscoverRequest{Prompt: "When did I see the dentist?", Limit: 3}).Return(discovery.DiscoverResponse{Hits: []discovery.Hit{
+		{RecordID: "visit", Score: 0.9, Description: "Dentist checkup"},
+		{RecordID: "receipt", Score: 0.6},
+		{RecordID: "deleted", Score: 0.5},
+	}}, nil)
+	store.EXPECT().Get(gomock.Any(), "visit").Return(records.Record{ID: "visit", Type: records.RecordTypeHealthVisit, Content: "Checkup with Dr. Berg", CreatedAt: created}, nil)
+	store.EXPECT().Get(gomock.Any(), "receipt").Return(records.Record{ID: "receipt", Type: records.RecordTypeReceipt, Content: "Dental clinic 80 EUR", CreatedAt: created}, nil)
+	store.EXPECT().Get(gomock.Any(), "deleted").Return(records.Record{}, storage.ErrNotFound)
+	var req ai.Request
+	provider.EXPECT().Generate(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, r ai.Request) (ai.Response, error) {
+		req = r
+		return ai.Response{Text: " You saw Dr. Berg on 2026-02-03 [visit]. \n"}, nil
+	})
+	a := newAnswerer(t, finder, store, provider)
+
+	// Act
+	answer, err := a.Answer(context.Background(), "When did I see the dentist?")
+
+	// Assert
+	require.NoError(t, err, "Answer() error should be nil")
+	assert.Equal(t, "You saw Dr. Berg on 2026-02-03 [visit].", answer.Text, "Answer() should return the model's answer")
+	assert.Equal(t, []answerer.Citation{{RecordID: "visit", Description: "Dentist checkup", Score: 0.9}}, answer.Citations, "Answer() should cite the records the answer names")
+	assert.Contains(t, req.Prompt, "[visit] health visit, 2026-02-03", "Answer() should head each record with its ID")
+	assert.Contains(t, req.Prompt, "Dental clinic 80 EUR", "Answer() should ground the prompt in every found record")
+	assert.Equal(t, ai.TaskAnswering, req.Task, "Answer() should mark the request as answering")
+	assert.Equal(t, []string{"health_visit", "receipt"}, req.RecordTypes, "Answer() should name the record types for the guardrails")
+}
+
+func TestRAGAnswerer_Answer_CitesSharedBrackets(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	finder := discoverymocks.NewMockDiscovery(ctrl)
+	store := storagemocks.NewMockStorage(ctrl)
+	provider := aimocks.NewMockProvider(ctrl)
+	finder.EXPECT().Discover(gomock.Any(), gomock.Any()).Return(discovery.DiscoverResponse{Hits: []discovery.Hit{{RecordID: "a"}, {RecordID: "b"}}}, nil)
+	store.EXPECT().Get(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, id string) (records.Record, error) {
+		return records.Record{ID: id, Type: records.RecordTypeReceipt, Content: "receipt " + id}, nil
+	}).Times(2)
+	provider.EXPECT().Generate(gomock.Any(), gomock.Any()).Return(ai.Response{Text: "Both receipts [b, a]."}, nil)
+	a := newAnswerer(t, finder, store, provider)
+
+	// Act
+	answer, err := a.Answer(context.Background(), "receipts")
+
+	// Assert
+	require.NoError(t, err, "Answer() error should be nil")
+	require.Len(t, answer.Citations, 2, "Answer() should read every ID in shared brackets")
+	assert.Equal(t, "a", answer.Citations[0].RecordID, "Answer() should order citations by rank")
+}
+
+func TestRAGAnswerer_Answer_NoRecords(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	finder := discoverymocks.NewMockDiscovery(ctrl)
+	finder.EXPECT().Discover(gomock.Any(), gomock.Any()).Return(discovery.DiscoverResponse{}, nil)
+	a := newAnswerer(t, finder, storagemocks.NewMockStorage(ctrl), aimocks.NewMockProvider(ctrl))
+
+	// Act
+	answer, err := a.Answer(context.Background(), "Where is my passport?")
+
+	// Assert
+	require.NoError(t, err, "Answer() error should be nil")
+	assert.Equal(t, answerer.NoRecordsAnswer, answer.Text, "Answer() should not ask the model without records")
+	assert.Empty(t, answer.Citations, "Answer() should cite nothing without records")
+}
+
+func TestRAGAnswerer_Answer_DiscoveryFails(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	finder := discoverymocks.NewMockDiscovery(ctrl)
+	finder.EXPECT().Discover(gomock.Any(), gomock.Any()).Return(discovery.DiscoverResponse{}, errors.New("index unavailable"))
+	a := newAnswerer(t, finder, storagemocks.NewMockStorage(ctrl), aimocks.NewMockProvider(ctrl))
+
+	// Act
+	_, err := a.Answer(context.Background(), "Where is my passport?")
+
+	// Assert
+	require.Error(t, err, "Answer() should fail when no records can be found")
+	assert.Contains(t, err.Error(), "index unavailable", "Answer() should wrap the discovery error")
+}
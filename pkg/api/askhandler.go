@@ -0,0 +1,57 @@
+package api
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/kazemisoroush/assistant/pkg/answerer"
+)
+
+// AskPath is the route of the question answering endpoint
+const AskPath = "/api/v1/ask"
+
+// AskRequest is the body of a question
+type AskRequest struct {
+	Question string `json:"question"`
+}
+
+// AskHandler answers questions from the records, citing the records used
+type AskHandler struct {
+	answerer answerer.Answerer
+}
+
+// NewAskHandler creates a new ask handler
+func NewAskHandler(answerer answerer.Answerer) http.Handler {
+	return &AskHandler{
+		answerer: answerer,
+	}
+}
+
+// ServeHTTP handles POST requests with a JSON AskRequest body, responding
+// with the answerer.Answer
+func (h *AskHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req AskRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || strings.TrimSpace(req.Question) == "" {
+		http.Error(w, "question is required", http.StatusBadRequest)
+		return
+	}
+
+	answer, err := h.answerer.Answer(r.Context(), req.Question)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Failed to answer question", "error", err)
+		http.Error(w, "failed to answer question", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(answer); err != nil {
+		slog.WarnContext(r.Context(), "Failed to write answer", "error", err)
+	}
+}
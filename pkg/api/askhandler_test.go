@@ -0,0 +1,63 @@
+package api_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/kazemisoroush/assistant/pkg/answerer"
+	"github.com/kazemisoroush/assistant/pkg/answerer/mocks"
+	"github.com/kazemisoroush/assistant/pkg/api"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestAskHandler_ServeHTTP(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	a := mocks.NewMockAnswerer(ctrl)
+	a.EXPECT().Answer(gomock.Any(), "When does my passport expire?").Return(answerer.Answer{
+		Text:      "In March 2030 [passport].",
+		Citations: []answerer.Citation{{RecordID: "passport", Score: 0.9}},
+	}, nil)
+	handler := api.NewAskHandler(a)
+	rec := httptest.NewRecorder()
+
+	// Act
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, api.AskPath, strings.NewReader(`{"question":"When does my passport expire?"}`)))
+
+	// Assert
+	assert.Equal(t, http.StatusOK, rec.Code, "ServeHTTP() should succeed")
+	assert.Contains(t, rec.Body.String(), `"answer":"In March 2030 [passport]."`, "ServeHTTP() should return the answer")
+	assert.Contains(t, rec.Body.String(), `"record_id":"passport"`, "ServeHTTP() should return the citations")
+}
+
+func TestAskHandler_ServeHTTP_MissingQuestion(t *testing.T) {
+	// Arrange
+	handler := api.NewAskHandler(mocks.NewMockAnswerer(gomock.NewController(t)))
+	rec := httptest.NewRecorder()
+
+	// Act
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, api.AskPath, strings.NewReader(`{"question":" "}`)))
+
+	// Assert
+	assert.Equal(t, http.StatusBadRequest, rec.Code, "ServeHTTP() should require a question")
+}
+
+func TestAskHandler_ServeHTTP_AnswerFails(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	a := mocks.NewMockAnswerer(ctrl)
+	a.EXPECT().Answer(gomock.Any(), gomock.Any()).Return(answerer.Answer{}, errors.New("model unavailable"))
+	handler := api.NewAskHandler(a)
+	rec := httptest.NewRecorder()
+
+	// Act
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, api.AskPath, strings.NewReader(`{"question":"receipts"}`)))
+
+	// Assert
+	assert.Equal(t, http.StatusInternalServerError, rec.Code, "ServeHTTP() should report a failed answer")
+	assert.NotContains(t, rec.Body.String(), "model unavailable", "ServeHTTP() should not leak the error")
+}
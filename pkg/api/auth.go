@@ -0,0 +1,39 @@
+package api
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+)
+
+// RequireToken lets through only requests carrying the token as a bearer token
+func RequireToken(token string, next http.Handler) http.Handler {
+	return require(token, next, func(r *http.Request) string {
+		bearer, _ := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		return bearer
+	})
+}
+
+// RequireFeedToken lets through only requests carrying the token as a bearer
+// token or as the token query parameter. Calendar apps cannot send headers
+// when subscribing to a feed, so they pass the token in its URL.
+func RequireFeedToken(token string, next http.Handler) http.Handler {
+	return require(token, next, func(r *http.Request) string {
+		if bearer, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok {
+			return bearer
+		}
+		return r.URL.Query().Get("token")
+	})
+}
+
+// require lets through only requests whose credential, read by credential,
+// is the token. No request is let through without a token.
+func require(token string, next http.Handler, credential func(*http.Request) string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if token == "" || subtle.ConstantTimeCompare([]byte(credential(r)), []byte(token)) != 1 {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
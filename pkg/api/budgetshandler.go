@@ -0,0 +1,45 @@
+package api
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/kazemisoroush/assistant/pkg/budgets"
+)
+
+// BudgetsPath is the route of the budgets endpoint
+const BudgetsPath = "/api/v1/budgets"
+
+// BudgetsHandler serves the current month's spend against every budget
+type BudgetsHandler struct {
+	checker budgets.Checker
+}
+
+// NewBudgetsHandler creates a new budgets handler
+func NewBudgetsHandler(checker budgets.Checker) http.Handler {
+	return &BudgetsHandler{
+		checker: checker,
+	}
+}
+
+// ServeHTTP handles GET requests
+func (h *BudgetsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	statuses, err := h.checker.Status(r.Context(), time.Now())
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Failed to check budgets", "error", err)
+		http.Error(w, "failed to check budgets", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(statuses); err != nil {
+		slog.WarnContext(r.Context(), "Failed to write budgets response", "error", err)
+	}
+}
@@ -0,0 +1,48 @@
+package api_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kazemisoroush/assistant/pkg/api"
+	"github.com/kazemisoroush/assistant/pkg/budgets"
+	"github.com/kazemisoroush/assistant/pkg/budgets/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestBudgetsHandler_ServeHTTP(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	checker := mocks.NewMockChecker(ctrl)
+	checker.EXPECT().Status(gomock.Any(), gomock.Any()).Return([]budgets.Status{
+		{Budget: budgets.Budget{Category: "groceries", Limit: 500, Currency: "EUR"}, Month: "2024-05", Spent: 430, Percent: 86, Threshold: 80},
+	}, nil)
+	handler := api.NewBudgetsHandler(checker)
+	rec := httptest.NewRecorder()
+
+	// Act
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, api.BudgetsPath, nil))
+
+	// Assert
+	require.Equal(t, http.StatusOK, rec.Code, "ServeHTTP() should succeed")
+	var statuses []budgets.Status
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &statuses), "ServeHTTP() should return JSON")
+	require.Len(t, statuses, 1, "ServeHTTP() should return every budget")
+	assert.Equal(t, "groceries", statuses[0].Category, "ServeHTTP() should include the budget")
+}
+
+func TestBudgetsHandler_ServeHTTP_MethodNotAllowed(t *testing.T) {
+	// Arrange
+	handler := api.NewBudgetsHandler(mocks.NewMockChecker(gomock.NewController(t)))
+	rec := httptest.NewRecorder()
+
+	// Act
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, api.BudgetsPath, nil))
+
+	// Assert
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code, "ServeHTTP() should only allow GET")
+}
@@ -0,0 +1,54 @@
+package api
+
+import (
+	"bytes"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/kazemisoroush/assistant/pkg/calendar"
+)
+
+// CalendarPath is the route of the iCalendar feed
+const CalendarPath = "/api/v1/calendar.ics"
+
+// CalendarHandler serves document expirations, renewal reminders and health
+// follow-ups as an iCalendar feed. Subscribers authenticate with the feed
+// token, checked by RequireFeedToken in front of the handler.
+type CalendarHandler struct {
+	builder calendar.Builder
+}
+
+// NewCalendarHandler creates a new calendar feed handler
+func NewCalendarHandler(builder calendar.Builder) http.Handler {
+	return &CalendarHandler{
+		builder: builder,
+	}
+}
+
+// ServeHTTP handles GET requests
+func (h *CalendarHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	events, err := h.builder.Events(r.Context())
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Failed to build calendar", "error", err)
+		http.Error(w, "failed to build calendar", http.StatusInternalServerError)
+		return
+	}
+
+	var buf bytes.Buffer
+	if err := calendar.WriteICS(&buf, events, time.Now()); err != nil {
+		slog.ErrorContext(r.Context(), "Failed to encode calendar", "error", err)
+		http.Error(w, "failed to build calendar", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	if _, err := w.Write(buf.Bytes()); err != nil {
+		slog.WarnContext(r.Context(), "Failed to write calendar response", "error", err)
+	}
+}
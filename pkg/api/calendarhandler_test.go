@@ -0,0 +1,92 @@
+package api_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/kazemisoroush/assistant/pkg/api"
+	"github.com/kazemisoroush/assistant/pkg/calendar"
+	"github.com/kazemisoroush/assistant/pkg/calendar/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestCalendarHandler_ServeHTTP(t *testing.T) {
+	tests := []struct {
+		name   string
+		target string
+		header string
+	}{
+		{name: "query token", target: api.CalendarPath + "?token=secret"},
+		{name: "bearer token", target: api.CalendarPath, header: "Bearer secret"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			ctrl := gomock.NewController(t)
+			builder := mocks.NewMockBuilder(ctrl)
+			builder.EXPECT().Events(gomock.Any()).Return([]calendar.Event{
+				{UID: "expiry-passport@assistant", Kind: calendar.KindExpiry, Summary: "passport expires", Date: time.Date(2025, 3, 31, 0, 0, 0, 0, time.UTC)},
+			}, nil)
+			handler := api.RequireFeedToken("secret", api.NewCalendarHandler(builder))
+			req := httptest.NewRequest(http.MethodGet, tt.target, nil)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			rec := httptest.NewRecorder()
+
+			// Act
+			handler.ServeHTTP(rec, req)
+
+			// Assert
+			require.Equal(t, http.StatusOK, rec.Code, "ServeHTTP() should succeed")
+			assert.Equal(t, "text/calendar; charset=utf-8", rec.Header().Get("Content-Type"), "ServeHTTP() should serve iCalendar")
+			assert.Contains(t, rec.Body.String(), "SUMMARY:passport expires", "ServeHTTP() should include the events")
+		})
+	}
+}
+
+func TestCalendarHandler_ServeHTTP_Rejected(t *testing.T) {
+	tests := []struct {
+		name     string
+		token    string
+		target   string
+		wantCode int
+	}{
+		{name: "wrong token", token: "secret", target: api.CalendarPath + "?token=guess", wantCode: http.StatusUnauthorized},
+		{name: "missing token", token: "secret", target: api.CalendarPath, wantCode: http.StatusUnauthorized},
+		{name: "no token configured", token: "", target: api.CalendarPath + "?token=", wantCode: http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			handler := api.RequireFeedToken(tt.token, api.NewCalendarHandler(mocks.NewMockBuilder(gomock.NewController(t))))
+			rec := httptest.NewRecorder()
+
+			// Act
+			handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.target, nil))
+
+			// Assert
+			assert.Equal(t, tt.wantCode, rec.Code, "ServeHTTP() should reject requests without the feed token")
+		})
+	}
+}
+
+func TestCalendarHandler_ServeHTTP_Error(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	builder := mocks.NewMockBuilder(ctrl)
+	builder.EXPECT().Events(gomock.Any()).Return(nil, errors.New("storage unavailable"))
+	handler := api.NewCalendarHandler(builder)
+	rec := httptest.NewRecorder()
+
+	// Act
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, api.CalendarPath, nil))
+
+	// Assert
+	assert.Equal(t, http.StatusInternalServerError, rec.Code, "ServeHTTP() should report calendar failures")
+}
@@ -0,0 +1,50 @@
+package api
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+
+	"github.com/kazemisoroush/assistant/pkg/claims"
+)
+
+// ClaimsPath is the route of the claims endpoint
+const ClaimsPath = "/api/v1/claims"
+
+// ClaimsHandler serves medical expenses with the insurance claims they were matched to
+type ClaimsHandler struct {
+	matcher claims.Matcher
+}
+
+// NewClaimsHandler creates a new claims handler
+func NewClaimsHandler(matcher claims.Matcher) http.Handler {
+	return &ClaimsHandler{
+		matcher: matcher,
+	}
+}
+
+// ServeHTTP handles GET requests with an optional status query parameter
+func (h *ClaimsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	status, err := claims.ParseStatus(r.URL.Query().Get("status"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	report, err := h.matcher.Match(r.Context(), status)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Failed to match claims", "error", err)
+		http.Error(w, "failed to match claims", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(report); err != nil {
+		slog.WarnContext(r.Context(), "Failed to write claims response", "error", err)
+	}
+}
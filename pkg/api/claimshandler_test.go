@@ -0,0 +1,48 @@
+package api_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kazemisoroush/assistant/pkg/api"
+	"github.com/kazemisoroush/assistant/pkg/claims"
+	"github.com/kazemisoroush/assistant/pkg/claims/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestClaimsHandler_ServeHTTP(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	matcher := mocks.NewMockMatcher(ctrl)
+	matcher.EXPECT().Match(gomock.Any(), claims.StatusUnclaimed).Return(claims.Report{
+		Matches:      []claims.Match{{Expense: claims.Expense{RecordID: "ocr-1", Amount: 90, Currency: "EUR"}, Status: claims.StatusUnclaimed}},
+		Unreimbursed: map[string]float64{"EUR": 90},
+	}, nil)
+	handler := api.NewClaimsHandler(matcher)
+	rec := httptest.NewRecorder()
+
+	// Act
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, api.ClaimsPath+"?status=unclaimed", nil))
+
+	// Assert
+	require.Equal(t, http.StatusOK, rec.Code, "ServeHTTP() should succeed")
+	var report claims.Report
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &report), "ServeHTTP() should return JSON")
+	assert.Len(t, report.Matches, 1, "ServeHTTP() should return the matched expenses")
+}
+
+func TestClaimsHandler_ServeHTTP_UnknownStatus(t *testing.T) {
+	// Arrange
+	handler := api.NewClaimsHandler(mocks.NewMockMatcher(gomock.NewController(t)))
+	rec := httptest.NewRecorder()
+
+	// Act
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, api.ClaimsPath+"?status=lost", nil))
+
+	// Assert
+	assert.Equal(t, http.StatusBadRequest, rec.Code, "ServeHTTP() should reject an unknown status")
+}
@@ -0,0 +1,46 @@
+package api
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/kazemisoroush/assistant/pkg/dashboard"
+)
+
+// DashboardPath is the route of the dashboard endpoint
+const DashboardPath = "/api/v1/dashboard"
+
+// DashboardHandler serves expiring documents, upcoming obligations, the review
+// queue, recent ingests and month-to-date spend in one payload
+type DashboardHandler struct {
+	builder dashboard.Builder
+}
+
+// NewDashboardHandler creates a new dashboard handler
+func NewDashboardHandler(builder dashboard.Builder) http.Handler {
+	return &DashboardHandler{
+		builder: builder,
+	}
+}
+
+// ServeHTTP handles GET requests
+func (h *DashboardHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	overview, err := h.builder.Build(r.Context(), time.Now())
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Failed to build dashboard", "error", err)
+		http.Error(w, "failed to build dashboard", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(overview); err != nil {
+		slog.WarnContext(r.Context(), "Failed to write dashboard response", "error", err)
+	}
+}
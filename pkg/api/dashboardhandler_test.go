@@ -0,0 +1,67 @@
+package api_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kazemisoroush/assistant/pkg/api"
+	"github.com/kazemisoroush/assistant/pkg/dashboard"
+	"github.com/kazemisoroush/assistant/pkg/dashboard/mocks"
+	"github.com/kazemisoroush/assistant/pkg/reminders"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestDashboardHandler_ServeHTTP(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	builder := mocks.NewMockBuilder(ctrl)
+	builder.EXPECT().Build(gomock.Any(), gomock.Any()).Return(dashboard.Dashboard{
+		Expiring: []reminders.Reminder{{RecordID: "passport", DaysLeft: 12}},
+		Upcoming: []dashboard.Obligation{{Kind: dashboard.KindRenewal, RecordID: "sub-1", Title: "Streamflix"}},
+	}, nil)
+	handler := api.NewDashboardHandler(builder)
+	rec := httptest.NewRecorder()
+
+	// Act
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, api.DashboardPath, nil))
+
+	// Assert
+	require.Equal(t, http.StatusOK, rec.Code, "ServeHTTP() should succeed")
+	var got dashboard.Dashboard
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &got), "ServeHTTP() should return JSON")
+	require.Len(t, got.Expiring, 1, "ServeHTTP() should include the expiring documents")
+	require.Len(t, got.Upcoming, 1, "ServeHTTP() should include the upcoming obligations")
+	assert.Equal(t, "Streamflix", got.Upcoming[0].Title, "ServeHTTP() should include the obligation title")
+}
+
+func TestDashboardHandler_ServeHTTP_MethodNotAllowed(t *testing.T) {
+	// Arrange
+	handler := api.NewDashboardHandler(mocks.NewMockBuilder(gomock.NewController(t)))
+	rec := httptest.NewRecorder()
+
+	// Act
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, api.DashboardPath, nil))
+
+	// Assert
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code, "ServeHTTP() should only accept GET")
+}
+
+func TestDashboardHandler_ServeHTTP_Error(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	builder := mocks.NewMockBuilder(ctrl)
+	builder.EXPECT().Build(gomock.Any(), gomock.Any()).Return(dashboard.Dashboard{}, errors.New("storage unavailable"))
+	handler := api.NewDashboardHandler(builder)
+	rec := httptest.NewRecorder()
+
+	// Act
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, api.DashboardPath, nil))
+
+	// Assert
+	assert.Equal(t, http.StatusInternalServerError, rec.Code, "ServeHTTP() should report dashboard failures")
+}
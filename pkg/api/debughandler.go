@@ -0,0 +1,24 @@
+package api
+
+import (
+	"expvar"
+	"net/http"
+	"net/http/pprof"
+)
+
+// DebugPath is the route prefix of the profiling and runtime variable endpoints
+const DebugPath = "/debug/"
+
+// NewDebugHandler serves pprof profiles under /debug/pprof/ and expvar
+// variables at /debug/vars. It exposes process internals, so it is meant to be
+// mounted only when profiling is wanted and never on a public listener.
+func NewDebugHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc(DebugPath+"pprof/", pprof.Index)
+	mux.HandleFunc(DebugPath+"pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc(DebugPath+"pprof/profile", pprof.Profile)
+	mux.HandleFunc(DebugPath+"pprof/symbol", pprof.Symbol)
+	mux.HandleFunc(DebugPath+"pprof/trace", pprof.Trace)
+	mux.Handle(DebugPath+"vars", expvar.Handler())
+	return mux
+}
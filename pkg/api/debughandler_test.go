@@ -0,0 +1,39 @@
+package api_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kazemisoroush/assistant/pkg/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDebugHandler_ServeHTTP_Vars(t *testing.T) {
+	// Arrange
+	handler := api.NewDebugHandler()
+	rec := httptest.NewRecorder()
+
+	// Act
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, api.DebugPath+"vars", nil))
+
+	// Assert
+	require.Equal(t, http.StatusOK, rec.Code, "ServeHTTP() should serve expvar variables")
+	var vars map[string]json.RawMessage
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &vars), "ServeHTTP() should return JSON")
+	assert.Contains(t, vars, "memstats", "ServeHTTP() should include the runtime memory statistics")
+}
+
+func TestDebugHandler_ServeHTTP_Profiles(t *testing.T) {
+	// Arrange
+	handler := api.NewDebugHandler()
+	rec := httptest.NewRecorder()
+
+	// Act
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, api.DebugPath+"pprof/heap?debug=1", nil))
+
+	// Assert
+	assert.Equal(t, http.StatusOK, rec.Code, "ServeHTTP() should serve pprof profiles")
+}
@@ -0,0 +1,44 @@
+package api
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+
+	"github.com/kazemisoroush/assistant/pkg/duplicates"
+)
+
+// DuplicatesPath is the route of the duplicates endpoint
+const DuplicatesPath = "/api/v1/duplicates"
+
+// DuplicatesHandler serves records that look like copies of the same document
+type DuplicatesHandler struct {
+	detector duplicates.Detector
+}
+
+// NewDuplicatesHandler creates a new duplicates handler
+func NewDuplicatesHandler(detector duplicates.Detector) http.Handler {
+	return &DuplicatesHandler{
+		detector: detector,
+	}
+}
+
+// ServeHTTP handles GET requests
+func (h *DuplicatesHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	candidates, err := h.detector.Find(r.Context())
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Failed to find duplicates", "error", err)
+		http.Error(w, "failed to find duplicates", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(candidates); err != nil {
+		slog.WarnContext(r.Context(), "Failed to write duplicates response", "error", err)
+	}
+}
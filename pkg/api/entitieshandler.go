@@ -0,0 +1,57 @@
+package api
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+
+	"github.com/kazemisoroush/assistant/pkg/entities"
+)
+
+// EntitiesPath is the route of the entities endpoint
+const EntitiesPath = "/api/v1/entities"
+
+// EntitiesHandler serves indexed entities and the records naming them
+type EntitiesHandler struct {
+	browser entities.Browser
+}
+
+// NewEntitiesHandler creates a new entities handler
+func NewEntitiesHandler(browser entities.Browser) http.Handler {
+	return &EntitiesHandler{
+		browser: browser,
+	}
+}
+
+// ServeHTTP handles GET requests. With a name query parameter the records
+// naming that entity are returned, otherwise entities of the optional kind.
+func (h *EntitiesHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	query := r.URL.Query()
+	kind, err := entities.ParseKind(query.Get("kind"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var result any
+	if name := query.Get("name"); name != "" {
+		result, err = h.browser.Records(r.Context(), name)
+	} else {
+		result, err = h.browser.Entities(r.Context(), kind)
+	}
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Failed to browse entities", "error", err)
+		http.Error(w, "failed to browse entities", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(result); err != nil {
+		slog.WarnContext(r.Context(), "Failed to write entities response", "error", err)
+	}
+}
@@ -0,0 +1,66 @@
+package api_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kazemisoroush/assistant/pkg/api"
+	"github.com/kazemisoroush/assistant/pkg/entities"
+	"github.com/kazemisoroush/assistant/pkg/entities/mocks"
+	"github.com/kazemisoroush/assistant/pkg/records"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestEntitiesHandler_ServeHTTP_ListsKind(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	browser := mocks.NewMockBrowser(ctrl)
+	browser.EXPECT().Entities(gomock.Any(), entities.KindDoctor).Return([]entities.Summary{
+		{Entity: entities.Entity{Name: "Dr. Smith", Kind: entities.KindDoctor}, Records: 3},
+	}, nil)
+	handler := api.NewEntitiesHandler(browser)
+	rec := httptest.NewRecorder()
+
+	// Act
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, api.EntitiesPath+"?kind=doctor", nil))
+
+	// Assert
+	require.Equal(t, http.StatusOK, rec.Code, "ServeHTTP() should succeed")
+	var summaries []entities.Summary
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &summaries), "ServeHTTP() should return JSON")
+	assert.Equal(t, 3, summaries[0].Records, "ServeHTTP() should return the record counts")
+}
+
+func TestEntitiesHandler_ServeHTTP_RecordsByName(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	browser := mocks.NewMockBrowser(ctrl)
+	browser.EXPECT().Records(gomock.Any(), "Dr. Smith").Return([]records.Record{{ID: "visit-1"}}, nil)
+	handler := api.NewEntitiesHandler(browser)
+	rec := httptest.NewRecorder()
+
+	// Act
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, api.EntitiesPath+"?name=Dr.+Smith", nil))
+
+	// Assert
+	require.Equal(t, http.StatusOK, rec.Code, "ServeHTTP() should succeed")
+	var recs []records.Record
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &recs), "ServeHTTP() should return JSON")
+	assert.Equal(t, "visit-1", recs[0].ID, "ServeHTTP() should return the records naming the entity")
+}
+
+func TestEntitiesHandler_ServeHTTP_UnknownKind(t *testing.T) {
+	// Arrange
+	handler := api.NewEntitiesHandler(mocks.NewMockBrowser(gomock.NewController(t)))
+	rec := httptest.NewRecorder()
+
+	// Act
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, api.EntitiesPath+"?kind=pet", nil))
+
+	// Assert
+	assert.Equal(t, http.StatusBadRequest, rec.Code, "ServeHTTP() should reject an unknown kind")
+}
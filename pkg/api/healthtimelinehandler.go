@@ -0,0 +1,44 @@
+package api
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+
+	"github.com/kazemisoroush/assistant/pkg/health"
+)
+
+// HealthTimelinePath is the route of the health timeline endpoint
+const HealthTimelinePath = "/api/v1/health/timeline"
+
+// HealthTimelineHandler serves chronological health timelines per person
+type HealthTimelineHandler struct {
+	builder health.TimelineBuilder
+}
+
+// NewHealthTimelineHandler creates a new health timeline handler
+func NewHealthTimelineHandler(builder health.TimelineBuilder) http.Handler {
+	return &HealthTimelineHandler{
+		builder: builder,
+	}
+}
+
+// ServeHTTP handles GET requests with an optional person query parameter
+func (h *HealthTimelineHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	timelines, err := h.builder.Timelines(r.Context(), r.URL.Query().Get("person"))
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Failed to build health timeline", "error", err)
+		http.Error(w, "failed to build health timeline", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(timelines); err != nil {
+		slog.WarnContext(r.Context(), "Failed to write health timeline response", "error", err)
+	}
+}
@@ -0,0 +1,51 @@
+package api
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+
+	"github.com/kazemisoroush/assistant/pkg/duplicates"
+)
+
+// MergePath is the route of the duplicate merge endpoint
+const MergePath = "/api/v1/duplicates/merge"
+
+// MergeHandler merges a duplicate record into the record it copies
+type MergeHandler struct {
+	merger duplicates.Merger
+}
+
+// NewMergeHandler creates a new merge handler
+func NewMergeHandler(merger duplicates.Merger) http.Handler {
+	return &MergeHandler{
+		merger: merger,
+	}
+}
+
+// ServeHTTP handles POST requests with a JSON duplicates.Candidate body
+// naming the record to keep and its duplicate
+func (h *MergeHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var candidate duplicates.Candidate
+	if err := json.NewDecoder(r.Body).Decode(&candidate); err != nil || candidate.KeepID == "" || candidate.DuplicateID == "" {
+		http.Error(w, "keep_id and duplicate_id are required", http.StatusBadRequest)
+		return
+	}
+
+	merged, err := h.merger.Merge(r.Context(), candidate.KeepID, candidate.DuplicateID)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Failed to merge records", "keep_id", candidate.KeepID, "duplicate_id", candidate.DuplicateID, "error", err)
+		http.Error(w, "failed to merge records", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(merged); err != nil {
+		slog.WarnContext(r.Context(), "Failed to write merge response", "error", err)
+	}
+}
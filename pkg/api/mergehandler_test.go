@@ -0,0 +1,54 @@
+package api_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/kazemisoroush/assistant/pkg/api"
+	"github.com/kazemisoroush/assistant/pkg/duplicates/mocks"
+	"github.com/kazemisoroush/assistant/pkg/records"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestMergeHandler_ServeHTTP(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	merger := mocks.NewMockMerger(ctrl)
+	merger.EXPECT().Merge(gomock.Any(), "scan", "email").Return(records.Record{ID: "scan"}, nil)
+	handler := api.NewMergeHandler(merger)
+	rec := httptest.NewRecorder()
+
+	// Act
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, api.MergePath, strings.NewReader(`{"keep_id":"scan","duplicate_id":"email"}`)))
+
+	// Assert
+	assert.Equal(t, http.StatusOK, rec.Code, "ServeHTTP() should succeed")
+	assert.Contains(t, rec.Body.String(), `"id":"scan"`, "ServeHTTP() should return the merged record")
+}
+
+func TestMergeHandler_ServeHTTP_MissingDuplicate(t *testing.T) {
+	// Arrange
+	handler := api.NewMergeHandler(mocks.NewMockMerger(gomock.NewController(t)))
+	rec := httptest.NewRecorder()
+
+	// Act
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, api.MergePath, strings.NewReader(`{"keep_id":"scan"}`)))
+
+	// Assert
+	assert.Equal(t, http.StatusBadRequest, rec.Code, "ServeHTTP() should require both records")
+}
+
+func TestMergeHandler_ServeHTTP_RejectsGet(t *testing.T) {
+	// Arrange
+	handler := api.NewMergeHandler(mocks.NewMockMerger(gomock.NewController(t)))
+	rec := httptest.NewRecorder()
+
+	// Act
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, api.MergePath, nil))
+
+	// Assert
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code, "ServeHTTP() should only accept POST")
+}
@@ -0,0 +1,313 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/kazemisoroush/assistant/pkg/records"
+	"github.com/kazemisoroush/assistant/pkg/records/discovery"
+	"github.com/kazemisoroush/assistant/pkg/records/ingestor"
+	"github.com/kazemisoroush/assistant/pkg/records/storage"
+	"github.com/kazemisoroush/assistant/pkg/relations"
+)
+
+const (
+	// RecordsPath is the route of the record collection of the records API
+	RecordsPath = "/api/v1/records"
+
+	// RecordPath is the route pattern of a single record of the records API
+	RecordPath = RecordsPath + "/{id}"
+
+	// defaultSearchLimit is the number of hits a search returns when no limit is given
+	defaultSearchLimit = 10
+
+	// maxSearchLimit bounds the number of hits a search returns
+	maxSearchLimit = 100
+)
+
+// recordSeq keeps IDs of records created in the same nanosecond unique
+var recordSeq atomic.Uint64
+
+// RecordRequest is the body of a request creating or updating a record
+type RecordRequest struct {
+	Type      records.RecordType `json:"type"`
+	Content   string             `json:"content"`
+	Metadata  map[string]any     `json:"metadata,omitempty"`
+	Tags      []string           `json:"tags,omitempty"`
+	ExpiresAt *time.Time         `json:"expires_at,omitempty"`
+}
+
+// RecordsResponse is the body of a response listing records
+type RecordsResponse struct {
+	Records []records.Record `json:"records"`
+	Next    int              `json:"next,omitempty"` // Offset of the next page, 0 on the last page
+}
+
+// SearchHit is a record found by a search
+type SearchHit struct {
+	RecordID    string              `json:"record_id"`
+	Score       float64             `json:"score"`
+	Description string              `json:"description,omitempty"`
+	Meta        map[string]any      `json:"meta,omitempty"`
+	Source      string              `json:"source,omitempty"`
+	Related     []relations.Related `json:"related,omitempty"`
+}
+
+// SearchResponse is the body of a response to a search
+type SearchResponse struct {
+	Query string      `json:"query"`
+	Hits  []SearchHit `json:"hits"`
+}
+
+// RecordsHandler serves the records of the vault. Records are created,
+// updated and deleted through the ingestor, so they are indexed for search
+// like scraped records, and read from the storage. Search hits come with
+// the records related to them.
+type RecordsHandler struct {
+	storage   storage.Storage
+	ingestor  ingestor.Ingestor
+	discovery discovery.Discovery
+	relations relations.Service
+}
+
+// NewRecordsHandler creates a new records handler, to be routed under both
+// RecordsPath and RecordPath
+func NewRecordsHandler(storage storage.Storage, ingestor ingestor.Ingestor, discovery discovery.Discovery, relations relations.Service) http.Handler {
+	return &RecordsHandler{
+		storage:   storage,
+		ingestor:  ingestor,
+		discovery: discovery,
+		relations: relations,
+	}
+}
+
+// ServeHTTP creates, lists and searches records on the collection, and reads,
+// updates and deletes a record by its ID. A q query parameter on the
+// collection searches records instead of listing them.
+func (h *RecordsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if id := r.PathValue("id"); id != "" {
+		h.record(w, r, id)
+		return
+	}
+	switch {
+	case r.Method == http.MethodPost:
+		h.create(w, r)
+	case r.Method == http.MethodGet && r.URL.Query().Has("q"):
+		h.search(w, r)
+	case r.Method == http.MethodGet:
+		h.list(w, r)
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// record reads, updates or deletes a record
+func (h *RecordsHandler) record(w http.ResponseWriter, r *http.Request, id string) {
+	switch r.Method {
+	case http.MethodGet:
+		rec, err := h.storage.Get(r.Context(), id)
+		if err != nil {
+			h.fail(w, r, "get record", err)
+			return
+		}
+		h.write(w, r, http.StatusOK, rec)
+	case http.MethodPut:
+		h.update(w, r, id)
+	case http.MethodDelete:
+		h.delete(w, r, id)
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// create ingests a new record from the request
+func (h *RecordsHandler) create(w http.ResponseWriter, r *http.Request) {
+	req, ok := h.decode(w, r)
+	if !ok {
+		return
+	}
+	now := time.Now().UTC()
+	rec := records.Record{
+		ID:        fmt.Sprintf("api-%d-%d", now.UnixNano(), recordSeq.Add(1)),
+		CreatedAt: now,
+	}
+	req.apply(&rec, now)
+	if err := h.ingest(r, rec); err != nil {
+		h.fail(w, r, "create record", err)
+		return
+	}
+	w.Header().Set("Location", RecordsPath+"/"+rec.ID)
+	h.write(w, r, http.StatusCreated, rec)
+}
+
+// update replaces the fields of an existing record with the request's
+func (h *RecordsHandler) update(w http.ResponseWriter, r *http.Request, id string) {
+	req, ok := h.decode(w, r)
+	if !ok {
+		return
+	}
+	rec, err := h.storage.Get(r.Context(), id)
+	if err != nil {
+		h.fail(w, r, "get record", err)
+		return
+	}
+	req.apply(&rec, time.Now().UTC())
+	if err := h.ingest(r, rec); err != nil {
+		h.fail(w, r, "update record", err)
+		return
+	}
+	h.write(w, r, http.StatusOK, rec)
+}
+
+// delete removes an existing record
+func (h *RecordsHandler) delete(w http.ResponseWriter, r *http.Request, id string) {
+	if _, err := h.storage.Get(r.Context(), id); err != nil {
+		h.fail(w, r, "get record", err)
+		return
+	}
+	if err := h.ingestor.Delete(r.Context(), id); err != nil {
+		h.fail(w, r, "delete record", err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// list serves the records of the optional type query parameter, a page of
+// them when the limit and offset query parameters are given
+func (h *RecordsHandler) list(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	limit, okLimit := count(query.Get("limit"))
+	offset, okOffset := count(query.Get("offset"))
+	if !okLimit || !okOffset {
+		http.Error(w, "limit and offset must not be negative numbers", http.StatusBadRequest)
+		return
+	}
+	recs, err := h.storage.List(r.Context(), records.RecordType(query.Get("type")))
+	if err != nil {
+		h.fail(w, r, "list records", err)
+		return
+	}
+
+	resp := RecordsResponse{Records: recs[min(offset, len(recs)):]}
+	if limit > 0 && len(resp.Records) > limit {
+		resp.Records = resp.Records[:limit]
+		resp.Next = offset + limit
+	}
+	if resp.Records == nil {
+		resp.Records = []records.Record{}
+	}
+	h.write(w, r, http.StatusOK, resp)
+}
+
+// search serves the records matching the q query parameter, at most limit of them
+func (h *RecordsHandler) search(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	if query.Get("q") == "" {
+		http.Error(w, "q must not be empty", http.StatusBadRequest)
+		return
+	}
+	limit := defaultSearchLimit
+	if raw := query.Get("limit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 1 {
+			http.Error(w, "limit must be a positive number", http.StatusBadRequest)
+			return
+		}
+		limit = min(n, maxSearchLimit)
+	}
+
+	resp, err := h.discovery.Discover(r.Context(), discovery.DiscoverRequest{Prompt: query.Get("q"), Limit: limit})
+	if err != nil {
+		h.fail(w, r, "search records", err)
+		return
+	}
+	hits, err := h.hits(r, resp.Hits)
+	if err != nil {
+		h.fail(w, r, "get related records", err)
+		return
+	}
+	h.write(w, r, http.StatusOK, SearchResponse{Query: query.Get("q"), Hits: hits})
+}
+
+// hits returns the search hits of the records found, with the records related
+// to them. Records found but since deleted have none.
+func (h *RecordsHandler) hits(r *http.Request, found []discovery.Hit) ([]SearchHit, error) {
+	hits := make([]SearchHit, 0, len(found))
+	for _, hit := range found {
+		related, err := h.relations.GetRelated(r.Context(), hit.RecordID)
+		if err != nil && !errors.Is(err, storage.ErrNotFound) {
+			return nil, err
+		}
+		hits = append(hits, SearchHit{RecordID: hit.RecordID, Score: hit.Score, Description: hit.Description, Meta: hit.Meta, Source: hit.Source, Related: related})
+	}
+	return hits, nil
+}
+
+// decode reads the record request in the body, answering bad requests
+func (h *RecordsHandler) decode(w http.ResponseWriter, r *http.Request) (RecordRequest, bool) {
+	var req RecordRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRecordBody)).Decode(&req); err != nil {
+		http.Error(w, "invalid record: "+err.Error(), http.StatusBadRequest)
+		return RecordRequest{}, false
+	}
+	if req.Type == "" || req.Content == "" {
+		http.Error(w, "type and content are required", http.StatusBadRequest)
+		return RecordRequest{}, false
+	}
+	return req, true
+}
+
+// ingest stores and indexes the record, so it is found by searches right away
+func (h *RecordsHandler) ingest(r *http.Request, rec records.Record) error {
+	if err := h.ingestor.Ingest(r.Context(), rec); err != nil {
+		return err
+	}
+	return h.ingestor.Flush(r.Context())
+}
+
+// write answers with the body as JSON
+func (h *RecordsHandler) write(w http.ResponseWriter, r *http.Request, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		slog.WarnContext(r.Context(), "Failed to write records response", "error", err)
+	}
+}
+
+// fail reports the error of an action
+func (h *RecordsHandler) fail(w http.ResponseWriter, r *http.Request, action string, err error) {
+	if errors.Is(err, storage.ErrNotFound) {
+		http.Error(w, "record not found", http.StatusNotFound)
+		return
+	}
+	slog.ErrorContext(r.Context(), "Failed to "+action, "error", err)
+	http.Error(w, "failed to "+action, http.StatusInternalServerError)
+}
+
+// count parses an optional non-negative number, 0 when empty
+func count(raw string) (int, bool) {
+	if raw == "" {
+		return 0, true
+	}
+	n, err := strconv.Atoi(raw)
+	return n, err == nil && n >= 0
+}
+
+// apply sets the fields of the record from the request
+func (req RecordRequest) apply(rec *records.Record, now time.Time) {
+	rec.Type = req.Type
+	rec.Content = req.Content
+	rec.Metadata = req.Metadata
+	if rec.Metadata == nil {
+		rec.Metadata = map[string]any{}
+	}
+	rec.Tags = req.Tags
+	rec.ExpiresAt = req.ExpiresAt
+	rec.UpdatedAt = now
+}
@@ -0,0 +1,238 @@
+package api_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/kazemisoroush/assistant/pkg/api"
+	"github.com/kazemisoroush/assistant/pkg/records"
+	"github.com/kazemisoroush/assistant/pkg/records/discovery"
+	discoverymocks "github.com/kazemisoroush/assistant/pkg/records/discovery/mocks"
+	ingestormocks "github.com/kazemisoroush/assistant/pkg/records/ingestor/mocks"
+	"github.com/kazemisoroush/assistant/pkg/records/storage"
+	storagemocks "github.com/kazemisoroush/assistant/pkg/records/storage/mocks"
+	"github.com/kazemisoroush/assistant/pkg/relations"
+	relationsmocks "github.com/kazemisoroush/assistant/pkg/relations/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+// recordsMux routes the records API to the handler
+func recordsMux(handler http.Handler) *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.Handle(api.RecordsPath, handler)
+	mux.Handle(api.RecordPath, handler)
+	return mux
+}
+
+func TestRecordsHandler_ServeHTTP_Create(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	recordIngestor := ingestormocks.NewMockService(ctrl)
+	var ingested records.Record
+	recordIngestor.EXPECT().Ingest(gomock.Any(), gomock.Any()).DoAndReturn(func(_ any, rec records.Record) error {
+		ingested = rec
+		return nil
+	})
+	recordIngestor.EXPECT().Flush(gomock.Any()).Return(nil)
+	mux := recordsMux(api.NewRecordsHandler(storagemocks.NewMockStorage(ctrl), recordIngestor, discoverymocks.NewMockDiscovery(ctrl), relationsmocks.NewMockService(ctrl)))
+	body := `{"type":"receipt","content":"Coffee 4.50 EUR","tags":["coffee"]}`
+	req := httptest.NewRequest(http.MethodPost, api.RecordsPath, strings.NewReader(body))
+	rec := httptest.NewRecorder()
+
+	// Act
+	mux.ServeHTTP(rec, req)
+
+	// Assert
+	require.Equal(t, http.StatusCreated, rec.Code, "status should be 201")
+	var got records.Record
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&got), "failed to decode response")
+	assert.NotEmpty(t, got.ID, "the record should be given an ID")
+	assert.Equal(t, got.ID, ingested.ID, "the response should be the ingested record")
+	assert.Equal(t, records.RecordTypeReceipt, ingested.Type, "the type should be set from the request")
+	assert.Equal(t, []string{"coffee"}, ingested.Tags, "the tags should be set from the request")
+	assert.False(t, ingested.CreatedAt.IsZero(), "the record should be dated")
+	assert.Equal(t, api.RecordsPath+"/"+got.ID, rec.Header().Get("Location"), "the location should address the record")
+}
+
+func TestRecordsHandler_ServeHTTP_Update(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	recordStorage := storagemocks.NewMockStorage(ctrl)
+	recordIngestor := ingestormocks.NewMockService(ctrl)
+	existing := records.Record{ID: "r1", Type: records.RecordTypeReceipt, Content: "Coffee 4.50 EUR"}
+	recordStorage.EXPECT().Get(gomock.Any(), "r1").Return(existing, nil)
+	var ingested records.Record
+	recordIngestor.EXPECT().Ingest(gomock.Any(), gomock.Any()).DoAndReturn(func(_ any, rec records.Record) error {
+		ingested = rec
+		return nil
+	})
+	recordIngestor.EXPECT().Flush(gomock.Any()).Return(nil)
+	mux := recordsMux(api.NewRecordsHandler(recordStorage, recordIngestor, discoverymocks.NewMockDiscovery(ctrl), relationsmocks.NewMockService(ctrl)))
+	req := httptest.NewRequest(http.MethodPut, api.RecordsPath+"/r1", strings.NewReader(`{"type":"receipt","content":"Coffee 5.00 EUR"}`))
+	rec := httptest.NewRecorder()
+
+	// Act
+	mux.ServeHTTP(rec, req)
+
+	// Assert
+	require.Equal(t, http.StatusOK, rec.Code, "status should be 200")
+	assert.Equal(t, "r1", ingested.ID, "the record should keep its ID")
+	assert.Equal(t, "Coffee 5.00 EUR", ingested.Content, "the content should be replaced")
+}
+
+func TestRecordsHandler_ServeHTTP_Search(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	recordDiscovery := discoverymocks.NewMockDiscovery(ctrl)
+	recordDiscovery.EXPECT().Discover(gomock.Any(), discovery.DiscoverRequest{Prompt: "coffee", Limit: 5}).
+		Return(discovery.DiscoverResponse{Hits: []discovery.Hit{{RecordID: "r1", Score: 0.9, Source: "vector"}}}, nil)
+	recordRelations := relationsmocks.NewMockService(ctrl)
+	recordRelations.EXPECT().GetRelated(gomock.Any(), "r1").
+		Return([]relations.Related{{Relation: relations.Relation{FromID: "r1", ToID: "r2", Kind: "belongs_to"}, Record: records.Record{ID: "r2"}}}, nil)
+	mux := recordsMux(api.NewRecordsHandler(storagemocks.NewMockStorage(ctrl), ingestormocks.NewMockService(ctrl), recordDiscovery, recordRelations))
+	req := httptest.NewRequest(http.MethodGet, api.RecordsPath+"?q=coffee&limit=5", nil)
+	rec := httptest.NewRecorder()
+
+	// Act
+	mux.ServeHTTP(rec, req)
+
+	// Assert
+	require.Equal(t, http.StatusOK, rec.Code, "status should be 200")
+	var got api.SearchResponse
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&got), "failed to decode response")
+	require.Len(t, got.Hits, 1, "the hits should be returned")
+	assert.Equal(t, "r1", got.Hits[0].RecordID, "the hit should identify the record")
+	require.Len(t, got.Hits[0].Related, 1, "the hit should come with its related records")
+	assert.Equal(t, "r2", got.Hits[0].Related[0].Record.ID, "the related record should be returned")
+}
+
+func TestRecordsHandler_ServeHTTP_Errors(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		target string
+		body   string
+		status int
+	}{
+		{name: "missing record", method: http.MethodGet, target: api.RecordsPath + "/missing", status: http.StatusNotFound},
+		{name: "delete missing record", method: http.MethodDelete, target: api.RecordsPath + "/missing", status: http.StatusNotFound},
+		{name: "missing content", method: http.MethodPost, target: api.RecordsPath, body: `{"type":"receipt"}`, status: http.StatusBadRequest},
+		{name: "malformed body", method: http.MethodPost, target: api.RecordsPath, body: `{`, status: http.StatusBadRequest},
+		{name: "bad limit", method: http.MethodGet, target: api.RecordsPath + "?q=coffee&limit=0", status: http.StatusBadRequest},
+		{name: "unsupported method", method: http.MethodPatch, target: api.RecordsPath, status: http.StatusMethodNotAllowed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			ctrl := gomock.NewController(t)
+			recordStorage := storagemocks.NewMockStorage(ctrl)
+			recordStorage.EXPECT().Get(gomock.Any(), "missing").Return(records.Record{}, storage.ErrNotFound).AnyTimes()
+			mux := recordsMux(api.NewRecordsHandler(recordStorage, ingestormocks.NewMockService(ctrl), discoverymocks.NewMockDiscovery(ctrl), relationsmocks.NewMockService(ctrl)))
+			req := httptest.NewRequest(tt.method, tt.target, strings.NewReader(tt.body))
+			rec := httptest.NewRecorder()
+
+			// Act
+			mux.ServeHTTP(rec, req)
+
+			// Assert
+			assert.Equal(t, tt.status, rec.Code, "status should match")
+		})
+	}
+}
+
+func TestRequireToken(t *testing.T) {
+	tests := []struct {
+		name          string
+		token         string
+		authorization string
+		status        int
+	}{
+		{name: "valid token", token: "secret", authorization: "Bearer secret", status: http.StatusNoContent},
+		{name: "wrong token", token: "secret", authorization: "Bearer wrong", status: http.StatusUnauthorized},
+		{name: "missing token", token: "secret", status: http.StatusUnauthorized},
+		{name: "no token configured", authorization: "Bearer ", status: http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			handler := api.RequireToken(tt.token, http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(http.StatusNoContent)
+			}))
+			req := httptest.NewRequest(http.MethodGet, api.RecordsPath, nil)
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			rec := httptest.NewRecorder()
+
+			// Act
+			handler.ServeHTTP(rec, req)
+
+			// Assert
+			assert.Equal(t, tt.status, rec.Code, "status should match")
+		})
+	}
+}
+
+func TestRequireFeedToken(t *testing.T) {
+	tests := []struct {
+		name          string
+		token         string
+		target        string
+		authorization string
+		status        int
+	}{
+		{name: "query token", token: "secret", target: api.CalendarPath + "?token=secret", status: http.StatusNoContent},
+		{name: "bearer token", token: "secret", target: api.CalendarPath, authorization: "Bearer secret", status: http.StatusNoContent},
+		{name: "wrong bearer token", token: "secret", target: api.CalendarPath + "?token=secret", authorization: "Bearer wrong", status: http.StatusUnauthorized},
+		{name: "wrong query token", token: "secret", target: api.CalendarPath + "?token=wrong", status: http.StatusUnauthorized},
+		{name: "no token configured", target: api.CalendarPath + "?token=", status: http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			handler := api.RequireFeedToken(tt.token, http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(http.StatusNoContent)
+			}))
+			req := httptest.NewRequest(http.MethodGet, tt.target, nil)
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			rec := httptest.NewRecorder()
+
+			// Act
+			handler.ServeHTTP(rec, req)
+
+			// Assert
+			assert.Equal(t, tt.status, rec.Code, "status should match")
+		})
+	}
+}
+
+func TestRecordsHandler_ServeHTTP_ListPage(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	recordStorage := storagemocks.NewMockStorage(ctrl)
+	recordStorage.EXPECT().List(gomock.Any(), records.RecordTypeReceipt).Return([]records.Record{{ID: "r1"}, {ID: "r2"}, {ID: "r3"}}, nil).Times(2)
+	mux := recordsMux(api.NewRecordsHandler(recordStorage, ingestormocks.NewMockService(ctrl), discoverymocks.NewMockDiscovery(ctrl), relationsmocks.NewMockService(ctrl)))
+
+	// Act
+	first := httptest.NewRecorder()
+	mux.ServeHTTP(first, httptest.NewRequest(http.MethodGet, api.RecordsPath+"?type=receipt&limit=2", nil))
+	last := httptest.NewRecorder()
+	mux.ServeHTTP(last, httptest.NewRequest(http.MethodGet, api.RecordsPath+"?type=receipt&limit=2&offset=2", nil))
+
+	// Assert
+	var firstPage, lastPage api.RecordsResponse
+	require.NoError(t, json.NewDecoder(first.Body).Decode(&firstPage), "failed to decode response")
+	require.NoError(t, json.NewDecoder(last.Body).Decode(&lastPage), "failed to decode response")
+	assert.Len(t, firstPage.Records, 2, "the first page should hold limit records")
+	assert.Equal(t, 2, firstPage.Next, "the first page should point at the next")
+	require.Len(t, lastPage.Records, 1, "the last page should hold the rest")
+	assert.Equal(t, "r3", lastPage.Records[0].ID, "the last page should start at the offset")
+	assert.Zero(t, lastPage.Next, "the last page should not point at another")
+}
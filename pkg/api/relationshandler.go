@@ -0,0 +1,113 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/kazemisoroush/assistant/pkg/records/storage"
+	"github.com/kazemisoroush/assistant/pkg/relations"
+)
+
+const (
+	// RelationsPath is the route pattern of the relations of a record
+	RelationsPath = RecordPath + "/relations"
+
+	// RelationPath is the route pattern of the relation from a record to another
+	RelationPath = RelationsPath + "/{to}"
+)
+
+// RelationRequest is the body of a request linking a record to another
+type RelationRequest struct {
+	ToID string `json:"to_id"`
+	Kind string `json:"kind,omitempty"`
+}
+
+// RelationsResponse is the body of a response listing the records related to one
+type RelationsResponse struct {
+	Related []relations.Related `json:"related"`
+}
+
+// RelationsHandler links records to each other
+type RelationsHandler struct {
+	relations relations.Service
+}
+
+// NewRelationsHandler creates a new relations handler, to be routed under
+// both RelationsPath and RelationPath
+func NewRelationsHandler(relations relations.Service) http.Handler {
+	return &RelationsHandler{
+		relations: relations,
+	}
+}
+
+// ServeHTTP lists the records related to a record and links it to another
+// on its relations, and unlinks it from another by the other's ID
+func (h *RelationsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	if to := r.PathValue("to"); to != "" {
+		if r.Method != http.MethodDelete {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		if err := h.relations.Unlink(r.Context(), id, to); err != nil {
+			h.fail(w, r, "unlink records", err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	switch r.Method {
+	case http.MethodGet:
+		related, err := h.relations.GetRelated(r.Context(), id)
+		if err != nil {
+			h.fail(w, r, "get related records", err)
+			return
+		}
+		h.write(w, r, http.StatusOK, RelationsResponse{Related: related})
+	case http.MethodPost:
+		h.link(w, r, id)
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// link links the record to the one named in the body
+func (h *RelationsHandler) link(w http.ResponseWriter, r *http.Request, id string) {
+	var req RelationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.ToID == "" {
+		http.Error(w, "to_id is required", http.StatusBadRequest)
+		return
+	}
+	relation, err := h.relations.Link(r.Context(), id, req.ToID, req.Kind)
+	if err != nil {
+		h.fail(w, r, "link records", err)
+		return
+	}
+	h.write(w, r, http.StatusCreated, relation)
+}
+
+// write answers with the body as JSON
+func (h *RelationsHandler) write(w http.ResponseWriter, r *http.Request, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		slog.WarnContext(r.Context(), "Failed to write relations response", "error", err)
+	}
+}
+
+// fail reports the error of an action
+func (h *RelationsHandler) fail(w http.ResponseWriter, r *http.Request, action string, err error) {
+	switch {
+	case errors.Is(err, relations.ErrSelfLink):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, storage.ErrNotFound):
+		http.Error(w, "record not found", http.StatusNotFound)
+	case errors.Is(err, relations.ErrNotFound):
+		http.Error(w, "relation not found", http.StatusNotFound)
+	default:
+		slog.ErrorContext(r.Context(), "Failed to "+action, "error", err)
+		http.Error(w, "failed to "+action, http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,106 @@
+package api_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/kazemisoroush/assistant/pkg/api"
+	"github.com/kazemisoroush/assistant/pkg/records/storage"
+	"github.com/kazemisoroush/assistant/pkg/relations"
+	relationsmocks "github.com/kazemisoroush/assistant/pkg/relations/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+// relationsMux routes the relations API to the handler
+func relationsMux(handler http.Handler) *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.Handle(api.RelationsPath, handler)
+	mux.Handle(api.RelationPath, handler)
+	return mux
+}
+
+func TestRelationsHandler_ServeHTTP_Link(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	service := relationsmocks.NewMockService(ctrl)
+	service.EXPECT().Link(gomock.Any(), "receipt", "visit", "belongs_to").
+		Return(relations.Relation{FromID: "receipt", ToID: "visit", Kind: "belongs_to"}, nil)
+	mux := relationsMux(api.NewRelationsHandler(service))
+	req := httptest.NewRequest(http.MethodPost, api.RecordsPath+"/receipt/relations", strings.NewReader(`{"to_id":"visit","kind":"belongs_to"}`))
+	rec := httptest.NewRecorder()
+
+	// Act
+	mux.ServeHTTP(rec, req)
+
+	// Assert
+	require.Equal(t, http.StatusCreated, rec.Code, "status should be 201")
+	var got relations.Relation
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&got), "failed to decode response")
+	assert.Equal(t, "visit", got.ToID, "the response should be the relation linked")
+}
+
+func TestRelationsHandler_ServeHTTP_GetRelatedAndUnlink(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	service := relationsmocks.NewMockService(ctrl)
+	service.EXPECT().GetRelated(gomock.Any(), "receipt").
+		Return([]relations.Related{{Relation: relations.Relation{FromID: "receipt", ToID: "visit"}}}, nil)
+	service.EXPECT().Unlink(gomock.Any(), "receipt", "visit").Return(nil)
+	mux := relationsMux(api.NewRelationsHandler(service))
+
+	// Act
+	listed := httptest.NewRecorder()
+	mux.ServeHTTP(listed, httptest.NewRequest(http.MethodGet, api.RecordsPath+"/receipt/relations", nil))
+	unlinked := httptest.NewRecorder()
+	mux.ServeHTTP(unlinked, httptest.NewRequest(http.MethodDelete, api.RecordsPath+"/receipt/relations/visit", nil))
+
+	// Assert
+	require.Equal(t, http.StatusOK, listed.Code, "status should be 200")
+	var got api.RelationsResponse
+	require.NoError(t, json.NewDecoder(listed.Body).Decode(&got), "failed to decode response")
+	assert.Len(t, got.Related, 1, "the related records should be returned")
+	assert.Equal(t, http.StatusNoContent, unlinked.Code, "status should be 204")
+}
+
+func TestRelationsHandler_ServeHTTP_Errors(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		target string
+		body   string
+		err    error
+		status int
+	}{
+		{name: "missing to_id", method: http.MethodPost, target: "/receipt/relations", body: `{}`, status: http.StatusBadRequest},
+		{name: "self link", method: http.MethodPost, target: "/receipt/relations", body: `{"to_id":"receipt"}`, err: relations.ErrSelfLink, status: http.StatusBadRequest},
+		{name: "missing record", method: http.MethodGet, target: "/missing/relations", err: storage.ErrNotFound, status: http.StatusNotFound},
+		{name: "missing relation", method: http.MethodDelete, target: "/receipt/relations/visit", err: relations.ErrNotFound, status: http.StatusNotFound},
+		{name: "unsupported method", method: http.MethodPut, target: "/receipt/relations/visit", status: http.StatusMethodNotAllowed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			ctrl := gomock.NewController(t)
+			service := relationsmocks.NewMockService(ctrl)
+			if tt.err != nil {
+				service.EXPECT().Link(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(relations.Relation{}, tt.err).AnyTimes()
+				service.EXPECT().GetRelated(gomock.Any(), gomock.Any()).Return(nil, tt.err).AnyTimes()
+				service.EXPECT().Unlink(gomock.Any(), gomock.Any(), gomock.Any()).Return(tt.err).AnyTimes()
+			}
+			mux := relationsMux(api.NewRelationsHandler(service))
+			req := httptest.NewRequest(tt.method, api.RecordsPath+tt.target, strings.NewReader(tt.body))
+			rec := httptest.NewRecorder()
+
+			// Act
+			mux.ServeHTTP(rec, req)
+
+			// Assert
+			assert.Equal(t, tt.status, rec.Code, "status should match")
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package api
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/kazemisoroush/assistant/pkg/analytics"
+)
+
+// SpendPath is the route of the spend analytics endpoint
+const SpendPath = "/api/v1/analytics/spend"
+
+// SpendHandler serves spend per month, category and vendor for charting
+type SpendHandler struct {
+	analyzer analytics.Analyzer
+}
+
+// NewSpendHandler creates a new spend handler
+func NewSpendHandler(analyzer analytics.Analyzer) http.Handler {
+	return &SpendHandler{
+		analyzer: analyzer,
+	}
+}
+
+// ServeHTTP handles GET requests with optional year, category, vendor and person query parameters
+func (h *SpendHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	query := r.URL.Query()
+	filter := analytics.SpendFilter{
+		Category: query.Get("category"),
+		Vendor:   query.Get("vendor"),
+		Person:   query.Get("person"),
+	}
+	if raw := query.Get("year"); raw != "" {
+		year, err := strconv.Atoi(raw)
+		if err != nil || year <= 0 {
+			http.Error(w, "year must be a positive integer", http.StatusBadRequest)
+			return
+		}
+		filter.Year = year
+	}
+
+	report, err := h.analyzer.Spend(r.Context(), filter)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Failed to report spend", "error", err)
+		http.Error(w, "failed to report spend", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(report); err != nil {
+		slog.WarnContext(r.Context(), "Failed to write spend response", "error", err)
+	}
+}
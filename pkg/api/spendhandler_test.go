@@ -0,0 +1,47 @@
+package api_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kazemisoroush/assistant/pkg/analytics"
+	"github.com/kazemisoroush/assistant/pkg/analytics/mocks"
+	"github.com/kazemisoroush/assistant/pkg/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestSpendHandler_ServeHTTP(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	analyzer := mocks.NewMockAnalyzer(ctrl)
+	analyzer.EXPECT().Spend(gomock.Any(), analytics.SpendFilter{Year: 2024, Category: "groceries"}).Return(analytics.SpendReport{
+		Months: []analytics.Bucket{{Key: "2024-01", Currency: "EUR", Total: 50, Count: 2}},
+	}, nil)
+	handler := api.NewSpendHandler(analyzer)
+	rec := httptest.NewRecorder()
+
+	// Act
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, api.SpendPath+"?year=2024&category=groceries", nil))
+
+	// Assert
+	require.Equal(t, http.StatusOK, rec.Code, "ServeHTTP() should succeed")
+	var report analytics.SpendReport
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &report), "ServeHTTP() should return JSON")
+	assert.Len(t, report.Months, 1, "ServeHTTP() should return the time series")
+}
+
+func TestSpendHandler_ServeHTTP_InvalidYear(t *testing.T) {
+	// Arrange
+	handler := api.NewSpendHandler(mocks.NewMockAnalyzer(gomock.NewController(t)))
+	rec := httptest.NewRecorder()
+
+	// Act
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, api.SpendPath+"?year=last", nil))
+
+	// Assert
+	assert.Equal(t, http.StatusBadRequest, rec.Code, "ServeHTTP() should reject a non-numeric year")
+}
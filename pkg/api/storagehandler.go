@@ -0,0 +1,199 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/kazemisoroush/assistant/pkg/records"
+	"github.com/kazemisoroush/assistant/pkg/records/storage"
+)
+
+const (
+	// StoragePath is the route of the record collection of the storage API
+	StoragePath = storage.RemoteRecordsPath
+
+	// StorageRecordPath is the route pattern of a single record of the storage API
+	StorageRecordPath = StoragePath + "/{id}"
+
+	// maxRecordBody bounds the size of a stored record
+	maxRecordBody = 32 << 20
+
+	// maxBatchBody bounds the size of a batch of stored records
+	maxBatchBody = 256 << 20
+)
+
+// StorageHandler serves a storage to clients of the remote storage backend.
+// Records are stored as clients send them; clients encrypting records keep
+// the server from reading them. Clients authenticate with the shared token
+// as a bearer token, checked by RequireToken in front of the handler.
+type StorageHandler struct {
+	storage storage.Storage
+}
+
+// NewStorageHandler creates a new storage handler, to be routed under both
+// StoragePath and StorageRecordPath.
+func NewStorageHandler(storage storage.Storage) http.Handler {
+	return &StorageHandler{
+		storage: storage,
+	}
+}
+
+// ServeHTTP lists, searches, stores, reads, updates and deletes records.
+// PUT on the collection stores a JSON array of records in one write.
+func (h *StorageHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if id := r.PathValue("id"); id != "" {
+		h.record(w, r, id)
+		return
+	}
+	switch r.Method {
+	case http.MethodGet:
+		h.list(w, r)
+	case http.MethodPost:
+		h.write(w, r, "", h.storage.Store)
+	case http.MethodPut:
+		h.writeBatch(w, r)
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// record reads, updates or deletes a record
+func (h *StorageHandler) record(w http.ResponseWriter, r *http.Request, id string) {
+	switch r.Method {
+	case http.MethodGet:
+		h.get(w, r, id)
+	case http.MethodPut:
+		h.write(w, r, id, h.storage.Update)
+	case http.MethodDelete:
+		h.respond(w, r, "delete record", h.storage.Delete(r.Context(), id))
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// list streams the records of the type, or expiring by the expiring_until
+// query parameter, or carrying the tag query parameter, one JSON document per
+// line. Requests with a q query parameter are searches.
+func (h *StorageHandler) list(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Query().Has("q") {
+		h.search(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	enc := json.NewEncoder(w)
+
+	if until := r.URL.Query().Get("expiring_until"); until != "" {
+		t, err := time.Parse(time.RFC3339, until)
+		if err != nil {
+			http.Error(w, "expiring_until must be an RFC 3339 time", http.StatusBadRequest)
+			return
+		}
+		recs, err := h.storage.ListExpiring(r.Context(), t)
+		h.encodeAll(w, r, enc, "list expiring records", recs, err)
+		return
+	}
+	if tag := r.URL.Query().Get("tag"); tag != "" {
+		recs, err := h.storage.ListByTag(r.Context(), tag)
+		h.encodeAll(w, r, enc, "list tagged records", recs, err)
+		return
+	}
+
+	err := h.storage.Each(r.Context(), records.RecordType(r.URL.Query().Get("type")), func(rec records.Record) error {
+		return enc.Encode(rec)
+	})
+	if err != nil {
+		// The status was sent with the first record, so the stream is cut
+		// short for the client to fail instead of missing records
+		slog.ErrorContext(r.Context(), "Failed to list records", "error", err)
+		panic(http.ErrAbortHandler)
+	}
+}
+
+// search streams the results of searching for the q query parameter, filtered
+// by the type and tag query parameters, one JSON document per line
+func (h *StorageHandler) search(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	limit := 0
+	if value := query.Get("limit"); value != "" {
+		var err error
+		if limit, err = strconv.Atoi(value); err != nil {
+			http.Error(w, "limit must be a number", http.StatusBadRequest)
+			return
+		}
+	}
+	filter := storage.SearchFilter{Type: records.RecordType(query.Get("type")), Tag: query.Get("tag")}
+	results, err := h.storage.Search(r.Context(), query.Get("q"), filter, limit)
+	if err != nil {
+		h.respond(w, r, "search records", err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	enc := json.NewEncoder(w)
+	for _, result := range results {
+		_ = enc.Encode(result)
+	}
+}
+
+// encodeAll writes the records of a list, or the error listing them
+func (h *StorageHandler) encodeAll(w http.ResponseWriter, r *http.Request, enc *json.Encoder, action string, recs []records.Record, err error) {
+	if err != nil {
+		h.respond(w, r, action, err)
+		return
+	}
+	for _, rec := range recs {
+		_ = enc.Encode(rec)
+	}
+}
+
+// get serves a record
+func (h *StorageHandler) get(w http.ResponseWriter, r *http.Request, id string) {
+	rec, err := h.storage.Get(r.Context(), id)
+	if err != nil {
+		h.respond(w, r, "get record", err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(rec); err != nil {
+		slog.WarnContext(r.Context(), "Failed to write record response", "error", err)
+	}
+}
+
+// write stores or updates the record in the body, which must carry the ID in the path, if any
+func (h *StorageHandler) write(w http.ResponseWriter, r *http.Request, id string, fn func(ctx context.Context, rec records.Record) error) {
+	var rec records.Record
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRecordBody)).Decode(&rec); err != nil || rec.ID == "" || (id != "" && rec.ID != id) {
+		http.Error(w, "a record with a matching id is required", http.StatusBadRequest)
+		return
+	}
+	h.respond(w, r, "write record", fn(r.Context(), rec))
+}
+
+// writeBatch stores the records of the JSON array in the body in one write
+func (h *StorageHandler) writeBatch(w http.ResponseWriter, r *http.Request) {
+	var recs []records.Record
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBatchBody)).Decode(&recs); err != nil ||
+		slices.ContainsFunc(recs, func(rec records.Record) bool { return rec.ID == "" }) {
+		http.Error(w, "an array of records with ids is required", http.StatusBadRequest)
+		return
+	}
+	h.respond(w, r, "write records", h.storage.StoreBatch(r.Context(), recs))
+}
+
+// respond reports the error of an action, if any, and otherwise answers with no content
+func (h *StorageHandler) respond(w http.ResponseWriter, r *http.Request, action string, err error) {
+	switch {
+	case err == nil:
+		w.WriteHeader(http.StatusNoContent)
+	case errors.Is(err, storage.ErrNotFound):
+		http.Error(w, "record not found", http.StatusNotFound)
+	default:
+		slog.ErrorContext(r.Context(), "Failed to "+action, "error", err)
+		http.Error(w, "failed to "+action, http.StatusInternalServerError)
+	}
+}
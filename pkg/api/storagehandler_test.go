@@ -0,0 +1,126 @@
+package api_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/kazemisoroush/assistant/pkg/api"
+	"github.com/kazemisoroush/assistant/pkg/keys"
+	keymocks "github.com/kazemisoroush/assistant/pkg/keys/mocks"
+	"github.com/kazemisoroush/assistant/pkg/records"
+	"github.com/kazemisoroush/assistant/pkg/records/storage"
+	storagemocks "github.com/kazemisoroush/assistant/pkg/records/storage/mocks"
+	"github.com/kazemisoroush/assistant/pkg/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+// openDB opens the database at path, closed once the test is done
+func openDB(t testing.TB, path string) *sqlite.DB {
+	t.Helper()
+	db, err := sqlite.Open(path)
+	require.NoError(t, err, "Open() error should be nil")
+	t.Cleanup(func() { _ = db.Close() })
+	return db
+}
+
+// serveStorage serves the storage API of the storage
+func serveStorage(t *testing.T, recordStorage storage.Storage) *httptest.Server {
+	t.Helper()
+	handler := api.RequireToken("secret", api.NewStorageHandler(recordStorage))
+	mux := http.NewServeMux()
+	mux.Handle(api.StoragePath, handler)
+	mux.Handle(api.StorageRecordPath, handler)
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestStorageHandler_EncryptedClient(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	serverStorage, err := storage.NewSQLiteStorage(openDB(t, filepath.Join(t.TempDir(), "server.db")))
+	require.NoError(t, err, "failed to create storage")
+	server := serveStorage(t, serverStorage)
+	ctrl := gomock.NewController(t)
+	keyManager := keymocks.NewMockKeyManager(ctrl)
+	keyManager.EXPECT().Key(gomock.Any()).Return(bytes.Repeat([]byte{7}, keys.KeySize), nil)
+	keyManager.EXPECT().Previous(gomock.Any()).Return(nil, keys.ErrKeyNotFound)
+	client := storage.NewEncryptingStorage(storage.NewRemoteStorage(server.Client(), server.URL, "secret"), keyManager)
+	expires := time.Date(2030, 1, 31, 0, 0, 0, 0, time.UTC)
+	now := time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC)
+	passport := records.Record{ID: "passport", Type: records.RecordTypeOther, Content: "Passport X1234567", CreatedAt: now, UpdatedAt: now, ExpiresAt: &expires, Metadata: map[string]interface{}{"holder": "Sam"}}
+	receipt := records.Record{ID: "receipt", Type: records.RecordTypeReceipt, Content: "Coffee 4.50 EUR", CreatedAt: now, UpdatedAt: now, Tags: []string{"food"}}
+
+	// Act
+	require.NoError(t, client.Store(ctx, passport), "Store() error should be nil")
+	require.NoError(t, client.Store(ctx, receipt), "Store() error should be nil")
+	receipt.Content = "Coffee 5.00 EUR"
+	require.NoError(t, client.Update(ctx, receipt), "Update() error should be nil")
+	receipts, err := client.List(ctx, records.RecordTypeReceipt)
+	require.NoError(t, err, "List() error should be nil")
+	expiring, err := client.ListExpiring(ctx, expires)
+	require.NoError(t, err, "ListExpiring() error should be nil")
+	tagged, err := client.ListByTag(ctx, "food")
+	require.NoError(t, err, "ListByTag() error should be nil")
+	got, err := client.Get(ctx, "passport")
+	require.NoError(t, err, "Get() error should be nil")
+	require.NoError(t, client.Delete(ctx, "receipt"), "Delete() error should be nil")
+	_, missingErr := client.Get(ctx, "receipt")
+
+	// Assert
+	require.Len(t, receipts, 1, "List() should filter records by type on the server")
+	assert.Equal(t, "Coffee 5.00 EUR", receipts[0].Content, "List() should return the decrypted update")
+	require.Len(t, expiring, 1, "ListExpiring() should filter records by expiry on the server")
+	require.Len(t, tagged, 1, "ListByTag() should filter the opened records by tag")
+	assert.Equal(t, "receipt", tagged[0].ID, "ListByTag() should return the tagged record")
+	assert.Equal(t, "Passport X1234567", got.Content, "Get() should decrypt the content")
+	assert.Equal(t, "Sam", got.Metadata["holder"], "Get() should decrypt the metadata")
+	assert.True(t, errors.Is(missingErr, storage.ErrNotFound), "Delete() should remove the record on the server")
+	raw, err := serverStorage.Get(ctx, "passport")
+	require.NoError(t, err, "failed to read server storage")
+	assert.NotContains(t, raw.Content, "X1234567", "the server should only store ciphertext")
+	assert.Empty(t, raw.Metadata, "the server should only store ciphertext")
+}
+
+func TestStorageHandler_ServeHTTP_Rejected(t *testing.T) {
+	tests := []struct {
+		name     string
+		token    string
+		method   string
+		target   string
+		header   string
+		body     string
+		wantCode int
+	}{
+		{name: "wrong token", token: "secret", method: http.MethodGet, target: api.StoragePath, header: "Bearer guess", wantCode: http.StatusUnauthorized},
+		{name: "no token configured", method: http.MethodGet, target: api.StoragePath, header: "Bearer ", wantCode: http.StatusUnauthorized},
+		{name: "mismatched id", token: "secret", method: http.MethodPut, target: api.StoragePath + "/a", header: "Bearer secret", body: `{"id":"b"}`, wantCode: http.StatusBadRequest},
+		{name: "wrong method", token: "secret", method: http.MethodPatch, target: api.StoragePath + "/a", header: "Bearer secret", wantCode: http.StatusMethodNotAllowed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			handler := api.RequireToken(tt.token, api.NewStorageHandler(storagemocks.NewMockStorage(gomock.NewController(t))))
+			mux := http.NewServeMux()
+			mux.Handle(api.StoragePath, handler)
+			mux.Handle(api.StorageRecordPath, handler)
+			req := httptest.NewRequest(tt.method, tt.target, bytes.NewBufferString(tt.body))
+			req.Header.Set("Authorization", tt.header)
+			rec := httptest.NewRecorder()
+
+			// Act
+			mux.ServeHTTP(rec, req)
+
+			// Assert
+			assert.Equal(t, tt.wantCode, rec.Code, "ServeHTTP() should reject the request")
+		})
+	}
+}
@@ -0,0 +1,45 @@
+package api
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/kazemisoroush/assistant/pkg/analytics"
+)
+
+// SubscriptionsPath is the route of the subscriptions endpoint
+const SubscriptionsPath = "/api/v1/analytics/subscriptions"
+
+// SubscriptionsHandler serves detected recurring bills and subscriptions
+type SubscriptionsHandler struct {
+	detector analytics.SubscriptionDetector
+}
+
+// NewSubscriptionsHandler creates a new subscriptions handler
+func NewSubscriptionsHandler(detector analytics.SubscriptionDetector) http.Handler {
+	return &SubscriptionsHandler{
+		detector: detector,
+	}
+}
+
+// ServeHTTP handles GET requests
+func (h *SubscriptionsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	report, err := h.detector.Subscriptions(r.Context(), time.Now())
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Failed to detect subscriptions", "error", err)
+		http.Error(w, "failed to detect subscriptions", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(report); err != nil {
+		slog.WarnContext(r.Context(), "Failed to write subscriptions response", "error", err)
+	}
+}
@@ -0,0 +1,97 @@
+package api
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/kazemisoroush/assistant/pkg/peersync"
+)
+
+// SyncPath is the route of the sync endpoint
+const SyncPath = peersync.ChangesPath
+
+const (
+	// defaultSyncLimit is how many changes are served when the request sets no limit
+	defaultSyncLimit = peersync.DefaultBatchSize
+
+	// maxSyncLimit bounds how many changes are served at once
+	maxSyncLimit = 1000
+
+	// maxSyncBody bounds the size of the changes a peer pushes at once
+	maxSyncBody = 64 << 20
+)
+
+// SyncHandler serves the changes logged by this instance to its peers and
+// applies theirs. Peers authenticate with the shared sync token as a bearer
+// token, checked by RequireToken in front of the handler.
+type SyncHandler struct {
+	replica peersync.Replica
+}
+
+// NewSyncHandler creates a new sync handler
+func NewSyncHandler(replica peersync.Replica) http.Handler {
+	return &SyncHandler{
+		replica: replica,
+	}
+}
+
+// ServeHTTP handles GET requests for the changes after the since query
+// parameter, and POST requests with a JSON peersync.Batch body to apply
+func (h *SyncHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if r.Method == http.MethodGet {
+		h.changes(w, r)
+		return
+	}
+	h.apply(w, r)
+}
+
+// changes serves a batch of changes
+func (h *SyncHandler) changes(w http.ResponseWriter, r *http.Request) {
+	since, err := strconv.ParseInt(r.URL.Query().Get("since"), 10, 64)
+	if err != nil && r.URL.Query().Has("since") {
+		http.Error(w, "since must be a sequence number", http.StatusBadRequest)
+		return
+	}
+	limit, err := strconv.Atoi(r.URL.Query().Get("limit"))
+	if err != nil || limit <= 0 {
+		limit = defaultSyncLimit
+	}
+
+	batch, err := h.replica.Changes(r.Context(), since, min(limit, maxSyncLimit))
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Failed to list changes", "since", since, "error", err)
+		http.Error(w, "failed to list changes", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(batch); err != nil {
+		slog.WarnContext(r.Context(), "Failed to write changes response", "error", err)
+	}
+}
+
+// apply applies a batch of changes pushed by a peer
+func (h *SyncHandler) apply(w http.ResponseWriter, r *http.Request) {
+	var batch peersync.Batch
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxSyncBody)).Decode(&batch); err != nil {
+		http.Error(w, "invalid changes", http.StatusBadRequest)
+		return
+	}
+
+	result, err := h.replica.Apply(r.Context(), batch.Changes)
+	if err != nil {
+		// The peer keeps its cursor and pushes the batch again
+		slog.ErrorContext(r.Context(), "Failed to apply changes", "changes", len(batch.Changes), "error", err)
+		http.Error(w, "failed to apply changes", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(result); err != nil {
+		slog.WarnContext(r.Context(), "Failed to write apply response", "error", err)
+	}
+}
@@ -0,0 +1,82 @@
+package api_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/kazemisoroush/assistant/pkg/api"
+	"github.com/kazemisoroush/assistant/pkg/peersync"
+	"github.com/kazemisoroush/assistant/pkg/peersync/mocks"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestSyncHandler_ServeHTTP_Changes(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	replica := mocks.NewMockReplica(ctrl)
+	replica.EXPECT().Changes(gomock.Any(), int64(12), 1000).Return(peersync.Batch{Changes: []peersync.Change{{Seq: 13, RecordID: "a"}}, Next: 13}, nil)
+	handler := api.RequireToken("secret", api.NewSyncHandler(replica))
+	req := httptest.NewRequest(http.MethodGet, api.SyncPath+"?since=12&limit=5000", nil)
+	req.Header.Set("Authorization", "Bearer secret")
+	rec := httptest.NewRecorder()
+
+	// Act
+	handler.ServeHTTP(rec, req)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, rec.Code, "ServeHTTP() should succeed")
+	assert.Contains(t, rec.Body.String(), `"record_id":"a"`, "ServeHTTP() should return the changes")
+}
+
+func TestSyncHandler_ServeHTTP_Apply(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	replica := mocks.NewMockReplica(ctrl)
+	replica.EXPECT().Apply(gomock.Any(), []peersync.Change{{RecordID: "a", Version: peersync.Version{"laptop": 1}, Deleted: true}}).Return(peersync.Result{Applied: 1}, nil)
+	handler := api.RequireToken("secret", api.NewSyncHandler(replica))
+	req := httptest.NewRequest(http.MethodPost, api.SyncPath, strings.NewReader(`{"changes":[{"record_id":"a","version":{"laptop":1},"deleted":true}]}`))
+	req.Header.Set("Authorization", "Bearer secret")
+	rec := httptest.NewRecorder()
+
+	// Act
+	handler.ServeHTTP(rec, req)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, rec.Code, "ServeHTTP() should succeed")
+	assert.Contains(t, rec.Body.String(), `"applied":1`, "ServeHTTP() should return the result")
+}
+
+func TestSyncHandler_ServeHTTP_Rejected(t *testing.T) {
+	tests := []struct {
+		name     string
+		token    string
+		method   string
+		header   string
+		wantCode int
+	}{
+		{name: "wrong token", token: "secret", method: http.MethodGet, header: "Bearer guess", wantCode: http.StatusUnauthorized},
+		{name: "missing token", token: "secret", method: http.MethodGet, wantCode: http.StatusUnauthorized},
+		{name: "no token configured", method: http.MethodGet, header: "Bearer ", wantCode: http.StatusUnauthorized},
+		{name: "wrong method", token: "secret", method: http.MethodDelete, header: "Bearer secret", wantCode: http.StatusMethodNotAllowed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			handler := api.RequireToken(tt.token, api.NewSyncHandler(mocks.NewMockReplica(gomock.NewController(t))))
+			req := httptest.NewRequest(tt.method, api.SyncPath, nil)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			rec := httptest.NewRecorder()
+
+			// Act
+			handler.ServeHTTP(rec, req)
+
+			// Assert
+			assert.Equal(t, tt.wantCode, rec.Code, "ServeHTTP() should reject the request")
+		})
+	}
+}
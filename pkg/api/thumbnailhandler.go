@@ -0,0 +1,55 @@
+package api
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/kazemisoroush/assistant/pkg/thumbnails"
+)
+
+// ThumbnailPath is the route pattern of the record thumbnail endpoint
+const ThumbnailPath = "/api/v1/records/{id}/thumbnail"
+
+// ThumbnailHandler serves the thumbnail generated for a record at ingest time
+type ThumbnailHandler struct {
+	store thumbnails.Store
+}
+
+// NewThumbnailHandler creates a new thumbnail handler
+func NewThumbnailHandler(store thumbnails.Store) http.Handler {
+	return &ThumbnailHandler{
+		store: store,
+	}
+}
+
+// ServeHTTP handles GET requests for the record ID in the path
+func (h *ThumbnailHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	id := r.PathValue("id")
+	if id == "" {
+		http.Error(w, "record ID is required", http.StatusBadRequest)
+		return
+	}
+
+	thumbnail, err := h.store.Get(r.Context(), id)
+	if errors.Is(err, thumbnails.ErrNotFound) {
+		http.Error(w, "thumbnail not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Failed to get thumbnail", "record_id", id, "error", err)
+		http.Error(w, "failed to get thumbnail", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", thumbnails.MediaType)
+	w.Header().Set("Cache-Control", "private, max-age=86400")
+	if _, err := w.Write(thumbnail); err != nil {
+		slog.WarnContext(r.Context(), "Failed to write thumbnail response", "error", err)
+	}
+}
@@ -0,0 +1,48 @@
+package api_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kazemisoroush/assistant/pkg/api"
+	"github.com/kazemisoroush/assistant/pkg/thumbnails"
+	"github.com/kazemisoroush/assistant/pkg/thumbnails/mocks"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestThumbnailHandler_ServeHTTP(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	store := mocks.NewMockStore(ctrl)
+	store.EXPECT().Get(gomock.Any(), "ocr-1").Return([]byte("jpeg"), nil)
+	mux := http.NewServeMux()
+	mux.Handle(api.ThumbnailPath, api.NewThumbnailHandler(store))
+	rec := httptest.NewRecorder()
+
+	// Act
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/records/ocr-1/thumbnail", nil))
+
+	// Assert
+	assert.Equal(t, http.StatusOK, rec.Code, "ServeHTTP() should succeed")
+	assert.Equal(t, thumbnails.MediaType, rec.Header().Get("Content-Type"), "ServeHTTP() should serve a JPEG")
+	assert.Equal(t, "jpeg", rec.Body.String(), "ServeHTTP() should return the stored thumbnail")
+}
+
+func TestThumbnailHandler_ServeHTTP_NotFound(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	store := mocks.NewMockStore(ctrl)
+	store.EXPECT().Get(gomock.Any(), "ocr-2").Return(nil, fmt.Errorf("%w: ocr-2", thumbnails.ErrNotFound))
+	mux := http.NewServeMux()
+	mux.Handle(api.ThumbnailPath, api.NewThumbnailHandler(store))
+	rec := httptest.NewRecorder()
+
+	// Act
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/records/ocr-2/thumbnail", nil))
+
+	// Assert
+	assert.Equal(t, http.StatusNotFound, rec.Code, "ServeHTTP() should report a missing thumbnail")
+}
@@ -0,0 +1,44 @@
+package api
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+
+	"github.com/kazemisoroush/assistant/pkg/trips"
+)
+
+// TripsPath is the route of the trips endpoint
+const TripsPath = "/api/v1/trips"
+
+// TripsHandler serves travel bookings grouped into trips with their receipts, visas and spend
+type TripsHandler struct {
+	clusterer trips.Clusterer
+}
+
+// NewTripsHandler creates a new trips handler
+func NewTripsHandler(clusterer trips.Clusterer) http.Handler {
+	return &TripsHandler{
+		clusterer: clusterer,
+	}
+}
+
+// ServeHTTP handles GET requests
+func (h *TripsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	found, err := h.clusterer.Trips(r.Context())
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Failed to cluster trips", "error", err)
+		http.Error(w, "failed to cluster trips", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(found); err != nil {
+		slog.WarnContext(r.Context(), "Failed to write trips response", "error", err)
+	}
+}
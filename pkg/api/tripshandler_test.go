@@ -0,0 +1,52 @@
+package api_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kazemisoroush/assistant/pkg/api"
+	"github.com/kazemisoroush/assistant/pkg/trips"
+	"github.com/kazemisoroush/assistant/pkg/trips/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestTripsHandler_ServeHTTP(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	clusterer := mocks.NewMockClusterer(ctrl)
+	clusterer.EXPECT().Trips(gomock.Any()).Return([]trips.Trip{
+		{ID: "flight-1", Destination: "Tokyo", Country: "JP", Spend: map[string]float64{"EUR": 1500}},
+	}, nil)
+	handler := api.NewTripsHandler(clusterer)
+	rec := httptest.NewRecorder()
+
+	// Act
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, api.TripsPath, nil))
+
+	// Assert
+	require.Equal(t, http.StatusOK, rec.Code, "ServeHTTP() should succeed")
+	var found []trips.Trip
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &found), "ServeHTTP() should return JSON")
+	require.Len(t, found, 1, "ServeHTTP() should return the trips")
+	assert.InDelta(t, 1500, found[0].Spend["EUR"], 0.001, "ServeHTTP() should include the trip spend")
+}
+
+func TestTripsHandler_ServeHTTP_Error(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	clusterer := mocks.NewMockClusterer(ctrl)
+	clusterer.EXPECT().Trips(gomock.Any()).Return(nil, errors.New("storage unavailable"))
+	handler := api.NewTripsHandler(clusterer)
+	rec := httptest.NewRecorder()
+
+	// Act
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, api.TripsPath, nil))
+
+	// Assert
+	assert.Equal(t, http.StatusInternalServerError, rec.Code, "ServeHTTP() should report clustering failures")
+}
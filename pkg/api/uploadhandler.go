@@ -0,0 +1,190 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"mime/multipart"
+	"net/http"
+
+	"github.com/kazemisoroush/assistant/pkg/records"
+	"github.com/kazemisoroush/assistant/pkg/records/extractor"
+	"github.com/kazemisoroush/assistant/pkg/records/ingestor"
+)
+
+const (
+	// UploadPath is the route documents are uploaded to
+	UploadPath = RecordsPath + "/upload"
+
+	// maxUploadFiles bounds the number of files of an upload
+	maxUploadFiles = 20
+)
+
+// errUnsupportedUpload is returned for uploaded files that are not images or PDFs
+var errUnsupportedUpload = errors.New("only images and PDFs can be uploaded")
+
+// UploadedRecord is a record extracted from an uploaded file
+type UploadedRecord struct {
+	FileName string             `json:"file_name"`
+	ID       string             `json:"id"`
+	Type     records.RecordType `json:"type"`
+	Metadata map[string]any     `json:"metadata"`
+}
+
+// UploadError is a file of an upload that failed, or the rest of the upload
+// when it has no file name
+type UploadError struct {
+	FileName string `json:"file_name,omitempty"`
+	Error    string `json:"error"`
+}
+
+// UploadResponse is the body of a response to an upload
+type UploadResponse struct {
+	Records []UploadedRecord `json:"records"`
+	Errors  []UploadError    `json:"errors,omitempty"`
+}
+
+// uploadFailure is a failed file of an upload, or the rest of the upload
+// when it has no file name, with the status to answer with
+type uploadFailure struct {
+	fileName string
+	status   int
+	err      error
+}
+
+// UploadHandler extracts records from uploaded images and PDFs and ingests
+// them, like files scraped from the local source
+type UploadHandler struct {
+	extractor extractor.ContentExtractor
+	ingestor  ingestor.Ingestor
+}
+
+// NewUploadHandler creates a new upload handler
+func NewUploadHandler(extractor extractor.ContentExtractor, ingestor ingestor.Ingestor) http.Handler {
+	return &UploadHandler{
+		extractor: extractor,
+		ingestor:  ingestor,
+	}
+}
+
+// ServeHTTP handles POST requests with a multipart body. Every file part is
+// read as it arrives, extracted into a record and ingested. Files failing
+// are reported next to the records of the others; the upload fails when no
+// file was ingested.
+func (h *UploadHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	r.Body = http.MaxBytesReader(w, r.Body, maxUploadFiles*extractor.MaxInputSize)
+	reader, err := r.MultipartReader()
+	if err != nil {
+		http.Error(w, "a multipart body is required", http.StatusBadRequest)
+		return
+	}
+
+	resp, failures := h.receive(r, reader)
+	// Ingested records are indexed in batches; flush so they can be searched right away
+	if err := h.ingestor.Flush(r.Context()); err != nil {
+		h.fail(w, r, uploadFailure{status: http.StatusInternalServerError, err: err})
+		return
+	}
+	if len(resp.Records) == 0 {
+		h.fail(w, r, failures[0])
+		return
+	}
+	for _, failure := range failures {
+		resp.Errors = append(resp.Errors, UploadError{FileName: failure.fileName, Error: h.describe(r, failure)})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		slog.WarnContext(r.Context(), "Failed to write upload response", "error", err)
+	}
+}
+
+// receive ingests the files of the multipart body, returning the records of
+// those ingested and the failures of the others
+func (h *UploadHandler) receive(r *http.Request, reader *multipart.Reader) (UploadResponse, []uploadFailure) {
+	resp := UploadResponse{Records: []UploadedRecord{}}
+	var failures []uploadFailure
+	files := 0
+	for {
+		part, err := reader.NextPart()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return resp, append(failures, uploadFailure{status: http.StatusBadRequest, err: errors.New("invalid multipart body")})
+		}
+		if part.FileName() == "" {
+			continue
+		}
+		if files == maxUploadFiles {
+			return resp, append(failures, uploadFailure{status: http.StatusRequestEntityTooLarge, err: errors.New("too many files")})
+		}
+		files++
+		uploaded, status, err := h.upload(r, part)
+		if err != nil {
+			failures = append(failures, uploadFailure{fileName: part.FileName(), status: status, err: err})
+			continue
+		}
+		resp.Records = append(resp.Records, uploaded)
+	}
+	if files == 0 {
+		failures = append(failures, uploadFailure{status: http.StatusBadRequest, err: errors.New("no file was uploaded")})
+	}
+	return resp, failures
+}
+
+// upload extracts the record of an uploaded file and ingests it, returning
+// the status to answer with when it fails. The media type is detected from
+// the content, as the one the client sends is not trusted.
+func (h *UploadHandler) upload(r *http.Request, part *multipart.Part) (UploadedRecord, int, error) {
+	input, err := extractor.ReaderInput(part, "")
+	if err != nil {
+		return UploadedRecord{}, http.StatusBadRequest, err
+	}
+	if input.Size > extractor.MaxInputSize {
+		return UploadedRecord{}, http.StatusRequestEntityTooLarge, errors.New("file is too large")
+	}
+	if !input.IsImage() && !input.IsPDF() {
+		return UploadedRecord{}, http.StatusUnsupportedMediaType, errUnsupportedUpload
+	}
+
+	rec, err := h.extractor.Extract(r.Context(), input)
+	if err != nil {
+		return UploadedRecord{}, http.StatusUnprocessableEntity, err
+	}
+	if err := h.ingestor.Ingest(r.Context(), rec); err != nil {
+		return UploadedRecord{}, http.StatusInternalServerError, err
+	}
+	return UploadedRecord{FileName: part.FileName(), ID: rec.ID, Type: rec.Type, Metadata: rec.Metadata}, http.StatusOK, nil
+}
+
+// fail answers with the status and description of a failure
+func (h *UploadHandler) fail(w http.ResponseWriter, r *http.Request, failure uploadFailure) {
+	description := h.describe(r, failure)
+	if failure.fileName != "" {
+		description = failure.fileName + ": " + description
+	}
+	http.Error(w, description, failure.status)
+}
+
+// describe returns the description of a failure for the client. Client
+// errors are described; failures of the extraction or the vault are only
+// logged.
+func (h *UploadHandler) describe(r *http.Request, failure uploadFailure) string {
+	switch failure.status {
+	case http.StatusUnprocessableEntity:
+		slog.WarnContext(r.Context(), "Failed to extract upload", "file_name", failure.fileName, "error", failure.err)
+		return "failed to extract a record from the upload"
+	case http.StatusInternalServerError:
+		slog.ErrorContext(r.Context(), "Failed to ingest upload", "file_name", failure.fileName, "error", failure.err)
+		return "failed to ingest upload"
+	default:
+		return failure.err.Error()
+	}
+}
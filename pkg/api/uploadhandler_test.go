@@ -0,0 +1,149 @@
+package api_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/textproto"
+	"testing"
+
+	"github.com/kazemisoroush/assistant/pkg/api"
+	"github.com/kazemisoroush/assistant/pkg/records"
+	"github.com/kazemisoroush/assistant/pkg/records/extractor"
+	extractormocks "github.com/kazemisoroush/assistant/pkg/records/extractor/mocks"
+	ingestormocks "github.com/kazemisoroush/assistant/pkg/records/ingestor/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+// pngHeader is the signature PNG files start with
+var pngHeader = []byte("\x89PNG\r\n\x1a\n")
+
+// multipartUpload builds a multipart body with a file part per name and content
+func multipartUpload(t *testing.T, files map[string][]byte, mediaType string) (*bytes.Buffer, string) {
+	t.Helper()
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	for name, content := range files {
+		header := textproto.MIMEHeader{}
+		header.Set("Content-Disposition", `form-data; name="file"; filename="`+name+`"`)
+		header.Set("Content-Type", mediaType)
+		part, err := writer.CreatePart(header)
+		require.NoError(t, err, "failed to create part")
+		_, err = part.Write(content)
+		require.NoError(t, err, "failed to write part")
+	}
+	require.NoError(t, writer.Close(), "failed to close multipart body")
+	return body, writer.FormDataContentType()
+}
+
+func TestUploadHandler_ServeHTTP(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	contentExtractor := extractormocks.NewMockContentExtractor(ctrl)
+	recordIngestor := ingestormocks.NewMockService(ctrl)
+	extracted := records.Record{ID: "ocr-1", Type: records.RecordTypeReceipt, Metadata: map[string]interface{}{"merchant": "Cafe"}}
+	contentExtractor.EXPECT().Extract(gomock.Any(), gomock.Any()).DoAndReturn(func(_ any, input extractor.Input) (records.Record, error) {
+		assert.Equal(t, "image/png", input.MediaType, "the upload should be passed with its media type")
+		return extracted, nil
+	})
+	recordIngestor.EXPECT().Ingest(gomock.Any(), extracted).Return(nil)
+	recordIngestor.EXPECT().Flush(gomock.Any()).Return(nil)
+	body, contentType := multipartUpload(t, map[string][]byte{"receipt.png": append(pngHeader, 1, 2, 3)}, "application/octet-stream")
+	req := httptest.NewRequest(http.MethodPost, api.UploadPath, body)
+	req.Header.Set("Content-Type", contentType)
+	rec := httptest.NewRecorder()
+
+	// Act
+	api.NewUploadHandler(contentExtractor, recordIngestor).ServeHTTP(rec, req)
+
+	// Assert
+	require.Equal(t, http.StatusCreated, rec.Code, "status should be 201")
+	var got api.UploadResponse
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&got), "failed to decode response")
+	require.Len(t, got.Records, 1, "the uploaded file should be returned")
+	assert.Equal(t, "receipt.png", got.Records[0].FileName, "the file name should be returned")
+	assert.Equal(t, "ocr-1", got.Records[0].ID, "the record ID should be returned")
+	assert.Equal(t, "Cafe", got.Records[0].Metadata["merchant"], "the extracted metadata should be returned")
+}
+
+func TestUploadHandler_ServeHTTP_Rejected(t *testing.T) {
+	tests := []struct {
+		name       string
+		files      map[string][]byte
+		mediaType  string
+		extractErr error
+		status     int
+	}{
+		{name: "no file", files: map[string][]byte{}, mediaType: "image/png", status: http.StatusBadRequest},
+		{name: "text file", files: map[string][]byte{"notes.txt": []byte("hello")}, mediaType: "text/plain", status: http.StatusUnsupportedMediaType},
+		{name: "text sent as an image", files: map[string][]byte{"receipt.png": []byte("hello")}, mediaType: "image/png", status: http.StatusUnsupportedMediaType},
+		{name: "extraction failure", files: map[string][]byte{"scan.pdf": []byte("%PDF-1.4")}, mediaType: "application/pdf", extractErr: errors.New("ocr failed"), status: http.StatusUnprocessableEntity},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			ctrl := gomock.NewController(t)
+			contentExtractor := extractormocks.NewMockContentExtractor(ctrl)
+			if tt.extractErr != nil {
+				contentExtractor.EXPECT().Extract(gomock.Any(), gomock.Any()).Return(records.Record{}, tt.extractErr)
+			}
+			recordIngestor := ingestormocks.NewMockService(ctrl)
+			recordIngestor.EXPECT().Flush(gomock.Any()).Return(nil)
+			body, contentType := multipartUpload(t, tt.files, tt.mediaType)
+			req := httptest.NewRequest(http.MethodPost, api.UploadPath, body)
+			req.Header.Set("Content-Type", contentType)
+			rec := httptest.NewRecorder()
+
+			// Act
+			api.NewUploadHandler(contentExtractor, recordIngestor).ServeHTTP(rec, req)
+
+			// Assert
+			assert.Equal(t, tt.status, rec.Code, "status should match")
+		})
+	}
+}
+
+func TestUploadHandler_ServeHTTP_PartialFailure(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	contentExtractor := extractormocks.NewMockContentExtractor(ctrl)
+	recordIngestor := ingestormocks.NewMockService(ctrl)
+	extracted := records.Record{ID: "ocr-1", Type: records.RecordTypeReceipt}
+	contentExtractor.EXPECT().Extract(gomock.Any(), gomock.Any()).Return(extracted, nil)
+	recordIngestor.EXPECT().Ingest(gomock.Any(), extracted).Return(nil)
+	recordIngestor.EXPECT().Flush(gomock.Any()).Return(nil)
+	body, contentType := multipartUpload(t, map[string][]byte{"receipt.png": pngHeader, "notes.txt": []byte("hello")}, "image/png")
+	req := httptest.NewRequest(http.MethodPost, api.UploadPath, body)
+	req.Header.Set("Content-Type", contentType)
+	rec := httptest.NewRecorder()
+
+	// Act
+	api.NewUploadHandler(contentExtractor, recordIngestor).ServeHTTP(rec, req)
+
+	// Assert
+	require.Equal(t, http.StatusCreated, rec.Code, "status should be 201 when a file was ingested")
+	var got api.UploadResponse
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&got), "failed to decode response")
+	require.Len(t, got.Records, 1, "the ingested file should be returned")
+	assert.Equal(t, "ocr-1", got.Records[0].ID, "the record ID should be returned")
+	assert.Equal(t, []api.UploadError{{FileName: "notes.txt", Error: "only images and PDFs can be uploaded"}}, got.Errors, "the failed file should be reported")
+}
+
+func TestUploadHandler_ServeHTTP_NotMultipart(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	req := httptest.NewRequest(http.MethodPost, api.UploadPath, bytes.NewBufferString("{}"))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+
+	// Act
+	api.NewUploadHandler(extractormocks.NewMockContentExtractor(ctrl), ingestormocks.NewMockService(ctrl)).ServeHTTP(rec, req)
+
+	// Assert
+	assert.Equal(t, http.StatusBadRequest, rec.Code, "status should be 400")
+}
@@ -0,0 +1,62 @@
+// Package api provides the HTTP handlers of the assistant API.
+package api
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/kazemisoroush/assistant/pkg/ai"
+)
+
+const (
+	// UsagePath is the route of the AI usage endpoint
+	UsagePath = "/api/v1/ai/usage"
+
+	// defaultUsageDays is the default number of days covered by the usage report
+	defaultUsageDays = 30
+)
+
+// UsageHandler serves AI usage and estimated cost per day, task and provider
+type UsageHandler struct {
+	store ai.UsageStore
+}
+
+// NewUsageHandler creates a new usage handler
+func NewUsageHandler(store ai.UsageStore) http.Handler {
+	return &UsageHandler{
+		store: store,
+	}
+}
+
+// ServeHTTP handles GET requests with an optional days query parameter
+func (h *UsageHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	days := defaultUsageDays
+	if raw := r.URL.Query().Get("days"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed <= 0 {
+			http.Error(w, "days must be a positive integer", http.StatusBadRequest)
+			return
+		}
+		days = parsed
+	}
+
+	report, err := h.store.Report(r.Context(), time.Now().AddDate(0, 0, -days))
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Failed to report AI usage", "error", err)
+		http.Error(w, "failed to report AI usage", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(report); err != nil {
+		slog.WarnContext(r.Context(), "Failed to write AI usage response", "error", err)
+	}
+}
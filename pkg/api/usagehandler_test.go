@@ -0,0 +1,49 @@
+package api_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/kazemisoroush/assistant/pkg/ai"
+	"github.com/kazemisoroush/assistant/pkg/ai/mocks"
+	"github.com/kazemisoroush/assistant/pkg/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestUsageHandler_ServeHTTP(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	store := mocks.NewMockUsageStore(ctrl)
+	store.EXPECT().Report(gomock.Any(), gomock.Any()).DoAndReturn(func(_ any, since time.Time) (ai.UsageReport, error) {
+		assert.WithinDuration(t, time.Now().AddDate(0, 0, -7), since, time.Minute, "ServeHTTP() should report on the requested days")
+		return ai.UsageReport{Total: ai.UsageTotals{Key: "total", Calls: 3}}, nil
+	})
+	handler := api.NewUsageHandler(store)
+	rec := httptest.NewRecorder()
+
+	// Act
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, api.UsagePath+"?days=7", nil))
+
+	// Assert
+	require.Equal(t, http.StatusOK, rec.Code, "ServeHTTP() should succeed")
+	var report ai.UsageReport
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &report), "ServeHTTP() should return JSON")
+	assert.Equal(t, 3, report.Total.Calls, "ServeHTTP() should return the usage report")
+}
+
+func TestUsageHandler_ServeHTTP_InvalidDays(t *testing.T) {
+	// Arrange
+	handler := api.NewUsageHandler(mocks.NewMockUsageStore(gomock.NewController(t)))
+	rec := httptest.NewRecorder()
+
+	// Act
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, api.UsagePath+"?days=abc", nil))
+
+	// Assert
+	assert.Equal(t, http.StatusBadRequest, rec.Code, "ServeHTTP() should reject a non-numeric days parameter")
+}
@@ -0,0 +1,45 @@
+package api
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/kazemisoroush/assistant/pkg/vehicles"
+)
+
+// VehiclesPath is the route of the vehicles endpoint
+const VehiclesPath = "/api/v1/vehicles"
+
+// VehiclesHandler serves vehicles with their service history, spend and upcoming services
+type VehiclesHandler struct {
+	tracker vehicles.Tracker
+}
+
+// NewVehiclesHandler creates a new vehicles handler
+func NewVehiclesHandler(tracker vehicles.Tracker) http.Handler {
+	return &VehiclesHandler{
+		tracker: tracker,
+	}
+}
+
+// ServeHTTP handles GET requests
+func (h *VehiclesHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	found, err := h.tracker.Vehicles(r.Context(), time.Now())
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Failed to track vehicles", "error", err)
+		http.Error(w, "failed to track vehicles", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(found); err != nil {
+		slog.WarnContext(r.Context(), "Failed to write vehicles response", "error", err)
+	}
+}
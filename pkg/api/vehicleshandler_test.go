@@ -0,0 +1,52 @@
+package api_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kazemisoroush/assistant/pkg/api"
+	"github.com/kazemisoroush/assistant/pkg/vehicles"
+	"github.com/kazemisoroush/assistant/pkg/vehicles/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestVehiclesHandler_ServeHTTP(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	tracker := mocks.NewMockTracker(ctrl)
+	tracker.EXPECT().Vehicles(gomock.Any(), gomock.Any()).Return([]vehicles.Vehicle{
+		{ID: "WVW123", Plate: "BAB123", Upcoming: []vehicles.Due{{ServiceType: "oil_change", DueDate: "2024-05-10"}}},
+	}, nil)
+	handler := api.NewVehiclesHandler(tracker)
+	rec := httptest.NewRecorder()
+
+	// Act
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, api.VehiclesPath, nil))
+
+	// Assert
+	require.Equal(t, http.StatusOK, rec.Code, "ServeHTTP() should succeed")
+	var found []vehicles.Vehicle
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &found), "ServeHTTP() should return JSON")
+	require.Len(t, found, 1, "ServeHTTP() should return the vehicles")
+	assert.Equal(t, "2024-05-10", found[0].Upcoming[0].DueDate, "ServeHTTP() should include the upcoming services")
+}
+
+func TestVehiclesHandler_ServeHTTP_Error(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	tracker := mocks.NewMockTracker(ctrl)
+	tracker.EXPECT().Vehicles(gomock.Any(), gomock.Any()).Return(nil, errors.New("storage unavailable"))
+	handler := api.NewVehiclesHandler(tracker)
+	rec := httptest.NewRecorder()
+
+	// Act
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, api.VehiclesPath, nil))
+
+	// Assert
+	assert.Equal(t, http.StatusInternalServerError, rec.Code, "ServeHTTP() should report tracking failures")
+}
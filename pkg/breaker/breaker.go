@@ -0,0 +1,111 @@
+// Package breaker stops calls to a failing dependency until it has had time to recover.
+package breaker
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// ErrOpen is returned instead of calling a dependency whose circuit is open
+var ErrOpen = errors.New("circuit breaker is open")
+
+// State is the state of a circuit
+type State string
+
+// Circuit states
+const (
+	// StateClosed lets every call through
+	StateClosed State = "closed"
+
+	// StateOpen rejects every call until the open timeout has passed
+	StateOpen State = "open"
+
+	// StateHalfOpen lets a single trial call through to probe the dependency
+	StateHalfOpen State = "half_open"
+)
+
+// Config represents the settings of a circuit breaker
+type Config struct {
+	// FailureThreshold is the number of consecutive failures that open the circuit; 0 disables the breaker
+	FailureThreshold int
+
+	// OpenTimeout is how long the circuit stays open before a trial call is let through
+	OpenTimeout time.Duration
+}
+
+// Breaker tracks consecutive failures of one dependency
+type Breaker struct {
+	name     string
+	cfg      Config
+	now      func() time.Time
+	mu       sync.Mutex
+	state    State
+	failures int
+	openedAt time.Time
+	trial    bool // A half-open trial call is in flight
+}
+
+// New creates a closed circuit breaker for the named dependency
+func New(name string, cfg Config) *Breaker {
+	return &Breaker{
+		name:  name,
+		cfg:   cfg,
+		now:   time.Now,
+		state: StateClosed,
+	}
+}
+
+// Allow returns ErrOpen when the call must not be made. Every allowed call
+// must be followed by Record with its outcome.
+func (b *Breaker) Allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == StateOpen && b.now().Sub(b.openedAt) >= b.cfg.OpenTimeout {
+		b.state = StateHalfOpen
+		b.trial = false
+	}
+
+	switch {
+	case b.state == StateOpen, b.state == StateHalfOpen && b.trial:
+		return fmt.Errorf("%s: %w", b.name, ErrOpen)
+	case b.state == StateHalfOpen:
+		b.trial = true
+	}
+	return nil
+}
+
+// Record reports the outcome of an allowed call
+func (b *Breaker) Record(failed bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if !failed {
+		if b.state != StateClosed {
+			slog.Info("Circuit closed", "dependency", b.name)
+		}
+		b.state = StateClosed
+		b.failures = 0
+		return
+	}
+
+	b.failures++
+	if b.state == StateHalfOpen || b.failures >= b.cfg.FailureThreshold {
+		if b.state != StateOpen {
+			slog.Warn("Circuit opened", "dependency", b.name, "failures", b.failures, "retry_after", b.cfg.OpenTimeout)
+		}
+		b.state = StateOpen
+		b.openedAt = b.now()
+		b.trial = false
+	}
+}
+
+// State returns the current state of the circuit
+func (b *Breaker) State() State {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.state
+}
@@ -0,0 +1,70 @@
+package breaker
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBreaker_OpensAfterConsecutiveFailures(t *testing.T) {
+	// Arrange
+	b := New("ollama", Config{FailureThreshold: 2, OpenTimeout: time.Minute})
+	b.Record(true)
+	b.Record(false)
+	b.Record(true)
+
+	// Act
+	b.Record(true)
+
+	// Assert
+	assert.Equal(t, StateOpen, b.State(), "Record() should open the circuit after consecutive failures")
+	assert.ErrorIs(t, b.Allow(), ErrOpen, "Allow() should reject calls while the circuit is open")
+}
+
+func TestBreaker_HalfOpensAfterTimeout(t *testing.T) {
+	// Arrange
+	now := time.Now()
+	b := New("ollama", Config{FailureThreshold: 1, OpenTimeout: time.Minute})
+	b.now = func() time.Time { return now }
+	b.Record(true)
+	now = now.Add(time.Minute)
+
+	// Act
+	trialErr := b.Allow()
+	concurrentErr := b.Allow()
+
+	// Assert
+	require.NoError(t, trialErr, "Allow() should let a trial call through after the open timeout")
+	assert.ErrorIs(t, concurrentErr, ErrOpen, "Allow() should reject other calls during the trial")
+	assert.Equal(t, StateHalfOpen, b.State(), "Allow() should half-open the circuit")
+}
+
+func TestBreaker_Record_TrialOutcome(t *testing.T) {
+	tests := []struct {
+		name   string
+		failed bool
+		want   State
+	}{
+		{name: "success closes the circuit", failed: false, want: StateClosed},
+		{name: "failure reopens the circuit", failed: true, want: StateOpen},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			b := New("bedrock", Config{FailureThreshold: 3, OpenTimeout: 0})
+			b.Record(true)
+			b.Record(true)
+			b.Record(true)
+			require.NoError(t, b.Allow(), "Allow() should let a trial call through")
+
+			// Act
+			b.Record(tt.failed)
+
+			// Assert
+			assert.Equal(t, tt.want, b.State(), "Record() should resolve the half-open circuit")
+		})
+	}
+}
@@ -0,0 +1,116 @@
+// Package budgets compares monthly receipt spend per category with budgets
+// and alerts when spend crosses a share of a budget.
+package budgets
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// DefaultThresholds are the percentages of a budget at which alerts are sent
+var DefaultThresholds = []int{80, 100}
+
+// Budget represents a monthly spending limit for one receipt category
+type Budget struct {
+	Category string  `json:"category"` // Lowercase receipt category, e.g. groceries
+	Limit    float64 `json:"limit"`
+	Currency string  `json:"currency"` // ISO 4217 code; receipts in other currencies are not counted
+}
+
+// Validate checks that the budget is complete and well-formed
+func (b Budget) Validate() error {
+	if b.Category == "" {
+		return errors.New("category is required")
+	}
+	if b.Limit <= 0 {
+		return fmt.Errorf("limit must be positive: %v", b.Limit)
+	}
+	if len(b.Currency) != 3 {
+		return fmt.Errorf("currency must be a 3-letter ISO code: %q", b.Currency)
+	}
+	return nil
+}
+
+// Normalize returns the budget with its category lowercased and currency uppercased
+func (b Budget) Normalize() Budget {
+	b.Category = strings.ToLower(strings.TrimSpace(b.Category))
+	b.Currency = strings.ToUpper(strings.TrimSpace(b.Currency))
+	return b
+}
+
+// Status represents the spend against a budget in one month
+type Status struct {
+	Budget
+	Month     string  `json:"month"` // YYYY-MM
+	Spent     float64 `json:"spent"`
+	Percent   float64 `json:"percent"`             // Spent as a percentage of the limit
+	Threshold int     `json:"threshold,omitempty"` // Highest threshold crossed, 0 if none
+}
+
+// String describes the status in a single line
+func (s Status) String() string {
+	return fmt.Sprintf("%s spend in %s is %.2f of %.2f %s (%.0f%%)", s.Category, s.Month, s.Spent, s.Limit, s.Currency, s.Percent)
+}
+
+// summary lists the statuses one per line
+func summary(statuses []Status) string {
+	var b strings.Builder
+	for _, s := range statuses {
+		b.WriteString("- " + s.String() + "\n")
+	}
+	return b.String()
+}
+
+// Store keeps the budgets and which alerts were already sent
+//
+//go:generate mockgen -destination=./mocks/mock_store.go -mock_names=Store=MockStore -package=mocks . Store
+type Store interface {
+	// Save adds or replaces the budget of a category
+	Save(ctx context.Context, budget Budget) error
+
+	// Delete removes the budget of a category
+	Delete(ctx context.Context, category string) error
+
+	// List returns all budgets ordered by category
+	List(ctx context.Context) ([]Budget, error)
+
+	// Alerted returns the highest threshold already alerted for the category in the month, 0 if none
+	Alerted(ctx context.Context, category, month string) (int, error)
+
+	// MarkAlerted records that the threshold was alerted for the category in the month
+	MarkAlerted(ctx context.Context, category, month string, threshold int) error
+}
+
+// Checker compares receipt spend with the budgets
+//
+//go:generate mockgen -destination=./mocks/mock_checker.go -mock_names=Checker=MockChecker -package=mocks . Checker
+type Checker interface {
+	// Status returns the spend against every budget in the month of now
+	Status(ctx context.Context, now time.Time) ([]Status, error)
+
+	// Run sends alerts for the budgets that crossed a threshold since the last run and returns them
+	Run(ctx context.Context, now time.Time) ([]Status, error)
+}
+
+// Notifier delivers budget alerts to the user
+//
+//go:generate mockgen -destination=./mocks/mock_notifier.go -mock_names=Notifier=MockNotifier -package=mocks . Notifier
+type Notifier interface {
+	// Notify delivers the alerts
+	Notify(ctx context.Context, alerts []Status) error
+}
+
+// Set validates the budget and saves it, normalized, to the store
+func Set(ctx context.Context, store Store, budget Budget) (Budget, error) {
+	budget = budget.Normalize()
+	if err := budget.Validate(); err != nil {
+		return Budget{}, fmt.Errorf("invalid budget: %w", err)
+	}
+	if err := store.Save(ctx, budget); err != nil {
+		return Budget{}, err
+	}
+	return budget, nil
+}
@@ -0,0 +1,24 @@
+package budgets
+
+import (
+	"context"
+
+	"github.com/kazemisoroush/assistant/pkg/notifications"
+)
+
+// EventNotifier sends budget alerts through the notification channels subscribed to them
+type EventNotifier struct {
+	notifier notifications.Notifier
+}
+
+// NewEventNotifier creates a notifier delivering budget alerts as notifications
+func NewEventNotifier(notifier notifications.Notifier) Notifier {
+	return &EventNotifier{
+		notifier: notifier,
+	}
+}
+
+// Notify implements Notifier
+func (n *EventNotifier) Notify(ctx context.Context, alerts []Status) error {
+	return n.notifier.Notify(ctx, notifications.EventBudgetAlerts, alerts)
+}
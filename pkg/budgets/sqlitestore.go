@@ -0,0 +1,108 @@
+package budgets
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/kazemisoroush/assistant/pkg/sqlite"
+)
+
+// SQLiteStore is a Store backed by SQLite
+type SQLiteStore struct {
+	db *sqlite.DB
+}
+
+// NewSQLiteStore creates a new SQLite budget store in the given database
+func NewSQLiteStore(db *sqlite.DB) (*SQLiteStore, error) {
+	schema := `
+    CREATE TABLE IF NOT EXISTS budgets (
+        category TEXT PRIMARY KEY,
+        monthly_limit REAL NOT NULL,
+        currency TEXT NOT NULL
+    );
+
+    CREATE TABLE IF NOT EXISTS budget_alerts (
+        category TEXT NOT NULL,
+        month TEXT NOT NULL,
+        threshold INTEGER NOT NULL,
+        PRIMARY KEY (category, month)
+    );
+    `
+	if _, err := db.Exec(schema); err != nil {
+		return nil, fmt.Errorf("failed to initialize budget schema: %w", err)
+	}
+
+	return &SQLiteStore{db: db}, nil
+}
+
+// Save implements Store
+func (s *SQLiteStore) Save(ctx context.Context, budget Budget) error {
+	if _, err := s.db.ExecContext(ctx,
+		`INSERT INTO budgets (category, monthly_limit, currency) VALUES (?, ?, ?)
+         ON CONFLICT (category) DO UPDATE SET monthly_limit = excluded.monthly_limit, currency = excluded.currency`,
+		budget.Category, budget.Limit, budget.Currency,
+	); err != nil {
+		return fmt.Errorf("failed to save budget %s: %w", budget.Category, err)
+	}
+	return nil
+}
+
+// Delete implements Store
+func (s *SQLiteStore) Delete(ctx context.Context, category string) error {
+	result, err := s.db.ExecContext(ctx, `DELETE FROM budgets WHERE category = ?`, category)
+	if err != nil {
+		return fmt.Errorf("failed to delete budget %s: %w", category, err)
+	}
+	if n, err := result.RowsAffected(); err == nil && n == 0 {
+		return fmt.Errorf("budget not found: %s", category)
+	}
+	return nil
+}
+
+// List implements Store
+func (s *SQLiteStore) List(ctx context.Context) ([]Budget, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT category, monthly_limit, currency FROM budgets ORDER BY category`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list budgets: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var budgets []Budget
+	for rows.Next() {
+		var budget Budget
+		if err := rows.Scan(&budget.Category, &budget.Limit, &budget.Currency); err != nil {
+			return nil, fmt.Errorf("failed to scan budget: %w", err)
+		}
+		budgets = append(budgets, budget)
+	}
+	return budgets, rows.Err()
+}
+
+// Alerted implements Store
+func (s *SQLiteStore) Alerted(ctx context.Context, category, month string) (int, error) {
+	var threshold int
+	err := s.db.QueryRowContext(ctx,
+		`SELECT threshold FROM budget_alerts WHERE category = ? AND month = ?`, category, month,
+	).Scan(&threshold)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to read budget alert: %w", err)
+	}
+	return threshold, nil
+}
+
+// MarkAlerted implements Store
+func (s *SQLiteStore) MarkAlerted(ctx context.Context, category, month string, threshold int) error {
+	if _, err := s.db.ExecContext(ctx,
+		`INSERT INTO budget_alerts (category, month, threshold) VALUES (?, ?, ?)
+         ON CONFLICT (category, month) DO UPDATE SET threshold = excluded.threshold`,
+		category, month, threshold,
+	); err != nil {
+		return fmt.Errorf("failed to record budget alert: %w", err)
+	}
+	return nil
+}
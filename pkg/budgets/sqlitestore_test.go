@@ -0,0 +1,83 @@
+package budgets_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/kazemisoroush/assistant/pkg/budgets"
+	"github.com/kazemisoroush/assistant/pkg/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// openDB opens the database at path, closed once the test is done
+func openDB(t testing.TB, path string) *sqlite.DB {
+	t.Helper()
+	db, err := sqlite.Open(path)
+	require.NoError(t, err, "Open() error should be nil")
+	t.Cleanup(func() { _ = db.Close() })
+	return db
+}
+
+func TestSQLiteStore_SetListDelete(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	store, err := budgets.NewSQLiteStore(openDB(t, filepath.Join(t.TempDir(), "assistant.db")))
+	require.NoError(t, err, "NewSQLiteStore() error should be nil")
+
+	// Act
+	saved, err := budgets.Set(ctx, store, budgets.Budget{Category: " Groceries", Limit: 500, Currency: "eur"})
+
+	// Assert
+	require.NoError(t, err, "Set() error should be nil")
+	assert.Equal(t, budgets.Budget{Category: "groceries", Limit: 500, Currency: "EUR"}, saved, "Set() should normalize the budget")
+	list, err := store.List(ctx)
+	require.NoError(t, err, "List() error should be nil")
+	assert.Equal(t, []budgets.Budget{saved}, list, "List() should return the saved budget")
+
+	require.NoError(t, store.Delete(ctx, "groceries"), "Delete() error should be nil")
+	list, err = store.List(ctx)
+	require.NoError(t, err, "List() error should be nil")
+	assert.Empty(t, list, "Delete() should remove the budget")
+	assert.Error(t, store.Delete(ctx, "groceries"), "Delete() should fail for an unknown budget")
+}
+
+func TestSQLiteStore_Alerts(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	store, err := budgets.NewSQLiteStore(openDB(t, filepath.Join(t.TempDir(), "assistant.db")))
+	require.NoError(t, err, "NewSQLiteStore() error should be nil")
+	require.NoError(t, store.MarkAlerted(ctx, "groceries", "2024-05", 80), "MarkAlerted() error should be nil")
+	require.NoError(t, store.MarkAlerted(ctx, "groceries", "2024-05", 100), "MarkAlerted() error should be nil")
+
+	// Act
+	alerted, err := store.Alerted(ctx, "groceries", "2024-05")
+	none, noneErr := store.Alerted(ctx, "groceries", "2024-06")
+
+	// Assert
+	require.NoError(t, err, "Alerted() error should be nil")
+	require.NoError(t, noneErr, "Alerted() error should be nil")
+	assert.Equal(t, 100, alerted, "Alerted() should return the latest threshold alerted")
+	assert.Zero(t, none, "Alerted() should return 0 for a month without alerts")
+}
+
+func TestBudget_Validate(t *testing.T) {
+	tests := []struct {
+		name   string
+		budget budgets.Budget
+	}{
+		{name: "missing category", budget: budgets.Budget{Limit: 100, Currency: "EUR"}},
+		{name: "zero limit", budget: budgets.Budget{Category: "groceries", Currency: "EUR"}},
+		{name: "bad currency", budget: budgets.Budget{Category: "groceries", Limit: 100, Currency: "euro"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			err := tt.budget.Validate()
+
+			// Assert
+			assert.Error(t, err, "Validate() should reject an invalid budget")
+		})
+	}
+}
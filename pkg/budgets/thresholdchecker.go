@@ -0,0 +1,141 @@
+package budgets
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/kazemisoroush/assistant/pkg/records"
+	"github.com/kazemisoroush/assistant/pkg/records/storage"
+)
+
+// monthFormat formats the month a status covers
+const monthFormat = "2006-01"
+
+// ThresholdChecker totals the receipts of the current month per category and
+// sends one alert per budget and month for each threshold crossed
+type ThresholdChecker struct {
+	store      Store
+	storage    storage.Storage
+	thresholds []int // Ascending
+	notifiers  []Notifier
+}
+
+// NewThresholdChecker creates a budget checker. Without thresholds, DefaultThresholds are used.
+func NewThresholdChecker(store Store, storage storage.Storage, thresholds []int, notifiers ...Notifier) Checker {
+	if len(thresholds) == 0 {
+		thresholds = DefaultThresholds
+	}
+	thresholds = slices.Clone(thresholds)
+	slices.Sort(thresholds)
+
+	return &ThresholdChecker{
+		store:      store,
+		storage:    storage,
+		thresholds: thresholds,
+		notifiers:  notifiers,
+	}
+}
+
+// Status implements Checker
+func (c *ThresholdChecker) Status(ctx context.Context, now time.Time) ([]Status, error) {
+	budgets, err := c.store.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list budgets: %w", err)
+	}
+	if len(budgets) == 0 {
+		return []Status{}, nil
+	}
+
+	month := now.Format(monthFormat)
+	spend, err := c.spend(ctx, month)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]Status, 0, len(budgets))
+	for _, budget := range budgets {
+		spent := spend[budget.Category+"/"+budget.Currency]
+		status := Status{Budget: budget, Month: month, Spent: spent, Percent: spent / budget.Limit * 100}
+		for _, threshold := range c.thresholds {
+			if status.Percent >= float64(threshold) {
+				status.Threshold = threshold
+			}
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+// Run implements Checker. Alerts are only marked as sent once every notifier
+// has delivered, so failed deliveries are retried on the next run.
+func (c *ThresholdChecker) Run(ctx context.Context, now time.Time) ([]Status, error) {
+	statuses, err := c.Status(ctx, now)
+	if err != nil {
+		return nil, err
+	}
+
+	due, err := c.due(ctx, statuses)
+	if err != nil || len(due) == 0 {
+		return nil, err
+	}
+
+	var errs []error
+	for _, notifier := range c.notifiers {
+		if err := notifier.Notify(ctx, due); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if err := errors.Join(errs...); err != nil {
+		return nil, fmt.Errorf("failed to send budget alerts: %w", err)
+	}
+
+	for _, status := range due {
+		if err := c.store.MarkAlerted(ctx, status.Category, status.Month, status.Threshold); err != nil {
+			return nil, fmt.Errorf("failed to mark budget %s as alerted: %w", status.Category, err)
+		}
+	}
+	return due, nil
+}
+
+// due returns the statuses that crossed a threshold not alerted yet this month
+func (c *ThresholdChecker) due(ctx context.Context, statuses []Status) ([]Status, error) {
+	var due []Status
+	for _, status := range statuses {
+		if status.Threshold == 0 {
+			continue
+		}
+		alerted, err := c.store.Alerted(ctx, status.Category, status.Month)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read alerts of budget %s: %w", status.Category, err)
+		}
+		if alerted < status.Threshold {
+			due = append(due, status)
+		}
+	}
+	return due, nil
+}
+
+// spend totals the receipts dated in the month, keyed "category/CURRENCY"
+func (c *ThresholdChecker) spend(ctx context.Context, month string) (map[string]float64, error) {
+	spend := map[string]float64{}
+	err := c.storage.Each(ctx, records.RecordTypeReceipt, func(rec records.Record) error {
+		var receipt records.ReceiptMetadata
+		if err := rec.DecodeMetadata(&receipt); err != nil {
+			slog.WarnContext(ctx, "Skipping receipt with malformed metadata", "record_id", rec.ID, "error", err)
+			return nil
+		}
+		if strings.HasPrefix(receipt.Date, month) {
+			spend[strings.ToLower(receipt.Category)+"/"+strings.ToUpper(receipt.Currency)] += receipt.Total
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list receipts: %w", err)
+	}
+	return spend, nil
+}
@@ -0,0 +1,125 @@
+package budgets_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/kazemisoroush/assistant/pkg/budgets"
+	"github.com/kazemisoroush/assistant/pkg/budgets/mocks"
+	"github.com/kazemisoroush/assistant/pkg/records"
+	storagemocks "github.com/kazemisoroush/assistant/pkg/records/storage/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+var now = time.Date(2024, 5, 20, 9, 0, 0, 0, time.UTC)
+
+// receipt builds a receipt record with the given metadata
+func receipt(id, category, date string, total float64, currency string) records.Record {
+	return records.Record{ID: id, Type: records.RecordTypeReceipt, Metadata: map[string]interface{}{
+		"merchant": "Shop", "date": date, "category": category, "total": total, "currency": currency,
+	}}
+}
+
+// eachOf returns a Storage.Each calling fn with the records
+func eachOf(recs []records.Record) func(context.Context, records.RecordType, func(records.Record) error) error {
+	return func(_ context.Context, _ records.RecordType, fn func(records.Record) error) error {
+		for _, rec := range recs {
+			if err := fn(rec); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// receipts are the receipts the tests check against the budgets
+var receipts = []records.Record{
+	receipt("market", "Groceries", "2024-05-02", 250, "EUR"),
+	receipt("bakery", "groceries", "2024-05-18", 180, "EUR"),
+	receipt("april", "groceries", "2024-04-30", 300, "EUR"),
+	receipt("abroad", "groceries", "2024-05-10", 90, "USD"),
+	receipt("cinema", "entertainment", "2024-05-11", 20, "EUR"),
+}
+
+func TestThresholdChecker_Status(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	store := mocks.NewMockStore(ctrl)
+	store.EXPECT().List(gomock.Any()).Return([]budgets.Budget{
+		{Category: "entertainment", Limit: 100, Currency: "EUR"},
+		{Category: "groceries", Limit: 500, Currency: "EUR"},
+	}, nil)
+	storage := storagemocks.NewMockStorage(ctrl)
+	storage.EXPECT().Each(gomock.Any(), records.RecordTypeReceipt, gomock.Any()).DoAndReturn(eachOf(receipts))
+	checker := budgets.NewThresholdChecker(store, storage, nil)
+
+	// Act
+	statuses, err := checker.Status(context.Background(), now)
+
+	// Assert
+	require.NoError(t, err, "Status() error should be nil")
+	require.Len(t, statuses, 2, "Status() should report every budget")
+	assert.Equal(t, "2024-05", statuses[1].Month, "Status() should cover the current month")
+	assert.InDelta(t, 430, statuses[1].Spent, 0.001, "Status() should only total the month's receipts in the budget currency")
+	assert.InDelta(t, 86, statuses[1].Percent, 0.001, "Status() should compute the share of the limit")
+	assert.Equal(t, 80, statuses[1].Threshold, "Status() should report the highest threshold crossed")
+	assert.Zero(t, statuses[0].Threshold, "Status() should not flag budgets below every threshold")
+}
+
+func TestThresholdChecker_Run(t *testing.T) {
+	tests := []struct {
+		name    string
+		alerted int
+		wantDue int
+	}{
+		{name: "new threshold", alerted: 0, wantDue: 1},
+		{name: "already alerted", alerted: 80, wantDue: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			ctrl := gomock.NewController(t)
+			store := mocks.NewMockStore(ctrl)
+			store.EXPECT().List(gomock.Any()).Return([]budgets.Budget{{Category: "groceries", Limit: 500, Currency: "EUR"}}, nil)
+			store.EXPECT().Alerted(gomock.Any(), "groceries", "2024-05").Return(tt.alerted, nil)
+			storage := storagemocks.NewMockStorage(ctrl)
+			storage.EXPECT().Each(gomock.Any(), records.RecordTypeReceipt, gomock.Any()).DoAndReturn(eachOf(receipts))
+			notifier := mocks.NewMockNotifier(ctrl)
+			if tt.wantDue > 0 {
+				notifier.EXPECT().Notify(gomock.Any(), gomock.Len(tt.wantDue)).Return(nil)
+				store.EXPECT().MarkAlerted(gomock.Any(), "groceries", "2024-05", 80).Return(nil)
+			}
+			checker := budgets.NewThresholdChecker(store, storage, []int{100, 80}, notifier)
+
+			// Act
+			due, err := checker.Run(context.Background(), now)
+
+			// Assert
+			require.NoError(t, err, "Run() error should be nil")
+			assert.Len(t, due, tt.wantDue, "Run() should only return alerts not sent yet")
+		})
+	}
+}
+
+func TestThresholdChecker_Run_NotifyFailure(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	store := mocks.NewMockStore(ctrl)
+	store.EXPECT().List(gomock.Any()).Return([]budgets.Budget{{Category: "groceries", Limit: 400, Currency: "EUR"}}, nil)
+	store.EXPECT().Alerted(gomock.Any(), "groceries", "2024-05").Return(0, nil)
+	storage := storagemocks.NewMockStorage(ctrl)
+	storage.EXPECT().Each(gomock.Any(), records.RecordTypeReceipt, gomock.Any()).DoAndReturn(eachOf(receipts))
+	notifier := mocks.NewMockNotifier(ctrl)
+	notifier.EXPECT().Notify(gomock.Any(), gomock.Any()).Return(errors.New("smtp down"))
+	checker := budgets.NewThresholdChecker(store, storage, nil, notifier)
+
+	// Act
+	_, err := checker.Run(context.Background(), now)
+
+	// Assert
+	assert.Error(t, err, "Run() should fail when a notifier fails, without marking the alert as sent")
+}
@@ -0,0 +1,30 @@
+package budgets
+
+import (
+	"context"
+	"fmt"
+	"io"
+)
+
+// WriterNotifier prints a plain-text summary of budget alerts, e.g. to the terminal
+type WriterNotifier struct {
+	w io.Writer
+}
+
+// NewWriterNotifier creates a notifier writing to w
+func NewWriterNotifier(w io.Writer) Notifier {
+	return &WriterNotifier{
+		w: w,
+	}
+}
+
+// Notify implements Notifier
+func (n *WriterNotifier) Notify(_ context.Context, alerts []Status) error {
+	if len(alerts) == 0 {
+		return nil
+	}
+	if _, err := io.WriteString(n.w, summary(alerts)); err != nil {
+		return fmt.Errorf("failed to write budget alerts: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,41 @@
+// Package calendar turns the important dates found in records into calendar
+// events that can be subscribed to as an iCalendar feed.
+package calendar
+
+import (
+	"context"
+	"time"
+
+	"github.com/kazemisoroush/assistant/pkg/records"
+)
+
+// Event kinds
+const (
+	// KindExpiry is the date a document expires
+	KindExpiry = "expiry"
+
+	// KindRenewal is a reminder to renew a document before it expires
+	KindRenewal = "renewal"
+
+	// KindFollowUp is a follow-up visit or test recommended at a health visit
+	KindFollowUp = "follow_up"
+)
+
+// Event represents an all-day calendar event derived from a record
+type Event struct {
+	UID         string             `json:"uid"` // Stable across exports so calendar apps update events in place
+	Kind        string             `json:"kind"`
+	RecordID    string             `json:"record_id"`
+	RecordType  records.RecordType `json:"record_type"`
+	Summary     string             `json:"summary"`
+	Description string             `json:"description,omitempty"`
+	Date        time.Time          `json:"date"`
+}
+
+// Builder collects calendar events from records
+//
+//go:generate mockgen -destination=./mocks/mock_builder.go -mock_names=Builder=MockBuilder -package=mocks . Builder
+type Builder interface {
+	// Events returns the events of every record, soonest first
+	Events(ctx context.Context) ([]Event, error)
+}
@@ -0,0 +1,80 @@
+package calendar
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+const (
+	// icsDate formats the date of all-day events
+	icsDate = "20060102"
+
+	// icsTimestamp formats UTC timestamps
+	icsTimestamp = "20060102T150405Z"
+
+	// icsLineLength is the maximum length of a content line in octets, before folding
+	icsLineLength = 75
+)
+
+// icsEscaper escapes text values as required by RFC 5545
+var icsEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+
+// WriteICS writes the events as an iCalendar (RFC 5545) calendar of all-day events
+func WriteICS(w io.Writer, events []Event, now time.Time) error {
+	out := bufio.NewWriter(w)
+	line := func(content string) {
+		_, _ = out.WriteString(fold(content) + "\r\n")
+	}
+
+	line("BEGIN:VCALENDAR")
+	line("VERSION:2.0")
+	line("PRODID:-//assistant//important dates//EN")
+	line("CALSCALE:GREGORIAN")
+	line("X-WR-CALNAME:Assistant")
+	for _, event := range events {
+		line("BEGIN:VEVENT")
+		line("UID:" + event.UID)
+		line("DTSTAMP:" + now.UTC().Format(icsTimestamp))
+		line("DTSTART;VALUE=DATE:" + event.Date.Format(icsDate))
+		line("DTEND;VALUE=DATE:" + event.Date.AddDate(0, 0, 1).Format(icsDate))
+		line("SUMMARY:" + icsEscaper.Replace(event.Summary))
+		if event.Description != "" {
+			line("DESCRIPTION:" + icsEscaper.Replace(event.Description))
+		}
+		line("CATEGORIES:" + icsEscaper.Replace(event.Kind))
+		line("TRANSP:TRANSPARENT")
+		line("END:VEVENT")
+	}
+	line("END:VCALENDAR")
+
+	if err := out.Flush(); err != nil {
+		return fmt.Errorf("failed to write calendar: %w", err)
+	}
+	return nil
+}
+
+// fold splits a content line longer than icsLineLength octets into
+// continuation lines starting with a space, without splitting UTF-8 characters
+func fold(content string) string {
+	if len(content) <= icsLineLength {
+		return content
+	}
+
+	var b strings.Builder
+	limit := icsLineLength
+	length := 0
+	for _, r := range content {
+		size := len(string(r))
+		if length+size > limit {
+			b.WriteString("\r\n ")
+			limit = icsLineLength - 1
+			length = 0
+		}
+		b.WriteRune(r)
+		length += size
+	}
+	return b.String()
+}
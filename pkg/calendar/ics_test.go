@@ -0,0 +1,41 @@
+package calendar_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kazemisoroush/assistant/pkg/calendar"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteICS(t *testing.T) {
+	// Arrange
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	events := []calendar.Event{{
+		UID:         "expiry-passport@assistant",
+		Kind:        calendar.KindExpiry,
+		Summary:     "passport expires; renew, now",
+		Description: strings.Repeat("long description ", 10),
+		Date:        time.Date(2025, 3, 31, 0, 0, 0, 0, time.UTC),
+	}}
+	var buf bytes.Buffer
+
+	// Act
+	err := calendar.WriteICS(&buf, events, now)
+
+	// Assert
+	require.NoError(t, err, "WriteICS() error should be nil")
+	ics := buf.String()
+	assert.True(t, strings.HasPrefix(ics, "BEGIN:VCALENDAR\r\n"), "WriteICS() should start a calendar")
+	assert.True(t, strings.HasSuffix(ics, "END:VCALENDAR\r\n"), "WriteICS() should end the calendar")
+	assert.Contains(t, ics, "DTSTART;VALUE=DATE:20250331\r\n", "WriteICS() should write all-day events")
+	assert.Contains(t, ics, "DTEND;VALUE=DATE:20250401\r\n", "WriteICS() should end all-day events the next day")
+	assert.Contains(t, ics, `SUMMARY:passport expires\; renew\, now`, "WriteICS() should escape text values")
+	for _, line := range strings.Split(ics, "\r\n") {
+		assert.LessOrEqual(t, len(line), 75, "WriteICS() should fold long lines")
+	}
+	assert.Contains(t, ics, "\r\n ", "WriteICS() should continue folded lines with a space")
+}
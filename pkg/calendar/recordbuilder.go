@@ -0,0 +1,127 @@
+package calendar
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/kazemisoroush/assistant/pkg/records"
+	"github.com/kazemisoroush/assistant/pkg/records/storage"
+)
+
+// RecordBuilder builds calendar events from the records kept in storage:
+// the expiry of time-bound documents, renewal reminders ahead of each expiry
+// and the follow-ups recommended at health visits
+type RecordBuilder struct {
+	storage      storage.Storage
+	reminderDays []int
+}
+
+// NewRecordBuilder creates a calendar builder adding a renewal reminder the given days before each expiry
+func NewRecordBuilder(storage storage.Storage, reminderDays []int) Builder {
+	return &RecordBuilder{
+		storage:      storage,
+		reminderDays: reminderDays,
+	}
+}
+
+// Events implements Builder
+func (b *RecordBuilder) Events(ctx context.Context) ([]Event, error) {
+	recs, err := b.storage.List(ctx, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list records: %w", err)
+	}
+
+	events := []Event{}
+	for _, rec := range recs {
+		if rec.ExpiresAt != nil {
+			events = append(events, b.expiry(rec)...)
+		}
+		if event, ok := followUp(rec); ok {
+			events = append(events, event)
+		}
+	}
+	sort.SliceStable(events, func(i, j int) bool { return events[i].Date.Before(events[j].Date) })
+	return events, nil
+}
+
+// expiry returns the expiry of a document and the reminders to renew it
+func (b *RecordBuilder) expiry(rec records.Record) []Event {
+	name := title(rec)
+	expiresAt := *rec.ExpiresAt
+	events := []Event{{
+		UID:        uid(KindExpiry, rec.ID, 0),
+		Kind:       KindExpiry,
+		RecordID:   rec.ID,
+		RecordType: rec.Type,
+		Summary:    fmt.Sprintf("%s expires", name),
+		Date:       expiresAt,
+	}}
+	for _, days := range b.reminderDays {
+		if days <= 0 {
+			continue
+		}
+		events = append(events, Event{
+			UID:         uid(KindRenewal, rec.ID, days),
+			Kind:        KindRenewal,
+			RecordID:    rec.ID,
+			RecordType:  rec.Type,
+			Summary:     fmt.Sprintf("Renew %s", name),
+			Description: fmt.Sprintf("%s expires on %s, in %d day(s).", name, expiresAt.Format(time.DateOnly), days),
+			Date:        expiresAt.AddDate(0, 0, -days),
+		})
+	}
+	return events
+}
+
+// followUp returns the follow-up recommended at a health visit, if any
+func followUp(rec records.Record) (Event, bool) {
+	if rec.Type != records.RecordTypeHealthVisit {
+		return Event{}, false
+	}
+	var meta records.HealthMetadata
+	if err := rec.DecodeMetadata(&meta); err != nil {
+		return Event{}, false
+	}
+	date, err := time.Parse(time.DateOnly, meta.FollowUp)
+	if err != nil {
+		return Event{}, false
+	}
+
+	summary := "Health follow-up"
+	if meta.Provider != "" {
+		summary += " with " + meta.Provider
+	}
+	if person := rec.Person(); person != "" {
+		summary += " for " + person
+	} else if meta.Patient != "" {
+		summary += " for " + meta.Patient
+	}
+	return Event{
+		UID:         uid(KindFollowUp, rec.ID, 0),
+		Kind:        KindFollowUp,
+		RecordID:    rec.ID,
+		RecordType:  rec.Type,
+		Summary:     summary,
+		Description: meta.Summary,
+		Date:        date,
+	}, true
+}
+
+// title names the document, preferring the extracted document type
+func title(rec records.Record) string {
+	if documentType, ok := rec.Metadata["document_type"].(string); ok && documentType != "" {
+		return documentType
+	}
+	return strings.ReplaceAll(string(rec.Type), "_", " ")
+}
+
+// uid identifies an event of a record across exports
+func uid(kind, recordID string, days int) string {
+	if days > 0 {
+		return fmt.Sprintf("%s-%d-%s@assistant", kind, days, recordID)
+	}
+	return fmt.Sprintf("%s-%s@assistant", kind, recordID)
+}
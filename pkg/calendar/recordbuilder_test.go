@@ -0,0 +1,52 @@
+package calendar_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/kazemisoroush/assistant/pkg/calendar"
+	"github.com/kazemisoroush/assistant/pkg/records"
+	"github.com/kazemisoroush/assistant/pkg/records/storage/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestRecordBuilder_Events(t *testing.T) {
+	// Arrange
+	expiresAt := time.Date(2025, 3, 31, 0, 0, 0, 0, time.UTC)
+	ctrl := gomock.NewController(t)
+	storage := mocks.NewMockStorage(ctrl)
+	storage.EXPECT().List(gomock.Any(), records.RecordType("")).Return([]records.Record{
+		{ID: "passport", Type: records.RecordTypeID, ExpiresAt: &expiresAt, Metadata: map[string]interface{}{"document_type": "passport"}},
+		{ID: "visit", Type: records.RecordTypeHealthVisit, Metadata: map[string]interface{}{
+			"patient": "Alex Doe", "provider": "Dr. Smith", "date": "2025-01-10", "summary": "Knee pain", "follow_up": "2025-02-10",
+		}},
+		{ID: "lab", Type: records.RecordTypeHealthLab, Metadata: map[string]interface{}{"follow_up": "2025-02-11"}},
+		{ID: "receipt", Type: records.RecordTypeReceipt},
+	}, nil)
+	builder := calendar.NewRecordBuilder(storage, []int{30})
+
+	// Act
+	events, err := builder.Events(context.Background())
+
+	// Assert
+	require.NoError(t, err, "Events() error should be nil")
+	require.Len(t, events, 3, "Events() should add expiries, renewals and health visit follow-ups")
+
+	followUp := events[0]
+	assert.Equal(t, calendar.KindFollowUp, followUp.Kind, "Events() should list the soonest event first")
+	assert.Equal(t, "Health follow-up with Dr. Smith for Alex Doe", followUp.Summary, "Events() should name the provider and patient")
+	assert.Equal(t, "Knee pain", followUp.Description, "Events() should describe the follow-up with the visit summary")
+
+	renewal := events[1]
+	assert.Equal(t, calendar.KindRenewal, renewal.Kind, "Events() should remind to renew before the expiry")
+	assert.Equal(t, "Renew passport", renewal.Summary, "Events() should name the document to renew")
+	assert.Equal(t, expiresAt.AddDate(0, 0, -30), renewal.Date, "Events() should place the renewal the reminder days before expiry")
+
+	expiry := events[2]
+	assert.Equal(t, calendar.KindExpiry, expiry.Kind, "Events() should add the expiry")
+	assert.Equal(t, expiresAt, expiry.Date, "Events() should place the expiry on the expiry date")
+	assert.NotEqual(t, expiry.UID, renewal.UID, "Events() should give every event its own UID")
+}
@@ -0,0 +1,62 @@
+// Package claims matches medical receipts to insurance claims and
+// reimbursements to track what is still to be submitted or paid out.
+package claims
+
+import (
+	"context"
+	"fmt"
+)
+
+// Status represents how far an expense got through the insurer
+type Status string
+
+// Expense statuses
+const (
+	StatusUnclaimed  Status = "unclaimed"  // No claim found for the expense
+	StatusPending    Status = "pending"    // Claimed but nothing reimbursed yet
+	StatusReimbursed Status = "reimbursed" // Reimbursed, possibly in part
+)
+
+// ParseStatus validates an expense status; the empty string stands for all statuses
+func ParseStatus(s string) (Status, error) {
+	switch status := Status(s); status {
+	case "", StatusUnclaimed, StatusPending, StatusReimbursed:
+		return status, nil
+	default:
+		return "", fmt.Errorf("unknown claim status %q", s)
+	}
+}
+
+// Expense represents a medical receipt
+type Expense struct {
+	RecordID string  `json:"record_id"`
+	Provider string  `json:"provider"`
+	Date     string  `json:"date"` // YYYY-MM-DD
+	Amount   float64 `json:"amount"`
+	Currency string  `json:"currency"`
+}
+
+// Match represents a medical expense and the insurance claim it was matched to, if any
+type Match struct {
+	Expense       Expense `json:"expense"`
+	ClaimRecordID string  `json:"claim_record_id,omitempty"`
+	Claimed       float64 `json:"claimed,omitempty"`
+	Reimbursed    float64 `json:"reimbursed,omitempty"`
+	Status        Status  `json:"status"`
+}
+
+// Report represents the matched expenses, oldest first
+type Report struct {
+	Matches []Match `json:"matches"`
+
+	// Unreimbursed totals the unclaimed and pending expenses per currency
+	Unreimbursed map[string]float64 `json:"unreimbursed"`
+}
+
+// Matcher links medical receipts to insurance claims
+//
+//go:generate mockgen -destination=./mocks/mock_matcher.go -mock_names=Matcher=MockMatcher -package=mocks . Matcher
+type Matcher interface {
+	// Match returns the medical expenses with their claims, optionally only those with the given status
+	Match(ctx context.Context, status Status) (Report, error)
+}
@@ -0,0 +1,241 @@
+package claims
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/kazemisoroush/assistant/pkg/records"
+	"github.com/kazemisoroush/assistant/pkg/records/storage"
+)
+
+// amountTolerance is the relative difference up to which a claimed amount matches a receipt total
+const amountTolerance = 0.01
+
+// genericWords are left out when comparing provider names
+var genericWords = []string{"dr", "the", "and", "of", "clinic", "medical", "center", "centre", "pharmacy", "practice", "hospital", "gmbh", "ltd", "inc", "llc"}
+
+// Config represents which receipts count as medical and how far apart a receipt and claim may be dated
+type Config struct {
+	MedicalCategories []string // Receipt categories of medical expenses
+	WindowDays        int      // Maximum days between the receipt date and the claimed service date
+}
+
+// RecordMatcher matches receipt and insurance records kept in storage. A claim
+// matches a receipt when it is in the same currency, its service date is
+// within the window of the receipt date, and its claimed amount or its
+// provider name agrees with the receipt. Each claim covers one receipt.
+type RecordMatcher struct {
+	storage storage.Storage
+	config  Config
+}
+
+// NewRecordMatcher creates a new claim matcher
+func NewRecordMatcher(storage storage.Storage, config Config) Matcher {
+	return &RecordMatcher{
+		storage: storage,
+		config:  config,
+	}
+}
+
+// expense is a medical receipt with its parsed date
+type expense struct {
+	Expense
+	date time.Time
+}
+
+// claim is an insurance claim with its parsed service date
+type claim struct {
+	recordID string
+	meta     records.InsuranceMetadata
+	date     time.Time
+}
+
+// candidate is a possible pairing of an expense and a claim
+type candidate struct {
+	expense int
+	claim   int
+	score   float64
+}
+
+// Match implements Matcher
+func (m *RecordMatcher) Match(ctx context.Context, status Status) (Report, error) {
+	expenses, err := m.expenses(ctx)
+	if err != nil {
+		return Report{}, err
+	}
+	claims, err := m.claims(ctx)
+	if err != nil {
+		return Report{}, err
+	}
+
+	matched := m.pair(expenses, claims)
+
+	report := Report{Matches: []Match{}, Unreimbursed: map[string]float64{}}
+	for i, e := range expenses {
+		match := Match{Expense: e.Expense, Status: StatusUnclaimed}
+		if c, ok := matched[i]; ok {
+			match.ClaimRecordID = c.recordID
+			match.Claimed = c.meta.Claimed
+			match.Reimbursed = c.meta.Reimbursed
+			match.Status = StatusPending
+			if c.meta.Reimbursed > 0 {
+				match.Status = StatusReimbursed
+			}
+		}
+		if match.Status != StatusReimbursed {
+			report.Unreimbursed[e.Currency] += e.Amount
+		}
+		if status == "" || match.Status == status {
+			report.Matches = append(report.Matches, match)
+		}
+	}
+	return report, nil
+}
+
+// expenses returns the medical receipts, oldest first
+func (m *RecordMatcher) expenses(ctx context.Context) ([]expense, error) {
+	recs, err := m.storage.List(ctx, records.RecordTypeReceipt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list receipts: %w", err)
+	}
+
+	var expenses []expense
+	for _, rec := range recs {
+		var receipt records.ReceiptMetadata
+		if err := rec.DecodeMetadata(&receipt); err != nil {
+			slog.WarnContext(ctx, "Skipping receipt with malformed metadata", "record_id", rec.ID, "error", err)
+			continue
+		}
+		if !slices.Contains(m.config.MedicalCategories, strings.ToLower(receipt.Category)) {
+			continue
+		}
+		date, err := time.Parse(time.DateOnly, receipt.Date)
+		if err != nil {
+			continue
+		}
+		expenses = append(expenses, expense{
+			Expense: Expense{
+				RecordID: rec.ID,
+				Provider: receipt.Merchant,
+				Date:     receipt.Date,
+				Amount:   receipt.Total,
+				Currency: strings.ToUpper(receipt.Currency),
+			},
+			date: date,
+		})
+	}
+	sort.SliceStable(expenses, func(i, j int) bool { return expenses[i].date.Before(expenses[j].date) })
+	return expenses, nil
+}
+
+// claims returns the insurance records that are claims or reimbursements with a service date
+func (m *RecordMatcher) claims(ctx context.Context) ([]claim, error) {
+	recs, err := m.storage.List(ctx, records.RecordTypeInsurance)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list insurance records: %w", err)
+	}
+
+	var claims []claim
+	for _, rec := range recs {
+		var meta records.InsuranceMetadata
+		if err := rec.DecodeMetadata(&meta); err != nil {
+			slog.WarnContext(ctx, "Skipping insurance record with malformed metadata", "record_id", rec.ID, "error", err)
+			continue
+		}
+		if !meta.IsClaim() {
+			continue
+		}
+		date, err := time.Parse(time.DateOnly, meta.ServiceDate)
+		if err != nil {
+			continue
+		}
+		claims = append(claims, claim{recordID: rec.ID, meta: meta, date: date})
+	}
+	return claims, nil
+}
+
+// pair assigns each expense its best scoring claim, best pairs first
+func (m *RecordMatcher) pair(expenses []expense, claims []claim) map[int]claim {
+	var candidates []candidate
+	for i, e := range expenses {
+		for j, c := range claims {
+			if score, ok := m.score(e, c); ok {
+				candidates = append(candidates, candidate{expense: i, claim: j, score: score})
+			}
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].score > candidates[j].score })
+
+	matched := map[int]claim{}
+	used := map[int]bool{}
+	for _, cand := range candidates {
+		if _, ok := matched[cand.expense]; ok || used[cand.claim] {
+			continue
+		}
+		matched[cand.expense] = claims[cand.claim]
+		used[cand.claim] = true
+	}
+	return matched
+}
+
+// score rates how well a claim fits an expense; ok is false when it cannot be the expense's claim
+func (m *RecordMatcher) score(e expense, c claim) (float64, bool) {
+	if c.meta.Currency != "" && !strings.EqualFold(c.meta.Currency, e.Currency) {
+		return 0, false
+	}
+	days := math.Abs(c.date.Sub(e.date).Hours() / 24)
+	if days > float64(m.config.WindowDays) {
+		return 0, false
+	}
+
+	amount := sameAmount(c.meta.Claimed, e.Amount)
+	provider := sameProvider(c.meta.Provider, e.Provider)
+	if !amount && !provider {
+		return 0, false
+	}
+
+	score := 1 - days/float64(m.config.WindowDays+1)
+	if amount {
+		score += 2
+	}
+	if provider {
+		score += 2
+	}
+	return score, true
+}
+
+// sameAmount reports whether a claimed amount equals a receipt total within amountTolerance
+func sameAmount(claimed, total float64) bool {
+	return claimed > 0 && math.Abs(claimed-total) <= amountTolerance*total
+}
+
+// sameProvider reports whether two provider names share a distinctive word
+func sameProvider(a, b string) bool {
+	words := providerWords(a)
+	for _, word := range providerWords(b) {
+		if slices.Contains(words, word) {
+			return true
+		}
+	}
+	return false
+}
+
+// providerWords splits a provider name into lowercase words, leaving out generic ones
+func providerWords(name string) []string {
+	var words []string
+	for _, word := range strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		if len(word) > 1 && !slices.Contains(genericWords, word) {
+			words = append(words, word)
+		}
+	}
+	return words
+}
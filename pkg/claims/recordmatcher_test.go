@@ -0,0 +1,99 @@
+package claims_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kazemisoroush/assistant/pkg/claims"
+	"github.com/kazemisoroush/assistant/pkg/records"
+	"github.com/kazemisoroush/assistant/pkg/records/storage/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+// receipt builds a receipt record with the given metadata
+func receipt(id, merchant, date, category string, total float64) records.Record {
+	return records.Record{ID: id, Type: records.RecordTypeReceipt, Metadata: map[string]interface{}{
+		"merchant": merchant, "date": date, "category": category, "total": total, "currency": "EUR",
+	}}
+}
+
+// insurance builds an insurance claim record with the given metadata
+func insurance(id, provider, serviceDate string, claimed, reimbursed float64) records.Record {
+	return records.Record{ID: id, Type: records.RecordTypeInsurance, Metadata: map[string]interface{}{
+		"provider": provider, "service_date": serviceDate, "claimed": claimed, "reimbursed": reimbursed, "currency": "EUR",
+	}}
+}
+
+func newMatcher(t *testing.T, receipts, insurances []records.Record) claims.Matcher {
+	ctrl := gomock.NewController(t)
+	storage := mocks.NewMockStorage(ctrl)
+	storage.EXPECT().List(gomock.Any(), records.RecordTypeReceipt).Return(receipts, nil)
+	storage.EXPECT().List(gomock.Any(), records.RecordTypeInsurance).Return(insurances, nil)
+	return claims.NewRecordMatcher(storage, claims.Config{MedicalCategories: []string{"health"}, WindowDays: 14})
+}
+
+func TestRecordMatcher_Match(t *testing.T) {
+	// Arrange
+	matcher := newMatcher(t,
+		[]records.Record{
+			receipt("dentist", "Dr. Weber Dental", "2024-03-01", "health", 180),
+			receipt("physio", "Physio Plus", "2024-04-10", "health", 90),
+			receipt("pharmacy", "City Apotheke", "2024-05-02", "health", 25.5),
+			receipt("groceries", "Lidl", "2024-03-01", "groceries", 180),
+		},
+		[]records.Record{
+			insurance("claim-dentist", "Weber", "2024-03-01", 180, 144),
+			insurance("claim-physio", "", "2024-04-12", 90, 0),
+			{ID: "policy", Type: records.RecordTypeInsurance, Metadata: map[string]interface{}{"document_type": "health insurance policy"}},
+		},
+	)
+
+	// Act
+	report, err := matcher.Match(context.Background(), "")
+
+	// Assert
+	require.NoError(t, err, "Match() error should be nil")
+	require.Len(t, report.Matches, 3, "Match() should only return medical receipts")
+	assert.Equal(t, "claim-dentist", report.Matches[0].ClaimRecordID, "Match() should match by provider, date and amount")
+	assert.Equal(t, claims.StatusReimbursed, report.Matches[0].Status, "Match() should flag reimbursed expenses")
+	assert.Equal(t, "claim-physio", report.Matches[1].ClaimRecordID, "Match() should match by amount within the date window")
+	assert.Equal(t, claims.StatusPending, report.Matches[1].Status, "Match() should flag claims awaiting reimbursement")
+	assert.Equal(t, claims.StatusUnclaimed, report.Matches[2].Status, "Match() should flag expenses without a claim")
+	assert.InDelta(t, 115.5, report.Unreimbursed["EUR"], 0.001, "Match() should total the unreimbursed expenses")
+}
+
+func TestRecordMatcher_Match_ClaimCoversOneReceipt(t *testing.T) {
+	// Arrange
+	matcher := newMatcher(t,
+		[]records.Record{
+			receipt("visit-1", "Dr. Weber", "2024-03-01", "health", 60),
+			receipt("visit-2", "Dr. Weber", "2024-03-08", "health", 60),
+		},
+		[]records.Record{insurance("claim-1", "Dr. Weber", "2024-03-08", 60, 0)},
+	)
+
+	// Act
+	report, err := matcher.Match(context.Background(), claims.StatusUnclaimed)
+
+	// Assert
+	require.NoError(t, err, "Match() error should be nil")
+	require.Len(t, report.Matches, 1, "Match() should filter by status")
+	assert.Equal(t, "visit-1", report.Matches[0].Expense.RecordID, "Match() should give the claim to the receipt closest to its service date")
+}
+
+func TestRecordMatcher_Match_OutsideWindow(t *testing.T) {
+	// Arrange
+	matcher := newMatcher(t,
+		[]records.Record{receipt("visit", "Dr. Weber", "2024-01-01", "health", 60)},
+		[]records.Record{insurance("claim", "Dr. Weber", "2024-03-01", 60, 60)},
+	)
+
+	// Act
+	report, err := matcher.Match(context.Background(), "")
+
+	// Assert
+	require.NoError(t, err, "Match() error should be nil")
+	assert.Equal(t, claims.StatusUnclaimed, report.Matches[0].Status, "Match() should not match claims outside the date window")
+}
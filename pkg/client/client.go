@@ -0,0 +1,137 @@
+// Package client is a Go client of the HTTP API the serve command serves, so
+// other programs can read and write the vault without speaking HTTP.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/kazemisoroush/assistant/pkg/httpclient"
+	"github.com/kazemisoroush/assistant/pkg/requestid"
+)
+
+const (
+	// DefaultMaxRetries is the number of retries of the client NewClient
+	// creates when none is given
+	DefaultMaxRetries = 3
+
+	// DefaultTimeout bounds the requests of the client NewClient creates when
+	// none is given, retries included
+	DefaultTimeout = time.Minute
+)
+
+// ErrNotFound is returned when the requested record does not exist
+var ErrNotFound = errors.New("not found")
+
+// StatusError is returned when the API answers with an error status
+type StatusError struct {
+	StatusCode int
+	Message    string
+}
+
+// Error implements error
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("api returned status %d: %s", e.StatusCode, e.Message)
+}
+
+// Unwrap lets errors.Is match ErrNotFound on 404 responses
+func (e *StatusError) Unwrap() error {
+	if e.StatusCode == http.StatusNotFound {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// Client calls the API served at a base URL, authenticating with the API
+// token. Requests failing with a transport error, 429 or 5xx are retried by
+// the HTTP client, and carry the request ID of their context.
+type Client struct {
+	client *http.Client
+	url    string
+	token  string
+}
+
+// NewClient creates a client of the API served at the base URL. A nil HTTP
+// client is replaced by one retrying DefaultMaxRetries times; pass a client
+// built with httpclient.New to tune retries and timeouts.
+func NewClient(client *http.Client, baseURL, token string) (*Client, error) {
+	if client == nil {
+		var err error
+		client, err = httpclient.New(httpclient.Config{Timeout: DefaultTimeout, MaxRetries: DefaultMaxRetries})
+		if err != nil {
+			return nil, fmt.Errorf("failed to create HTTP client: %w", err)
+		}
+	}
+	return &Client{
+		client: client,
+		url:    strings.TrimSuffix(baseURL, "/"),
+		token:  token,
+	}, nil
+}
+
+// get decodes the JSON response to a GET request of the path into v
+func (c *Client) get(ctx context.Context, path string, query url.Values, v any) error {
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+	return c.do(ctx, http.MethodGet, path, nil, v)
+}
+
+// do sends a request with the JSON body, if any, and decodes the JSON
+// response into v, if any
+func (c *Client) do(ctx context.Context, method, path string, body, v any) error {
+	if body == nil {
+		return c.send(ctx, method, path, "", nil, v)
+	}
+	data, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("failed to marshal request: %w", err)
+	}
+	return c.send(ctx, method, path, "application/json", data, v)
+}
+
+// send sends a request with the body, if any, and decodes the JSON response
+// into v, if any. The body is kept in memory so retries can replay it.
+func (c *Client) send(ctx context.Context, method, path, contentType string, body []byte, v any) error {
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.url+path, reader)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	if id := requestid.FromContext(ctx); id != "" {
+		req.Header.Set(requestid.Header, id)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode >= 300 {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return &StatusError{StatusCode: resp.StatusCode, Message: strings.TrimSpace(string(message))}
+	}
+	if v == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
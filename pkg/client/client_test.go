@@ -0,0 +1,156 @@
+package client_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/kazemisoroush/assistant/pkg/api"
+	"github.com/kazemisoroush/assistant/pkg/client"
+	"github.com/kazemisoroush/assistant/pkg/records"
+	discoverymocks "github.com/kazemisoroush/assistant/pkg/records/discovery/mocks"
+	ingestormocks "github.com/kazemisoroush/assistant/pkg/records/ingestor/mocks"
+	"github.com/kazemisoroush/assistant/pkg/records/storage"
+	storagemocks "github.com/kazemisoroush/assistant/pkg/records/storage/mocks"
+	relationsmocks "github.com/kazemisoroush/assistant/pkg/relations/mocks"
+	"github.com/kazemisoroush/assistant/pkg/requestid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+// serveRecords serves the records API of the storage behind the API token
+func serveRecords(t *testing.T, recordStorage storage.Storage) *client.Client {
+	t.Helper()
+	ctrl := gomock.NewController(t)
+	handler := api.RequireToken("secret", api.NewRecordsHandler(recordStorage, ingestormocks.NewMockService(ctrl), discoverymocks.NewMockDiscovery(ctrl), relationsmocks.NewMockService(ctrl)))
+	mux := http.NewServeMux()
+	mux.Handle(api.RecordsPath, handler)
+	mux.Handle(api.RecordPath, handler)
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	c, err := client.NewClient(server.Client(), server.URL, "secret")
+	require.NoError(t, err, "NewClient() error should be nil")
+	return c
+}
+
+func TestClient_Record(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	recordStorage := storagemocks.NewMockStorage(ctrl)
+	recordStorage.EXPECT().Get(gomock.Any(), "r 1").Return(records.Record{ID: "r 1", Content: "Coffee"}, nil)
+	recordStorage.EXPECT().Get(gomock.Any(), "missing").Return(records.Record{}, storage.ErrNotFound)
+	c := serveRecords(t, recordStorage)
+
+	// Act
+	got, err := c.Record(context.Background(), "r 1")
+	_, missingErr := c.Record(context.Background(), "missing")
+
+	// Assert
+	require.NoError(t, err, "Record() error should be nil")
+	assert.Equal(t, "Coffee", got.Content, "Record() should return the record")
+	assert.True(t, errors.Is(missingErr, client.ErrNotFound), "Record() should report missing records as not found")
+}
+
+func TestClient_EachRecord(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	recordStorage := storagemocks.NewMockStorage(ctrl)
+	var stored []records.Record
+	for i := range client.DefaultPageSize + 5 {
+		stored = append(stored, records.Record{ID: fmt.Sprintf("r%d", i)})
+	}
+	recordStorage.EXPECT().List(gomock.Any(), records.RecordTypeReceipt).Return(stored, nil).Times(2)
+	c := serveRecords(t, recordStorage)
+
+	// Act
+	var ids []string
+	err := c.EachRecord(context.Background(), records.RecordTypeReceipt, func(rec records.Record) error {
+		ids = append(ids, rec.ID)
+		return nil
+	})
+
+	// Assert
+	require.NoError(t, err, "EachRecord() error should be nil")
+	require.Len(t, ids, len(stored), "EachRecord() should page through every record")
+	assert.Equal(t, "r104", ids[len(ids)-1], "EachRecord() should keep the storage order")
+}
+
+func TestClient_Unauthorized(t *testing.T) {
+	// Arrange
+	server := httptest.NewServer(api.RequireToken("secret", http.NotFoundHandler()))
+	defer server.Close()
+	c, err := client.NewClient(server.Client(), server.URL, "wrong")
+	require.NoError(t, err, "NewClient() error should be nil")
+
+	// Act
+	_, err = c.Dashboard(context.Background())
+
+	// Assert
+	var statusErr *client.StatusError
+	require.True(t, errors.As(err, &statusErr), "Dashboard() should return the error status")
+	assert.Equal(t, http.StatusUnauthorized, statusErr.StatusCode, "the status should be 401")
+	assert.False(t, errors.Is(err, client.ErrNotFound), "only 404 should match ErrNotFound")
+}
+
+func TestClient_Retries(t *testing.T) {
+	// Arrange
+	attempts := 0
+	var gotID, gotAuth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts == 1 {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		gotID = r.Header.Get(requestid.Header)
+		gotAuth = r.Header.Get("Authorization")
+		_, _ = w.Write([]byte(`{"id":"api-1","content":"Coffee"}`))
+	}))
+	defer server.Close()
+	c, err := client.NewClient(nil, server.URL+"/", "secret")
+	require.NoError(t, err, "NewClient() error should be nil")
+	ctx := requestid.WithID(context.Background(), "req-1")
+
+	// Act
+	rec, err := c.CreateRecord(ctx, api.RecordRequest{Type: records.RecordTypeReceipt, Content: "Coffee"})
+
+	// Assert
+	require.NoError(t, err, "CreateRecord() error should be nil")
+	assert.Equal(t, "api-1", rec.ID, "CreateRecord() should return the created record")
+	assert.Equal(t, 2, attempts, "the default client should retry unavailable servers")
+	assert.Equal(t, "req-1", gotID, "the request ID should be sent")
+	assert.Equal(t, "Bearer secret", gotAuth, "the token should be sent")
+}
+
+func TestClient_Upload(t *testing.T) {
+	// Arrange
+	var gotName string
+	var gotContent []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		file, header, err := r.FormFile("file")
+		require.NoError(t, err, "the upload should carry a file part")
+		gotName = header.Filename
+		gotContent, _ = io.ReadAll(file)
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(api.UploadResponse{Records: []api.UploadedRecord{{FileName: header.Filename, ID: "ocr-1"}}})
+	}))
+	defer server.Close()
+	c, err := client.NewClient(server.Client(), server.URL, "secret")
+	require.NoError(t, err, "NewClient() error should be nil")
+
+	// Act
+	got, err := c.Upload(context.Background(), "receipt.png", strings.NewReader("png"))
+
+	// Assert
+	require.NoError(t, err, "Upload() error should be nil")
+	assert.Equal(t, "ocr-1", got.ID, "Upload() should return the created record")
+	assert.Equal(t, "receipt.png", gotName, "Upload() should send the file name")
+	assert.Equal(t, "png", string(gotContent), "Upload() should send the content")
+}
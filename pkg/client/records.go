@@ -0,0 +1,152 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"net/url"
+	"strconv"
+
+	"github.com/kazemisoroush/assistant/pkg/api"
+	"github.com/kazemisoroush/assistant/pkg/records"
+)
+
+// DefaultPageSize is the number of records EachRecord fetches per request
+const DefaultPageSize = 100
+
+// ListOptions selects the records ListRecords returns
+type ListOptions struct {
+	Type   records.RecordType // All types when empty
+	Limit  int                // All records when 0
+	Offset int
+}
+
+// CreateRecord creates a record, which is indexed for search right away
+func (c *Client) CreateRecord(ctx context.Context, req api.RecordRequest) (records.Record, error) {
+	var rec records.Record
+	if err := c.do(ctx, http.MethodPost, api.RecordsPath, req, &rec); err != nil {
+		return records.Record{}, fmt.Errorf("failed to create record: %w", err)
+	}
+	return rec, nil
+}
+
+// Record returns the record with the ID, or an error matching ErrNotFound
+func (c *Client) Record(ctx context.Context, id string) (records.Record, error) {
+	var rec records.Record
+	if err := c.do(ctx, http.MethodGet, recordPath(id), nil, &rec); err != nil {
+		return records.Record{}, fmt.Errorf("failed to get record %s: %w", id, err)
+	}
+	return rec, nil
+}
+
+// UpdateRecord replaces the type, content, metadata, tags and expiry of the record with the ID
+func (c *Client) UpdateRecord(ctx context.Context, id string, req api.RecordRequest) (records.Record, error) {
+	var rec records.Record
+	if err := c.do(ctx, http.MethodPut, recordPath(id), req, &rec); err != nil {
+		return records.Record{}, fmt.Errorf("failed to update record %s: %w", id, err)
+	}
+	return rec, nil
+}
+
+// DeleteRecord deletes the record with the ID
+func (c *Client) DeleteRecord(ctx context.Context, id string) error {
+	if err := c.do(ctx, http.MethodDelete, recordPath(id), nil, nil); err != nil {
+		return fmt.Errorf("failed to delete record %s: %w", id, err)
+	}
+	return nil
+}
+
+// ListRecords returns a page of records. The response's Next is the offset
+// of the next page, 0 on the last page.
+func (c *Client) ListRecords(ctx context.Context, opts ListOptions) (api.RecordsResponse, error) {
+	query := url.Values{}
+	if opts.Type != "" {
+		query.Set("type", string(opts.Type))
+	}
+	if opts.Limit > 0 {
+		query.Set("limit", strconv.Itoa(opts.Limit))
+	}
+	if opts.Offset > 0 {
+		query.Set("offset", strconv.Itoa(opts.Offset))
+	}
+	var resp api.RecordsResponse
+	if err := c.get(ctx, api.RecordsPath, query, &resp); err != nil {
+		return api.RecordsResponse{}, fmt.Errorf("failed to list records: %w", err)
+	}
+	return resp, nil
+}
+
+// EachRecord calls fn with every record of the type, or of all types when
+// empty, fetching them a page at a time. Iteration stops at the first error
+// fn returns, which EachRecord returns.
+func (c *Client) EachRecord(ctx context.Context, recType records.RecordType, fn func(records.Record) error) error {
+	opts := ListOptions{Type: recType, Limit: DefaultPageSize}
+	for {
+		page, err := c.ListRecords(ctx, opts)
+		if err != nil {
+			return err
+		}
+		for _, rec := range page.Records {
+			if err := fn(rec); err != nil {
+				return err
+			}
+		}
+		if page.Next == 0 {
+			return nil
+		}
+		opts.Offset = page.Next
+	}
+}
+
+// Search returns at most limit records matching the query, the server's
+// default number of them when limit is 0
+func (c *Client) Search(ctx context.Context, q string, limit int) (api.SearchResponse, error) {
+	query := url.Values{}
+	query.Set("q", q)
+	if limit > 0 {
+		query.Set("limit", strconv.Itoa(limit))
+	}
+	var resp api.SearchResponse
+	if err := c.get(ctx, api.RecordsPath, query, &resp); err != nil {
+		return api.SearchResponse{}, fmt.Errorf("failed to search records: %w", err)
+	}
+	return resp, nil
+}
+
+// Upload uploads an image or PDF, which is extracted into a record and
+// ingested. The server detects the media type from the content.
+func (c *Client) Upload(ctx context.Context, fileName string, content io.Reader) (api.UploadedRecord, error) {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	header := textproto.MIMEHeader{}
+	header.Set("Content-Disposition", mime.FormatMediaType("form-data", map[string]string{"name": "file", "filename": fileName}))
+	part, err := writer.CreatePart(header)
+	if err != nil {
+		return api.UploadedRecord{}, fmt.Errorf("failed to create upload: %w", err)
+	}
+	if _, err := io.Copy(part, content); err != nil {
+		return api.UploadedRecord{}, fmt.Errorf("failed to read %s: %w", fileName, err)
+	}
+	if err := writer.Close(); err != nil {
+		return api.UploadedRecord{}, fmt.Errorf("failed to create upload: %w", err)
+	}
+
+	var resp api.UploadResponse
+	if err := c.send(ctx, http.MethodPost, api.UploadPath, writer.FormDataContentType(), body.Bytes(), &resp); err != nil {
+		return api.UploadedRecord{}, fmt.Errorf("failed to upload %s: %w", fileName, err)
+	}
+	if len(resp.Records) != 1 {
+		return api.UploadedRecord{}, fmt.Errorf("failed to upload %s: got %d records", fileName, len(resp.Records))
+	}
+	return resp.Records[0], nil
+}
+
+// recordPath is the path of the record with the ID
+func recordPath(id string) string {
+	return api.RecordsPath + "/" + url.PathEscape(id)
+}
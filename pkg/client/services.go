@@ -0,0 +1,161 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/kazemisoroush/assistant/pkg/ai"
+	"github.com/kazemisoroush/assistant/pkg/analytics"
+	"github.com/kazemisoroush/assistant/pkg/api"
+	"github.com/kazemisoroush/assistant/pkg/budgets"
+	"github.com/kazemisoroush/assistant/pkg/claims"
+	"github.com/kazemisoroush/assistant/pkg/dashboard"
+	"github.com/kazemisoroush/assistant/pkg/duplicates"
+	"github.com/kazemisoroush/assistant/pkg/entities"
+	"github.com/kazemisoroush/assistant/pkg/health"
+	"github.com/kazemisoroush/assistant/pkg/records"
+	"github.com/kazemisoroush/assistant/pkg/trips"
+	"github.com/kazemisoroush/assistant/pkg/vehicles"
+)
+
+// Dashboard returns the overview of the vault
+func (c *Client) Dashboard(ctx context.Context) (dashboard.Dashboard, error) {
+	var overview dashboard.Dashboard
+	if err := c.get(ctx, api.DashboardPath, nil, &overview); err != nil {
+		return dashboard.Dashboard{}, fmt.Errorf("failed to get dashboard: %w", err)
+	}
+	return overview, nil
+}
+
+// Spend returns the spend of the receipts matching the filter
+func (c *Client) Spend(ctx context.Context, filter analytics.SpendFilter) (analytics.SpendReport, error) {
+	query := url.Values{}
+	if filter.Year > 0 {
+		query.Set("year", strconv.Itoa(filter.Year))
+	}
+	for key, value := range map[string]string{"category": filter.Category, "vendor": filter.Vendor, "person": filter.Person} {
+		if value != "" {
+			query.Set(key, value)
+		}
+	}
+	var report analytics.SpendReport
+	if err := c.get(ctx, api.SpendPath, query, &report); err != nil {
+		return analytics.SpendReport{}, fmt.Errorf("failed to get spend: %w", err)
+	}
+	return report, nil
+}
+
+// Subscriptions returns the recurring charges found in receipts
+func (c *Client) Subscriptions(ctx context.Context) (analytics.SubscriptionReport, error) {
+	var report analytics.SubscriptionReport
+	if err := c.get(ctx, api.SubscriptionsPath, nil, &report); err != nil {
+		return analytics.SubscriptionReport{}, fmt.Errorf("failed to get subscriptions: %w", err)
+	}
+	return report, nil
+}
+
+// HealthTimelines returns the health timelines of the person, or of everyone when empty
+func (c *Client) HealthTimelines(ctx context.Context, person string) ([]health.Timeline, error) {
+	var timelines []health.Timeline
+	if err := c.get(ctx, api.HealthTimelinePath, optional("person", person), &timelines); err != nil {
+		return nil, fmt.Errorf("failed to get health timelines: %w", err)
+	}
+	return timelines, nil
+}
+
+// Entities returns the entities of the kind, or of all kinds when empty
+func (c *Client) Entities(ctx context.Context, kind entities.Kind) ([]entities.Summary, error) {
+	var summaries []entities.Summary
+	if err := c.get(ctx, api.EntitiesPath, optional("kind", string(kind)), &summaries); err != nil {
+		return nil, fmt.Errorf("failed to get entities: %w", err)
+	}
+	return summaries, nil
+}
+
+// EntityRecords returns the records naming the entity
+func (c *Client) EntityRecords(ctx context.Context, name string) ([]records.Record, error) {
+	var recs []records.Record
+	if err := c.get(ctx, api.EntitiesPath, optional("name", name), &recs); err != nil {
+		return nil, fmt.Errorf("failed to get records of entity %s: %w", name, err)
+	}
+	return recs, nil
+}
+
+// Claims returns the medical expenses and their claims in the status, or in any when empty
+func (c *Client) Claims(ctx context.Context, status claims.Status) (claims.Report, error) {
+	var report claims.Report
+	if err := c.get(ctx, api.ClaimsPath, optional("status", string(status)), &report); err != nil {
+		return claims.Report{}, fmt.Errorf("failed to get claims: %w", err)
+	}
+	return report, nil
+}
+
+// Trips returns the trips clustered from travel records
+func (c *Client) Trips(ctx context.Context) ([]trips.Trip, error) {
+	var found []trips.Trip
+	if err := c.get(ctx, api.TripsPath, nil, &found); err != nil {
+		return nil, fmt.Errorf("failed to get trips: %w", err)
+	}
+	return found, nil
+}
+
+// Budgets returns the status of every budget
+func (c *Client) Budgets(ctx context.Context) ([]budgets.Status, error) {
+	var statuses []budgets.Status
+	if err := c.get(ctx, api.BudgetsPath, nil, &statuses); err != nil {
+		return nil, fmt.Errorf("failed to get budgets: %w", err)
+	}
+	return statuses, nil
+}
+
+// Vehicles returns the vehicles found in records
+func (c *Client) Vehicles(ctx context.Context) ([]vehicles.Vehicle, error) {
+	var found []vehicles.Vehicle
+	if err := c.get(ctx, api.VehiclesPath, nil, &found); err != nil {
+		return nil, fmt.Errorf("failed to get vehicles: %w", err)
+	}
+	return found, nil
+}
+
+// Duplicates returns the pairs of records likely to be duplicates
+func (c *Client) Duplicates(ctx context.Context) ([]duplicates.Candidate, error) {
+	var candidates []duplicates.Candidate
+	if err := c.get(ctx, api.DuplicatesPath, nil, &candidates); err != nil {
+		return nil, fmt.Errorf("failed to get duplicates: %w", err)
+	}
+	return candidates, nil
+}
+
+// MergeDuplicates merges the duplicate into the kept record, returning the merged record
+func (c *Client) MergeDuplicates(ctx context.Context, keepID, duplicateID string) (records.Record, error) {
+	var merged records.Record
+	candidate := duplicates.Candidate{KeepID: keepID, DuplicateID: duplicateID}
+	if err := c.do(ctx, http.MethodPost, api.MergePath, candidate, &merged); err != nil {
+		return records.Record{}, fmt.Errorf("failed to merge record %s into %s: %w", duplicateID, keepID, err)
+	}
+	return merged, nil
+}
+
+// AIUsage returns the AI usage of the last days, the server's default number of them when 0
+func (c *Client) AIUsage(ctx context.Context, days int) (ai.UsageReport, error) {
+	query := url.Values{}
+	if days > 0 {
+		query.Set("days", strconv.Itoa(days))
+	}
+	var report ai.UsageReport
+	if err := c.get(ctx, api.UsagePath, query, &report); err != nil {
+		return ai.UsageReport{}, fmt.Errorf("failed to get AI usage: %w", err)
+	}
+	return report, nil
+}
+
+// optional returns the query parameter, or no query when the value is empty
+func optional(key, value string) url.Values {
+	if value == "" {
+		return nil
+	}
+	return url.Values{key: {value}}
+}
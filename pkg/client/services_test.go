@@ -0,0 +1,58 @@
+package client_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kazemisoroush/assistant/pkg/analytics"
+	"github.com/kazemisoroush/assistant/pkg/api"
+	"github.com/kazemisoroush/assistant/pkg/client"
+	"github.com/kazemisoroush/assistant/pkg/duplicates"
+	"github.com/kazemisoroush/assistant/pkg/records"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_Spend(t *testing.T) {
+	// Arrange
+	var gotPath, gotQuery string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		gotQuery = r.URL.RawQuery
+		_ = json.NewEncoder(w).Encode(analytics.SpendReport{})
+	}))
+	defer server.Close()
+	c, err := client.NewClient(server.Client(), server.URL, "secret")
+	require.NoError(t, err, "NewClient() error should be nil")
+
+	// Act
+	_, err = c.Spend(context.Background(), analytics.SpendFilter{Year: 2024, Category: "groceries"})
+
+	// Assert
+	require.NoError(t, err, "Spend() error should be nil")
+	assert.Equal(t, api.SpendPath, gotPath, "Spend() should call the spend endpoint")
+	assert.Equal(t, "category=groceries&year=2024", gotQuery, "Spend() should send the set filters only")
+}
+
+func TestClient_MergeDuplicates(t *testing.T) {
+	// Arrange
+	var got duplicates.Candidate
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&got)
+		_ = json.NewEncoder(w).Encode(records.Record{ID: got.KeepID})
+	}))
+	defer server.Close()
+	c, err := client.NewClient(server.Client(), server.URL, "secret")
+	require.NoError(t, err, "NewClient() error should be nil")
+
+	// Act
+	merged, err := c.MergeDuplicates(context.Background(), "keep", "dup")
+
+	// Assert
+	require.NoError(t, err, "MergeDuplicates() error should be nil")
+	assert.Equal(t, duplicates.Candidate{KeepID: "keep", DuplicateID: "dup"}, got, "MergeDuplicates() should send the pair")
+	assert.Equal(t, "keep", merged.ID, "MergeDuplicates() should return the merged record")
+}
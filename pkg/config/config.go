@@ -6,26 +6,355 @@ import (
 	"fmt"
 	"log/slog"
 	"os"
+	"path/filepath"
+	"slices"
 	"strings"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/caarlos0/env/v11"
+	"github.com/kazemisoroush/assistant/pkg/requestid"
 )
 
 // Config represents the configuration for the application
 type Config struct {
 	Timeout    time.Duration `env:"TIMEOUT" envDefault:"180s"`
 	LogLevel   string        `env:"LOG_LEVEL" envDefault:"info"`
-	AWSConfig  aws.Config    // Loaded using AWS SDK, not from env
+	AWSConfig  aws.Config    // Loaded using AWS SDK by LoadAWSConfig, not from env
 	SQLitePath string        `env:"SQLITE_PATH" envDefault:"./data/assistant.db"`
 
+	// Backend selection for record storage and vector storage; the "remote"
+	// storage backend keeps records encrypted on an assistant server. The
+	// vector backend is selected by VECTOR_PROVIDER or VECTOR_BACKEND, which
+	// must agree when both are set, and is local when neither is.
+	StorageBackend string `env:"STORAGE_BACKEND" envDefault:"sqlite"`
+	VectorBackend  string `env:"VECTOR_BACKEND"`
+
+	// Vector backend selection and the servers of the remote vector backends
+	Vector VectorConfig `envPrefix:"VECTOR_"`
+
+	// Files the local vector index is persisted in, as <path>.vec and <path>.ids; kept in memory only when empty.
+	// The database of the sqlite vector backend, SQLITE_PATH when empty.
+	VectorIndexPath string `env:"VECTOR_INDEX_PATH"`
+
+	// Store the content of records compressed; records stored before are still read
+	CompressContent bool `env:"STORAGE_COMPRESS_CONTENT" envDefault:"false"`
+
+	// File the local-json storage backend keeps records in
+	JSONPath string `env:"STORAGE_JSON_PATH" envDefault:"./data/records.json"`
+
 	// AI configuration (organized by provider)
 	AI AIConfig `envPrefix:"AI_"`
 
 	// Records configuration
 	Sources SourcesConfig `envPrefix:"SOURCES_"`
+
+	// Executables providing additional sources and extractors
+	Plugins PluginsConfig `envPrefix:"PLUGINS_"`
+
+	// Directories the watch command ingests files from as they change
+	Watch WatchConfig `envPrefix:"WATCH_"`
+
+	// Workers and queues of the scrape stages
+	Pipeline PipelineConfig `envPrefix:"PIPELINE_"`
+
+	// Security configuration
+	Security SecurityConfig `envPrefix:"SECURITY_"`
+
+	// Outbound HTTP configuration shared by all clients
+	HTTP HTTPConfig `envPrefix:"HTTP_"`
+
+	// Circuit breaker around remote AI providers and vector stores
+	Breaker BreakerConfig `envPrefix:"BREAKER_"`
+
+	// Expiry reminders for time-bound documents
+	Reminders RemindersConfig `envPrefix:"REMINDERS_"`
+
+	// Tax-year report generation
+	TaxReport TaxReportConfig `envPrefix:"TAX_REPORT_"`
+
+	// Markdown export into an Obsidian vault
+	Obsidian ObsidianConfig `envPrefix:"OBSIDIAN_"`
+
+	// Matching of medical receipts to insurance claims
+	Claims ClaimsConfig `envPrefix:"CLAIMS_"`
+
+	// Near-duplicate detection across re-scans and copies of the same document
+	Duplicates DuplicatesConfig `envPrefix:"DUPLICATES_"`
+
+	// Thumbnails of scanned images and PDFs generated at ingest time
+	Thumbnails ThumbnailsConfig `envPrefix:"THUMBNAILS_"`
+
+	// Local OCR of images with Tesseract, used unless vision extraction is enabled
+	OCR OCRConfig `envPrefix:"OCR_"`
+
+	// Text of PDFs, read from their text layer or by OCR of their rendered pages
+	PDF PDFConfig `envPrefix:"PDF_"`
+
+	// Clustering of travel records into trips
+	Trips TripsConfig `envPrefix:"TRIPS_"`
+
+	// Monthly category budgets and their spend alerts
+	Budgets BudgetsConfig `envPrefix:"BUDGETS_"`
+
+	// Vehicle maintenance intervals and service-due reminders
+	Vehicles VehiclesConfig `envPrefix:"VEHICLES_"`
+
+	// Overview of upcoming obligations and recent activity
+	Dashboard DashboardConfig `envPrefix:"DASHBOARD_"`
+
+	// iCalendar feed and export of important dates
+	Calendar CalendarConfig `envPrefix:"CALENDAR_"`
+
+	// Channels delivering reminders, budget alerts and scrape failure reports
+	Notifications NotificationsConfig `envPrefix:"NOTIFY_"`
+
+	// Slack app answering the /assistant slash command and ingesting uploads
+	Slack SlackConfig `envPrefix:"SLACK_"`
+
+	// Telegram bot answering searches and questions
+	Telegram TelegramConfig `envPrefix:"TELEGRAM_"`
+
+	// Sync of records with other instances
+	Sync SyncConfig `envPrefix:"SYNC_"`
+
+	// Server keeping the encrypted records of the remote storage backend
+	Remote RemoteConfig `envPrefix:"REMOTE_"`
+
+	// HTTP API served by the serve command
+	API APIConfig `envPrefix:"API_"`
+}
+
+// RemoteConfig represents the server records are kept on by the remote
+// storage backend, and the storage API the remote-server command serves.
+// Clients encrypt records with the SECURITY_ key before sending them and
+// index and search them locally, so the server only holds ciphertext.
+type RemoteConfig struct {
+	URL   string `env:"URL"`                     // Base URL of the server the remote storage backend uses
+	Token string `env:"TOKEN"`                   // Shared by the server and its clients; the storage API is disabled when empty
+	Addr  string `env:"ADDR" envDefault:":8070"` // Address the remote-server command serves the storage API on
+}
+
+// VectorConfig represents the vector backend selection and the servers of
+// the remote vector backends. The provider is one of local, sqlite, chroma
+// and qdrant; VECTOR_BACKEND selects it when empty.
+type VectorConfig struct {
+	Provider string `env:"PROVIDER"`
+
+	Chunk  ChunkConfig  `envPrefix:"CHUNK_"`
+	Chroma ChromaConfig `envPrefix:"CHROMA_"`
+	Qdrant QdrantConfig `envPrefix:"QDRANT_"`
+}
+
+// ChunkConfig represents how the content of long records is split into
+// overlapping chunks, counted in words, each embedded on its own. Records are
+// embedded whole when the size is 0.
+type ChunkConfig struct {
+	Size    int `env:"SIZE" envDefault:"300"`
+	Overlap int `env:"OVERLAP" envDefault:"50"`
+}
+
+// ChromaConfig represents the Chroma server and collection the chroma vector
+// backend keeps embeddings in. The token is sent as a bearer token when set.
+type ChromaConfig struct {
+	URL        string `env:"URL" envDefault:"http://localhost:8000"`
+	Token      string `env:"TOKEN"`
+	Tenant     string `env:"TENANT" envDefault:"default_tenant"`
+	Database   string `env:"DATABASE" envDefault:"default_database"`
+	Collection string `env:"COLLECTION" envDefault:"records"`
+}
+
+// QdrantConfig represents the Qdrant server and collection the qdrant vector
+// backend keeps embeddings in, over its REST API. The API key is sent when set.
+type QdrantConfig struct {
+	URL        string `env:"URL" envDefault:"http://localhost:6333"`
+	APIKey     string `env:"API_KEY"`
+	Collection string `env:"COLLECTION" envDefault:"records"`
+}
+
+// APIConfig represents the HTTP API the serve command serves. Clients
+// authenticate with the token as a bearer token; the command refuses to
+// start without one. Debug serves the pprof and expvar endpoints as well.
+type APIConfig struct {
+	Addr  string `env:"ADDR" envDefault:":8000"`
+	Token string `env:"TOKEN"`
+	Debug bool   `env:"DEBUG" envDefault:"false"`
+}
+
+// SyncConfig represents the instances records are synced with. Sync is
+// disabled until the token shared by the instances is set; the node ID must
+// differ between them and never change, and defaults to the host name.
+type SyncConfig struct {
+	Token     string   `env:"TOKEN"`
+	Node      string   `env:"NODE"`
+	Peers     []string `env:"PEERS" envSeparator:","`      // Base URLs of the peers' sync servers
+	Addr      string   `env:"ADDR" envDefault:":8090"`     // Address the sync-server command serves changes on
+	BatchSize int      `env:"BATCH_SIZE" envDefault:"100"` // Changes exchanged per request
+}
+
+// TelegramConfig represents the Telegram bot run by the telegram command. The
+// bot is disabled until its token and allowed chats are set; it answers no
+// other chats, as its answers disclose the records.
+type TelegramConfig struct {
+	BotToken     string        `env:"BOT_TOKEN"`
+	AllowedChats []int64       `env:"ALLOWED_CHATS" envSeparator:","`
+	PollTimeout  time.Duration `env:"POLL_TIMEOUT" envDefault:"30s"` // Must stay below HTTP_TIMEOUT when it is set
+}
+
+// SlackConfig represents the Slack app served by the slack command. The app
+// is disabled until its bot token and signing secret are set.
+type SlackConfig struct {
+	Addr          string `env:"ADDR" envDefault:":8080"` // Address the slash command and event endpoints are served on
+	BotToken      string `env:"BOT_TOKEN"`
+	SigningSecret string `env:"SIGNING_SECRET"`
+	Channel       string `env:"CHANNEL"`    // ID of the channel whose uploaded files are ingested
+	RecordURL     string `env:"RECORD_URL"` // Base URL records are linked under in replies
+}
+
+// NotificationsConfig represents the notification channels and their templates.
+// Each channel is disabled until its destination is set and receives every
+// event unless its events are listed.
+type NotificationsConfig struct {
+	TemplatesDir string              `env:"TEMPLATES_DIR"` // <event>.subject.tmpl and <event>.body.tmpl override the built-in templates
+	Email        NotifyEmailConfig   `envPrefix:"EMAIL_"`
+	Ntfy         NtfyConfig          `envPrefix:"NTFY_"`
+	Pushover     PushoverConfig      `envPrefix:"PUSHOVER_"`
+	Webhook      NotifyWebhookConfig `envPrefix:"WEBHOOK_"`
+}
+
+// NotifyEmailConfig represents the SMTP settings of the email channel
+type NotifyEmailConfig struct {
+	EmailConfig
+	Events []string `env:"EVENTS" envSeparator:","`
+}
+
+// NtfyConfig represents the ntfy topic push notifications are published to
+type NtfyConfig struct {
+	URL    string   `env:"URL" envDefault:"https://ntfy.sh"`
+	Topic  string   `env:"TOPIC"`
+	Token  string   `env:"TOKEN"`
+	Events []string `env:"EVENTS" envSeparator:","`
+}
+
+// PushoverConfig represents the Pushover application and user push notifications are sent with and to
+type PushoverConfig struct {
+	Token  string   `env:"TOKEN"`
+	User   string   `env:"USER"`
+	Events []string `env:"EVENTS" envSeparator:","`
+}
+
+// NotifyWebhookConfig represents the URL notifications are posted to as JSON
+type NotifyWebhookConfig struct {
+	URL    string   `env:"URL"`
+	Events []string `env:"EVENTS" envSeparator:","`
+}
+
+// CalendarConfig represents who may subscribe to the calendar feed and when renewals are reminded
+type CalendarConfig struct {
+	FeedToken    string `env:"FEED_TOKEN"`                                     // The feed is disabled when empty
+	ReminderDays []int  `env:"REMINDER_DAYS" envSeparator:"," envDefault:"30"` // Days before an expiry a renewal event is added
+}
+
+// DashboardConfig represents how far ahead the dashboard looks and how many recent records it lists
+type DashboardConfig struct {
+	WindowDays  int `env:"WINDOW_DAYS" envDefault:"30"`
+	RecentLimit int `env:"RECENT_LIMIT" envDefault:"10"`
+}
+
+// VehiclesConfig represents the service intervals of car records and how early services are reminded
+type VehiclesConfig struct {
+	LeadDays int `env:"LEAD_DAYS" envDefault:"30"`
+
+	// Service type to months and to distance between services; a service is due at whichever comes first
+	ServiceMonths   map[string]int `env:"SERVICE_MONTHS" envKeyValSeparator:"=" envDefault:"oil_change=12,inspection=24"`
+	ServiceDistance map[string]int `env:"SERVICE_DISTANCE" envKeyValSeparator:"=" envDefault:"oil_change=15000,tires=40000,brakes=50000"`
+}
+
+// BudgetsConfig represents when budget alerts are sent and where, besides the reminders email
+type BudgetsConfig struct {
+	Thresholds []int  `env:"THRESHOLDS" envSeparator:"," envDefault:"80,100"` // Percentages of a budget
+	WebhookURL string `env:"WEBHOOK_URL"`                                     // Alerts are posted as webhook notifications when set
+}
+
+// TripsConfig represents how far apart bookings of one trip may be and which country is home
+type TripsConfig struct {
+	GapDays     int    `env:"GAP_DAYS" envDefault:"2"`
+	HomeCountry string `env:"HOME_COUNTRY"` // ISO 3166-1 alpha-2 code; bookings back home end a trip
+}
+
+// ThumbnailsConfig represents whether, where and how large record thumbnails are generated
+type ThumbnailsConfig struct {
+	Enabled     bool   `env:"ENABLED" envDefault:"true"`
+	Dir         string `env:"DIR" envDefault:"./data/thumbnails"`
+	MaxSize     int    `env:"MAX_SIZE" envDefault:"256"`          // Longest side in pixels
+	PDFRenderer string `env:"PDF_RENDERER" envDefault:"pdftoppm"` // Poppler's pdftoppm; empty disables PDF thumbnails
+}
+
+// DuplicatesConfig represents how similar two records must be to be reported
+// as duplicates, and what is done with records found to be duplicates as
+// they are ingested
+type DuplicatesConfig struct {
+	TextThreshold      float64 `env:"TEXT_THRESHOLD" envDefault:"0.8"`
+	EmbeddingThreshold float64 `env:"EMBEDDING_THRESHOLD" envDefault:"0.97"` // 0 disables embedding comparison
+	OnIngest           string  `env:"ON_INGEST" envDefault:"off"`            // "off", "skip", "merge" or "flag"
+}
+
+// ClaimsConfig represents which receipts are medical expenses and how closely claims must be dated to them
+type ClaimsConfig struct {
+	MedicalCategories []string `env:"MEDICAL_CATEGORIES" envSeparator:"," envDefault:"health,medical,pharmacy,dental"`
+	WindowDays        int      `env:"WINDOW_DAYS" envDefault:"14"`
+}
+
+// ObsidianConfig represents where records are exported as Markdown notes and
+// whether their original files are copied along
+type ObsidianConfig struct {
+	VaultDir    string `env:"VAULT_DIR" envDefault:"./data/vault"`
+	Attachments bool   `env:"ATTACHMENTS" envDefault:"true"`
+}
+
+// TaxReportConfig represents which records go into tax reports and where they are written
+type TaxReportConfig struct {
+	FiscalYearStartMonth int    `env:"FISCAL_YEAR_START_MONTH" envDefault:"1"`
+	OutputDir            string `env:"OUTPUT_DIR" envDefault:"./data/reports"`
+
+	// Receipt category to deduction, e.g. "health=medical,office=work_equipment"
+	Deductions map[string]string `env:"DEDUCTIONS" envKeyValSeparator:"="`
+}
+
+// RemindersConfig represents when and where expiry reminders are sent
+type RemindersConfig struct {
+	LeadDays   []int       `env:"LEAD_DAYS" envSeparator:"," envDefault:"90,30,7,1"`
+	WebhookURL string      `env:"WEBHOOK_URL"` // Reminders are posted as webhook notifications when set
+	Email      EmailConfig `envPrefix:"EMAIL_"`
+}
+
+// EmailConfig represents the SMTP settings for emailed reminders and budget alerts; email is disabled when To is empty
+type EmailConfig struct {
+	SMTPHost string `env:"SMTP_HOST"`
+	SMTPPort int    `env:"SMTP_PORT" envDefault:"587"`
+	Username string `env:"USERNAME"`
+	Password string `env:"PASSWORD"`
+	From     string `env:"FROM"`
+	To       string `env:"TO"`
+}
+
+// BreakerConfig represents the circuit breaker settings shared by remote dependencies
+type BreakerConfig struct {
+	FailureThreshold int           `env:"FAILURE_THRESHOLD" envDefault:"5"` // 0 disables the breaker
+	OpenTimeout      time.Duration `env:"OPEN_TIMEOUT" envDefault:"30s"`
+}
+
+// HTTPConfig represents tuning for outbound HTTP calls (Ollama, embedders, webhooks, remote sources).
+// Without a timeout, calls are bounded by the command's TIMEOUT.
+type HTTPConfig struct {
+	Timeout             time.Duration `env:"TIMEOUT" envDefault:"0s"`
+	ProxyURL            string        `env:"PROXY_URL"`
+	MaxRetries          int           `env:"MAX_RETRIES" envDefault:"2"`
+	InsecureSkipVerify  bool          `env:"TLS_SKIP_VERIFY" envDefault:"false"`
+	MaxIdleConns        int           `env:"MAX_IDLE_CONNS" envDefault:"100"`
+	MaxIdleConnsPerHost int           `env:"MAX_IDLE_CONNS_PER_HOST" envDefault:"16"`
+	IdleConnTimeout     time.Duration `env:"IDLE_CONN_TIMEOUT" envDefault:"90s"`
 }
 
 // OllamaConfig represents the configuration for local AI services
@@ -34,19 +363,240 @@ type OllamaConfig struct {
 	Model string `env:"MODEL" envDefault:"codellama:7b-instruct"`
 }
 
+// BedrockConfig represents the configuration for AWS Bedrock
+type BedrockConfig struct {
+	Region          string `env:"REGION"` // Overrides the AWS configuration region when set
+	FoundationModel string `env:"FOUNDATION_MODEL" envDefault:"anthropic.claude-3-haiku-20240307-v1:0"`
+
+	// AgentServiceRoleARN is the IAM role assumed for Bedrock Agent calls; the agent is disabled when empty
+	AgentServiceRoleARN string `env:"AGENT_SERVICE_ROLE_ARN"`
+
+	// AllowedRecordTypes may be sent to Bedrock; health and ID records are kept local by default
+	AllowedRecordTypes []string `env:"ALLOWED_RECORD_TYPES" envSeparator:"," envDefault:"receipt,insurance,travel,work_contract,tax,car,home,visa,warranty,other"`
+}
+
+// OpenAIConfig represents the configuration for OpenAI-compatible endpoints
+type OpenAIConfig struct {
+	URL    string `env:"URL" envDefault:"https://api.openai.com/v1"`
+	APIKey string `env:"API_KEY"`
+	Model  string `env:"MODEL" envDefault:"gpt-4o-mini"`
+
+	// AllowedRecordTypes may be sent to the endpoint; health and ID records are kept local by default
+	AllowedRecordTypes []string `env:"ALLOWED_RECORD_TYPES" envSeparator:"," envDefault:"receipt,insurance,travel,work_contract,tax,car,home,visa,warranty,other"`
+}
+
+// AnthropicConfig represents the configuration for the Anthropic Messages API
+type AnthropicConfig struct {
+	URL       string `env:"URL" envDefault:"https://api.anthropic.com/v1"`
+	APIKey    string `env:"API_KEY"`
+	Model     string `env:"MODEL" envDefault:"claude-3-5-haiku-latest"`
+	MaxTokens int    `env:"MAX_TOKENS" envDefault:"4096"`
+
+	// AllowedRecordTypes may be sent to Anthropic; health and ID records are kept local by default
+	AllowedRecordTypes []string `env:"ALLOWED_RECORD_TYPES" envSeparator:"," envDefault:"receipt,insurance,travel,work_contract,tax,car,home,visa,warranty,other"`
+}
+
+// LlamaCppConfig represents the configuration for in-process GGUF models
+type LlamaCppConfig struct {
+	ModelPath          string `env:"MODEL_PATH"`
+	EmbeddingModelPath string `env:"EMBEDDING_MODEL_PATH"` // Defaults to ModelPath when empty
+	ContextSize        int    `env:"CONTEXT_SIZE" envDefault:"4096"`
+	Threads            int    `env:"THREADS" envDefault:"0"` // 0 lets llama.cpp decide
+	GPULayers          int    `env:"GPU_LAYERS" envDefault:"0"`
+	MaxTokens          int    `env:"MAX_TOKENS" envDefault:"512"`
+}
+
 // AIConfig represents the overall AI configuration with provider-specific settings
 type AIConfig struct {
 	// Provider selection (can be overridden per request)
 	DefaultProvider string `env:"DEFAULT_PROVIDER" envDefault:"bedrock"`
 
+	// Providers tried in order when the default provider fails
+	FallbackProviders []string `env:"FALLBACK_PROVIDERS" envSeparator:","`
+
 	// Provider-specific configurations
-	Ollama OllamaConfig `envPrefix:"OLLAMA_"`
+	Ollama    OllamaConfig    `envPrefix:"OLLAMA_"`
+	Bedrock   BedrockConfig   `envPrefix:"BEDROCK_"`
+	OpenAI    OpenAIConfig    `envPrefix:"OPENAI_"`
+	Anthropic AnthropicConfig `envPrefix:"ANTHROPIC_"`
+	LlamaCpp  LlamaCppConfig  `envPrefix:"LLAMACPP_"`
+
+	// Embedding model configuration, kept separate from the generation model
+	Embeddings EmbeddingsConfig `envPrefix:"EMBEDDINGS_"`
+
+	// Retry policy for transient LLM and embedding failures
+	Retry RetryConfig `envPrefix:"RETRY_"`
+
+	// Directory of <name>.tmpl files overriding the built-in prompt templates
+	PromptsDir string `env:"PROMPTS_DIR"`
+
+	// Context window of the default model in tokens; 0 detects it from the model name
+	ContextWindow int `env:"CONTEXT_WINDOW" envDefault:"0"`
+
+	// Tokens kept free for the prompt template and the model's answer
+	PromptReserveTokens int `env:"PROMPT_RESERVE_TOKENS" envDefault:"1024"`
+
+	// Response cache for classification and extraction calls
+	Cache CacheConfig `envPrefix:"CACHE_"`
+
+	// Usage and cost tracking of every LLM and embedding call
+	Usage UsageConfig `envPrefix:"USAGE_"`
+
+	// Transcribe images with the vision-capable default model instead of Tesseract OCR
+	VisionExtraction bool `env:"VISION_EXTRACTION" envDefault:"false"`
+
+	// Index doctors, clinics, vendors, employers and insurers named in ingested records
+	EntityExtraction bool `env:"ENTITY_EXTRACTION" envDefault:"true"`
+
+	// Short summaries of records, stored as their description and indexed with their content
+	Summaries SummariesConfig `envPrefix:"SUMMARIES_"`
+
+	// Answers to questions grounded in the records search finds
+	Answers AnswersConfig `envPrefix:"ANSWERS_"`
+
+	// Logging and recording of prompt and response pairs for diagnosis
+	Trace TraceConfig `envPrefix:"TRACE_"`
+
+	// Redact PII from prompts and embedding input before they are sent to cloud providers
+	RedactPII bool `env:"REDACT_PII" envDefault:"true"`
+
+	// Provider and model per task, e.g. "classification=ollama:qwen2.5:0.5b,answering=bedrock";
+	// tasks without an entry use the default provider chain
+	TaskModels map[string]string `env:"TASK_MODELS" envKeyValSeparator:"="`
+}
+
+// SummariesConfig represents when records are summarized and which are long enough to need it
+type SummariesConfig struct {
+	Mode      string `env:"MODE" envDefault:"off"`       // "off", "ingest" or "lazy" (summarized by the summarize command)
+	MinLength int    `env:"MIN_LENGTH" envDefault:"500"` // Records with less content, in characters, are not summarized
+}
+
+// AnswersConfig represents how questions are answered from the records
+type AnswersConfig struct {
+	TopK         int `env:"TOP_K" envDefault:"5"`          // Number of records an answer is grounded in
+	HistoryTurns int `env:"HISTORY_TURNS" envDefault:"10"` // Number of earlier chat turns kept in the prompt
+}
+
+// CacheConfig represents the configuration of the LLM response cache
+type CacheConfig struct {
+	Enabled    bool          `env:"ENABLED" envDefault:"true"`
+	Path       string        `env:"PATH" envDefault:"./data/llm_cache.db"`
+	TTL        time.Duration `env:"TTL" envDefault:"720h"`
+	MaxEntries int           `env:"MAX_ENTRIES" envDefault:"10000"`
+}
+
+// TraceConfig represents the configuration of LLM interaction tracing
+type TraceConfig struct {
+	Enabled  bool   `env:"ENABLED" envDefault:"false"`
+	MaxChars int    `env:"MAX_CHARS" envDefault:"2000"` // Cap on logged prompts and responses
+	Path     string `env:"PATH"`                        // JSON lines file for replay; not written when empty
+}
+
+// UsageConfig represents the configuration of AI usage tracking
+type UsageConfig struct {
+	Enabled bool   `env:"ENABLED" envDefault:"true"`
+	Path    string `env:"PATH" envDefault:"./data/ai_usage.db"`
+}
+
+// defaultVectorProvider is the vector backend selected when neither
+// VECTOR_PROVIDER nor VECTOR_BACKEND is set
+const defaultVectorProvider = "local"
+
+// VectorProvider returns the selected vector backend: VECTOR_PROVIDER when
+// set, VECTOR_BACKEND otherwise
+func (c Config) VectorProvider() string {
+	switch {
+	case c.Vector.Provider != "":
+		return c.Vector.Provider
+	case c.VectorBackend != "":
+		return c.VectorBackend
+	default:
+		return defaultVectorProvider
+	}
+}
+
+// DefaultModel returns the generation model of the default provider
+func (c AIConfig) DefaultModel() string {
+	switch c.DefaultProvider {
+	case "ollama":
+		return c.Ollama.Model
+	case "bedrock":
+		return c.Bedrock.FoundationModel
+	case "openai":
+		return c.OpenAI.Model
+	case "anthropic":
+		return c.Anthropic.Model
+	case "llamacpp":
+		return strings.TrimSuffix(filepath.Base(c.LlamaCpp.ModelPath), filepath.Ext(c.LlamaCpp.ModelPath))
+	default:
+		return ""
+	}
+}
+
+// RetryConfig represents the retry policy for LLM and embedding calls
+type RetryConfig struct {
+	MaxAttempts int           `env:"MAX_ATTEMPTS" envDefault:"3"`
+	BaseDelay   time.Duration `env:"BASE_DELAY" envDefault:"500ms"`
+	MaxDelay    time.Duration `env:"MAX_DELAY" envDefault:"10s"`
 }
 
-// SourcesConfig represents configuration for data sources
+// EmbeddingsConfig represents the configuration for the embedding model
+type EmbeddingsConfig struct {
+	Provider   string `env:"PROVIDER" envDefault:"local"`
+	Model      string `env:"MODEL"`
+	Dimensions int    `env:"DIMENSIONS" envDefault:"100"`
+	BatchSize  int    `env:"BATCH_SIZE" envDefault:"32"`
+	Endpoint   string `env:"ENDPOINT"`
+}
+
+// PipelineConfig represents the workers of each scrape stage and the results
+// each buffers for the next. Extraction runs OCR, classification and metadata
+// extraction, and downloads the files of the Google Drive and WebDAV sources;
+// ingestion stores, summarizes and indexes records. Scraped records are
+// ingested in batches, each stored and written to the vector store in one write.
+type PipelineConfig struct {
+	ReadWorkers       int           `env:"READ_WORKERS" envDefault:"2"`
+	ReadQueue         int           `env:"READ_QUEUE" envDefault:"16"`
+	ExtractWorkers    int           `env:"EXTRACT_WORKERS" envDefault:"4"`
+	ExtractQueue      int           `env:"EXTRACT_QUEUE" envDefault:"8"`
+	IngestWorkers     int           `env:"INGEST_WORKERS" envDefault:"1"`
+	IngestQueue       int           `env:"INGEST_QUEUE" envDefault:"8"`
+	IndexBatchSize    int           `env:"INDEX_BATCH_SIZE" envDefault:"32"`
+	IndexBatchTimeout time.Duration `env:"INDEX_BATCH_TIMEOUT" envDefault:"2s"`
+}
+
+// OCRConfig represents the pool of Tesseract clients transcribing images.
+// Languages are Tesseract language pack names and must be installed.
+type OCRConfig struct {
+	Clients   int      `env:"CLIENTS" envDefault:"0"` // 0 uses one client per CPU
+	Languages []string `env:"LANGUAGES" envSeparator:"," envDefault:"eng"`
+}
+
+// PDFConfig represents the poppler tools PDFs are read with. PDFs without a
+// text layer, e.g. scans, are rendered page by page and transcribed like images.
+type PDFConfig struct {
+	TextExtractor string `env:"TEXT_EXTRACTOR" envDefault:"pdftotext"` // Empty transcribes every PDF
+	Renderer      string `env:"RENDERER" envDefault:"pdftoppm"`        // Empty skips PDFs without a text layer
+	DPI           int    `env:"DPI" envDefault:"300"`                  // Resolution pages are rendered at for OCR
+	MaxPages      int    `env:"MAX_PAGES" envDefault:"20"`             // 0 transcribes every page
+}
+
+// PluginsConfig represents where source and extractor plugins are installed.
+// A missing directory disables plugins.
+type PluginsConfig struct {
+	Dir string `env:"DIR" envDefault:"./plugins"`
+}
+
+// SourcesConfig represents configuration for data sources. Sources buffer
+// the records and errors they scrape for ingestion and wait once the buffers
+// are full.
 type SourcesConfig struct {
-	StoragePath string            `env:"STORAGE_PATH" envDefault:"./data/records"`
-	Local       LocalSourceConfig `envPrefix:"LOCAL_"`
+	StoragePath  string             `env:"STORAGE_PATH" envDefault:"./data/records"`
+	RecordBuffer int                `env:"RECORD_BUFFER" envDefault:"8"`
+	ErrorBuffer  int                `env:"ERROR_BUFFER" envDefault:"1"`
+	Local        LocalSourceConfig  `envPrefix:"LOCAL_"`
+	GDrive       GDriveSourceConfig `envPrefix:"GDRIVE_"`
+	WebDAV       WebDAVSourceConfig `envPrefix:"WEBDAV_"`
 }
 
 // LocalSourceConfig represents configuration for local file source
@@ -55,7 +605,51 @@ type LocalSourceConfig struct {
 	BasePath string `env:"BASE_PATH" envDefault:"./testdata"`
 }
 
-// setupLogger configures slog with JSON output and the specified log level
+// GDriveSourceConfig represents configuration for the Google Drive source.
+// The OAuth client must be a "Desktop app" client, signed in once with the
+// gdrive-login command from a browser on the same machine. On a headless
+// machine, set LoginPort and forward it, such as with ssh -L.
+type GDriveSourceConfig struct {
+	Enabled      bool   `env:"ENABLED" envDefault:"false"`
+	ClientID     string `env:"CLIENT_ID"`
+	ClientSecret string `env:"CLIENT_SECRET"`
+	Scope        string `env:"SCOPE" envDefault:"https://www.googleapis.com/auth/drive.readonly"`
+	FolderID     string `env:"FOLDER_ID"` // Empty syncs the whole drive
+	TokenPath    string `env:"TOKEN_PATH" envDefault:"./data/gdrive_token.json"`
+	StatePath    string `env:"STATE_PATH" envDefault:"./data/gdrive_state.json"`
+	LoginPort    int    `env:"LOGIN_PORT" envDefault:"0"` // Loopback port the sign-in is redirected to; 0 picks a free one
+}
+
+// WebDAVSourceConfig represents configuration for the WebDAV source, such as
+// a Nextcloud folder. Requests authenticate with the token when set, or else
+// with the username and password.
+type WebDAVSourceConfig struct {
+	Enabled  bool   `env:"ENABLED" envDefault:"false"`
+	URL      string `env:"URL"` // Collection synced with its subcollections
+	Username string `env:"USERNAME"`
+	Password string `env:"PASSWORD"`
+	Token    string `env:"TOKEN"`
+}
+
+// WatchConfig represents configuration for the watch command. Files failing
+// to be extracted are moved to the quarantine directory.
+type WatchConfig struct {
+	Paths         []string      `env:"PATHS" envSeparator:","` // Empty watches SOURCES_LOCAL_BASE_PATH
+	Interval      time.Duration `env:"INTERVAL" envDefault:"2s"`
+	Debounce      time.Duration `env:"DEBOUNCE" envDefault:"2s"`
+	QuarantineDir string        `env:"QUARANTINE_DIR" envDefault:"./data/quarantine"`
+}
+
+// SecurityConfig represents configuration for at-rest encryption key management
+type SecurityConfig struct {
+	KeyringService string `env:"KEYRING_SERVICE" envDefault:"assistant"`
+	KeyringAccount string `env:"KEYRING_ACCOUNT" envDefault:"encryption-key"`
+	Passphrase     string `env:"PASSPHRASE"` // Fallback when the OS keyring is unavailable
+	SaltPath       string `env:"SALT_PATH" envDefault:"./data/keys.salt"`
+}
+
+// setupLogger configures slog with JSON output and the specified log level.
+// Records logged with a context carry its request ID.
 func setupLogger(level string) {
 	var logLevel slog.Level
 
@@ -78,10 +672,12 @@ func setupLogger(level string) {
 	})
 
 	// Set the default logger
-	slog.SetDefault(slog.New(handler))
+	slog.SetDefault(slog.New(requestid.NewLogHandler(handler)))
 }
 
-// LoadConfig loads and validates configuration from environment variables and AWS
+// LoadConfig loads and validates configuration from environment variables.
+// The AWS configuration is loaded separately with LoadAWSConfig, so commands
+// that never call AWS start without it.
 func LoadConfig() (Config, error) {
 	var cfg Config
 
@@ -93,15 +689,95 @@ func LoadConfig() (Config, error) {
 	// Setup structured logging as early as possible
 	setupLogger(cfg.LogLevel)
 
-	ctx, cancel := context.WithTimeout(context.Background(), cfg.Timeout)
-	defer cancel()
+	if err := cfg.Validate(); err != nil {
+		return cfg, err
+	}
+	return cfg, nil
+}
+
+// storageBackends are the record storage backends STORAGE_BACKEND selects
+var storageBackends = []string{"sqlite", "local-json", "remote"}
+
+// vectorBackends are the vector backends VECTOR_PROVIDER and VECTOR_BACKEND select
+var vectorBackends = []string{"local", "sqlite", "chroma", "qdrant"}
 
-	// Load AWS config
+// declinedBackends are the backends this binary does not implement, with the reason
+var declinedBackends = map[string]string{
+	"dynamo":  "records are searched with SQL full-text and tag queries DynamoDB cannot serve",
+	"bedrock": "a Bedrock knowledge base indexes its own data sources, not records sent to it",
+}
+
+// Validate reports settings selecting what this binary cannot run
+func (c Config) Validate() error {
+	if err := validateBackend("storage", c.StorageBackend, storageBackends); err != nil {
+		return err
+	}
+	if c.Vector.Provider != "" && c.VectorBackend != "" && c.Vector.Provider != c.VectorBackend {
+		return fmt.Errorf("VECTOR_PROVIDER %q conflicts with VECTOR_BACKEND %q, set only one", c.Vector.Provider, c.VectorBackend)
+	}
+	return validateBackend("vector", c.VectorProvider(), vectorBackends)
+}
+
+// validateBackend reports a backend that is not one of the supported ones,
+// and why when it is declined
+func validateBackend(kind, backend string, supported []string) error {
+	if slices.Contains(supported, backend) {
+		return nil
+	}
+	expected := strings.Join(supported, ", ")
+	if reason, ok := declinedBackends[backend]; ok {
+		return fmt.Errorf("%s backend %q is not supported: %s; expected one of %s", kind, backend, reason, expected)
+	}
+	return fmt.Errorf("unknown %s backend %q, expected one of %s", kind, backend, expected)
+}
+
+// LoadAWSConfig loads the AWS configuration from the default credential and config sources
+func LoadAWSConfig(ctx context.Context) (aws.Config, error) {
 	awsCfg, err := config.LoadDefaultConfig(ctx)
 	if err != nil {
-		return cfg, fmt.Errorf("failed to load AWS configuration: %w", err)
+		return aws.Config{}, fmt.Errorf("failed to load AWS configuration: %w", err)
+	}
+	return awsCfg, nil
+}
+
+// Setting represents an environment variable read into the configuration
+type Setting struct {
+	Key     string `json:"key"`
+	Value   string `json:"value"`   // Masked for secrets
+	Default bool   `json:"default"` // The variable is unset and Value is its default
+}
+
+// secretSuffixes mark the variables whose values Settings masks
+var secretSuffixes = []string{"PASSWORD", "PASSPHRASE", "TOKEN", "SECRET", "API_KEY", "_USER"}
+
+// Settings returns every environment variable the configuration reads with
+// its effective value, masking secrets
+func Settings() ([]Setting, error) {
+	params, err := env.GetFieldParams(&Config{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read configuration variables: %w", err)
 	}
-	cfg.AWSConfig = awsCfg
 
-	return cfg, nil
+	settings := make([]Setting, 0, len(params))
+	for _, param := range params {
+		value, set := os.LookupEnv(param.Key)
+		if !set {
+			value = param.DefaultValue
+		}
+		if value != "" && isSecret(param.Key) {
+			value = "********"
+		}
+		settings = append(settings, Setting{Key: param.Key, Value: value, Default: !set})
+	}
+	return settings, nil
+}
+
+// isSecret reports whether a variable holds a credential
+func isSecret(key string) bool {
+	for _, suffix := range secretSuffixes {
+		if strings.HasSuffix(key, suffix) {
+			return true
+		}
+	}
+	return false
 }
@@ -1,6 +1,7 @@
 package config
 
 import (
+	"context"
 	"os"
 	"testing"
 	"time"
@@ -13,15 +14,181 @@ import (
 func TestLoadConfig_Success(t *testing.T) {
 	// Setup environment variables
 	envVars := map[string]string{
-		"TIMEOUT":                 "120s",
-		"LOG_LEVEL":               "debug",
-		"SQLITE_PATH":             "/tmp/test.db",
-		"AI_DEFAULT_PROVIDER":     "ollama",
-		"AI_OLLAMA_URL":           "http://localhost:11434",
-		"AI_OLLAMA_MODEL":         "llama2",
-		"SOURCES_STORAGE_PATH":    "/data/test",
-		"SOURCES_LOCAL_ENABLED":   "true",
-		"SOURCES_LOCAL_BASE_PATH": "/tmp/testdata",
+		"TIMEOUT":                            "120s",
+		"LOG_LEVEL":                          "debug",
+		"SQLITE_PATH":                        "/tmp/test.db",
+		"STORAGE_BACKEND":                    "remote",
+		"STORAGE_COMPRESS_CONTENT":           "true",
+		"STORAGE_JSON_PATH":                  "/tmp/records.json",
+		"VECTOR_INDEX_PATH":                  "/tmp/vectors",
+		"VECTOR_BACKEND":                     "chroma",
+		"AI_DEFAULT_PROVIDER":                "ollama",
+		"AI_OLLAMA_URL":                      "http://localhost:11434",
+		"AI_OLLAMA_MODEL":                    "llama2",
+		"AI_FALLBACK_PROVIDERS":              "bedrock,openai",
+		"AI_BEDROCK_REGION":                  "us-west-2",
+		"AI_BEDROCK_FOUNDATION_MODEL":        "anthropic.claude-3-sonnet",
+		"AI_BEDROCK_AGENT_SERVICE_ROLE_ARN":  "arn:aws:iam::123456789012:role/assistant-agent",
+		"AI_OPENAI_URL":                      "http://localhost:8000/v1",
+		"AI_OPENAI_API_KEY":                  "sk-test",
+		"AI_OPENAI_MODEL":                    "local-model",
+		"AI_ANTHROPIC_URL":                   "http://localhost:9000/v1",
+		"AI_ANTHROPIC_API_KEY":               "sk-ant-test",
+		"AI_ANTHROPIC_MODEL":                 "claude-sonnet-4-5",
+		"AI_ANTHROPIC_MAX_TOKENS":            "2048",
+		"AI_VISION_EXTRACTION":               "true",
+		"AI_ENTITY_EXTRACTION":               "false",
+		"AI_SUMMARIES_MODE":                  "lazy",
+		"AI_SUMMARIES_MIN_LENGTH":            "2000",
+		"AI_ANSWERS_TOP_K":                   "8",
+		"AI_ANSWERS_HISTORY_TURNS":           "4",
+		"AI_USAGE_ENABLED":                   "false",
+		"AI_USAGE_PATH":                      "/tmp/usage.db",
+		"AI_TASK_MODELS":                     "classification=ollama:qwen2.5:0.5b,answering=bedrock",
+		"AI_REDACT_PII":                      "false",
+		"AI_TRACE_ENABLED":                   "true",
+		"AI_TRACE_MAX_CHARS":                 "500",
+		"AI_TRACE_PATH":                      "/tmp/trace.jsonl",
+		"AI_BEDROCK_ALLOWED_RECORD_TYPES":    "receipt,travel",
+		"AI_OPENAI_ALLOWED_RECORD_TYPES":     "receipt",
+		"AI_ANTHROPIC_ALLOWED_RECORD_TYPES":  "car,home",
+		"AI_LLAMACPP_MODEL_PATH":             "/models/qwen2.5-1.5b.gguf",
+		"AI_LLAMACPP_EMBEDDING_MODEL_PATH":   "/models/nomic-embed.gguf",
+		"AI_LLAMACPP_CONTEXT_SIZE":           "8192",
+		"AI_LLAMACPP_THREADS":                "4",
+		"AI_LLAMACPP_GPU_LAYERS":             "20",
+		"AI_LLAMACPP_MAX_TOKENS":             "256",
+		"AI_RETRY_MAX_ATTEMPTS":              "5",
+		"AI_RETRY_BASE_DELAY":                "1s",
+		"AI_RETRY_MAX_DELAY":                 "30s",
+		"AI_PROMPTS_DIR":                     "/tmp/prompts",
+		"AI_CONTEXT_WINDOW":                  "8192",
+		"AI_PROMPT_RESERVE_TOKENS":           "512",
+		"AI_CACHE_ENABLED":                   "false",
+		"AI_CACHE_PATH":                      "/tmp/cache.db",
+		"AI_CACHE_TTL":                       "1h",
+		"AI_CACHE_MAX_ENTRIES":               "50",
+		"AI_EMBEDDINGS_PROVIDER":             "ollama",
+		"AI_EMBEDDINGS_MODEL":                "nomic-embed-text",
+		"AI_EMBEDDINGS_DIMENSIONS":           "768",
+		"AI_EMBEDDINGS_BATCH_SIZE":           "16",
+		"AI_EMBEDDINGS_ENDPOINT":             "http://localhost:11434",
+		"SOURCES_STORAGE_PATH":               "/data/test",
+		"SOURCES_RECORD_BUFFER":              "64",
+		"SOURCES_ERROR_BUFFER":               "4",
+		"SOURCES_LOCAL_ENABLED":              "true",
+		"SOURCES_LOCAL_BASE_PATH":            "/tmp/testdata",
+		"SOURCES_GDRIVE_ENABLED":             "true",
+		"SOURCES_GDRIVE_CLIENT_ID":           "client-id",
+		"SOURCES_GDRIVE_CLIENT_SECRET":       "client-secret",
+		"SOURCES_GDRIVE_SCOPE":               "https://www.googleapis.com/auth/drive.file",
+		"SOURCES_GDRIVE_LOGIN_PORT":          "8085",
+		"SOURCES_GDRIVE_FOLDER_ID":           "folder-1",
+		"SOURCES_GDRIVE_TOKEN_PATH":          "/tmp/gdrive_token.json",
+		"SOURCES_GDRIVE_STATE_PATH":          "/tmp/gdrive_state.json",
+		"SOURCES_WEBDAV_ENABLED":             "true",
+		"SOURCES_WEBDAV_URL":                 "https://cloud.example.com/remote.php/dav/files/alice/Scans",
+		"SOURCES_WEBDAV_USERNAME":            "alice",
+		"SOURCES_WEBDAV_PASSWORD":            "app-password",
+		"SOURCES_WEBDAV_TOKEN":               "webdav-token",
+		"WATCH_PATHS":                        "/tmp/inbox,/tmp/scans",
+		"WATCH_INTERVAL":                     "500ms",
+		"WATCH_DEBOUNCE":                     "5s",
+		"WATCH_QUARANTINE_DIR":               "/tmp/quarantine",
+		"PIPELINE_READ_WORKERS":              "3",
+		"PIPELINE_EXTRACT_WORKERS":           "6",
+		"PIPELINE_INGEST_QUEUE":              "32",
+		"PIPELINE_INDEX_BATCH_SIZE":          "64",
+		"PIPELINE_INDEX_BATCH_TIMEOUT":       "5s",
+		"SECURITY_KEYRING_SERVICE":           "test-service",
+		"SECURITY_KEYRING_ACCOUNT":           "test-account",
+		"SECURITY_PASSPHRASE":                "secret",
+		"SECURITY_SALT_PATH":                 "/tmp/keys.salt",
+		"HTTP_TIMEOUT":                       "10s",
+		"HTTP_PROXY_URL":                     "http://proxy:3128",
+		"HTTP_MAX_RETRIES":                   "5",
+		"HTTP_TLS_SKIP_VERIFY":               "true",
+		"HTTP_MAX_IDLE_CONNS":                "50",
+		"HTTP_MAX_IDLE_CONNS_PER_HOST":       "8",
+		"HTTP_IDLE_CONN_TIMEOUT":             "30s",
+		"BREAKER_FAILURE_THRESHOLD":          "3",
+		"BREAKER_OPEN_TIMEOUT":               "1m",
+		"REMINDERS_LEAD_DAYS":                "60,14",
+		"REMINDERS_WEBHOOK_URL":              "http://localhost:9090/hooks/reminders",
+		"REMINDERS_EMAIL_SMTP_HOST":          "smtp.example.com",
+		"REMINDERS_EMAIL_SMTP_PORT":          "2525",
+		"REMINDERS_EMAIL_USERNAME":           "mailer",
+		"REMINDERS_EMAIL_PASSWORD":           "mail-secret",
+		"REMINDERS_EMAIL_FROM":               "assistant@example.com",
+		"REMINDERS_EMAIL_TO":                 "me@example.com",
+		"TAX_REPORT_FISCAL_YEAR_START_MONTH": "7",
+		"TAX_REPORT_OUTPUT_DIR":              "/tmp/reports",
+		"OBSIDIAN_VAULT_DIR":                 "/home/me/vault",
+		"OBSIDIAN_ATTACHMENTS":               "false",
+		"TAX_REPORT_DEDUCTIONS":              "health=medical,office=work_equipment",
+		"CLAIMS_MEDICAL_CATEGORIES":          "health,optician",
+		"CLAIMS_WINDOW_DAYS":                 "30",
+		"DUPLICATES_TEXT_THRESHOLD":          "0.9",
+		"DUPLICATES_EMBEDDING_THRESHOLD":     "0",
+		"THUMBNAILS_ENABLED":                 "false",
+		"THUMBNAILS_DIR":                     "/tmp/thumbnails",
+		"THUMBNAILS_MAX_SIZE":                "128",
+		"THUMBNAILS_PDF_RENDERER":            "",
+		"OCR_CLIENTS":                        "3",
+		"OCR_LANGUAGES":                      "eng,fas",
+		"PDF_TEXT_EXTRACTOR":                 "/usr/local/bin/pdftotext",
+		"PDF_RENDERER":                       "/opt/poppler/pdftoppm",
+		"PDF_DPI":                            "150",
+		"PDF_MAX_PAGES":                      "5",
+		"PLUGINS_DIR":                        "/opt/assistant/plugins",
+		"TRIPS_GAP_DAYS":                     "5",
+		"TRIPS_HOME_COUNTRY":                 "DE",
+		"BUDGETS_THRESHOLDS":                 "50,90",
+		"BUDGETS_WEBHOOK_URL":                "https://hooks.example.com/budgets",
+		"VEHICLES_LEAD_DAYS":                 "14",
+		"VEHICLES_SERVICE_MONTHS":            "oil_change=6",
+		"VEHICLES_SERVICE_DISTANCE":          "oil_change=10000,tires=30000",
+		"DASHBOARD_WINDOW_DAYS":              "14",
+		"DASHBOARD_RECENT_LIMIT":             "5",
+		"CALENDAR_FEED_TOKEN":                "feed-secret",
+		"CALENDAR_REMINDER_DAYS":             "60,14",
+		"NOTIFY_TEMPLATES_DIR":               "/etc/assistant/notifications",
+		"NOTIFY_EMAIL_SMTP_HOST":             "smtp.example.com",
+		"NOTIFY_EMAIL_TO":                    "me@example.com",
+		"NOTIFY_EMAIL_EVENTS":                "reminders,budget_alerts",
+		"NOTIFY_NTFY_TOPIC":                  "assistant-alerts",
+		"NOTIFY_PUSHOVER_TOKEN":              "app-token",
+		"NOTIFY_PUSHOVER_USER":               "user-key",
+		"NOTIFY_WEBHOOK_URL":                 "https://hooks.example.com/notify",
+		"NOTIFY_WEBHOOK_EVENTS":              "scrape_failure",
+		"SLACK_ADDR":                         ":9090",
+		"SLACK_BOT_TOKEN":                    "xoxb-token",
+		"SLACK_SIGNING_SECRET":               "signing-secret",
+		"SLACK_CHANNEL":                      "C123",
+		"SLACK_RECORD_URL":                   "https://vault.example.com/records",
+		"TELEGRAM_BOT_TOKEN":                 "123:abc",
+		"TELEGRAM_ALLOWED_CHATS":             "42,-100",
+		"TELEGRAM_POLL_TIMEOUT":              "10s",
+		"SYNC_TOKEN":                         "sync-secret",
+		"SYNC_NODE":                          "laptop",
+		"SYNC_PEERS":                         "https://home.example.com,http://nas:8090",
+		"SYNC_ADDR":                          ":9091",
+		"SYNC_BATCH_SIZE":                    "50",
+		"REMOTE_URL":                         "https://vault.example.com",
+		"REMOTE_TOKEN":                       "remote-secret",
+		"REMOTE_ADDR":                        ":9070",
+		"API_ADDR":                           ":9000",
+		"API_TOKEN":                          "api-secret",
+		"API_DEBUG":                          "true",
+		"VECTOR_PROVIDER":                    "chroma",
+		"VECTOR_CHROMA_URL":                  "http://chroma:8000",
+		"VECTOR_CHROMA_TOKEN":                "chroma-secret",
+		"VECTOR_CHROMA_TENANT":               "home",
+		"VECTOR_CHROMA_DATABASE":             "vault",
+		"VECTOR_CHROMA_COLLECTION":           "documents",
+		"VECTOR_QDRANT_URL":                  "http://qdrant:6333",
+		"VECTOR_QDRANT_API_KEY":              "qdrant-secret",
+		"VECTOR_QDRANT_COLLECTION":           "documents",
 	}
 
 	// Set environment variables
@@ -45,23 +212,290 @@ func TestLoadConfig_Success(t *testing.T) {
 	assert.Equal(t, 120*time.Second, cfg.Timeout, "Timeout should be 120s")
 	assert.Equal(t, "debug", cfg.LogLevel, "LogLevel should be 'debug'")
 	assert.Equal(t, "/tmp/test.db", cfg.SQLitePath, "SQLitePath should be '/tmp/test.db'")
+	assert.Equal(t, "remote", cfg.StorageBackend, "StorageBackend should be 'remote'")
+	assert.True(t, cfg.CompressContent, "CompressContent should be true")
+	assert.Equal(t, "/tmp/records.json", cfg.JSONPath, "JSONPath should be '/tmp/records.json'")
+	assert.Equal(t, "/tmp/vectors", cfg.VectorIndexPath, "VectorIndexPath should be '/tmp/vectors'")
+	assert.Equal(t, "chroma", cfg.VectorBackend, "VectorBackend should be 'chroma'")
 
 	// AI configuration
 	assert.Equal(t, "ollama", cfg.AI.DefaultProvider, "AI.DefaultProvider should be 'ollama'")
 	assert.Equal(t, "http://localhost:11434", cfg.AI.Ollama.URL, "AI.Ollama.URL should be 'http://localhost:11434'")
 	assert.Equal(t, "llama2", cfg.AI.Ollama.Model, "AI.Ollama.Model should be 'llama2'")
+	assert.Equal(t, []string{"bedrock", "openai"}, cfg.AI.FallbackProviders, "AI.FallbackProviders should be [bedrock openai]")
+	assert.Equal(t, "us-west-2", cfg.AI.Bedrock.Region, "AI.Bedrock.Region should be 'us-west-2'")
+	assert.Equal(t, "anthropic.claude-3-sonnet", cfg.AI.Bedrock.FoundationModel, "AI.Bedrock.FoundationModel should be 'anthropic.claude-3-sonnet'")
+	assert.Equal(t, "arn:aws:iam::123456789012:role/assistant-agent", cfg.AI.Bedrock.AgentServiceRoleARN, "AI.Bedrock.AgentServiceRoleARN should be set")
+	assert.Equal(t, "http://localhost:8000/v1", cfg.AI.OpenAI.URL, "AI.OpenAI.URL should be 'http://localhost:8000/v1'")
+	assert.Equal(t, "sk-test", cfg.AI.OpenAI.APIKey, "AI.OpenAI.APIKey should be 'sk-test'")
+	assert.Equal(t, "local-model", cfg.AI.OpenAI.Model, "AI.OpenAI.Model should be 'local-model'")
+	assert.Equal(t, "http://localhost:9000/v1", cfg.AI.Anthropic.URL, "AI.Anthropic.URL should be 'http://localhost:9000/v1'")
+	assert.Equal(t, "sk-ant-test", cfg.AI.Anthropic.APIKey, "AI.Anthropic.APIKey should be 'sk-ant-test'")
+	assert.Equal(t, "claude-sonnet-4-5", cfg.AI.Anthropic.Model, "AI.Anthropic.Model should be 'claude-sonnet-4-5'")
+	assert.Equal(t, 2048, cfg.AI.Anthropic.MaxTokens, "AI.Anthropic.MaxTokens should be 2048")
+	assert.True(t, cfg.AI.VisionExtraction, "AI.VisionExtraction should be true")
+	assert.False(t, cfg.AI.EntityExtraction, "AI.EntityExtraction should be false")
+	assert.Equal(t, "lazy", cfg.AI.Summaries.Mode, "AI.Summaries.Mode should be 'lazy'")
+	assert.Equal(t, 2000, cfg.AI.Summaries.MinLength, "AI.Summaries.MinLength should be 2000")
+	assert.Equal(t, 8, cfg.AI.Answers.TopK, "AI.Answers.TopK should be 8")
+	assert.Equal(t, 4, cfg.AI.Answers.HistoryTurns, "AI.Answers.HistoryTurns should be 4")
+	assert.False(t, cfg.AI.Usage.Enabled, "AI.Usage.Enabled should be false")
+	assert.Equal(t, "/tmp/usage.db", cfg.AI.Usage.Path, "AI.Usage.Path should be '/tmp/usage.db'")
+	assert.Equal(t, map[string]string{"classification": "ollama:qwen2.5:0.5b", "answering": "bedrock"}, cfg.AI.TaskModels, "AI.TaskModels should map tasks to provider and model")
+	assert.False(t, cfg.AI.RedactPII, "AI.RedactPII should be false")
+	assert.True(t, cfg.AI.Trace.Enabled, "AI.Trace.Enabled should be true")
+	assert.Equal(t, 500, cfg.AI.Trace.MaxChars, "AI.Trace.MaxChars should be 500")
+	assert.Equal(t, "/tmp/trace.jsonl", cfg.AI.Trace.Path, "AI.Trace.Path should be '/tmp/trace.jsonl'")
+	assert.Equal(t, []string{"receipt", "travel"}, cfg.AI.Bedrock.AllowedRecordTypes, "AI.Bedrock.AllowedRecordTypes should be 'receipt,travel'")
+	assert.Equal(t, []string{"receipt"}, cfg.AI.OpenAI.AllowedRecordTypes, "AI.OpenAI.AllowedRecordTypes should be 'receipt'")
+	assert.Equal(t, []string{"car", "home"}, cfg.AI.Anthropic.AllowedRecordTypes, "AI.Anthropic.AllowedRecordTypes should be 'car,home'")
+	assert.Equal(t, "/models/qwen2.5-1.5b.gguf", cfg.AI.LlamaCpp.ModelPath, "AI.LlamaCpp.ModelPath should be '/models/qwen2.5-1.5b.gguf'")
+	assert.Equal(t, "/models/nomic-embed.gguf", cfg.AI.LlamaCpp.EmbeddingModelPath, "AI.LlamaCpp.EmbeddingModelPath should be '/models/nomic-embed.gguf'")
+	assert.Equal(t, 8192, cfg.AI.LlamaCpp.ContextSize, "AI.LlamaCpp.ContextSize should be 8192")
+	assert.Equal(t, 4, cfg.AI.LlamaCpp.Threads, "AI.LlamaCpp.Threads should be 4")
+	assert.Equal(t, 20, cfg.AI.LlamaCpp.GPULayers, "AI.LlamaCpp.GPULayers should be 20")
+	assert.Equal(t, 256, cfg.AI.LlamaCpp.MaxTokens, "AI.LlamaCpp.MaxTokens should be 256")
+	assert.Equal(t, 5, cfg.AI.Retry.MaxAttempts, "AI.Retry.MaxAttempts should be 5")
+	assert.Equal(t, time.Second, cfg.AI.Retry.BaseDelay, "AI.Retry.BaseDelay should be 1s")
+	assert.Equal(t, 30*time.Second, cfg.AI.Retry.MaxDelay, "AI.Retry.MaxDelay should be 30s")
+	assert.Equal(t, "/tmp/prompts", cfg.AI.PromptsDir, "AI.PromptsDir should be '/tmp/prompts'")
+	assert.Equal(t, 8192, cfg.AI.ContextWindow, "AI.ContextWindow should be 8192")
+	assert.Equal(t, 512, cfg.AI.PromptReserveTokens, "AI.PromptReserveTokens should be 512")
+	assert.False(t, cfg.AI.Cache.Enabled, "AI.Cache.Enabled should be false")
+	assert.Equal(t, "/tmp/cache.db", cfg.AI.Cache.Path, "AI.Cache.Path should be '/tmp/cache.db'")
+	assert.Equal(t, time.Hour, cfg.AI.Cache.TTL, "AI.Cache.TTL should be 1h")
+	assert.Equal(t, 50, cfg.AI.Cache.MaxEntries, "AI.Cache.MaxEntries should be 50")
+	assert.Equal(t, "llama2", cfg.AI.DefaultModel(), "AI.DefaultModel() should be the Ollama model")
+	assert.Equal(t, "ollama", cfg.AI.Embeddings.Provider, "AI.Embeddings.Provider should be 'ollama'")
+	assert.Equal(t, "nomic-embed-text", cfg.AI.Embeddings.Model, "AI.Embeddings.Model should be 'nomic-embed-text'")
+	assert.Equal(t, 768, cfg.AI.Embeddings.Dimensions, "AI.Embeddings.Dimensions should be 768")
+	assert.Equal(t, 16, cfg.AI.Embeddings.BatchSize, "AI.Embeddings.BatchSize should be 16")
+	assert.Equal(t, "http://localhost:11434", cfg.AI.Embeddings.Endpoint, "AI.Embeddings.Endpoint should be 'http://localhost:11434'")
 
 	// Sources configuration
 	assert.Equal(t, "/data/test", cfg.Sources.StoragePath, "Sources.StoragePath should be '/data/test'")
+	assert.Equal(t, 64, cfg.Sources.RecordBuffer, "Sources.RecordBuffer should be 64")
+	assert.Equal(t, 4, cfg.Sources.ErrorBuffer, "Sources.ErrorBuffer should be 4")
 	assert.True(t, cfg.Sources.Local.Enabled, "Sources.Local.Enabled should be true")
 	assert.Equal(t, "/tmp/testdata", cfg.Sources.Local.BasePath, "Sources.Local.BasePath should be '/tmp/testdata'")
+	assert.True(t, cfg.Sources.GDrive.Enabled, "Sources.GDrive.Enabled should be true")
+	assert.Equal(t, "client-id", cfg.Sources.GDrive.ClientID, "Sources.GDrive.ClientID should be 'client-id'")
+	assert.Equal(t, "client-secret", cfg.Sources.GDrive.ClientSecret, "Sources.GDrive.ClientSecret should be 'client-secret'")
+	assert.Equal(t, "https://www.googleapis.com/auth/drive.file", cfg.Sources.GDrive.Scope, "Sources.GDrive.Scope should be the drive.file scope")
+	assert.Equal(t, 8085, cfg.Sources.GDrive.LoginPort, "Sources.GDrive.LoginPort should be 8085")
+	assert.Equal(t, "folder-1", cfg.Sources.GDrive.FolderID, "Sources.GDrive.FolderID should be 'folder-1'")
+	assert.Equal(t, "/tmp/gdrive_token.json", cfg.Sources.GDrive.TokenPath, "Sources.GDrive.TokenPath should be '/tmp/gdrive_token.json'")
+	assert.Equal(t, "/tmp/gdrive_state.json", cfg.Sources.GDrive.StatePath, "Sources.GDrive.StatePath should be '/tmp/gdrive_state.json'")
+	assert.True(t, cfg.Sources.WebDAV.Enabled, "Sources.WebDAV.Enabled should be true")
+	assert.Equal(t, []string{"/tmp/inbox", "/tmp/scans"}, cfg.Watch.Paths, "Watch.Paths should be split on commas")
+	assert.Equal(t, 500*time.Millisecond, cfg.Watch.Interval, "Watch.Interval should be 500ms")
+	assert.Equal(t, 5*time.Second, cfg.Watch.Debounce, "Watch.Debounce should be 5s")
+	assert.Equal(t, "/tmp/quarantine", cfg.Watch.QuarantineDir, "Watch.QuarantineDir should be '/tmp/quarantine'")
+	assert.Equal(t, "https://cloud.example.com/remote.php/dav/files/alice/Scans", cfg.Sources.WebDAV.URL, "Sources.WebDAV.URL should be the Nextcloud folder")
+	assert.Equal(t, "alice", cfg.Sources.WebDAV.Username, "Sources.WebDAV.Username should be 'alice'")
+	assert.Equal(t, "app-password", cfg.Sources.WebDAV.Password, "Sources.WebDAV.Password should be 'app-password'")
+	assert.Equal(t, "webdav-token", cfg.Sources.WebDAV.Token, "Sources.WebDAV.Token should be 'webdav-token'")
+	assert.Equal(t, 3, cfg.Pipeline.ReadWorkers, "Pipeline.ReadWorkers should be 3")
+	assert.Equal(t, 6, cfg.Pipeline.ExtractWorkers, "Pipeline.ExtractWorkers should be 6")
+	assert.Equal(t, 32, cfg.Pipeline.IngestQueue, "Pipeline.IngestQueue should be 32")
+	assert.Equal(t, 64, cfg.Pipeline.IndexBatchSize, "Pipeline.IndexBatchSize should be 64")
+	assert.Equal(t, 5*time.Second, cfg.Pipeline.IndexBatchTimeout, "Pipeline.IndexBatchTimeout should be 5s")
 
-	// Verify AWS config was loaded (should not be nil/zero value)
-	if cfg.AWSConfig.Region == "" {
+	// Security configuration
+	assert.Equal(t, "test-service", cfg.Security.KeyringService, "Security.KeyringService should be 'test-service'")
+	assert.Equal(t, "test-account", cfg.Security.KeyringAccount, "Security.KeyringAccount should be 'test-account'")
+	assert.Equal(t, "secret", cfg.Security.Passphrase, "Security.Passphrase should be 'secret'")
+	assert.Equal(t, "/tmp/keys.salt", cfg.Security.SaltPath, "Security.SaltPath should be '/tmp/keys.salt'")
+
+	// HTTP configuration
+	assert.Equal(t, 10*time.Second, cfg.HTTP.Timeout, "HTTP.Timeout should be 10s")
+	assert.Equal(t, "http://proxy:3128", cfg.HTTP.ProxyURL, "HTTP.ProxyURL should be 'http://proxy:3128'")
+	assert.Equal(t, 5, cfg.HTTP.MaxRetries, "HTTP.MaxRetries should be 5")
+	assert.True(t, cfg.HTTP.InsecureSkipVerify, "HTTP.InsecureSkipVerify should be true")
+	assert.Equal(t, 50, cfg.HTTP.MaxIdleConns, "HTTP.MaxIdleConns should be 50")
+	assert.Equal(t, 8, cfg.HTTP.MaxIdleConnsPerHost, "HTTP.MaxIdleConnsPerHost should be 8")
+	assert.Equal(t, 30*time.Second, cfg.HTTP.IdleConnTimeout, "HTTP.IdleConnTimeout should be 30s")
+
+	// Circuit breaker configuration
+	assert.Equal(t, 3, cfg.Breaker.FailureThreshold, "Breaker.FailureThreshold should be 3")
+	assert.Equal(t, time.Minute, cfg.Breaker.OpenTimeout, "Breaker.OpenTimeout should be 1m")
+
+	// Reminders configuration
+	assert.Equal(t, []int{60, 14}, cfg.Reminders.LeadDays, "Reminders.LeadDays should be [60 14]")
+	assert.Equal(t, "http://localhost:9090/hooks/reminders", cfg.Reminders.WebhookURL, "Reminders.WebhookURL should be set")
+	assert.Equal(t, "smtp.example.com", cfg.Reminders.Email.SMTPHost, "Reminders.Email.SMTPHost should be 'smtp.example.com'")
+	assert.Equal(t, 2525, cfg.Reminders.Email.SMTPPort, "Reminders.Email.SMTPPort should be 2525")
+	assert.Equal(t, "mailer", cfg.Reminders.Email.Username, "Reminders.Email.Username should be 'mailer'")
+	assert.Equal(t, "mail-secret", cfg.Reminders.Email.Password, "Reminders.Email.Password should be 'mail-secret'")
+	assert.Equal(t, "assistant@example.com", cfg.Reminders.Email.From, "Reminders.Email.From should be 'assistant@example.com'")
+	assert.Equal(t, "me@example.com", cfg.Reminders.Email.To, "Reminders.Email.To should be 'me@example.com'")
+
+	// Tax report configuration
+	assert.Equal(t, 7, cfg.TaxReport.FiscalYearStartMonth, "TaxReport.FiscalYearStartMonth should be 7")
+	assert.Equal(t, "/tmp/reports", cfg.TaxReport.OutputDir, "TaxReport.OutputDir should be '/tmp/reports'")
+	assert.Equal(t, "/home/me/vault", cfg.Obsidian.VaultDir, "Obsidian.VaultDir should be '/home/me/vault'")
+	assert.False(t, cfg.Obsidian.Attachments, "Obsidian.Attachments should be false")
+	assert.Equal(t, map[string]string{"health": "medical", "office": "work_equipment"}, cfg.TaxReport.Deductions, "TaxReport.Deductions should map categories to deductions")
+	assert.Equal(t, []string{"health", "optician"}, cfg.Claims.MedicalCategories, "Claims.MedicalCategories should be [health optician]")
+	assert.Equal(t, 30, cfg.Claims.WindowDays, "Claims.WindowDays should be 30")
+	assert.Equal(t, 0.9, cfg.Duplicates.TextThreshold, "Duplicates.TextThreshold should be 0.9")
+	assert.Zero(t, cfg.Duplicates.EmbeddingThreshold, "Duplicates.EmbeddingThreshold should be 0")
+	assert.False(t, cfg.Thumbnails.Enabled, "Thumbnails.Enabled should be false")
+	assert.Equal(t, "/tmp/thumbnails", cfg.Thumbnails.Dir, "Thumbnails.Dir should be '/tmp/thumbnails'")
+	assert.Equal(t, 128, cfg.Thumbnails.MaxSize, "Thumbnails.MaxSize should be 128")
+	assert.Equal(t, 3, cfg.OCR.Clients, "OCR.Clients should be 3")
+	assert.Equal(t, []string{"eng", "fas"}, cfg.OCR.Languages, "OCR.Languages should be eng and fas")
+	assert.Equal(t, "/usr/local/bin/pdftotext", cfg.PDF.TextExtractor, "PDF.TextExtractor should be '/usr/local/bin/pdftotext'")
+	assert.Equal(t, "/opt/poppler/pdftoppm", cfg.PDF.Renderer, "PDF.Renderer should be '/opt/poppler/pdftoppm'")
+	assert.Equal(t, 150, cfg.PDF.DPI, "PDF.DPI should be 150")
+	assert.Equal(t, 5, cfg.PDF.MaxPages, "PDF.MaxPages should be 5")
+	assert.Equal(t, "/opt/assistant/plugins", cfg.Plugins.Dir, "Plugins.Dir should be /opt/assistant/plugins")
+	assert.Equal(t, 5, cfg.Trips.GapDays, "Trips.GapDays should be 5")
+	assert.Equal(t, "DE", cfg.Trips.HomeCountry, "Trips.HomeCountry should be 'DE'")
+	assert.Equal(t, []int{50, 90}, cfg.Budgets.Thresholds, "Budgets.Thresholds should be [50 90]")
+	assert.Equal(t, "https://hooks.example.com/budgets", cfg.Budgets.WebhookURL, "Budgets.WebhookURL should be set")
+	assert.Equal(t, 14, cfg.Vehicles.LeadDays, "Vehicles.LeadDays should be 14")
+	assert.Equal(t, map[string]int{"oil_change": 6}, cfg.Vehicles.ServiceMonths, "Vehicles.ServiceMonths should map service types to months")
+	assert.Equal(t, map[string]int{"oil_change": 10000, "tires": 30000}, cfg.Vehicles.ServiceDistance, "Vehicles.ServiceDistance should map service types to distances")
+	assert.Equal(t, 14, cfg.Dashboard.WindowDays, "Dashboard.WindowDays should be 14")
+	assert.Equal(t, 5, cfg.Dashboard.RecentLimit, "Dashboard.RecentLimit should be 5")
+	assert.Equal(t, "feed-secret", cfg.Calendar.FeedToken, "Calendar.FeedToken should be set")
+	assert.Equal(t, []int{60, 14}, cfg.Calendar.ReminderDays, "Calendar.ReminderDays should be [60 14]")
+	assert.Equal(t, "/etc/assistant/notifications", cfg.Notifications.TemplatesDir, "Notifications.TemplatesDir should be set")
+	assert.Equal(t, "smtp.example.com", cfg.Notifications.Email.SMTPHost, "Notifications.Email.SMTPHost should be set")
+	assert.Equal(t, 587, cfg.Notifications.Email.SMTPPort, "Notifications.Email.SMTPPort should default to 587")
+	assert.Equal(t, "me@example.com", cfg.Notifications.Email.To, "Notifications.Email.To should be set")
+	assert.Equal(t, []string{"reminders", "budget_alerts"}, cfg.Notifications.Email.Events, "Notifications.Email.Events should be set")
+	assert.Equal(t, "assistant-alerts", cfg.Notifications.Ntfy.Topic, "Notifications.Ntfy.Topic should be set")
+	assert.Equal(t, "app-token", cfg.Notifications.Pushover.Token, "Notifications.Pushover.Token should be set")
+	assert.Equal(t, "user-key", cfg.Notifications.Pushover.User, "Notifications.Pushover.User should be set")
+	assert.Equal(t, "https://hooks.example.com/notify", cfg.Notifications.Webhook.URL, "Notifications.Webhook.URL should be set")
+	assert.Equal(t, []string{"scrape_failure"}, cfg.Notifications.Webhook.Events, "Notifications.Webhook.Events should be set")
+	assert.Equal(t, ":9090", cfg.Slack.Addr, "Slack.Addr should be ':9090'")
+	assert.Equal(t, "xoxb-token", cfg.Slack.BotToken, "Slack.BotToken should be set")
+	assert.Equal(t, "signing-secret", cfg.Slack.SigningSecret, "Slack.SigningSecret should be set")
+	assert.Equal(t, "C123", cfg.Slack.Channel, "Slack.Channel should be set")
+	assert.Equal(t, "https://vault.example.com/records", cfg.Slack.RecordURL, "Slack.RecordURL should be set")
+	assert.Equal(t, "123:abc", cfg.Telegram.BotToken, "Telegram.BotToken should be set")
+	assert.Equal(t, []int64{42, -100}, cfg.Telegram.AllowedChats, "Telegram.AllowedChats should be set")
+	assert.Equal(t, 10*time.Second, cfg.Telegram.PollTimeout, "Telegram.PollTimeout should be 10s")
+	assert.Equal(t, "sync-secret", cfg.Sync.Token, "Sync.Token should be set")
+	assert.Equal(t, "laptop", cfg.Sync.Node, "Sync.Node should be set")
+	assert.Equal(t, []string{"https://home.example.com", "http://nas:8090"}, cfg.Sync.Peers, "Sync.Peers should be set")
+	assert.Equal(t, ":9091", cfg.Sync.Addr, "Sync.Addr should be ':9091'")
+	assert.Equal(t, 50, cfg.Sync.BatchSize, "Sync.BatchSize should be 50")
+	assert.Equal(t, "https://vault.example.com", cfg.Remote.URL, "Remote.URL should be set")
+	assert.Equal(t, "remote-secret", cfg.Remote.Token, "Remote.Token should be set")
+	assert.Equal(t, ":9070", cfg.Remote.Addr, "Remote.Addr should be ':9070'")
+	assert.Equal(t, ":9000", cfg.API.Addr, "API.Addr should be ':9000'")
+	assert.Equal(t, "api-secret", cfg.API.Token, "API.Token should be set")
+	assert.True(t, cfg.API.Debug, "API.Debug should be true")
+	assert.Equal(t, "chroma", cfg.Vector.Provider, "Vector.Provider should be 'chroma'")
+	assert.Equal(t, "chroma", cfg.VectorProvider(), "VectorProvider() should be 'chroma'")
+	assert.Equal(t, "http://chroma:8000", cfg.Vector.Chroma.URL, "Vector.Chroma.URL should be set")
+	assert.Equal(t, "chroma-secret", cfg.Vector.Chroma.Token, "Vector.Chroma.Token should be set")
+	assert.Equal(t, "home", cfg.Vector.Chroma.Tenant, "Vector.Chroma.Tenant should be 'home'")
+	assert.Equal(t, "vault", cfg.Vector.Chroma.Database, "Vector.Chroma.Database should be 'vault'")
+	assert.Equal(t, "documents", cfg.Vector.Chroma.Collection, "Vector.Chroma.Collection should be 'documents'")
+	assert.Equal(t, "http://qdrant:6333", cfg.Vector.Qdrant.URL, "Vector.Qdrant.URL should be set")
+	assert.Equal(t, "qdrant-secret", cfg.Vector.Qdrant.APIKey, "Vector.Qdrant.APIKey should be set")
+	assert.Equal(t, "documents", cfg.Vector.Qdrant.Collection, "Vector.Qdrant.Collection should be 'documents'")
+	assert.Empty(t, cfg.AWSConfig.Region, "AWS config should not be loaded with the environment")
+}
+
+// TestLoadAWSConfig tests loading the AWS configuration
+func TestLoadConfig_UnknownStorageBackend(t *testing.T) {
+	// Arrange
+	t.Setenv("STORAGE_BACKEND", "dynamo")
+
+	// Act
+	_, err := LoadConfig()
+
+	// Assert
+	assert.ErrorContains(t, err, `storage backend "dynamo" is not supported`, "LoadConfig should decline a storage backend that is not implemented")
+	assert.ErrorContains(t, err, "expected one of sqlite, local-json, remote", "LoadConfig should name the supported storage backends")
+}
+
+func TestLoadConfig_DeclinedVectorBackend(t *testing.T) {
+	// Arrange
+	t.Setenv("VECTOR_PROVIDER", "bedrock")
+
+	// Act
+	_, err := LoadConfig()
+
+	// Assert
+	assert.ErrorContains(t, err, `vector backend "bedrock" is not supported: a Bedrock knowledge base indexes its own data sources`, "LoadConfig should decline the bedrock backend with the reason")
+}
+
+func TestLoadConfig_UnknownVectorBackend(t *testing.T) {
+	// Arrange
+	t.Setenv("VECTOR_BACKEND", "milvus")
+
+	// Act
+	_, err := LoadConfig()
+
+	// Assert
+	assert.ErrorContains(t, err, `unknown vector backend "milvus", expected one of local, sqlite, chroma, qdrant`, "LoadConfig should reject a vector backend that does not exist")
+}
+
+func TestLoadConfig_ConflictingVectorBackends(t *testing.T) {
+	// Arrange
+	t.Setenv("VECTOR_PROVIDER", "chroma")
+	t.Setenv("VECTOR_BACKEND", "qdrant")
+
+	// Act
+	_, err := LoadConfig()
+
+	// Assert
+	assert.ErrorContains(t, err, "conflicts with VECTOR_BACKEND", "LoadConfig should reject vector backends that disagree")
+}
+
+func TestVectorProvider(t *testing.T) {
+	// Arrange
+	cfg := Config{VectorBackend: "qdrant"}
+
+	// Act
+	provider := cfg.VectorProvider()
+
+	// Assert
+	assert.Equal(t, "qdrant", provider, "VectorProvider() should fall back to VECTOR_BACKEND")
+}
+
+func TestLoadAWSConfig(t *testing.T) {
+	// Act
+	awsCfg, err := LoadAWSConfig(context.Background())
+
+	// Assert
+	require.NoError(t, err, "LoadAWSConfig should not return an error")
+	if awsCfg.Region == "" {
 		t.Log("Warning: AWS config region is empty (may be expected in test environment)")
 	}
 }
 
+// TestSettings tests that settings report effective values and mask secrets
+func TestSettings(t *testing.T) {
+	// Arrange
+	t.Setenv("LOG_LEVEL", "debug")
+	t.Setenv("AI_OPENAI_API_KEY", "sk-test")
+	t.Setenv("SLACK_SIGNING_SECRET", "signing-secret")
+	require.NoError(t, os.Unsetenv("SQLITE_PATH"))
+
+	// Act
+	settings, err := Settings()
+
+	// Assert
+	require.NoError(t, err, "Settings should not return an error")
+	byKey := map[string]Setting{}
+	for _, setting := range settings {
+		byKey[setting.Key] = setting
+	}
+	assert.Equal(t, Setting{Key: "LOG_LEVEL", Value: "debug"}, byKey["LOG_LEVEL"], "LOG_LEVEL should report the set value")
+	assert.Equal(t, Setting{Key: "SQLITE_PATH", Value: "./data/assistant.db", Default: true}, byKey["SQLITE_PATH"], "SQLITE_PATH should report its default")
+	assert.Equal(t, "********", byKey["AI_OPENAI_API_KEY"].Value, "AI_OPENAI_API_KEY should be masked")
+	assert.Equal(t, "********", byKey["SLACK_SIGNING_SECRET"].Value, "SLACK_SIGNING_SECRET should be masked")
+	assert.Contains(t, byKey, "NOTIFY_EMAIL_PASSWORD", "Settings should include nested variables")
+}
+
 // TestLoadConfig_DefaultValues tests that default values are applied when env vars are not set
 func TestLoadConfig_DefaultValues(t *testing.T) {
 	// Clear all relevant environment variables to ensure defaults are used
@@ -69,11 +503,62 @@ func TestLoadConfig_DefaultValues(t *testing.T) {
 		"TIMEOUT",
 		"LOG_LEVEL",
 		"SQLITE_PATH",
+		"STORAGE_BACKEND",
+		"STORAGE_COMPRESS_CONTENT",
+		"STORAGE_JSON_PATH",
+		"VECTOR_INDEX_PATH",
+		"VECTOR_BACKEND",
 		"AI_DEFAULT_PROVIDER",
 		"AI_OLLAMA_URL",
 		"AI_OLLAMA_MODEL",
+		"AI_EMBEDDINGS_PROVIDER",
+		"AI_EMBEDDINGS_MODEL",
+		"AI_EMBEDDINGS_DIMENSIONS",
+		"AI_EMBEDDINGS_BATCH_SIZE",
+		"AI_EMBEDDINGS_ENDPOINT",
 		"AI_BEDROCK_REGION",
 		"AI_BEDROCK_FOUNDATION_MODEL",
+		"AI_BEDROCK_AGENT_SERVICE_ROLE_ARN",
+		"AI_FALLBACK_PROVIDERS",
+		"AI_OPENAI_URL",
+		"AI_OPENAI_API_KEY",
+		"AI_OPENAI_MODEL",
+		"AI_ANTHROPIC_URL",
+		"AI_ANTHROPIC_API_KEY",
+		"AI_ANTHROPIC_MODEL",
+		"AI_ANTHROPIC_MAX_TOKENS",
+		"AI_VISION_EXTRACTION",
+		"AI_ENTITY_EXTRACTION",
+		"AI_SUMMARIES_MODE",
+		"AI_SUMMARIES_MIN_LENGTH",
+		"AI_ANSWERS_TOP_K",
+		"AI_ANSWERS_HISTORY_TURNS",
+		"AI_USAGE_ENABLED",
+		"AI_USAGE_PATH",
+		"AI_TASK_MODELS",
+		"AI_REDACT_PII",
+		"AI_TRACE_ENABLED",
+		"AI_TRACE_MAX_CHARS",
+		"AI_TRACE_PATH",
+		"AI_BEDROCK_ALLOWED_RECORD_TYPES",
+		"AI_OPENAI_ALLOWED_RECORD_TYPES",
+		"AI_ANTHROPIC_ALLOWED_RECORD_TYPES",
+		"AI_LLAMACPP_MODEL_PATH",
+		"AI_LLAMACPP_EMBEDDING_MODEL_PATH",
+		"AI_LLAMACPP_CONTEXT_SIZE",
+		"AI_LLAMACPP_THREADS",
+		"AI_LLAMACPP_GPU_LAYERS",
+		"AI_LLAMACPP_MAX_TOKENS",
+		"AI_RETRY_MAX_ATTEMPTS",
+		"AI_RETRY_BASE_DELAY",
+		"AI_RETRY_MAX_DELAY",
+		"AI_PROMPTS_DIR",
+		"AI_CONTEXT_WINDOW",
+		"AI_PROMPT_RESERVE_TOKENS",
+		"AI_CACHE_ENABLED",
+		"AI_CACHE_PATH",
+		"AI_CACHE_TTL",
+		"AI_CACHE_MAX_ENTRIES",
 		"POSTGRES_HOST",
 		"POSTGRES_PORT",
 		"POSTGRES_DATABASE",
@@ -81,8 +566,124 @@ func TestLoadConfig_DefaultValues(t *testing.T) {
 		"POSTGRES_PASSWORD",
 		"POSTGRES_SSL_MODE",
 		"SOURCES_STORAGE_PATH",
+		"SOURCES_RECORD_BUFFER",
+		"SOURCES_ERROR_BUFFER",
 		"SOURCES_LOCAL_ENABLED",
 		"SOURCES_LOCAL_BASE_PATH",
+		"SOURCES_GDRIVE_ENABLED",
+		"SOURCES_GDRIVE_CLIENT_ID",
+		"SOURCES_GDRIVE_CLIENT_SECRET",
+		"SOURCES_GDRIVE_SCOPE",
+		"SOURCES_GDRIVE_LOGIN_PORT",
+		"SOURCES_GDRIVE_FOLDER_ID",
+		"SOURCES_GDRIVE_TOKEN_PATH",
+		"SOURCES_GDRIVE_STATE_PATH",
+		"SOURCES_WEBDAV_ENABLED",
+		"SOURCES_WEBDAV_URL",
+		"SOURCES_WEBDAV_USERNAME",
+		"SOURCES_WEBDAV_PASSWORD",
+		"SOURCES_WEBDAV_TOKEN",
+		"WATCH_PATHS",
+		"WATCH_INTERVAL",
+		"WATCH_DEBOUNCE",
+		"WATCH_QUARANTINE_DIR",
+		"PIPELINE_READ_WORKERS",
+		"PIPELINE_EXTRACT_WORKERS",
+		"PIPELINE_INGEST_QUEUE",
+		"PIPELINE_INDEX_BATCH_SIZE",
+		"PIPELINE_INDEX_BATCH_TIMEOUT",
+		"SECURITY_KEYRING_SERVICE",
+		"SECURITY_KEYRING_ACCOUNT",
+		"SECURITY_PASSPHRASE",
+		"SECURITY_SALT_PATH",
+		"HTTP_TIMEOUT",
+		"HTTP_PROXY_URL",
+		"HTTP_MAX_RETRIES",
+		"HTTP_TLS_SKIP_VERIFY",
+		"HTTP_MAX_IDLE_CONNS",
+		"HTTP_MAX_IDLE_CONNS_PER_HOST",
+		"HTTP_IDLE_CONN_TIMEOUT",
+		"BREAKER_FAILURE_THRESHOLD",
+		"BREAKER_OPEN_TIMEOUT",
+		"REMINDERS_LEAD_DAYS",
+		"REMINDERS_WEBHOOK_URL",
+		"REMINDERS_EMAIL_SMTP_HOST",
+		"REMINDERS_EMAIL_SMTP_PORT",
+		"REMINDERS_EMAIL_USERNAME",
+		"REMINDERS_EMAIL_PASSWORD",
+		"REMINDERS_EMAIL_FROM",
+		"REMINDERS_EMAIL_TO",
+		"TAX_REPORT_FISCAL_YEAR_START_MONTH",
+		"TAX_REPORT_OUTPUT_DIR",
+		"OBSIDIAN_VAULT_DIR",
+		"OBSIDIAN_ATTACHMENTS",
+		"TAX_REPORT_DEDUCTIONS",
+		"CLAIMS_MEDICAL_CATEGORIES",
+		"CLAIMS_WINDOW_DAYS",
+		"DUPLICATES_TEXT_THRESHOLD",
+		"DUPLICATES_EMBEDDING_THRESHOLD",
+		"DUPLICATES_ON_INGEST",
+		"THUMBNAILS_ENABLED",
+		"THUMBNAILS_DIR",
+		"THUMBNAILS_MAX_SIZE",
+		"THUMBNAILS_PDF_RENDERER",
+		"OCR_CLIENTS",
+		"OCR_LANGUAGES",
+		"PDF_TEXT_EXTRACTOR",
+		"PDF_RENDERER",
+		"PDF_DPI",
+		"PDF_MAX_PAGES",
+		"PLUGINS_DIR",
+		"TRIPS_GAP_DAYS",
+		"TRIPS_HOME_COUNTRY",
+		"BUDGETS_THRESHOLDS",
+		"BUDGETS_WEBHOOK_URL",
+		"VEHICLES_LEAD_DAYS",
+		"VEHICLES_SERVICE_MONTHS",
+		"VEHICLES_SERVICE_DISTANCE",
+		"DASHBOARD_WINDOW_DAYS",
+		"DASHBOARD_RECENT_LIMIT",
+		"CALENDAR_FEED_TOKEN",
+		"CALENDAR_REMINDER_DAYS",
+		"NOTIFY_TEMPLATES_DIR",
+		"NOTIFY_EMAIL_SMTP_HOST",
+		"NOTIFY_EMAIL_TO",
+		"NOTIFY_EMAIL_EVENTS",
+		"NOTIFY_NTFY_TOPIC",
+		"NOTIFY_PUSHOVER_TOKEN",
+		"NOTIFY_PUSHOVER_USER",
+		"NOTIFY_WEBHOOK_URL",
+		"NOTIFY_WEBHOOK_EVENTS",
+		"SLACK_ADDR",
+		"SLACK_BOT_TOKEN",
+		"SLACK_SIGNING_SECRET",
+		"SLACK_CHANNEL",
+		"SLACK_RECORD_URL",
+		"TELEGRAM_BOT_TOKEN",
+		"TELEGRAM_ALLOWED_CHATS",
+		"TELEGRAM_POLL_TIMEOUT",
+		"SYNC_TOKEN",
+		"SYNC_NODE",
+		"SYNC_PEERS",
+		"SYNC_ADDR",
+		"SYNC_BATCH_SIZE",
+		"REMOTE_URL",
+		"REMOTE_TOKEN",
+		"REMOTE_ADDR",
+		"API_ADDR",
+		"API_TOKEN",
+		"API_DEBUG",
+		"VECTOR_PROVIDER",
+		"VECTOR_CHROMA_URL",
+		"VECTOR_CHROMA_TOKEN",
+		"VECTOR_CHROMA_TENANT",
+		"VECTOR_CHROMA_DATABASE",
+		"VECTOR_CHROMA_COLLECTION",
+		"VECTOR_QDRANT_URL",
+		"VECTOR_QDRANT_API_KEY",
+		"VECTOR_QDRANT_COLLECTION",
+		"VECTOR_CHUNK_SIZE",
+		"VECTOR_CHUNK_OVERLAP",
 	}
 
 	for _, key := range envVarsToClear {
@@ -97,14 +698,180 @@ func TestLoadConfig_DefaultValues(t *testing.T) {
 	assert.Equal(t, 180*time.Second, cfg.Timeout, "Default Timeout should be 180s")
 	assert.Equal(t, "info", cfg.LogLevel, "Default LogLevel should be 'info'")
 	assert.Equal(t, "./data/assistant.db", cfg.SQLitePath, "Default SQLitePath should be './data/assistant.db'")
+	assert.Equal(t, "sqlite", cfg.StorageBackend, "Default StorageBackend should be 'sqlite'")
+	assert.False(t, cfg.CompressContent, "Default CompressContent should be false")
+	assert.Equal(t, "./data/records.json", cfg.JSONPath, "Default JSONPath should be './data/records.json'")
+	assert.Empty(t, cfg.VectorIndexPath, "Default VectorIndexPath should be empty")
+	assert.Empty(t, cfg.VectorBackend, "Default VectorBackend should be empty")
 
 	// AI configuration defaults
 	assert.Equal(t, "bedrock", cfg.AI.DefaultProvider, "Default AI.DefaultProvider should be 'bedrock'")
 	assert.Equal(t, "http://localhost:11434", cfg.AI.Ollama.URL, "Default AI.Ollama.URL should be 'http://localhost:11434'")
 	assert.Equal(t, "codellama:7b-instruct", cfg.AI.Ollama.Model, "Default AI.Ollama.Model should be 'codellama:7b-instruct'")
+	assert.Empty(t, cfg.AI.FallbackProviders, "Default AI.FallbackProviders should be empty")
+	assert.Equal(t, "anthropic.claude-3-haiku-20240307-v1:0", cfg.AI.Bedrock.FoundationModel, "Default AI.Bedrock.FoundationModel should be 'anthropic.claude-3-haiku-20240307-v1:0'")
+	assert.Empty(t, cfg.AI.Bedrock.AgentServiceRoleARN, "Default AI.Bedrock.AgentServiceRoleARN should be empty")
+	assert.Equal(t, "https://api.openai.com/v1", cfg.AI.OpenAI.URL, "Default AI.OpenAI.URL should be 'https://api.openai.com/v1'")
+	assert.Equal(t, "gpt-4o-mini", cfg.AI.OpenAI.Model, "Default AI.OpenAI.Model should be 'gpt-4o-mini'")
+	assert.Equal(t, "https://api.anthropic.com/v1", cfg.AI.Anthropic.URL, "Default AI.Anthropic.URL should be 'https://api.anthropic.com/v1'")
+	assert.Equal(t, "claude-3-5-haiku-latest", cfg.AI.Anthropic.Model, "Default AI.Anthropic.Model should be 'claude-3-5-haiku-latest'")
+	assert.Equal(t, 4096, cfg.AI.Anthropic.MaxTokens, "Default AI.Anthropic.MaxTokens should be 4096")
+	assert.False(t, cfg.AI.VisionExtraction, "Default AI.VisionExtraction should be false")
+	assert.True(t, cfg.AI.EntityExtraction, "Default AI.EntityExtraction should be true")
+	assert.Equal(t, "off", cfg.AI.Summaries.Mode, "Default AI.Summaries.Mode should be 'off'")
+	assert.Equal(t, 500, cfg.AI.Summaries.MinLength, "Default AI.Summaries.MinLength should be 500")
+	assert.Equal(t, 5, cfg.AI.Answers.TopK, "Default AI.Answers.TopK should be 5")
+	assert.Equal(t, 10, cfg.AI.Answers.HistoryTurns, "Default AI.Answers.HistoryTurns should be 10")
+	assert.True(t, cfg.AI.Usage.Enabled, "Default AI.Usage.Enabled should be true")
+	assert.Equal(t, "./data/ai_usage.db", cfg.AI.Usage.Path, "Default AI.Usage.Path should be './data/ai_usage.db'")
+	assert.Empty(t, cfg.AI.TaskModels, "Default AI.TaskModels should be empty")
+	assert.True(t, cfg.AI.RedactPII, "Default AI.RedactPII should be true")
+	assert.False(t, cfg.AI.Trace.Enabled, "Default AI.Trace.Enabled should be false")
+	assert.Equal(t, 2000, cfg.AI.Trace.MaxChars, "Default AI.Trace.MaxChars should be 2000")
+	assert.Empty(t, cfg.AI.Trace.Path, "Default AI.Trace.Path should be empty")
+	assert.NotContains(t, cfg.AI.Bedrock.AllowedRecordTypes, "health_visit", "Default AI.Bedrock.AllowedRecordTypes should exclude health records")
+	assert.NotContains(t, cfg.AI.OpenAI.AllowedRecordTypes, "id", "Default AI.OpenAI.AllowedRecordTypes should exclude ID records")
+	assert.Contains(t, cfg.AI.Anthropic.AllowedRecordTypes, "receipt", "Default AI.Anthropic.AllowedRecordTypes should include receipts")
+	assert.Empty(t, cfg.AI.LlamaCpp.ModelPath, "Default AI.LlamaCpp.ModelPath should be empty")
+	assert.Equal(t, 4096, cfg.AI.LlamaCpp.ContextSize, "Default AI.LlamaCpp.ContextSize should be 4096")
+	assert.Equal(t, 0, cfg.AI.LlamaCpp.Threads, "Default AI.LlamaCpp.Threads should be 0")
+	assert.Equal(t, 0, cfg.AI.LlamaCpp.GPULayers, "Default AI.LlamaCpp.GPULayers should be 0")
+	assert.Equal(t, 512, cfg.AI.LlamaCpp.MaxTokens, "Default AI.LlamaCpp.MaxTokens should be 512")
+	assert.Equal(t, 3, cfg.AI.Retry.MaxAttempts, "Default AI.Retry.MaxAttempts should be 3")
+	assert.Equal(t, 500*time.Millisecond, cfg.AI.Retry.BaseDelay, "Default AI.Retry.BaseDelay should be 500ms")
+	assert.Equal(t, 10*time.Second, cfg.AI.Retry.MaxDelay, "Default AI.Retry.MaxDelay should be 10s")
+	assert.Equal(t, 0, cfg.AI.ContextWindow, "Default AI.ContextWindow should be 0")
+	assert.Equal(t, 1024, cfg.AI.PromptReserveTokens, "Default AI.PromptReserveTokens should be 1024")
+	assert.True(t, cfg.AI.Cache.Enabled, "Default AI.Cache.Enabled should be true")
+	assert.Equal(t, "./data/llm_cache.db", cfg.AI.Cache.Path, "Default AI.Cache.Path should be './data/llm_cache.db'")
+	assert.Equal(t, 720*time.Hour, cfg.AI.Cache.TTL, "Default AI.Cache.TTL should be 720h")
+	assert.Equal(t, 10000, cfg.AI.Cache.MaxEntries, "Default AI.Cache.MaxEntries should be 10000")
+	assert.Equal(t, "local", cfg.AI.Embeddings.Provider, "Default AI.Embeddings.Provider should be 'local'")
+	assert.Equal(t, 100, cfg.AI.Embeddings.Dimensions, "Default AI.Embeddings.Dimensions should be 100")
+	assert.Equal(t, 32, cfg.AI.Embeddings.BatchSize, "Default AI.Embeddings.BatchSize should be 32")
 
 	// Sources configuration defaults
 	assert.Equal(t, "./data/records", cfg.Sources.StoragePath, "Default Sources.StoragePath should be './data/records'")
+	assert.Equal(t, 8, cfg.Sources.RecordBuffer, "Default Sources.RecordBuffer should be 8")
+	assert.Equal(t, 1, cfg.Sources.ErrorBuffer, "Default Sources.ErrorBuffer should be 1")
 	assert.True(t, cfg.Sources.Local.Enabled, "Default Sources.Local.Enabled should be true")
 	assert.Equal(t, "./testdata", cfg.Sources.Local.BasePath, "Default Sources.Local.BasePath should be './testdata'")
+	assert.False(t, cfg.Sources.GDrive.Enabled, "Default Sources.GDrive.Enabled should be false")
+	assert.Empty(t, cfg.Sources.GDrive.ClientID, "Default Sources.GDrive.ClientID should be empty")
+	assert.Equal(t, "https://www.googleapis.com/auth/drive.readonly", cfg.Sources.GDrive.Scope, "Default Sources.GDrive.Scope should be the drive.readonly scope")
+	assert.Zero(t, cfg.Sources.GDrive.LoginPort, "Default Sources.GDrive.LoginPort should be 0")
+	assert.Empty(t, cfg.Sources.GDrive.FolderID, "Default Sources.GDrive.FolderID should be empty")
+	assert.Equal(t, "./data/gdrive_token.json", cfg.Sources.GDrive.TokenPath, "Default Sources.GDrive.TokenPath should be './data/gdrive_token.json'")
+	assert.Equal(t, "./data/gdrive_state.json", cfg.Sources.GDrive.StatePath, "Default Sources.GDrive.StatePath should be './data/gdrive_state.json'")
+	assert.False(t, cfg.Sources.WebDAV.Enabled, "Default Sources.WebDAV.Enabled should be false")
+	assert.Empty(t, cfg.Watch.Paths, "Default Watch.Paths should be empty")
+	assert.Equal(t, 2*time.Second, cfg.Watch.Interval, "Default Watch.Interval should be 2s")
+	assert.Equal(t, 2*time.Second, cfg.Watch.Debounce, "Default Watch.Debounce should be 2s")
+	assert.Equal(t, "./data/quarantine", cfg.Watch.QuarantineDir, "Default Watch.QuarantineDir should be './data/quarantine'")
+	assert.Empty(t, cfg.Sources.WebDAV.URL, "Default Sources.WebDAV.URL should be empty")
+	assert.Empty(t, cfg.Sources.WebDAV.Token, "Default Sources.WebDAV.Token should be empty")
+	assert.Equal(t, 2, cfg.Pipeline.ReadWorkers, "Default Pipeline.ReadWorkers should be 2")
+	assert.Equal(t, 16, cfg.Pipeline.ReadQueue, "Default Pipeline.ReadQueue should be 16")
+	assert.Equal(t, 4, cfg.Pipeline.ExtractWorkers, "Default Pipeline.ExtractWorkers should be 4")
+	assert.Equal(t, 8, cfg.Pipeline.ExtractQueue, "Default Pipeline.ExtractQueue should be 8")
+	assert.Equal(t, 1, cfg.Pipeline.IngestWorkers, "Default Pipeline.IngestWorkers should be 1")
+	assert.Equal(t, 8, cfg.Pipeline.IngestQueue, "Default Pipeline.IngestQueue should be 8")
+	assert.Equal(t, 32, cfg.Pipeline.IndexBatchSize, "Default Pipeline.IndexBatchSize should be 32")
+	assert.Equal(t, 2*time.Second, cfg.Pipeline.IndexBatchTimeout, "Default Pipeline.IndexBatchTimeout should be 2s")
+
+	// Security configuration defaults
+	assert.Equal(t, "assistant", cfg.Security.KeyringService, "Default Security.KeyringService should be 'assistant'")
+	assert.Equal(t, "encryption-key", cfg.Security.KeyringAccount, "Default Security.KeyringAccount should be 'encryption-key'")
+	assert.Empty(t, cfg.Security.Passphrase, "Default Security.Passphrase should be empty")
+	assert.Equal(t, "./data/keys.salt", cfg.Security.SaltPath, "Default Security.SaltPath should be './data/keys.salt'")
+
+	// HTTP configuration defaults
+	assert.Zero(t, cfg.HTTP.Timeout, "Default HTTP.Timeout should be 0 so calls are bounded by the command timeout")
+	assert.Empty(t, cfg.HTTP.ProxyURL, "Default HTTP.ProxyURL should be empty")
+	assert.Equal(t, 2, cfg.HTTP.MaxRetries, "Default HTTP.MaxRetries should be 2")
+	assert.False(t, cfg.HTTP.InsecureSkipVerify, "Default HTTP.InsecureSkipVerify should be false")
+	assert.Equal(t, 100, cfg.HTTP.MaxIdleConns, "Default HTTP.MaxIdleConns should be 100")
+	assert.Equal(t, 16, cfg.HTTP.MaxIdleConnsPerHost, "Default HTTP.MaxIdleConnsPerHost should be 16")
+	assert.Equal(t, 90*time.Second, cfg.HTTP.IdleConnTimeout, "Default HTTP.IdleConnTimeout should be 90s")
+
+	// Circuit breaker defaults
+	assert.Equal(t, 5, cfg.Breaker.FailureThreshold, "Default Breaker.FailureThreshold should be 5")
+	assert.Equal(t, 30*time.Second, cfg.Breaker.OpenTimeout, "Default Breaker.OpenTimeout should be 30s")
+
+	// Reminders defaults
+	assert.Equal(t, []int{90, 30, 7, 1}, cfg.Reminders.LeadDays, "Default Reminders.LeadDays should be [90 30 7 1]")
+	assert.Empty(t, cfg.Reminders.WebhookURL, "Default Reminders.WebhookURL should be empty")
+	assert.Equal(t, 587, cfg.Reminders.Email.SMTPPort, "Default Reminders.Email.SMTPPort should be 587")
+	assert.Empty(t, cfg.Reminders.Email.To, "Default Reminders.Email.To should be empty")
+
+	// Tax report defaults
+	assert.Equal(t, 1, cfg.TaxReport.FiscalYearStartMonth, "Default TaxReport.FiscalYearStartMonth should be 1")
+	assert.Equal(t, "./data/reports", cfg.TaxReport.OutputDir, "Default TaxReport.OutputDir should be './data/reports'")
+	assert.Equal(t, "./data/vault", cfg.Obsidian.VaultDir, "Default Obsidian.VaultDir should be './data/vault'")
+	assert.True(t, cfg.Obsidian.Attachments, "Default Obsidian.Attachments should be true")
+	assert.Empty(t, cfg.TaxReport.Deductions, "Default TaxReport.Deductions should be empty")
+	assert.Equal(t, []string{"health", "medical", "pharmacy", "dental"}, cfg.Claims.MedicalCategories, "Default Claims.MedicalCategories should be [health medical pharmacy dental]")
+	assert.Equal(t, 14, cfg.Claims.WindowDays, "Default Claims.WindowDays should be 14")
+	assert.Equal(t, 0.8, cfg.Duplicates.TextThreshold, "Default Duplicates.TextThreshold should be 0.8")
+	assert.Equal(t, 0.97, cfg.Duplicates.EmbeddingThreshold, "Default Duplicates.EmbeddingThreshold should be 0.97")
+	assert.Equal(t, "off", cfg.Duplicates.OnIngest, "Default Duplicates.OnIngest should be 'off'")
+	assert.True(t, cfg.Thumbnails.Enabled, "Default Thumbnails.Enabled should be true")
+	assert.Equal(t, "./data/thumbnails", cfg.Thumbnails.Dir, "Default Thumbnails.Dir should be './data/thumbnails'")
+	assert.Equal(t, 256, cfg.Thumbnails.MaxSize, "Default Thumbnails.MaxSize should be 256")
+	assert.Equal(t, "pdftoppm", cfg.Thumbnails.PDFRenderer, "Default Thumbnails.PDFRenderer should be 'pdftoppm'")
+	assert.Equal(t, 0, cfg.OCR.Clients, "Default OCR.Clients should be 0")
+	assert.Equal(t, []string{"eng"}, cfg.OCR.Languages, "Default OCR.Languages should be eng")
+	assert.Equal(t, "pdftotext", cfg.PDF.TextExtractor, "Default PDF.TextExtractor should be 'pdftotext'")
+	assert.Equal(t, "pdftoppm", cfg.PDF.Renderer, "Default PDF.Renderer should be 'pdftoppm'")
+	assert.Equal(t, 300, cfg.PDF.DPI, "Default PDF.DPI should be 300")
+	assert.Equal(t, 20, cfg.PDF.MaxPages, "Default PDF.MaxPages should be 20")
+	assert.Equal(t, "./plugins", cfg.Plugins.Dir, "Default Plugins.Dir should be ./plugins")
+	assert.Equal(t, 2, cfg.Trips.GapDays, "Default Trips.GapDays should be 2")
+	assert.Empty(t, cfg.Trips.HomeCountry, "Default Trips.HomeCountry should be empty")
+	assert.Equal(t, []int{80, 100}, cfg.Budgets.Thresholds, "Default Budgets.Thresholds should be [80 100]")
+	assert.Empty(t, cfg.Budgets.WebhookURL, "Default Budgets.WebhookURL should be empty")
+	assert.Equal(t, 30, cfg.Vehicles.LeadDays, "Default Vehicles.LeadDays should be 30")
+	assert.Equal(t, map[string]int{"oil_change": 12, "inspection": 24}, cfg.Vehicles.ServiceMonths, "Default Vehicles.ServiceMonths should cover oil changes and inspections")
+	assert.Equal(t, map[string]int{"oil_change": 15000, "tires": 40000, "brakes": 50000}, cfg.Vehicles.ServiceDistance, "Default Vehicles.ServiceDistance should cover oil changes, tires and brakes")
+	assert.Equal(t, 30, cfg.Dashboard.WindowDays, "Default Dashboard.WindowDays should be 30")
+	assert.Equal(t, 10, cfg.Dashboard.RecentLimit, "Default Dashboard.RecentLimit should be 10")
+	assert.Empty(t, cfg.Calendar.FeedToken, "Default Calendar.FeedToken should be empty")
+	assert.Equal(t, []int{30}, cfg.Calendar.ReminderDays, "Default Calendar.ReminderDays should be [30]")
+	assert.Empty(t, cfg.Notifications.TemplatesDir, "Default Notifications.TemplatesDir should be empty")
+	assert.Empty(t, cfg.Notifications.Email.To, "Default Notifications.Email.To should be empty")
+	assert.Empty(t, cfg.Notifications.Email.Events, "Default Notifications.Email.Events should be empty")
+	assert.Equal(t, "https://ntfy.sh", cfg.Notifications.Ntfy.URL, "Default Notifications.Ntfy.URL should be the public server")
+	assert.Empty(t, cfg.Notifications.Ntfy.Topic, "Default Notifications.Ntfy.Topic should be empty")
+	assert.Empty(t, cfg.Notifications.Pushover.User, "Default Notifications.Pushover.User should be empty")
+	assert.Empty(t, cfg.Notifications.Webhook.URL, "Default Notifications.Webhook.URL should be empty")
+	assert.Equal(t, ":8080", cfg.Slack.Addr, "Default Slack.Addr should be ':8080'")
+	assert.Empty(t, cfg.Slack.BotToken, "Default Slack.BotToken should be empty")
+	assert.Empty(t, cfg.Slack.SigningSecret, "Default Slack.SigningSecret should be empty")
+	assert.Empty(t, cfg.Slack.Channel, "Default Slack.Channel should be empty")
+	assert.Empty(t, cfg.Telegram.BotToken, "Default Telegram.BotToken should be empty")
+	assert.Empty(t, cfg.Telegram.AllowedChats, "Default Telegram.AllowedChats should be empty")
+	assert.Equal(t, 30*time.Second, cfg.Telegram.PollTimeout, "Default Telegram.PollTimeout should be 30s")
+	assert.Empty(t, cfg.Sync.Token, "Default Sync.Token should be empty")
+	assert.Empty(t, cfg.Sync.Node, "Default Sync.Node should be empty")
+	assert.Empty(t, cfg.Sync.Peers, "Default Sync.Peers should be empty")
+	assert.Equal(t, ":8090", cfg.Sync.Addr, "Default Sync.Addr should be ':8090'")
+	assert.Equal(t, 100, cfg.Sync.BatchSize, "Default Sync.BatchSize should be 100")
+	assert.Empty(t, cfg.Remote.URL, "Default Remote.URL should be empty")
+	assert.Empty(t, cfg.Remote.Token, "Default Remote.Token should be empty")
+	assert.Equal(t, ":8070", cfg.Remote.Addr, "Default Remote.Addr should be ':8070'")
+	assert.Equal(t, ":8000", cfg.API.Addr, "Default API.Addr should be ':8000'")
+	assert.Empty(t, cfg.API.Token, "Default API.Token should be empty")
+	assert.False(t, cfg.API.Debug, "Default API.Debug should be false")
+	assert.Empty(t, cfg.Vector.Provider, "Default Vector.Provider should be empty")
+	assert.Equal(t, "local", cfg.VectorProvider(), "Default VectorProvider() should be 'local'")
+	assert.Equal(t, "http://localhost:8000", cfg.Vector.Chroma.URL, "Default Vector.Chroma.URL should be 'http://localhost:8000'")
+	assert.Empty(t, cfg.Vector.Chroma.Token, "Default Vector.Chroma.Token should be empty")
+	assert.Equal(t, "default_tenant", cfg.Vector.Chroma.Tenant, "Default Vector.Chroma.Tenant should be 'default_tenant'")
+	assert.Equal(t, "default_database", cfg.Vector.Chroma.Database, "Default Vector.Chroma.Database should be 'default_database'")
+	assert.Equal(t, "records", cfg.Vector.Chroma.Collection, "Default Vector.Chroma.Collection should be 'records'")
+	assert.Equal(t, 300, cfg.Vector.Chunk.Size, "Default Vector.Chunk.Size should be 300")
+	assert.Equal(t, 50, cfg.Vector.Chunk.Overlap, "Default Vector.Chunk.Overlap should be 50")
+	assert.Equal(t, "http://localhost:6333", cfg.Vector.Qdrant.URL, "Default Vector.Qdrant.URL should be 'http://localhost:6333'")
+	assert.Empty(t, cfg.Vector.Qdrant.APIKey, "Default Vector.Qdrant.APIKey should be empty")
+	assert.Equal(t, "records", cfg.Vector.Qdrant.Collection, "Default Vector.Qdrant.Collection should be 'records'")
 }
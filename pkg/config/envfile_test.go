@@ -0,0 +1,62 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadEnvFile(t *testing.T) {
+	// Arrange
+	path := filepath.Join(t.TempDir(), "assistant.env")
+	content := "# Assistant\n\nSQLITE_PATH=/data/records.db\nexport LOG_LEVEL=\"debug\"\nAPI_TOKEN='a=b'\n"
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600), "failed to write config file")
+	t.Setenv("LOG_LEVEL", "warn")
+	for _, key := range []string{"SQLITE_PATH", "API_TOKEN"} {
+		t.Setenv(key, "")
+		require.NoError(t, os.Unsetenv(key), "failed to unset %s", key)
+	}
+
+	// Act
+	err := LoadEnvFile(path)
+
+	// Assert
+	require.NoError(t, err, "LoadEnvFile() error should be nil")
+	assert.Equal(t, "/data/records.db", os.Getenv("SQLITE_PATH"), "LoadEnvFile() should set the variables")
+	assert.Equal(t, "a=b", os.Getenv("API_TOKEN"), "LoadEnvFile() should unquote values")
+	assert.Equal(t, "warn", os.Getenv("LOG_LEVEL"), "LoadEnvFile() should keep variables already set")
+}
+
+func TestLoadEnvFile_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{name: "missing separator", content: "SQLITE_PATH\n"},
+		{name: "missing key", content: "=value\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			path := filepath.Join(t.TempDir(), "assistant.env")
+			require.NoError(t, os.WriteFile(path, []byte(tt.content), 0o600), "failed to write config file")
+
+			// Act
+			err := LoadEnvFile(path)
+
+			// Assert
+			assert.Error(t, err, "LoadEnvFile() should reject malformed lines")
+		})
+	}
+}
+
+func TestLoadEnvFile_Missing(t *testing.T) {
+	// Act
+	err := LoadEnvFile(filepath.Join(t.TempDir(), "missing.env"))
+
+	// Assert
+	assert.Error(t, err, "LoadEnvFile() should fail for missing files")
+}
@@ -0,0 +1,60 @@
+// Package dashboard gathers upcoming obligations and recent activity into a
+// single overview for a home screen.
+package dashboard
+
+import (
+	"context"
+	"time"
+
+	"github.com/kazemisoroush/assistant/pkg/analytics"
+	"github.com/kazemisoroush/assistant/pkg/records"
+	"github.com/kazemisoroush/assistant/pkg/reminders"
+)
+
+// Obligation kinds
+const (
+	// KindService is a vehicle service falling due
+	KindService = "service"
+
+	// KindRenewal is the next charge of a subscription
+	KindRenewal = "renewal"
+)
+
+// Obligation represents something besides a document expiry that falls due soon
+type Obligation struct {
+	Kind     string  `json:"kind"`
+	RecordID string  `json:"record_id"` // The record the obligation follows up on
+	Title    string  `json:"title"`
+	DueDate  string  `json:"due_date"` // YYYY-MM-DD
+	DaysLeft int     `json:"days_left"`
+	Amount   float64 `json:"amount,omitempty"`
+	Currency string  `json:"currency,omitempty"`
+}
+
+// Entry represents a record listed on the dashboard
+type Entry struct {
+	RecordID   string             `json:"record_id"`
+	RecordType records.RecordType `json:"record_type"`
+	CreatedAt  time.Time          `json:"created_at"`
+	Reason     string             `json:"reason,omitempty"` // Why the record needs review
+}
+
+// Dashboard represents the overview of what needs attention
+type Dashboard struct {
+	GeneratedAt time.Time            `json:"generated_at"`
+	Expiring    []reminders.Reminder `json:"expiring"`   // Documents expiring within the window, including expired ones
+	Upcoming    []Obligation         `json:"upcoming"`   // Services and renewals due within the window, soonest first
+	Unreviewed  []Entry              `json:"unreviewed"` // The review queue, newest first
+	Recent      []Entry              `json:"recent"`     // The latest ingested records, newest first
+
+	// Spend totals the receipts of the current month, one bucket per currency
+	Spend []analytics.Bucket `json:"spend"`
+}
+
+// Builder builds the dashboard
+//
+//go:generate mockgen -destination=./mocks/mock_builder.go -mock_names=Builder=MockBuilder -package=mocks . Builder
+type Builder interface {
+	// Build returns the dashboard as of now
+	Build(ctx context.Context, now time.Time) (Dashboard, error)
+}
@@ -0,0 +1,184 @@
+package dashboard
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/kazemisoroush/assistant/pkg/analytics"
+	"github.com/kazemisoroush/assistant/pkg/records"
+	"github.com/kazemisoroush/assistant/pkg/records/storage"
+	"github.com/kazemisoroush/assistant/pkg/reminders"
+	"github.com/kazemisoroush/assistant/pkg/vehicles"
+)
+
+// Config represents how far ahead the dashboard looks and how many recent records it lists
+type Config struct {
+	WindowDays  int // Days ahead expiries, services and renewals are shown
+	RecentLimit int // Recently ingested records listed
+}
+
+// OverviewBuilder builds the dashboard from the reminder engine, the vehicle
+// tracker, the spend analytics and the records kept in storage
+type OverviewBuilder struct {
+	storage  storage.Storage
+	engine   reminders.Engine
+	tracker  vehicles.Tracker
+	analyzer analytics.Analyzer
+	detector analytics.SubscriptionDetector
+	config   Config
+}
+
+// NewOverviewBuilder creates a new dashboard builder
+func NewOverviewBuilder(
+	storage storage.Storage,
+	engine reminders.Engine,
+	tracker vehicles.Tracker,
+	analyzer analytics.Analyzer,
+	detector analytics.SubscriptionDetector,
+	config Config,
+) Builder {
+	return &OverviewBuilder{
+		storage:  storage,
+		engine:   engine,
+		tracker:  tracker,
+		analyzer: analyzer,
+		detector: detector,
+		config:   config,
+	}
+}
+
+// Build implements Builder
+func (b *OverviewBuilder) Build(ctx context.Context, now time.Time) (Dashboard, error) {
+	dashboard := Dashboard{GeneratedAt: now}
+
+	var err error
+	if dashboard.Expiring, err = b.expiring(ctx, now); err != nil {
+		return Dashboard{}, err
+	}
+	if dashboard.Upcoming, err = b.upcoming(ctx, now); err != nil {
+		return Dashboard{}, err
+	}
+	if dashboard.Unreviewed, dashboard.Recent, err = b.entries(ctx); err != nil {
+		return Dashboard{}, err
+	}
+	if dashboard.Spend, err = b.spend(ctx, now); err != nil {
+		return Dashboard{}, err
+	}
+	return dashboard, nil
+}
+
+// expiring returns the documents expiring within the window
+func (b *OverviewBuilder) expiring(ctx context.Context, now time.Time) ([]reminders.Reminder, error) {
+	upcoming, err := b.engine.Upcoming(ctx, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list expiring documents: %w", err)
+	}
+
+	expiring := []reminders.Reminder{}
+	for _, reminder := range upcoming {
+		if reminder.DaysLeft <= b.config.WindowDays {
+			expiring = append(expiring, reminder)
+		}
+	}
+	return expiring, nil
+}
+
+// upcoming returns the vehicle services and subscription renewals due within the window
+func (b *OverviewBuilder) upcoming(ctx context.Context, now time.Time) ([]Obligation, error) {
+	found, err := b.tracker.Vehicles(ctx, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list vehicle services: %w", err)
+	}
+	report, err := b.detector.Subscriptions(ctx, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list subscriptions: %w", err)
+	}
+
+	upcoming := []Obligation{}
+	for _, v := range found {
+		for _, due := range v.Upcoming {
+			if due.DaysLeft <= b.config.WindowDays {
+				upcoming = append(upcoming, service(v, due))
+			}
+		}
+	}
+	upcoming = append(upcoming, b.renewals(report, now)...)
+	sort.SliceStable(upcoming, func(i, j int) bool { return upcoming[i].DaysLeft < upcoming[j].DaysLeft })
+	return upcoming, nil
+}
+
+// renewals returns the next charges of active subscriptions due within the window
+func (b *OverviewBuilder) renewals(report analytics.SubscriptionReport, now time.Time) []Obligation {
+	var renewals []Obligation
+	for _, sub := range report.Subscriptions {
+		daysLeft := reminders.DaysBetween(now, sub.NextCharge)
+		if sub.Active && len(sub.RecordIDs) > 0 && daysLeft >= 0 && daysLeft <= b.config.WindowDays {
+			renewals = append(renewals, Obligation{
+				Kind:     KindRenewal,
+				RecordID: sub.RecordIDs[len(sub.RecordIDs)-1],
+				Title:    sub.Vendor,
+				DueDate:  sub.NextCharge.Format(time.DateOnly),
+				DaysLeft: daysLeft,
+				Amount:   sub.Amount,
+				Currency: sub.Currency,
+			})
+		}
+	}
+	return renewals
+}
+
+// entries returns the records waiting for review and the most recently ingested records, newest first
+func (b *OverviewBuilder) entries(ctx context.Context) ([]Entry, []Entry, error) {
+	recs, err := b.storage.List(ctx, "")
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list records: %w", err)
+	}
+	sort.SliceStable(recs, func(i, j int) bool { return recs[i].CreatedAt.After(recs[j].CreatedAt) })
+
+	unreviewed, recent := []Entry{}, []Entry{}
+	for _, rec := range recs {
+		if rec.NeedsReview() {
+			reason, _ := rec.Metadata[records.MetaReviewReason].(string)
+			unreviewed = append(unreviewed, Entry{RecordID: rec.ID, RecordType: rec.Type, CreatedAt: rec.CreatedAt, Reason: reason})
+		}
+		if len(recent) < b.config.RecentLimit {
+			recent = append(recent, Entry{RecordID: rec.ID, RecordType: rec.Type, CreatedAt: rec.CreatedAt})
+		}
+	}
+	return unreviewed, recent, nil
+}
+
+// spend returns the receipt totals of the current month
+func (b *OverviewBuilder) spend(ctx context.Context, now time.Time) ([]analytics.Bucket, error) {
+	report, err := b.analyzer.Spend(ctx, analytics.SpendFilter{Year: now.Year()})
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate spend: %w", err)
+	}
+
+	month := now.Format("2006-01")
+	spend := []analytics.Bucket{}
+	for _, bucket := range report.Months {
+		if bucket.Key == month && bucket.Count > 0 {
+			spend = append(spend, bucket)
+		}
+	}
+	return spend, nil
+}
+
+// service describes a vehicle service falling due
+func service(v vehicles.Vehicle, due vehicles.Due) Obligation {
+	name := v.Name
+	if name == "" {
+		name = v.ID
+	}
+	return Obligation{
+		Kind:     KindService,
+		RecordID: due.LastRecordID,
+		Title:    fmt.Sprintf("%s of %s", strings.ReplaceAll(due.ServiceType, "_", " "), name),
+		DueDate:  due.DueDate,
+		DaysLeft: due.DaysLeft,
+	}
+}
@@ -0,0 +1,88 @@
+package dashboard_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/kazemisoroush/assistant/pkg/analytics"
+	analyticsmocks "github.com/kazemisoroush/assistant/pkg/analytics/mocks"
+	"github.com/kazemisoroush/assistant/pkg/dashboard"
+	"github.com/kazemisoroush/assistant/pkg/records"
+	storagemocks "github.com/kazemisoroush/assistant/pkg/records/storage/mocks"
+	"github.com/kazemisoroush/assistant/pkg/reminders"
+	remindermocks "github.com/kazemisoroush/assistant/pkg/reminders/mocks"
+	"github.com/kazemisoroush/assistant/pkg/vehicles"
+	vehiclemocks "github.com/kazemisoroush/assistant/pkg/vehicles/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestOverviewBuilder_Build(t *testing.T) {
+	// Arrange
+	now := time.Date(2024, 4, 20, 9, 0, 0, 0, time.UTC)
+	ctrl := gomock.NewController(t)
+
+	engine := remindermocks.NewMockEngine(ctrl)
+	engine.EXPECT().Upcoming(gomock.Any(), now).Return([]reminders.Reminder{
+		{RecordID: "passport", Title: "passport", DaysLeft: 12},
+		{RecordID: "visa", Title: "visa", DaysLeft: 80},
+	}, nil)
+
+	tracker := vehiclemocks.NewMockTracker(ctrl)
+	tracker.EXPECT().Vehicles(gomock.Any(), now).Return([]vehicles.Vehicle{{
+		ID:   "WVW123",
+		Name: "VW Golf",
+		Upcoming: []vehicles.Due{
+			{ServiceType: "oil_change", LastRecordID: "oil", DueDate: "2024-05-10", DaysLeft: 20},
+			{ServiceType: "inspection", LastRecordID: "inspection", DueDate: "2026-01-16", DaysLeft: 636},
+		},
+	}}, nil)
+
+	detector := analyticsmocks.NewMockSubscriptionDetector(ctrl)
+	detector.EXPECT().Subscriptions(gomock.Any(), now).Return(analytics.SubscriptionReport{Subscriptions: []analytics.Subscription{
+		{Vendor: "Streamflix", Amount: 12.99, Currency: "EUR", Active: true, NextCharge: now.AddDate(0, 0, 3), RecordIDs: []string{"sub-1", "sub-2"}},
+		{Vendor: "Gym", Amount: 30, Currency: "EUR", Active: false, NextCharge: now.AddDate(0, 0, 5), RecordIDs: []string{"gym-1"}},
+	}}, nil)
+
+	storage := storagemocks.NewMockStorage(ctrl)
+	storage.EXPECT().List(gomock.Any(), records.RecordType("")).Return([]records.Record{
+		{ID: "old", Type: records.RecordTypeReceipt, CreatedAt: now.AddDate(0, 0, -10)},
+		{ID: "scan", Type: records.RecordTypeOther, CreatedAt: now.AddDate(0, 0, -1), Metadata: map[string]interface{}{
+			records.MetaNeedsReview: true, records.MetaReviewReason: "classification deferred",
+		}},
+		{ID: "newest", Type: records.RecordTypeReceipt, CreatedAt: now},
+	}, nil)
+
+	analyzer := analyticsmocks.NewMockAnalyzer(ctrl)
+	analyzer.EXPECT().Spend(gomock.Any(), analytics.SpendFilter{Year: 2024}).Return(analytics.SpendReport{Months: []analytics.Bucket{
+		{Key: "2024-03", Currency: "EUR", Total: 500, Count: 9},
+		{Key: "2024-04", Currency: "EUR", Total: 120, Count: 3},
+		{Key: "2024-04", Currency: "USD", Total: 0, Count: 0},
+	}}, nil)
+
+	builder := dashboard.NewOverviewBuilder(storage, engine, tracker, analyzer, detector, dashboard.Config{WindowDays: 30, RecentLimit: 2})
+
+	// Act
+	got, err := builder.Build(context.Background(), now)
+
+	// Assert
+	require.NoError(t, err, "Build() error should be nil")
+	require.Len(t, got.Expiring, 1, "Build() should only list documents expiring within the window")
+	assert.Equal(t, "passport", got.Expiring[0].RecordID, "Build() should list the expiring document")
+
+	require.Len(t, got.Upcoming, 2, "Build() should list services and active renewals due within the window")
+	assert.Equal(t, dashboard.Obligation{
+		Kind: dashboard.KindRenewal, RecordID: "sub-2", Title: "Streamflix", DueDate: "2024-04-23", DaysLeft: 3, Amount: 12.99, Currency: "EUR",
+	}, got.Upcoming[0], "Build() should list the soonest obligation first")
+	assert.Equal(t, "oil change of VW Golf", got.Upcoming[1].Title, "Build() should name the service and vehicle")
+
+	require.Len(t, got.Unreviewed, 1, "Build() should list the review queue")
+	assert.Equal(t, "classification deferred", got.Unreviewed[0].Reason, "Build() should explain why a record needs review")
+
+	require.Len(t, got.Recent, 2, "Build() should limit the recent records")
+	assert.Equal(t, "newest", got.Recent[0].RecordID, "Build() should list the newest record first")
+
+	assert.Equal(t, []analytics.Bucket{{Key: "2024-04", Currency: "EUR", Total: 120, Count: 3}}, got.Spend, "Build() should total the current month per currency")
+}
@@ -0,0 +1,66 @@
+package digest
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/kazemisoroush/assistant/pkg/dashboard"
+	"github.com/kazemisoroush/assistant/pkg/records"
+	"github.com/kazemisoroush/assistant/pkg/records/storage"
+)
+
+// DashboardComposer composes digests from the dashboard, which tracks the
+// review queue, expirations and spend, and the records ingested in the period
+type DashboardComposer struct {
+	builder dashboard.Builder
+	storage storage.Storage
+}
+
+// NewDashboardComposer creates a new digest composer
+func NewDashboardComposer(builder dashboard.Builder, storage storage.Storage) Composer {
+	return &DashboardComposer{
+		builder: builder,
+		storage: storage,
+	}
+}
+
+// Compose implements Composer
+func (c *DashboardComposer) Compose(ctx context.Context, period Period, now time.Time) (Digest, error) {
+	overview, err := c.builder.Build(ctx, now)
+	if err != nil {
+		return Digest{}, fmt.Errorf("failed to build dashboard: %w", err)
+	}
+
+	from := period.Start(now)
+	created, err := c.created(ctx, from, now)
+	if err != nil {
+		return Digest{}, err
+	}
+	return Digest{
+		Period:     period,
+		From:       from,
+		To:         now,
+		New:        created,
+		Unreviewed: overview.Unreviewed,
+		Expiring:   overview.Expiring,
+		Spend:      overview.Spend,
+	}, nil
+}
+
+// created returns the records ingested from from until to, newest first
+func (c *DashboardComposer) created(ctx context.Context, from, to time.Time) ([]dashboard.Entry, error) {
+	entries := []dashboard.Entry{}
+	err := c.storage.Each(ctx, "", func(rec records.Record) error {
+		if !rec.CreatedAt.Before(from) && rec.CreatedAt.Before(to) {
+			entries = append(entries, dashboard.Entry{RecordID: rec.ID, RecordType: rec.Type, CreatedAt: rec.CreatedAt})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list records: %w", err)
+	}
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].CreatedAt.After(entries[j].CreatedAt) })
+	return entries, nil
+}
@@ -0,0 +1,103 @@
+package digest_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/kazemisoroush/assistant/pkg/analytics"
+	"github.com/kazemisoroush/assistant/pkg/dashboard"
+	dashboardmocks "github.com/kazemisoroush/assistant/pkg/dashboard/mocks"
+	"github.com/kazemisoroush/assistant/pkg/digest"
+	"github.com/kazemisoroush/assistant/pkg/records"
+	storagemocks "github.com/kazemisoroush/assistant/pkg/records/storage/mocks"
+	"github.com/kazemisoroush/assistant/pkg/reminders"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+// eachOf returns a Storage.Each implementation visiting recs
+func eachOf(recs []records.Record) func(context.Context, records.RecordType, func(records.Record) error) error {
+	return func(_ context.Context, _ records.RecordType, fn func(records.Record) error) error {
+		for _, rec := range recs {
+			if err := fn(rec); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+func TestDashboardComposer_Compose(t *testing.T) {
+	// Arrange
+	now := time.Date(2024, 4, 20, 9, 0, 0, 0, time.UTC)
+	ctrl := gomock.NewController(t)
+	builder := dashboardmocks.NewMockBuilder(ctrl)
+	builder.EXPECT().Build(gomock.Any(), now).Return(dashboard.Dashboard{
+		Unreviewed: []dashboard.Entry{{RecordID: "blurry", Reason: "low confidence"}},
+		Expiring:   []reminders.Reminder{{RecordID: "passport", DaysLeft: 12}},
+		Spend:      []analytics.Bucket{{Key: "total", Currency: "EUR", Total: 120, Count: 3}},
+	}, nil)
+	storage := storagemocks.NewMockStorage(ctrl)
+	storage.EXPECT().Each(gomock.Any(), records.RecordType(""), gomock.Any()).DoAndReturn(eachOf([]records.Record{
+		{ID: "old", Type: records.RecordTypeReceipt, CreatedAt: now.AddDate(0, 0, -8)},
+		{ID: "monday", Type: records.RecordTypeReceipt, CreatedAt: now.AddDate(0, 0, -5)},
+		{ID: "friday", Type: records.RecordTypeTravel, CreatedAt: now.AddDate(0, 0, -1)},
+	}))
+	composer := digest.NewDashboardComposer(builder, storage)
+
+	// Act
+	got, err := composer.Compose(context.Background(), digest.PeriodWeekly, now)
+
+	// Assert
+	require.NoError(t, err, "Compose() error should be nil")
+	assert.Equal(t, now.AddDate(0, 0, -7), got.From, "Compose() should cover the last week")
+	require.Len(t, got.New, 2, "Compose() should list the records ingested in the period")
+	assert.Equal(t, "friday", got.New[0].RecordID, "Compose() should list the newest record first")
+	assert.Equal(t, "monday", got.New[1].RecordID, "Compose() should list older records after")
+	assert.Len(t, got.Unreviewed, 1, "Compose() should include the review queue")
+	assert.Len(t, got.Expiring, 1, "Compose() should include the expiring documents")
+	assert.Len(t, got.Spend, 1, "Compose() should include the month's spend")
+}
+
+func TestDashboardComposer_Compose_DashboardError(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	builder := dashboardmocks.NewMockBuilder(ctrl)
+	builder.EXPECT().Build(gomock.Any(), gomock.Any()).Return(dashboard.Dashboard{}, errors.New("storage unavailable"))
+	composer := digest.NewDashboardComposer(builder, storagemocks.NewMockStorage(ctrl))
+
+	// Act
+	_, err := composer.Compose(context.Background(), digest.PeriodMonthly, time.Now())
+
+	// Assert
+	assert.Error(t, err, "Compose() should fail when the dashboard fails")
+}
+
+func TestParsePeriod(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    digest.Period
+		wantErr bool
+	}{
+		{input: "weekly", want: digest.PeriodWeekly},
+		{input: "monthly", want: digest.PeriodMonthly},
+		{input: "daily", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			// Act
+			got, err := digest.ParsePeriod(tt.input)
+
+			// Assert
+			if tt.wantErr {
+				assert.Error(t, err, "ParsePeriod() should reject unknown periods")
+				return
+			}
+			require.NoError(t, err, "ParsePeriod() error should be nil")
+			assert.Equal(t, tt.want, got, "ParsePeriod() should return the period")
+		})
+	}
+}
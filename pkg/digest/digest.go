@@ -0,0 +1,60 @@
+// Package digest summarizes the activity of the vault over a week or a month:
+// the records ingested, the review queue, upcoming expirations and the spend
+// of the month so far.
+package digest
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/kazemisoroush/assistant/pkg/analytics"
+	"github.com/kazemisoroush/assistant/pkg/dashboard"
+	"github.com/kazemisoroush/assistant/pkg/reminders"
+)
+
+// Period is the stretch of time a digest covers
+type Period string
+
+// Digest periods
+const (
+	PeriodWeekly  Period = "weekly"
+	PeriodMonthly Period = "monthly"
+)
+
+// ParsePeriod returns the period named s
+func ParsePeriod(s string) (Period, error) {
+	switch period := Period(s); period {
+	case PeriodWeekly, PeriodMonthly:
+		return period, nil
+	default:
+		return "", fmt.Errorf("unknown digest period %q, expected %s or %s", s, PeriodWeekly, PeriodMonthly)
+	}
+}
+
+// Start returns when the period ending at end began
+func (p Period) Start(end time.Time) time.Time {
+	if p == PeriodMonthly {
+		return end.AddDate(0, -1, 0)
+	}
+	return end.AddDate(0, 0, -7)
+}
+
+// Digest represents the activity of a period
+type Digest struct {
+	Period     Period               `json:"period"`
+	From       time.Time            `json:"from"`
+	To         time.Time            `json:"to"`
+	New        []dashboard.Entry    `json:"new"`        // Records ingested in the period, newest first
+	Unreviewed []dashboard.Entry    `json:"unreviewed"` // The review queue, newest first
+	Expiring   []reminders.Reminder `json:"expiring"`   // Documents expiring soon, including expired ones
+	Spend      []analytics.Bucket   `json:"spend"`      // Receipts of the current month, one bucket per currency
+}
+
+// Composer composes digests
+//
+//go:generate mockgen -destination=./mocks/mock_composer.go -mock_names=Composer=MockComposer -package=mocks . Composer
+type Composer interface {
+	// Compose returns the digest of the period ending at now
+	Compose(ctx context.Context, period Period, now time.Time) (Digest, error)
+}
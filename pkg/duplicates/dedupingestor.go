@@ -0,0 +1,261 @@
+package duplicates
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kazemisoroush/assistant/pkg/records"
+	"github.com/kazemisoroush/assistant/pkg/records/ingestor"
+	"github.com/kazemisoroush/assistant/pkg/records/knowledgebase"
+	"github.com/kazemisoroush/assistant/pkg/records/storage"
+)
+
+// Action represents what is done with a record ingested as a duplicate
+type Action string
+
+// Duplicate actions
+const (
+	ActionOff   Action = "off"   // Records are ingested without being checked
+	ActionSkip  Action = "skip"  // The duplicate is not ingested
+	ActionMerge Action = "merge" // The duplicate is merged into the record it duplicates
+	ActionFlag  Action = "flag"  // The duplicate is ingested naming the record it duplicates
+)
+
+// MetaDuplicateOf is the metadata key naming the record a flagged duplicate duplicates
+const MetaDuplicateOf = "duplicate_of"
+
+// DedupIngestor checks every record ingested against the records already
+// indexed most similar to it, the records ingested last and the records
+// before it in the same batch, and skips, merges or flags it when one of them
+// has the same text, mostly the same words or, for indexed records, a
+// near-identical embedding. Records of the same file, such as its previous
+// version, and the copies merged into a record are never its duplicates.
+// The records ingested last are checked in memory, as the ingestor may hold
+// them back for its next index write.
+type DedupIngestor struct {
+	ingestor      ingestor.Ingestor
+	storage       storage.Storage
+	vectorStorage knowledgebase.VectorStorage
+	config        Config
+	action        Action
+	window        int
+
+	mu     sync.Mutex
+	recent []records.Record // Up to window records ingested last, which may not be indexed yet
+}
+
+// NewDedupIngestor wraps an ingestor with deduplication, taking the action on
+// records duplicating another by the thresholds of the config. Indexed records
+// are loaded from the storage before a duplicate is merged into them. The
+// last window records ingested are kept until the next Flush; window is the
+// most records the ingestor holds back from the index.
+func NewDedupIngestor(ingestor ingestor.Ingestor, storage storage.Storage, vectorStorage knowledgebase.VectorStorage, config Config, action Action, window int) ingestor.Ingestor {
+	return &DedupIngestor{
+		ingestor:      ingestor,
+		storage:       storage,
+		vectorStorage: vectorStorage,
+		config:        config,
+		action:        action,
+		window:        window,
+	}
+}
+
+// Ingest implements ingestor.Ingestor
+func (d *DedupIngestor) Ingest(ctx context.Context, record records.Record) error {
+	record, ok, err := d.dedup(ctx, record, nil)
+	if err != nil || !ok {
+		return err
+	}
+	if err := d.ingestor.Ingest(ctx, record); err != nil {
+		return err
+	}
+	d.remember(record)
+	return nil
+}
+
+// IngestBatch implements ingestor.Ingestor. A duplicate merged into a record
+// is ingested in the batch in its place, so later duplicates of the same
+// record in the batch merge into it too.
+func (d *DedupIngestor) IngestBatch(ctx context.Context, recs []records.Record) error {
+	batch := make([]records.Record, 0, len(recs))
+	for _, record := range recs {
+		deduped, ok, err := d.dedup(ctx, record, batch)
+		if err != nil {
+			return err
+		}
+		if ok {
+			batch = put(batch, deduped)
+		}
+	}
+	if err := d.ingestor.IngestBatch(ctx, batch); err != nil {
+		return err
+	}
+	d.remember(batch...)
+	return nil
+}
+
+// remember keeps the records ingested last, up to the window
+func (d *DedupIngestor) remember(recs ...records.Record) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for _, record := range recs {
+		d.recent = put(d.recent, record)
+	}
+	if window := max(d.window, 0); len(d.recent) > window {
+		d.recent = slices.Delete(d.recent, 0, len(d.recent)-window)
+	}
+}
+
+// local returns the records of the batch followed by the records ingested
+// last that are not in it
+func (d *DedupIngestor) local(batch []records.Record) []records.Record {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	local := slices.Clone(batch)
+	for _, record := range d.recent {
+		if !slices.ContainsFunc(batch, func(r records.Record) bool { return r.ID == record.ID }) {
+			local = append(local, record)
+		}
+	}
+	return local
+}
+
+// put adds a record to the batch, replacing the one with the same ID
+func put(batch []records.Record, record records.Record) []records.Record {
+	if i := slices.IndexFunc(batch, func(r records.Record) bool { return r.ID == record.ID }); i >= 0 {
+		batch[i] = record
+		return batch
+	}
+	return append(batch, record)
+}
+
+// dedup returns the record to ingest in place of a record, and whether there
+// is one: a duplicate is skipped, merged into the record it duplicates or
+// flagged
+func (d *DedupIngestor) dedup(ctx context.Context, record records.Record, batch []records.Record) (records.Record, bool, error) {
+	original, candidate, err := d.find(ctx, record, batch)
+	if err != nil || candidate == (Candidate{}) {
+		return record, err == nil, err
+	}
+	slog.InfoContext(ctx, "Ingesting duplicate record", "record_id", record.ID, "duplicate_of", original.ID,
+		"reason", candidate.Reason, "score", candidate.Score, "action", d.action)
+
+	switch d.action {
+	case ActionSkip:
+		return records.Record{}, false, nil
+	case ActionMerge:
+		return merge(original, record, time.Now()), true, nil
+	default:
+		if record.Metadata == nil {
+			record.Metadata = map[string]interface{}{}
+		}
+		record.Metadata[MetaDuplicateOf] = original.ID
+		return record, true, nil
+	}
+}
+
+// find returns the record a record duplicates most closely, of the batch,
+// of the records ingested last or loaded whole from the storage, with their
+// candidate pair, or an empty candidate when it duplicates none
+func (d *DedupIngestor) find(ctx context.Context, record records.Record, batch []records.Record) (records.Record, Candidate, error) {
+	if strings.TrimSpace(record.Content) == "" {
+		return records.Record{}, Candidate{}, nil
+	}
+	results, err := d.vectorStorage.Search(ctx, record.Content, neighbours)
+	if err != nil {
+		return records.Record{}, Candidate{}, fmt.Errorf("failed to search records similar to %s: %w", record.ID, err)
+	}
+
+	local := d.local(batch)
+	printed := fingerprintOf(record)
+	var best Candidate
+	var original records.Record
+	for _, neighbour := range d.neighbours(record, local, results) {
+		score, reason, ok := d.match(printed, neighbour)
+		if ok && score > best.Score {
+			best = Candidate{KeepID: neighbour.Record.ID, DuplicateID: record.ID, Score: score, Reason: reason}
+			original = neighbour.Record
+		}
+	}
+	if best == (Candidate{}) || slices.ContainsFunc(local, func(r records.Record) bool { return r.ID == best.KeepID }) {
+		return original, best, nil
+	}
+
+	// Vector stores may hold only part of an indexed record, such as one of its chunks
+	original, err = d.storage.Get(ctx, best.KeepID)
+	if errors.Is(err, storage.ErrNotFound) {
+		return records.Record{}, Candidate{}, nil
+	}
+	if err != nil {
+		return records.Record{}, Candidate{}, fmt.Errorf("failed to get record %s: %w", best.KeepID, err)
+	}
+	return original, best, nil
+}
+
+// neighbours returns the records a record may duplicate: the records kept in
+// memory and the indexed records most similar to it, leaving out the indexed
+// versions of records in memory
+func (d *DedupIngestor) neighbours(record records.Record, local []records.Record, results []records.SearchResult) []records.SearchResult {
+	found := make([]records.SearchResult, 0, len(local)+len(results))
+	for _, rec := range local {
+		found = append(found, records.SearchResult{Record: rec})
+	}
+	for _, result := range results {
+		if !slices.ContainsFunc(local, func(r records.Record) bool { return r.ID == result.Record.ID }) {
+			found = append(found, result)
+		}
+	}
+	return slices.DeleteFunc(found, func(n records.SearchResult) bool { return !d.comparable(record, n.Record) })
+}
+
+// match compares a record with a neighbour by their text, and by the
+// neighbour's embedding score when the texts differ
+func (d *DedupIngestor) match(printed fingerprint, neighbour records.SearchResult) (float64, Reason, bool) {
+	score, reason, ok := compare(printed, fingerprintOf(neighbour.Record), d.config)
+	if !ok && d.config.EmbeddingThreshold > 0 && neighbour.Score >= d.config.EmbeddingThreshold {
+		return neighbour.Score, ReasonEmbedding, true
+	}
+	return score, reason, ok
+}
+
+// comparable reports whether an indexed record may be duplicated by a record
+// ingested: it is neither the record itself, a version of the same file nor
+// a copy merged into the record
+func (d *DedupIngestor) comparable(record, indexed records.Record) bool {
+	if indexed.ID == record.ID {
+		return false
+	}
+	if path := record.SourcePath(); path != "" && path == indexed.SourcePath() {
+		return false
+	}
+	return !slices.ContainsFunc(copiesOf(record), func(c Copy) bool { return c.RecordID == indexed.ID })
+}
+
+// Delete implements ingestor.Ingestor
+func (d *DedupIngestor) Delete(ctx context.Context, id string) error {
+	if err := d.ingestor.Delete(ctx, id); err != nil {
+		return err
+	}
+	d.mu.Lock()
+	d.recent = slices.DeleteFunc(d.recent, func(r records.Record) bool { return r.ID == id })
+	d.mu.Unlock()
+	return nil
+}
+
+// Flush implements ingestor.Ingestor. Once the records ingested are indexed,
+// they are no longer kept in memory.
+func (d *DedupIngestor) Flush(ctx context.Context) error {
+	if err := d.ingestor.Flush(ctx); err != nil {
+		return err
+	}
+	d.mu.Lock()
+	d.recent = nil
+	d.mu.Unlock()
+	return nil
+}
@@ -0,0 +1,244 @@
+package duplicates_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/kazemisoroush/assistant/pkg/duplicates"
+	"github.com/kazemisoroush/assistant/pkg/records"
+	"github.com/kazemisoroush/assistant/pkg/records/chunker"
+	ingestormocks "github.com/kazemisoroush/assistant/pkg/records/ingestor/mocks"
+	"github.com/kazemisoroush/assistant/pkg/records/knowledgebase"
+	vectormocks "github.com/kazemisoroush/assistant/pkg/records/knowledgebase/mocks"
+	"github.com/kazemisoroush/assistant/pkg/records/storage"
+	storagemocks "github.com/kazemisoroush/assistant/pkg/records/storage/mocks"
+	"github.com/kazemisoroush/assistant/pkg/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+// indexedScan is the record a rescan of the same invoice duplicates
+var indexedScan = records.Record{ID: "scan", FilePath: "/docs/scan.pdf", CreatedAt: day, Content: "invoice dr. weber dental cleaning total 180 eur"}
+
+// rescan returns a record of another file with the same text as indexedScan
+func rescan() records.Record {
+	return records.Record{ID: "rescan", FilePath: "/docs/rescan.pdf", CreatedAt: day.AddDate(0, 0, 1), Content: "INVOICE Dr. Weber dental cleaning total 180 EUR"}
+}
+
+// newVectorStorage finds indexedScan for every search
+func newVectorStorage(ctrl *gomock.Controller) *vectormocks.MockVectorStorage {
+	vectorStorage := vectormocks.NewMockVectorStorage(ctrl)
+	vectorStorage.EXPECT().Search(gomock.Any(), gomock.Any(), gomock.Any()).Return([]records.SearchResult{{Record: indexedScan, Score: 0.5}}, nil).AnyTimes()
+	return vectorStorage
+}
+
+// newStorage holds indexedScan
+func newStorage(ctrl *gomock.Controller) *storagemocks.MockStorage {
+	recordStorage := storagemocks.NewMockStorage(ctrl)
+	recordStorage.EXPECT().Get(gomock.Any(), indexedScan.ID).Return(indexedScan, nil).AnyTimes()
+	return recordStorage
+}
+
+// newInner returns an ingestor that fails the test when it is flushed, as
+// the records ingested are checked without writing the index
+func newInner(ctrl *gomock.Controller) *ingestormocks.MockService {
+	return ingestormocks.NewMockService(ctrl)
+}
+
+func TestDedupIngestor_Ingest_Skip(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	inner := newInner(ctrl)
+	ing := duplicates.NewDedupIngestor(inner, newStorage(ctrl), newVectorStorage(ctrl), duplicates.Config{TextThreshold: 0.8}, duplicates.ActionSkip, 10)
+
+	// Act
+	err := ing.Ingest(context.Background(), rescan())
+
+	// Assert
+	assert.NoError(t, err, "Ingest() should skip a record with the same text as an indexed one")
+}
+
+func TestDedupIngestor_Ingest_Flag(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	inner := newInner(ctrl)
+	var ingested records.Record
+	inner.EXPECT().Ingest(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, rec records.Record) error {
+		ingested = rec
+		return nil
+	})
+	ing := duplicates.NewDedupIngestor(inner, newStorage(ctrl), newVectorStorage(ctrl), duplicates.Config{TextThreshold: 0.8}, duplicates.ActionFlag, 10)
+
+	// Act
+	err := ing.Ingest(context.Background(), rescan())
+
+	// Assert
+	require.NoError(t, err, "Ingest() error should be nil")
+	assert.Equal(t, "rescan", ingested.ID, "Ingest() should ingest a flagged duplicate")
+	assert.Equal(t, "scan", ingested.Metadata[duplicates.MetaDuplicateOf], "Ingest() should name the record a duplicate duplicates")
+}
+
+func TestDedupIngestor_IngestBatch_Merge(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	inner := newInner(ctrl)
+	unique := records.Record{ID: "lease", FilePath: "/docs/lease.pdf", Content: "tenancy agreement for the flat"}
+	var batch []records.Record
+	inner.EXPECT().IngestBatch(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, recs []records.Record) error {
+		batch = recs
+		return nil
+	})
+	ing := duplicates.NewDedupIngestor(inner, newStorage(ctrl), newVectorStorage(ctrl), duplicates.Config{TextThreshold: 0.8}, duplicates.ActionMerge, 10)
+
+	// Act
+	err := ing.IngestBatch(context.Background(), []records.Record{rescan(), unique})
+
+	// Assert
+	require.NoError(t, err, "IngestBatch() error should be nil")
+	require.Len(t, batch, 2, "IngestBatch() should ingest the merged record in place of the duplicate")
+	assert.Equal(t, "scan", batch[0].ID, "IngestBatch() should merge a duplicate into the record it duplicates")
+	assert.Equal(t, []duplicates.Copy{{RecordID: "rescan", Content: rescan().Content, CreatedAt: rescan().CreatedAt}}, batch[0].Metadata[duplicates.MetaCopies], "IngestBatch() should keep the duplicate's text")
+	assert.Equal(t, unique, batch[1], "IngestBatch() should ingest other records as they are")
+}
+
+func TestDedupIngestor_IngestBatch_WithinBatch(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	inner := newInner(ctrl)
+	vectorStorage := vectormocks.NewMockVectorStorage(ctrl)
+	vectorStorage.EXPECT().Search(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+	inner.EXPECT().IngestBatch(gomock.Any(), []records.Record{indexedScan}).Return(nil)
+	ing := duplicates.NewDedupIngestor(inner, storagemocks.NewMockStorage(ctrl), vectorStorage, duplicates.Config{TextThreshold: 0.8}, duplicates.ActionSkip, 10)
+
+	// Act
+	err := ing.IngestBatch(context.Background(), []records.Record{indexedScan, rescan()})
+
+	// Assert
+	assert.NoError(t, err, "IngestBatch() should skip a record duplicating one before it in the batch")
+}
+
+func TestDedupIngestor_Ingest_Recent(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	inner := newInner(ctrl)
+	vectorStorage := vectormocks.NewMockVectorStorage(ctrl)
+	vectorStorage.EXPECT().Search(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+	inner.EXPECT().Ingest(gomock.Any(), indexedScan).Return(nil)
+	ing := duplicates.NewDedupIngestor(inner, storagemocks.NewMockStorage(ctrl), vectorStorage, duplicates.Config{TextThreshold: 0.8}, duplicates.ActionSkip, 10)
+	require.NoError(t, ing.Ingest(ctx, indexedScan), "Ingest() error should be nil")
+
+	// Act
+	err := ing.Ingest(ctx, rescan())
+
+	// Assert
+	assert.NoError(t, err, "Ingest() should skip a record duplicating one ingested before it was indexed")
+}
+
+func TestDedupIngestor_Flush_ForgetsRecent(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	inner := newInner(ctrl)
+	vectorStorage := vectormocks.NewMockVectorStorage(ctrl)
+	vectorStorage.EXPECT().Search(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+	inner.EXPECT().Ingest(gomock.Any(), indexedScan).Return(nil)
+	inner.EXPECT().Flush(gomock.Any()).Return(nil)
+	inner.EXPECT().Ingest(gomock.Any(), rescan()).Return(nil)
+	ing := duplicates.NewDedupIngestor(inner, storagemocks.NewMockStorage(ctrl), vectorStorage, duplicates.Config{TextThreshold: 0.8}, duplicates.ActionSkip, 10)
+	require.NoError(t, ing.Ingest(ctx, indexedScan), "Ingest() error should be nil")
+	require.NoError(t, ing.Flush(ctx), "Flush() error should be nil")
+
+	// Act
+	err := ing.Ingest(ctx, rescan())
+
+	// Assert
+	assert.NoError(t, err, "Ingest() should leave records flushed to the index for the vector search")
+}
+
+func TestDedupIngestor_Ingest_MergeChunked(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	inner := newInner(ctrl)
+	long := " of the practice in the old town for the appointment on the first of march paid by card"
+	stored := indexedScan
+	stored.Content += long
+	recordStorage := storagemocks.NewMockStorage(ctrl)
+	recordStorage.EXPECT().Get(gomock.Any(), stored.ID).Return(stored, nil).AnyTimes()
+	db, err := sqlite.Open(":memory:")
+	require.NoError(t, err, "Open() error should be nil")
+	defer func() { _ = db.Close() }()
+	vectorStorage, err := knowledgebase.NewVectorStorage(knowledgebase.VectorStorageConfig{
+		Backend:     "local",
+		Records:     recordStorage,
+		Chunks:      chunker.Config{Size: 4},
+		ChunkCounts: db,
+	})
+	require.NoError(t, err, "NewVectorStorage() error should be nil")
+	require.NoError(t, vectorStorage.Index(ctx, stored), "Index() error should be nil")
+	duplicate := rescan()
+	duplicate.Content += long
+	var merged records.Record
+	inner.EXPECT().Ingest(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, rec records.Record) error {
+		merged = rec
+		return nil
+	})
+	ing := duplicates.NewDedupIngestor(inner, recordStorage, vectorStorage, duplicates.Config{TextThreshold: 0.8}, duplicates.ActionMerge, 10)
+
+	// Act
+	err = ing.Ingest(ctx, duplicate)
+
+	// Assert
+	require.NoError(t, err, "Ingest() error should be nil")
+	assert.Equal(t, stored.ID, merged.ID, "Ingest() should merge a duplicate into the record it duplicates")
+	assert.Equal(t, stored.Content, merged.Content, "Ingest() should keep the whole content of a chunked record")
+}
+
+func TestDedupIngestor_Ingest_OriginalDeleted(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	inner := newInner(ctrl)
+	recordStorage := storagemocks.NewMockStorage(ctrl)
+	recordStorage.EXPECT().Get(gomock.Any(), indexedScan.ID).Return(records.Record{}, storage.ErrNotFound)
+	inner.EXPECT().Ingest(gomock.Any(), rescan()).Return(nil)
+	ing := duplicates.NewDedupIngestor(inner, recordStorage, newVectorStorage(ctrl), duplicates.Config{TextThreshold: 0.8}, duplicates.ActionMerge, 10)
+
+	// Act
+	err := ing.Ingest(context.Background(), rescan())
+
+	// Assert
+	assert.NoError(t, err, "Ingest() should ingest a record whose indexed duplicate was deleted as it is")
+}
+
+func TestDedupIngestor_Ingest_SameFile(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	inner := newInner(ctrl)
+	version := rescan()
+	version.FilePath = indexedScan.FilePath
+	inner.EXPECT().Ingest(gomock.Any(), version).Return(nil)
+	ing := duplicates.NewDedupIngestor(inner, newStorage(ctrl), newVectorStorage(ctrl), duplicates.Config{TextThreshold: 0.8}, duplicates.ActionSkip, 10)
+
+	// Act
+	err := ing.Ingest(context.Background(), version)
+
+	// Assert
+	assert.NoError(t, err, "Ingest() should ingest a new version of a file as it is")
+}
+
+func TestDedupIngestor_Ingest_SearchFailure(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	vectorStorage := vectormocks.NewMockVectorStorage(ctrl)
+	vectorStorage.EXPECT().Search(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, errors.New("unavailable"))
+	ing := duplicates.NewDedupIngestor(newInner(ctrl), newStorage(ctrl), vectorStorage, duplicates.Config{TextThreshold: 0.8}, duplicates.ActionSkip, 10)
+
+	// Act
+	err := ing.Ingest(context.Background(), rescan())
+
+	// Assert
+	assert.Error(t, err, "Ingest() should fail when similar records fail to be searched")
+}
@@ -0,0 +1,55 @@
+// Package duplicates finds records that are copies of the same document, such
+// as re-scans or an emailed and a paper copy, and merges them.
+package duplicates
+
+import (
+	"context"
+	"time"
+
+	"github.com/kazemisoroush/assistant/pkg/records"
+)
+
+// Reason represents why two records are considered duplicates
+type Reason string
+
+// Duplicate reasons, strongest first
+const (
+	ReasonContentHash Reason = "content_hash"    // Same text after folding case and whitespace
+	ReasonText        Reason = "text_similarity" // Mostly the same words, e.g. two scans of one page
+	ReasonEmbedding   Reason = "embedding"       // Near-identical meaning according to the vector store
+)
+
+// MetaCopies is the metadata key holding the merged duplicates of a record
+const MetaCopies = "copies"
+
+// Candidate represents a pair of records that look like the same document
+type Candidate struct {
+	KeepID      string  `json:"keep_id"`      // The older record, kept by default
+	DuplicateID string  `json:"duplicate_id"` // The newer record, merged into the kept one
+	Score       float64 `json:"score"`        // Similarity between 0 and 1
+	Reason      Reason  `json:"reason"`
+}
+
+// Copy represents a duplicate merged into a record; its text is kept so no scan is lost
+type Copy struct {
+	RecordID  string    `json:"record_id"`
+	Content   string    `json:"content"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Detector finds duplicate records
+//
+//go:generate mockgen -destination=./mocks/mock_detector.go -mock_names=Detector=MockDetector -package=mocks . Detector
+type Detector interface {
+	// Find returns the likely duplicate pairs, most similar first
+	Find(ctx context.Context) ([]Candidate, error)
+}
+
+// Merger merges duplicate records
+//
+//go:generate mockgen -destination=./mocks/mock_merger.go -mock_names=Merger=MockMerger -package=mocks . Merger
+type Merger interface {
+	// Merge folds the duplicate into the kept record, redirects links to it and
+	// deletes the duplicate. It returns the merged record.
+	Merge(ctx context.Context, keepID, duplicateID string) (records.Record, error)
+}
@@ -0,0 +1,179 @@
+package duplicates
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/kazemisoroush/assistant/pkg/records"
+	"github.com/kazemisoroush/assistant/pkg/records/knowledgebase"
+	"github.com/kazemisoroush/assistant/pkg/records/storage"
+)
+
+// shingleSize is the number of consecutive words compared by text similarity
+const shingleSize = 3
+
+// neighbours is how many semantic search results are checked per record
+const neighbours = 5
+
+// Config represents how similar two records must be to be reported
+type Config struct {
+	TextThreshold      float64 // Minimum share of word shingles in common
+	EmbeddingThreshold float64 // Minimum vector search score; 0 disables embedding comparison
+}
+
+// RecordDetector compares stored records by content hash, word shingles and,
+// optionally, embedding similarity
+type RecordDetector struct {
+	storage       storage.Storage
+	vectorStorage knowledgebase.VectorStorage
+	config        Config
+}
+
+// NewRecordDetector creates a new duplicate detector
+func NewRecordDetector(storage storage.Storage, vectorStorage knowledgebase.VectorStorage, config Config) Detector {
+	return &RecordDetector{
+		storage:       storage,
+		vectorStorage: vectorStorage,
+		config:        config,
+	}
+}
+
+// fingerprint is the normalized text of a record used for comparison
+type fingerprint struct {
+	record   records.Record
+	hash     [sha256.Size]byte
+	shingles map[string]bool
+}
+
+// Find implements Detector. Each pair is reported once, under its strongest reason.
+func (d *RecordDetector) Find(ctx context.Context) ([]Candidate, error) {
+	recs, err := d.storage.List(ctx, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list records: %w", err)
+	}
+
+	prints := make([]fingerprint, 0, len(recs))
+	byID := map[string]records.Record{}
+	for _, rec := range recs {
+		if strings.TrimSpace(rec.Content) == "" {
+			continue
+		}
+		prints = append(prints, fingerprintOf(rec))
+		byID[rec.ID] = rec
+	}
+
+	found := map[[2]string]Candidate{}
+	d.findTextual(prints, found)
+	if d.config.EmbeddingThreshold > 0 {
+		if err := d.findSemantic(ctx, prints, byID, found); err != nil {
+			return nil, err
+		}
+	}
+	return sorted(found), nil
+}
+
+// findTextual adds the pairs with identical or mostly identical text
+func (d *RecordDetector) findTextual(prints []fingerprint, found map[[2]string]Candidate) {
+	for i := range prints {
+		for j := i + 1; j < len(prints); j++ {
+			if score, reason, ok := compare(prints[i], prints[j], d.config); ok {
+				add(found, candidate(prints[i].record, prints[j].record, score, reason))
+			}
+		}
+	}
+}
+
+// sorted returns the candidates most similar first
+func sorted(found map[[2]string]Candidate) []Candidate {
+	candidates := make([]Candidate, 0, len(found))
+	for _, c := range found {
+		candidates = append(candidates, c)
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].Score != candidates[j].Score {
+			return candidates[i].Score > candidates[j].Score
+		}
+		return candidates[i].KeepID+candidates[i].DuplicateID < candidates[j].KeepID+candidates[j].DuplicateID
+	})
+	return candidates
+}
+
+// compare checks two records for identical or mostly identical text
+func compare(a, b fingerprint, config Config) (float64, Reason, bool) {
+	if a.hash == b.hash {
+		return 1, ReasonContentHash, true
+	}
+	if score := jaccard(a.shingles, b.shingles); score >= config.TextThreshold {
+		return score, ReasonText, true
+	}
+	return 0, "", false
+}
+
+// findSemantic adds the pairs the vector store scores above the embedding threshold
+func (d *RecordDetector) findSemantic(ctx context.Context, prints []fingerprint, byID map[string]records.Record, found map[[2]string]Candidate) error {
+	for _, fp := range prints {
+		results, err := d.vectorStorage.Search(ctx, fp.record.Content, neighbours)
+		if err != nil {
+			return fmt.Errorf("failed to search records similar to %s: %w", fp.record.ID, err)
+		}
+		for _, result := range results {
+			other, ok := byID[result.Record.ID]
+			if !ok || other.ID == fp.record.ID || result.Score < d.config.EmbeddingThreshold {
+				continue
+			}
+			add(found, candidate(fp.record, other, result.Score, ReasonEmbedding))
+		}
+	}
+	return nil
+}
+
+// add records a candidate unless the pair was already found
+func add(found map[[2]string]Candidate, c Candidate) {
+	key := [2]string{c.KeepID, c.DuplicateID}
+	if _, ok := found[key]; !ok {
+		found[key] = c
+	}
+}
+
+// candidate pairs two records, keeping the one ingested first
+func candidate(a, b records.Record, score float64, reason Reason) Candidate {
+	if b.CreatedAt.Before(a.CreatedAt) || (b.CreatedAt.Equal(a.CreatedAt) && b.ID < a.ID) {
+		a, b = b, a
+	}
+	return Candidate{KeepID: a.ID, DuplicateID: b.ID, Score: score, Reason: reason}
+}
+
+// fingerprintOf hashes and shingles the record text with case and whitespace folded
+func fingerprintOf(rec records.Record) fingerprint {
+	words := strings.Fields(strings.ToLower(rec.Content))
+	shingles := map[string]bool{}
+	for i := 0; i+shingleSize <= len(words); i++ {
+		shingles[strings.Join(words[i:i+shingleSize], " ")] = true
+	}
+	if len(words) < shingleSize {
+		shingles[strings.Join(words, " ")] = true
+	}
+	return fingerprint{
+		record:   rec,
+		hash:     sha256.Sum256([]byte(strings.Join(words, " "))),
+		shingles: shingles,
+	}
+}
+
+// jaccard returns the share of shingles two texts have in common
+func jaccard(a, b map[string]bool) float64 {
+	common := 0
+	for shingle := range a {
+		if b[shingle] {
+			common++
+		}
+	}
+	total := len(a) + len(b) - common
+	if total == 0 {
+		return 0
+	}
+	return float64(common) / float64(total)
+}
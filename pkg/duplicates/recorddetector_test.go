@@ -0,0 +1,66 @@
+package duplicates_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/kazemisoroush/assistant/pkg/duplicates"
+	"github.com/kazemisoroush/assistant/pkg/records"
+	vectormocks "github.com/kazemisoroush/assistant/pkg/records/knowledgebase/mocks"
+	"github.com/kazemisoroush/assistant/pkg/records/storage/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+var day = time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC)
+
+func TestRecordDetector_Find(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	storage := mocks.NewMockStorage(ctrl)
+	storage.EXPECT().List(gomock.Any(), records.RecordType("")).Return([]records.Record{
+		{ID: "rescan", CreatedAt: day.AddDate(0, 1, 0), Content: "INVOICE  Dr. Weber dental cleaning total 180 EUR paid on 1 March 2024"},
+		{ID: "scan", CreatedAt: day, Content: "invoice dr. weber dental cleaning total 180 eur paid on 1 march 2024"},
+		{ID: "email", CreatedAt: day.AddDate(0, 0, 2), Content: "Invoice Dr. Weber dental cleaning total 180 EUR paid on 1 March 2024 thank you"},
+		{ID: "unrelated", CreatedAt: day, Content: "Lidl groceries bread milk total 12 EUR"},
+		{ID: "empty", CreatedAt: day},
+	}, nil)
+	detector := duplicates.NewRecordDetector(storage, vectormocks.NewMockVectorStorage(ctrl), duplicates.Config{TextThreshold: 0.8})
+
+	// Act
+	candidates, err := detector.Find(context.Background())
+
+	// Assert
+	require.NoError(t, err, "Find() error should be nil")
+	require.Len(t, candidates, 3, "Find() should pair every copy of the invoice once")
+	assert.Equal(t, duplicates.Candidate{KeepID: "scan", DuplicateID: "rescan", Score: 1, Reason: duplicates.ReasonContentHash}, candidates[0], "Find() should match identical text regardless of case and whitespace, keeping the older record")
+	assert.Equal(t, duplicates.ReasonText, candidates[1].Reason, "Find() should match mostly identical text")
+	assert.Equal(t, [2]string{"email", "rescan"}, [2]string{candidates[1].KeepID, candidates[1].DuplicateID}, "Find() should keep the older record")
+	assert.Equal(t, [2]string{"scan", "email"}, [2]string{candidates[2].KeepID, candidates[2].DuplicateID}, "Find() should keep the older record")
+}
+
+func TestRecordDetector_Find_Embeddings(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	storage := mocks.NewMockStorage(ctrl)
+	paper := records.Record{ID: "paper", CreatedAt: day, Content: "Car insurance policy renewal for 2024"}
+	email := records.Record{ID: "email", CreatedAt: day.AddDate(0, 0, 1), Content: "Your motor policy has been renewed for the year 2024"}
+	storage.EXPECT().List(gomock.Any(), records.RecordType("")).Return([]records.Record{paper, email}, nil)
+	vectorStorage := vectormocks.NewMockVectorStorage(ctrl)
+	vectorStorage.EXPECT().Search(gomock.Any(), paper.Content, gomock.Any()).Return([]records.SearchResult{
+		{Record: paper, Score: 1}, {Record: email, Score: 0.97},
+	}, nil)
+	vectorStorage.EXPECT().Search(gomock.Any(), email.Content, gomock.Any()).Return([]records.SearchResult{
+		{Record: email, Score: 1}, {Record: paper, Score: 0.97},
+	}, nil)
+	detector := duplicates.NewRecordDetector(storage, vectorStorage, duplicates.Config{TextThreshold: 0.8, EmbeddingThreshold: 0.95})
+
+	// Act
+	candidates, err := detector.Find(context.Background())
+
+	// Assert
+	require.NoError(t, err, "Find() error should be nil")
+	assert.Equal(t, []duplicates.Candidate{{KeepID: "paper", DuplicateID: "email", Score: 0.97, Reason: duplicates.ReasonEmbedding}}, candidates, "Find() should report semantically near-identical records once")
+}
@@ -0,0 +1,161 @@
+package duplicates
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/kazemisoroush/assistant/pkg/records"
+	"github.com/kazemisoroush/assistant/pkg/records/ingestor"
+	"github.com/kazemisoroush/assistant/pkg/records/storage"
+)
+
+// RecordMerger merges stored records. The merged record is re-ingested so
+// search and derived records follow it.
+type RecordMerger struct {
+	storage  storage.Storage
+	ingestor ingestor.Ingestor
+}
+
+// NewRecordMerger creates a new duplicate merger
+func NewRecordMerger(storage storage.Storage, ingestor ingestor.Ingestor) Merger {
+	return &RecordMerger{
+		storage:  storage,
+		ingestor: ingestor,
+	}
+}
+
+// Merge implements Merger
+func (m *RecordMerger) Merge(ctx context.Context, keepID, duplicateID string) (records.Record, error) {
+	if keepID == duplicateID {
+		return records.Record{}, errors.New("cannot merge a record into itself")
+	}
+	keep, err := m.storage.Get(ctx, keepID)
+	if err != nil {
+		return records.Record{}, fmt.Errorf("failed to get record %s: %w", keepID, err)
+	}
+	duplicate, err := m.storage.Get(ctx, duplicateID)
+	if err != nil {
+		return records.Record{}, fmt.Errorf("failed to get record %s: %w", duplicateID, err)
+	}
+
+	merged := merge(keep, duplicate, time.Now())
+	if err := m.ingestor.Ingest(ctx, merged); err != nil {
+		return records.Record{}, fmt.Errorf("failed to ingest merged record: %w", err)
+	}
+	if err := m.redirectLinks(ctx, duplicateID, keepID); err != nil {
+		return records.Record{}, err
+	}
+	if err := m.ingestor.Delete(ctx, duplicateID); err != nil {
+		return records.Record{}, fmt.Errorf("failed to delete duplicate %s: %w", duplicateID, err)
+	}
+	if err := m.ingestor.Flush(ctx); err != nil {
+		return records.Record{}, fmt.Errorf("failed to index merged record: %w", err)
+	}
+	return merged, nil
+}
+
+// redirectLinks points the links of every other record from one record to another
+func (m *RecordMerger) redirectLinks(ctx context.Context, from, to string) error {
+	recs, err := m.storage.List(ctx, "")
+	if err != nil {
+		return fmt.Errorf("failed to list records: %w", err)
+	}
+	for _, rec := range recs {
+		links := rec.Links()
+		if rec.ID == to || rec.ID == from || !slices.Contains(links, from) {
+			continue
+		}
+		rec.Metadata[records.MetaLinks] = union(links, []string{to}, from)
+		if err := m.storage.Update(ctx, rec); err != nil {
+			return fmt.Errorf("failed to redirect links of %s: %w", rec.ID, err)
+		}
+	}
+	return nil
+}
+
+// merge folds the duplicate into the kept record. Fields of the kept record
+// win; the duplicate fills the gaps and its text is kept as a copy.
+func merge(keep, duplicate records.Record, now time.Time) records.Record {
+	merged := keep
+	merged.Metadata = map[string]interface{}{}
+	for key, value := range duplicate.Metadata {
+		merged.Metadata[key] = value
+	}
+	for key, value := range keep.Metadata {
+		if !isEmpty(value) || isEmpty(merged.Metadata[key]) {
+			merged.Metadata[key] = value
+		}
+	}
+
+	if merged.Type == records.RecordTypeOther {
+		merged.Type = duplicate.Type
+	}
+	if merged.Content == "" {
+		merged.Content = duplicate.Content
+	}
+	if duplicate.CreatedAt.Before(merged.CreatedAt) {
+		merged.CreatedAt = duplicate.CreatedAt
+	}
+	if merged.ExpiresAt == nil {
+		merged.ExpiresAt = duplicate.ExpiresAt
+	}
+	merged.UpdatedAt = now
+	merged.Tags = union(keep.Tags, duplicate.Tags)
+
+	if links := union(keep.Links(), duplicate.Links(), keep.ID, duplicate.ID); len(links) > 0 {
+		merged.Metadata[records.MetaLinks] = links
+	}
+
+	copies := append(copiesOf(keep), Copy{RecordID: duplicate.ID, Content: duplicate.Content, CreatedAt: duplicate.CreatedAt})
+	merged.Metadata[MetaCopies] = append(copies, copiesOf(duplicate)...)
+	return merged
+}
+
+// union returns the distinct values of both lists, leaving out the excluded ones
+func union(a, b []string, exclude ...string) []string {
+	var dst []string
+	for _, value := range slices.Concat(a, b) {
+		if value == "" || slices.Contains(exclude, value) || slices.Contains(dst, value) {
+			continue
+		}
+		dst = append(dst, value)
+	}
+	return dst
+}
+
+// copiesOf returns the duplicates previously merged into a record
+func copiesOf(rec records.Record) []Copy {
+	raw, ok := rec.Metadata[MetaCopies]
+	if !ok {
+		return nil
+	}
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return nil
+	}
+	var copies []Copy
+	if err := json.Unmarshal(data, &copies); err != nil {
+		return nil
+	}
+	return copies
+}
+
+// isEmpty reports whether a metadata value carries no information
+func isEmpty(value interface{}) bool {
+	switch v := value.(type) {
+	case nil:
+		return true
+	case string:
+		return v == ""
+	case float64:
+		return v == 0
+	case int:
+		return v == 0
+	default:
+		return false
+	}
+}
@@ -0,0 +1,69 @@
+package duplicates_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kazemisoroush/assistant/pkg/duplicates"
+	"github.com/kazemisoroush/assistant/pkg/records"
+	ingestormocks "github.com/kazemisoroush/assistant/pkg/records/ingestor/mocks"
+	"github.com/kazemisoroush/assistant/pkg/records/storage/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestRecordMerger_Merge(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	storage := mocks.NewMockStorage(ctrl)
+	ingestor := ingestormocks.NewMockService(ctrl)
+	keep := records.Record{ID: "scan", Type: records.RecordTypeReceipt, CreatedAt: day.AddDate(0, 0, 1), Content: "scanned invoice", Tags: []string{"TBA"},
+		Metadata: map[string]interface{}{"merchant": "Dr. Weber", "category": ""}}
+	duplicate := records.Record{ID: "email", Type: records.RecordTypeReceipt, CreatedAt: day, Content: "emailed invoice", Tags: []string{"TBA", "email"},
+		Metadata: map[string]interface{}{"merchant": "Weber Dental", "category": "health", records.MetaLinks: []interface{}{"visit-1"}}}
+	warranty := records.Record{ID: "warranty-email", Metadata: map[string]interface{}{records.MetaLinks: []interface{}{"email"}}}
+	storage.EXPECT().Get(gomock.Any(), "scan").Return(keep, nil)
+	storage.EXPECT().Get(gomock.Any(), "email").Return(duplicate, nil)
+	storage.EXPECT().List(gomock.Any(), records.RecordType("")).Return([]records.Record{keep, duplicate, warranty}, nil)
+	var merged, redirected records.Record
+	gomock.InOrder(
+		ingestor.EXPECT().Ingest(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, rec records.Record) error {
+			merged = rec
+			return nil
+		}),
+		storage.EXPECT().Update(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, rec records.Record) error {
+			redirected = rec
+			return nil
+		}),
+		ingestor.EXPECT().Delete(gomock.Any(), "email").Return(nil),
+		ingestor.EXPECT().Flush(gomock.Any()).Return(nil),
+	)
+
+	// Act
+	result, err := duplicates.NewRecordMerger(storage, ingestor).Merge(context.Background(), "scan", "email")
+
+	// Assert
+	require.NoError(t, err, "Merge() error should be nil")
+	assert.Equal(t, merged, result, "Merge() should return the ingested record")
+	assert.Equal(t, "scan", merged.ID, "Merge() should keep the kept record's ID")
+	assert.Equal(t, "Dr. Weber", merged.Metadata["merchant"], "Merge() should prefer the kept record's metadata")
+	assert.Equal(t, "health", merged.Metadata["category"], "Merge() should fill gaps from the duplicate")
+	assert.Equal(t, []string{"visit-1"}, merged.Links(), "Merge() should keep the duplicate's links")
+	assert.Equal(t, []string{"TBA", "email"}, merged.Tags, "Merge() should combine tags")
+	assert.Equal(t, day, merged.CreatedAt, "Merge() should keep the earliest creation time")
+	assert.Equal(t, []duplicates.Copy{{RecordID: "email", Content: "emailed invoice", CreatedAt: day}}, merged.Metadata[duplicates.MetaCopies], "Merge() should keep the duplicate's text")
+	assert.Equal(t, []string{"scan"}, redirected.Links(), "Merge() should redirect links to the kept record")
+}
+
+func TestRecordMerger_Merge_SameRecord(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	merger := duplicates.NewRecordMerger(mocks.NewMockStorage(ctrl), ingestormocks.NewMockService(ctrl))
+
+	// Act
+	_, err := merger.Merge(context.Background(), "scan", "scan")
+
+	// Assert
+	assert.Error(t, err, "Merge() should refuse to merge a record into itself")
+}
@@ -0,0 +1,88 @@
+// Package entities extracts the people and organizations named in records and
+// indexes them for browsing records by entity.
+package entities
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kazemisoroush/assistant/pkg/records"
+)
+
+// Kind represents the role of an entity
+type Kind string
+
+// Entity kinds
+const (
+	KindDoctor   Kind = "doctor"
+	KindClinic   Kind = "clinic"
+	KindVendor   Kind = "vendor"
+	KindEmployer Kind = "employer"
+	KindInsurer  Kind = "insurer"
+)
+
+// AllKinds returns every entity kind
+func AllKinds() []Kind {
+	return []Kind{KindDoctor, KindClinic, KindVendor, KindEmployer, KindInsurer}
+}
+
+// ParseKind validates an entity kind; the empty string stands for all kinds
+func ParseKind(s string) (Kind, error) {
+	if s == "" {
+		return "", nil
+	}
+	for _, kind := range AllKinds() {
+		if string(kind) == s {
+			return kind, nil
+		}
+	}
+	return "", fmt.Errorf("unknown entity kind %q", s)
+}
+
+// Entity represents a named entity found in a record
+type Entity struct {
+	Name string `json:"name"`
+	Kind Kind   `json:"kind"`
+}
+
+// Summary represents an indexed entity and how many records involve it
+type Summary struct {
+	Entity
+	Records int `json:"records"`
+}
+
+// Extractor finds the entities named in a record
+//
+//go:generate mockgen -destination=./mocks/mock_extractor.go -mock_names=Extractor=MockExtractor -package=mocks . Extractor
+type Extractor interface {
+	// Extract returns the distinct entities named in the record
+	Extract(ctx context.Context, rec records.Record) ([]Entity, error)
+}
+
+// Index associates entities with the records naming them
+//
+//go:generate mockgen -destination=./mocks/mock_index.go -mock_names=Index=MockIndex -package=mocks . Index
+type Index interface {
+	// Set replaces the entities associated with a record
+	Set(ctx context.Context, recordID string, entities []Entity) error
+
+	// Remove drops the associations of a record
+	Remove(ctx context.Context, recordID string) error
+
+	// Entities lists indexed entities, optionally of one kind, most referenced first
+	Entities(ctx context.Context, kind Kind) ([]Summary, error)
+
+	// RecordIDs returns the records naming an entity, matched case-insensitively
+	RecordIDs(ctx context.Context, name string) ([]string, error)
+}
+
+// Browser lists indexed entities and the records naming them
+//
+//go:generate mockgen -destination=./mocks/mock_browser.go -mock_names=Browser=MockBrowser -package=mocks . Browser
+type Browser interface {
+	// Entities lists indexed entities, optionally of one kind
+	Entities(ctx context.Context, kind Kind) ([]Summary, error)
+
+	// Records returns the records naming an entity
+	Records(ctx context.Context, name string) ([]records.Record, error)
+}
@@ -0,0 +1,46 @@
+package entities
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kazemisoroush/assistant/pkg/records"
+	"github.com/kazemisoroush/assistant/pkg/records/storage"
+)
+
+// IndexBrowser resolves entity index entries to stored records
+type IndexBrowser struct {
+	index   Index
+	storage storage.Storage
+}
+
+// NewIndexBrowser creates a new index browser
+func NewIndexBrowser(index Index, storage storage.Storage) Browser {
+	return &IndexBrowser{
+		index:   index,
+		storage: storage,
+	}
+}
+
+// Entities implements Browser
+func (b *IndexBrowser) Entities(ctx context.Context, kind Kind) ([]Summary, error) {
+	return b.index.Entities(ctx, kind)
+}
+
+// Records implements Browser
+func (b *IndexBrowser) Records(ctx context.Context, name string) ([]records.Record, error) {
+	ids, err := b.index.RecordIDs(ctx, name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up entity records: %w", err)
+	}
+
+	result := make([]records.Record, 0, len(ids))
+	for _, id := range ids {
+		rec, err := b.storage.Get(ctx, id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get record %s: %w", id, err)
+		}
+		result = append(result, rec)
+	}
+	return result, nil
+}
@@ -0,0 +1,69 @@
+package entities
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/kazemisoroush/assistant/pkg/ai"
+	"github.com/kazemisoroush/assistant/pkg/prompts"
+	"github.com/kazemisoroush/assistant/pkg/records"
+)
+
+// LLMExtractor uses a language model to find entities in record content
+type LLMExtractor struct {
+	provider ai.Provider
+	prompts  prompts.Renderer
+}
+
+// extraction is the JSON the model is asked for
+type extraction struct {
+	Entities []Entity `json:"entities"`
+}
+
+// NewLLMExtractor creates a new LLM entity extractor
+func NewLLMExtractor(provider ai.Provider, prompts prompts.Renderer) Extractor {
+	return &LLMExtractor{
+		provider: provider,
+		prompts:  prompts,
+	}
+}
+
+// Extract implements Extractor. Entities of unknown kinds are dropped.
+func (l *LLMExtractor) Extract(ctx context.Context, rec records.Record) ([]Entity, error) {
+	kinds := make([]string, 0, len(AllKinds()))
+	for _, kind := range AllKinds() {
+		kinds = append(kinds, string(kind))
+	}
+
+	prompt, err := l.prompts.Render(prompts.EntityExtraction, map[string]any{
+		"Kinds": strings.Join(kinds, ", "),
+		"Text":  rec.Content,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var out extraction
+	if err := l.provider.GenerateJSON(ctx, ai.Request{Prompt: prompt, Task: ai.TaskEntityExtraction, RecordType: string(rec.Type)}, &out); err != nil {
+		return nil, fmt.Errorf("failed to extract entities of record %s: %w", rec.ID, err)
+	}
+
+	seen := map[Entity]bool{}
+	var found []Entity
+	for _, entity := range out.Entities {
+		entity.Name = strings.TrimSpace(entity.Name)
+		entity.Kind = Kind(strings.ToLower(strings.TrimSpace(string(entity.Kind))))
+		if entity.Name == "" || !slices.Contains(AllKinds(), entity.Kind) {
+			continue
+		}
+		key := Entity{Name: strings.ToLower(entity.Name), Kind: entity.Kind}
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		found = append(found, entity)
+	}
+	return found, nil
+}
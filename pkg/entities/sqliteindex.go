@@ -0,0 +1,136 @@
+package entities
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/kazemisoroush/assistant/pkg/sqlite"
+)
+
+// SQLiteIndex is an Index backed by SQLite
+type SQLiteIndex struct {
+	db *sqlite.DB
+}
+
+// NewSQLiteIndex creates a new SQLite entity index in the given database
+func NewSQLiteIndex(db *sqlite.DB) (*SQLiteIndex, error) {
+	schema := `
+    CREATE TABLE IF NOT EXISTS entities (
+        id INTEGER PRIMARY KEY AUTOINCREMENT,
+        name TEXT NOT NULL,
+        normalized_name TEXT NOT NULL,
+        kind TEXT NOT NULL,
+        UNIQUE (normalized_name, kind)
+    );
+
+    CREATE TABLE IF NOT EXISTS entity_records (
+        entity_id INTEGER NOT NULL REFERENCES entities(id),
+        record_id TEXT NOT NULL,
+        PRIMARY KEY (entity_id, record_id)
+    );
+
+    CREATE INDEX IF NOT EXISTS idx_entity_records_record_id ON entity_records(record_id);
+    `
+	if _, err := db.Exec(schema); err != nil {
+		return nil, fmt.Errorf("failed to initialize entity schema: %w", err)
+	}
+
+	return &SQLiteIndex{db: db}, nil
+}
+
+// Set implements Index
+func (s *SQLiteIndex) Set(ctx context.Context, recordID string, entities []Entity) error {
+	err := s.db.Transact(ctx, func(tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx, `DELETE FROM entity_records WHERE record_id = ?`, recordID); err != nil {
+			return fmt.Errorf("failed to clear entities of record %s: %w", recordID, err)
+		}
+
+		for _, entity := range entities {
+			if _, err := tx.ExecContext(ctx,
+				`INSERT INTO entities (name, normalized_name, kind) VALUES (?, ?, ?) ON CONFLICT (normalized_name, kind) DO NOTHING`,
+				entity.Name, normalize(entity.Name), entity.Kind,
+			); err != nil {
+				return fmt.Errorf("failed to store entity %s: %w", entity.Name, err)
+			}
+			if _, err := tx.ExecContext(ctx,
+				`INSERT OR IGNORE INTO entity_records (entity_id, record_id)
+             SELECT id, ? FROM entities WHERE normalized_name = ? AND kind = ?`,
+				recordID, normalize(entity.Name), entity.Kind,
+			); err != nil {
+				return fmt.Errorf("failed to associate entity %s: %w", entity.Name, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to set entities of record %s: %w", recordID, err)
+	}
+	return nil
+}
+
+// Remove implements Index
+func (s *SQLiteIndex) Remove(ctx context.Context, recordID string) error {
+	if _, err := s.db.ExecContext(ctx, `DELETE FROM entity_records WHERE record_id = ?`, recordID); err != nil {
+		return fmt.Errorf("failed to remove entities of record %s: %w", recordID, err)
+	}
+	return nil
+}
+
+// Entities implements Index
+func (s *SQLiteIndex) Entities(ctx context.Context, kind Kind) ([]Summary, error) {
+	query := `
+        SELECT e.name, e.kind, COUNT(er.record_id) AS records
+        FROM entities e
+        JOIN entity_records er ON er.entity_id = e.id
+        WHERE ? = '' OR e.kind = ?
+        GROUP BY e.id
+        ORDER BY records DESC, e.name
+    `
+	rows, err := s.db.QueryContext(ctx, query, kind, kind)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list entities: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var summaries []Summary
+	for rows.Next() {
+		var summary Summary
+		if err := rows.Scan(&summary.Name, &summary.Kind, &summary.Records); err != nil {
+			return nil, fmt.Errorf("failed to scan entity: %w", err)
+		}
+		summaries = append(summaries, summary)
+	}
+	return summaries, rows.Err()
+}
+
+// RecordIDs implements Index
+func (s *SQLiteIndex) RecordIDs(ctx context.Context, name string) ([]string, error) {
+	rows, err := s.db.QueryContext(ctx, `
+        SELECT DISTINCT er.record_id
+        FROM entities e
+        JOIN entity_records er ON er.entity_id = e.id
+        WHERE e.normalized_name = ?
+        ORDER BY er.record_id
+    `, normalize(name))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find records of entity %s: %w", name, err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan record ID: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// normalize folds case and whitespace so spellings of the same name match
+func normalize(name string) string {
+	return strings.ToLower(strings.Join(strings.Fields(name), " "))
+}
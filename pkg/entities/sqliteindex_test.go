@@ -0,0 +1,61 @@
+package entities_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/kazemisoroush/assistant/pkg/entities"
+	"github.com/kazemisoroush/assistant/pkg/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// openDB opens the database at path, closed once the test is done
+func openDB(t testing.TB, path string) *sqlite.DB {
+	t.Helper()
+	db, err := sqlite.Open(path)
+	require.NoError(t, err, "Open() error should be nil")
+	t.Cleanup(func() { _ = db.Close() })
+	return db
+}
+
+func TestSQLiteIndex_BrowseByEntity(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	index, err := entities.NewSQLiteIndex(openDB(t, filepath.Join(t.TempDir(), "assistant.db")))
+	require.NoError(t, err, "NewSQLiteIndex() error should be nil")
+	smith := entities.Entity{Name: "Dr. Smith", Kind: entities.KindDoctor}
+	clinic := entities.Entity{Name: "City Clinic", Kind: entities.KindClinic}
+	require.NoError(t, index.Set(ctx, "visit-1", []entities.Entity{smith, clinic}), "Set() error should be nil")
+	require.NoError(t, index.Set(ctx, "visit-2", []entities.Entity{{Name: "dr. smith", Kind: entities.KindDoctor}}), "Set() error should be nil")
+
+	// Act
+	doctors, err := index.Entities(ctx, entities.KindDoctor)
+	require.NoError(t, err, "Entities() error should be nil")
+	ids, err := index.RecordIDs(ctx, "DR. SMITH")
+	require.NoError(t, err, "RecordIDs() error should be nil")
+
+	// Assert
+	assert.Equal(t, []entities.Summary{{Entity: smith, Records: 2}}, doctors, "Entities() should merge names differing only in case")
+	assert.ElementsMatch(t, []string{"visit-1", "visit-2"}, ids, "RecordIDs() should match names case-insensitively")
+}
+
+func TestSQLiteIndex_SetReplacesAndRemove(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	index, err := entities.NewSQLiteIndex(openDB(t, filepath.Join(t.TempDir(), "assistant.db")))
+	require.NoError(t, err, "NewSQLiteIndex() error should be nil")
+	require.NoError(t, index.Set(ctx, "ocr-1", []entities.Entity{{Name: "Old Vendor", Kind: entities.KindVendor}}), "Set() error should be nil")
+	require.NoError(t, index.Set(ctx, "ocr-2", []entities.Entity{{Name: "Acme Insurance", Kind: entities.KindInsurer}}), "Set() error should be nil")
+
+	// Act
+	require.NoError(t, index.Set(ctx, "ocr-1", []entities.Entity{{Name: "New Vendor", Kind: entities.KindVendor}}), "Set() error should be nil")
+	require.NoError(t, index.Remove(ctx, "ocr-2"), "Remove() error should be nil")
+	all, err := index.Entities(ctx, "")
+	require.NoError(t, err, "Entities() error should be nil")
+
+	// Assert
+	require.Len(t, all, 1, "Entities() should only list entities still referenced by records")
+	assert.Equal(t, "New Vendor", all[0].Name, "Set() should replace the entities of a record")
+}
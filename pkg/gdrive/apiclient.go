@@ -0,0 +1,166 @@
+package gdrive
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// DefaultAPIURL is the base URL of the Google Drive API
+const DefaultAPIURL = "https://www.googleapis.com/drive/v3"
+
+// fileFields are the fields of the files the API returns
+const fileFields = "id,name,mimeType,parents,trashed,version"
+
+// APIClient calls the Google Drive API over HTTP
+type APIClient struct {
+	client *http.Client
+	tokens TokenSource
+	url    string
+}
+
+// NewAPIClient creates a client calling the Drive API at url, or at
+// DefaultAPIURL when empty, authorized with the token source's access tokens
+func NewAPIClient(client *http.Client, tokens TokenSource, url string) Client {
+	if url == "" {
+		url = DefaultAPIURL
+	}
+	return &APIClient{
+		client: client,
+		tokens: tokens,
+		url:    strings.TrimSuffix(url, "/"),
+	}
+}
+
+// apiError is the body of a failed Drive API call
+type apiError struct {
+	Error struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// ListFiles implements Client
+func (c *APIClient) ListFiles(ctx context.Context, query string) ([]File, error) {
+	var files []File
+	pageToken := ""
+	for {
+		var page struct {
+			Files         []File `json:"files"`
+			NextPageToken string `json:"nextPageToken"`
+		}
+		params := url.Values{
+			"q":         {query},
+			"fields":    {"nextPageToken,files(" + fileFields + ")"},
+			"pageSize":  {"1000"},
+			"pageToken": {pageToken},
+		}
+		if err := c.getJSON(ctx, "/files", params, &page); err != nil {
+			return nil, err
+		}
+		files = append(files, page.Files...)
+		if page.NextPageToken == "" {
+			return files, nil
+		}
+		pageToken = page.NextPageToken
+	}
+}
+
+// Export implements Client
+func (c *APIClient) Export(ctx context.Context, id, mediaType string) (io.ReadCloser, error) {
+	return c.get(ctx, "/files/"+url.PathEscape(id)+"/export", url.Values{"mimeType": {mediaType}})
+}
+
+// Download implements Client
+func (c *APIClient) Download(ctx context.Context, id string) (io.ReadCloser, error) {
+	return c.get(ctx, "/files/"+url.PathEscape(id), url.Values{"alt": {"media"}})
+}
+
+// StartPageToken implements Client
+func (c *APIClient) StartPageToken(ctx context.Context) (string, error) {
+	var result struct {
+		StartPageToken string `json:"startPageToken"`
+	}
+	if err := c.getJSON(ctx, "/changes/startPageToken", nil, &result); err != nil {
+		return "", err
+	}
+	return result.StartPageToken, nil
+}
+
+// Changes implements Client. Pages are followed until the API returns the
+// page token for later changes.
+func (c *APIClient) Changes(ctx context.Context, pageToken string) ([]Change, string, error) {
+	var changes []Change
+	for {
+		var page struct {
+			Changes           []Change `json:"changes"`
+			NextPageToken     string   `json:"nextPageToken"`
+			NewStartPageToken string   `json:"newStartPageToken"`
+		}
+		params := url.Values{
+			"pageToken": {pageToken},
+			"fields":    {"nextPageToken,newStartPageToken,changes(fileId,removed,file(" + fileFields + "))"},
+			"pageSize":  {"1000"},
+		}
+		if err := c.getJSON(ctx, "/changes", params, &page); err != nil {
+			return nil, "", err
+		}
+		changes = append(changes, page.Changes...)
+		if page.NewStartPageToken != "" {
+			return changes, page.NewStartPageToken, nil
+		}
+		if page.NextPageToken == "" {
+			return nil, "", fmt.Errorf("google drive changes returned no page token")
+		}
+		pageToken = page.NextPageToken
+	}
+}
+
+// getJSON calls the API, decoding the response into result
+func (c *APIClient) getJSON(ctx context.Context, path string, params url.Values, result any) error {
+	body, err := c.get(ctx, path, params)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = body.Close() }()
+	if err := json.NewDecoder(body).Decode(result); err != nil {
+		return fmt.Errorf("failed to decode google drive %s response: %w", path, err)
+	}
+	return nil
+}
+
+// get calls the API, returning the body of a successful response
+func (c *APIClient) get(ctx context.Context, path string, params url.Values) (io.ReadCloser, error) {
+	token, err := c.tokens.AccessToken(ctx)
+	if err != nil {
+		return nil, err
+	}
+	endpoint := c.url + path
+	if len(params) > 0 {
+		endpoint += "?" + params.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create google drive request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call google drive %s: %w", path, err)
+	}
+	if resp.StatusCode == http.StatusOK {
+		return resp.Body, nil
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	var failure apiError
+	if err := json.NewDecoder(resp.Body).Decode(&failure); err != nil || failure.Error.Message == "" {
+		return nil, fmt.Errorf("google drive %s failed with status %d", path, resp.StatusCode)
+	}
+	return nil, fmt.Errorf("google drive %s failed with status %d: %s", path, resp.StatusCode, failure.Error.Message)
+}
@@ -0,0 +1,106 @@
+package gdrive_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kazemisoroush/assistant/pkg/gdrive"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// staticTokens is a token source returning a fixed access token
+type staticTokens string
+
+// AccessToken implements gdrive.TokenSource
+func (s staticTokens) AccessToken(context.Context) (string, error) {
+	return string(s), nil
+}
+
+func TestAPIClient_ListFiles(t *testing.T) {
+	// Arrange
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/files", r.URL.Path, "ListFiles() should list files")
+		assert.Equal(t, "Bearer TOKEN", r.Header.Get("Authorization"), "ListFiles() should authorize the call")
+		assert.Equal(t, "'root' in parents", r.URL.Query().Get("q"), "ListFiles() should send the query")
+		if r.URL.Query().Get("pageToken") == "" {
+			_, _ = io.WriteString(w, `{"files":[{"id":"a","name":"a.pdf"}],"nextPageToken":"p2"}`)
+			return
+		}
+		_, _ = io.WriteString(w, `{"files":[{"id":"b","name":"b.pdf","parents":["root"]}]}`)
+	}))
+	defer server.Close()
+	client := gdrive.NewAPIClient(server.Client(), staticTokens("TOKEN"), server.URL)
+
+	// Act
+	files, err := client.ListFiles(context.Background(), "'root' in parents")
+
+	// Assert
+	require.NoError(t, err, "ListFiles() should succeed")
+	require.Len(t, files, 2, "ListFiles() should follow every page")
+	assert.Equal(t, []string{"root"}, files[1].Parents, "ListFiles() should decode the parents")
+}
+
+func TestAPIClient_Changes(t *testing.T) {
+	// Arrange
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("pageToken") == "10" {
+			_, _ = io.WriteString(w, `{"changes":[{"fileId":"a","removed":true}],"nextPageToken":"11"}`)
+			return
+		}
+		_, _ = io.WriteString(w, `{"changes":[{"fileId":"b","file":{"id":"b","name":"b.pdf"}}],"newStartPageToken":"12"}`)
+	}))
+	defer server.Close()
+	client := gdrive.NewAPIClient(server.Client(), staticTokens("TOKEN"), server.URL)
+
+	// Act
+	changes, next, err := client.Changes(context.Background(), "10")
+
+	// Assert
+	require.NoError(t, err, "Changes() should succeed")
+	require.Len(t, changes, 2, "Changes() should follow every page")
+	assert.True(t, changes[0].Removed, "Changes() should decode removed files")
+	assert.Equal(t, "b.pdf", changes[1].File.Name, "Changes() should decode the changed file")
+	assert.Equal(t, "12", next, "Changes() should return the page token for later changes")
+}
+
+func TestAPIClient_Download(t *testing.T) {
+	// Arrange
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/files/a", r.URL.Path, "Download() should request the file")
+		assert.Equal(t, "media", r.URL.Query().Get("alt"), "Download() should request the content")
+		_, _ = io.WriteString(w, "content")
+	}))
+	defer server.Close()
+	client := gdrive.NewAPIClient(server.Client(), staticTokens("TOKEN"), server.URL)
+
+	// Act
+	content, err := client.Download(context.Background(), "a")
+
+	// Assert
+	require.NoError(t, err, "Download() should succeed")
+	defer func() { _ = content.Close() }()
+	data, err := io.ReadAll(content)
+	require.NoError(t, err, "ReadAll() error should be nil")
+	assert.Equal(t, "content", string(data), "Download() should return the content")
+}
+
+func TestAPIClient_Failed(t *testing.T) {
+	// Arrange
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = io.WriteString(w, `{"error":{"code":403,"message":"Export only supports Docs Editors files."}}`)
+	}))
+	defer server.Close()
+	client := gdrive.NewAPIClient(server.Client(), staticTokens("TOKEN"), server.URL)
+
+	// Act
+	_, err := client.Export(context.Background(), "a", "text/plain")
+
+	// Assert
+	require.Error(t, err, "Export() should fail when Drive rejects the call")
+	assert.Contains(t, err.Error(), "Export only supports Docs Editors files", "Export() should report Drive's message")
+}
@@ -0,0 +1,303 @@
+package gdrive
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Google's OAuth2 endpoints and the Drive scope files are read with
+const (
+	DefaultAuthURL     = "https://accounts.google.com/o/oauth2/v2/auth"
+	DefaultTokenURL    = "https://oauth2.googleapis.com/token"
+	ScopeDriveReadonly = "https://www.googleapis.com/auth/drive.readonly"
+)
+
+// expiryMargin is how long before it expires an access token is refreshed
+const expiryMargin = time.Minute
+
+// ErrNotAuthorized is returned while no token was saved by the sign-in
+var ErrNotAuthorized = errors.New("google drive is not authorized")
+
+// OAuthConfig represents the OAuth2 client the sign-in is made with
+type OAuthConfig struct {
+	ClientID     string
+	ClientSecret string
+	Scope        string
+	AuthURL      string // DefaultAuthURL when empty
+	TokenURL     string // DefaultTokenURL when empty
+	TokenPath    string // File the token is saved to
+}
+
+// Token represents the tokens saved by the sign-in
+type Token struct {
+	AccessToken  string    `json:"access_token"`
+	RefreshToken string    `json:"refresh_token"`
+	Expiry       time.Time `json:"expiry"`
+}
+
+// tokenResponse is the body of a token endpoint response
+type tokenResponse struct {
+	AccessToken      string `json:"access_token"`
+	RefreshToken     string `json:"refresh_token"`
+	ExpiresIn        int    `json:"expires_in"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+// Authorizer signs in with the OAuth2 loopback flow of installed apps and
+// keeps the token it obtains, refreshing the access token once it expires
+type Authorizer struct {
+	client *http.Client
+	cfg    OAuthConfig
+	mu     sync.Mutex
+	token  *Token // Loaded from TokenPath on first use
+}
+
+// NewAuthorizer creates an authorizer calling the OAuth2 endpoints with the given HTTP client
+func NewAuthorizer(client *http.Client, cfg OAuthConfig) *Authorizer {
+	if cfg.AuthURL == "" {
+		cfg.AuthURL = DefaultAuthURL
+	}
+	if cfg.TokenURL == "" {
+		cfg.TokenURL = DefaultTokenURL
+	}
+	return &Authorizer{
+		client: client,
+		cfg:    cfg,
+	}
+}
+
+// Authorize signs in with the loopback flow. It serves the redirect on the
+// listener and prompts the user to open the consent page, then exchanges the
+// code the browser is redirected back with and saves the token. It fails once
+// the user denied the sign-in.
+func (a *Authorizer) Authorize(ctx context.Context, listener net.Listener, prompt func(authURL string)) error {
+	verifier, err := randomString()
+	if err != nil {
+		return err
+	}
+	state, err := randomString()
+	if err != nil {
+		return err
+	}
+	redirectURI := "http://" + listener.Addr().String()
+
+	callbacks := make(chan url.Values, 1)
+	server := &http.Server{
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			query := r.URL.Query()
+			// Requests without the state, such as the browser's favicon request, are not the redirect
+			if query.Get("state") != state {
+				http.NotFound(w, r)
+				return
+			}
+			select {
+			case callbacks <- query:
+			default:
+			}
+			if query.Get("error") != "" {
+				_, _ = io.WriteString(w, "Sign-in failed; see the terminal for details.\n")
+				return
+			}
+			_, _ = io.WriteString(w, "Signed in; you can close this window.\n")
+		}),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go func() { _ = server.Serve(listener) }()
+	defer func() { _ = server.Close() }()
+
+	challenge := sha256.Sum256([]byte(verifier))
+	prompt(a.cfg.AuthURL + "?" + url.Values{
+		"client_id":             {a.cfg.ClientID},
+		"redirect_uri":          {redirectURI},
+		"response_type":         {"code"},
+		"scope":                 {a.cfg.Scope},
+		"state":                 {state},
+		"code_challenge":        {base64.RawURLEncoding.EncodeToString(challenge[:])},
+		"code_challenge_method": {"S256"},
+		// Offline access with consent, so a refresh token is issued on every sign-in
+		"access_type": {"offline"},
+		"prompt":      {"consent"},
+	}.Encode())
+
+	var query url.Values
+	select {
+	case <-ctx.Done():
+		return fmt.Errorf("sign-in was not finished: %w", ctx.Err())
+	case query = <-callbacks:
+	}
+	if query.Get("error") != "" {
+		return fmt.Errorf("sign-in failed: %s", query.Get("error"))
+	}
+
+	resp, err := a.exchange(ctx, url.Values{
+		"client_id":     {a.cfg.ClientID},
+		"client_secret": {a.cfg.ClientSecret},
+		"code":          {query.Get("code")},
+		"code_verifier": {verifier},
+		"redirect_uri":  {redirectURI},
+		"grant_type":    {"authorization_code"},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to exchange sign-in code: %w", err)
+	}
+	if resp.Error != "" {
+		return fmt.Errorf("sign-in failed: %s", describe(resp))
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.save(newToken(resp, ""))
+}
+
+// AccessToken implements TokenSource, refreshing the saved token once it expires
+func (a *Authorizer) AccessToken(ctx context.Context) (string, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.token == nil {
+		token, err := a.load()
+		if err != nil {
+			return "", err
+		}
+		a.token = token
+	}
+	if time.Now().Add(expiryMargin).Before(a.token.Expiry) {
+		return a.token.AccessToken, nil
+	}
+
+	resp, err := a.exchange(ctx, url.Values{
+		"client_id":     {a.cfg.ClientID},
+		"client_secret": {a.cfg.ClientSecret},
+		"refresh_token": {a.token.RefreshToken},
+		"grant_type":    {"refresh_token"},
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to refresh google drive token: %w", err)
+	}
+	if resp.Error != "" {
+		return "", fmt.Errorf("failed to refresh google drive token: %s", describe(resp))
+	}
+	// Refresh responses keep the refresh token issued by the sign-in
+	token := newToken(resp, a.token.RefreshToken)
+	if err := a.save(token); err != nil {
+		return "", err
+	}
+	return token.AccessToken, nil
+}
+
+// exchange posts a form to the token endpoint. Token errors, such as an
+// invalid code, are returned in the response rather than as an error.
+func (a *Authorizer) exchange(ctx context.Context, form url.Values) (tokenResponse, error) {
+	var resp tokenResponse
+	err := a.post(ctx, a.cfg.TokenURL, form, &resp)
+	if err != nil && resp.Error == "" {
+		return tokenResponse{}, err
+	}
+	return resp, nil
+}
+
+// post posts a form, decoding the JSON response into result. The body of a
+// failed response is decoded as well, so the caller can read its error.
+func (a *Authorizer) post(ctx context.Context, endpoint string, form url.Values, result any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return fmt.Errorf("failed to create oauth request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to call oauth endpoint: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	decodeErr := json.NewDecoder(resp.Body).Decode(result)
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("oauth endpoint failed with status %d", resp.StatusCode)
+	}
+	if decodeErr != nil {
+		return fmt.Errorf("failed to decode oauth response: %w", decodeErr)
+	}
+	return nil
+}
+
+// load reads the token saved by the sign-in
+func (a *Authorizer) load() (*Token, error) {
+	data, err := os.ReadFile(a.cfg.TokenPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNotAuthorized
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read google drive token: %w", err)
+	}
+	var token Token
+	if err := json.Unmarshal(data, &token); err != nil {
+		return nil, fmt.Errorf("failed to decode google drive token: %w", err)
+	}
+	return &token, nil
+}
+
+// save writes the token readable by the owner only, replacing the saved one
+// atomically. The caller holds the lock.
+func (a *Authorizer) save(token *Token) error {
+	data, err := json.Marshal(token)
+	if err != nil {
+		return fmt.Errorf("failed to encode google drive token: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(a.cfg.TokenPath), 0700); err != nil {
+		return fmt.Errorf("failed to create google drive token directory: %w", err)
+	}
+	tmp := a.cfg.TokenPath + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return fmt.Errorf("failed to write google drive token: %w", err)
+	}
+	if err := os.Rename(tmp, a.cfg.TokenPath); err != nil {
+		return fmt.Errorf("failed to save google drive token: %w", err)
+	}
+	a.token = token
+	return nil
+}
+
+// newToken returns the token of a token endpoint response, keeping
+// refreshToken when the response holds none
+func newToken(resp tokenResponse, refreshToken string) *Token {
+	if resp.RefreshToken != "" {
+		refreshToken = resp.RefreshToken
+	}
+	return &Token{
+		AccessToken:  resp.AccessToken,
+		RefreshToken: refreshToken,
+		Expiry:       time.Now().Add(time.Duration(resp.ExpiresIn) * time.Second),
+	}
+}
+
+// describe returns the error of a token endpoint response
+func describe(resp tokenResponse) string {
+	if resp.ErrorDescription == "" {
+		return resp.Error
+	}
+	return resp.Error + ": " + resp.ErrorDescription
+}
+
+// randomString returns a random URL-safe string, used as the PKCE code
+// verifier and the state of a sign-in
+func randomString() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate random string: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
@@ -0,0 +1,156 @@
+package gdrive_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kazemisoroush/assistant/pkg/gdrive"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuthorizer_Authorize(t *testing.T) {
+	// Arrange
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		assert.Equal(t, "authorization_code", r.Form.Get("grant_type"), "Authorize() should exchange the code")
+		assert.Equal(t, "code-1", r.Form.Get("code"), "Authorize() should exchange the code the browser was redirected with")
+		assert.NotEmpty(t, r.Form.Get("code_verifier"), "Authorize() should send the PKCE code verifier")
+		assert.True(t, strings.HasPrefix(r.Form.Get("redirect_uri"), "http://127.0.0.1:"), "Authorize() should send the loopback redirect")
+		_, _ = io.WriteString(w, `{"access_token":"access","refresh_token":"refresh","expires_in":3600}`)
+	}))
+	defer server.Close()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	path := filepath.Join(t.TempDir(), "token.json")
+	authorizer := gdrive.NewAuthorizer(server.Client(), gdrive.OAuthConfig{
+		ClientID:     "client",
+		ClientSecret: "secret",
+		Scope:        gdrive.ScopeDriveReadonly,
+		AuthURL:      "https://accounts.example.com/auth",
+		TokenURL:     server.URL,
+		TokenPath:    path,
+	})
+	var consent *url.URL
+	redirected := make(chan int, 1)
+
+	// Act
+	authErr := authorizer.Authorize(context.Background(), listener, func(authURL string) {
+		consent, err = url.Parse(authURL)
+		require.NoError(t, err)
+		query := consent.Query()
+		// The browser is redirected back once the user consented
+		go func() {
+			resp, err := http.Get(query.Get("redirect_uri") + "/?code=code-1&state=" + url.QueryEscape(query.Get("state")))
+			require.NoError(t, err)
+			_ = resp.Body.Close()
+			redirected <- resp.StatusCode
+		}()
+	})
+
+	// Assert
+	require.NoError(t, authErr, "Authorize() should succeed")
+	assert.Equal(t, http.StatusOK, <-redirected, "Authorize() should answer the redirect")
+	query := consent.Query()
+	assert.Equal(t, "accounts.example.com", consent.Host, "Authorize() should prompt with the consent page")
+	assert.Equal(t, "client", query.Get("client_id"), "Authorize() should send the client ID")
+	assert.Equal(t, gdrive.ScopeDriveReadonly, query.Get("scope"), "Authorize() should send the scope")
+	assert.Equal(t, "S256", query.Get("code_challenge_method"), "Authorize() should sign in with PKCE")
+	assert.Equal(t, "offline", query.Get("access_type"), "Authorize() should ask for a refresh token")
+	info, err := os.Stat(path)
+	require.NoError(t, err, "Authorize() should save the token")
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm(), "Authorize() should save the token readable by the owner only")
+	token, err := authorizer.AccessToken(context.Background())
+	require.NoError(t, err, "AccessToken() should succeed")
+	assert.Equal(t, "access", token, "AccessToken() should return the saved token")
+}
+
+func TestAuthorizer_Authorize_Denied(t *testing.T) {
+	// Arrange
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	authorizer := gdrive.NewAuthorizer(http.DefaultClient, gdrive.OAuthConfig{TokenPath: filepath.Join(t.TempDir(), "token.json")})
+
+	// Act
+	err = authorizer.Authorize(context.Background(), listener, func(authURL string) {
+		consent, err := url.Parse(authURL)
+		require.NoError(t, err)
+		query := consent.Query()
+		go func() {
+			// Requests without the state are not the redirect
+			resp, err := http.Get(query.Get("redirect_uri") + "/favicon.ico")
+			require.NoError(t, err)
+			_ = resp.Body.Close()
+			resp, err = http.Get(query.Get("redirect_uri") + "/?error=access_denied&state=" + url.QueryEscape(query.Get("state")))
+			require.NoError(t, err)
+			_ = resp.Body.Close()
+		}()
+	})
+
+	// Assert
+	require.Error(t, err, "Authorize() should fail once the user denied the sign-in")
+	assert.Contains(t, err.Error(), "access_denied", "Authorize() should report the error of the redirect")
+}
+
+func TestAuthorizer_Authorize_Canceled(t *testing.T) {
+	// Arrange
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	authorizer := gdrive.NewAuthorizer(http.DefaultClient, gdrive.OAuthConfig{TokenPath: filepath.Join(t.TempDir(), "token.json")})
+	ctx, cancel := context.WithCancel(context.Background())
+
+	// Act
+	err = authorizer.Authorize(ctx, listener, func(string) { cancel() })
+
+	// Assert
+	assert.ErrorIs(t, err, context.Canceled, "Authorize() should stop waiting once canceled")
+}
+
+func TestAuthorizer_AccessToken_Refresh(t *testing.T) {
+	// Arrange
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		assert.Equal(t, "refresh_token", r.Form.Get("grant_type"), "AccessToken() should refresh the token")
+		assert.Equal(t, "refresh", r.Form.Get("refresh_token"), "AccessToken() should send the refresh token")
+		_, _ = io.WriteString(w, `{"access_token":"fresh","expires_in":3600}`)
+	}))
+	defer server.Close()
+	path := filepath.Join(t.TempDir(), "token.json")
+	data, err := json.Marshal(gdrive.Token{AccessToken: "stale", RefreshToken: "refresh", Expiry: time.Now().Add(-time.Hour)})
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(path, data, 0600))
+	authorizer := gdrive.NewAuthorizer(server.Client(), gdrive.OAuthConfig{TokenURL: server.URL, TokenPath: path})
+
+	// Act
+	token, err := authorizer.AccessToken(context.Background())
+
+	// Assert
+	require.NoError(t, err, "AccessToken() should succeed")
+	assert.Equal(t, "fresh", token, "AccessToken() should return the refreshed token")
+	data, err = os.ReadFile(path)
+	require.NoError(t, err)
+	var saved gdrive.Token
+	require.NoError(t, json.Unmarshal(data, &saved))
+	assert.Equal(t, "refresh", saved.RefreshToken, "AccessToken() should keep the refresh token")
+}
+
+func TestAuthorizer_AccessToken_NotAuthorized(t *testing.T) {
+	// Arrange
+	authorizer := gdrive.NewAuthorizer(http.DefaultClient, gdrive.OAuthConfig{TokenPath: filepath.Join(t.TempDir(), "token.json")})
+
+	// Act
+	_, err := authorizer.AccessToken(context.Background())
+
+	// Assert
+	assert.ErrorIs(t, err, gdrive.ErrNotAuthorized, "AccessToken() should fail before the sign-in")
+}
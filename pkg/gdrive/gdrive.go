@@ -0,0 +1,59 @@
+// Package gdrive calls the Google Drive API, authorized with the OAuth2
+// loopback flow of installed apps.
+package gdrive
+
+import (
+	"context"
+	"io"
+)
+
+// Media types of the folders and Google Docs editor files
+const (
+	MimeTypeFolder       = "application/vnd.google-apps.folder"
+	MimeTypeDocument     = "application/vnd.google-apps.document"
+	MimeTypeSpreadsheet  = "application/vnd.google-apps.spreadsheet"
+	MimeTypePresentation = "application/vnd.google-apps.presentation"
+)
+
+// Client calls the Google Drive API
+//
+//go:generate mockgen -destination=./mocks/mock_client.go -mock_names=Client=MockClient -package=mocks . Client
+type Client interface {
+	// ListFiles returns every file matching a Drive search query
+	ListFiles(ctx context.Context, query string) ([]File, error)
+
+	// Export returns a Google Docs editor file converted to the media type
+	Export(ctx context.Context, id, mediaType string) (io.ReadCloser, error)
+
+	// Download returns the content of a file
+	Download(ctx context.Context, id string) (io.ReadCloser, error)
+
+	// StartPageToken returns the page token listing the changes made from now on
+	StartPageToken(ctx context.Context) (string, error)
+
+	// Changes returns every change made since the page token, with the page
+	// token listing the changes made after them
+	Changes(ctx context.Context, pageToken string) ([]Change, string, error)
+}
+
+// TokenSource returns the access token Drive API calls are authorized with
+type TokenSource interface {
+	AccessToken(ctx context.Context) (string, error)
+}
+
+// File represents a file or folder in Google Drive
+type File struct {
+	ID       string   `json:"id"`
+	Name     string   `json:"name"`
+	MimeType string   `json:"mimeType"`
+	Parents  []string `json:"parents"`
+	Trashed  bool     `json:"trashed"`
+	Version  string   `json:"version"` // Increases whenever the file changes
+}
+
+// Change represents a file added, modified or removed since a page token
+type Change struct {
+	FileID  string `json:"fileId"`
+	Removed bool   `json:"removed"`
+	File    *File  `json:"file"` // Nil for removed files
+}
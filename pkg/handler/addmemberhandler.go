@@ -0,0 +1,44 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kazemisoroush/assistant/pkg/household"
+)
+
+// AddMemberHandler adds or replaces a household member.
+type AddMemberHandler struct {
+	store household.Store
+}
+
+// NewAddMemberHandler creates a new add member handler.
+func NewAddMemberHandler(store household.Store) Handler {
+	return &AddMemberHandler{
+		store: store,
+	}
+}
+
+// Handle implements Handler. Request data is the household.Member to save.
+func (h *AddMemberHandler) Handle(ctx context.Context, request Request) (Response, error) {
+	member, ok := request.Data.(household.Member)
+	if !ok {
+		return Response{
+			Success: false,
+			Errors:  []string{"household member is required"},
+		}, fmt.Errorf("household member is required")
+	}
+
+	added, err := household.Add(ctx, h.store, member)
+	if err != nil {
+		return Response{
+			Success: false,
+			Errors:  []string{fmt.Sprintf("failed to add household member: %v", err)},
+		}, fmt.Errorf("failed to add household member: %w", err)
+	}
+
+	return Response{
+		Success: true,
+		Data:    added,
+	}, nil
+}
@@ -0,0 +1,50 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/kazemisoroush/assistant/pkg/ai"
+)
+
+const (
+	// StatsCommandType is the command type for usage statistics
+	StatsCommandType = "stats"
+
+	// DefaultStatsDays is the default number of days covered by usage statistics
+	DefaultStatsDays = 30
+)
+
+// AIUsageHandler reports LLM and embedding usage and estimated cost.
+type AIUsageHandler struct {
+	store ai.UsageStore
+}
+
+// NewAIUsageHandler creates a new AI usage handler.
+func NewAIUsageHandler(store ai.UsageStore) Handler {
+	return &AIUsageHandler{
+		store: store,
+	}
+}
+
+// Handle implements Handler. Request data is the number of days to report on.
+func (h *AIUsageHandler) Handle(ctx context.Context, request Request) (Response, error) {
+	days, ok := request.Data.(int)
+	if !ok || days <= 0 {
+		days = DefaultStatsDays
+	}
+
+	report, err := h.store.Report(ctx, time.Now().AddDate(0, 0, -days))
+	if err != nil {
+		return Response{
+			Success: false,
+			Errors:  []string{fmt.Sprintf("failed to report AI usage: %v", err)},
+		}, fmt.Errorf("failed to report AI usage: %w", err)
+	}
+
+	return Response{
+		Success: true,
+		Data:    report,
+	}, nil
+}
@@ -0,0 +1,44 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kazemisoroush/assistant/pkg/answerer"
+)
+
+// AnswerHandler answers questions from the records search finds.
+type AnswerHandler struct {
+	answerer answerer.Answerer
+}
+
+// NewAnswerHandler creates a new answer handler.
+func NewAnswerHandler(answerer answerer.Answerer) Handler {
+	return &AnswerHandler{
+		answerer: answerer,
+	}
+}
+
+// Handle implements Handler for ask operations answered from the records.
+func (h *AnswerHandler) Handle(ctx context.Context, request Request) (Response, error) {
+	question, ok := request.Data.(string)
+	if !ok || question == "" {
+		return Response{
+			Success: false,
+			Errors:  []string{"question is required"},
+		}, fmt.Errorf("question is required")
+	}
+
+	answer, err := h.answerer.Answer(ctx, question)
+	if err != nil {
+		return Response{
+			Success: false,
+			Errors:  []string{fmt.Sprintf("ask failed: %v", err)},
+		}, fmt.Errorf("ask failed: %w", err)
+	}
+
+	return Response{
+		Success: true,
+		Data:    answer,
+	}, nil
+}
@@ -0,0 +1,49 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kazemisoroush/assistant/pkg/agent"
+)
+
+const (
+	// AskCommandType is the command type for agent-answered questions
+	AskCommandType = "ask"
+)
+
+// AskHandler answers multi-step questions with an agent.
+type AskHandler struct {
+	agent agent.Agent
+}
+
+// NewAskHandler creates a new ask handler.
+func NewAskHandler(agent agent.Agent) Handler {
+	return &AskHandler{
+		agent: agent,
+	}
+}
+
+// Handle implements Handler for ask operations.
+func (h *AskHandler) Handle(ctx context.Context, request Request) (Response, error) {
+	question, ok := request.Data.(string)
+	if !ok || question == "" {
+		return Response{
+			Success: false,
+			Errors:  []string{"question is required"},
+		}, fmt.Errorf("question is required")
+	}
+
+	answer, err := h.agent.Ask(ctx, question)
+	if err != nil {
+		return Response{
+			Success: false,
+			Errors:  []string{fmt.Sprintf("ask failed: %v", err)},
+		}, fmt.Errorf("ask failed: %w", err)
+	}
+
+	return Response{
+		Success: true,
+		Data:    answer,
+	}, nil
+}
@@ -0,0 +1,47 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kazemisoroush/assistant/pkg/household"
+	"github.com/kazemisoroush/assistant/pkg/records/storage"
+)
+
+// AssignPersonHandler attributes a record to a household member.
+type AssignPersonHandler struct {
+	store   household.Store
+	storage storage.Storage
+}
+
+// NewAssignPersonHandler creates a new assign person handler.
+func NewAssignPersonHandler(store household.Store, storage storage.Storage) Handler {
+	return &AssignPersonHandler{
+		store:   store,
+		storage: storage,
+	}
+}
+
+// Handle implements Handler. Request data is the household.Assignment to make.
+func (h *AssignPersonHandler) Handle(ctx context.Context, request Request) (Response, error) {
+	assignment, ok := request.Data.(household.Assignment)
+	if !ok || assignment.RecordID == "" || assignment.Person == "" {
+		return Response{
+			Success: false,
+			Errors:  []string{"record and household member are required"},
+		}, fmt.Errorf("record and household member are required")
+	}
+
+	rec, err := household.Assign(ctx, h.store, h.storage, assignment.RecordID, assignment.Person)
+	if err != nil {
+		return Response{
+			Success: false,
+			Errors:  []string{fmt.Sprintf("failed to assign record: %v", err)},
+		}, fmt.Errorf("failed to assign record: %w", err)
+	}
+
+	return Response{
+		Success: true,
+		Data:    rec,
+	}, nil
+}
@@ -0,0 +1,57 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/kazemisoroush/assistant/pkg/budgets"
+)
+
+const (
+	// BudgetsCommandType is the command type for monthly category budgets
+	BudgetsCommandType = "budgets"
+
+	// BudgetsSetSubcommand is the budgets subcommand adding or replacing a budget
+	BudgetsSetSubcommand = "set"
+
+	// BudgetsRemoveSubcommand is the budgets subcommand removing a budget
+	BudgetsRemoveSubcommand = "remove"
+)
+
+// BudgetsHandler reports spend against budgets or sends threshold alerts.
+type BudgetsHandler struct {
+	checker budgets.Checker
+}
+
+// NewBudgetsHandler creates a new budgets handler.
+func NewBudgetsHandler(checker budgets.Checker) Handler {
+	return &BudgetsHandler{
+		checker: checker,
+	}
+}
+
+// Handle implements Handler. Request data is true to send the alerts that
+// became due, otherwise the spend against every budget is listed without sending.
+func (h *BudgetsHandler) Handle(ctx context.Context, request Request) (Response, error) {
+	check, _ := request.Data.(bool)
+
+	var statuses []budgets.Status
+	var err error
+	if check {
+		statuses, err = h.checker.Run(ctx, time.Now())
+	} else {
+		statuses, err = h.checker.Status(ctx, time.Now())
+	}
+	if err != nil {
+		return Response{
+			Success: false,
+			Errors:  []string{fmt.Sprintf("failed to check budgets: %v", err)},
+		}, fmt.Errorf("failed to check budgets: %w", err)
+	}
+
+	return Response{
+		Success: true,
+		Data:    statuses,
+	}, nil
+}
@@ -0,0 +1,43 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kazemisoroush/assistant/pkg/claims"
+)
+
+const (
+	// ClaimsCommandType is the command type for insurance claim tracking
+	ClaimsCommandType = "claims"
+)
+
+// ClaimsHandler reports medical expenses with the insurance claims they were matched to.
+type ClaimsHandler struct {
+	matcher claims.Matcher
+}
+
+// NewClaimsHandler creates a new claims handler.
+func NewClaimsHandler(matcher claims.Matcher) Handler {
+	return &ClaimsHandler{
+		matcher: matcher,
+	}
+}
+
+// Handle implements Handler. Request data is an optional claims.Status to limit the expenses to.
+func (h *ClaimsHandler) Handle(ctx context.Context, request Request) (Response, error) {
+	status, _ := request.Data.(claims.Status)
+
+	report, err := h.matcher.Match(ctx, status)
+	if err != nil {
+		return Response{
+			Success: false,
+			Errors:  []string{fmt.Sprintf("failed to match claims: %v", err)},
+		}, fmt.Errorf("failed to match claims: %w", err)
+	}
+
+	return Response{
+		Success: true,
+		Data:    report,
+	}, nil
+}
@@ -0,0 +1,42 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/kazemisoroush/assistant/pkg/dashboard"
+)
+
+const (
+	// DashboardCommandType is the command type for the overview of upcoming obligations
+	DashboardCommandType = "dashboard"
+)
+
+// DashboardHandler reports expiring documents, upcoming obligations, the review queue, recent ingests and month-to-date spend.
+type DashboardHandler struct {
+	builder dashboard.Builder
+}
+
+// NewDashboardHandler creates a new dashboard handler.
+func NewDashboardHandler(builder dashboard.Builder) Handler {
+	return &DashboardHandler{
+		builder: builder,
+	}
+}
+
+// Handle implements Handler.
+func (h *DashboardHandler) Handle(ctx context.Context, _ Request) (Response, error) {
+	overview, err := h.builder.Build(ctx, time.Now())
+	if err != nil {
+		return Response{
+			Success: false,
+			Errors:  []string{fmt.Sprintf("failed to build dashboard: %v", err)},
+		}, fmt.Errorf("failed to build dashboard: %w", err)
+	}
+
+	return Response{
+		Success: true,
+		Data:    overview,
+	}, nil
+}
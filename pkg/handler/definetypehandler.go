@@ -0,0 +1,44 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kazemisoroush/assistant/pkg/records"
+	"github.com/kazemisoroush/assistant/pkg/records/typestore"
+)
+
+// DefineTypeHandler creates or replaces a user-defined record type.
+type DefineTypeHandler struct {
+	store typestore.Store
+}
+
+// NewDefineTypeHandler creates a new define type handler.
+func NewDefineTypeHandler(store typestore.Store) Handler {
+	return &DefineTypeHandler{
+		store: store,
+	}
+}
+
+// Handle implements Handler. Request data is the records.TypeDefinition to save.
+func (h *DefineTypeHandler) Handle(ctx context.Context, request Request) (Response, error) {
+	def, ok := request.Data.(records.TypeDefinition)
+	if !ok {
+		return Response{
+			Success: false,
+			Errors:  []string{"record type definition is required"},
+		}, fmt.Errorf("record type definition is required")
+	}
+
+	if err := typestore.Define(ctx, h.store, def); err != nil {
+		return Response{
+			Success: false,
+			Errors:  []string{fmt.Sprintf("failed to define record type: %v", err)},
+		}, fmt.Errorf("failed to define record type: %w", err)
+	}
+
+	return Response{
+		Success: true,
+		Data:    def,
+	}, nil
+}
@@ -0,0 +1,58 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kazemisoroush/assistant/pkg/records/ingestor"
+	"github.com/kazemisoroush/assistant/pkg/records/storage"
+)
+
+const (
+	// DeleteCommandType is the command type for deleting a record
+	DeleteCommandType = "delete"
+)
+
+// DeleteRecordHandler deletes a record and removes it from the search index.
+type DeleteRecordHandler struct {
+	storage  storage.Storage
+	ingestor ingestor.Ingestor
+}
+
+// NewDeleteRecordHandler creates a new delete record handler.
+func NewDeleteRecordHandler(storage storage.Storage, ingestor ingestor.Ingestor) Handler {
+	return &DeleteRecordHandler{
+		storage:  storage,
+		ingestor: ingestor,
+	}
+}
+
+// Handle implements Handler. Request data is the ID of the record.
+func (h *DeleteRecordHandler) Handle(ctx context.Context, request Request) (Response, error) {
+	id, ok := request.Data.(string)
+	if !ok || id == "" {
+		return Response{
+			Success: false,
+			Errors:  []string{"record ID is required"},
+		}, fmt.Errorf("record ID is required")
+	}
+
+	// Deleting a missing record is reported instead of silently succeeding
+	if _, err := h.storage.Get(ctx, id); err != nil {
+		return Response{
+			Success: false,
+			Errors:  []string{fmt.Sprintf("failed to get record: %v", err)},
+		}, fmt.Errorf("failed to get record: %w", err)
+	}
+	if err := h.ingestor.Delete(ctx, id); err != nil {
+		return Response{
+			Success: false,
+			Errors:  []string{fmt.Sprintf("failed to delete record: %v", err)},
+		}, fmt.Errorf("failed to delete record: %w", err)
+	}
+
+	return Response{
+		Success: true,
+		Data:    id,
+	}, nil
+}
@@ -0,0 +1,67 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/kazemisoroush/assistant/pkg/digest"
+	"github.com/kazemisoroush/assistant/pkg/notifications"
+)
+
+const (
+	// DigestCommandType is the command type for the digest of vault activity
+	DigestCommandType = "digest"
+)
+
+// DigestRequest represents the request data of the digest command
+type DigestRequest struct {
+	Period digest.Period
+	Send   bool // Send the digest through the notification channels
+}
+
+// DigestHandler composes the digest of a period and optionally sends it.
+type DigestHandler struct {
+	composer digest.Composer
+	notifier notifications.Notifier
+}
+
+// NewDigestHandler creates a new digest handler.
+func NewDigestHandler(composer digest.Composer, notifier notifications.Notifier) Handler {
+	return &DigestHandler{
+		composer: composer,
+		notifier: notifier,
+	}
+}
+
+// Handle implements Handler.
+func (h *DigestHandler) Handle(ctx context.Context, request Request) (Response, error) {
+	req, ok := request.Data.(DigestRequest)
+	if !ok {
+		return Response{
+			Success: false,
+			Errors:  []string{"digest request is required"},
+		}, fmt.Errorf("digest request is required")
+	}
+
+	composed, err := h.composer.Compose(ctx, req.Period, time.Now())
+	if err != nil {
+		return Response{
+			Success: false,
+			Errors:  []string{fmt.Sprintf("failed to compose digest: %v", err)},
+		}, fmt.Errorf("failed to compose digest: %w", err)
+	}
+	if req.Send {
+		if err := h.notifier.Notify(ctx, notifications.EventDigest, composed); err != nil {
+			return Response{
+				Success: false,
+				Errors:  []string{fmt.Sprintf("failed to send digest: %v", err)},
+			}, fmt.Errorf("failed to send digest: %w", err)
+		}
+	}
+
+	return Response{
+		Success: true,
+		Data:    composed,
+	}, nil
+}
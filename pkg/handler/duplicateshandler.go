@@ -0,0 +1,47 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kazemisoroush/assistant/pkg/duplicates"
+)
+
+const (
+	// DuplicatesCommandType is the command type for duplicate detection and merging
+	DuplicatesCommandType = "duplicates"
+
+	// DuplicatesMergeSubcommand is the duplicates subcommand merging one pair of records
+	DuplicatesMergeSubcommand = "merge"
+
+	// DuplicatesReviewSubcommand is the duplicates subcommand walking through every pair interactively
+	DuplicatesReviewSubcommand = "review"
+)
+
+// DuplicatesHandler lists records that look like copies of the same document.
+type DuplicatesHandler struct {
+	detector duplicates.Detector
+}
+
+// NewDuplicatesHandler creates a new duplicates handler.
+func NewDuplicatesHandler(detector duplicates.Detector) Handler {
+	return &DuplicatesHandler{
+		detector: detector,
+	}
+}
+
+// Handle implements Handler. Request data is unused.
+func (h *DuplicatesHandler) Handle(ctx context.Context, _ Request) (Response, error) {
+	candidates, err := h.detector.Find(ctx)
+	if err != nil {
+		return Response{
+			Success: false,
+			Errors:  []string{fmt.Sprintf("failed to find duplicates: %v", err)},
+		}, fmt.Errorf("failed to find duplicates: %w", err)
+	}
+
+	return Response{
+		Success: true,
+		Data:    candidates,
+	}, nil
+}
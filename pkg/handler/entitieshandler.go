@@ -0,0 +1,52 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kazemisoroush/assistant/pkg/entities"
+)
+
+const (
+	// EntitiesCommandType is the command type for browsing records by entity
+	EntitiesCommandType = "entities"
+)
+
+// EntitiesHandler lists indexed entities and the records naming them.
+type EntitiesHandler struct {
+	browser entities.Browser
+}
+
+// NewEntitiesHandler creates a new entities handler.
+func NewEntitiesHandler(browser entities.Browser) Handler {
+	return &EntitiesHandler{
+		browser: browser,
+	}
+}
+
+// Handle implements Handler. Request data is an optional entities.Entity: with a
+// name the records naming it are returned, otherwise the entities of its kind.
+func (h *EntitiesHandler) Handle(ctx context.Context, request Request) (Response, error) {
+	query, _ := request.Data.(entities.Entity)
+
+	var (
+		data any
+		err  error
+	)
+	if query.Name != "" {
+		data, err = h.browser.Records(ctx, query.Name)
+	} else {
+		data, err = h.browser.Entities(ctx, query.Kind)
+	}
+	if err != nil {
+		return Response{
+			Success: false,
+			Errors:  []string{fmt.Sprintf("failed to browse entities: %v", err)},
+		}, fmt.Errorf("failed to browse entities: %w", err)
+	}
+
+	return Response{
+		Success: true,
+		Data:    data,
+	}, nil
+}
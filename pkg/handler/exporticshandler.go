@@ -0,0 +1,44 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kazemisoroush/assistant/pkg/calendar"
+)
+
+const (
+	// ExportCommandType is the command type for exporting records to other applications
+	ExportCommandType = "export"
+
+	// ExportICSSubcommand is the export subcommand writing important dates as an iCalendar file
+	ExportICSSubcommand = "ics"
+)
+
+// ExportICSHandler collects document expirations, renewal reminders and health follow-ups as calendar events.
+type ExportICSHandler struct {
+	builder calendar.Builder
+}
+
+// NewExportICSHandler creates a new iCalendar export handler.
+func NewExportICSHandler(builder calendar.Builder) Handler {
+	return &ExportICSHandler{
+		builder: builder,
+	}
+}
+
+// Handle implements Handler. The response carries the events, soonest first.
+func (h *ExportICSHandler) Handle(ctx context.Context, _ Request) (Response, error) {
+	events, err := h.builder.Events(ctx)
+	if err != nil {
+		return Response{
+			Success: false,
+			Errors:  []string{fmt.Sprintf("failed to collect calendar events: %v", err)},
+		}, fmt.Errorf("failed to collect calendar events: %w", err)
+	}
+
+	return Response{
+		Success: true,
+		Data:    events,
+	}, nil
+}
@@ -0,0 +1,48 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kazemisoroush/assistant/pkg/obsidian"
+)
+
+// ExportObsidianSubcommand is the export subcommand syncing the records into an Obsidian vault
+const ExportObsidianSubcommand = "obsidian"
+
+// ExportObsidianHandler syncs the records as Markdown notes into an Obsidian vault.
+type ExportObsidianHandler struct {
+	exporter obsidian.Exporter
+}
+
+// NewExportObsidianHandler creates a new Obsidian export handler.
+func NewExportObsidianHandler(exporter obsidian.Exporter) Handler {
+	return &ExportObsidianHandler{
+		exporter: exporter,
+	}
+}
+
+// Handle implements Handler. Request data is the vault directory; the
+// response carries what the export changed.
+func (h *ExportObsidianHandler) Handle(ctx context.Context, request Request) (Response, error) {
+	dir, ok := request.Data.(string)
+	if !ok || dir == "" {
+		return Response{
+			Success: false,
+			Errors:  []string{"vault directory is required"},
+		}, fmt.Errorf("vault directory is required")
+	}
+
+	result, err := h.exporter.Export(ctx, dir)
+	if err != nil {
+		return Response{
+			Success: false,
+			Errors:  []string{fmt.Sprintf("failed to export vault: %v", err)},
+		}, fmt.Errorf("failed to export vault: %w", err)
+	}
+
+	return Response{
+		Success: true,
+		Data:    result,
+	}, nil
+}
@@ -0,0 +1,49 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kazemisoroush/assistant/pkg/records/storage"
+)
+
+const (
+	// GetCommandType is the command type for reading a record
+	GetCommandType = "get"
+)
+
+// GetRecordHandler reads a record by its ID.
+type GetRecordHandler struct {
+	storage storage.Storage
+}
+
+// NewGetRecordHandler creates a new get record handler.
+func NewGetRecordHandler(storage storage.Storage) Handler {
+	return &GetRecordHandler{
+		storage: storage,
+	}
+}
+
+// Handle implements Handler. Request data is the ID of the record.
+func (h *GetRecordHandler) Handle(ctx context.Context, request Request) (Response, error) {
+	id, ok := request.Data.(string)
+	if !ok || id == "" {
+		return Response{
+			Success: false,
+			Errors:  []string{"record ID is required"},
+		}, fmt.Errorf("record ID is required")
+	}
+
+	rec, err := h.storage.Get(ctx, id)
+	if err != nil {
+		return Response{
+			Success: false,
+			Errors:  []string{fmt.Sprintf("failed to get record: %v", err)},
+		}, fmt.Errorf("failed to get record: %w", err)
+	}
+
+	return Response{
+		Success: true,
+		Data:    rec,
+	}, nil
+}
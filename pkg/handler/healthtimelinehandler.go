@@ -0,0 +1,46 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kazemisoroush/assistant/pkg/health"
+)
+
+const (
+	// HealthCommandType is the command type for health views
+	HealthCommandType = "health"
+
+	// HealthTimelineSubcommand is the health subcommand showing timelines
+	HealthTimelineSubcommand = "timeline"
+)
+
+// HealthTimelineHandler returns chronological health timelines per person.
+type HealthTimelineHandler struct {
+	builder health.TimelineBuilder
+}
+
+// NewHealthTimelineHandler creates a new health timeline handler.
+func NewHealthTimelineHandler(builder health.TimelineBuilder) Handler {
+	return &HealthTimelineHandler{
+		builder: builder,
+	}
+}
+
+// Handle implements Handler. Request data is an optional person to limit the timeline to.
+func (h *HealthTimelineHandler) Handle(ctx context.Context, request Request) (Response, error) {
+	person, _ := request.Data.(string)
+
+	timelines, err := h.builder.Timelines(ctx, person)
+	if err != nil {
+		return Response{
+			Success: false,
+			Errors:  []string{fmt.Sprintf("failed to build health timeline: %v", err)},
+		}, fmt.Errorf("failed to build health timeline: %w", err)
+	}
+
+	return Response{
+		Success: true,
+		Data:    timelines,
+	}, nil
+}
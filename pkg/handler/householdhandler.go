@@ -0,0 +1,50 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kazemisoroush/assistant/pkg/household"
+)
+
+const (
+	// HouseholdCommandType is the command type for managing household members
+	HouseholdCommandType = "household"
+
+	// HouseholdAddSubcommand is the household subcommand adding or replacing a member
+	HouseholdAddSubcommand = "add"
+
+	// HouseholdRemoveSubcommand is the household subcommand removing a member
+	HouseholdRemoveSubcommand = "remove"
+
+	// HouseholdAssignSubcommand is the household subcommand attributing a record to a member
+	HouseholdAssignSubcommand = "assign"
+)
+
+// HouseholdHandler lists the household members.
+type HouseholdHandler struct {
+	store household.Store
+}
+
+// NewHouseholdHandler creates a new household handler.
+func NewHouseholdHandler(store household.Store) Handler {
+	return &HouseholdHandler{
+		store: store,
+	}
+}
+
+// Handle implements Handler. Request data is unused.
+func (h *HouseholdHandler) Handle(ctx context.Context, _ Request) (Response, error) {
+	members, err := h.store.List(ctx)
+	if err != nil {
+		return Response{
+			Success: false,
+			Errors:  []string{fmt.Sprintf("failed to list household members: %v", err)},
+		}, fmt.Errorf("failed to list household members: %w", err)
+	}
+
+	return Response{
+		Success: true,
+		Data:    members,
+	}, nil
+}
@@ -0,0 +1,68 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/kazemisoroush/assistant/pkg/records"
+	"github.com/kazemisoroush/assistant/pkg/records/storage"
+)
+
+const (
+	// ListCommandType is the command type for listing records
+	ListCommandType = "list"
+)
+
+// errListed stops iterating the storage once enough records are listed
+var errListed = errors.New("listed")
+
+// ListRequest represents the records to list: those of Type, or of all
+// types when empty, at most Limit of them, or all when 0
+type ListRequest struct {
+	Type  records.RecordType
+	Limit int
+}
+
+// ListRecordsHandler lists stored records.
+type ListRecordsHandler struct {
+	storage storage.Storage
+}
+
+// NewListRecordsHandler creates a new list records handler.
+func NewListRecordsHandler(storage storage.Storage) Handler {
+	return &ListRecordsHandler{
+		storage: storage,
+	}
+}
+
+// Handle implements Handler. Request data is a ListRequest.
+func (h *ListRecordsHandler) Handle(ctx context.Context, request Request) (Response, error) {
+	list, ok := request.Data.(ListRequest)
+	if !ok || list.Limit < 0 {
+		return Response{
+			Success: false,
+			Errors:  []string{"invalid list request"},
+		}, fmt.Errorf("invalid list request")
+	}
+
+	recs := []records.Record{}
+	err := h.storage.Each(ctx, list.Type, func(rec records.Record) error {
+		recs = append(recs, rec)
+		if list.Limit > 0 && len(recs) == list.Limit {
+			return errListed
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errListed) {
+		return Response{
+			Success: false,
+			Errors:  []string{fmt.Sprintf("failed to list records: %v", err)},
+		}, fmt.Errorf("failed to list records: %w", err)
+	}
+
+	return Response{
+		Success: true,
+		Data:    recs,
+	}, nil
+}
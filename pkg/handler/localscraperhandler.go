@@ -2,9 +2,15 @@ package handler
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log/slog"
+	"time"
 
+	"github.com/kazemisoroush/assistant/pkg/records"
+	"github.com/kazemisoroush/assistant/pkg/records/checkpoint"
 	"github.com/kazemisoroush/assistant/pkg/records/ingestor"
+	"github.com/kazemisoroush/assistant/pkg/records/pipeline"
 	"github.com/kazemisoroush/assistant/pkg/records/source"
 )
 
@@ -13,63 +19,305 @@ var (
 	ScrapeCommandType = "scrape"
 )
 
+// ScrapeRequest represents the request data of the scrape command
+type ScrapeRequest struct {
+	Resume    bool                      // Resume every resumable source from its checkpoint
+	KeepGoing bool                      // Collect failures to scrape files and scrape the remaining ones instead of stopping
+	Progress  func(checkpoint.Progress) // Called as resumable sources walk and finish files; may be nil
+}
+
+// failures collects the failures of a scrape. Unless the scrape keeps going,
+// the first failure stops it.
+type failures struct {
+	keepGoing bool
+	errs      []error
+}
+
+// add collects a failure, returning it when it stops the scrape
+func (f *failures) add(ctx context.Context, err error) error {
+	if !f.keepGoing {
+		return err
+	}
+	slog.WarnContext(ctx, "Scrape failure", "error", err)
+	f.errs = append(f.errs, err)
+	return nil
+}
+
+// messages returns the failures collected
+func (f *failures) messages() []string {
+	messages := make([]string, 0, len(f.errs))
+	for _, err := range f.errs {
+		messages = append(messages, err.Error())
+	}
+	return messages
+}
+
+// checkpointInterval is how many ingested records a scrape checkpoint is saved after
+const checkpointInterval = 50
+
 // LocalScraperHandler handles scraping records from local sources.
 type LocalScraperHandler struct {
-	ingestor ingestor.Ingestor
-	sources  []source.Source
+	ingestor    ingestor.Ingestor
+	sources     []source.Source
+	ingest      pipeline.StageConfig
+	batch       ingestor.BatchConfig
+	checkpoints checkpoint.Store // nil disables checkpoints
 }
 
-// NewLocalScraperHandler creates a new local scraper handler.
-func NewLocalScraperHandler(ingestor ingestor.Ingestor, sources []source.Source) Handler {
+// NewLocalScraperHandler creates a new local scraper handler. Scraped records
+// are grouped in batches, each ingested in one write by the workers of the
+// ingest stage. The progress of resumable sources is checkpointed in
+// checkpoints, which may be nil.
+func NewLocalScraperHandler(ingestor ingestor.Ingestor, sources []source.Source, ingest pipeline.StageConfig, batch ingestor.BatchConfig, checkpoints checkpoint.Store) Handler {
 	return &LocalScraperHandler{
-		ingestor: ingestor,
-		sources:  sources,
+		ingestor:    ingestor,
+		sources:     sources,
+		ingest:      ingest,
+		batch:       batch,
+		checkpoints: checkpoints,
 	}
 }
 
-// Handle implements Handler.
-func (l LocalScraperHandler) Handle(ctx context.Context, _ Request) (Response, error) {
+// Handle implements Handler. Request data, when given, is a ScrapeRequest.
+// Scraping stops at the first error unless the request keeps going, in which
+// case the failures are collected into the response, which fails once every
+// source was scraped. Once done, the stage timings are logged per record
+// type, with batches mixing types timed under an unknown type.
+func (l LocalScraperHandler) Handle(ctx context.Context, request Request) (Response, error) {
+	defer l.logStageTimings(ctx)
+	req, _ := request.Data.(ScrapeRequest)
+	failed := &failures{keepGoing: req.KeepGoing}
 	recordCount := 0
 
 	for _, src := range l.sources {
-		recordChan, errChan := src.Scrape(ctx)
-
-		for {
-			select {
-			case record, ok := <-recordChan:
-				if !ok {
-					recordChan = nil
-					continue
-				}
-				if err := l.ingestor.Ingest(ctx, record); err != nil {
-					return Response{
-						Success: false,
-						Errors:  []string{fmt.Sprintf("failed to ingest record from source %s: %v", src.Name(), err)},
-					}, fmt.Errorf("failed to ingest record from source %s: %w", src.Name(), err)
-				}
-				recordCount++
-			case err, ok := <-errChan:
-				if !ok {
-					errChan = nil
-					continue
-				}
-				return Response{
-					Success: false,
-					Errors:  []string{fmt.Sprintf("error while scraping source %s: %v", src.Name(), err)},
-				}, fmt.Errorf("error while scraping source %s: %w", src.Name(), err)
-			}
-
-			if recordChan == nil && errChan == nil {
-				break
-			}
+		count, err := l.scrape(ctx, src, req, failed)
+		recordCount += count
+		if err != nil {
+			return Response{
+				Success: false,
+				Errors:  append(failed.messages(), err.Error()),
+			}, err
 		}
 	}
 
+	data := map[string]any{
+		"records_ingested": recordCount,
+		"sources_scraped":  len(l.sources),
+		"failures":         len(failed.errs),
+	}
+	if len(failed.errs) > 0 {
+		return Response{
+			Success: false,
+			Data:    data,
+			Errors:  failed.messages(),
+		}, fmt.Errorf("scrape finished with %d failures", len(failed.errs))
+	}
 	return Response{
 		Success: true,
-		Data: map[string]any{
-			"records_ingested": recordCount,
-			"sources_scraped":  len(l.sources),
-		},
+		Data:    data,
 	}, nil
 }
+
+// scrape ingests the records of a source and returns how many were ingested.
+// Records still buffered for indexing are indexed even when scraping failed
+// or was interrupted.
+func (l LocalScraperHandler) scrape(ctx context.Context, src source.Source, req ScrapeRequest, failed *failures) (int, error) {
+	tracker, err := l.tracker(ctx, src, req)
+	if err != nil {
+		return 0, err
+	}
+
+	before := len(failed.errs)
+	count, err := l.consume(ctx, src, tracker, failed)
+	if flushErr := l.ingestor.Flush(context.WithoutCancel(ctx)); flushErr != nil {
+		// The checkpoint saved last still covers indexed records only
+		return count, errors.Join(err, fmt.Errorf("failed to index records from source %s: %w", src.Name(), flushErr))
+	}
+	// The checkpoint of a source with failed files or cancelled part way is
+	// kept, so resuming retries the files left
+	l.finish(ctx, src, tracker, err == nil && ctx.Err() == nil && len(failed.errs) == before)
+	return count, err
+}
+
+// tracker returns the tracker of a resumable source, continuing from its
+// checkpoint when resuming, or nil when the source is not resumable
+func (l LocalScraperHandler) tracker(ctx context.Context, src source.Source, req ScrapeRequest) (*checkpoint.Tracker, error) {
+	if _, ok := src.(source.Resumable); !ok {
+		return nil, nil
+	}
+	if !req.Resume || l.checkpoints == nil {
+		return checkpoint.NewTracker(checkpoint.Checkpoint{Source: src.Name()}, req.Progress), nil
+	}
+
+	cp, err := l.checkpoints.Load(ctx, src.Name())
+	if err != nil {
+		return nil, err
+	}
+	if cp.Cursor != "" {
+		slog.InfoContext(ctx, "Resuming scrape", "source", src.Name(), "cursor", cp.Cursor, "ingested", cp.Ingested)
+	}
+	return checkpoint.NewTracker(cp, req.Progress), nil
+}
+
+// consume runs the ingest stage over the records of a source, passing
+// failures to failed
+func (l LocalScraperHandler) consume(ctx context.Context, src source.Source, tracker *checkpoint.Tracker, failed *failures) (int, error) {
+	// Cancelling stops the source and the ingest workers after a failure
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	recordChan, errChan := l.open(ctx, src, tracker)
+	batches := pipeline.Batch(ctx, recordChan, l.batch.Size, l.batch.Timeout)
+	ingestErrs := make(chan error)
+	ingested := pipeline.Run(ctx, l.ingest, batches, l.ingestBatch(src, tracker, failed.keepGoing, ingestErrs), ingestErrs)
+
+	count := 0
+	for {
+		select {
+		case n, ok := <-ingested:
+			if !ok {
+				return count, l.pending(ctx, src, errChan, failed)
+			}
+			// Checkpoints are saved whenever another checkpointInterval records were ingested
+			if (count+n)/checkpointInterval > count/checkpointInterval {
+				l.save(ctx, src, tracker)
+			}
+			count += n
+		case err := <-ingestErrs:
+			if err := failed.add(ctx, err); err != nil {
+				return count, err
+			}
+		case err, ok := <-errChan:
+			if !ok {
+				errChan = nil
+				continue
+			}
+			if err := failed.add(ctx, fmt.Errorf("error while scraping source %s: %w", src.Name(), err)); err != nil {
+				return count, err
+			}
+		}
+	}
+}
+
+// ingestBatch returns the ingest stage's function, which ingests a batch of
+// records of a source and returns how many were ingested. When the scrape
+// keeps going, a batch failing to be ingested is ingested again one record at
+// a time, so only the files whose records fail are reported, to errs.
+func (l LocalScraperHandler) ingestBatch(src source.Source, tracker *checkpoint.Tracker, keepGoing bool, errs chan<- error) func(context.Context, []records.Record) (int, error) {
+	return func(ctx context.Context, batch []records.Record) (int, error) {
+		ctx, timings := pipeline.WithTimings(ctx)
+		err := l.ingestor.IngestBatch(ctx, batch)
+		l.ingest.Metrics.Record(timings, batchType(batch))
+		if err != nil && keepGoing && len(batch) > 1 {
+			return l.ingestEach(ctx, src, tracker, batch, errs), nil
+		}
+		for _, record := range batch {
+			tracker.Finished(record.SourcePath(), err != nil)
+		}
+		if err != nil {
+			return 0, fmt.Errorf("failed to ingest %d records from source %s: %w", len(batch), src.Name(), err)
+		}
+		return len(batch), nil
+	}
+}
+
+// ingestEach ingests the records of a batch one at a time, sending the error
+// of every record failing to errs, and returns how many were ingested
+func (l LocalScraperHandler) ingestEach(ctx context.Context, src source.Source, tracker *checkpoint.Tracker, batch []records.Record, errs chan<- error) int {
+	count := 0
+	for _, record := range batch {
+		err := l.ingestor.Ingest(ctx, record)
+		tracker.Finished(record.SourcePath(), err != nil)
+		if err != nil {
+			pipeline.Send(ctx, errs, fmt.Errorf("failed to ingest record %s from source %s: %w", record.SourcePath(), src.Name(), err), nil)
+			continue
+		}
+		count++
+	}
+	return count
+}
+
+// batchType returns the type shared by the records of a batch, or "" when their types differ
+func batchType(batch []records.Record) string {
+	for _, record := range batch[1:] {
+		if record.Type != batch[0].Type {
+			return ""
+		}
+	}
+	return string(batch[0].Type)
+}
+
+// open starts scraping the source, from the tracker's cursor when it has one
+func (l LocalScraperHandler) open(ctx context.Context, src source.Source, tracker *checkpoint.Tracker) (<-chan records.Record, <-chan error) {
+	if tracker == nil {
+		return src.Scrape(ctx)
+	}
+	return src.(source.Resumable).Resume(ctx, tracker)
+}
+
+// pending passes the errors the source reported before it finished to
+// failed, returning the first that stops the scrape. The source closes its
+// error channel before the records channel, so receiving does not block once
+// every record was ingested.
+func (l LocalScraperHandler) pending(ctx context.Context, src source.Source, errChan <-chan error, failed *failures) error {
+	if errChan == nil {
+		return nil
+	}
+	for err := range errChan {
+		if err := failed.add(ctx, fmt.Errorf("error while scraping source %s: %w", src.Name(), err)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// save checkpoints the progress of a scrape. The records it covers are
+// indexed first, so a resumed scrape never skips records missing from the index.
+func (l LocalScraperHandler) save(ctx context.Context, src source.Source, tracker *checkpoint.Tracker) {
+	if tracker == nil || l.checkpoints == nil {
+		return
+	}
+	cp := tracker.Checkpoint(time.Now())
+	if err := l.ingestor.Flush(ctx); err != nil {
+		slog.WarnContext(ctx, "Failed to index records before checkpoint", "source", src.Name(), "error", err)
+		return
+	}
+	if err := l.checkpoints.Save(ctx, cp); err != nil {
+		slog.WarnContext(ctx, "Failed to save scrape checkpoint", "source", src.Name(), "error", err)
+	}
+}
+
+// finish removes the checkpoint once the source was scraped completely, or
+// saves the checkpoint of an interrupted scrape or one with failed files. It
+// runs even when ctx was cancelled.
+func (l LocalScraperHandler) finish(ctx context.Context, src source.Source, tracker *checkpoint.Tracker, complete bool) {
+	if tracker == nil || l.checkpoints == nil {
+		return
+	}
+	ctx = context.WithoutCancel(ctx)
+
+	var err error
+	if complete {
+		err = l.checkpoints.Delete(ctx, src.Name())
+	} else {
+		err = l.checkpoints.Save(ctx, tracker.Checkpoint(time.Now()))
+	}
+	if err != nil {
+		slog.WarnContext(ctx, "Failed to update scrape checkpoint", "source", src.Name(), "error", err)
+	}
+}
+
+// logStageTimings logs how long every stage took per record type
+func (l LocalScraperHandler) logStageTimings(ctx context.Context) {
+	for _, stage := range l.ingest.Metrics.Summary() {
+		slog.InfoContext(ctx, "Stage timings",
+			"stage", stage.Stage,
+			"type", stage.RecordType,
+			"records", stage.Count,
+			"errors", stage.Errors,
+			"total", stage.Total,
+			"mean", stage.Mean,
+			"p95", stage.P95,
+		)
+	}
+}
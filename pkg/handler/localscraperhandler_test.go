@@ -0,0 +1,133 @@
+package handler_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/kazemisoroush/assistant/pkg/handler"
+	"github.com/kazemisoroush/assistant/pkg/records"
+	"github.com/kazemisoroush/assistant/pkg/records/checkpoint"
+	checkpointmocks "github.com/kazemisoroush/assistant/pkg/records/checkpoint/mocks"
+	"github.com/kazemisoroush/assistant/pkg/records/ingestor"
+	ingestormocks "github.com/kazemisoroush/assistant/pkg/records/ingestor/mocks"
+	"github.com/kazemisoroush/assistant/pkg/records/pipeline"
+	"github.com/kazemisoroush/assistant/pkg/records/source"
+	sourcemocks "github.com/kazemisoroush/assistant/pkg/records/source/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+// scraped returns the channels of a scrape sending the records
+func scraped(recs ...records.Record) (<-chan records.Record, <-chan error) {
+	recordChan := make(chan records.Record, len(recs))
+	for _, record := range recs {
+		recordChan <- record
+	}
+	close(recordChan)
+	errChan := make(chan error)
+	close(errChan)
+	return recordChan, errChan
+}
+
+// file returns a record extracted from the file at path
+func file(path string) records.Record {
+	return records.Record{ID: path, Metadata: map[string]interface{}{records.MetaSourcePath: path}}
+}
+
+// resumable is a resumable source whose scrape sends the records
+type resumable struct {
+	*sourcemocks.MockSource
+	recs []records.Record
+}
+
+// Resume implements source.Resumable
+func (r resumable) Resume(context.Context, *checkpoint.Tracker) (<-chan records.Record, <-chan error) {
+	return scraped(r.recs...)
+}
+
+func TestLocalScraperHandler_Handle_Cancelled_KeepsCheckpoint(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	recordIngestor := ingestormocks.NewMockService(ctrl)
+	checkpoints := checkpointmocks.NewMockStore(ctrl)
+	src := sourcemocks.NewMockSource(ctrl)
+	src.EXPECT().Name().Return("local").AnyTimes()
+	recordIngestor.EXPECT().Flush(gomock.Any()).Return(nil)
+	checkpoints.EXPECT().Save(gomock.Any(), gomock.Any()).Return(nil)
+	h := handler.NewLocalScraperHandler(recordIngestor, []source.Source{resumable{MockSource: src}}, pipeline.StageConfig{}, ingestor.BatchConfig{Size: 2}, checkpoints)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// Act
+	_, err := h.Handle(ctx, handler.Request{Data: handler.ScrapeRequest{}})
+
+	// Assert
+	assert.NoError(t, err, "Handle() should stop without an error once cancelled")
+}
+
+func TestLocalScraperHandler_Handle_Complete_DeletesCheckpoint(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	recordIngestor := ingestormocks.NewMockService(ctrl)
+	checkpoints := checkpointmocks.NewMockStore(ctrl)
+	src := sourcemocks.NewMockSource(ctrl)
+	src.EXPECT().Name().Return("local").AnyTimes()
+	recordIngestor.EXPECT().IngestBatch(gomock.Any(), gomock.Len(1)).Return(nil)
+	recordIngestor.EXPECT().Flush(gomock.Any()).Return(nil)
+	checkpoints.EXPECT().Delete(gomock.Any(), "local").Return(nil)
+	h := handler.NewLocalScraperHandler(recordIngestor, []source.Source{resumable{MockSource: src, recs: []records.Record{file("a.pdf")}}}, pipeline.StageConfig{}, ingestor.BatchConfig{Size: 2}, checkpoints)
+
+	// Act
+	_, err := h.Handle(context.Background(), handler.Request{Data: handler.ScrapeRequest{}})
+
+	// Assert
+	assert.NoError(t, err, "Handle() should scrape the source")
+}
+
+func TestLocalScraperHandler_Handle_KeepGoing_BadRecordInBatch(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	recordIngestor := ingestormocks.NewMockService(ctrl)
+	src := sourcemocks.NewMockSource(ctrl)
+	src.EXPECT().Name().Return("local").AnyTimes()
+	src.EXPECT().Scrape(gomock.Any()).Return(scraped(file("a.pdf"), file("b.pdf"), file("c.pdf")))
+	recordIngestor.EXPECT().IngestBatch(gomock.Any(), gomock.Len(3)).Return(errors.New("invalid record"))
+	recordIngestor.EXPECT().Ingest(gomock.Any(), file("a.pdf")).Return(nil)
+	recordIngestor.EXPECT().Ingest(gomock.Any(), file("b.pdf")).Return(errors.New("invalid record"))
+	recordIngestor.EXPECT().Ingest(gomock.Any(), file("c.pdf")).Return(nil)
+	recordIngestor.EXPECT().Flush(gomock.Any()).Return(nil)
+	h := handler.NewLocalScraperHandler(recordIngestor, []source.Source{src}, pipeline.StageConfig{}, ingestor.BatchConfig{Size: 3}, nil)
+
+	// Act
+	resp, err := h.Handle(context.Background(), handler.Request{Data: handler.ScrapeRequest{KeepGoing: true}})
+
+	// Assert
+	require.Error(t, err, "Handle() should report the failed record")
+	require.Len(t, resp.Errors, 1, "Handle() should report only the file whose record failed")
+	assert.Contains(t, resp.Errors[0], "b.pdf", "Handle() should name the file whose record failed")
+	data, ok := resp.Data.(map[string]any)
+	require.True(t, ok, "Handle() should return the scrape counts")
+	assert.Equal(t, 2, data["records_ingested"], "Handle() should ingest the good records of the failed batch")
+	assert.Equal(t, 1, data["failures"], "Handle() should count one failure")
+}
+
+func TestLocalScraperHandler_Handle_BadRecordInBatch(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	recordIngestor := ingestormocks.NewMockService(ctrl)
+	src := sourcemocks.NewMockSource(ctrl)
+	src.EXPECT().Name().Return("local").AnyTimes()
+	src.EXPECT().Scrape(gomock.Any()).Return(scraped(file("a.pdf"), file("b.pdf")))
+	recordIngestor.EXPECT().IngestBatch(gomock.Any(), gomock.Len(2)).Return(errors.New("invalid record"))
+	recordIngestor.EXPECT().Flush(gomock.Any()).Return(nil)
+	h := handler.NewLocalScraperHandler(recordIngestor, []source.Source{src}, pipeline.StageConfig{}, ingestor.BatchConfig{Size: 2}, nil)
+
+	// Act
+	_, err := h.Handle(context.Background(), handler.Request{Data: handler.ScrapeRequest{}})
+
+	// Assert
+	require.Error(t, err, "Handle() should stop at the failed batch")
+	assert.Contains(t, err.Error(), "invalid record", "Handle() should wrap the ingest error")
+}
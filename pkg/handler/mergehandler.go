@@ -0,0 +1,44 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kazemisoroush/assistant/pkg/duplicates"
+)
+
+// MergeHandler merges a duplicate record into the record it copies.
+type MergeHandler struct {
+	merger duplicates.Merger
+}
+
+// NewMergeHandler creates a new merge handler.
+func NewMergeHandler(merger duplicates.Merger) Handler {
+	return &MergeHandler{
+		merger: merger,
+	}
+}
+
+// Handle implements Handler. Request data is the duplicates.Candidate to merge.
+func (h *MergeHandler) Handle(ctx context.Context, request Request) (Response, error) {
+	candidate, ok := request.Data.(duplicates.Candidate)
+	if !ok || candidate.KeepID == "" || candidate.DuplicateID == "" {
+		return Response{
+			Success: false,
+			Errors:  []string{"record to keep and duplicate are required"},
+		}, fmt.Errorf("record to keep and duplicate are required")
+	}
+
+	merged, err := h.merger.Merge(ctx, candidate.KeepID, candidate.DuplicateID)
+	if err != nil {
+		return Response{
+			Success: false,
+			Errors:  []string{fmt.Sprintf("failed to merge records: %v", err)},
+		}, fmt.Errorf("failed to merge records: %w", err)
+	}
+
+	return Response{
+		Success: true,
+		Data:    merged,
+	}, nil
+}
@@ -0,0 +1,51 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/kazemisoroush/assistant/pkg/reminders"
+)
+
+const (
+	// RemindersCommandType is the command type for document expiry reminders
+	RemindersCommandType = "reminders"
+)
+
+// RemindersHandler lists or sends reminders about expiring documents.
+type RemindersHandler struct {
+	engine reminders.Engine
+}
+
+// NewRemindersHandler creates a new reminders handler.
+func NewRemindersHandler(engine reminders.Engine) Handler {
+	return &RemindersHandler{
+		engine: engine,
+	}
+}
+
+// Handle implements Handler. Request data is true to send the reminders that
+// became due, otherwise all upcoming reminders are listed without sending.
+func (h *RemindersHandler) Handle(ctx context.Context, request Request) (Response, error) {
+	send, _ := request.Data.(bool)
+
+	var due []reminders.Reminder
+	var err error
+	if send {
+		due, err = h.engine.Run(ctx, time.Now())
+	} else {
+		due, err = h.engine.Upcoming(ctx, time.Now())
+	}
+	if err != nil {
+		return Response{
+			Success: false,
+			Errors:  []string{fmt.Sprintf("failed to process reminders: %v", err)},
+		}, fmt.Errorf("failed to process reminders: %w", err)
+	}
+
+	return Response{
+		Success: true,
+		Data:    due,
+	}, nil
+}
@@ -0,0 +1,45 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/kazemisoroush/assistant/pkg/budgets"
+)
+
+// RemoveBudgetHandler removes the monthly budget of a category.
+type RemoveBudgetHandler struct {
+	store budgets.Store
+}
+
+// NewRemoveBudgetHandler creates a new remove budget handler.
+func NewRemoveBudgetHandler(store budgets.Store) Handler {
+	return &RemoveBudgetHandler{
+		store: store,
+	}
+}
+
+// Handle implements Handler. Request data is the category of the budget to remove.
+func (h *RemoveBudgetHandler) Handle(ctx context.Context, request Request) (Response, error) {
+	category, ok := request.Data.(string)
+	if !ok || category == "" {
+		return Response{
+			Success: false,
+			Errors:  []string{"budget category is required"},
+		}, fmt.Errorf("budget category is required")
+	}
+
+	category = strings.ToLower(category)
+	if err := h.store.Delete(ctx, category); err != nil {
+		return Response{
+			Success: false,
+			Errors:  []string{fmt.Sprintf("failed to remove budget: %v", err)},
+		}, fmt.Errorf("failed to remove budget: %w", err)
+	}
+
+	return Response{
+		Success: true,
+		Data:    category,
+	}, nil
+}
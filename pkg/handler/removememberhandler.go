@@ -0,0 +1,44 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kazemisoroush/assistant/pkg/household"
+)
+
+// RemoveMemberHandler removes a household member. Records attributed to the
+// member keep their attribution.
+type RemoveMemberHandler struct {
+	store household.Store
+}
+
+// NewRemoveMemberHandler creates a new remove member handler.
+func NewRemoveMemberHandler(store household.Store) Handler {
+	return &RemoveMemberHandler{
+		store: store,
+	}
+}
+
+// Handle implements Handler. Request data is the name of the member to remove.
+func (h *RemoveMemberHandler) Handle(ctx context.Context, request Request) (Response, error) {
+	name, ok := request.Data.(string)
+	if !ok || name == "" {
+		return Response{
+			Success: false,
+			Errors:  []string{"household member name is required"},
+		}, fmt.Errorf("household member name is required")
+	}
+
+	if err := h.store.Delete(ctx, name); err != nil {
+		return Response{
+			Success: false,
+			Errors:  []string{fmt.Sprintf("failed to remove household member: %v", err)},
+		}, fmt.Errorf("failed to remove household member: %w", err)
+	}
+
+	return Response{
+		Success: true,
+		Data:    name,
+	}, nil
+}
@@ -0,0 +1,44 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kazemisoroush/assistant/pkg/records"
+	"github.com/kazemisoroush/assistant/pkg/records/typestore"
+)
+
+// RemoveTypeHandler removes a user-defined record type.
+type RemoveTypeHandler struct {
+	store typestore.Store
+}
+
+// NewRemoveTypeHandler creates a new remove type handler.
+func NewRemoveTypeHandler(store typestore.Store) Handler {
+	return &RemoveTypeHandler{
+		store: store,
+	}
+}
+
+// Handle implements Handler. Request data is the name of the record type to remove.
+func (h *RemoveTypeHandler) Handle(ctx context.Context, request Request) (Response, error) {
+	name, ok := request.Data.(string)
+	if !ok || name == "" {
+		return Response{
+			Success: false,
+			Errors:  []string{"record type name is required"},
+		}, fmt.Errorf("record type name is required")
+	}
+
+	if err := typestore.Remove(ctx, h.store, records.RecordType(name)); err != nil {
+		return Response{
+			Success: false,
+			Errors:  []string{fmt.Sprintf("failed to remove record type: %v", err)},
+		}, fmt.Errorf("failed to remove record type: %w", err)
+	}
+
+	return Response{
+		Success: true,
+		Data:    name,
+	}, nil
+}
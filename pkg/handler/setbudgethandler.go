@@ -0,0 +1,44 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kazemisoroush/assistant/pkg/budgets"
+)
+
+// SetBudgetHandler adds or replaces the monthly budget of a category.
+type SetBudgetHandler struct {
+	store budgets.Store
+}
+
+// NewSetBudgetHandler creates a new set budget handler.
+func NewSetBudgetHandler(store budgets.Store) Handler {
+	return &SetBudgetHandler{
+		store: store,
+	}
+}
+
+// Handle implements Handler. Request data is the budgets.Budget to save.
+func (h *SetBudgetHandler) Handle(ctx context.Context, request Request) (Response, error) {
+	budget, ok := request.Data.(budgets.Budget)
+	if !ok {
+		return Response{
+			Success: false,
+			Errors:  []string{"budget is required"},
+		}, fmt.Errorf("budget is required")
+	}
+
+	saved, err := budgets.Set(ctx, h.store, budget)
+	if err != nil {
+		return Response{
+			Success: false,
+			Errors:  []string{fmt.Sprintf("failed to set budget: %v", err)},
+		}, fmt.Errorf("failed to set budget: %w", err)
+	}
+
+	return Response{
+		Success: true,
+		Data:    saved,
+	}, nil
+}
@@ -0,0 +1,51 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kazemisoroush/assistant/pkg/records"
+	"github.com/kazemisoroush/assistant/pkg/records/typestore"
+)
+
+// SetSchemaHandler attaches a metadata schema to a record type, or removes it.
+type SetSchemaHandler struct {
+	store typestore.Store
+}
+
+// NewSetSchemaHandler creates a new set schema handler.
+func NewSetSchemaHandler(store typestore.Store) Handler {
+	return &SetSchemaHandler{
+		store: store,
+	}
+}
+
+// Handle implements Handler. Request data is the records.Schema to attach;
+// a schema without fields removes the schema of its type.
+func (h *SetSchemaHandler) Handle(ctx context.Context, request Request) (Response, error) {
+	schema, ok := request.Data.(records.Schema)
+	if !ok || schema.Type == "" {
+		return Response{
+			Success: false,
+			Errors:  []string{"record type schema is required"},
+		}, fmt.Errorf("record type schema is required")
+	}
+
+	var err error
+	if len(schema.Fields) == 0 {
+		err = typestore.RemoveSchema(ctx, h.store, schema.Type)
+	} else {
+		err = typestore.DefineSchema(ctx, h.store, schema.Type, schema.Fields)
+	}
+	if err != nil {
+		return Response{
+			Success: false,
+			Errors:  []string{fmt.Sprintf("failed to set schema of %s: %v", schema.Type, err)},
+		}, fmt.Errorf("failed to set schema of %s: %w", schema.Type, err)
+	}
+
+	return Response{
+		Success: true,
+		Data:    schema,
+	}, nil
+}
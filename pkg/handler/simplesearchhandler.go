@@ -27,22 +27,35 @@ func NewSimpleSearchHandler(discovery discovery.Discovery) Handler {
 	}
 }
 
-// Handle implements Handler for search operations.
+// SearchRequest represents a search returning at most Limit hits, DefaultSearchLimit when 0
+type SearchRequest struct {
+	Prompt string
+	Limit  int
+}
+
+// Handle implements Handler for search operations. Request data is the
+// search prompt or a SearchRequest.
 func (h *SimpleSearchHandler) Handle(ctx context.Context, request Request) (Response, error) {
 	// Extract search prompt from request data
-	prompt, ok := request.Data.(string)
-	if !ok || prompt == "" {
+	search, ok := request.Data.(SearchRequest)
+	if prompt, isPrompt := request.Data.(string); isPrompt {
+		search, ok = SearchRequest{Prompt: prompt}, true
+	}
+	if !ok || search.Prompt == "" {
 		return Response{
 			Success: false,
 			Errors:  []string{"search prompt is required"},
 		}, fmt.Errorf("search prompt is required")
 	}
 
-	// Perform discovery with default limit
+	// Perform discovery, with the default limit unless one is given
 	discoverRequest := discovery.DiscoverRequest{
-		Prompt: prompt,
+		Prompt: search.Prompt,
 		Limit:  DefaultSearchLimit,
 	}
+	if search.Limit > 0 {
+		discoverRequest.Limit = search.Limit
+	}
 
 	discoverResponse, err := h.discovery.Discover(ctx, discoverRequest)
 	if err != nil {
@@ -0,0 +1,43 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kazemisoroush/assistant/pkg/analytics"
+)
+
+const (
+	// SpendCommandType is the command type for expense analytics
+	SpendCommandType = "spend"
+)
+
+// SpendHandler reports spending aggregated from receipts.
+type SpendHandler struct {
+	analyzer analytics.Analyzer
+}
+
+// NewSpendHandler creates a new spend handler.
+func NewSpendHandler(analyzer analytics.Analyzer) Handler {
+	return &SpendHandler{
+		analyzer: analyzer,
+	}
+}
+
+// Handle implements Handler. Request data is an optional analytics.SpendFilter.
+func (h *SpendHandler) Handle(ctx context.Context, request Request) (Response, error) {
+	filter, _ := request.Data.(analytics.SpendFilter)
+
+	report, err := h.analyzer.Spend(ctx, filter)
+	if err != nil {
+		return Response{
+			Success: false,
+			Errors:  []string{fmt.Sprintf("failed to report spend: %v", err)},
+		}, fmt.Errorf("failed to report spend: %w", err)
+	}
+
+	return Response{
+		Success: true,
+		Data:    report,
+	}, nil
+}
@@ -0,0 +1,42 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/kazemisoroush/assistant/pkg/analytics"
+)
+
+const (
+	// SubscriptionsCommandType is the command type for recurring charge detection
+	SubscriptionsCommandType = "subscriptions"
+)
+
+// SubscriptionsHandler lists recurring bills and subscriptions.
+type SubscriptionsHandler struct {
+	detector analytics.SubscriptionDetector
+}
+
+// NewSubscriptionsHandler creates a new subscriptions handler.
+func NewSubscriptionsHandler(detector analytics.SubscriptionDetector) Handler {
+	return &SubscriptionsHandler{
+		detector: detector,
+	}
+}
+
+// Handle implements Handler.
+func (h *SubscriptionsHandler) Handle(ctx context.Context, _ Request) (Response, error) {
+	report, err := h.detector.Subscriptions(ctx, time.Now())
+	if err != nil {
+		return Response{
+			Success: false,
+			Errors:  []string{fmt.Sprintf("failed to detect subscriptions: %v", err)},
+		}, fmt.Errorf("failed to detect subscriptions: %w", err)
+	}
+
+	return Response{
+		Success: true,
+		Data:    report,
+	}, nil
+}
@@ -0,0 +1,56 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kazemisoroush/assistant/pkg/records/knowledgebase"
+	"github.com/kazemisoroush/assistant/pkg/records/storage"
+	"github.com/kazemisoroush/assistant/pkg/summaries"
+)
+
+const (
+	// SummarizeCommandType is the command type for summarizing records ingested without a summary
+	SummarizeCommandType = "summarize"
+)
+
+// SummarizeHandler summarizes the records that have no description yet.
+type SummarizeHandler struct {
+	summarizer    summaries.Summarizer
+	storage       storage.Storage
+	vectorStorage knowledgebase.VectorStorage
+}
+
+// NewSummarizeHandler creates a new summarize handler.
+func NewSummarizeHandler(summarizer summaries.Summarizer, storage storage.Storage, vectorStorage knowledgebase.VectorStorage) Handler {
+	return &SummarizeHandler{
+		summarizer:    summarizer,
+		storage:       storage,
+		vectorStorage: vectorStorage,
+	}
+}
+
+// Handle implements Handler. Request data is the maximum number of records
+// to summarize, 0 for all; the response carries the IDs of those summarized.
+func (h *SummarizeHandler) Handle(ctx context.Context, request Request) (Response, error) {
+	limit, _ := request.Data.(int)
+	if limit < 0 {
+		return Response{
+			Success: false,
+			Errors:  []string{"limit must not be negative"},
+		}, fmt.Errorf("limit must not be negative: %d", limit)
+	}
+
+	summarized, err := summaries.Backfill(ctx, h.summarizer, h.storage, h.vectorStorage, limit)
+	if err != nil {
+		return Response{
+			Success: false,
+			Errors:  []string{fmt.Sprintf("failed to summarize records: %v", err)},
+		}, fmt.Errorf("failed to summarize records: %w", err)
+	}
+
+	return Response{
+		Success: true,
+		Data:    summarized,
+	}, nil
+}
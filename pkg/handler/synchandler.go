@@ -0,0 +1,61 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/kazemisoroush/assistant/pkg/peersync"
+)
+
+const (
+	// SyncCommandType is the command type for syncing records with peers
+	SyncCommandType = "sync"
+)
+
+// SyncHandler syncs the records with every peer in turn. A peer failing to
+// sync does not stop the others.
+type SyncHandler struct {
+	syncer peersync.Syncer
+	peers  []peersync.Peer
+}
+
+// NewSyncHandler creates a new sync handler.
+func NewSyncHandler(syncer peersync.Syncer, peers []peersync.Peer) Handler {
+	return &SyncHandler{
+		syncer: syncer,
+		peers:  peers,
+	}
+}
+
+// Handle implements Handler. The response carries a report per peer synced.
+func (h *SyncHandler) Handle(ctx context.Context, _ Request) (Response, error) {
+	if len(h.peers) == 0 {
+		return Response{
+			Success: false,
+			Errors:  []string{"no peers are configured"},
+		}, fmt.Errorf("no peers are configured")
+	}
+
+	var reports []peersync.Report
+	var errs []error
+	for _, peer := range h.peers {
+		report, err := h.syncer.Sync(ctx, peer)
+		reports = append(reports, report)
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if err := errors.Join(errs...); err != nil {
+		return Response{
+			Success: false,
+			Data:    reports,
+			Errors:  []string{fmt.Sprintf("failed to sync: %v", err)},
+		}, fmt.Errorf("failed to sync: %w", err)
+	}
+
+	return Response{
+		Success: true,
+		Data:    reports,
+	}, nil
+}
@@ -0,0 +1,60 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/kazemisoroush/assistant/pkg/taxreport"
+)
+
+const (
+	// TaxReportCommandType is the command type for tax-year reports
+	TaxReportCommandType = "tax-report"
+)
+
+// TaxReportHandler writes the tax report bundle of a fiscal year.
+type TaxReportHandler struct {
+	generator taxreport.Generator
+	dir       string
+}
+
+// NewTaxReportHandler creates a new tax report handler writing bundles into dir.
+func NewTaxReportHandler(generator taxreport.Generator, dir string) Handler {
+	return &TaxReportHandler{
+		generator: generator,
+		dir:       dir,
+	}
+}
+
+// Handle implements Handler. Request data is the fiscal year; the response carries the bundle manifest.
+func (h *TaxReportHandler) Handle(ctx context.Context, request Request) (Response, error) {
+	fiscalYear, ok := request.Data.(int)
+	if !ok || fiscalYear <= 0 {
+		return Response{
+			Success: false,
+			Errors:  []string{"fiscal year is required"},
+		}, fmt.Errorf("fiscal year is required")
+	}
+
+	report, err := h.generator.Generate(ctx, fiscalYear)
+	if err != nil {
+		return Response{
+			Success: false,
+			Errors:  []string{fmt.Sprintf("failed to generate tax report: %v", err)},
+		}, fmt.Errorf("failed to generate tax report: %w", err)
+	}
+
+	manifest, err := taxreport.WriteBundle(h.dir, report, time.Now())
+	if err != nil {
+		return Response{
+			Success: false,
+			Errors:  []string{fmt.Sprintf("failed to write tax report: %v", err)},
+		}, fmt.Errorf("failed to write tax report: %w", err)
+	}
+
+	return Response{
+		Success: true,
+		Data:    manifest,
+	}, nil
+}
@@ -0,0 +1,41 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kazemisoroush/assistant/pkg/trips"
+)
+
+const (
+	// TripsCommandType is the command type for trips clustered from travel records
+	TripsCommandType = "trips"
+)
+
+// TripsHandler reports travel bookings grouped into trips with their receipts, visas and spend.
+type TripsHandler struct {
+	clusterer trips.Clusterer
+}
+
+// NewTripsHandler creates a new trips handler.
+func NewTripsHandler(clusterer trips.Clusterer) Handler {
+	return &TripsHandler{
+		clusterer: clusterer,
+	}
+}
+
+// Handle implements Handler.
+func (h *TripsHandler) Handle(ctx context.Context, _ Request) (Response, error) {
+	found, err := h.clusterer.Trips(ctx)
+	if err != nil {
+		return Response{
+			Success: false,
+			Errors:  []string{fmt.Sprintf("failed to cluster trips: %v", err)},
+		}, fmt.Errorf("failed to cluster trips: %w", err)
+	}
+
+	return Response{
+		Success: true,
+		Data:    found,
+	}, nil
+}
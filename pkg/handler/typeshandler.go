@@ -0,0 +1,50 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kazemisoroush/assistant/pkg/records/typestore"
+)
+
+const (
+	// TypesCommandType is the command type for managing user-defined record types
+	TypesCommandType = "types"
+
+	// TypesAddSubcommand is the types subcommand defining a record type
+	TypesAddSubcommand = "add"
+
+	// TypesRemoveSubcommand is the types subcommand removing a record type
+	TypesRemoveSubcommand = "remove"
+
+	// TypesSchemaSubcommand is the types subcommand attaching a metadata schema to a record type
+	TypesSchemaSubcommand = "schema"
+)
+
+// TypesHandler lists the user-defined record types.
+type TypesHandler struct {
+	store typestore.Store
+}
+
+// NewTypesHandler creates a new types handler.
+func NewTypesHandler(store typestore.Store) Handler {
+	return &TypesHandler{
+		store: store,
+	}
+}
+
+// Handle implements Handler. Request data is unused.
+func (h *TypesHandler) Handle(ctx context.Context, _ Request) (Response, error) {
+	defs, err := h.store.List(ctx)
+	if err != nil {
+		return Response{
+			Success: false,
+			Errors:  []string{fmt.Sprintf("failed to list record types: %v", err)},
+		}, fmt.Errorf("failed to list record types: %w", err)
+	}
+
+	return Response{
+		Success: true,
+		Data:    defs,
+	}, nil
+}
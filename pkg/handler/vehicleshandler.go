@@ -0,0 +1,51 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/kazemisoroush/assistant/pkg/vehicles"
+)
+
+const (
+	// VehiclesCommandType is the command type for vehicle maintenance
+	VehiclesCommandType = "vehicles"
+)
+
+// VehiclesHandler lists vehicles with their service history or sends service-due reminders.
+type VehiclesHandler struct {
+	tracker vehicles.Tracker
+}
+
+// NewVehiclesHandler creates a new vehicles handler.
+func NewVehiclesHandler(tracker vehicles.Tracker) Handler {
+	return &VehiclesHandler{
+		tracker: tracker,
+	}
+}
+
+// Handle implements Handler. Request data is true to send the service
+// reminders that became due, otherwise the vehicles are listed.
+func (h *VehiclesHandler) Handle(ctx context.Context, request Request) (Response, error) {
+	send, _ := request.Data.(bool)
+
+	var data interface{}
+	var err error
+	if send {
+		data, err = h.tracker.Run(ctx, time.Now())
+	} else {
+		data, err = h.tracker.Vehicles(ctx, time.Now())
+	}
+	if err != nil {
+		return Response{
+			Success: false,
+			Errors:  []string{fmt.Sprintf("failed to track vehicles: %v", err)},
+		}, fmt.Errorf("failed to track vehicles: %w", err)
+	}
+
+	return Response{
+		Success: true,
+		Data:    data,
+	}, nil
+}
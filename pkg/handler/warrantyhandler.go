@@ -0,0 +1,44 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/kazemisoroush/assistant/pkg/warranty"
+)
+
+const (
+	// WarrantyCommandType is the command type for warranty lookups
+	WarrantyCommandType = "warranty"
+)
+
+// WarrantyHandler answers whether products are still under warranty.
+type WarrantyHandler struct {
+	checker warranty.Checker
+}
+
+// NewWarrantyHandler creates a new warranty handler.
+func NewWarrantyHandler(checker warranty.Checker) Handler {
+	return &WarrantyHandler{
+		checker: checker,
+	}
+}
+
+// Handle implements Handler. Request data is an optional product query, e.g. "laptop".
+func (h *WarrantyHandler) Handle(ctx context.Context, request Request) (Response, error) {
+	query, _ := request.Data.(string)
+
+	statuses, err := h.checker.Check(ctx, query, time.Now())
+	if err != nil {
+		return Response{
+			Success: false,
+			Errors:  []string{fmt.Sprintf("failed to check warranties: %v", err)},
+		}, fmt.Errorf("failed to check warranties: %w", err)
+	}
+
+	return Response{
+		Success: true,
+		Data:    statuses,
+	}, nil
+}
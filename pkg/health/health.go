@@ -0,0 +1,33 @@
+// Package health builds per-person views over health visit, test and lab records.
+package health
+
+import (
+	"context"
+	"time"
+
+	"github.com/kazemisoroush/assistant/pkg/records"
+)
+
+// Entry represents one health record on a timeline
+type Entry struct {
+	RecordID string             `json:"record_id"`
+	Type     records.RecordType `json:"type"`
+	Date     time.Time          `json:"date"`
+	Provider string             `json:"provider,omitempty"`
+	Summary  string             `json:"summary,omitempty"`
+	Labs     []Entry            `json:"labs,omitempty"` // Tests and labs ordered at a visit
+}
+
+// Timeline represents the chronological health history of one person
+type Timeline struct {
+	Person  string  `json:"person"` // Empty when the patient could not be extracted
+	Entries []Entry `json:"entries"`
+}
+
+// TimelineBuilder assembles health timelines
+//
+//go:generate mockgen -destination=./mocks/mock_timelinebuilder.go -mock_names=TimelineBuilder=MockTimelineBuilder -package=mocks . TimelineBuilder
+type TimelineBuilder interface {
+	// Timelines returns one timeline per person, or only the given person's when person is not empty
+	Timelines(ctx context.Context, person string) ([]Timeline, error)
+}
@@ -0,0 +1,156 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/kazemisoroush/assistant/pkg/records"
+	"github.com/kazemisoroush/assistant/pkg/records/storage"
+)
+
+// healthTypes are the record types shown on a timeline
+var healthTypes = []records.RecordType{
+	records.RecordTypeHealthVisit,
+	records.RecordTypeHealthTest,
+	records.RecordTypeHealthLab,
+}
+
+// orderWindow is how long after a visit an unlinked test or lab is assumed to have been ordered there
+const orderWindow = 14 * 24 * time.Hour
+
+// RecordTimelineBuilder builds timelines from stored health records, per
+// household member a record is attributed to or else per extracted patient.
+// Tests and labs are grouped under the visit they link to, or without a link
+// under the person's most recent visit within orderWindow before them.
+type RecordTimelineBuilder struct {
+	storage storage.Storage
+}
+
+// NewRecordTimelineBuilder creates a new timeline builder
+func NewRecordTimelineBuilder(storage storage.Storage) TimelineBuilder {
+	return &RecordTimelineBuilder{
+		storage: storage,
+	}
+}
+
+// healthRecord is a health record with its decoded metadata
+type healthRecord struct {
+	record records.Record
+	meta   records.HealthMetadata
+}
+
+// Timelines implements TimelineBuilder
+func (b *RecordTimelineBuilder) Timelines(ctx context.Context, person string) ([]Timeline, error) {
+	byPerson := map[string][]healthRecord{}
+	for _, recordType := range healthTypes {
+		recs, err := b.storage.List(ctx, recordType)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list %s records: %w", recordType, err)
+		}
+		for _, rec := range recs {
+			var meta records.HealthMetadata
+			if err := rec.DecodeMetadata(&meta); err != nil {
+				slog.WarnContext(ctx, "Skipping health record with malformed metadata", "record_id", rec.ID, "error", err)
+				continue
+			}
+			if attributed := rec.Person(); attributed != "" {
+				meta.Patient = attributed
+			}
+			if person != "" && !strings.EqualFold(meta.Patient, person) {
+				continue
+			}
+			key := strings.ToLower(meta.Patient)
+			byPerson[key] = append(byPerson[key], healthRecord{record: rec, meta: meta})
+		}
+	}
+
+	timelines := make([]Timeline, 0, len(byPerson))
+	for _, recs := range byPerson {
+		timelines = append(timelines, Timeline{
+			Person:  recs[0].meta.Patient,
+			Entries: entries(recs),
+		})
+	}
+	sort.Slice(timelines, func(i, j int) bool { return timelines[i].Person < timelines[j].Person })
+	return timelines, nil
+}
+
+// entries orders one person's records chronologically, nesting linked tests and labs under their visit
+func entries(recs []healthRecord) []Entry {
+	visits := map[string]*Entry{}
+	for _, rec := range recs {
+		if rec.record.Type == records.RecordTypeHealthVisit {
+			entry := toEntry(rec)
+			visits[rec.record.ID] = &entry
+		}
+	}
+
+	var result []Entry
+	for _, rec := range recs {
+		if rec.record.Type == records.RecordTypeHealthVisit {
+			continue
+		}
+		entry := toEntry(rec)
+		if visit := orderingVisit(rec.record, entry.Date, visits); visit != nil {
+			visit.Labs = append(visit.Labs, entry)
+			continue
+		}
+		result = append(result, entry)
+	}
+	for _, visit := range visits {
+		sortEntries(visit.Labs)
+		result = append(result, *visit)
+	}
+
+	sortEntries(result)
+	return result
+}
+
+// orderingVisit returns the visit a test or lab was ordered at, if known
+func orderingVisit(rec records.Record, date time.Time, visits map[string]*Entry) *Entry {
+	for _, id := range rec.Links() {
+		if visit, ok := visits[id]; ok {
+			return visit
+		}
+	}
+
+	var latest *Entry
+	for _, visit := range visits {
+		if visit.Date.After(date) || date.Sub(visit.Date) > orderWindow {
+			continue
+		}
+		if latest == nil || visit.Date.After(latest.Date) || (visit.Date.Equal(latest.Date) && visit.RecordID < latest.RecordID) {
+			latest = visit
+		}
+	}
+	return latest
+}
+
+// toEntry converts a record into a timeline entry, dating it by the extracted
+// date and falling back to when it was ingested
+func toEntry(rec healthRecord) Entry {
+	date, err := time.Parse(time.DateOnly, rec.meta.Date)
+	if err != nil {
+		date = rec.record.CreatedAt
+	}
+	return Entry{
+		RecordID: rec.record.ID,
+		Type:     rec.record.Type,
+		Date:     date,
+		Provider: rec.meta.Provider,
+		Summary:  rec.meta.Summary,
+	}
+}
+
+func sortEntries(entries []Entry) {
+	sort.SliceStable(entries, func(i, j int) bool {
+		if !entries[i].Date.Equal(entries[j].Date) {
+			return entries[i].Date.Before(entries[j].Date)
+		}
+		return entries[i].RecordID < entries[j].RecordID
+	})
+}
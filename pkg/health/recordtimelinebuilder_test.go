@@ -0,0 +1,77 @@
+package health_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kazemisoroush/assistant/pkg/health"
+	"github.com/kazemisoroush/assistant/pkg/records"
+	"github.com/kazemisoroush/assistant/pkg/records/storage/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func healthRecord(id string, recordType records.RecordType, patient, date string, links ...string) records.Record {
+	metadata := map[string]interface{}{"patient": patient, "provider": "Dr. Schmidt", "date": date}
+	if len(links) > 0 {
+		metadata[records.MetaLinks] = links
+	}
+	return records.Record{ID: id, Type: recordType, Metadata: metadata}
+}
+
+func TestRecordTimelineBuilder_Timelines(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	storage := mocks.NewMockStorage(ctrl)
+	storage.EXPECT().List(gomock.Any(), records.RecordTypeHealthVisit).Return([]records.Record{
+		healthRecord("visit-1", records.RecordTypeHealthVisit, "Anna", "2024-02-01"),
+		healthRecord("visit-2", records.RecordTypeHealthVisit, "Anna", "2024-05-10"),
+		healthRecord("visit-3", records.RecordTypeHealthVisit, "Ben", "2024-03-01"),
+	}, nil)
+	storage.EXPECT().List(gomock.Any(), records.RecordTypeHealthTest).Return([]records.Record{
+		healthRecord("test-1", records.RecordTypeHealthTest, "Anna", "2024-03-20"),
+	}, nil)
+	storage.EXPECT().List(gomock.Any(), records.RecordTypeHealthLab).Return([]records.Record{
+		healthRecord("lab-linked", records.RecordTypeHealthLab, "Anna", "2024-06-30", "visit-1"),
+		healthRecord("lab-nearby", records.RecordTypeHealthLab, "Anna", "2024-05-15"),
+	}, nil)
+	builder := health.NewRecordTimelineBuilder(storage)
+
+	// Act
+	timelines, err := builder.Timelines(context.Background(), "anna")
+
+	// Assert
+	require.NoError(t, err, "Timelines() error should be nil")
+	require.Len(t, timelines, 1, "Timelines() should only include the requested person")
+	entries := timelines[0].Entries
+	require.Len(t, entries, 3, "Timelines() should nest labs under their visits")
+	assert.Equal(t, "visit-1", entries[0].RecordID, "Timelines() should order entries chronologically")
+	assert.Equal(t, "lab-linked", entries[0].Labs[0].RecordID, "Timelines() should group labs under the linked visit")
+	assert.Equal(t, "test-1", entries[1].RecordID, "Timelines() should keep tests without an ordering visit on the timeline")
+	assert.Equal(t, "lab-nearby", entries[2].Labs[0].RecordID, "Timelines() should group unlinked labs under a recent visit")
+}
+
+func TestRecordTimelineBuilder_Timelines_AttributedPerson(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	storage := mocks.NewMockStorage(ctrl)
+	checkup := healthRecord("checkup", records.RecordTypeHealthVisit, "", "2024-02-01")
+	checkup.Metadata[records.MetaPerson] = "Lena Schmidt"
+	storage.EXPECT().List(gomock.Any(), records.RecordTypeHealthVisit).Return([]records.Record{
+		checkup,
+		healthRecord("visit", records.RecordTypeHealthVisit, "Lena Schmidt", "2024-04-01"),
+	}, nil)
+	storage.EXPECT().List(gomock.Any(), records.RecordTypeHealthTest).Return(nil, nil)
+	storage.EXPECT().List(gomock.Any(), records.RecordTypeHealthLab).Return(nil, nil)
+	builder := health.NewRecordTimelineBuilder(storage)
+
+	// Act
+	timelines, err := builder.Timelines(context.Background(), "")
+
+	// Assert
+	require.NoError(t, err, "Timelines() error should be nil")
+	require.Len(t, timelines, 1, "Timelines() should group attributed records with the person's other records")
+	assert.Equal(t, "Lena Schmidt", timelines[0].Person, "Timelines() should name the household member")
+	assert.Len(t, timelines[0].Entries, 2, "Timelines() should include the attributed record")
+}
@@ -0,0 +1,108 @@
+// Package household keeps the family members records can be attributed to,
+// distinct from the users of the application.
+package household
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/kazemisoroush/assistant/pkg/records"
+	"github.com/kazemisoroush/assistant/pkg/records/storage"
+)
+
+// Member represents a person in the household
+type Member struct {
+	Name         string   `json:"name"`
+	Relationship string   `json:"relationship,omitempty"` // e.g. self, spouse, child
+	Aliases      []string `json:"aliases,omitempty"`      // Other names on documents, e.g. a nickname or maiden name
+}
+
+// Validate checks that the member is named
+func (m Member) Validate() error {
+	if strings.TrimSpace(m.Name) == "" {
+		return errors.New("name is required")
+	}
+	return nil
+}
+
+// Names returns the name and aliases the member may appear under
+func (m Member) Names() []string {
+	return append([]string{m.Name}, m.Aliases...)
+}
+
+// Assignment represents the attribution of a record to a household member
+type Assignment struct {
+	RecordID string `json:"record_id"`
+	Person   string `json:"person"`
+}
+
+// Store keeps the household members
+//
+//go:generate mockgen -destination=./mocks/mock_store.go -mock_names=Store=MockStore -package=mocks . Store
+type Store interface {
+	// Save adds or replaces a member
+	Save(ctx context.Context, member Member) error
+
+	// Delete removes a member by name
+	Delete(ctx context.Context, name string) error
+
+	// List returns all members ordered by name
+	List(ctx context.Context) ([]Member, error)
+}
+
+// Detector attributes records to household members
+//
+//go:generate mockgen -destination=./mocks/mock_detector.go -mock_names=Detector=MockDetector -package=mocks . Detector
+type Detector interface {
+	// Detect returns the name of the member the record is about, or "" when it cannot tell
+	Detect(ctx context.Context, rec records.Record) (string, error)
+}
+
+// Add validates the member and saves it to the store
+func Add(ctx context.Context, store Store, member Member) (Member, error) {
+	member.Name = strings.TrimSpace(member.Name)
+	if err := member.Validate(); err != nil {
+		return Member{}, fmt.Errorf("invalid household member: %w", err)
+	}
+	if err := store.Save(ctx, member); err != nil {
+		return Member{}, err
+	}
+	return member, nil
+}
+
+// Find returns the member with the given name, ignoring case
+func Find(ctx context.Context, store Store, name string) (Member, error) {
+	members, err := store.List(ctx)
+	if err != nil {
+		return Member{}, fmt.Errorf("failed to list household members: %w", err)
+	}
+	for _, member := range members {
+		if strings.EqualFold(member.Name, strings.TrimSpace(name)) {
+			return member, nil
+		}
+	}
+	return Member{}, fmt.Errorf("household member not found: %s", name)
+}
+
+// Assign attributes a stored record to a household member
+func Assign(ctx context.Context, store Store, recordStorage storage.Storage, recordID, name string) (records.Record, error) {
+	member, err := Find(ctx, store, name)
+	if err != nil {
+		return records.Record{}, err
+	}
+	rec, err := recordStorage.Get(ctx, recordID)
+	if err != nil {
+		return records.Record{}, fmt.Errorf("failed to get record %s: %w", recordID, err)
+	}
+
+	if rec.Metadata == nil {
+		rec.Metadata = map[string]interface{}{}
+	}
+	rec.Metadata[records.MetaPerson] = member.Name
+	if err := recordStorage.Update(ctx, rec); err != nil {
+		return records.Record{}, fmt.Errorf("failed to assign record %s: %w", recordID, err)
+	}
+	return rec, nil
+}
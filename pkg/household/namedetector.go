@@ -0,0 +1,87 @@
+package household
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/kazemisoroush/assistant/pkg/records"
+)
+
+// NameDetector attributes a record to the member whose name or aliases it
+// mentions most. The extracted patient of health records is checked before
+// the content. Records mentioning several members equally often are left
+// unattributed.
+type NameDetector struct {
+	store Store
+}
+
+// NewNameDetector creates a detector matching the members of the store
+func NewNameDetector(store Store) Detector {
+	return &NameDetector{
+		store: store,
+	}
+}
+
+// Detect implements Detector
+func (d *NameDetector) Detect(ctx context.Context, rec records.Record) (string, error) {
+	members, err := d.store.List(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to list household members: %w", err)
+	}
+	if len(members) == 0 {
+		return "", nil
+	}
+
+	if patient, _ := rec.Metadata["patient"].(string); patient != "" {
+		if name := mostMentioned(members, patient); name != "" {
+			return name, nil
+		}
+	}
+	return mostMentioned(members, rec.Content), nil
+}
+
+// mostMentioned returns the member named most often in the text, or "" on a tie or no mention
+func mostMentioned(members []Member, text string) string {
+	textWords := words(text)
+	best, bestCount, tie := "", 0, false
+	for _, member := range members {
+		count := 0
+		for _, name := range member.Names() {
+			count += occurrences(textWords, words(name))
+		}
+		switch {
+		case count > bestCount:
+			best, bestCount, tie = member.Name, count, false
+		case count == bestCount && count > 0:
+			tie = true
+		}
+	}
+	if tie {
+		return ""
+	}
+	return best
+}
+
+// occurrences counts how often the phrase appears as consecutive words of the text
+func occurrences(text, phrase []string) int {
+	if len(phrase) == 0 {
+		return 0
+	}
+	count := 0
+	for i := 0; i+len(phrase) <= len(text); i++ {
+		if slices.Equal(text[i:i+len(phrase)], phrase) {
+			count++
+		}
+	}
+	return count
+}
+
+// words splits text into lowercase words
+func words(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
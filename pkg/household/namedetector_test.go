@@ -0,0 +1,69 @@
+package household_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kazemisoroush/assistant/pkg/household"
+	"github.com/kazemisoroush/assistant/pkg/household/mocks"
+	"github.com/kazemisoroush/assistant/pkg/records"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+// members is the household the detector tests attribute records to
+var members = []household.Member{
+	{Name: "Anna Schmidt", Relationship: "spouse", Aliases: []string{"Anna Weber"}},
+	{Name: "Max Schmidt", Relationship: "self"},
+	{Name: "Lena Schmidt", Relationship: "child", Aliases: []string{"Lenchen"}},
+}
+
+func TestNameDetector_Detect(t *testing.T) {
+	tests := []struct {
+		name   string
+		record records.Record
+		want   string
+	}{
+		{
+			name:   "patient metadata",
+			record: records.Record{Content: "Max Schmidt paid the invoice.", Metadata: map[string]interface{}{"patient": "LENA SCHMIDT"}},
+			want:   "Lena Schmidt",
+		},
+		{
+			name:   "alias in content",
+			record: records.Record{Content: "Vaccination card of Lenchen, born 2019. Lenchen received MMR."},
+			want:   "Lena Schmidt",
+		},
+		{
+			name:   "most mentioned",
+			record: records.Record{Content: "Policy holder: Anna Weber. Insured: Anna Weber. Contact: Max Schmidt."},
+			want:   "Anna Schmidt",
+		},
+		{
+			name:   "tie",
+			record: records.Record{Content: "Joint account of Anna Schmidt and Max Schmidt."},
+			want:   "",
+		},
+		{
+			name:   "surname only",
+			record: records.Record{Content: "Dear Family Schmidt"},
+			want:   "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			store := mocks.NewMockStore(gomock.NewController(t))
+			store.EXPECT().List(gomock.Any()).Return(members, nil)
+			detector := household.NewNameDetector(store)
+
+			// Act
+			person, err := detector.Detect(context.Background(), tt.record)
+
+			// Assert
+			require.NoError(t, err, "Detect() error should be nil")
+			assert.Equal(t, tt.want, person, "Detect() should attribute the record to the most mentioned member")
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package household
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/kazemisoroush/assistant/pkg/sqlite"
+)
+
+// SQLiteStore is a Store backed by SQLite
+type SQLiteStore struct {
+	db *sqlite.DB
+}
+
+// NewSQLiteStore creates a new SQLite household store in the given database
+func NewSQLiteStore(db *sqlite.DB) (*SQLiteStore, error) {
+	schema := `
+    CREATE TABLE IF NOT EXISTS household_members (
+        name TEXT PRIMARY KEY COLLATE NOCASE,
+        relationship TEXT NOT NULL DEFAULT '',
+        aliases TEXT NOT NULL DEFAULT '[]'
+    );
+    `
+	if _, err := db.Exec(schema); err != nil {
+		return nil, fmt.Errorf("failed to initialize household schema: %w", err)
+	}
+
+	return &SQLiteStore{db: db}, nil
+}
+
+// Save implements Store
+func (s *SQLiteStore) Save(ctx context.Context, member Member) error {
+	aliases, err := json.Marshal(member.Aliases)
+	if err != nil {
+		return fmt.Errorf("failed to marshal aliases of %s: %w", member.Name, err)
+	}
+	if _, err := s.db.ExecContext(ctx,
+		`INSERT INTO household_members (name, relationship, aliases) VALUES (?, ?, ?)
+         ON CONFLICT (name) DO UPDATE SET relationship = excluded.relationship, aliases = excluded.aliases`,
+		member.Name, member.Relationship, string(aliases),
+	); err != nil {
+		return fmt.Errorf("failed to save household member %s: %w", member.Name, err)
+	}
+	return nil
+}
+
+// Delete implements Store
+func (s *SQLiteStore) Delete(ctx context.Context, name string) error {
+	result, err := s.db.ExecContext(ctx, `DELETE FROM household_members WHERE name = ?`, name)
+	if err != nil {
+		return fmt.Errorf("failed to delete household member %s: %w", name, err)
+	}
+	if n, err := result.RowsAffected(); err == nil && n == 0 {
+		return fmt.Errorf("household member not found: %s", name)
+	}
+	return nil
+}
+
+// List implements Store
+func (s *SQLiteStore) List(ctx context.Context) ([]Member, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT name, relationship, aliases FROM household_members ORDER BY name`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list household members: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var members []Member
+	for rows.Next() {
+		var member Member
+		var aliases string
+		if err := rows.Scan(&member.Name, &member.Relationship, &aliases); err != nil {
+			return nil, fmt.Errorf("failed to scan household member: %w", err)
+		}
+		if err := json.Unmarshal([]byte(aliases), &member.Aliases); err != nil {
+			return nil, fmt.Errorf("failed to decode aliases of %s: %w", member.Name, err)
+		}
+		members = append(members, member)
+	}
+	return members, rows.Err()
+}
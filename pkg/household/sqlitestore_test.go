@@ -0,0 +1,73 @@
+package household_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/kazemisoroush/assistant/pkg/household"
+	"github.com/kazemisoroush/assistant/pkg/records"
+	storagemocks "github.com/kazemisoroush/assistant/pkg/records/storage/mocks"
+	"github.com/kazemisoroush/assistant/pkg/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+// openDB opens the database at path, closed once the test is done
+func openDB(t testing.TB, path string) *sqlite.DB {
+	t.Helper()
+	db, err := sqlite.Open(path)
+	require.NoError(t, err, "Open() error should be nil")
+	t.Cleanup(func() { _ = db.Close() })
+	return db
+}
+
+func TestSQLiteStore_AddListDelete(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	store, err := household.NewSQLiteStore(openDB(t, filepath.Join(t.TempDir(), "assistant.db")))
+	require.NoError(t, err, "NewSQLiteStore() error should be nil")
+	lena := household.Member{Name: " Lena Schmidt ", Relationship: "child", Aliases: []string{"Lenchen"}}
+
+	// Act
+	added, err := household.Add(ctx, store, lena)
+
+	// Assert
+	require.NoError(t, err, "Add() error should be nil")
+	assert.Equal(t, "Lena Schmidt", added.Name, "Add() should trim the name")
+	members, err := store.List(ctx)
+	require.NoError(t, err, "List() error should be nil")
+	assert.Equal(t, []household.Member{added}, members, "List() should return the saved member")
+
+	require.NoError(t, store.Delete(ctx, "lena schmidt"), "Delete() should match names ignoring case")
+	members, err = store.List(ctx)
+	require.NoError(t, err, "List() error should be nil")
+	assert.Empty(t, members, "Delete() should remove the member")
+
+	_, err = household.Add(ctx, store, household.Member{Name: "  "})
+	assert.Error(t, err, "Add() should reject an unnamed member")
+}
+
+func TestAssign(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	store, err := household.NewSQLiteStore(openDB(t, filepath.Join(t.TempDir(), "assistant.db")))
+	require.NoError(t, err, "NewSQLiteStore() error should be nil")
+	_, err = household.Add(ctx, store, household.Member{Name: "Lena Schmidt"})
+	require.NoError(t, err, "Add() error should be nil")
+
+	recordStorage := storagemocks.NewMockStorage(gomock.NewController(t))
+	recordStorage.EXPECT().Get(gomock.Any(), "vaccination").Return(records.Record{ID: "vaccination", Type: records.RecordTypeHealthVisit}, nil)
+	recordStorage.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil)
+
+	// Act
+	rec, err := household.Assign(ctx, store, recordStorage, "vaccination", "lena schmidt")
+
+	// Assert
+	require.NoError(t, err, "Assign() error should be nil")
+	assert.Equal(t, "Lena Schmidt", rec.Person(), "Assign() should attribute the record to the member")
+
+	_, err = household.Assign(ctx, store, recordStorage, "vaccination", "Tom")
+	assert.Error(t, err, "Assign() should reject unknown members")
+}
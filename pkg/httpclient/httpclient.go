@@ -0,0 +1,60 @@
+// Package httpclient builds the HTTP client shared by all outbound calls.
+package httpclient
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// Config represents the tuning options applied to outbound HTTP calls
+type Config struct {
+	Timeout            time.Duration // Overall timeout per request, including retries; 0 leaves requests bounded by their context only
+	ProxyURL           string        // Optional proxy; empty uses the environment (HTTP_PROXY etc.)
+	MaxRetries         int           // Retries for transport errors, 429 and 5xx responses
+	InsecureSkipVerify bool          // Skip TLS verification for self-hosted services
+
+	// Connection pooling; zero values keep the net/http defaults
+	MaxIdleConns        int           // Idle keep-alive connections kept across all hosts
+	MaxIdleConnsPerHost int           // Idle keep-alive connections kept per host, e.g. Ollama
+	IdleConnTimeout     time.Duration // How long an idle connection is kept
+}
+
+// New creates an HTTP client from the given configuration
+func New(cfg Config) (*http.Client, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if cfg.MaxIdleConns > 0 {
+		transport.MaxIdleConns = cfg.MaxIdleConns
+	}
+	if cfg.MaxIdleConnsPerHost > 0 {
+		transport.MaxIdleConnsPerHost = cfg.MaxIdleConnsPerHost
+	}
+	if cfg.IdleConnTimeout > 0 {
+		transport.IdleConnTimeout = cfg.IdleConnTimeout
+	}
+
+	if cfg.ProxyURL != "" {
+		proxyURL, err := url.Parse(cfg.ProxyURL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy URL: %w", err)
+		}
+		transport.Proxy = http.ProxyURL(proxyURL)
+	}
+
+	if cfg.InsecureSkipVerify {
+		// Explicitly opted in for self-hosted services with self-signed certificates
+		transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+	}
+
+	var roundTripper http.RoundTripper = transport
+	if cfg.MaxRetries > 0 {
+		roundTripper = newRetryTransport(transport, cfg.MaxRetries)
+	}
+
+	return &http.Client{
+		Timeout:   cfg.Timeout,
+		Transport: roundTripper,
+	}, nil
+}
@@ -0,0 +1,66 @@
+package httpclient
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew_RetriesServerErrors(t *testing.T) {
+	// Arrange
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client, err := New(Config{Timeout: 5 * time.Second, MaxRetries: 2})
+	require.NoError(t, err, "New() error should be nil")
+
+	// Act
+	resp, err := client.Post(server.URL, "text/plain", strings.NewReader("payload"))
+
+	// Assert
+	require.NoError(t, err, "Post() error should be nil")
+	defer func() { _ = resp.Body.Close() }()
+	assert.Equal(t, http.StatusOK, resp.StatusCode, "Post() should succeed after retries")
+	assert.Equal(t, int32(3), calls.Load(), "server should be called once plus two retries")
+}
+
+func TestNew_InvalidProxy(t *testing.T) {
+	// Arrange
+	cfg := Config{ProxyURL: "://bad"}
+
+	// Act
+	_, err := New(cfg)
+
+	// Assert
+	require.Error(t, err, "New() should fail for an invalid proxy URL")
+}
+
+func TestNew_PoolsConnections(t *testing.T) {
+	// Arrange
+	cfg := Config{MaxIdleConns: 50, MaxIdleConnsPerHost: 8, IdleConnTimeout: 30 * time.Second}
+
+	// Act
+	client, err := New(cfg)
+
+	// Assert
+	require.NoError(t, err, "New() error should be nil")
+	transport, ok := client.Transport.(*http.Transport)
+	require.True(t, ok, "New() should use the transport directly without retries")
+	assert.Equal(t, 50, transport.MaxIdleConns, "New() should set the idle connections")
+	assert.Equal(t, 8, transport.MaxIdleConnsPerHost, "New() should set the idle connections per host")
+	assert.Equal(t, 30*time.Second, transport.IdleConnTimeout, "New() should set the idle connection timeout")
+	assert.Zero(t, client.Timeout, "New() should leave requests bounded by their context without a timeout")
+}
@@ -0,0 +1,68 @@
+package httpclient
+
+import (
+	"io"
+	"net/http"
+	"time"
+)
+
+// retryBaseDelay is the delay before the first retry; it doubles on each attempt
+const retryBaseDelay = 200 * time.Millisecond
+
+// retryTransport retries requests that fail with a transport error, 429 or 5xx
+type retryTransport struct {
+	next       http.RoundTripper
+	maxRetries int
+}
+
+func newRetryTransport(next http.RoundTripper, maxRetries int) http.RoundTripper {
+	return &retryTransport{
+		next:       next,
+		maxRetries: maxRetries,
+	}
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	delay := retryBaseDelay
+
+	for attempt := 0; ; attempt++ {
+		resp, err := t.next.RoundTrip(req)
+		if attempt >= t.maxRetries || !shouldRetry(resp, err) || !rewindable(req) {
+			return resp, err
+		}
+
+		if resp != nil {
+			// Drain so the connection can be reused
+			_, _ = io.Copy(io.Discard, resp.Body)
+			_ = resp.Body.Close()
+		}
+
+		select {
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		case <-time.After(delay):
+		}
+		delay *= 2
+
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req.Body = body
+		}
+	}
+}
+
+func shouldRetry(resp *http.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+	return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= http.StatusInternalServerError
+}
+
+// rewindable reports whether the request body can be replayed for another attempt
+func rewindable(req *http.Request) bool {
+	return req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+}
@@ -0,0 +1,152 @@
+package imports
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"mime"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+var (
+	// htmlTitle captures the title of an HTML note
+	htmlTitle = regexp.MustCompile(`(?is)<title[^>]*>(.*?)</title>`)
+
+	// htmlMeta captures the name and content of a meta tag
+	htmlMeta = regexp.MustCompile(`(?is)<meta\s+name="([^"]+)"\s+content="([^"]*)"`)
+
+	// htmlImage captures the source of an embedded image
+	htmlImage = regexp.MustCompile(`(?is)<img[^>]+src="([^"]+)"`)
+
+	// dataURI captures the media type and base64 data of a data URI
+	dataURI = regexp.MustCompile(`(?s)^data:([^;,]+);base64,(.*)$`)
+)
+
+// AppleNotesReader reads notes exported from Apple Notes into a directory,
+// one file per note as written by the common export tools: HTML with the
+// images embedded as data URIs or next to the note, or plain text and
+// Markdown. A note is dated by its "created" and "modified" meta tags when it
+// has them, otherwise by the file's modification time. Folders become tags.
+type AppleNotesReader struct {
+	dir string
+}
+
+// NewAppleNotesReader creates a reader of the notes exported into dir
+func NewAppleNotesReader(dir string) Reader {
+	return &AppleNotesReader{dir: dir}
+}
+
+// Name implements Reader
+func (r *AppleNotesReader) Name() string {
+	return "apple-notes"
+}
+
+// Notes implements Reader
+func (r *AppleNotesReader) Notes(ctx context.Context, fn func(Note) error) error {
+	return filepath.WalkDir(r.dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		ext := strings.ToLower(filepath.Ext(path))
+		if d.IsDir() || (ext != ".html" && ext != ".htm" && ext != ".txt" && ext != ".md") {
+			return nil
+		}
+		note, err := r.read(path, ext)
+		if err != nil {
+			return err
+		}
+		return fn(note)
+	})
+}
+
+// read reads the note in the file
+func (r *AppleNotesReader) read(path, ext string) (Note, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Note{}, fmt.Errorf("failed to read note %s: %w", path, err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return Note{}, fmt.Errorf("failed to stat note %s: %w", path, err)
+	}
+
+	rel, _ := filepath.Rel(r.dir, path)
+	sum := sha256.Sum256([]byte(filepath.ToSlash(rel)))
+	note := Note{
+		ID:      hex.EncodeToString(sum[:8]),
+		Title:   strings.TrimSuffix(filepath.Base(path), filepath.Ext(path)),
+		Text:    string(data),
+		Created: info.ModTime(),
+		Updated: info.ModTime(),
+	}
+	if folder := filepath.Dir(rel); folder != "." {
+		note.Tags = strings.Split(filepath.ToSlash(folder), "/")
+	}
+	if ext == ".html" || ext == ".htm" {
+		readHTML(&note, string(data), filepath.Dir(path))
+	}
+	return note, nil
+}
+
+// readHTML reads the title, dates, text and images of an HTML note
+func readHTML(note *Note, html, dir string) {
+	if match := htmlTitle.FindStringSubmatch(html); match != nil && strings.TrimSpace(match[1]) != "" {
+		note.Title = strings.TrimSpace(match[1])
+	}
+	for _, match := range htmlMeta.FindAllStringSubmatch(html, -1) {
+		date, err := time.Parse(time.RFC3339, match[2])
+		if err != nil {
+			continue
+		}
+		switch strings.ToLower(match[1]) {
+		case "created":
+			note.Created = date
+		case "modified", "updated":
+			note.Updated = date
+		}
+	}
+	note.Text = markupText(html)
+
+	for i, match := range htmlImage.FindAllStringSubmatch(html, -1) {
+		if attachment, ok := image(match[1], dir, i); ok {
+			note.Attachments = append(note.Attachments, attachment)
+		}
+	}
+}
+
+// image reads an embedded image from a data URI or a file next to the note.
+// Remote and unreadable images are skipped.
+func image(src, dir string, i int) (Attachment, bool) {
+	if match := dataURI.FindStringSubmatch(src); match != nil {
+		data, err := base64.StdEncoding.DecodeString(match[2])
+		if err != nil {
+			return Attachment{}, false
+		}
+		return Attachment{Name: fmt.Sprintf("image-%d", i+1), MediaType: match[1], Data: data}, true
+	}
+	if strings.Contains(src, "://") {
+		return Attachment{}, false
+	}
+
+	name, err := url.PathUnescape(src)
+	if err != nil {
+		return Attachment{}, false
+	}
+	path := filepath.Join(dir, filepath.FromSlash(name))
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Attachment{}, false
+	}
+	return Attachment{Name: filepath.Base(path), MediaType: mime.TypeByExtension(filepath.Ext(path)), Data: data}, true
+}
@@ -0,0 +1,58 @@
+package imports_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/kazemisoroush/assistant/pkg/imports"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAppleNotesReader_Notes(t *testing.T) {
+	// Arrange
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "Travel"), 0o700), "failed to create folder")
+	html := `<html><head><title>Flight to Lisbon</title>
+<meta name="created" content="2018-06-01T12:00:00Z">
+<meta name="modified" content="2018-06-02T08:30:00Z">
+<style>body { font-family: sans-serif; }</style></head>
+<body><div>Booking ref ABC123</div><div>Seat 14C</div>
+<img src="data:image/png;base64,iVBORw0KGgo=">
+<img src="boarding%20pass.jpg">
+<img src="https://example.com/logo.png"></body></html>`
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "Travel", "Flight.html"), []byte(html), 0o600), "failed to write note")
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "Travel", "boarding pass.jpg"), []byte("jpeg"), 0o600), "failed to write image")
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "Groceries.txt"), []byte("Milk\nEggs"), 0o600), "failed to write note")
+	reader := imports.NewAppleNotesReader(dir)
+
+	// Act
+	notes := map[string]imports.Note{}
+	err := reader.Notes(context.Background(), func(note imports.Note) error {
+		notes[note.Title] = note
+		return nil
+	})
+
+	// Assert
+	require.NoError(t, err, "Notes() should read the export")
+	require.Len(t, notes, 2, "Notes() should read every note and skip other files")
+
+	flight := notes["Flight to Lisbon"]
+	assert.Equal(t, "Booking ref ABC123\nSeat 14C", flight.Text, "Notes() should read the body text without markup")
+	assert.Equal(t, time.Date(2018, 6, 1, 12, 0, 0, 0, time.UTC), flight.Created, "Notes() should read the creation date")
+	assert.Equal(t, time.Date(2018, 6, 2, 8, 30, 0, 0, time.UTC), flight.Updated, "Notes() should read the modification date")
+	assert.Equal(t, []string{"Travel"}, flight.Tags, "Notes() should tag notes by their folder")
+	require.Len(t, flight.Attachments, 2, "Notes() should read embedded and local images and skip remote ones")
+	assert.Equal(t, "image/png", flight.Attachments[0].MediaType, "Notes() should read the data URI's media type")
+	assert.Equal(t, []byte("\x89PNG\r\n\x1a\n"), flight.Attachments[0].Data, "Notes() should decode the data URI")
+	assert.Equal(t, "boarding pass.jpg", flight.Attachments[1].Name, "Notes() should read images next to the note")
+	assert.Equal(t, []byte("jpeg"), flight.Attachments[1].Data, "Notes() should read images next to the note")
+
+	groceries := notes["Groceries"]
+	assert.Equal(t, "Milk\nEggs", groceries.Text, "Notes() should read text notes as they are")
+	assert.False(t, groceries.Created.IsZero(), "Notes() should date notes without dates by the file")
+	assert.Empty(t, groceries.Tags, "Notes() should not tag notes outside folders")
+}
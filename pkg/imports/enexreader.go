@@ -0,0 +1,133 @@
+package imports
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+// enexTimeLayout is how ENEX exports format dates
+const enexTimeLayout = "20060102T150405Z"
+
+// enexNote is a note element of an ENEX export
+type enexNote struct {
+	Title     string         `xml:"title"`
+	Content   string         `xml:"content"` // ENML
+	Created   string         `xml:"created"`
+	Updated   string         `xml:"updated"`
+	Tags      []string       `xml:"tag"`
+	Resources []enexResource `xml:"resource"`
+}
+
+// enexResource is a file embedded in an ENEX note
+type enexResource struct {
+	Data     string `xml:"data"` // Base64 encoded
+	Mime     string `xml:"mime"`
+	FileName string `xml:"resource-attributes>file-name"`
+}
+
+// ENEXReader reads the notes of an Evernote ENEX export. Notes are decoded
+// one at a time, so exports larger than memory can be imported.
+type ENEXReader struct {
+	path string
+}
+
+// NewENEXReader creates a reader of the ENEX file at path
+func NewENEXReader(path string) Reader {
+	return &ENEXReader{path: path}
+}
+
+// Name implements Reader
+func (r *ENEXReader) Name() string {
+	return "evernote"
+}
+
+// Notes implements Reader
+func (r *ENEXReader) Notes(ctx context.Context, fn func(Note) error) error {
+	f, err := os.Open(r.path)
+	if err != nil {
+		return fmt.Errorf("failed to open ENEX export: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	decoder := xml.NewDecoder(f)
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		raw, err := nextNote(decoder)
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		note, err := raw.note()
+		if err != nil {
+			return fmt.Errorf("failed to read ENEX note %q: %w", raw.Title, err)
+		}
+		if err := fn(note); err != nil {
+			return err
+		}
+	}
+}
+
+// nextNote decodes the next note element, returning io.EOF after the last
+func nextNote(decoder *xml.Decoder) (enexNote, error) {
+	for {
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			return enexNote{}, err
+		}
+		if err != nil {
+			return enexNote{}, fmt.Errorf("failed to read ENEX export: %w", err)
+		}
+		if start, ok := token.(xml.StartElement); ok && start.Name.Local == "note" {
+			var raw enexNote
+			if err := decoder.DecodeElement(&raw, &start); err != nil {
+				return enexNote{}, fmt.Errorf("failed to decode ENEX note: %w", err)
+			}
+			return raw, nil
+		}
+	}
+}
+
+// note converts the ENEX note, decoding its resources
+func (n enexNote) note() (Note, error) {
+	created, _ := time.Parse(enexTimeLayout, n.Created)
+	updated, err := time.Parse(enexTimeLayout, n.Updated)
+	if err != nil {
+		updated = created
+	}
+	note := Note{
+		ID:      enexID(n),
+		Title:   strings.TrimSpace(n.Title),
+		Text:    markupText(n.Content),
+		Created: created,
+		Updated: updated,
+		Tags:    n.Tags,
+	}
+	for i, resource := range n.Resources {
+		data, err := base64.StdEncoding.DecodeString(strings.Join(strings.Fields(resource.Data), ""))
+		if err != nil {
+			return Note{}, fmt.Errorf("failed to decode resource %d: %w", i, err)
+		}
+		note.Attachments = append(note.Attachments, Attachment{Name: resource.FileName, MediaType: resource.Mime, Data: data})
+	}
+	return note, nil
+}
+
+// enexID identifies a note by its title and creation date, which ENEX exports
+// keep across exports; the export has no note IDs
+func enexID(n enexNote) string {
+	sum := sha256.Sum256([]byte(n.Title + "\x00" + n.Created))
+	return hex.EncodeToString(sum[:8])
+}
@@ -0,0 +1,99 @@
+package imports_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/kazemisoroush/assistant/pkg/imports"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const enexExport = `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE en-export SYSTEM "http://xml.evernote.com/pub/evernote-export3.dtd">
+<en-export export-date="20240101T000000Z" application="Evernote">
+  <note>
+    <title>Dishwasher warranty</title>
+    <content><![CDATA[<?xml version="1.0" encoding="UTF-8"?><en-note><div>Bought at Kitchen World</div><div>Warranty &amp; receipt attached</div><en-media type="image/png" hash="abc"/></en-note>]]></content>
+    <created>20190314T091500Z</created>
+    <updated>20190315T100000Z</updated>
+    <tag>home</tag>
+    <resource>
+      <data encoding="base64">iVBORw0K
+GgoAAAAN</data>
+      <mime>image/png</mime>
+      <resource-attributes><file-name>receipt.png</file-name></resource-attributes>
+    </resource>
+  </note>
+  <note>
+    <title>Packing list</title>
+    <content><![CDATA[<en-note><ul><li>Passport</li><li>Charger</li></ul></en-note>]]></content>
+    <created>20200101T080000Z</created>
+  </note>
+</en-export>`
+
+func TestENEXReader_Notes(t *testing.T) {
+	// Arrange
+	path := filepath.Join(t.TempDir(), "export.enex")
+	require.NoError(t, os.WriteFile(path, []byte(enexExport), 0o600), "failed to write export")
+	reader := imports.NewENEXReader(path)
+
+	// Act
+	var notes []imports.Note
+	err := reader.Notes(context.Background(), func(note imports.Note) error {
+		notes = append(notes, note)
+		return nil
+	})
+
+	// Assert
+	require.NoError(t, err, "Notes() should read the export")
+	require.Len(t, notes, 2, "Notes() should read every note")
+	note := notes[0]
+	assert.Equal(t, "Dishwasher warranty", note.Title, "Notes() should read the title")
+	assert.Equal(t, "Bought at Kitchen World\nWarranty & receipt attached", note.Text, "Notes() should read the text without markup")
+	assert.Equal(t, time.Date(2019, 3, 14, 9, 15, 0, 0, time.UTC), note.Created, "Notes() should read the creation date")
+	assert.Equal(t, time.Date(2019, 3, 15, 10, 0, 0, 0, time.UTC), note.Updated, "Notes() should read the update date")
+	assert.Equal(t, []string{"home"}, note.Tags, "Notes() should read the tags")
+	require.Len(t, note.Attachments, 1, "Notes() should read the resources")
+	assert.Equal(t, "receipt.png", note.Attachments[0].Name, "Notes() should read the resource's file name")
+	assert.Equal(t, "image/png", note.Attachments[0].MediaType, "Notes() should read the resource's media type")
+	assert.Equal(t, []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\x0d"), note.Attachments[0].Data, "Notes() should decode the resource")
+	assert.Equal(t, "Passport\nCharger", notes[1].Text, "Notes() should end a line after every list item")
+	assert.Equal(t, notes[1].Created, notes[1].Updated, "Notes() should date notes never updated by their creation")
+	assert.NotEqual(t, notes[0].ID, notes[1].ID, "Notes() should identify every note")
+}
+
+func TestENEXReader_Notes_StableIDs(t *testing.T) {
+	// Arrange
+	path := filepath.Join(t.TempDir(), "export.enex")
+	require.NoError(t, os.WriteFile(path, []byte(enexExport), 0o600), "failed to write export")
+	reader := imports.NewENEXReader(path)
+	ids := func() []string {
+		var ids []string
+		require.NoError(t, reader.Notes(context.Background(), func(note imports.Note) error {
+			ids = append(ids, note.ID)
+			return nil
+		}), "failed to read export")
+		return ids
+	}
+
+	// Act
+	first, second := ids(), ids()
+
+	// Assert
+	assert.Equal(t, first, second, "Notes() should identify notes the same across imports")
+}
+
+func TestENEXReader_Notes_MissingFile(t *testing.T) {
+	// Arrange
+	reader := imports.NewENEXReader(filepath.Join(t.TempDir(), "missing.enex"))
+
+	// Act
+	err := reader.Notes(context.Background(), func(imports.Note) error { return nil })
+
+	// Assert
+	assert.Error(t, err, "Notes() should fail when the export is missing")
+}
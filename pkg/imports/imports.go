@@ -0,0 +1,37 @@
+// Package imports brings notes exported from note-taking applications into
+// the vault. Every note's text and every image or PDF embedded in it is
+// extracted into a typed record dated like the note, so a note taken years
+// ago keeps its date.
+package imports
+
+import (
+	"context"
+	"time"
+)
+
+// Note represents a note read from an export
+type Note struct {
+	ID          string // Identifies the note within the export, stable across imports
+	Title       string
+	Text        string // Plain text, without markup
+	Created     time.Time
+	Updated     time.Time
+	Tags        []string
+	Attachments []Attachment
+}
+
+// Attachment represents a file embedded in a note
+type Attachment struct {
+	Name      string
+	MediaType string
+	Data      []byte
+}
+
+// Reader reads the notes of an export
+type Reader interface {
+	// Name identifies the application the notes were exported from
+	Name() string
+
+	// Notes calls fn with every note of the export, stopping at the first error
+	Notes(ctx context.Context, fn func(Note) error) error
+}
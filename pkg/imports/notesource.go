@@ -0,0 +1,143 @@
+package imports
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/kazemisoroush/assistant/pkg/records"
+	"github.com/kazemisoroush/assistant/pkg/records/extractor"
+	"github.com/kazemisoroush/assistant/pkg/records/pipeline"
+	"github.com/kazemisoroush/assistant/pkg/records/source"
+)
+
+// Metadata keys set on imported records
+const (
+	// MetaImportedFrom names the application a record was imported from
+	MetaImportedFrom = "imported_from"
+
+	// MetaNoteTitle holds the title of the note a record was imported from
+	MetaNoteTitle = "note_title"
+)
+
+// NoteSource extracts records from the notes of an export. A note's text is
+// extracted into one record and every image or PDF embedded in it into
+// another, linked to the note's record. Records are dated like their note
+// and keep their IDs across imports, so importing again updates them.
+type NoteSource struct {
+	reader    Reader
+	extractor extractor.ContentExtractor
+	buffers   source.Buffers
+}
+
+// NewNoteSource creates a source extracting the notes the reader reads
+func NewNoteSource(reader Reader, extractor extractor.ContentExtractor, buffers source.Buffers) source.Source {
+	return &NoteSource{
+		reader:    reader,
+		extractor: extractor,
+		buffers:   buffers,
+	}
+}
+
+// Name implements source.Source
+func (s *NoteSource) Name() string {
+	return s.reader.Name()
+}
+
+// Scrape implements source.Source. Notes failing to be extracted are
+// reported as errors.
+func (s *NoteSource) Scrape(ctx context.Context) (<-chan records.Record, <-chan error) {
+	recordChan := make(chan records.Record, max(s.buffers.Records, 0))
+	errChan := make(chan error, max(s.buffers.Errors, 1))
+
+	go func() {
+		defer close(recordChan)
+		defer close(errChan)
+		err := s.reader.Notes(ctx, func(note Note) error {
+			return s.send(ctx, note, recordChan, errChan)
+		})
+		if err != nil && ctx.Err() == nil {
+			pipeline.Send(ctx, errChan, fmt.Errorf("failed to read %s export: %w", s.Name(), err), nil)
+		}
+	}()
+
+	return recordChan, errChan
+}
+
+// send extracts the records of a note and sends them
+func (s *NoteSource) send(ctx context.Context, note Note, recordChan chan<- records.Record, errChan chan<- error) error {
+	recs, err := s.extract(ctx, note)
+	if err != nil && !pipeline.Send(ctx, errChan, fmt.Errorf("failed to import note %q: %w", note.Title, err), nil) {
+		return ctx.Err()
+	}
+	for _, rec := range recs {
+		if !pipeline.Send(ctx, recordChan, rec, nil) {
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+// extract returns the records of the note's text and attachments, and the
+// errors extracting them
+func (s *NoteSource) extract(ctx context.Context, note Note) ([]records.Record, error) {
+	var recs []records.Record
+	var errs []error
+	var links []string
+	if note.Text != "" {
+		text := strings.TrimSpace(note.Title + "\n\n" + note.Text)
+		rec, err := s.record(ctx, note, "", []byte(text), "text/plain", nil)
+		if err != nil {
+			errs = append(errs, err)
+		} else {
+			recs = append(recs, rec)
+			links = []string{rec.ID}
+		}
+	}
+
+	for i, attachment := range note.Attachments {
+		if !strings.HasPrefix(attachment.MediaType, "image/") && attachment.MediaType != "application/pdf" {
+			continue
+		}
+		rec, err := s.record(ctx, note, fmt.Sprintf("-%d", i+1), attachment.Data, attachment.MediaType, links)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("attachment %s: %w", attachment.Name, err))
+			continue
+		}
+		recs = append(recs, rec)
+	}
+	return recs, errors.Join(errs...)
+}
+
+// record extracts a record from part of a note, dating and identifying it by the note
+func (s *NoteSource) record(ctx context.Context, note Note, suffix string, content []byte, mediaType string, links []string) (records.Record, error) {
+	input, err := extractor.ReaderInput(bytes.NewReader(content), mediaType)
+	if err != nil {
+		return records.Record{}, err
+	}
+	rec, err := s.extractor.Extract(ctx, input)
+	if err != nil {
+		return records.Record{}, err
+	}
+
+	rec.ID = fmt.Sprintf("%s-%s%s", s.Name(), note.ID, suffix)
+	if !note.Created.IsZero() {
+		rec.CreatedAt = note.Created
+		rec.UpdatedAt = note.Created
+	}
+	if note.Updated.After(rec.UpdatedAt) {
+		rec.UpdatedAt = note.Updated
+	}
+	rec.Tags = append(rec.Tags, note.Tags...)
+	if rec.Metadata == nil {
+		rec.Metadata = map[string]interface{}{}
+	}
+	rec.Metadata[MetaImportedFrom] = s.Name()
+	rec.Metadata[MetaNoteTitle] = note.Title
+	if len(links) > 0 {
+		rec.Metadata[records.MetaLinks] = links
+	}
+	return rec, nil
+}
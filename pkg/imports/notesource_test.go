@@ -0,0 +1,120 @@
+package imports_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/kazemisoroush/assistant/pkg/imports"
+	"github.com/kazemisoroush/assistant/pkg/records"
+	"github.com/kazemisoroush/assistant/pkg/records/extractor"
+	extractormocks "github.com/kazemisoroush/assistant/pkg/records/extractor/mocks"
+	"github.com/kazemisoroush/assistant/pkg/records/source"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+// notesReader reads the notes it holds
+type notesReader []imports.Note
+
+func (r notesReader) Name() string { return "evernote" }
+
+func (r notesReader) Notes(_ context.Context, fn func(imports.Note) error) error {
+	for _, note := range r {
+		if err := fn(note); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// drain collects everything a scrape sends
+func drain(recordChan <-chan records.Record, errChan <-chan error) ([]records.Record, []error) {
+	var recs []records.Record
+	var errs []error
+	for recordChan != nil || errChan != nil {
+		select {
+		case rec, ok := <-recordChan:
+			if !ok {
+				recordChan = nil
+				continue
+			}
+			recs = append(recs, rec)
+		case err, ok := <-errChan:
+			if !ok {
+				errChan = nil
+				continue
+			}
+			errs = append(errs, err)
+		}
+	}
+	return recs, errs
+}
+
+func TestNoteSource_Scrape(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	created := time.Date(2019, 3, 14, 9, 15, 0, 0, time.UTC)
+	updated := time.Date(2019, 3, 15, 10, 0, 0, 0, time.UTC)
+	contentExtractor := extractormocks.NewMockContentExtractor(ctrl)
+	contentExtractor.EXPECT().Extract(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, input extractor.Input) (records.Record, error) {
+		data, err := input.ReadAll()
+		require.NoError(t, err, "failed to read input")
+		if input.IsText() {
+			assert.Equal(t, "Dishwasher warranty\n\nBought at Kitchen World", string(data), "Scrape() should extract the title and text")
+			return records.Record{ID: "generated", Type: records.RecordTypeWarranty, CreatedAt: time.Now(), UpdatedAt: time.Now()}, nil
+		}
+		return records.Record{ID: "generated", Type: records.RecordTypeReceipt, Tags: []string{"kitchen"}, CreatedAt: time.Now(), UpdatedAt: time.Now()}, nil
+	}).Times(2)
+	src := imports.NewNoteSource(notesReader{{
+		ID:      "n1",
+		Title:   "Dishwasher warranty",
+		Text:    "Bought at Kitchen World",
+		Created: created,
+		Updated: updated,
+		Tags:    []string{"home"},
+		Attachments: []imports.Attachment{
+			{Name: "receipt.png", MediaType: "image/png", Data: []byte("png")},
+			{Name: "manual.zip", MediaType: "application/zip", Data: []byte("zip")},
+		},
+	}}, contentExtractor, source.Buffers{Records: 4, Errors: 4})
+
+	// Act
+	recs, errs := drain(src.Scrape(context.Background()))
+
+	// Assert
+	assert.Empty(t, errs, "Scrape() should not fail")
+	require.Len(t, recs, 2, "Scrape() should extract the text and the images, skipping other attachments")
+	text, image := recs[0], recs[1]
+	assert.Equal(t, "evernote-n1", text.ID, "Scrape() should identify the text's record by the note")
+	assert.Equal(t, "evernote-n1-1", image.ID, "Scrape() should identify the attachment's record by the note")
+	assert.Equal(t, created, text.CreatedAt, "Scrape() should date records by the note's creation")
+	assert.Equal(t, updated, image.UpdatedAt, "Scrape() should date record updates by the note's")
+	assert.Equal(t, []string{"kitchen", "home"}, image.Tags, "Scrape() should add the note's tags")
+	assert.Equal(t, []string{"evernote-n1"}, image.Links(), "Scrape() should link attachments to the note's record")
+	assert.Equal(t, "evernote", text.Metadata[imports.MetaImportedFrom], "Scrape() should name the application")
+	assert.Equal(t, "Dishwasher warranty", image.Metadata[imports.MetaNoteTitle], "Scrape() should keep the note's title")
+}
+
+func TestNoteSource_Scrape_ExtractionFailure(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	contentExtractor := extractormocks.NewMockContentExtractor(ctrl)
+	contentExtractor.EXPECT().Extract(gomock.Any(), gomock.Any()).Return(records.Record{}, errors.New("model unavailable"))
+	contentExtractor.EXPECT().Extract(gomock.Any(), gomock.Any()).Return(records.Record{ID: "generated"}, nil)
+	src := imports.NewNoteSource(notesReader{
+		{ID: "n1", Title: "Broken", Text: "text"},
+		{ID: "n2", Title: "Fine", Text: "text"},
+	}, contentExtractor, source.Buffers{Records: 4, Errors: 4})
+
+	// Act
+	recs, errs := drain(src.Scrape(context.Background()))
+
+	// Assert
+	require.Len(t, errs, 1, "Scrape() should report the note failing to be extracted")
+	assert.Contains(t, errs[0].Error(), "Broken", "Scrape() should name the note failing to be extracted")
+	require.Len(t, recs, 1, "Scrape() should go on with the other notes")
+	assert.Equal(t, "evernote-n2", recs[0].ID, "Scrape() should send the other notes' records")
+}
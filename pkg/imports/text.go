@@ -0,0 +1,65 @@
+package imports
+
+import (
+	"encoding/xml"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// blockElements end a line of text
+var blockElements = []string{"br", "div", "p", "li", "tr", "h1", "h2", "h3", "h4", "h5", "h6", "en-todo", "hr", "blockquote", "pre"}
+
+// skippedElements hold no note text
+var skippedElements = []string{"head", "script", "style", "en-media"}
+
+// blankLines matches runs of empty lines
+var blankLines = regexp.MustCompile(`\n\s*\n\s*\n+`)
+
+// markupText returns the text of ENML or HTML markup, one line per block
+// element. Markup that is not well-formed is read leniently.
+func markupText(markup string) string {
+	decoder := xml.NewDecoder(strings.NewReader(markup))
+	decoder.Strict = false
+	decoder.AutoClose = xml.HTMLAutoClose
+	decoder.Entity = xml.HTMLEntity
+
+	var text textWriter
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			// io.EOF, or markup too broken to read on
+			break
+		}
+		text.write(token)
+	}
+	return strings.TrimSpace(blankLines.ReplaceAllString(text.String(), "\n\n"))
+}
+
+// textWriter collects the text of markup tokens
+type textWriter struct {
+	strings.Builder
+	skipping int // Depth of elements holding no text
+}
+
+// write adds the text of the token, ending a line after block elements
+func (w *textWriter) write(token xml.Token) {
+	switch t := token.(type) {
+	case xml.StartElement:
+		if slices.Contains(skippedElements, strings.ToLower(t.Name.Local)) {
+			w.skipping++
+		}
+	case xml.EndElement:
+		name := strings.ToLower(t.Name.Local)
+		if slices.Contains(skippedElements, name) && w.skipping > 0 {
+			w.skipping--
+		}
+		if slices.Contains(blockElements, name) {
+			w.WriteString("\n")
+		}
+	case xml.CharData:
+		if w.skipping == 0 {
+			w.Write(t)
+		}
+	}
+}
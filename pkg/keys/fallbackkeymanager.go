@@ -0,0 +1,126 @@
+package keys
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// Key sources recorded by FallbackKeyManager
+const (
+	sourcePrimary  = "keyring"
+	sourceFallback = "passphrase"
+)
+
+// FallbackKeyManager uses the primary key manager and switches to the fallback
+// when the primary reports that the OS keyring is unavailable, as long as no
+// key was ever provisioned. The manager holding the key is recorded in the
+// file at sourcePath once it is used, and only that one is used from then on:
+// data sealed with a keyring key is never silently sealed with a passphrase
+// key instead, nor the other way round. Without a fallback, the keyring is
+// recorded all the same, so a key derived from a passphrase before is not
+// replaced by a new keyring key when the passphrase is no longer given.
+type FallbackKeyManager struct {
+	mu         sync.Mutex
+	primary    KeyManager
+	fallback   KeyManager
+	sourcePath string
+}
+
+// NewFallbackKeyManager creates a new fallback key manager recording the
+// manager holding the key in the file at sourcePath. The fallback may be nil.
+func NewFallbackKeyManager(primary, fallback KeyManager, sourcePath string) KeyManager {
+	return &FallbackKeyManager{
+		primary:    primary,
+		fallback:   fallback,
+		sourcePath: sourcePath,
+	}
+}
+
+// Key returns the current encryption key
+func (m *FallbackKeyManager) Key(ctx context.Context) ([]byte, error) {
+	return m.use(ctx, func(manager KeyManager) ([]byte, error) { return manager.Key(ctx) })
+}
+
+// Rotate replaces the current key with a freshly generated one and returns it
+func (m *FallbackKeyManager) Rotate(ctx context.Context) ([]byte, error) {
+	return m.use(ctx, func(manager KeyManager) ([]byte, error) { return manager.Rotate(ctx) })
+}
+
+// Previous returns the key that was current before the last rotation
+func (m *FallbackKeyManager) Previous(ctx context.Context) ([]byte, error) {
+	return m.use(ctx, func(manager KeyManager) ([]byte, error) { return manager.Previous(ctx) })
+}
+
+// use calls the manager holding the key. Before it is recorded, the primary
+// is called, and the fallback when the keyring is unavailable; the one
+// answering is recorded.
+func (m *FallbackKeyManager) use(ctx context.Context, call func(KeyManager) ([]byte, error)) ([]byte, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	source, err := m.source()
+	if err != nil {
+		return nil, err
+	}
+	switch source {
+	case sourcePrimary:
+		key, err := call(m.primary)
+		if errors.Is(err, ErrKeyringUnavailable) {
+			return nil, fmt.Errorf("the encryption key is kept in the OS keyring, not switching to the passphrase key: %w", err)
+		}
+		return key, err
+	case sourceFallback:
+		if m.fallback == nil {
+			return nil, fmt.Errorf("the encryption key is derived from a passphrase, but none is given")
+		}
+		return call(m.fallback)
+	}
+
+	source = sourcePrimary
+	key, err := call(m.primary)
+	if errors.Is(err, ErrKeyringUnavailable) && m.fallback != nil {
+		slog.WarnContext(ctx, "OS keyring unavailable, using passphrase-derived key", "error", err)
+		source = sourceFallback
+		key, err = call(m.fallback)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := m.record(source); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// source returns the recorded manager holding the key, empty when none is
+func (m *FallbackKeyManager) source() (string, error) {
+	data, err := os.ReadFile(m.sourcePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read key source file: %w", err)
+	}
+	source := strings.TrimSpace(string(data))
+	if source != sourcePrimary && source != sourceFallback {
+		return "", fmt.Errorf("unknown key source %q in %s", source, m.sourcePath)
+	}
+	return source, nil
+}
+
+// record records the manager holding the key
+func (m *FallbackKeyManager) record(source string) error {
+	if err := os.MkdirAll(filepath.Dir(m.sourcePath), 0700); err != nil {
+		return fmt.Errorf("failed to create key source directory: %w", err)
+	}
+	if err := os.WriteFile(m.sourcePath, []byte(source+"\n"), 0600); err != nil {
+		return fmt.Errorf("failed to write key source file: %w", err)
+	}
+	return nil
+}
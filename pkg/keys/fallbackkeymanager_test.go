@@ -0,0 +1,103 @@
+package keys
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/kazemisoroush/assistant/pkg/keys/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestFallbackKeyManager_Key_FallsBackBeforeProvisioning(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	primary := mocks.NewMockKeyManager(ctrl)
+	fallback := mocks.NewMockKeyManager(ctrl)
+	primary.EXPECT().Key(gomock.Any()).Return(nil, ErrKeyringUnavailable)
+	fallback.EXPECT().Key(gomock.Any()).Return([]byte("fallback"), nil)
+	sourcePath := filepath.Join(t.TempDir(), keySourceFile)
+	manager := NewFallbackKeyManager(primary, fallback, sourcePath)
+
+	// Act
+	key, err := manager.Key(context.Background())
+
+	// Assert
+	require.NoError(t, err, "Key() error should be nil")
+	assert.Equal(t, []byte("fallback"), key, "Key() should return the fallback key")
+	data, err := os.ReadFile(sourcePath)
+	require.NoError(t, err, "Key() should write the key source file")
+	assert.Equal(t, sourceFallback+"\n", string(data), "Key() should record the fallback as the key source")
+}
+
+func TestFallbackKeyManager_Key_KeepsRecordedKeyring(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	primary := mocks.NewMockKeyManager(ctrl)
+	fallback := mocks.NewMockKeyManager(ctrl)
+	primary.EXPECT().Key(gomock.Any()).Return([]byte("primary"), nil)
+	primary.EXPECT().Key(gomock.Any()).Return(nil, ErrKeyringUnavailable)
+	manager := NewFallbackKeyManager(primary, fallback, filepath.Join(t.TempDir(), keySourceFile))
+	_, err := manager.Key(context.Background())
+	require.NoError(t, err, "Key() error should be nil")
+
+	// Act
+	_, err = manager.Key(context.Background())
+
+	// Assert
+	assert.ErrorIs(t, err, ErrKeyringUnavailable, "Key() should not switch to the fallback once the keyring holds the key")
+}
+
+func TestFallbackKeyManager_Key_KeepsRecordedFallback(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	primary := mocks.NewMockKeyManager(ctrl)
+	fallback := mocks.NewMockKeyManager(ctrl)
+	sourcePath := filepath.Join(t.TempDir(), keySourceFile)
+	require.NoError(t, os.WriteFile(sourcePath, []byte(sourceFallback+"\n"), 0600))
+	fallback.EXPECT().Key(gomock.Any()).Return([]byte("fallback"), nil)
+	manager := NewFallbackKeyManager(primary, fallback, sourcePath)
+
+	// Act
+	key, err := manager.Key(context.Background())
+
+	// Assert
+	require.NoError(t, err, "Key() error should be nil")
+	assert.Equal(t, []byte("fallback"), key, "Key() should keep using the fallback once it holds the key")
+}
+
+func TestFallbackKeyManager_Key_RecordsKeyringWithoutFallback(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	primary := mocks.NewMockKeyManager(ctrl)
+	primary.EXPECT().Key(gomock.Any()).Return([]byte("primary"), nil)
+	sourcePath := filepath.Join(t.TempDir(), keySourceFile)
+	manager := NewFallbackKeyManager(primary, nil, sourcePath)
+
+	// Act
+	_, err := manager.Key(context.Background())
+
+	// Assert
+	require.NoError(t, err, "Key() error should be nil")
+	data, err := os.ReadFile(sourcePath)
+	require.NoError(t, err, "Key() should write the key source file")
+	assert.Equal(t, sourcePrimary+"\n", string(data), "Key() should record the keyring as the key source")
+}
+
+func TestFallbackKeyManager_Key_RecordedFallbackWithoutPassphrase(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	primary := mocks.NewMockKeyManager(ctrl)
+	sourcePath := filepath.Join(t.TempDir(), keySourceFile)
+	require.NoError(t, os.WriteFile(sourcePath, []byte(sourceFallback+"\n"), 0600))
+	manager := NewFallbackKeyManager(primary, nil, sourcePath)
+
+	// Act
+	_, err := manager.Key(context.Background())
+
+	// Assert
+	assert.ErrorContains(t, err, "derived from a passphrase", "Key() should not create a keyring key once a passphrase holds the key")
+}
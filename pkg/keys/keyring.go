@@ -0,0 +1,47 @@
+package keys
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/zalando/go-keyring"
+)
+
+// Keyring stores secrets in a platform credential store
+//
+//go:generate mockgen -destination=./mocks/mock_keyring.go -mock_names=Keyring=MockKeyring -package=mocks . Keyring
+type Keyring interface {
+	// Get returns the secret stored for the given service and account
+	Get(service, account string) (string, error)
+
+	// Set stores the secret for the given service and account
+	Set(service, account, secret string) error
+}
+
+// OSKeyring is a Keyring backed by macOS Keychain, Secret Service or Windows Credential Manager
+type OSKeyring struct{}
+
+// NewOSKeyring creates a new OS keyring
+func NewOSKeyring() Keyring {
+	return &OSKeyring{}
+}
+
+// Get returns the secret stored for the given service and account
+func (k *OSKeyring) Get(service, account string) (string, error) {
+	secret, err := keyring.Get(service, account)
+	if errors.Is(err, keyring.ErrNotFound) {
+		return "", ErrKeyNotFound
+	}
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrKeyringUnavailable, err)
+	}
+	return secret, nil
+}
+
+// Set stores the secret for the given service and account
+func (k *OSKeyring) Set(service, account, secret string) error {
+	if err := keyring.Set(service, account, secret); err != nil {
+		return fmt.Errorf("%w: %v", ErrKeyringUnavailable, err)
+	}
+	return nil
+}
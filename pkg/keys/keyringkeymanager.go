@@ -0,0 +1,94 @@
+package keys
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+)
+
+// previousSuffix is appended to the account name to store the pre-rotation key
+const previousSuffix = ".previous"
+
+// KeyringKeyManager keeps the encryption key in a Keyring
+type KeyringKeyManager struct {
+	keyring Keyring
+	service string
+	account string
+}
+
+// NewKeyringKeyManager creates a new keyring-backed key manager
+func NewKeyringKeyManager(keyring Keyring, service, account string) KeyManager {
+	return &KeyringKeyManager{
+		keyring: keyring,
+		service: service,
+		account: account,
+	}
+}
+
+// Key returns the current encryption key, creating one if none exists yet
+func (m *KeyringKeyManager) Key(_ context.Context) ([]byte, error) {
+	key, err := m.load(m.account)
+	if !errors.Is(err, ErrKeyNotFound) {
+		return key, err
+	}
+
+	key, err = generateKey()
+	if err != nil {
+		return nil, err
+	}
+	if err := m.save(m.account, key); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// Rotate replaces the current key with a freshly generated one and returns it
+func (m *KeyringKeyManager) Rotate(ctx context.Context) ([]byte, error) {
+	current, err := m.Key(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	next, err := generateKey()
+	if err != nil {
+		return nil, err
+	}
+
+	// Keep the old key first so a failure below never loses it
+	if err := m.save(m.account+previousSuffix, current); err != nil {
+		return nil, err
+	}
+	if err := m.save(m.account, next); err != nil {
+		return nil, err
+	}
+	return next, nil
+}
+
+// Previous returns the key that was current before the last rotation
+func (m *KeyringKeyManager) Previous(_ context.Context) ([]byte, error) {
+	return m.load(m.account + previousSuffix)
+}
+
+func (m *KeyringKeyManager) load(account string) ([]byte, error) {
+	encoded, err := m.keyring.Get(m.service, account)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode key from keyring: %w", err)
+	}
+	if len(key) != KeySize {
+		return nil, fmt.Errorf("invalid key size in keyring: %d", len(key))
+	}
+	return key, nil
+}
+
+func (m *KeyringKeyManager) save(account string, key []byte) error {
+	if err := m.keyring.Set(m.service, account, base64.StdEncoding.EncodeToString(key)); err != nil {
+		return fmt.Errorf("failed to store key in keyring: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package keys
+
+import (
+	"context"
+	"encoding/base64"
+	"testing"
+
+	"github.com/kazemisoroush/assistant/pkg/keys/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestKeyringKeyManager_Key_CreatesMissingKey(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	keyring := mocks.NewMockKeyring(ctrl)
+	var stored string
+	keyring.EXPECT().Get("svc", "acct").Return("", ErrKeyNotFound)
+	keyring.EXPECT().Set("svc", "acct", gomock.Any()).DoAndReturn(func(_, _, secret string) error {
+		stored = secret
+		return nil
+	})
+	manager := NewKeyringKeyManager(keyring, "svc", "acct")
+
+	// Act
+	key, err := manager.Key(context.Background())
+
+	// Assert
+	require.NoError(t, err, "Key() error should be nil")
+	assert.Len(t, key, KeySize, "Key() should return a key of KeySize bytes")
+	assert.Equal(t, base64.StdEncoding.EncodeToString(key), stored, "Key() should store the generated key")
+}
+
+func TestKeyringKeyManager_Key_KeyringUnavailable(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	keyring := mocks.NewMockKeyring(ctrl)
+	keyring.EXPECT().Get("svc", "acct").Return("", ErrKeyringUnavailable)
+	manager := NewKeyringKeyManager(keyring, "svc", "acct")
+
+	// Act
+	_, err := manager.Key(context.Background())
+
+	// Assert
+	assert.ErrorIs(t, err, ErrKeyringUnavailable, "Key() should report the keyring as unavailable")
+}
+
+func TestKeyringKeyManager_Rotate(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	keyring := mocks.NewMockKeyring(ctrl)
+	current := make([]byte, KeySize)
+	encodedCurrent := base64.StdEncoding.EncodeToString(current)
+	keyring.EXPECT().Get("svc", "acct").Return(encodedCurrent, nil)
+	keyring.EXPECT().Set("svc", "acct.previous", encodedCurrent).Return(nil)
+	keyring.EXPECT().Set("svc", "acct", gomock.Any()).Return(nil)
+	manager := NewKeyringKeyManager(keyring, "svc", "acct")
+
+	// Act
+	next, err := manager.Rotate(context.Background())
+
+	// Assert
+	require.NoError(t, err, "Rotate() error should be nil")
+	assert.Len(t, next, KeySize, "Rotate() should return a key of KeySize bytes")
+	assert.NotEqual(t, current, next, "Rotate() should return a new key")
+}
@@ -0,0 +1,63 @@
+// Package keys manages the encryption key used to protect data at rest.
+package keys
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"path/filepath"
+)
+
+// KeySize is the size in bytes of the at-rest encryption key (AES-256)
+const KeySize = 32
+
+var (
+	// ErrKeyNotFound is returned when no key has been stored yet
+	ErrKeyNotFound = errors.New("encryption key not found")
+
+	// ErrKeyringUnavailable is returned when the OS keyring cannot be reached
+	ErrKeyringUnavailable = errors.New("OS keyring unavailable")
+)
+
+// KeyManager provides access to the at-rest encryption key.
+//
+//go:generate mockgen -destination=./mocks/mock_keymanager.go -mock_names=KeyManager=MockKeyManager -package=mocks . KeyManager
+type KeyManager interface {
+	// Key returns the current encryption key, creating one if none exists yet
+	Key(ctx context.Context) ([]byte, error)
+
+	// Rotate replaces the current key with a freshly generated one and returns it
+	// The replaced key stays available through Previous until the next rotation
+	Rotate(ctx context.Context) ([]byte, error)
+
+	// Previous returns the key that was current before the last rotation
+	Previous(ctx context.Context) ([]byte, error)
+}
+
+// keySourceFile is the file next to the salt file recording which key
+// manager holds the key
+const keySourceFile = "keys.source"
+
+// NewKeyManager creates a key manager backed by the OS keyring. When a passphrase
+// is given, a passphrase-derived key is used if the keyring is unavailable
+// before any key is provisioned, and from then on.
+func NewKeyManager(service, account, passphrase, saltPath string) KeyManager {
+	primary := NewKeyringKeyManager(NewOSKeyring(), service, account)
+	var fallback KeyManager
+	if passphrase != "" {
+		fallback = NewPassphraseKeyManager(passphrase, saltPath)
+	}
+
+	sourcePath := filepath.Join(filepath.Dir(saltPath), keySourceFile)
+	return NewFallbackKeyManager(primary, fallback, sourcePath)
+}
+
+// generateKey returns KeySize bytes of cryptographically secure randomness
+func generateKey() ([]byte, error) {
+	key := make([]byte, KeySize)
+	if _, err := rand.Read(key); err != nil {
+		return nil, fmt.Errorf("failed to generate key: %w", err)
+	}
+	return key, nil
+}
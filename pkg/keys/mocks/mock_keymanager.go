@@ -0,0 +1,86 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/kazemisoroush/assistant/pkg/keys (interfaces: KeyManager)
+//
+// Generated by this command:
+//
+//	mockgen -destination=./mocks/mock_keymanager.go -mock_names=KeyManager=MockKeyManager -package=mocks . KeyManager
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockKeyManager is a mock of KeyManager interface.
+type MockKeyManager struct {
+	ctrl     *gomock.Controller
+	recorder *MockKeyManagerMockRecorder
+	isgomock struct{}
+}
+
+// MockKeyManagerMockRecorder is the mock recorder for MockKeyManager.
+type MockKeyManagerMockRecorder struct {
+	mock *MockKeyManager
+}
+
+// NewMockKeyManager creates a new mock instance.
+func NewMockKeyManager(ctrl *gomock.Controller) *MockKeyManager {
+	mock := &MockKeyManager{ctrl: ctrl}
+	mock.recorder = &MockKeyManagerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockKeyManager) EXPECT() *MockKeyManagerMockRecorder {
+	return m.recorder
+}
+
+// Key mocks base method.
+func (m *MockKeyManager) Key(ctx context.Context) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Key", ctx)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Key indicates an expected call of Key.
+func (mr *MockKeyManagerMockRecorder) Key(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Key", reflect.TypeOf((*MockKeyManager)(nil).Key), ctx)
+}
+
+// Previous mocks base method.
+func (m *MockKeyManager) Previous(ctx context.Context) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Previous", ctx)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Previous indicates an expected call of Previous.
+func (mr *MockKeyManagerMockRecorder) Previous(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Previous", reflect.TypeOf((*MockKeyManager)(nil).Previous), ctx)
+}
+
+// Rotate mocks base method.
+func (m *MockKeyManager) Rotate(ctx context.Context) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Rotate", ctx)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Rotate indicates an expected call of Rotate.
+func (mr *MockKeyManagerMockRecorder) Rotate(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Rotate", reflect.TypeOf((*MockKeyManager)(nil).Rotate), ctx)
+}
@@ -0,0 +1,69 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/kazemisoroush/assistant/pkg/keys (interfaces: Keyring)
+//
+// Generated by this command:
+//
+//	mockgen -destination=./mocks/mock_keyring.go -mock_names=Keyring=MockKeyring -package=mocks . Keyring
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockKeyring is a mock of Keyring interface.
+type MockKeyring struct {
+	ctrl     *gomock.Controller
+	recorder *MockKeyringMockRecorder
+	isgomock struct{}
+}
+
+// MockKeyringMockRecorder is the mock recorder for MockKeyring.
+type MockKeyringMockRecorder struct {
+	mock *MockKeyring
+}
+
+// NewMockKeyring creates a new mock instance.
+func NewMockKeyring(ctrl *gomock.Controller) *MockKeyring {
+	mock := &MockKeyring{ctrl: ctrl}
+	mock.recorder = &MockKeyringMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockKeyring) EXPECT() *MockKeyringMockRecorder {
+	return m.recorder
+}
+
+// Get mocks base method.
+func (m *MockKeyring) Get(service, account string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", service, account)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockKeyringMockRecorder) Get(service, account any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockKeyring)(nil).Get), service, account)
+}
+
+// Set mocks base method.
+func (m *MockKeyring) Set(service, account, secret string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Set", service, account, secret)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Set indicates an expected call of Set.
+func (mr *MockKeyringMockRecorder) Set(service, account, secret any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Set", reflect.TypeOf((*MockKeyring)(nil).Set), service, account, secret)
+}
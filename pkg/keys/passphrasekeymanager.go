@@ -0,0 +1,157 @@
+package keys
+
+import (
+	"context"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+const (
+	// saltSize is the size in bytes of the key derivation salt
+	saltSize = 16
+
+	// pbkdf2Iterations follows the OWASP recommendation for PBKDF2-HMAC-SHA256
+	pbkdf2Iterations = 600_000
+)
+
+// saltFile is the on-disk representation of the key derivation salts
+type saltFile struct {
+	Current  []byte `json:"current"`
+	Previous []byte `json:"previous,omitempty"`
+}
+
+// PassphraseKeyManager derives the encryption key from a passphrase.
+// Only the salts are persisted; rotating replaces the salt, which yields a new key.
+type PassphraseKeyManager struct {
+	mu         sync.Mutex
+	passphrase string
+	saltPath   string
+}
+
+// NewPassphraseKeyManager creates a new passphrase-based key manager
+func NewPassphraseKeyManager(passphrase, saltPath string) KeyManager {
+	return &PassphraseKeyManager{
+		passphrase: passphrase,
+		saltPath:   saltPath,
+	}
+}
+
+// Key returns the current encryption key, creating a salt if none exists yet
+func (m *PassphraseKeyManager) Key(_ context.Context) ([]byte, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	salts, err := m.loadOrCreate()
+	if err != nil {
+		return nil, err
+	}
+	return m.derive(salts.Current)
+}
+
+// Rotate replaces the current salt with a fresh one and returns the derived key
+func (m *PassphraseKeyManager) Rotate(_ context.Context) ([]byte, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	salts, err := m.loadOrCreate()
+	if err != nil {
+		return nil, err
+	}
+
+	next, err := generateSalt()
+	if err != nil {
+		return nil, err
+	}
+
+	salts = saltFile{Current: next, Previous: salts.Current}
+	if err := m.write(salts); err != nil {
+		return nil, err
+	}
+	return m.derive(salts.Current)
+}
+
+// Previous returns the key that was current before the last rotation
+func (m *PassphraseKeyManager) Previous(_ context.Context) ([]byte, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	salts, err := m.read()
+	if err != nil {
+		return nil, err
+	}
+	if len(salts.Previous) == 0 {
+		return nil, ErrKeyNotFound
+	}
+	return m.derive(salts.Previous)
+}
+
+func (m *PassphraseKeyManager) derive(salt []byte) ([]byte, error) {
+	key, err := pbkdf2.Key(sha256.New, m.passphrase, salt, pbkdf2Iterations, KeySize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive key: %w", err)
+	}
+	return key, nil
+}
+
+func (m *PassphraseKeyManager) loadOrCreate() (saltFile, error) {
+	salts, err := m.read()
+	if !errors.Is(err, ErrKeyNotFound) {
+		return salts, err
+	}
+
+	salt, err := generateSalt()
+	if err != nil {
+		return saltFile{}, err
+	}
+	salts = saltFile{Current: salt}
+	if err := m.write(salts); err != nil {
+		return saltFile{}, err
+	}
+	return salts, nil
+}
+
+func (m *PassphraseKeyManager) read() (saltFile, error) {
+	data, err := os.ReadFile(m.saltPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return saltFile{}, ErrKeyNotFound
+	}
+	if err != nil {
+		return saltFile{}, fmt.Errorf("failed to read salt file: %w", err)
+	}
+
+	var salts saltFile
+	if err := json.Unmarshal(data, &salts); err != nil {
+		return saltFile{}, fmt.Errorf("failed to parse salt file: %w", err)
+	}
+	return salts, nil
+}
+
+func (m *PassphraseKeyManager) write(salts saltFile) error {
+	if err := os.MkdirAll(filepath.Dir(m.saltPath), 0700); err != nil {
+		return fmt.Errorf("failed to create salt directory: %w", err)
+	}
+
+	data, err := json.Marshal(salts)
+	if err != nil {
+		return fmt.Errorf("failed to marshal salt file: %w", err)
+	}
+	if err := os.WriteFile(m.saltPath, data, 0600); err != nil {
+		return fmt.Errorf("failed to write salt file: %w", err)
+	}
+	return nil
+}
+
+func generateSalt() ([]byte, error) {
+	salt := make([]byte, saltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, fmt.Errorf("failed to generate salt: %w", err)
+	}
+	return salt, nil
+}
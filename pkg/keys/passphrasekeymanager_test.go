@@ -0,0 +1,55 @@
+package keys
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPassphraseKeyManager_Key_IsStable(t *testing.T) {
+	// Arrange
+	saltPath := filepath.Join(t.TempDir(), "keys.salt")
+	ctx := context.Background()
+	first, err := NewPassphraseKeyManager("secret", saltPath).Key(ctx)
+	require.NoError(t, err, "Key() error should be nil")
+
+	// Act
+	second, err := NewPassphraseKeyManager("secret", saltPath).Key(ctx)
+
+	// Assert
+	require.NoError(t, err, "Key() error should be nil")
+	assert.Len(t, second, KeySize, "Key() should return a key of KeySize bytes")
+	assert.Equal(t, first, second, "Key() should derive the same key from the same passphrase and salt")
+}
+
+func TestPassphraseKeyManager_Rotate(t *testing.T) {
+	// Arrange
+	manager := NewPassphraseKeyManager("secret", filepath.Join(t.TempDir(), "keys.salt"))
+	ctx := context.Background()
+	original, err := manager.Key(ctx)
+	require.NoError(t, err, "Key() error should be nil")
+
+	// Act
+	rotated, err := manager.Rotate(ctx)
+
+	// Assert
+	require.NoError(t, err, "Rotate() error should be nil")
+	assert.NotEqual(t, original, rotated, "Rotate() should produce a new key")
+	previous, err := manager.Previous(ctx)
+	require.NoError(t, err, "Previous() error should be nil")
+	assert.Equal(t, original, previous, "Previous() should return the pre-rotation key")
+}
+
+func TestPassphraseKeyManager_Previous_NoRotation(t *testing.T) {
+	// Arrange
+	manager := NewPassphraseKeyManager("secret", filepath.Join(t.TempDir(), "keys.salt"))
+
+	// Act
+	_, err := manager.Previous(context.Background())
+
+	// Assert
+	assert.ErrorIs(t, err, ErrKeyNotFound, "Previous() should fail before any rotation")
+}
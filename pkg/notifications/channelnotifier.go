@@ -0,0 +1,56 @@
+package notifications
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// Route subscribes a channel to events
+type Route struct {
+	Channel Channel
+	Events  []Event // Empty subscribes the channel to every event
+}
+
+// ChannelNotifier renders notifications with templates and sends them through
+// every channel routed the event
+type ChannelNotifier struct {
+	templates *Templates
+	routes    []Route
+}
+
+// NewChannelNotifier creates a notifier sending through the routed channels
+func NewChannelNotifier(templates *Templates, routes ...Route) Notifier {
+	return &ChannelNotifier{
+		templates: templates,
+		routes:    routes,
+	}
+}
+
+// Notify implements Notifier. Every subscribed channel is tried; the failures
+// are returned together.
+func (n *ChannelNotifier) Notify(ctx context.Context, event Event, data any) error {
+	var channels []Channel
+	for _, route := range n.routes {
+		if len(route.Events) == 0 || slices.Contains(route.Events, event) {
+			channels = append(channels, route.Channel)
+		}
+	}
+	if len(channels) == 0 {
+		return nil
+	}
+
+	msg, err := n.templates.Render(event, data)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, channel := range channels {
+		if err := channel.Send(ctx, msg); err != nil {
+			errs = append(errs, fmt.Errorf("failed to send %s notification via %s: %w", event, channel.Name(), err))
+		}
+	}
+	return errors.Join(errs...)
+}
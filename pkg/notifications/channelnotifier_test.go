@@ -0,0 +1,59 @@
+package notifications_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/kazemisoroush/assistant/pkg/notifications"
+	"github.com/kazemisoroush/assistant/pkg/notifications/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestChannelNotifier_Notify(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	templates, err := notifications.NewTemplates("")
+	require.NoError(t, err, "NewTemplates() error should be nil")
+
+	all := mocks.NewMockChannel(ctrl)
+	failing := mocks.NewMockChannel(ctrl)
+	other := mocks.NewMockChannel(ctrl)
+	var sent notifications.Message
+	all.EXPECT().Send(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, msg notifications.Message) error {
+		sent = msg
+		return nil
+	})
+	failing.EXPECT().Send(gomock.Any(), gomock.Any()).Return(errors.New("boom"))
+	failing.EXPECT().Name().Return("failing")
+	other.EXPECT().Send(gomock.Any(), gomock.Any()).Times(0)
+
+	notifier := notifications.NewChannelNotifier(templates,
+		notifications.Route{Channel: all},
+		notifications.Route{Channel: failing, Events: []notifications.Event{notifications.EventScrapeFailure}},
+		notifications.Route{Channel: other, Events: []notifications.Event{notifications.EventReminders}},
+	)
+
+	// Act
+	err = notifier.Notify(context.Background(), notifications.EventScrapeFailure, "disk full")
+
+	// Assert
+	require.Error(t, err, "Notify() should report channels that failed")
+	assert.Contains(t, err.Error(), "failing", "Notify() should name the failed channel")
+	assert.Equal(t, "disk full\n", sent.Body, "Notify() should send the rendered message to the other channels")
+}
+
+func TestChannelNotifier_Notify_NoChannels(t *testing.T) {
+	// Arrange
+	templates, err := notifications.NewTemplates("")
+	require.NoError(t, err, "NewTemplates() error should be nil")
+	notifier := notifications.NewChannelNotifier(templates)
+
+	// Act
+	err = notifier.Notify(context.Background(), "unknown", nil)
+
+	// Assert
+	assert.NoError(t, err, "Notify() should do nothing without subscribed channels")
+}
@@ -0,0 +1,95 @@
+package notifications
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/smtp"
+	"net/textproto"
+	"strconv"
+	"strings"
+)
+
+// EmailConfig represents the SMTP settings of the email channel
+type EmailConfig struct {
+	Host     string
+	Port     int
+	Username string // Authentication is skipped when empty
+	Password string
+	From     string
+	To       string
+}
+
+// EmailChannel emails messages over SMTP
+type EmailChannel struct {
+	cfg      EmailConfig
+	sendMail func(addr string, auth smtp.Auth, from string, to []string, msg []byte) error
+}
+
+// NewEmailChannel creates a channel sending mail with the given SMTP settings
+func NewEmailChannel(cfg EmailConfig) Channel {
+	return &EmailChannel{
+		cfg:      cfg,
+		sendMail: smtp.SendMail,
+	}
+}
+
+// Name implements Channel
+func (c *EmailChannel) Name() string {
+	return "email"
+}
+
+// Send implements Channel
+func (c *EmailChannel) Send(_ context.Context, msg Message) error {
+	var auth smtp.Auth
+	if c.cfg.Username != "" {
+		auth = smtp.PlainAuth("", c.cfg.Username, c.cfg.Password, c.cfg.Host)
+	}
+
+	addr := net.JoinHostPort(c.cfg.Host, strconv.Itoa(c.cfg.Port))
+	if err := c.sendMail(addr, auth, c.cfg.From, []string{c.cfg.To}, c.message(msg)); err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+	return nil
+}
+
+// message builds the RFC 5322 message, with the plain text and HTML bodies
+// as alternatives when the message has an HTML body
+func (c *EmailChannel) message(msg Message) []byte {
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", c.cfg.From)
+	fmt.Fprintf(&b, "To: %s\r\n", c.cfg.To)
+	// Encoded, so non-ASCII subjects survive and line breaks cannot add headers
+	fmt.Fprintf(&b, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", msg.Subject))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	if msg.HTML == "" {
+		b.WriteString("Content-Type: text/plain; charset=utf-8\r\n\r\n")
+		b.WriteString(crlf(msg.Body))
+		return []byte(b.String())
+	}
+
+	var parts bytes.Buffer
+	w := multipart.NewWriter(&parts)
+	writePart(w, "text/plain; charset=utf-8", msg.Body)
+	writePart(w, "text/html; charset=utf-8", msg.HTML)
+	_ = w.Close()
+
+	fmt.Fprintf(&b, "Content-Type: multipart/alternative; boundary=%s\r\n\r\n", w.Boundary())
+	b.Write(parts.Bytes())
+	return []byte(b.String())
+}
+
+// writePart adds a body part; writes to the in-memory buffer cannot fail
+func writePart(w *multipart.Writer, contentType, body string) {
+	part, _ := w.CreatePart(textproto.MIMEHeader{"Content-Type": {contentType}})
+	_, _ = io.WriteString(part, crlf(body))
+}
+
+// crlf converts line endings to the CRLF mail requires
+func crlf(body string) string {
+	return strings.ReplaceAll(body, "\n", "\r\n")
+}
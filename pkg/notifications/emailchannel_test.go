@@ -0,0 +1,30 @@
+package notifications
+
+import (
+	"context"
+	"net/smtp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEmailChannel_Send_EncodesSubject(t *testing.T) {
+	// Arrange
+	var sent string
+	channel := &EmailChannel{
+		cfg: EmailConfig{Host: "smtp.example.com", Port: 587, From: "vault@example.com", To: "me@example.com"},
+		sendMail: func(_ string, _ smtp.Auth, _ string, _ []string, msg []byte) error {
+			sent = string(msg)
+			return nil
+		},
+	}
+
+	// Act
+	err := channel.Send(context.Background(), Message{Subject: "Führerschein läuft ab\r\nBcc: other@example.com", Body: "soon"})
+
+	// Assert
+	require.NoError(t, err, "Send() error should be nil")
+	assert.Contains(t, sent, "Subject: =?utf-8?q?F=C3=BChrerschein_l=C3=A4uft_ab=0D=0ABcc:_other@example.com?=\r\n", "Send() should encode the subject")
+	assert.NotContains(t, sent, "\r\nBcc:", "Send() should not let the subject add headers")
+}
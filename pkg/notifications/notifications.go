@@ -0,0 +1,45 @@
+// Package notifications delivers rendered messages about events, such as due
+// reminders or crossed budgets, through pluggable channels: SMTP email, ntfy
+// and Pushover push notifications, and generic webhooks.
+package notifications
+
+import "context"
+
+// Event names what a notification is about and selects its template
+type Event string
+
+// Events notified about
+const (
+	EventReminders     Event = "reminders"      // Data is []reminders.Reminder
+	EventBudgetAlerts  Event = "budget_alerts"  // Data is []budgets.Status
+	EventScrapeFailure Event = "scrape_failure" // Data is the error message
+	EventDigest        Event = "digest"         // Data is digest.Digest
+)
+
+// Message represents a notification rendered for delivery
+type Message struct {
+	Event   Event  `json:"event"`
+	Subject string `json:"subject"`
+	Body    string `json:"body"`
+	HTML    string `json:"html,omitempty"` // HTML version of the body, sent by channels that support it
+	Data    any    `json:"data,omitempty"` // The value the templates were rendered from
+}
+
+// Channel delivers messages to the user
+//
+//go:generate mockgen -destination=./mocks/mock_channel.go -mock_names=Channel=MockChannel -package=mocks . Channel
+type Channel interface {
+	// Name identifies the channel in errors
+	Name() string
+
+	// Send delivers the message
+	Send(ctx context.Context, msg Message) error
+}
+
+// Notifier renders notifications about events and sends them through the channels subscribed to the event
+//
+//go:generate mockgen -destination=./mocks/mock_notifier.go -mock_names=Notifier=MockNotifier -package=mocks . Notifier
+type Notifier interface {
+	// Notify renders the event's templates with data and sends the message
+	Notify(ctx context.Context, event Event, data any) error
+}
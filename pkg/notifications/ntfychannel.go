@@ -0,0 +1,55 @@
+package notifications
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// DefaultNtfyURL is the public ntfy server
+const DefaultNtfyURL = "https://ntfy.sh"
+
+// NtfyConfig represents the server and topic push notifications are published to
+type NtfyConfig struct {
+	URL   string // Server URL, DefaultNtfyURL when empty
+	Topic string
+	Token string // Access token of protected topics, optional
+}
+
+// NtfyChannel publishes messages to an ntfy topic
+type NtfyChannel struct {
+	client *http.Client
+	cfg    NtfyConfig
+}
+
+// NewNtfyChannel creates a channel publishing to the configured topic with the given client
+func NewNtfyChannel(client *http.Client, cfg NtfyConfig) Channel {
+	if cfg.URL == "" {
+		cfg.URL = DefaultNtfyURL
+	}
+	return &NtfyChannel{
+		client: client,
+		cfg:    cfg,
+	}
+}
+
+// Name implements Channel
+func (c *NtfyChannel) Name() string {
+	return "ntfy"
+}
+
+// Send implements Channel
+func (c *NtfyChannel) Send(ctx context.Context, msg Message) error {
+	url := strings.TrimSuffix(c.cfg.URL, "/") + "/" + c.cfg.Topic
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, strings.NewReader(msg.Body))
+	if err != nil {
+		return fmt.Errorf("failed to create ntfy request: %w", err)
+	}
+	req.Header.Set("Title", msg.Subject)
+	req.Header.Set("Tags", string(msg.Event))
+	if c.cfg.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.cfg.Token)
+	}
+	return send(c.client, req)
+}
@@ -0,0 +1,49 @@
+package notifications_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kazemisoroush/assistant/pkg/notifications"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNtfyChannel_Send(t *testing.T) {
+	// Arrange
+	var path, title, auth, body string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		path, title, auth, body = r.URL.Path, r.Header.Get("Title"), r.Header.Get("Authorization"), string(data)
+	}))
+	defer server.Close()
+	channel := notifications.NewNtfyChannel(server.Client(), notifications.NtfyConfig{URL: server.URL + "/", Topic: "alerts", Token: "secret"})
+
+	// Act
+	err := channel.Send(context.Background(), notifications.Message{Event: notifications.EventReminders, Subject: "Visa expiring", Body: "- visa\n"})
+
+	// Assert
+	require.NoError(t, err, "Send() error should be nil")
+	assert.Equal(t, "/alerts", path, "Send() should publish to the topic")
+	assert.Equal(t, "Visa expiring", title, "Send() should set the title")
+	assert.Equal(t, "Bearer secret", auth, "Send() should authenticate with the token")
+	assert.Equal(t, "- visa\n", body, "Send() should publish the body")
+}
+
+func TestNtfyChannel_Send_ErrorStatus(t *testing.T) {
+	// Arrange
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		http.Error(w, "forbidden", http.StatusForbidden)
+	}))
+	defer server.Close()
+	channel := notifications.NewNtfyChannel(server.Client(), notifications.NtfyConfig{URL: server.URL, Topic: "alerts"})
+
+	// Act
+	err := channel.Send(context.Background(), notifications.Message{Subject: "s", Body: "b"})
+
+	// Assert
+	assert.Error(t, err, "Send() should fail on a non-2xx status")
+}
@@ -0,0 +1,65 @@
+package notifications
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// DefaultPushoverURL is the Pushover message API
+const DefaultPushoverURL = "https://api.pushover.net/1/messages.json"
+
+// pushoverMaxMessage is the longest message Pushover accepts, in characters
+const pushoverMaxMessage = 1024
+
+// PushoverConfig represents the application and user push notifications are sent with and to
+type PushoverConfig struct {
+	URL   string // Message API URL, DefaultPushoverURL when empty
+	Token string // Application API token
+	User  string // User or group key
+}
+
+// PushoverChannel sends messages as Pushover notifications
+type PushoverChannel struct {
+	client *http.Client
+	cfg    PushoverConfig
+}
+
+// NewPushoverChannel creates a channel sending to the configured user with the given client
+func NewPushoverChannel(client *http.Client, cfg PushoverConfig) Channel {
+	if cfg.URL == "" {
+		cfg.URL = DefaultPushoverURL
+	}
+	return &PushoverChannel{
+		client: client,
+		cfg:    cfg,
+	}
+}
+
+// Name implements Channel
+func (c *PushoverChannel) Name() string {
+	return "pushover"
+}
+
+// Send implements Channel. Messages longer than Pushover accepts are truncated.
+func (c *PushoverChannel) Send(ctx context.Context, msg Message) error {
+	body := []rune(msg.Body)
+	if len(body) > pushoverMaxMessage {
+		body = append(body[:pushoverMaxMessage-1], '…')
+	}
+	form := url.Values{
+		"token":   {c.cfg.Token},
+		"user":    {c.cfg.User},
+		"title":   {msg.Subject},
+		"message": {string(body)},
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.cfg.URL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return fmt.Errorf("failed to create pushover request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return send(c.client, req)
+}
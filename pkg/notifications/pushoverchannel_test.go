@@ -0,0 +1,36 @@
+package notifications_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/kazemisoroush/assistant/pkg/notifications"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPushoverChannel_Send(t *testing.T) {
+	// Arrange
+	var form url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.NoError(t, r.ParseForm(), "Send() should post a form")
+		form = r.PostForm
+	}))
+	defer server.Close()
+	channel := notifications.NewPushoverChannel(server.Client(), notifications.PushoverConfig{URL: server.URL, Token: "app", User: "me"})
+
+	// Act
+	err := channel.Send(context.Background(), notifications.Message{Subject: "Budgets", Body: strings.Repeat("x", 2000)})
+
+	// Assert
+	require.NoError(t, err, "Send() error should be nil")
+	assert.Equal(t, "app", form.Get("token"), "Send() should send the application token")
+	assert.Equal(t, "me", form.Get("user"), "Send() should send the user key")
+	assert.Equal(t, "Budgets", form.Get("title"), "Send() should send the subject as title")
+	assert.Equal(t, 1024, utf8.RuneCountInString(form.Get("message")), "Send() should truncate long messages")
+}
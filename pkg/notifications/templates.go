@@ -0,0 +1,175 @@
+package notifications
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	htmltemplate "html/template"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+// Template represents the subject and body templates of an event's notifications
+type Template struct {
+	Subject string
+	Body    string
+	HTML    string // Optional HTML body, escaped as HTML when rendered
+}
+
+// builtinTemplates are the templates of the events notified about
+var builtinTemplates = map[Event]Template{
+	EventReminders: {
+		Subject: "{{len .}} document(s) expiring soon",
+		Body:    "{{range .}}- {{.}}\n{{end}}",
+	},
+	EventBudgetAlerts: {
+		Subject: "{{len .}} budget(s) crossed a spending threshold",
+		Body:    "{{range .}}- {{.}}\n{{end}}",
+	},
+	EventScrapeFailure: {
+		Subject: "Scraping records failed",
+		Body:    "{{.}}\n",
+	},
+	EventDigest: {
+		Subject: "Your {{.Period}} digest: {{len .New}} new record(s)",
+		Body:    digestBody,
+		HTML:    digestHTML,
+	},
+}
+
+// digestBody is the plain text body of the digest
+const digestBody = `Activity from {{.From.Format "2006-01-02"}} to {{.To.Format "2006-01-02"}}
+
+New records: {{len .New}}
+{{range .New}}- {{.RecordType}} {{.RecordID}}
+{{end}}
+Needing review: {{len .Unreviewed}}
+{{range .Unreviewed}}- {{.RecordType}} {{.RecordID}}{{with .Reason}}: {{.}}{{end}}
+{{end}}
+Expiring soon: {{len .Expiring}}
+{{range .Expiring}}- {{.}}
+{{end}}
+Spent this month:
+{{range .Spend}}- {{printf "%.2f" .Total}} {{.Currency}} across {{.Count}} receipt(s)
+{{else}}- nothing
+{{end}}`
+
+// digestHTML is the HTML body of the digest
+const digestHTML = `<!DOCTYPE html>
+<html>
+<body style="font-family: sans-serif;">
+<h2>Your {{.Period}} digest</h2>
+<p>Activity from {{.From.Format "2006-01-02"}} to {{.To.Format "2006-01-02"}}</p>
+
+<h3>New records ({{len .New}})</h3>
+{{if .New}}<ul>{{range .New}}<li>{{.RecordType}} <code>{{.RecordID}}</code></li>{{end}}</ul>{{else}}<p>None</p>{{end}}
+
+<h3>Needing review ({{len .Unreviewed}})</h3>
+{{if .Unreviewed}}<ul>{{range .Unreviewed}}<li>{{.RecordType}} <code>{{.RecordID}}</code>{{with .Reason}}: {{.}}{{end}}</li>{{end}}</ul>{{else}}<p>None</p>{{end}}
+
+<h3>Expiring soon ({{len .Expiring}})</h3>
+{{if .Expiring}}<ul>{{range .Expiring}}<li>{{.}}</li>{{end}}</ul>{{else}}<p>None</p>{{end}}
+
+<h3>Spent this month</h3>
+{{if .Spend}}<table>{{range .Spend}}<tr><td>{{.Currency}}</td><td style="text-align: right;">{{printf "%.2f" .Total}}</td><td>{{.Count}} receipt(s)</td></tr>{{end}}</table>{{else}}<p>Nothing</p>{{end}}
+</body>
+</html>
+`
+
+// parsedTemplate is a Template parsed for rendering
+type parsedTemplate struct {
+	subject *template.Template
+	body    *template.Template
+	html    *htmltemplate.Template // nil without an HTML body
+}
+
+// Templates renders the notifications of events
+type Templates struct {
+	templates map[Event]parsedTemplate
+}
+
+// NewTemplates creates the built-in templates. Files named
+// <event>.subject.tmpl, <event>.body.tmpl and <event>.html.tmpl in
+// overrideDir replace the built-in templates of the event; an empty overrideDir or a missing
+// directory keeps the built-ins.
+func NewTemplates(overrideDir string) (*Templates, error) {
+	t := &Templates{templates: make(map[Event]parsedTemplate)}
+	for event, builtin := range builtinTemplates {
+		if overrideDir != "" {
+			var err error
+			if builtin, err = loadOverride(overrideDir, event, builtin); err != nil {
+				return nil, err
+			}
+		}
+		if err := t.add(event, builtin); err != nil {
+			return nil, err
+		}
+	}
+	return t, nil
+}
+
+// Render fills the event's templates with data
+func (t *Templates) Render(event Event, data any) (Message, error) {
+	tmpl, ok := t.templates[event]
+	if !ok {
+		return Message{}, fmt.Errorf("unknown notification event: %s", event)
+	}
+
+	var subject, body bytes.Buffer
+	if err := tmpl.subject.Execute(&subject, data); err != nil {
+		return Message{}, fmt.Errorf("failed to render %s subject: %w", event, err)
+	}
+	if err := tmpl.body.Execute(&body, data); err != nil {
+		return Message{}, fmt.Errorf("failed to render %s body: %w", event, err)
+	}
+	msg := Message{
+		Event:   event,
+		Subject: strings.TrimSpace(subject.String()),
+		Body:    body.String(),
+		Data:    data,
+	}
+	if tmpl.html != nil {
+		var html bytes.Buffer
+		if err := tmpl.html.Execute(&html, data); err != nil {
+			return Message{}, fmt.Errorf("failed to render %s HTML body: %w", event, err)
+		}
+		msg.HTML = html.String()
+	}
+	return msg, nil
+}
+
+func (t *Templates) add(event Event, tmpl Template) error {
+	subject, err := template.New(string(event) + ".subject").Parse(tmpl.Subject)
+	if err != nil {
+		return fmt.Errorf("failed to parse %s subject: %w", event, err)
+	}
+	body, err := template.New(string(event) + ".body").Parse(tmpl.Body)
+	if err != nil {
+		return fmt.Errorf("failed to parse %s body: %w", event, err)
+	}
+	parsed := parsedTemplate{subject: subject, body: body}
+	if tmpl.HTML != "" {
+		if parsed.html, err = htmltemplate.New(string(event) + ".html").Parse(tmpl.HTML); err != nil {
+			return fmt.Errorf("failed to parse %s HTML body: %w", event, err)
+		}
+	}
+	t.templates[event] = parsed
+	return nil
+}
+
+// loadOverride replaces the parts of the template overridden in dir
+func loadOverride(dir string, event Event, tmpl Template) (Template, error) {
+	for part, text := range map[string]*string{"subject": &tmpl.Subject, "body": &tmpl.Body, "html": &tmpl.HTML} {
+		data, err := os.ReadFile(filepath.Join(dir, fmt.Sprintf("%s.%s.tmpl", event, part)))
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return Template{}, fmt.Errorf("failed to read %s %s override: %w", event, part, err)
+		}
+		*text = string(data)
+	}
+	return tmpl, nil
+}
@@ -0,0 +1,122 @@
+package notifications_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/kazemisoroush/assistant/pkg/analytics"
+	"github.com/kazemisoroush/assistant/pkg/dashboard"
+	"github.com/kazemisoroush/assistant/pkg/digest"
+	"github.com/kazemisoroush/assistant/pkg/notifications"
+	"github.com/kazemisoroush/assistant/pkg/records"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// item is notification data described by its String method
+type item string
+
+func (i item) String() string {
+	return "item " + string(i)
+}
+
+func TestTemplates_Render_Builtin(t *testing.T) {
+	// Arrange
+	templates, err := notifications.NewTemplates("")
+	require.NoError(t, err, "NewTemplates() error should be nil")
+	data := []item{"a", "b"}
+
+	// Act
+	msg, err := templates.Render(notifications.EventBudgetAlerts, data)
+
+	// Assert
+	require.NoError(t, err, "Render() error should be nil")
+	assert.Equal(t, notifications.EventBudgetAlerts, msg.Event, "Render() should set the event")
+	assert.Equal(t, "2 budget(s) crossed a spending threshold", msg.Subject, "Render() should render the subject")
+	assert.Equal(t, "- item a\n- item b\n", msg.Body, "Render() should describe every item")
+	assert.Equal(t, data, msg.Data, "Render() should keep the data")
+}
+
+func TestTemplates_Render_UnknownEvent(t *testing.T) {
+	// Arrange
+	templates, err := notifications.NewTemplates("")
+	require.NoError(t, err, "NewTemplates() error should be nil")
+
+	// Act
+	_, err = templates.Render("unknown", nil)
+
+	// Assert
+	assert.Error(t, err, "Render() should reject unknown events")
+}
+
+func TestNewTemplates_Override(t *testing.T) {
+	// Arrange
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "scrape_failure.subject.tmpl"), []byte("Scrape broke\n"), 0600))
+
+	// Act
+	templates, err := notifications.NewTemplates(dir)
+
+	// Assert
+	require.NoError(t, err, "NewTemplates() error should be nil")
+	msg, err := templates.Render(notifications.EventScrapeFailure, "disk full")
+	require.NoError(t, err, "Render() error should be nil")
+	assert.Equal(t, "Scrape broke", msg.Subject, "Render() should use the overridden subject")
+	assert.Equal(t, "disk full\n", msg.Body, "Render() should keep the built-in body")
+}
+
+func TestNewTemplates_InvalidOverride(t *testing.T) {
+	// Arrange
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "reminders.body.tmpl"), []byte("{{range .}}"), 0600))
+
+	// Act
+	_, err := notifications.NewTemplates(dir)
+
+	// Assert
+	assert.Error(t, err, "NewTemplates() should reject templates that do not parse")
+}
+
+func TestTemplates_Render_DigestHTML(t *testing.T) {
+	// Arrange
+	templates, err := notifications.NewTemplates("")
+	require.NoError(t, err, "NewTemplates() error should be nil")
+	now := time.Date(2024, 4, 20, 9, 0, 0, 0, time.UTC)
+	data := digest.Digest{
+		Period:     digest.PeriodWeekly,
+		From:       now.AddDate(0, 0, -7),
+		To:         now,
+		New:        []dashboard.Entry{{RecordID: "rec-1", RecordType: records.RecordTypeReceipt}},
+		Unreviewed: []dashboard.Entry{{RecordID: "rec-2", RecordType: records.RecordTypeOther, Reason: "<unreadable>"}},
+		Spend:      []analytics.Bucket{{Key: "total", Currency: "EUR", Total: 120.5, Count: 3}},
+	}
+
+	// Act
+	msg, err := templates.Render(notifications.EventDigest, data)
+
+	// Assert
+	require.NoError(t, err, "Render() error should be nil")
+	assert.Equal(t, "Your weekly digest: 1 new record(s)", msg.Subject, "Render() should render the subject")
+	assert.Contains(t, msg.Body, "- receipt rec-1\n", "Render() should list new records in the text body")
+	assert.Contains(t, msg.Body, "- 120.50 EUR across 3 receipt(s)\n", "Render() should total the spend in the text body")
+	assert.Contains(t, msg.HTML, "<li>receipt <code>rec-1</code></li>", "Render() should list new records in the HTML body")
+	assert.Contains(t, msg.HTML, "&lt;unreadable&gt;", "Render() should escape data in the HTML body")
+}
+
+func TestNewTemplates_OverrideHTML(t *testing.T) {
+	// Arrange
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "scrape_failure.html.tmpl"), []byte("<p>{{.}}</p>"), 0600))
+
+	// Act
+	templates, err := notifications.NewTemplates(dir)
+
+	// Assert
+	require.NoError(t, err, "NewTemplates() error should be nil")
+	msg, err := templates.Render(notifications.EventScrapeFailure, "disk <full>")
+	require.NoError(t, err, "Render() error should be nil")
+	assert.Equal(t, "<p>disk &lt;full&gt;</p>", msg.HTML, "Render() should render the HTML override")
+	assert.Equal(t, "disk <full>\n", msg.Body, "Render() should keep the text body")
+}
@@ -0,0 +1,59 @@
+package notifications
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// WebhookChannel posts messages as JSON to a URL
+type WebhookChannel struct {
+	client *http.Client
+	url    string
+}
+
+// NewWebhookChannel creates a channel posting to url with the given client
+func NewWebhookChannel(client *http.Client, url string) Channel {
+	return &WebhookChannel{
+		client: client,
+		url:    url,
+	}
+}
+
+// Name implements Channel
+func (c *WebhookChannel) Name() string {
+	return "webhook"
+}
+
+// Send implements Channel
+func (c *WebhookChannel) Send(ctx context.Context, msg Message) error {
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("failed to marshal notification: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	return send(c.client, req)
+}
+
+// send performs the request and fails on a non-2xx status
+func send(client *http.Client, req *http.Request) error {
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to post notification: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("%s returned status %d: %s", req.URL.Host, resp.StatusCode, string(respBody))
+	}
+	return nil
+}
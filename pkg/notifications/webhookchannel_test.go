@@ -0,0 +1,54 @@
+package notifications_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kazemisoroush/assistant/pkg/notifications"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookChannel_Send(t *testing.T) {
+	// Arrange
+	var payload map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method, "Send() should POST")
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&payload), "Send() should send JSON")
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+	channel := notifications.NewWebhookChannel(server.Client(), server.URL)
+
+	// Act
+	err := channel.Send(context.Background(), notifications.Message{
+		Event:   notifications.EventScrapeFailure,
+		Subject: "Scraping records failed",
+		Body:    "disk full\n",
+		Data:    "disk full",
+	})
+
+	// Assert
+	require.NoError(t, err, "Send() error should be nil")
+	assert.Equal(t, "scrape_failure", payload["event"], "Send() should post the event")
+	assert.Equal(t, "Scraping records failed", payload["subject"], "Send() should post the subject")
+	assert.Equal(t, "disk full", payload["data"], "Send() should post the data")
+}
+
+func TestWebhookChannel_Send_ErrorStatus(t *testing.T) {
+	// Arrange
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		http.Error(w, "boom", http.StatusBadGateway)
+	}))
+	defer server.Close()
+	channel := notifications.NewWebhookChannel(server.Client(), server.URL)
+
+	// Act
+	err := channel.Send(context.Background(), notifications.Message{Subject: "s"})
+
+	// Assert
+	assert.Error(t, err, "Send() should fail on a non-2xx status")
+}
@@ -0,0 +1,154 @@
+package obsidian
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/kazemisoroush/assistant/pkg/records"
+)
+
+// titleLength is the longest title taken from a record's summary
+const titleLength = 80
+
+// plainKey matches front matter keys that need no quoting
+var plainKey = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// unsafeNameChars are not allowed in note names or break wiki-links
+var unsafeNameChars = strings.NewReplacer(
+	"/", "-", "\\", "-", ":", "-", "*", "-", "?", "-", "\"", "-",
+	"<", "-", ">", "-", "|", "-", "#", "-", "^", "-", "[", "-", "]", "-",
+)
+
+// bodyKeys are metadata keys rendered in the note body instead of the front matter
+var bodyKeys = []string{records.MetaDescription, records.MetaLinks}
+
+// note holds what a note is rendered from
+type note struct {
+	record     records.Record
+	titles     map[string]string // Titles of all records by ID, for link labels
+	backlinks  []string          // IDs of the records linking to this one
+	attachment string            // Vault path of the copied original file, if any
+}
+
+// noteName returns the name of a record's note, which wiki-links refer to
+func noteName(id string) string {
+	return unsafeNameChars.Replace(id)
+}
+
+// notePath returns the vault path of a record's note: one folder per record type
+func notePath(rec records.Record) string {
+	recordType := rec.Type
+	if recordType == "" {
+		recordType = records.RecordTypeOther
+	}
+	return path.Join(noteName(string(recordType)), noteName(rec.ID)+".md")
+}
+
+// title names a record by the first line of its summary, or by its type and date
+func title(rec records.Record) string {
+	if line, _, _ := strings.Cut(strings.TrimSpace(rec.Description()), "\n"); line != "" {
+		if runes := []rune(line); len(runes) > titleLength {
+			line = string(runes[:titleLength]) + "…"
+		}
+		return line
+	}
+	recordType := string(rec.Type)
+	if recordType == "" {
+		recordType = string(records.RecordTypeOther)
+	}
+	return fmt.Sprintf("%s%s %s", strings.ToUpper(recordType[:1]), recordType[1:], rec.CreatedAt.Format(time.DateOnly))
+}
+
+// render returns the Markdown of the note
+func (n note) render() string {
+	var b strings.Builder
+	n.writeFrontMatter(&b)
+
+	rec := n.record
+	fmt.Fprintf(&b, "\n# %s\n", n.titles[rec.ID])
+	if description := strings.TrimSpace(rec.Description()); description != "" {
+		b.WriteString("\n> " + strings.ReplaceAll(description, "\n", "\n> ") + "\n")
+	}
+	if content := strings.TrimSpace(rec.Content); content != "" {
+		b.WriteString("\n" + content + "\n")
+	}
+	n.writeLinks(&b, "Related", rec.Links())
+	n.writeLinks(&b, "Linked from", n.backlinks)
+	if n.attachment != "" {
+		fmt.Fprintf(&b, "\n## Attachments\n\n![[%s]]\n", n.attachment)
+	}
+	return b.String()
+}
+
+// writeFrontMatter writes the record fields and metadata as YAML front
+// matter. Values are written as JSON, which is valid YAML.
+func (n note) writeFrontMatter(b *strings.Builder) {
+	rec := n.record
+	b.WriteString("---\n")
+	writeField(b, "id", rec.ID)
+	writeField(b, "type", rec.Type)
+	writeField(b, "created", rec.CreatedAt.Format(time.RFC3339))
+	writeField(b, "updated", rec.UpdatedAt.Format(time.RFC3339))
+	if rec.ExpiresAt != nil {
+		writeField(b, "expires", rec.ExpiresAt.Format(time.DateOnly))
+	}
+	writeField(b, "aliases", []string{n.titles[rec.ID]})
+	writeField(b, "tags", tags(rec))
+
+	keys := make([]string, 0, len(rec.Metadata))
+	for key := range rec.Metadata {
+		if !slices.Contains(bodyKeys, key) {
+			keys = append(keys, key)
+		}
+	}
+	slices.Sort(keys)
+	for _, key := range keys {
+		writeField(b, key, rec.Metadata[key])
+	}
+	b.WriteString("---\n")
+}
+
+// writeField writes a front matter field, quoting keys that need it
+func writeField(b *strings.Builder, key string, value any) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return
+	}
+	if !plainKey.MatchString(key) {
+		quoted, _ := json.Marshal(key)
+		key = string(quoted)
+	}
+	fmt.Fprintf(b, "%s: %s\n", key, data)
+}
+
+// writeLinks writes a section of wiki-links to the records, labelled with their titles
+func (n note) writeLinks(b *strings.Builder, heading string, ids []string) {
+	if len(ids) == 0 {
+		return
+	}
+	fmt.Fprintf(b, "\n## %s\n\n", heading)
+	for _, id := range ids {
+		label, ok := n.titles[id]
+		if !ok {
+			label = id
+		}
+		fmt.Fprintf(b, "- [[%s|%s]]\n", noteName(id), strings.NewReplacer("|", "-", "[", "(", "]", ")").Replace(label))
+	}
+}
+
+// tags returns the record type and tags in the form Obsidian accepts, without spaces
+func tags(rec records.Record) []string {
+	tags := []string{}
+	for _, tag := range append([]string{string(rec.Type)}, rec.Tags...) {
+		tag = strings.Join(strings.Fields(strings.ToLower(tag)), "-")
+		if tag != "" && !slices.Contains(tags, tag) {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
@@ -0,0 +1,26 @@
+// Package obsidian exports records as Markdown notes into an Obsidian vault.
+// Every record is a note with its metadata as front matter, wiki-links to the
+// records it is linked with and a reference to its original file. Exporting
+// again updates the notes that changed and removes the notes of deleted
+// records, so the vault stays in sync.
+package obsidian
+
+import (
+	"context"
+)
+
+// Result counts what an export changed in the vault
+type Result struct {
+	Written     int `json:"written"`     // Notes created or updated
+	Unchanged   int `json:"unchanged"`   // Notes already up to date
+	Removed     int `json:"removed"`     // Notes and attachments of records no longer stored
+	Attachments int `json:"attachments"` // Original files copied into the vault
+}
+
+// Exporter exports records into a vault
+//
+//go:generate mockgen -destination=./mocks/mock_exporter.go -mock_names=Exporter=MockExporter -package=mocks . Exporter
+type Exporter interface {
+	// Export syncs the notes in the vault at dir with the stored records
+	Export(ctx context.Context, dir string) (Result, error)
+}
@@ -0,0 +1,219 @@
+package obsidian
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path"
+	"path/filepath"
+
+	"github.com/kazemisoroush/assistant/pkg/records"
+	"github.com/kazemisoroush/assistant/pkg/records/storage"
+)
+
+// manifestFile lists what the last export wrote, so notes of deleted records
+// are removed while notes created in the vault by hand are left alone
+const manifestFile = ".assistant-export.json"
+
+// attachmentsDir is the vault folder original files are copied into
+const attachmentsDir = "attachments"
+
+// Config represents how records are exported
+type Config struct {
+	Attachments bool // Copy the original files into the vault
+}
+
+// manifest maps record IDs to the vault paths exported for them
+type manifest struct {
+	Notes       map[string]string `json:"notes"`
+	Attachments map[string]string `json:"attachments"`
+}
+
+// RecordExporter exports the stored records. Records are read twice: first
+// for the titles and backlinks every note links with, then to write the notes.
+type RecordExporter struct {
+	storage storage.Storage
+	cfg     Config
+}
+
+// NewRecordExporter creates a new vault exporter
+func NewRecordExporter(storage storage.Storage, cfg Config) Exporter {
+	return &RecordExporter{
+		storage: storage,
+		cfg:     cfg,
+	}
+}
+
+// Export implements Exporter
+func (e *RecordExporter) Export(ctx context.Context, dir string) (Result, error) {
+	titles, backlinks, err := e.index(ctx)
+	if err != nil {
+		return Result{}, err
+	}
+	previous, err := loadManifest(dir)
+	if err != nil {
+		return Result{}, err
+	}
+
+	current := manifest{Notes: map[string]string{}, Attachments: map[string]string{}}
+	var result Result
+	err = e.storage.Each(ctx, "", func(rec records.Record) error {
+		n := note{record: rec, titles: titles, backlinks: backlinks[rec.ID]}
+		n.attachment = e.attach(ctx, dir, rec, &result)
+		if n.attachment != "" {
+			current.Attachments[rec.ID] = n.attachment
+		}
+
+		current.Notes[rec.ID] = notePath(rec)
+		written, err := writeIfChanged(filepath.Join(dir, filepath.FromSlash(current.Notes[rec.ID])), []byte(n.render()))
+		if err != nil {
+			return fmt.Errorf("failed to write note of record %s: %w", rec.ID, err)
+		}
+		if written {
+			result.Written++
+		} else {
+			result.Unchanged++
+		}
+		return nil
+	})
+	if err != nil {
+		return result, fmt.Errorf("failed to export records: %w", err)
+	}
+
+	result.Removed += removeStale(dir, previous.Notes, current.Notes)
+	result.Removed += removeStale(dir, previous.Attachments, current.Attachments)
+	return result, saveManifest(dir, current)
+}
+
+// index returns the title of every record and the records linking to each
+func (e *RecordExporter) index(ctx context.Context) (map[string]string, map[string][]string, error) {
+	titles := map[string]string{}
+	backlinks := map[string][]string{}
+	err := e.storage.Each(ctx, "", func(rec records.Record) error {
+		titles[rec.ID] = title(rec)
+		for _, id := range rec.Links() {
+			backlinks[id] = append(backlinks[id], rec.ID)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list records: %w", err)
+	}
+	return titles, backlinks, nil
+}
+
+// attach copies the record's original file into the vault unless an
+// identical copy is there, and returns its vault path. Records whose file is
+// gone are exported without attachment.
+func (e *RecordExporter) attach(ctx context.Context, dir string, rec records.Record, result *Result) string {
+	source := rec.SourcePath()
+	if !e.cfg.Attachments || source == "" {
+		return ""
+	}
+	vaultPath := path.Join(attachmentsDir, noteName(rec.ID)+filepath.Ext(source))
+	copied, err := copyIfChanged(source, filepath.Join(dir, filepath.FromSlash(vaultPath)))
+	if err != nil {
+		slog.WarnContext(ctx, "Failed to copy original file into vault", "record_id", rec.ID, "error", err)
+		return ""
+	}
+	if copied {
+		result.Attachments++
+	}
+	return vaultPath
+}
+
+// writeIfChanged writes data to the file unless it already holds it, and
+// reports whether it wrote
+func writeIfChanged(file string, data []byte) (bool, error) {
+	existing, err := os.ReadFile(file)
+	if err == nil && bytes.Equal(existing, data) {
+		return false, nil
+	}
+	if err := os.MkdirAll(filepath.Dir(file), 0o750); err != nil {
+		return false, err
+	}
+	return true, os.WriteFile(file, data, 0o600)
+}
+
+// copyIfChanged copies src to dst unless dst has the same size and
+// modification time, and reports whether it copied
+func copyIfChanged(src, dst string) (copied bool, err error) {
+	info, err := os.Stat(src)
+	if err != nil {
+		return false, err
+	}
+	if existing, err := os.Stat(dst); err == nil && existing.Size() == info.Size() && existing.ModTime().Equal(info.ModTime()) {
+		return false, nil
+	}
+	if err := os.MkdirAll(filepath.Dir(dst), 0o750); err != nil {
+		return false, err
+	}
+
+	in, err := os.Open(src)
+	if err != nil {
+		return false, err
+	}
+	defer func() { _ = in.Close() }()
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+	if err != nil {
+		return false, err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		_ = out.Close()
+		return false, err
+	}
+	if err := out.Close(); err != nil {
+		return false, err
+	}
+	// The copy keeps the original's modification time, so the next export recognizes it
+	return true, os.Chtimes(dst, info.ModTime(), info.ModTime())
+}
+
+// removeStale deletes the files exported before that are not exported any
+// more, and returns how many it deleted
+func removeStale(dir string, previous, current map[string]string) int {
+	removed := 0
+	for id, vaultPath := range previous {
+		if current[id] == vaultPath {
+			continue
+		}
+		if err := os.Remove(filepath.Join(dir, filepath.FromSlash(vaultPath))); err == nil {
+			removed++
+		}
+	}
+	return removed
+}
+
+// loadManifest reads what the last export wrote, empty if the vault was never exported to
+func loadManifest(dir string) (manifest, error) {
+	m := manifest{Notes: map[string]string{}, Attachments: map[string]string{}}
+	data, err := os.ReadFile(filepath.Join(dir, manifestFile))
+	if errors.Is(err, fs.ErrNotExist) {
+		return m, nil
+	}
+	if err != nil {
+		return m, fmt.Errorf("failed to read export manifest: %w", err)
+	}
+	if err := json.Unmarshal(data, &m); err != nil {
+		return m, fmt.Errorf("failed to decode export manifest: %w", err)
+	}
+	return m, nil
+}
+
+// saveManifest records what this export wrote
+func saveManifest(dir string, m manifest) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal export manifest: %w", err)
+	}
+	if _, err := writeIfChanged(filepath.Join(dir, manifestFile), data); err != nil {
+		return fmt.Errorf("failed to write export manifest: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+package obsidian_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/kazemisoroush/assistant/pkg/obsidian"
+	"github.com/kazemisoroush/assistant/pkg/records"
+	storagemocks "github.com/kazemisoroush/assistant/pkg/records/storage/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+// eachOf returns a Storage.Each implementation visiting recs
+func eachOf(recs []records.Record) func(context.Context, records.RecordType, func(records.Record) error) error {
+	return func(_ context.Context, _ records.RecordType, fn func(records.Record) error) error {
+		for _, rec := range recs {
+			if err := fn(rec); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// readNote returns the content of a note in the vault
+func readNote(t *testing.T, vault, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(vault, path))
+	require.NoError(t, err, "note %s should exist", path)
+	return string(data)
+}
+
+func TestRecordExporter_Export(t *testing.T) {
+	// Arrange
+	vault := t.TempDir()
+	scan := filepath.Join(t.TempDir(), "warranty.pdf")
+	require.NoError(t, os.WriteFile(scan, []byte("%PDF-1.4"), 0o600), "failed to write scan")
+	created := time.Date(2024, 3, 5, 10, 0, 0, 0, time.UTC)
+	recs := []records.Record{
+		{
+			ID: "receipt-1", Type: records.RecordTypeReceipt, Content: "Laptop 1299 EUR", CreatedAt: created, UpdatedAt: created,
+			Tags:     []string{"Home Office"},
+			Metadata: map[string]interface{}{records.MetaDescription: "Laptop from the electronics store", "vendor": "Electronics Store"},
+		},
+		{
+			ID: "warranty-1", Type: records.RecordTypeWarranty, Content: "Two year warranty", CreatedAt: created, UpdatedAt: created,
+			Metadata: map[string]interface{}{records.MetaLinks: []string{"receipt-1"}, records.MetaSourcePath: scan},
+		},
+	}
+	ctrl := gomock.NewController(t)
+	storage := storagemocks.NewMockStorage(ctrl)
+	storage.EXPECT().Each(gomock.Any(), records.RecordType(""), gomock.Any()).DoAndReturn(eachOf(recs)).Times(2)
+	exporter := obsidian.NewRecordExporter(storage, obsidian.Config{Attachments: true})
+
+	// Act
+	result, err := exporter.Export(context.Background(), vault)
+
+	// Assert
+	require.NoError(t, err, "Export() error should be nil")
+	assert.Equal(t, obsidian.Result{Written: 2, Attachments: 1}, result, "Export() should write every note and copy the scan")
+
+	receipt := readNote(t, vault, "receipt/receipt-1.md")
+	assert.Contains(t, receipt, `vendor: "Electronics Store"`, "note should carry the metadata as front matter")
+	assert.Contains(t, receipt, `tags: ["receipt","home-office"]`, "note should carry tags Obsidian accepts")
+	assert.Contains(t, receipt, "# Laptop from the electronics store", "note should be titled by the summary")
+	assert.Contains(t, receipt, "## Linked from\n\n- [[warranty-1|Warranty 2024-03-05]]", "note should link back to the records linking to it")
+
+	warranty := readNote(t, vault, "warranty/warranty-1.md")
+	assert.Contains(t, warranty, "- [[receipt-1|Laptop from the electronics store]]", "note should link to its related records")
+	assert.Contains(t, warranty, "![[attachments/warranty-1.pdf]]", "note should embed the original file")
+	assert.FileExists(t, filepath.Join(vault, "attachments", "warranty-1.pdf"), "Export() should copy the original file")
+}
+
+func TestRecordExporter_Export_Incremental(t *testing.T) {
+	// Arrange
+	vault := t.TempDir()
+	now := time.Date(2024, 3, 5, 10, 0, 0, 0, time.UTC)
+	kept := records.Record{ID: "kept", Type: records.RecordTypeReceipt, Content: "Coffee", CreatedAt: now, UpdatedAt: now}
+	deleted := records.Record{ID: "deleted", Type: records.RecordTypeReceipt, Content: "Tea", CreatedAt: now, UpdatedAt: now}
+	ctrl := gomock.NewController(t)
+	storage := storagemocks.NewMockStorage(ctrl)
+	storage.EXPECT().Each(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(eachOf([]records.Record{kept, deleted})).Times(2)
+	storage.EXPECT().Each(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(eachOf([]records.Record{kept})).Times(2)
+	exporter := obsidian.NewRecordExporter(storage, obsidian.Config{})
+	handwritten := filepath.Join(vault, "receipt", "my notes.md")
+	_, err := exporter.Export(context.Background(), vault)
+	require.NoError(t, err, "first Export() error should be nil")
+	require.NoError(t, os.WriteFile(handwritten, []byte("mine"), 0o600), "failed to write handwritten note")
+
+	// Act
+	result, err := exporter.Export(context.Background(), vault)
+
+	// Assert
+	require.NoError(t, err, "Export() error should be nil")
+	assert.Equal(t, obsidian.Result{Unchanged: 1, Removed: 1}, result, "Export() should only remove the note of the deleted record")
+	assert.NoFileExists(t, filepath.Join(vault, "receipt", "deleted.md"), "Export() should remove the note of the deleted record")
+	assert.FileExists(t, handwritten, "Export() should leave notes it did not write alone")
+}
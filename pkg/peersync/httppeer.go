@@ -0,0 +1,88 @@
+package peersync
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/kazemisoroush/assistant/pkg/requestid"
+)
+
+// HTTPPeer is the Replica of a peer reached over its API
+type HTTPPeer struct {
+	client *http.Client
+	url    string
+	token  string
+}
+
+// NewHTTPPeer creates the replica of the peer served at the base URL,
+// authenticating with the shared sync token
+func NewHTTPPeer(client *http.Client, baseURL, token string) Replica {
+	return &HTTPPeer{
+		client: client,
+		url:    strings.TrimSuffix(baseURL, "/") + ChangesPath,
+		token:  token,
+	}
+}
+
+// Changes implements Replica
+func (p *HTTPPeer) Changes(ctx context.Context, since int64, limit int) (Batch, error) {
+	query := url.Values{}
+	query.Set("since", strconv.FormatInt(since, 10))
+	query.Set("limit", strconv.Itoa(limit))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.url+"?"+query.Encode(), nil)
+	if err != nil {
+		return Batch{}, fmt.Errorf("failed to create changes request: %w", err)
+	}
+
+	var batch Batch
+	if err := p.do(req, &batch); err != nil {
+		return Batch{}, fmt.Errorf("failed to get changes: %w", err)
+	}
+	return batch, nil
+}
+
+// Apply implements Replica
+func (p *HTTPPeer) Apply(ctx context.Context, changes []Change) (Result, error) {
+	body, err := json.Marshal(Batch{Changes: changes})
+	if err != nil {
+		return Result{}, fmt.Errorf("failed to marshal changes: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.url, bytes.NewReader(body))
+	if err != nil {
+		return Result{}, fmt.Errorf("failed to create apply request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	var result Result
+	if err := p.do(req, &result); err != nil {
+		return Result{}, fmt.Errorf("failed to apply changes: %w", err)
+	}
+	return result, nil
+}
+
+// do sends the request and decodes the JSON response into v
+func (p *HTTPPeer) do(req *http.Request, v any) error {
+	req.Header.Set("Authorization", "Bearer "+p.token)
+	if id := requestid.FromContext(req.Context()); id != "" {
+		req.Header.Set(requestid.Header, id)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("peer returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
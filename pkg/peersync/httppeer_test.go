@@ -0,0 +1,75 @@
+package peersync_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kazemisoroush/assistant/pkg/peersync"
+	"github.com/kazemisoroush/assistant/pkg/records"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTPPeer_Changes(t *testing.T) {
+	// Arrange
+	var gotAuth, gotQuery string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuth = r.Header.Get("Authorization")
+		gotQuery = r.URL.RawQuery
+		_ = json.NewEncoder(w).Encode(peersync.Batch{
+			Changes: []peersync.Change{{Seq: 8, RecordID: "a", Version: peersync.Version{"server": 1}, Record: &records.Record{ID: "a"}}},
+			Next:    8,
+		})
+	}))
+	defer server.Close()
+	peer := peersync.NewHTTPPeer(server.Client(), server.URL+"/", "secret")
+
+	// Act
+	batch, err := peer.Changes(context.Background(), 7, 50)
+
+	// Assert
+	require.NoError(t, err, "Changes() error should be nil")
+	assert.Equal(t, "Bearer secret", gotAuth, "Changes() should authenticate with the sync token")
+	assert.Equal(t, "limit=50&since=7", gotQuery, "Changes() should ask for the changes after the sequence")
+	require.Len(t, batch.Changes, 1, "Changes() should decode the batch")
+	assert.Equal(t, int64(8), batch.Next, "Changes() should decode the batch")
+}
+
+func TestHTTPPeer_Apply(t *testing.T) {
+	// Arrange
+	var got peersync.Batch
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, peersync.ChangesPath, r.URL.Path, "Apply() should post to the changes endpoint")
+		_ = json.NewDecoder(r.Body).Decode(&got)
+		_ = json.NewEncoder(w).Encode(peersync.Result{Applied: 1})
+	}))
+	defer server.Close()
+	peer := peersync.NewHTTPPeer(server.Client(), server.URL, "secret")
+
+	// Act
+	result, err := peer.Apply(context.Background(), []peersync.Change{{RecordID: "a", Deleted: true, Version: peersync.Version{"laptop": 2}}})
+
+	// Assert
+	require.NoError(t, err, "Apply() error should be nil")
+	assert.Equal(t, peersync.Result{Applied: 1}, result, "Apply() should decode the result")
+	require.Len(t, got.Changes, 1, "Apply() should send the changes")
+	assert.True(t, got.Changes[0].Deleted, "Apply() should send the changes")
+}
+
+func TestHTTPPeer_Changes_Unauthorized(t *testing.T) {
+	// Arrange
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+	}))
+	defer server.Close()
+	peer := peersync.NewHTTPPeer(server.Client(), server.URL, "wrong")
+
+	// Act
+	_, err := peer.Changes(context.Background(), 0, 10)
+
+	// Assert
+	assert.ErrorContains(t, err, "401", "Changes() should report the peer's status")
+}
@@ -0,0 +1,140 @@
+package peersync
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/kazemisoroush/assistant/pkg/records"
+	"github.com/kazemisoroush/assistant/pkg/records/ingestor"
+	"github.com/kazemisoroush/assistant/pkg/records/storage"
+)
+
+// LocalReplica is the Replica of this instance. Changes from peers are
+// ingested, so synced records are indexed like scraped ones.
+type LocalReplica struct {
+	storage  storage.Storage
+	ingestor ingestor.Ingestor
+	log      Log
+	node     string
+}
+
+// NewLocalReplica creates the replica of the node's records. The storage and
+// the ingestor's storage should track changes with the same log and node.
+func NewLocalReplica(storage storage.Storage, ingestor ingestor.Ingestor, log Log, node string) *LocalReplica {
+	return &LocalReplica{
+		storage:  storage,
+		ingestor: ingestor,
+		log:      log,
+		node:     node,
+	}
+}
+
+// Changes implements Replica
+func (r *LocalReplica) Changes(ctx context.Context, since int64, limit int) (Batch, error) {
+	changes, err := r.log.Since(ctx, since, limit)
+	if err != nil {
+		return Batch{}, err
+	}
+
+	batch := Batch{Changes: changes, Next: since, More: len(changes) == limit}
+	for i, change := range changes {
+		batch.Next = change.Seq
+		if change.Deleted {
+			continue
+		}
+		rec, err := r.storage.Get(ctx, change.RecordID)
+		if err != nil {
+			return Batch{}, fmt.Errorf("failed to read changed record %s: %w", change.RecordID, err)
+		}
+		changes[i].Record = &rec
+	}
+	return batch, nil
+}
+
+// Apply implements Replica. Changes failing to be applied are skipped and
+// their errors returned once the others were applied.
+func (r *LocalReplica) Apply(ctx context.Context, changes []Change) (Result, error) {
+	ctx = withApplying(ctx)
+
+	var result Result
+	var errs []error
+	for _, change := range changes {
+		outcome, err := r.apply(ctx, change)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to apply change of record %s: %w", change.RecordID, err))
+			continue
+		}
+		result = result.Add(outcome)
+	}
+	if err := r.ingestor.Flush(ctx); err != nil {
+		errs = append(errs, fmt.Errorf("failed to index synced records: %w", err))
+	}
+	return result, errors.Join(errs...)
+}
+
+// apply applies a peer's change unless the local record already saw it
+func (r *LocalReplica) apply(ctx context.Context, change Change) (Result, error) {
+	if !change.Deleted && change.Record == nil {
+		return Result{}, fmt.Errorf("change carries no record")
+	}
+	local, err := r.log.Get(ctx, change.RecordID)
+	if err != nil {
+		return Result{}, err
+	}
+
+	switch change.Version.Compare(local.Version) {
+	case After:
+		return Result{Applied: 1}, r.write(ctx, change, change.Version)
+	case Concurrent:
+		if !local.Deleted {
+			rec, err := r.storage.Get(ctx, change.RecordID)
+			if err != nil {
+				return Result{}, fmt.Errorf("failed to read conflicting record: %w", err)
+			}
+			local.Record = &rec
+		}
+		// The resolved change descends from both, so the peer takes it on the next sync
+		winner := ResolveConflict(local, change)
+		return Result{Conflicts: 1}, r.write(ctx, winner, local.Version.Merge(change.Version).Increment(r.node))
+	default:
+		return Result{Skipped: 1}, nil
+	}
+}
+
+// write stores or deletes the record as the change left it, and logs the change at the version
+func (r *LocalReplica) write(ctx context.Context, change Change, version Version) error {
+	if change.Deleted {
+		// A record the local instance never had has nothing to delete
+		if _, err := r.storage.Get(ctx, change.RecordID); err == nil {
+			if err := r.ingestor.Delete(ctx, change.RecordID); err != nil {
+				return err
+			}
+		}
+	} else if err := r.ingestor.Ingest(ctx, *change.Record); err != nil {
+		return err
+	}
+	_, err := r.log.Put(ctx, Change{RecordID: change.RecordID, Version: version, Deleted: change.Deleted})
+	return err
+}
+
+// Backfill logs a change to every record changed before sync was enabled,
+// so peers pull them, and returns how many were logged
+func (r *LocalReplica) Backfill(ctx context.Context) (int, error) {
+	logged := 0
+	err := r.storage.Each(ctx, "", func(rec records.Record) error {
+		change, err := r.log.Get(ctx, rec.ID)
+		if err != nil || len(change.Version) > 0 {
+			return err
+		}
+		if _, err := r.log.Put(ctx, Change{RecordID: rec.ID, Version: Version{}.Increment(r.node)}); err != nil {
+			return err
+		}
+		logged++
+		return nil
+	})
+	if err != nil {
+		return logged, fmt.Errorf("failed to backfill change log: %w", err)
+	}
+	return logged, nil
+}
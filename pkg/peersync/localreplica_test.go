@@ -0,0 +1,144 @@
+package peersync_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/kazemisoroush/assistant/pkg/peersync"
+	"github.com/kazemisoroush/assistant/pkg/records"
+	"github.com/kazemisoroush/assistant/pkg/records/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// storageIngestor ingests records straight into the storage
+type storageIngestor struct {
+	storage storage.Storage
+}
+
+func (i storageIngestor) Ingest(ctx context.Context, rec records.Record) error {
+	if _, err := i.storage.Get(ctx, rec.ID); err == nil {
+		return i.storage.Update(ctx, rec)
+	}
+	return i.storage.Store(ctx, rec)
+}
+
+func (i storageIngestor) IngestBatch(ctx context.Context, recs []records.Record) error {
+	return i.storage.StoreBatch(ctx, recs)
+}
+
+func (i storageIngestor) Delete(ctx context.Context, id string) error {
+	return i.storage.Delete(ctx, id)
+}
+
+func (i storageIngestor) Flush(context.Context) error {
+	return nil
+}
+
+// instance is an assistant instance tracking the changes to its records
+type instance struct {
+	storage storage.Storage
+	log     *peersync.SQLiteLog
+	replica *peersync.LocalReplica
+}
+
+// newInstance creates an instance with its own database
+func newInstance(t *testing.T, node string) instance {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "assistant.db")
+	sqliteStorage, err := storage.NewSQLiteStorage(openDB(t, path))
+	require.NoError(t, err, "failed to create storage")
+	log, err := peersync.NewSQLiteLog(openDB(t, path))
+	require.NoError(t, err, "failed to create change log")
+
+	tracking := peersync.NewTrackingStorage(sqliteStorage, log, node)
+	return instance{
+		storage: tracking,
+		log:     log,
+		replica: peersync.NewLocalReplica(tracking, storageIngestor{storage: tracking}, log, node),
+	}
+}
+
+// record returns a record updated at the given time
+func record(id, content string, updated time.Time) records.Record {
+	return records.Record{ID: id, Type: records.RecordTypeReceipt, Content: content, CreatedAt: updated, UpdatedAt: updated}
+}
+
+func TestLocalReplica_Changes(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	laptop := newInstance(t, "laptop")
+	now := time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC)
+	require.NoError(t, laptop.storage.Store(ctx, record("a", "coffee", now)), "failed to store record")
+	require.NoError(t, laptop.storage.Store(ctx, record("b", "rent", now)), "failed to store record")
+	require.NoError(t, laptop.storage.Delete(ctx, "a"), "failed to delete record")
+
+	// Act
+	first, err := laptop.replica.Changes(ctx, 0, 1)
+	require.NoError(t, err, "Changes() error should be nil")
+	second, err := laptop.replica.Changes(ctx, first.Next, 1)
+
+	// Assert
+	require.NoError(t, err, "Changes() error should be nil")
+	require.Len(t, first.Changes, 1, "Changes() should return up to the limit")
+	assert.True(t, first.More, "Changes() should report more changes")
+	assert.Equal(t, "b", first.Changes[0].RecordID, "Changes() should return the oldest change first")
+	require.NotNil(t, first.Changes[0].Record, "Changes() should carry the changed record")
+	assert.Equal(t, "rent", first.Changes[0].Record.Content, "Changes() should carry the changed record")
+	assert.Equal(t, peersync.Version{"laptop": 1}, first.Changes[0].Version, "Changes() should version the change by the node")
+	require.Len(t, second.Changes, 1, "Changes() should continue after the previous batch")
+	assert.True(t, second.Changes[0].Deleted, "Changes() should return deletions")
+	assert.Nil(t, second.Changes[0].Record, "Changes() should not carry deleted records")
+	assert.Equal(t, peersync.Version{"laptop": 2}, second.Changes[0].Version, "Changes() should count every change to a record")
+}
+
+func TestLocalReplica_Apply_SkipsSeenChanges(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	server := newInstance(t, "server")
+	now := time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC)
+	rec := record("a", "coffee", now)
+	change := peersync.Change{RecordID: "a", Version: peersync.Version{"laptop": 1}, Record: &rec}
+
+	// Act
+	applied, err := server.replica.Apply(ctx, []peersync.Change{change})
+	require.NoError(t, err, "Apply() error should be nil")
+	again, err := server.replica.Apply(ctx, []peersync.Change{change})
+
+	// Assert
+	require.NoError(t, err, "Apply() error should be nil")
+	assert.Equal(t, peersync.Result{Applied: 1}, applied, "Apply() should apply new changes")
+	assert.Equal(t, peersync.Result{Skipped: 1}, again, "Apply() should skip changes already seen")
+	logged, err := server.log.Get(ctx, "a")
+	require.NoError(t, err, "failed to get change")
+	assert.Equal(t, peersync.Version{"laptop": 1}, logged.Version, "Apply() should log the peer's version, not a change of its own")
+}
+
+func TestLocalReplica_Backfill(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "assistant.db")
+	sqliteStorage, err := storage.NewSQLiteStorage(openDB(t, path))
+	require.NoError(t, err, "failed to create storage")
+	log, err := peersync.NewSQLiteLog(openDB(t, path))
+	require.NoError(t, err, "failed to create change log")
+	now := time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC)
+	// Stored before sync was enabled, so untracked
+	require.NoError(t, sqliteStorage.Store(ctx, record("a", "coffee", now)), "failed to store record")
+	replica := peersync.NewLocalReplica(sqliteStorage, storageIngestor{storage: sqliteStorage}, log, "laptop")
+
+	// Act
+	logged, err := replica.Backfill(ctx)
+	require.NoError(t, err, "Backfill() error should be nil")
+	again, err := replica.Backfill(ctx)
+
+	// Assert
+	require.NoError(t, err, "Backfill() error should be nil")
+	assert.Equal(t, 1, logged, "Backfill() should log untracked records")
+	assert.Equal(t, 0, again, "Backfill() should skip tracked records")
+	batch, err := replica.Changes(ctx, 0, 10)
+	require.NoError(t, err, "Changes() error should be nil")
+	assert.Len(t, batch.Changes, 1, "Changes() should return backfilled records")
+}
@@ -0,0 +1,185 @@
+// Package peersync keeps the records of several instances in step without a
+// shared database. Every instance logs the latest change to each of its
+// records with a version vector counting the changes every instance made to
+// the record. A sync pulls the changes a peer logged since the last sync and
+// pushes the local ones over the API. A change descending from the local
+// version replaces the record, one the local version descends from is
+// ignored, and concurrent changes are resolved with ResolveConflict.
+package peersync
+
+import (
+	"context"
+	"maps"
+
+	"github.com/kazemisoroush/assistant/pkg/records"
+)
+
+// ChangesPath is the route of the API endpoint serving and applying changes
+const ChangesPath = "/api/v1/sync/changes"
+
+// Version is the version vector of a record: how many changes each instance made to it
+type Version map[string]uint64
+
+// Order is how two versions relate
+type Order int
+
+const (
+	// Equal versions saw the same changes
+	Equal Order = iota
+	// Before versions miss changes the other version saw
+	Before
+	// After versions saw every change the other version saw, and more
+	After
+	// Concurrent versions each saw changes the other did not
+	Concurrent
+)
+
+// Compare returns how the version relates to the other
+func (v Version) Compare(other Version) Order {
+	before, after := false, false
+	for node, n := range v {
+		if n > other[node] {
+			after = true
+		}
+	}
+	for node, n := range other {
+		if n > v[node] {
+			before = true
+		}
+	}
+	switch {
+	case before && after:
+		return Concurrent
+	case before:
+		return Before
+	case after:
+		return After
+	default:
+		return Equal
+	}
+}
+
+// Merge returns the version that saw the changes of both versions
+func (v Version) Merge(other Version) Version {
+	merged := maps.Clone(v)
+	if merged == nil {
+		merged = Version{}
+	}
+	for node, n := range other {
+		merged[node] = max(merged[node], n)
+	}
+	return merged
+}
+
+// Increment returns the version after one more change by the node
+func (v Version) Increment(node string) Version {
+	next := v.Merge(nil)
+	next[node]++
+	return next
+}
+
+// Change represents the latest change to a record
+type Change struct {
+	Seq      int64           `json:"seq"` // Position in the log of the instance that logged it
+	RecordID string          `json:"record_id"`
+	Version  Version         `json:"version"`
+	Deleted  bool            `json:"deleted,omitempty"`
+	Record   *records.Record `json:"record,omitempty"` // Record after the change; absent for deletions and in the log
+}
+
+// Batch represents a page of the changes logged by an instance
+type Batch struct {
+	Changes []Change `json:"changes"`
+	Next    int64    `json:"next"` // Sequence the next page follows
+	More    bool     `json:"more"` // Whether more changes follow
+}
+
+// Result counts the outcome of applying changes
+type Result struct {
+	Applied   int `json:"applied"`   // Changes descending from the local version
+	Conflicts int `json:"conflicts"` // Changes concurrent with the local version, resolved
+	Skipped   int `json:"skipped"`   // Changes already seen
+}
+
+// Add returns the sum of both results
+func (r Result) Add(other Result) Result {
+	return Result{
+		Applied:   r.Applied + other.Applied,
+		Conflicts: r.Conflicts + other.Conflicts,
+		Skipped:   r.Skipped + other.Skipped,
+	}
+}
+
+// Replica defines the records of an instance, local or reached over the API
+//
+//go:generate mockgen -destination=./mocks/mock_replica.go -mock_names=Replica=MockReplica -package=mocks . Replica
+type Replica interface {
+	// Changes returns up to limit changes logged after the sequence, oldest first
+	Changes(ctx context.Context, since int64, limit int) (Batch, error)
+
+	// Apply applies changes logged by another instance
+	Apply(ctx context.Context, changes []Change) (Result, error)
+}
+
+// Cursor represents how far syncs with a peer got
+type Cursor struct {
+	Pulled int64 // Sequence of the last change pulled from the peer
+	Pushed int64 // Sequence of the last local change pushed to the peer
+}
+
+// Log defines the log of the latest change to every record, and the cursors
+// of the peers synced with
+//
+//go:generate mockgen -destination=./mocks/mock_log.go -mock_names=Log=MockLog -package=mocks . Log
+type Log interface {
+	// Get returns the latest change to a record, with an empty version when it never changed
+	Get(ctx context.Context, recordID string) (Change, error)
+
+	// Put logs the latest change to a record, replacing its previous change,
+	// and returns its sequence. The change's record is not logged.
+	Put(ctx context.Context, change Change) (int64, error)
+
+	// Since returns up to limit changes logged after the sequence, oldest first
+	Since(ctx context.Context, since int64, limit int) ([]Change, error)
+
+	// Cursor returns how far syncs with a peer got, zero before the first
+	Cursor(ctx context.Context, peer string) (Cursor, error)
+
+	// SaveCursor stores how far syncs with a peer got
+	SaveCursor(ctx context.Context, peer string, cursor Cursor) error
+}
+
+// ResolveConflict returns which of two concurrent changes to a record wins.
+// An update wins over a deletion, so no data is lost; otherwise the record
+// updated last wins. Ties are broken by content, so both instances pick the
+// same change.
+func ResolveConflict(local, remote Change) Change {
+	switch {
+	case local.Deleted || local.Record == nil:
+		if remote.Deleted || remote.Record == nil {
+			return local
+		}
+		return remote
+	case remote.Deleted || remote.Record == nil:
+		return local
+	}
+	l, r := local.Record, remote.Record
+	if r.UpdatedAt.After(l.UpdatedAt) || (r.UpdatedAt.Equal(l.UpdatedAt) && r.Content > l.Content) {
+		return remote
+	}
+	return local
+}
+
+// applyingKey marks contexts applying changes logged by another instance
+type applyingKey struct{}
+
+// withApplying returns a copy of ctx whose writes apply another instance's change
+func withApplying(ctx context.Context) context.Context {
+	return context.WithValue(ctx, applyingKey{}, true)
+}
+
+// applying reports whether writes under ctx apply another instance's change
+func applying(ctx context.Context) bool {
+	applying, _ := ctx.Value(applyingKey{}).(bool)
+	return applying
+}
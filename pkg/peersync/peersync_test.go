@@ -0,0 +1,85 @@
+package peersync_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kazemisoroush/assistant/pkg/peersync"
+	"github.com/kazemisoroush/assistant/pkg/records"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVersion_Compare(t *testing.T) {
+	tests := []struct {
+		name  string
+		v     peersync.Version
+		other peersync.Version
+		want  peersync.Order
+	}{
+		{name: "equal", v: peersync.Version{"laptop": 2, "server": 1}, other: peersync.Version{"laptop": 2, "server": 1}, want: peersync.Equal},
+		{name: "both empty", v: peersync.Version{}, other: nil, want: peersync.Equal},
+		{name: "before", v: peersync.Version{"laptop": 1}, other: peersync.Version{"laptop": 1, "server": 1}, want: peersync.Before},
+		{name: "after", v: peersync.Version{"laptop": 3}, other: peersync.Version{"laptop": 2}, want: peersync.After},
+		{name: "after empty", v: peersync.Version{"laptop": 1}, other: peersync.Version{}, want: peersync.After},
+		{name: "concurrent", v: peersync.Version{"laptop": 2, "server": 1}, other: peersync.Version{"laptop": 1, "server": 2}, want: peersync.Concurrent},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			got := tt.v.Compare(tt.other)
+
+			// Assert
+			assert.Equal(t, tt.want, got, "Compare() should relate the versions")
+		})
+	}
+}
+
+func TestVersion_MergeIncrement(t *testing.T) {
+	// Arrange
+	local := peersync.Version{"laptop": 2, "server": 1}
+	remote := peersync.Version{"laptop": 1, "server": 3, "nas": 1}
+
+	// Act
+	merged := local.Merge(remote).Increment("laptop")
+
+	// Assert
+	assert.Equal(t, peersync.Version{"laptop": 3, "server": 3, "nas": 1}, merged, "Merge() should keep every node's latest change")
+	assert.Equal(t, peersync.Version{"laptop": 2, "server": 1}, local, "Merge() and Increment() should not modify the version")
+	assert.Equal(t, peersync.After, merged.Compare(remote), "the merged version should descend from both")
+	assert.Equal(t, peersync.After, merged.Compare(local), "the merged version should descend from both")
+}
+
+func TestResolveConflict(t *testing.T) {
+	earlier := time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC)
+	later := earlier.Add(time.Hour)
+	change := func(content string, updated time.Time) peersync.Change {
+		return peersync.Change{RecordID: "r1", Record: &records.Record{ID: "r1", Content: content, UpdatedAt: updated}}
+	}
+	deleted := peersync.Change{RecordID: "r1", Deleted: true}
+
+	tests := []struct {
+		name   string
+		local  peersync.Change
+		remote peersync.Change
+		want   string
+	}{
+		{name: "remote updated last", local: change("local", earlier), remote: change("remote", later), want: "remote"},
+		{name: "local updated last", local: change("local", later), remote: change("remote", earlier), want: "local"},
+		{name: "tie broken by content", local: change("a", earlier), remote: change("b", earlier), want: "b"},
+		{name: "update wins over local deletion", local: deleted, remote: change("remote", earlier), want: "remote"},
+		{name: "update wins over remote deletion", local: change("local", earlier), remote: deleted, want: "local"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			got := peersync.ResolveConflict(tt.local, tt.remote)
+			mirrored := peersync.ResolveConflict(tt.remote, tt.local)
+
+			// Assert
+			if assert.NotNil(t, got.Record, "ResolveConflict() should keep the update") {
+				assert.Equal(t, tt.want, got.Record.Content, "ResolveConflict() should pick the winning change")
+			}
+			assert.Equal(t, got, mirrored, "ResolveConflict() should pick the same change on both instances")
+		})
+	}
+}
@@ -0,0 +1,138 @@
+package peersync
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/kazemisoroush/assistant/pkg/sqlite"
+)
+
+// SQLiteLog is a Log backed by SQLite. Only the latest change to every
+// record is kept, so the log grows with the records, not their changes.
+type SQLiteLog struct {
+	db *sqlite.DB
+}
+
+// NewSQLiteLog creates a new SQLite change log in the given database
+func NewSQLiteLog(db *sqlite.DB) (*SQLiteLog, error) {
+	schema := `
+    CREATE TABLE IF NOT EXISTS sync_changes (
+        record_id TEXT PRIMARY KEY,
+        version TEXT NOT NULL,
+        deleted INTEGER NOT NULL,
+        seq INTEGER NOT NULL UNIQUE
+    );
+    CREATE TABLE IF NOT EXISTS sync_peers (
+        peer TEXT PRIMARY KEY,
+        pulled INTEGER NOT NULL,
+        pushed INTEGER NOT NULL
+    );
+    `
+	if _, err := db.Exec(schema); err != nil {
+		return nil, fmt.Errorf("failed to initialize change log schema: %w", err)
+	}
+
+	return &SQLiteLog{db: db}, nil
+}
+
+// Get implements Log
+func (l *SQLiteLog) Get(ctx context.Context, recordID string) (Change, error) {
+	change, err := scanChange(l.db.QueryRowContext(ctx,
+		`SELECT record_id, version, deleted, seq FROM sync_changes WHERE record_id = ?`, recordID,
+	))
+	if errors.Is(err, sql.ErrNoRows) {
+		return Change{RecordID: recordID, Version: Version{}}, nil
+	}
+	if err != nil {
+		return Change{}, fmt.Errorf("failed to get change of record %s: %w", recordID, err)
+	}
+	return change, nil
+}
+
+// Put implements Log
+func (l *SQLiteLog) Put(ctx context.Context, change Change) (int64, error) {
+	version, err := json.Marshal(change.Version)
+	if err != nil {
+		return 0, fmt.Errorf("failed to marshal version of record %s: %w", change.RecordID, err)
+	}
+
+	var seq int64
+	err = l.db.QueryRowContext(ctx,
+		`INSERT INTO sync_changes (record_id, version, deleted, seq)
+         VALUES (?, ?, ?, (SELECT COALESCE(MAX(seq), 0) + 1 FROM sync_changes))
+         ON CONFLICT (record_id) DO UPDATE SET version = excluded.version, deleted = excluded.deleted, seq = excluded.seq
+         RETURNING seq`,
+		change.RecordID, string(version), change.Deleted,
+	).Scan(&seq)
+	if err != nil {
+		return 0, fmt.Errorf("failed to log change of record %s: %w", change.RecordID, err)
+	}
+	return seq, nil
+}
+
+// Since implements Log
+func (l *SQLiteLog) Since(ctx context.Context, since int64, limit int) ([]Change, error) {
+	rows, err := l.db.QueryContext(ctx,
+		`SELECT record_id, version, deleted, seq FROM sync_changes WHERE seq > ? ORDER BY seq LIMIT ?`, since, limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list changes: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var changes []Change
+	for rows.Next() {
+		change, err := scanChange(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan change: %w", err)
+		}
+		changes = append(changes, change)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list changes: %w", err)
+	}
+	return changes, nil
+}
+
+// Cursor implements Log
+func (l *SQLiteLog) Cursor(ctx context.Context, peer string) (Cursor, error) {
+	var cursor Cursor
+	err := l.db.QueryRowContext(ctx,
+		`SELECT pulled, pushed FROM sync_peers WHERE peer = ?`, peer,
+	).Scan(&cursor.Pulled, &cursor.Pushed)
+	if errors.Is(err, sql.ErrNoRows) {
+		return Cursor{}, nil
+	}
+	if err != nil {
+		return Cursor{}, fmt.Errorf("failed to load cursor of peer %s: %w", peer, err)
+	}
+	return cursor, nil
+}
+
+// SaveCursor implements Log
+func (l *SQLiteLog) SaveCursor(ctx context.Context, peer string, cursor Cursor) error {
+	if _, err := l.db.ExecContext(ctx,
+		`INSERT INTO sync_peers (peer, pulled, pushed) VALUES (?, ?, ?)
+         ON CONFLICT (peer) DO UPDATE SET pulled = excluded.pulled, pushed = excluded.pushed`,
+		peer, cursor.Pulled, cursor.Pushed,
+	); err != nil {
+		return fmt.Errorf("failed to save cursor of peer %s: %w", peer, err)
+	}
+	return nil
+}
+
+// scanChange reads a change from a row
+func scanChange(row interface{ Scan(dest ...any) error }) (Change, error) {
+	var change Change
+	var version string
+	if err := row.Scan(&change.RecordID, &version, &change.Deleted, &change.Seq); err != nil {
+		return Change{}, err
+	}
+	if err := json.Unmarshal([]byte(version), &change.Version); err != nil {
+		return Change{}, fmt.Errorf("failed to unmarshal version of record %s: %w", change.RecordID, err)
+	}
+	return change, nil
+}
@@ -0,0 +1,73 @@
+package peersync_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/kazemisoroush/assistant/pkg/peersync"
+	"github.com/kazemisoroush/assistant/pkg/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// openDB opens the database at path, closed once the test is done
+func openDB(t testing.TB, path string) *sqlite.DB {
+	t.Helper()
+	db, err := sqlite.Open(path)
+	require.NoError(t, err, "Open() error should be nil")
+	t.Cleanup(func() { _ = db.Close() })
+	return db
+}
+
+func TestSQLiteLog_PutSince(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	log, err := peersync.NewSQLiteLog(openDB(t, filepath.Join(t.TempDir(), "assistant.db")))
+	require.NoError(t, err, "NewSQLiteLog() error should be nil")
+
+	// Act
+	first, err := log.Put(ctx, peersync.Change{RecordID: "a", Version: peersync.Version{"laptop": 1}})
+	require.NoError(t, err, "Put() error should be nil")
+	_, err = log.Put(ctx, peersync.Change{RecordID: "b", Version: peersync.Version{"laptop": 1}})
+	require.NoError(t, err, "Put() error should be nil")
+	third, err := log.Put(ctx, peersync.Change{RecordID: "a", Version: peersync.Version{"laptop": 2}, Deleted: true})
+	require.NoError(t, err, "Put() error should be nil")
+
+	// Assert
+	assert.Greater(t, third, first, "Put() should log every change after the previous ones")
+	changes, err := log.Since(ctx, 0, 10)
+	require.NoError(t, err, "Since() error should be nil")
+	require.Len(t, changes, 2, "Since() should only list the latest change to every record")
+	assert.Equal(t, "b", changes[0].RecordID, "Since() should list changes oldest first")
+	assert.Equal(t, peersync.Change{Seq: third, RecordID: "a", Version: peersync.Version{"laptop": 2}, Deleted: true}, changes[1], "Since() should list the latest change")
+
+	later, err := log.Since(ctx, changes[0].Seq, 10)
+	require.NoError(t, err, "Since() error should be nil")
+	assert.Len(t, later, 1, "Since() should skip the changes up to the sequence")
+
+	got, err := log.Get(ctx, "a")
+	require.NoError(t, err, "Get() error should be nil")
+	assert.True(t, got.Deleted, "Get() should return the latest change")
+	never, err := log.Get(ctx, "c")
+	require.NoError(t, err, "Get() error should be nil for records never changed")
+	assert.Empty(t, never.Version, "Get() should return an empty version for records never changed")
+}
+
+func TestSQLiteLog_Cursor(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	log, err := peersync.NewSQLiteLog(openDB(t, filepath.Join(t.TempDir(), "assistant.db")))
+	require.NoError(t, err, "NewSQLiteLog() error should be nil")
+
+	// Act
+	before, err := log.Cursor(ctx, "https://home.example.com")
+	require.NoError(t, err, "Cursor() error should be nil before the first sync")
+	require.NoError(t, log.SaveCursor(ctx, "https://home.example.com", peersync.Cursor{Pulled: 7, Pushed: 3}), "SaveCursor() error should be nil")
+	after, err := log.Cursor(ctx, "https://home.example.com")
+
+	// Assert
+	require.NoError(t, err, "Cursor() error should be nil")
+	assert.Equal(t, peersync.Cursor{}, before, "Cursor() should start from scratch")
+	assert.Equal(t, peersync.Cursor{Pulled: 7, Pushed: 3}, after, "Cursor() should return the saved cursor")
+}
@@ -0,0 +1,101 @@
+package peersync
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+)
+
+// DefaultBatchSize is how many changes are exchanged per request when the batch size is not set
+const DefaultBatchSize = 100
+
+// Peer represents another instance records are synced with
+type Peer struct {
+	Name    string // Identifies the peer's cursor, e.g. its URL
+	Replica Replica
+}
+
+// Report represents the outcome of syncing with a peer
+type Report struct {
+	Peer   string `json:"peer"`
+	Pulled Result `json:"pulled"` // Changes of the peer applied locally
+	Pushed Result `json:"pushed"` // Local changes applied by the peer
+}
+
+// Syncer defines operations for syncing records with peers
+//
+//go:generate mockgen -destination=./mocks/mock_syncer.go -mock_names=Syncer=MockSyncer -package=mocks . Syncer
+type Syncer interface {
+	// Sync pulls the changes the peer logged since the last sync, then pushes the local ones
+	Sync(ctx context.Context, peer Peer) (Report, error)
+}
+
+// ReplicaSyncer syncs the local replica with peers a batch at a time. The
+// cursors move after every batch, so an interrupted sync continues where it
+// stopped.
+type ReplicaSyncer struct {
+	local     Replica
+	log       Log
+	batchSize int
+}
+
+// NewReplicaSyncer creates a syncer of the local replica, keeping the peers' cursors in the log
+func NewReplicaSyncer(local Replica, log Log, batchSize int) Syncer {
+	if batchSize <= 0 {
+		batchSize = DefaultBatchSize
+	}
+	return &ReplicaSyncer{
+		local:     local,
+		log:       log,
+		batchSize: batchSize,
+	}
+}
+
+// Sync implements Syncer
+func (s *ReplicaSyncer) Sync(ctx context.Context, peer Peer) (Report, error) {
+	report := Report{Peer: peer.Name}
+	cursor, err := s.log.Cursor(ctx, peer.Name)
+	if err != nil {
+		return report, err
+	}
+
+	report.Pulled, err = s.transfer(ctx, peer.Replica, s.local, &cursor.Pulled, func() error {
+		return s.log.SaveCursor(ctx, peer.Name, cursor)
+	})
+	if err != nil {
+		return report, fmt.Errorf("failed to pull changes from %s: %w", peer.Name, err)
+	}
+	report.Pushed, err = s.transfer(ctx, s.local, peer.Replica, &cursor.Pushed, func() error {
+		return s.log.SaveCursor(ctx, peer.Name, cursor)
+	})
+	if err != nil {
+		return report, fmt.Errorf("failed to push changes to %s: %w", peer.Name, err)
+	}
+	slog.InfoContext(ctx, "Synced with peer", "peer", peer.Name, "pulled", report.Pulled, "pushed", report.Pushed)
+	return report, nil
+}
+
+// transfer applies the changes from logged after the sequence to the other
+// replica, advancing the sequence and saving it after every batch
+func (s *ReplicaSyncer) transfer(ctx context.Context, from, to Replica, seq *int64, save func() error) (Result, error) {
+	var result Result
+	for {
+		batch, err := from.Changes(ctx, *seq, s.batchSize)
+		if err != nil {
+			return result, err
+		}
+		applied, err := to.Apply(ctx, batch.Changes)
+		result = result.Add(applied)
+		if err != nil {
+			// The cursor stays before the batch, so the next sync retries it
+			return result, err
+		}
+		*seq = batch.Next
+		if err := save(); err != nil {
+			return result, err
+		}
+		if !batch.More {
+			return result, nil
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package peersync_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/kazemisoroush/assistant/pkg/peersync"
+	"github.com/kazemisoroush/assistant/pkg/peersync/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestReplicaSyncer_Sync_Converges(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	laptop, server := newInstance(t, "laptop"), newInstance(t, "server")
+	earlier := time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC)
+	later := earlier.Add(time.Hour)
+	require.NoError(t, laptop.storage.Store(ctx, record("laptop-only", "coffee", earlier)), "failed to store record")
+	require.NoError(t, server.storage.Store(ctx, record("server-only", "rent", earlier)), "failed to store record")
+	require.NoError(t, server.storage.Store(ctx, record("deleted", "old", earlier)), "failed to store record")
+	syncer := peersync.NewReplicaSyncer(laptop.replica, laptop.log, 1)
+	peer := peersync.Peer{Name: "server", Replica: server.replica}
+	_, err := syncer.Sync(ctx, peer)
+	require.NoError(t, err, "failed to sync")
+
+	// Both edit the same record while apart, and the server deletes one
+	require.NoError(t, laptop.storage.Update(ctx, record("server-only", "rent, edited on the laptop", later)), "failed to update record")
+	require.NoError(t, server.storage.Update(ctx, record("server-only", "rent, edited on the server", earlier)), "failed to update record")
+	require.NoError(t, server.storage.Delete(ctx, "deleted"), "failed to delete record")
+
+	// Act
+	report, err := syncer.Sync(ctx, peer)
+	require.NoError(t, err, "Sync() error should be nil")
+	_, err = syncer.Sync(ctx, peer)
+
+	// Assert
+	require.NoError(t, err, "Sync() error should be nil")
+	assert.Equal(t, 1, report.Pulled.Conflicts, "Sync() should resolve the concurrent edit")
+	for _, inst := range []instance{laptop, server} {
+		rec, err := inst.storage.Get(ctx, "laptop-only")
+		require.NoError(t, err, "Sync() should copy the laptop's records to the server")
+		assert.Equal(t, "coffee", rec.Content, "Sync() should copy the record")
+		rec, err = inst.storage.Get(ctx, "server-only")
+		require.NoError(t, err, "Sync() should copy the server's records to the laptop")
+		assert.Equal(t, "rent, edited on the laptop", rec.Content, "Sync() should keep the edit made last on both instances")
+		_, err = inst.storage.Get(ctx, "deleted")
+		assert.Error(t, err, "Sync() should delete the record on both instances")
+	}
+}
+
+func TestReplicaSyncer_Sync_KeepsCursorOnFailure(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	local := mocks.NewMockReplica(ctrl)
+	remote := mocks.NewMockReplica(ctrl)
+	log := mocks.NewMockLog(ctrl)
+	log.EXPECT().Cursor(gomock.Any(), "server").Return(peersync.Cursor{Pulled: 4, Pushed: 2}, nil)
+	remote.EXPECT().Changes(gomock.Any(), int64(4), 10).Return(peersync.Batch{Changes: []peersync.Change{{RecordID: "a"}}, Next: 5}, nil)
+	local.EXPECT().Apply(gomock.Any(), gomock.Any()).Return(peersync.Result{}, errors.New("disk full"))
+	syncer := peersync.NewReplicaSyncer(local, log, 10)
+
+	// Act
+	_, err := syncer.Sync(ctx, peersync.Peer{Name: "server", Replica: remote})
+
+	// Assert
+	assert.ErrorContains(t, err, "disk full", "Sync() should fail when changes fail to be applied")
+}
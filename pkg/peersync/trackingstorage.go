@@ -0,0 +1,79 @@
+package peersync
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kazemisoroush/assistant/pkg/records"
+	"github.com/kazemisoroush/assistant/pkg/records/storage"
+)
+
+// TrackingStorage is a Storage logging every change to its records, so the
+// changes are synced to peers. Writes applying a peer's change are logged by
+// the replica applying them instead.
+type TrackingStorage struct {
+	storage.Storage
+	log  Log
+	node string
+}
+
+// NewTrackingStorage wraps a storage so the node's changes to records are logged
+func NewTrackingStorage(storage storage.Storage, log Log, node string) storage.Storage {
+	return &TrackingStorage{
+		Storage: storage,
+		log:     log,
+		node:    node,
+	}
+}
+
+// Store implements storage.Storage
+func (s *TrackingStorage) Store(ctx context.Context, rec records.Record) error {
+	if err := s.Storage.Store(ctx, rec); err != nil {
+		return err
+	}
+	return s.track(ctx, rec.ID, false)
+}
+
+// StoreBatch implements storage.Storage
+func (s *TrackingStorage) StoreBatch(ctx context.Context, recs []records.Record) error {
+	if err := s.Storage.StoreBatch(ctx, recs); err != nil {
+		return err
+	}
+	for _, rec := range recs {
+		if err := s.track(ctx, rec.ID, false); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Update implements storage.Storage
+func (s *TrackingStorage) Update(ctx context.Context, rec records.Record) error {
+	if err := s.Storage.Update(ctx, rec); err != nil {
+		return err
+	}
+	return s.track(ctx, rec.ID, false)
+}
+
+// Delete implements storage.Storage
+func (s *TrackingStorage) Delete(ctx context.Context, id string) error {
+	if err := s.Storage.Delete(ctx, id); err != nil {
+		return err
+	}
+	return s.track(ctx, id, true)
+}
+
+// track logs a change to a record by the node
+func (s *TrackingStorage) track(ctx context.Context, id string, deleted bool) error {
+	if applying(ctx) {
+		return nil
+	}
+	change, err := s.log.Get(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to track change of record %s: %w", id, err)
+	}
+	if _, err := s.log.Put(ctx, Change{RecordID: id, Version: change.Version.Increment(s.node), Deleted: deleted}); err != nil {
+		return fmt.Errorf("failed to track change of record %s: %w", id, err)
+	}
+	return nil
+}
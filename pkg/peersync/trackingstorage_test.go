@@ -0,0 +1,48 @@
+package peersync_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/kazemisoroush/assistant/pkg/peersync"
+	"github.com/kazemisoroush/assistant/pkg/peersync/mocks"
+	"github.com/kazemisoroush/assistant/pkg/records"
+	storagemocks "github.com/kazemisoroush/assistant/pkg/records/storage/mocks"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestTrackingStorage_Update(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	inner := storagemocks.NewMockStorage(ctrl)
+	log := mocks.NewMockLog(ctrl)
+	rec := records.Record{ID: "a"}
+	inner.EXPECT().Update(gomock.Any(), rec).Return(nil)
+	log.EXPECT().Get(gomock.Any(), "a").Return(peersync.Change{RecordID: "a", Version: peersync.Version{"server": 2}}, nil)
+	log.EXPECT().Put(gomock.Any(), peersync.Change{RecordID: "a", Version: peersync.Version{"server": 2, "laptop": 1}}).Return(int64(9), nil)
+	tracking := peersync.NewTrackingStorage(inner, log, "laptop")
+
+	// Act
+	err := tracking.Update(ctx, rec)
+
+	// Assert
+	assert.NoError(t, err, "Update() error should be nil")
+}
+
+func TestTrackingStorage_Delete_Failure(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	inner := storagemocks.NewMockStorage(ctrl)
+	inner.EXPECT().Delete(gomock.Any(), "a").Return(errors.New("record not found: a"))
+	tracking := peersync.NewTrackingStorage(inner, mocks.NewMockLog(ctrl), "laptop")
+
+	// Act
+	err := tracking.Delete(ctx, "a")
+
+	// Assert
+	assert.Error(t, err, "Delete() should fail when the storage fails")
+}
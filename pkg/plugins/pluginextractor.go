@@ -0,0 +1,76 @@
+package plugins
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/kazemisoroush/assistant/pkg/records"
+	"github.com/kazemisoroush/assistant/pkg/records/extractor"
+)
+
+// recordSeq disambiguates the IDs of records extracted in the same nanosecond
+var recordSeq atomic.Int64
+
+// extractRequest is written to an extractor plugin's stdin
+type extractRequest struct {
+	Path      string `json:"path,omitempty"`
+	MediaType string `json:"media_type"`
+	Content   []byte `json:"content"`
+}
+
+// PluginExtractor extracts records from documents with the extractor plugin
+// declaring their media type, and from all other documents with the fallback
+type PluginExtractor struct {
+	byMediaType map[string]Plugin
+	fallback    extractor.ContentExtractor
+}
+
+// NewPluginExtractor creates an extractor routing documents to the plugins.
+// A media type declared by several plugins is handled by the first.
+func NewPluginExtractor(plugins []Plugin, fallback extractor.ContentExtractor) extractor.ContentExtractor {
+	byMediaType := map[string]Plugin{}
+	for _, plugin := range plugins {
+		for _, mediaType := range plugin.Manifest.MediaTypes {
+			if _, ok := byMediaType[mediaType]; !ok {
+				byMediaType[mediaType] = plugin
+			}
+		}
+	}
+	return &PluginExtractor{
+		byMediaType: byMediaType,
+		fallback:    fallback,
+	}
+}
+
+// Extract implements extractor.ContentExtractor
+func (e *PluginExtractor) Extract(ctx context.Context, input extractor.Input) (records.Record, error) {
+	plugin, ok := e.byMediaType[input.MediaType]
+	if !ok {
+		return e.fallback.Extract(ctx, input)
+	}
+
+	content, err := input.ReadAll()
+	if err != nil {
+		return records.Record{}, err
+	}
+	var msg message
+	request := extractRequest{Path: input.Path, MediaType: input.MediaType, Content: content}
+	if err := call(ctx, plugin.Path, methodExtract, request, &msg); err != nil {
+		return records.Record{}, fmt.Errorf("plugin %s: %w", plugin.Manifest.Name, err)
+	}
+	if msg.Error != "" {
+		return records.Record{}, fmt.Errorf("plugin %s: %s", plugin.Manifest.Name, msg.Error)
+	}
+	if msg.Record == nil {
+		return records.Record{}, fmt.Errorf("plugin %s returned no record", plugin.Manifest.Name)
+	}
+
+	now := time.Now()
+	rec := normalize(plugin.Manifest.Name, *msg.Record, now)
+	if rec.ID == "" {
+		rec.ID = fmt.Sprintf("%s-%d-%d", plugin.Manifest.Name, now.UnixNano(), recordSeq.Add(1))
+	}
+	return rec, nil
+}
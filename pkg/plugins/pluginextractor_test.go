@@ -0,0 +1,78 @@
+package plugins_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/kazemisoroush/assistant/pkg/plugins"
+	"github.com/kazemisoroush/assistant/pkg/records"
+	"github.com/kazemisoroush/assistant/pkg/records/extractor"
+	extractormocks "github.com/kazemisoroush/assistant/pkg/records/extractor/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestPluginExtractor_Extract(t *testing.T) {
+	// Arrange
+	// The plugin answers with the size of the request it read, proving it received the document
+	path := writePlugin(t, t.TempDir(), "ofx", `extract)
+	size=$(wc -c)
+	echo "{\"record\":{\"type\":\"receipt\",\"content\":\"request of $size bytes\"}}"
+	;;`)
+	ctrl := gomock.NewController(t)
+	fallback := extractormocks.NewMockContentExtractor(ctrl)
+	contentExtractor := plugins.NewPluginExtractor([]plugins.Plugin{
+		{Path: path, Manifest: plugins.Manifest{Name: "ofx", Kind: plugins.KindExtractor, MediaTypes: []string{"application/x-ofx"}}},
+	}, fallback)
+	input, err := extractor.ReaderInput(strings.NewReader("<OFX>statement</OFX>"), "application/x-ofx")
+	require.NoError(t, err, "failed to create input")
+
+	// Act
+	rec, err := contentExtractor.Extract(context.Background(), input)
+
+	// Assert
+	require.NoError(t, err, "Extract() error should be nil")
+	assert.NotEmpty(t, rec.ID, "Extract() should give the record an ID")
+	assert.Equal(t, records.RecordTypeReceipt, rec.Type, "Extract() should return the plugin's record")
+	assert.Contains(t, rec.Content, "request of", "Extract() should send the document to the plugin")
+	assert.Equal(t, "ofx", rec.Metadata[plugins.MetaPlugin], "Extract() should name the plugin in the metadata")
+}
+
+func TestPluginExtractor_Extract_Fallback(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	fallback := extractormocks.NewMockContentExtractor(ctrl)
+	fallback.EXPECT().Extract(gomock.Any(), gomock.Any()).Return(records.Record{ID: "ocr-1"}, nil)
+	contentExtractor := plugins.NewPluginExtractor([]plugins.Plugin{
+		{Path: "/nonexistent/ofx", Manifest: plugins.Manifest{Name: "ofx", Kind: plugins.KindExtractor, MediaTypes: []string{"application/x-ofx"}}},
+	}, fallback)
+	input, err := extractor.ReaderInput(strings.NewReader("hello"), "text/plain")
+	require.NoError(t, err, "failed to create input")
+
+	// Act
+	rec, err := contentExtractor.Extract(context.Background(), input)
+
+	// Assert
+	require.NoError(t, err, "Extract() error should be nil")
+	assert.Equal(t, "ocr-1", rec.ID, "Extract() should use the fallback for other media types")
+}
+
+func TestPluginExtractor_Extract_PluginError(t *testing.T) {
+	// Arrange
+	path := writePlugin(t, t.TempDir(), "ofx", `extract) cat >/dev/null; echo '{"error":"unsupported OFX version"}' ;;`)
+	ctrl := gomock.NewController(t)
+	contentExtractor := plugins.NewPluginExtractor([]plugins.Plugin{
+		{Path: path, Manifest: plugins.Manifest{Name: "ofx", Kind: plugins.KindExtractor, MediaTypes: []string{"application/x-ofx"}}},
+	}, extractormocks.NewMockContentExtractor(ctrl))
+	input, err := extractor.ReaderInput(strings.NewReader("<OFX/>"), "application/x-ofx")
+	require.NoError(t, err, "failed to create input")
+
+	// Act
+	_, err = contentExtractor.Extract(context.Background(), input)
+
+	// Assert
+	require.Error(t, err, "Extract() should fail when the plugin reports an error")
+	assert.Contains(t, err.Error(), "unsupported OFX version", "Extract() should report the plugin's error")
+}
@@ -0,0 +1,201 @@
+// Package plugins runs sources and content extractors shipped as separate
+// executables, so custom scrapers can be maintained outside this module.
+//
+// Every executable in the plugins directory is a plugin. It is run with the
+// method as its only argument and exchanges JSON over stdin and stdout:
+//
+//   - describe: prints its manifest, e.g. {"name":"mybank","kind":"source"}
+//     or {"name":"ofx","kind":"extractor","media_types":["application/x-ofx"]}
+//   - scrape (sources): prints one message per line as records are scraped
+//   - extract (extractors): reads {"path":...,"media_type":...,"content":<base64>}
+//     and prints one message
+//
+// A message is {"record":{...}} with a record in the JSON form of
+// records.Record, or {"error":"..."} when an item failed. A plugin exiting
+// with a non-zero status fails the call; what it wrote to stderr is reported
+// with the error.
+package plugins
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/kazemisoroush/assistant/pkg/records"
+)
+
+// Kind is what a plugin provides
+type Kind string
+
+// Plugin kinds
+const (
+	KindSource    Kind = "source"    // Scrapes records from somewhere, like a bank portal
+	KindExtractor Kind = "extractor" // Extracts records from documents of its media types
+)
+
+// Plugin methods, passed as the only argument
+const (
+	methodDescribe = "describe"
+	methodScrape   = "scrape"
+	methodExtract  = "extract"
+)
+
+// describeTimeout bounds how long a plugin may take to describe itself
+const describeTimeout = 10 * time.Second
+
+// stderrLimit is how much of a plugin's stderr is kept for error messages
+const stderrLimit = 4 << 10
+
+// Manifest represents what a plugin reports about itself
+type Manifest struct {
+	Name       string   `json:"name"`
+	Kind       Kind     `json:"kind"`
+	MediaTypes []string `json:"media_types,omitempty"` // Media types an extractor handles
+}
+
+// Plugin represents an installed plugin executable
+type Plugin struct {
+	Path     string
+	Manifest Manifest
+}
+
+// message is a record or an error written by a plugin
+type message struct {
+	Record *records.Record `json:"record,omitempty"`
+	Error  string          `json:"error,omitempty"`
+}
+
+// Discover describes the executables in dir. Executables failing to describe
+// themselves are skipped with a warning. A missing directory has no plugins.
+func Discover(ctx context.Context, dir string) ([]Plugin, error) {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read plugins directory: %w", err)
+	}
+
+	var plugins []Plugin
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if err != nil || !info.Mode().IsRegular() || info.Mode().Perm()&0o111 == 0 {
+			continue
+		}
+		plugin := Plugin{Path: filepath.Join(dir, entry.Name())}
+		if plugin.Manifest, err = describe(ctx, plugin.Path); err != nil {
+			slog.WarnContext(ctx, "Skipping plugin", "path", plugin.Path, "error", err)
+			continue
+		}
+		plugins = append(plugins, plugin)
+	}
+	return plugins, nil
+}
+
+// OfKind returns the plugins of the kind
+func OfKind(plugins []Plugin, kind Kind) []Plugin {
+	var matching []Plugin
+	for _, plugin := range plugins {
+		if plugin.Manifest.Kind == kind {
+			matching = append(matching, plugin)
+		}
+	}
+	return matching
+}
+
+// describe runs the describe method and validates the manifest
+func describe(ctx context.Context, path string) (Manifest, error) {
+	ctx, cancel := context.WithTimeout(ctx, describeTimeout)
+	defer cancel()
+
+	var manifest Manifest
+	if err := call(ctx, path, methodDescribe, nil, &manifest); err != nil {
+		return Manifest{}, err
+	}
+	if manifest.Name == "" {
+		return Manifest{}, errors.New("manifest has no name")
+	}
+	if !slices.Contains([]Kind{KindSource, KindExtractor}, manifest.Kind) {
+		return Manifest{}, fmt.Errorf("unknown plugin kind %q", manifest.Kind)
+	}
+	if manifest.Kind == KindExtractor && len(manifest.MediaTypes) == 0 {
+		return Manifest{}, errors.New("extractor declares no media types")
+	}
+	return manifest, nil
+}
+
+// call runs a method writing request, when not nil, to the plugin's stdin
+// and decodes its output into response
+func call(ctx context.Context, path, method string, request, response any) error {
+	cmd := exec.CommandContext(ctx, path, method)
+	if request != nil {
+		data, err := json.Marshal(request)
+		if err != nil {
+			return fmt.Errorf("failed to encode %s request: %w", method, err)
+		}
+		cmd.Stdin = bytes.NewReader(data)
+	}
+	var stdout bytes.Buffer
+	stderr := &limitedBuffer{limit: stderrLimit}
+	cmd.Stdout, cmd.Stderr = &stdout, stderr
+
+	if err := cmd.Run(); err != nil {
+		return runError(method, err, stderr)
+	}
+	if err := json.Unmarshal(stdout.Bytes(), response); err != nil {
+		return fmt.Errorf("failed to decode %s response: %w", method, err)
+	}
+	return nil
+}
+
+// runError describes a failed run with what the plugin wrote to stderr
+func runError(method string, err error, stderr *limitedBuffer) error {
+	if msg := bytes.TrimSpace(stderr.Bytes()); len(msg) > 0 {
+		return fmt.Errorf("plugin %s failed: %w: %s", method, err, msg)
+	}
+	return fmt.Errorf("plugin %s failed: %w", method, err)
+}
+
+// limitedBuffer keeps the first limit bytes written to it
+type limitedBuffer struct {
+	bytes.Buffer
+	limit int
+}
+
+// Write implements io.Writer, discarding what does not fit
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if room := b.limit - b.Len(); room > 0 {
+		b.Buffer.Write(p[:min(len(p), room)])
+	}
+	return len(p), nil
+}
+
+// normalize fills in what a plugin left out of a record and marks its origin
+func normalize(name string, rec records.Record, now time.Time) records.Record {
+	if rec.Type == "" {
+		rec.Type = records.RecordTypeOther
+	}
+	if rec.CreatedAt.IsZero() {
+		rec.CreatedAt = now
+	}
+	if rec.UpdatedAt.IsZero() {
+		rec.UpdatedAt = rec.CreatedAt
+	}
+	if rec.Metadata == nil {
+		rec.Metadata = map[string]interface{}{}
+	}
+	rec.Metadata[MetaPlugin] = name
+	return rec
+}
+
+// MetaPlugin is the metadata key naming the plugin a record came from
+const MetaPlugin = "plugin"
@@ -0,0 +1,57 @@
+package plugins_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/kazemisoroush/assistant/pkg/plugins"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writePlugin installs a shell script answering the plugin methods in cases,
+// a case statement body over the method in $1
+func writePlugin(t *testing.T, dir, name, cases string) string {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("plugins are shell scripts in tests")
+	}
+	path := filepath.Join(dir, name)
+	script := "#!/bin/sh\ncase \"$1\" in\n" + cases + "\nesac\n"
+	require.NoError(t, os.WriteFile(path, []byte(script), 0o755), "failed to write plugin")
+	return path
+}
+
+func TestDiscover(t *testing.T) {
+	// Arrange
+	dir := t.TempDir()
+	writePlugin(t, dir, "mybank", `describe) echo '{"name":"mybank","kind":"source"}' ;;`)
+	writePlugin(t, dir, "ofx", `describe) echo '{"name":"ofx","kind":"extractor","media_types":["application/x-ofx"]}' ;;`)
+	writePlugin(t, dir, "broken", `describe) echo 'not json' ;;`)
+	writePlugin(t, dir, "nameless", `describe) echo '{"kind":"source"}' ;;`)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "README.md"), []byte("docs"), 0o644), "failed to write readme")
+
+	// Act
+	got, err := plugins.Discover(context.Background(), dir)
+
+	// Assert
+	require.NoError(t, err, "Discover() error should be nil")
+	require.Len(t, got, 2, "Discover() should skip files that are not working plugins")
+	assert.Equal(t, "mybank", got[0].Manifest.Name, "Discover() should describe the source plugin")
+	assert.Equal(t, filepath.Join(dir, "mybank"), got[0].Path, "Discover() should keep the plugin path")
+	sources := plugins.OfKind(got, plugins.KindSource)
+	require.Len(t, sources, 1, "OfKind() should select the source plugins")
+	assert.Equal(t, "mybank", sources[0].Manifest.Name, "OfKind() should select the source plugins")
+}
+
+func TestDiscover_MissingDirectory(t *testing.T) {
+	// Act
+	got, err := plugins.Discover(context.Background(), filepath.Join(t.TempDir(), "plugins"))
+
+	// Assert
+	assert.NoError(t, err, "Discover() should not fail without a plugins directory")
+	assert.Empty(t, got, "Discover() should find no plugins")
+}
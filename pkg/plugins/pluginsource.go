@@ -0,0 +1,106 @@
+package plugins
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"time"
+
+	"github.com/kazemisoroush/assistant/pkg/records"
+	"github.com/kazemisoroush/assistant/pkg/records/pipeline"
+	"github.com/kazemisoroush/assistant/pkg/records/source"
+)
+
+// PluginSource scrapes records with a source plugin. Records must carry an
+// ID, so scraping the same item again updates its record.
+type PluginSource struct {
+	plugin  Plugin
+	buffers source.Buffers
+}
+
+// NewPluginSource creates a source running the plugin
+func NewPluginSource(plugin Plugin, buffers source.Buffers) source.Source {
+	return &PluginSource{
+		plugin:  plugin,
+		buffers: buffers,
+	}
+}
+
+// Name implements source.Source
+func (s *PluginSource) Name() string {
+	return s.plugin.Manifest.Name
+}
+
+// Scrape implements source.Source. Items the plugin failed to scrape are
+// reported as errors; cancelling ctx kills the plugin.
+func (s *PluginSource) Scrape(ctx context.Context) (<-chan records.Record, <-chan error) {
+	recordChan := make(chan records.Record, max(s.buffers.Records, 0))
+	errChan := make(chan error, max(s.buffers.Errors, 1))
+
+	go func() {
+		defer close(recordChan)
+		defer close(errChan)
+		if err := s.run(ctx, recordChan, errChan); err != nil {
+			pipeline.Send(ctx, errChan, fmt.Errorf("plugin %s: %w", s.Name(), err), nil)
+		}
+	}()
+
+	return recordChan, errChan
+}
+
+// run starts the plugin and forwards the messages it writes
+func (s *PluginSource) run(ctx context.Context, recordChan chan<- records.Record, errChan chan<- error) error {
+	cmd := exec.CommandContext(ctx, s.plugin.Path, methodScrape)
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return fmt.Errorf("failed to open plugin output: %w", err)
+	}
+	stderr := &limitedBuffer{limit: stderrLimit}
+	cmd.Stderr = stderr
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to start plugin: %w", err)
+	}
+
+	forwardErr := s.forward(ctx, json.NewDecoder(stdout), recordChan, errChan)
+	if forwardErr != nil {
+		// Stop the plugin; nothing reads what it writes any more
+		_ = cmd.Process.Kill()
+	}
+	if err := cmd.Wait(); err != nil && forwardErr == nil {
+		return runError(methodScrape, err, stderr)
+	}
+	return forwardErr
+}
+
+// forward decodes messages until the plugin closes its output
+func (s *PluginSource) forward(ctx context.Context, decoder *json.Decoder, recordChan chan<- records.Record, errChan chan<- error) error {
+	for {
+		var msg message
+		err := decoder.Decode(&msg)
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to decode plugin output: %w", err)
+		}
+
+		switch {
+		case msg.Error != "":
+			err = fmt.Errorf("plugin %s: %s", s.Name(), msg.Error)
+		case msg.Record == nil || msg.Record.ID == "":
+			err = fmt.Errorf("plugin %s: record without an ID", s.Name())
+		}
+		if err != nil {
+			if !pipeline.Send(ctx, errChan, err, nil) {
+				return ctx.Err()
+			}
+			continue
+		}
+		if !pipeline.Send(ctx, recordChan, normalize(s.Name(), *msg.Record, time.Now()), nil) {
+			return ctx.Err()
+		}
+	}
+}
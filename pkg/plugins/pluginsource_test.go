@@ -0,0 +1,72 @@
+package plugins_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kazemisoroush/assistant/pkg/plugins"
+	"github.com/kazemisoroush/assistant/pkg/records"
+	"github.com/kazemisoroush/assistant/pkg/records/source"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// drain collects everything a scrape sends
+func drain(recordChan <-chan records.Record, errChan <-chan error) ([]records.Record, []error) {
+	var recs []records.Record
+	var errs []error
+	for recordChan != nil || errChan != nil {
+		select {
+		case rec, ok := <-recordChan:
+			if !ok {
+				recordChan = nil
+				continue
+			}
+			recs = append(recs, rec)
+		case err, ok := <-errChan:
+			if !ok {
+				errChan = nil
+				continue
+			}
+			errs = append(errs, err)
+		}
+	}
+	return recs, errs
+}
+
+func TestPluginSource_Scrape(t *testing.T) {
+	// Arrange
+	path := writePlugin(t, t.TempDir(), "mybank", `scrape)
+	echo '{"record":{"id":"tx-1","type":"receipt","content":"Coffee 4.50 EUR"}}'
+	echo '{"error":"statement 2 is locked"}'
+	echo '{"record":{"id":"tx-2","content":"Rent"}}'
+	;;`)
+	src := plugins.NewPluginSource(plugins.Plugin{Path: path, Manifest: plugins.Manifest{Name: "mybank", Kind: plugins.KindSource}}, source.Buffers{Records: 4, Errors: 4})
+
+	// Act
+	recs, errs := drain(src.Scrape(context.Background()))
+
+	// Assert
+	require.Len(t, recs, 2, "Scrape() should send every record")
+	assert.Equal(t, "tx-1", recs[0].ID, "Scrape() should keep the plugin's IDs")
+	assert.Equal(t, records.RecordTypeReceipt, recs[0].Type, "Scrape() should keep the plugin's record type")
+	assert.Equal(t, records.RecordTypeOther, recs[1].Type, "Scrape() should default the record type")
+	assert.False(t, recs[1].CreatedAt.IsZero(), "Scrape() should default the creation time")
+	assert.Equal(t, "mybank", recs[1].Metadata[plugins.MetaPlugin], "Scrape() should name the plugin in the metadata")
+	require.Len(t, errs, 1, "Scrape() should report the items the plugin failed to scrape")
+	assert.Contains(t, errs[0].Error(), "statement 2 is locked", "Scrape() should report the plugin's error")
+}
+
+func TestPluginSource_Scrape_Failure(t *testing.T) {
+	// Arrange
+	path := writePlugin(t, t.TempDir(), "mybank", `scrape) echo 'login rejected' >&2; exit 3 ;;`)
+	src := plugins.NewPluginSource(plugins.Plugin{Path: path, Manifest: plugins.Manifest{Name: "mybank", Kind: plugins.KindSource}}, source.Buffers{})
+
+	// Act
+	recs, errs := drain(src.Scrape(context.Background()))
+
+	// Assert
+	assert.Empty(t, recs, "Scrape() should send no records")
+	require.Len(t, errs, 1, "Scrape() should report the failed run")
+	assert.Contains(t, errs[0].Error(), "login rejected", "Scrape() should report the plugin's stderr")
+}
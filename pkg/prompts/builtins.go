@@ -0,0 +1,82 @@
+package prompts
+
+// definition describes a built-in template
+type definition struct {
+	name      string
+	version   string
+	variables []string
+	text      string
+}
+
+// builtins are the templates shipped with the application
+var builtins = []definition{
+	{
+		name:      Classification,
+		version:   "v1",
+		variables: []string{"Types", "Text"},
+		text:      `Classify the following text into exactly one of these categories: {{.Types}}. Reply with ONLY the category name in lowercase. Text: {{.Text}} Category:`,
+	},
+	{
+		name:      MetadataExtraction,
+		version:   "v1",
+		variables: []string{"Type", "Fields", "Text"},
+		text: `Extract the following fields from this {{.Type}} document: {{.Fields}}.
+Use null for any field that is not present. Dates must be in YYYY-MM-DD format and amounts must be numbers.
+Document:
+{{.Text}}`,
+	},
+	{
+		name:      EntityExtraction,
+		version:   "v1",
+		variables: []string{"Kinds", "Text"},
+		text: `List the named entities in this document. Only include entities of these kinds: {{.Kinds}}.
+Return JSON with the key "entities": an array of objects with "name" (as written in the document) and "kind".
+Document:
+{{.Text}}`,
+	},
+	{
+		name:      QueryParsing,
+		version:   "v1",
+		variables: []string{"Types", "Today", "Query"},
+		text: `Today is {{.Today}}. Turn the question below into a search filter.
+Return JSON with the keys "keywords" (string), "type" (one of: {{.Types}}, or empty), "from" and "to" (YYYY-MM-DD or empty).
+Question: {{.Query}}`,
+	},
+	{
+		name:      Answering,
+		version:   "v1",
+		variables: []string{"Context", "Question"},
+		text: `Answer the question using only the records below. Cite the record IDs you used in square brackets.
+If the records do not contain the answer, say you don't know.
+Records:
+{{.Context}}
+Question: {{.Question}}`,
+	},
+	{
+		name:      Chatting,
+		version:   "v1",
+		variables: []string{"History", "Context", "Question"},
+		text: `You are chatting with the owner of the records below about their documents. Answer the latest question using the conversation so far and only the records below.
+Cite the record IDs you used in square brackets. If the records do not contain the answer, say you don't know.
+Conversation:
+{{.History}}
+Records:
+{{.Context}}
+Question: {{.Question}}`,
+	},
+	{
+		name:    Transcription,
+		version: "v1",
+		text: `Transcribe all text in this image exactly as it appears, preserving line breaks and reading order.
+Reply with ONLY the transcribed text. If the image contains no text, reply with an empty message.`,
+	},
+	{
+		name:      Summarization,
+		version:   "v1",
+		variables: []string{"Type", "Text"},
+		text: `Summarize this {{.Type}} document in 2 to 3 sentences. Name who or what it is about, the key dates and amounts, and anything that must be done.
+Reply with ONLY the summary.
+Document:
+{{.Text}}`,
+	},
+}
@@ -0,0 +1,135 @@
+// Package prompts provides the named, versioned prompt templates sent to language models.
+package prompts
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+// Template names
+const (
+	Classification     = "classification"
+	MetadataExtraction = "metadata_extraction"
+	EntityExtraction   = "entity_extraction"
+	QueryParsing       = "query_parsing"
+	Answering          = "answering"
+	Transcription      = "transcription"
+	Summarization      = "summarization"
+	Chatting           = "chatting"
+)
+
+// overrideVersion is the version reported for templates loaded from the user prompts directory
+const overrideVersion = "user"
+
+// Renderer renders named prompt templates
+//
+//go:generate mockgen -destination=./mocks/mock_renderer.go -mock_names=Renderer=MockRenderer -package=mocks . Renderer
+type Renderer interface {
+	// Render fills the named template with vars and returns the prompt text
+	Render(name string, vars map[string]any) (string, error)
+}
+
+// Template represents a single named prompt template
+type Template struct {
+	Name      string
+	Version   string
+	Variables []string // Variables that must be supplied when rendering
+	text      *template.Template
+}
+
+// Registry holds the built-in templates and any user overrides
+type Registry struct {
+	templates map[string]Template
+}
+
+// NewRegistry creates a registry of the built-in templates. Files named
+// <name>.tmpl in overrideDir replace the built-in template of the same name;
+// an empty overrideDir or a missing directory keeps the built-ins.
+func NewRegistry(overrideDir string) (*Registry, error) {
+	r := &Registry{templates: make(map[string]Template)}
+
+	for _, def := range builtins {
+		if err := r.add(def.name, def.version, def.variables, def.text); err != nil {
+			return nil, err
+		}
+	}
+
+	if overrideDir == "" {
+		return r, nil
+	}
+	if err := r.loadOverrides(overrideDir); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Get returns the named template
+func (r *Registry) Get(name string) (Template, error) {
+	tmpl, ok := r.templates[name]
+	if !ok {
+		return Template{}, fmt.Errorf("unknown prompt template: %s", name)
+	}
+	return tmpl, nil
+}
+
+// Render fills the named template with vars and returns the prompt text
+func (r *Registry) Render(name string, vars map[string]any) (string, error) {
+	tmpl, err := r.Get(name)
+	if err != nil {
+		return "", err
+	}
+
+	var missing []string
+	for _, v := range tmpl.Variables {
+		if _, ok := vars[v]; !ok {
+			missing = append(missing, v)
+		}
+	}
+	if len(missing) > 0 {
+		return "", fmt.Errorf("prompt %s (%s) is missing variables: %s", name, tmpl.Version, strings.Join(missing, ", "))
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.text.Execute(&buf, vars); err != nil {
+		return "", fmt.Errorf("failed to render prompt %s (%s): %w", name, tmpl.Version, err)
+	}
+	return buf.String(), nil
+}
+
+func (r *Registry) add(name, version string, variables []string, text string) error {
+	parsed, err := template.New(name).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return fmt.Errorf("failed to parse prompt %s (%s): %w", name, version, err)
+	}
+
+	r.templates[name] = Template{
+		Name:      name,
+		Version:   version,
+		Variables: variables,
+		text:      parsed,
+	}
+	return nil
+}
+
+func (r *Registry) loadOverrides(dir string) error {
+	for name, builtin := range r.templates {
+		data, err := os.ReadFile(filepath.Join(dir, name+".tmpl"))
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read prompt override %s: %w", name, err)
+		}
+
+		// Overrides must accept the same variables as the template they replace
+		if err := r.add(name, overrideVersion, builtin.Variables, string(data)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+package prompts
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistry_Render_Builtin(t *testing.T) {
+	// Arrange
+	registry, err := NewRegistry("")
+	require.NoError(t, err, "NewRegistry() error should be nil")
+
+	// Act
+	prompt, err := registry.Render(Classification, map[string]any{"Types": "receipt, tax", "Text": "Total $5"})
+
+	// Assert
+	require.NoError(t, err, "Render() error should be nil")
+	assert.Contains(t, prompt, "receipt, tax", "Render() should include the types")
+	assert.Contains(t, prompt, "Total $5", "Render() should include the text")
+}
+
+func TestRegistry_Render_MissingVariable(t *testing.T) {
+	// Arrange
+	registry, err := NewRegistry("")
+	require.NoError(t, err, "NewRegistry() error should be nil")
+
+	// Act
+	_, err = registry.Render(Classification, map[string]any{"Text": "Total $5"})
+
+	// Assert
+	require.Error(t, err, "Render() should fail when a variable is missing")
+	assert.Contains(t, err.Error(), "Types", "error should name the missing variable")
+}
+
+func TestNewRegistry_Override(t *testing.T) {
+	// Arrange
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, Classification+".tmpl"), []byte("Custom: {{.Text}}"), 0600))
+
+	// Act
+	registry, err := NewRegistry(dir)
+
+	// Assert
+	require.NoError(t, err, "NewRegistry() error should be nil")
+	tmpl, err := registry.Get(Classification)
+	require.NoError(t, err, "Get() error should be nil")
+	assert.Equal(t, "user", tmpl.Version, "override should report the user version")
+	prompt, err := registry.Render(Classification, map[string]any{"Types": "", "Text": "hello"})
+	require.NoError(t, err, "Render() error should be nil")
+	assert.Equal(t, "Custom: hello", prompt, "Render() should use the override text")
+}
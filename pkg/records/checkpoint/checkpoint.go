@@ -0,0 +1,148 @@
+// Package checkpoint keeps the progress of scrapes, so an interrupted scrape
+// resumes after the files it already processed instead of starting over.
+package checkpoint
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Checkpoint represents how far a scrape of a source got
+type Checkpoint struct {
+	Source    string    `json:"source"`
+	Cursor    string    `json:"cursor"`   // Last file processed with every file before it; empty before the first
+	Ingested  int       `json:"ingested"` // Files whose record was ingested
+	Failed    int       `json:"failed"`   // Failures to read, extract or ingest a file; the cursor stops before failed files
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Progress represents how many files a scrape came across in its current
+// run, which a resumed scrape starts counting from zero
+type Progress struct {
+	Source     string
+	Discovered int // Files walked, including those skipped
+	Ingested   int
+	Failed     int
+	Skipped    int // Files unchanged since they were scraped
+}
+
+// Done returns how many of the files discovered were processed
+func (p Progress) Done() int {
+	return p.Ingested + p.Failed + p.Skipped
+}
+
+// Store defines operations for keeping scrape checkpoints
+//
+//go:generate mockgen -destination=./mocks/mock_store.go -mock_names=Store=MockStore -package=mocks . Store
+type Store interface {
+	// Load returns the checkpoint of a source, with an empty cursor when there is none
+	Load(ctx context.Context, source string) (Checkpoint, error)
+
+	// Save stores the checkpoint of its source, replacing the previous one
+	Save(ctx context.Context, checkpoint Checkpoint) error
+
+	// Delete removes the checkpoint of a source, if any
+	Delete(ctx context.Context, source string) error
+}
+
+// Tracker advances a checkpoint while a source is scraped. Files are walked
+// in order but finish out of order, as stages process several at once, so
+// the cursor only moves past a file once every file walked before it
+// finished. It is safe for concurrent use; a nil Tracker tracks nothing.
+type Tracker struct {
+	mu         sync.Mutex
+	checkpoint Checkpoint
+	walked     []string        // Files walked after the cursor, in order
+	finished   map[string]bool // Files walked after the cursor that finished
+	progress   Progress
+	report     func(Progress) // nil reports nothing
+}
+
+// NewTracker creates a tracker continuing from the checkpoint. The progress
+// is passed to report, which may be nil, whenever a file is walked or
+// finished. Report is called with the tracker locked, so calls are in order.
+func NewTracker(checkpoint Checkpoint, report func(Progress)) *Tracker {
+	return &Tracker{
+		checkpoint: checkpoint,
+		finished:   map[string]bool{},
+		progress:   Progress{Source: checkpoint.Source},
+		report:     report,
+	}
+}
+
+// Cursor returns the cursor the scrape resumed from
+func (t *Tracker) Cursor() string {
+	if t == nil {
+		return ""
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.checkpoint.Cursor
+}
+
+// Walked records that a file is about to be processed
+func (t *Tracker) Walked(path string) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.walked = append(t.walked, path)
+	t.progress.Discovered++
+	t.reportProgress()
+}
+
+// Skipped records that a file was walked but skipped, as it is unchanged
+// since it was scraped
+func (t *Tracker) Skipped() {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.progress.Discovered++
+	t.progress.Skipped++
+	t.reportProgress()
+}
+
+// Finished records that a file was processed. A failed file is counted but
+// never passed by the cursor, so a resumed scrape retries it.
+func (t *Tracker) Finished(path string, failed bool) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	defer t.reportProgress()
+	if failed {
+		t.checkpoint.Failed++
+		t.progress.Failed++
+		return
+	}
+	t.checkpoint.Ingested++
+	t.progress.Ingested++
+	t.finished[path] = true
+	for len(t.walked) > 0 && t.finished[t.walked[0]] {
+		t.checkpoint.Cursor = t.walked[0]
+		delete(t.finished, t.walked[0])
+		t.walked = t.walked[1:]
+	}
+}
+
+// reportProgress passes the progress to report. The tracker must be locked.
+func (t *Tracker) reportProgress() {
+	if t.report != nil {
+		t.report(t.progress)
+	}
+}
+
+// Checkpoint returns the checkpoint reached so far
+func (t *Tracker) Checkpoint(now time.Time) Checkpoint {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	checkpoint := t.checkpoint
+	checkpoint.UpdatedAt = now
+	return checkpoint
+}
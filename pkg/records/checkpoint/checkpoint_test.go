@@ -0,0 +1,66 @@
+package checkpoint_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kazemisoroush/assistant/pkg/records/checkpoint"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTracker_Finished(t *testing.T) {
+	// Arrange
+	tracker := checkpoint.NewTracker(checkpoint.Checkpoint{Source: "local", Cursor: "a", Ingested: 1}, nil)
+	for _, path := range []string{"b", "c", "d", "e"} {
+		tracker.Walked(path)
+	}
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	// Act
+	tracker.Finished("c", false)
+	beforeB := tracker.Checkpoint(now)
+	tracker.Finished("b", false)
+	tracker.Finished("d", true)
+	tracker.Finished("e", false)
+	cp := tracker.Checkpoint(now)
+
+	// Assert
+	assert.Equal(t, "a", beforeB.Cursor, "Finished() should not pass a file walked earlier that is still processed")
+	assert.Equal(t, "c", cp.Cursor, "Finished() should advance past every file finished in order")
+	assert.Equal(t, 4, cp.Ingested, "Finished() should count ingested files on top of the checkpoint")
+	assert.Equal(t, 1, cp.Failed, "Finished() should count failed files")
+	assert.Equal(t, now, cp.UpdatedAt, "Checkpoint() should stamp the checkpoint")
+}
+
+func TestTracker_Nil(t *testing.T) {
+	// Arrange
+	var tracker *checkpoint.Tracker
+
+	// Act
+	tracker.Walked("a")
+	tracker.Finished("a", false)
+
+	// Assert
+	assert.Empty(t, tracker.Cursor(), "Cursor() should be empty for a nil tracker")
+}
+
+func TestTracker_Report(t *testing.T) {
+	// Arrange
+	var reported []checkpoint.Progress
+	tracker := checkpoint.NewTracker(checkpoint.Checkpoint{Source: "local", Ingested: 5}, func(progress checkpoint.Progress) {
+		reported = append(reported, progress)
+	})
+
+	// Act
+	tracker.Walked("a")
+	tracker.Walked("b")
+	tracker.Skipped()
+	tracker.Finished("a", false)
+	tracker.Finished("b", true)
+
+	// Assert
+	assert.Len(t, reported, 5, "NewTracker() should report the progress whenever a file is walked or finished")
+	last := reported[len(reported)-1]
+	assert.Equal(t, checkpoint.Progress{Source: "local", Discovered: 3, Ingested: 1, Failed: 1, Skipped: 1}, last, "NewTracker() should report the progress of the current run")
+	assert.Equal(t, 3, last.Done(), "Done() should count every file processed")
+}
@@ -0,0 +1,72 @@
+package checkpoint
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/kazemisoroush/assistant/pkg/sqlite"
+)
+
+// SQLiteStore is a Store backed by SQLite
+type SQLiteStore struct {
+	db *sqlite.DB
+}
+
+// NewSQLiteStore creates a new SQLite checkpoint store in the given database
+func NewSQLiteStore(db *sqlite.DB) (*SQLiteStore, error) {
+	schema := `
+    CREATE TABLE IF NOT EXISTS scrape_checkpoints (
+        source TEXT PRIMARY KEY,
+        cursor TEXT NOT NULL,
+        ingested INTEGER NOT NULL,
+        failed INTEGER NOT NULL,
+        updated_at DATETIME NOT NULL
+    );
+    `
+	if _, err := db.Exec(schema); err != nil {
+		return nil, fmt.Errorf("failed to initialize checkpoint schema: %w", err)
+	}
+
+	return &SQLiteStore{db: db}, nil
+}
+
+// Load implements Store
+func (s *SQLiteStore) Load(ctx context.Context, source string) (Checkpoint, error) {
+	checkpoint := Checkpoint{Source: source}
+	var updatedAt time.Time
+	err := s.db.QueryRowContext(ctx,
+		`SELECT cursor, ingested, failed, updated_at FROM scrape_checkpoints WHERE source = ?`, source,
+	).Scan(&checkpoint.Cursor, &checkpoint.Ingested, &checkpoint.Failed, &updatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return checkpoint, nil
+	}
+	if err != nil {
+		return Checkpoint{}, fmt.Errorf("failed to load checkpoint of source %s: %w", source, err)
+	}
+	checkpoint.UpdatedAt = updatedAt
+	return checkpoint, nil
+}
+
+// Save implements Store
+func (s *SQLiteStore) Save(ctx context.Context, checkpoint Checkpoint) error {
+	if _, err := s.db.ExecContext(ctx,
+		`INSERT INTO scrape_checkpoints (source, cursor, ingested, failed, updated_at) VALUES (?, ?, ?, ?, ?)
+         ON CONFLICT (source) DO UPDATE SET cursor = excluded.cursor, ingested = excluded.ingested,
+             failed = excluded.failed, updated_at = excluded.updated_at`,
+		checkpoint.Source, checkpoint.Cursor, checkpoint.Ingested, checkpoint.Failed, checkpoint.UpdatedAt,
+	); err != nil {
+		return fmt.Errorf("failed to save checkpoint of source %s: %w", checkpoint.Source, err)
+	}
+	return nil
+}
+
+// Delete implements Store
+func (s *SQLiteStore) Delete(ctx context.Context, source string) error {
+	if _, err := s.db.ExecContext(ctx, `DELETE FROM scrape_checkpoints WHERE source = ?`, source); err != nil {
+		return fmt.Errorf("failed to delete checkpoint of source %s: %w", source, err)
+	}
+	return nil
+}
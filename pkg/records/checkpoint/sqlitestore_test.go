@@ -0,0 +1,44 @@
+package checkpoint_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/kazemisoroush/assistant/pkg/records/checkpoint"
+	"github.com/kazemisoroush/assistant/pkg/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// openDB opens the database at path, closed once the test is done
+func openDB(t testing.TB, path string) *sqlite.DB {
+	t.Helper()
+	db, err := sqlite.Open(path)
+	require.NoError(t, err, "Open() error should be nil")
+	t.Cleanup(func() { _ = db.Close() })
+	return db
+}
+
+func TestSQLiteStore_SaveLoadDelete(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	store, err := checkpoint.NewSQLiteStore(openDB(t, filepath.Join(t.TempDir(), "assistant.db")))
+	require.NoError(t, err, "NewSQLiteStore() error should be nil")
+	saved := checkpoint.Checkpoint{Source: "local", Cursor: "docs/b.pdf", Ingested: 12, Failed: 1, UpdatedAt: time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)}
+
+	// Act
+	err = store.Save(ctx, saved)
+
+	// Assert
+	require.NoError(t, err, "Save() error should be nil")
+	loaded, err := store.Load(ctx, "local")
+	require.NoError(t, err, "Load() error should be nil")
+	assert.Equal(t, saved, loaded, "Load() should return the saved checkpoint")
+
+	require.NoError(t, store.Delete(ctx, "local"), "Delete() error should be nil")
+	loaded, err = store.Load(ctx, "local")
+	require.NoError(t, err, "Load() error should be nil without a checkpoint")
+	assert.Equal(t, checkpoint.Checkpoint{Source: "local"}, loaded, "Load() should start from scratch once the checkpoint was deleted")
+}
@@ -0,0 +1,66 @@
+// Package chunker splits the content of long records into overlapping chunks,
+// so every part of a long document is embedded on its own instead of the
+// whole document sharing one embedding.
+package chunker
+
+import (
+	"strconv"
+	"strings"
+)
+
+// MaxChunks is the most chunks content is split into. Longer content is
+// split into longer chunks, so the chunks of a record are always found
+// under the IDs of its first MaxChunks chunks.
+const MaxChunks = 32
+
+// idSeparator separates the ID of a record from the number of one of its chunks
+const idSeparator = "#chunk-"
+
+// Config represents how content is split into chunks, counted in words
+type Config struct {
+	Size    int // Words per chunk; 0 disables chunking
+	Overlap int // Words each chunk repeats from the end of the one before it
+}
+
+// Split splits content into chunks of words, each overlapping the one
+// before it. Content fitting in one chunk is returned as it is.
+func Split(content string, cfg Config) []string {
+	words := strings.Fields(content)
+	if cfg.Size <= 0 || len(words) <= cfg.Size {
+		return []string{content}
+	}
+	overlap := min(max(cfg.Overlap, 0), cfg.Size-1)
+	// Each chunk advances by stride words past the one before it
+	stride := max(cfg.Size-overlap, (len(words)-overlap+MaxChunks-1)/MaxChunks)
+
+	var chunks []string
+	for start := 0; ; start += stride {
+		end := min(start+stride+overlap, len(words))
+		chunks = append(chunks, strings.Join(words[start:end], " "))
+		if end == len(words) {
+			return chunks
+		}
+	}
+}
+
+// ID returns the ID the chunk at index i of a record is indexed under. The
+// first chunk is indexed under the record's own ID.
+func ID(recordID string, i int) string {
+	if i == 0 {
+		return recordID
+	}
+	return recordID + idSeparator + strconv.Itoa(i)
+}
+
+// Parent returns the ID of the record a chunk ID belongs to, or the ID
+// itself when it is not the ID of a chunk
+func Parent(id string) string {
+	i := strings.LastIndex(id, idSeparator)
+	if i < 0 {
+		return id
+	}
+	if _, err := strconv.Atoi(id[i+len(idSeparator):]); err != nil {
+		return id
+	}
+	return id[:i]
+}
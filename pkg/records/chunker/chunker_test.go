@@ -0,0 +1,52 @@
+package chunker_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/kazemisoroush/assistant/pkg/records/chunker"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// words returns n numbered words separated by spaces
+func words(n int) string {
+	parts := make([]string, n)
+	for i := range parts {
+		parts[i] = fmt.Sprintf("w%d", i)
+	}
+	return strings.Join(parts, " ")
+}
+
+func TestSplit(t *testing.T) {
+	// Act
+	chunks := chunker.Split(words(10), chunker.Config{Size: 4, Overlap: 1})
+
+	// Assert
+	assert.Equal(t, []string{"w0 w1 w2 w3", "w3 w4 w5 w6", "w6 w7 w8 w9"}, chunks, "Split() should overlap every chunk with the one before it")
+}
+
+func TestSplit_FitsOneChunk(t *testing.T) {
+	// Act
+	chunks := chunker.Split("short\n\ncontent", chunker.Config{Size: 4, Overlap: 1})
+
+	// Assert
+	assert.Equal(t, []string{"short\n\ncontent"}, chunks, "Split() should return content fitting in one chunk as it is")
+}
+
+func TestSplit_MaxChunks(t *testing.T) {
+	// Act
+	chunks := chunker.Split(words(1000), chunker.Config{Size: 10, Overlap: 2})
+
+	// Assert
+	require.LessOrEqual(t, len(chunks), chunker.MaxChunks, "Split() should grow chunks to split into at most MaxChunks")
+	assert.True(t, strings.HasSuffix(chunks[len(chunks)-1], "w999"), "Split() should keep every word")
+}
+
+func TestIDParent(t *testing.T) {
+	// Assert
+	assert.Equal(t, "rec-1", chunker.ID("rec-1", 0), "ID() should index the first chunk under the record's ID")
+	assert.Equal(t, "rec-1", chunker.Parent(chunker.ID("rec-1", 3)), "Parent() should return the record of a chunk")
+	assert.Equal(t, "rec#chunk-x", chunker.Parent("rec#chunk-x"), "Parent() should return IDs of no chunk as they are")
+}
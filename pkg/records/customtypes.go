@@ -0,0 +1,176 @@
+package records
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// FieldType represents the type of a custom metadata field
+type FieldType string
+
+// Field types of custom metadata
+const (
+	FieldTypeString FieldType = "string"
+	FieldTypeNumber FieldType = "number"
+	FieldTypeDate   FieldType = "date" // YYYY-MM-DD
+	FieldTypeBool   FieldType = "bool"
+)
+
+// FieldSchema describes a metadata field extracted from records of a custom type
+type FieldSchema struct {
+	Name        string    `json:"name"`
+	Type        FieldType `json:"type"`
+	Description string    `json:"description,omitempty"`
+	Required    bool      `json:"required,omitempty"` // Must be present and not empty
+	Enum        []string  `json:"enum,omitempty"`     // Allowed values of a string field
+}
+
+// TypeDefinition describes a user-defined record type
+type TypeDefinition struct {
+	Name        RecordType    `json:"name"`
+	Description string        `json:"description"`
+	Fields      []FieldSchema `json:"fields,omitempty"` // Metadata extracted from records of the type
+	Hints       []string      `json:"hints,omitempty"`  // Words or phrases that identify the type during classification
+}
+
+// typeNamePattern restricts custom type and field names to lowercase identifiers
+var typeNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+// Validate checks that the definition has a new, well-formed name and a valid metadata schema
+func (d TypeDefinition) Validate() error {
+	if !typeNamePattern.MatchString(string(d.Name)) {
+		return fmt.Errorf("type name must be lowercase letters, digits and underscores: %q", d.Name)
+	}
+	if slices.Contains(builtinRecordTypes(), d.Name) {
+		return fmt.Errorf("%s is a built-in record type", d.Name)
+	}
+	if d.Description == "" {
+		return errors.New("description is required")
+	}
+
+	return validateFields(d.Fields)
+}
+
+// validateFields checks that the fields have distinct, well-formed names and known types
+func validateFields(fields []FieldSchema) error {
+	seen := map[string]bool{}
+	for _, field := range fields {
+		if !typeNamePattern.MatchString(field.Name) {
+			return fmt.Errorf("field name must be lowercase letters, digits and underscores: %q", field.Name)
+		}
+		if seen[field.Name] {
+			return fmt.Errorf("field %s is defined twice", field.Name)
+		}
+		seen[field.Name] = true
+		if !slices.Contains([]FieldType{FieldTypeString, FieldTypeNumber, FieldTypeDate, FieldTypeBool}, field.Type) {
+			return fmt.Errorf("field %s has unknown type %q", field.Name, field.Type)
+		}
+		if len(field.Enum) > 0 && field.Type != FieldTypeString {
+			return fmt.Errorf("field %s lists allowed values but is not a string", field.Name)
+		}
+	}
+	return nil
+}
+
+// Check validates a single decoded JSON value, nil when absent, against the field
+func (f FieldSchema) Check(value interface{}) error {
+	if value == nil || value == "" {
+		if f.Required {
+			return fmt.Errorf("%s is required", f.Name)
+		}
+		if value == nil {
+			return nil
+		}
+	}
+	if err := f.checkType(value); err != nil {
+		return err
+	}
+	if text, _ := value.(string); len(f.Enum) > 0 && text != "" && !slices.Contains(f.Enum, text) {
+		return fmt.Errorf("%s must be one of %s: %q", f.Name, strings.Join(f.Enum, ", "), text)
+	}
+	return nil
+}
+
+// checkType validates a decoded JSON value against the field type
+func (f FieldSchema) checkType(value interface{}) error {
+	var ok bool
+	switch f.Type {
+	case FieldTypeString:
+		_, ok = value.(string)
+	case FieldTypeNumber:
+		_, ok = value.(float64)
+	case FieldTypeBool:
+		_, ok = value.(bool)
+	case FieldTypeDate:
+		var date string
+		if date, ok = value.(string); ok && date != "" {
+			if _, err := time.Parse(time.DateOnly, date); err != nil {
+				return fmt.Errorf("%s must be in YYYY-MM-DD format: %q", f.Name, date)
+			}
+		}
+	}
+	if !ok {
+		return fmt.Errorf("%s must be a %s: %v", f.Name, f.Type, value)
+	}
+	return nil
+}
+
+// customTypes holds the registered user-defined record types
+var customTypes = struct {
+	sync.RWMutex
+	definitions map[RecordType]TypeDefinition
+}{definitions: map[RecordType]TypeDefinition{}}
+
+// RegisterType makes a user-defined record type valid for classification and filters
+func RegisterType(def TypeDefinition) error {
+	if err := def.Validate(); err != nil {
+		return fmt.Errorf("invalid record type %s: %w", def.Name, err)
+	}
+	customTypes.Lock()
+	defer customTypes.Unlock()
+	customTypes.definitions[def.Name] = def
+	return nil
+}
+
+// UnregisterType removes a user-defined record type. Records of the type keep it.
+func UnregisterType(name RecordType) {
+	customTypes.Lock()
+	defer customTypes.Unlock()
+	delete(customTypes.definitions, name)
+}
+
+// CustomType returns the definition of a user-defined record type
+func CustomType(name RecordType) (TypeDefinition, bool) {
+	customTypes.RLock()
+	defer customTypes.RUnlock()
+	def, ok := customTypes.definitions[name]
+	return def, ok
+}
+
+// CustomTypes returns the user-defined record types sorted by name
+func CustomTypes() []TypeDefinition {
+	customTypes.RLock()
+	defer customTypes.RUnlock()
+	defs := make([]TypeDefinition, 0, len(customTypes.definitions))
+	for _, def := range customTypes.definitions {
+		defs = append(defs, def)
+	}
+	sort.Slice(defs, func(i, j int) bool { return defs[i].Name < defs[j].Name })
+	return defs
+}
+
+// customRecordTypes returns the names of the user-defined record types
+func customRecordTypes() []RecordType {
+	defs := CustomTypes()
+	types := make([]RecordType, 0, len(defs))
+	for _, def := range defs {
+		types = append(types, def.Name)
+	}
+	return types
+}
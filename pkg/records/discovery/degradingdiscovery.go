@@ -0,0 +1,36 @@
+package discovery
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/kazemisoroush/assistant/pkg/breaker"
+)
+
+// DegradingDiscovery serves results from a fallback discovery, such as
+// keyword search, while the primary discovery's dependencies are unavailable.
+type DegradingDiscovery struct {
+	primary  Discovery
+	fallback Discovery
+}
+
+// NewDegradingDiscovery creates a new instance of DegradingDiscovery.
+func NewDegradingDiscovery(primary, fallback Discovery) Discovery {
+	return &DegradingDiscovery{
+		primary:  primary,
+		fallback: fallback,
+	}
+}
+
+// Discover implements the Discovery interface. Only an open circuit falls
+// back; other errors are returned so real failures are not masked.
+func (d *DegradingDiscovery) Discover(ctx context.Context, request DiscoverRequest) (DiscoverResponse, error) {
+	resp, err := d.primary.Discover(ctx, request)
+	if !errors.Is(err, breaker.ErrOpen) {
+		return resp, err
+	}
+
+	slog.WarnContext(ctx, "Search degraded to keyword matching", "error", err)
+	return d.fallback.Discover(ctx, request)
+}
@@ -22,8 +22,21 @@ type DiscoverResponse struct {
 
 // Hit represents a single discovered record with metadata
 type Hit struct {
-	RecordID string
-	Score    float64
-	Meta     map[string]any // type/date/merchant/etc if you have it
-	Source   string         // "vector", "sql", "hybrid"
+	RecordID    string
+	Score       float64
+	Description string         // Summary of the record, empty if it was not summarized
+	Meta        map[string]any // type/date/merchant/etc if you have it
+	Source      string         // SourceVector, SourceSQL or SourceHybrid
 }
+
+// Sources of hits, naming the search that found them
+const (
+	// SourceVector marks hits found by vector search
+	SourceVector = "vector"
+
+	// SourceSQL marks hits found by the storage's keyword search
+	SourceSQL = "sql"
+
+	// SourceHybrid marks hits found by both searches
+	SourceHybrid = "hybrid"
+)
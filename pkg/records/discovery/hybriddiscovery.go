@@ -0,0 +1,99 @@
+package discovery
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+)
+
+// Reciprocal-rank fusion settings
+const (
+	// rrfK damps the weight of the top ranks, so a record ranked well by both
+	// searches beats one ranked first by a single search
+	rrfK = 60
+
+	// candidateFactor is how many more hits than requested each search returns
+	// to be fused
+	candidateFactor = 2
+)
+
+// HybridDiscovery runs keyword and vector search in parallel and merges their
+// hits with reciprocal-rank fusion, so records matching the prompt's words
+// and records matching its meaning both surface.
+type HybridDiscovery struct {
+	keyword Discovery
+	vector  Discovery
+}
+
+// NewHybridDiscovery creates a new instance of HybridDiscovery.
+func NewHybridDiscovery(keyword, vector Discovery) Discovery {
+	return &HybridDiscovery{
+		keyword: keyword,
+		vector:  vector,
+	}
+}
+
+// Discover implements the Discovery interface. Hits are scored by the sum of
+// 1/(rrfK + rank) over the searches finding them, scaled so a record ranked
+// first by both scores 1. Either search failing fails the discovery, so an
+// open circuit reaches a DegradingDiscovery wrapping this one.
+func (d *HybridDiscovery) Discover(ctx context.Context, request DiscoverRequest) (DiscoverResponse, error) {
+	candidates := request
+	candidates.Limit = request.Limit * candidateFactor
+
+	var keyword, vector DiscoverResponse
+	var keywordErr, vectorErr error
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		keyword, keywordErr = d.keyword.Discover(ctx, candidates)
+	}()
+	go func() {
+		defer wg.Done()
+		vector, vectorErr = d.vector.Discover(ctx, candidates)
+	}()
+	wg.Wait()
+	if err := errors.Join(keywordErr, vectorErr); err != nil {
+		return DiscoverResponse{}, err
+	}
+
+	hits := fuse(keyword.Hits, vector.Hits)
+	if request.Limit > 0 && len(hits) > request.Limit {
+		hits = hits[:request.Limit]
+	}
+	return DiscoverResponse{Hits: hits}, nil
+}
+
+// fuse merges the ranked keyword and vector hits by reciprocal-rank fusion,
+// best first
+func fuse(keyword, vector []Hit) []Hit {
+	byID := make(map[string]*Hit, len(keyword)+len(vector))
+	var order []string
+	add := func(hits []Hit, source string) {
+		for rank, hit := range hits {
+			score := 1 / float64(rrfK+rank+1)
+			if fused, ok := byID[hit.RecordID]; ok {
+				fused.Score += score
+				fused.Source = SourceHybrid
+				continue
+			}
+			hit.Score, hit.Source = score, source
+			byID[hit.RecordID] = &hit
+			order = append(order, hit.RecordID)
+		}
+	}
+	add(keyword, SourceSQL)
+	add(vector, SourceVector)
+
+	best := 2 / float64(rrfK+1)
+	hits := make([]Hit, 0, len(order))
+	for _, id := range order {
+		hit := *byID[id]
+		hit.Score /= best
+		hits = append(hits, hit)
+	}
+	sort.SliceStable(hits, func(i, j int) bool { return hits[i].Score > hits[j].Score })
+	return hits
+}
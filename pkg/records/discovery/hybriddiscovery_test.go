@@ -0,0 +1,61 @@
+package discovery_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/kazemisoroush/assistant/pkg/breaker"
+	"github.com/kazemisoroush/assistant/pkg/records/discovery"
+	discoverymocks "github.com/kazemisoroush/assistant/pkg/records/discovery/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestHybridDiscovery_Discover_FusesRanks(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	keyword := discoverymocks.NewMockDiscovery(ctrl)
+	vector := discoverymocks.NewMockDiscovery(ctrl)
+	request := discovery.DiscoverRequest{Prompt: "dentist invoice", Limit: 2}
+	candidates := discovery.DiscoverRequest{Prompt: "dentist invoice", Limit: 4}
+	keyword.EXPECT().Discover(gomock.Any(), candidates).Return(discovery.DiscoverResponse{Hits: []discovery.Hit{
+		{RecordID: "invoice", Score: 0.9, Source: discovery.SourceSQL},
+		{RecordID: "both", Score: 0.5, Source: discovery.SourceSQL},
+	}}, nil)
+	vector.EXPECT().Discover(gomock.Any(), candidates).Return(discovery.DiscoverResponse{Hits: []discovery.Hit{
+		{RecordID: "both", Score: 0.8, Description: "Dentist visit", Source: discovery.SourceVector},
+		{RecordID: "visit", Score: 0.7, Source: discovery.SourceVector},
+	}}, nil)
+	d := discovery.NewHybridDiscovery(keyword, vector)
+
+	// Act
+	resp, err := d.Discover(context.Background(), request)
+
+	// Assert
+	require.NoError(t, err, "Discover() error should be nil")
+	require.Len(t, resp.Hits, 2, "Discover() should return at most limit hits")
+	assert.Equal(t, "both", resp.Hits[0].RecordID, "Discover() should rank hits found by both searches first")
+	assert.Equal(t, discovery.SourceHybrid, resp.Hits[0].Source, "Discover() should mark hits found by both searches")
+	assert.Equal(t, "invoice", resp.Hits[1].RecordID, "Discover() should rank single-search hits by their rank")
+	assert.Equal(t, discovery.SourceSQL, resp.Hits[1].Source, "Discover() should keep the source of single-search hits")
+	assert.Greater(t, resp.Hits[0].Score, resp.Hits[1].Score, "Discover() should score by the fused ranks")
+	assert.LessOrEqual(t, resp.Hits[0].Score, 1.0, "Discover() should score from 0 to 1")
+}
+
+func TestHybridDiscovery_Discover_SearchFails(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	keyword := discoverymocks.NewMockDiscovery(ctrl)
+	vector := discoverymocks.NewMockDiscovery(ctrl)
+	keyword.EXPECT().Discover(gomock.Any(), gomock.Any()).Return(discovery.DiscoverResponse{}, nil)
+	vector.EXPECT().Discover(gomock.Any(), gomock.Any()).Return(discovery.DiscoverResponse{}, fmt.Errorf("vector storage search failed: %w", breaker.ErrOpen))
+	d := discovery.NewHybridDiscovery(keyword, vector)
+
+	// Act
+	_, err := d.Discover(context.Background(), discovery.DiscoverRequest{Prompt: "receipt"})
+
+	// Assert
+	assert.ErrorIs(t, err, breaker.ErrOpen, "Discover() should return the error of a failing search for the circuit to degrade")
+}
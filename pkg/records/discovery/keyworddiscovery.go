@@ -0,0 +1,43 @@
+package discovery
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kazemisoroush/assistant/pkg/records/storage"
+)
+
+// KeywordDiscovery finds records by the prompt's words with the storage's
+// full-text search. It needs no embedding model or vector store, so it keeps
+// search working when those are unavailable.
+type KeywordDiscovery struct {
+	storage storage.Storage
+}
+
+// NewKeywordDiscovery creates a new instance of KeywordDiscovery.
+func NewKeywordDiscovery(storage storage.Storage) Discovery {
+	return &KeywordDiscovery{
+		storage: storage,
+	}
+}
+
+// Discover implements the Discovery interface. Records are ranked by the
+// storage's search over their title, summary, content, file name and tags.
+func (d *KeywordDiscovery) Discover(ctx context.Context, request DiscoverRequest) (DiscoverResponse, error) {
+	results, err := d.storage.Search(ctx, request.Prompt, storage.SearchFilter{}, request.Limit)
+	if err != nil {
+		return DiscoverResponse{}, fmt.Errorf("failed to search records: %w", err)
+	}
+
+	hits := make([]Hit, 0, len(results))
+	for _, result := range results {
+		hits = append(hits, Hit{
+			RecordID:    result.Record.ID,
+			Score:       result.Score,
+			Description: result.Record.Description(),
+			Meta:        result.Record.Metadata,
+			Source:      SourceSQL,
+		})
+	}
+	return DiscoverResponse{Hits: hits}, nil
+}
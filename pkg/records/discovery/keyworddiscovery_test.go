@@ -0,0 +1,106 @@
+package discovery_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/kazemisoroush/assistant/pkg/breaker"
+	"github.com/kazemisoroush/assistant/pkg/records"
+	"github.com/kazemisoroush/assistant/pkg/records/discovery"
+	discoverymocks "github.com/kazemisoroush/assistant/pkg/records/discovery/mocks"
+	"github.com/kazemisoroush/assistant/pkg/records/storage"
+	storagemocks "github.com/kazemisoroush/assistant/pkg/records/storage/mocks"
+	"github.com/kazemisoroush/assistant/pkg/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+// openDB opens the database at path, closed once the test is done
+func openDB(t testing.TB, path string) *sqlite.DB {
+	t.Helper()
+	db, err := sqlite.Open(path)
+	require.NoError(t, err, "Open() error should be nil")
+	t.Cleanup(func() { _ = db.Close() })
+	return db
+}
+
+func TestKeywordDiscovery_Discover_RanksBySearch(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	store := storagemocks.NewMockStorage(ctrl)
+	store.EXPECT().Search(gomock.Any(), "blood test", storage.SearchFilter{}, 10).Return([]records.SearchResult{
+		{Record: records.Record{ID: "blood", Content: "Blood test results", Metadata: map[string]interface{}{records.MetaDescription: "Lab results"}}, Score: 0.8},
+		{Record: records.Record{ID: "both", Content: "Blood test at the Shell clinic"}, Score: 0.5},
+	}, nil)
+	d := discovery.NewKeywordDiscovery(store)
+
+	// Act
+	resp, err := d.Discover(context.Background(), discovery.DiscoverRequest{Prompt: "blood test", Limit: 10})
+
+	// Assert
+	require.NoError(t, err, "Discover() error should be nil")
+	require.Len(t, resp.Hits, 2, "Discover() should return every search result")
+	assert.Equal(t, "blood", resp.Hits[0].RecordID, "Discover() should keep the search ranking")
+	assert.InDelta(t, 0.8, resp.Hits[0].Score, 0.001, "Discover() should keep the search score")
+	assert.Equal(t, "Lab results", resp.Hits[0].Description, "Discover() should describe hits by their summary")
+	assert.Equal(t, discovery.SourceSQL, resp.Hits[0].Source, "Discover() should mark hits as keyword matches")
+}
+
+func TestKeywordDiscovery_Discover_SearchFails(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	store := storagemocks.NewMockStorage(ctrl)
+	store.EXPECT().Search(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, errors.New("database is locked"))
+	d := discovery.NewKeywordDiscovery(store)
+
+	// Act
+	_, err := d.Discover(context.Background(), discovery.DiscoverRequest{Prompt: "receipt"})
+
+	// Assert
+	require.Error(t, err, "Discover() should fail when the search fails")
+	assert.Contains(t, err.Error(), "database is locked", "Discover() should wrap the search error")
+}
+
+func TestDegradingDiscovery_Discover_FallsBackWhenCircuitOpen(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	primary := discoverymocks.NewMockDiscovery(ctrl)
+	fallback := discoverymocks.NewMockDiscovery(ctrl)
+	primary.EXPECT().Discover(gomock.Any(), gomock.Any()).Return(discovery.DiscoverResponse{}, fmt.Errorf("vector storage search failed: %w", breaker.ErrOpen))
+	fallback.EXPECT().Discover(gomock.Any(), gomock.Any()).Return(discovery.DiscoverResponse{Hits: []discovery.Hit{{RecordID: "1", Source: discovery.SourceSQL}}}, nil)
+	d := discovery.NewDegradingDiscovery(primary, fallback)
+
+	// Act
+	resp, err := d.Discover(context.Background(), discovery.DiscoverRequest{Prompt: "receipt"})
+
+	// Assert
+	require.NoError(t, err, "Discover() error should be nil")
+	assert.Equal(t, discovery.SourceSQL, resp.Hits[0].Source, "Discover() should serve keyword results while the circuit is open")
+}
+
+func BenchmarkKeywordDiscovery_Discover(b *testing.B) {
+	words := []string{"invoice", "receipt", "dentist", "insurance", "passport", "flight", "hotel", "grocery", "fuel", "warranty"}
+	store, err := storage.NewSQLiteStorage(openDB(b, filepath.Join(b.TempDir(), "records.db")))
+	if err != nil {
+		b.Fatalf("NewSQLiteStorage() failed: %v", err)
+	}
+	ctx := context.Background()
+	for i := range 10_000 {
+		rec := records.Record{ID: fmt.Sprintf("rec-%d", i), Type: records.RecordTypeOther, Content: fmt.Sprintf("%s %s document %d", words[i%len(words)], words[(i/7)%len(words)], i)}
+		if err := store.Store(ctx, rec); err != nil {
+			b.Fatalf("Store() failed: %v", err)
+		}
+	}
+	d := discovery.NewKeywordDiscovery(store)
+
+	b.ResetTimer()
+	for range b.N {
+		if _, err := d.Discover(ctx, discovery.DiscoverRequest{Prompt: "dentist insurance", Limit: 10}); err != nil {
+			b.Fatalf("Discover() failed: %v", err)
+		}
+	}
+}
@@ -30,10 +30,11 @@ func (d *SimpleDiscovery) Discover(ctx context.Context, request DiscoverRequest)
 	hits := make([]Hit, 0, len(result))
 	for _, res := range result {
 		hit := Hit{
-			RecordID: res.Record.ID,
-			Score:    res.Score,
-			Meta:     res.Record.Metadata,
-			Source:   "vector",
+			RecordID:    res.Record.ID,
+			Score:       res.Score,
+			Description: res.Record.Description(),
+			Meta:        res.Record.Metadata,
+			Source:      SourceVector,
 		}
 		hits = append(hits, hit)
 	}
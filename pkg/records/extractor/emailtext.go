@@ -0,0 +1,246 @@
+package extractor
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"log/slog"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"net/textproto"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Media types of the emails text is read from
+const (
+	MediaTypeEmail = "message/rfc822"
+	MediaTypeMbox  = "application/mbox"
+)
+
+// mboxSeparator starts every message of an mbox archive
+var mboxSeparator = []byte("From ")
+
+// htmlTag matches the tags of HTML bodies, which are dropped from their text
+var htmlTag = regexp.MustCompile(`(?s)<(script|style)[^>]*>.*?</(script|style)>|<[^>]*>`)
+
+// email is the text read from an RFC 5322 message
+type email struct {
+	subject     string
+	from        string
+	to          string
+	date        time.Time
+	plain       []string // Text bodies
+	html        []string // HTML bodies, used when there is no text body
+	attachments []string // Names of the attachments
+	attached    []string // Text read from the attachments
+	ocrUsed     bool
+}
+
+// String renders the headers, body and attachments of the email
+func (e *email) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Subject: %s\nFrom: %s\nTo: %s\n", e.subject, e.from, e.to)
+	if !e.date.IsZero() {
+		fmt.Fprintf(&b, "Date: %s\n", e.date.Format(time.RFC3339))
+	}
+	body := e.plain
+	if len(body) == 0 {
+		body = e.html
+	}
+	for _, text := range slices.Concat(body, e.attached) {
+		b.WriteString("\n" + strings.TrimSpace(text) + "\n")
+	}
+	return b.String()
+}
+
+// readEmail returns the text of an email, or of every message of an mbox
+// archive, adding the headers of a single email to the metadata
+func (o *OCRContentExtractor) readEmail(ctx context.Context, input Input, data []byte, meta map[string]interface{}) (string, error) {
+	if input.MediaType == MediaTypeMbox {
+		return o.readMbox(ctx, data, meta)
+	}
+	e, err := o.parseEmail(ctx, data)
+	if err != nil {
+		return "", err
+	}
+	meta["ocr_used"] = e.ocrUsed
+	meta["email_subject"] = e.subject
+	meta["email_from"] = e.from
+	meta["email_to"] = e.to
+	if !e.date.IsZero() {
+		meta["email_date"] = e.date.Format(time.RFC3339)
+	}
+	if len(e.attachments) > 0 {
+		meta["email_attachments"] = e.attachments
+	}
+	return e.String(), nil
+}
+
+// readMbox returns the text of the messages of an mbox archive in order
+func (o *OCRContentExtractor) readMbox(ctx context.Context, data []byte, meta map[string]interface{}) (string, error) {
+	messages := splitMbox(data)
+	if len(messages) == 0 {
+		return "", errors.New("mbox archive holds no messages")
+	}
+	texts := make([]string, 0, len(messages))
+	ocrUsed := false
+	for i, message := range messages {
+		e, err := o.parseEmail(ctx, message)
+		if err != nil {
+			return "", fmt.Errorf("failed to read message %d: %w", i+1, err)
+		}
+		texts = append(texts, e.String())
+		ocrUsed = ocrUsed || e.ocrUsed
+	}
+	meta["ocr_used"] = ocrUsed
+	meta["email_messages"] = len(messages)
+	return strings.Join(texts, "\n---\n\n"), nil
+}
+
+// splitMbox returns the messages of an mbox archive. Each starts after a
+// "From " separator line; body lines escaped as ">From " are unescaped.
+func splitMbox(data []byte) [][]byte {
+	var messages [][]byte
+	var current []byte
+	started := false
+	for _, line := range bytes.SplitAfter(data, []byte("\n")) {
+		if bytes.HasPrefix(line, mboxSeparator) {
+			if started {
+				messages = append(messages, current)
+			}
+			current, started = nil, true
+			continue
+		}
+		if unquoted := bytes.TrimLeft(line, ">"); len(unquoted) < len(line) && bytes.HasPrefix(unquoted, mboxSeparator) {
+			line = line[1:]
+		}
+		current = append(current, line...)
+	}
+	if started {
+		messages = append(messages, current)
+	}
+	return messages
+}
+
+// parseEmail reads the headers, bodies and attachments of a message
+func (o *OCRContentExtractor) parseEmail(ctx context.Context, data []byte) (*email, error) {
+	msg, err := mail.ReadMessage(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse email: %w", err)
+	}
+	e := &email{
+		subject: decodeHeader(msg.Header.Get("Subject")),
+		from:    decodeHeader(msg.Header.Get("From")),
+		to:      decodeHeader(msg.Header.Get("To")),
+	}
+	e.date, _ = msg.Header.Date()
+	if err := o.readPart(ctx, e, textproto.MIMEHeader(msg.Header), msg.Body); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// readPart adds the text of a MIME part to the email, descending into the
+// parts of multipart parts
+func (o *OCRContentExtractor) readPart(ctx context.Context, e *email, header textproto.MIMEHeader, body io.Reader) error {
+	mediaType, params, err := mime.ParseMediaType(header.Get("Content-Type"))
+	if err != nil {
+		mediaType = "text/plain"
+	}
+	if strings.HasPrefix(mediaType, "multipart/") {
+		return o.readParts(ctx, e, multipart.NewReader(body, params["boundary"]))
+	}
+
+	data, err := io.ReadAll(io.LimitReader(transferDecoder(header, body), MaxInputSize))
+	if err != nil {
+		return fmt.Errorf("failed to read email part: %w", err)
+	}
+	if name := attachmentName(header, params); name != "" {
+		o.readAttachment(ctx, e, name, mediaType, data)
+		return nil
+	}
+	switch mediaType {
+	case "text/plain":
+		e.plain = append(e.plain, string(data))
+	case "text/html":
+		e.html = append(e.html, html.UnescapeString(htmlTag.ReplaceAllString(string(data), " ")))
+	}
+	return nil
+}
+
+// readParts adds the text of the parts of a multipart part to the email
+func (o *OCRContentExtractor) readParts(ctx context.Context, e *email, parts *multipart.Reader) error {
+	for {
+		part, err := parts.NextPart()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read email part: %w", err)
+		}
+		if err := o.readPart(ctx, e, part.Header, part); err != nil {
+			return err
+		}
+	}
+}
+
+// readAttachment adds the text of an attachment to the email, reading it as
+// any other document would be. Attachments that cannot be read are skipped.
+func (o *OCRContentExtractor) readAttachment(ctx context.Context, e *email, name, mediaType string, data []byte) {
+	e.attachments = append(e.attachments, name)
+	if mediaType == "" || mediaType == "application/octet-stream" {
+		mediaType = detectMediaType(data[:min(len(data), sniffLen)], filepath.Ext(name))
+	}
+	input, err := ReaderInput(bytes.NewReader(data), mediaType)
+	if err != nil || input.IsEmail() || !o.supports(input) {
+		return
+	}
+	text, ocrUsed, err := o.readText(ctx, input, data)
+	if err != nil {
+		slog.WarnContext(ctx, "Failed to read email attachment", "attachment", name, "error", err)
+		return
+	}
+	e.attached = append(e.attached, fmt.Sprintf("Attachment %s:\n%s", name, text))
+	e.ocrUsed = e.ocrUsed || ocrUsed
+}
+
+// transferDecoder decodes a part's body by its Content-Transfer-Encoding.
+// Multipart readers decode quoted-printable parts themselves.
+func transferDecoder(header textproto.MIMEHeader, body io.Reader) io.Reader {
+	switch strings.ToLower(header.Get("Content-Transfer-Encoding")) {
+	case "base64":
+		return base64.NewDecoder(base64.StdEncoding, body)
+	case "quoted-printable":
+		return quotedprintable.NewReader(body)
+	default:
+		return body
+	}
+}
+
+// attachmentName returns the file name of an attached part, or "" for bodies
+func attachmentName(header textproto.MIMEHeader, params map[string]string) string {
+	if _, dispositionParams, err := mime.ParseMediaType(header.Get("Content-Disposition")); err == nil && dispositionParams["filename"] != "" {
+		return decodeHeader(dispositionParams["filename"])
+	}
+	return decodeHeader(params["name"])
+}
+
+// decodeHeader decodes the RFC 2047 encoded words of a header, keeping
+// headers it cannot decode as they are
+func decodeHeader(value string) string {
+	decoded, err := new(mime.WordDecoder).DecodeHeader(value)
+	if err != nil {
+		return value
+	}
+	return decoded
+}
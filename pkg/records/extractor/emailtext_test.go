@@ -0,0 +1,107 @@
+package extractor_test
+
+import (
+	"context"
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kazemisoroush/assistant/pkg/records"
+	"github.com/kazemisoroush/assistant/pkg/records/extractor"
+	"github.com/kazemisoroush/assistant/pkg/records/extractor/mocks"
+	"github.com/kazemisoroush/assistant/pkg/tokens"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+// invoiceEmail is a multipart email with text and HTML bodies and a PDF attachment
+var invoiceEmail = strings.Join([]string{
+	"From: =?UTF-8?Q?Caf=C3=A9_Berg?= <billing@example.com>",
+	"To: me@example.com",
+	"Subject: Your invoice",
+	"Date: Mon, 02 Mar 2026 09:30:00 +0000",
+	"MIME-Version: 1.0",
+	`Content-Type: multipart/mixed; boundary="mixed"`,
+	"",
+	"--mixed",
+	`Content-Type: multipart/alternative; boundary="alt"`,
+	"",
+	"--alt",
+	"Content-Type: text/plain; charset=utf-8",
+	"Content-Transfer-Encoding: quoted-printable",
+	"",
+	"Thanks for your visit, the total is 42 EUR.=",
+	"",
+	"--alt",
+	"Content-Type: text/html; charset=utf-8",
+	"",
+	"<p>Thanks for your <b>visit</b></p>",
+	"--alt--",
+	"--mixed",
+	"Content-Type: application/octet-stream",
+	`Content-Disposition: attachment; filename="invoice.pdf"`,
+	"Content-Transfer-Encoding: base64",
+	"",
+	base64.StdEncoding.EncodeToString([]byte("%PDF-1.7 invoice")),
+	"--mixed--",
+	"",
+}, "\r\n")
+
+// writeFile writes the content to a file named name and returns its input
+func writeFile(t *testing.T, name, content string) extractor.Input {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0600))
+	input, err := extractor.FileInput(path)
+	require.NoError(t, err, "FileInput() error should be nil")
+	return input
+}
+
+func TestOCRContentExtractor_Extract_Email(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	pdfs := mocks.NewMockPDFConverter(ctrl)
+	typeExtractor := mocks.NewMockTypeExtractor(ctrl)
+	metadataExtractor := mocks.NewMockMetadataExtractor(ctrl)
+	pdfs.EXPECT().Text(gomock.Any(), []byte("%PDF-1.7 invoice")).Return("Invoice 2026-031, espresso machine service, 42 EUR", nil)
+	typeExtractor.EXPECT().GetType(gomock.Any(), gomock.Any()).Return(records.RecordTypeReceipt, nil)
+	metadataExtractor.EXPECT().GetMetadata(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, nil)
+	e := extractor.NewOCRContentExtractor(mocks.NewMockImageTranscriber(ctrl), pdfs, typeExtractor, metadataExtractor, tokens.NewBudgeter(8000, 1000))
+
+	// Act
+	rec, err := e.Extract(context.Background(), writeFile(t, "invoice.eml", invoiceEmail))
+
+	// Assert
+	require.NoError(t, err, "Extract() error should be nil")
+	assert.Equal(t, "Your invoice", rec.Title, "Extract() should title the record with the subject")
+	assert.Contains(t, rec.Content, "From: Café Berg <billing@example.com>", "Extract() should decode the headers")
+	assert.Contains(t, rec.Content, "the total is 42 EUR.\n", "Extract() should read the text body")
+	assert.NotContains(t, rec.Content, "<b>", "Extract() should prefer the text body over the HTML body")
+	assert.Contains(t, rec.Content, "Attachment invoice.pdf:\nInvoice 2026-031", "Extract() should read the attachments")
+	assert.Equal(t, "2026-03-02T09:30:00Z", rec.Metadata["email_date"], "Extract() should record the date")
+	assert.Equal(t, []string{"invoice.pdf"}, rec.Metadata["email_attachments"], "Extract() should record the attachments")
+}
+
+func TestOCRContentExtractor_Extract_Mbox(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	typeExtractor := mocks.NewMockTypeExtractor(ctrl)
+	metadataExtractor := mocks.NewMockMetadataExtractor(ctrl)
+	typeExtractor.EXPECT().GetType(gomock.Any(), gomock.Any()).Return(records.RecordTypeOther, nil)
+	metadataExtractor.EXPECT().GetMetadata(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, nil)
+	e := extractor.NewOCRContentExtractor(mocks.NewMockImageTranscriber(ctrl), nil, typeExtractor, metadataExtractor, tokens.NewBudgeter(8000, 1000))
+	archive := "From a@example.com Mon Mar  2 09:30:00 2026\nSubject: First\n\nHello\n>From the start\n\n" +
+		"From b@example.com Tue Mar  3 10:00:00 2026\nSubject: <html>\nContent-Type: text/html\n\n<p>Second &amp; last</p>\n"
+
+	// Act
+	rec, err := e.Extract(context.Background(), writeFile(t, "archive.mbox", archive))
+
+	// Assert
+	require.NoError(t, err, "Extract() error should be nil")
+	assert.Equal(t, 2, rec.Metadata["email_messages"], "Extract() should read every message")
+	assert.Contains(t, rec.Content, "Hello\nFrom the start", "Extract() should unescape body lines starting with From")
+	assert.Contains(t, rec.Content, "Second & last", "Extract() should read the text of HTML bodies")
+}
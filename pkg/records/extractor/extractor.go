@@ -7,16 +7,45 @@ import (
 	"github.com/kazemisoroush/assistant/pkg/records"
 )
 
-// ContentExtractor defines an interface for extracting records from raw content.
+// ContentExtractor defines an interface for extracting records from documents.
 //
 //go:generate mockgen -destination=./mocks/mock_extractor.go -mock_names=ContentExtractor=MockContentExtractor -package=mocks . ContentExtractor
 type ContentExtractor interface {
-	// Extract processes raw content and returns a Record
-	Extract(ctx context.Context, rawContent string) (records.Record, error)
+	// Extract processes the document and returns a Record
+	Extract(ctx context.Context, input Input) (records.Record, error)
 }
 
 // TypeExtractor defines an interface for classifying record types from text content.
+//
+//go:generate mockgen -destination=./mocks/mock_typeextractor.go -mock_names=TypeExtractor=MockTypeExtractor -package=mocks . TypeExtractor
 type TypeExtractor interface {
 	// GetType classifies the record type based on raw content
 	GetType(ctx context.Context, textContent string) (records.RecordType, error)
 }
+
+// MetadataExtractor defines an interface for extracting type-specific metadata from text content.
+//
+//go:generate mockgen -destination=./mocks/mock_metadataextractor.go -mock_names=MetadataExtractor=MockMetadataExtractor -package=mocks . MetadataExtractor
+type MetadataExtractor interface {
+	// GetMetadata returns structured fields for the record type, or nil if the type has none
+	GetMetadata(ctx context.Context, recordType records.RecordType, textContent string) (map[string]interface{}, error)
+}
+
+// ImageTranscriber defines an interface for turning an image into its text content.
+//
+//go:generate mockgen -destination=./mocks/mock_imagetranscriber.go -mock_names=ImageTranscriber=MockImageTranscriber -package=mocks . ImageTranscriber
+type ImageTranscriber interface {
+	// Transcribe returns the text found in the image
+	Transcribe(ctx context.Context, image []byte, mediaType string) (string, error)
+}
+
+// PDFConverter defines an interface for reading PDF documents.
+//
+//go:generate mockgen -destination=./mocks/mock_pdfconverter.go -mock_names=PDFConverter=MockPDFConverter -package=mocks . PDFConverter
+type PDFConverter interface {
+	// Text returns the text layer of the PDF, which scanned PDFs lack
+	Text(ctx context.Context, pdf []byte) (string, error)
+
+	// Pages renders the pages of the PDF as PNG images
+	Pages(ctx context.Context, pdf []byte) ([][]byte, error)
+}
@@ -0,0 +1,41 @@
+package extractor
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image/png"
+	"slices"
+)
+
+// ErrHEICUnavailable is returned for HEIC images when the binary was built
+// without libheif. Build with the heif tag to transcribe phone photos.
+var ErrHEICUnavailable = errors.New("HEIC decoding is unavailable, build with the heif tag")
+
+// heicBrands are the major brands of the ISO BMFF files holding HEIF images
+var heicBrands = []string{"heic", "heix", "heim", "heis", "hevc", "hevx", "mif1", "msf1"}
+
+// isHEIC reports whether the media type is a HEIC or HEIF image
+func isHEIC(mediaType string) bool {
+	return mediaType == "image/heic" || mediaType == "image/heif"
+}
+
+// sniffHEIC reports whether the leading bytes are of a HEIF file, whose
+// ftyp box names a HEIF brand. Content sniffing does not recognize them.
+func sniffHEIC(head []byte) bool {
+	return len(head) >= 12 && string(head[4:8]) == "ftyp" && slices.Contains(heicBrands, string(head[8:12]))
+}
+
+// heicToPNG decodes the primary image of a HEIC file and encodes it as PNG,
+// which every transcriber accepts
+func heicToPNG(data []byte) ([]byte, error) {
+	img, err := decodeHEICImpl(data)
+	if err != nil {
+		return nil, err
+	}
+	var out bytes.Buffer
+	if err := png.Encode(&out, img); err != nil {
+		return nil, fmt.Errorf("failed to encode HEIC image as PNG: %w", err)
+	}
+	return out.Bytes(), nil
+}
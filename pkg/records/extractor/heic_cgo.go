@@ -0,0 +1,64 @@
+//go:build heif && cgo
+
+package extractor
+
+/*
+#cgo LDFLAGS: -lheif
+#include <libheif/heif.h>
+*/
+import "C"
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"unsafe"
+)
+
+// decodeHEICImpl decodes the primary image of a HEIC file with libheif. The
+// data is copied into the libheif context, so no Go memory is kept by C.
+func decodeHEICImpl(data []byte) (image.Image, error) {
+	if len(data) == 0 {
+		return nil, errors.New("HEIC image is empty")
+	}
+	ctx := C.heif_context_alloc()
+	defer C.heif_context_free(ctx)
+
+	if err := heifError(C.heif_context_read_from_memory(ctx, unsafe.Pointer(&data[0]), C.size_t(len(data)), nil)); err != nil {
+		return nil, fmt.Errorf("failed to read HEIC image: %w", err)
+	}
+	var handle *C.struct_heif_image_handle
+	if err := heifError(C.heif_context_get_primary_image_handle(ctx, &handle)); err != nil {
+		return nil, fmt.Errorf("failed to find the primary HEIC image: %w", err)
+	}
+	defer C.heif_image_handle_release(handle)
+
+	var img *C.struct_heif_image
+	if err := heifError(C.heif_decode_image(handle, &img, C.heif_colorspace_RGB, C.heif_chroma_interleaved_RGBA, nil)); err != nil {
+		return nil, fmt.Errorf("failed to decode HEIC image: %w", err)
+	}
+	defer C.heif_image_release(img)
+
+	width := int(C.heif_image_get_width(img, C.heif_channel_interleaved))
+	height := int(C.heif_image_get_height(img, C.heif_channel_interleaved))
+	var stride C.int
+	plane := C.heif_image_get_plane_readonly(img, C.heif_channel_interleaved, &stride)
+	if plane == nil || width <= 0 || height <= 0 {
+		return nil, errors.New("HEIC image has no RGBA pixels")
+	}
+
+	out := image.NewNRGBA(image.Rect(0, 0, width, height))
+	pixels := unsafe.Slice((*byte)(unsafe.Pointer(plane)), int(stride)*height)
+	for y := 0; y < height; y++ {
+		copy(out.Pix[y*out.Stride:y*out.Stride+width*4], pixels[y*int(stride):])
+	}
+	return out, nil
+}
+
+// heifError converts a libheif error to a Go error, nil when it reports success
+func heifError(err C.struct_heif_error) error {
+	if err.code == C.heif_error_Ok {
+		return nil
+	}
+	return errors.New(C.GoString(err.message))
+}
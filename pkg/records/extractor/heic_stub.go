@@ -0,0 +1,10 @@
+//go:build !heif || !cgo
+
+package extractor
+
+import "image"
+
+// decodeHEICImpl reports that the binary was built without libheif
+func decodeHEICImpl(_ []byte) (image.Image, error) {
+	return nil, ErrHEICUnavailable
+}
@@ -0,0 +1,30 @@
+//go:build !heif || !cgo
+
+package extractor_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/kazemisoroush/assistant/pkg/records/extractor"
+	"github.com/kazemisoroush/assistant/pkg/records/extractor/mocks"
+	"github.com/kazemisoroush/assistant/pkg/tokens"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestOCRContentExtractor_Extract_HEICUnavailable(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	e := extractor.NewOCRContentExtractor(mocks.NewMockImageTranscriber(ctrl), nil, mocks.NewMockTypeExtractor(ctrl), mocks.NewMockMetadataExtractor(ctrl), tokens.NewBudgeter(8000, 1000))
+	input, err := extractor.ReaderInput(strings.NewReader("\x00\x00\x00\x18ftypheic\x00\x00\x00\x00mif1heic"), "")
+	require.NoError(t, err, "ReaderInput() error should be nil")
+
+	// Act
+	_, err = e.Extract(context.Background(), input)
+
+	// Assert
+	assert.ErrorIs(t, err, extractor.ErrHEICUnavailable, "Extract() should not transcribe HEIC images without libheif")
+}
@@ -0,0 +1,176 @@
+package extractor
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// MaxInputSize is the largest document a record is extracted from, in bytes
+const MaxInputSize = 64 << 20
+
+// sniffLen is how many leading bytes are used to detect the media type
+const sniffLen = 512
+
+// Input represents a document to extract a record from. The content is opened
+// on demand, so it is never held as a whole unless an extractor needs it.
+type Input struct {
+	Path      string // File the document is read from, empty for in-memory documents
+	MediaType string // Detected MIME type without parameters, e.g. image/png or text/plain
+	Size      int64
+	open      func() (io.ReadCloser, error)
+}
+
+// FileInput describes the file at path, detecting its media type from its first bytes
+func FileInput(path string) (Input, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return Input{}, fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer func() { _ = f.Close() }()
+
+	info, err := f.Stat()
+	if err != nil {
+		return Input{}, fmt.Errorf("failed to stat %s: %w", path, err)
+	}
+	head := make([]byte, sniffLen)
+	n, err := io.ReadFull(f, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return Input{}, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	return Input{
+		Path:      path,
+		MediaType: detectMediaType(head[:n], filepath.Ext(path)),
+		Size:      info.Size(),
+		open:      func() (io.ReadCloser, error) { return os.Open(path) },
+	}, nil
+}
+
+// ReaderInput reads a document from r, detecting its media type when mediaType is empty
+func ReaderInput(r io.Reader, mediaType string) (Input, error) {
+	data, err := io.ReadAll(io.LimitReader(r, MaxInputSize+1))
+	if err != nil {
+		return Input{}, fmt.Errorf("failed to read document: %w", err)
+	}
+	if mediaType == "" {
+		mediaType = detectMediaType(data[:min(len(data), sniffLen)], "")
+	}
+	return Input{
+		MediaType: baseMediaType(mediaType),
+		Size:      int64(len(data)),
+		open:      func() (io.ReadCloser, error) { return io.NopCloser(bytes.NewReader(data)), nil },
+	}, nil
+}
+
+// Name identifies the input in errors and logs
+func (i Input) Name() string {
+	if i.Path != "" {
+		return i.Path
+	}
+	return "document"
+}
+
+// IsImage reports whether the document is an image
+func (i Input) IsImage() bool {
+	return strings.HasPrefix(i.MediaType, "image/")
+}
+
+// IsOffice reports whether the document is a DOCX or ODT document
+func (i Input) IsOffice() bool {
+	_, ok := officeFormats[i.MediaType]
+	return ok
+}
+
+// IsEmail reports whether the document is an email or an mbox archive of emails
+func (i Input) IsEmail() bool {
+	return i.MediaType == MediaTypeEmail || i.MediaType == MediaTypeMbox
+}
+
+// IsPDF reports whether the document is a PDF
+func (i Input) IsPDF() bool {
+	return i.MediaType == "application/pdf"
+}
+
+// IsText reports whether the document is plain or structured text
+func (i Input) IsText() bool {
+	return strings.HasPrefix(i.MediaType, "text/") || i.MediaType == "application/json" || i.MediaType == "application/xml"
+}
+
+// Open returns a reader of the document; callers close it
+func (i Input) Open() (io.ReadCloser, error) {
+	if i.open == nil {
+		return nil, errors.New("input has no content")
+	}
+	return i.open()
+}
+
+// ReadAll returns the content of the document, failing for documents larger than MaxInputSize
+func (i Input) ReadAll() ([]byte, error) {
+	if i.Size > MaxInputSize {
+		return nil, fmt.Errorf("%s is larger than %d bytes", i.Name(), MaxInputSize)
+	}
+	r, err := i.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = r.Close() }()
+
+	data, err := io.ReadAll(io.LimitReader(r, MaxInputSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", i.Name(), err)
+	}
+	if len(data) > MaxInputSize {
+		return nil, fmt.Errorf("%s is larger than %d bytes", i.Name(), MaxInputSize)
+	}
+	return data, nil
+}
+
+// containedTypes maps file extensions to the media types of documents that
+// content sniffing reports by their container: office documents are zip
+// archives and emails are plain text
+var containedTypes = map[string]struct{ sniffed, mediaType string }{
+	".docx": {sniffed: "application/zip", mediaType: MediaTypeDOCX},
+	".odt":  {sniffed: "application/zip", mediaType: MediaTypeODT},
+	".eml":  {sniffed: "text/plain", mediaType: MediaTypeEmail},
+	".mbox": {sniffed: "text/plain", mediaType: MediaTypeMbox},
+}
+
+// detectMediaType sniffs the media type from the leading bytes, falling back
+// to the file extension for types content sniffing does not recognize, e.g.
+// TIFF, or reports by their container, e.g. DOCX. HEIC images are sniffed by
+// their ftyp box.
+func detectMediaType(head []byte, ext string) string {
+	if sniffHEIC(head) {
+		return "image/heic"
+	}
+	mediaType := baseMediaType(http.DetectContentType(head))
+	if contained, ok := containedTypes[strings.ToLower(ext)]; ok && mediaType == contained.sniffed {
+		return contained.mediaType
+	}
+	if mediaType != "application/octet-stream" || ext == "" {
+		return mediaType
+	}
+	if looksLikeImageExt(ext) {
+		return extToMime(ext)
+	}
+	if byExt := mime.TypeByExtension(ext); byExt != "" {
+		return baseMediaType(byExt)
+	}
+	return mediaType
+}
+
+// baseMediaType drops the parameters of a media type, e.g. the charset
+func baseMediaType(mediaType string) string {
+	base, _, err := mime.ParseMediaType(mediaType)
+	if err != nil {
+		return strings.ToLower(strings.TrimSpace(mediaType))
+	}
+	return base
+}
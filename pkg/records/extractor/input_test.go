@@ -0,0 +1,74 @@
+package extractor_test
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kazemisoroush/assistant/pkg/records/extractor"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileInput(t *testing.T) {
+	tests := []struct {
+		name      string
+		file      string
+		content   []byte
+		mediaType string
+		isImage   bool
+		isText    bool
+	}{
+		{name: "text", file: "note.md", content: []byte("Invoice total 12 EUR"), mediaType: "text/plain", isText: true},
+		{name: "png", file: "scan.bin", content: []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR"), mediaType: "image/png", isImage: true},
+		{name: "tiff by extension", file: "scan.tif", content: []byte("II*\x00\x08\x00\x00\x00\x00\x01"), mediaType: "image/tiff", isImage: true},
+		{name: "pdf", file: "policy.pdf", content: []byte("%PDF-1.7\n%\xe2\xe3\xcf\xd3"), mediaType: "application/pdf"},
+		{name: "docx by extension", file: "contract.docx", content: []byte("PK\x03\x04\x14\x00\x06\x00"), mediaType: extractor.MediaTypeDOCX},
+		{name: "heic", file: "IMG_0001", content: []byte("\x00\x00\x00\x18ftypheic\x00\x00\x00\x00mif1heic"), mediaType: "image/heic", isImage: true},
+		{name: "heif by extension", file: "photo.heif", content: []byte("\x00\x01\x02\x03"), mediaType: "image/heif", isImage: true},
+		{name: "email by extension", file: "invoice.eml", content: []byte("From: shop@example.com\r\nSubject: Invoice\r\n\r\nTotal 12 EUR"), mediaType: extractor.MediaTypeEmail},
+		{name: "zip", file: "photos.zip", content: []byte("PK\x03\x04\x14\x00\x06\x00"), mediaType: "application/zip"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			path := filepath.Join(t.TempDir(), tt.file)
+			require.NoError(t, os.WriteFile(path, tt.content, 0600))
+
+			// Act
+			input, err := extractor.FileInput(path)
+
+			// Assert
+			require.NoError(t, err, "FileInput() error should be nil")
+			assert.Equal(t, tt.mediaType, input.MediaType, "FileInput() should detect the media type")
+			assert.Equal(t, tt.isImage, input.IsImage(), "IsImage() should match the media type")
+			assert.Equal(t, tt.isText, input.IsText(), "IsText() should match the media type")
+			assert.Equal(t, int64(len(tt.content)), input.Size, "FileInput() should report the size")
+			data, err := input.ReadAll()
+			require.NoError(t, err, "ReadAll() error should be nil")
+			assert.Equal(t, tt.content, data, "ReadAll() should return the file content")
+		})
+	}
+}
+
+func TestReaderInput(t *testing.T) {
+	// Arrange
+	reader := strings.NewReader("Lab results: all normal")
+
+	// Act
+	input, err := extractor.ReaderInput(reader, "")
+
+	// Assert
+	require.NoError(t, err, "ReaderInput() error should be nil")
+	assert.Equal(t, "text/plain", input.MediaType, "ReaderInput() should detect the media type")
+	for range 2 {
+		r, err := input.Open()
+		require.NoError(t, err, "Open() error should be nil")
+		data, err := io.ReadAll(r)
+		require.NoError(t, err, "reading the input should not fail")
+		assert.Equal(t, "Lab results: all normal", string(data), "Open() should read the content every time")
+	}
+}
@@ -0,0 +1,43 @@
+package extractor
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/kazemisoroush/assistant/pkg/ai"
+	"github.com/kazemisoroush/assistant/pkg/prompts"
+)
+
+// LLMImageTranscriber transcribes images with a vision-capable language model
+type LLMImageTranscriber struct {
+	provider ai.Provider
+	prompts  prompts.Renderer
+}
+
+// NewLLMImageTranscriber creates a new LLMImageTranscriber instance
+func NewLLMImageTranscriber(provider ai.Provider, prompts prompts.Renderer) ImageTranscriber {
+	return &LLMImageTranscriber{
+		provider: provider,
+		prompts:  prompts,
+	}
+}
+
+// Transcribe returns the text found in the image
+func (l *LLMImageTranscriber) Transcribe(ctx context.Context, image []byte, mediaType string) (string, error) {
+	prompt, err := l.prompts.Render(prompts.Transcription, map[string]any{})
+	if err != nil {
+		return "", err
+	}
+
+	response, err := l.provider.Generate(ctx, ai.Request{
+		Prompt: prompt,
+		Images: []ai.Image{{MediaType: mediaType, Data: image}},
+		Task:   ai.TaskTranscription,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to transcribe image with %s: %w", l.provider.Name(), err)
+	}
+
+	return strings.TrimSpace(response.Text), nil
+}
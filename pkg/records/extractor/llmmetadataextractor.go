@@ -0,0 +1,232 @@
+package extractor
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"strings"
+
+	"github.com/kazemisoroush/assistant/pkg/ai"
+	"github.com/kazemisoroush/assistant/pkg/prompts"
+	"github.com/kazemisoroush/assistant/pkg/records"
+)
+
+// receiptFields describes the ReceiptMetadata fields to the model
+const receiptFields = "merchant (string), date (YYYY-MM-DD), total (number), currency (ISO 4217 code), category (lowercase spending category, e.g. groceries, dining, transport, utilities, health), product (main item purchased), warranty_months (number, warranty duration stated on the receipt or invoice, 0 if none), country (ISO 3166-1 alpha-2 code of where the purchase was made, empty if unknown)"
+
+// documentFields describes the DocumentMetadata fields to the model
+const documentFields = "document_type (string, e.g. passport, visa, insurance policy, registration, laptop warranty), expires_at (expiry or renewal date as YYYY-MM-DD, empty if none)"
+
+// insuranceFields describes the InsuranceMetadata fields to the model
+const insuranceFields = documentFields + ", provider (doctor, clinic or pharmacy a claim or reimbursement is for, empty for policies), service_date (date of the treatment claimed as YYYY-MM-DD, empty for policies), claimed (number, amount claimed, 0 if none), reimbursed (number, amount reimbursed or paid out, 0 if none), currency (ISO 4217 code of the amounts)"
+
+// visaFields describes the VisaMetadata fields to the model
+const visaFields = documentFields + ", country (ISO 3166-1 alpha-2 code of the country the visa or permit is for)"
+
+// travelFields describes the TravelMetadata fields to the model
+const travelFields = "destination (city or place travelled to), country (ISO 3166-1 alpha-2 code of the destination), start_date (departure or check-in date as YYYY-MM-DD), end_date (arrival or check-out date as YYYY-MM-DD, empty if the same day), total (number, price of the booking, 0 if not stated), currency (ISO 4217 code of the price)"
+
+// carFields describes the CarMetadata fields to the model
+const carFields = documentFields + ", vin (vehicle identification number, empty if not stated), plate (license plate, empty if not stated), vehicle (make and model), date (date of the service or repair as YYYY-MM-DD, empty if none), odometer (number, odometer reading at the service, 0 if not stated), service_type (one of oil_change, inspection, tires, brakes, repair, other; empty if the document is not a service or repair), cost (number, amount paid, 0 if none), currency (ISO 4217 code of the cost)"
+
+// healthFields describes the HealthMetadata fields to the model
+const healthFields = "patient (full name of the person the record is about), provider (doctor, clinic or laboratory), date (YYYY-MM-DD), summary (one short sentence: reason for the visit or test performed), follow_up (date of a recommended follow-up visit or test as YYYY-MM-DD, empty if none)"
+
+// LLMMetadataExtractor uses a language model to extract schema-validated
+// metadata. Fields of the schema attached to a record type are requested
+// alongside the built-in fields and the model is re-prompted until they validate.
+type LLMMetadataExtractor struct {
+	provider ai.Provider
+	prompts  prompts.Renderer
+}
+
+// NewLLMMetadataExtractor creates a new LLMMetadataExtractor instance
+func NewLLMMetadataExtractor(provider ai.Provider, prompts prompts.Renderer) MetadataExtractor {
+	return &LLMMetadataExtractor{
+		provider: provider,
+		prompts:  prompts,
+	}
+}
+
+// GetMetadata returns structured fields for the record type, or nil if the type has none
+func (l *LLMMetadataExtractor) GetMetadata(ctx context.Context, recordType records.RecordType, textContent string) (map[string]interface{}, error) {
+	fields, typed := builtinMetadata(recordType)
+	schema := records.SchemaFields(recordType)
+	if typed == nil && len(schema) == 0 {
+		return nil, nil
+	}
+	out := &schemaMetadata{typed: typed, fields: schema}
+	return l.extract(ctx, recordType, describeFields(fields, schema), textContent, out)
+}
+
+// builtinMetadata returns the field descriptions and metadata struct of a
+// built-in record type, or a nil struct if the type has none
+func builtinMetadata(recordType records.RecordType) (string, any) {
+	switch recordType {
+	case records.RecordTypeReceipt:
+		return receiptFields, &records.ReceiptMetadata{}
+	case records.RecordTypeInsurance:
+		return insuranceFields, &records.InsuranceMetadata{}
+	case records.RecordTypeVisa:
+		return visaFields, &records.VisaMetadata{}
+	case records.RecordTypeTravel:
+		return travelFields, &records.TravelMetadata{}
+	case records.RecordTypeCar:
+		return carFields, &records.CarMetadata{}
+	case records.RecordTypeID, records.RecordTypeWarranty:
+		return documentFields, &records.DocumentMetadata{}
+	case records.RecordTypeHealthVisit, records.RecordTypeHealthTest, records.RecordTypeHealthLab:
+		return healthFields, &records.HealthMetadata{}
+	default:
+		return "", nil
+	}
+}
+
+// extract asks the model for the described fields and decodes them into out
+func (l *LLMMetadataExtractor) extract(ctx context.Context, recordType records.RecordType, fields, textContent string, out any) (map[string]interface{}, error) {
+	prompt, err := l.prompts.Render(prompts.MetadataExtraction, map[string]any{
+		"Type":   recordType,
+		"Fields": fields,
+		"Text":   textContent,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := l.provider.GenerateJSON(ctx, ai.Request{Prompt: prompt, Task: ai.TaskMetadataExtraction, RecordType: string(recordType)}, out); err != nil {
+		return nil, fmt.Errorf("failed to extract %s metadata: %w", recordType, err)
+	}
+
+	return toMap(out)
+}
+
+// describeFields appends the schema fields not already described by the
+// built-in field descriptions
+func describeFields(builtin string, schema []records.FieldSchema) string {
+	var fields []string
+	if builtin != "" {
+		fields = append(fields, builtin)
+	}
+	for _, field := range schema {
+		if strings.HasPrefix(builtin, field.Name+" (") || strings.Contains(builtin, ", "+field.Name+" (") {
+			continue
+		}
+		fields = append(fields, describeField(field))
+	}
+	return strings.Join(fields, ", ")
+}
+
+// describeField describes a schema field to the model
+func describeField(field records.FieldSchema) string {
+	kind := string(field.Type)
+	if field.Type == records.FieldTypeDate {
+		kind = "YYYY-MM-DD"
+	}
+	if len(field.Enum) > 0 {
+		kind += ", one of " + strings.Join(field.Enum, ", ")
+	}
+	if field.Required {
+		kind += ", required"
+	}
+	if field.Description != "" {
+		kind += ", " + field.Description
+	}
+	return fmt.Sprintf("%s (%s)", field.Name, kind)
+}
+
+// schemaMetadata holds extracted metadata validated against the schema of its
+// type. Built-in types decode into their metadata struct; schema fields the
+// struct does not declare are kept alongside it.
+type schemaMetadata struct {
+	typed  any // Metadata struct of a built-in type, nil for other types
+	fields []records.FieldSchema
+	values map[string]interface{} // Schema fields not declared by the struct
+}
+
+// UnmarshalJSON decodes the metadata struct and keeps the remaining schema fields
+func (m *schemaMetadata) UnmarshalJSON(data []byte) error {
+	if m.typed != nil {
+		if err := json.Unmarshal(data, m.typed); err != nil {
+			return err
+		}
+	}
+	var values map[string]interface{}
+	if err := json.Unmarshal(data, &values); err != nil {
+		return err
+	}
+	declared, err := m.declared()
+	if err != nil {
+		return err
+	}
+	m.values = map[string]interface{}{}
+	for _, field := range m.fields {
+		if _, ok := declared[field.Name]; ok {
+			continue
+		}
+		if value, ok := values[field.Name]; ok && value != nil {
+			m.values[field.Name] = value
+		}
+	}
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler
+func (m schemaMetadata) MarshalJSON() ([]byte, error) {
+	merged, err := m.merged()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(merged)
+}
+
+// Validate checks the metadata struct and then the schema
+func (m schemaMetadata) Validate() error {
+	if v, ok := m.typed.(ai.Validator); ok {
+		if err := v.Validate(); err != nil {
+			return err
+		}
+	}
+	merged, err := m.merged()
+	if err != nil {
+		return err
+	}
+	for _, field := range m.fields {
+		if err := field.Check(merged[field.Name]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// declared returns the fields of the metadata struct
+func (m schemaMetadata) declared() (map[string]interface{}, error) {
+	if m.typed == nil {
+		return map[string]interface{}{}, nil
+	}
+	return toMap(m.typed)
+}
+
+// merged returns the fields of the metadata struct and the remaining schema fields
+func (m schemaMetadata) merged() (map[string]interface{}, error) {
+	merged, err := m.declared()
+	if err != nil {
+		return nil, err
+	}
+	maps.Copy(merged, m.values)
+	return merged, nil
+}
+
+// toMap converts a metadata struct into the generic map stored on records
+func toMap(v any) (map[string]interface{}, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal metadata: %w", err)
+	}
+
+	var m map[string]interface{}
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal metadata: %w", err)
+	}
+	return m, nil
+}
@@ -0,0 +1,61 @@
+package extractor
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/kazemisoroush/assistant/pkg/ai"
+	"github.com/kazemisoroush/assistant/pkg/prompts"
+	"github.com/kazemisoroush/assistant/pkg/records"
+)
+
+// LLMTypeExtractor uses a language model to classify record types.
+type LLMTypeExtractor struct {
+	provider ai.Provider
+	prompts  prompts.Renderer
+}
+
+// NewLLMTypeExtractor creates a new LLMTypeExtractor instance
+func NewLLMTypeExtractor(provider ai.Provider, prompts prompts.Renderer) TypeExtractor {
+	return &LLMTypeExtractor{
+		provider: provider,
+		prompts:  prompts,
+	}
+}
+
+// GetType classifies the record type based on raw content
+func (l *LLMTypeExtractor) GetType(ctx context.Context, textContent string) (records.RecordType, error) {
+	prompt, err := l.prompts.Render(prompts.Classification, map[string]any{
+		"Types": typeList(),
+		"Text":  textContent,
+	})
+	if err != nil {
+		return records.RecordTypeOther, err
+	}
+
+	response, err := l.provider.Generate(ctx, ai.Request{Prompt: prompt, Task: ai.TaskClassification})
+	if err != nil {
+		return records.RecordTypeOther, fmt.Errorf("failed to classify record type with %s: %w", l.provider.Name(), err)
+	}
+
+	recordType := records.RecordType(strings.TrimSpace(strings.ToLower(response.Text)))
+	if !recordType.IsValid() {
+		return records.RecordTypeOther, nil
+	}
+
+	return recordType, nil
+}
+
+// typeList names the record types for the classification prompt, followed by
+// the descriptions and hints of user-defined types
+func typeList() string {
+	list := strings.Join(records.AllRecordTypesAsStrings(), ", ")
+	for _, def := range records.CustomTypes() {
+		list += fmt.Sprintf("; %s means %s", def.Name, def.Description)
+		if len(def.Hints) > 0 {
+			list += fmt.Sprintf(" (e.g. %s)", strings.Join(def.Hints, ", "))
+		}
+	}
+	return list
+}
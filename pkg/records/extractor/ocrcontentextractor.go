@@ -1,240 +1,277 @@
 package extractor
 
 import (
+	"bytes"
 	"context"
-	"encoding/base64"
 	"errors"
 	"fmt"
-	"os"
-	"path/filepath"
+	"maps"
 	"strings"
+	"sync/atomic"
 	"time"
+	"unicode"
 
+	"github.com/kazemisoroush/assistant/pkg/ai"
+	"github.com/kazemisoroush/assistant/pkg/breaker"
 	"github.com/kazemisoroush/assistant/pkg/records"
-	"github.com/otiai10/gosseract/v2"
+	"github.com/kazemisoroush/assistant/pkg/records/pipeline"
+	"github.com/kazemisoroush/assistant/pkg/tokens"
 )
 
-// OCRContentExtractor extracts records from images using OCR
+// minPageText is the number of letters and digits a PDF page averages when
+// its text layer is read instead of transcribing it; scans carry little or no text
+const minPageText = 20
+
+// recordSeq keeps the IDs of records extracted concurrently in the same instant apart
+var recordSeq atomic.Uint64
+
+// OCRContentExtractor extracts records from images using OCR. PDFs are read
+// from their text layer, or transcribed page by page when they have none.
+// The text of DOCX and ODT documents is read from their XML, and emails are
+// read with the text of their attachments.
 type OCRContentExtractor struct {
-	typeExtractor TypeExtractor
+	transcriber       ImageTranscriber
+	pdfs              PDFConverter // nil leaves PDFs unsupported
+	typeExtractor     TypeExtractor
+	metadataExtractor MetadataExtractor
+	budgeter          *tokens.Budgeter
 }
 
 // NewOCRContentExtractor creates a new OCRExtractor instance
-func NewOCRContentExtractor(typeExtractor TypeExtractor) ContentExtractor {
+func NewOCRContentExtractor(transcriber ImageTranscriber, pdfs PDFConverter, typeExtractor TypeExtractor, metadataExtractor MetadataExtractor, budgeter *tokens.Budgeter) ContentExtractor {
 	return &OCRContentExtractor{
-		typeExtractor: typeExtractor,
+		transcriber:       transcriber,
+		pdfs:              pdfs,
+		typeExtractor:     typeExtractor,
+		metadataExtractor: metadataExtractor,
+		budgeter:          budgeter,
 	}
 }
 
-// Extract processes raw content (image or text) and returns a Record
-func (o *OCRContentExtractor) Extract(ctx context.Context, rawContent string) (records.Record, error) {
+// Extract transcribes images and reads text documents, then classifies the text into a Record
+func (o *OCRContentExtractor) Extract(ctx context.Context, input Input) (records.Record, error) {
 	now := time.Now()
 
-	// 1) Try to OCR if rawContent looks like an image input; otherwise treat it as already-text.
-	text, meta, err := o.toText(rawContent)
+	// 1) OCR images; text documents are used as they are
+	text, meta, err := o.toText(ctx, input)
 	if err != nil {
 		return records.Record{}, fmt.Errorf("OCR extraction failed: %w", err)
 	}
 
-	// 2) Classify based on extracted text
-	recordType, err := o.typeExtractor.GetType(ctx, text)
+	// 2) Fit the text into the model's context window; the record keeps the full text
+	promptText, truncated := o.budgeter.Fit(text)
+	if truncated {
+		meta["truncated"] = true
+		meta["content_tokens"] = o.budgeter.Count(text)
+	}
+
+	// 3) Classify and extract type-specific metadata
+	recordType, typeMeta, err := o.classify(ctx, promptText)
 	if err != nil {
-		return records.Record{}, fmt.Errorf("failed to classify record type: %w", err)
+		return records.Record{}, err
 	}
+	maps.Copy(meta, typeMeta)
 
+	subject, _ := meta["email_subject"].(string)
 	rec := records.Record{
-		ID:        fmt.Sprintf("ocr-%d", now.UnixNano()),
+		ID:        fmt.Sprintf("ocr-%d-%d", now.UnixNano(), recordSeq.Add(1)),
 		Type:      recordType,
+		Title:     subject,
 		Content:   text,
 		CreatedAt: now,
 		UpdatedAt: now,
 		Metadata:  meta,
 		Tags:      []string{"TBA"},
+		ExpiresAt: expiresAt(meta),
 	}
 	return rec, nil
 }
 
-// toText tries to OCR if rawContent is image-ish; otherwise returns rawContent as text.
-// Metadata returned is useful for debugging (source/type, OCR used, etc.).
-func (o *OCRContentExtractor) toText(rawContent string) (string, map[string]interface{}, error) {
-	meta := map[string]interface{}{
-		"source": "ocr",
+// expiresAt lifts the extracted expiry date out of the metadata
+func expiresAt(meta map[string]interface{}) *time.Time {
+	value, _ := meta[records.MetaExpiresAt].(string)
+	expiry, err := time.Parse(time.DateOnly, value)
+	if err != nil {
+		return nil
 	}
+	return &expiry
+}
 
-	s := strings.TrimSpace(rawContent)
-	if s == "" {
-		return "", meta, errors.New("rawContent is empty")
+// classify determines the record type and its metadata. While the model is
+// unavailable, classification is deferred: the record is stored as "other"
+// and flagged for review instead of failing the scrape. Records whose type may
+// not be sent to the model are flagged for review without metadata.
+func (o *OCRContentExtractor) classify(ctx context.Context, text string) (records.RecordType, map[string]interface{}, error) {
+	done := pipeline.Track(ctx, pipeline.StageClassify)
+	recordType, err := o.typeExtractor.GetType(ctx, text)
+	done(err)
+	if errors.Is(err, breaker.ErrOpen) {
+		return records.RecordTypeOther, map[string]interface{}{
+			records.MetaNeedsReview:  true,
+			records.MetaReviewReason: "classification deferred: " + err.Error(),
+		}, nil
 	}
-
-	// Case A) data URL: data:image/png;base64,xxxx
-	if looksLikeDataURL(s) {
-		meta["input_kind"] = "data_url"
-		mime, b64 := splitDataURL(s)
-		meta["mime"] = mime
-
-		imgBytes, err := base64.StdEncoding.DecodeString(b64)
-		if err != nil {
-			return "", meta, fmt.Errorf("failed to decode data URL base64: %w", err)
-		}
-		text, err := o.ocrBytesToText(imgBytes, mimeToExt(mime))
-		if err != nil {
-			return "", meta, err
-		}
-		meta["ocr_used"] = true
-		return text, meta, nil
+	if err != nil {
+		return records.RecordTypeOther, nil, fmt.Errorf("failed to classify record type: %w", err)
 	}
 
-	// Case B) looks like a file path to an image
-	if looksLikeImagePath(s) {
-		meta["input_kind"] = "file_path"
-		text, err := o.ocrFileToText(s)
-		if err != nil {
-			return "", meta, err
-		}
-		meta["ocr_used"] = true
-		return text, meta, nil
+	done = pipeline.Track(ctx, pipeline.StageMetadata)
+	typeMeta, err := o.metadataExtractor.GetMetadata(ctx, recordType, text)
+	done(err)
+	if errors.Is(err, ai.ErrRecordTypeNotAllowed) {
+		// Guardrails keep this type off the configured model; a local model or a person has to fill it in
+		return recordType, map[string]interface{}{
+			records.MetaNeedsReview:  true,
+			records.MetaReviewReason: "metadata not extracted: " + err.Error(),
+		}, nil
 	}
+	if err != nil {
+		return records.RecordTypeOther, nil, fmt.Errorf("failed to extract metadata: %w", err)
+	}
+	return recordType, typeMeta, nil
+}
 
-	// Case C) raw base64 (no prefix) — common if you store images in DB
-	// We only attempt this if it's plausibly base64 and fairly large.
-	if looksLikeBase64ImageBlob(s) {
-		meta["input_kind"] = "base64_blob"
-
-		imgBytes, err := base64.StdEncoding.DecodeString(stripBase64Whitespace(s))
-		if err != nil {
-			// If decode fails, treat as text (some OCR output can look base64-ish)
-			meta["ocr_used"] = false
-			return rawContent, meta, nil
-		}
-
-		// We don’t know the type; assume png by default (you can sniff magic bytes if you want).
-		// Better: sniff header and choose ext. We'll do a tiny sniff.
-		ext := sniffImageExt(imgBytes)
-		text, err := o.ocrBytesToText(imgBytes, ext)
-		if err != nil {
-			return "", meta, err
-		}
-		meta["ocr_used"] = true
-		meta["sniffed_ext"] = ext
-		return text, meta, nil
+// toText transcribes an image or reads a text, office, PDF or email document. Metadata
+// returned records the detected media type and whether OCR was used.
+func (o *OCRContentExtractor) toText(ctx context.Context, input Input) (string, map[string]interface{}, error) {
+	meta := map[string]interface{}{
+		"source": "ocr",
+		"mime":   input.MediaType,
+	}
+	if !o.supports(input) {
+		return "", meta, fmt.Errorf("unsupported media type %s", input.MediaType)
 	}
 
-	// Case D) already text
-	meta["input_kind"] = "text"
-	meta["ocr_used"] = false
-	return rawContent, meta, nil
-}
+	done := pipeline.Track(ctx, pipeline.StageRead)
+	data, err := input.ReadAll()
+	done(err)
+	if err != nil {
+		return "", meta, err
+	}
+	if len(bytes.TrimSpace(data)) == 0 {
+		return "", meta, errors.New("content is empty")
+	}
 
-func looksLikeDataURL(s string) bool {
-	return strings.HasPrefix(s, "data:image/") && strings.Contains(s, ";base64,")
+	if input.IsEmail() {
+		text, err := o.readEmail(ctx, input, data, meta)
+		return text, meta, err
+	}
+	text, ocrUsed, err := o.readText(ctx, input, data)
+	if err != nil {
+		return "", meta, err
+	}
+	meta["ocr_used"] = ocrUsed
+	return text, meta, nil
 }
 
-func splitDataURL(s string) (mime string, b64 string) {
-	// format: data:image/png;base64,XXXX
-	// mime part between "data:" and ";base64,"
-	prefix := "data:"
-	i := strings.Index(s, ";base64,")
-	mime = s[len(prefix):i]
-	b64 = s[i+len(";base64,"):]
-	return mime, b64
+// supports reports whether text can be read from the input
+func (o *OCRContentExtractor) supports(input Input) bool {
+	return input.IsImage() || input.IsText() || input.IsOffice() || input.IsEmail() || (input.IsPDF() && o.pdfs != nil)
 }
 
-func looksLikeImagePath(s string) bool {
-	ext := strings.ToLower(filepath.Ext(s))
-	if ext == ".png" || ext == ".jpg" || ext == ".jpeg" || ext == ".webp" || ext == ".tif" || ext == ".tiff" {
-		_, err := os.Stat(s)
-		return err == nil
+// readText returns the text of a document and whether OCR was used
+func (o *OCRContentExtractor) readText(ctx context.Context, input Input, data []byte) (string, bool, error) {
+	switch {
+	case input.IsText():
+		return string(data), false, nil
+	case input.IsOffice():
+		text, err := officeText(data, input.MediaType)
+		return text, false, err
+	case input.IsPDF():
+		return o.readPDF(ctx, data)
 	}
-	return false
+
+	done := pipeline.Track(ctx, pipeline.StageOCR)
+	text, err := o.transcribe(ctx, data, input.MediaType)
+	done(err)
+	return text, true, err
 }
 
-func looksLikeBase64ImageBlob(s string) bool {
-	// Heuristic: base64 strings are usually long, only base64 chars, and length%4==0 often.
-	// We keep this conservative so we don't mis-detect random text.
-	ss := stripBase64Whitespace(s)
-	if len(ss) < 500 { // images are usually larger than this
-		return false
-	}
-	if len(ss)%4 != 0 {
-		return false
-	}
-	for _, r := range ss {
-		if (r < 'a' || r > 'z') &&
-			(r < 'A' || r > 'Z') &&
-			(r < '0' || r > '9') &&
-			r != '+' && r != '/' && r != '=' {
-			return false
+// transcribe returns the text of an image. HEIC images, which phones take
+// photos in, are converted to PNG first.
+func (o *OCRContentExtractor) transcribe(ctx context.Context, image []byte, mediaType string) (string, error) {
+	if isHEIC(mediaType) {
+		converted, err := heicToPNG(image)
+		if err != nil {
+			return "", err
 		}
+		image, mediaType = converted, "image/png"
 	}
-	return true
+	return o.transcriber.Transcribe(ctx, image, mediaType)
 }
 
-func stripBase64Whitespace(s string) string {
-	// remove common whitespace/newlines
-	s = strings.ReplaceAll(s, "\n", "")
-	s = strings.ReplaceAll(s, "\r", "")
-	s = strings.ReplaceAll(s, "\t", "")
-	s = strings.ReplaceAll(s, " ", "")
-	return s
-}
-func (o *OCRContentExtractor) ocrBytesToText(img []byte, ext string) (string, error) {
-	// Tesseract/gosseract prefers a file path, so we write a temp file.
-	tmpDir := os.TempDir()
-	if ext == "" {
-		ext = ".png"
+// readPDF returns the text layer of a PDF, or the transcription of its pages
+// when the text layer is too sparse, and whether the pages were transcribed
+func (o *OCRContentExtractor) readPDF(ctx context.Context, pdf []byte) (string, bool, error) {
+	text, err := o.pdfs.Text(ctx, pdf)
+	if err != nil {
+		return "", false, err
 	}
-	tmpFile := filepath.Join(tmpDir, fmt.Sprintf("ocr-%d%s", time.Now().UnixNano(), ext))
-
-	if err := os.WriteFile(tmpFile, img, 0600); err != nil {
-		return "", fmt.Errorf("failed to write temp image: %w", err)
+	if hasTextLayer(text) {
+		return text, false, nil
 	}
-	defer func() {
-		_ = os.Remove(tmpFile)
-	}()
 
-	return o.ocrFileToText(tmpFile)
+	done := pipeline.Track(ctx, pipeline.StageOCR)
+	text, err = o.transcribePages(ctx, pdf)
+	done(err)
+	return text, true, err
 }
 
-func mimeToExt(mime string) string {
-	switch strings.ToLower(mime) {
-	case "image/png":
-		return ".png"
-	case "image/jpeg", "image/jpg":
-		return ".jpg"
-	case "image/webp":
-		return ".webp"
-	default:
-		return ".png"
+// transcribePages renders the pages of a PDF and transcribes them in order
+func (o *OCRContentExtractor) transcribePages(ctx context.Context, pdf []byte) (string, error) {
+	pages, err := o.pdfs.Pages(ctx, pdf)
+	if err != nil {
+		return "", err
+	}
+	texts := make([]string, 0, len(pages))
+	for i, page := range pages {
+		text, err := o.transcriber.Transcribe(ctx, page, "image/png")
+		if err != nil {
+			return "", fmt.Errorf("failed to transcribe page %d: %w", i+1, err)
+		}
+		if text = strings.TrimSpace(text); text != "" {
+			texts = append(texts, text)
+		}
 	}
+	return strings.Join(texts, "\n\n"), nil
 }
 
-func sniffImageExt(b []byte) string {
-	// Minimal magic-byte sniffing
-	if len(b) >= 8 && string(b[:8]) == "\x89PNG\r\n\x1a\n" {
-		return ".png"
-	}
-	if len(b) >= 3 && b[0] == 0xFF && b[1] == 0xD8 && b[2] == 0xFF {
-		return ".jpg"
-	}
-	// WEBP: "RIFF....WEBP"
-	if len(b) >= 12 && string(b[:4]) == "RIFF" && string(b[8:12]) == "WEBP" {
-		return ".webp"
+// hasTextLayer reports whether the text of a PDF, with its pages separated by
+// form feeds, averages at least minPageText letters and digits per page
+func hasTextLayer(text string) bool {
+	pages := strings.Count(strings.TrimRight(text, "\f\n"), "\f") + 1
+	count := 0
+	for _, r := range text {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			count++
+		}
 	}
-	return ".png"
+	return count >= minPageText*pages
 }
 
-func (o *OCRContentExtractor) ocrFileToText(path string) (string, error) {
-	client := gosseract.NewClient()
-	defer func() {
-		if err := client.Close(); err != nil {
-			fmt.Printf("warning: failed to close tesseract client: %v\n", err)
-		}
-	}()
+// looksLikeImageExt reports whether the file extension is of an image format transcribers accept
+func looksLikeImageExt(ext string) bool {
+	switch strings.ToLower(ext) {
+	case ".png", ".jpg", ".jpeg", ".webp", ".tif", ".tiff", ".heic", ".heif":
+		return true
+	default:
+		return false
+	}
+}
 
-	// Optional: set languages. Requires language packs installed.
-	// client.SetLanguage("eng") // or "eng+fas" if you install Persian traineddata
-	if err := client.SetImage(path); err != nil {
-		return "", fmt.Errorf("failed to set image: %w", err)
+func extToMime(ext string) string {
+	switch strings.ToLower(ext) {
+	case ".jpg", ".jpeg":
+		return "image/jpeg"
+	case ".webp":
+		return "image/webp"
+	case ".tif", ".tiff":
+		return "image/tiff"
+	case ".heic":
+		return "image/heic"
+	case ".heif":
+		return "image/heif"
+	default:
+		return "image/png"
 	}
-	return client.Text()
 }
@@ -0,0 +1,151 @@
+package extractor_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kazemisoroush/assistant/pkg/records"
+	"github.com/kazemisoroush/assistant/pkg/records/extractor"
+	"github.com/kazemisoroush/assistant/pkg/records/extractor/mocks"
+	"github.com/kazemisoroush/assistant/pkg/tokens"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+// pdfInput returns an in-memory PDF input
+func pdfInput(t *testing.T) extractor.Input {
+	t.Helper()
+	input, err := extractor.ReaderInput(strings.NewReader("%PDF-1.7 ..."), "")
+	require.NoError(t, err, "ReaderInput() error should be nil")
+	require.True(t, input.IsPDF(), "ReaderInput() should detect the PDF")
+	return input
+}
+
+func TestOCRContentExtractor_Extract_PDFTextLayer(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	transcriber := mocks.NewMockImageTranscriber(ctrl)
+	pdfs := mocks.NewMockPDFConverter(ctrl)
+	typeExtractor := mocks.NewMockTypeExtractor(ctrl)
+	metadataExtractor := mocks.NewMockMetadataExtractor(ctrl)
+	text := "Lab results: haemoglobin 14.2 g/dL, within the reference range.\f"
+	pdfs.EXPECT().Text(gomock.Any(), gomock.Any()).Return(text, nil)
+	typeExtractor.EXPECT().GetType(gomock.Any(), text).Return(records.RecordTypeHealthLab, nil)
+	metadataExtractor.EXPECT().GetMetadata(gomock.Any(), records.RecordTypeHealthLab, text).Return(nil, nil)
+	e := extractor.NewOCRContentExtractor(transcriber, pdfs, typeExtractor, metadataExtractor, tokens.NewBudgeter(8000, 1000))
+
+	// Act
+	rec, err := e.Extract(context.Background(), pdfInput(t))
+
+	// Assert
+	require.NoError(t, err, "Extract() error should be nil")
+	assert.Equal(t, text, rec.Content, "Extract() should read the text layer")
+	assert.Equal(t, false, rec.Metadata["ocr_used"], "Extract() should not transcribe PDFs with a text layer")
+	assert.Equal(t, "application/pdf", rec.Metadata["mime"], "Extract() should record the media type")
+}
+
+func TestOCRContentExtractor_Extract_ScannedPDF(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	transcriber := mocks.NewMockImageTranscriber(ctrl)
+	pdfs := mocks.NewMockPDFConverter(ctrl)
+	typeExtractor := mocks.NewMockTypeExtractor(ctrl)
+	metadataExtractor := mocks.NewMockMetadataExtractor(ctrl)
+	pdfs.EXPECT().Text(gomock.Any(), gomock.Any()).Return("1\f2\f", nil)
+	pdfs.EXPECT().Pages(gomock.Any(), gomock.Any()).Return([][]byte{[]byte("page 1"), []byte("page 2")}, nil)
+	transcriber.EXPECT().Transcribe(gomock.Any(), []byte("page 1"), "image/png").Return("Rental contract\n", nil)
+	transcriber.EXPECT().Transcribe(gomock.Any(), []byte("page 2"), "image/png").Return("Signed by both parties", nil)
+	typeExtractor.EXPECT().GetType(gomock.Any(), gomock.Any()).Return(records.RecordTypeHome, nil)
+	metadataExtractor.EXPECT().GetMetadata(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, nil)
+	e := extractor.NewOCRContentExtractor(transcriber, pdfs, typeExtractor, metadataExtractor, tokens.NewBudgeter(8000, 1000))
+
+	// Act
+	rec, err := e.Extract(context.Background(), pdfInput(t))
+
+	// Assert
+	require.NoError(t, err, "Extract() error should be nil")
+	assert.Equal(t, "Rental contract\n\nSigned by both parties", rec.Content, "Extract() should transcribe the pages in order")
+	assert.Equal(t, true, rec.Metadata["ocr_used"], "Extract() should record that the pages were transcribed")
+}
+
+func TestOCRContentExtractor_Extract_PDFUnsupported(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	e := extractor.NewOCRContentExtractor(mocks.NewMockImageTranscriber(ctrl), nil, mocks.NewMockTypeExtractor(ctrl), mocks.NewMockMetadataExtractor(ctrl), tokens.NewBudgeter(8000, 1000))
+
+	// Act
+	_, err := e.Extract(context.Background(), pdfInput(t))
+
+	// Assert
+	require.Error(t, err, "Extract() should fail without a PDF converter")
+	assert.Contains(t, err.Error(), "unsupported media type", "Extract() should report the unsupported PDF")
+}
+
+// writeOffice writes an office document holding the body entry
+func writeOffice(t *testing.T, name, entry, body string) string {
+	t.Helper()
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	w, err := archive.Create(entry)
+	require.NoError(t, err, "Create() error should be nil")
+	_, err = w.Write([]byte(body))
+	require.NoError(t, err, "Write() error should be nil")
+	require.NoError(t, archive.Close(), "Close() error should be nil")
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, buf.Bytes(), 0600))
+	return path
+}
+
+func TestOCRContentExtractor_Extract_OfficeDocuments(t *testing.T) {
+	tests := []struct {
+		name  string
+		file  string
+		entry string
+		body  string
+	}{
+		{
+			name:  "docx",
+			file:  "contract.docx",
+			entry: "word/document.xml",
+			body: `<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:body>` +
+				`<w:p><w:pPr><w:tabs><w:tab w:val="left" w:pos="720"/></w:tabs></w:pPr><w:r><w:t>Employment</w:t></w:r><w:r><w:t xml:space="preserve"> contract</w:t></w:r></w:p>` +
+				`<w:p><w:r><w:t>Salary:</w:t><w:tab/><w:t>5000 EUR</w:t></w:r></w:p></w:body></w:document>`,
+		},
+		{
+			name:  "odt",
+			file:  "contract.odt",
+			entry: "content.xml",
+			body: `<office:document-content xmlns:office="urn:oasis:names:tc:opendocument:xmlns:office:1.0" xmlns:text="urn:oasis:names:tc:opendocument:xmlns:text:1.0">` +
+				`<office:body><office:text><text:h>Employment<text:s/><text:span>contract</text:span></text:h>` +
+				`<text:p>Salary:<text:tab/>5000 EUR</text:p></office:text></office:body></office:document-content>`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			ctrl := gomock.NewController(t)
+			typeExtractor := mocks.NewMockTypeExtractor(ctrl)
+			metadataExtractor := mocks.NewMockMetadataExtractor(ctrl)
+			typeExtractor.EXPECT().GetType(gomock.Any(), gomock.Any()).Return(records.RecordTypeWorkContract, nil)
+			metadataExtractor.EXPECT().GetMetadata(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, nil)
+			e := extractor.NewOCRContentExtractor(mocks.NewMockImageTranscriber(ctrl), nil, typeExtractor, metadataExtractor, tokens.NewBudgeter(8000, 1000))
+			input, err := extractor.FileInput(writeOffice(t, tt.file, tt.entry, tt.body))
+			require.NoError(t, err, "FileInput() error should be nil")
+
+			// Act
+			rec, err := e.Extract(context.Background(), input)
+
+			// Assert
+			require.NoError(t, err, "Extract() error should be nil")
+			assert.Equal(t, "Employment contract\nSalary:\t5000 EUR", rec.Content, "Extract() should read one paragraph per line")
+			assert.Equal(t, false, rec.Metadata["ocr_used"], "Extract() should not transcribe office documents")
+		})
+	}
+}
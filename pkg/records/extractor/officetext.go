@@ -0,0 +1,113 @@
+package extractor
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Media types of the office documents text is read from
+const (
+	MediaTypeDOCX = "application/vnd.openxmlformats-officedocument.wordprocessingml.document"
+	MediaTypeODT  = "application/vnd.oasis.opendocument.text"
+)
+
+// officeFormat describes where the text of an office document is and which
+// of its XML elements hold it. Elements are matched by local name.
+type officeFormat struct {
+	entry      string            // Archive entry holding the document body
+	text       map[string]bool   // Elements whose character data is text, including nested elements
+	spaces     map[string]string // Empty elements standing for whitespace
+	ignored    map[string]bool   // Elements whose content is formatting, not text
+	paragraphs map[string]bool   // Elements ending a line
+}
+
+// officeFormats are the office document formats by media type
+var officeFormats = map[string]officeFormat{
+	MediaTypeDOCX: {
+		entry:      "word/document.xml",
+		text:       map[string]bool{"t": true},
+		spaces:     map[string]string{"tab": "\t", "br": "\n", "cr": "\n"},
+		ignored:    map[string]bool{"pPr": true, "rPr": true},
+		paragraphs: map[string]bool{"p": true},
+	},
+	MediaTypeODT: {
+		entry:      "content.xml",
+		text:       map[string]bool{"p": true, "h": true},
+		spaces:     map[string]string{"s": " ", "tab": "\t", "line-break": "\n"},
+		paragraphs: map[string]bool{"p": true, "h": true},
+	},
+}
+
+// officeText returns the text of a DOCX or ODT document, one paragraph per line
+func officeText(data []byte, mediaType string) (string, error) {
+	format, ok := officeFormats[mediaType]
+	if !ok {
+		return "", fmt.Errorf("unsupported media type %s", mediaType)
+	}
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return "", fmt.Errorf("failed to open document: %w", err)
+	}
+	body, err := archive.Open(format.entry)
+	if err != nil {
+		return "", fmt.Errorf("failed to open document body: %w", err)
+	}
+	defer func() { _ = body.Close() }()
+
+	// The body is decompressed no further than any other document is read
+	decoder := xml.NewDecoder(io.LimitReader(body, MaxInputSize))
+	w := officeWriter{format: format}
+	for {
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			return strings.TrimSpace(w.text.String()), nil
+		}
+		if err != nil {
+			return "", fmt.Errorf("failed to parse document body: %w", err)
+		}
+		w.write(token)
+	}
+}
+
+// officeWriter collects the text of the tokens of an office document body
+type officeWriter struct {
+	format  officeFormat
+	text    strings.Builder
+	depth   int // Number of open text elements
+	ignored int // Number of open ignored elements
+}
+
+// write adds the text a token stands for
+func (w *officeWriter) write(token xml.Token) {
+	switch t := token.(type) {
+	case xml.StartElement:
+		w.depth += oneIf(w.format.text[t.Name.Local])
+		w.ignored += oneIf(w.format.ignored[t.Name.Local])
+		if w.ignored == 0 {
+			w.text.WriteString(w.format.spaces[t.Name.Local])
+		}
+	case xml.EndElement:
+		w.depth -= oneIf(w.format.text[t.Name.Local])
+		w.ignored -= oneIf(w.format.ignored[t.Name.Local])
+		if w.format.paragraphs[t.Name.Local] {
+			w.text.WriteString("\n")
+		}
+	case xml.CharData:
+		if w.depth > 0 && w.ignored == 0 {
+			w.text.Write(t)
+		}
+	}
+}
+
+// oneIf returns 1 for true and 0 for false
+func oneIf(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
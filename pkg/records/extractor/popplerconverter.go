@@ -0,0 +1,107 @@
+package extractor
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// ErrPDFUnavailable is returned for PDFs without a text layer while no PDF
+// renderer is installed. The PDFs are retried by the next scrape.
+var ErrPDFUnavailable = errors.New("PDF renderer is unavailable")
+
+// PopplerConfig represents the poppler tools PDFs are read with
+type PopplerConfig struct {
+	TextExtractor string // pdftotext; empty or missing reads no text layer
+	Renderer      string // pdftoppm; empty or missing renders no pages
+	DPI           int    // Resolution pages are rendered at
+	MaxPages      int    // 0 renders every page
+}
+
+// PopplerConverter reads PDFs with poppler's command line tools. PDFs are
+// passed to the tools on stdin; rendered pages go through a temporary directory.
+type PopplerConverter struct {
+	config PopplerConfig
+}
+
+// NewPopplerConverter creates a new PopplerConverter instance
+func NewPopplerConverter(config PopplerConfig) PDFConverter {
+	return &PopplerConverter{config: config}
+}
+
+// Text implements PDFConverter. Without a text extractor every PDF reads as
+// having no text layer, so its pages are transcribed.
+func (p *PopplerConverter) Text(ctx context.Context, pdf []byte) (string, error) {
+	if p.config.TextExtractor == "" {
+		return "", nil
+	}
+	path, err := exec.LookPath(p.config.TextExtractor)
+	if err != nil {
+		return "", nil
+	}
+
+	text, err := runPoppler(ctx, path, pdf, "-layout", "-enc", "UTF-8", "-", "-")
+	if err != nil {
+		return "", fmt.Errorf("failed to read PDF text: %w", err)
+	}
+	return string(text), nil
+}
+
+// Pages implements PDFConverter, rendering the pages as PNG images
+func (p *PopplerConverter) Pages(ctx context.Context, pdf []byte) ([][]byte, error) {
+	if p.config.Renderer == "" {
+		return nil, fmt.Errorf("%w: no renderer is configured", ErrPDFUnavailable)
+	}
+	path, err := exec.LookPath(p.config.Renderer)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s is not installed", ErrPDFUnavailable, p.config.Renderer)
+	}
+
+	dir, err := os.MkdirTemp("", "assistant-pdf-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create page directory: %w", err)
+	}
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	args := []string{"-png", "-r", strconv.Itoa(p.config.DPI)}
+	if p.config.MaxPages > 0 {
+		args = append(args, "-l", strconv.Itoa(p.config.MaxPages))
+	}
+	if _, err := runPoppler(ctx, path, pdf, append(args, "-", filepath.Join(dir, "page"))...); err != nil {
+		return nil, fmt.Errorf("failed to render PDF: %w", err)
+	}
+
+	// Page files are numbered with zero padding, so they list in page order
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list rendered pages: %w", err)
+	}
+	pages := make([][]byte, 0, len(entries))
+	for _, entry := range entries {
+		page, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read rendered page: %w", err)
+		}
+		pages = append(pages, page)
+	}
+	return pages, nil
+}
+
+// runPoppler runs a poppler tool with the PDF on stdin and returns its output
+func runPoppler(ctx context.Context, path string, pdf []byte, args ...string) ([]byte, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, path, args...)
+	cmd.Stdin = bytes.NewReader(pdf)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("%w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.Bytes(), nil
+}
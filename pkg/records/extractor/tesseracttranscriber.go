@@ -0,0 +1,114 @@
+package extractor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"runtime"
+	"slices"
+
+	"github.com/otiai10/gosseract/v2"
+)
+
+// ErrOCRUnavailable is returned for images while Tesseract or its language
+// packs are not installed. Text documents are still extracted, and the images
+// are retried by the next scrape.
+var ErrOCRUnavailable = errors.New("tesseract OCR is unavailable")
+
+// TesseractConfig represents the pool of Tesseract clients
+type TesseractConfig struct {
+	Clients   int      // Clients transcribing images in parallel; 0 uses one per CPU
+	Languages []string // Language packs every client recognizes, e.g. "eng" and "fas"
+}
+
+// TesseractTranscriber transcribes images locally with Tesseract OCR. Images
+// are passed to Tesseract in memory. Creating a client initializes Tesseract,
+// so the clients are created up front with their languages set and shared by
+// the extract workers; a client transcribes one image at a time.
+type TesseractTranscriber struct {
+	clients     chan *gosseract.Client // Idle clients
+	unavailable error                  // Set when Tesseract cannot transcribe images
+}
+
+// NewTesseractTranscriber creates a new TesseractTranscriber instance with a
+// pool of pre-initialized clients. When Tesseract or a language pack is
+// missing, a warning is logged and every image fails with ErrOCRUnavailable.
+// Call Close to release the clients.
+func NewTesseractTranscriber(config TesseractConfig) *TesseractTranscriber {
+	size := config.Clients
+	if size <= 0 {
+		size = runtime.NumCPU()
+	}
+	languages := config.Languages
+	if len(languages) == 0 {
+		languages = []string{"eng"}
+	}
+
+	t := &TesseractTranscriber{clients: make(chan *gosseract.Client, size)}
+	if err := t.fill(size, languages); err != nil {
+		slog.Warn("Tesseract OCR is unavailable, images will not be transcribed", "error", err)
+		_ = t.Close()
+		t.unavailable = fmt.Errorf("%w: %v", ErrOCRUnavailable, err)
+	}
+	return t
+}
+
+// Transcribe returns the text found in the image, waiting for an idle client
+func (t *TesseractTranscriber) Transcribe(ctx context.Context, image []byte, _ string) (string, error) {
+	if t.unavailable != nil {
+		return "", t.unavailable
+	}
+
+	var client *gosseract.Client
+	select {
+	case client = <-t.clients:
+	case <-ctx.Done():
+		return "", ctx.Err()
+	}
+	defer func() { t.clients <- client }()
+
+	if err := client.SetImageFromBytes(image); err != nil {
+		return "", fmt.Errorf("failed to set image: %w", err)
+	}
+	return client.Text()
+}
+
+// Close releases the idle clients
+func (t *TesseractTranscriber) Close() error {
+	var errs []error
+	for {
+		select {
+		case client := <-t.clients:
+			errs = append(errs, client.Close())
+		default:
+			if err := errors.Join(errs...); err != nil {
+				return fmt.Errorf("failed to close tesseract client: %w", err)
+			}
+			return nil
+		}
+	}
+}
+
+// fill creates the clients after checking the language packs are installed
+func (t *TesseractTranscriber) fill(size int, languages []string) error {
+	available, err := gosseract.GetAvailableLanguages()
+	if err != nil {
+		return fmt.Errorf("failed to list language packs: %w", err)
+	}
+	for _, language := range languages {
+		if !slices.Contains(available, language) {
+			return fmt.Errorf("language pack %q is not installed", language)
+		}
+	}
+
+	for range size {
+		client := gosseract.NewClient()
+		if err := client.SetLanguage(languages...); err != nil {
+			_ = client.Close()
+			return fmt.Errorf("failed to set languages: %w", err)
+		}
+		t.clients <- client
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package extractor
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/kazemisoroush/assistant/pkg/records"
+	"github.com/kazemisoroush/assistant/pkg/thumbnails"
+)
+
+// ThumbnailContentExtractor stores a thumbnail of every scanned image or PDF
+// it extracts a record from. Thumbnails are best effort and never fail extraction.
+type ThumbnailContentExtractor struct {
+	extractor ContentExtractor
+	generator thumbnails.Generator
+	store     thumbnails.Store
+}
+
+// NewThumbnailContentExtractor wraps a content extractor with thumbnail generation
+func NewThumbnailContentExtractor(extractor ContentExtractor, generator thumbnails.Generator, store thumbnails.Store) ContentExtractor {
+	return &ThumbnailContentExtractor{
+		extractor: extractor,
+		generator: generator,
+		store:     store,
+	}
+}
+
+// Extract implements ContentExtractor
+func (t *ThumbnailContentExtractor) Extract(ctx context.Context, input Input) (records.Record, error) {
+	rec, err := t.extractor.Extract(ctx, input)
+	if err != nil {
+		return rec, err
+	}
+
+	if !input.IsImage() && !input.IsPDF() {
+		return rec, nil
+	}
+	data, err := input.ReadAll()
+	if err != nil {
+		slog.WarnContext(ctx, "Failed to read document for thumbnail", "record_id", rec.ID, "error", err)
+		return rec, nil
+	}
+	thumbnail, err := t.generator.Generate(ctx, data, input.MediaType)
+	if errors.Is(err, thumbnails.ErrUnsupported) {
+		return rec, nil
+	}
+	if err != nil {
+		slog.WarnContext(ctx, "Failed to generate thumbnail", "record_id", rec.ID, "error", err)
+		return rec, nil
+	}
+	if err := t.store.Put(ctx, rec.ID, thumbnail); err != nil {
+		slog.WarnContext(ctx, "Failed to store thumbnail", "record_id", rec.ID, "error", err)
+		return rec, nil
+	}
+
+	if rec.Metadata == nil {
+		rec.Metadata = map[string]interface{}{}
+	}
+	rec.Metadata[records.MetaThumbnail] = true
+	return rec, nil
+}
@@ -0,0 +1,84 @@
+package ingestor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/kazemisoroush/assistant/pkg/ai"
+	"github.com/kazemisoroush/assistant/pkg/entities"
+	"github.com/kazemisoroush/assistant/pkg/records"
+)
+
+// EntityIngestor indexes the entities named in every ingested record. The
+// index is secondary: records are ingested even when extraction fails.
+type EntityIngestor struct {
+	ingestor  Ingestor
+	extractor entities.Extractor
+	index     entities.Index
+}
+
+// NewEntityIngestor wraps an ingestor with entity extraction and indexing
+func NewEntityIngestor(ingestor Ingestor, extractor entities.Extractor, index entities.Index) Ingestor {
+	return &EntityIngestor{
+		ingestor:  ingestor,
+		extractor: extractor,
+		index:     index,
+	}
+}
+
+// Ingest implements Ingestor
+func (e *EntityIngestor) Ingest(ctx context.Context, record records.Record) error {
+	if err := e.ingestor.Ingest(ctx, record); err != nil {
+		return err
+	}
+	return e.indexEntities(ctx, record)
+}
+
+// IngestBatch implements Ingestor. Entities are indexed once the batch was ingested.
+func (e *EntityIngestor) IngestBatch(ctx context.Context, recs []records.Record) error {
+	if err := e.ingestor.IngestBatch(ctx, recs); err != nil {
+		return err
+	}
+	for _, record := range recs {
+		if err := e.indexEntities(ctx, record); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// indexEntities extracts the entities of an ingested record and indexes them
+func (e *EntityIngestor) indexEntities(ctx context.Context, record records.Record) error {
+	found, err := e.extractor.Extract(ctx, record)
+	if errors.Is(err, ai.ErrRecordTypeNotAllowed) {
+		// Guardrails keep this record off the configured model
+		return nil
+	}
+	if err != nil {
+		slog.WarnContext(ctx, "Failed to extract entities", "record_id", record.ID, "error", err)
+		return nil
+	}
+
+	if err := e.index.Set(ctx, record.ID, found); err != nil {
+		return fmt.Errorf("failed to index entities: %w", err)
+	}
+	return nil
+}
+
+// Delete implements Ingestor
+func (e *EntityIngestor) Delete(ctx context.Context, id string) error {
+	if err := e.ingestor.Delete(ctx, id); err != nil {
+		return err
+	}
+	if err := e.index.Remove(ctx, id); err != nil {
+		return fmt.Errorf("failed to remove entities: %w", err)
+	}
+	return nil
+}
+
+// Flush implements Ingestor
+func (e *EntityIngestor) Flush(ctx context.Context) error {
+	return e.ingestor.Flush(ctx)
+}
@@ -0,0 +1,82 @@
+package ingestor_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/kazemisoroush/assistant/pkg/ai"
+	"github.com/kazemisoroush/assistant/pkg/entities"
+	entitymocks "github.com/kazemisoroush/assistant/pkg/entities/mocks"
+	"github.com/kazemisoroush/assistant/pkg/records"
+	"github.com/kazemisoroush/assistant/pkg/records/ingestor"
+	"github.com/kazemisoroush/assistant/pkg/records/ingestor/mocks"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestEntityIngestor_Ingest_IndexesEntities(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	inner := mocks.NewMockService(ctrl)
+	extractor := entitymocks.NewMockExtractor(ctrl)
+	index := entitymocks.NewMockIndex(ctrl)
+	rec := records.Record{ID: "visit-1", Type: records.RecordTypeHealthVisit}
+	found := []entities.Entity{{Name: "Dr. Smith", Kind: entities.KindDoctor}}
+	inner.EXPECT().Ingest(gomock.Any(), rec).Return(nil)
+	extractor.EXPECT().Extract(gomock.Any(), rec).Return(found, nil)
+	index.EXPECT().Set(gomock.Any(), "visit-1", found).Return(nil)
+
+	// Act
+	err := ingestor.NewEntityIngestor(inner, extractor, index).Ingest(context.Background(), rec)
+
+	// Assert
+	assert.NoError(t, err, "Ingest() error should be nil")
+}
+
+func TestEntityIngestor_Ingest_ExtractionFailureKeepsRecord(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+	}{
+		{name: "guardrail", err: fmt.Errorf("%w: health records may not be sent to openai", ai.ErrRecordTypeNotAllowed)},
+		{name: "provider", err: errors.New("model unavailable")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			ctrl := gomock.NewController(t)
+			inner := mocks.NewMockService(ctrl)
+			extractor := entitymocks.NewMockExtractor(ctrl)
+			index := entitymocks.NewMockIndex(ctrl)
+			rec := records.Record{ID: "visit-1"}
+			inner.EXPECT().Ingest(gomock.Any(), rec).Return(nil)
+			extractor.EXPECT().Extract(gomock.Any(), rec).Return(nil, tt.err)
+
+			// Act
+			err := ingestor.NewEntityIngestor(inner, extractor, index).Ingest(context.Background(), rec)
+
+			// Assert
+			assert.NoError(t, err, "Ingest() should not fail the record when extraction fails")
+		})
+	}
+}
+
+func TestEntityIngestor_Delete_RemovesEntities(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	inner := mocks.NewMockService(ctrl)
+	index := entitymocks.NewMockIndex(ctrl)
+	gomock.InOrder(
+		inner.EXPECT().Delete(gomock.Any(), "visit-1").Return(nil),
+		index.EXPECT().Remove(gomock.Any(), "visit-1").Return(nil),
+	)
+
+	// Act
+	err := ingestor.NewEntityIngestor(inner, entitymocks.NewMockExtractor(ctrl), index).Delete(context.Background(), "visit-1")
+
+	// Assert
+	assert.NoError(t, err, "Delete() error should be nil")
+}
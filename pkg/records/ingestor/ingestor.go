@@ -14,6 +14,13 @@ type Ingestor interface {
 	// Ingest processes and stores a record
 	Ingest(ctx context.Context, record records.Record) error
 
+	// IngestBatch processes several records and stores them in one write.
+	// Either every record is stored or, when one fails, none.
+	IngestBatch(ctx context.Context, recs []records.Record) error
+
 	// Delete removes a record
 	Delete(ctx context.Context, id string) error
+
+	// Flush indexes the records ingested but not indexed yet
+	Flush(ctx context.Context) error
 }
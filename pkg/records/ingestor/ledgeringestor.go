@@ -0,0 +1,95 @@
+package ingestor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/kazemisoroush/assistant/pkg/records"
+	"github.com/kazemisoroush/assistant/pkg/records/ledger"
+	"github.com/kazemisoroush/assistant/pkg/records/storage"
+)
+
+// LedgerIngestor records the file version of every record stamped with one
+// once it was ingested, so later scrapes and watches skip the file until it
+// changes. The record of a file's previous version is deleted once the new
+// one is in. Records without a stamp are ingested as they are.
+type LedgerIngestor struct {
+	ingestor Ingestor
+	ledger   ledger.Store
+}
+
+// NewLedgerIngestor wraps an ingestor with the scan ledger
+func NewLedgerIngestor(ingestor Ingestor, ledger ledger.Store) Ingestor {
+	return &LedgerIngestor{
+		ingestor: ingestor,
+		ledger:   ledger,
+	}
+}
+
+// Ingest implements Ingestor
+func (l *LedgerIngestor) Ingest(ctx context.Context, record records.Record) error {
+	scan, ok := ledger.Stamped(record)
+	if !ok {
+		return l.ingestor.Ingest(ctx, record)
+	}
+	// Without an entry the previous version is the zero Entry
+	previous, _, err := l.ledger.Get(ctx, scan.Path)
+	if err != nil {
+		return err
+	}
+
+	if err := l.ingestor.Ingest(ctx, record); err != nil {
+		return err
+	}
+	return l.replace(ctx, previous, scan)
+}
+
+// IngestBatch implements Ingestor
+func (l *LedgerIngestor) IngestBatch(ctx context.Context, recs []records.Record) error {
+	var scans, previous []ledger.Entry
+	for _, record := range recs {
+		scan, ok := ledger.Stamped(record)
+		if !ok {
+			continue
+		}
+		entry, _, err := l.ledger.Get(ctx, scan.Path)
+		if err != nil {
+			return err
+		}
+		scans = append(scans, scan)
+		previous = append(previous, entry)
+	}
+
+	if err := l.ingestor.IngestBatch(ctx, recs); err != nil {
+		return err
+	}
+	for i, scan := range scans {
+		if err := l.replace(ctx, previous[i], scan); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// replace puts the ingested version of a file in the ledger, deleting the
+// record of its previous version, if there was one
+func (l *LedgerIngestor) replace(ctx context.Context, previous, scan ledger.Entry) error {
+	if previous.RecordID != "" && previous.RecordID != scan.RecordID {
+		if err := l.ingestor.Delete(ctx, previous.RecordID); err != nil && !errors.Is(err, storage.ErrNotFound) {
+			return fmt.Errorf("failed to delete record of previous version of %s: %w", scan.Path, err)
+		}
+	}
+	return l.ledger.Put(ctx, scan)
+}
+
+// Delete implements Ingestor. The ledger entry of the record's file stays,
+// so the file is not scraped again while unchanged.
+func (l *LedgerIngestor) Delete(ctx context.Context, id string) error {
+	return l.ingestor.Delete(ctx, id)
+}
+
+// Flush implements Ingestor
+func (l *LedgerIngestor) Flush(ctx context.Context) error {
+	return l.ingestor.Flush(ctx)
+}
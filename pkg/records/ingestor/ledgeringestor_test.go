@@ -0,0 +1,72 @@
+package ingestor_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/kazemisoroush/assistant/pkg/records"
+	"github.com/kazemisoroush/assistant/pkg/records/ingestor"
+	"github.com/kazemisoroush/assistant/pkg/records/ingestor/mocks"
+	"github.com/kazemisoroush/assistant/pkg/records/ledger"
+	ledgermocks "github.com/kazemisoroush/assistant/pkg/records/ledger/mocks"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestLedgerIngestor_Ingest_ReplacesPreviousVersion(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	inner := mocks.NewMockService(ctrl)
+	store := ledgermocks.NewMockStore(ctrl)
+	scan := ledger.Entry{Path: "/docs/receipt.pdf", SHA256: "new", ModTime: time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)}
+	rec := scan.Stamp(records.Record{ID: "rec-2", FilePath: scan.Path})
+	gomock.InOrder(
+		store.EXPECT().Get(gomock.Any(), scan.Path).Return(ledger.Entry{Path: scan.Path, SHA256: "old", RecordID: "rec-1"}, true, nil),
+		inner.EXPECT().Ingest(gomock.Any(), rec).Return(nil),
+		inner.EXPECT().Delete(gomock.Any(), "rec-1").Return(nil),
+		store.EXPECT().Put(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, entry ledger.Entry) error {
+			assert.Equal(t, "new", entry.SHA256, "Ingest() should record the ingested version")
+			assert.Equal(t, "rec-2", entry.RecordID, "Ingest() should record the ingested record")
+			return nil
+		}),
+	)
+
+	// Act
+	err := ingestor.NewLedgerIngestor(inner, store).Ingest(context.Background(), rec)
+
+	// Assert
+	assert.NoError(t, err, "Ingest() error should be nil")
+}
+
+func TestLedgerIngestor_Ingest_FailureKeepsLedger(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	inner := mocks.NewMockService(ctrl)
+	store := ledgermocks.NewMockStore(ctrl)
+	scan := ledger.Entry{Path: "/docs/receipt.pdf", SHA256: "new", ModTime: time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)}
+	rec := scan.Stamp(records.Record{ID: "rec-2", FilePath: scan.Path})
+	store.EXPECT().Get(gomock.Any(), scan.Path).Return(ledger.Entry{}, false, nil)
+	inner.EXPECT().Ingest(gomock.Any(), rec).Return(errors.New("disk full"))
+
+	// Act
+	err := ingestor.NewLedgerIngestor(inner, store).Ingest(context.Background(), rec)
+
+	// Assert
+	assert.Error(t, err, "Ingest() should fail when the record fails to be ingested")
+}
+
+func TestLedgerIngestor_Ingest_Unstamped(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	inner := mocks.NewMockService(ctrl)
+	rec := records.Record{ID: "rec-1"}
+	inner.EXPECT().Ingest(gomock.Any(), rec).Return(nil)
+
+	// Act
+	err := ingestor.NewLedgerIngestor(inner, ledgermocks.NewMockStore(ctrl)).Ingest(context.Background(), rec)
+
+	// Assert
+	assert.NoError(t, err, "Ingest() should ingest records without a stamp as they are")
+}
@@ -55,6 +55,20 @@ func (mr *MockServiceMockRecorder) Delete(ctx, id any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockService)(nil).Delete), ctx, id)
 }
 
+// Flush mocks base method.
+func (m *MockService) Flush(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Flush", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Flush indicates an expected call of Flush.
+func (mr *MockServiceMockRecorder) Flush(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Flush", reflect.TypeOf((*MockService)(nil).Flush), ctx)
+}
+
 // Ingest mocks base method.
 func (m *MockService) Ingest(ctx context.Context, record records.Record) error {
 	m.ctrl.T.Helper()
@@ -68,3 +82,17 @@ func (mr *MockServiceMockRecorder) Ingest(ctx, record any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Ingest", reflect.TypeOf((*MockService)(nil).Ingest), ctx, record)
 }
+
+// IngestBatch mocks base method.
+func (m *MockService) IngestBatch(ctx context.Context, recs []records.Record) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IngestBatch", ctx, recs)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// IngestBatch indicates an expected call of IngestBatch.
+func (mr *MockServiceMockRecorder) IngestBatch(ctx, recs any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IngestBatch", reflect.TypeOf((*MockService)(nil).IngestBatch), ctx, recs)
+}
@@ -0,0 +1,69 @@
+package ingestor
+
+import (
+	"context"
+	"log/slog"
+	"maps"
+
+	"github.com/kazemisoroush/assistant/pkg/household"
+	"github.com/kazemisoroush/assistant/pkg/records"
+)
+
+// PersonIngestor attributes every ingested record not yet attributed to a
+// household member to the member detected from its content. Records are
+// ingested unattributed when detection fails.
+type PersonIngestor struct {
+	ingestor Ingestor
+	detector household.Detector
+}
+
+// NewPersonIngestor wraps an ingestor with household member detection
+func NewPersonIngestor(ingestor Ingestor, detector household.Detector) Ingestor {
+	return &PersonIngestor{
+		ingestor: ingestor,
+		detector: detector,
+	}
+}
+
+// Ingest implements Ingestor
+func (p *PersonIngestor) Ingest(ctx context.Context, record records.Record) error {
+	return p.ingestor.Ingest(ctx, p.attribute(ctx, record))
+}
+
+// IngestBatch implements Ingestor
+func (p *PersonIngestor) IngestBatch(ctx context.Context, recs []records.Record) error {
+	attributed := make([]records.Record, len(recs))
+	for i, record := range recs {
+		attributed[i] = p.attribute(ctx, record)
+	}
+	return p.ingestor.IngestBatch(ctx, attributed)
+}
+
+// attribute attributes a record not yet attributed to the household member detected
+func (p *PersonIngestor) attribute(ctx context.Context, record records.Record) records.Record {
+	if record.Person() != "" {
+		return record
+	}
+	person, err := p.detector.Detect(ctx, record)
+	if err != nil {
+		slog.WarnContext(ctx, "Failed to detect household member", "record_id", record.ID, "error", err)
+	}
+	if person != "" {
+		record.Metadata = maps.Clone(record.Metadata)
+		if record.Metadata == nil {
+			record.Metadata = map[string]interface{}{}
+		}
+		record.Metadata[records.MetaPerson] = person
+	}
+	return record
+}
+
+// Delete implements Ingestor
+func (p *PersonIngestor) Delete(ctx context.Context, id string) error {
+	return p.ingestor.Delete(ctx, id)
+}
+
+// Flush implements Ingestor
+func (p *PersonIngestor) Flush(ctx context.Context) error {
+	return p.ingestor.Flush(ctx)
+}
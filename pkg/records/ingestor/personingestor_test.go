@@ -0,0 +1,67 @@
+package ingestor_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	householdmocks "github.com/kazemisoroush/assistant/pkg/household/mocks"
+	"github.com/kazemisoroush/assistant/pkg/records"
+	"github.com/kazemisoroush/assistant/pkg/records/ingestor"
+	"github.com/kazemisoroush/assistant/pkg/records/ingestor/mocks"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestPersonIngestor_Ingest_AttributesRecord(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	inner := mocks.NewMockService(ctrl)
+	detector := householdmocks.NewMockDetector(ctrl)
+	rec := records.Record{ID: "vaccination", Type: records.RecordTypeHealthVisit, Content: "Vaccination card of Lena Schmidt"}
+	detector.EXPECT().Detect(gomock.Any(), rec).Return("Lena Schmidt", nil)
+	var stored records.Record
+	inner.EXPECT().Ingest(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, r records.Record) error {
+		stored = r
+		return nil
+	})
+
+	// Act
+	err := ingestor.NewPersonIngestor(inner, detector).Ingest(context.Background(), rec)
+
+	// Assert
+	assert.NoError(t, err, "Ingest() error should be nil")
+	assert.Equal(t, "Lena Schmidt", stored.Person(), "Ingest() should attribute the record to the detected member")
+	assert.Empty(t, rec.Person(), "Ingest() should not modify the caller's metadata")
+}
+
+func TestPersonIngestor_Ingest_KeepsExistingPerson(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	inner := mocks.NewMockService(ctrl)
+	detector := householdmocks.NewMockDetector(ctrl)
+	rec := records.Record{ID: "receipt", Metadata: map[string]interface{}{records.MetaPerson: "Max Schmidt"}}
+	inner.EXPECT().Ingest(gomock.Any(), rec).Return(nil)
+
+	// Act
+	err := ingestor.NewPersonIngestor(inner, detector).Ingest(context.Background(), rec)
+
+	// Assert
+	assert.NoError(t, err, "Ingest() should not re-detect attributed records")
+}
+
+func TestPersonIngestor_Ingest_DetectionFailureKeepsRecord(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	inner := mocks.NewMockService(ctrl)
+	detector := householdmocks.NewMockDetector(ctrl)
+	rec := records.Record{ID: "receipt"}
+	detector.EXPECT().Detect(gomock.Any(), rec).Return("", errors.New("database locked"))
+	inner.EXPECT().Ingest(gomock.Any(), rec).Return(nil)
+
+	// Act
+	err := ingestor.NewPersonIngestor(inner, detector).Ingest(context.Background(), rec)
+
+	// Assert
+	assert.NoError(t, err, "Ingest() should ingest the record unattributed")
+}
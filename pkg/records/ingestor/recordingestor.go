@@ -2,28 +2,48 @@ package ingestor
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
 
 	"github.com/kazemisoroush/assistant/pkg/records"
 	"github.com/kazemisoroush/assistant/pkg/records/knowledgebase"
+	"github.com/kazemisoroush/assistant/pkg/records/pipeline"
 	"github.com/kazemisoroush/assistant/pkg/records/storage"
 )
 
-// RecordIngestor is an implementation of the Ingestor interface.
+// BatchConfig represents how records are buffered before they are written to the vector store
+type BatchConfig struct {
+	Size    int           // Records written to the vector store at once; 1 or less indexes every record as it is ingested
+	Timeout time.Duration // How long a partial batch waits before it is written; 0 waits for Flush
+}
+
+// RecordIngestor is an implementation of the Ingestor interface. Records are
+// stored as they are ingested and indexed in batches: a batch is written once
+// it is full, once its timeout passes, or on Flush.
 type RecordIngestor struct {
 	storage       storage.Storage
 	vectorStorage knowledgebase.VectorStorage
+	batch         BatchConfig
+
+	mu      sync.Mutex
+	pending []records.Record // Stored but not indexed yet
+	timer   *time.Timer      // Writes the pending batch once it timed out
+	failed  error            // Error of a timed out batch, reported by the next Flush
 }
 
 // NewRecordIngestor creates a new instance of RecordIngestor.
-func NewRecordIngestor(storage storage.Storage, vectorStorage knowledgebase.VectorStorage) Ingestor {
+func NewRecordIngestor(storage storage.Storage, vectorStorage knowledgebase.VectorStorage, batch BatchConfig) Ingestor {
 	return &RecordIngestor{
 		storage:       storage,
 		vectorStorage: vectorStorage,
+		batch:         batch,
 	}
 }
 
-// Ingest processes and stores a record without checking for existing records
 // Ingest processes and stores a record (upsert behavior)
 func (s *RecordIngestor) Ingest(ctx context.Context, record records.Record) error {
 	// Check if record exists
@@ -33,24 +53,62 @@ func (s *RecordIngestor) Ingest(ctx context.Context, record records.Record) erro
 		if err := s.storage.Delete(ctx, record.ID); err != nil {
 			return fmt.Errorf("failed to delete existing record from storage: %w", err)
 		}
-		if err := s.vectorStorage.Delete(ctx, record.ID); err != nil {
+		if err := s.unindex(ctx, record.ID); err != nil {
 			return fmt.Errorf("failed to delete existing record from vector store: %w", err)
 		}
 	}
 
 	// Store the record
-	if err := s.storage.Store(ctx, record); err != nil {
+	done := pipeline.Track(ctx, pipeline.StageStore)
+	err = s.storage.Store(ctx, record)
+	done(err)
+	if err != nil {
 		return fmt.Errorf("failed to store record: %w", err)
 	}
 
 	// Index in vector store for semantic search
-	if err := s.vectorStorage.Index(ctx, record); err != nil {
+	done = pipeline.Track(ctx, pipeline.StageIndex)
+	err = s.index(ctx, record)
+	done(err)
+	if err != nil {
 		return fmt.Errorf("failed to index record: %w", err)
 	}
 
 	return nil
 }
 
+// IngestBatch implements Ingestor. The records are stored in one write,
+// replacing those with the same IDs, and indexed in one write.
+func (s *RecordIngestor) IngestBatch(ctx context.Context, recs []records.Record) error {
+	if len(recs) == 0 {
+		return nil
+	}
+	var replaced []string
+	for _, rec := range recs {
+		if _, err := s.storage.Get(ctx, rec.ID); err == nil {
+			replaced = append(replaced, rec.ID)
+		}
+	}
+
+	done := pipeline.Track(ctx, pipeline.StageStore)
+	err := s.storage.StoreBatch(ctx, recs)
+	done(err)
+	if err != nil {
+		return fmt.Errorf("failed to store records: %w", err)
+	}
+
+	// Replaced records drop their embeddings before the batch is indexed
+	for _, id := range replaced {
+		if err := s.unindex(ctx, id); err != nil {
+			return fmt.Errorf("failed to delete existing record from vector store: %w", err)
+		}
+	}
+	done = pipeline.Track(ctx, pipeline.StageIndex)
+	err = s.write(ctx, recs)
+	done(err)
+	return err
+}
+
 // Delete removes a record
 func (s *RecordIngestor) Delete(ctx context.Context, id string) error {
 	if err := s.storage.Delete(ctx, id); err != nil {
@@ -58,9 +116,93 @@ func (s *RecordIngestor) Delete(ctx context.Context, id string) error {
 	}
 
 	// Delete from vector store
-	if err := s.vectorStorage.Delete(ctx, id); err != nil {
+	if err := s.unindex(ctx, id); err != nil {
 		return fmt.Errorf("failed to delete from vector store: %w", err)
 	}
 
 	return nil
 }
+
+// Flush implements Ingestor
+func (s *RecordIngestor) Flush(ctx context.Context) error {
+	s.mu.Lock()
+	batch := s.take()
+	failed := s.failed
+	s.failed = nil
+	s.mu.Unlock()
+
+	return errors.Join(failed, s.write(ctx, batch))
+}
+
+// index queues the record for the next batch, writing the batch once it is full
+func (s *RecordIngestor) index(ctx context.Context, record records.Record) error {
+	if s.batch.Size <= 1 {
+		return s.vectorStorage.Index(ctx, record)
+	}
+
+	s.mu.Lock()
+	s.pending = append(s.pending, record)
+	if len(s.pending) < s.batch.Size {
+		if s.timer == nil && s.batch.Timeout > 0 {
+			s.timer = time.AfterFunc(s.batch.Timeout, s.timeout)
+		}
+		s.mu.Unlock()
+		return nil
+	}
+	batch := s.take()
+	s.mu.Unlock()
+
+	return s.write(ctx, batch)
+}
+
+// unindex removes the record from the vector store, or from the pending
+// batch when it was not written yet
+func (s *RecordIngestor) unindex(ctx context.Context, id string) error {
+	s.mu.Lock()
+	n := len(s.pending)
+	s.pending = slices.DeleteFunc(s.pending, func(rec records.Record) bool { return rec.ID == id })
+	queued := len(s.pending) < n
+	s.mu.Unlock()
+
+	if queued {
+		return nil
+	}
+	return s.vectorStorage.Delete(ctx, id)
+}
+
+// timeout writes the pending batch once it waited for the batch timeout. The
+// ingest that queued it may have returned, so a failure is kept for Flush.
+func (s *RecordIngestor) timeout() {
+	s.mu.Lock()
+	batch := s.take()
+	s.mu.Unlock()
+
+	if err := s.write(context.Background(), batch); err != nil {
+		slog.Warn("Failed to index timed out batch", "records", len(batch), "error", err)
+		s.mu.Lock()
+		s.failed = errors.Join(s.failed, err)
+		s.mu.Unlock()
+	}
+}
+
+// take empties the pending batch and stops its timer. The caller holds the lock.
+func (s *RecordIngestor) take() []records.Record {
+	batch := s.pending
+	s.pending = nil
+	if s.timer != nil {
+		s.timer.Stop()
+		s.timer = nil
+	}
+	return batch
+}
+
+// write indexes the batch in one vector store write
+func (s *RecordIngestor) write(ctx context.Context, batch []records.Record) error {
+	if len(batch) == 0 {
+		return nil
+	}
+	if err := s.vectorStorage.IndexBatch(ctx, batch); err != nil {
+		return fmt.Errorf("failed to index batch of %d records: %w", len(batch), err)
+	}
+	return nil
+}
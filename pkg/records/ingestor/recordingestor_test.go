@@ -0,0 +1,183 @@
+package ingestor_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/kazemisoroush/assistant/pkg/records"
+	"github.com/kazemisoroush/assistant/pkg/records/ingestor"
+	"github.com/kazemisoroush/assistant/pkg/records/knowledgebase"
+	kbmocks "github.com/kazemisoroush/assistant/pkg/records/knowledgebase/mocks"
+	"github.com/kazemisoroush/assistant/pkg/records/storage"
+	storagemocks "github.com/kazemisoroush/assistant/pkg/records/storage/mocks"
+	"github.com/kazemisoroush/assistant/pkg/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+// openDB opens the database at path, closed once the test is done
+func openDB(t testing.TB, path string) *sqlite.DB {
+	t.Helper()
+	db, err := sqlite.Open(path)
+	require.NoError(t, err, "Open() error should be nil")
+	t.Cleanup(func() { _ = db.Close() })
+	return db
+}
+
+// newStorage expects every record to be stored as new
+func newStorage(ctrl *gomock.Controller) *storagemocks.MockStorage {
+	storage := storagemocks.NewMockStorage(ctrl)
+	storage.EXPECT().Get(gomock.Any(), gomock.Any()).Return(records.Record{}, errors.New("not found")).AnyTimes()
+	storage.EXPECT().Store(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	return storage
+}
+
+func TestRecordIngestor_Ingest_IndexesFullBatches(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	vectorStorage := kbmocks.NewMockVectorStorage(ctrl)
+	recs := []records.Record{{ID: "a"}, {ID: "b"}, {ID: "c"}}
+	gomock.InOrder(
+		vectorStorage.EXPECT().IndexBatch(gomock.Any(), recs[:2]).Return(nil),
+		vectorStorage.EXPECT().IndexBatch(gomock.Any(), recs[2:]).Return(nil),
+	)
+	ing := ingestor.NewRecordIngestor(newStorage(ctrl), vectorStorage, ingestor.BatchConfig{Size: 2})
+
+	// Act
+	for _, rec := range recs {
+		require.NoError(t, ing.Ingest(context.Background(), rec), "Ingest() error should be nil")
+	}
+	err := ing.Flush(context.Background())
+
+	// Assert
+	require.NoError(t, err, "Flush() error should be nil")
+}
+
+func TestRecordIngestor_Ingest_IndexesTimedOutBatch(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	vectorStorage := kbmocks.NewMockVectorStorage(ctrl)
+	indexed := make(chan []records.Record, 1)
+	vectorStorage.EXPECT().IndexBatch(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, recs []records.Record) error {
+		indexed <- recs
+		return errors.New("unavailable")
+	})
+	ing := ingestor.NewRecordIngestor(newStorage(ctrl), vectorStorage, ingestor.BatchConfig{Size: 10, Timeout: 10 * time.Millisecond})
+
+	// Act
+	err := ing.Ingest(context.Background(), records.Record{ID: "a"})
+
+	// Assert
+	require.NoError(t, err, "Ingest() error should be nil")
+	select {
+	case recs := <-indexed:
+		assert.Equal(t, []records.Record{{ID: "a"}}, recs, "Ingest() should index a partial batch once it timed out")
+	case <-time.After(time.Second):
+		t.Fatal("Ingest() should index a partial batch once it timed out")
+	}
+	assert.Eventually(t, func() bool {
+		return ing.Flush(context.Background()) != nil
+	}, time.Second, 10*time.Millisecond, "Flush() should report the failure of a timed out batch")
+}
+
+func TestRecordIngestor_Delete_DropsQueuedRecord(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	storage := newStorage(ctrl)
+	storage.EXPECT().Delete(gomock.Any(), "a").Return(nil)
+	vectorStorage := kbmocks.NewMockVectorStorage(ctrl)
+	vectorStorage.EXPECT().IndexBatch(gomock.Any(), []records.Record{{ID: "b"}}).Return(nil)
+	ing := ingestor.NewRecordIngestor(storage, vectorStorage, ingestor.BatchConfig{Size: 10})
+	require.NoError(t, ing.Ingest(context.Background(), records.Record{ID: "a"}), "Ingest() error should be nil")
+	require.NoError(t, ing.Ingest(context.Background(), records.Record{ID: "b"}), "Ingest() error should be nil")
+
+	// Act
+	err := ing.Delete(context.Background(), "a")
+
+	// Assert
+	require.NoError(t, err, "Delete() error should be nil")
+	require.NoError(t, ing.Flush(context.Background()), "Flush() error should be nil")
+}
+
+func TestRecordIngestor_Ingest_WithoutBatching(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	vectorStorage := kbmocks.NewMockVectorStorage(ctrl)
+	vectorStorage.EXPECT().Index(gomock.Any(), records.Record{ID: "a"}).Return(nil)
+	ing := ingestor.NewRecordIngestor(newStorage(ctrl), vectorStorage, ingestor.BatchConfig{})
+
+	// Act
+	err := ing.Ingest(context.Background(), records.Record{ID: "a"})
+
+	// Assert
+	require.NoError(t, err, "Ingest() error should be nil")
+	require.NoError(t, ing.Flush(context.Background()), "Flush() error should be nil")
+}
+
+func TestRecordIngestor_IngestBatch_ReplacesExisting(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	storage := storagemocks.NewMockStorage(ctrl)
+	vectorStorage := kbmocks.NewMockVectorStorage(ctrl)
+	recs := []records.Record{{ID: "a"}, {ID: "b"}}
+	storage.EXPECT().Get(gomock.Any(), "a").Return(records.Record{ID: "a"}, nil)
+	storage.EXPECT().Get(gomock.Any(), "b").Return(records.Record{}, errors.New("not found"))
+	gomock.InOrder(
+		storage.EXPECT().StoreBatch(gomock.Any(), recs).Return(nil),
+		vectorStorage.EXPECT().Delete(gomock.Any(), "a").Return(nil),
+		vectorStorage.EXPECT().IndexBatch(gomock.Any(), recs).Return(nil),
+	)
+	ing := ingestor.NewRecordIngestor(storage, vectorStorage, ingestor.BatchConfig{})
+
+	// Act
+	err := ing.IngestBatch(context.Background(), recs)
+
+	// Assert
+	require.NoError(t, err, "IngestBatch() error should be nil")
+}
+
+func TestRecordIngestor_IngestBatch_StoreFailure(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	storage := storagemocks.NewMockStorage(ctrl)
+	storage.EXPECT().Get(gomock.Any(), gomock.Any()).Return(records.Record{}, errors.New("not found")).AnyTimes()
+	storage.EXPECT().StoreBatch(gomock.Any(), gomock.Any()).Return(errors.New("disk full"))
+	ing := ingestor.NewRecordIngestor(storage, kbmocks.NewMockVectorStorage(ctrl), ingestor.BatchConfig{})
+
+	// Act
+	err := ing.IngestBatch(context.Background(), []records.Record{{ID: "a"}})
+
+	// Assert
+	assert.Error(t, err, "IngestBatch() should fail without indexing when the batch fails to be stored")
+}
+
+// BenchmarkRecordIngestor_Ingest measures ingesting extracted records into
+// SQLite and the local vector store, without OCR or model calls
+func BenchmarkRecordIngestor_Ingest(b *testing.B) {
+	for _, size := range []int{1, 32} {
+		b.Run(fmt.Sprintf("batch=%d", size), func(b *testing.B) {
+			recordStorage, err := storage.NewSQLiteStorage(openDB(b, filepath.Join(b.TempDir(), "records.db")))
+			if err != nil {
+				b.Fatalf("NewSQLiteStorage() failed: %v", err)
+			}
+			ing := ingestor.NewRecordIngestor(recordStorage, knowledgebase.NewLocalVectorStorage(), ingestor.BatchConfig{Size: size})
+			ctx := context.Background()
+
+			b.ResetTimer()
+			for i := range b.N {
+				rec := records.Record{ID: fmt.Sprintf("rec-%d", i), Type: records.RecordTypeReceipt, Content: fmt.Sprintf("Receipt %d for groceries", i)}
+				if err := ing.Ingest(ctx, rec); err != nil {
+					b.Fatalf("Ingest() failed: %v", err)
+				}
+			}
+			if err := ing.Flush(ctx); err != nil {
+				b.Fatalf("Flush() failed: %v", err)
+			}
+		})
+	}
+}
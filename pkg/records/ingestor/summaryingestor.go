@@ -0,0 +1,66 @@
+package ingestor
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/kazemisoroush/assistant/pkg/ai"
+	"github.com/kazemisoroush/assistant/pkg/records"
+	"github.com/kazemisoroush/assistant/pkg/summaries"
+)
+
+// SummaryIngestor summarizes every ingested record without a description
+// before it is stored, so the summary is indexed with its content. Records
+// are ingested without a summary when summarization fails.
+type SummaryIngestor struct {
+	ingestor   Ingestor
+	summarizer summaries.Summarizer
+}
+
+// NewSummaryIngestor wraps an ingestor with record summarization
+func NewSummaryIngestor(ingestor Ingestor, summarizer summaries.Summarizer) Ingestor {
+	return &SummaryIngestor{
+		ingestor:   ingestor,
+		summarizer: summarizer,
+	}
+}
+
+// Ingest implements Ingestor
+func (s *SummaryIngestor) Ingest(ctx context.Context, record records.Record) error {
+	return s.ingestor.Ingest(ctx, s.describe(ctx, record))
+}
+
+// IngestBatch implements Ingestor
+func (s *SummaryIngestor) IngestBatch(ctx context.Context, recs []records.Record) error {
+	described := make([]records.Record, len(recs))
+	for i, record := range recs {
+		described[i] = s.describe(ctx, record)
+	}
+	return s.ingestor.IngestBatch(ctx, described)
+}
+
+// describe summarizes a record without a description
+func (s *SummaryIngestor) describe(ctx context.Context, record records.Record) records.Record {
+	if record.Description() != "" {
+		return record
+	}
+	description, err := s.summarizer.Summarize(ctx, record)
+	if err != nil && !errors.Is(err, ai.ErrRecordTypeNotAllowed) {
+		slog.WarnContext(ctx, "Failed to summarize record", "record_id", record.ID, "error", err)
+	}
+	if description != "" {
+		record = summaries.Describe(record, description)
+	}
+	return record
+}
+
+// Delete implements Ingestor
+func (s *SummaryIngestor) Delete(ctx context.Context, id string) error {
+	return s.ingestor.Delete(ctx, id)
+}
+
+// Flush implements Ingestor
+func (s *SummaryIngestor) Flush(ctx context.Context) error {
+	return s.ingestor.Flush(ctx)
+}
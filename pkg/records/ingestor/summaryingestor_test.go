@@ -0,0 +1,67 @@
+package ingestor_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/kazemisoroush/assistant/pkg/records"
+	"github.com/kazemisoroush/assistant/pkg/records/ingestor"
+	"github.com/kazemisoroush/assistant/pkg/records/ingestor/mocks"
+	summarymocks "github.com/kazemisoroush/assistant/pkg/summaries/mocks"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestSummaryIngestor_Ingest_StoresSummary(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	inner := mocks.NewMockService(ctrl)
+	summarizer := summarymocks.NewMockSummarizer(ctrl)
+	rec := records.Record{ID: "lease", Type: records.RecordTypeHome, Content: "Lease agreement ..."}
+	summarizer.EXPECT().Summarize(gomock.Any(), rec).Return("Lease of the Berlin flat from 2024.", nil)
+	var stored records.Record
+	inner.EXPECT().Ingest(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, r records.Record) error {
+		stored = r
+		return nil
+	})
+
+	// Act
+	err := ingestor.NewSummaryIngestor(inner, summarizer).Ingest(context.Background(), rec)
+
+	// Assert
+	assert.NoError(t, err, "Ingest() error should be nil")
+	assert.Equal(t, "Lease of the Berlin flat from 2024.", stored.Description(), "Ingest() should store the summary as the description")
+	assert.Empty(t, rec.Description(), "Ingest() should not modify the caller's metadata")
+}
+
+func TestSummaryIngestor_Ingest_KeepsExistingDescription(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	inner := mocks.NewMockService(ctrl)
+	summarizer := summarymocks.NewMockSummarizer(ctrl)
+	rec := records.Record{ID: "lease", Metadata: map[string]interface{}{records.MetaDescription: "Lease of the Berlin flat."}}
+	inner.EXPECT().Ingest(gomock.Any(), rec).Return(nil)
+
+	// Act
+	err := ingestor.NewSummaryIngestor(inner, summarizer).Ingest(context.Background(), rec)
+
+	// Assert
+	assert.NoError(t, err, "Ingest() should not re-summarize described records")
+}
+
+func TestSummaryIngestor_Ingest_FailureKeepsRecord(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	inner := mocks.NewMockService(ctrl)
+	summarizer := summarymocks.NewMockSummarizer(ctrl)
+	rec := records.Record{ID: "lease", Content: "Lease agreement ..."}
+	summarizer.EXPECT().Summarize(gomock.Any(), rec).Return("", errors.New("model unavailable"))
+	inner.EXPECT().Ingest(gomock.Any(), rec).Return(nil)
+
+	// Act
+	err := ingestor.NewSummaryIngestor(inner, summarizer).Ingest(context.Background(), rec)
+
+	// Assert
+	assert.NoError(t, err, "Ingest() should ingest the record without a summary")
+}
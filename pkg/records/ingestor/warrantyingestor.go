@@ -0,0 +1,108 @@
+package ingestor
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/kazemisoroush/assistant/pkg/records"
+)
+
+// warrantyIDPrefix prefixes the ID of a warranty derived from a purchase receipt
+const warrantyIDPrefix = "warranty-"
+
+// WarrantyIngestor ingests, next to every receipt stating a warranty, a
+// warranty record that expires when the warranty ends and links to the
+// purchase, so the reminders engine and warranty lookups pick it up.
+type WarrantyIngestor struct {
+	ingestor Ingestor
+}
+
+// NewWarrantyIngestor wraps an ingestor with warranty derivation
+func NewWarrantyIngestor(ingestor Ingestor) Ingestor {
+	return &WarrantyIngestor{
+		ingestor: ingestor,
+	}
+}
+
+// Ingest implements Ingestor
+func (w *WarrantyIngestor) Ingest(ctx context.Context, record records.Record) error {
+	if err := w.ingestor.Ingest(ctx, record); err != nil {
+		return err
+	}
+
+	warranty, ok := warrantyFor(record)
+	if !ok {
+		return nil
+	}
+	if err := w.ingestor.Ingest(ctx, warranty); err != nil {
+		return fmt.Errorf("failed to ingest warranty of record %s: %w", record.ID, err)
+	}
+	return nil
+}
+
+// IngestBatch implements Ingestor. The warranties derived from the receipts
+// are ingested in the same batch.
+func (w *WarrantyIngestor) IngestBatch(ctx context.Context, recs []records.Record) error {
+	batch := slices.Clone(recs)
+	for _, rec := range recs {
+		if warranty, ok := warrantyFor(rec); ok {
+			batch = append(batch, warranty)
+		}
+	}
+	return w.ingestor.IngestBatch(ctx, batch)
+}
+
+// Delete implements Ingestor. Warranties derived from a receipt are not
+// deleted with it; they stay until deleted themselves.
+func (w *WarrantyIngestor) Delete(ctx context.Context, id string) error {
+	return w.ingestor.Delete(ctx, id)
+}
+
+// Flush implements Ingestor
+func (w *WarrantyIngestor) Flush(ctx context.Context) error {
+	return w.ingestor.Flush(ctx)
+}
+
+// warrantyFor derives the warranty record of a receipt stating a warranty duration
+func warrantyFor(rec records.Record) (records.Record, bool) {
+	if rec.Type != records.RecordTypeReceipt {
+		return records.Record{}, false
+	}
+	var receipt records.ReceiptMetadata
+	if err := rec.DecodeMetadata(&receipt); err != nil || receipt.WarrantyMonths <= 0 {
+		return records.Record{}, false
+	}
+	purchased, err := time.Parse(time.DateOnly, receipt.Date)
+	if err != nil {
+		return records.Record{}, false
+	}
+
+	product := receipt.Product
+	if product == "" {
+		product = "purchase from " + receipt.Merchant
+	}
+	expiresAt := purchased.AddDate(0, receipt.WarrantyMonths, 0)
+
+	return records.Record{
+		ID:   warrantyIDPrefix + rec.ID,
+		Type: records.RecordTypeWarranty,
+		Content: strings.Join([]string{
+			fmt.Sprintf("Warranty for %s bought at %s on %s.", product, receipt.Merchant, receipt.Date),
+			fmt.Sprintf("Covered for %d months, until %s.", receipt.WarrantyMonths, expiresAt.Format(time.DateOnly)),
+		}, " "),
+		CreatedAt: rec.CreatedAt,
+		UpdatedAt: rec.UpdatedAt,
+		Metadata: map[string]interface{}{
+			"document_type":   product + " warranty",
+			"product":         product,
+			"merchant":        receipt.Merchant,
+			"purchase_date":   receipt.Date,
+			"warranty_months": receipt.WarrantyMonths,
+			records.MetaLinks: []string{rec.ID},
+		},
+		ExpiresAt: &expiresAt,
+	}, true
+}
@@ -0,0 +1,64 @@
+package ingestor_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/kazemisoroush/assistant/pkg/records"
+	"github.com/kazemisoroush/assistant/pkg/records/ingestor"
+	"github.com/kazemisoroush/assistant/pkg/records/ingestor/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestWarrantyIngestor_Ingest_DerivesWarranty(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	inner := mocks.NewMockService(ctrl)
+	receipt := records.Record{
+		ID:   "ocr-1",
+		Type: records.RecordTypeReceipt,
+		Metadata: map[string]interface{}{
+			"merchant":        "Media Markt",
+			"date":            "2024-03-15",
+			"total":           999.0,
+			"currency":        "EUR",
+			"product":         "laptop",
+			"warranty_months": 24.0,
+		},
+	}
+	var warranty records.Record
+	gomock.InOrder(
+		inner.EXPECT().Ingest(gomock.Any(), receipt).Return(nil),
+		inner.EXPECT().Ingest(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, rec records.Record) error {
+			warranty = rec
+			return nil
+		}),
+	)
+
+	// Act
+	err := ingestor.NewWarrantyIngestor(inner).Ingest(context.Background(), receipt)
+
+	// Assert
+	require.NoError(t, err, "Ingest() error should be nil")
+	assert.Equal(t, records.RecordTypeWarranty, warranty.Type, "Ingest() should derive a warranty record")
+	assert.Equal(t, []string{"ocr-1"}, warranty.Links(), "Ingest() should link the warranty to the purchase")
+	require.NotNil(t, warranty.ExpiresAt, "Ingest() should set the warranty expiry")
+	assert.Equal(t, time.Date(2026, time.March, 15, 0, 0, 0, 0, time.UTC), *warranty.ExpiresAt, "Ingest() should add the warranty duration to the purchase date")
+}
+
+func TestWarrantyIngestor_Ingest_ReceiptWithoutWarranty(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	inner := mocks.NewMockService(ctrl)
+	receipt := records.Record{ID: "ocr-2", Type: records.RecordTypeReceipt, Metadata: map[string]interface{}{"date": "2024-03-15"}}
+	inner.EXPECT().Ingest(gomock.Any(), receipt).Return(nil).Times(1)
+
+	// Act
+	err := ingestor.NewWarrantyIngestor(inner).Ingest(context.Background(), receipt)
+
+	// Assert
+	assert.NoError(t, err, "Ingest() should only ingest the receipt itself")
+}
@@ -0,0 +1,42 @@
+package knowledgebase
+
+import "context"
+
+// BatchingEmbedder splits EmbedBatch calls into chunks no larger than the batch size
+type BatchingEmbedder struct {
+	embedder  Embedder
+	batchSize int
+}
+
+// NewBatchingEmbedder wraps an embedder so batch requests never exceed batchSize texts
+func NewBatchingEmbedder(embedder Embedder, batchSize int) Embedder {
+	return &BatchingEmbedder{
+		embedder:  embedder,
+		batchSize: batchSize,
+	}
+}
+
+// Embed generates embeddings for text
+func (b *BatchingEmbedder) Embed(ctx context.Context, text string) ([]float32, error) {
+	return b.embedder.Embed(ctx, text)
+}
+
+// EmbedBatch generates embeddings for multiple texts, one chunk at a time
+func (b *BatchingEmbedder) EmbedBatch(ctx context.Context, texts []string) ([][]float32, error) {
+	embeddings := make([][]float32, 0, len(texts))
+	for start := 0; start < len(texts); start += b.batchSize {
+		end := min(start+b.batchSize, len(texts))
+
+		chunk, err := b.embedder.EmbedBatch(ctx, texts[start:end])
+		if err != nil {
+			return nil, err
+		}
+		embeddings = append(embeddings, chunk...)
+	}
+	return embeddings, nil
+}
+
+// Dimensions returns the dimension of the embedding vectors
+func (b *BatchingEmbedder) Dimensions() int {
+	return b.embedder.Dimensions()
+}
@@ -0,0 +1,29 @@
+package knowledgebase
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kazemisoroush/assistant/pkg/records/knowledgebase/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestBatchingEmbedder_EmbedBatch_SplitsIntoChunks(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	inner := mocks.NewMockEmbedder(ctrl)
+	gomock.InOrder(
+		inner.EXPECT().EmbedBatch(gomock.Any(), []string{"a", "b"}).Return([][]float32{{1}, {2}}, nil),
+		inner.EXPECT().EmbedBatch(gomock.Any(), []string{"c"}).Return([][]float32{{3}}, nil),
+	)
+	embedder := NewBatchingEmbedder(inner, 2)
+
+	// Act
+	embeddings, err := embedder.EmbedBatch(context.Background(), []string{"a", "b", "c"})
+
+	// Assert
+	require.NoError(t, err, "EmbedBatch() error should be nil")
+	assert.Equal(t, [][]float32{{1}, {2}, {3}}, embeddings, "EmbedBatch() should preserve input order")
+}
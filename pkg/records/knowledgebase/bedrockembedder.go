@@ -0,0 +1,170 @@
+package knowledgebase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockruntime"
+)
+
+const (
+	// EmbedderProviderBedrock is the provider name of the BedrockEmbedder
+	EmbedderProviderBedrock = "bedrock"
+
+	// DefaultBedrockEmbeddingModel is the model the BedrockEmbedder uses when none is configured
+	DefaultBedrockEmbeddingModel = "amazon.titan-embed-text-v2:0"
+
+	// cohereMaxBatch is the most texts a Cohere embedding model accepts per request
+	cohereMaxBatch = 96
+)
+
+// titanV2Dimensions are the output sizes Titan Text Embeddings V2 can be asked for
+var titanV2Dimensions = []int{256, 512, 1024}
+
+// BedrockInvoker is the subset of the Bedrock runtime API used by BedrockEmbedder
+type BedrockInvoker interface {
+	InvokeModel(ctx context.Context, params *bedrockruntime.InvokeModelInput, optFns ...func(*bedrockruntime.Options)) (*bedrockruntime.InvokeModelOutput, error)
+}
+
+// BedrockEmbedder embeds texts with a Titan or Cohere embedding model on AWS
+// Bedrock. Cohere models embed a batch in one request; Titan models take one
+// text per request.
+type BedrockEmbedder struct {
+	client     BedrockInvoker
+	model      string
+	dimensions int
+}
+
+// NewBedrockEmbedder creates an embedder of the Bedrock model. The dimensions
+// of known models are detected from their ID; Titan V2 is asked for the
+// given dimensions when it supports them, and unknown models are trusted to
+// return them.
+func NewBedrockEmbedder(client BedrockInvoker, model string, dimensions int) Embedder {
+	if model == "" {
+		model = DefaultBedrockEmbeddingModel
+	}
+	return &BedrockEmbedder{
+		client:     client,
+		model:      model,
+		dimensions: bedrockDimensions(model, dimensions),
+	}
+}
+
+// bedrockDimensions returns the dimensions of the embeddings of the model
+func bedrockDimensions(model string, dimensions int) int {
+	switch {
+	case strings.Contains(model, "titan-embed-text-v2"):
+		for _, supported := range titanV2Dimensions {
+			if dimensions == supported {
+				return dimensions
+			}
+		}
+		return 1024
+	case strings.Contains(model, "titan-embed-text-v1"), strings.Contains(model, "titan-embed-g1-text"):
+		return 1536
+	case strings.Contains(model, "cohere.embed-english-v3"), strings.Contains(model, "cohere.embed-multilingual-v3"):
+		return 1024
+	default:
+		return dimensions
+	}
+}
+
+// Embed implements Embedder
+func (e *BedrockEmbedder) Embed(ctx context.Context, text string) ([]float32, error) {
+	embeddings, err := e.EmbedBatch(ctx, []string{text})
+	if err != nil {
+		return nil, err
+	}
+	return embeddings[0], nil
+}
+
+// EmbedBatch implements Embedder
+func (e *BedrockEmbedder) EmbedBatch(ctx context.Context, texts []string) ([][]float32, error) {
+	embeddings := make([][]float32, 0, len(texts))
+	if e.cohere() {
+		for start := 0; start < len(texts); start += cohereMaxBatch {
+			chunk, err := e.embedCohere(ctx, texts[start:min(start+cohereMaxBatch, len(texts))])
+			if err != nil {
+				return nil, err
+			}
+			embeddings = append(embeddings, chunk...)
+		}
+	} else {
+		for _, text := range texts {
+			embedding, err := e.embedTitan(ctx, text)
+			if err != nil {
+				return nil, err
+			}
+			embeddings = append(embeddings, embedding)
+		}
+	}
+	for _, embedding := range embeddings {
+		if len(embedding) != e.dimensions {
+			return nil, fmt.Errorf("model %s returned %d dimensions, configured for %d", e.model, len(embedding), e.dimensions)
+		}
+	}
+	return embeddings, nil
+}
+
+// Dimensions implements Embedder
+func (e *BedrockEmbedder) Dimensions() int {
+	return e.dimensions
+}
+
+// cohere reports whether the model is a Cohere embedding model
+func (e *BedrockEmbedder) cohere() bool {
+	return strings.Contains(e.model, "cohere.embed")
+}
+
+// embedTitan embeds a text with a Titan model
+func (e *BedrockEmbedder) embedTitan(ctx context.Context, text string) ([]float32, error) {
+	request := map[string]any{"inputText": text}
+	if strings.Contains(e.model, "titan-embed-text-v2") {
+		request["dimensions"] = e.dimensions
+	}
+	var result struct {
+		Embedding []float32 `json:"embedding"`
+	}
+	if err := e.invoke(ctx, request, &result); err != nil {
+		return nil, err
+	}
+	return result.Embedding, nil
+}
+
+// embedCohere embeds a batch of texts with a Cohere model
+func (e *BedrockEmbedder) embedCohere(ctx context.Context, texts []string) ([][]float32, error) {
+	var result struct {
+		Embeddings [][]float32 `json:"embeddings"`
+	}
+	if err := e.invoke(ctx, map[string]any{"texts": texts, "input_type": "search_document"}, &result); err != nil {
+		return nil, err
+	}
+	if len(result.Embeddings) != len(texts) {
+		return nil, fmt.Errorf("bedrock returned %d embeddings for %d texts", len(result.Embeddings), len(texts))
+	}
+	return result.Embeddings, nil
+}
+
+// invoke calls the model with the request, decoding its response into result
+func (e *BedrockEmbedder) invoke(ctx context.Context, request any, result any) error {
+	body, err := json.Marshal(request)
+	if err != nil {
+		return fmt.Errorf("failed to marshal embedding request: %w", err)
+	}
+	output, err := e.client.InvokeModel(ctx, &bedrockruntime.InvokeModelInput{
+		ModelId:     aws.String(e.model),
+		ContentType: aws.String("application/json"),
+		Accept:      aws.String("application/json"),
+		Body:        body,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to call Bedrock InvokeModel API: %w", err)
+	}
+	if err := json.Unmarshal(output.Body, result); err != nil {
+		return fmt.Errorf("failed to decode Bedrock embedding response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,120 @@
+package knowledgebase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/bedrockruntime"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeInvoker answers InvokeModel calls with embeddings of the given
+// dimensions, recording the request bodies
+type fakeInvoker struct {
+	dimensions int
+	requests   []map[string]any
+	err        error
+}
+
+// InvokeModel implements BedrockInvoker
+func (f *fakeInvoker) InvokeModel(_ context.Context, params *bedrockruntime.InvokeModelInput, _ ...func(*bedrockruntime.Options)) (*bedrockruntime.InvokeModelOutput, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	var request map[string]any
+	if err := json.Unmarshal(params.Body, &request); err != nil {
+		return nil, err
+	}
+	f.requests = append(f.requests, request)
+
+	var response any = map[string]any{"embedding": make([]float32, f.dimensions)}
+	if texts, ok := request["texts"].([]any); ok {
+		embeddings := make([][]float32, len(texts))
+		for i := range embeddings {
+			embeddings[i] = make([]float32, f.dimensions)
+		}
+		response = map[string]any{"embeddings": embeddings}
+	}
+	body, err := json.Marshal(response)
+	return &bedrockruntime.InvokeModelOutput{Body: body}, err
+}
+
+func TestBedrockEmbedder_EmbedBatch_Titan(t *testing.T) {
+	// Arrange
+	client := &fakeInvoker{dimensions: 512}
+	embedder := NewBedrockEmbedder(client, "", 512)
+
+	// Act
+	embeddings, err := embedder.EmbedBatch(context.Background(), []string{"first", "second"})
+
+	// Assert
+	require.NoError(t, err, "EmbedBatch() error should be nil")
+	assert.Len(t, embeddings, 2, "EmbedBatch() should return an embedding per text")
+	require.Len(t, client.requests, 2, "EmbedBatch() should send Titan one text per request")
+	assert.Equal(t, "second", client.requests[1]["inputText"], "EmbedBatch() should send the texts in order")
+	assert.InDelta(t, 512, client.requests[0]["dimensions"], 0, "EmbedBatch() should ask Titan V2 for the dimensions")
+}
+
+func TestBedrockEmbedder_EmbedBatch_CohereBatches(t *testing.T) {
+	// Arrange
+	client := &fakeInvoker{dimensions: 1024}
+	embedder := NewBedrockEmbedder(client, "cohere.embed-english-v3", 0)
+	texts := make([]string, cohereMaxBatch+1)
+
+	// Act
+	embeddings, err := embedder.EmbedBatch(context.Background(), texts)
+
+	// Assert
+	require.NoError(t, err, "EmbedBatch() error should be nil")
+	assert.Len(t, embeddings, len(texts), "EmbedBatch() should return an embedding per text")
+	assert.Len(t, client.requests, 2, "EmbedBatch() should split batches at the Cohere limit")
+}
+
+func TestBedrockEmbedder_Dimensions(t *testing.T) {
+	tests := []struct {
+		name       string
+		model      string
+		dimensions int
+		want       int
+	}{
+		{name: "titan v2 supported", model: "amazon.titan-embed-text-v2:0", dimensions: 256, want: 256},
+		{name: "titan v2 unsupported", model: "amazon.titan-embed-text-v2:0", dimensions: 100, want: 1024},
+		{name: "titan v1", model: "amazon.titan-embed-text-v1", dimensions: 100, want: 1536},
+		{name: "cohere", model: "cohere.embed-multilingual-v3", dimensions: 100, want: 1024},
+		{name: "unknown", model: "custom.embed", dimensions: 384, want: 384},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			embedder := NewBedrockEmbedder(&fakeInvoker{}, tt.model, tt.dimensions)
+
+			// Assert
+			assert.Equal(t, tt.want, embedder.Dimensions(), "Dimensions() should detect the dimensions of the model")
+		})
+	}
+}
+
+func TestBedrockEmbedder_Embed_Errors(t *testing.T) {
+	tests := []struct {
+		name   string
+		client *fakeInvoker
+	}{
+		{name: "invoke fails", client: &fakeInvoker{err: errors.New("throttled")}},
+		{name: "dimension mismatch", client: &fakeInvoker{dimensions: 8}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			embedder := NewBedrockEmbedder(tt.client, "", 1024)
+
+			// Act
+			_, err := embedder.Embed(context.Background(), "text")
+
+			// Assert
+			require.Error(t, err, "Embed() should fail")
+		})
+	}
+}
@@ -0,0 +1,66 @@
+package knowledgebase
+
+import (
+	"context"
+	"errors"
+
+	"github.com/kazemisoroush/assistant/pkg/breaker"
+	"github.com/kazemisoroush/assistant/pkg/records"
+)
+
+// BreakerVectorStorage stops calling a remote vector store that keeps failing,
+// failing fast with breaker.ErrOpen until the circuit half-opens
+type BreakerVectorStorage struct {
+	storage VectorStorage
+	breaker *breaker.Breaker
+}
+
+// NewBreakerVectorStorage wraps a vector store with a circuit breaker
+func NewBreakerVectorStorage(storage VectorStorage, b *breaker.Breaker) VectorStorage {
+	return &BreakerVectorStorage{
+		storage: storage,
+		breaker: b,
+	}
+}
+
+// Index adds the record unless the circuit is open
+func (b *BreakerVectorStorage) Index(ctx context.Context, rec records.Record) error {
+	return b.call(func() error {
+		return b.storage.Index(ctx, rec)
+	})
+}
+
+// IndexBatch adds the records unless the circuit is open
+func (b *BreakerVectorStorage) IndexBatch(ctx context.Context, recs []records.Record) error {
+	return b.call(func() error {
+		return b.storage.IndexBatch(ctx, recs)
+	})
+}
+
+// Search searches the store unless the circuit is open
+func (b *BreakerVectorStorage) Search(ctx context.Context, prompt string, limit int) ([]records.SearchResult, error) {
+	var results []records.SearchResult
+	err := b.call(func() error {
+		var err error
+		results, err = b.storage.Search(ctx, prompt, limit)
+		return err
+	})
+	return results, err
+}
+
+// Delete removes the record unless the circuit is open
+func (b *BreakerVectorStorage) Delete(ctx context.Context, recID string) error {
+	return b.call(func() error {
+		return b.storage.Delete(ctx, recID)
+	})
+}
+
+// call records every error except cancellation by the caller as a failure
+func (b *BreakerVectorStorage) call(fn func() error) error {
+	if err := b.breaker.Allow(); err != nil {
+		return err
+	}
+	err := fn()
+	b.breaker.Record(err != nil && !errors.Is(err, context.Canceled))
+	return err
+}
@@ -0,0 +1,243 @@
+package knowledgebase
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/kazemisoroush/assistant/pkg/records"
+)
+
+// ChromaConfig represents the Chroma server and collection of the chroma backend
+type ChromaConfig struct {
+	URL        string
+	Token      string // Sent as a bearer token when set
+	Tenant     string // "default_tenant" when empty
+	Database   string // "default_database" when empty
+	Collection string // "records" when empty
+}
+
+// Chroma defaults of the configuration
+const (
+	defaultChromaTenant     = "default_tenant"
+	defaultChromaDatabase   = "default_database"
+	defaultChromaCollection = "records"
+)
+
+// ChromaVectorStorage keeps record embeddings in a collection of a Chroma
+// server, created with cosine distance when missing. Records are embedded
+// by the embedder, or by hashing their terms when there is none, and stored
+// with their type, creation time and scalar metadata, which SearchWhere
+// filters on. The records of the results are loaded from the record storage.
+type ChromaVectorStorage struct {
+	client     *http.Client
+	base       string // URL of the collections of the database
+	token      string
+	collection string // ID of the collection
+	records    RecordGetter
+	embedder   Embedder // nil to hash terms
+	dims       int
+}
+
+// NewChromaVectorStorage creates a vector store on the Chroma collection of
+// the configuration, creating the collection when it does not exist
+func NewChromaVectorStorage(ctx context.Context, client *http.Client, cfg ChromaConfig, embedder Embedder, recordGetter RecordGetter) (*ChromaVectorStorage, error) {
+	if cfg.URL == "" {
+		return nil, fmt.Errorf("the chroma vector storage backend requires a Chroma URL")
+	}
+	tenant, database, name := cfg.Tenant, cfg.Database, cfg.Collection
+	if tenant == "" {
+		tenant = defaultChromaTenant
+	}
+	if database == "" {
+		database = defaultChromaDatabase
+	}
+	if name == "" {
+		name = defaultChromaCollection
+	}
+	dims := vectorSize
+	if embedder != nil {
+		dims = embedder.Dimensions()
+	}
+
+	s := &ChromaVectorStorage{
+		client:   client,
+		base:     fmt.Sprintf("%s/api/v2/tenants/%s/databases/%s/collections", strings.TrimSuffix(cfg.URL, "/"), url.PathEscape(tenant), url.PathEscape(database)),
+		token:    cfg.Token,
+		records:  recordGetter,
+		embedder: embedder,
+		dims:     dims,
+	}
+	var collection struct {
+		ID string `json:"id"`
+	}
+	if err := s.post(ctx, "", map[string]any{
+		"name":          name,
+		"get_or_create": true,
+		"metadata":      map[string]any{"hnsw:space": "cosine"},
+	}, &collection); err != nil {
+		return nil, fmt.Errorf("failed to create Chroma collection %s: %w", name, err)
+	}
+	s.collection = collection.ID
+	return s, nil
+}
+
+// Index implements VectorStorage
+func (s *ChromaVectorStorage) Index(ctx context.Context, rec records.Record) error {
+	return s.IndexBatch(ctx, []records.Record{rec})
+}
+
+// IndexBatch implements VectorStorage, upserting the embeddings of every
+// record in one request. No record is indexed when one of them has no ID.
+func (s *ChromaVectorStorage) IndexBatch(ctx context.Context, recs []records.Record) error {
+	ids := make([]string, 0, len(recs))
+	texts := make([]string, 0, len(recs))
+	metadatas := make([]map[string]any, 0, len(recs))
+	for _, rec := range recs {
+		if rec.ID == "" {
+			return fmt.Errorf("record ID is required")
+		}
+		ids = append(ids, rec.ID)
+		texts = append(texts, rec.IndexText())
+		metadatas = append(metadatas, chromaMetadata(rec))
+	}
+	vectors, err := embedVectors(ctx, s.embedder, s.dims, texts)
+	if err != nil {
+		return err
+	}
+	embeddings := make([][]float32, len(recs))
+	for i := range embeddings {
+		embeddings[i] = vectors[i*s.dims : (i+1)*s.dims]
+	}
+
+	if err := s.post(ctx, "/"+s.collection+"/upsert", map[string]any{
+		"ids":        ids,
+		"embeddings": embeddings,
+		"metadatas":  metadatas,
+	}, nil); err != nil {
+		return fmt.Errorf("failed to upsert embeddings: %w", err)
+	}
+	return nil
+}
+
+// Search implements VectorStorage
+func (s *ChromaVectorStorage) Search(ctx context.Context, prompt string, limit int) ([]records.SearchResult, error) {
+	return s.SearchWhere(ctx, prompt, limit, nil)
+}
+
+// SearchWhere searches the records whose metadata match the Chroma where
+// filter, such as {"type": "receipt"}; a nil filter matches every record.
+// Records are scored by their cosine similarity with the prompt.
+func (s *ChromaVectorStorage) SearchWhere(ctx context.Context, prompt string, limit int, where map[string]any) ([]records.SearchResult, error) {
+	query, err := embedVectors(ctx, s.embedder, s.dims, []string{prompt})
+	if err != nil {
+		return nil, err
+	}
+	if limit <= 0 {
+		limit = defaultSearchResults
+	}
+	request := map[string]any{
+		"query_embeddings": [][]float32{query},
+		"n_results":        limit,
+		"include":          []string{"distances"},
+	}
+	if len(where) > 0 {
+		request["where"] = where
+	}
+	var response struct {
+		IDs       [][]string  `json:"ids"`
+		Distances [][]float64 `json:"distances"`
+	}
+	if err := s.post(ctx, "/"+s.collection+"/query", request, &response); err != nil {
+		return nil, fmt.Errorf("failed to query Chroma: %w", err)
+	}
+	if len(response.IDs) == 0 || len(response.Distances) == 0 || len(response.Distances[0]) != len(response.IDs[0]) {
+		return []records.SearchResult{}, nil
+	}
+	return s.results(ctx, response.IDs[0], response.Distances[0]), nil
+}
+
+// results loads the records of the query results closer than the maximum
+// cosine distance, scored by their similarity
+func (s *ChromaVectorStorage) results(ctx context.Context, ids []string, distances []float64) []records.SearchResult {
+	results := make([]records.SearchResult, 0, len(ids))
+	for i, id := range ids {
+		score := 1 - distances[i]
+		if score <= 0 {
+			continue
+		}
+		rec, err := s.records.Get(ctx, id)
+		if err != nil {
+			slog.WarnContext(ctx, "Skipping indexed record that failed to load", "record_id", id, "error", err)
+			continue
+		}
+		results = append(results, records.SearchResult{Record: rec, Score: score})
+	}
+	return results
+}
+
+// Delete implements VectorStorage
+func (s *ChromaVectorStorage) Delete(ctx context.Context, recID string) error {
+	if err := s.post(ctx, "/"+s.collection+"/delete", map[string]any{"ids": []string{recID}}, nil); err != nil {
+		return fmt.Errorf("failed to delete embedding of record %s: %w", recID, err)
+	}
+	return nil
+}
+
+// post sends the request body as JSON to the path under the collections,
+// decoding the response into v unless it is nil
+func (s *ChromaVectorStorage) post(ctx context.Context, path string, body any, v any) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("failed to marshal request: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.base+path, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if s.token != "" {
+		req.Header.Set("Authorization", "Bearer "+s.token)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to call Chroma: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("chroma returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(message)))
+	}
+	if v == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("failed to decode Chroma response: %w", err)
+	}
+	return nil
+}
+
+// chromaMetadata returns the metadata a record is stored with: its type,
+// creation time as Unix seconds and the metadata fields Chroma can hold
+func chromaMetadata(rec records.Record) map[string]any {
+	metadata := map[string]any{"type": string(rec.Type)}
+	if !rec.CreatedAt.IsZero() {
+		metadata["created_at"] = rec.CreatedAt.Unix()
+	}
+	for key, value := range rec.Metadata {
+		switch value.(type) {
+		case string, bool, float64, int, int64:
+			if _, reserved := metadata[key]; !reserved {
+				metadata[key] = value
+			}
+		}
+	}
+	return metadata
+}
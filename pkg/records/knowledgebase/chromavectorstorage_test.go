@@ -0,0 +1,107 @@
+package knowledgebase
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/kazemisoroush/assistant/pkg/records"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// chromaServer is a Chroma server keeping one collection in memory
+type chromaServer struct {
+	requests map[string]map[string]any // Last request body of each operation
+	ids      []string
+}
+
+// newChromaServer serves the collection API of the default tenant and database
+func newChromaServer(t *testing.T) (*chromaServer, *httptest.Server) {
+	t.Helper()
+	chroma := &chromaServer{requests: map[string]map[string]any{}}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		const collections = "/api/v2/tenants/default_tenant/databases/default_database/collections"
+		if r.Method != http.MethodPost || !strings.HasPrefix(r.URL.Path, collections) || r.Header.Get("Authorization") != "Bearer secret" {
+			http.Error(w, "unexpected request", http.StatusBadRequest)
+			return
+		}
+		var body map[string]any
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body), "request body should be JSON")
+		operation := strings.TrimPrefix(strings.TrimPrefix(r.URL.Path, collections), "/collection-id/")
+		chroma.requests[operation] = body
+
+		switch operation {
+		case "":
+			_ = json.NewEncoder(w).Encode(map[string]any{"id": "collection-id", "name": body["name"]})
+		case "upsert":
+			for _, id := range body["ids"].([]any) {
+				chroma.ids = append(chroma.ids, id.(string))
+			}
+			_, _ = w.Write([]byte("{}"))
+		case "query":
+			distances := make([]float64, len(chroma.ids))
+			distances[len(distances)-1] = 1
+			_ = json.NewEncoder(w).Encode(map[string]any{"ids": [][]string{chroma.ids}, "distances": [][]float64{distances}})
+		default:
+			_, _ = w.Write([]byte("{}"))
+		}
+	}))
+	t.Cleanup(server.Close)
+	return chroma, server
+}
+
+func TestChromaVectorStorage_IndexAndSearchWhere(t *testing.T) {
+	// Arrange
+	chroma, server := newChromaServer(t)
+	recs := recordMap{
+		"receipt": {ID: "receipt", Type: records.RecordTypeReceipt, Content: "Coffee", Metadata: map[string]any{"merchant": "Cafe", "items": []any{"latte"}}},
+		"visa":    {ID: "visa", Type: records.RecordTypeVisa, Content: "Visa"},
+	}
+	ctx := context.Background()
+	store, err := NewChromaVectorStorage(ctx, server.Client(), ChromaConfig{URL: server.URL, Token: "secret"}, nil, recs)
+	require.NoError(t, err, "NewChromaVectorStorage() error should be nil")
+	require.NoError(t, store.IndexBatch(ctx, []records.Record{recs["receipt"], recs["visa"]}), "IndexBatch() error should be nil")
+
+	// Act
+	results, err := store.SearchWhere(ctx, "coffee", 5, map[string]any{"type": "receipt"})
+
+	// Assert
+	require.NoError(t, err, "SearchWhere() error should be nil")
+	require.Len(t, results, 1, "SearchWhere() should drop results at the maximum distance")
+	assert.Equal(t, "receipt", results[0].Record.ID, "SearchWhere() should load the records of the results")
+	assert.Equal(t, "records", chroma.requests[""]["name"], "NewChromaVectorStorage() should default the collection")
+	metadata := chroma.requests["upsert"]["metadatas"].([]any)[0].(map[string]any)
+	assert.Equal(t, map[string]any{"type": "receipt", "merchant": "Cafe"}, metadata, "IndexBatch() should store the scalar metadata")
+	assert.Equal(t, map[string]any{"type": "receipt"}, chroma.requests["query"]["where"], "SearchWhere() should send the filter")
+	assert.InDelta(t, 5, chroma.requests["query"]["n_results"], 0, "SearchWhere() should send the limit")
+}
+
+func TestChromaVectorStorage_Delete(t *testing.T) {
+	// Arrange
+	chroma, server := newChromaServer(t)
+	ctx := context.Background()
+	store, err := NewChromaVectorStorage(ctx, server.Client(), ChromaConfig{URL: server.URL, Token: "secret"}, nil, recordMap{})
+	require.NoError(t, err, "NewChromaVectorStorage() error should be nil")
+
+	// Act
+	err = store.Delete(ctx, "receipt")
+
+	// Assert
+	require.NoError(t, err, "Delete() error should be nil")
+	assert.Equal(t, []any{"receipt"}, chroma.requests["delete"]["ids"], "Delete() should send the ID")
+}
+
+func TestNewChromaVectorStorage_ErrorStatus(t *testing.T) {
+	// Arrange
+	_, server := newChromaServer(t)
+
+	// Act
+	_, err := NewChromaVectorStorage(context.Background(), server.Client(), ChromaConfig{URL: server.URL, Token: "wrong"}, nil, recordMap{})
+
+	// Assert
+	require.Error(t, err, "NewChromaVectorStorage() should fail when the collection is not created")
+}
@@ -0,0 +1,67 @@
+package knowledgebase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/kazemisoroush/assistant/pkg/sqlite"
+)
+
+// chunkCounts keeps how many chunks the records indexed in more than one
+// were split into, so every chunk of a record is deleted with it
+type chunkCounts struct {
+	db *sqlite.DB
+}
+
+// openChunkCounts opens the chunk counts in the SQLite database
+func openChunkCounts(db *sqlite.DB) (*chunkCounts, error) {
+	schema := `
+    CREATE TABLE IF NOT EXISTS vector_chunks (
+        record_id TEXT PRIMARY KEY,
+        chunks INTEGER NOT NULL
+    );
+    `
+	if _, err := db.Exec(schema); err != nil {
+		return nil, fmt.Errorf("failed to initialize chunk counts schema: %w", err)
+	}
+
+	return &chunkCounts{db: db}, nil
+}
+
+// get returns the number of chunks a record was indexed in, 1 when it was
+// indexed whole or not at all
+func (c *chunkCounts) get(ctx context.Context, id string) (int, error) {
+	var chunks int
+	err := c.db.QueryRowContext(ctx, `SELECT chunks FROM vector_chunks WHERE record_id = ?`, id).Scan(&chunks)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 1, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to get chunk count of record %s: %w", id, err)
+	}
+	return chunks, nil
+}
+
+// set keeps the number of chunks of every record, forgetting those indexed whole
+func (c *chunkCounts) set(ctx context.Context, counts map[string]int) error {
+	err := c.db.Transact(ctx, func(tx *sql.Tx) error {
+		for id, chunks := range counts {
+			query, args := `DELETE FROM vector_chunks WHERE record_id = ?`, []any{id}
+			if chunks > 1 {
+				query = `INSERT INTO vector_chunks (record_id, chunks) VALUES (?, ?)
+                 ON CONFLICT (record_id) DO UPDATE SET chunks = excluded.chunks`
+				args = append(args, chunks)
+			}
+			if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to set chunk counts: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,161 @@
+package knowledgebase
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+
+	"github.com/kazemisoroush/assistant/pkg/records"
+	"github.com/kazemisoroush/assistant/pkg/records/chunker"
+	"github.com/kazemisoroush/assistant/pkg/sqlite"
+)
+
+// chunkSearchFactor is how many more hits than asked for are searched, as
+// several may be chunks of the same record
+const chunkSearchFactor = 4
+
+// ChunkingVectorStorage indexes the content of long records in overlapping
+// chunks, each under its own ID, and merges the hits of a record's chunks into
+// one result scored by its best chunk. The first chunk is indexed under the
+// record's ID, so records fitting in one chunk are indexed as they are. The
+// number of chunks of every record is kept, so they are deleted with it.
+type ChunkingVectorStorage struct {
+	storage VectorStorage
+	config  chunker.Config
+	counts  *chunkCounts
+	records RecordGetter
+}
+
+// NewChunkingVectorStorage wraps a vector store with chunking, keeping the
+// chunk counts in the SQLite database.
+// The records of the results are loaded from the record getter. Stores
+// loading the records of their results must load chunks as the record they
+// were split from; NewVectorStorage sets them up so.
+func NewChunkingVectorStorage(storage VectorStorage, config chunker.Config, counts *sqlite.DB, recordGetter RecordGetter) (VectorStorage, error) {
+	chunkCounts, err := openChunkCounts(counts)
+	if err != nil {
+		return nil, err
+	}
+	return &ChunkingVectorStorage{
+		storage: storage,
+		config:  config,
+		counts:  chunkCounts,
+		records: recordGetter,
+	}, nil
+}
+
+// Index implements VectorStorage
+func (c *ChunkingVectorStorage) Index(ctx context.Context, rec records.Record) error {
+	return c.IndexBatch(ctx, []records.Record{rec})
+}
+
+// IndexBatch implements VectorStorage. The chunks of every record are
+// indexed in one write, after the chunks a previous version of a record had
+// beyond those of the new one are deleted.
+func (c *ChunkingVectorStorage) IndexBatch(ctx context.Context, recs []records.Record) error {
+	var chunks []records.Record
+	counts := make(map[string]int, len(recs))
+	for _, rec := range recs {
+		recChunks := c.chunks(rec)
+		if err := c.deleteChunks(ctx, rec.ID, len(recChunks)); err != nil {
+			return err
+		}
+		chunks = append(chunks, recChunks...)
+		counts[rec.ID] = len(recChunks)
+	}
+
+	var err error
+	if len(chunks) == 1 {
+		err = c.storage.Index(ctx, chunks[0])
+	} else {
+		err = c.storage.IndexBatch(ctx, chunks)
+	}
+	if err != nil {
+		return err
+	}
+	return c.counts.set(ctx, counts)
+}
+
+// chunks returns the records of the chunks of a record's content, each a
+// copy of the record holding its chunk under the chunk's ID
+func (c *ChunkingVectorStorage) chunks(rec records.Record) []records.Record {
+	contents := chunker.Split(rec.Content, c.config)
+	if len(contents) == 1 {
+		return []records.Record{rec}
+	}
+	chunks := make([]records.Record, 0, len(contents))
+	for i, content := range contents {
+		chunk := rec
+		chunk.ID = chunker.ID(rec.ID, i)
+		chunk.Content = content
+		chunks = append(chunks, chunk)
+	}
+	return chunks
+}
+
+// Search implements VectorStorage. Results hold the whole record the best
+// chunk of each was split from, loaded from the record getter, never the
+// chunk, and are in the order of their best chunk. Records failing to load,
+// such as records deleted since they were indexed, are skipped.
+func (c *ChunkingVectorStorage) Search(ctx context.Context, prompt string, limit int) ([]records.SearchResult, error) {
+	hits, err := c.storage.Search(ctx, prompt, limit*chunkSearchFactor)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool, len(hits))
+	results := make([]records.SearchResult, 0, len(hits))
+	for _, hit := range hits {
+		id := chunker.Parent(hit.Record.ID)
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		rec, err := c.records.Get(ctx, id)
+		if err != nil {
+			slog.WarnContext(ctx, "Skipping indexed record that failed to load", "record_id", id, "error", err)
+			continue
+		}
+		results = append(results, records.SearchResult{Record: rec, Score: hit.Score})
+		if limit > 0 && len(results) == limit {
+			break
+		}
+	}
+	return results, nil
+}
+
+// Delete implements VectorStorage. The chunks of the record are deleted
+// last first, so a delete failing part way is resumed by deleting it again.
+func (c *ChunkingVectorStorage) Delete(ctx context.Context, recID string) error {
+	if err := c.deleteChunks(ctx, recID, 1); err != nil {
+		return err
+	}
+	return c.storage.Delete(ctx, recID)
+}
+
+// deleteChunks deletes the chunks of a record beyond the first keep of
+// them, last first, keeping its chunk count as they are deleted
+func (c *ChunkingVectorStorage) deleteChunks(ctx context.Context, recID string, keep int) error {
+	count, err := c.counts.get(ctx, recID)
+	if err != nil {
+		return err
+	}
+	for i := count - 1; i >= max(keep, 1); i-- {
+		if err := c.storage.Delete(ctx, chunker.ID(recID, i)); err != nil {
+			return fmt.Errorf("failed to delete chunk %d of record %s: %w", i, recID, err)
+		}
+		if err := c.counts.set(ctx, map[string]int{recID: i}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Close closes the wrapped store when it holds resources
+func (c *ChunkingVectorStorage) Close() error {
+	if closer, ok := c.storage.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+package knowledgebase
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/kazemisoroush/assistant/pkg/records"
+	"github.com/kazemisoroush/assistant/pkg/records/chunker"
+	"github.com/kazemisoroush/assistant/pkg/records/knowledgebase/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+// newChunking wraps the vector store with chunking, keeping the chunk counts
+// in memory until the test ends
+func newChunking(t *testing.T, inner VectorStorage, config chunker.Config, recs recordMap) VectorStorage {
+	t.Helper()
+	store, err := NewChunkingVectorStorage(inner, config, openDB(t, ":memory:"), recs)
+	require.NoError(t, err, "NewChunkingVectorStorage() error should be nil")
+	t.Cleanup(func() { _ = store.(io.Closer).Close() })
+	return store
+}
+
+func TestChunkingVectorStorage_Index(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	inner := mocks.NewMockVectorStorage(ctrl)
+	var indexed []records.Record
+	inner.EXPECT().IndexBatch(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, recs []records.Record) error {
+		indexed = recs
+		return nil
+	})
+	store := newChunking(t, inner, chunker.Config{Size: 3, Overlap: 1}, nil)
+
+	// Act
+	err := store.Index(context.Background(), records.Record{ID: "lease", Title: "Lease", Content: "rent is due monthly by transfer"})
+
+	// Assert
+	require.NoError(t, err, "Index() error should be nil")
+	require.Len(t, indexed, 3, "Index() should index every chunk of a long record")
+	assert.Equal(t, "lease", indexed[0].ID, "Index() should index the first chunk under the record's ID")
+	assert.Equal(t, chunker.ID("lease", 1), indexed[1].ID, "Index() should index later chunks under their own IDs")
+	assert.Equal(t, "due monthly by", indexed[1].Content, "Index() should index the chunk's content")
+	assert.Equal(t, "Lease", indexed[1].Title, "Index() should keep the record's fields on its chunks")
+}
+
+func TestChunkingVectorStorage_Index_Shorter(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	inner := NewLocalVectorStorage()
+	store := newChunking(t, inner, chunker.Config{Size: 3, Overlap: 1}, nil)
+	require.NoError(t, store.Index(ctx, records.Record{ID: "lease", Content: "rent is due monthly by transfer"}), "Index() error should be nil")
+
+	// Act
+	err := store.Index(ctx, records.Record{ID: "lease", Content: "rent is due"})
+
+	// Assert
+	require.NoError(t, err, "Index() error should be nil")
+	assert.Error(t, inner.Delete(ctx, chunker.ID("lease", 1)), "Index() should delete the chunks the previous version had beyond the new one")
+	assert.NoError(t, inner.Delete(ctx, "lease"), "Index() should index the new version")
+}
+
+func TestChunkingVectorStorage_Search_MergesChunks(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	inner := mocks.NewMockVectorStorage(ctrl)
+	inner.EXPECT().Search(gomock.Any(), "rent", 8).Return([]records.SearchResult{
+		{Record: records.Record{ID: chunker.ID("lease", 2), Content: "due monthly"}, Score: 0.9},
+		{Record: records.Record{ID: "deleted"}, Score: 0.8},
+		{Record: records.Record{ID: "receipt"}, Score: 0.7},
+		{Record: records.Record{ID: "lease"}, Score: 0.5},
+		{Record: records.Record{ID: "invoice"}, Score: 0.3},
+	}, nil)
+	recs := recordMap{
+		"lease":   {ID: "lease", Content: "rent is due monthly by transfer"},
+		"receipt": {ID: "receipt", Content: "rent receipt"},
+		"invoice": {ID: "invoice"},
+	}
+	store := newChunking(t, inner, chunker.Config{Size: 3}, recs)
+
+	// Act
+	results, err := store.Search(context.Background(), "rent", 2)
+
+	// Assert
+	require.NoError(t, err, "Search() error should be nil")
+	assert.Equal(t, []records.SearchResult{
+		{Record: recs["lease"], Score: 0.9},
+		{Record: recs["receipt"], Score: 0.7},
+	}, results, "Search() should return each whole record once, scored by its best chunk, skipping those failing to load")
+}
+
+func TestChunkingVectorStorage_Delete(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	inner := mocks.NewMockVectorStorage(ctrl)
+	inner.EXPECT().IndexBatch(gomock.Any(), gomock.Len(3)).Return(nil)
+	store := newChunking(t, inner, chunker.Config{Size: 3, Overlap: 1}, nil)
+	require.NoError(t, store.Index(ctx, records.Record{ID: "lease", Content: "rent is due monthly by transfer"}), "Index() error should be nil")
+	gomock.InOrder(
+		inner.EXPECT().Delete(gomock.Any(), chunker.ID("lease", 2)).Return(nil),
+		inner.EXPECT().Delete(gomock.Any(), chunker.ID("lease", 1)).Return(errors.New("connection reset")),
+		inner.EXPECT().Delete(gomock.Any(), chunker.ID("lease", 1)).Return(nil),
+		inner.EXPECT().Delete(gomock.Any(), "lease").Return(nil),
+	)
+
+	// Act
+	failed := store.Delete(ctx, "lease")
+	err := store.Delete(ctx, "lease")
+
+	// Assert
+	assert.ErrorContains(t, failed, "connection reset", "Delete() should return the error of a chunk failing to be deleted")
+	assert.NoError(t, err, "Delete() should resume with the chunks left")
+}
+
+func TestNewVectorStorage_ChunksWithoutRecords(t *testing.T) {
+	// Act
+	_, err := NewVectorStorage(VectorStorageConfig{Backend: VectorBackendLocal, Chunks: chunker.Config{Size: 300}})
+
+	// Assert
+	assert.Error(t, err, "NewVectorStorage() should require the records to return with chunked results")
+}
@@ -1,7 +1,10 @@
 // Package knowledgebase provides vector search and embedding functionality for records.
 package knowledgebase
 
-import "context"
+import (
+	"context"
+	"net/http"
+)
 
 // Embedder generates vector embeddings from text
 //
@@ -20,8 +23,16 @@ type Embedder interface {
 
 // EmbedderConfig represents configuration for an embedder
 type EmbedderConfig struct {
-	Provider string // "ollama", "bedrock", "openai", etc.
-	Model    string // Model name
-	APIKey   string // API key if required
-	Endpoint string // Custom endpoint if required
+	Provider   string // "ollama", "bedrock", "openai", etc.
+	Model      string // Model name
+	APIKey     string // API key if required
+	Endpoint   string // Custom endpoint if required
+	Dimensions int    // Dimension of the embedding vectors
+	BatchSize  int    // Maximum number of texts sent per embedding request
+
+	// HTTPClient calls embedders served over HTTP, retrying failed calls when configured to
+	HTTPClient *http.Client
+
+	// BedrockClient calls the embedding models on AWS Bedrock
+	BedrockClient BedrockInvoker
 }
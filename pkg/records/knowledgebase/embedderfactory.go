@@ -0,0 +1,34 @@
+package knowledgebase
+
+import "fmt"
+
+// EmbedderProviderLocal is the provider name of the built-in LocalEmbedder
+const EmbedderProviderLocal = "local"
+
+// NewEmbedder creates the embedder selected by the given configuration
+func NewEmbedder(cfg EmbedderConfig) (Embedder, error) {
+	var embedder Embedder
+
+	switch cfg.Provider {
+	case EmbedderProviderLocal, "":
+		embedder = NewLocalEmbedder(cfg.Dimensions)
+	case EmbedderProviderOllama:
+		if cfg.HTTPClient == nil || cfg.Dimensions <= 0 {
+			return nil, fmt.Errorf("the ollama embedder requires an HTTP client and the dimensions of its model")
+		}
+		embedder = NewOllamaEmbedder(cfg.HTTPClient, cfg.Endpoint, cfg.Model, cfg.Dimensions)
+	case EmbedderProviderBedrock:
+		if cfg.BedrockClient == nil {
+			return nil, fmt.Errorf("the bedrock embedder requires a Bedrock client")
+		}
+		embedder = NewBedrockEmbedder(cfg.BedrockClient, cfg.Model, cfg.Dimensions)
+	default:
+		return nil, fmt.Errorf("unsupported embedder provider: %s", cfg.Provider)
+	}
+
+	if cfg.BatchSize > 0 {
+		embedder = NewBatchingEmbedder(embedder, cfg.BatchSize)
+	}
+
+	return embedder, nil
+}
@@ -0,0 +1,53 @@
+package knowledgebase
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewEmbedder_UnsupportedProvider(t *testing.T) {
+	// Arrange
+	cfg := EmbedderConfig{Provider: "unknown"}
+
+	// Act
+	_, err := NewEmbedder(cfg)
+
+	// Assert
+	require.Error(t, err, "NewEmbedder() should fail for an unsupported provider")
+}
+
+func TestNewEmbedder_Ollama(t *testing.T) {
+	// Arrange
+	cfg := EmbedderConfig{Provider: EmbedderProviderOllama, Dimensions: 768, HTTPClient: http.DefaultClient}
+
+	// Act
+	embedder, err := NewEmbedder(cfg)
+
+	// Assert
+	require.NoError(t, err, "NewEmbedder() error should be nil")
+	require.Equal(t, 768, embedder.Dimensions(), "NewEmbedder() should keep the dimensions")
+}
+
+func TestNewEmbedder_OllamaWithoutHTTPClient(t *testing.T) {
+	// Arrange
+	cfg := EmbedderConfig{Provider: EmbedderProviderOllama, Dimensions: 768}
+
+	// Act
+	_, err := NewEmbedder(cfg)
+
+	// Assert
+	require.Error(t, err, "NewEmbedder() should fail without an HTTP client")
+}
+
+func TestNewEmbedder_BedrockWithoutClient(t *testing.T) {
+	// Arrange
+	cfg := EmbedderConfig{Provider: EmbedderProviderBedrock}
+
+	// Act
+	_, err := NewEmbedder(cfg)
+
+	// Assert
+	require.Error(t, err, "NewEmbedder() should fail without a Bedrock client")
+}
@@ -35,26 +35,48 @@ func NewLocalVectorStorage() VectorStorage {
 // Index adds record embeddings to the vector store
 // For POC, we use a simple bag-of-words approach with TF-IDF-like scoring
 func (lvs *LocalVectorStorage) Index(_ context.Context, record records.Record) error {
+	if record.ID == "" {
+		return fmt.Errorf("record ID is required")
+	}
+	embedding := embed(record)
+
 	lvs.mu.Lock()
 	defer lvs.mu.Unlock()
 
-	if record.ID == "" {
-		return fmt.Errorf("record ID is required")
+	lvs.embeddings[record.ID] = embedding
+	return nil
+}
+
+// IndexBatch adds the embeddings of several records, holding the lock once.
+// No record is indexed when one of them has no ID.
+func (lvs *LocalVectorStorage) IndexBatch(_ context.Context, recs []records.Record) error {
+	embeddings := make([]*RecordEmbedding, 0, len(recs))
+	for _, record := range recs {
+		if record.ID == "" {
+			return fmt.Errorf("record ID is required")
+		}
+		embeddings = append(embeddings, embed(record))
 	}
 
-	// Create a simple term frequency map from record content
-	terms := extractTerms(record.Content)
+	lvs.mu.Lock()
+	defer lvs.mu.Unlock()
+
+	for _, embedding := range embeddings {
+		lvs.embeddings[embedding.RecID] = embedding
+	}
+	return nil
+}
 
-	// Create embedding
-	embedding := &RecordEmbedding{
+// embed creates the embedding of a record from a simple term frequency map
+// of its summary and content
+func embed(record records.Record) *RecordEmbedding {
+	terms := extractTerms(record.IndexText())
+	return &RecordEmbedding{
 		RecID:  record.ID,
 		Terms:  terms,
 		Record: record,
 		Vector: termsToVector(terms),
 	}
-
-	lvs.embeddings[record.ID] = embedding
-	return nil
 }
 
 // Search performs semantic similarity search using cosine similarity
@@ -140,10 +162,12 @@ func extractTerms(text string) map[string]float64 {
 	return terms
 }
 
+// vectorSize is the number of dimensions terms are hashed into
+const vectorSize = 100
+
 // termsToVector converts term frequencies to a simple vector representation
 func termsToVector(terms map[string]float64) []float64 {
 	// For simplicity, we'll create a fixed-size vector using hash-based indexing
-	vectorSize := 100
 	vector := make([]float64, vectorSize)
 
 	for term, freq := range terms {
@@ -2,6 +2,7 @@ package knowledgebase
 
 import (
 	"context"
+	"fmt"
 	"testing"
 
 	"github.com/kazemisoroush/assistant/pkg/records"
@@ -40,6 +41,44 @@ func TestLocalVectorStorage_Index_MissingID(t *testing.T) {
 	require.Error(t, err, "Index() error should not be nil for missing ID")
 }
 
+func TestLocalVectorStorage_IndexBatch(t *testing.T) {
+	// Arrange
+	store := NewLocalVectorStorage()
+	recs := []records.Record{
+		{ID: "rec1", Content: "Go is a great programming language"},
+		{ID: "rec2", Content: "Rust is a systems programming language"},
+	}
+	ctx := context.Background()
+
+	// Act
+	err := store.IndexBatch(ctx, recs)
+
+	// Assert
+	require.NoError(t, err, "IndexBatch() error should be nil")
+	results, err := store.Search(ctx, "programming language", 10)
+	require.NoError(t, err, "Search() error should be nil")
+	assert.Len(t, results, 2, "IndexBatch() should index every record")
+}
+
+func TestLocalVectorStorage_IndexBatch_MissingID(t *testing.T) {
+	// Arrange
+	store := NewLocalVectorStorage()
+	recs := []records.Record{
+		{ID: "rec1", Content: "Go is a great programming language"},
+		{Content: "Rust is a systems programming language"},
+	}
+	ctx := context.Background()
+
+	// Act
+	err := store.IndexBatch(ctx, recs)
+
+	// Assert
+	require.Error(t, err, "IndexBatch() error should not be nil for missing ID")
+	results, err := store.Search(ctx, "programming language", 10)
+	require.NoError(t, err, "Search() error should be nil")
+	assert.Empty(t, results, "IndexBatch() should index no record of a rejected batch")
+}
+
 func TestLocalVectorStorage_Search(t *testing.T) {
 	// Arrange
 	store := NewLocalVectorStorage()
@@ -109,3 +148,36 @@ func TestLocalVectorStorage_Delete_NotFound(t *testing.T) {
 	// Assert
 	require.Error(t, err, "Delete() error should not be nil for nonexistent record")
 }
+
+// benchWords are the words synthetic benchmark records are made of
+var benchWords = []string{"invoice", "receipt", "dentist", "insurance", "passport", "flight", "hotel", "grocery", "fuel", "warranty", "laptop", "visa", "clinic", "policy", "tax", "rent"}
+
+// benchRecord builds a synthetic record of a few words picked by its index
+func benchRecord(i int) records.Record {
+	content := fmt.Sprintf("%s %s %s %s document %d", benchWords[i%len(benchWords)], benchWords[(i/3)%len(benchWords)],
+		benchWords[(i/7)%len(benchWords)], benchWords[(i/11)%len(benchWords)], i)
+	return records.Record{ID: fmt.Sprintf("rec-%d", i), Content: content}
+}
+
+func BenchmarkLocalVectorStorage_Search(b *testing.B) {
+	for _, size := range []int{10_000, 100_000} {
+		b.Run(fmt.Sprintf("records=%d", size), func(b *testing.B) {
+			store := NewLocalVectorStorage()
+			recs := make([]records.Record, size)
+			for i := range recs {
+				recs[i] = benchRecord(i)
+			}
+			ctx := context.Background()
+			if err := store.IndexBatch(ctx, recs); err != nil {
+				b.Fatalf("IndexBatch() failed: %v", err)
+			}
+
+			b.ResetTimer()
+			for range b.N {
+				if _, err := store.Search(ctx, "dentist insurance invoice", 10); err != nil {
+					b.Fatalf("Search() failed: %v", err)
+				}
+			}
+		})
+	}
+}
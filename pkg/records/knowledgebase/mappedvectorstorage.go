@@ -0,0 +1,458 @@
+package knowledgebase
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"unsafe"
+
+	"github.com/kazemisoroush/assistant/pkg/records"
+)
+
+// Layout of the files of a mapped index. The vectors file starts with a
+// header of the magic and the dimensions, followed by one fixed-stride row of
+// little-endian float32 values per line of the ID sidecar. A sidecar line is
+// "+ID" for the embedding of a record, superseding earlier rows of the same
+// ID, or "-ID" for a deletion, whose row is zeros.
+const (
+	mappedMagic      = "AVEC"
+	mappedHeaderSize = 8
+	vectorsExt       = ".vec"
+	idsExt           = ".ids"
+)
+
+// RecordGetter loads the records search results are returned with
+type RecordGetter interface {
+	// Get retrieves a record by ID
+	Get(ctx context.Context, id string) (records.Record, error)
+}
+
+// MappedVectorStorage is a persistent local vector store. Embeddings are
+// appended to a flat file of float32 rows, memory-mapped when the store is
+// opened so it loads without decoding, and searched by scanning the rows
+// without allocating per record. Only the IDs are kept on the heap; the
+// records of the results are loaded from the record storage. Records are
+// embedded by the embedder, or by hashing their terms when there is none.
+type MappedVectorStorage struct {
+	mu       sync.RWMutex
+	records  RecordGetter
+	embedder Embedder // nil to hash terms
+	dims     int
+	vectors  *os.File // Append handle of the vectors file
+	ids      *os.File // Append handle of the ID sidecar
+	unmap    func() error
+
+	mapped []float32      // Rows mapped when opened
+	tail   []float32      // Rows appended since
+	rowIDs []string       // Record of each row; empty for superseded and deleted rows
+	rows   map[string]int // Row of each indexed record
+	failed error          // Set when a write left the files out of step; writes are refused until reopened
+}
+
+// NewMappedVectorStorage opens the index stored at path, creating it when
+// missing. The index keeps the dimensions of the embedder it was created
+// with, nil for hashed terms; it is rebuilt by deleting its files. Rows left
+// behind by updates and deletions are compacted away when they outnumber
+// the live ones. Call Close to release the mapping.
+func NewMappedVectorStorage(path string, embedder Embedder, recordGetter RecordGetter) (*MappedVectorStorage, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create vector index directory: %w", err)
+	}
+
+	lines, err := readIDs(path + idsExt)
+	if err != nil {
+		return nil, err
+	}
+	dims := vectorSize
+	if embedder != nil {
+		dims = embedder.Dimensions()
+	}
+	data, unmap, err := openVectors(path+vectorsExt, dims)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &MappedVectorStorage{records: recordGetter, embedder: embedder, dims: dims, rows: map[string]int{}, unmap: unmap}
+	inStep := s.load(data, lines)
+	if !inStep || len(s.rowIDs)-len(s.rows) > len(s.rows) {
+		err := s.compact(path)
+		_ = unmap()
+		if err != nil {
+			return nil, err
+		}
+		return NewMappedVectorStorage(path, embedder, recordGetter)
+	}
+
+	if err := s.openAppend(path); err != nil {
+		_ = s.Close()
+		return nil, err
+	}
+	return s, nil
+}
+
+// load applies the rows of the mapped vectors file named by the sidecar lines,
+// the latest row of each record winning. It reports whether the files are in
+// step; a write interrupted between or within them leaves them out of step.
+func (s *MappedVectorStorage) load(data []byte, lines []string) bool {
+	stride := s.dims * 4
+	stored := (len(data) - mappedHeaderSize) / stride
+	s.mapped = floats(data[mappedHeaderSize : mappedHeaderSize+stored*stride])
+	s.rowIDs = make([]string, 0, len(lines))
+	for _, line := range lines[:min(len(lines), stored)] {
+		s.rowIDs = append(s.rowIDs, "")
+		if line != "" {
+			s.apply(len(s.rowIDs)-1, line[:1], line[1:])
+		}
+	}
+	return len(lines) == stored && len(data) == mappedHeaderSize+stored*stride
+}
+
+// readIDs returns the lines of the ID sidecar, or none when it does not exist yet
+func readIDs(path string) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read vector index IDs: %w", err)
+	}
+	// The last element is empty, or a line whose write was interrupted
+	lines := strings.Split(string(data), "\n")
+	return lines[:len(lines)-1], nil
+}
+
+// openVectors maps the vectors file of rows of dims values, writing its header when it is new
+func openVectors(path string, dims int) ([]byte, func() error, error) {
+	info, err := os.Stat(path)
+	if errors.Is(err, os.ErrNotExist) || err == nil && info.Size() == 0 {
+		header := make([]byte, mappedHeaderSize)
+		copy(header, mappedMagic)
+		binary.LittleEndian.PutUint32(header[4:], uint32(dims))
+		if err := os.WriteFile(path, header, 0600); err != nil {
+			return nil, nil, fmt.Errorf("failed to create vector index: %w", err)
+		}
+	} else if err != nil {
+		return nil, nil, fmt.Errorf("failed to open vector index: %w", err)
+	}
+
+	data, unmap, err := mapFile(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to map vector index: %w", err)
+	}
+	if len(data) < mappedHeaderSize || string(data[:4]) != mappedMagic {
+		_ = unmap()
+		return nil, nil, fmt.Errorf("invalid vector index: %s", path)
+	}
+	if stored := binary.LittleEndian.Uint32(data[4:]); stored != uint32(dims) {
+		_ = unmap()
+		return nil, nil, fmt.Errorf("vector index %s has %d dimensions, not the %d of the embedder; delete it to rebuild the index", path, stored, dims)
+	}
+	return data, unmap, nil
+}
+
+// compact rewrites the index with the live rows only, replacing the files at once
+func (s *MappedVectorStorage) compact(path string) error {
+	var vectors, ids bytes.Buffer
+	vectors.WriteString(mappedMagic)
+	_ = binary.Write(&vectors, binary.LittleEndian, uint32(s.dims))
+	for row, id := range s.rowIDs {
+		if id == "" {
+			continue
+		}
+		_ = binary.Write(&vectors, binary.LittleEndian, s.row(row))
+		ids.WriteString("+" + id + "\n")
+	}
+	slog.Info("Compacting vector index", "path", path, "rows", len(s.rowIDs), "live", len(s.rows))
+
+	if err := writeReplacing(path+vectorsExt, vectors.Bytes()); err != nil {
+		return err
+	}
+	return writeReplacing(path+idsExt, ids.Bytes())
+}
+
+// writeReplacing writes data to a temporary file renamed over path
+func writeReplacing(path string, data []byte) error {
+	if err := os.WriteFile(path+".tmp", data, 0600); err != nil {
+		return fmt.Errorf("failed to compact vector index: %w", err)
+	}
+	if err := os.Rename(path+".tmp", path); err != nil {
+		return fmt.Errorf("failed to compact vector index: %w", err)
+	}
+	return nil
+}
+
+// openAppend opens both files for appending rows
+func (s *MappedVectorStorage) openAppend(path string) error {
+	var err error
+	if s.vectors, err = os.OpenFile(path+vectorsExt, os.O_WRONLY|os.O_APPEND, 0600); err != nil {
+		return fmt.Errorf("failed to open vector index: %w", err)
+	}
+	if s.ids, err = os.OpenFile(path+idsExt, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600); err != nil {
+		return fmt.Errorf("failed to open vector index IDs: %w", err)
+	}
+	return nil
+}
+
+// Index implements VectorStorage
+func (s *MappedVectorStorage) Index(ctx context.Context, rec records.Record) error {
+	return s.IndexBatch(ctx, []records.Record{rec})
+}
+
+// IndexBatch implements VectorStorage, appending the rows of every record in
+// one write. No record is indexed when one of them has no valid ID.
+func (s *MappedVectorStorage) IndexBatch(ctx context.Context, recs []records.Record) error {
+	ids := make([]string, 0, len(recs))
+	texts := make([]string, 0, len(recs))
+	for _, rec := range recs {
+		if rec.ID == "" || strings.Contains(rec.ID, "\n") {
+			return fmt.Errorf("record ID is required and must be a single line")
+		}
+		ids = append(ids, rec.ID)
+		texts = append(texts, rec.IndexText())
+	}
+	vectors, err := s.embed(ctx, texts)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.write("+", ids, vectors)
+}
+
+// Delete implements VectorStorage
+func (s *MappedVectorStorage) Delete(_ context.Context, recID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.rows[recID]; !ok {
+		return fmt.Errorf("record not found: %s", recID)
+	}
+	return s.write("-", []string{recID}, make([]float32, s.dims))
+}
+
+// write appends rows to both files and applies them
+func (s *MappedVectorStorage) write(op string, ids []string, vectors []float32) error {
+	if s.failed != nil {
+		return fmt.Errorf("vector index needs reopening after a failed write: %w", s.failed)
+	}
+
+	var lines strings.Builder
+	for _, id := range ids {
+		lines.WriteString(op + id + "\n")
+	}
+	var buf bytes.Buffer
+	_ = binary.Write(&buf, binary.LittleEndian, vectors)
+	if _, err := s.vectors.Write(buf.Bytes()); err != nil {
+		s.failed = err
+		return fmt.Errorf("failed to write vector index: %w", err)
+	}
+	if _, err := s.ids.WriteString(lines.String()); err != nil {
+		s.failed = err
+		return fmt.Errorf("failed to write vector index IDs: %w", err)
+	}
+
+	s.tail = append(s.tail, vectors...)
+	for _, id := range ids {
+		s.rowIDs = append(s.rowIDs, "")
+		s.apply(len(s.rowIDs)-1, op, id)
+	}
+	return nil
+}
+
+// apply records a row, superseding the earlier row of the same record
+func (s *MappedVectorStorage) apply(row int, op, id string) {
+	if prev, ok := s.rows[id]; ok {
+		s.rowIDs[prev] = ""
+		delete(s.rows, id)
+	}
+	if op == "+" {
+		s.rows[id] = row
+		s.rowIDs[row] = id
+	}
+}
+
+// Search implements VectorStorage. Rows are scored by their dot product with
+// the query, the cosine similarity as both are normalized.
+func (s *MappedVectorStorage) Search(ctx context.Context, prompt string, limit int) ([]records.SearchResult, error) {
+	query, err := s.embed(ctx, []string{prompt})
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	top := topHits{limit: limit}
+	for row, id := range s.rowIDs {
+		if id == "" {
+			continue
+		}
+		if score := dot(query, s.row(row)); score > 0 {
+			top.add(hit{id: id, score: score})
+		}
+	}
+	s.mu.RUnlock()
+
+	results := make([]records.SearchResult, 0, len(top.hits))
+	for _, h := range top.hits {
+		rec, err := s.records.Get(ctx, h.id)
+		if err != nil {
+			slog.WarnContext(ctx, "Skipping indexed record that failed to load", "record_id", h.id, "error", err)
+			continue
+		}
+		results = append(results, records.SearchResult{Record: rec, Score: float64(h.score)})
+	}
+	return results, nil
+}
+
+// row returns the vector of a row, mapped or appended since
+func (s *MappedVectorStorage) row(row int) []float32 {
+	if start := row * s.dims; start < len(s.mapped) {
+		return s.mapped[start : start+s.dims]
+	}
+	start := row*s.dims - len(s.mapped)
+	return s.tail[start : start+s.dims]
+}
+
+// embed returns the normalized vectors of the texts, one row after the other
+func (s *MappedVectorStorage) embed(ctx context.Context, texts []string) ([]float32, error) {
+	return embedVectors(ctx, s.embedder, s.dims, texts)
+}
+
+// embedVectors returns the normalized vectors of dims values the embedder
+// returns for the texts, one row after the other, or of their hashed terms
+// when the embedder is nil
+func embedVectors(ctx context.Context, embedder Embedder, dims int, texts []string) ([]float32, error) {
+	vectors := make([]float32, 0, len(texts)*dims)
+	if embedder == nil {
+		for _, text := range texts {
+			vectors = append(vectors, float32s(termsToVector(extractTerms(text)))...)
+		}
+		return vectors, nil
+	}
+
+	embeddings, err := embedder.EmbedBatch(ctx, texts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to embed records: %w", err)
+	}
+	if len(embeddings) != len(texts) {
+		return nil, fmt.Errorf("failed to embed records: got %d embeddings for %d texts", len(embeddings), len(texts))
+	}
+	for _, embedding := range embeddings {
+		if len(embedding) != dims {
+			return nil, fmt.Errorf("failed to embed records: got %d dimensions, expected %d", len(embedding), dims)
+		}
+		vectors = append(vectors, normalize(embedding)...)
+	}
+	return vectors, nil
+}
+
+// Close releases the mapping and closes the files
+func (s *MappedVectorStorage) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var errs []error
+	if s.vectors != nil {
+		errs = append(errs, s.vectors.Close())
+	}
+	if s.ids != nil {
+		errs = append(errs, s.ids.Close())
+	}
+	errs = append(errs, s.unmap())
+	s.unmap = func() error { return nil }
+	s.vectors, s.ids = nil, nil
+	s.mapped, s.tail, s.rowIDs = nil, nil, nil
+	return errors.Join(errs...)
+}
+
+// hit is a scored row kept while searching
+type hit struct {
+	id    string
+	score float32
+}
+
+// topHits keeps the best hits in descending score order, up to limit when it is positive
+type topHits struct {
+	limit int
+	hits  []hit
+}
+
+// add inserts the hit in order, dropping the worst once more than limit are kept
+func (t *topHits) add(h hit) {
+	if t.limit > 0 && len(t.hits) == t.limit {
+		if h.score <= t.hits[len(t.hits)-1].score {
+			return
+		}
+		t.hits = t.hits[:len(t.hits)-1]
+	}
+	i := len(t.hits)
+	t.hits = append(t.hits, h)
+	for ; i > 0 && t.hits[i-1].score < h.score; i-- {
+		t.hits[i] = t.hits[i-1]
+	}
+	t.hits[i] = h
+}
+
+// dot returns the dot product of two vectors of the same length
+func dot(a, b []float32) float32 {
+	var sum float32
+	for i := range a {
+		sum += a[i] * b[i]
+	}
+	return sum
+}
+
+// normalize scales a vector to unit length, so dot products are cosine similarities
+func normalize(vector []float32) []float32 {
+	var sum float64
+	for _, v := range vector {
+		sum += float64(v) * float64(v)
+	}
+	if sum == 0 {
+		return vector
+	}
+	magnitude := float32(math.Sqrt(sum))
+	out := make([]float32, len(vector))
+	for i, v := range vector {
+		out[i] = v / magnitude
+	}
+	return out
+}
+
+// float32s converts a vector to the precision it is stored with
+func float32s(vector []float64) []float32 {
+	out := make([]float32, len(vector))
+	for i, v := range vector {
+		out[i] = float32(v)
+	}
+	return out
+}
+
+// floats returns little-endian float32 values in place on little-endian
+// hosts, and decoded otherwise
+func floats(data []byte) []float32 {
+	if len(data) == 0 {
+		return nil
+	}
+	if binary.NativeEndian.Uint16([]byte{1, 0}) != 1 {
+		return decodeFloats(data)
+	}
+	return unsafe.Slice((*float32)(unsafe.Pointer(&data[0])), len(data)/4)
+}
+
+// decodeFloats decodes little-endian float32 values
+func decodeFloats(data []byte) []float32 {
+	out := make([]float32, len(data)/4)
+	for i := range out {
+		out[i] = math.Float32frombits(binary.LittleEndian.Uint32(data[i*4:]))
+	}
+	return out
+}
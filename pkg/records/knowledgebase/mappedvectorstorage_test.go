@@ -0,0 +1,211 @@
+package knowledgebase
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/kazemisoroush/assistant/pkg/records"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordMap is a RecordGetter over records kept in memory
+type recordMap map[string]records.Record
+
+// Get implements RecordGetter
+func (m recordMap) Get(_ context.Context, id string) (records.Record, error) {
+	rec, ok := m[id]
+	if !ok {
+		return records.Record{}, fmt.Errorf("record not found: %s", id)
+	}
+	return rec, nil
+}
+
+// openMapped opens a mapped index at path, closing it when the test ends
+func openMapped(t *testing.T, path string, recs recordMap) *MappedVectorStorage {
+	t.Helper()
+	store, err := NewMappedVectorStorage(path, nil, recs)
+	require.NoError(t, err, "NewMappedVectorStorage() error should be nil")
+	t.Cleanup(func() { _ = store.Close() })
+	return store
+}
+
+func TestMappedVectorStorage_Search_AfterReopen(t *testing.T) {
+	// Arrange
+	path := filepath.Join(t.TempDir(), "vectors")
+	recs := recordMap{
+		"go":     {ID: "go", Content: "Go is a great programming language"},
+		"python": {ID: "python", Content: "Python is used for data science"},
+		"rust":   {ID: "rust", Content: "Rust offers memory safety"},
+	}
+	ctx := context.Background()
+	store := openMapped(t, path, recs)
+	require.NoError(t, store.IndexBatch(ctx, []records.Record{recs["go"], recs["python"]}), "IndexBatch() error should be nil")
+	require.NoError(t, store.Close(), "Close() error should be nil")
+	reopened := openMapped(t, path, recs)
+	require.NoError(t, reopened.Index(ctx, recs["rust"]), "Index() error should be nil")
+
+	// Act
+	mapped, err := reopened.Search(ctx, "programming language", 10)
+	require.NoError(t, err, "Search() error should be nil")
+	appended, err := reopened.Search(ctx, "memory safety", 10)
+	require.NoError(t, err, "Search() error should be nil")
+
+	// Assert
+	require.NotEmpty(t, mapped, "Search() should find rows mapped from the file")
+	assert.Equal(t, "go", mapped[0].Record.ID, "Search() should rank the best match first")
+	require.NotEmpty(t, appended, "Search() should find rows appended since opening")
+	assert.Equal(t, "rust", appended[0].Record.ID, "Search() should rank the best match first")
+}
+
+func TestMappedVectorStorage_Delete_Persists(t *testing.T) {
+	// Arrange
+	path := filepath.Join(t.TempDir(), "vectors")
+	recs := recordMap{"rec1": {ID: "rec1", Content: "dentist invoice"}}
+	ctx := context.Background()
+	store := openMapped(t, path, recs)
+	require.NoError(t, store.Index(ctx, recs["rec1"]), "Index() error should be nil")
+
+	// Act
+	err := store.Delete(ctx, "rec1")
+	require.NoError(t, store.Close(), "Close() error should be nil")
+
+	// Assert
+	require.NoError(t, err, "Delete() error should be nil")
+	results, err := openMapped(t, path, recs).Search(ctx, "dentist invoice", 10)
+	require.NoError(t, err, "Search() error should be nil")
+	assert.Empty(t, results, "Search() should not find deleted records after reopening")
+	require.Error(t, openMapped(t, path, recs).Delete(ctx, "missing"), "Delete() error should not be nil for nonexistent record")
+}
+
+func TestMappedVectorStorage_Index_SupersedesAndCompacts(t *testing.T) {
+	// Arrange
+	path := filepath.Join(t.TempDir(), "vectors")
+	recs := recordMap{"rec1": {ID: "rec1", Content: "hotel booking"}}
+	ctx := context.Background()
+	store := openMapped(t, path, recs)
+	require.NoError(t, store.Index(ctx, records.Record{ID: "rec1", Content: "flight ticket"}), "Index() error should be nil")
+	require.NoError(t, store.Index(ctx, recs["rec1"]), "Index() error should be nil")
+	require.NoError(t, store.Index(ctx, recs["rec1"]), "Index() error should be nil")
+	require.NoError(t, store.Close(), "Close() error should be nil")
+
+	// Act
+	reopened := openMapped(t, path, recs)
+	stale, err := reopened.Search(ctx, "flight ticket", 10)
+	require.NoError(t, err, "Search() error should be nil")
+	current, err := reopened.Search(ctx, "hotel booking", 10)
+	require.NoError(t, err, "Search() error should be nil")
+
+	// Assert
+	assert.Empty(t, stale, "Search() should not match superseded rows")
+	assert.Len(t, current, 1, "Search() should match the latest row once")
+	info, err := os.Stat(path + vectorsExt)
+	require.NoError(t, err, "Stat() error should be nil")
+	assert.Equal(t, int64(mappedHeaderSize+vectorSize*4), info.Size(), "reopening should compact superseded rows away")
+}
+
+func TestMappedVectorStorage_RecoversInterruptedWrite(t *testing.T) {
+	// Arrange
+	path := filepath.Join(t.TempDir(), "vectors")
+	recs := recordMap{"rec1": {ID: "rec1", Content: "passport renewal"}}
+	ctx := context.Background()
+	store := openMapped(t, path, recs)
+	require.NoError(t, store.Index(ctx, recs["rec1"]), "Index() error should be nil")
+	require.NoError(t, store.Close(), "Close() error should be nil")
+	f, err := os.OpenFile(path+vectorsExt, os.O_WRONLY|os.O_APPEND, 0600)
+	require.NoError(t, err, "OpenFile() error should be nil")
+	_, err = f.Write(make([]byte, vectorSize*4/2))
+	require.NoError(t, err, "Write() error should be nil")
+	require.NoError(t, f.Close(), "Close() error should be nil")
+
+	// Act
+	reopened := openMapped(t, path, recs)
+	err = reopened.Index(ctx, records.Record{ID: "rec2", Content: "visa application"})
+	require.NoError(t, err, "Index() error should be nil")
+	require.NoError(t, reopened.Close(), "Close() error should be nil")
+	results, err := openMapped(t, path, recordMap{"rec1": recs["rec1"], "rec2": {ID: "rec2"}}).Search(ctx, "passport renewal", 10)
+
+	// Assert
+	require.NoError(t, err, "Search() error should be nil")
+	require.NotEmpty(t, results, "Search() should keep the rows written before the interrupted write")
+	assert.Equal(t, "rec1", results[0].Record.ID, "Search() should keep rows aligned with their IDs")
+}
+
+func BenchmarkMappedVectorStorage_Search(b *testing.B) {
+	for _, size := range []int{10_000, 100_000} {
+		b.Run(fmt.Sprintf("records=%d", size), func(b *testing.B) {
+			path := filepath.Join(b.TempDir(), "vectors")
+			recs := recordMap{}
+			batch := make([]records.Record, size)
+			for i := range batch {
+				batch[i] = benchRecord(i)
+				recs[batch[i].ID] = batch[i]
+			}
+			store, err := NewMappedVectorStorage(path, nil, recs)
+			if err != nil {
+				b.Fatalf("NewMappedVectorStorage() failed: %v", err)
+			}
+			ctx := context.Background()
+			if err := store.IndexBatch(ctx, batch); err != nil {
+				b.Fatalf("IndexBatch() failed: %v", err)
+			}
+			_ = store.Close()
+			if store, err = NewMappedVectorStorage(path, nil, recs); err != nil {
+				b.Fatalf("NewMappedVectorStorage() failed: %v", err)
+			}
+			defer func() { _ = store.Close() }()
+
+			b.ResetTimer()
+			for range b.N {
+				if _, err := store.Search(ctx, "dentist insurance invoice", 10); err != nil {
+					b.Fatalf("Search() failed: %v", err)
+				}
+			}
+		})
+	}
+}
+
+func TestMappedVectorStorage_Search_WithEmbedder(t *testing.T) {
+	// Arrange
+	var requests []map[string]any
+	server := ollamaServer(t, 4, &requests)
+	embedder := NewOllamaEmbedder(server.Client(), server.URL, "", 4)
+	path := filepath.Join(t.TempDir(), "vectors")
+	recs := recordMap{
+		"a": {ID: "a", Content: "first"},
+		"b": {ID: "b", Content: "second"},
+	}
+	ctx := context.Background()
+	store, err := NewMappedVectorStorage(path, embedder, recs)
+	require.NoError(t, err, "NewMappedVectorStorage() error should be nil")
+	t.Cleanup(func() { _ = store.Close() })
+	require.NoError(t, store.IndexBatch(ctx, []records.Record{recs["a"], recs["b"]}), "IndexBatch() error should be nil")
+
+	// Act
+	results, err := store.Search(ctx, "query", 10)
+
+	// Assert
+	require.NoError(t, err, "Search() error should be nil")
+	require.NotEmpty(t, results, "Search() should find the embedded records")
+	assert.Equal(t, "a", results[0].Record.ID, "Search() should rank the closest embedding first")
+	assert.Len(t, requests, 2, "IndexBatch() should embed the batch in one request")
+}
+
+func TestMappedVectorStorage_DimensionMismatch(t *testing.T) {
+	// Arrange
+	var requests []map[string]any
+	server := ollamaServer(t, 4, &requests)
+	path := filepath.Join(t.TempDir(), "vectors")
+	store := openMapped(t, path, recordMap{})
+	require.NoError(t, store.Index(context.Background(), records.Record{ID: "a", Content: "text"}), "Index() error should be nil")
+	require.NoError(t, store.Close(), "Close() error should be nil")
+
+	// Act
+	_, err := NewMappedVectorStorage(path, NewOllamaEmbedder(server.Client(), server.URL, "", 4), recordMap{})
+
+	// Assert
+	require.Error(t, err, "NewMappedVectorStorage() should fail on an index of other dimensions")
+}
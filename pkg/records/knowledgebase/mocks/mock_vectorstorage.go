@@ -69,6 +69,20 @@ func (mr *MockVectorStorageMockRecorder) Index(ctx, rec any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Index", reflect.TypeOf((*MockVectorStorage)(nil).Index), ctx, rec)
 }
 
+// IndexBatch mocks base method.
+func (m *MockVectorStorage) IndexBatch(ctx context.Context, recs []records.Record) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IndexBatch", ctx, recs)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// IndexBatch indicates an expected call of IndexBatch.
+func (mr *MockVectorStorageMockRecorder) IndexBatch(ctx, recs any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IndexBatch", reflect.TypeOf((*MockVectorStorage)(nil).IndexBatch), ctx, recs)
+}
+
 // Search mocks base method.
 func (m *MockVectorStorage) Search(ctx context.Context, prompt string, limit int) ([]records.SearchResult, error) {
 	m.ctrl.T.Helper()
@@ -0,0 +1,105 @@
+package knowledgebase
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+const (
+	// EmbedderProviderOllama is the provider name of the OllamaEmbedder
+	EmbedderProviderOllama = "ollama"
+
+	// DefaultOllamaURL is where the OllamaEmbedder reaches Ollama when no endpoint is configured
+	DefaultOllamaURL = "http://localhost:11434"
+
+	// DefaultOllamaEmbeddingModel is the model the OllamaEmbedder uses when none is configured
+	DefaultOllamaEmbeddingModel = "nomic-embed-text"
+)
+
+// OllamaEmbedder embeds texts with an embedding model served by Ollama. A
+// batch is embedded in one request to the /api/embed endpoint; retries are
+// left to the HTTP client.
+type OllamaEmbedder struct {
+	client     *http.Client
+	url        string
+	model      string
+	dimensions int
+}
+
+// NewOllamaEmbedder creates an embedder of the model served by Ollama at
+// url, whose embeddings have the given dimensions
+func NewOllamaEmbedder(client *http.Client, url, model string, dimensions int) Embedder {
+	if url == "" {
+		url = DefaultOllamaURL
+	}
+	if model == "" {
+		model = DefaultOllamaEmbeddingModel
+	}
+	return &OllamaEmbedder{
+		client:     client,
+		url:        strings.TrimSuffix(url, "/"),
+		model:      model,
+		dimensions: dimensions,
+	}
+}
+
+// Embed implements Embedder
+func (e *OllamaEmbedder) Embed(ctx context.Context, text string) ([]float32, error) {
+	embeddings, err := e.EmbedBatch(ctx, []string{text})
+	if err != nil {
+		return nil, err
+	}
+	return embeddings[0], nil
+}
+
+// EmbedBatch implements Embedder
+func (e *OllamaEmbedder) EmbedBatch(ctx context.Context, texts []string) ([][]float32, error) {
+	if len(texts) == 0 {
+		return [][]float32{}, nil
+	}
+	body, err := json.Marshal(map[string]any{"model": e.model, "input": texts})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal embed request: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.url+"/api/embed", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create embed request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call Ollama (check if Ollama is running at %s): %w", e.url, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("ollama returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(message)))
+	}
+
+	var result struct {
+		Embeddings [][]float32 `json:"embeddings"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode Ollama embeddings: %w", err)
+	}
+	if len(result.Embeddings) != len(texts) {
+		return nil, fmt.Errorf("ollama returned %d embeddings for %d texts", len(result.Embeddings), len(texts))
+	}
+	for _, embedding := range result.Embeddings {
+		if len(embedding) != e.dimensions {
+			return nil, fmt.Errorf("model %s returned %d dimensions, configured for %d", e.model, len(embedding), e.dimensions)
+		}
+	}
+	return result.Embeddings, nil
+}
+
+// Dimensions implements Embedder
+func (e *OllamaEmbedder) Dimensions() int {
+	return e.dimensions
+}
@@ -0,0 +1,84 @@
+package knowledgebase
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ollamaServer serves /api/embed with one embedding of the given dimensions
+// per input, recording the requests it receives
+func ollamaServer(t *testing.T, dimensions int, requests *[]map[string]any) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/api/embed" {
+			http.NotFound(w, r)
+			return
+		}
+		var req map[string]any
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req), "request body should be JSON")
+		*requests = append(*requests, req)
+		inputs, _ := req["input"].([]any)
+		embeddings := make([][]float32, len(inputs))
+		for i := range embeddings {
+			embeddings[i] = make([]float32, dimensions)
+			embeddings[i][i%dimensions] = 1
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"embeddings": embeddings})
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestOllamaEmbedder_EmbedBatch_OneRequest(t *testing.T) {
+	// Arrange
+	var requests []map[string]any
+	server := ollamaServer(t, 4, &requests)
+	embedder := NewOllamaEmbedder(server.Client(), server.URL+"/", "", 4)
+
+	// Act
+	embeddings, err := embedder.EmbedBatch(context.Background(), []string{"first", "second"})
+
+	// Assert
+	require.NoError(t, err, "EmbedBatch() error should be nil")
+	require.Len(t, embeddings, 2, "EmbedBatch() should return an embedding per text")
+	assert.Equal(t, []float32{0, 1, 0, 0}, embeddings[1], "EmbedBatch() should keep the order of the texts")
+	require.Len(t, requests, 1, "EmbedBatch() should embed the batch in one request")
+	assert.Equal(t, DefaultOllamaEmbeddingModel, requests[0]["model"], "EmbedBatch() should default the model")
+	assert.Equal(t, []any{"first", "second"}, requests[0]["input"], "EmbedBatch() should send the texts")
+}
+
+func TestOllamaEmbedder_Embed_DimensionMismatch(t *testing.T) {
+	// Arrange
+	var requests []map[string]any
+	server := ollamaServer(t, 8, &requests)
+	embedder := NewOllamaEmbedder(server.Client(), server.URL, "nomic-embed-text", 4)
+
+	// Act
+	_, err := embedder.Embed(context.Background(), "text")
+
+	// Assert
+	require.Error(t, err, "Embed() should fail when the model returns other dimensions")
+	assert.Contains(t, err.Error(), "8 dimensions", "Embed() error should name the returned dimensions")
+}
+
+func TestOllamaEmbedder_Embed_ErrorStatus(t *testing.T) {
+	// Arrange
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		http.Error(w, "model not found", http.StatusNotFound)
+	}))
+	t.Cleanup(server.Close)
+	embedder := NewOllamaEmbedder(server.Client(), server.URL, "missing", 4)
+
+	// Act
+	_, err := embedder.Embed(context.Background(), "text")
+
+	// Assert
+	require.Error(t, err, "Embed() should fail on an error status")
+	assert.Contains(t, err.Error(), "model not found", "Embed() error should carry the response")
+}
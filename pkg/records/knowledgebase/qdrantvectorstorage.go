@@ -0,0 +1,299 @@
+package knowledgebase
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha1"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/kazemisoroush/assistant/pkg/records"
+)
+
+// QdrantConfig represents the Qdrant server and collection of the qdrant backend
+type QdrantConfig struct {
+	URL        string
+	APIKey     string // Sent in the api-key header when set
+	Collection string // "records" when empty
+}
+
+// defaultQdrantCollection is the collection of the qdrant backend when none is configured
+const defaultQdrantCollection = "records"
+
+// errQdrantNotFound is returned by calls answered with 404
+var errQdrantNotFound = errors.New("not found")
+
+// PayloadFilter selects the records a search considers by the payload they
+// are stored with. Every set field must match: the type, at least one of the
+// tags, and each metadata value.
+type PayloadFilter struct {
+	Type     records.RecordType
+	Tags     []string
+	Metadata map[string]any
+}
+
+// QdrantVectorStorage keeps record embeddings as points of a Qdrant
+// collection, created with cosine distance when missing, searched with its
+// approximate nearest neighbour index over the REST API. Point IDs are UUIDs
+// derived from the record IDs; the payload of a point holds the record ID,
+// type, tags, creation time and scalar metadata, which SearchFiltered
+// filters on. Records are embedded by the embedder, or by hashing their
+// terms when there is none, and loaded from the record storage.
+type QdrantVectorStorage struct {
+	client   *http.Client
+	base     string // URL of the collection
+	apiKey   string
+	records  RecordGetter
+	embedder Embedder // nil to hash terms
+	dims     int
+}
+
+// NewQdrantVectorStorage creates a vector store on the Qdrant collection of
+// the configuration, creating the collection when it does not exist. An
+// existing collection must have the dimensions of the embedder.
+func NewQdrantVectorStorage(ctx context.Context, client *http.Client, cfg QdrantConfig, embedder Embedder, recordGetter RecordGetter) (*QdrantVectorStorage, error) {
+	if cfg.URL == "" {
+		return nil, fmt.Errorf("the qdrant vector storage backend requires a Qdrant URL")
+	}
+	collection := cfg.Collection
+	if collection == "" {
+		collection = defaultQdrantCollection
+	}
+	dims := vectorSize
+	if embedder != nil {
+		dims = embedder.Dimensions()
+	}
+
+	s := &QdrantVectorStorage{
+		client:   client,
+		base:     strings.TrimSuffix(cfg.URL, "/") + "/collections/" + url.PathEscape(collection),
+		apiKey:   cfg.APIKey,
+		records:  recordGetter,
+		embedder: embedder,
+		dims:     dims,
+	}
+	if err := s.ensureCollection(ctx); err != nil {
+		return nil, fmt.Errorf("failed to prepare Qdrant collection %s: %w", collection, err)
+	}
+	return s, nil
+}
+
+// ensureCollection creates the collection, or checks the dimensions of an existing one
+func (s *QdrantVectorStorage) ensureCollection(ctx context.Context) error {
+	var info struct {
+		Result struct {
+			Config struct {
+				Params struct {
+					Vectors struct {
+						Size int `json:"size"`
+					} `json:"vectors"`
+				} `json:"params"`
+			} `json:"config"`
+		} `json:"result"`
+	}
+	err := s.call(ctx, http.MethodGet, "", nil, &info)
+	if errors.Is(err, errQdrantNotFound) {
+		return s.call(ctx, http.MethodPut, "", map[string]any{
+			"vectors": map[string]any{"size": s.dims, "distance": "Cosine"},
+		}, nil)
+	}
+	if err != nil {
+		return err
+	}
+	if size := info.Result.Config.Params.Vectors.Size; size != s.dims {
+		return fmt.Errorf("collection has %d dimensions, not the %d of the embedder; delete it to rebuild the index", size, s.dims)
+	}
+	return nil
+}
+
+// Index implements VectorStorage
+func (s *QdrantVectorStorage) Index(ctx context.Context, rec records.Record) error {
+	return s.IndexBatch(ctx, []records.Record{rec})
+}
+
+// IndexBatch implements VectorStorage, upserting the points of every record
+// in one request. No record is indexed when one of them has no ID.
+func (s *QdrantVectorStorage) IndexBatch(ctx context.Context, recs []records.Record) error {
+	texts := make([]string, 0, len(recs))
+	for _, rec := range recs {
+		if rec.ID == "" {
+			return fmt.Errorf("record ID is required")
+		}
+		texts = append(texts, rec.IndexText())
+	}
+	vectors, err := embedVectors(ctx, s.embedder, s.dims, texts)
+	if err != nil {
+		return err
+	}
+
+	points := make([]map[string]any, 0, len(recs))
+	for i, rec := range recs {
+		points = append(points, map[string]any{
+			"id":      qdrantPointID(rec.ID),
+			"vector":  vectors[i*s.dims : (i+1)*s.dims],
+			"payload": qdrantPayload(rec),
+		})
+	}
+	if err := s.call(ctx, http.MethodPut, "/points?wait=true", map[string]any{"points": points}, nil); err != nil {
+		return fmt.Errorf("failed to upsert points: %w", err)
+	}
+	return nil
+}
+
+// Search implements VectorStorage
+func (s *QdrantVectorStorage) Search(ctx context.Context, prompt string, limit int) ([]records.SearchResult, error) {
+	return s.SearchFiltered(ctx, prompt, limit, PayloadFilter{})
+}
+
+// SearchFiltered searches the records whose payload matches the filter,
+// scored by their cosine similarity with the prompt
+func (s *QdrantVectorStorage) SearchFiltered(ctx context.Context, prompt string, limit int, filter PayloadFilter) ([]records.SearchResult, error) {
+	query, err := embedVectors(ctx, s.embedder, s.dims, []string{prompt})
+	if err != nil {
+		return nil, err
+	}
+	if limit <= 0 {
+		limit = defaultSearchResults
+	}
+	request := map[string]any{
+		"vector":          query,
+		"limit":           limit,
+		"with_payload":    []string{"record_id"},
+		"score_threshold": 0,
+	}
+	if must := filter.conditions(); len(must) > 0 {
+		request["filter"] = map[string]any{"must": must}
+	}
+	var response struct {
+		Result []struct {
+			Score   float64 `json:"score"`
+			Payload struct {
+				RecordID string `json:"record_id"`
+			} `json:"payload"`
+		} `json:"result"`
+	}
+	if err := s.call(ctx, http.MethodPost, "/points/search", request, &response); err != nil {
+		return nil, fmt.Errorf("failed to search Qdrant: %w", err)
+	}
+
+	results := make([]records.SearchResult, 0, len(response.Result))
+	for _, point := range response.Result {
+		if point.Score <= 0 {
+			continue
+		}
+		rec, err := s.records.Get(ctx, point.Payload.RecordID)
+		if err != nil {
+			slog.WarnContext(ctx, "Skipping indexed record that failed to load", "record_id", point.Payload.RecordID, "error", err)
+			continue
+		}
+		results = append(results, records.SearchResult{Record: rec, Score: point.Score})
+	}
+	return results, nil
+}
+
+// Delete implements VectorStorage
+func (s *QdrantVectorStorage) Delete(ctx context.Context, recID string) error {
+	if err := s.call(ctx, http.MethodPost, "/points/delete?wait=true", map[string]any{"points": []string{qdrantPointID(recID)}}, nil); err != nil {
+		return fmt.Errorf("failed to delete point of record %s: %w", recID, err)
+	}
+	return nil
+}
+
+// call sends the request body as JSON to the path under the collection,
+// decoding the response into v unless it is nil
+func (s *QdrantVectorStorage) call(ctx context.Context, method, path string, body any, v any) error {
+	reader, err := jsonBody(body)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, method, s.base+path, reader)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if s.apiKey != "" {
+		req.Header.Set("api-key", s.apiKey)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to call Qdrant: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode == http.StatusNotFound {
+		return errQdrantNotFound
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("qdrant returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(message)))
+	}
+	if v == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("failed to decode Qdrant response: %w", err)
+	}
+	return nil
+}
+
+// jsonBody returns the body encoded as JSON, or no body when it is nil
+func jsonBody(body any) (io.Reader, error) {
+	if body == nil {
+		return http.NoBody, nil
+	}
+	data, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+	return bytes.NewReader(data), nil
+}
+
+// conditions returns the Qdrant conditions every matching point meets
+func (f PayloadFilter) conditions() []map[string]any {
+	var must []map[string]any
+	if f.Type != "" {
+		must = append(must, map[string]any{"key": "type", "match": map[string]any{"value": string(f.Type)}})
+	}
+	if len(f.Tags) > 0 {
+		must = append(must, map[string]any{"key": "tags", "match": map[string]any{"any": f.Tags}})
+	}
+	for key, value := range f.Metadata {
+		must = append(must, map[string]any{"key": "metadata." + key, "match": map[string]any{"value": value}})
+	}
+	return must
+}
+
+// qdrantPayload returns the payload a record is stored with: its ID, type,
+// tags, creation time as Unix seconds and the metadata fields Qdrant can match
+func qdrantPayload(rec records.Record) map[string]any {
+	metadata := map[string]any{}
+	for key, value := range rec.Metadata {
+		switch value.(type) {
+		case string, bool, float64, int, int64:
+			metadata[key] = value
+		}
+	}
+	payload := map[string]any{"record_id": rec.ID, "type": string(rec.Type), "metadata": metadata}
+	if len(rec.Tags) > 0 {
+		payload["tags"] = rec.Tags
+	}
+	if !rec.CreatedAt.IsZero() {
+		payload["created_at"] = rec.CreatedAt.Unix()
+	}
+	return payload
+}
+
+// qdrantPointID returns the UUID of the point of a record, derived from its ID
+// as a name-based UUID since Qdrant only accepts UUIDs and integers
+func qdrantPointID(recID string) string {
+	sum := sha1.Sum([]byte(recID))
+	sum[6] = sum[6]&0x0f | 0x50
+	sum[8] = sum[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", sum[0:4], sum[4:6], sum[6:8], sum[8:10], sum[10:16])
+}
@@ -0,0 +1,122 @@
+package knowledgebase
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kazemisoroush/assistant/pkg/records"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// qdrantServer is a Qdrant server with one collection of the given size,
+// missing until created when size is 0
+type qdrantServer struct {
+	size     int
+	requests map[string]map[string]any // Last request body of each method and path
+	points   []map[string]any
+}
+
+// newQdrantServer serves the REST API of the records collection
+func newQdrantServer(t *testing.T, size int) (*qdrantServer, *httptest.Server) {
+	t.Helper()
+	qdrant := &qdrantServer{size: size, requests: map[string]map[string]any{}}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("api-key") != "secret" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		var body map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		route := r.Method + " " + r.URL.Path
+		qdrant.requests[route] = body
+
+		switch route {
+		case "GET /collections/records":
+			if qdrant.size == 0 {
+				http.NotFound(w, r)
+				return
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{"result": map[string]any{"config": map[string]any{"params": map[string]any{"vectors": map[string]any{"size": qdrant.size}}}}})
+		case "PUT /collections/records/points":
+			for _, point := range body["points"].([]any) {
+				qdrant.points = append(qdrant.points, point.(map[string]any))
+			}
+			_, _ = w.Write([]byte(`{"result":{}}`))
+		case "POST /collections/records/points/search":
+			result := make([]map[string]any, 0, len(qdrant.points))
+			for i, point := range qdrant.points {
+				result = append(result, map[string]any{"id": point["id"], "score": 0.9 - float64(i), "payload": point["payload"]})
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{"result": result})
+		default:
+			_, _ = w.Write([]byte(`{"result":true}`))
+		}
+	}))
+	t.Cleanup(server.Close)
+	return qdrant, server
+}
+
+func TestQdrantVectorStorage_IndexAndSearchFiltered(t *testing.T) {
+	// Arrange
+	qdrant, server := newQdrantServer(t, 0)
+	recs := recordMap{
+		"receipt": {ID: "receipt", Type: records.RecordTypeReceipt, Content: "Coffee", Tags: []string{"food"}, Metadata: map[string]any{"merchant": "Cafe"}},
+		"visa":    {ID: "visa", Type: records.RecordTypeVisa, Content: "Visa"},
+	}
+	ctx := context.Background()
+	store, err := NewQdrantVectorStorage(ctx, server.Client(), QdrantConfig{URL: server.URL, APIKey: "secret"}, nil, recs)
+	require.NoError(t, err, "NewQdrantVectorStorage() error should be nil")
+	require.NoError(t, store.IndexBatch(ctx, []records.Record{recs["receipt"], recs["visa"]}), "IndexBatch() error should be nil")
+
+	// Act
+	results, err := store.SearchFiltered(ctx, "coffee", 5, PayloadFilter{Type: records.RecordTypeReceipt, Tags: []string{"food"}, Metadata: map[string]any{"merchant": "Cafe"}})
+
+	// Assert
+	require.NoError(t, err, "SearchFiltered() error should be nil")
+	require.Len(t, results, 1, "SearchFiltered() should drop results without similarity")
+	assert.Equal(t, "receipt", results[0].Record.ID, "SearchFiltered() should load the records of the payloads")
+	assert.Equal(t, map[string]any{"size": float64(vectorSize), "distance": "Cosine"}, qdrant.requests["PUT /collections/records"]["vectors"], "NewQdrantVectorStorage() should create a missing collection")
+	assert.Equal(t, qdrantPointID("receipt"), qdrant.points[0]["id"], "IndexBatch() should derive the point ID from the record ID")
+	must := qdrant.requests["POST /collections/records/points/search"]["filter"].(map[string]any)["must"]
+	assert.Len(t, must, 3, "SearchFiltered() should send a condition per filter field")
+}
+
+func TestQdrantVectorStorage_Delete(t *testing.T) {
+	// Arrange
+	qdrant, server := newQdrantServer(t, vectorSize)
+	ctx := context.Background()
+	store, err := NewQdrantVectorStorage(ctx, server.Client(), QdrantConfig{URL: server.URL, APIKey: "secret"}, nil, recordMap{})
+	require.NoError(t, err, "NewQdrantVectorStorage() error should be nil")
+
+	// Act
+	err = store.Delete(ctx, "receipt")
+
+	// Assert
+	require.NoError(t, err, "Delete() error should be nil")
+	assert.Equal(t, []any{qdrantPointID("receipt")}, qdrant.requests["POST /collections/records/points/delete"]["points"], "Delete() should send the point ID")
+}
+
+func TestNewQdrantVectorStorage_DimensionMismatch(t *testing.T) {
+	// Arrange
+	_, server := newQdrantServer(t, 768)
+
+	// Act
+	_, err := NewQdrantVectorStorage(context.Background(), server.Client(), QdrantConfig{URL: server.URL, APIKey: "secret"}, nil, recordMap{})
+
+	// Assert
+	require.Error(t, err, "NewQdrantVectorStorage() should fail on a collection of other dimensions")
+}
+
+func TestQdrantPointID(t *testing.T) {
+	// Act
+	id := qdrantPointID("receipt")
+
+	// Assert
+	assert.Regexp(t, `^[0-9a-f]{8}-[0-9a-f]{4}-5[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`, id, "qdrantPointID() should return a name-based UUID")
+	assert.Equal(t, id, qdrantPointID("receipt"), "qdrantPointID() should be stable")
+	assert.NotEqual(t, id, qdrantPointID("visa"), "qdrantPointID() should differ between records")
+}
@@ -0,0 +1,184 @@
+package knowledgebase
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/binary"
+	"fmt"
+	"log/slog"
+	"sync"
+
+	"github.com/kazemisoroush/assistant/pkg/records"
+	"github.com/kazemisoroush/assistant/pkg/sqlite"
+)
+
+// SQLiteVectorStorage is a persistent local vector store keeping the
+// normalized embedding of each record as a BLOB of little-endian float32
+// values. The vectors are loaded into memory when the store is opened and
+// searched there; writes go to the database first. Like MappedVectorStorage,
+// the records of the results are loaded from the record storage.
+type SQLiteVectorStorage struct {
+	mu       sync.RWMutex
+	db       *sqlite.DB
+	records  RecordGetter
+	embedder Embedder // nil to hash terms
+	dims     int
+	vectors  map[string][]float32
+}
+
+// NewSQLiteVectorStorage opens the vector store in the SQLite database,
+// creating its tables when missing. The store keeps the dimensions of the
+// embedder it was created with, nil for hashed terms.
+func NewSQLiteVectorStorage(db *sqlite.DB, embedder Embedder, recordGetter RecordGetter) (*SQLiteVectorStorage, error) {
+	schema := `
+    CREATE TABLE IF NOT EXISTS vector_index (
+        id INTEGER PRIMARY KEY CHECK (id = 1),
+        dimensions INTEGER NOT NULL
+    );
+    CREATE TABLE IF NOT EXISTS record_vectors (
+        record_id TEXT PRIMARY KEY,
+        vector BLOB NOT NULL
+    );
+    `
+	if _, err := db.Exec(schema); err != nil {
+		return nil, fmt.Errorf("failed to initialize vector storage schema: %w", err)
+	}
+
+	dims := vectorSize
+	if embedder != nil {
+		dims = embedder.Dimensions()
+	}
+	s := &SQLiteVectorStorage{db: db, records: recordGetter, embedder: embedder, dims: dims, vectors: map[string][]float32{}}
+	if err := s.load(context.Background()); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// load checks the dimensions of the stored vectors and reads them into memory
+func (s *SQLiteVectorStorage) load(ctx context.Context) error {
+	if _, err := s.db.ExecContext(ctx, `INSERT OR IGNORE INTO vector_index (id, dimensions) VALUES (1, ?)`, s.dims); err != nil {
+		return fmt.Errorf("failed to initialize vector storage: %w", err)
+	}
+	var stored int
+	if err := s.db.QueryRowContext(ctx, `SELECT dimensions FROM vector_index WHERE id = 1`).Scan(&stored); err != nil {
+		return fmt.Errorf("failed to read vector storage dimensions: %w", err)
+	}
+	if stored != s.dims {
+		return fmt.Errorf("vector storage has %d dimensions, not the %d of the embedder; delete its tables to rebuild the index", stored, s.dims)
+	}
+
+	rows, err := s.db.QueryContext(ctx, `SELECT record_id, vector FROM record_vectors`)
+	if err != nil {
+		return fmt.Errorf("failed to load vectors: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+	for rows.Next() {
+		var id string
+		var blob []byte
+		if err := rows.Scan(&id, &blob); err != nil {
+			return fmt.Errorf("failed to scan vector: %w", err)
+		}
+		if len(blob) != s.dims*4 {
+			slog.WarnContext(ctx, "Skipping vector of invalid size", "record_id", id, "bytes", len(blob))
+			continue
+		}
+		s.vectors[id] = decodeFloats(blob)
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to load vectors: %w", err)
+	}
+	return nil
+}
+
+// Index implements VectorStorage
+func (s *SQLiteVectorStorage) Index(ctx context.Context, rec records.Record) error {
+	return s.IndexBatch(ctx, []records.Record{rec})
+}
+
+// IndexBatch implements VectorStorage, storing the vectors of every record in
+// one transaction. No record is indexed when one of them has no ID.
+func (s *SQLiteVectorStorage) IndexBatch(ctx context.Context, recs []records.Record) error {
+	texts := make([]string, 0, len(recs))
+	for _, rec := range recs {
+		if rec.ID == "" {
+			return fmt.Errorf("record ID is required")
+		}
+		texts = append(texts, rec.IndexText())
+	}
+	vectors, err := embedVectors(ctx, s.embedder, s.dims, texts)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	err = s.db.Transact(ctx, func(tx *sql.Tx) error {
+		for i, rec := range recs {
+			var blob bytes.Buffer
+			_ = binary.Write(&blob, binary.LittleEndian, vectors[i*s.dims:(i+1)*s.dims])
+			if _, err := tx.ExecContext(ctx,
+				`INSERT INTO record_vectors (record_id, vector) VALUES (?, ?)
+             ON CONFLICT (record_id) DO UPDATE SET vector = excluded.vector`,
+				rec.ID, blob.Bytes(),
+			); err != nil {
+				return fmt.Errorf("failed to store vector of record %s: %w", rec.ID, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to store vectors: %w", err)
+	}
+
+	for i, rec := range recs {
+		s.vectors[rec.ID] = vectors[i*s.dims : (i+1)*s.dims]
+	}
+	return nil
+}
+
+// Search implements VectorStorage. Vectors are scored by their dot product
+// with the query, the cosine similarity as both are normalized.
+func (s *SQLiteVectorStorage) Search(ctx context.Context, prompt string, limit int) ([]records.SearchResult, error) {
+	query, err := embedVectors(ctx, s.embedder, s.dims, []string{prompt})
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	top := topHits{limit: limit}
+	for id, vector := range s.vectors {
+		if score := dot(query, vector); score > 0 {
+			top.add(hit{id: id, score: score})
+		}
+	}
+	s.mu.RUnlock()
+
+	results := make([]records.SearchResult, 0, len(top.hits))
+	for _, h := range top.hits {
+		rec, err := s.records.Get(ctx, h.id)
+		if err != nil {
+			slog.WarnContext(ctx, "Skipping indexed record that failed to load", "record_id", h.id, "error", err)
+			continue
+		}
+		results = append(results, records.SearchResult{Record: rec, Score: float64(h.score)})
+	}
+	return results, nil
+}
+
+// Delete implements VectorStorage
+func (s *SQLiteVectorStorage) Delete(ctx context.Context, recID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.vectors[recID]; !ok {
+		return fmt.Errorf("record not found: %s", recID)
+	}
+	if _, err := s.db.ExecContext(ctx, `DELETE FROM record_vectors WHERE record_id = ?`, recID); err != nil {
+		return fmt.Errorf("failed to delete vector of record %s: %w", recID, err)
+	}
+	delete(s.vectors, recID)
+	return nil
+}
@@ -0,0 +1,105 @@
+package knowledgebase
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/kazemisoroush/assistant/pkg/records"
+	"github.com/kazemisoroush/assistant/pkg/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// openDB opens the database at path, closed once the test is done
+func openDB(t testing.TB, path string) *sqlite.DB {
+	t.Helper()
+	db, err := sqlite.Open(path)
+	require.NoError(t, err, "Open() error should be nil")
+	t.Cleanup(func() { _ = db.Close() })
+	return db
+}
+
+// openSQLite opens a SQLite vector store at path, closing its database when the test ends
+func openSQLite(t *testing.T, path string, embedder Embedder, recs recordMap) *SQLiteVectorStorage {
+	t.Helper()
+	store, err := NewSQLiteVectorStorage(openDB(t, path), embedder, recs)
+	require.NoError(t, err, "NewSQLiteVectorStorage() error should be nil")
+	return store
+}
+
+func TestSQLiteVectorStorage_Search_AfterReopen(t *testing.T) {
+	// Arrange
+	path := filepath.Join(t.TempDir(), "assistant.db")
+	recs := recordMap{
+		"go":     {ID: "go", Content: "Go is a great programming language"},
+		"python": {ID: "python", Content: "Python is used for data science"},
+	}
+	ctx := context.Background()
+	store := openSQLite(t, path, nil, recs)
+	require.NoError(t, store.IndexBatch(ctx, []records.Record{recs["go"], recs["python"]}), "IndexBatch() error should be nil")
+	reopened := openSQLite(t, path, nil, recs)
+
+	// Act
+	results, err := reopened.Search(ctx, "programming language", 10)
+
+	// Assert
+	require.NoError(t, err, "Search() error should be nil")
+	require.NotEmpty(t, results, "Search() should find vectors loaded from the database")
+	assert.Equal(t, "go", results[0].Record.ID, "Search() should rank the best match first")
+}
+
+func TestSQLiteVectorStorage_Delete_Persists(t *testing.T) {
+	// Arrange
+	path := filepath.Join(t.TempDir(), "assistant.db")
+	recs := recordMap{"go": {ID: "go", Content: "Go is a great programming language"}}
+	ctx := context.Background()
+	store := openSQLite(t, path, nil, recs)
+	require.NoError(t, store.Index(ctx, recs["go"]), "Index() error should be nil")
+
+	// Act
+	err := store.Delete(ctx, "go")
+
+	// Assert
+	require.NoError(t, err, "Delete() error should be nil")
+	results, err := openSQLite(t, path, nil, recs).Search(ctx, "programming language", 10)
+	require.NoError(t, err, "Search() error should be nil")
+	assert.Empty(t, results, "Delete() should persist the removal")
+	assert.Error(t, store.Delete(ctx, "missing"), "Delete() should fail for a record that is not indexed")
+}
+
+func TestSQLiteVectorStorage_Index_Replaces(t *testing.T) {
+	// Arrange
+	path := filepath.Join(t.TempDir(), "assistant.db")
+	updated := records.Record{ID: "doc", Content: "Rust offers memory safety"}
+	recs := recordMap{"doc": updated}
+	ctx := context.Background()
+	store := openSQLite(t, path, nil, recs)
+	require.NoError(t, store.Index(ctx, records.Record{ID: "doc", Content: "Go is a great programming language"}), "Index() error should be nil")
+
+	// Act
+	err := store.Index(ctx, updated)
+
+	// Assert
+	require.NoError(t, err, "Index() error should be nil")
+	results, err := store.Search(ctx, "memory safety", 10)
+	require.NoError(t, err, "Search() error should be nil")
+	require.Len(t, results, 1, "Index() should replace the vector of the record")
+	stale, err := store.Search(ctx, "programming language", 10)
+	require.NoError(t, err, "Search() error should be nil")
+	assert.Empty(t, stale, "Index() should drop the previous vector")
+}
+
+func TestSQLiteVectorStorage_DimensionMismatch(t *testing.T) {
+	// Arrange
+	var requests []map[string]any
+	server := ollamaServer(t, 4, &requests)
+	path := filepath.Join(t.TempDir(), "assistant.db")
+	openSQLite(t, path, nil, recordMap{})
+
+	// Act
+	_, err := NewSQLiteVectorStorage(openDB(t, path), NewOllamaEmbedder(server.Client(), server.URL, "", 4), recordMap{})
+
+	// Assert
+	require.Error(t, err, "NewSQLiteVectorStorage() should fail on vectors of other dimensions")
+}
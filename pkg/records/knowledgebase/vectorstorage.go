@@ -6,14 +6,21 @@ import (
 	"github.com/kazemisoroush/assistant/pkg/records"
 )
 
+// defaultSearchResults is the number of results a remote backend returns for
+// a search without a limit
+const defaultSearchResults = 100
+
 // VectorStorage defines operations for vector-based record search
-// This is an interface for future implementation with Chroma, Pinecone, or AWS Bedrock
+// Implemented in process, in SQLite and by Chroma and Qdrant servers
 //
 //go:generate mockgen -destination=./mocks/mock_vectorstorage.go -mock_names=VectorStorage=MockVectorStorage -package=mocks . VectorStorage
 type VectorStorage interface {
 	// Index adds record embeddings to the vector store
 	Index(ctx context.Context, rec records.Record) error
 
+	// IndexBatch adds the embeddings of several records in one write
+	IndexBatch(ctx context.Context, recs []records.Record) error
+
 	// Search performs semantic similarity search
 	Search(ctx context.Context, prompt string, limit int) ([]records.SearchResult, error)
 
@@ -0,0 +1,152 @@
+package knowledgebase
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/kazemisoroush/assistant/pkg/breaker"
+	"github.com/kazemisoroush/assistant/pkg/records"
+	"github.com/kazemisoroush/assistant/pkg/records/chunker"
+	"github.com/kazemisoroush/assistant/pkg/sqlite"
+)
+
+// Vector storage backend names
+const (
+	VectorBackendLocal  = "local"
+	VectorBackendSQLite = "sqlite"
+	VectorBackendChroma = "chroma"
+	VectorBackendQdrant = "qdrant"
+)
+
+// VectorStorageConfig represents the configuration used to select and build a vector storage backend
+type VectorStorageConfig struct {
+	Backend string // "local", "sqlite", "chroma", "qdrant"
+
+	// Path persists the local index in memory-mapped files; it is kept in
+	// memory only when empty
+	Path string
+
+	// Database keeps the index of the sqlite backend, which requires it
+	Database *sqlite.DB
+
+	// Records loads the records of the results of a persisted local or sqlite index
+	Records RecordGetter
+
+	// Breaker opens a circuit around remote backends after repeated failures
+	Breaker breaker.Config
+
+	// Chunks splits the content of long records before it is embedded;
+	// records are embedded whole when its size is 0. Chunking requires
+	// Records, and keeps the number of chunks of every record in the SQLite
+	// database ChunkCounts.
+	Chunks      chunker.Config
+	ChunkCounts *sqlite.DB
+
+	// Embedder embeds the records of a persisted local or sqlite index;
+	// terms are hashed when nil. A local index requires Path with it.
+	Embedder Embedder
+
+	// Chroma and Qdrant are the servers and collections of their backends, called with HTTPClient
+	Chroma     ChromaConfig
+	Qdrant     QdrantConfig
+	HTTPClient *http.Client
+}
+
+// NewVectorStorage creates the vector storage backend selected by the given
+// configuration, indexing long records in chunks when configured to
+func NewVectorStorage(cfg VectorStorageConfig) (VectorStorage, error) {
+	if cfg.Chunks.Size <= 0 {
+		return newVectorStorage(cfg)
+	}
+	if cfg.Records == nil || cfg.ChunkCounts == nil {
+		return nil, fmt.Errorf("chunking records requires a record getter and a database for the chunk counts")
+	}
+	recordGetter := cfg.Records
+	cfg.Records = chunkRecords{records: recordGetter}
+	storage, err := newVectorStorage(cfg)
+	if err != nil {
+		return nil, err
+	}
+	chunking, err := NewChunkingVectorStorage(storage, cfg.Chunks, cfg.ChunkCounts, recordGetter)
+	if err != nil {
+		if closer, ok := storage.(io.Closer); ok {
+			_ = closer.Close()
+		}
+		return nil, err
+	}
+	return chunking, nil
+}
+
+// chunkRecords loads the chunks of records as the record they were split from
+type chunkRecords struct {
+	records RecordGetter
+}
+
+// Get implements RecordGetter
+func (c chunkRecords) Get(ctx context.Context, id string) (records.Record, error) {
+	return c.records.Get(ctx, chunker.Parent(id))
+}
+
+// newVectorStorage creates the vector storage backend selected by the given configuration
+func newVectorStorage(cfg VectorStorageConfig) (VectorStorage, error) {
+	switch cfg.Backend {
+	case VectorBackendLocal, VectorBackendSQLite:
+		return newLocalVectorStorage(cfg)
+	case VectorBackendChroma, VectorBackendQdrant:
+		storage, err := newHTTPVectorStorage(cfg)
+		if err != nil {
+			return nil, err
+		}
+		if cfg.Breaker.FailureThreshold > 0 {
+			storage = NewBreakerVectorStorage(storage, breaker.New("vector_"+cfg.Backend, cfg.Breaker))
+		}
+		return storage, nil
+	default:
+		return nil, fmt.Errorf("unknown vector storage backend: %s", cfg.Backend)
+	}
+}
+
+// newLocalVectorStorage creates a vector storage backend running in the
+// process, persisted in mapped files or SQLite when a path is given
+func newLocalVectorStorage(cfg VectorStorageConfig) (VectorStorage, error) {
+	switch {
+	case cfg.Backend == VectorBackendSQLite && cfg.Database == nil:
+		return nil, fmt.Errorf("the sqlite vector storage backend requires a database")
+	case cfg.Backend == VectorBackendSQLite:
+		storage, err := NewSQLiteVectorStorage(cfg.Database, cfg.Embedder, cfg.Records)
+		if err != nil {
+			return nil, err
+		}
+		return storage, nil
+	case cfg.Path == "" && cfg.Embedder != nil:
+		return nil, fmt.Errorf("an embedder requires a persisted local index path")
+	case cfg.Path == "":
+		return NewLocalVectorStorage(), nil
+	}
+	storage, err := NewMappedVectorStorage(cfg.Path, cfg.Embedder, cfg.Records)
+	if err != nil {
+		return nil, err
+	}
+	return storage, nil
+}
+
+// newHTTPVectorStorage creates a backend of a vector database served over HTTP
+func newHTTPVectorStorage(cfg VectorStorageConfig) (VectorStorage, error) {
+	if cfg.HTTPClient == nil {
+		return nil, fmt.Errorf("the %s vector storage backend requires an HTTP client", cfg.Backend)
+	}
+	if cfg.Backend == VectorBackendQdrant {
+		storage, err := NewQdrantVectorStorage(context.Background(), cfg.HTTPClient, cfg.Qdrant, cfg.Embedder, cfg.Records)
+		if err != nil {
+			return nil, err
+		}
+		return storage, nil
+	}
+	storage, err := NewChromaVectorStorage(context.Background(), cfg.HTTPClient, cfg.Chroma, cfg.Embedder, cfg.Records)
+	if err != nil {
+		return nil, err
+	}
+	return storage, nil
+}
@@ -0,0 +1,107 @@
+// Package ledger keeps the version of every file scraped or watched, so a
+// scrape skips the files unchanged since their record was ingested, replaces
+// the records of changed files and removes the records of files since deleted.
+package ledger
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/kazemisoroush/assistant/pkg/records"
+)
+
+// Entry represents the version of a file whose record was ingested
+type Entry struct {
+	Path     string
+	SHA256   string    // Hex digest of the file's content
+	ModTime  time.Time // Files whose modification time is unchanged are not hashed again
+	Version  string    // Version a server reports for a remote file, compared in place of its content
+	RecordID string    // Record extracted from this version of the file
+}
+
+// Store defines operations for keeping the ledger
+//
+//go:generate mockgen -destination=./mocks/mock_store.go -mock_names=Store=MockStore -package=mocks . Store
+type Store interface {
+	// Get returns the entry of a file and whether there is one
+	Get(ctx context.Context, path string) (Entry, bool, error)
+
+	// List returns the entries of the files under a directory
+	List(ctx context.Context, dir string) ([]Entry, error)
+
+	// Put stores the entry of its file, replacing the previous one
+	Put(ctx context.Context, entry Entry) error
+
+	// Delete removes the entry of a file, if any
+	Delete(ctx context.Context, path string) error
+}
+
+// Scan returns the current version of a file, without a record
+func Scan(path string) (Entry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return Entry{}, fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer func() { _ = f.Close() }()
+
+	// Stat before reading, so a file modified meanwhile is hashed again next time
+	info, err := f.Stat()
+	if err != nil {
+		return Entry{}, fmt.Errorf("failed to stat %s: %w", path, err)
+	}
+	hash := sha256.New()
+	if _, err := io.Copy(hash, f); err != nil {
+		return Entry{}, fmt.Errorf("failed to hash %s: %w", path, err)
+	}
+	return Entry{Path: path, SHA256: hex.EncodeToString(hash.Sum(nil)), ModTime: info.ModTime()}, nil
+}
+
+// Unchanged reports whether a file last modified at modTime is the version
+// of its entry. A file touched but unchanged has its new modification time
+// put in the store, so it is not hashed again.
+func Unchanged(ctx context.Context, store Store, entry Entry, modTime time.Time) bool {
+	if modTime.Equal(entry.ModTime) {
+		return true
+	}
+	scan, err := Scan(entry.Path)
+	if err != nil || scan.SHA256 != entry.SHA256 {
+		return false
+	}
+	scan.RecordID = entry.RecordID
+	if err := store.Put(ctx, scan); err != nil {
+		slog.WarnContext(ctx, "Failed to update scan ledger", "path", entry.Path, "error", err)
+	}
+	return true
+}
+
+// Stamp names the file version in the metadata of the record extracted from it
+func (e Entry) Stamp(record records.Record) records.Record {
+	if record.Metadata == nil {
+		record.Metadata = map[string]interface{}{}
+	}
+	record.Metadata[records.MetaSourceSHA256] = e.SHA256
+	record.Metadata[records.MetaSourceModTime] = e.ModTime.Format(time.RFC3339Nano)
+	if e.Version != "" {
+		record.Metadata[records.MetaSourceVersion] = e.Version
+	}
+	return record
+}
+
+// Stamped returns the entry of the file version a record was stamped with,
+// and false for records that were not
+func Stamped(record records.Record) (Entry, bool) {
+	sum, _ := record.Metadata[records.MetaSourceSHA256].(string)
+	stamp, _ := record.Metadata[records.MetaSourceModTime].(string)
+	version, _ := record.Metadata[records.MetaSourceVersion].(string)
+	modTime, err := time.Parse(time.RFC3339Nano, stamp)
+	if sum == "" || record.SourcePath() == "" || err != nil {
+		return Entry{}, false
+	}
+	return Entry{Path: record.SourcePath(), SHA256: sum, ModTime: modTime, Version: version, RecordID: record.ID}, true
+}
@@ -0,0 +1,64 @@
+package ledger_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/kazemisoroush/assistant/pkg/records"
+	"github.com/kazemisoroush/assistant/pkg/records/ledger"
+	"github.com/kazemisoroush/assistant/pkg/records/ledger/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestScan_StampStamped(t *testing.T) {
+	// Arrange
+	path := filepath.Join(t.TempDir(), "receipt.txt")
+	require.NoError(t, os.WriteFile(path, []byte("hello"), 0600))
+
+	// Act
+	scan, err := ledger.Scan(path)
+	require.NoError(t, err, "Scan() error should be nil")
+	record := scan.Stamp(records.Record{ID: "rec-1", FilePath: path})
+	stamped, ok := ledger.Stamped(record)
+
+	// Assert
+	assert.Equal(t, "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824", scan.SHA256, "Scan() should hash the file content")
+	require.True(t, ok, "Stamped() should find the stamp of a stamped record")
+	assert.Equal(t, path, stamped.Path, "Stamped() should name the record's file")
+	assert.Equal(t, scan.SHA256, stamped.SHA256, "Stamped() should return the stamped digest")
+	assert.True(t, scan.ModTime.Equal(stamped.ModTime), "Stamped() should return the stamped modification time")
+	assert.Equal(t, "rec-1", stamped.RecordID, "Stamped() should return the record's ID")
+	_, ok = ledger.Stamped(records.Record{ID: "rec-2", FilePath: path})
+	assert.False(t, ok, "Stamped() should ignore records without a stamp")
+	remote, ok := ledger.Stamped(ledger.Entry{SHA256: "abc", Version: "etag-1"}.Stamp(records.Record{ID: "rec-3", FilePath: "webdav:/a.pdf"}))
+	require.True(t, ok, "Stamped() should find the stamp of a remote file's record")
+	assert.Equal(t, "etag-1", remote.Version, "Stamped() should return the stamped version")
+}
+
+func TestUnchanged(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	store := mocks.NewMockStore(ctrl)
+	path := filepath.Join(t.TempDir(), "receipt.txt")
+	require.NoError(t, os.WriteFile(path, []byte("hello"), 0600))
+	scan, err := ledger.Scan(path)
+	require.NoError(t, err, "Scan() error should be nil")
+	entry := ledger.Entry{Path: path, SHA256: scan.SHA256, ModTime: scan.ModTime.Add(-time.Hour), RecordID: "rec-1"}
+	store.EXPECT().Put(gomock.Any(), ledger.Entry{Path: path, SHA256: scan.SHA256, ModTime: scan.ModTime, RecordID: "rec-1"}).Return(nil)
+
+	// Act
+	same := ledger.Unchanged(ctx, store, ledger.Entry{Path: path, ModTime: scan.ModTime}, scan.ModTime)
+	touched := ledger.Unchanged(ctx, store, entry, scan.ModTime)
+	changed := ledger.Unchanged(ctx, store, ledger.Entry{Path: path, SHA256: "other", RecordID: "rec-1"}, scan.ModTime)
+
+	// Assert
+	assert.True(t, same, "Unchanged() should not hash a file with the entry's modification time")
+	assert.True(t, touched, "Unchanged() should find a touched file with the entry's content unchanged")
+	assert.False(t, changed, "Unchanged() should find a file with other content changed")
+}
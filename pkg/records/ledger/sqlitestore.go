@@ -0,0 +1,142 @@
+package ledger
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/kazemisoroush/assistant/pkg/sqlite"
+)
+
+// SQLiteStore is a Store backed by SQLite
+type SQLiteStore struct {
+	db *sqlite.DB
+}
+
+// NewSQLiteStore creates a new SQLite ledger store in the given database
+func NewSQLiteStore(db *sqlite.DB) (*SQLiteStore, error) {
+	// Modification times are kept in nanoseconds, so they compare exactly.
+	// Remote files, which have none, are kept as 0.
+	schema := `
+    CREATE TABLE IF NOT EXISTS scan_ledger (
+        path TEXT PRIMARY KEY,
+        sha256 TEXT NOT NULL,
+        mod_time INTEGER NOT NULL,
+        version TEXT NOT NULL DEFAULT '',
+        record_id TEXT NOT NULL
+    );
+    `
+	if _, err := db.Exec(schema); err != nil {
+		return nil, fmt.Errorf("failed to initialize ledger schema: %w", err)
+	}
+	// Ledgers created before versions were kept lack the column
+	var versioned bool
+	if err := db.QueryRow(`SELECT COUNT(*) > 0 FROM pragma_table_info('scan_ledger') WHERE name = 'version'`).Scan(&versioned); err != nil {
+		return nil, fmt.Errorf("failed to read ledger schema: %w", err)
+	}
+	if !versioned {
+		if _, err := db.Exec(`ALTER TABLE scan_ledger ADD COLUMN version TEXT NOT NULL DEFAULT ''`); err != nil {
+			return nil, fmt.Errorf("failed to add ledger version: %w", err)
+		}
+	}
+
+	return &SQLiteStore{db: db}, nil
+}
+
+// Get implements Store
+func (s *SQLiteStore) Get(ctx context.Context, path string) (Entry, bool, error) {
+	entry := Entry{Path: path}
+	var modTime int64
+	err := s.db.QueryRowContext(ctx,
+		`SELECT sha256, mod_time, version, record_id FROM scan_ledger WHERE path = ?`, path,
+	).Scan(&entry.SHA256, &modTime, &entry.Version, &entry.RecordID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return Entry{}, false, nil
+	}
+	if err != nil {
+		return Entry{}, false, fmt.Errorf("failed to get ledger entry of %s: %w", path, err)
+	}
+	entry.ModTime = fromUnixNano(modTime)
+	return entry, true, nil
+}
+
+// List implements Store. Files under the working directory are walked into
+// relative paths, so "." lists the relative paths, leaving out those of parent
+// directories and of remote files, which are kept under a scheme such as
+// webdav: and so have a colon.
+func (s *SQLiteStore) List(ctx context.Context, dir string) ([]Entry, error) {
+	query := `SELECT path, sha256, mod_time, version, record_id FROM scan_ledger WHERE substr(path, 1, ?) = ? ORDER BY path`
+	var args []any
+	switch dir = filepath.Clean(dir); dir {
+	case ".":
+		query = `SELECT path, sha256, mod_time, version, record_id FROM scan_ledger
+            WHERE path NOT LIKE '/%' AND path NOT LIKE '../%' AND instr(path, ':') = 0 ORDER BY path`
+	default:
+		prefix := dir
+		if !strings.HasSuffix(prefix, string(filepath.Separator)) {
+			prefix += string(filepath.Separator)
+		}
+		args = []any{len(prefix), prefix}
+	}
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list ledger entries under %s: %w", dir, err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var entries []Entry
+	for rows.Next() {
+		var entry Entry
+		var modTime int64
+		if err := rows.Scan(&entry.Path, &entry.SHA256, &modTime, &entry.Version, &entry.RecordID); err != nil {
+			return nil, fmt.Errorf("failed to scan ledger entry: %w", err)
+		}
+		entry.ModTime = fromUnixNano(modTime)
+		entries = append(entries, entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list ledger entries under %s: %w", dir, err)
+	}
+	return entries, nil
+}
+
+// Put implements Store
+func (s *SQLiteStore) Put(ctx context.Context, entry Entry) error {
+	if _, err := s.db.ExecContext(ctx,
+		`INSERT INTO scan_ledger (path, sha256, mod_time, version, record_id) VALUES (?, ?, ?, ?, ?)
+         ON CONFLICT (path) DO UPDATE SET sha256 = excluded.sha256, mod_time = excluded.mod_time,
+             version = excluded.version, record_id = excluded.record_id`,
+		entry.Path, entry.SHA256, unixNano(entry.ModTime), entry.Version, entry.RecordID,
+	); err != nil {
+		return fmt.Errorf("failed to put ledger entry of %s: %w", entry.Path, err)
+	}
+	return nil
+}
+
+// Delete implements Store
+func (s *SQLiteStore) Delete(ctx context.Context, path string) error {
+	if _, err := s.db.ExecContext(ctx, `DELETE FROM scan_ledger WHERE path = ?`, path); err != nil {
+		return fmt.Errorf("failed to delete ledger entry of %s: %w", path, err)
+	}
+	return nil
+}
+
+// unixNano returns a modification time in nanoseconds, or 0 without one
+func unixNano(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.UnixNano()
+}
+
+// fromUnixNano returns the modification time kept in nanoseconds
+func fromUnixNano(n int64) time.Time {
+	if n == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, n)
+}
@@ -0,0 +1,93 @@
+package ledger_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/kazemisoroush/assistant/pkg/records/ledger"
+	"github.com/kazemisoroush/assistant/pkg/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// openDB opens the database at path, closed once the test is done
+func openDB(t testing.TB, path string) *sqlite.DB {
+	t.Helper()
+	db, err := sqlite.Open(path)
+	require.NoError(t, err, "Open() error should be nil")
+	t.Cleanup(func() { _ = db.Close() })
+	return db
+}
+
+func TestSQLiteStore_PutGetListDelete(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	store, err := ledger.NewSQLiteStore(openDB(t, filepath.Join(t.TempDir(), "assistant.db")))
+	require.NoError(t, err, "NewSQLiteStore() error should be nil")
+	modTime := time.Date(2024, 5, 1, 12, 0, 0, 123456789, time.UTC)
+	scan := ledger.Entry{Path: filepath.Join("docs", "scans", "a.pdf"), SHA256: "abc", ModTime: modTime, RecordID: "rec-1"}
+	other := ledger.Entry{Path: filepath.Join("docs", "scansold", "b.pdf"), SHA256: "def", ModTime: modTime, RecordID: "rec-2"}
+
+	// Act
+	require.NoError(t, store.Put(ctx, scan), "Put() error should be nil")
+	require.NoError(t, store.Put(ctx, other), "Put() error should be nil")
+	scan.RecordID = "rec-3"
+	require.NoError(t, store.Put(ctx, scan), "Put() should replace the entry of a file")
+
+	// Assert
+	got, ok, err := store.Get(ctx, scan.Path)
+	require.NoError(t, err, "Get() error should be nil")
+	require.True(t, ok, "Get() should find the entry put")
+	assert.Equal(t, "rec-3", got.RecordID, "Get() should return the entry put last")
+	assert.True(t, modTime.Equal(got.ModTime), "Get() should keep the modification time to the nanosecond")
+
+	listed, err := store.List(ctx, filepath.Join("docs", "scans"))
+	require.NoError(t, err, "List() error should be nil")
+	require.Len(t, listed, 1, "List() should only return the entries under the directory")
+	assert.Equal(t, scan.Path, listed[0].Path, "List() should return the entry under the directory")
+
+	require.NoError(t, store.Delete(ctx, scan.Path), "Delete() error should be nil")
+	_, ok, err = store.Get(ctx, scan.Path)
+	require.NoError(t, err, "Get() error should be nil without an entry")
+	assert.False(t, ok, "Get() should not find a deleted entry")
+}
+
+func TestSQLiteStore_PutGet_RemoteFile(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	store, err := ledger.NewSQLiteStore(openDB(t, filepath.Join(t.TempDir(), "assistant.db")))
+	require.NoError(t, err, "NewSQLiteStore() error should be nil")
+	remote := ledger.Entry{Path: "webdav:/Scans/a.pdf", SHA256: "abc", Version: "etag-1", RecordID: "rec-1"}
+
+	// Act
+	require.NoError(t, store.Put(ctx, remote), "Put() error should be nil")
+	got, ok, err := store.Get(ctx, remote.Path)
+
+	// Assert
+	require.NoError(t, err, "Get() error should be nil")
+	require.True(t, ok, "Get() should find the entry put")
+	assert.Equal(t, remote, got, "Get() should return the version of a remote file without a modification time")
+}
+
+func TestSQLiteStore_List_WorkingDirectory(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	store, err := ledger.NewSQLiteStore(openDB(t, filepath.Join(t.TempDir(), "assistant.db")))
+	require.NoError(t, err, "NewSQLiteStore() error should be nil")
+	for _, path := range []string{"a.pdf", filepath.Join("scans", "b.pdf"), "/docs/c.pdf", filepath.Join("..", "d.pdf"), "webdav:/Scans/e.pdf", "gdrive://f"} {
+		require.NoError(t, store.Put(ctx, ledger.Entry{Path: path, SHA256: "abc", RecordID: "rec-" + path}), "Put() error should be nil")
+	}
+
+	// Act
+	listed, err := store.List(ctx, ".")
+
+	// Assert
+	require.NoError(t, err, "List() error should be nil")
+	paths := make([]string, 0, len(listed))
+	for _, entry := range listed {
+		paths = append(paths, entry.Path)
+	}
+	assert.Equal(t, []string{"a.pdf", filepath.Join("scans", "b.pdf")}, paths, "List() should return the relative local paths under the working directory")
+}
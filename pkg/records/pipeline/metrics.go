@@ -0,0 +1,247 @@
+package pipeline
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Stages timed for every record, in the order a record passes them
+const (
+	StageRead     = "read"             // Reading the document
+	StageOCR      = "ocr"              // Transcribing an image
+	StageClassify = "classify"         // Classifying the record type
+	StageMetadata = "extract_metadata" // Extracting type-specific metadata
+	StageStore    = "store"            // Storing the record
+	StageIndex    = "index"            // Indexing, including the batch writes an ingest triggers
+)
+
+// stageOrder sorts the stages in summaries
+var stageOrder = []string{StageRead, StageOCR, StageClassify, StageMetadata, StageStore, StageIndex}
+
+// unknownType labels the timings of records that failed before they were classified
+const unknownType = "unknown"
+
+// latencyBuckets are the upper bounds of the histogram buckets in seconds
+var latencyBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60}
+
+// histogram counts the latencies of one stage for one record type
+type histogram struct {
+	counts []int64 // Per bucket, the last one counts latencies above every bound
+	count  int64
+	errors int64
+	sum    time.Duration
+}
+
+// observe counts a latency
+func (h *histogram) observe(d time.Duration, failed bool) {
+	i, _ := slices.BinarySearch(latencyBuckets, d.Seconds())
+	h.counts[i]++
+	h.count++
+	h.sum += d
+	if failed {
+		h.errors++
+	}
+}
+
+// quantile estimates the latency below which q of the observations fall, as
+// the upper bound of the bucket holding it
+func (h *histogram) quantile(q float64) time.Duration {
+	rank := int64(q * float64(h.count))
+	var seen int64
+	for i, n := range h.counts {
+		seen += n
+		if seen > rank && i < len(latencyBuckets) {
+			return time.Duration(latencyBuckets[i] * float64(time.Second))
+		}
+	}
+	return time.Duration(latencyBuckets[len(latencyBuckets)-1] * float64(time.Second))
+}
+
+// metricKey identifies the histogram of a stage for a record type
+type metricKey struct {
+	stage      string
+	recordType string
+}
+
+// Metrics keeps a latency histogram and error counter per stage and record
+// type. It implements expvar.Var, so it can be published on the expvar
+// endpoint, and is safe for concurrent use. A nil Metrics records nothing.
+type Metrics struct {
+	mu         sync.Mutex
+	histograms map[metricKey]*histogram
+}
+
+// NewMetrics creates empty metrics
+func NewMetrics() *Metrics {
+	return &Metrics{histograms: map[metricKey]*histogram{}}
+}
+
+// Observe counts how long a stage took for a record of the given type
+func (m *Metrics) Observe(stage, recordType string, d time.Duration, failed bool) {
+	if m == nil {
+		return
+	}
+	if recordType == "" {
+		recordType = unknownType
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	key := metricKey{stage: stage, recordType: recordType}
+	h, ok := m.histograms[key]
+	if !ok {
+		h = &histogram{counts: make([]int64, len(latencyBuckets)+1)}
+		m.histograms[key] = h
+	}
+	h.observe(d, failed)
+}
+
+// Record observes the stages timed for a record once its type is known
+func (m *Metrics) Record(timings *Timings, recordType string) {
+	if m == nil || timings == nil {
+		return
+	}
+	for _, span := range timings.take() {
+		m.Observe(span.stage, recordType, span.duration, span.failed)
+	}
+}
+
+// StageSummary summarizes the latencies of a stage for a record type
+type StageSummary struct {
+	Stage      string
+	RecordType string
+	Count      int64
+	Errors     int64
+	Total      time.Duration
+	Mean       time.Duration
+	P95        time.Duration // Upper bound of the bucket holding the 95th percentile
+}
+
+// Summary returns a summary per stage and record type, in stage order
+func (m *Metrics) Summary() []StageSummary {
+	if m == nil {
+		return nil
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	summaries := make([]StageSummary, 0, len(m.histograms))
+	for key, h := range m.histograms {
+		summaries = append(summaries, StageSummary{
+			Stage:      key.stage,
+			RecordType: key.recordType,
+			Count:      h.count,
+			Errors:     h.errors,
+			Total:      h.sum,
+			Mean:       h.sum / time.Duration(h.count),
+			P95:        h.quantile(0.95),
+		})
+	}
+	slices.SortFunc(summaries, func(a, b StageSummary) int {
+		if byStage := slices.Index(stageOrder, a.Stage) - slices.Index(stageOrder, b.Stage); byStage != 0 {
+			return byStage
+		}
+		if a.RecordType < b.RecordType {
+			return -1
+		}
+		if a.RecordType > b.RecordType {
+			return 1
+		}
+		return 0
+	})
+	return summaries
+}
+
+// histogramJSON is a histogram as published on the expvar endpoint
+type histogramJSON struct {
+	Count      int64            `json:"count"`
+	Errors     int64            `json:"errors"`
+	SumSeconds float64          `json:"sum_seconds"`
+	Buckets    map[string]int64 `json:"buckets"` // Cumulative counts keyed by upper bound in seconds
+}
+
+// String implements expvar.Var, rendering the histograms keyed by stage and record type
+func (m *Metrics) String() string {
+	m.mu.Lock()
+	stages := map[string]map[string]histogramJSON{}
+	for key, h := range m.histograms {
+		if stages[key.stage] == nil {
+			stages[key.stage] = map[string]histogramJSON{}
+		}
+		stages[key.stage][key.recordType] = h.json()
+	}
+	m.mu.Unlock()
+
+	data, err := json.Marshal(stages)
+	if err != nil {
+		return "{}"
+	}
+	return string(data)
+}
+
+// json renders the histogram with cumulative bucket counts
+func (h *histogram) json() histogramJSON {
+	buckets := make(map[string]int64, len(h.counts))
+	var cumulative int64
+	for i, n := range h.counts {
+		cumulative += n
+		bound := "+Inf"
+		if i < len(latencyBuckets) {
+			bound = strconv.FormatFloat(latencyBuckets[i], 'g', -1, 64)
+		}
+		buckets[bound] = cumulative
+	}
+	return histogramJSON{Count: h.count, Errors: h.errors, SumSeconds: h.sum.Seconds(), Buckets: buckets}
+}
+
+// span is the time one stage took for a record
+type span struct {
+	stage    string
+	duration time.Duration
+	failed   bool
+}
+
+// Timings collects the stages timed for a record until its type is known. It
+// is safe for concurrent use.
+type Timings struct {
+	mu    sync.Mutex
+	spans []span
+}
+
+// timingsKey is the context key of the record's Timings
+type timingsKey struct{}
+
+// WithTimings returns a context collecting the stage timings of a record
+func WithTimings(ctx context.Context) (context.Context, *Timings) {
+	timings := &Timings{}
+	return context.WithValue(ctx, timingsKey{}, timings), timings
+}
+
+// Track starts timing a stage of the record ctx collects timings for. Call
+// the returned function with the stage's error once it is done. Without
+// Timings in ctx nothing is timed.
+func Track(ctx context.Context, stage string) func(err error) {
+	timings, _ := ctx.Value(timingsKey{}).(*Timings)
+	if timings == nil {
+		return func(error) {}
+	}
+	start := time.Now()
+	return func(err error) {
+		timings.mu.Lock()
+		defer timings.mu.Unlock()
+		timings.spans = append(timings.spans, span{stage: stage, duration: time.Since(start), failed: err != nil})
+	}
+}
+
+// take returns the timed stages and clears them
+func (t *Timings) take() []span {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	spans := t.spans
+	t.spans = nil
+	return spans
+}
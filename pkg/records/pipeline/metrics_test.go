@@ -0,0 +1,91 @@
+package pipeline_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/kazemisoroush/assistant/pkg/records/pipeline"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetrics_Summary(t *testing.T) {
+	// Arrange
+	metrics := pipeline.NewMetrics()
+	for i := 0; i < 19; i++ {
+		metrics.Observe(pipeline.StageOCR, "receipt", 20*time.Millisecond, false)
+	}
+	metrics.Observe(pipeline.StageOCR, "receipt", 3*time.Second, true)
+	metrics.Observe(pipeline.StageRead, "receipt", time.Millisecond, false)
+	metrics.Observe(pipeline.StageRead, "", time.Millisecond, true)
+
+	// Act
+	summary := metrics.Summary()
+
+	// Assert
+	require.Len(t, summary, 3, "Summary() should return one entry per stage and record type")
+	assert.Equal(t, pipeline.StageRead, summary[0].Stage, "Summary() should list stages in pipeline order")
+	assert.Equal(t, "receipt", summary[0].RecordType, "Summary() should sort record types")
+	assert.Equal(t, "unknown", summary[1].RecordType, "Summary() should label unclassified records")
+	ocr := summary[2]
+	assert.Equal(t, int64(20), ocr.Count, "Summary() should count every observation")
+	assert.Equal(t, int64(1), ocr.Errors, "Summary() should count failures")
+	assert.Equal(t, 19*20*time.Millisecond+3*time.Second, ocr.Total, "Summary() should sum the latencies")
+	assert.Equal(t, 5*time.Second, ocr.P95, "Summary() should report the bucket holding the 95th percentile")
+}
+
+func TestMetrics_Record(t *testing.T) {
+	// Arrange
+	metrics := pipeline.NewMetrics()
+	ctx, timings := pipeline.WithTimings(context.Background())
+	pipeline.Track(ctx, pipeline.StageClassify)(nil)
+	pipeline.Track(ctx, pipeline.StageMetadata)(errors.New("model unavailable"))
+
+	// Act
+	metrics.Record(timings, "travel")
+	metrics.Record(timings, "travel")
+
+	// Assert
+	summary := metrics.Summary()
+	require.Len(t, summary, 2, "Record() should observe every tracked stage")
+	assert.Equal(t, pipeline.StageClassify, summary[0].Stage, "Record() should observe the classify stage")
+	assert.Equal(t, int64(1), summary[0].Count, "Record() should observe the timings once")
+	assert.Equal(t, int64(1), summary[1].Errors, "Record() should count the failed stage")
+}
+
+func TestTrack_WithoutTimings(t *testing.T) {
+	// Arrange
+	var metrics *pipeline.Metrics
+
+	// Act
+	pipeline.Track(context.Background(), pipeline.StageStore)(nil)
+	metrics.Observe(pipeline.StageStore, "receipt", time.Second, false)
+
+	// Assert
+	assert.Empty(t, metrics.Summary(), "nil Metrics should record nothing")
+}
+
+func TestMetrics_String(t *testing.T) {
+	// Arrange
+	metrics := pipeline.NewMetrics()
+	metrics.Observe(pipeline.StageIndex, "receipt", 30*time.Millisecond, false)
+	metrics.Observe(pipeline.StageIndex, "receipt", 2*time.Minute, false)
+
+	// Act
+	var got map[string]map[string]struct {
+		Count   int64            `json:"count"`
+		Buckets map[string]int64 `json:"buckets"`
+	}
+	err := json.Unmarshal([]byte(metrics.String()), &got)
+
+	// Assert
+	require.NoError(t, err, "String() should render JSON")
+	index := got[pipeline.StageIndex]["receipt"]
+	assert.Equal(t, int64(2), index.Count, "String() should include the count")
+	assert.Equal(t, int64(0), index.Buckets["0.025"], "String() should count cumulatively per bucket")
+	assert.Equal(t, int64(1), index.Buckets["0.05"], "String() should count cumulatively per bucket")
+	assert.Equal(t, int64(2), index.Buckets["+Inf"], "String() should count latencies above every bound")
+}
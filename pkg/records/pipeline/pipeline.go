@@ -0,0 +1,174 @@
+// Package pipeline runs ingestion as stages connected by bounded queues, so
+// slow stages such as OCR and language model calls overlap with disk I/O,
+// storage and indexing. Every stage has its own number of workers.
+//
+// A stage whose queue is full waits for the next stage, so work never piles
+// up ahead of a slow stage. Such waits are counted as the stage's Pressure,
+// which tells which stage to give more workers.
+package pipeline
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// StageConfig represents how many workers run a stage and how many results it buffers
+type StageConfig struct {
+	Workers  int       // Goroutines processing the stage, at least one
+	Queue    int       // Results buffered for the next stage
+	Pressure *Pressure // Counts the waits on a full queue, optional
+	Metrics  *Metrics  // Receives the stage timings of the records processed, optional
+}
+
+// Pressure counts how often and how long a stage waited to hand results to a
+// slower stage. It is safe for concurrent use.
+type Pressure struct {
+	waits  atomic.Int64
+	waited atomic.Int64 // Nanoseconds
+}
+
+// Waits returns how often a result waited for room in a full queue
+func (p *Pressure) Waits() int64 {
+	return p.waits.Load()
+}
+
+// Waited returns how long results waited for room in full queues in total
+func (p *Pressure) Waited() time.Duration {
+	return time.Duration(p.waited.Load())
+}
+
+// record counts a wait. A nil Pressure counts nothing.
+func (p *Pressure) record(d time.Duration) {
+	if p == nil {
+		return
+	}
+	p.waits.Add(1)
+	p.waited.Add(int64(d))
+}
+
+// Send delivers v unless ctx is done first and reports whether it was
+// delivered. A wait for room in the channel is counted in pressure, which may be nil.
+func Send[T any](ctx context.Context, ch chan<- T, v T, pressure *Pressure) bool {
+	select {
+	case ch <- v:
+		return true
+	default:
+	}
+
+	start := time.Now()
+	select {
+	case ch <- v:
+		pressure.record(time.Since(start))
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// Run starts a stage whose workers apply fn to every item received from in
+// and send the results to the returned channel. The channel is closed once in
+// is closed and drained, or ctx is done. Failed items are dropped and their
+// errors sent to errs, which the caller must drain.
+func Run[In, Out any](ctx context.Context, cfg StageConfig, in <-chan In, fn func(context.Context, In) (Out, error), errs chan<- error) <-chan Out {
+	out := make(chan Out, max(cfg.Queue, 0))
+
+	var wg sync.WaitGroup
+	for range max(cfg.Workers, 1) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			work(ctx, in, fn, out, errs, cfg.Pressure)
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(out)
+	}()
+	return out
+}
+
+// work processes items until in is closed or ctx is done
+func work[In, Out any](ctx context.Context, in <-chan In, fn func(context.Context, In) (Out, error), out chan<- Out, errs chan<- error, pressure *Pressure) {
+	for {
+		var item In
+		select {
+		case <-ctx.Done():
+			return
+		case next, ok := <-in:
+			if !ok {
+				return
+			}
+			item = next
+		}
+
+		result, err := fn(ctx, item)
+		if err != nil {
+			select {
+			case errs <- err:
+			case <-ctx.Done():
+				return
+			}
+			continue
+		}
+		if !Send(ctx, out, result, pressure) {
+			return
+		}
+	}
+}
+
+// Batch groups the items received from in into batches of up to size items
+// and sends them to the returned channel. A partial batch is sent once it
+// waited for timeout, where 0 waits until it is full, and once in is closed.
+// The channel is closed once in is closed and drained, or ctx is done.
+func Batch[T any](ctx context.Context, in <-chan T, size int, timeout time.Duration) <-chan []T {
+	out := make(chan []T)
+
+	go func() {
+		defer close(out)
+		collect(ctx, in, out, size, timeout)
+	}()
+
+	return out
+}
+
+// collect sends the batches of the items received until in is closed or ctx is done
+func collect[T any](ctx context.Context, in <-chan T, out chan<- []T, size int, timeout time.Duration) {
+	var batch []T
+	var expired <-chan time.Time // Set while a partial batch waits
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case item, ok := <-in:
+			if !ok {
+				if len(batch) > 0 {
+					Send(ctx, out, batch, nil)
+				}
+				return
+			}
+			batch = append(batch, item)
+			if len(batch) == 1 {
+				expired = after(timeout)
+			}
+			if len(batch) < size {
+				continue
+			}
+		case <-expired:
+		}
+		if !Send(ctx, out, batch, nil) {
+			return
+		}
+		batch, expired = nil, nil
+	}
+}
+
+// after returns a channel receiving once the timeout passed, or nil, which
+// never receives, for a timeout of 0
+func after(timeout time.Duration) <-chan time.Time {
+	if timeout <= 0 {
+		return nil
+	}
+	return time.After(timeout)
+}
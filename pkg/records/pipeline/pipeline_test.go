@@ -0,0 +1,132 @@
+package pipeline_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/kazemisoroush/assistant/pkg/records/pipeline"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRun(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	in := make(chan int)
+	go func() {
+		defer close(in)
+		for i := 1; i <= 10; i++ {
+			in <- i
+		}
+	}()
+	errs := make(chan error, 10)
+	double := func(_ context.Context, i int) (int, error) {
+		if i%5 == 0 {
+			return 0, fmt.Errorf("item %d failed", i)
+		}
+		return i * 2, nil
+	}
+
+	// Act
+	out := pipeline.Run(ctx, pipeline.StageConfig{Workers: 3, Queue: 2}, in, double, errs)
+	var results []int
+	for result := range out {
+		results = append(results, result)
+	}
+	close(errs)
+
+	// Assert
+	sort.Ints(results)
+	assert.Equal(t, []int{2, 4, 6, 8, 12, 14, 16, 18}, results, "Run() should send every successful result")
+	var failed []error
+	for err := range errs {
+		failed = append(failed, err)
+	}
+	assert.Len(t, failed, 2, "Run() should report every failed item")
+}
+
+func TestRun_Cancelled(t *testing.T) {
+	// Arrange
+	ctx, cancel := context.WithCancel(context.Background())
+	in := make(chan int)
+	errs := make(chan error)
+	blocked := func(ctx context.Context, _ int) (int, error) {
+		<-ctx.Done()
+		return 0, errors.New("cancelled")
+	}
+	out := pipeline.Run(ctx, pipeline.StageConfig{Workers: 2}, in, blocked, errs)
+	in <- 1
+
+	// Act
+	cancel()
+
+	// Assert
+	for range out {
+		assert.Fail(t, "Run() should not send results after cancellation")
+	}
+}
+
+func TestRun_Pressure(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	in := make(chan int, 1)
+	in <- 1
+	close(in)
+	pressure := &pipeline.Pressure{}
+	identity := func(_ context.Context, i int) (int, error) {
+		return i, nil
+	}
+
+	// Act
+	out := pipeline.Run(ctx, pipeline.StageConfig{Workers: 1, Pressure: pressure}, in, identity, make(chan error))
+	time.Sleep(20 * time.Millisecond)
+	for range out {
+	}
+
+	// Assert
+	assert.Equal(t, int64(1), pressure.Waits(), "Run() should count a wait for a slower stage")
+	assert.Positive(t, pressure.Waited(), "Run() should measure the wait for a slower stage")
+}
+
+func TestBatch(t *testing.T) {
+	// Arrange
+	in := make(chan int)
+	go func() {
+		defer close(in)
+		for i := 1; i <= 5; i++ {
+			in <- i
+		}
+	}()
+
+	// Act
+	var batches [][]int
+	for batch := range pipeline.Batch(context.Background(), in, 2, 0) {
+		batches = append(batches, batch)
+	}
+
+	// Assert
+	assert.Equal(t, [][]int{{1, 2}, {3, 4}, {5}}, batches, "Batch() should send full batches and the partial batch left")
+}
+
+func TestBatch_Timeout(t *testing.T) {
+	// Arrange
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	in := make(chan int)
+	defer close(in)
+
+	// Act
+	out := pipeline.Batch(ctx, in, 10, 10*time.Millisecond)
+	in <- 1
+	var batch []int
+	select {
+	case batch = <-out:
+	case <-ctx.Done():
+	}
+
+	// Assert
+	assert.Equal(t, []int{1}, batch, "Batch() should send a partial batch once it timed out")
+}
@@ -0,0 +1,103 @@
+package records
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"sort"
+	"sync"
+)
+
+// Schema constrains the metadata of a built-in record type. User-defined types
+// carry their schema in the Fields of their definition instead.
+type Schema struct {
+	Type   RecordType    `json:"type"`
+	Fields []FieldSchema `json:"fields"`
+}
+
+// Validate checks that the schema is for a built-in type and its fields are well-formed
+func (s Schema) Validate() error {
+	if !slices.Contains(builtinRecordTypes(), s.Type) {
+		return fmt.Errorf("not a built-in record type: %s", s.Type)
+	}
+	if len(s.Fields) == 0 {
+		return fmt.Errorf("schema of %s has no fields", s.Type)
+	}
+	return validateFields(s.Fields)
+}
+
+// schemas holds the registered schemas of built-in record types
+var schemas = struct {
+	sync.RWMutex
+	byType map[RecordType]Schema
+}{byType: map[RecordType]Schema{}}
+
+// RegisterSchema makes extraction and updates of a built-in record type validate against the schema
+func RegisterSchema(schema Schema) error {
+	if err := schema.Validate(); err != nil {
+		return fmt.Errorf("invalid schema: %w", err)
+	}
+	schemas.Lock()
+	defer schemas.Unlock()
+	schemas.byType[schema.Type] = schema
+	return nil
+}
+
+// UnregisterSchema removes the schema of a built-in record type
+func UnregisterSchema(recordType RecordType) {
+	schemas.Lock()
+	defer schemas.Unlock()
+	delete(schemas.byType, recordType)
+}
+
+// Schemas returns the registered schemas of built-in record types sorted by type
+func Schemas() []Schema {
+	schemas.RLock()
+	defer schemas.RUnlock()
+	result := make([]Schema, 0, len(schemas.byType))
+	for _, schema := range schemas.byType {
+		result = append(result, schema)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Type < result[j].Type })
+	return result
+}
+
+// SchemaFields returns the fields the metadata of a record type is validated
+// against: the fields of a user-defined type or the schema of a built-in one
+func SchemaFields(recordType RecordType) []FieldSchema {
+	if def, ok := CustomType(recordType); ok {
+		return def.Fields
+	}
+	schemas.RLock()
+	defer schemas.RUnlock()
+	return schemas.byType[recordType].Fields
+}
+
+// ValidateMetadata checks the metadata against the schema of the record type
+func ValidateMetadata(recordType RecordType, metadata map[string]interface{}) error {
+	for _, field := range SchemaFields(recordType) {
+		if err := field.Check(metadata[field.Name]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ValidateUpdate checks an updated record against the schema of its type. Only
+// the fields the update changes are checked, so records stored before the
+// schema was attached can still be updated, unless the update changes the type.
+func ValidateUpdate(before, after Record) error {
+	if before.Type != after.Type {
+		return ValidateMetadata(after.Type, after.Metadata)
+	}
+	for _, field := range SchemaFields(after.Type) {
+		value := after.Metadata[field.Name]
+		if reflect.DeepEqual(before.Metadata[field.Name], value) {
+			continue
+		}
+		if err := field.Check(value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
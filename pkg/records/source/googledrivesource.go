@@ -0,0 +1,324 @@
+package source
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"slices"
+	"strings"
+
+	"github.com/kazemisoroush/assistant/pkg/gdrive"
+	"github.com/kazemisoroush/assistant/pkg/records"
+	"github.com/kazemisoroush/assistant/pkg/records/extractor"
+	"github.com/kazemisoroush/assistant/pkg/records/ledger"
+	"github.com/kazemisoroush/assistant/pkg/records/pipeline"
+	"github.com/kazemisoroush/assistant/pkg/records/storage"
+)
+
+// googleDrivePathPrefix starts the source path of records scraped from Google Drive
+const googleDrivePathPrefix = "gdrive://"
+
+// exportTypes are the media types Google Docs editor files are exported as
+var exportTypes = map[string]string{
+	gdrive.MimeTypeDocument:     "text/plain",
+	gdrive.MimeTypeSpreadsheet:  "text/csv",
+	gdrive.MimeTypePresentation: "text/plain",
+}
+
+// googleAppsPrefix starts the media types of Google Docs editor files
+const googleAppsPrefix = "application/vnd.google-apps."
+
+// GoogleDriveConfig represents the Drive folder a source syncs, where it
+// keeps its sync state and how many files it downloads and extracts at once
+type GoogleDriveConfig struct {
+	FolderID  string               // Folder synced with its subfolders; empty syncs the whole drive
+	StatePath string               // File holding the page token changes are listed from
+	Extract   pipeline.StageConfig // Downloads files and extracts their records
+	Buffers   Buffers
+}
+
+// GoogleDriveSource reads the files of a Google Drive folder. The first
+// scrape reads every file; later scrapes read the files changed since the
+// records of the last scrape's files were ingested. Google Docs editor files
+// are exported as text and other files are extracted by their media type, so
+// images are transcribed. Files are downloaded and extracted by the workers
+// of the extract stage.
+//
+// Records are stamped with the version of their file, and files whose
+// version is in the ledger are skipped. The ledger ingestor records the
+// version once the record is ingested and deletes the record of the file's
+// previous version. The records of files removed, trashed or moved out of
+// the folder are deleted.
+type GoogleDriveSource struct {
+	client    gdrive.Client
+	extractor extractor.ContentExtractor
+	ledger    ledger.Store
+	deleter   Deleter
+	config    GoogleDriveConfig
+}
+
+// NewGoogleDriveSource creates a new Google Drive source skipping the files
+// in the ledger and deleting the records of files removed with the deleter
+func NewGoogleDriveSource(client gdrive.Client, extractor extractor.ContentExtractor, ledger ledger.Store, deleter Deleter, config GoogleDriveConfig) Source {
+	return &GoogleDriveSource{
+		client:    client,
+		extractor: extractor,
+		ledger:    ledger,
+		deleter:   deleter,
+		config:    config,
+	}
+}
+
+// driveState is the sync state saved once the changes to sync are listed.
+// Changes are listed from the page token again until the records of all
+// their files were ingested, then from the next page token.
+type driveState struct {
+	PageToken     string            `json:"page_token"`
+	NextPageToken string            `json:"next_page_token"`
+	Files         map[string]string `json:"files"` // Version of every file to sync by ID
+}
+
+// Name returns the source name
+func (gs *GoogleDriveSource) Name() string {
+	return "gdrive"
+}
+
+// Scrape reads the files added or changed since the last scrape whose
+// records were all ingested, so files failing to be read or ingested are
+// read again by the next scrape
+func (gs *GoogleDriveSource) Scrape(ctx context.Context) (<-chan records.Record, <-chan error) {
+	recordChan := make(chan records.Record, max(gs.config.Buffers.Records, 0))
+	errChan := make(chan error, max(gs.config.Buffers.Errors, 1))
+
+	go func() {
+		defer close(recordChan)
+		defer close(errChan)
+		if err := gs.run(ctx, recordChan, errChan); err != nil {
+			pipeline.Send(ctx, errChan, err, nil)
+		}
+	}()
+
+	return recordChan, errChan
+}
+
+// run deletes the records of the files removed and sends the records of the
+// files to sync whose version is not in the ledger
+func (gs *GoogleDriveSource) run(ctx context.Context, recordChan chan<- records.Record, errChan chan<- error) error {
+	var state driveState
+	if err := loadState(gs.config.StatePath, &state); err != nil {
+		return err
+	}
+	pageToken, err := gs.pageToken(ctx, state)
+	if err != nil {
+		return err
+	}
+	files, removed, next, err := gs.pending(ctx, pageToken)
+	if err != nil {
+		return err
+	}
+	versions := make(map[string]string, len(files))
+	for _, file := range files {
+		versions[file.ID] = file.Version
+	}
+	if err := saveState(gs.config.StatePath, driveState{PageToken: pageToken, NextPageToken: next, Files: versions}); err != nil {
+		return err
+	}
+
+	if err := gs.remove(ctx, removed); err != nil {
+		return err
+	}
+	var changed []gdrive.File
+	for _, file := range files {
+		unchanged, err := ingested(ctx, gs.ledger, googleDrivePathPrefix+file.ID, file.Version)
+		if err != nil {
+			return err
+		}
+		if !unchanged {
+			changed = append(changed, file)
+		}
+	}
+	extractAll(ctx, gs.config.Extract, changed, gs.extract, recordChan, errChan)
+	return nil
+}
+
+// pageToken returns the page token to list changes from: the next page
+// token once the records of every file listed last were ingested
+func (gs *GoogleDriveSource) pageToken(ctx context.Context, state driveState) (string, error) {
+	if state.NextPageToken == "" {
+		return state.PageToken, nil
+	}
+	for id, version := range state.Files {
+		ok, err := ingested(ctx, gs.ledger, googleDrivePathPrefix+id, version)
+		if err != nil {
+			return "", err
+		}
+		if !ok {
+			return state.PageToken, nil
+		}
+	}
+	return state.NextPageToken, nil
+}
+
+// pending returns the files to sync, the IDs of the files removed and the
+// page token listing later changes. Without a page token every file of the
+// folder is synced.
+func (gs *GoogleDriveSource) pending(ctx context.Context, pageToken string) ([]gdrive.File, []string, string, error) {
+	if pageToken == "" {
+		// Taken before listing, so files changed while listing are synced again
+		next, err := gs.client.StartPageToken(ctx)
+		if err != nil {
+			return nil, nil, "", fmt.Errorf("failed to get google drive page token: %w", err)
+		}
+		_, files, err := gs.walk(ctx, true)
+		return slices.DeleteFunc(files, func(file gdrive.File) bool { return !readable(file) }), nil, next, err
+	}
+
+	changes, next, err := gs.client.Changes(ctx, pageToken)
+	if err != nil {
+		return nil, nil, "", fmt.Errorf("failed to list google drive changes: %w", err)
+	}
+	folders, _, err := gs.walk(ctx, false)
+	if err != nil {
+		return nil, nil, "", err
+	}
+	var files []gdrive.File
+	var removed []string
+	for _, change := range changes {
+		switch {
+		case change.Removed || change.File == nil || !syncs(*change.File, folders):
+			removed = append(removed, change.FileID)
+		case readable(*change.File):
+			files = append(files, *change.File)
+		}
+	}
+	return files, removed, next, nil
+}
+
+// remove deletes the records of the files removed and their ledger entries.
+// Records already deleted are passed over.
+func (gs *GoogleDriveSource) remove(ctx context.Context, ids []string) error {
+	for _, id := range ids {
+		path := googleDrivePathPrefix + id
+		entry, ok, err := gs.ledger.Get(ctx, path)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			continue
+		}
+		if err := gs.deleter.Delete(ctx, entry.RecordID); err != nil && !errors.Is(err, storage.ErrNotFound) {
+			return fmt.Errorf("failed to delete record of removed google drive file %s: %w", id, err)
+		}
+		if err := gs.ledger.Delete(ctx, path); err != nil {
+			return err
+		}
+		slog.InfoContext(ctx, "Deleted record of removed file", "source", gs.Name(), "path", path, "record_id", entry.RecordID)
+	}
+	return nil
+}
+
+// walk returns the IDs of the synced folder and its subfolders, and their
+// files when withFiles is set. Without a folder it lists the whole drive.
+func (gs *GoogleDriveSource) walk(ctx context.Context, withFiles bool) (map[string]bool, []gdrive.File, error) {
+	if gs.config.FolderID == "" {
+		if !withFiles {
+			return nil, nil, nil
+		}
+		files, err := gs.client.ListFiles(ctx, fmt.Sprintf("trashed = false and mimeType != '%s'", gdrive.MimeTypeFolder))
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to list google drive files: %w", err)
+		}
+		return nil, files, nil
+	}
+
+	folders := map[string]bool{gs.config.FolderID: true}
+	queue := []string{gs.config.FolderID}
+	var files []gdrive.File
+	for len(queue) > 0 {
+		children, err := gs.client.ListFiles(ctx, childrenQuery(queue[0], withFiles))
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to list google drive folder %s: %w", queue[0], err)
+		}
+		queue = queue[1:]
+		for _, child := range children {
+			if child.MimeType != gdrive.MimeTypeFolder {
+				files = append(files, child)
+			} else if !folders[child.ID] {
+				folders[child.ID] = true
+				queue = append(queue, child.ID)
+			}
+		}
+	}
+	return folders, files, nil
+}
+
+// childrenQuery returns the Drive search query listing the children of a
+// folder, or only its subfolders unless withFiles is set
+func childrenQuery(folderID string, withFiles bool) string {
+	query := fmt.Sprintf("'%s' in parents and trashed = false", strings.ReplaceAll(folderID, "'", `\'`))
+	if !withFiles {
+		query += fmt.Sprintf(" and mimeType = '%s'", gdrive.MimeTypeFolder)
+	}
+	return query
+}
+
+// readable reports whether a file has content to extract. Google Docs editor
+// files without a text export, such as forms, have none.
+func readable(file gdrive.File) bool {
+	_, ok := exportTypes[file.MimeType]
+	return ok || !strings.HasPrefix(file.MimeType, googleAppsPrefix)
+}
+
+// syncs reports whether a changed file is a file in one of the synced
+// folders, or in the drive when folders is nil
+func syncs(file gdrive.File, folders map[string]bool) bool {
+	if file.Trashed || file.MimeType == gdrive.MimeTypeFolder {
+		return false
+	}
+	return folders == nil || slices.ContainsFunc(file.Parents, func(parent string) bool { return folders[parent] })
+}
+
+// extract reads a file and extracts its record, stamped with the file's version
+func (gs *GoogleDriveSource) extract(ctx context.Context, file gdrive.File) (records.Record, error) {
+	content, mediaType, err := gs.open(ctx, file)
+	if err != nil {
+		return records.Record{}, err
+	}
+	path := googleDrivePathPrefix + file.ID
+	input, scan, err := remoteInput(content, mediaType, path, file.Version)
+	_ = content.Close()
+	if err != nil {
+		return records.Record{}, fmt.Errorf("failed to read google drive file %s: %w", file.Name, err)
+	}
+
+	record, err := gs.extractor.Extract(ctx, input)
+	if err != nil {
+		return records.Record{}, fmt.Errorf("failed to extract record from google drive file %s: %w", file.Name, err)
+	}
+	return scan.Stamp(located(record, path, file.Name)), nil
+}
+
+// open returns the content of a file with its media type, exporting Google
+// Docs editor files as text
+func (gs *GoogleDriveSource) open(ctx context.Context, file gdrive.File) (io.ReadCloser, string, error) {
+	if exportType, ok := exportTypes[file.MimeType]; ok {
+		content, err := gs.client.Export(ctx, file.ID, exportType)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to export google drive file %s: %w", file.Name, err)
+		}
+		return content, exportType, nil
+	}
+
+	content, err := gs.client.Download(ctx, file.ID)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to download google drive file %s: %w", file.Name, err)
+	}
+	// Drive reports unknown files as octet streams, which are sniffed instead
+	mediaType := file.MimeType
+	if mediaType == "application/octet-stream" {
+		mediaType = ""
+	}
+	return content, mediaType, nil
+}
@@ -0,0 +1,173 @@
+package source_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kazemisoroush/assistant/pkg/gdrive"
+	gdrivemocks "github.com/kazemisoroush/assistant/pkg/gdrive/mocks"
+	"github.com/kazemisoroush/assistant/pkg/records"
+	"github.com/kazemisoroush/assistant/pkg/records/extractor"
+	"github.com/kazemisoroush/assistant/pkg/records/extractor/mocks"
+	ingestormocks "github.com/kazemisoroush/assistant/pkg/records/ingestor/mocks"
+	"github.com/kazemisoroush/assistant/pkg/records/ledger"
+	ledgermocks "github.com/kazemisoroush/assistant/pkg/records/ledger/mocks"
+	"github.com/kazemisoroush/assistant/pkg/records/source"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+// drain collects the records and errors of a scrape
+func drain(recordChan <-chan records.Record, errChan <-chan error) ([]records.Record, []error) {
+	var recs []records.Record
+	var errs []error
+	for recordChan != nil || errChan != nil {
+		select {
+		case rec, ok := <-recordChan:
+			if !ok {
+				recordChan = nil
+				continue
+			}
+			recs = append(recs, rec)
+		case err, ok := <-errChan:
+			if !ok {
+				errChan = nil
+				continue
+			}
+			errs = append(errs, err)
+		}
+	}
+	return recs, errs
+}
+
+// content returns a file's content as returned by the Drive API
+func content(text string) io.ReadCloser {
+	return io.NopCloser(strings.NewReader(text))
+}
+
+// extractMediaType extracts a record naming the media type of its input
+func extractMediaType(_ context.Context, input extractor.Input) (records.Record, error) {
+	return records.Record{ID: input.MediaType, Content: input.MediaType}, nil
+}
+
+func TestGoogleDriveSource_Scrape_Folder(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	client := gdrivemocks.NewMockClient(ctrl)
+	contentExtractor := mocks.NewMockContentExtractor(ctrl)
+	scans := ledgermocks.NewMockStore(ctrl)
+	statePath := filepath.Join(t.TempDir(), "state.json")
+	client.EXPECT().StartPageToken(gomock.Any()).Return("42", nil)
+	client.EXPECT().ListFiles(gomock.Any(), "'root-1' in parents and trashed = false").Return([]gdrive.File{
+		{ID: "doc", Name: "Lease", MimeType: gdrive.MimeTypeDocument, Version: "3"},
+		{ID: "sub", Name: "Scans", MimeType: gdrive.MimeTypeFolder},
+		{ID: "form", Name: "Survey", MimeType: "application/vnd.google-apps.form", Version: "1"},
+	}, nil)
+	client.EXPECT().ListFiles(gomock.Any(), "'sub' in parents and trashed = false").Return([]gdrive.File{
+		{ID: "img", Name: "receipt.png", MimeType: "image/png", Version: "7"},
+	}, nil)
+	scans.EXPECT().Get(gomock.Any(), gomock.Any()).Return(ledger.Entry{}, false, nil).Times(2)
+	client.EXPECT().Export(gomock.Any(), "doc", "text/plain").Return(content("Lease agreement"), nil)
+	client.EXPECT().Download(gomock.Any(), "img").Return(content("\x89PNG\r\n\x1a\n"), nil)
+	contentExtractor.EXPECT().Extract(gomock.Any(), gomock.Any()).DoAndReturn(extractMediaType).Times(2)
+	src := source.NewGoogleDriveSource(client, contentExtractor, scans, nil, source.GoogleDriveConfig{FolderID: "root-1", StatePath: statePath})
+
+	// Act
+	recs, errs := drain(src.Scrape(context.Background()))
+
+	// Assert
+	require.Empty(t, errs, "Scrape() should not report errors")
+	require.Len(t, recs, 2, "Scrape() should read the folder's files and skip files without a text export")
+	assert.Equal(t, "text/plain", recs[0].Content, "Scrape() should export documents as text")
+	assert.Equal(t, "Lease", recs[0].Title, "Scrape() should title records by file name")
+	assert.Equal(t, "gdrive://doc", recs[0].SourcePath(), "Scrape() should record the file the record came from")
+	stamped, ok := ledger.Stamped(recs[0])
+	require.True(t, ok, "Scrape() should stamp records with the version of their file")
+	assert.Equal(t, "3", stamped.Version, "Scrape() should stamp records with the version of their file")
+	assert.Equal(t, "image/png", recs[1].Content, "Scrape() should extract files by their media type")
+	assert.Equal(t, "receipt.png", recs[1].FileName, "Scrape() should name the file")
+	state, err := os.ReadFile(statePath)
+	require.NoError(t, err, "Scrape() should save the sync state")
+	assert.JSONEq(t, `{"page_token":"","next_page_token":"42","files":{"doc":"3","img":"7"}}`, string(state),
+		"Scrape() should save the page token taken before listing with the files to sync")
+}
+
+func TestGoogleDriveSource_Scrape_Changes(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	client := gdrivemocks.NewMockClient(ctrl)
+	contentExtractor := mocks.NewMockContentExtractor(ctrl)
+	scans := ledgermocks.NewMockStore(ctrl)
+	deleter := ingestormocks.NewMockService(ctrl)
+	statePath := filepath.Join(t.TempDir(), "state.json")
+	require.NoError(t, os.WriteFile(statePath, []byte(`{"page_token":"40","next_page_token":"42","files":{"done":"2"}}`), 0600))
+	scans.EXPECT().Get(gomock.Any(), "gdrive://done").Return(ledger.Entry{Version: "2", RecordID: "rec-done"}, true, nil)
+	client.EXPECT().Changes(gomock.Any(), "42").Return([]gdrive.Change{
+		{FileID: "gone", Removed: true},
+		{FileID: "new", File: &gdrive.File{ID: "new", Name: "budget", MimeType: gdrive.MimeTypeSpreadsheet, Parents: []string{"sub"}, Version: "1"}},
+		{FileID: "same", File: &gdrive.File{ID: "same", Name: "same.pdf", MimeType: "application/pdf", Parents: []string{"root-1"}, Version: "5"}},
+		{FileID: "other", File: &gdrive.File{ID: "other", Name: "other.pdf", MimeType: "application/pdf", Parents: []string{"elsewhere"}}},
+		{FileID: "bin", File: &gdrive.File{ID: "bin", Name: "old.pdf", MimeType: "application/pdf", Parents: []string{"root-1"}, Trashed: true}},
+	}, "50", nil)
+	client.EXPECT().ListFiles(gomock.Any(), "'root-1' in parents and trashed = false and mimeType = 'application/vnd.google-apps.folder'").
+		Return([]gdrive.File{{ID: "sub", MimeType: gdrive.MimeTypeFolder}}, nil)
+	client.EXPECT().ListFiles(gomock.Any(), "'sub' in parents and trashed = false and mimeType = 'application/vnd.google-apps.folder'").Return(nil, nil)
+	scans.EXPECT().Get(gomock.Any(), "gdrive://gone").Return(ledger.Entry{RecordID: "rec-gone"}, true, nil)
+	scans.EXPECT().Get(gomock.Any(), "gdrive://other").Return(ledger.Entry{}, false, nil)
+	scans.EXPECT().Get(gomock.Any(), "gdrive://bin").Return(ledger.Entry{RecordID: "rec-bin"}, true, nil)
+	deleter.EXPECT().Delete(gomock.Any(), "rec-gone").Return(nil)
+	deleter.EXPECT().Delete(gomock.Any(), "rec-bin").Return(nil)
+	scans.EXPECT().Delete(gomock.Any(), "gdrive://gone").Return(nil)
+	scans.EXPECT().Delete(gomock.Any(), "gdrive://bin").Return(nil)
+	scans.EXPECT().Get(gomock.Any(), "gdrive://new").Return(ledger.Entry{}, false, nil)
+	scans.EXPECT().Get(gomock.Any(), "gdrive://same").Return(ledger.Entry{Version: "5", RecordID: "rec-same"}, true, nil)
+	client.EXPECT().Export(gomock.Any(), "new", "text/csv").Return(content("month,amount"), nil)
+	contentExtractor.EXPECT().Extract(gomock.Any(), gomock.Any()).DoAndReturn(extractMediaType)
+	src := source.NewGoogleDriveSource(client, contentExtractor, scans, deleter, source.GoogleDriveConfig{FolderID: "root-1", StatePath: statePath})
+
+	// Act
+	recs, errs := drain(src.Scrape(context.Background()))
+
+	// Assert
+	require.Empty(t, errs, "Scrape() should not report errors")
+	require.Len(t, recs, 1, "Scrape() should read only the files changed in the folder and not yet ingested")
+	assert.Equal(t, "text/csv", recs[0].Content, "Scrape() should export spreadsheets as CSV")
+	state, err := os.ReadFile(statePath)
+	require.NoError(t, err, "ReadFile() error should be nil")
+	assert.JSONEq(t, `{"page_token":"42","next_page_token":"50","files":{"new":"1","same":"5"}}`, string(state),
+		"Scrape() should move on to the next page token once the files listed last were ingested")
+}
+
+func TestGoogleDriveSource_Scrape_NotIngested(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	client := gdrivemocks.NewMockClient(ctrl)
+	contentExtractor := mocks.NewMockContentExtractor(ctrl)
+	scans := ledgermocks.NewMockStore(ctrl)
+	statePath := filepath.Join(t.TempDir(), "state.json")
+	require.NoError(t, os.WriteFile(statePath, []byte(`{"page_token":"40","next_page_token":"42","files":{"a":"2"}}`), 0600))
+	scans.EXPECT().Get(gomock.Any(), "gdrive://a").Return(ledger.Entry{Version: "1", RecordID: "rec-a"}, true, nil).Times(2)
+	client.EXPECT().Changes(gomock.Any(), "40").Return([]gdrive.Change{
+		{FileID: "a", File: &gdrive.File{ID: "a", Name: "a.pdf", MimeType: "application/pdf", Version: "2"}},
+	}, "42", nil)
+	client.EXPECT().Download(gomock.Any(), "a").Return(nil, errors.New("rate limited"))
+	src := source.NewGoogleDriveSource(client, contentExtractor, scans, nil, source.GoogleDriveConfig{StatePath: statePath})
+
+	// Act
+	recs, errs := drain(src.Scrape(context.Background()))
+
+	// Assert
+	require.Len(t, errs, 1, "Scrape() should report the file failing to download")
+	assert.Contains(t, errs[0].Error(), "rate limited", "Scrape() should wrap the download error")
+	assert.Empty(t, recs, "Scrape() should send no records")
+	state, err := os.ReadFile(statePath)
+	require.NoError(t, err, "ReadFile() error should be nil")
+	assert.JSONEq(t, `{"page_token":"40","next_page_token":"42","files":{"a":"2"}}`, string(state),
+		"Scrape() should list the changes again until the files listed last were ingested")
+}
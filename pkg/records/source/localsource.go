@@ -3,26 +3,66 @@ package source
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io/fs"
-	"os"
+	"log/slog"
 	"path/filepath"
+	"slices"
+	"strings"
 
 	"github.com/kazemisoroush/assistant/pkg/records"
+	"github.com/kazemisoroush/assistant/pkg/records/checkpoint"
 	"github.com/kazemisoroush/assistant/pkg/records/extractor"
+	"github.com/kazemisoroush/assistant/pkg/records/ledger"
+	"github.com/kazemisoroush/assistant/pkg/records/pipeline"
+	"github.com/kazemisoroush/assistant/pkg/records/storage"
 )
 
-// LocalSource reads files from a local directory structure
+// LocalConfig represents where a local source reads files and how it processes them
+type LocalConfig struct {
+	BasePath string
+	Read     pipeline.StageConfig // Reads files and detects their media type
+	Extract  pipeline.StageConfig // Extracts records from the files read
+	Buffers  Buffers
+}
+
+// Deleter deletes records, such as those of files removed since they were scraped
+type Deleter interface {
+	Delete(ctx context.Context, id string) error
+}
+
+// scannedFile is a file read for extraction with the version of its content
+type scannedFile struct {
+	input extractor.Input
+	scan  ledger.Entry
+}
+
+// LocalSource reads files from a local directory structure. Files are read
+// and extracted in stages, each with its own workers, so OCR and model calls
+// for some files overlap with reading others. Every stage waits once its
+// queue is full, so a directory is never walked far ahead of extraction.
+//
+// Records are stamped with the version of their file. Files whose version is
+// in the ledger are skipped: those with an unchanged modification time
+// without being read, those touched but unchanged once hashed. A scrape
+// walking the whole base path deletes the records of files since removed.
 type LocalSource struct {
 	extractor extractor.ContentExtractor
-	basePath  string
+	ledger    ledger.Store // nil scrapes every file
+	deleter   Deleter
+	config    LocalConfig
 }
 
-// NewLocalSource creates a new local file source
-func NewLocalSource(extractor extractor.ContentExtractor, basePath string) Source {
+// NewLocalSource creates a new local file source. The ledger, which may be
+// nil, holds the files scraped before; the records of those removed since
+// are deleted with deleter.
+func NewLocalSource(extractor extractor.ContentExtractor, ledger ledger.Store, deleter Deleter, config LocalConfig) Source {
 	return &LocalSource{
 		extractor: extractor,
-		basePath:  basePath,
+		ledger:    ledger,
+		deleter:   deleter,
+		config:    config,
 	}
 }
 
@@ -31,53 +71,197 @@ func (ls *LocalSource) Name() string {
 	return "local"
 }
 
-// Scrape reads files from the local directory structure
+// Scrape reads files from the local directory structure. Once done, it logs
+// the stages that waited for a slower one.
 func (ls *LocalSource) Scrape(ctx context.Context) (<-chan records.Record, <-chan error) {
-	recordChan := make(chan records.Record)
-	errChan := make(chan error, 1)
+	return ls.Resume(ctx, nil)
+}
+
+// Resume implements Resumable. Files and directories walked before the
+// cursor are skipped.
+func (ls *LocalSource) Resume(ctx context.Context, tracker *checkpoint.Tracker) (<-chan records.Record, <-chan error) {
+	recordChan := make(chan records.Record, max(ls.config.Buffers.Records, 0))
+	errChan := make(chan error, max(ls.config.Buffers.Errors, 1))
 
 	go func() {
 		defer close(recordChan)
 		defer close(errChan)
+		ls.run(ctx, tracker, recordChan, errChan)
+	}()
 
-		err := filepath.WalkDir(ls.basePath, func(path string, d fs.DirEntry, err error) error {
-			// Check for context cancellation
-			select {
-			case <-ctx.Done():
-				return ctx.Err()
-			default:
-			}
-
-			if err != nil {
-				return err
-			}
-
-			// Skip directories
-			if d.IsDir() {
-				return nil
-			}
-
-			// Read file content
-			content, err := os.ReadFile(path)
-			if err != nil {
-				errChan <- fmt.Errorf("failed to read file %s: %w", path, err)
-				return nil // Continue processing other files
-			}
-
-			record, err := ls.extractor.Extract(ctx, string(content))
-			if err != nil {
-				errChan <- fmt.Errorf("failed to extract record from file %s: %w", path, err)
-				return nil // Continue processing other files
-			}
-
-			recordChan <- record
-			return nil
-		})
+	return recordChan, errChan
+}
+
+// run walks the base path and sends the records extracted from its files
+func (ls *LocalSource) run(ctx context.Context, tracker *checkpoint.Tracker, recordChan chan<- records.Record, errChan chan<- error) {
+	pressures := map[string]*pipeline.Pressure{"walk": {}, "read": {}, "extract": {}}
+	defer logPressure(ctx, ls.Name(), pressures)
+	read, extract := ls.config.Read, ls.config.Extract
+	read.Pressure, extract.Pressure = pressures["read"], pressures["extract"]
 
+	scanned, err := ls.scanned(ctx)
+	if err != nil {
+		pipeline.Send(ctx, errChan, err, nil)
+		return
+	}
+	// Only a walk of the whole base path tells which files were removed
+	whole := tracker.Cursor() == ""
+
+	paths := make(chan string, max(read.Queue, 0))
+	walked := map[string]bool{}
+	walkErr := make(chan error, 1)
+	go func() {
+		defer close(paths)
+		walkErr <- ls.walk(ctx, paths, pressures["walk"], tracker, scanned, walked)
+	}()
+
+	// Failed files are reported and the remaining files still processed
+	stageErrs := make(chan error)
+	files := pipeline.Run(ctx, read, paths, func(_ context.Context, path string) (scannedFile, error) {
+		file, err := readFile(path)
 		if err != nil {
-			errChan <- fmt.Errorf("failed to walk directory: %w", err)
+			tracker.Finished(path, true)
 		}
-	}()
+		return file, err
+	}, stageErrs)
+	extracted := pipeline.Run(ctx, extract, files, func(ctx context.Context, file scannedFile) (records.Record, error) {
+		ctx, timings := pipeline.WithTimings(ctx)
+		record, err := ls.extractor.Extract(ctx, file.input)
+		extract.Metrics.Record(timings, string(record.Type))
+		if err != nil {
+			tracker.Finished(file.input.Path, true)
+			return records.Record{}, fmt.Errorf("failed to extract record from file %s: %w", file.input.Path, err)
+		}
+		return file.scan.Stamp(located(record, file.input.Path, filepath.Base(file.input.Path))), nil
+	}, stageErrs)
 
-	return recordChan, errChan
+	forward(ctx, extracted, stageErrs, recordChan, errChan)
+	if err := <-walkErr; err != nil {
+		pipeline.Send(ctx, errChan, fmt.Errorf("failed to walk directory: %w", err), nil)
+		return
+	}
+	if whole && ctx.Err() == nil {
+		if err := ls.prune(ctx, scanned, walked); err != nil {
+			pipeline.Send(ctx, errChan, err, nil)
+		}
+	}
+}
+
+// readFile reads a file for extraction and scans its version
+func readFile(path string) (scannedFile, error) {
+	input, err := extractor.FileInput(path)
+	if err != nil {
+		return scannedFile{}, err
+	}
+	scan, err := ledger.Scan(path)
+	if err != nil {
+		return scannedFile{}, err
+	}
+	return scannedFile{input: input, scan: scan}, nil
+}
+
+// walk sends the path of every file under the base path after the tracker's
+// cursor, except the files unchanged since they were scanned. Every file
+// walked is noted in walked.
+func (ls *LocalSource) walk(ctx context.Context, paths chan<- string, pressure *pipeline.Pressure, tracker *checkpoint.Tracker, scanned map[string]ledger.Entry, walked map[string]bool) error {
+	cursor := tracker.Cursor()
+	return filepath.WalkDir(filepath.Clean(ls.config.BasePath), func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if cursor != "" && !walksAfter(path, cursor) {
+			return skip(d, path, cursor)
+		}
+
+		// Skip directories
+		if d.IsDir() {
+			return nil
+		}
+
+		walked[path] = true
+		if ls.unchanged(ctx, path, d, scanned) {
+			tracker.Skipped()
+			return nil
+		}
+		tracker.Walked(path)
+		if !pipeline.Send(ctx, paths, path, pressure) {
+			return ctx.Err()
+		}
+		return nil
+	})
+}
+
+// scanned returns the ledger entries of the files under the base path by path
+func (ls *LocalSource) scanned(ctx context.Context) (map[string]ledger.Entry, error) {
+	if ls.ledger == nil {
+		return nil, nil
+	}
+	entries, err := ls.ledger.List(ctx, ls.config.BasePath)
+	if err != nil {
+		return nil, err
+	}
+	scanned := make(map[string]ledger.Entry, len(entries))
+	for _, entry := range entries {
+		scanned[entry.Path] = entry
+	}
+	return scanned, nil
+}
+
+// unchanged reports whether a file is the version in the ledger
+func (ls *LocalSource) unchanged(ctx context.Context, path string, d fs.DirEntry, scanned map[string]ledger.Entry) bool {
+	entry, ok := scanned[path]
+	if !ok {
+		return false
+	}
+	info, err := d.Info()
+	if err != nil {
+		return false
+	}
+	return ledger.Unchanged(ctx, ls.ledger, entry, info.ModTime())
+}
+
+// prune deletes the records of the files in the ledger that were not
+// walked, as they were removed. Records already deleted are passed over.
+func (ls *LocalSource) prune(ctx context.Context, scanned map[string]ledger.Entry, walked map[string]bool) error {
+	for path, entry := range scanned {
+		if walked[path] {
+			continue
+		}
+		if err := ls.deleter.Delete(ctx, entry.RecordID); err != nil && !errors.Is(err, storage.ErrNotFound) {
+			return fmt.Errorf("failed to delete record of removed file %s: %w", path, err)
+		}
+		if err := ls.ledger.Delete(ctx, path); err != nil {
+			return err
+		}
+		slog.InfoContext(ctx, "Deleted record of removed file", "source", ls.Name(), "path", path, "record_id", entry.RecordID)
+	}
+	return nil
+}
+
+// walksAfter reports whether WalkDir visits path after cursor. WalkDir visits
+// the entries of every directory in lexical order, so paths compare by their elements.
+func walksAfter(path, cursor string) bool {
+	return slices.Compare(strings.Split(filepath.ToSlash(path), "/"), strings.Split(filepath.ToSlash(cursor), "/")) > 0
+}
+
+// skip passes over an entry walked before the cursor. Directories are
+// skipped as a whole unless they contain the cursor.
+func skip(d fs.DirEntry, path, cursor string) error {
+	if d.IsDir() && !strings.HasPrefix(cursor, path+string(filepath.Separator)) {
+		return filepath.SkipDir
+	}
+	return nil
+}
+
+// logPressure logs the stages of a scrape that waited for a slower stage. The
+// extract stage waits for the consumer once its queue is full.
+func logPressure(ctx context.Context, source string, pressures map[string]*pipeline.Pressure) {
+	for _, stage := range []string{"walk", "read", "extract"} {
+		pressure := pressures[stage]
+		if pressure.Waits() == 0 {
+			continue
+		}
+		slog.InfoContext(ctx, "Scrape stage waited for a slower stage", "source", source, "stage", stage,
+			"waits", pressure.Waits(), "waited", pressure.Waited())
+	}
 }
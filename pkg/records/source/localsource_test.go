@@ -0,0 +1,191 @@
+package source_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/kazemisoroush/assistant/pkg/records"
+	"github.com/kazemisoroush/assistant/pkg/records/checkpoint"
+	"github.com/kazemisoroush/assistant/pkg/records/extractor"
+	"github.com/kazemisoroush/assistant/pkg/records/extractor/mocks"
+	ingestormocks "github.com/kazemisoroush/assistant/pkg/records/ingestor/mocks"
+	"github.com/kazemisoroush/assistant/pkg/records/ledger"
+	ledgermocks "github.com/kazemisoroush/assistant/pkg/records/ledger/mocks"
+	"github.com/kazemisoroush/assistant/pkg/records/pipeline"
+	"github.com/kazemisoroush/assistant/pkg/records/source"
+	"github.com/kazemisoroush/assistant/pkg/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+// openDB opens the database at path, closed once the test is done
+func openDB(t testing.TB, path string) *sqlite.DB {
+	t.Helper()
+	db, err := sqlite.Open(path)
+	require.NoError(t, err, "Open() error should be nil")
+	t.Cleanup(func() { _ = db.Close() })
+	return db
+}
+
+func TestLocalSource_Scrape(t *testing.T) {
+	// Arrange
+	dir := t.TempDir()
+	for _, name := range []string{"a.txt", "b.txt", "broken.txt"} {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte("content of "+name), 0600))
+	}
+	ctrl := gomock.NewController(t)
+	contentExtractor := mocks.NewMockContentExtractor(ctrl)
+	contentExtractor.EXPECT().Extract(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, input extractor.Input) (records.Record, error) {
+		if filepath.Base(input.Path) == "broken.txt" {
+			return records.Record{}, errors.New("model unavailable")
+		}
+		assert.Equal(t, "text/plain", input.MediaType, "Scrape() should detect the media type")
+		return records.Record{ID: filepath.Base(input.Path)}, nil
+	}).Times(3)
+	src := source.NewLocalSource(contentExtractor, nil, nil, source.LocalConfig{
+		BasePath: dir,
+		Read:     pipeline.StageConfig{Workers: 2, Queue: 1},
+		Extract:  pipeline.StageConfig{Workers: 2},
+		Buffers:  source.Buffers{Records: 1, Errors: 1},
+	})
+
+	// Act
+	recordChan, errChan := src.Scrape(context.Background())
+	var ids []string
+	var errs []error
+	for recordChan != nil || errChan != nil {
+		select {
+		case rec, ok := <-recordChan:
+			if !ok {
+				recordChan = nil
+				continue
+			}
+			ids = append(ids, rec.ID)
+		case err, ok := <-errChan:
+			if !ok {
+				errChan = nil
+				continue
+			}
+			errs = append(errs, err)
+		}
+	}
+
+	// Assert
+	sort.Strings(ids)
+	assert.Equal(t, []string{"a.txt", "b.txt"}, ids, "Scrape() should extract every readable file")
+	require.Len(t, errs, 1, "Scrape() should report the failed file")
+	assert.Contains(t, errs[0].Error(), "broken.txt", "Scrape() should name the failed file")
+}
+
+func TestLocalSource_Resume(t *testing.T) {
+	// Arrange
+	dir := t.TempDir()
+	for _, name := range []string{"a/x.txt", "b/c.txt", "b/d.txt", "e.txt"} {
+		require.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(dir, name)), 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte("content of "+name), 0600))
+	}
+	ctrl := gomock.NewController(t)
+	contentExtractor := mocks.NewMockContentExtractor(ctrl)
+	contentExtractor.EXPECT().Extract(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, input extractor.Input) (records.Record, error) {
+		return records.Record{ID: filepath.Base(input.Path)}, nil
+	}).Times(2)
+	src := source.NewLocalSource(contentExtractor, nil, nil, source.LocalConfig{BasePath: dir})
+	tracker := checkpoint.NewTracker(checkpoint.Checkpoint{Source: "local", Cursor: filepath.Join(dir, "b", "c.txt")}, nil)
+
+	// Act
+	recordChan, errChan := src.(source.Resumable).Resume(context.Background(), tracker)
+	var recs []records.Record
+	for rec := range recordChan {
+		recs = append(recs, rec)
+		tracker.Finished(rec.SourcePath(), false)
+	}
+
+	// Assert
+	assert.Empty(t, errChan, "Resume() should not report errors")
+	require.Len(t, recs, 2, "Resume() should only extract the files after the cursor")
+	assert.Equal(t, filepath.Join(dir, "b", "d.txt"), recs[0].SourcePath(), "Resume() should name the file of a record")
+	assert.Equal(t, "d.txt", recs[0].FileName, "Resume() should set the file name of a record")
+	assert.Equal(t, "d", recs[0].Title, "Resume() should title a record after its file")
+	assert.Equal(t, filepath.Join(dir, "e.txt"), tracker.Cursor(), "Resume() should walk the files it scrapes into the tracker")
+}
+
+func TestLocalSource_Scrape_SkipsScannedFiles(t *testing.T) {
+	// Arrange
+	dir := t.TempDir()
+	for _, name := range []string{"same.txt", "touched.txt", "edited.txt", "new.txt"} {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte("content of "+name), 0600))
+	}
+	scan := func(name string) ledger.Entry {
+		entry, err := ledger.Scan(filepath.Join(dir, name))
+		require.NoError(t, err, "Scan() error should be nil")
+		entry.RecordID = "rec-" + name
+		return entry
+	}
+	same, touched, edited := scan("same.txt"), scan("touched.txt"), scan("edited.txt")
+	touched.ModTime = touched.ModTime.Add(-time.Hour)
+	edited.SHA256, edited.ModTime = "previous", edited.ModTime.Add(-time.Hour)
+	gone := ledger.Entry{Path: filepath.Join(dir, "gone.txt"), SHA256: "gone", RecordID: "rec-gone.txt"}
+	ctrl := gomock.NewController(t)
+	contentExtractor := mocks.NewMockContentExtractor(ctrl)
+	scans := ledgermocks.NewMockStore(ctrl)
+	deleter := ingestormocks.NewMockService(ctrl)
+	scans.EXPECT().List(gomock.Any(), dir).Return([]ledger.Entry{edited, gone, same, touched}, nil)
+	scans.EXPECT().Put(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, entry ledger.Entry) error {
+		assert.Equal(t, touched.Path, entry.Path, "Scrape() should only update the ledger entry of touched files")
+		assert.Equal(t, "rec-touched.txt", entry.RecordID, "Scrape() should keep the record of touched files")
+		return nil
+	})
+	deleter.EXPECT().Delete(gomock.Any(), "rec-gone.txt").Return(nil)
+	scans.EXPECT().Delete(gomock.Any(), gone.Path).Return(nil)
+	contentExtractor.EXPECT().Extract(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, input extractor.Input) (records.Record, error) {
+		return records.Record{ID: filepath.Base(input.Path)}, nil
+	}).Times(2)
+	src := source.NewLocalSource(contentExtractor, scans, deleter, source.LocalConfig{BasePath: dir})
+
+	// Act
+	recs, errs := drain(src.Scrape(context.Background()))
+
+	// Assert
+	assert.Empty(t, errs, "Scrape() should not report errors")
+	require.Len(t, recs, 2, "Scrape() should only extract new and edited files")
+	sort.Slice(recs, func(i, j int) bool { return recs[i].ID < recs[j].ID })
+	assert.Equal(t, "edited.txt", recs[0].ID, "Scrape() should extract edited files")
+	stamped, ok := ledger.Stamped(recs[1])
+	require.True(t, ok, "Scrape() should stamp records with the version of their file")
+	assert.Equal(t, scan("new.txt").SHA256, stamped.SHA256, "Scrape() should stamp records with the digest of their file")
+}
+
+func TestLocalSource_Scrape_WorkingDirectory(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	scans, err := ledger.NewSQLiteStore(openDB(t, filepath.Join(t.TempDir(), "assistant.db")))
+	require.NoError(t, err, "NewSQLiteStore() error should be nil")
+	t.Chdir(t.TempDir())
+	require.NoError(t, os.WriteFile("kept.txt", []byte("content of kept.txt"), 0600))
+	kept, err := ledger.Scan("kept.txt")
+	require.NoError(t, err, "Scan() error should be nil")
+	kept.RecordID = "rec-kept"
+	for _, entry := range []ledger.Entry{kept, {Path: "gone.txt", SHA256: "gone", RecordID: "rec-gone"}, {Path: "webdav:/Scans/a.pdf", SHA256: "remote", RecordID: "rec-remote"}} {
+		require.NoError(t, scans.Put(ctx, entry), "Put() error should be nil")
+	}
+	ctrl := gomock.NewController(t)
+	deleter := ingestormocks.NewMockService(ctrl)
+	deleter.EXPECT().Delete(gomock.Any(), "rec-gone").Return(nil)
+	src := source.NewLocalSource(mocks.NewMockContentExtractor(ctrl), scans, deleter, source.LocalConfig{BasePath: "."})
+
+	// Act
+	recs, errs := drain(src.Scrape(ctx))
+
+	// Assert
+	assert.Empty(t, errs, "Scrape() should not report errors")
+	assert.Empty(t, recs, "Scrape() should skip the files scanned under the working directory")
+	_, ok, err := scans.Get(ctx, "webdav:/Scans/a.pdf")
+	require.NoError(t, err, "Get() error should be nil")
+	assert.True(t, ok, "Scrape() should keep the ledger entries of remote files")
+}
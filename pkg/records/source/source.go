@@ -3,10 +3,27 @@ package source
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"path/filepath"
+	"strings"
 
 	"github.com/kazemisoroush/assistant/pkg/records"
+	"github.com/kazemisoroush/assistant/pkg/records/checkpoint"
+	"github.com/kazemisoroush/assistant/pkg/records/extractor"
+	"github.com/kazemisoroush/assistant/pkg/records/ledger"
+	"github.com/kazemisoroush/assistant/pkg/records/pipeline"
 )
 
+// Buffers represents how many records and errors a source buffers for its
+// consumer. A source whose buffers are full waits for the consumer instead of
+// scraping ahead of it.
+type Buffers struct {
+	Records int
+	Errors  int
+}
+
 // Source represents a source of records that can be scraped/ingested
 //
 //go:generate mockgen -destination=./mocks/mock_source.go -mock_names=Source=MockSource -package=mocks . Source
@@ -18,3 +35,86 @@ type Source interface {
 	// Returns a channel of records and an error channel
 	Scrape(ctx context.Context) (<-chan records.Record, <-chan error)
 }
+
+// Resumable is a Source that can resume a scrape from a checkpoint
+type Resumable interface {
+	Source
+
+	// Resume scrapes the files after the tracker's cursor, reporting every
+	// file it walks, skips or fails to read or extract to the tracker.
+	// Records name their file in records.MetaSourcePath, so the consumer
+	// reports them finished once ingested.
+	Resume(ctx context.Context, tracker *checkpoint.Tracker) (<-chan records.Record, <-chan error)
+}
+
+// located names the file a record was extracted from, by its source path and
+// file name. Records the extractor did not title are titled by the file name.
+func located(record records.Record, path, name string) records.Record {
+	if record.Metadata == nil {
+		record.Metadata = map[string]interface{}{}
+	}
+	record.Metadata[records.MetaSourcePath] = path
+	record.FilePath = path
+	record.FileName = name
+	if record.Title == "" {
+		record.Title = strings.TrimSuffix(name, filepath.Ext(name))
+	}
+	return record
+}
+
+// ingested reports whether the ledger holds a version of a remote file, so
+// its record was ingested. Files without a version are always read.
+func ingested(ctx context.Context, scans ledger.Store, path, version string) (bool, error) {
+	if version == "" {
+		return false, nil
+	}
+	entry, ok, err := scans.Get(ctx, path)
+	if err != nil {
+		return false, err
+	}
+	return ok && entry.Version == version, nil
+}
+
+// remoteInput reads the content of a remote file for extraction, with the
+// version of the file to stamp its record with
+func remoteInput(content io.Reader, mediaType, path, version string) (extractor.Input, ledger.Entry, error) {
+	hash := sha256.New()
+	input, err := extractor.ReaderInput(io.TeeReader(content, hash), mediaType)
+	if err != nil {
+		return extractor.Input{}, ledger.Entry{}, err
+	}
+	return input, ledger.Entry{Path: path, SHA256: hex.EncodeToString(hash.Sum(nil)), Version: version}, nil
+}
+
+// extractAll extracts the records of files with the workers of the stage and
+// sends them as they finish. Files failing to be extracted are reported
+// without stopping the others.
+func extractAll[F any](ctx context.Context, stage pipeline.StageConfig, files []F, extract func(context.Context, F) (records.Record, error), recordChan chan<- records.Record, errChan chan<- error) {
+	queue := make(chan F)
+	go func() {
+		defer close(queue)
+		for _, file := range files {
+			if !pipeline.Send(ctx, queue, file, nil) {
+				return
+			}
+		}
+	}()
+
+	stageErrs := make(chan error)
+	forward(ctx, pipeline.Run(ctx, stage, queue, extract, stageErrs), stageErrs, recordChan, errChan)
+}
+
+// forward passes extracted records and stage errors on until the last stage is drained
+func forward(ctx context.Context, extracted <-chan records.Record, stageErrs <-chan error, recordChan chan<- records.Record, errChan chan<- error) {
+	for {
+		select {
+		case record, ok := <-extracted:
+			if !ok {
+				return
+			}
+			pipeline.Send(ctx, recordChan, record, nil)
+		case err := <-stageErrs:
+			pipeline.Send(ctx, errChan, err, nil)
+		}
+	}
+}
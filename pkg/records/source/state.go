@@ -0,0 +1,44 @@
+package source
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// loadState decodes the sync state a source saved at path into state,
+// leaving state as it is before the source saved any
+func loadState(path string, state any) error {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read source state: %w", err)
+	}
+	if err := json.Unmarshal(data, state); err != nil {
+		return fmt.Errorf("failed to decode source state %s: %w", path, err)
+	}
+	return nil
+}
+
+// saveState replaces the sync state a source saved at path atomically
+func saveState(path string, state any) error {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("failed to encode source state: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("failed to create source state directory: %w", err)
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return fmt.Errorf("failed to write source state: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to save source state: %w", err)
+	}
+	return nil
+}
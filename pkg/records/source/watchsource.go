@@ -0,0 +1,162 @@
+package source
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/kazemisoroush/assistant/pkg/records"
+	"github.com/kazemisoroush/assistant/pkg/records/extractor"
+	"github.com/kazemisoroush/assistant/pkg/records/ledger"
+	"github.com/kazemisoroush/assistant/pkg/records/pipeline"
+	"github.com/kazemisoroush/assistant/pkg/records/watcher"
+)
+
+// WatchConfig represents where a watch source moves the files it fails to extract
+type WatchConfig struct {
+	QuarantineDir string
+	Buffers       Buffers
+}
+
+// WatchSource extracts records from files as a watcher reports them, so its
+// scrape runs until cancelled. Files failing to be read or extracted are
+// moved to the quarantine directory rather than stopping the scrape, so they
+// can be inspected and are not extracted again while they stay unchanged.
+//
+// Like the local source, records are stamped with the version of their file
+// and files whose version is in the ledger are skipped, so a file modified
+// has its record replaced and a scrape does not extract it again.
+type WatchSource struct {
+	watcher   watcher.Watcher
+	extractor extractor.ContentExtractor
+	ledger    ledger.Store // nil extracts every file reported
+	config    WatchConfig
+}
+
+// NewWatchSource creates a new watch source. The ledger, which may be nil,
+// holds the files scraped or watched before.
+func NewWatchSource(watcher watcher.Watcher, extractor extractor.ContentExtractor, ledger ledger.Store, config WatchConfig) Source {
+	return &WatchSource{
+		watcher:   watcher,
+		extractor: extractor,
+		ledger:    ledger,
+		config:    config,
+	}
+}
+
+// Name returns the source name
+func (ws *WatchSource) Name() string {
+	return "watch"
+}
+
+// Scrape extracts records from the files the watcher reports until ctx is
+// done. Only a failing watcher is reported as an error.
+func (ws *WatchSource) Scrape(ctx context.Context) (<-chan records.Record, <-chan error) {
+	recordChan := make(chan records.Record, max(ws.config.Buffers.Records, 0))
+	errChan := make(chan error, max(ws.config.Buffers.Errors, 1))
+
+	go func() {
+		defer close(recordChan)
+		defer close(errChan)
+		if err := ws.run(ctx, recordChan); err != nil {
+			pipeline.Send(ctx, errChan, fmt.Errorf("failed to watch files: %w", err), nil)
+		}
+	}()
+
+	return recordChan, errChan
+}
+
+// run sends the records of the files reported until the watcher stops
+func (ws *WatchSource) run(ctx context.Context, recordChan chan<- records.Record) error {
+	paths, watchErrs := ws.watcher.Watch(ctx)
+	for paths != nil {
+		select {
+		case path, ok := <-paths:
+			if !ok {
+				paths = nil
+				continue
+			}
+			if !ws.send(ctx, path, recordChan) {
+				return nil
+			}
+		case err, ok := <-watchErrs:
+			if ok {
+				return err
+			}
+			watchErrs = nil
+		}
+	}
+	if watchErrs == nil {
+		return nil
+	}
+	return <-watchErrs
+}
+
+// send extracts the record of a file and sends it, quarantining the file
+// when it fails to be extracted. It returns false once ctx is done; files
+// whose extraction was interrupted are left where they are.
+func (ws *WatchSource) send(ctx context.Context, path string, recordChan chan<- records.Record) bool {
+	if ws.unchanged(ctx, path) {
+		return true
+	}
+	record, err := ws.extract(ctx, path)
+	if ctx.Err() != nil {
+		return false
+	}
+	if err != nil {
+		ws.quarantine(ctx, path, err)
+		return true
+	}
+	return pipeline.Send(ctx, recordChan, record, nil)
+}
+
+// unchanged reports whether a file reported is the version in the ledger
+func (ws *WatchSource) unchanged(ctx context.Context, path string) bool {
+	if ws.ledger == nil {
+		return false
+	}
+	entry, ok, err := ws.ledger.Get(ctx, path)
+	if err != nil || !ok {
+		return false
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return false
+	}
+	return ledger.Unchanged(ctx, ws.ledger, entry, info.ModTime())
+}
+
+// extract reads a file and extracts its record, stamped with the file's version
+func (ws *WatchSource) extract(ctx context.Context, path string) (records.Record, error) {
+	file, err := readFile(path)
+	if err != nil {
+		return records.Record{}, err
+	}
+	record, err := ws.extractor.Extract(ctx, file.input)
+	if err != nil {
+		return records.Record{}, fmt.Errorf("failed to extract record from file %s: %w", path, err)
+	}
+	return file.scan.Stamp(located(record, path, filepath.Base(path))), nil
+}
+
+// quarantine moves a file failing to be extracted to the quarantine
+// directory, keeping its name unless a quarantined file already has it
+func (ws *WatchSource) quarantine(ctx context.Context, path string, extractErr error) {
+	if err := os.MkdirAll(ws.config.QuarantineDir, 0700); err != nil {
+		slog.WarnContext(ctx, "Failed to create quarantine directory", "path", path, "error", err, "cause", extractErr)
+		return
+	}
+	target := filepath.Join(ws.config.QuarantineDir, filepath.Base(path))
+	if _, err := os.Lstat(target); !errors.Is(err, os.ErrNotExist) {
+		target = filepath.Join(ws.config.QuarantineDir, fmt.Sprintf("%d-%s", time.Now().UnixNano(), filepath.Base(path)))
+	}
+	if err := os.Rename(path, target); err != nil {
+		slog.WarnContext(ctx, "Failed to quarantine file", "path", path, "error", err, "cause", extractErr)
+		return
+	}
+	slog.WarnContext(ctx, "Quarantined file failing to be extracted", "path", path, "quarantine", target, "error", extractErr)
+}
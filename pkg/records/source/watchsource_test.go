@@ -0,0 +1,112 @@
+package source_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/kazemisoroush/assistant/pkg/records"
+	"github.com/kazemisoroush/assistant/pkg/records/extractor"
+	"github.com/kazemisoroush/assistant/pkg/records/extractor/mocks"
+	"github.com/kazemisoroush/assistant/pkg/records/ledger"
+	ledgermocks "github.com/kazemisoroush/assistant/pkg/records/ledger/mocks"
+	"github.com/kazemisoroush/assistant/pkg/records/source"
+	watchermocks "github.com/kazemisoroush/assistant/pkg/records/watcher/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+// reported returns channels reporting the paths, as a watcher does once stopped
+func reported(paths ...string) (<-chan string, <-chan error) {
+	pathChan := make(chan string, len(paths))
+	errChan := make(chan error)
+	for _, path := range paths {
+		pathChan <- path
+	}
+	close(pathChan)
+	close(errChan)
+	return pathChan, errChan
+}
+
+func TestWatchSource_Scrape(t *testing.T) {
+	// Arrange
+	dir := t.TempDir()
+	quarantine := filepath.Join(dir, "quarantine")
+	good, broken := filepath.Join(dir, "receipt.txt"), filepath.Join(dir, "broken.txt")
+	require.NoError(t, os.WriteFile(good, []byte("Groceries 42 EUR"), 0600))
+	require.NoError(t, os.WriteFile(broken, []byte("???"), 0600))
+	ctrl := gomock.NewController(t)
+	fileWatcher := watchermocks.NewMockWatcher(ctrl)
+	contentExtractor := mocks.NewMockContentExtractor(ctrl)
+	fileWatcher.EXPECT().Watch(gomock.Any()).Return(reported(broken, good))
+	contentExtractor.EXPECT().Extract(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, input extractor.Input) (records.Record, error) {
+		if input.Path == broken {
+			return records.Record{}, errors.New("unreadable scan")
+		}
+		return records.Record{ID: "rec-1"}, nil
+	}).Times(2)
+	src := source.NewWatchSource(fileWatcher, contentExtractor, nil, source.WatchConfig{QuarantineDir: quarantine})
+
+	// Act
+	recs, errs := drain(src.Scrape(context.Background()))
+
+	// Assert
+	assert.Empty(t, errs, "Scrape() should not stop for files failing to be extracted")
+	require.Len(t, recs, 1, "Scrape() should send the records of the files extracted")
+	assert.Equal(t, good, recs[0].SourcePath(), "Scrape() should record the file the record came from")
+	assert.Equal(t, "receipt", recs[0].Title, "Scrape() should title records by file name")
+	assert.NoFileExists(t, broken, "Scrape() should move files failing to be extracted")
+	assert.FileExists(t, filepath.Join(quarantine, "broken.txt"), "Scrape() should quarantine files failing to be extracted")
+}
+
+func TestWatchSource_Scrape_Ledger(t *testing.T) {
+	// Arrange
+	dir := t.TempDir()
+	scanned, modified := filepath.Join(dir, "scanned.txt"), filepath.Join(dir, "modified.txt")
+	require.NoError(t, os.WriteFile(scanned, []byte("Groceries 42 EUR"), 0600))
+	require.NoError(t, os.WriteFile(modified, []byte("Fuel 60 EUR"), 0600))
+	scan, err := ledger.Scan(scanned)
+	require.NoError(t, err, "Scan() error should be nil")
+	ctrl := gomock.NewController(t)
+	fileWatcher := watchermocks.NewMockWatcher(ctrl)
+	contentExtractor := mocks.NewMockContentExtractor(ctrl)
+	scans := ledgermocks.NewMockStore(ctrl)
+	fileWatcher.EXPECT().Watch(gomock.Any()).Return(reported(scanned, modified))
+	scans.EXPECT().Get(gomock.Any(), scanned).Return(scan, true, nil)
+	scans.EXPECT().Get(gomock.Any(), modified).Return(ledger.Entry{Path: modified, SHA256: "previous", RecordID: "rec-1"}, true, nil)
+	contentExtractor.EXPECT().Extract(gomock.Any(), gomock.Any()).Return(records.Record{ID: "rec-2"}, nil)
+	src := source.NewWatchSource(fileWatcher, contentExtractor, scans, source.WatchConfig{QuarantineDir: filepath.Join(dir, "quarantine")})
+
+	// Act
+	recs, errs := drain(src.Scrape(context.Background()))
+
+	// Assert
+	assert.Empty(t, errs, "Scrape() should report no errors")
+	require.Len(t, recs, 1, "Scrape() should skip files whose version is in the ledger")
+	stamped, ok := ledger.Stamped(recs[0])
+	require.True(t, ok, "Scrape() should stamp records with the version of their file")
+	assert.Equal(t, modified, stamped.Path, "Scrape() should stamp the record of the modified file")
+}
+
+func TestWatchSource_Scrape_WatcherFails(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	fileWatcher := watchermocks.NewMockWatcher(ctrl)
+	pathChan, errChan := make(chan string), make(chan error, 1)
+	errChan <- errors.New("directory unmounted")
+	close(errChan)
+	close(pathChan)
+	fileWatcher.EXPECT().Watch(gomock.Any()).Return((<-chan string)(pathChan), (<-chan error)(errChan))
+	src := source.NewWatchSource(fileWatcher, mocks.NewMockContentExtractor(ctrl), nil, source.WatchConfig{QuarantineDir: t.TempDir()})
+
+	// Act
+	recs, errs := drain(src.Scrape(context.Background()))
+
+	// Assert
+	assert.Empty(t, recs, "Scrape() should send no records")
+	require.Len(t, errs, 1, "Scrape() should report the failing watcher")
+	assert.Contains(t, errs[0].Error(), "directory unmounted", "Scrape() should wrap the watcher error")
+}
@@ -0,0 +1,134 @@
+package source
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kazemisoroush/assistant/pkg/records"
+	"github.com/kazemisoroush/assistant/pkg/records/extractor"
+	"github.com/kazemisoroush/assistant/pkg/records/ledger"
+	"github.com/kazemisoroush/assistant/pkg/records/pipeline"
+	"github.com/kazemisoroush/assistant/pkg/webdav"
+)
+
+// webDAVPathPrefix starts the source path of records scraped from a WebDAV server
+const webDAVPathPrefix = "webdav:"
+
+// WebDAVConfig represents how many files a WebDAV source downloads and extracts at once
+type WebDAVConfig struct {
+	Extract pipeline.StageConfig // Downloads files and extracts their records
+	Buffers Buffers
+}
+
+// WebDAVSource reads the files of a WebDAV collection and its
+// subcollections. Records are stamped with the etag of their file, and files
+// whose etag is in the ledger are skipped, so scrapes only read new and
+// modified files. The ledger ingestor records the etag once the record is
+// ingested and deletes the record of the file's previous version, so files
+// failing to be read or ingested are read again by the next scrape. Files
+// are downloaded and extracted by the workers of the extract stage.
+type WebDAVSource struct {
+	client    webdav.Client
+	extractor extractor.ContentExtractor
+	ledger    ledger.Store
+	config    WebDAVConfig
+}
+
+// NewWebDAVSource creates a new WebDAV source skipping the files in the ledger
+func NewWebDAVSource(client webdav.Client, extractor extractor.ContentExtractor, ledger ledger.Store, config WebDAVConfig) Source {
+	return &WebDAVSource{
+		client:    client,
+		extractor: extractor,
+		ledger:    ledger,
+		config:    config,
+	}
+}
+
+// Name returns the source name
+func (ws *WebDAVSource) Name() string {
+	return "webdav"
+}
+
+// Scrape reads the files added or modified since their record was ingested
+func (ws *WebDAVSource) Scrape(ctx context.Context) (<-chan records.Record, <-chan error) {
+	recordChan := make(chan records.Record, max(ws.config.Buffers.Records, 0))
+	errChan := make(chan error, max(ws.config.Buffers.Errors, 1))
+
+	go func() {
+		defer close(recordChan)
+		defer close(errChan)
+		if err := ws.run(ctx, recordChan, errChan); err != nil {
+			pipeline.Send(ctx, errChan, err, nil)
+		}
+	}()
+
+	return recordChan, errChan
+}
+
+// run sends the records of the files whose etag is not in the ledger
+func (ws *WebDAVSource) run(ctx context.Context, recordChan chan<- records.Record, errChan chan<- error) error {
+	files, err := ws.walk(ctx)
+	if err != nil {
+		return err
+	}
+	var changed []webdav.Entry
+	for _, file := range files {
+		unchanged, err := ingested(ctx, ws.ledger, webDAVPathPrefix+file.Href, file.ETag)
+		if err != nil {
+			return err
+		}
+		if !unchanged {
+			changed = append(changed, file)
+		}
+	}
+	extractAll(ctx, ws.config.Extract, changed, ws.extract, recordChan, errChan)
+	return nil
+}
+
+// walk returns the files of the base collection and its subcollections
+func (ws *WebDAVSource) walk(ctx context.Context) ([]webdav.Entry, error) {
+	var files []webdav.Entry
+	queue := []string{""}
+	seen := map[string]bool{}
+	for len(queue) > 0 {
+		entries, err := ws.client.List(ctx, queue[0])
+		if err != nil {
+			return nil, fmt.Errorf("failed to list webdav collection: %w", err)
+		}
+		queue = queue[1:]
+		for _, entry := range entries {
+			if !entry.Collection {
+				files = append(files, entry)
+			} else if !seen[entry.Href] {
+				seen[entry.Href] = true
+				queue = append(queue, entry.Href)
+			}
+		}
+	}
+	return files, nil
+}
+
+// extract reads a file and extracts its record, stamped with the file's etag
+func (ws *WebDAVSource) extract(ctx context.Context, file webdav.Entry) (records.Record, error) {
+	content, err := ws.client.Get(ctx, file.Href)
+	if err != nil {
+		return records.Record{}, fmt.Errorf("failed to download webdav file %s: %w", file.Name, err)
+	}
+	// Servers report unknown files as octet streams, which are sniffed instead
+	mediaType := file.ContentType
+	if mediaType == "application/octet-stream" {
+		mediaType = ""
+	}
+	path := webDAVPathPrefix + file.Href
+	input, scan, err := remoteInput(content, mediaType, path, file.ETag)
+	_ = content.Close()
+	if err != nil {
+		return records.Record{}, fmt.Errorf("failed to read webdav file %s: %w", file.Name, err)
+	}
+
+	record, err := ws.extractor.Extract(ctx, input)
+	if err != nil {
+		return records.Record{}, fmt.Errorf("failed to extract record from webdav file %s: %w", file.Name, err)
+	}
+	return scan.Stamp(located(record, path, file.Name)), nil
+}
@@ -0,0 +1,118 @@
+package source_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"testing"
+
+	"github.com/kazemisoroush/assistant/pkg/records"
+	"github.com/kazemisoroush/assistant/pkg/records/extractor"
+	"github.com/kazemisoroush/assistant/pkg/records/extractor/mocks"
+	"github.com/kazemisoroush/assistant/pkg/records/ledger"
+	ledgermocks "github.com/kazemisoroush/assistant/pkg/records/ledger/mocks"
+	"github.com/kazemisoroush/assistant/pkg/records/pipeline"
+	"github.com/kazemisoroush/assistant/pkg/records/source"
+	"github.com/kazemisoroush/assistant/pkg/webdav"
+	webdavmocks "github.com/kazemisoroush/assistant/pkg/webdav/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestWebDAVSource_Scrape_Changed(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	client := webdavmocks.NewMockClient(ctrl)
+	contentExtractor := mocks.NewMockContentExtractor(ctrl)
+	scans := ledgermocks.NewMockStore(ctrl)
+	client.EXPECT().List(gomock.Any(), "").Return([]webdav.Entry{
+		{Href: "/Scans/same.pdf", Name: "same.pdf", ETag: "1", ContentType: "application/pdf"},
+		{Href: "/Scans/edited.pdf", Name: "edited.pdf", ETag: "2", ContentType: "application/pdf"},
+		{Href: "/Scans/2024/", Name: "2024", Collection: true},
+	}, nil)
+	client.EXPECT().List(gomock.Any(), "/Scans/2024/").Return([]webdav.Entry{
+		{Href: "/Scans/2024/new.png", Name: "new.png", ETag: "1", ContentType: "image/png"},
+	}, nil)
+	scans.EXPECT().Get(gomock.Any(), "webdav:/Scans/same.pdf").Return(ledger.Entry{Version: "1", RecordID: "rec-1"}, true, nil)
+	scans.EXPECT().Get(gomock.Any(), "webdav:/Scans/edited.pdf").Return(ledger.Entry{Version: "1", RecordID: "rec-2"}, true, nil)
+	scans.EXPECT().Get(gomock.Any(), "webdav:/Scans/2024/new.png").Return(ledger.Entry{}, false, nil)
+	client.EXPECT().Get(gomock.Any(), "/Scans/edited.pdf").Return(content("%PDF-1.7"), nil)
+	client.EXPECT().Get(gomock.Any(), "/Scans/2024/new.png").Return(content("\x89PNG\r\n\x1a\n"), nil)
+	contentExtractor.EXPECT().Extract(gomock.Any(), gomock.Any()).DoAndReturn(extractMediaType).Times(2)
+	src := source.NewWebDAVSource(client, contentExtractor, scans, source.WebDAVConfig{})
+
+	// Act
+	recs, errs := drain(src.Scrape(context.Background()))
+
+	// Assert
+	require.Empty(t, errs, "Scrape() should not report errors")
+	require.Len(t, recs, 2, "Scrape() should read only new and modified files")
+	assert.Equal(t, "edited", recs[0].Title, "Scrape() should title records by file name")
+	assert.Equal(t, "webdav:/Scans/edited.pdf", recs[0].SourcePath(), "Scrape() should record the file the record came from")
+	assert.Equal(t, "image/png", recs[1].Content, "Scrape() should extract files by their content type")
+	stamped, ok := ledger.Stamped(recs[0])
+	require.True(t, ok, "Scrape() should stamp records with the version of their file")
+	assert.Equal(t, "2", stamped.Version, "Scrape() should stamp records with the etag of their file")
+}
+
+func TestWebDAVSource_Scrape_Failed(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	client := webdavmocks.NewMockClient(ctrl)
+	contentExtractor := mocks.NewMockContentExtractor(ctrl)
+	scans := ledgermocks.NewMockStore(ctrl)
+	client.EXPECT().List(gomock.Any(), "").Return([]webdav.Entry{
+		{Href: "/a.pdf", Name: "a.pdf", ETag: "2"},
+		{Href: "/b.txt", Name: "b.txt", ETag: "1", ContentType: "text/plain"},
+	}, nil)
+	scans.EXPECT().Get(gomock.Any(), gomock.Any()).Return(ledger.Entry{}, false, nil).Times(2)
+	client.EXPECT().Get(gomock.Any(), "/a.pdf").Return(nil, errors.New("connection reset"))
+	client.EXPECT().Get(gomock.Any(), "/b.txt").Return(content("notes"), nil)
+	contentExtractor.EXPECT().Extract(gomock.Any(), gomock.Any()).DoAndReturn(extractMediaType)
+	src := source.NewWebDAVSource(client, contentExtractor, scans, source.WebDAVConfig{Buffers: source.Buffers{Errors: 2}})
+
+	// Act
+	recs, errs := drain(src.Scrape(context.Background()))
+
+	// Assert
+	require.Len(t, errs, 1, "Scrape() should report the file failing to download")
+	assert.Contains(t, errs[0].Error(), "connection reset", "Scrape() should wrap the download error")
+	assert.Len(t, recs, 1, "Scrape() should read the remaining files")
+}
+
+func TestWebDAVSource_Scrape_Concurrent(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	client := webdavmocks.NewMockClient(ctrl)
+	contentExtractor := mocks.NewMockContentExtractor(ctrl)
+	client.EXPECT().List(gomock.Any(), "").Return([]webdav.Entry{
+		{Href: "/a.txt", Name: "a.txt", ETag: "1"},
+		{Href: "/b.txt", Name: "b.txt", ETag: "1"},
+		{Href: "/c.txt", Name: "c.txt", ETag: "1"},
+	}, nil)
+	client.EXPECT().Get(gomock.Any(), gomock.Any()).DoAndReturn(func(context.Context, string) (io.ReadCloser, error) {
+		return content("notes"), nil
+	}).Times(3)
+	// Every extraction waits until all three started, so they only finish when run at once
+	var started sync.WaitGroup
+	started.Add(3)
+	contentExtractor.EXPECT().Extract(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, input extractor.Input) (records.Record, error) {
+		started.Done()
+		started.Wait()
+		return extractMediaType(ctx, input)
+	}).Times(3)
+	scans := ledgermocks.NewMockStore(ctrl)
+	scans.EXPECT().Get(gomock.Any(), gomock.Any()).Return(ledger.Entry{}, false, nil).Times(3)
+	src := source.NewWebDAVSource(client, contentExtractor, scans, source.WebDAVConfig{
+		Extract: pipeline.StageConfig{Workers: 3},
+	})
+
+	// Act
+	recs, errs := drain(src.Scrape(context.Background()))
+
+	// Assert
+	assert.Empty(t, errs, "Scrape() should not report errors")
+	assert.Len(t, recs, 3, "Scrape() should extract files with every worker of the extract stage")
+}
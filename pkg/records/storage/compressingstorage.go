@@ -0,0 +1,190 @@
+package storage
+
+import (
+	"bytes"
+	"compress/flate"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/kazemisoroush/assistant/pkg/records"
+)
+
+// compressedPrefix marks content stored compressed. Extracted text never
+// starts with a NUL byte, so content stored before compression was enabled
+// is read back unchanged.
+const compressedPrefix = "\x00deflate:"
+
+// compressMinSize is the content length below which compression does not pay
+// for its prefix and encoding
+const compressMinSize = 512
+
+// CompressingStorage is a Storage that stores record content compressed and
+// decompresses it on read, for backends that keep content as text. Callers,
+// and the indexes built from the records they ingest, only ever see the
+// uncompressed content. SQLiteStorage compresses content itself, so its
+// search index keeps the text.
+type CompressingStorage struct {
+	Storage
+}
+
+// NewCompressingStorage wraps a storage so record content is stored compressed
+func NewCompressingStorage(storage Storage) Storage {
+	return &CompressingStorage{Storage: storage}
+}
+
+// Store implements Storage
+func (s *CompressingStorage) Store(ctx context.Context, rec records.Record) error {
+	rec, err := compress(rec)
+	if err != nil {
+		return err
+	}
+	return s.Storage.Store(ctx, rec)
+}
+
+// StoreBatch implements Storage
+func (s *CompressingStorage) StoreBatch(ctx context.Context, recs []records.Record) error {
+	compressed := make([]records.Record, len(recs))
+	for i, rec := range recs {
+		var err error
+		if compressed[i], err = compress(rec); err != nil {
+			return err
+		}
+	}
+	return s.Storage.StoreBatch(ctx, compressed)
+}
+
+// Update implements Storage
+func (s *CompressingStorage) Update(ctx context.Context, rec records.Record) error {
+	rec, err := compress(rec)
+	if err != nil {
+		return err
+	}
+	return s.Storage.Update(ctx, rec)
+}
+
+// Get implements Storage
+func (s *CompressingStorage) Get(ctx context.Context, id string) (records.Record, error) {
+	rec, err := s.Storage.Get(ctx, id)
+	if err != nil {
+		return records.Record{}, err
+	}
+	return decompress(rec)
+}
+
+// List implements Storage
+func (s *CompressingStorage) List(ctx context.Context, recType records.RecordType) ([]records.Record, error) {
+	recs, err := s.Storage.List(ctx, recType)
+	if err != nil {
+		return nil, err
+	}
+	return decompressAll(recs)
+}
+
+// ListExpiring implements Storage
+func (s *CompressingStorage) ListExpiring(ctx context.Context, until time.Time) ([]records.Record, error) {
+	recs, err := s.Storage.ListExpiring(ctx, until)
+	if err != nil {
+		return nil, err
+	}
+	return decompressAll(recs)
+}
+
+// ListByTag implements Storage
+func (s *CompressingStorage) ListByTag(ctx context.Context, tag string) ([]records.Record, error) {
+	recs, err := s.Storage.ListByTag(ctx, tag)
+	if err != nil {
+		return nil, err
+	}
+	return decompressAll(recs)
+}
+
+// Search implements Storage by scanning the decompressed records, as the
+// wrapped storage only holds the compressed content
+func (s *CompressingStorage) Search(ctx context.Context, query string, filter SearchFilter, limit int) ([]records.SearchResult, error) {
+	return scanSearch(ctx, s.Each, query, filter, limit)
+}
+
+// Each implements Storage
+func (s *CompressingStorage) Each(ctx context.Context, recType records.RecordType, fn func(records.Record) error) error {
+	return s.Storage.Each(ctx, recType, func(rec records.Record) error {
+		rec, err := decompress(rec)
+		if err != nil {
+			return err
+		}
+		return fn(rec)
+	})
+}
+
+// compress returns the record with its content compressed, unless it is too
+// short or compressing would not make it smaller
+func compress(rec records.Record) (records.Record, error) {
+	if len(rec.Content) < compressMinSize {
+		return rec, nil
+	}
+	compressed, err := deflate(rec.Content)
+	if err != nil {
+		return records.Record{}, fmt.Errorf("failed to compress content of record %s: %w", rec.ID, err)
+	}
+	content := compressedPrefix + base64.StdEncoding.EncodeToString(compressed)
+	if len(content) < len(rec.Content) {
+		rec.Content = content
+	}
+	return rec, nil
+}
+
+// decompress returns the record with its content decompressed, if it was stored compressed
+func decompress(rec records.Record) (records.Record, error) {
+	encoded, ok := strings.CutPrefix(rec.Content, compressedPrefix)
+	if !ok {
+		return rec, nil
+	}
+	compressed, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return records.Record{}, fmt.Errorf("failed to decompress content of record %s: %w", rec.ID, err)
+	}
+	if rec.Content, err = inflate(compressed); err != nil {
+		return records.Record{}, fmt.Errorf("failed to decompress content of record %s: %w", rec.ID, err)
+	}
+	return rec, nil
+}
+
+// deflate compresses text with DEFLATE
+func deflate(text string) ([]byte, error) {
+	var buf bytes.Buffer
+	w, err := flate.NewWriter(&buf, flate.DefaultCompression)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := io.WriteString(w, text); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// inflate decompresses text compressed by deflate
+func inflate(compressed []byte) (string, error) {
+	text, err := io.ReadAll(flate.NewReader(bytes.NewReader(compressed)))
+	if err != nil {
+		return "", err
+	}
+	return string(text), nil
+}
+
+// decompressAll decompresses the content of every record
+func decompressAll(recs []records.Record) ([]records.Record, error) {
+	for i, rec := range recs {
+		rec, err := decompress(rec)
+		if err != nil {
+			return nil, err
+		}
+		recs[i] = rec
+	}
+	return recs, nil
+}
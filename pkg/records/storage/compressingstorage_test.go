@@ -0,0 +1,94 @@
+package storage_test
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kazemisoroush/assistant/pkg/records"
+	"github.com/kazemisoroush/assistant/pkg/records/storage"
+	"github.com/kazemisoroush/assistant/pkg/records/storage/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestCompressingStorage_Store_CompressesLongContent(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	inner := mocks.NewMockStorage(ctrl)
+	content := strings.Repeat("Invoice total 42.00 EUR paid by card. ", 50)
+	var stored records.Record
+	inner.EXPECT().Store(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, rec records.Record) error {
+		stored = rec
+		return nil
+	})
+	s := storage.NewCompressingStorage(inner)
+
+	// Act
+	err := s.Store(context.Background(), records.Record{ID: "rec-1", Content: content})
+
+	// Assert
+	require.NoError(t, err, "Store() error should be nil")
+	assert.Less(t, len(stored.Content), len(content)/2, "Store() should compress long content")
+}
+
+func TestCompressingStorage_Store_KeepsShortContent(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	inner := mocks.NewMockStorage(ctrl)
+	inner.EXPECT().Store(gomock.Any(), records.Record{ID: "rec-1", Content: "Short receipt"}).Return(nil)
+	s := storage.NewCompressingStorage(inner)
+
+	// Act
+	err := s.Store(context.Background(), records.Record{ID: "rec-1", Content: "Short receipt"})
+
+	// Assert
+	require.NoError(t, err, "Store() error should be nil")
+}
+
+func TestCompressingStorage_RoundTrip(t *testing.T) {
+	// Arrange
+	jsonStorage, err := storage.NewJSONStorage(filepath.Join(t.TempDir(), "records.json"))
+	require.NoError(t, err, "NewJSONStorage() error should be nil")
+	ctx := context.Background()
+	require.NoError(t, jsonStorage.Store(ctx, records.Record{ID: "plain", Type: records.RecordTypeReceipt, Content: strings.Repeat("stored before compression ", 40)}), "Store() error should be nil")
+	s := storage.NewCompressingStorage(jsonStorage)
+	content := strings.Repeat("Blood test results within normal range. ", 40)
+
+	// Act
+	require.NoError(t, s.Store(ctx, records.Record{ID: "compressed", Type: records.RecordTypeHealthVisit, Content: content}), "Store() error should be nil")
+	got, err := s.Get(ctx, "compressed")
+	require.NoError(t, err, "Get() error should be nil")
+	raw, err := jsonStorage.Get(ctx, "compressed")
+	require.NoError(t, err, "Get() error should be nil")
+	plain, err := s.Get(ctx, "plain")
+	require.NoError(t, err, "Get() error should be nil")
+
+	// Assert
+	assert.Equal(t, content, got.Content, "Get() should decompress the content")
+	assert.NotEqual(t, content, raw.Content, "the content should be stored compressed")
+	assert.Equal(t, strings.Repeat("stored before compression ", 40), plain.Content, "Get() should read uncompressed content unchanged")
+}
+
+func TestCompressingStorage_Search(t *testing.T) {
+	// Arrange
+	jsonStorage, err := storage.NewJSONStorage(filepath.Join(t.TempDir(), "records.json"))
+	require.NoError(t, err, "NewJSONStorage() error should be nil")
+	ctx := context.Background()
+	s := storage.NewCompressingStorage(jsonStorage)
+	filler := strings.Repeat("Results within normal range. ", 40)
+	require.NoError(t, s.StoreBatch(ctx, []records.Record{
+		{ID: "blood", Type: records.RecordTypeHealthVisit, Content: "Blood test. " + filler},
+		{ID: "xray", Type: records.RecordTypeHealthVisit, Content: "Chest x-ray. " + filler},
+	}), "StoreBatch() error should be nil")
+
+	// Act
+	results, err := s.Search(ctx, "blood", storage.SearchFilter{}, 10)
+
+	// Assert
+	require.NoError(t, err, "Search() error should be nil")
+	require.Len(t, results, 1, "Search() should find the text of compressed content")
+	assert.Equal(t, "Blood test. "+filler, results[0].Record.Content, "Search() should decompress the content of the results")
+}
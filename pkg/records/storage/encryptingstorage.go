@@ -0,0 +1,288 @@
+package storage
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kazemisoroush/assistant/pkg/keys"
+	"github.com/kazemisoroush/assistant/pkg/records"
+)
+
+// encryptedPrefix marks content holding a sealed record
+const encryptedPrefix = "\x00aes-gcm:"
+
+// sealed is the part of a record only the key holder can read
+type sealed struct {
+	Title    string                 `json:"title,omitempty"`
+	Content  string                 `json:"content"`
+	FilePath string                 `json:"file_path,omitempty"`
+	FileName string                 `json:"file_name,omitempty"`
+	Metadata map[string]interface{} `json:"metadata,omitempty"`
+	Tags     []string               `json:"tags,omitempty"`
+}
+
+// EncryptingStorage is a Storage that encrypts the title, content, file,
+// metadata and tags of records with AES-256-GCM before storing them and decrypts them on read,
+// so the storage only ever holds ciphertext. The ID, type and dates stay
+// readable, so the storage can still filter records by type and expiry, and
+// are authenticated with the sealed parts, except the update date the storage
+// sets. Records sealed with the key replaced by the last rotation are still
+// read; records the storage returns unsealed are rejected.
+type EncryptingStorage struct {
+	Storage
+	keys keys.KeyManager
+
+	mu       sync.Mutex
+	current  cipher.AEAD
+	previous cipher.AEAD // nil before the first rotation
+}
+
+// NewEncryptingStorage wraps a storage so records are stored encrypted with
+// the key manager's key. Keys are loaded on first use.
+func NewEncryptingStorage(storage Storage, keyManager keys.KeyManager) Storage {
+	return &EncryptingStorage{Storage: storage, keys: keyManager}
+}
+
+// Store implements Storage
+func (s *EncryptingStorage) Store(ctx context.Context, rec records.Record) error {
+	rec, err := s.seal(ctx, rec)
+	if err != nil {
+		return err
+	}
+	return s.Storage.Store(ctx, rec)
+}
+
+// StoreBatch implements Storage
+func (s *EncryptingStorage) StoreBatch(ctx context.Context, recs []records.Record) error {
+	sealed := make([]records.Record, len(recs))
+	for i, rec := range recs {
+		var err error
+		if sealed[i], err = s.seal(ctx, rec); err != nil {
+			return err
+		}
+	}
+	return s.Storage.StoreBatch(ctx, sealed)
+}
+
+// Update implements Storage. The storage keeps the creation date of the
+// record it updates, so the record is sealed with it.
+func (s *EncryptingStorage) Update(ctx context.Context, rec records.Record) error {
+	stored, err := s.Storage.Get(ctx, rec.ID)
+	if err != nil {
+		return err
+	}
+	rec.CreatedAt = stored.CreatedAt
+	rec, err = s.seal(ctx, rec)
+	if err != nil {
+		return err
+	}
+	return s.Storage.Update(ctx, rec)
+}
+
+// Get implements Storage
+func (s *EncryptingStorage) Get(ctx context.Context, id string) (records.Record, error) {
+	rec, err := s.Storage.Get(ctx, id)
+	if err != nil {
+		return records.Record{}, err
+	}
+	return s.open(ctx, rec)
+}
+
+// List implements Storage
+func (s *EncryptingStorage) List(ctx context.Context, recType records.RecordType) ([]records.Record, error) {
+	recs, err := s.Storage.List(ctx, recType)
+	if err != nil {
+		return nil, err
+	}
+	return s.openAll(ctx, recs)
+}
+
+// ListExpiring implements Storage
+func (s *EncryptingStorage) ListExpiring(ctx context.Context, until time.Time) ([]records.Record, error) {
+	recs, err := s.Storage.ListExpiring(ctx, until)
+	if err != nil {
+		return nil, err
+	}
+	return s.openAll(ctx, recs)
+}
+
+// ListByTag implements Storage. Tags are sealed, so the records are opened
+// and filtered here rather than by the storage.
+func (s *EncryptingStorage) ListByTag(ctx context.Context, tag string) ([]records.Record, error) {
+	recs, err := s.List(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+	tagged := recs[:0]
+	for _, rec := range recs {
+		if slices.Contains(rec.Tags, tag) {
+			tagged = append(tagged, rec)
+		}
+	}
+	return tagged, nil
+}
+
+// Search implements Storage. The storage only holds ciphertext, so the
+// opened records are scanned instead.
+func (s *EncryptingStorage) Search(ctx context.Context, query string, filter SearchFilter, limit int) ([]records.SearchResult, error) {
+	return scanSearch(ctx, s.Each, query, filter, limit)
+}
+
+// Each implements Storage
+func (s *EncryptingStorage) Each(ctx context.Context, recType records.RecordType, fn func(records.Record) error) error {
+	return s.Storage.Each(ctx, recType, func(rec records.Record) error {
+		rec, err := s.open(ctx, rec)
+		if err != nil {
+			return err
+		}
+		return fn(rec)
+	})
+}
+
+// seal returns the record with its title, content, file, metadata and tags
+// encrypted. The readable fields are authenticated, so sealed parts cannot be
+// moved to another record and the type and dates cannot be changed.
+func (s *EncryptingStorage) seal(ctx context.Context, rec records.Record) (records.Record, error) {
+	current, _, err := s.ciphers(ctx)
+	if err != nil {
+		return records.Record{}, err
+	}
+	plaintext, err := json.Marshal(sealed{
+		Title:    rec.Title,
+		Content:  rec.Content,
+		FilePath: rec.FilePath,
+		FileName: rec.FileName,
+		Metadata: rec.Metadata,
+		Tags:     rec.Tags,
+	})
+	if err != nil {
+		return records.Record{}, fmt.Errorf("failed to encrypt record %s: %w", rec.ID, err)
+	}
+
+	nonce := make([]byte, current.NonceSize(), current.NonceSize()+len(plaintext)+current.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return records.Record{}, fmt.Errorf("failed to encrypt record %s: %w", rec.ID, err)
+	}
+	ciphertext := current.Seal(nonce, nonce, plaintext, associatedData(rec))
+
+	rec.Content = encryptedPrefix + base64.StdEncoding.EncodeToString(ciphertext)
+	rec.Title, rec.FilePath, rec.FileName = "", "", ""
+	rec.Metadata = nil
+	rec.Tags = nil
+	return rec, nil
+}
+
+// open returns the record with its sealed parts decrypted. Records not
+// sealed are rejected, as the storage is not trusted to return them.
+func (s *EncryptingStorage) open(ctx context.Context, rec records.Record) (records.Record, error) {
+	encoded, ok := strings.CutPrefix(rec.Content, encryptedPrefix)
+	if !ok {
+		return records.Record{}, fmt.Errorf("failed to decrypt record %s: record is not encrypted", rec.ID)
+	}
+	ciphertext, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return records.Record{}, fmt.Errorf("failed to decrypt record %s: %w", rec.ID, err)
+	}
+	current, previous, err := s.ciphers(ctx)
+	if err != nil {
+		return records.Record{}, err
+	}
+
+	plaintext, err := decrypt(current, ciphertext, associatedData(rec))
+	if err != nil && previous != nil {
+		plaintext, err = decrypt(previous, ciphertext, associatedData(rec))
+	}
+	if err != nil {
+		return records.Record{}, fmt.Errorf("failed to decrypt record %s: %w", rec.ID, err)
+	}
+
+	var parts sealed
+	if err := json.Unmarshal(plaintext, &parts); err != nil {
+		return records.Record{}, fmt.Errorf("failed to decrypt record %s: %w", rec.ID, err)
+	}
+	rec.Title, rec.Content, rec.Metadata, rec.Tags = parts.Title, parts.Content, parts.Metadata, parts.Tags
+	rec.FilePath, rec.FileName = parts.FilePath, parts.FileName
+	return rec, nil
+}
+
+// openAll decrypts every record
+func (s *EncryptingStorage) openAll(ctx context.Context, recs []records.Record) ([]records.Record, error) {
+	for i, rec := range recs {
+		rec, err := s.open(ctx, rec)
+		if err != nil {
+			return nil, err
+		}
+		recs[i] = rec
+	}
+	return recs, nil
+}
+
+// ciphers returns the ciphers of the current and previous keys, loading the
+// keys once, as deriving them can be slow
+func (s *EncryptingStorage) ciphers(ctx context.Context) (cipher.AEAD, cipher.AEAD, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.current != nil {
+		return s.current, s.previous, nil
+	}
+
+	key, err := s.keys.Key(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load encryption key: %w", err)
+	}
+	current, err := newAEAD(key)
+	if err != nil {
+		return nil, nil, err
+	}
+	previousKey, err := s.keys.Previous(ctx)
+	if err != nil && !errors.Is(err, keys.ErrKeyNotFound) {
+		return nil, nil, fmt.Errorf("failed to load previous encryption key: %w", err)
+	}
+	if err == nil {
+		if s.previous, err = newAEAD(previousKey); err != nil {
+			return nil, nil, err
+		}
+	}
+	s.current = current
+	return s.current, s.previous, nil
+}
+
+// newAEAD returns the AES-GCM cipher of the key
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("invalid encryption key: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
+
+// associatedData returns the readable fields of a record authenticated with
+// its sealed parts. Dates are compared as instants, so storages keeping them
+// in another time zone still match.
+func associatedData(rec records.Record) []byte {
+	expires := ""
+	if rec.ExpiresAt != nil {
+		expires = strconv.FormatInt(rec.ExpiresAt.UnixNano(), 10)
+	}
+	return []byte(strings.Join([]string{rec.ID, string(rec.Type), strconv.FormatInt(rec.CreatedAt.UnixNano(), 10), expires}, "\x00"))
+}
+
+// decrypt opens the nonce-prefixed ciphertext of a record
+func decrypt(aead cipher.AEAD, ciphertext []byte, associatedData []byte) ([]byte, error) {
+	if len(ciphertext) < aead.NonceSize() {
+		return nil, fmt.Errorf("ciphertext is too short")
+	}
+	nonce, box := ciphertext[:aead.NonceSize()], ciphertext[aead.NonceSize():]
+	return aead.Open(nil, nonce, box, associatedData)
+}
@@ -0,0 +1,206 @@
+package storage_test
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kazemisoroush/assistant/pkg/keys"
+	keymocks "github.com/kazemisoroush/assistant/pkg/keys/mocks"
+	"github.com/kazemisoroush/assistant/pkg/records"
+	"github.com/kazemisoroush/assistant/pkg/records/storage"
+	"github.com/kazemisoroush/assistant/pkg/records/storage/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+// key returns a test key filled with the byte
+func key(b byte) []byte {
+	return bytes.Repeat([]byte{b}, keys.KeySize)
+}
+
+func TestEncryptingStorage_Store_SealsRecord(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	inner := mocks.NewMockStorage(ctrl)
+	keyManager := keymocks.NewMockKeyManager(ctrl)
+	keyManager.EXPECT().Key(gomock.Any()).Return(key(1), nil)
+	keyManager.EXPECT().Previous(gomock.Any()).Return(nil, keys.ErrKeyNotFound)
+	var stored records.Record
+	inner.EXPECT().Store(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, rec records.Record) error {
+		stored = rec
+		return nil
+	})
+	s := storage.NewEncryptingStorage(inner, keyManager)
+
+	// Act
+	err := s.Store(context.Background(), records.Record{
+		ID:       "rec-1",
+		Type:     records.RecordTypeReceipt,
+		Title:    "Pharmacy receipt",
+		Content:  "Pharmacy receipt, 12.40 EUR",
+		FilePath: "/scans/pharmacy.pdf",
+		FileName: "pharmacy.pdf",
+		Metadata: map[string]interface{}{"merchant": "Apotheke"},
+		Tags:     []string{"health"},
+	})
+
+	// Assert
+	require.NoError(t, err, "Store() error should be nil")
+	assert.NotContains(t, stored.Content, "Pharmacy", "Store() should encrypt the content")
+	assert.Nil(t, stored.Metadata, "Store() should encrypt the metadata")
+	assert.Nil(t, stored.Tags, "Store() should encrypt the tags")
+	assert.Empty(t, stored.Title+stored.FilePath+stored.FileName, "Store() should encrypt the title and file")
+	assert.Equal(t, records.RecordTypeReceipt, stored.Type, "Store() should keep the type readable for filtering")
+}
+
+func TestEncryptingStorage_RoundTrip(t *testing.T) {
+	// Arrange
+	sqliteStorage, err := storage.NewSQLiteStorage(openDB(t, filepath.Join(t.TempDir(), "records.db")))
+	require.NoError(t, err, "NewSQLiteStorage() error should be nil")
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	keyManager := keymocks.NewMockKeyManager(ctrl)
+	keyManager.EXPECT().Key(gomock.Any()).Return(key(1), nil)
+	keyManager.EXPECT().Previous(gomock.Any()).Return(nil, keys.ErrKeyNotFound)
+	s := storage.NewEncryptingStorage(sqliteStorage, keyManager)
+	expires := time.Date(2027, time.January, 1, 0, 0, 0, 0, time.FixedZone("CET", 3600))
+	rec := records.Record{ID: "sealed", Type: records.RecordTypeReceipt, Content: "Blood test results", Metadata: map[string]interface{}{"lab": "City Lab"},
+		CreatedAt: time.Date(2026, time.March, 1, 9, 30, 0, 123, time.Local), ExpiresAt: &expires}
+
+	// Act
+	require.NoError(t, s.Store(ctx, rec), "Store() error should be nil")
+	got, err := s.Get(ctx, "sealed")
+	require.NoError(t, err, "Get() error should be nil")
+	listed, err := s.List(ctx, records.RecordTypeReceipt)
+
+	// Assert
+	require.NoError(t, err, "List() error should be nil")
+	assert.Equal(t, "Blood test results", got.Content, "Get() should decrypt the content")
+	assert.Equal(t, "City Lab", got.Metadata["lab"], "Get() should decrypt the metadata")
+	require.Len(t, listed, 1, "List() should return every record")
+	assert.Equal(t, "Blood test results", listed[0].Content, "List() should decrypt the content")
+	raw, err := sqliteStorage.Get(ctx, "sealed")
+	require.NoError(t, err, "Get() error should be nil")
+	assert.False(t, strings.Contains(raw.Content, "Blood"), "the storage should only hold ciphertext")
+}
+
+func TestEncryptingStorage_Get_PreviousKey(t *testing.T) {
+	// Arrange
+	sqliteStorage, err := storage.NewSQLiteStorage(openDB(t, filepath.Join(t.TempDir(), "records.db")))
+	require.NoError(t, err, "NewSQLiteStorage() error should be nil")
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	before := keymocks.NewMockKeyManager(ctrl)
+	before.EXPECT().Key(gomock.Any()).Return(key(1), nil)
+	before.EXPECT().Previous(gomock.Any()).Return(nil, keys.ErrKeyNotFound)
+	require.NoError(t, storage.NewEncryptingStorage(sqliteStorage, before).Store(ctx, records.Record{ID: "rec-1", Content: "Visa"}), "Store() error should be nil")
+	rotated := keymocks.NewMockKeyManager(ctrl)
+	rotated.EXPECT().Key(gomock.Any()).Return(key(2), nil)
+	rotated.EXPECT().Previous(gomock.Any()).Return(key(1), nil)
+
+	// Act
+	got, err := storage.NewEncryptingStorage(sqliteStorage, rotated).Get(ctx, "rec-1")
+
+	// Assert
+	require.NoError(t, err, "Get() error should be nil")
+	assert.Equal(t, "Visa", got.Content, "Get() should decrypt records sealed before the last rotation")
+}
+
+func TestEncryptingStorage_Get_WrongKey(t *testing.T) {
+	// Arrange
+	sqliteStorage, err := storage.NewSQLiteStorage(openDB(t, filepath.Join(t.TempDir(), "records.db")))
+	require.NoError(t, err, "NewSQLiteStorage() error should be nil")
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	owner := keymocks.NewMockKeyManager(ctrl)
+	owner.EXPECT().Key(gomock.Any()).Return(key(1), nil)
+	owner.EXPECT().Previous(gomock.Any()).Return(nil, keys.ErrKeyNotFound)
+	require.NoError(t, storage.NewEncryptingStorage(sqliteStorage, owner).Store(ctx, records.Record{ID: "rec-1", Content: "Visa"}), "Store() error should be nil")
+	other := keymocks.NewMockKeyManager(ctrl)
+	other.EXPECT().Key(gomock.Any()).Return(key(3), nil)
+	other.EXPECT().Previous(gomock.Any()).Return(nil, keys.ErrKeyNotFound)
+
+	// Act
+	_, err = storage.NewEncryptingStorage(sqliteStorage, other).Get(ctx, "rec-1")
+
+	// Assert
+	assert.Error(t, err, "Get() should fail without the key")
+}
+
+func TestEncryptingStorage_Update_KeepsCreatedAt(t *testing.T) {
+	// Arrange
+	sqliteStorage, err := storage.NewSQLiteStorage(openDB(t, filepath.Join(t.TempDir(), "records.db")))
+	require.NoError(t, err, "NewSQLiteStorage() error should be nil")
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	keyManager := keymocks.NewMockKeyManager(ctrl)
+	keyManager.EXPECT().Key(gomock.Any()).Return(key(1), nil)
+	keyManager.EXPECT().Previous(gomock.Any()).Return(nil, keys.ErrKeyNotFound)
+	s := storage.NewEncryptingStorage(sqliteStorage, keyManager)
+	created := time.Date(2026, time.March, 1, 0, 0, 0, 0, time.UTC)
+	require.NoError(t, s.Store(ctx, records.Record{ID: "rec-1", Content: "Visa", CreatedAt: created}), "Store() error should be nil")
+
+	// Act
+	err = s.Update(ctx, records.Record{ID: "rec-1", Content: "Visa renewed", CreatedAt: created.AddDate(0, 1, 0)})
+
+	// Assert
+	require.NoError(t, err, "Update() error should be nil")
+	got, err := s.Get(ctx, "rec-1")
+	require.NoError(t, err, "Get() should read an updated record")
+	assert.Equal(t, "Visa renewed", got.Content, "Get() should decrypt the updated content")
+}
+
+func TestEncryptingStorage_Get_Rejected(t *testing.T) {
+	tests := []struct {
+		name   string
+		tamper func(records.Record) records.Record
+	}{
+		{name: "unsealed", tamper: func(rec records.Record) records.Record {
+			rec.Content = "Visa"
+			return rec
+		}},
+		{name: "type changed", tamper: func(rec records.Record) records.Record {
+			rec.Type = records.RecordTypeReceipt
+			return rec
+		}},
+		{name: "expiry changed", tamper: func(rec records.Record) records.Record {
+			rec.ExpiresAt = nil
+			return rec
+		}},
+		{name: "moved", tamper: func(rec records.Record) records.Record {
+			rec.ID = "rec-2"
+			return rec
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			ctx := context.Background()
+			ctrl := gomock.NewController(t)
+			inner := mocks.NewMockStorage(ctrl)
+			keyManager := keymocks.NewMockKeyManager(ctrl)
+			keyManager.EXPECT().Key(gomock.Any()).Return(key(1), nil)
+			keyManager.EXPECT().Previous(gomock.Any()).Return(nil, keys.ErrKeyNotFound)
+			var stored records.Record
+			inner.EXPECT().Store(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, rec records.Record) error {
+				stored = rec
+				return nil
+			})
+			s := storage.NewEncryptingStorage(inner, keyManager)
+			expires := time.Date(2027, time.January, 1, 0, 0, 0, 0, time.UTC)
+			require.NoError(t, s.Store(ctx, records.Record{ID: "rec-1", Content: "Visa", ExpiresAt: &expires}), "Store() error should be nil")
+			inner.EXPECT().Get(gomock.Any(), "rec-1").Return(tt.tamper(stored), nil)
+
+			// Act
+			_, err := s.Get(ctx, "rec-1")
+
+			// Assert
+			assert.Error(t, err, "Get() should reject a record the storage did not return as sealed")
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package storage
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/kazemisoroush/assistant/pkg/keys"
+	"github.com/kazemisoroush/assistant/pkg/sqlite"
+)
+
+// Storage backend names
+const (
+	BackendSQLite    = "sqlite"
+	BackendLocalJSON = "local-json"
+	BackendRemote    = "remote"
+)
+
+// Config represents the configuration used to select and build a storage backend
+type Config struct {
+	Backend         string     // "sqlite", "local-json", "remote"
+	SQLite          *sqlite.DB // Database of the sqlite backend
+	JSONPath        string     // Records file path for the local-json backend
+	CompressContent bool       // Store record content compressed
+
+	// Server of the remote backend. Records are encrypted with the key
+	// manager's key before they are sent, so the server never reads them.
+	RemoteURL   string
+	RemoteToken string
+	HTTPClient  *http.Client
+	Keys        keys.KeyManager
+}
+
+// NewStorage creates the storage backend selected by the given configuration
+func NewStorage(cfg Config) (Storage, error) {
+	switch cfg.Backend {
+	case BackendSQLite:
+		if cfg.SQLite == nil {
+			return nil, fmt.Errorf("the sqlite storage backend requires a database")
+		}
+		// SQLite compresses content itself, so its search index keeps the text
+		newSQLiteStorage := NewSQLiteStorage
+		if cfg.CompressContent {
+			newSQLiteStorage = NewCompressedSQLiteStorage
+		}
+		sqliteStorage, err := newSQLiteStorage(cfg.SQLite)
+		if err != nil {
+			return nil, err
+		}
+		return NewValidatingStorage(sqliteStorage), nil
+	case BackendLocalJSON:
+		jsonStorage, err := NewJSONStorage(cfg.JSONPath)
+		if err != nil {
+			return nil, err
+		}
+		return wrap(jsonStorage, cfg), nil
+	case BackendRemote:
+		if cfg.RemoteURL == "" || cfg.Keys == nil {
+			return nil, fmt.Errorf("remote storage requires a server URL and an encryption key")
+		}
+		// Content is compressed before it is encrypted, as ciphertext does not compress
+		return wrap(NewEncryptingStorage(NewRemoteStorage(cfg.HTTPClient, cfg.RemoteURL, cfg.RemoteToken), cfg.Keys), cfg), nil
+	default:
+		return nil, fmt.Errorf("unknown storage backend: %s", cfg.Backend)
+	}
+}
+
+// wrap validates the records stored in a backend, compressing their content when configured to
+func wrap(recordStorage Storage, cfg Config) Storage {
+	if cfg.CompressContent {
+		recordStorage = NewCompressingStorage(recordStorage)
+	}
+	return NewValidatingStorage(recordStorage)
+}
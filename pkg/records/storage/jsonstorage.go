@@ -0,0 +1,430 @@
+package storage
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/kazemisoroush/assistant/pkg/records"
+)
+
+// Compaction of the records log: it is rewritten with the records alone once
+// it holds more than compactRatio entries per record and at least compactMin.
+const (
+	compactRatio = 2
+	compactMin   = 1024
+)
+
+// JSONStorage implements Storage with the changes to records appended to a
+// log of JSON lines, one line per change, so a write costs the size of the
+// change. Only the position of every record in the log is kept in memory, and
+// records are read from the log when needed. A line cut short by a crash is
+// dropped on open. The log is compacted into one line per record once most
+// of its entries are stale. Records are iterated in the order they were stored.
+type JSONStorage struct {
+	mu      sync.RWMutex
+	path    string
+	size    int64 // Bytes of whole lines in the log
+	entries int   // Records and deletions in the log
+	slots   []slot
+	index   map[string]int // Position of each record in slots
+}
+
+// slot is a record kept in the log, with its type so listings of a type
+// read only the records of that type
+type slot struct {
+	id      string
+	recType records.RecordType
+	loc     location
+}
+
+// location is where the last version of a record is in the log: the line
+// holding it and its position among the records of that line
+type location struct {
+	offset int64
+	length int
+	pos    int
+}
+
+// logEntry is a line of the records log: records stored, replacing those
+// with the same IDs, or a record deleted
+type logEntry struct {
+	Records []records.Record `json:"records,omitempty"`
+	Deleted string           `json:"deleted,omitempty"`
+}
+
+// NewJSONStorage opens the records log at path, creating it on the first write
+func NewJSONStorage(path string) (*JSONStorage, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create storage directory: %w", err)
+	}
+
+	s := &JSONStorage{path: path, index: map[string]int{}}
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read records file: %w", err)
+	}
+	defer func() { _ = file.Close() }()
+	if err := s.load(bufio.NewReader(file)); err != nil {
+		return nil, fmt.Errorf("failed to decode records file %s: %w", path, err)
+	}
+	// Drop a line cut short, so the next change starts on a line of its own
+	if err := os.Truncate(path, s.size); err != nil {
+		return nil, fmt.Errorf("failed to repair records file: %w", err)
+	}
+	return s, nil
+}
+
+// load indexes the whole lines of the log, stopping at a last line without
+// its line break
+func (s *JSONStorage) load(reader *bufio.Reader) error {
+	for {
+		line, err := reader.ReadBytes('\n')
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		var entry logEntry
+		if err := json.Unmarshal(line, &entry); err != nil {
+			return fmt.Errorf("line at byte %d: %w", s.size, err)
+		}
+		s.apply(entry, len(line))
+	}
+}
+
+// Store saves a record, replacing the record with the same ID as StoreBatch does
+func (s *JSONStorage) Store(ctx context.Context, rec records.Record) error {
+	if err := s.StoreBatch(ctx, []records.Record{rec}); err != nil {
+		return fmt.Errorf("failed to store record: %w", err)
+	}
+	return nil
+}
+
+// StoreBatch implements Storage. The records are appended to the log in one line.
+func (s *JSONStorage) StoreBatch(_ context.Context, recs []records.Record) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.write(logEntry{Records: recs}); err != nil {
+		return fmt.Errorf("failed to store batch of %d records: %w", len(recs), err)
+	}
+	return nil
+}
+
+// Get retrieves a record by ID
+func (s *JSONStorage) Get(_ context.Context, id string) (records.Record, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	i, ok := s.index[id]
+	if !ok {
+		return records.Record{}, fmt.Errorf("%w: %s", ErrNotFound, id)
+	}
+	return s.read(s.slots[i])
+}
+
+// List returns all records with optional type filter, newest first
+func (s *JSONStorage) List(ctx context.Context, recType records.RecordType) ([]records.Record, error) {
+	var out []records.Record
+	err := s.Each(ctx, recType, func(rec records.Record) error {
+		out = append(out, rec)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	slices.SortStableFunc(out, func(a, b records.Record) int {
+		return b.CreatedAt.Compare(a.CreatedAt)
+	})
+	return out, nil
+}
+
+// Each implements Storage, reading the records from the log one at a time.
+// The log is read as it was when Each was called, so fn may write to the
+// storage.
+func (s *JSONStorage) Each(_ context.Context, recType records.RecordType, fn func(records.Record) error) error {
+	s.mu.RLock()
+	slots := slices.Clone(s.slots)
+	reader, err := s.open()
+	s.mu.RUnlock()
+	if err != nil {
+		return err
+	}
+	defer reader.close()
+
+	for _, sl := range slots {
+		if recType != "" && sl.recType != recType {
+			continue
+		}
+		rec, err := reader.read(sl)
+		if err != nil {
+			return err
+		}
+		if err := fn(rec); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ListExpiring returns records expiring at or before the given time, soonest first
+func (s *JSONStorage) ListExpiring(ctx context.Context, until time.Time) ([]records.Record, error) {
+	var out []records.Record
+	err := s.Each(ctx, "", func(rec records.Record) error {
+		if rec.ExpiresAt != nil && !rec.ExpiresAt.After(until) {
+			out = append(out, rec)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	slices.SortStableFunc(out, func(a, b records.Record) int {
+		return a.ExpiresAt.Compare(*b.ExpiresAt)
+	})
+	return out, nil
+}
+
+// ListByTag returns the records carrying the tag, newest first
+func (s *JSONStorage) ListByTag(ctx context.Context, tag string) ([]records.Record, error) {
+	var out []records.Record
+	err := s.Each(ctx, "", func(rec records.Record) error {
+		if slices.Contains(rec.Tags, tag) {
+			out = append(out, rec)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	slices.SortStableFunc(out, func(a, b records.Record) int {
+		return b.CreatedAt.Compare(a.CreatedAt)
+	})
+	return out, nil
+}
+
+// Search implements Storage by scanning the records
+func (s *JSONStorage) Search(ctx context.Context, query string, filter SearchFilter, limit int) ([]records.SearchResult, error) {
+	return scanSearch(ctx, s.Each, query, filter, limit)
+}
+
+// Update updates an existing record, keeping the time it was created
+func (s *JSONStorage) Update(_ context.Context, rec records.Record) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i, ok := s.index[rec.ID]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrNotFound, rec.ID)
+	}
+	existing, err := s.read(s.slots[i])
+	if err != nil {
+		return err
+	}
+	rec.CreatedAt = existing.CreatedAt
+	if err := s.write(logEntry{Records: []records.Record{rec}}); err != nil {
+		return fmt.Errorf("failed to update record: %w", err)
+	}
+	return nil
+}
+
+// Delete removes a record
+func (s *JSONStorage) Delete(_ context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.index[id]; !ok {
+		return fmt.Errorf("%w: %s", ErrNotFound, id)
+	}
+	if err := s.write(logEntry{Deleted: id}); err != nil {
+		return fmt.Errorf("failed to delete record: %w", err)
+	}
+	return nil
+}
+
+// apply indexes the change of a log entry written as a line of length bytes
+// at the end of the log
+func (s *JSONStorage) apply(entry logEntry, length int) {
+	for pos, rec := range entry.Records {
+		sl := slot{id: rec.ID, recType: rec.Type, loc: location{offset: s.size, length: length, pos: pos}}
+		if i, ok := s.index[rec.ID]; ok {
+			s.slots[i] = sl
+			continue
+		}
+		s.slots = append(s.slots, sl)
+		s.index[rec.ID] = len(s.slots) - 1
+	}
+	s.entries += len(entry.Records)
+	s.size += int64(length)
+
+	i, ok := s.index[entry.Deleted]
+	if entry.Deleted == "" || !ok {
+		return
+	}
+	s.slots = slices.Delete(s.slots, i, i+1)
+	delete(s.index, entry.Deleted)
+	for _, sl := range s.slots[i:] {
+		s.index[sl.id]--
+	}
+	s.entries++
+}
+
+// write appends a change to the log and indexes it, compacting the log when
+// most of it is stale. A failed append is cut off the log, leaving the
+// records unchanged.
+func (s *JSONStorage) write(entry logEntry) error {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to encode records: %w", err)
+	}
+	line = append(line, '\n')
+
+	file, err := os.OpenFile(s.path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open records file: %w", err)
+	}
+	_, err = file.Write(line)
+	if err = errors.Join(err, file.Close()); err != nil {
+		_ = os.Truncate(s.path, s.size)
+		return fmt.Errorf("failed to write records file: %w", err)
+	}
+	s.apply(entry, len(line))
+
+	if s.entries >= compactMin && s.entries > compactRatio*len(s.slots) {
+		// The change is saved either way, so a failed compaction is retried on the next one
+		if err := s.compact(); err != nil {
+			slog.Warn("Failed to compact records file", "path", s.path, "error", err)
+		}
+	}
+	return nil
+}
+
+// compact rewrites the log as one line per record, copying the records one
+// at a time into a temporary file renamed over it so a crash never leaves it
+// half written. The caller holds the write lock.
+func (s *JSONStorage) compact() error {
+	reader, err := s.open()
+	if err != nil {
+		return err
+	}
+	defer reader.close()
+
+	tmp, err := os.OpenFile(s.path+".tmp", os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to create records file: %w", err)
+	}
+	locs, size, err := copyRecords(reader, s.slots, tmp)
+	if err = errors.Join(err, tmp.Close()); err != nil {
+		_ = os.Remove(s.path + ".tmp")
+		return fmt.Errorf("failed to write records file: %w", err)
+	}
+	if err := os.Rename(s.path+".tmp", s.path); err != nil {
+		return fmt.Errorf("failed to replace records file: %w", err)
+	}
+	for i := range s.slots {
+		s.slots[i].loc = locs[i]
+	}
+	s.size = size
+	s.entries = len(s.slots)
+	return nil
+}
+
+// copyRecords writes the records of the slots to w, one line each, and
+// returns where they were written and how many bytes
+func copyRecords(reader *logReader, slots []slot, w io.Writer) ([]location, int64, error) {
+	buffered := bufio.NewWriter(w)
+	locs := make([]location, len(slots))
+	var size int64
+	for i, sl := range slots {
+		rec, err := reader.read(sl)
+		if err != nil {
+			return nil, 0, err
+		}
+		line, err := json.Marshal(logEntry{Records: []records.Record{rec}})
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to encode record %s: %w", sl.id, err)
+		}
+		line = append(line, '\n')
+		if _, err := buffered.Write(line); err != nil {
+			return nil, 0, err
+		}
+		locs[i] = location{offset: size, length: len(line)}
+		size += int64(len(line))
+	}
+	return locs, size, buffered.Flush()
+}
+
+// read reads the record of a slot from the log. The caller holds the lock.
+func (s *JSONStorage) read(sl slot) (records.Record, error) {
+	reader, err := s.open()
+	if err != nil {
+		return records.Record{}, err
+	}
+	defer reader.close()
+	return reader.read(sl)
+}
+
+// open opens the log for reading records. The caller holds the lock; once
+// released, the reader keeps reading the log as it was, as the log is only
+// appended to or replaced by a new file.
+func (s *JSONStorage) open() (*logReader, error) {
+	if s.size == 0 {
+		return &logReader{offset: -1}, nil
+	}
+	file, err := os.Open(s.path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open records file: %w", err)
+	}
+	return &logReader{file: file, offset: -1}, nil
+}
+
+// logReader reads records from the log, decoding a line once while the
+// records stored with it are read in turn
+type logReader struct {
+	file   *os.File // nil while the log is empty
+	offset int64    // Offset of the line decoded last
+	recs   []records.Record
+}
+
+// read reads the record of a slot
+func (r *logReader) read(sl slot) (records.Record, error) {
+	if sl.loc.offset != r.offset {
+		if r.file == nil {
+			return records.Record{}, fmt.Errorf("record %s is not in the records file", sl.id)
+		}
+		line := make([]byte, sl.loc.length)
+		if _, err := r.file.ReadAt(line, sl.loc.offset); err != nil {
+			return records.Record{}, fmt.Errorf("failed to read record %s: %w", sl.id, err)
+		}
+		var entry logEntry
+		if err := json.Unmarshal(line, &entry); err != nil {
+			return records.Record{}, fmt.Errorf("failed to decode record %s: %w", sl.id, err)
+		}
+		r.offset, r.recs = sl.loc.offset, entry.Records
+	}
+	if sl.loc.pos >= len(r.recs) || r.recs[sl.loc.pos].ID != sl.id {
+		return records.Record{}, fmt.Errorf("record %s is not at its position in the records file", sl.id)
+	}
+	return r.recs[sl.loc.pos], nil
+}
+
+// close closes the log
+func (r *logReader) close() {
+	if r.file != nil {
+		_ = r.file.Close()
+	}
+}
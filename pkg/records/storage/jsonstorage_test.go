@@ -0,0 +1,136 @@
+package storage_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/kazemisoroush/assistant/pkg/records"
+	"github.com/kazemisoroush/assistant/pkg/records/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJSONStorage_PersistsAcrossReopen(t *testing.T) {
+	// Arrange
+	path := filepath.Join(t.TempDir(), "records.json")
+	ctx := context.Background()
+	created := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	expires := created.AddDate(0, 1, 0)
+	store, err := storage.NewJSONStorage(path)
+	require.NoError(t, err, "NewJSONStorage() error should be nil")
+	require.NoError(t, store.Store(ctx, records.Record{ID: "old", Type: records.RecordTypeReceipt, Content: "old", CreatedAt: created, Tags: []string{"food"}}), "Store() error should be nil")
+	require.NoError(t, store.Store(ctx, records.Record{ID: "new", Type: records.RecordTypeVisa, Content: "new", CreatedAt: created.Add(time.Hour), ExpiresAt: &expires}), "Store() error should be nil")
+	require.NoError(t, store.Update(ctx, records.Record{ID: "old", Type: records.RecordTypeReceipt, Content: "updated", Tags: []string{"food"}}), "Update() error should be nil")
+
+	// Act
+	reopened, err := storage.NewJSONStorage(path)
+	require.NoError(t, err, "NewJSONStorage() error should be nil")
+	listed, listErr := reopened.List(ctx, "")
+	expiring, expiringErr := reopened.ListExpiring(ctx, expires)
+	tagged, taggedErr := reopened.ListByTag(ctx, "food")
+	old, getErr := reopened.Get(ctx, "old")
+
+	// Assert
+	require.NoError(t, listErr, "List() error should be nil")
+	require.NoError(t, expiringErr, "ListExpiring() error should be nil")
+	require.NoError(t, taggedErr, "ListByTag() error should be nil")
+	require.NoError(t, getErr, "Get() error should be nil")
+	require.Len(t, listed, 2, "List() should return the stored records")
+	assert.Equal(t, "new", listed[0].ID, "List() should return the newest record first")
+	require.Len(t, expiring, 1, "ListExpiring() should return the expiring record")
+	require.Len(t, tagged, 1, "ListByTag() should return the tagged record")
+	assert.Equal(t, "old", tagged[0].ID, "ListByTag() should return the tagged record")
+	assert.Equal(t, "updated", old.Content, "Update() should persist the new content")
+	assert.Equal(t, created, old.CreatedAt, "Update() should keep the creation time")
+	assert.Equal(t, []string{"food"}, old.Tags, "Store() should persist the tags")
+}
+
+func TestJSONStorage_Errors(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	store, err := storage.NewJSONStorage(filepath.Join(t.TempDir(), "records.json"))
+	require.NoError(t, err, "NewJSONStorage() error should be nil")
+	require.NoError(t, store.Store(ctx, records.Record{ID: "a", Type: records.RecordTypeOther}), "Store() error should be nil")
+
+	// Act
+	_, getErr := store.Get(ctx, "missing")
+	updateErr := store.Update(ctx, records.Record{ID: "missing"})
+	deleteErr := store.Delete(ctx, "a")
+	deletedErr := store.Delete(ctx, "a")
+
+	// Assert
+	assert.ErrorIs(t, getErr, storage.ErrNotFound, "Get() should fail with ErrNotFound")
+	assert.ErrorIs(t, updateErr, storage.ErrNotFound, "Update() should fail with ErrNotFound")
+	assert.NoError(t, deleteErr, "Delete() error should be nil")
+	assert.ErrorIs(t, deletedErr, storage.ErrNotFound, "Delete() should fail with ErrNotFound once deleted")
+}
+
+func TestJSONStorage_DropsLineCutShort(t *testing.T) {
+	// Arrange
+	path := filepath.Join(t.TempDir(), "records.json")
+	ctx := context.Background()
+	store, err := storage.NewJSONStorage(path)
+	require.NoError(t, err, "NewJSONStorage() error should be nil")
+	require.NoError(t, store.Store(ctx, records.Record{ID: "a"}), "Store() error should be nil")
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0600)
+	require.NoError(t, err, "failed to open records file")
+	_, err = file.WriteString(`{"records":[{"id":"b"`)
+	require.NoError(t, err, "failed to write records file")
+	require.NoError(t, file.Close(), "failed to close records file")
+
+	// Act
+	reopened, err := storage.NewJSONStorage(path)
+	require.NoError(t, err, "NewJSONStorage() should open a log whose last line was cut short")
+	storeErr := reopened.Store(ctx, records.Record{ID: "c"})
+	reopened, reopenErr := storage.NewJSONStorage(path)
+
+	// Assert
+	require.NoError(t, storeErr, "Store() error should be nil")
+	require.NoError(t, reopenErr, "NewJSONStorage() should open the log written after the cut")
+	listed, err := reopened.List(ctx, "")
+	require.NoError(t, err, "List() error should be nil")
+	assert.Len(t, listed, 2, "NewJSONStorage() should drop the record cut short")
+}
+
+func TestJSONStorage_Compacts(t *testing.T) {
+	// Arrange
+	path := filepath.Join(t.TempDir(), "records.json")
+	ctx := context.Background()
+	store, err := storage.NewJSONStorage(path)
+	require.NoError(t, err, "NewJSONStorage() error should be nil")
+	require.NoError(t, store.StoreBatch(ctx, []records.Record{{ID: "kept"}, {ID: "deleted"}}), "StoreBatch() error should be nil")
+	require.NoError(t, store.Delete(ctx, "deleted"), "Delete() error should be nil")
+
+	// Act
+	for i := range 2000 {
+		require.NoError(t, store.Update(ctx, records.Record{ID: "kept", Content: fmt.Sprint(i)}), "Update() error should be nil")
+	}
+
+	// Assert
+	data, err := os.ReadFile(path)
+	require.NoError(t, err, "failed to read records file")
+	assert.Less(t, bytes.Count(data, []byte("\n")), 1100, "the log should be compacted once most of it is stale")
+	reopened, err := storage.NewJSONStorage(path)
+	require.NoError(t, err, "NewJSONStorage() error should be nil")
+	listed, err := reopened.List(ctx, "")
+	require.NoError(t, err, "List() error should be nil")
+	require.Len(t, listed, 1, "the compacted log should hold the records alone")
+	assert.Equal(t, "1999", listed[0].Content, "the compacted log should hold the last change")
+}
+
+func TestNewStorage_LocalJSON(t *testing.T) {
+	// Arrange
+	cfg := storage.Config{Backend: storage.BackendLocalJSON, JSONPath: filepath.Join(t.TempDir(), "records.json")}
+
+	// Act
+	recordStorage, err := storage.NewStorage(cfg)
+
+	// Assert
+	require.NoError(t, err, "NewStorage() error should be nil")
+	assert.NotNil(t, recordStorage, "NewStorage() should return the local-json backend")
+}
@@ -12,8 +12,10 @@ package mocks
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	records "github.com/kazemisoroush/assistant/pkg/records"
+	storage "github.com/kazemisoroush/assistant/pkg/records/storage"
 	gomock "go.uber.org/mock/gomock"
 )
 
@@ -55,6 +57,20 @@ func (mr *MockStorageMockRecorder) Delete(ctx, id any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockStorage)(nil).Delete), ctx, id)
 }
 
+// Each mocks base method.
+func (m *MockStorage) Each(ctx context.Context, recType records.RecordType, fn func(records.Record) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Each", ctx, recType, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Each indicates an expected call of Each.
+func (mr *MockStorageMockRecorder) Each(ctx, recType, fn any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Each", reflect.TypeOf((*MockStorage)(nil).Each), ctx, recType, fn)
+}
+
 // Get mocks base method.
 func (m *MockStorage) Get(ctx context.Context, id string) (records.Record, error) {
 	m.ctrl.T.Helper()
@@ -85,6 +101,51 @@ func (mr *MockStorageMockRecorder) List(ctx, recType any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockStorage)(nil).List), ctx, recType)
 }
 
+// ListByTag mocks base method.
+func (m *MockStorage) ListByTag(ctx context.Context, tag string) ([]records.Record, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByTag", ctx, tag)
+	ret0, _ := ret[0].([]records.Record)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByTag indicates an expected call of ListByTag.
+func (mr *MockStorageMockRecorder) ListByTag(ctx, tag any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByTag", reflect.TypeOf((*MockStorage)(nil).ListByTag), ctx, tag)
+}
+
+// ListExpiring mocks base method.
+func (m *MockStorage) ListExpiring(ctx context.Context, until time.Time) ([]records.Record, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListExpiring", ctx, until)
+	ret0, _ := ret[0].([]records.Record)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListExpiring indicates an expected call of ListExpiring.
+func (mr *MockStorageMockRecorder) ListExpiring(ctx, until any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListExpiring", reflect.TypeOf((*MockStorage)(nil).ListExpiring), ctx, until)
+}
+
+// Search mocks base method.
+func (m *MockStorage) Search(ctx context.Context, query string, filter storage.SearchFilter, limit int) ([]records.SearchResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Search", ctx, query, filter, limit)
+	ret0, _ := ret[0].([]records.SearchResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Search indicates an expected call of Search.
+func (mr *MockStorageMockRecorder) Search(ctx, query, filter, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Search", reflect.TypeOf((*MockStorage)(nil).Search), ctx, query, filter, limit)
+}
+
 // Store mocks base method.
 func (m *MockStorage) Store(ctx context.Context, rec records.Record) error {
 	m.ctrl.T.Helper()
@@ -99,6 +160,20 @@ func (mr *MockStorageMockRecorder) Store(ctx, rec any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Store", reflect.TypeOf((*MockStorage)(nil).Store), ctx, rec)
 }
 
+// StoreBatch mocks base method.
+func (m *MockStorage) StoreBatch(ctx context.Context, recs []records.Record) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StoreBatch", ctx, recs)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// StoreBatch indicates an expected call of StoreBatch.
+func (mr *MockStorageMockRecorder) StoreBatch(ctx, recs any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StoreBatch", reflect.TypeOf((*MockStorage)(nil).StoreBatch), ctx, recs)
+}
+
 // Update mocks base method.
 func (m *MockStorage) Update(ctx context.Context, rec records.Record) error {
 	m.ctrl.T.Helper()
@@ -0,0 +1,247 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kazemisoroush/assistant/pkg/records"
+	"github.com/kazemisoroush/assistant/pkg/requestid"
+)
+
+// RemoteRecordsPath is the route of the storage API a remote storage talks to.
+// A record is addressed as RemoteRecordsPath/<id>; batches are put to RemoteRecordsPath.
+const RemoteRecordsPath = "/api/v1/storage/records"
+
+// RemoteStorage is a Storage kept by an assistant server, reached over its
+// storage API. Records are sent as they are, so remote storage should be
+// wrapped in an EncryptingStorage when the server is not trusted.
+type RemoteStorage struct {
+	client *http.Client
+	url    string
+	token  string
+}
+
+// NewRemoteStorage creates a storage kept by the server at the base URL,
+// authenticating with its token
+func NewRemoteStorage(client *http.Client, baseURL, token string) Storage {
+	return &RemoteStorage{
+		client: client,
+		url:    strings.TrimSuffix(baseURL, "/") + RemoteRecordsPath,
+		token:  token,
+	}
+}
+
+// Store implements Storage
+func (s *RemoteStorage) Store(ctx context.Context, rec records.Record) error {
+	if err := s.send(ctx, http.MethodPost, s.url, rec); err != nil {
+		return fmt.Errorf("failed to store record %s: %w", rec.ID, err)
+	}
+	return nil
+}
+
+// StoreBatch implements Storage. The server stores the batch in one write.
+func (s *RemoteStorage) StoreBatch(ctx context.Context, recs []records.Record) error {
+	if err := s.send(ctx, http.MethodPut, s.url, recs); err != nil {
+		return fmt.Errorf("failed to store batch of %d records: %w", len(recs), err)
+	}
+	return nil
+}
+
+// Update implements Storage
+func (s *RemoteStorage) Update(ctx context.Context, rec records.Record) error {
+	if err := s.send(ctx, http.MethodPut, s.recordURL(rec.ID), rec); err != nil {
+		return fmt.Errorf("failed to update record %s: %w", rec.ID, err)
+	}
+	return nil
+}
+
+// Delete implements Storage
+func (s *RemoteStorage) Delete(ctx context.Context, id string) error {
+	if err := s.send(ctx, http.MethodDelete, s.recordURL(id), nil); err != nil {
+		return fmt.Errorf("failed to delete record %s: %w", id, err)
+	}
+	return nil
+}
+
+// Get implements Storage
+func (s *RemoteStorage) Get(ctx context.Context, id string) (records.Record, error) {
+	var rec records.Record
+	err := s.stream(ctx, s.recordURL(id), func(dec *json.Decoder) error {
+		return dec.Decode(&rec)
+	})
+	if err != nil {
+		return records.Record{}, fmt.Errorf("failed to get record %s: %w", id, err)
+	}
+	return rec, nil
+}
+
+// List implements Storage
+func (s *RemoteStorage) List(ctx context.Context, recType records.RecordType) ([]records.Record, error) {
+	var recs []records.Record
+	err := s.Each(ctx, recType, func(rec records.Record) error {
+		recs = append(recs, rec)
+		return nil
+	})
+	return recs, err
+}
+
+// ListExpiring implements Storage
+func (s *RemoteStorage) ListExpiring(ctx context.Context, until time.Time) ([]records.Record, error) {
+	var recs []records.Record
+	err := s.each(ctx, url.Values{"expiring_until": {until.UTC().Format(time.RFC3339)}}, func(rec records.Record) error {
+		recs = append(recs, rec)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list expiring records: %w", err)
+	}
+	return recs, nil
+}
+
+// ListByTag implements Storage
+func (s *RemoteStorage) ListByTag(ctx context.Context, tag string) ([]records.Record, error) {
+	var recs []records.Record
+	err := s.each(ctx, url.Values{"tag": {tag}}, func(rec records.Record) error {
+		recs = append(recs, rec)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list records tagged %s: %w", tag, err)
+	}
+	return recs, nil
+}
+
+// Search implements Storage. The server searches its storage and streams the
+// results.
+func (s *RemoteStorage) Search(ctx context.Context, query string, filter SearchFilter, limit int) ([]records.SearchResult, error) {
+	params := url.Values{"q": {query}}
+	if filter.Type != "" {
+		params.Set("type", string(filter.Type))
+	}
+	if filter.Tag != "" {
+		params.Set("tag", filter.Tag)
+	}
+	if limit > 0 {
+		params.Set("limit", strconv.Itoa(limit))
+	}
+
+	var results []records.SearchResult
+	err := s.stream(ctx, s.url+"?"+params.Encode(), func(dec *json.Decoder) error {
+		for dec.More() {
+			var result records.SearchResult
+			if err := dec.Decode(&result); err != nil {
+				return err
+			}
+			results = append(results, result)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to search records: %w", err)
+	}
+	return results, nil
+}
+
+// Each implements Storage. The server streams the records one JSON document
+// at a time, so neither side holds them all.
+func (s *RemoteStorage) Each(ctx context.Context, recType records.RecordType, fn func(records.Record) error) error {
+	query := url.Values{}
+	if recType != "" {
+		query.Set("type", string(recType))
+	}
+	if err := s.each(ctx, query, fn); err != nil {
+		return fmt.Errorf("failed to list records: %w", err)
+	}
+	return nil
+}
+
+// each calls fn with every record the records query streams
+func (s *RemoteStorage) each(ctx context.Context, query url.Values, fn func(records.Record) error) error {
+	target := s.url
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+	return s.stream(ctx, target, func(dec *json.Decoder) error {
+		for dec.More() {
+			var rec records.Record
+			if err := dec.Decode(&rec); err != nil {
+				return err
+			}
+			if err := fn(rec); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// recordURL returns the URL of a record
+func (s *RemoteStorage) recordURL(id string) string {
+	return s.url + "/" + url.PathEscape(id)
+}
+
+// send sends the record, if any, with the method
+func (s *RemoteStorage) send(ctx context.Context, method, target string, rec any) error {
+	var body io.Reader
+	if rec != nil {
+		data, err := json.Marshal(rec)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, target, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := s.do(req)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+// stream gets the target and reads its JSON body with fn
+func (s *RemoteStorage) stream(ctx context.Context, target string, fn func(*json.Decoder) error) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := s.do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	return fn(json.NewDecoder(resp.Body))
+}
+
+// do sends the authenticated request, turning error statuses into errors
+func (s *RemoteStorage) do(req *http.Request) (*http.Response, error) {
+	req.Header.Set("Authorization", "Bearer "+s.token)
+	if id := requestid.FromContext(req.Context()); id != "" {
+		req.Header.Set(requestid.Header, id)
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 300 {
+		return resp, nil
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrNotFound
+	}
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	return nil, fmt.Errorf("server returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+}
@@ -0,0 +1,98 @@
+package storage_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kazemisoroush/assistant/pkg/records"
+	"github.com/kazemisoroush/assistant/pkg/records/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRemoteStorage_Each(t *testing.T) {
+	// Arrange
+	var gotAuth, gotQuery string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuth = r.Header.Get("Authorization")
+		gotQuery = r.URL.RawQuery
+		enc := json.NewEncoder(w)
+		_ = enc.Encode(records.Record{ID: "a", Type: records.RecordTypeReceipt})
+		_ = enc.Encode(records.Record{ID: "b", Type: records.RecordTypeReceipt})
+	}))
+	defer server.Close()
+	s := storage.NewRemoteStorage(server.Client(), server.URL, "secret")
+
+	// Act
+	var ids []string
+	err := s.Each(context.Background(), records.RecordTypeReceipt, func(rec records.Record) error {
+		ids = append(ids, rec.ID)
+		return nil
+	})
+
+	// Assert
+	require.NoError(t, err, "Each() error should be nil")
+	assert.Equal(t, []string{"a", "b"}, ids, "Each() should read every streamed record")
+	assert.Equal(t, "Bearer secret", gotAuth, "Each() should authenticate with the token")
+	assert.Equal(t, "type=receipt", gotQuery, "Each() should filter by type")
+}
+
+func TestRemoteStorage_ListByTag(t *testing.T) {
+	// Arrange
+	var gotQuery string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotQuery = r.URL.RawQuery
+		_ = json.NewEncoder(w).Encode(records.Record{ID: "a", Tags: []string{"food"}})
+	}))
+	defer server.Close()
+	s := storage.NewRemoteStorage(server.Client(), server.URL, "secret")
+
+	// Act
+	recs, err := s.ListByTag(context.Background(), "food")
+
+	// Assert
+	require.NoError(t, err, "ListByTag() error should be nil")
+	require.Len(t, recs, 1, "ListByTag() should read the streamed records")
+	assert.Equal(t, "tag=food", gotQuery, "ListByTag() should filter by tag on the server")
+}
+
+func TestRemoteStorage_Update(t *testing.T) {
+	// Arrange
+	var gotMethod, gotPath string
+	var got records.Record
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotMethod, gotPath = r.Method, r.URL.EscapedPath()
+		_ = json.NewDecoder(r.Body).Decode(&got)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+	s := storage.NewRemoteStorage(server.Client(), server.URL+"/", "secret")
+
+	// Act
+	err := s.Update(context.Background(), records.Record{ID: "scan 1", Content: "sealed"})
+
+	// Assert
+	require.NoError(t, err, "Update() error should be nil")
+	assert.Equal(t, http.MethodPut, gotMethod, "Update() should put the record")
+	assert.Equal(t, storage.RemoteRecordsPath+"/scan%201", gotPath, "Update() should address the record by its escaped ID")
+	assert.Equal(t, "sealed", got.Content, "Update() should send the record")
+}
+
+func TestRemoteStorage_Get_NotFound(t *testing.T) {
+	// Arrange
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		http.Error(w, "record not found", http.StatusNotFound)
+	}))
+	defer server.Close()
+	s := storage.NewRemoteStorage(server.Client(), server.URL, "secret")
+
+	// Act
+	_, err := s.Get(context.Background(), "missing")
+
+	// Assert
+	assert.True(t, errors.Is(err, storage.ErrNotFound), "Get() should report missing records as not found")
+}
@@ -0,0 +1,180 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"slices"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/kazemisoroush/assistant/pkg/records"
+)
+
+// Search settings shared by the storages
+const (
+	// minTermLength is the length of the shortest query word searched for
+	minTermLength = 3
+
+	// snippetWords is the number of words a snippet spans
+	snippetWords = 12
+
+	// Markers around the matched words of a snippet
+	snippetOpen  = "["
+	snippetClose = "]"
+	snippetGap   = "…"
+)
+
+// SearchFilter narrows a search. Zero fields match every record.
+type SearchFilter struct {
+	Type records.RecordType
+	Tag  string
+}
+
+// matches reports whether the record passes the filter
+func (f SearchFilter) matches(rec records.Record) bool {
+	if f.Type != "" && rec.Type != f.Type {
+		return false
+	}
+	return f.Tag == "" || slices.Contains(rec.Tags, f.Tag)
+}
+
+// searchTerm is a word of a search query. A prefix term matches the words
+// starting with it.
+type searchTerm struct {
+	word   string
+	prefix bool
+}
+
+// parseQuery returns the distinct lowercase words of the query, ignoring
+// words too short to be meaningful. A word ending in * is a prefix term.
+func parseQuery(query string) []searchTerm {
+	fields := strings.FieldsFunc(strings.ToLower(query), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '*'
+	})
+
+	seen := make(map[searchTerm]bool, len(fields))
+	terms := make([]searchTerm, 0, len(fields))
+	for _, field := range fields {
+		term := searchTerm{word: strings.ReplaceAll(field, "*", ""), prefix: strings.HasSuffix(field, "*")}
+		if len(term.word) < minTermLength || seen[term] {
+			continue
+		}
+		seen[term] = true
+		terms = append(terms, term)
+	}
+	return terms
+}
+
+// ftsQuery returns the FTS5 query matching any of the terms. Terms only hold
+// letters and digits, so quoting them is enough to escape them.
+func ftsQuery(terms []searchTerm) string {
+	parts := make([]string, len(terms))
+	for i, term := range terms {
+		parts[i] = `"` + term.word + `"`
+		if term.prefix {
+			parts[i] += "*"
+		}
+	}
+	return strings.Join(parts, " OR ")
+}
+
+// scanSearch searches the records each streams without an index, scoring
+// them by the share of terms found in their searchable text. Only matching
+// records are kept while the records are scanned.
+func scanSearch(ctx context.Context, each func(context.Context, records.RecordType, func(records.Record) error) error, query string, filter SearchFilter, limit int) ([]records.SearchResult, error) {
+	terms := parseQuery(query)
+	if len(terms) == 0 {
+		return nil, nil
+	}
+
+	var results []records.SearchResult
+	err := each(ctx, filter.Type, func(rec records.Record) error {
+		if !filter.matches(rec) {
+			return nil
+		}
+		text := strings.ToLower(searchText(rec))
+		matched := 0
+		for _, term := range terms {
+			if strings.Contains(text, term.word) {
+				matched++
+			}
+		}
+		if matched > 0 {
+			results = append(results, records.SearchResult{
+				Record:  rec,
+				Score:   float64(matched) / float64(len(terms)),
+				Snippet: snippet(rec.IndexText(), terms),
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(results, func(i, j int) bool { return results[i].Score > results[j].Score })
+	if limit > 0 && len(results) > limit {
+		results = results[:limit]
+	}
+	return results, nil
+}
+
+// searchText returns the text a record is searched by: its index text, file
+// name and tags
+func searchText(rec records.Record) string {
+	return rec.IndexText() + "\n" + rec.FileName + "\n" + strings.Join(rec.Tags, " ")
+}
+
+// snippet returns the words of the text around the first matched term, with
+// the matched words marked, or "" if no term is in the text
+func snippet(text string, terms []searchTerm) string {
+	words := strings.Fields(text)
+	first := -1
+	marked := make([]string, len(words))
+	for i, word := range words {
+		marked[i] = word
+		lower := strings.ToLower(word)
+		if slices.ContainsFunc(terms, func(term searchTerm) bool { return strings.Contains(lower, term.word) }) {
+			marked[i] = snippetOpen + word + snippetClose
+			if first < 0 {
+				first = i
+			}
+		}
+	}
+	if first < 0 {
+		return ""
+	}
+
+	start := max(first-snippetWords/2, 0)
+	end := min(start+snippetWords, len(words))
+	out := strings.Join(marked[start:end], " ")
+	if start > 0 {
+		out = snippetGap + out
+	}
+	if end < len(words) {
+		out += snippetGap
+	}
+	return out
+}
+
+// relevance maps a rank where higher is better and 0 is the worst, such as
+// a negated BM25 rank, onto the 0-1 score of records.SearchResult
+func relevance(rank float64) float64 {
+	if rank <= 0 {
+		return 0
+	}
+	return rank / (1 + rank)
+}
+
+// extraScanner scans the columns selected after the record columns into
+// extra, leaving the record columns to the record scanner
+type extraScanner struct {
+	rows  *sql.Rows
+	extra []any
+}
+
+// Scan implements the scanner the record scanners read with
+func (e extraScanner) Scan(dest ...any) error {
+	return e.rows.Scan(append(dest, e.extra...)...)
+}
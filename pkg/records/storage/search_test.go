@@ -0,0 +1,158 @@
+package storage_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kazemisoroush/assistant/pkg/records"
+	"github.com/kazemisoroush/assistant/pkg/records/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// storeSearchable stores records to search for
+func storeSearchable(t *testing.T, s storage.Storage) {
+	t.Helper()
+	created := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	for _, rec := range []records.Record{
+		{ID: "passport", Type: records.RecordTypeID, Title: "Passport", Content: "Issued by the passport office, valid ten years", Tags: []string{"travel"}},
+		{ID: "visa", Type: records.RecordTypeVisa, Content: "Work visa, passport number X1234567 attached", Tags: []string{"travel", "work"}},
+		{ID: "receipt", Type: records.RecordTypeReceipt, Content: "Groceries at the corner shop", FileName: "passenger_fare.pdf"},
+	} {
+		rec.CreatedAt, rec.UpdatedAt = created, created
+		require.NoError(t, s.Store(context.Background(), rec), "Store() error should be nil")
+	}
+}
+
+// ids returns the IDs of the records of the results
+func ids(results []records.SearchResult) []string {
+	out := make([]string, len(results))
+	for i, result := range results {
+		out[i] = result.Record.ID
+	}
+	return out
+}
+
+func TestSQLiteStorage_Search(t *testing.T) {
+	// Arrange
+	s, err := storage.NewSQLiteStorage(openDB(t, filepath.Join(t.TempDir(), "records.db")))
+	require.NoError(t, err, "NewSQLiteStorage() error should be nil")
+	storeSearchable(t, s)
+	ctx := context.Background()
+
+	// Act
+	matched, matchedErr := s.Search(ctx, "passport", storage.SearchFilter{}, 0)
+	prefixed, prefixedErr := s.Search(ctx, "passe*", storage.SearchFilter{}, 0)
+	filtered, filteredErr := s.Search(ctx, "passport", storage.SearchFilter{Type: records.RecordTypeVisa, Tag: "work"}, 0)
+	limited, limitedErr := s.Search(ctx, "passport", storage.SearchFilter{}, 1)
+	short, shortErr := s.Search(ctx, "id", storage.SearchFilter{}, 0)
+
+	// Assert
+	require.NoError(t, matchedErr, "Search() error should be nil")
+	require.NoError(t, prefixedErr, "Search() error should be nil")
+	require.NoError(t, filteredErr, "Search() error should be nil")
+	require.NoError(t, limitedErr, "Search() error should be nil")
+	require.NoError(t, shortErr, "Search() error should be nil")
+	require.Equal(t, []string{"passport", "visa"}, ids(matched), "Search() should rank the titled record first")
+	assert.Greater(t, matched[0].Score, 0.0, "Search() should score the results")
+	assert.LessOrEqual(t, matched[0].Score, 1.0, "Search() should score the results from 0 to 1")
+	assert.Contains(t, strings.ToLower(matched[1].Snippet), "[passport]", "Search() should mark the matched words in the snippet")
+	assert.Equal(t, []string{"receipt"}, ids(prefixed), "Search() should match words starting with a prefix term")
+	assert.Equal(t, []string{"visa"}, ids(filtered), "Search() should apply the filter")
+	assert.Len(t, limited, 1, "Search() should return at most limit results")
+	assert.Empty(t, short, "Search() should ignore words too short to search for")
+}
+
+func TestSQLiteStorage_Search_FollowsWrites(t *testing.T) {
+	// Arrange
+	path := filepath.Join(t.TempDir(), "records.db")
+	s, err := storage.NewSQLiteStorage(openDB(t, path))
+	require.NoError(t, err, "NewSQLiteStorage() error should be nil")
+	storeSearchable(t, s)
+	ctx := context.Background()
+	visa, err := s.Get(ctx, "visa")
+	require.NoError(t, err, "Get() error should be nil")
+	visa.Content = "Work visa, renewed"
+	require.NoError(t, s.Update(ctx, visa), "Update() error should be nil")
+	require.NoError(t, s.Delete(ctx, "passport"), "Delete() error should be nil")
+
+	// Act
+	reopened, err := storage.NewSQLiteStorage(openDB(t, path))
+	require.NoError(t, err, "NewSQLiteStorage() error should be nil")
+	gone, goneErr := reopened.Search(ctx, "passport", storage.SearchFilter{}, 0)
+	renewed, renewedErr := reopened.Search(ctx, "renewed", storage.SearchFilter{}, 0)
+
+	// Assert
+	require.NoError(t, goneErr, "Search() error should be nil")
+	require.NoError(t, renewedErr, "Search() error should be nil")
+	assert.Empty(t, gone, "Search() should drop updated and deleted records")
+	assert.Equal(t, []string{"visa"}, ids(renewed), "Search() should find the updated content")
+}
+
+func TestSQLiteStorage_Search_CompressedContent(t *testing.T) {
+	// Arrange
+	path := filepath.Join(t.TempDir(), "records.db")
+	s, err := storage.NewCompressedSQLiteStorage(openDB(t, path))
+	require.NoError(t, err, "NewCompressedSQLiteStorage() error should be nil")
+	ctx := context.Background()
+	filler := strings.Repeat("Results within normal range. ", 40)
+	require.NoError(t, s.StoreBatch(ctx, []records.Record{
+		{ID: "blood", Type: records.RecordTypeHealthVisit, Content: "Blood test. " + filler},
+		{ID: "xray", Type: records.RecordTypeHealthVisit, Content: "Chest x-ray. " + filler},
+	}), "StoreBatch() error should be nil")
+	require.NoError(t, s.Update(ctx, records.Record{ID: "xray", Type: records.RecordTypeHealthVisit, Content: "Cholesterol panel. " + filler}), "Update() error should be nil")
+
+	// Act
+	blood, bloodErr := s.Search(ctx, "blood", storage.SearchFilter{}, 10)
+	cholesterol, cholesterolErr := s.Search(ctx, "cholesterol", storage.SearchFilter{}, 10)
+
+	// Assert
+	require.NoError(t, bloodErr, "Search() error should be nil")
+	require.NoError(t, cholesterolErr, "Search() error should be nil")
+	require.Len(t, blood, 1, "Search() should find the text of compressed content")
+	assert.Equal(t, "Blood test. "+filler, blood[0].Record.Content, "Search() should decompress the content of the results")
+	assert.Contains(t, blood[0].Snippet, "Blood", "Search() should quote the text of compressed content")
+	assert.Equal(t, []string{"xray"}, ids(cholesterol), "Search() should find the text of updated compressed content")
+}
+
+func TestJSONStorage_Search(t *testing.T) {
+	// Arrange
+	s, err := storage.NewJSONStorage(filepath.Join(t.TempDir(), "records.json"))
+	require.NoError(t, err, "NewJSONStorage() error should be nil")
+	storeSearchable(t, s)
+
+	// Act
+	results, err := s.Search(context.Background(), "passport travel", storage.SearchFilter{Tag: "travel"}, 0)
+
+	// Assert
+	require.NoError(t, err, "Search() error should be nil")
+	require.Equal(t, []string{"passport", "visa"}, ids(results), "Search() should return the tagged matches")
+	assert.InDelta(t, 1.0, results[0].Score, 0.001, "Search() should score by the share of matched words")
+	assert.Equal(t, "[Passport]", strings.Fields(results[0].Snippet)[0], "Search() should mark the matched words in the snippet")
+}
+
+func TestRemoteStorage_Search(t *testing.T) {
+	// Arrange
+	var gotQuery string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotQuery = r.URL.RawQuery
+		_ = json.NewEncoder(w).Encode(records.SearchResult{Record: records.Record{ID: "visa"}, Score: 0.5, Snippet: "[visa]"})
+	}))
+	defer server.Close()
+	s := storage.NewRemoteStorage(server.Client(), server.URL, "secret")
+
+	// Act
+	results, err := s.Search(context.Background(), "visa", storage.SearchFilter{Type: records.RecordTypeVisa}, 5)
+
+	// Assert
+	require.NoError(t, err, "Search() error should be nil")
+	require.Len(t, results, 1, "Search() should read the streamed results")
+	assert.Equal(t, "[visa]", results[0].Snippet, "Search() should read the snippets")
+	assert.Equal(t, "limit=5&q=visa&type=visa", gotQuery, "Search() should send the query and filter")
+}
@@ -5,51 +5,47 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"os"
-	"path/filepath"
-
-	// Import sqlite3 driver for database/sql
-	_ "github.com/mattn/go-sqlite3"
+	"log/slog"
+	"time"
 
 	"github.com/kazemisoroush/assistant/pkg/records"
+	"github.com/kazemisoroush/assistant/pkg/sqlite"
 )
 
-// SQLiteStorage implements the storage.SQLiteStorage interface using SQLite
+// SQLiteStorage implements Storage using SQLite.
+// Reads share the pool of reader connections of the database while writes go
+// through its single writer connection.
+// Records are searched with an FTS5 index when SQLite was built with FTS5,
+// i.e. with the sqlite_fts5 build tag, and by scanning them otherwise.
+// Compressed content is kept in the content_deflate column while the index
+// holds its text.
 type SQLiteStorage struct {
-	db *sql.DB
+	db       *sql.DB    // Reader pool
+	writer   *sqlite.DB // Database, writing through its single writer connection
+	fts      bool       // Whether the records_fts index is maintained
+	compress bool       // Whether long content is stored compressed
 }
 
-// NewSQLiteStorage creates a new SQLite storage instance with the given database path.
-func NewSQLiteStorage(dbPath string) (*SQLiteStorage, error) {
-	// Ensure the directory exists
-	dir := filepath.Dir(dbPath)
-	if err := os.MkdirAll(dir, 0755); err != nil {
-		return nil, fmt.Errorf("failed to create database directory: %w", err)
-	}
-
-	// Open database connection
-	db, err := sql.Open("sqlite3", dbPath)
-	if err != nil {
-		return nil, fmt.Errorf("failed to open database: %w", err)
-	}
-
-	// Enable foreign keys and WAL mode for better concurrency
-	if _, err := db.Exec("PRAGMA foreign_keys = ON"); err != nil {
-		_ = db.Close()
-		return nil, fmt.Errorf("failed to enable foreign keys: %w", err)
-	}
+// NewSQLiteStorage creates a new SQLite storage instance in the given database.
+func NewSQLiteStorage(db *sqlite.DB) (*SQLiteStorage, error) {
+	return newSQLiteStorage(db, false)
+}
 
-	if _, err := db.Exec("PRAGMA journal_mode = WAL"); err != nil {
-		_ = db.Close()
-		return nil, fmt.Errorf("failed to enable WAL mode: %w", err)
-	}
+// NewCompressedSQLiteStorage creates a SQLite storage that stores long
+// record content compressed and decompresses it on read. Records stored
+// uncompressed before are read back unchanged.
+func NewCompressedSQLiteStorage(db *sqlite.DB) (*SQLiteStorage, error) {
+	return newSQLiteStorage(db, true)
+}
 
-	s := &SQLiteStorage{db: db}
+// newSQLiteStorage sets up the storage in the database, compressing content when compress is set
+func newSQLiteStorage(db *sqlite.DB, compress bool) (*SQLiteStorage, error) {
+	s := &SQLiteStorage{db: db.Readers(), writer: db, compress: compress}
 
 	// Initialize schema
 	if err := s.initSchema(); err != nil {
-		_ = db.Close()
 		return nil, fmt.Errorf("failed to initialize schema: %w", err)
 	}
 
@@ -57,7 +53,7 @@ func NewSQLiteStorage(dbPath string) (*SQLiteStorage, error) {
 }
 
 // initSchema creates the necessary tables
-func (s SQLiteStorage) initSchema() error {
+func (s *SQLiteStorage) initSchema() error {
 	schema := `
     CREATE TABLE IF NOT EXISTS records (
         id TEXT PRIMARY KEY,
@@ -72,121 +68,308 @@ func (s SQLiteStorage) initSchema() error {
     CREATE INDEX IF NOT EXISTS idx_records_created_at ON records(created_at);
     `
 
-	_, err := s.db.Exec(schema)
-	return err
+	if _, err := s.writer.Exec(schema); err != nil {
+		return err
+	}
+
+	// Columns added after the initial schema
+	if err := s.addColumnIfMissing("records", "expires_at", "DATETIME"); err != nil {
+		return err
+	}
+	if err := s.addColumnIfMissing("records", "tags", "TEXT NOT NULL DEFAULT '[]'"); err != nil {
+		return err
+	}
+	for _, column := range []string{"title", "file_path", "file_name"} {
+		if err := s.addColumnIfMissing("records", column, "TEXT NOT NULL DEFAULT ''"); err != nil {
+			return err
+		}
+	}
+	if err := s.addColumnIfMissing("records", "content_deflate", "BLOB"); err != nil {
+		return err
+	}
+	if _, err := s.writer.Exec(`CREATE INDEX IF NOT EXISTS idx_records_expires_at ON records(expires_at)`); err != nil {
+		return err
+	}
+	return s.initSearchIndex()
 }
 
-// Store saves a record
-func (s SQLiteStorage) Store(ctx context.Context, rec records.Record) error {
-	metadata, err := json.Marshal(rec.Metadata)
+// addColumnIfMissing adds a column to databases created before it existed
+func (s *SQLiteStorage) addColumnIfMissing(table, column, definition string) error {
+	rows, err := s.writer.Query(fmt.Sprintf("PRAGMA table_info(%s)", table))
 	if err != nil {
-		return fmt.Errorf("failed to marshal metadata: %w", err)
+		return fmt.Errorf("failed to read %s schema: %w", table, err)
 	}
+	defer func() { _ = rows.Close() }()
 
+	for rows.Next() {
+		var (
+			cid          int
+			name, ctype  string
+			notNull, pk  int
+			defaultValue sql.NullString
+		)
+		if err := rows.Scan(&cid, &name, &ctype, &notNull, &defaultValue, &pk); err != nil {
+			return fmt.Errorf("failed to scan %s schema: %w", table, err)
+		}
+		if name == column {
+			return nil
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to read %s schema: %w", table, err)
+	}
+
+	if _, err := s.writer.Exec(fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", table, column, definition)); err != nil {
+		return fmt.Errorf("failed to add %s.%s: %w", table, column, err)
+	}
+	return nil
+}
+
+// Store saves a record, replacing the record with the same ID as StoreBatch does
+func (s *SQLiteStorage) Store(ctx context.Context, rec records.Record) error {
+	if err := s.StoreBatch(ctx, []records.Record{rec}); err != nil {
+		return fmt.Errorf("failed to store record: %w", err)
+	}
+	return nil
+}
+
+// StoreBatch implements Storage. The records are written in one transaction.
+func (s *SQLiteStorage) StoreBatch(ctx context.Context, recs []records.Record) error {
 	query := `
-        INSERT INTO records (id, type, content, metadata, created_at, updated_at)
-        VALUES (?, ?, ?, ?, ?, ?)
+        INSERT INTO records (` + recordColumns + `)
+        VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+        ON CONFLICT (id) DO UPDATE SET
+            type = excluded.type, title = excluded.title, content = excluded.content,
+            content_deflate = excluded.content_deflate, file_path = excluded.file_path, file_name = excluded.file_name, metadata = excluded.metadata,
+            tags = excluded.tags, created_at = excluded.created_at, updated_at = excluded.updated_at,
+            expires_at = excluded.expires_at
     `
 
-	_, err = s.db.ExecContext(ctx, query,
+	err := s.transact(ctx, func(tx *sql.Tx) error {
+		stmt, err := tx.PrepareContext(ctx, query)
+		if err != nil {
+			return err
+		}
+		defer func() { _ = stmt.Close() }()
+
+		for _, rec := range recs {
+			args, err := s.recordArgs(rec)
+			if err != nil {
+				return err
+			}
+			if _, err := stmt.ExecContext(ctx, args...); err != nil {
+				return fmt.Errorf("record %s: %w", rec.ID, err)
+			}
+			if err := s.indexText(ctx, tx, rec); err != nil {
+				return fmt.Errorf("record %s: %w", rec.ID, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to store batch of %d records: %w", len(recs), err)
+	}
+	return nil
+}
+
+// recordArgs returns the values of a record's recordColumns, in order
+func (s *SQLiteStorage) recordArgs(rec records.Record) ([]interface{}, error) {
+	metadata, err := json.Marshal(rec.Metadata)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal metadata: %w", err)
+	}
+	tags, err := marshalTags(rec.Tags)
+	if err != nil {
+		return nil, err
+	}
+	content, deflated, err := s.contentValues(rec)
+	if err != nil {
+		return nil, err
+	}
+	return []interface{}{
 		rec.ID,
 		rec.Type,
-		rec.Content,
+		rec.Title,
+		content,
+		deflated,
+		rec.FilePath,
+		rec.FileName,
 		string(metadata),
+		tags,
 		rec.CreatedAt,
 		rec.UpdatedAt,
-	)
+		utc(rec.ExpiresAt),
+	}, nil
+}
+
+// recordColumns are the columns read by scanRecord and written by recordArgs, in order
+const recordColumns = "id, type, title, content, content_deflate, file_path, file_name, metadata, tags, created_at, updated_at, expires_at"
+
+// qualifiedRecordColumns are recordColumns of the records table aliased as r
+const qualifiedRecordColumns = "r.id, r.type, r.title, r.content, r.content_deflate, r.file_path, r.file_name, r.metadata, r.tags, r.created_at, r.updated_at, r.expires_at"
+
+// contentValues returns the values of the content and content_deflate
+// columns of a record. When compressing, content long enough to shrink is
+// stored compressed in content_deflate, leaving content empty.
+func (s *SQLiteStorage) contentValues(rec records.Record) (string, []byte, error) {
+	if !s.compress || len(rec.Content) < compressMinSize {
+		return rec.Content, nil, nil
+	}
+	deflated, err := deflate(rec.Content)
 	if err != nil {
-		return fmt.Errorf("failed to store record: %w", err)
+		return "", nil, fmt.Errorf("failed to compress content of record %s: %w", rec.ID, err)
 	}
-
-	return nil
+	if len(deflated) >= len(rec.Content) {
+		return rec.Content, nil, nil
+	}
+	return "", deflated, nil
 }
 
 // Get retrieves a record by ID
-func (s SQLiteStorage) Get(ctx context.Context, id string) (records.Record, error) {
-	query := `
-        SELECT id, type, content, metadata, created_at, updated_at
-        FROM records
-        WHERE id = ?
-    `
-
-	var rec records.Record
-	var metadataJSON string
+func (s *SQLiteStorage) Get(ctx context.Context, id string) (records.Record, error) {
+	query := `SELECT ` + recordColumns + ` FROM records WHERE id = ?`
 
-	err := s.db.QueryRowContext(ctx, query, id).Scan(
-		&rec.ID,
-		&rec.Type,
-		&rec.Content,
-		&metadataJSON,
-		&rec.CreatedAt,
-		&rec.UpdatedAt,
-	)
+	rec, err := scanRecord(s.db.QueryRowContext(ctx, query, id))
 	if err == sql.ErrNoRows {
-		return records.Record{}, fmt.Errorf("record not found: %s", id)
+		return records.Record{}, fmt.Errorf("%w: %s", ErrNotFound, id)
 	}
 	if err != nil {
 		return records.Record{}, fmt.Errorf("failed to get record: %w", err)
 	}
 
-	if err := json.Unmarshal([]byte(metadataJSON), &rec.Metadata); err != nil {
-		return records.Record{}, fmt.Errorf("failed to unmarshal metadata: %w", err)
-	}
-
 	return rec, nil
 }
 
 // List returns all records with optional type filter
-func (s SQLiteStorage) List(ctx context.Context, recType records.RecordType) ([]records.Record, error) {
-	var query string
-	var args []interface{}
-
+func (s *SQLiteStorage) List(ctx context.Context, recType records.RecordType) ([]records.Record, error) {
 	if recType != "" {
-		query = `
-            SELECT id, type, content, metadata, created_at, updated_at
-            FROM records
-            WHERE type = ?
-            ORDER BY created_at DESC
-        `
-		args = append(args, recType)
-	} else {
-		query = `
-            SELECT id, type, content, metadata, created_at, updated_at
-            FROM records
-            ORDER BY created_at DESC
-        `
+		return s.query(ctx, `SELECT `+recordColumns+` FROM records WHERE type = ? ORDER BY created_at DESC`, recType)
 	}
+	return s.query(ctx, `SELECT `+recordColumns+` FROM records ORDER BY created_at DESC`)
+}
+
+// eachPageSize is how many records Each reads at a time
+const eachPageSize = 500
+
+// Each implements Storage. Records are read a page at a time by rowid, so no
+// query is left open while fn runs and fn may write to the storage.
+func (s *SQLiteStorage) Each(ctx context.Context, recType records.RecordType, fn func(records.Record) error) error {
+	var after int64
+	for {
+		page, last, err := s.page(ctx, recType, after)
+		if err != nil {
+			return err
+		}
+		for _, rec := range page {
+			if err := fn(rec); err != nil {
+				return err
+			}
+		}
+		if len(page) < eachPageSize {
+			return nil
+		}
+		after = last
+	}
+}
+
+// page returns up to eachPageSize records after the given rowid, with the rowid of the last one
+func (s *SQLiteStorage) page(ctx context.Context, recType records.RecordType, after int64) ([]records.Record, int64, error) {
+	rows, err := s.db.QueryContext(ctx, `
+        SELECT rowid, `+recordColumns+`
+        FROM records
+        WHERE rowid > ? AND (? = '' OR type = ?)
+        ORDER BY rowid
+        LIMIT ?
+    `, after, recType, recType, eachPageSize)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list records: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	recs := make([]records.Record, 0, eachPageSize)
+	last := after
+	for rows.Next() {
+		rec, err := scanRecord(rowidScanner{rows: rows, rowid: &last})
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan record: %w", err)
+		}
+		recs = append(recs, rec)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("error iterating records: %w", err)
+	}
+	return recs, last, nil
+}
+
+// rowidScanner scans the rowid selected before recordColumns, leaving the rest to scanRecord
+type rowidScanner struct {
+	rows  *sql.Rows
+	rowid *int64
+}
+
+// Scan implements the scanner scanRecord reads with
+func (r rowidScanner) Scan(dest ...any) error {
+	return r.rows.Scan(append([]any{r.rowid}, dest...)...)
+}
+
+// ListExpiring returns records expiring at or before the given time, soonest first
+func (s *SQLiteStorage) ListExpiring(ctx context.Context, until time.Time) ([]records.Record, error) {
+	return s.query(ctx, `
+        SELECT `+recordColumns+`
+        FROM records
+        WHERE expires_at IS NOT NULL AND expires_at <= ?
+        ORDER BY expires_at ASC
+    `, until.UTC())
+}
 
+// ListByTag returns the records carrying the tag, newest first
+func (s *SQLiteStorage) ListByTag(ctx context.Context, tag string) ([]records.Record, error) {
+	return s.query(ctx, `
+        SELECT `+recordColumns+`
+        FROM records
+        WHERE EXISTS (SELECT 1 FROM json_each(records.tags) WHERE json_each.value = ?)
+        ORDER BY created_at DESC
+    `, tag)
+}
+
+// marshalTags encodes the tags of a record for the tags column
+func marshalTags(tags []string) (string, error) {
+	if tags == nil {
+		return "[]", nil
+	}
+	data, err := json.Marshal(tags)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal tags: %w", err)
+	}
+	return string(data), nil
+}
+
+// utc normalizes an optional time to UTC so stored values compare correctly as text
+func utc(t *time.Time) interface{} {
+	if t == nil {
+		return nil
+	}
+	return t.UTC()
+}
+
+// query returns the records selected by a query over recordColumns
+func (s *SQLiteStorage) query(ctx context.Context, query string, args ...interface{}) ([]records.Record, error) {
 	rows, err := s.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list records: %w", err)
 	}
 	defer func() {
 		if err := rows.Close(); err != nil {
-			fmt.Printf("warning: failed to close rows: %v\n", err)
+			slog.WarnContext(ctx, "Failed to close rows", "error", err)
 		}
 	}()
 
 	var recs []records.Record
 	for rows.Next() {
-		var rec records.Record
-		var metadataJSON string
-
-		if err := rows.Scan(
-			&rec.ID,
-			&rec.Type,
-			&rec.Content,
-			&metadataJSON,
-			&rec.CreatedAt,
-			&rec.UpdatedAt,
-		); err != nil {
+		rec, err := scanRecord(rows)
+		if err != nil {
 			return nil, fmt.Errorf("failed to scan record: %w", err)
 		}
-
-		if err := json.Unmarshal([]byte(metadataJSON), &rec.Metadata); err != nil {
-			return nil, fmt.Errorf("failed to unmarshal metadata: %w", err)
-		}
-
 		recs = append(recs, rec)
 	}
 
@@ -197,46 +380,112 @@ func (s SQLiteStorage) List(ctx context.Context, recType records.RecordType) ([]
 	return recs, nil
 }
 
+// scanRecord reads a record selected with recordColumns
+func scanRecord(row interface{ Scan(dest ...any) error }) (records.Record, error) {
+	var rec records.Record
+	var metadataJSON, tagsJSON string
+	var deflated []byte
+	var expiresAt sql.NullTime
+
+	if err := row.Scan(
+		&rec.ID,
+		&rec.Type,
+		&rec.Title,
+		&rec.Content,
+		&deflated,
+		&rec.FilePath,
+		&rec.FileName,
+		&metadataJSON,
+		&tagsJSON,
+		&rec.CreatedAt,
+		&rec.UpdatedAt,
+		&expiresAt,
+	); err != nil {
+		return records.Record{}, err
+	}
+
+	if expiresAt.Valid {
+		rec.ExpiresAt = &expiresAt.Time
+	}
+	if deflated != nil {
+		content, err := inflate(deflated)
+		if err != nil {
+			return records.Record{}, fmt.Errorf("failed to decompress content: %w", err)
+		}
+		rec.Content = content
+	}
+	if err := json.Unmarshal([]byte(metadataJSON), &rec.Metadata); err != nil {
+		return records.Record{}, fmt.Errorf("failed to unmarshal metadata: %w", err)
+	}
+	if err := json.Unmarshal([]byte(tagsJSON), &rec.Tags); err != nil {
+		return records.Record{}, fmt.Errorf("failed to unmarshal tags: %w", err)
+	}
+	if len(rec.Tags) == 0 {
+		rec.Tags = nil
+	}
+
+	return rec, nil
+}
+
 // Update updates an existing record
-func (s SQLiteStorage) Update(ctx context.Context, rec records.Record) error {
+func (s *SQLiteStorage) Update(ctx context.Context, rec records.Record) error {
 	metadata, err := json.Marshal(rec.Metadata)
 	if err != nil {
 		return fmt.Errorf("failed to marshal metadata: %w", err)
 	}
+	tags, err := marshalTags(rec.Tags)
+	if err != nil {
+		return err
+	}
+	content, deflated, err := s.contentValues(rec)
+	if err != nil {
+		return err
+	}
 
 	query := `
         UPDATE records
-        SET type = ?, content = ?, metadata = ?, updated_at = ?
+        SET type = ?, title = ?, content = ?, content_deflate = ?, file_path = ?, file_name = ?, metadata = ?, tags = ?, updated_at = ?, expires_at = ?
         WHERE id = ?
     `
 
-	result, err := s.db.ExecContext(ctx, query,
-		rec.Type,
-		rec.Content,
-		string(metadata),
-		rec.UpdatedAt,
-		rec.ID,
-	)
+	var rows int64
+	err = s.transact(ctx, func(tx *sql.Tx) error {
+		result, err := tx.ExecContext(ctx, query,
+			rec.Type,
+			rec.Title,
+			content,
+			deflated,
+			rec.FilePath,
+			rec.FileName,
+			string(metadata),
+			tags,
+			rec.UpdatedAt,
+			utc(rec.ExpiresAt),
+			rec.ID,
+		)
+		if err != nil {
+			return err
+		}
+		if rows, err = result.RowsAffected(); err != nil {
+			return err
+		}
+		return s.indexText(ctx, tx, rec)
+	})
 	if err != nil {
 		return fmt.Errorf("failed to update record: %w", err)
 	}
-
-	rows, err := result.RowsAffected()
-	if err != nil {
-		return fmt.Errorf("failed to get rows affected: %w", err)
-	}
 	if rows == 0 {
-		return fmt.Errorf("record not found: %s", rec.ID)
+		return fmt.Errorf("%w: %s", ErrNotFound, rec.ID)
 	}
 
 	return nil
 }
 
 // Delete removes a record
-func (s SQLiteStorage) Delete(ctx context.Context, id string) error {
+func (s *SQLiteStorage) Delete(ctx context.Context, id string) error {
 	query := `DELETE FROM records WHERE id = ?`
 
-	result, err := s.db.ExecContext(ctx, query, id)
+	result, err := s.exec(ctx, query, id)
 	if err != nil {
 		return fmt.Errorf("failed to delete record: %w", err)
 	}
@@ -246,13 +495,179 @@ func (s SQLiteStorage) Delete(ctx context.Context, id string) error {
 		return fmt.Errorf("failed to get rows affected: %w", err)
 	}
 	if rows == 0 {
-		return fmt.Errorf("record not found: %s", id)
+		return fmt.Errorf("%w: %s", ErrNotFound, id)
+	}
+
+	return nil
+}
+
+// exec runs a write on the writer connection
+func (s *SQLiteStorage) exec(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return s.writer.ExecContext(ctx, query, args...)
+}
+
+// transact runs fn in a transaction on the writer connection, committed
+// once fn succeeds and rolled back otherwise
+func (s *SQLiteStorage) transact(ctx context.Context, fn func(*sql.Tx) error) error {
+	return s.writer.Transact(ctx, fn)
+}
+
+// ftsTriggers keep records_fts in step with the records table
+var ftsTriggers = []string{"records_fts_insert", "records_fts_update", "records_fts_delete"}
+
+// ftsValues selects the indexed columns of a record row
+const ftsValues = `%[1]s.rowid, %[1]s.title, COALESCE(json_extract(%[1]s.metadata, '$.description'), ''), %[1]s.content, %[1]s.file_name, %[1]s.tags`
+
+// initSearchIndex creates the full-text index and the triggers maintaining
+// it. Without FTS5 the triggers are dropped, as they cannot write the index;
+// the index is rebuilt once FTS5 is back.
+func (s *SQLiteStorage) initSearchIndex() error {
+	var enabled bool
+	if err := s.writer.QueryRow(`SELECT sqlite_compileoption_used('ENABLE_FTS5')`).Scan(&enabled); err != nil {
+		return fmt.Errorf("failed to check for FTS5: %w", err)
+	}
+	if !enabled {
+		for _, trigger := range ftsTriggers {
+			if _, err := s.writer.Exec(`DROP TRIGGER IF EXISTS ` + trigger); err != nil {
+				return fmt.Errorf("failed to drop search trigger: %w", err)
+			}
+		}
+		return nil
+	}
+
+	var triggers int
+	if err := s.writer.QueryRow(`SELECT COUNT(*) FROM sqlite_master WHERE type = 'trigger' AND name LIKE 'records_fts_%'`).Scan(&triggers); err != nil {
+		return fmt.Errorf("failed to read search triggers: %w", err)
+	}
+	schema := `
+    CREATE VIRTUAL TABLE IF NOT EXISTS records_fts USING fts5(
+        title, description, content, file_name, tags,
+        tokenize = 'unicode61 remove_diacritics 2',
+        prefix = '2 3'
+    );
+    CREATE TRIGGER IF NOT EXISTS records_fts_insert AFTER INSERT ON records BEGIN
+        INSERT INTO records_fts (rowid, title, description, content, file_name, tags) VALUES (` + fmt.Sprintf(ftsValues, "new") + `);
+    END;
+    CREATE TRIGGER IF NOT EXISTS records_fts_update AFTER UPDATE ON records BEGIN
+        DELETE FROM records_fts WHERE rowid = old.rowid;
+        INSERT INTO records_fts (rowid, title, description, content, file_name, tags) VALUES (` + fmt.Sprintf(ftsValues, "new") + `);
+    END;
+    CREATE TRIGGER IF NOT EXISTS records_fts_delete AFTER DELETE ON records BEGIN
+        DELETE FROM records_fts WHERE rowid = old.rowid;
+    END;
+    `
+	if triggers < len(ftsTriggers) {
+		// The index is new or missed writes made without FTS5
+		schema += `
+        DELETE FROM records_fts;
+        INSERT INTO records_fts (rowid, title, description, content, file_name, tags)
+        SELECT ` + fmt.Sprintf(ftsValues, "records") + ` FROM records;
+        `
+	}
+	if _, err := s.writer.Exec(schema); err != nil {
+		return fmt.Errorf("failed to create search index: %w", err)
+	}
+	s.fts = true
+	if triggers < len(ftsTriggers) {
+		return s.reindexText()
+	}
+	return nil
+}
+
+// indexText indexes the text of a record in place of the empty content the
+// search triggers index when its content is stored compressed. Records that
+// may have been stored compressed are indexed again, which is harmless.
+func (s *SQLiteStorage) indexText(ctx context.Context, tx *sql.Tx, rec records.Record) error {
+	if !s.fts || !s.compress || len(rec.Content) < compressMinSize {
+		return nil
+	}
+	_, err := tx.ExecContext(ctx, `UPDATE records_fts SET content = ? WHERE rowid = (SELECT rowid FROM records WHERE id = ?)`, rec.Content, rec.ID)
+	return err
+}
+
+// reindexText indexes the text of every record whose content is stored
+// compressed, after the index was rebuilt from the records table
+func (s *SQLiteStorage) reindexText() error {
+	ctx := context.Background()
+	rows, err := s.writer.QueryContext(ctx, `SELECT rowid, content_deflate FROM records WHERE content_deflate IS NOT NULL`)
+	if err != nil {
+		return fmt.Errorf("failed to read compressed records: %w", err)
+	}
+	texts := make(map[int64]string)
+	for rows.Next() {
+		var rowid int64
+		var deflated []byte
+		if err := rows.Scan(&rowid, &deflated); err != nil {
+			_ = rows.Close()
+			return fmt.Errorf("failed to scan compressed record: %w", err)
+		}
+		if texts[rowid], err = inflate(deflated); err != nil {
+			_ = rows.Close()
+			return fmt.Errorf("failed to decompress record content: %w", err)
+		}
+	}
+	if err := errors.Join(rows.Err(), rows.Close()); err != nil {
+		return fmt.Errorf("failed to read compressed records: %w", err)
 	}
 
+	err = s.transact(ctx, func(tx *sql.Tx) error {
+		for rowid, text := range texts {
+			if _, err := tx.ExecContext(ctx, `UPDATE records_fts SET content = ? WHERE rowid = ?`, text, rowid); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to index compressed records: %w", err)
+	}
 	return nil
 }
 
-// Close closes the database connection
-func (s SQLiteStorage) Close() error {
-	return s.db.Close()
+// Search implements Storage, ranking records with BM25 over their title,
+// summary, content, file name and tags
+func (s *SQLiteStorage) Search(ctx context.Context, query string, filter SearchFilter, limit int) ([]records.SearchResult, error) {
+	if !s.fts {
+		return scanSearch(ctx, s.Each, query, filter, limit)
+	}
+	terms := parseQuery(query)
+	if len(terms) == 0 {
+		return nil, nil
+	}
+	if limit <= 0 {
+		limit = -1 // No limit
+	}
+
+	rows, err := s.db.QueryContext(ctx, `
+        SELECT `+qualifiedRecordColumns+`,
+            bm25(records_fts, 10.0, 5.0, 1.0, 5.0, 3.0) AS rank,
+            snippet(records_fts, -1, ?, ?, ?, ?)
+        FROM records_fts
+        JOIN records r ON r.rowid = records_fts.rowid
+        WHERE records_fts MATCH ?
+            AND (? = '' OR r.type = ?)
+            AND (? = '' OR EXISTS (SELECT 1 FROM json_each(r.tags) WHERE json_each.value = ?))
+        ORDER BY rank
+        LIMIT ?
+    `, snippetOpen, snippetClose, snippetGap, snippetWords, ftsQuery(terms),
+		filter.Type, filter.Type, filter.Tag, filter.Tag, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search records: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var results []records.SearchResult
+	for rows.Next() {
+		var result records.SearchResult
+		var rank float64
+		if result.Record, err = scanRecord(extraScanner{rows: rows, extra: []any{&rank, &result.Snippet}}); err != nil {
+			return nil, fmt.Errorf("failed to scan record: %w", err)
+		}
+		result.Score = relevance(-rank)
+		results = append(results, result)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating records: %w", err)
+	}
+	return results, nil
 }
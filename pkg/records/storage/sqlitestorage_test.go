@@ -2,23 +2,45 @@ package storage
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/kazemisoroush/assistant/pkg/records"
+	"github.com/kazemisoroush/assistant/pkg/sqlite"
 )
 
+// openDB opens the database at path, closed once the test is done
+func openDB(t testing.TB, path string) *sqlite.DB {
+	t.Helper()
+	db, err := sqlite.Open(path)
+	if err != nil {
+		t.Fatalf("failed to open test database: %v", err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+	return db
+}
+
 func setupTestDB(t *testing.T) (*SQLiteStorage, func()) {
 	t.Helper()
 
 	// Use in-memory database for testing
-	storage, err := NewSQLiteStorage(":memory:")
+	db, err := sqlite.Open(":memory:")
+	if err != nil {
+		t.Fatalf("failed to open test database: %v", err)
+	}
+	storage, err := NewSQLiteStorage(db)
 	if err != nil {
+		_ = db.Close()
 		t.Fatalf("failed to create test storage: %v", err)
 	}
 
 	cleanup := func() {
-		_ = storage.Close()
+		_ = db.Close()
 	}
 
 	return storage, cleanup
@@ -68,6 +90,77 @@ func TestStore(t *testing.T) {
 	}
 }
 
+func TestStore_FileFields(t *testing.T) {
+	storage, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	rec := createTestRecord("scan", records.RecordTypeID)
+	rec.Title = "Passport"
+	rec.FilePath = "/docs/passport.pdf"
+	rec.FileName = "passport.pdf"
+	if err := storage.Store(ctx, rec); err != nil {
+		t.Fatalf("Store failed: %v", err)
+	}
+	rec.Title = "Renewed passport"
+	if err := storage.Update(ctx, rec); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+
+	retrieved, err := storage.Get(ctx, rec.ID)
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if retrieved.Title != "Renewed passport" {
+		t.Errorf("expected Title %q, got %q", "Renewed passport", retrieved.Title)
+	}
+	if retrieved.FilePath != rec.FilePath || retrieved.FileName != rec.FileName {
+		t.Errorf("expected file %s (%s), got %s (%s)", rec.FilePath, rec.FileName, retrieved.FilePath, retrieved.FileName)
+	}
+}
+
+func TestStoreBatch(t *testing.T) {
+	storage, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	existing := createTestRecord("a", records.RecordTypeReceipt)
+	if err := storage.Store(ctx, existing); err != nil {
+		t.Fatalf("Store failed: %v", err)
+	}
+	existing.Content = "replaced content"
+	if err := storage.StoreBatch(ctx, []records.Record{existing, createTestRecord("b", records.RecordTypeReceipt)}); err != nil {
+		t.Fatalf("StoreBatch failed: %v", err)
+	}
+
+	retrieved, err := storage.Get(ctx, "a")
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if retrieved.Content != "replaced content" {
+		t.Errorf("expected Content %q, got %q", "replaced content", retrieved.Content)
+	}
+	if _, err := storage.Get(ctx, "b"); err != nil {
+		t.Errorf("expected new record to be stored: %v", err)
+	}
+}
+
+func TestStoreBatch_Failure(t *testing.T) {
+	storage, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	invalid := createTestRecord("b", records.RecordTypeReceipt)
+	invalid.Metadata["channel"] = make(chan int)
+	if err := storage.StoreBatch(ctx, []records.Record{createTestRecord("a", records.RecordTypeReceipt), invalid}); err == nil {
+		t.Fatal("expected error for record with unmarshalable metadata")
+	}
+
+	if _, err := storage.Get(ctx, "a"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound for record of failed batch, got %v", err)
+	}
+}
+
 func TestGet(t *testing.T) {
 	storage, cleanup := setupTestDB(t)
 	defer cleanup()
@@ -168,6 +261,67 @@ func TestList_WithFilter(t *testing.T) {
 	}
 }
 
+func TestEach(t *testing.T) {
+	storage, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	// Store more records than fit on a page, and one of another type
+	for i := range eachPageSize + 1 {
+		if err := storage.Store(ctx, createTestRecord(fmt.Sprintf("receipt-%d", i), records.RecordTypeReceipt)); err != nil {
+			t.Fatalf("Store failed: %v", err)
+		}
+	}
+	if err := storage.Store(ctx, createTestRecord("visit", records.RecordTypeHealthVisit)); err != nil {
+		t.Fatalf("Store failed: %v", err)
+	}
+
+	// Update each receipt while iterating
+	seen := map[string]bool{}
+	err := storage.Each(ctx, records.RecordTypeReceipt, func(rec records.Record) error {
+		if rec.Type != records.RecordTypeReceipt {
+			t.Errorf("expected type %s, got %s", records.RecordTypeReceipt, rec.Type)
+		}
+		seen[rec.ID] = true
+		rec.Content = "updated"
+		return storage.Update(ctx, rec)
+	})
+	if err != nil {
+		t.Fatalf("Each failed: %v", err)
+	}
+
+	if len(seen) != eachPageSize+1 {
+		t.Errorf("expected %d receipts, got %d", eachPageSize+1, len(seen))
+	}
+}
+
+func TestEach_StopsAtError(t *testing.T) {
+	storage, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	for _, id := range []string{"id-1", "id-2"} {
+		if err := storage.Store(ctx, createTestRecord(id, records.RecordTypeReceipt)); err != nil {
+			t.Fatalf("Store failed: %v", err)
+		}
+	}
+
+	stop := errors.New("stop")
+	calls := 0
+	err := storage.Each(ctx, "", func(records.Record) error {
+		calls++
+		return stop
+	})
+
+	if !errors.Is(err, stop) {
+		t.Errorf("expected the callback error, got %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("expected 1 call, got %d", calls)
+	}
+}
+
 func TestUpdate(t *testing.T) {
 	storage, cleanup := setupTestDB(t)
 	defer cleanup()
@@ -255,19 +409,231 @@ func TestDelete_NotFound(t *testing.T) {
 }
 
 func TestClose(t *testing.T) {
-	storage, _ := setupTestDB(t)
-
-	err := storage.Close()
-	if err != nil {
-		t.Fatalf("Close failed: %v", err)
-	}
+	storage, cleanup := setupTestDB(t)
+	cleanup()
 
-	// Attempting operations after close should fail
+	// Attempting operations after the database is closed should fail
 	ctx := context.Background()
 	rec := createTestRecord("test-id-6", records.RecordTypeReceipt)
 
-	err = storage.Store(ctx, rec)
+	err := storage.Store(ctx, rec)
 	if err == nil {
 		t.Error("expected error when using closed storage, got nil")
 	}
 }
+
+func TestListExpiring(t *testing.T) {
+	storage, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	now := time.Now()
+	soon := now.AddDate(0, 0, 10)
+	later := now.AddDate(1, 0, 0)
+
+	visa := createTestRecord("visa", records.RecordTypeVisa)
+	visa.ExpiresAt = &soon
+	passport := createTestRecord("passport", records.RecordTypeID)
+	passport.ExpiresAt = &later
+	receipt := createTestRecord("receipt", records.RecordTypeReceipt)
+	for _, rec := range []records.Record{visa, passport, receipt} {
+		if err := storage.Store(ctx, rec); err != nil {
+			t.Fatalf("Store failed: %v", err)
+		}
+	}
+
+	expiring, err := storage.ListExpiring(ctx, now.AddDate(0, 0, 30))
+	if err != nil {
+		t.Fatalf("ListExpiring failed: %v", err)
+	}
+
+	if len(expiring) != 1 {
+		t.Fatalf("expected 1 expiring record, got %d", len(expiring))
+	}
+	if expiring[0].ID != "visa" {
+		t.Errorf("expected record visa, got %s", expiring[0].ID)
+	}
+	if expiring[0].ExpiresAt == nil || !expiring[0].ExpiresAt.Equal(soon) {
+		t.Errorf("expected ExpiresAt %v, got %v", soon, expiring[0].ExpiresAt)
+	}
+}
+
+func TestListByTag(t *testing.T) {
+	storage, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	now := time.Now()
+
+	older := createTestRecord("older", records.RecordTypeReceipt)
+	older.CreatedAt = now.Add(-time.Hour)
+	older.Tags = []string{"food", "work"}
+	newer := createTestRecord("newer", records.RecordTypeReceipt)
+	newer.CreatedAt = now
+	newer.Tags = []string{"food"}
+	untagged := createTestRecord("untagged", records.RecordTypeReceipt)
+	untagged.Tags = nil
+	for _, rec := range []records.Record{older, newer, untagged} {
+		if err := storage.Store(ctx, rec); err != nil {
+			t.Fatalf("Store failed: %v", err)
+		}
+	}
+
+	food, err := storage.ListByTag(ctx, "food")
+	if err != nil {
+		t.Fatalf("ListByTag failed: %v", err)
+	}
+	if len(food) != 2 {
+		t.Fatalf("expected 2 food records, got %d", len(food))
+	}
+	if food[0].ID != "newer" || food[1].ID != "older" {
+		t.Errorf("expected newest first, got %s, %s", food[0].ID, food[1].ID)
+	}
+	if len(food[1].Tags) != 2 || food[1].Tags[1] != "work" {
+		t.Errorf("expected tags to round-trip, got %v", food[1].Tags)
+	}
+}
+
+func TestUpdate_Tags(t *testing.T) {
+	storage, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	rec := createTestRecord("tagged", records.RecordTypeReceipt)
+	rec.Tags = []string{"work"}
+	if err := storage.Store(ctx, rec); err != nil {
+		t.Fatalf("Store failed: %v", err)
+	}
+
+	rec.Tags = nil
+	if err := storage.Update(ctx, rec); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+
+	work, err := storage.ListByTag(ctx, "work")
+	if err != nil {
+		t.Fatalf("ListByTag failed: %v", err)
+	}
+	if len(work) != 0 {
+		t.Errorf("expected Update to replace the tags, got %d work records", len(work))
+	}
+	got, err := storage.Get(ctx, "tagged")
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if got.Tags != nil {
+		t.Errorf("expected no tags, got %v", got.Tags)
+	}
+}
+
+func TestStore_SharedDatabase(t *testing.T) {
+	// Two storages stand in for processes sharing the database file
+	dbPath := filepath.Join(t.TempDir(), "records.db")
+	var storages []*SQLiteStorage
+	for range 2 {
+		storage, err := NewSQLiteStorage(openDB(t, dbPath))
+		if err != nil {
+			t.Fatalf("failed to create test storage: %v", err)
+		}
+		storages = append(storages, storage)
+	}
+
+	ctx := context.Background()
+	var wg sync.WaitGroup
+	errs := make(chan error, 100)
+	for i, storage := range storages {
+		for j := range 50 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				errs <- storage.Store(ctx, createTestRecord(fmt.Sprintf("rec-%d-%d", i, j), records.RecordTypeReceipt))
+			}()
+		}
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("Store failed: %v", err)
+		}
+	}
+	recs, err := storages[0].List(ctx, "")
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if len(recs) != 100 {
+		t.Errorf("expected 100 records, got %d", len(recs))
+	}
+}
+
+func TestStore_Compressed(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "records.db")
+	ctx := context.Background()
+	plain := createTestRecord("plain", records.RecordTypeReceipt)
+	plain.Content = strings.Repeat("stored before compression ", 40)
+	uncompressed, err := NewSQLiteStorage(openDB(t, dbPath))
+	if err != nil {
+		t.Fatalf("failed to create test storage: %v", err)
+	}
+	if err := uncompressed.Store(ctx, plain); err != nil {
+		t.Fatalf("Store failed: %v", err)
+	}
+
+	storage, err := NewCompressedSQLiteStorage(openDB(t, dbPath))
+	if err != nil {
+		t.Fatalf("failed to create test storage: %v", err)
+	}
+	rec := createTestRecord("compressed", records.RecordTypeHealthVisit)
+	rec.Content = strings.Repeat("Blood test results within normal range. ", 40)
+	if err := storage.Store(ctx, rec); err != nil {
+		t.Fatalf("Store failed: %v", err)
+	}
+
+	var content string
+	var deflated []byte
+	if err := storage.db.QueryRowContext(ctx, `SELECT content, content_deflate FROM records WHERE id = ?`, rec.ID).Scan(&content, &deflated); err != nil {
+		t.Fatalf("failed to read stored content: %v", err)
+	}
+	if content != "" || len(deflated) == 0 || len(deflated) >= len(rec.Content)/2 {
+		t.Errorf("expected the content to be stored compressed, got %d bytes of text and %d compressed", len(content), len(deflated))
+	}
+	recs, err := storage.List(ctx, "")
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	contents := make(map[string]string)
+	for _, rec := range recs {
+		contents[rec.ID] = rec.Content
+	}
+	if contents["compressed"] != rec.Content {
+		t.Errorf("expected List to decompress the content, got %q", contents["compressed"])
+	}
+	if contents["plain"] != plain.Content {
+		t.Errorf("expected List to read uncompressed content unchanged, got %q", contents["plain"])
+	}
+}
+
+// BenchmarkSQLiteStorage_Store measures writing single records and runs of
+// records, as a scrape stores them, to a database on disk
+func BenchmarkSQLiteStorage_Store(b *testing.B) {
+	for _, batch := range []int{1, 100} {
+		b.Run(fmt.Sprintf("records=%d", batch), func(b *testing.B) {
+			storage, err := NewSQLiteStorage(openDB(b, filepath.Join(b.TempDir(), "records.db")))
+			if err != nil {
+				b.Fatalf("failed to create benchmark storage: %v", err)
+			}
+			ctx := context.Background()
+
+			b.ResetTimer()
+			for i := range b.N {
+				for j := range batch {
+					if err := storage.Store(ctx, createTestRecord(fmt.Sprintf("rec-%d-%d", i, j), records.RecordTypeReceipt)); err != nil {
+						b.Fatalf("Store() failed: %v", err)
+					}
+				}
+			}
+			b.ReportMetric(float64(b.N*batch)/b.Elapsed().Seconds(), "records/s")
+		})
+	}
+}
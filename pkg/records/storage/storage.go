@@ -2,23 +2,48 @@ package storage
 
 import (
 	"context"
+	"errors"
+	"time"
 
 	"github.com/kazemisoroush/assistant/pkg/records"
 )
 
+// ErrNotFound is returned when no record has the ID
+var ErrNotFound = errors.New("record not found")
+
 // Storage defines the persistence layer interface
 //
 //go:generate mockgen -destination=./mocks/mock_storage.go -mock_names=Storage=MockStorage -package=mocks . Storage
 type Storage interface {
-	// Store saves a record
+	// Store saves a record, replacing the record with the same ID
 	Store(ctx context.Context, rec records.Record) error
 
+	// StoreBatch saves several records in one write, replacing the records
+	// with the same IDs. Either every record is saved or, when one fails, none.
+	StoreBatch(ctx context.Context, recs []records.Record) error
+
 	// Get retrieves a record by ID
 	Get(ctx context.Context, id string) (records.Record, error)
 
 	// List returns all records with optional type filter
 	List(ctx context.Context, recType records.RecordType) ([]records.Record, error)
 
+	// Each calls fn with every record, with optional type filter, without
+	// loading them all at once. Iteration stops at the first error fn returns,
+	// which Each returns. Records are passed in storage order.
+	Each(ctx context.Context, recType records.RecordType, fn func(records.Record) error) error
+
+	// ListExpiring returns records expiring at or before the given time, soonest first
+	ListExpiring(ctx context.Context, until time.Time) ([]records.Record, error)
+
+	// ListByTag returns the records carrying the tag, newest first
+	ListByTag(ctx context.Context, tag string) ([]records.Record, error)
+
+	// Search returns the records matching any word of the query, best first,
+	// up to limit when positive. A word ending in * matches the words starting
+	// with it.
+	Search(ctx context.Context, query string, filter SearchFilter, limit int) ([]records.SearchResult, error)
+
 	// Update updates an existing record
 	Update(ctx context.Context, rec records.Record) error
 
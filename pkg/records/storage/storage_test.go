@@ -0,0 +1,123 @@
+package storage_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/kazemisoroush/assistant/pkg/records"
+	"github.com/kazemisoroush/assistant/pkg/records/storage"
+	"github.com/kazemisoroush/assistant/pkg/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// openDB opens the database at path, closed once the test is done
+func openDB(t testing.TB, path string) *sqlite.DB {
+	t.Helper()
+	db, err := sqlite.Open(path)
+	require.NoError(t, err, "Open() error should be nil")
+	t.Cleanup(func() { _ = db.Close() })
+	return db
+}
+
+// backends opens every local record storage backend, so the contract of
+// Storage is checked against each
+var backends = []struct {
+	name string
+	open func(t *testing.T) storage.Storage
+}{
+	{name: "local-json", open: func(t *testing.T) storage.Storage {
+		s, err := storage.NewJSONStorage(filepath.Join(t.TempDir(), "records.json"))
+		require.NoError(t, err, "NewJSONStorage() error should be nil")
+		return s
+	}},
+	{name: "sqlite", open: func(t *testing.T) storage.Storage {
+		s, err := storage.NewSQLiteStorage(openDB(t, filepath.Join(t.TempDir(), "records.db")))
+		require.NoError(t, err, "NewSQLiteStorage() error should be nil")
+		return s
+	}},
+	{name: "sqlite compressed", open: func(t *testing.T) storage.Storage {
+		s, err := storage.NewCompressedSQLiteStorage(openDB(t, filepath.Join(t.TempDir(), "records.db")))
+		require.NoError(t, err, "NewCompressedSQLiteStorage() error should be nil")
+		return s
+	}},
+}
+
+func TestStorage_Store_ReplacesSameID(t *testing.T) {
+	for _, backend := range backends {
+		t.Run(backend.name, func(t *testing.T) {
+			// Arrange
+			ctx := context.Background()
+			s := backend.open(t)
+			created := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+			require.NoError(t, s.Store(ctx, records.Record{ID: "a", Type: records.RecordTypeReceipt, Content: "first", CreatedAt: created}), "Store() error should be nil")
+			require.NoError(t, s.StoreBatch(ctx, []records.Record{{ID: "b", Type: records.RecordTypeOther, CreatedAt: created}}), "StoreBatch() error should be nil")
+
+			// Act
+			storeErr := s.Store(ctx, records.Record{ID: "a", Type: records.RecordTypeReceipt, Content: "second", CreatedAt: created})
+			batchErr := s.StoreBatch(ctx, []records.Record{{ID: "b", Type: records.RecordTypeOther, Content: "replaced", CreatedAt: created}})
+
+			// Assert
+			require.NoError(t, storeErr, "Store() should replace the record with the same ID")
+			require.NoError(t, batchErr, "StoreBatch() should replace the records with the same IDs")
+			a, err := s.Get(ctx, "a")
+			require.NoError(t, err, "Get() error should be nil")
+			assert.Equal(t, "second", a.Content, "Store() should replace the record")
+			b, err := s.Get(ctx, "b")
+			require.NoError(t, err, "Get() error should be nil")
+			assert.Equal(t, "replaced", b.Content, "StoreBatch() should replace the record")
+			listed, err := s.List(ctx, "")
+			require.NoError(t, err, "List() error should be nil")
+			assert.Len(t, listed, 2, "Store() should keep one record per ID")
+		})
+	}
+}
+
+func TestStorage_NotFound(t *testing.T) {
+	for _, backend := range backends {
+		t.Run(backend.name, func(t *testing.T) {
+			// Arrange
+			ctx := context.Background()
+			s := backend.open(t)
+
+			// Act
+			_, getErr := s.Get(ctx, "missing")
+			updateErr := s.Update(ctx, records.Record{ID: "missing", Type: records.RecordTypeOther})
+			deleteErr := s.Delete(ctx, "missing")
+
+			// Assert
+			assert.ErrorIs(t, getErr, storage.ErrNotFound, "Get() should fail with ErrNotFound")
+			assert.ErrorIs(t, updateErr, storage.ErrNotFound, "Update() should fail with ErrNotFound")
+			assert.ErrorIs(t, deleteErr, storage.ErrNotFound, "Delete() should fail with ErrNotFound")
+		})
+	}
+}
+
+func TestStorage_Each_StorageOrder(t *testing.T) {
+	for _, backend := range backends {
+		t.Run(backend.name, func(t *testing.T) {
+			// Arrange
+			ctx := context.Background()
+			s := backend.open(t)
+			require.NoError(t, s.StoreBatch(ctx, []records.Record{
+				{ID: "a", Type: records.RecordTypeReceipt},
+				{ID: "b", Type: records.RecordTypeOther},
+				{ID: "c", Type: records.RecordTypeReceipt},
+			}), "StoreBatch() error should be nil")
+			require.NoError(t, s.Update(ctx, records.Record{ID: "a", Type: records.RecordTypeReceipt, Content: "updated"}), "Update() error should be nil")
+
+			// Act
+			var ids []string
+			err := s.Each(ctx, records.RecordTypeReceipt, func(rec records.Record) error {
+				ids = append(ids, rec.ID)
+				return nil
+			})
+
+			// Assert
+			require.NoError(t, err, "Each() error should be nil")
+			assert.Equal(t, []string{"a", "c"}, ids, "Each() should pass the records of the type in storage order")
+		})
+	}
+}
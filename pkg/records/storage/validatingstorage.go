@@ -0,0 +1,31 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kazemisoroush/assistant/pkg/records"
+)
+
+// ValidatingStorage is a Storage that rejects updates whose metadata violates
+// the schema of the record type
+type ValidatingStorage struct {
+	Storage
+}
+
+// NewValidatingStorage wraps a storage so updates are validated against the record type schemas
+func NewValidatingStorage(storage Storage) Storage {
+	return &ValidatingStorage{Storage: storage}
+}
+
+// Update implements Storage
+func (s *ValidatingStorage) Update(ctx context.Context, rec records.Record) error {
+	before, err := s.Get(ctx, rec.ID)
+	if err != nil {
+		return err
+	}
+	if err := records.ValidateUpdate(before, rec); err != nil {
+		return fmt.Errorf("invalid %s metadata of record %s: %w", rec.Type, rec.ID, err)
+	}
+	return s.Storage.Update(ctx, rec)
+}
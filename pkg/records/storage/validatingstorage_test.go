@@ -0,0 +1,91 @@
+package storage_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kazemisoroush/assistant/pkg/records"
+	"github.com/kazemisoroush/assistant/pkg/records/storage"
+	"github.com/kazemisoroush/assistant/pkg/records/storage/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestValidatingStorage_Update(t *testing.T) {
+	require.NoError(t, records.RegisterSchema(records.Schema{
+		Type:   records.RecordTypeHome,
+		Fields: []records.FieldSchema{{Name: "amount", Type: records.FieldTypeNumber}, {Name: "visit_date", Type: records.FieldTypeDate}},
+	}), "RegisterSchema() error should be nil")
+	t.Cleanup(func() { records.UnregisterSchema(records.RecordTypeHome) })
+
+	stored := records.Record{ID: "rec-1", Type: records.RecordTypeHome, Metadata: map[string]interface{}{"amount": "legacy", "visit_date": "2024-05-01"}}
+
+	tests := []struct {
+		name     string
+		update   records.Record
+		wantSave bool
+	}{
+		{
+			name:     "valid change",
+			update:   records.Record{ID: "rec-1", Type: records.RecordTypeHome, Metadata: map[string]interface{}{"amount": "legacy", "visit_date": "2024-06-01"}},
+			wantSave: true,
+		},
+		{
+			name:     "unchanged invalid field",
+			update:   records.Record{ID: "rec-1", Type: records.RecordTypeHome, Metadata: map[string]interface{}{"amount": "legacy", "visit_date": "2024-05-01", "note": "x"}},
+			wantSave: true,
+		},
+		{
+			name:   "changed field of wrong type",
+			update: records.Record{ID: "rec-1", Type: records.RecordTypeHome, Metadata: map[string]interface{}{"amount": "legacy", "visit_date": "June 1st"}},
+		},
+		{
+			name:     "removed optional field",
+			update:   records.Record{ID: "rec-1", Type: records.RecordTypeHome, Metadata: map[string]interface{}{"amount": "legacy"}},
+			wantSave: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			ctrl := gomock.NewController(t)
+			inner := mocks.NewMockStorage(ctrl)
+			inner.EXPECT().Get(gomock.Any(), "rec-1").Return(stored, nil)
+			if tt.wantSave {
+				inner.EXPECT().Update(gomock.Any(), tt.update).Return(nil)
+			}
+			validating := storage.NewValidatingStorage(inner)
+
+			// Act
+			err := validating.Update(context.Background(), tt.update)
+
+			// Assert
+			if tt.wantSave {
+				assert.NoError(t, err, "Update() should accept the record")
+			} else {
+				assert.Error(t, err, "Update() should reject the record")
+			}
+		})
+	}
+}
+
+func TestValidatingStorage_Update_ChangedType(t *testing.T) {
+	// Arrange
+	require.NoError(t, records.RegisterSchema(records.Schema{
+		Type:   records.RecordTypeTax,
+		Fields: []records.FieldSchema{{Name: "amount", Type: records.FieldTypeNumber, Required: true}},
+	}), "RegisterSchema() error should be nil")
+	t.Cleanup(func() { records.UnregisterSchema(records.RecordTypeTax) })
+	ctrl := gomock.NewController(t)
+	inner := mocks.NewMockStorage(ctrl)
+	inner.EXPECT().Get(gomock.Any(), "rec-1").Return(records.Record{ID: "rec-1", Type: records.RecordTypeOther}, nil)
+	validating := storage.NewValidatingStorage(inner)
+
+	// Act
+	err := validating.Update(context.Background(), records.Record{ID: "rec-1", Type: records.RecordTypeTax})
+
+	// Assert
+	assert.Error(t, err, "Update() should validate the whole schema when the type changes")
+}
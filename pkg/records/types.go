@@ -2,7 +2,11 @@
 package records
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
 	"slices"
+	"strings"
 	"time"
 )
 
@@ -29,11 +33,20 @@ const (
 	RecordTypeCar          RecordType = "car"
 	RecordTypeHome         RecordType = "home"
 	RecordTypeVisa         RecordType = "visa"
+	RecordTypeWarranty     RecordType = "warranty"
 	RecordTypeOther        RecordType = "other"
 )
 
-// AllRecordTypes returns a slice of all defined record types.
+// AllRecordTypes returns a slice of all defined record types, built-in and
+// user-defined, with RecordTypeOther last.
 func AllRecordTypes() []RecordType {
+	types := builtinRecordTypes()
+	types = slices.Insert(types, len(types)-1, customRecordTypes()...)
+	return types
+}
+
+// builtinRecordTypes returns the record types shipped with the application
+func builtinRecordTypes() []RecordType {
 	return []RecordType{
 		RecordTypeHealthVisit,
 		RecordTypeHealthTest,
@@ -47,6 +60,7 @@ func AllRecordTypes() []RecordType {
 		RecordTypeCar,
 		RecordTypeHome,
 		RecordTypeVisa,
+		RecordTypeWarranty,
 		RecordTypeOther,
 	}
 }
@@ -65,15 +79,307 @@ func AllRecordTypesAsStrings() []string {
 type Record struct {
 	ID        string                 `json:"id"`
 	Type      RecordType             `json:"type"`
-	Content   string                 `json:"content"` // Extracted text content
+	Title     string                 `json:"title,omitempty"`
+	Content   string                 `json:"content"`             // Extracted text content
+	FilePath  string                 `json:"file_path,omitempty"` // Path of the file the record was scraped from
+	FileName  string                 `json:"file_name,omitempty"`
 	CreatedAt time.Time              `json:"created_at"`
 	UpdatedAt time.Time              `json:"updated_at"`
 	Metadata  map[string]interface{} `json:"metadata"` // Flexible for type-specific fields
 	Tags      []string               `json:"tags,omitempty"`
+	ExpiresAt *time.Time             `json:"expires_at,omitempty"` // Set on time-bound documents such as visas and policies
+}
+
+// Metadata keys flagging records for manual review
+const (
+	// MetaNeedsReview is true on records whose processing was deferred or is uncertain
+	MetaNeedsReview = "needs_review"
+
+	// MetaReviewReason explains why a record needs review
+	MetaReviewReason = "review_reason"
+)
+
+// MetaThumbnail is the metadata key set on records with a stored thumbnail
+const MetaThumbnail = "thumbnail"
+
+// MetaPerson is the metadata key naming the household member a record is about
+const MetaPerson = "person"
+
+// Person returns the household member the record is attributed to, or "" if none
+func (r Record) Person() string {
+	person, _ := r.Metadata[MetaPerson].(string)
+	return person
+}
+
+// MetaSourcePath is the metadata key holding the path of the file a record was scraped from
+const MetaSourcePath = "source_path"
+
+// SourcePath returns the path of the file the record was scraped from, or "" if unknown.
+// Records stored before FilePath was set only name it in their metadata.
+func (r Record) SourcePath() string {
+	if r.FilePath != "" {
+		return r.FilePath
+	}
+	path, _ := r.Metadata[MetaSourcePath].(string)
+	return path
+}
+
+// Metadata keys identifying the version of the file a record was scraped from
+const (
+	// MetaSourceSHA256 is the hex SHA-256 of the file's content
+	MetaSourceSHA256 = "source_sha256"
+
+	// MetaSourceModTime is the file's modification time in RFC 3339 format with nanoseconds
+	MetaSourceModTime = "source_mod_time"
+
+	// MetaSourceVersion is the version a server reports for a remote file
+	MetaSourceVersion = "source_version"
+)
+
+// MetaDescription is the metadata key holding a short summary of the record
+const MetaDescription = "description"
+
+// Description returns the summary of the record, or "" if it was not summarized
+func (r Record) Description() string {
+	description, _ := r.Metadata[MetaDescription].(string)
+	return description
+}
+
+// IndexText returns the text records are searched by: the title and summary,
+// if any, followed by the content
+func (r Record) IndexText() string {
+	parts := make([]string, 0, 3)
+	for _, part := range []string{r.Title, r.Description(), r.Content} {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return strings.Join(parts, "\n\n")
+}
+
+// MetaLinks is the metadata key listing the IDs of related records, e.g. the visit that ordered a lab
+const MetaLinks = "links"
+
+// Links returns the IDs of the records this record is linked to
+func (r Record) Links() []string {
+	var links []string
+	switch values := r.Metadata[MetaLinks].(type) {
+	case []string:
+		links = values
+	case []interface{}: // Metadata read back from JSON
+		for _, value := range values {
+			if id, ok := value.(string); ok {
+				links = append(links, id)
+			}
+		}
+	}
+	return links
+}
+
+// NeedsReview reports whether the record is waiting in the review queue
+func (r Record) NeedsReview() bool {
+	needsReview, _ := r.Metadata[MetaNeedsReview].(bool)
+	return needsReview
+}
+
+// DecodeMetadata decodes the record metadata into a typed struct such as ReceiptMetadata
+func (r Record) DecodeMetadata(out any) error {
+	data, err := json.Marshal(r.Metadata)
+	if err != nil {
+		return fmt.Errorf("failed to marshal metadata: %w", err)
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("failed to decode metadata of record %s: %w", r.ID, err)
+	}
+	return nil
 }
 
 // SearchResult represents a search result with relevance score
 type SearchResult struct {
-	Record Record  `json:"record"`
-	Score  float64 `json:"score"` // Relevance score (0-1)
+	Record  Record  `json:"record"`
+	Score   float64 `json:"score"`             // Relevance score (0-1)
+	Snippet string  `json:"snippet,omitempty"` // Excerpt of the record with the matched words in [brackets]
+}
+
+// ReceiptMetadata represents the structured fields extracted from a receipt
+type ReceiptMetadata struct {
+	Merchant string  `json:"merchant"`
+	Date     string  `json:"date"` // YYYY-MM-DD
+	Total    float64 `json:"total"`
+	Currency string  `json:"currency"`           // ISO 4217 code
+	Category string  `json:"category,omitempty"` // Spending category, e.g. groceries
+	Country  string  `json:"country,omitempty"`  // ISO 3166-1 alpha-2 code of where the purchase was made
+
+	// Warranty stated on the receipt, if any
+	Product        string `json:"product,omitempty"`
+	WarrantyMonths int    `json:"warranty_months,omitempty"`
+}
+
+// Validate checks that the receipt metadata is complete and well-formed
+func (m ReceiptMetadata) Validate() error {
+	if m.Merchant == "" {
+		return errors.New("merchant is required")
+	}
+	if _, err := time.Parse(time.DateOnly, m.Date); err != nil {
+		return fmt.Errorf("date must be in YYYY-MM-DD format: %q", m.Date)
+	}
+	if m.Total < 0 {
+		return fmt.Errorf("total must not be negative: %v", m.Total)
+	}
+	if len(m.Currency) != 3 {
+		return fmt.Errorf("currency must be a 3-letter ISO code: %q", m.Currency)
+	}
+	if m.WarrantyMonths < 0 {
+		return fmt.Errorf("warranty_months must not be negative: %d", m.WarrantyMonths)
+	}
+	return nil
+}
+
+// DocumentMetadata represents the structured fields extracted from time-bound
+// documents such as visas, passports and insurance policies
+type DocumentMetadata struct {
+	DocumentType string `json:"document_type"` // e.g. passport, visa, insurance policy
+	ExpiresAt    string `json:"expires_at"`    // YYYY-MM-DD, empty when the document does not expire
+}
+
+// Validate checks that the expiry date, when present, is well-formed
+func (m DocumentMetadata) Validate() error {
+	if m.ExpiresAt == "" {
+		return nil
+	}
+	if _, err := time.Parse(time.DateOnly, m.ExpiresAt); err != nil {
+		return fmt.Errorf("expires_at must be in YYYY-MM-DD format: %q", m.ExpiresAt)
+	}
+	return nil
+}
+
+// VisaMetadata represents the structured fields extracted from visas and residence permits
+type VisaMetadata struct {
+	DocumentMetadata
+
+	Country string `json:"country,omitempty"` // ISO 3166-1 alpha-2 code of the country the visa is for
+}
+
+// CarMetadata represents the structured fields extracted from car records:
+// registrations and policies carry an expiry, service invoices the work done
+type CarMetadata struct {
+	DocumentMetadata
+
+	VIN         string  `json:"vin,omitempty"`
+	Plate       string  `json:"plate,omitempty"`
+	Vehicle     string  `json:"vehicle,omitempty"`      // Make and model, e.g. VW Golf
+	Date        string  `json:"date,omitempty"`         // YYYY-MM-DD date of the service
+	Odometer    float64 `json:"odometer,omitempty"`     // Reading at the service, in the unit of the document
+	ServiceType string  `json:"service_type,omitempty"` // e.g. oil_change, inspection; empty when not a service
+	Cost        float64 `json:"cost,omitempty"`
+	Currency    string  `json:"currency,omitempty"` // ISO 4217 code
+}
+
+// Validate checks that the dates, when present, are well-formed and numbers are not negative
+func (m CarMetadata) Validate() error {
+	if err := m.DocumentMetadata.Validate(); err != nil {
+		return err
+	}
+	if m.Date != "" {
+		if _, err := time.Parse(time.DateOnly, m.Date); err != nil {
+			return fmt.Errorf("date must be in YYYY-MM-DD format: %q", m.Date)
+		}
+	}
+	if m.Odometer < 0 || m.Cost < 0 {
+		return fmt.Errorf("odometer and cost must not be negative: %v, %v", m.Odometer, m.Cost)
+	}
+	return nil
+}
+
+// TravelMetadata represents the structured fields extracted from bookings
+// such as flights, trains and hotels
+type TravelMetadata struct {
+	Destination string  `json:"destination"`        // City or place travelled to
+	Country     string  `json:"country"`            // ISO 3166-1 alpha-2 code of the destination
+	StartDate   string  `json:"start_date"`         // YYYY-MM-DD departure or check-in
+	EndDate     string  `json:"end_date,omitempty"` // YYYY-MM-DD arrival or check-out, empty for same-day travel
+	Total       float64 `json:"total,omitempty"`    // Price of the booking
+	Currency    string  `json:"currency,omitempty"` // ISO 4217 code
+}
+
+// Validate checks that the dates, when present, are well-formed and in order
+func (m TravelMetadata) Validate() error {
+	if m.StartDate != "" {
+		if _, err := time.Parse(time.DateOnly, m.StartDate); err != nil {
+			return fmt.Errorf("start_date must be in YYYY-MM-DD format: %q", m.StartDate)
+		}
+	}
+	if m.EndDate != "" {
+		if _, err := time.Parse(time.DateOnly, m.EndDate); err != nil {
+			return fmt.Errorf("end_date must be in YYYY-MM-DD format: %q", m.EndDate)
+		}
+		if m.EndDate < m.StartDate {
+			return fmt.Errorf("end_date must not be before start_date: %q < %q", m.EndDate, m.StartDate)
+		}
+	}
+	if m.Total < 0 {
+		return fmt.Errorf("total must not be negative: %v", m.Total)
+	}
+	return nil
+}
+
+// MetaExpiresAt is the metadata key holding the extracted expiry date (YYYY-MM-DD)
+const MetaExpiresAt = "expires_at"
+
+// InsuranceMetadata represents the structured fields extracted from insurance
+// records: policies carry an expiry, claims and reimbursements the treatment
+// they cover
+type InsuranceMetadata struct {
+	DocumentMetadata
+
+	Provider    string  `json:"provider,omitempty"`     // Doctor, clinic or pharmacy the claim is for
+	ServiceDate string  `json:"service_date,omitempty"` // YYYY-MM-DD date of the treatment claimed
+	Claimed     float64 `json:"claimed,omitempty"`      // Amount submitted to the insurer
+	Reimbursed  float64 `json:"reimbursed,omitempty"`   // Amount paid out by the insurer
+	Currency    string  `json:"currency,omitempty"`     // ISO 4217 code
+}
+
+// IsClaim reports whether the record is a claim or reimbursement rather than a policy
+func (m InsuranceMetadata) IsClaim() bool {
+	return m.Claimed > 0 || m.Reimbursed > 0
+}
+
+// Validate checks that the dates, when present, are well-formed and amounts are not negative
+func (m InsuranceMetadata) Validate() error {
+	if err := m.DocumentMetadata.Validate(); err != nil {
+		return err
+	}
+	if m.ServiceDate != "" {
+		if _, err := time.Parse(time.DateOnly, m.ServiceDate); err != nil {
+			return fmt.Errorf("service_date must be in YYYY-MM-DD format: %q", m.ServiceDate)
+		}
+	}
+	if m.Claimed < 0 || m.Reimbursed < 0 {
+		return fmt.Errorf("claimed and reimbursed must not be negative: %v, %v", m.Claimed, m.Reimbursed)
+	}
+	return nil
+}
+
+// HealthMetadata represents the structured fields extracted from health visits, tests and labs
+type HealthMetadata struct {
+	Patient  string `json:"patient"`             // Person the record is about
+	Provider string `json:"provider"`            // Doctor, clinic or laboratory
+	Date     string `json:"date"`                // YYYY-MM-DD
+	Summary  string `json:"summary"`             // Reason for the visit or the test performed
+	FollowUp string `json:"follow_up,omitempty"` // YYYY-MM-DD of a recommended follow-up visit or test
+}
+
+// Validate checks that the dates, when present, are well-formed
+func (m HealthMetadata) Validate() error {
+	if m.Date != "" {
+		if _, err := time.Parse(time.DateOnly, m.Date); err != nil {
+			return fmt.Errorf("date must be in YYYY-MM-DD format: %q", m.Date)
+		}
+	}
+	if m.FollowUp != "" {
+		if _, err := time.Parse(time.DateOnly, m.FollowUp); err != nil {
+			return fmt.Errorf("follow_up must be in YYYY-MM-DD format: %q", m.FollowUp)
+		}
+	}
+	return nil
 }
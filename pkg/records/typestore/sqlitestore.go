@@ -0,0 +1,138 @@
+package typestore
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/kazemisoroush/assistant/pkg/records"
+
+	"github.com/kazemisoroush/assistant/pkg/sqlite"
+)
+
+// SQLiteStore is a Store backed by SQLite
+type SQLiteStore struct {
+	db *sqlite.DB
+}
+
+// NewSQLiteStore creates a new SQLite record type store in the given database
+func NewSQLiteStore(db *sqlite.DB) (*SQLiteStore, error) {
+	schema := `
+    CREATE TABLE IF NOT EXISTS record_types (
+        name TEXT PRIMARY KEY,
+        definition TEXT NOT NULL
+    );
+
+    CREATE TABLE IF NOT EXISTS record_schemas (
+        type TEXT PRIMARY KEY,
+        fields TEXT NOT NULL
+    );
+    `
+	if _, err := db.Exec(schema); err != nil {
+		return nil, fmt.Errorf("failed to initialize record type schema: %w", err)
+	}
+
+	return &SQLiteStore{db: db}, nil
+}
+
+// Save implements Store
+func (s *SQLiteStore) Save(ctx context.Context, def records.TypeDefinition) error {
+	data, err := json.Marshal(def)
+	if err != nil {
+		return fmt.Errorf("failed to marshal record type %s: %w", def.Name, err)
+	}
+	if _, err := s.db.ExecContext(ctx,
+		`INSERT INTO record_types (name, definition) VALUES (?, ?)
+         ON CONFLICT (name) DO UPDATE SET definition = excluded.definition`,
+		def.Name, string(data),
+	); err != nil {
+		return fmt.Errorf("failed to save record type %s: %w", def.Name, err)
+	}
+	return nil
+}
+
+// Delete implements Store
+func (s *SQLiteStore) Delete(ctx context.Context, name records.RecordType) error {
+	result, err := s.db.ExecContext(ctx, `DELETE FROM record_types WHERE name = ?`, name)
+	if err != nil {
+		return fmt.Errorf("failed to delete record type %s: %w", name, err)
+	}
+	if n, err := result.RowsAffected(); err == nil && n == 0 {
+		return fmt.Errorf("record type not found: %s", name)
+	}
+	return nil
+}
+
+// List implements Store
+func (s *SQLiteStore) List(ctx context.Context) ([]records.TypeDefinition, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT definition FROM record_types ORDER BY name`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list record types: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var defs []records.TypeDefinition
+	for rows.Next() {
+		var data string
+		if err := rows.Scan(&data); err != nil {
+			return nil, fmt.Errorf("failed to scan record type: %w", err)
+		}
+		var def records.TypeDefinition
+		if err := json.Unmarshal([]byte(data), &def); err != nil {
+			return nil, fmt.Errorf("failed to decode record type: %w", err)
+		}
+		defs = append(defs, def)
+	}
+	return defs, rows.Err()
+}
+
+// SaveSchema implements Store
+func (s *SQLiteStore) SaveSchema(ctx context.Context, schema records.Schema) error {
+	data, err := json.Marshal(schema.Fields)
+	if err != nil {
+		return fmt.Errorf("failed to marshal schema of %s: %w", schema.Type, err)
+	}
+	if _, err := s.db.ExecContext(ctx,
+		`INSERT INTO record_schemas (type, fields) VALUES (?, ?)
+         ON CONFLICT (type) DO UPDATE SET fields = excluded.fields`,
+		schema.Type, string(data),
+	); err != nil {
+		return fmt.Errorf("failed to save schema of %s: %w", schema.Type, err)
+	}
+	return nil
+}
+
+// DeleteSchema implements Store
+func (s *SQLiteStore) DeleteSchema(ctx context.Context, recordType records.RecordType) error {
+	result, err := s.db.ExecContext(ctx, `DELETE FROM record_schemas WHERE type = ?`, recordType)
+	if err != nil {
+		return fmt.Errorf("failed to delete schema of %s: %w", recordType, err)
+	}
+	if n, err := result.RowsAffected(); err == nil && n == 0 {
+		return fmt.Errorf("schema not found: %s", recordType)
+	}
+	return nil
+}
+
+// ListSchemas implements Store
+func (s *SQLiteStore) ListSchemas(ctx context.Context) ([]records.Schema, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT type, fields FROM record_schemas ORDER BY type`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list schemas: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var schemas []records.Schema
+	for rows.Next() {
+		var schema records.Schema
+		var data string
+		if err := rows.Scan(&schema.Type, &data); err != nil {
+			return nil, fmt.Errorf("failed to scan schema: %w", err)
+		}
+		if err := json.Unmarshal([]byte(data), &schema.Fields); err != nil {
+			return nil, fmt.Errorf("failed to decode schema of %s: %w", schema.Type, err)
+		}
+		schemas = append(schemas, schema)
+	}
+	return schemas, rows.Err()
+}
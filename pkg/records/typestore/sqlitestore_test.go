@@ -0,0 +1,133 @@
+package typestore_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/kazemisoroush/assistant/pkg/records"
+	"github.com/kazemisoroush/assistant/pkg/records/typestore"
+	"github.com/kazemisoroush/assistant/pkg/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// openDB opens the database at path, closed once the test is done
+func openDB(t testing.TB, path string) *sqlite.DB {
+	t.Helper()
+	db, err := sqlite.Open(path)
+	require.NoError(t, err, "Open() error should be nil")
+	t.Cleanup(func() { _ = db.Close() })
+	return db
+}
+
+func TestSQLiteStore_DefineLoadRemove(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	dbPath := filepath.Join(t.TempDir(), "assistant.db")
+	store, err := typestore.NewSQLiteStore(openDB(t, dbPath))
+	require.NoError(t, err, "NewSQLiteStore() error should be nil")
+	pet := records.TypeDefinition{
+		Name:        "pet",
+		Description: "veterinary records of pets",
+		Fields:      []records.FieldSchema{{Name: "animal", Type: records.FieldTypeString}, {Name: "visit_date", Type: records.FieldTypeDate}},
+		Hints:       []string{"vet", "vaccination"},
+	}
+	t.Cleanup(func() { records.UnregisterType(pet.Name) })
+	require.NoError(t, typestore.Define(ctx, store, pet), "Define() error should be nil")
+	records.UnregisterType(pet.Name)
+
+	// Act
+	require.NoError(t, typestore.Load(ctx, store), "Load() error should be nil")
+
+	// Assert
+	assert.True(t, records.RecordType("pet").IsValid(), "Load() should register stored types")
+	types := records.AllRecordTypes()
+	assert.Equal(t, records.RecordTypeOther, types[len(types)-1], "AllRecordTypes() should keep other last")
+	def, ok := records.CustomType("pet")
+	require.True(t, ok, "CustomType() should find the loaded type")
+	assert.Equal(t, pet, def, "Load() should restore the full definition")
+
+	require.NoError(t, typestore.Remove(ctx, store, pet.Name), "Remove() error should be nil")
+	assert.False(t, records.RecordType("pet").IsValid(), "Remove() should unregister the type")
+	defs, err := store.List(ctx)
+	require.NoError(t, err, "List() error should be nil")
+	assert.Empty(t, defs, "Remove() should delete the stored definition")
+}
+
+func TestDefine_RejectsInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		def  records.TypeDefinition
+	}{
+		{name: "builtin", def: records.TypeDefinition{Name: records.RecordTypeReceipt, Description: "receipts"}},
+		{name: "malformed name", def: records.TypeDefinition{Name: "Pet Records", Description: "pets"}},
+		{name: "unknown field type", def: records.TypeDefinition{Name: "pet", Description: "pets", Fields: []records.FieldSchema{{Name: "weight", Type: "kg"}}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			store, err := typestore.NewSQLiteStore(openDB(t, filepath.Join(t.TempDir(), "assistant.db")))
+			require.NoError(t, err, "NewSQLiteStore() error should be nil")
+
+			// Act
+			err = typestore.Define(context.Background(), store, tt.def)
+
+			// Assert
+			assert.Error(t, err, "Define() should reject the definition")
+		})
+	}
+}
+
+func TestSQLiteStore_DefineLoadRemoveSchema(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	store, err := typestore.NewSQLiteStore(openDB(t, filepath.Join(t.TempDir(), "assistant.db")))
+	require.NoError(t, err, "NewSQLiteStore() error should be nil")
+	fields := []records.FieldSchema{
+		{Name: "amount", Type: records.FieldTypeNumber, Required: true},
+		{Name: "kind", Type: records.FieldTypeString, Enum: []string{"income", "property"}},
+	}
+	t.Cleanup(func() { records.UnregisterSchema(records.RecordTypeTax) })
+	require.NoError(t, typestore.DefineSchema(ctx, store, records.RecordTypeTax, fields), "DefineSchema() error should be nil")
+	records.UnregisterSchema(records.RecordTypeTax)
+
+	// Act
+	require.NoError(t, typestore.Load(ctx, store), "Load() error should be nil")
+
+	// Assert
+	assert.Equal(t, fields, records.SchemaFields(records.RecordTypeTax), "Load() should register stored schemas")
+	assert.Error(t, records.ValidateMetadata(records.RecordTypeTax, map[string]interface{}{"amount": "12 EUR"}), "ValidateMetadata() should reject values of the wrong type")
+	assert.Error(t, records.ValidateMetadata(records.RecordTypeTax, map[string]interface{}{"kind": "income"}), "ValidateMetadata() should reject missing required fields")
+	assert.Error(t, records.ValidateMetadata(records.RecordTypeTax, map[string]interface{}{"amount": 12.0, "kind": "sales"}), "ValidateMetadata() should reject values not allowed")
+	assert.NoError(t, records.ValidateMetadata(records.RecordTypeTax, map[string]interface{}{"amount": 12.0, "kind": "income"}), "ValidateMetadata() should accept valid metadata")
+
+	require.NoError(t, typestore.RemoveSchema(ctx, store, records.RecordTypeTax), "RemoveSchema() error should be nil")
+	assert.Empty(t, records.SchemaFields(records.RecordTypeTax), "RemoveSchema() should unregister the schema")
+	schemas, err := store.ListSchemas(ctx)
+	require.NoError(t, err, "ListSchemas() error should be nil")
+	assert.Empty(t, schemas, "RemoveSchema() should delete the stored schema")
+}
+
+func TestDefineSchema_CustomTypeReplacesFields(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	store, err := typestore.NewSQLiteStore(openDB(t, filepath.Join(t.TempDir(), "assistant.db")))
+	require.NoError(t, err, "NewSQLiteStore() error should be nil")
+	t.Cleanup(func() { records.UnregisterType("pet") })
+	require.NoError(t, typestore.Define(ctx, store, records.TypeDefinition{Name: "pet", Description: "pets"}), "Define() error should be nil")
+	fields := []records.FieldSchema{{Name: "visit_date", Type: records.FieldTypeDate, Required: true}}
+
+	// Act
+	err = typestore.DefineSchema(ctx, store, "pet", fields)
+
+	// Assert
+	require.NoError(t, err, "DefineSchema() error should be nil")
+	def, ok := records.CustomType("pet")
+	require.True(t, ok, "CustomType() should find the type")
+	assert.Equal(t, fields, def.Fields, "DefineSchema() should replace the fields of a custom type")
+	schemas, err := store.ListSchemas(ctx)
+	require.NoError(t, err, "ListSchemas() error should be nil")
+	assert.Empty(t, schemas, "DefineSchema() should keep custom type fields in the definition")
+}
@@ -0,0 +1,110 @@
+// Package typestore persists user-defined record types and the schemas of
+// built-in ones, and registers them at startup.
+package typestore
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kazemisoroush/assistant/pkg/records"
+)
+
+// Store persists user-defined record type definitions
+//
+//go:generate mockgen -destination=./mocks/mock_store.go -mock_names=Store=MockStore -package=mocks . Store
+type Store interface {
+	// Save creates or replaces a definition
+	Save(ctx context.Context, def records.TypeDefinition) error
+
+	// Delete removes a definition
+	Delete(ctx context.Context, name records.RecordType) error
+
+	// List returns all definitions sorted by name
+	List(ctx context.Context) ([]records.TypeDefinition, error)
+
+	// SaveSchema creates or replaces the schema of a built-in type
+	SaveSchema(ctx context.Context, schema records.Schema) error
+
+	// DeleteSchema removes the schema of a built-in type
+	DeleteSchema(ctx context.Context, recordType records.RecordType) error
+
+	// ListSchemas returns all schemas of built-in types sorted by type
+	ListSchemas(ctx context.Context) ([]records.Schema, error)
+}
+
+// Load registers every stored definition and schema with the records package
+func Load(ctx context.Context, store Store) error {
+	defs, err := store.List(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list record types: %w", err)
+	}
+	for _, def := range defs {
+		if err := records.RegisterType(def); err != nil {
+			return err
+		}
+	}
+
+	schemas, err := store.ListSchemas(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list record schemas: %w", err)
+	}
+	for _, schema := range schemas {
+		if err := records.RegisterSchema(schema); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Define validates, saves and registers a definition
+func Define(ctx context.Context, store Store, def records.TypeDefinition) error {
+	if err := def.Validate(); err != nil {
+		return fmt.Errorf("invalid record type %s: %w", def.Name, err)
+	}
+	if err := store.Save(ctx, def); err != nil {
+		return err
+	}
+	return records.RegisterType(def)
+}
+
+// Remove deletes and unregisters a definition. Records of the type keep it.
+func Remove(ctx context.Context, store Store, name records.RecordType) error {
+	if err := store.Delete(ctx, name); err != nil {
+		return err
+	}
+	records.UnregisterType(name)
+	return nil
+}
+
+// DefineSchema validates, saves and registers the schema of a record type. The
+// schema of a user-defined type replaces the fields of its definition.
+func DefineSchema(ctx context.Context, store Store, recordType records.RecordType, fields []records.FieldSchema) error {
+	if def, ok := records.CustomType(recordType); ok {
+		def.Fields = fields
+		return Define(ctx, store, def)
+	}
+
+	schema := records.Schema{Type: recordType, Fields: fields}
+	if err := schema.Validate(); err != nil {
+		return fmt.Errorf("invalid schema of %s: %w", recordType, err)
+	}
+	if err := store.SaveSchema(ctx, schema); err != nil {
+		return err
+	}
+	return records.RegisterSchema(schema)
+}
+
+// RemoveSchema deletes and unregisters the schema of a record type. Records
+// of the type keep their metadata.
+func RemoveSchema(ctx context.Context, store Store, recordType records.RecordType) error {
+	if def, ok := records.CustomType(recordType); ok {
+		def.Fields = nil
+		return Define(ctx, store, def)
+	}
+
+	if err := store.DeleteSchema(ctx, recordType); err != nil {
+		return err
+	}
+	records.UnregisterSchema(recordType)
+	return nil
+}
@@ -0,0 +1,163 @@
+package watcher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"slices"
+	"time"
+)
+
+// PollingConfig represents the directories a polling watcher walks and how often
+type PollingConfig struct {
+	Paths    []string      // Directories watched with their subdirectories
+	Exclude  []string      // Directories never reported, such as the quarantine directory
+	Interval time.Duration // How often the directories are walked
+	Debounce time.Duration // How long a file must stay unchanged before it is reported
+}
+
+// fileState is what a polling watcher knows of a file
+type fileState struct {
+	modTime  time.Time
+	size     int64
+	changed  time.Time // When the file was last seen changing
+	reported bool
+}
+
+// knownFiles are the files a polling watcher saw on its last walk, by path
+type knownFiles map[string]*fileState
+
+// PollingWatcher watches directories by walking them at an interval and
+// comparing the modification time and size of their files. It needs no
+// platform support and sees changes on network mounts, at the cost of
+// noticing them up to an interval late.
+type PollingWatcher struct {
+	config PollingConfig
+}
+
+// NewPollingWatcher creates a new PollingWatcher instance
+func NewPollingWatcher(config PollingConfig) Watcher {
+	return &PollingWatcher{config: config}
+}
+
+// Watch implements Watcher. Files present when it starts are not reported
+// unless they change. Failing to walk a directory stops watching.
+func (w *PollingWatcher) Watch(ctx context.Context) (<-chan string, <-chan error) {
+	pathChan := make(chan string)
+	errChan := make(chan error, 1)
+
+	go func() {
+		defer close(pathChan)
+		defer close(errChan)
+		if err := w.run(ctx, pathChan); err != nil {
+			errChan <- err
+		}
+	}()
+
+	return pathChan, errChan
+}
+
+// run walks the directories every interval until ctx is done, sending the files that settled
+func (w *PollingWatcher) run(ctx context.Context, pathChan chan<- string) error {
+	files, err := w.scan()
+	if err != nil {
+		return err
+	}
+	known := make(knownFiles, len(files))
+	for path, info := range files {
+		known[path] = &fileState{modTime: info.ModTime(), size: info.Size(), reported: true}
+	}
+
+	ticker := time.NewTicker(w.config.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case now := <-ticker.C:
+			files, err := w.scan()
+			if err != nil {
+				return err
+			}
+			known.update(files, now)
+			for _, path := range known.settled(now, w.config.Debounce) {
+				select {
+				case pathChan <- path:
+				case <-ctx.Done():
+					return nil
+				}
+			}
+		}
+	}
+}
+
+// scan returns the files under the watched directories by path
+func (w *PollingWatcher) scan() (map[string]fs.FileInfo, error) {
+	files := map[string]fs.FileInfo{}
+	for _, root := range w.config.Paths {
+		err := filepath.WalkDir(filepath.Clean(root), func(path string, d fs.DirEntry, err error) error {
+			// Files removed while walking are gone by the next walk
+			if errors.Is(err, fs.ErrNotExist) && path != filepath.Clean(root) {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			if d.IsDir() {
+				return w.skip(path)
+			}
+			info, err := d.Info()
+			if err != nil {
+				return nil
+			}
+			files[path] = info
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to walk watched directory %s: %w", root, err)
+		}
+	}
+	return files, nil
+}
+
+// skip returns filepath.SkipDir for excluded directories
+func (w *PollingWatcher) skip(dir string) error {
+	for _, excluded := range w.config.Exclude {
+		if filepath.Clean(excluded) == dir {
+			return filepath.SkipDir
+		}
+	}
+	return nil
+}
+
+// update notes the files added, modified and removed since the last walk
+func (k knownFiles) update(files map[string]fs.FileInfo, now time.Time) {
+	for path := range k {
+		if _, ok := files[path]; !ok {
+			delete(k, path)
+		}
+	}
+	for path, info := range files {
+		state, ok := k[path]
+		if ok && state.modTime.Equal(info.ModTime()) && state.size == info.Size() {
+			continue
+		}
+		k[path] = &fileState{modTime: info.ModTime(), size: info.Size(), changed: now}
+	}
+}
+
+// settled returns the files unchanged for the debounce period that were not
+// reported since they last changed, in path order, marking them reported
+func (k knownFiles) settled(now time.Time, debounce time.Duration) []string {
+	var paths []string
+	for path, state := range k {
+		if !state.reported && now.Sub(state.changed) >= debounce {
+			state.reported = true
+			paths = append(paths, path)
+		}
+	}
+	slices.Sort(paths)
+	return paths
+}
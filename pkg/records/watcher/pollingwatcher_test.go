@@ -0,0 +1,63 @@
+package watcher_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/kazemisoroush/assistant/pkg/records/watcher"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPollingWatcher_Watch(t *testing.T) {
+	// Arrange
+	dir := t.TempDir()
+	quarantine := filepath.Join(dir, "quarantine")
+	require.NoError(t, os.MkdirAll(quarantine, 0700))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "existing.txt"), []byte("old"), 0600))
+	w := watcher.NewPollingWatcher(watcher.PollingConfig{
+		Paths:    []string{dir},
+		Exclude:  []string{quarantine},
+		Interval: 10 * time.Millisecond,
+		Debounce: 30 * time.Millisecond,
+	})
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// Act
+	paths, errs := w.Watch(ctx)
+	time.Sleep(50 * time.Millisecond)
+	require.NoError(t, os.WriteFile(filepath.Join(quarantine, "broken.pdf"), []byte("broken"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "new.txt"), []byte("new"), 0600))
+	var got string
+	select {
+	case got = <-paths:
+	case err := <-errs:
+		require.NoError(t, err, "Watch() should not fail")
+	case <-ctx.Done():
+	}
+	cancel()
+
+	// Assert
+	assert.Equal(t, filepath.Join(dir, "new.txt"), got, "Watch() should report the new file only")
+}
+
+func TestPollingWatcher_Watch_MissingDirectory(t *testing.T) {
+	// Arrange
+	w := watcher.NewPollingWatcher(watcher.PollingConfig{
+		Paths:    []string{filepath.Join(t.TempDir(), "missing")},
+		Interval: 10 * time.Millisecond,
+	})
+
+	// Act
+	paths, errs := w.Watch(context.Background())
+	err := <-errs
+
+	// Assert
+	require.Error(t, err, "Watch() should fail when a watched directory is missing")
+	_, ok := <-paths
+	assert.False(t, ok, "Watch() should stop once it failed")
+}
@@ -0,0 +1,14 @@
+// Package watcher reports the files added or modified under directories as
+// they settle, so they can be ingested while the assistant runs.
+package watcher
+
+import "context"
+
+// Watcher reports changed files
+//
+//go:generate mockgen -destination=./mocks/mock_watcher.go -mock_names=Watcher=MockWatcher -package=mocks . Watcher
+type Watcher interface {
+	// Watch sends the path of every file added or modified after it started,
+	// once the file stopped changing. It runs until ctx is done.
+	Watch(ctx context.Context) (<-chan string, <-chan error)
+}
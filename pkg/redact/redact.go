@@ -0,0 +1,82 @@
+// Package redact removes personally identifiable information from text.
+package redact
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Redactor replaces personally identifiable information in text with placeholders
+//
+//go:generate mockgen -destination=./mocks/mock_redactor.go -mock_names=Redactor=MockRedactor -package=mocks . Redactor
+type Redactor interface {
+	// Redact returns text with every detected PII value replaced by a placeholder
+	Redact(text string) string
+}
+
+// rule replaces matches of a pattern with a placeholder. When valid is set,
+// only matches it accepts are replaced.
+type rule struct {
+	placeholder string
+	pattern     *regexp.Regexp
+	valid       func(match string) bool
+}
+
+// rules are applied in order, so patterns that could be mistaken for a later
+// one (an IBAN or card number for a phone number) come first
+var rules = []rule{
+	{placeholder: "[EMAIL]", pattern: regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`)},
+	{placeholder: "[IBAN]", pattern: regexp.MustCompile(`\b[A-Z]{2}\d{2}(?: ?[A-Z0-9]{4}){2,7}(?: ?[A-Z0-9]{1,3})?\b`)},
+	{placeholder: "[CARD_NUMBER]", pattern: regexp.MustCompile(`\b\d(?:[ -]?\d){12,18}\b`), valid: luhn},
+	{placeholder: "[SSN]", pattern: regexp.MustCompile(`\b\d{3}-\d{2}-\d{4}\b`)},
+	{placeholder: "[PHONE]", pattern: regexp.MustCompile(`\+\d{1,3}[ .-]?(?:\(\d{1,4}\)[ .-]?)?\d{2,4}(?:[ .-]?\d{2,4}){1,3}\b`)},
+	{placeholder: "[PHONE]", pattern: regexp.MustCompile(`\(?\b\d{3}\)?[ .-]\d{3}[ .-]\d{4}\b`)},
+	{placeholder: "[PHONE]", pattern: regexp.MustCompile(`\b0\d{3} ?\d{3} ?\d{3}\b`)},
+}
+
+// PatternRedactor detects email addresses, IBANs, card numbers, US social
+// security numbers and phone numbers with regular expressions
+type PatternRedactor struct{}
+
+// NewPatternRedactor creates a new pattern-based redactor
+func NewPatternRedactor() Redactor {
+	return &PatternRedactor{}
+}
+
+// Redact implements Redactor
+func (p *PatternRedactor) Redact(text string) string {
+	for _, r := range rules {
+		text = r.pattern.ReplaceAllStringFunc(text, func(match string) string {
+			if r.valid != nil && !r.valid(match) {
+				return match
+			}
+			return r.placeholder
+		})
+	}
+	return text
+}
+
+// luhn reports whether the digits in s pass the Luhn checksum used by card numbers
+func luhn(s string) bool {
+	digits := strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return r
+		}
+		return -1
+	}, s)
+
+	sum := 0
+	double := false
+	for i := len(digits) - 1; i >= 0; i-- {
+		d := int(digits[i] - '0')
+		if double {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+	}
+	return sum%10 == 0
+}
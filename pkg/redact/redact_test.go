@@ -0,0 +1,36 @@
+package redact
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPatternRedactor_Redact(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{name: "email", text: "Contact jane.doe@example.com today", want: "Contact [EMAIL] today"},
+		{name: "card number", text: "Paid with 4111 1111 1111 1111", want: "Paid with [CARD_NUMBER]"},
+		{name: "digits failing the checksum are kept", text: "Order 4111 1111 1111 1112", want: "Order 4111 1111 1111 1112"},
+		{name: "iban", text: "IBAN DE89 3704 0044 0532 0130 00", want: "IBAN [IBAN]"},
+		{name: "ssn", text: "SSN 123-45-6789", want: "SSN [SSN]"},
+		{name: "international phone", text: "Call +61 412 345 678", want: "Call [PHONE]"},
+		{name: "local phone", text: "Call 0412 345 678", want: "Call [PHONE]"},
+		{name: "us phone", text: "Call (555) 123-4567", want: "Call [PHONE]"},
+		{name: "amounts and dates are kept", text: "Total 1234.56 on 2024-01-15", want: "Total 1234.56 on 2024-01-15"},
+	}
+
+	redactor := NewPatternRedactor()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			got := redactor.Redact(tt.text)
+
+			// Assert
+			assert.Equal(t, tt.want, got, "Redact() should replace PII with a placeholder")
+		})
+	}
+}
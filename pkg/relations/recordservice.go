@@ -0,0 +1,91 @@
+package relations
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/kazemisoroush/assistant/pkg/records/storage"
+)
+
+// RecordService links stored records. The links extractors keep in record
+// metadata are related records too, though only relations linked through the
+// service can be unlinked.
+type RecordService struct {
+	store   Store
+	storage storage.Storage
+}
+
+// NewRecordService creates a new relations service
+func NewRecordService(store Store, storage storage.Storage) Service {
+	return &RecordService{
+		store:   store,
+		storage: storage,
+	}
+}
+
+// Link implements Service. Both records must exist.
+func (s *RecordService) Link(ctx context.Context, fromID, toID, kind string) (Relation, error) {
+	if fromID == toID {
+		return Relation{}, ErrSelfLink
+	}
+	for _, id := range []string{fromID, toID} {
+		if _, err := s.storage.Get(ctx, id); err != nil {
+			return Relation{}, fmt.Errorf("failed to get record %s: %w", id, err)
+		}
+	}
+	if kind == "" {
+		kind = KindRelated
+	}
+
+	relation := Relation{FromID: fromID, ToID: toID, Kind: kind, CreatedAt: time.Now().UTC()}
+	if err := s.store.Link(ctx, relation); err != nil {
+		return Relation{}, err
+	}
+	return relation, nil
+}
+
+// Unlink implements Service
+func (s *RecordService) Unlink(ctx context.Context, fromID, toID string) error {
+	return s.store.Unlink(ctx, fromID, toID)
+}
+
+// GetRelated implements Service. Each related record is returned once, under
+// the relation linked first; records deleted since they were linked are left out.
+func (s *RecordService) GetRelated(ctx context.Context, id string) ([]Related, error) {
+	rec, err := s.storage.Get(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get record %s: %w", id, err)
+	}
+	relations, err := s.store.List(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	for _, link := range rec.Links() {
+		relations = append(relations, Relation{FromID: id, ToID: link, Kind: KindRelated})
+	}
+
+	seen := map[string]bool{id: true}
+	var related []Related
+	for _, relation := range relations {
+		other := relation.ToID
+		if other == id {
+			other = relation.FromID
+		}
+		if seen[other] {
+			continue
+		}
+		seen[other] = true
+
+		otherRec, err := s.storage.Get(ctx, other)
+		if errors.Is(err, storage.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to get related record %s: %w", other, err)
+		}
+		related = append(related, Related{Relation: relation, Record: otherRec})
+	}
+	return related, nil
+}
@@ -0,0 +1,86 @@
+package relations_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kazemisoroush/assistant/pkg/records"
+	"github.com/kazemisoroush/assistant/pkg/records/storage"
+	storagemocks "github.com/kazemisoroush/assistant/pkg/records/storage/mocks"
+	"github.com/kazemisoroush/assistant/pkg/relations"
+	"github.com/kazemisoroush/assistant/pkg/relations/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestRecordService_Link(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	store := mocks.NewMockStore(ctrl)
+	recordStorage := storagemocks.NewMockStorage(ctrl)
+	recordStorage.EXPECT().Get(gomock.Any(), "receipt").Return(records.Record{ID: "receipt"}, nil)
+	recordStorage.EXPECT().Get(gomock.Any(), "visit").Return(records.Record{ID: "visit"}, nil)
+	var stored relations.Relation
+	store.EXPECT().Link(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, relation relations.Relation) error {
+		stored = relation
+		return nil
+	})
+	service := relations.NewRecordService(store, recordStorage)
+
+	// Act
+	relation, err := service.Link(context.Background(), "receipt", "visit", "")
+
+	// Assert
+	require.NoError(t, err, "Link() error should be nil")
+	assert.Equal(t, stored, relation, "Link() should return the stored relation")
+	assert.Equal(t, relations.KindRelated, relation.Kind, "Link() should default the kind")
+	assert.False(t, relation.CreatedAt.IsZero(), "Link() should date the relation")
+}
+
+func TestRecordService_Link_Invalid(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	recordStorage := storagemocks.NewMockStorage(ctrl)
+	recordStorage.EXPECT().Get(gomock.Any(), "receipt").Return(records.Record{ID: "receipt"}, nil)
+	recordStorage.EXPECT().Get(gomock.Any(), "missing").Return(records.Record{}, storage.ErrNotFound)
+	service := relations.NewRecordService(mocks.NewMockStore(ctrl), recordStorage)
+
+	// Act
+	_, selfErr := service.Link(context.Background(), "receipt", "receipt", "")
+	_, missingErr := service.Link(context.Background(), "receipt", "missing", "")
+
+	// Assert
+	assert.ErrorIs(t, selfErr, relations.ErrSelfLink, "Link() should not link a record to itself")
+	assert.ErrorIs(t, missingErr, storage.ErrNotFound, "Link() should not link a missing record")
+}
+
+func TestRecordService_GetRelated(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	store := mocks.NewMockStore(ctrl)
+	store.EXPECT().List(gomock.Any(), "receipt").Return([]relations.Relation{
+		{FromID: "receipt", ToID: "visit", Kind: "belongs_to"},
+		{FromID: "policy", ToID: "receipt", Kind: "covers"},
+		{FromID: "receipt", ToID: "deleted", Kind: "related"},
+	}, nil)
+	recordStorage := storagemocks.NewMockStorage(ctrl)
+	recordStorage.EXPECT().Get(gomock.Any(), "receipt").
+		Return(records.Record{ID: "receipt", Metadata: map[string]any{records.MetaLinks: []string{"visit", "invoice"}}}, nil)
+	recordStorage.EXPECT().Get(gomock.Any(), "visit").Return(records.Record{ID: "visit"}, nil)
+	recordStorage.EXPECT().Get(gomock.Any(), "policy").Return(records.Record{ID: "policy"}, nil)
+	recordStorage.EXPECT().Get(gomock.Any(), "deleted").Return(records.Record{}, storage.ErrNotFound)
+	recordStorage.EXPECT().Get(gomock.Any(), "invoice").Return(records.Record{ID: "invoice"}, nil)
+	service := relations.NewRecordService(store, recordStorage)
+
+	// Act
+	related, err := service.GetRelated(context.Background(), "receipt")
+
+	// Assert
+	require.NoError(t, err, "GetRelated() error should be nil")
+	require.Len(t, related, 3, "GetRelated() should return every related record once, leaving out deleted ones")
+	assert.Equal(t, "visit", related[0].Record.ID, "GetRelated() should return records linked from the record")
+	assert.Equal(t, "belongs_to", related[0].Relation.Kind, "GetRelated() should keep the relation linked first")
+	assert.Equal(t, "policy", related[1].Record.ID, "GetRelated() should return records linked to the record")
+	assert.Equal(t, "invoice", related[2].Record.ID, "GetRelated() should return the links in the metadata")
+}
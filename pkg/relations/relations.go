@@ -0,0 +1,66 @@
+// Package relations links records to each other, such as a receipt to the
+// health visit it belongs to or an insurance policy to the car it covers.
+package relations
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/kazemisoroush/assistant/pkg/records"
+)
+
+// KindRelated is the kind of relations linked without one, and of the links
+// kept in record metadata
+const KindRelated = "related"
+
+var (
+	// ErrNotFound is returned when unlinking records that are not linked
+	ErrNotFound = errors.New("relation not found")
+
+	// ErrSelfLink is returned when linking a record to itself
+	ErrSelfLink = errors.New("a record cannot be linked to itself")
+)
+
+// Relation represents a link from one record to another
+type Relation struct {
+	FromID    string    `json:"from_id"`
+	ToID      string    `json:"to_id"`
+	Kind      string    `json:"kind"` // How the first record relates to the second, e.g. belongs_to or covers
+	CreatedAt time.Time `json:"created_at,omitzero"`
+}
+
+// Related represents a record related to another, with the relation linking them
+type Related struct {
+	Relation Relation       `json:"relation"`
+	Record   records.Record `json:"record"`
+}
+
+// Store keeps the relations between records
+//
+//go:generate mockgen -destination=./mocks/mock_store.go -mock_names=Store=MockStore -package=mocks . Store
+type Store interface {
+	// Link adds a relation, replacing the one from and to the same records
+	Link(ctx context.Context, relation Relation) error
+
+	// Unlink removes the relation from one record to another, returning
+	// ErrNotFound when there is none
+	Unlink(ctx context.Context, fromID, toID string) error
+
+	// List returns the relations from and to a record, oldest first
+	List(ctx context.Context, id string) ([]Relation, error)
+}
+
+// Service links records and finds the records related to one
+//
+//go:generate mockgen -destination=./mocks/mock_service.go -mock_names=Service=MockService -package=mocks . Service
+type Service interface {
+	// Link relates one record to another by the kind of relation, KindRelated when empty
+	Link(ctx context.Context, fromID, toID, kind string) (Relation, error)
+
+	// Unlink removes the relation from one record to another
+	Unlink(ctx context.Context, fromID, toID string) error
+
+	// GetRelated returns the records related to a record, either way
+	GetRelated(ctx context.Context, id string) ([]Related, error)
+}
@@ -0,0 +1,84 @@
+package relations
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/kazemisoroush/assistant/pkg/sqlite"
+)
+
+// SQLiteStore is a Store backed by SQLite
+type SQLiteStore struct {
+	db *sqlite.DB
+}
+
+// NewSQLiteStore creates a new SQLite relations store in the given database
+func NewSQLiteStore(db *sqlite.DB) (*SQLiteStore, error) {
+	schema := `
+    CREATE TABLE IF NOT EXISTS record_relations (
+        from_id TEXT NOT NULL,
+        to_id TEXT NOT NULL,
+        kind TEXT NOT NULL,
+        created_at DATETIME NOT NULL,
+        PRIMARY KEY (from_id, to_id)
+    );
+    CREATE INDEX IF NOT EXISTS idx_record_relations_to_id ON record_relations(to_id);
+    `
+	if _, err := db.Exec(schema); err != nil {
+		return nil, fmt.Errorf("failed to initialize relations schema: %w", err)
+	}
+
+	return &SQLiteStore{db: db}, nil
+}
+
+// Link implements Store
+func (s *SQLiteStore) Link(ctx context.Context, relation Relation) error {
+	if _, err := s.db.ExecContext(ctx,
+		`INSERT INTO record_relations (from_id, to_id, kind, created_at) VALUES (?, ?, ?, ?)
+         ON CONFLICT (from_id, to_id) DO UPDATE SET kind = excluded.kind, created_at = excluded.created_at`,
+		relation.FromID, relation.ToID, relation.Kind, relation.CreatedAt,
+	); err != nil {
+		return fmt.Errorf("failed to link record %s to %s: %w", relation.FromID, relation.ToID, err)
+	}
+	return nil
+}
+
+// Unlink implements Store
+func (s *SQLiteStore) Unlink(ctx context.Context, fromID, toID string) error {
+	result, err := s.db.ExecContext(ctx, `DELETE FROM record_relations WHERE from_id = ? AND to_id = ?`, fromID, toID)
+	if err != nil {
+		return fmt.Errorf("failed to unlink record %s from %s: %w", fromID, toID, err)
+	}
+	if n, err := result.RowsAffected(); err == nil && n == 0 {
+		return fmt.Errorf("%w: %s to %s", ErrNotFound, fromID, toID)
+	}
+	return nil
+}
+
+// List implements Store
+func (s *SQLiteStore) List(ctx context.Context, id string) ([]Relation, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT from_id, to_id, kind, created_at FROM record_relations WHERE from_id = ? OR to_id = ? ORDER BY created_at, from_id, to_id`,
+		id, id,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list relations of record %s: %w", id, err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var relations []Relation
+	for rows.Next() {
+		var relation Relation
+		var createdAt time.Time
+		if err := rows.Scan(&relation.FromID, &relation.ToID, &relation.Kind, &createdAt); err != nil {
+			return nil, fmt.Errorf("failed to scan relation: %w", err)
+		}
+		relation.CreatedAt = createdAt.UTC()
+		relations = append(relations, relation)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list relations of record %s: %w", id, err)
+	}
+	return relations, nil
+}
@@ -0,0 +1,49 @@
+package relations_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/kazemisoroush/assistant/pkg/relations"
+	"github.com/kazemisoroush/assistant/pkg/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// openDB opens the database at path, closed once the test is done
+func openDB(t testing.TB, path string) *sqlite.DB {
+	t.Helper()
+	db, err := sqlite.Open(path)
+	require.NoError(t, err, "Open() error should be nil")
+	t.Cleanup(func() { _ = db.Close() })
+	return db
+}
+
+func TestSQLiteStore_LinkListUnlink(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	store, err := relations.NewSQLiteStore(openDB(t, filepath.Join(t.TempDir(), "assistant.db")))
+	require.NoError(t, err, "NewSQLiteStore() error should be nil")
+	linkedAt := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	receipt := relations.Relation{FromID: "receipt", ToID: "visit", Kind: "belongs_to", CreatedAt: linkedAt}
+	policy := relations.Relation{FromID: "policy", ToID: "receipt", Kind: "covers", CreatedAt: linkedAt.Add(time.Hour)}
+
+	// Act
+	require.NoError(t, store.Link(ctx, receipt), "Link() error should be nil")
+	require.NoError(t, store.Link(ctx, policy), "Link() error should be nil")
+	receipt.Kind = "paid_for"
+	require.NoError(t, store.Link(ctx, receipt), "Link() should replace the relation")
+
+	// Assert
+	got, err := store.List(ctx, "receipt")
+	require.NoError(t, err, "List() error should be nil")
+	assert.Equal(t, []relations.Relation{receipt, policy}, got, "List() should return the relations either way, oldest first")
+
+	require.NoError(t, store.Unlink(ctx, "receipt", "visit"), "Unlink() error should be nil")
+	got, err = store.List(ctx, "visit")
+	require.NoError(t, err, "List() error should be nil")
+	assert.Empty(t, got, "Unlink() should remove the relation")
+	assert.ErrorIs(t, store.Unlink(ctx, "receipt", "visit"), relations.ErrNotFound, "Unlink() should fail on records not linked")
+}
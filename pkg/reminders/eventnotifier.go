@@ -0,0 +1,24 @@
+package reminders
+
+import (
+	"context"
+
+	"github.com/kazemisoroush/assistant/pkg/notifications"
+)
+
+// EventNotifier sends reminders through the notification channels subscribed to reminders
+type EventNotifier struct {
+	notifier notifications.Notifier
+}
+
+// NewEventNotifier creates a notifier delivering reminders as notifications
+func NewEventNotifier(notifier notifications.Notifier) Notifier {
+	return &EventNotifier{
+		notifier: notifier,
+	}
+}
+
+// Notify implements Notifier
+func (n *EventNotifier) Notify(ctx context.Context, reminders []Reminder) error {
+	return n.notifier.Notify(ctx, notifications.EventReminders, reminders)
+}
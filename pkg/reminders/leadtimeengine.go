@@ -0,0 +1,167 @@
+package reminders
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/kazemisoroush/assistant/pkg/records"
+	"github.com/kazemisoroush/assistant/pkg/records/storage"
+)
+
+// metaReminderLeadDays records on each record the tightest lead time already reminded about
+const metaReminderLeadDays = "reminder_lead_days"
+
+// LeadTimeEngine sends one reminder per configured lead time before a record
+// expires, and one more once it has expired
+type LeadTimeEngine struct {
+	storage   storage.Storage
+	leadDays  []int // Ascending
+	notifiers []Notifier
+}
+
+// NewLeadTimeEngine creates a reminders engine. Without lead days, DefaultLeadDays are used.
+func NewLeadTimeEngine(storage storage.Storage, leadDays []int, notifiers ...Notifier) Engine {
+	if len(leadDays) == 0 {
+		leadDays = DefaultLeadDays
+	}
+	leadDays = slices.Clone(leadDays)
+	slices.Sort(leadDays)
+
+	return &LeadTimeEngine{
+		storage:   storage,
+		leadDays:  leadDays,
+		notifiers: notifiers,
+	}
+}
+
+// Upcoming implements Engine
+func (e *LeadTimeEngine) Upcoming(ctx context.Context, now time.Time) ([]Reminder, error) {
+	recs, err := e.expiring(ctx, now)
+	if err != nil {
+		return nil, err
+	}
+
+	reminders := make([]Reminder, 0, len(recs))
+	for _, rec := range recs {
+		reminders = append(reminders, e.reminder(rec, now))
+	}
+	return reminders, nil
+}
+
+// Run implements Engine. Records are only marked as reminded once every
+// notifier has delivered, so failed deliveries are retried on the next run.
+func (e *LeadTimeEngine) Run(ctx context.Context, now time.Time) ([]Reminder, error) {
+	recs, err := e.expiring(ctx, now)
+	if err != nil {
+		return nil, err
+	}
+
+	var due []Reminder
+	var dueRecs []records.Record
+	for _, rec := range recs {
+		reminder := e.reminder(rec, now)
+		if alreadyReminded(rec, reminder.LeadDays) {
+			continue
+		}
+		due = append(due, reminder)
+		dueRecs = append(dueRecs, rec)
+	}
+	if len(due) == 0 {
+		return nil, nil
+	}
+
+	var errs []error
+	for _, notifier := range e.notifiers {
+		if err := notifier.Notify(ctx, due); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if err := errors.Join(errs...); err != nil {
+		return nil, fmt.Errorf("failed to send reminders: %w", err)
+	}
+
+	for i, rec := range dueRecs {
+		if err := e.markReminded(ctx, rec, due[i].LeadDays, now); err != nil {
+			return nil, err
+		}
+	}
+	return due, nil
+}
+
+func (e *LeadTimeEngine) expiring(ctx context.Context, now time.Time) ([]records.Record, error) {
+	longest := e.leadDays[len(e.leadDays)-1]
+	recs, err := e.storage.ListExpiring(ctx, now.AddDate(0, 0, longest))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list expiring records: %w", err)
+	}
+	return recs, nil
+}
+
+// reminder builds the reminder for a record, choosing the tightest lead time
+// the record is already within; expired records use a lead time of 0
+func (e *LeadTimeEngine) reminder(rec records.Record, now time.Time) Reminder {
+	daysLeft := DaysBetween(now, *rec.ExpiresAt)
+
+	lead := 0
+	if daysLeft >= 0 {
+		lead = e.leadDays[len(e.leadDays)-1]
+		for _, days := range e.leadDays {
+			if daysLeft <= days {
+				lead = days
+				break
+			}
+		}
+	}
+
+	return Reminder{
+		RecordID:   rec.ID,
+		RecordType: rec.Type,
+		Title:      title(rec),
+		ExpiresAt:  *rec.ExpiresAt,
+		DaysLeft:   daysLeft,
+		LeadDays:   lead,
+	}
+}
+
+func (e *LeadTimeEngine) markReminded(ctx context.Context, rec records.Record, lead int, now time.Time) error {
+	if rec.Metadata == nil {
+		rec.Metadata = map[string]interface{}{}
+	}
+	rec.Metadata[metaReminderLeadDays] = lead
+	rec.UpdatedAt = now
+	if err := e.storage.Update(ctx, rec); err != nil {
+		return fmt.Errorf("failed to mark record %s as reminded: %w", rec.ID, err)
+	}
+	return nil
+}
+
+// alreadyReminded reports whether a reminder at this or a tighter lead time was sent
+func alreadyReminded(rec records.Record, lead int) bool {
+	// Metadata numbers come back from JSON as float64
+	sent, ok := rec.Metadata[metaReminderLeadDays].(float64)
+	if !ok {
+		if sentInt, isInt := rec.Metadata[metaReminderLeadDays].(int); isInt {
+			sent, ok = float64(sentInt), true
+		}
+	}
+	return ok && int(sent) <= lead
+}
+
+// DaysBetween counts calendar days from now until the date, negative once it
+// has passed. Expiries, service due dates and charges are all counted with it.
+func DaysBetween(now, date time.Time) int {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	day := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+	return int(day.Sub(today).Hours() / 24)
+}
+
+// title names the document, preferring the extracted document type
+func title(rec records.Record) string {
+	if documentType, ok := rec.Metadata["document_type"].(string); ok && documentType != "" {
+		return documentType
+	}
+	return string(rec.Type)
+}
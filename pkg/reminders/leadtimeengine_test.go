@@ -0,0 +1,112 @@
+package reminders_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/kazemisoroush/assistant/pkg/records"
+	storagemocks "github.com/kazemisoroush/assistant/pkg/records/storage/mocks"
+	"github.com/kazemisoroush/assistant/pkg/reminders"
+	"github.com/kazemisoroush/assistant/pkg/reminders/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+var now = time.Date(2026, time.March, 1, 9, 0, 0, 0, time.UTC)
+
+func expiringRecord(id string, days int, metadata map[string]interface{}) records.Record {
+	expiresAt := now.AddDate(0, 0, days)
+	return records.Record{ID: id, Type: records.RecordTypeVisa, Metadata: metadata, ExpiresAt: &expiresAt}
+}
+
+func TestLeadTimeEngine_Upcoming(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	storage := storagemocks.NewMockStorage(ctrl)
+	storage.EXPECT().ListExpiring(gomock.Any(), now.AddDate(0, 0, 30)).Return([]records.Record{
+		expiringRecord("expired", -2, nil),
+		expiringRecord("visa", 5, map[string]interface{}{"document_type": "work visa"}),
+		expiringRecord("policy", 20, nil),
+	}, nil)
+	engine := reminders.NewLeadTimeEngine(storage, []int{30, 7})
+
+	// Act
+	upcoming, err := engine.Upcoming(context.Background(), now)
+
+	// Assert
+	require.NoError(t, err, "Upcoming() error should be nil")
+	require.Len(t, upcoming, 3, "Upcoming() should return a reminder per expiring record")
+	assert.True(t, upcoming[0].Expired(), "Upcoming() should include expired records")
+	assert.Equal(t, 0, upcoming[0].LeadDays, "Upcoming() should use a lead time of 0 for expired records")
+	assert.Equal(t, "work visa", upcoming[1].Title, "Upcoming() should title reminders by document type")
+	assert.Equal(t, 5, upcoming[1].DaysLeft, "Upcoming() should count the days left")
+	assert.Equal(t, 7, upcoming[1].LeadDays, "Upcoming() should pick the tightest lead time")
+	assert.Equal(t, 30, upcoming[2].LeadDays, "Upcoming() should pick the lead time the record is within")
+}
+
+func TestLeadTimeEngine_Run_SendsDueRemindersOnce(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	storage := storagemocks.NewMockStorage(ctrl)
+	storage.EXPECT().ListExpiring(gomock.Any(), gomock.Any()).Return([]records.Record{
+		expiringRecord("reminded", 20, map[string]interface{}{"reminder_lead_days": float64(30)}),
+		expiringRecord("due", 5, map[string]interface{}{"reminder_lead_days": float64(30)}),
+	}, nil)
+	storage.EXPECT().Update(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, rec records.Record) error {
+		assert.Equal(t, "due", rec.ID, "Run() should only mark the reminded record")
+		assert.Equal(t, 7, rec.Metadata["reminder_lead_days"], "Run() should record the lead time reminded about")
+		return nil
+	})
+	notifier := mocks.NewMockNotifier(ctrl)
+	notifier.EXPECT().Notify(gomock.Any(), gomock.Len(1)).Return(nil)
+	engine := reminders.NewLeadTimeEngine(storage, []int{30, 7}, notifier)
+
+	// Act
+	due, err := engine.Run(context.Background(), now)
+
+	// Assert
+	require.NoError(t, err, "Run() error should be nil")
+	require.Len(t, due, 1, "Run() should skip records already reminded at this lead time")
+	assert.Equal(t, "due", due[0].RecordID, "Run() should return the due reminder")
+}
+
+func TestLeadTimeEngine_Run_NotifierFailureKeepsRemindersDue(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	storage := storagemocks.NewMockStorage(ctrl)
+	storage.EXPECT().ListExpiring(gomock.Any(), gomock.Any()).Return([]records.Record{expiringRecord("due", 5, nil)}, nil)
+	storage.EXPECT().Update(gomock.Any(), gomock.Any()).Times(0)
+	notifier := mocks.NewMockNotifier(ctrl)
+	notifier.EXPECT().Notify(gomock.Any(), gomock.Any()).Return(errors.New("smtp unavailable"))
+	engine := reminders.NewLeadTimeEngine(storage, nil, notifier)
+
+	// Act
+	_, err := engine.Run(context.Background(), now)
+
+	// Assert
+	assert.Error(t, err, "Run() should fail when a notifier fails")
+}
+
+func TestDaysBetween(t *testing.T) {
+	tests := []struct {
+		name string
+		date time.Time
+		want int
+	}{
+		{name: "later today", date: now.Add(10 * time.Hour), want: 0},
+		{name: "tomorrow morning", date: time.Date(2026, time.March, 2, 1, 0, 0, 0, time.UTC), want: 1},
+		{name: "passed", date: time.Date(2026, time.February, 27, 23, 0, 0, 0, time.UTC), want: -2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			days := reminders.DaysBetween(now, tt.date)
+
+			// Assert
+			assert.Equal(t, tt.want, days, "DaysBetween() should count calendar days")
+		})
+	}
+}
@@ -0,0 +1,70 @@
+// Package reminders notifies about time-bound documents before they expire.
+package reminders
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/kazemisoroush/assistant/pkg/records"
+)
+
+// DefaultLeadDays are the days before expiry at which reminders are sent
+var DefaultLeadDays = []int{90, 30, 7, 1}
+
+// Reminder represents an upcoming or past expiry of a record
+type Reminder struct {
+	RecordID   string             `json:"record_id"`
+	RecordType records.RecordType `json:"record_type"`
+	Title      string             `json:"title"`
+	ExpiresAt  time.Time          `json:"expires_at"`
+	DaysLeft   int                `json:"days_left"` // Negative once the document has expired
+	LeadDays   int                `json:"lead_days"` // The lead time that made the reminder due
+}
+
+// Expired reports whether the document has already expired
+func (r Reminder) Expired() bool {
+	return r.DaysLeft < 0
+}
+
+// String describes the reminder in a single line
+func (r Reminder) String() string {
+	expiry := r.ExpiresAt.Format(time.DateOnly)
+	switch {
+	case r.Expired():
+		return fmt.Sprintf("%s (record %s) expired on %s, %d day(s) ago", r.Title, r.RecordID, expiry, -r.DaysLeft)
+	case r.DaysLeft == 0:
+		return fmt.Sprintf("%s (record %s) expires today", r.Title, r.RecordID)
+	default:
+		return fmt.Sprintf("%s (record %s) expires on %s, in %d day(s)", r.Title, r.RecordID, expiry, r.DaysLeft)
+	}
+}
+
+// summary lists the reminders one per line
+func summary(reminders []Reminder) string {
+	var b strings.Builder
+	for _, r := range reminders {
+		b.WriteString("- " + r.String() + "\n")
+	}
+	return b.String()
+}
+
+// Engine finds expiring records and sends their reminders
+//
+//go:generate mockgen -destination=./mocks/mock_engine.go -mock_names=Engine=MockEngine -package=mocks . Engine
+type Engine interface {
+	// Upcoming returns a reminder for every record expiring within the longest lead time, including expired ones
+	Upcoming(ctx context.Context, now time.Time) ([]Reminder, error)
+
+	// Run sends the reminders that became due since the last run and returns them
+	Run(ctx context.Context, now time.Time) ([]Reminder, error)
+}
+
+// Notifier delivers reminders to the user
+//
+//go:generate mockgen -destination=./mocks/mock_notifier.go -mock_names=Notifier=MockNotifier -package=mocks . Notifier
+type Notifier interface {
+	// Notify delivers the reminders
+	Notify(ctx context.Context, reminders []Reminder) error
+}
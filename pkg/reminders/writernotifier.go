@@ -0,0 +1,30 @@
+package reminders
+
+import (
+	"context"
+	"fmt"
+	"io"
+)
+
+// WriterNotifier prints a plain-text summary of reminders, e.g. to the terminal
+type WriterNotifier struct {
+	w io.Writer
+}
+
+// NewWriterNotifier creates a notifier writing to w
+func NewWriterNotifier(w io.Writer) Notifier {
+	return &WriterNotifier{
+		w: w,
+	}
+}
+
+// Notify implements Notifier
+func (n *WriterNotifier) Notify(_ context.Context, reminders []Reminder) error {
+	if len(reminders) == 0 {
+		return nil
+	}
+	if _, err := io.WriteString(n.w, summary(reminders)); err != nil {
+		return fmt.Errorf("failed to write reminders: %w", err)
+	}
+	return nil
+}
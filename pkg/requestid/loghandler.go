@@ -0,0 +1,43 @@
+package requestid
+
+import (
+	"context"
+	"log/slog"
+)
+
+// LogKey is the attribute log records carry the request ID under
+const LogKey = "request_id"
+
+// LogHandler adds the request ID carried by the context to every log record
+// written with one of the slog Context functions
+type LogHandler struct {
+	next slog.Handler
+}
+
+// NewLogHandler wraps next so log records carry the request ID of their context
+func NewLogHandler(next slog.Handler) *LogHandler {
+	return &LogHandler{next: next}
+}
+
+// Enabled implements slog.Handler
+func (h *LogHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+// Handle implements slog.Handler
+func (h *LogHandler) Handle(ctx context.Context, record slog.Record) error {
+	if id := FromContext(ctx); id != "" {
+		record.AddAttrs(slog.String(LogKey, id))
+	}
+	return h.next.Handle(ctx, record)
+}
+
+// WithAttrs implements slog.Handler
+func (h *LogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &LogHandler{next: h.next.WithAttrs(attrs)}
+}
+
+// WithGroup implements slog.Handler
+func (h *LogHandler) WithGroup(name string) slog.Handler {
+	return &LogHandler{next: h.next.WithGroup(name)}
+}
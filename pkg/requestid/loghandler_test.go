@@ -0,0 +1,43 @@
+package requestid_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	"github.com/kazemisoroush/assistant/pkg/requestid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogHandler_Handle(t *testing.T) {
+	// Arrange
+	var buf bytes.Buffer
+	logger := slog.New(requestid.NewLogHandler(slog.NewJSONHandler(&buf, nil))).With("component", "scraper")
+	ctx := requestid.WithID(context.Background(), "abc123")
+
+	// Act
+	logger.InfoContext(ctx, "Scrape started")
+
+	// Assert
+	var line map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &line), "log line should be JSON")
+	assert.Equal(t, "abc123", line[requestid.LogKey], "Handle() should add the request ID")
+	assert.Equal(t, "scraper", line["component"], "Handle() should keep the logger's attributes")
+}
+
+func TestLogHandler_Handle_WithoutID(t *testing.T) {
+	// Arrange
+	var buf bytes.Buffer
+	logger := slog.New(requestid.NewLogHandler(slog.NewJSONHandler(&buf, nil)))
+
+	// Act
+	logger.InfoContext(context.Background(), "Scrape started")
+
+	// Assert
+	var line map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &line), "log line should be JSON")
+	assert.NotContains(t, line, requestid.LogKey, "Handle() should not add an empty request ID")
+}
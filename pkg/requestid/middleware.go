@@ -0,0 +1,19 @@
+package requestid
+
+import "net/http"
+
+// Header is the HTTP header carrying the request ID
+const Header = "X-Request-ID"
+
+// Middleware gives every request an ID, reusing the one the client sent in
+// the X-Request-ID header, and echoes it in the response
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(Header)
+		if id == "" {
+			id = New()
+		}
+		w.Header().Set(Header, id)
+		next.ServeHTTP(w, r.WithContext(WithID(r.Context(), id)))
+	})
+}
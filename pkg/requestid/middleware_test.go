@@ -0,0 +1,44 @@
+package requestid_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kazemisoroush/assistant/pkg/requestid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMiddleware(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+	}{
+		{name: "generated"},
+		{name: "from client", header: "client-id"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			var got string
+			handler := requestid.Middleware(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+				got = requestid.FromContext(r.Context())
+			}))
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/dashboard", nil)
+			if tt.header != "" {
+				req.Header.Set(requestid.Header, tt.header)
+			}
+			rec := httptest.NewRecorder()
+
+			// Act
+			handler.ServeHTTP(rec, req)
+
+			// Assert
+			assert.NotEmpty(t, got, "Middleware() should put a request ID in the context")
+			if tt.header != "" {
+				assert.Equal(t, tt.header, got, "Middleware() should reuse the client's request ID")
+			}
+			assert.Equal(t, got, rec.Header().Get(requestid.Header), "Middleware() should echo the request ID")
+		})
+	}
+}
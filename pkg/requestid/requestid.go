@@ -0,0 +1,25 @@
+// Package requestid correlates the work done for a single CLI command or API request.
+package requestid
+
+import (
+	"context"
+	"crypto/rand"
+)
+
+type contextKey struct{}
+
+// New returns a new random request ID
+func New() string {
+	return rand.Text()
+}
+
+// WithID returns a copy of ctx carrying the request ID
+func WithID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, contextKey{}, id)
+}
+
+// FromContext returns the request ID carried by ctx, or an empty string
+func FromContext(ctx context.Context) string {
+	id, _ := ctx.Value(contextKey{}).(string)
+	return id
+}
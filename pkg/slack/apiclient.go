@@ -0,0 +1,108 @@
+package slack
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// DefaultAPIURL is the base URL of the Slack Web API
+const DefaultAPIURL = "https://slack.com/api"
+
+// APIConfig represents the bot token the Web API is called with
+type APIConfig struct {
+	URL   string // Base URL of the Web API, DefaultAPIURL when empty
+	Token string // Bot token with the chat:write and files:read scopes
+}
+
+// APIClient calls the Slack Web API over HTTP
+type APIClient struct {
+	client *http.Client
+	cfg    APIConfig
+}
+
+// NewAPIClient creates a client calling the Web API with the given HTTP client
+func NewAPIClient(client *http.Client, cfg APIConfig) Client {
+	if cfg.URL == "" {
+		cfg.URL = DefaultAPIURL
+	}
+	return &APIClient{
+		client: client,
+		cfg:    cfg,
+	}
+}
+
+// postMessageRequest is the body of a chat.postMessage call
+type postMessageRequest struct {
+	Channel  string `json:"channel"`
+	ThreadTS string `json:"thread_ts,omitempty"`
+	Text     string `json:"text"`
+}
+
+// apiResponse is the envelope of every Web API response; failed calls are
+// reported with status 200 and ok set to false
+type apiResponse struct {
+	OK    bool   `json:"ok"`
+	Error string `json:"error"`
+	TS    string `json:"ts"`
+}
+
+// PostMessage implements Client
+func (c *APIClient) PostMessage(ctx context.Context, channel, threadTS, text string) (string, error) {
+	body, err := json.Marshal(postMessageRequest{Channel: channel, ThreadTS: threadTS, Text: text})
+	if err != nil {
+		return "", fmt.Errorf("failed to encode slack message: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimSuffix(c.cfg.URL, "/")+"/chat.postMessage", bytes.NewReader(body))
+	if err != nil {
+		return "", fmt.Errorf("failed to create slack request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+
+	resp, err := c.do(req)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	var result apiResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", fmt.Errorf("failed to decode slack response: %w", err)
+	}
+	if !result.OK {
+		return "", fmt.Errorf("slack rejected message: %s", result.Error)
+	}
+	return result.TS, nil
+}
+
+// Download implements Client
+func (c *APIClient) Download(ctx context.Context, url string) (io.ReadCloser, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create slack request: %w", err)
+	}
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+// do sends the request with the bot token, failing for error statuses
+func (c *APIClient) do(req *http.Request) (*http.Response, error) {
+	req.Header.Set("Authorization", "Bearer "+c.cfg.Token)
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call slack: %w", err)
+	}
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		defer func() { _ = resp.Body.Close() }()
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("%s returned status %d: %s", req.URL.Host, resp.StatusCode, string(respBody))
+	}
+	return resp, nil
+}
@@ -0,0 +1,71 @@
+package slack_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kazemisoroush/assistant/pkg/slack"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPIClient_PostMessage(t *testing.T) {
+	// Arrange
+	var got map[string]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/chat.postMessage", r.URL.Path, "PostMessage() should call chat.postMessage")
+		assert.Equal(t, "Bearer xoxb-token", r.Header.Get("Authorization"), "PostMessage() should send the bot token")
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+		_, _ = io.WriteString(w, `{"ok":true,"ts":"300.1"}`)
+	}))
+	defer server.Close()
+	client := slack.NewAPIClient(server.Client(), slack.APIConfig{URL: server.URL, Token: "xoxb-token"})
+
+	// Act
+	ts, err := client.PostMessage(context.Background(), "C1", "100.1", "hello")
+
+	// Assert
+	require.NoError(t, err, "PostMessage() should succeed")
+	assert.Equal(t, "300.1", ts, "PostMessage() should return the message timestamp")
+	assert.Equal(t, map[string]string{"channel": "C1", "thread_ts": "100.1", "text": "hello"}, got, "PostMessage() should reply in the thread")
+}
+
+func TestAPIClient_PostMessage_Rejected(t *testing.T) {
+	// Arrange
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = io.WriteString(w, `{"ok":false,"error":"not_in_channel"}`)
+	}))
+	defer server.Close()
+	client := slack.NewAPIClient(server.Client(), slack.APIConfig{URL: server.URL, Token: "xoxb-token"})
+
+	// Act
+	_, err := client.PostMessage(context.Background(), "C1", "", "hello")
+
+	// Assert
+	require.Error(t, err, "PostMessage() should fail when Slack rejects the message")
+	assert.Contains(t, err.Error(), "not_in_channel", "PostMessage() should report Slack's error")
+}
+
+func TestAPIClient_Download(t *testing.T) {
+	// Arrange
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer xoxb-token", r.Header.Get("Authorization"), "Download() should send the bot token")
+		_, _ = io.WriteString(w, "file content")
+	}))
+	defer server.Close()
+	client := slack.NewAPIClient(server.Client(), slack.APIConfig{URL: server.URL, Token: "xoxb-token"})
+
+	// Act
+	body, err := client.Download(context.Background(), server.URL+"/files/F1")
+
+	// Assert
+	require.NoError(t, err, "Download() should succeed")
+	defer func() { _ = body.Close() }()
+	data, err := io.ReadAll(body)
+	require.NoError(t, err)
+	assert.Equal(t, "file content", string(data), "Download() should return the file content")
+}
@@ -0,0 +1,302 @@
+package slack
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kazemisoroush/assistant/pkg/records"
+	"github.com/kazemisoroush/assistant/pkg/records/discovery"
+	"github.com/kazemisoroush/assistant/pkg/records/extractor"
+	"github.com/kazemisoroush/assistant/pkg/records/ingestor"
+	"github.com/kazemisoroush/assistant/pkg/requestid"
+)
+
+// Routes of the endpoints Slack calls
+const (
+	CommandsPath = "/slack/commands" // Request URL of the slash command
+	EventsPath   = "/slack/events"   // Request URL of event subscriptions
+)
+
+// Defaults applied to unset configuration
+const (
+	DefaultSearchLimit = 10
+	DefaultTimeout     = 3 * time.Minute
+)
+
+// searchSubcommand is the slash command text that searches the records
+const searchSubcommand = "search"
+
+// maxBodySize is the largest request body Slack sends
+const maxBodySize = 1 << 20
+
+// Config represents the Slack app the handler serves
+type Config struct {
+	SigningSecret string        // Verifies that requests come from Slack
+	Channel       string        // ID of the channel whose uploaded files are ingested; none when empty
+	RecordURL     string        // Base URL records are linked under; records are not linked when empty
+	SearchLimit   int           // Results of a search, DefaultSearchLimit when 0
+	Timeout       time.Duration // Time a search or upload may take once acknowledged, DefaultTimeout when 0
+}
+
+// Handler serves the slash command and event subscriptions of a Slack app.
+// Slack expects an answer within three seconds, so requests are acknowledged
+// at once and searches and uploads are processed in the background.
+type Handler struct {
+	cfg       Config
+	client    Client
+	discovery discovery.Discovery
+	extractor extractor.ContentExtractor
+	ingestor  ingestor.Ingestor
+	mux       *http.ServeMux
+	work      sync.WaitGroup
+}
+
+// NewHandler creates a handler searching with discovery and ingesting
+// uploads with the extractor and ingestor. Call Wait before releasing them.
+func NewHandler(cfg Config, client Client, discovery discovery.Discovery, extractor extractor.ContentExtractor, ingestor ingestor.Ingestor) *Handler {
+	if cfg.SearchLimit <= 0 {
+		cfg.SearchLimit = DefaultSearchLimit
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = DefaultTimeout
+	}
+	h := &Handler{
+		cfg:       cfg,
+		client:    client,
+		discovery: discovery,
+		extractor: extractor,
+		ingestor:  ingestor,
+		mux:       http.NewServeMux(),
+	}
+	h.mux.HandleFunc(CommandsPath, h.serveCommand)
+	h.mux.HandleFunc(EventsPath, h.serveEvent)
+	return h
+}
+
+// ServeHTTP routes requests to the slash command and event endpoints
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mux.ServeHTTP(w, r)
+}
+
+// Wait blocks until the searches and uploads in progress are answered
+func (h *Handler) Wait() {
+	h.work.Wait()
+}
+
+// commandResponse is the immediate answer to a slash command
+type commandResponse struct {
+	ResponseType string `json:"response_type"`
+	Text         string `json:"text"`
+}
+
+// serveCommand acknowledges "/assistant search <query>" and posts the results in a thread
+func (h *Handler) serveCommand(w http.ResponseWriter, r *http.Request) {
+	body, ok := h.readSigned(w, r)
+	if !ok {
+		return
+	}
+	form, err := url.ParseQuery(string(body))
+	if err != nil {
+		http.Error(w, "invalid command", http.StatusBadRequest)
+		return
+	}
+
+	subcommand, query, _ := strings.Cut(strings.TrimSpace(form.Get("text")), " ")
+	query = strings.TrimSpace(query)
+	if subcommand != searchSubcommand || query == "" {
+		writeJSON(w, commandResponse{ResponseType: "ephemeral", Text: fmt.Sprintf("Usage: %s %s <query>", form.Get("command"), searchSubcommand)})
+		return
+	}
+
+	channel, user := form.Get("channel_id"), form.Get("user_id")
+	h.background(r.Context(), func(ctx context.Context) { h.search(ctx, channel, user, query) })
+	writeJSON(w, commandResponse{ResponseType: "ephemeral", Text: fmt.Sprintf("Searching for %q…", query)})
+}
+
+// search posts the query to the channel and its results in the query's thread
+func (h *Handler) search(ctx context.Context, channel, user, query string) {
+	ts, err := h.client.PostMessage(ctx, channel, "", fmt.Sprintf("<@%s> searched for: %s", user, query))
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to post slack search", "channel", channel, "error", err)
+		return
+	}
+
+	text := "No records found."
+	resp, err := h.discovery.Discover(ctx, discovery.DiscoverRequest{Prompt: query, Limit: h.cfg.SearchLimit})
+	switch {
+	case err != nil:
+		slog.ErrorContext(ctx, "Slack search failed", "error", err)
+		text = fmt.Sprintf("Search failed: %v", err)
+	case len(resp.Hits) > 0:
+		lines := make([]string, 0, len(resp.Hits))
+		for _, hit := range resp.Hits {
+			lines = append(lines, "• "+h.summary(hit.RecordID, hit.Description))
+		}
+		text = strings.Join(lines, "\n")
+	}
+	h.reply(ctx, channel, ts, text)
+}
+
+// eventEnvelope is the body of an event subscription request
+type eventEnvelope struct {
+	Type      string       `json:"type"`
+	Challenge string       `json:"challenge"`
+	Event     messageEvent `json:"event"`
+}
+
+// messageEvent is a message posted to a channel the app is in
+type messageEvent struct {
+	Type    string `json:"type"`
+	Subtype string `json:"subtype"`
+	Channel string `json:"channel"`
+	TS      string `json:"ts"`
+	BotID   string `json:"bot_id"`
+	Files   []File `json:"files"`
+}
+
+// serveEvent answers the URL verification challenge and ingests the files
+// shared in the designated channel
+func (h *Handler) serveEvent(w http.ResponseWriter, r *http.Request) {
+	body, ok := h.readSigned(w, r)
+	if !ok {
+		return
+	}
+	var envelope eventEnvelope
+	if err := json.Unmarshal(body, &envelope); err != nil {
+		http.Error(w, "invalid event", http.StatusBadRequest)
+		return
+	}
+
+	if envelope.Type == "url_verification" {
+		writeJSON(w, map[string]string{"challenge": envelope.Challenge})
+		return
+	}
+
+	// Slack retries events it considers unacknowledged; the first delivery is already being processed
+	event := envelope.Event
+	if envelope.Type == "event_callback" && r.Header.Get("X-Slack-Retry-Num") == "" && h.ingests(event) {
+		h.background(r.Context(), func(ctx context.Context) { h.ingestFiles(ctx, event) })
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// ingests reports whether the event shares files in the designated channel
+func (h *Handler) ingests(event messageEvent) bool {
+	return h.cfg.Channel != "" && event.Channel == h.cfg.Channel &&
+		event.Type == "message" && event.Subtype == "file_share" &&
+		event.BotID == "" && len(event.Files) > 0
+}
+
+// ingestFiles ingests every shared file and replies with the resulting records in the message's thread
+func (h *Handler) ingestFiles(ctx context.Context, event messageEvent) {
+	lines := make([]string, 0, len(event.Files)+1)
+	for _, file := range event.Files {
+		rec, err := h.ingestFile(ctx, file)
+		if err != nil {
+			slog.ErrorContext(ctx, "Failed to ingest slack file", "file", file.Name, "error", err)
+			lines = append(lines, fmt.Sprintf("Failed to ingest %s: %v", file.Name, err))
+			continue
+		}
+		lines = append(lines, "Ingested "+file.Name+": "+h.summary(rec.ID, rec.Description()))
+	}
+
+	// Ingested records are indexed in batches; flush so they can be searched right away
+	if err := h.ingestor.Flush(ctx); err != nil {
+		slog.ErrorContext(ctx, "Failed to index slack files", "error", err)
+		lines = append(lines, fmt.Sprintf("The records are stored but not searchable yet: %v", err))
+	}
+	h.reply(ctx, event.Channel, event.TS, strings.Join(lines, "\n"))
+}
+
+// ingestFile downloads a shared file, extracts its record and ingests it
+func (h *Handler) ingestFile(ctx context.Context, file File) (records.Record, error) {
+	body, err := h.client.Download(ctx, file.DownloadURL)
+	if err != nil {
+		return records.Record{}, err
+	}
+	defer func() { _ = body.Close() }()
+
+	input, err := extractor.ReaderInput(body, file.MediaType)
+	if err != nil {
+		return records.Record{}, err
+	}
+	rec, err := h.extractor.Extract(ctx, input)
+	if err != nil {
+		return records.Record{}, err
+	}
+	if err := h.ingestor.Ingest(ctx, rec); err != nil {
+		return records.Record{}, err
+	}
+	return rec, nil
+}
+
+// summary describes a record in a message, linking it when a record URL is configured
+func (h *Handler) summary(id, description string) string {
+	link := "`" + id + "`"
+	if h.cfg.RecordURL != "" {
+		link = fmt.Sprintf("<%s/%s|%s>", strings.TrimSuffix(h.cfg.RecordURL, "/"), url.PathEscape(id), id)
+	}
+	if description == "" {
+		return link
+	}
+	return link + " " + description
+}
+
+// reply posts text in the thread of the message at ts
+func (h *Handler) reply(ctx context.Context, channel, ts, text string) {
+	if _, err := h.client.PostMessage(ctx, channel, ts, text); err != nil {
+		slog.ErrorContext(ctx, "Failed to post slack reply", "channel", channel, "error", err)
+	}
+}
+
+// background runs work after the request is acknowledged, with its own
+// timeout. The work keeps the request's ID, or gets its own without one.
+func (h *Handler) background(reqCtx context.Context, work func(ctx context.Context)) {
+	id := requestid.FromContext(reqCtx)
+	if id == "" {
+		id = requestid.New()
+	}
+	h.work.Add(1)
+	go func() {
+		defer h.work.Done()
+		ctx, cancel := context.WithTimeout(requestid.WithID(context.Background(), id), h.cfg.Timeout)
+		defer cancel()
+		work(ctx)
+	}()
+}
+
+// readSigned reads the body of a POST request signed by Slack, answering
+// other requests with an error
+func (h *Handler) readSigned(w http.ResponseWriter, r *http.Request) ([]byte, bool) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return nil, false
+	}
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxBodySize))
+	if err != nil {
+		http.Error(w, "failed to read request", http.StatusBadRequest)
+		return nil, false
+	}
+	if err := verify(h.cfg.SigningSecret, r.Header, body, time.Now()); err != nil {
+		slog.WarnContext(r.Context(), "Rejected slack request", "path", r.URL.Path, "error", err)
+		http.Error(w, "invalid signature", http.StatusUnauthorized)
+		return nil, false
+	}
+	return body, true
+}
+
+// writeJSON writes v as the JSON response
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		slog.Warn("Failed to write slack response", "error", err)
+	}
+}
@@ -0,0 +1,195 @@
+package slack_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kazemisoroush/assistant/pkg/records"
+	"github.com/kazemisoroush/assistant/pkg/records/discovery"
+	discoverymocks "github.com/kazemisoroush/assistant/pkg/records/discovery/mocks"
+	"github.com/kazemisoroush/assistant/pkg/records/extractor"
+	extractormocks "github.com/kazemisoroush/assistant/pkg/records/extractor/mocks"
+	ingestormocks "github.com/kazemisoroush/assistant/pkg/records/ingestor/mocks"
+	"github.com/kazemisoroush/assistant/pkg/slack"
+	"github.com/kazemisoroush/assistant/pkg/slack/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+const testSecret = "signing-secret"
+
+// signedRequest returns a POST request signed with testSecret at the given time
+func signedRequest(path, body string, at time.Time) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+	timestamp := strconv.FormatInt(at.Unix(), 10)
+	req.Header.Set("X-Slack-Request-Timestamp", timestamp)
+	req.Header.Set("X-Slack-Signature", slack.Sign(testSecret, timestamp, []byte(body)))
+	return req
+}
+
+// handlerMocks holds the services a test handler is built from
+type handlerMocks struct {
+	client    *mocks.MockClient
+	discovery *discoverymocks.MockDiscovery
+	extractor *extractormocks.MockContentExtractor
+	ingestor  *ingestormocks.MockService
+}
+
+func newHandler(t *testing.T) (*slack.Handler, handlerMocks) {
+	ctrl := gomock.NewController(t)
+	m := handlerMocks{
+		client:    mocks.NewMockClient(ctrl),
+		discovery: discoverymocks.NewMockDiscovery(ctrl),
+		extractor: extractormocks.NewMockContentExtractor(ctrl),
+		ingestor:  ingestormocks.NewMockService(ctrl),
+	}
+	cfg := slack.Config{
+		SigningSecret: testSecret,
+		Channel:       "C-UPLOADS",
+		RecordURL:     "https://vault.example.com/records/",
+	}
+	return slack.NewHandler(cfg, m.client, m.discovery, m.extractor, m.ingestor), m
+}
+
+func TestHandler_Command_Search(t *testing.T) {
+	// Arrange
+	handler, m := newHandler(t)
+	m.client.EXPECT().PostMessage(gomock.Any(), "C1", "", "<@U1> searched for: car insurance").Return("100.1", nil)
+	m.discovery.EXPECT().Discover(gomock.Any(), discovery.DiscoverRequest{Prompt: "car insurance", Limit: slack.DefaultSearchLimit}).
+		Return(discovery.DiscoverResponse{Hits: []discovery.Hit{{RecordID: "rec-1", Description: "Car insurance policy"}}}, nil)
+	m.client.EXPECT().PostMessage(gomock.Any(), "C1", "100.1", "• <https://vault.example.com/records/rec-1|rec-1> Car insurance policy").Return("100.2", nil)
+	form := url.Values{"command": {"/assistant"}, "text": {"search car insurance"}, "channel_id": {"C1"}, "user_id": {"U1"}}
+	rec := httptest.NewRecorder()
+
+	// Act
+	handler.ServeHTTP(rec, signedRequest(slack.CommandsPath, form.Encode(), time.Now()))
+	handler.Wait()
+
+	// Assert
+	assert.Equal(t, http.StatusOK, rec.Code, "ServeHTTP() should acknowledge the command")
+	assert.Contains(t, rec.Body.String(), "Searching for", "ServeHTTP() should answer the user at once")
+}
+
+func TestHandler_Command_Usage(t *testing.T) {
+	// Arrange
+	handler, _ := newHandler(t)
+	form := url.Values{"command": {"/assistant"}, "text": {"find receipts"}}
+	rec := httptest.NewRecorder()
+
+	// Act
+	handler.ServeHTTP(rec, signedRequest(slack.CommandsPath, form.Encode(), time.Now()))
+	handler.Wait()
+
+	// Assert
+	require.Equal(t, http.StatusOK, rec.Code, "ServeHTTP() should answer unknown subcommands")
+	var resp map[string]string
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	assert.Equal(t, "Usage: /assistant search <query>", resp["text"], "ServeHTTP() should explain the command")
+}
+
+func TestHandler_RejectsInvalidSignatures(t *testing.T) {
+	tests := []struct {
+		name string
+		req  *http.Request
+	}{
+		{name: "stale", req: signedRequest(slack.CommandsPath, "text=search+x", time.Now().Add(-10*time.Minute))},
+		{name: "tampered", req: func() *http.Request {
+			req := signedRequest(slack.EventsPath, `{"type":"url_verification"}`, time.Now())
+			req.Body = io.NopCloser(strings.NewReader(`{"type":"event_callback"}`))
+			return req
+		}()},
+		{name: "unsigned", req: httptest.NewRequest(http.MethodPost, slack.EventsPath, strings.NewReader("{}"))},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			handler, _ := newHandler(t)
+			rec := httptest.NewRecorder()
+
+			// Act
+			handler.ServeHTTP(rec, tt.req)
+
+			// Assert
+			assert.Equal(t, http.StatusUnauthorized, rec.Code, "ServeHTTP() should reject requests not signed by Slack")
+		})
+	}
+}
+
+func TestHandler_Event_URLVerification(t *testing.T) {
+	// Arrange
+	handler, _ := newHandler(t)
+	rec := httptest.NewRecorder()
+
+	// Act
+	handler.ServeHTTP(rec, signedRequest(slack.EventsPath, `{"type":"url_verification","challenge":"abc123"}`, time.Now()))
+
+	// Assert
+	require.Equal(t, http.StatusOK, rec.Code, "ServeHTTP() should answer the challenge")
+	assert.JSONEq(t, `{"challenge":"abc123"}`, rec.Body.String(), "ServeHTTP() should echo the challenge")
+}
+
+func TestHandler_Event_IngestsUploads(t *testing.T) {
+	// Arrange
+	handler, m := newHandler(t)
+	m.client.EXPECT().Download(gomock.Any(), "https://files.slack.com/receipt.txt").
+		Return(io.NopCloser(strings.NewReader("Coffee 4.50 EUR")), nil)
+	m.extractor.EXPECT().Extract(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, input extractor.Input) (records.Record, error) {
+		data, err := input.ReadAll()
+		require.NoError(t, err)
+		assert.Equal(t, "Coffee 4.50 EUR", string(data), "Extract() should receive the uploaded file")
+		return records.Record{ID: "rec-1", Type: records.RecordTypeReceipt, Metadata: map[string]interface{}{records.MetaDescription: "Coffee receipt"}}, nil
+	})
+	m.ingestor.EXPECT().Ingest(gomock.Any(), gomock.Any()).Return(nil)
+	m.ingestor.EXPECT().Flush(gomock.Any()).Return(nil)
+	m.client.EXPECT().PostMessage(gomock.Any(), "C-UPLOADS", "200.1", "Ingested receipt.txt: <https://vault.example.com/records/rec-1|rec-1> Coffee receipt").Return("200.2", nil)
+	body := `{"type":"event_callback","event":{"type":"message","subtype":"file_share","channel":"C-UPLOADS","ts":"200.1",
+		"files":[{"id":"F1","name":"receipt.txt","mimetype":"text/plain","url_private_download":"https://files.slack.com/receipt.txt"}]}}`
+	rec := httptest.NewRecorder()
+
+	// Act
+	handler.ServeHTTP(rec, signedRequest(slack.EventsPath, body, time.Now()))
+	handler.Wait()
+
+	// Assert
+	assert.Equal(t, http.StatusOK, rec.Code, "ServeHTTP() should acknowledge the event")
+}
+
+func TestHandler_Event_IgnoresOtherMessages(t *testing.T) {
+	tests := []struct {
+		name  string
+		body  string
+		retry bool
+	}{
+		{name: "other channel", body: `{"type":"event_callback","event":{"type":"message","subtype":"file_share","channel":"C-OTHER","files":[{"id":"F1"}]}}`},
+		{name: "no files", body: `{"type":"event_callback","event":{"type":"message","channel":"C-UPLOADS","text":"hello"}}`},
+		{name: "bot upload", body: `{"type":"event_callback","event":{"type":"message","subtype":"file_share","channel":"C-UPLOADS","bot_id":"B1","files":[{"id":"F1"}]}}`},
+		{name: "retry", body: `{"type":"event_callback","event":{"type":"message","subtype":"file_share","channel":"C-UPLOADS","files":[{"id":"F1"}]}}`, retry: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			handler, _ := newHandler(t)
+			req := signedRequest(slack.EventsPath, tt.body, time.Now())
+			if tt.retry {
+				req.Header.Set("X-Slack-Retry-Num", "1")
+			}
+			rec := httptest.NewRecorder()
+
+			// Act
+			handler.ServeHTTP(rec, req)
+			handler.Wait()
+
+			// Assert
+			assert.Equal(t, http.StatusOK, rec.Code, "ServeHTTP() should acknowledge events it ignores")
+		})
+	}
+}
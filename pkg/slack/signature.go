@@ -0,0 +1,41 @@
+package slack
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// maxRequestAge is how old a signed request may be before it is rejected as a replay
+const maxRequestAge = 5 * time.Minute
+
+// ErrInvalidSignature is returned for requests not signed with the app's signing secret
+var ErrInvalidSignature = errors.New("invalid slack request signature")
+
+// Sign returns the signature Slack sends with a request body at the given timestamp
+func Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	_, _ = mac.Write([]byte("v0:" + timestamp + ":"))
+	_, _ = mac.Write(body)
+	return "v0=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// verify checks that the request was signed by Slack with secret recently
+func verify(secret string, header http.Header, body []byte, now time.Time) error {
+	timestamp := header.Get("X-Slack-Request-Timestamp")
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+	if age := now.Sub(time.Unix(seconds, 0)); age > maxRequestAge || age < -maxRequestAge {
+		return ErrInvalidSignature
+	}
+	if !hmac.Equal([]byte(Sign(secret, timestamp, body)), []byte(header.Get("X-Slack-Signature"))) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
@@ -0,0 +1,29 @@
+// Package slack integrates the assistant with a Slack app: the /assistant
+// slash command searches the records, and files uploaded to a designated
+// channel are ingested. Responses are posted back as threaded messages.
+package slack
+
+import (
+	"context"
+	"io"
+)
+
+// Client calls the Slack Web API on behalf of the app's bot user
+//
+//go:generate mockgen -destination=./mocks/mock_client.go -mock_names=Client=MockClient -package=mocks . Client
+type Client interface {
+	// PostMessage posts text to a channel, as a reply in the thread of
+	// threadTS unless it is empty, and returns the timestamp of the message
+	PostMessage(ctx context.Context, channel, threadTS, text string) (string, error)
+
+	// Download opens a private file shared with the app; callers close it
+	Download(ctx context.Context, url string) (io.ReadCloser, error)
+}
+
+// File represents a file shared in a message
+type File struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	MediaType   string `json:"mimetype"`
+	DownloadURL string `json:"url_private_download"`
+}
@@ -0,0 +1,105 @@
+// Package sqlite opens the SQLite database the stores of the assistant share
+// with the records. The database is opened once per process and handed to
+// every store, so their writes share one connection.
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	// Registers the sqlite3 driver
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// busyTimeout is how long a write waits for the lock held by another
+// process, such as the watch daemon writing while the CLI runs
+const busyTimeout = 5 * time.Second
+
+// memoryDatabase is the path of a database kept in memory
+const memoryDatabase = ":memory:"
+
+// DB is a database shared by the stores of the process. Its single writer
+// connection serves the writes of every store, so they never contend with
+// each other, and the reads of stores that do not read often; stores reading
+// often read through its pool of reader connections instead.
+type DB struct {
+	*sql.DB         // Single writer connection
+	readers *sql.DB // Pool of reader connections
+}
+
+// Open opens the database at the given path, creating its directory. The
+// database is switched to WAL mode, letting readers run while a write is in
+// progress. Foreign keys are enabled and the busy timeout is set on every
+// connection. Transactions on the writer take the write lock as they begin,
+// so a transaction reading before it writes waits for the lock held by
+// another process instead of failing to upgrade its read lock.
+func Open(dbPath string) (*DB, error) {
+	if err := os.MkdirAll(filepath.Dir(dbPath), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create database directory: %w", err)
+	}
+
+	writer, err := openPool(dbPath, "&_txlock=immediate")
+	if err != nil {
+		return nil, err
+	}
+	writer.SetMaxOpenConns(1)
+	if _, err := writer.Exec("PRAGMA journal_mode = WAL"); err != nil {
+		_ = writer.Close()
+		return nil, fmt.Errorf("failed to enable WAL mode: %w", err)
+	}
+
+	// Each connection to an in-memory database is a database of its own
+	if dbPath == memoryDatabase {
+		return &DB{DB: writer, readers: writer}, nil
+	}
+	readers, err := openPool(dbPath, "")
+	if err != nil {
+		_ = writer.Close()
+		return nil, err
+	}
+	return &DB{DB: writer, readers: readers}, nil
+}
+
+// openPool opens a pool of connections to the database, with options
+// appended to its DSN
+func openPool(dbPath, options string) (*sql.DB, error) {
+	dsn := fmt.Sprintf("%s?_foreign_keys=on&_busy_timeout=%d%s", dbPath, busyTimeout.Milliseconds(), options)
+	db, err := sql.Open("sqlite3", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+	return db, nil
+}
+
+// Readers returns the pool of reader connections, for reads running next to
+// the writes of the other stores
+func (d *DB) Readers() *sql.DB {
+	return d.readers
+}
+
+// Transact runs fn in a transaction on the writer connection, committed once
+// fn succeeds and rolled back otherwise
+func (d *DB) Transact(ctx context.Context, fn func(*sql.Tx) error) error {
+	tx, err := d.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	if err := fn(tx); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// Close closes the writer and reader connections
+func (d *DB) Close() error {
+	if d.readers == d.DB {
+		return d.DB.Close()
+	}
+	return errors.Join(d.readers.Close(), d.DB.Close())
+}
@@ -0,0 +1,120 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpen_ConcurrentWrites(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	db, err := Open(filepath.Join(t.TempDir(), "data", "assistant.db"))
+	require.NoError(t, err, "Open() error should be nil")
+	defer func() { _ = db.Close() }()
+	_, err = db.ExecContext(ctx, `CREATE TABLE writes (id TEXT PRIMARY KEY)`)
+	require.NoError(t, err, "ExecContext() error should be nil")
+
+	// Act
+	var wg sync.WaitGroup
+	errs := make(chan error, 100)
+	for i := range 100 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if i%2 == 1 {
+				errs <- db.Transact(ctx, func(tx *sql.Tx) error {
+					_, err := tx.ExecContext(ctx, `INSERT INTO writes (id) VALUES (?)`, fmt.Sprint(i))
+					return err
+				})
+				return
+			}
+			_, err := db.ExecContext(ctx, `INSERT INTO writes (id) VALUES (?)`, fmt.Sprint(i))
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	// Assert
+	for err := range errs {
+		assert.NoError(t, err, "writes should wait for each other on the writer connection")
+	}
+	var count int
+	require.NoError(t, db.Readers().QueryRowContext(ctx, `SELECT COUNT(*) FROM writes`).Scan(&count), "failed to count writes")
+	assert.Equal(t, 100, count, "every write should be stored")
+}
+
+func TestOpen_OtherProcess(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	dbPath := filepath.Join(t.TempDir(), "assistant.db")
+	db, err := Open(dbPath)
+	require.NoError(t, err, "Open() error should be nil")
+	defer func() { _ = db.Close() }()
+	other, err := Open(dbPath)
+	require.NoError(t, err, "Open() error should be nil")
+	defer func() { _ = other.Close() }()
+	_, err = db.ExecContext(ctx, `CREATE TABLE writes (id TEXT PRIMARY KEY)`)
+	require.NoError(t, err, "ExecContext() error should be nil")
+
+	// Act
+	var wg sync.WaitGroup
+	errs := make(chan error, 100)
+	for i := range 100 {
+		writer := db
+		if i%2 == 1 {
+			writer = other
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- writer.Transact(ctx, func(tx *sql.Tx) error {
+				var count int
+				if err := tx.QueryRowContext(ctx, `SELECT COUNT(*) FROM writes`).Scan(&count); err != nil {
+					return err
+				}
+				_, err := tx.ExecContext(ctx, `INSERT INTO writes (id) VALUES (?)`, fmt.Sprint(i))
+				return err
+			})
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	// Assert
+	for err := range errs {
+		assert.NoError(t, err, "transactions reading before they write should wait for the lock held by another process")
+	}
+}
+
+func TestDB_Transact_RollsBack(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	db, err := Open(memoryDatabase)
+	require.NoError(t, err, "Open() error should be nil")
+	defer func() { _ = db.Close() }()
+	_, err = db.ExecContext(ctx, `CREATE TABLE writes (id TEXT PRIMARY KEY)`)
+	require.NoError(t, err, "ExecContext() error should be nil")
+
+	// Act
+	err = db.Transact(ctx, func(tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx, `INSERT INTO writes (id) VALUES ('a')`); err != nil {
+			return err
+		}
+		return errors.New("disk full")
+	})
+
+	// Assert
+	assert.EqualError(t, err, "disk full", "Transact() should return the error of fn")
+	var count int
+	require.NoError(t, db.Readers().QueryRowContext(ctx, `SELECT COUNT(*) FROM writes`).Scan(&count), "failed to count writes")
+	assert.Zero(t, count, "Transact() should roll back the writes of a failed fn")
+}
@@ -0,0 +1,74 @@
+package summaries
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
+
+	"github.com/kazemisoroush/assistant/pkg/ai"
+	"github.com/kazemisoroush/assistant/pkg/records"
+	"github.com/kazemisoroush/assistant/pkg/records/knowledgebase"
+	"github.com/kazemisoroush/assistant/pkg/records/storage"
+)
+
+// errLimitReached stops a backfill once limit records were summarized
+var errLimitReached = errors.New("limit reached")
+
+// Backfill summarizes up to limit records without a description, or all of
+// them when limit is 0, and re-indexes each so it is searched by its summary.
+// Records that may not be sent to the model or fail to summarize are skipped.
+// It returns the IDs of the records summarized.
+func Backfill(ctx context.Context, summarizer Summarizer, storage storage.Storage, vectorStorage knowledgebase.VectorStorage, limit int) ([]string, error) {
+	summarized := []string{}
+	err := storage.Each(ctx, "", func(rec records.Record) error {
+		if limit > 0 && len(summarized) >= limit {
+			return errLimitReached
+		}
+		if rec.Description() != "" {
+			return nil
+		}
+
+		description := summarize(ctx, summarizer, rec)
+		if description == "" {
+			return nil
+		}
+
+		rec = Describe(rec, description)
+		if err := storage.Update(ctx, rec); err != nil {
+			return fmt.Errorf("failed to store summary of record %s: %w", rec.ID, err)
+		}
+		if err := vectorStorage.Index(ctx, rec); err != nil {
+			return fmt.Errorf("failed to index summary of record %s: %w", rec.ID, err)
+		}
+		summarized = append(summarized, rec.ID)
+		return nil
+	})
+	if err != nil && !errors.Is(err, errLimitReached) {
+		return summarized, err
+	}
+	return summarized, nil
+}
+
+// summarize returns the summary of the record, or "" when it was not summarized
+func summarize(ctx context.Context, summarizer Summarizer, rec records.Record) string {
+	description, err := summarizer.Summarize(ctx, rec)
+	if err != nil && !errors.Is(err, ai.ErrRecordTypeNotAllowed) {
+		slog.WarnContext(ctx, "Skipping record that failed to summarize", "record_id", rec.ID, "error", err)
+	}
+	if err != nil {
+		return ""
+	}
+	return description
+}
+
+// Describe returns the record with the description set, leaving the original metadata untouched
+func Describe(rec records.Record, description string) records.Record {
+	rec.Metadata = maps.Clone(rec.Metadata)
+	if rec.Metadata == nil {
+		rec.Metadata = map[string]interface{}{}
+	}
+	rec.Metadata[records.MetaDescription] = description
+	return rec
+}
@@ -0,0 +1,82 @@
+package summaries_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kazemisoroush/assistant/pkg/ai"
+	"github.com/kazemisoroush/assistant/pkg/records"
+	kbmocks "github.com/kazemisoroush/assistant/pkg/records/knowledgebase/mocks"
+	storagemocks "github.com/kazemisoroush/assistant/pkg/records/storage/mocks"
+	"github.com/kazemisoroush/assistant/pkg/summaries"
+	"github.com/kazemisoroush/assistant/pkg/summaries/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+// eachOf returns an Each implementation passing recs to the callback
+func eachOf(recs []records.Record) func(context.Context, records.RecordType, func(records.Record) error) error {
+	return func(_ context.Context, _ records.RecordType, fn func(records.Record) error) error {
+		for _, rec := range recs {
+			if err := fn(rec); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+func TestBackfill(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	described := records.Record{ID: "described", Metadata: map[string]interface{}{records.MetaDescription: "Already summarized."}}
+	short := records.Record{ID: "short", Content: "Milk 1.29"}
+	guarded := records.Record{ID: "guarded", Type: records.RecordTypeHealthLab, Content: "Blood panel ..."}
+	lease := records.Record{ID: "lease", Type: records.RecordTypeHome, Content: "Lease agreement ..."}
+	storage := storagemocks.NewMockStorage(ctrl)
+	storage.EXPECT().Each(gomock.Any(), records.RecordType(""), gomock.Any()).DoAndReturn(eachOf([]records.Record{described, short, guarded, lease}))
+
+	summarizer := mocks.NewMockSummarizer(ctrl)
+	summarizer.EXPECT().Summarize(gomock.Any(), short).Return("", nil)
+	summarizer.EXPECT().Summarize(gomock.Any(), guarded).Return("", ai.ErrRecordTypeNotAllowed)
+	summarizer.EXPECT().Summarize(gomock.Any(), lease).Return("Lease of the Berlin flat.", nil)
+
+	vectorStorage := kbmocks.NewMockVectorStorage(ctrl)
+	storage.EXPECT().Update(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, rec records.Record) error {
+		assert.Equal(t, "Lease of the Berlin flat.", rec.Description(), "Backfill() should store the summary")
+		return nil
+	})
+	vectorStorage.EXPECT().Index(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, rec records.Record) error {
+		assert.Contains(t, rec.IndexText(), "Lease of the Berlin flat.", "Backfill() should index the record with its summary")
+		return nil
+	})
+
+	// Act
+	summarized, err := summaries.Backfill(context.Background(), summarizer, storage, vectorStorage, 0)
+
+	// Assert
+	require.NoError(t, err, "Backfill() error should be nil")
+	assert.Equal(t, []string{"lease"}, summarized, "Backfill() should only summarize records without a description")
+}
+
+func TestBackfill_Limit(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	first := records.Record{ID: "first", Content: "First document ..."}
+	second := records.Record{ID: "second", Content: "Second document ..."}
+	storage := storagemocks.NewMockStorage(ctrl)
+	storage.EXPECT().Each(gomock.Any(), records.RecordType(""), gomock.Any()).DoAndReturn(eachOf([]records.Record{first, second}))
+	summarizer := mocks.NewMockSummarizer(ctrl)
+	summarizer.EXPECT().Summarize(gomock.Any(), first).Return("The first document.", nil)
+	storage.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil)
+	vectorStorage := kbmocks.NewMockVectorStorage(ctrl)
+	vectorStorage.EXPECT().Index(gomock.Any(), gomock.Any()).Return(nil)
+
+	// Act
+	summarized, err := summaries.Backfill(context.Background(), summarizer, storage, vectorStorage, 1)
+
+	// Assert
+	require.NoError(t, err, "Backfill() error should be nil")
+	assert.Equal(t, []string{"first"}, summarized, "Backfill() should stop at the limit")
+}
@@ -0,0 +1,54 @@
+package summaries
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/kazemisoroush/assistant/pkg/ai"
+	"github.com/kazemisoroush/assistant/pkg/prompts"
+	"github.com/kazemisoroush/assistant/pkg/records"
+	"github.com/kazemisoroush/assistant/pkg/tokens"
+)
+
+// LLMSummarizer uses a language model to summarize record content. Content
+// longer than the prompt budget is truncated before it is sent.
+type LLMSummarizer struct {
+	provider  ai.Provider
+	prompts   prompts.Renderer
+	budgeter  *tokens.Budgeter
+	minLength int
+}
+
+// NewLLMSummarizer creates a new LLM summarizer skipping records with less than minLength characters of content
+func NewLLMSummarizer(provider ai.Provider, prompts prompts.Renderer, budgeter *tokens.Budgeter, minLength int) Summarizer {
+	return &LLMSummarizer{
+		provider:  provider,
+		prompts:   prompts,
+		budgeter:  budgeter,
+		minLength: minLength,
+	}
+}
+
+// Summarize implements Summarizer
+func (l *LLMSummarizer) Summarize(ctx context.Context, rec records.Record) (string, error) {
+	content := strings.TrimSpace(rec.Content)
+	if content == "" || len(content) < l.minLength {
+		return "", nil
+	}
+
+	text, _ := l.budgeter.Fit(content)
+	prompt, err := l.prompts.Render(prompts.Summarization, map[string]any{
+		"Type": strings.ReplaceAll(string(rec.Type), "_", " "),
+		"Text": text,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := l.provider.Generate(ctx, ai.Request{Prompt: prompt, Task: ai.TaskSummarization, RecordType: string(rec.Type)})
+	if err != nil {
+		return "", fmt.Errorf("failed to summarize record %s: %w", rec.ID, err)
+	}
+	return strings.TrimSpace(resp.Text), nil
+}
@@ -0,0 +1,29 @@
+// Package summaries writes short summaries of records, stored as their
+// description and searched alongside their content.
+package summaries
+
+import (
+	"context"
+
+	"github.com/kazemisoroush/assistant/pkg/records"
+)
+
+// Summarization modes
+const (
+	// ModeOff never summarizes records
+	ModeOff = "off"
+
+	// ModeIngest summarizes records as they are ingested
+	ModeIngest = "ingest"
+
+	// ModeLazy leaves records unsummarized until Backfill is run
+	ModeLazy = "lazy"
+)
+
+// Summarizer summarizes records
+//
+//go:generate mockgen -destination=./mocks/mock_summarizer.go -mock_names=Summarizer=MockSummarizer -package=mocks . Summarizer
+type Summarizer interface {
+	// Summarize returns a 2-3 sentence summary of the record, or "" when it is too short to need one
+	Summarize(ctx context.Context, rec records.Record) (string, error)
+}
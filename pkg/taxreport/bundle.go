@@ -0,0 +1,116 @@
+package taxreport
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// WriteBundle writes the report as CSV and PDF plus a JSON manifest of the
+// included records into dir, and returns the manifest
+func WriteBundle(dir string, report Report, now time.Time) (Manifest, error) {
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return Manifest{}, fmt.Errorf("failed to create report directory: %w", err)
+	}
+
+	base := fmt.Sprintf("tax-%d", report.FiscalYear)
+	csvFile := base + ".csv"
+	pdfFile := base + ".pdf"
+	manifestFile := base + "-manifest.json"
+
+	if err := writeCSV(filepath.Join(dir, csvFile), report); err != nil {
+		return Manifest{}, err
+	}
+	if err := os.WriteFile(filepath.Join(dir, pdfFile), renderPDF(pdfLines(report)), 0o600); err != nil {
+		return Manifest{}, fmt.Errorf("failed to write PDF report: %w", err)
+	}
+
+	manifest := Manifest{
+		FiscalYear:  report.FiscalYear,
+		GeneratedAt: now,
+		RecordIDs:   make([]string, 0, len(report.Items)),
+		Files:       []string{csvFile, pdfFile},
+	}
+	for _, item := range report.Items {
+		manifest.RecordIDs = append(manifest.RecordIDs, item.RecordID)
+	}
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return Manifest{}, fmt.Errorf("failed to marshal manifest: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, manifestFile), data, 0o600); err != nil {
+		return Manifest{}, fmt.Errorf("failed to write manifest: %w", err)
+	}
+	return manifest, nil
+}
+
+func writeCSV(path string, report Report) (err error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create CSV report: %w", err)
+	}
+	defer func() {
+		if closeErr := file.Close(); err == nil && closeErr != nil {
+			err = fmt.Errorf("failed to close CSV report: %w", closeErr)
+		}
+	}()
+
+	w := csv.NewWriter(file)
+	rows := [][]string{{"record_id", "type", "date", "description", "deduction", "amount", "currency"}}
+	for _, item := range report.Items {
+		amount := ""
+		if item.Deduction != "" {
+			amount = strconv.FormatFloat(item.Amount, 'f', 2, 64)
+		}
+		rows = append(rows, []string{
+			item.RecordID,
+			string(item.Type),
+			item.Date.Format(time.DateOnly),
+			item.Description,
+			item.Deduction,
+			amount,
+			item.Currency,
+		})
+	}
+	if err := w.WriteAll(rows); err != nil {
+		return fmt.Errorf("failed to write CSV report: %w", err)
+	}
+	return nil
+}
+
+// pdfLines lays out the report as text lines: items, then totals per deduction and currency
+func pdfLines(report Report) []string {
+	lines := []string{
+		fmt.Sprintf("Tax report for fiscal year %d", report.FiscalYear),
+		fmt.Sprintf("Period: %s to %s", report.From.Format(time.DateOnly), report.To.AddDate(0, 0, -1).Format(time.DateOnly)),
+		"",
+	}
+
+	totals := map[string]float64{}
+	for _, item := range report.Items {
+		line := fmt.Sprintf("%s  %-14s %s", item.Date.Format(time.DateOnly), item.Type, item.Description)
+		if item.Deduction != "" {
+			line += fmt.Sprintf("  [%s] %.2f %s", item.Deduction, item.Amount, item.Currency)
+			totals[item.Deduction+" "+item.Currency] += item.Amount
+		}
+		lines = append(lines, line)
+	}
+
+	keys := make([]string, 0, len(totals))
+	for key := range totals {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	lines = append(lines, "", "Totals by deduction:")
+	for _, key := range keys {
+		lines = append(lines, fmt.Sprintf("  %s: %.2f", key, totals[key]))
+	}
+	return lines
+}
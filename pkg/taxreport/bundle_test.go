@@ -0,0 +1,51 @@
+package taxreport_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kazemisoroush/assistant/pkg/records"
+	"github.com/kazemisoroush/assistant/pkg/taxreport"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteBundle(t *testing.T) {
+	// Arrange
+	dir := t.TempDir()
+	report := taxreport.Report{
+		FiscalYear: 2024,
+		From:       time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC),
+		To:         time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC),
+		Items: []taxreport.Item{
+			{RecordID: "r1", Type: records.RecordTypeReceipt, Description: "Pharmacy (night)", Amount: 12.5, Currency: "EUR", Deduction: "medical"},
+			{RecordID: "c1", Type: records.RecordTypeWorkContract, Description: "work_contract"},
+		},
+	}
+
+	// Act
+	manifest, err := taxreport.WriteBundle(dir, report, time.Now())
+
+	// Assert
+	require.NoError(t, err, "WriteBundle() error should be nil")
+	assert.Equal(t, []string{"r1", "c1"}, manifest.RecordIDs, "WriteBundle() should list the included records")
+
+	csvData, err := os.ReadFile(filepath.Join(dir, "tax-2024.csv"))
+	require.NoError(t, err, "WriteBundle() should write the CSV report")
+	assert.Contains(t, string(csvData), "r1,receipt,0001-01-01,Pharmacy (night),medical,12.50,EUR", "WriteBundle() should write one row per item")
+
+	pdfData, err := os.ReadFile(filepath.Join(dir, "tax-2024.pdf"))
+	require.NoError(t, err, "WriteBundle() should write the PDF report")
+	assert.True(t, strings.HasPrefix(string(pdfData), "%PDF-"), "WriteBundle() should write a PDF")
+	assert.Contains(t, string(pdfData), `Pharmacy \(night\)`, "WriteBundle() should escape PDF text")
+
+	manifestData, err := os.ReadFile(filepath.Join(dir, "tax-2024-manifest.json"))
+	require.NoError(t, err, "WriteBundle() should write the manifest")
+	var written taxreport.Manifest
+	require.NoError(t, json.Unmarshal(manifestData, &written), "WriteBundle() should write the manifest as JSON")
+	assert.Equal(t, manifest.Files, written.Files, "WriteBundle() should list the bundle files")
+}
@@ -0,0 +1,141 @@
+package taxreport
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/kazemisoroush/assistant/pkg/records"
+	"github.com/kazemisoroush/assistant/pkg/records/storage"
+)
+
+// otherDeduction is the deduction of receipts tagged deductible without a mapped category
+const otherDeduction = "other"
+
+// Config represents how records are selected for a tax report
+type Config struct {
+	FiscalYearStartMonth time.Month        // Fiscal year N ends the month before this month of year N; January for calendar years
+	Deductions           map[string]string // Receipt category to deduction, e.g. health=medical
+}
+
+// RecordGenerator builds tax reports from stored records: receipts tagged
+// deductible or in a mapped category, work contracts and tax documents
+type RecordGenerator struct {
+	storage storage.Storage
+	cfg     Config
+}
+
+// NewRecordGenerator creates a new tax report generator
+func NewRecordGenerator(storage storage.Storage, cfg Config) Generator {
+	if cfg.FiscalYearStartMonth < time.January || cfg.FiscalYearStartMonth > time.December {
+		cfg.FiscalYearStartMonth = time.January
+	}
+	return &RecordGenerator{
+		storage: storage,
+		cfg:     cfg,
+	}
+}
+
+// Generate implements Generator
+func (g *RecordGenerator) Generate(ctx context.Context, fiscalYear int) (Report, error) {
+	from, to := g.period(fiscalYear)
+	report := Report{FiscalYear: fiscalYear, From: from, To: to}
+
+	receipts, err := g.storage.List(ctx, records.RecordTypeReceipt)
+	if err != nil {
+		return Report{}, fmt.Errorf("failed to list receipts: %w", err)
+	}
+	for _, rec := range receipts {
+		item, ok := g.receiptItem(rec)
+		if ok && inPeriod(item.Date, from, to) {
+			report.Items = append(report.Items, item)
+		}
+	}
+
+	for _, recordType := range []records.RecordType{records.RecordTypeWorkContract, records.RecordTypeTax} {
+		recs, err := g.storage.List(ctx, recordType)
+		if err != nil {
+			return Report{}, fmt.Errorf("failed to list %s records: %w", recordType, err)
+		}
+		for _, rec := range recs {
+			item := documentItem(rec)
+			if inPeriod(item.Date, from, to) {
+				report.Items = append(report.Items, item)
+			}
+		}
+	}
+
+	sort.SliceStable(report.Items, func(i, j int) bool { return report.Items[i].Date.Before(report.Items[j].Date) })
+	return report, nil
+}
+
+// period returns the start and exclusive end of the fiscal year
+func (g *RecordGenerator) period(fiscalYear int) (time.Time, time.Time) {
+	to := time.Date(fiscalYear, g.cfg.FiscalYearStartMonth, 1, 0, 0, 0, 0, time.UTC)
+	if g.cfg.FiscalYearStartMonth == time.January {
+		to = to.AddDate(1, 0, 0)
+	}
+	return to.AddDate(-1, 0, 0), to
+}
+
+// receiptItem converts a deductible receipt into a report item
+func (g *RecordGenerator) receiptItem(rec records.Record) (Item, bool) {
+	var receipt records.ReceiptMetadata
+	if err := rec.DecodeMetadata(&receipt); err != nil {
+		slog.Warn("Skipping receipt with malformed metadata", "record_id", rec.ID, "error", err)
+		return Item{}, false
+	}
+	date, err := time.Parse(time.DateOnly, receipt.Date)
+	if err != nil {
+		return Item{}, false
+	}
+
+	deduction, mapped := g.cfg.Deductions[strings.ToLower(receipt.Category)]
+	if !mapped {
+		if !slices.Contains(rec.Tags, DeductibleTag) {
+			return Item{}, false
+		}
+		deduction = otherDeduction
+	}
+
+	return Item{
+		RecordID:    rec.ID,
+		Type:        rec.Type,
+		Date:        date,
+		Description: receipt.Merchant,
+		Amount:      receipt.Total,
+		Currency:    receipt.Currency,
+		Deduction:   deduction,
+	}, true
+}
+
+// documentItem converts a supporting document into a report item, dated by
+// its extracted date or when it was ingested
+func documentItem(rec records.Record) Item {
+	date := rec.CreatedAt
+	if raw, ok := rec.Metadata["date"].(string); ok {
+		if parsed, err := time.Parse(time.DateOnly, raw); err == nil {
+			date = parsed
+		}
+	}
+
+	description := string(rec.Type)
+	if documentType, ok := rec.Metadata["document_type"].(string); ok && documentType != "" {
+		description = documentType
+	}
+
+	return Item{
+		RecordID:    rec.ID,
+		Type:        rec.Type,
+		Date:        date,
+		Description: description,
+	}
+}
+
+func inPeriod(date, from, to time.Time) bool {
+	return !date.Before(from) && date.Before(to)
+}
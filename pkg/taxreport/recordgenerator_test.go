@@ -0,0 +1,62 @@
+package taxreport_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/kazemisoroush/assistant/pkg/records"
+	"github.com/kazemisoroush/assistant/pkg/records/storage/mocks"
+	"github.com/kazemisoroush/assistant/pkg/taxreport"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func receipt(id, date, category string, tags ...string) records.Record {
+	return records.Record{
+		ID:   id,
+		Type: records.RecordTypeReceipt,
+		Tags: tags,
+		Metadata: map[string]interface{}{
+			"merchant": "Shop " + id,
+			"date":     date,
+			"total":    10.0,
+			"currency": "AUD",
+			"category": category,
+		},
+	}
+}
+
+func TestRecordGenerator_Generate(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	storage := mocks.NewMockStorage(ctrl)
+	storage.EXPECT().List(gomock.Any(), records.RecordTypeReceipt).Return([]records.Record{
+		receipt("medical", "2023-08-01", "health"),
+		receipt("tagged", "2024-02-01", "office", taxreport.DeductibleTag),
+		receipt("groceries", "2023-09-01", "groceries"),
+		receipt("previous-year", "2023-06-30", "health"),
+	}, nil)
+	storage.EXPECT().List(gomock.Any(), records.RecordTypeWorkContract).Return([]records.Record{
+		{ID: "contract", Type: records.RecordTypeWorkContract, Metadata: map[string]interface{}{"date": "2023-07-15"}},
+	}, nil)
+	storage.EXPECT().List(gomock.Any(), records.RecordTypeTax).Return([]records.Record{
+		{ID: "old-assessment", Type: records.RecordTypeTax, CreatedAt: time.Date(2022, time.March, 1, 0, 0, 0, 0, time.UTC)},
+	}, nil)
+	generator := taxreport.NewRecordGenerator(storage, taxreport.Config{
+		FiscalYearStartMonth: time.July,
+		Deductions:           map[string]string{"health": "medical"},
+	})
+
+	// Act
+	report, err := generator.Generate(context.Background(), 2024)
+
+	// Assert
+	require.NoError(t, err, "Generate() error should be nil")
+	assert.Equal(t, time.Date(2023, time.July, 1, 0, 0, 0, 0, time.UTC), report.From, "Generate() should start the fiscal year in the configured month")
+	require.Len(t, report.Items, 3, "Generate() should include deductible receipts and supporting documents of the fiscal year")
+	assert.Equal(t, "contract", report.Items[0].RecordID, "Generate() should order items by date")
+	assert.Equal(t, "medical", report.Items[1].Deduction, "Generate() should map categories to deductions")
+	assert.Equal(t, "other", report.Items[2].Deduction, "Generate() should include receipts tagged deductible")
+}
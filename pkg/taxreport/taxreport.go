@@ -0,0 +1,48 @@
+// Package taxreport collects tax-relevant records for a fiscal year into a
+// bundle of documents for the accountant.
+package taxreport
+
+import (
+	"context"
+	"time"
+
+	"github.com/kazemisoroush/assistant/pkg/records"
+)
+
+// DeductibleTag marks receipts as deductible regardless of their category
+const DeductibleTag = "deductible"
+
+// Item represents one record included in a tax report
+type Item struct {
+	RecordID    string             `json:"record_id"`
+	Type        records.RecordType `json:"type"`
+	Date        time.Time          `json:"date"`
+	Description string             `json:"description"`
+	Amount      float64            `json:"amount,omitempty"`
+	Currency    string             `json:"currency,omitempty"`
+	Deduction   string             `json:"deduction,omitempty"` // Empty for supporting documents
+}
+
+// Report represents the tax-relevant records of a fiscal year
+type Report struct {
+	FiscalYear int       `json:"fiscal_year"`
+	From       time.Time `json:"from"`
+	To         time.Time `json:"to"` // Exclusive
+	Items      []Item    `json:"items"`
+}
+
+// Manifest lists the contents of a written report bundle
+type Manifest struct {
+	FiscalYear  int       `json:"fiscal_year"`
+	GeneratedAt time.Time `json:"generated_at"`
+	RecordIDs   []string  `json:"record_ids"`
+	Files       []string  `json:"files"`
+}
+
+// Generator collects the tax-relevant records of a fiscal year
+//
+//go:generate mockgen -destination=./mocks/mock_generator.go -mock_names=Generator=MockGenerator -package=mocks . Generator
+type Generator interface {
+	// Generate returns the report for the fiscal year
+	Generate(ctx context.Context, fiscalYear int) (Report, error)
+}
@@ -0,0 +1,234 @@
+package telegram
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/kazemisoroush/assistant/pkg/agent"
+	"github.com/kazemisoroush/assistant/pkg/records"
+	"github.com/kazemisoroush/assistant/pkg/records/discovery"
+	"github.com/kazemisoroush/assistant/pkg/records/storage"
+	"github.com/kazemisoroush/assistant/pkg/requestid"
+)
+
+// Defaults applied to unset configuration
+const (
+	DefaultSearchLimit = 5
+	DefaultPollTimeout = 30 * time.Second
+	DefaultTimeout     = 3 * time.Minute
+)
+
+// Commands the bot answers; any other text is a question
+const (
+	searchCommand = "/search"
+	startCommand  = "/start"
+	helpCommand   = "/help"
+)
+
+// fileButtonPrefix marks the data of buttons fetching a record's original file
+const fileButtonPrefix = "file:"
+
+// pollRetryDelay is how long polling pauses after a failed poll
+const pollRetryDelay = 5 * time.Second
+
+// usage answers /start, /help and empty searches
+const usage = "Send /search <query> to find records, or ask a question about them."
+
+// Config represents who the bot answers and how it searches
+type Config struct {
+	AllowedChats []int64       // Chats the bot answers; messages from any other chat are ignored
+	SearchLimit  int           // Results of a search, DefaultSearchLimit when 0
+	PollTimeout  time.Duration // How long a poll waits for updates, DefaultPollTimeout when 0
+	Timeout      time.Duration // Time answering an update may take, DefaultTimeout when 0
+}
+
+// Bot answers the queries sent to a Telegram bot. Updates are long-polled,
+// so the bot needs no public endpoint, and answered one at a time.
+type Bot struct {
+	cfg       Config
+	client    Client
+	discovery discovery.Discovery
+	agent     agent.Agent // nil answers questions with a search
+	storage   storage.Storage
+}
+
+// NewBot creates a bot searching with discovery and answering questions with
+// the agent, or with a search when agent is nil. Original files are read
+// from the paths records were scraped from.
+func NewBot(cfg Config, client Client, discovery discovery.Discovery, agent agent.Agent, storage storage.Storage) *Bot {
+	if cfg.SearchLimit <= 0 {
+		cfg.SearchLimit = DefaultSearchLimit
+	}
+	if cfg.PollTimeout <= 0 {
+		cfg.PollTimeout = DefaultPollTimeout
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = DefaultTimeout
+	}
+	return &Bot{
+		cfg:       cfg,
+		client:    client,
+		discovery: discovery,
+		agent:     agent,
+		storage:   storage,
+	}
+}
+
+// Run answers updates until ctx is cancelled. Failed polls are retried.
+func (b *Bot) Run(ctx context.Context) error {
+	var offset int64
+	for {
+		updates, err := b.client.GetUpdates(ctx, offset, b.cfg.PollTimeout)
+		if ctx.Err() != nil {
+			return nil
+		}
+		if err != nil {
+			slog.WarnContext(ctx, "Failed to poll telegram updates", "error", err)
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-time.After(pollRetryDelay):
+			}
+			continue
+		}
+		for _, update := range updates {
+			offset = update.ID + 1
+			b.answer(ctx, update)
+		}
+	}
+}
+
+// answer handles a single update with its own request ID and timeout
+func (b *Bot) answer(ctx context.Context, update Update) {
+	ctx, cancel := context.WithTimeout(requestid.WithID(ctx, requestid.New()), b.cfg.Timeout)
+	defer cancel()
+
+	switch {
+	case update.Message != nil && b.allowed(update.Message.Chat.ID):
+		b.answerMessage(ctx, update.Message.Chat.ID, strings.TrimSpace(update.Message.Text))
+	case update.CallbackQuery != nil && update.CallbackQuery.Message != nil && b.allowed(update.CallbackQuery.Message.Chat.ID):
+		b.answerButton(ctx, update.CallbackQuery)
+	default:
+		slog.WarnContext(ctx, "Ignored telegram update from a chat that is not allowed", "update", update.ID)
+	}
+}
+
+// allowed reports whether the bot answers the chat
+func (b *Bot) allowed(chatID int64) bool {
+	return slices.Contains(b.cfg.AllowedChats, chatID)
+}
+
+// answerMessage answers a command or a question
+func (b *Bot) answerMessage(ctx context.Context, chatID int64, text string) {
+	command, query, _ := strings.Cut(text, " ")
+	// Commands may be addressed to the bot, e.g. /search@vault_bot
+	command, _, _ = strings.Cut(command, "@")
+	query = strings.TrimSpace(query)
+
+	switch {
+	case command == searchCommand && query != "":
+		b.search(ctx, chatID, query)
+	case command == searchCommand, command == startCommand, command == helpCommand, text == "":
+		b.send(ctx, chatID, usage, nil)
+	case b.agent != nil:
+		b.ask(ctx, chatID, text)
+	default:
+		b.search(ctx, chatID, text)
+	}
+}
+
+// search sends the records matching the query, with a button per record fetching its original file
+func (b *Bot) search(ctx context.Context, chatID int64, query string) {
+	resp, err := b.discovery.Discover(ctx, discovery.DiscoverRequest{Prompt: query, Limit: b.cfg.SearchLimit})
+	if err != nil {
+		slog.ErrorContext(ctx, "Telegram search failed", "error", err)
+		b.send(ctx, chatID, fmt.Sprintf("Search failed: %v", err), nil)
+		return
+	}
+	if len(resp.Hits) == 0 {
+		b.send(ctx, chatID, "No records found.", nil)
+		return
+	}
+
+	lines := make([]string, 0, len(resp.Hits))
+	var buttons []Button
+	for i, hit := range resp.Hits {
+		lines = append(lines, fmt.Sprintf("%d. %s", i+1, describe(hit)))
+		if path, _ := hit.Meta[records.MetaSourcePath].(string); path != "" {
+			buttons = append(buttons, Button{Text: fmt.Sprintf("%d. %s", i+1, filepath.Base(path)), Data: fileButtonPrefix + hit.RecordID})
+		}
+	}
+	b.send(ctx, chatID, strings.Join(lines, "\n"), buttons)
+}
+
+// ask sends the agent's answer to a question
+func (b *Bot) ask(ctx context.Context, chatID int64, question string) {
+	answer, err := b.agent.Ask(ctx, question)
+	if err != nil {
+		slog.ErrorContext(ctx, "Telegram question failed", "error", err)
+		b.send(ctx, chatID, fmt.Sprintf("Failed to answer: %v", err), nil)
+		return
+	}
+	b.send(ctx, chatID, answer, nil)
+}
+
+// answerButton sends the original file of the record a pressed button belongs to
+func (b *Bot) answerButton(ctx context.Context, query *CallbackQuery) {
+	id, ok := strings.CutPrefix(query.Data, fileButtonPrefix)
+	if !ok {
+		b.acknowledge(ctx, query.ID, "")
+		return
+	}
+	if err := b.sendFile(ctx, query.Message.Chat.ID, id); err != nil {
+		slog.ErrorContext(ctx, "Failed to send telegram file", "record", id, "error", err)
+		b.acknowledge(ctx, query.ID, "The original file is not available")
+		return
+	}
+	b.acknowledge(ctx, query.ID, "")
+}
+
+// sendFile sends the file the record was scraped from
+func (b *Bot) sendFile(ctx context.Context, chatID int64, id string) error {
+	rec, err := b.storage.Get(ctx, id)
+	if err != nil {
+		return err
+	}
+	path := rec.SourcePath()
+	if path == "" {
+		return fmt.Errorf("record %s has no source file", id)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open source file of record %s: %w", id, err)
+	}
+	defer func() { _ = f.Close() }()
+	return b.client.SendDocument(ctx, chatID, filepath.Base(path), f)
+}
+
+// send sends a message, logging failures
+func (b *Bot) send(ctx context.Context, chatID int64, text string, buttons []Button) {
+	if err := b.client.SendMessage(ctx, chatID, text, buttons); err != nil {
+		slog.ErrorContext(ctx, "Failed to send telegram message", "chat", chatID, "error", err)
+	}
+}
+
+// acknowledge answers a pressed button, logging failures
+func (b *Bot) acknowledge(ctx context.Context, callbackID, text string) {
+	if err := b.client.AnswerCallback(ctx, callbackID, text); err != nil {
+		slog.ErrorContext(ctx, "Failed to answer telegram button", "error", err)
+	}
+}
+
+// describe summarizes a search hit in a line
+func describe(hit discovery.Hit) string {
+	if hit.Description != "" {
+		return hit.Description
+	}
+	return hit.RecordID
+}
@@ -0,0 +1,130 @@
+package telegram_test
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	agentmocks "github.com/kazemisoroush/assistant/pkg/agent/mocks"
+	"github.com/kazemisoroush/assistant/pkg/records"
+	"github.com/kazemisoroush/assistant/pkg/records/discovery"
+	discoverymocks "github.com/kazemisoroush/assistant/pkg/records/discovery/mocks"
+	storagemocks "github.com/kazemisoroush/assistant/pkg/records/storage/mocks"
+	"github.com/kazemisoroush/assistant/pkg/telegram"
+	"github.com/kazemisoroush/assistant/pkg/telegram/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+const testChat = int64(42)
+
+// botMocks holds the services a test bot is built from
+type botMocks struct {
+	client    *mocks.MockClient
+	discovery *discoverymocks.MockDiscovery
+	agent     *agentmocks.MockAgent
+	storage   *storagemocks.MockStorage
+}
+
+func newMocks(t *testing.T) botMocks {
+	ctrl := gomock.NewController(t)
+	return botMocks{
+		client:    mocks.NewMockClient(ctrl),
+		discovery: discoverymocks.NewMockDiscovery(ctrl),
+		agent:     agentmocks.NewMockAgent(ctrl),
+		storage:   storagemocks.NewMockStorage(ctrl),
+	}
+}
+
+// run answers the updates with the bot, stopping it at the next poll
+func run(t *testing.T, bot *telegram.Bot, client *mocks.MockClient, updates ...telegram.Update) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	client.EXPECT().GetUpdates(gomock.Any(), int64(0), telegram.DefaultPollTimeout).Return(updates, nil)
+	client.EXPECT().GetUpdates(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, _ int64, _ time.Duration) ([]telegram.Update, error) {
+		cancel()
+		return nil, ctx.Err()
+	})
+	require.NoError(t, bot.Run(ctx), "Run() should stop cleanly once cancelled")
+}
+
+// message returns an update carrying text sent in chat
+func message(id, chat int64, text string) telegram.Update {
+	return telegram.Update{ID: id, Message: &telegram.Message{Chat: telegram.Chat{ID: chat}, Text: text}}
+}
+
+func TestBot_Search(t *testing.T) {
+	// Arrange
+	m := newMocks(t)
+	bot := telegram.NewBot(telegram.Config{AllowedChats: []int64{testChat}}, m.client, m.discovery, m.agent, m.storage)
+	m.discovery.EXPECT().Discover(gomock.Any(), discovery.DiscoverRequest{Prompt: "car insurance", Limit: telegram.DefaultSearchLimit}).
+		Return(discovery.DiscoverResponse{Hits: []discovery.Hit{
+			{RecordID: "rec-1", Description: "Car insurance policy", Meta: map[string]any{records.MetaSourcePath: "/docs/policy.pdf"}},
+			{RecordID: "rec-2"},
+		}}, nil)
+	m.client.EXPECT().SendMessage(gomock.Any(), testChat, "1. Car insurance policy\n2. rec-2", []telegram.Button{
+		{Text: "1. policy.pdf", Data: "file:rec-1"},
+	}).Return(nil)
+
+	// Act
+	run(t, bot, m.client, message(0, testChat, "/search@vault_bot car insurance"))
+}
+
+func TestBot_Question(t *testing.T) {
+	// Arrange
+	m := newMocks(t)
+	bot := telegram.NewBot(telegram.Config{AllowedChats: []int64{testChat}}, m.client, m.discovery, m.agent, m.storage)
+	m.agent.EXPECT().Ask(gomock.Any(), "when does my passport expire?").Return("On 2030-01-01.", nil)
+	m.client.EXPECT().SendMessage(gomock.Any(), testChat, "On 2030-01-01.", gomock.Nil()).Return(nil)
+
+	// Act
+	run(t, bot, m.client, message(0, testChat, "when does my passport expire?"))
+}
+
+func TestBot_Question_WithoutAgent(t *testing.T) {
+	// Arrange
+	m := newMocks(t)
+	bot := telegram.NewBot(telegram.Config{AllowedChats: []int64{testChat}}, m.client, m.discovery, nil, m.storage)
+	m.discovery.EXPECT().Discover(gomock.Any(), gomock.Any()).Return(discovery.DiscoverResponse{}, nil)
+	m.client.EXPECT().SendMessage(gomock.Any(), testChat, "No records found.", gomock.Nil()).Return(nil)
+
+	// Act
+	run(t, bot, m.client, message(0, testChat, "passport"))
+}
+
+func TestBot_IgnoresOtherChats(t *testing.T) {
+	// Arrange
+	m := newMocks(t)
+	bot := telegram.NewBot(telegram.Config{AllowedChats: []int64{testChat}}, m.client, m.discovery, m.agent, m.storage)
+
+	// Act
+	run(t, bot, m.client, message(0, 7, "/search passport"))
+}
+
+func TestBot_FileButton(t *testing.T) {
+	// Arrange
+	m := newMocks(t)
+	path := filepath.Join(t.TempDir(), "policy.pdf")
+	require.NoError(t, os.WriteFile(path, []byte("%PDF"), 0600))
+	bot := telegram.NewBot(telegram.Config{AllowedChats: []int64{testChat}}, m.client, m.discovery, m.agent, m.storage)
+	m.storage.EXPECT().Get(gomock.Any(), "rec-1").Return(records.Record{ID: "rec-1", Metadata: map[string]interface{}{records.MetaSourcePath: path}}, nil)
+	m.client.EXPECT().SendDocument(gomock.Any(), testChat, "policy.pdf", gomock.Any()).DoAndReturn(func(_ context.Context, _ int64, _ string, content io.Reader) error {
+		data, err := io.ReadAll(content)
+		require.NoError(t, err)
+		assert.Equal(t, "%PDF", string(data), "SendDocument() should receive the original file")
+		return nil
+	})
+	m.client.EXPECT().AnswerCallback(gomock.Any(), "cb-1", "").Return(nil)
+	button := telegram.Update{ID: 0, CallbackQuery: &telegram.CallbackQuery{
+		ID:      "cb-1",
+		Message: &telegram.Message{Chat: telegram.Chat{ID: testChat}},
+		Data:    "file:rec-1",
+	}}
+
+	// Act
+	run(t, bot, m.client, button)
+}
@@ -0,0 +1,149 @@
+package telegram
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultAPIURL is the base URL of the Telegram Bot API
+const DefaultAPIURL = "https://api.telegram.org"
+
+// APIConfig represents the bot the Bot API is called as
+type APIConfig struct {
+	URL   string // Base URL of the Bot API, DefaultAPIURL when empty
+	Token string
+}
+
+// BotAPIClient calls the Telegram Bot API over HTTP
+type BotAPIClient struct {
+	client *http.Client
+	cfg    APIConfig
+}
+
+// NewBotAPIClient creates a client calling the Bot API with the given HTTP client
+func NewBotAPIClient(client *http.Client, cfg APIConfig) Client {
+	if cfg.URL == "" {
+		cfg.URL = DefaultAPIURL
+	}
+	return &BotAPIClient{
+		client: client,
+		cfg:    cfg,
+	}
+}
+
+// apiResponse is the envelope of every Bot API response
+type apiResponse struct {
+	OK          bool            `json:"ok"`
+	Description string          `json:"description"`
+	Result      json.RawMessage `json:"result"`
+}
+
+// inlineButton is a button of an inline keyboard
+type inlineButton struct {
+	Text         string `json:"text"`
+	CallbackData string `json:"callback_data"`
+}
+
+// GetUpdates implements Client
+func (c *BotAPIClient) GetUpdates(ctx context.Context, offset int64, timeout time.Duration) ([]Update, error) {
+	var updates []Update
+	err := c.callJSON(ctx, "getUpdates", map[string]any{
+		"offset":          offset,
+		"timeout":         int(timeout.Seconds()),
+		"allowed_updates": []string{"message", "callback_query"},
+	}, &updates)
+	return updates, err
+}
+
+// SendMessage implements Client
+func (c *BotAPIClient) SendMessage(ctx context.Context, chatID int64, text string, buttons []Button) error {
+	params := map[string]any{"chat_id": chatID, "text": text}
+	if len(buttons) > 0 {
+		keyboard := make([][]inlineButton, 0, len(buttons))
+		for _, button := range buttons {
+			keyboard = append(keyboard, []inlineButton{{Text: button.Text, CallbackData: button.Data}})
+		}
+		params["reply_markup"] = map[string]any{"inline_keyboard": keyboard}
+	}
+	return c.callJSON(ctx, "sendMessage", params, nil)
+}
+
+// SendDocument implements Client
+func (c *BotAPIClient) SendDocument(ctx context.Context, chatID int64, name string, content io.Reader) error {
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	if err := form.WriteField("chat_id", strconv.FormatInt(chatID, 10)); err != nil {
+		return fmt.Errorf("failed to encode telegram document: %w", err)
+	}
+	part, err := form.CreateFormFile("document", name)
+	if err != nil {
+		return fmt.Errorf("failed to encode telegram document: %w", err)
+	}
+	if _, err := io.Copy(part, content); err != nil {
+		return fmt.Errorf("failed to encode telegram document: %w", err)
+	}
+	if err := form.Close(); err != nil {
+		return fmt.Errorf("failed to encode telegram document: %w", err)
+	}
+	return c.call(ctx, "sendDocument", form.FormDataContentType(), &body, nil)
+}
+
+// AnswerCallback implements Client
+func (c *BotAPIClient) AnswerCallback(ctx context.Context, callbackID, text string) error {
+	return c.callJSON(ctx, "answerCallbackQuery", map[string]any{"callback_query_id": callbackID, "text": text}, nil)
+}
+
+// callJSON calls a Bot API method with JSON parameters, decoding its result into result unless it is nil
+func (c *BotAPIClient) callJSON(ctx context.Context, method string, params any, result any) error {
+	body, err := json.Marshal(params)
+	if err != nil {
+		return fmt.Errorf("failed to encode telegram %s request: %w", method, err)
+	}
+	return c.call(ctx, method, "application/json", bytes.NewReader(body), result)
+}
+
+// call posts a request to a Bot API method, decoding its result into result unless it is nil
+func (c *BotAPIClient) call(ctx context.Context, method, contentType string, body io.Reader, result any) error {
+	endpoint := strings.TrimSuffix(c.cfg.URL, "/") + "/bot" + c.cfg.Token + "/" + method
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, body)
+	if err != nil {
+		return fmt.Errorf("failed to create telegram request: %w", err)
+	}
+	req.Header.Set("Content-Type", contentType)
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		// The URL holds the bot token, so only the method is reported
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return fmt.Errorf("failed to call telegram %s: %w", method, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	var envelope apiResponse
+	if err := json.NewDecoder(resp.Body).Decode(&envelope); err != nil {
+		return fmt.Errorf("failed to decode telegram %s response with status %d: %w", method, resp.StatusCode, err)
+	}
+	if !envelope.OK {
+		return fmt.Errorf("telegram %s failed: %s", method, envelope.Description)
+	}
+	if result == nil {
+		return nil
+	}
+	if err := json.Unmarshal(envelope.Result, result); err != nil {
+		return fmt.Errorf("failed to decode telegram %s result: %w", method, err)
+	}
+	return nil
+}
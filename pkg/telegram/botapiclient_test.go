@@ -0,0 +1,75 @@
+package telegram_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/kazemisoroush/assistant/pkg/telegram"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBotAPIClient_GetUpdates(t *testing.T) {
+	// Arrange
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/botTOKEN/getUpdates", r.URL.Path, "GetUpdates() should call getUpdates as the bot")
+		var params map[string]any
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&params))
+		assert.InDelta(t, 7, params["offset"], 0, "GetUpdates() should send the offset")
+		assert.InDelta(t, 30, params["timeout"], 0, "GetUpdates() should long-poll")
+		_, _ = io.WriteString(w, `{"ok":true,"result":[{"update_id":7,"message":{"chat":{"id":42},"text":"/search passport"}}]}`)
+	}))
+	defer server.Close()
+	client := telegram.NewBotAPIClient(server.Client(), telegram.APIConfig{URL: server.URL, Token: "TOKEN"})
+
+	// Act
+	updates, err := client.GetUpdates(context.Background(), 7, 30*time.Second)
+
+	// Assert
+	require.NoError(t, err, "GetUpdates() should succeed")
+	require.Len(t, updates, 1, "GetUpdates() should return the updates")
+	assert.Equal(t, int64(42), updates[0].Message.Chat.ID, "GetUpdates() should decode the chat")
+	assert.Equal(t, "/search passport", updates[0].Message.Text, "GetUpdates() should decode the text")
+}
+
+func TestBotAPIClient_SendMessage(t *testing.T) {
+	// Arrange
+	var got map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+		_, _ = io.WriteString(w, `{"ok":true,"result":{}}`)
+	}))
+	defer server.Close()
+	client := telegram.NewBotAPIClient(server.Client(), telegram.APIConfig{URL: server.URL, Token: "TOKEN"})
+
+	// Act
+	err := client.SendMessage(context.Background(), 42, "1. Policy", []telegram.Button{{Text: "1. policy.pdf", Data: "file:rec-1"}})
+
+	// Assert
+	require.NoError(t, err, "SendMessage() should succeed")
+	assert.Equal(t, map[string]any{"inline_keyboard": []any{[]any{map[string]any{"text": "1. policy.pdf", "callback_data": "file:rec-1"}}}},
+		got["reply_markup"], "SendMessage() should attach a row per button")
+}
+
+func TestBotAPIClient_Failed(t *testing.T) {
+	// Arrange
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = io.WriteString(w, `{"ok":false,"description":"Forbidden: bot was blocked by the user"}`)
+	}))
+	defer server.Close()
+	client := telegram.NewBotAPIClient(server.Client(), telegram.APIConfig{URL: server.URL, Token: "TOKEN"})
+
+	// Act
+	err := client.AnswerCallback(context.Background(), "cb-1", "")
+
+	// Assert
+	require.Error(t, err, "AnswerCallback() should fail when Telegram rejects the call")
+	assert.Contains(t, err.Error(), "bot was blocked", "AnswerCallback() should report Telegram's description")
+	assert.NotContains(t, err.Error(), "TOKEN", "AnswerCallback() should not leak the bot token")
+}
@@ -0,0 +1,58 @@
+// Package telegram answers queries sent to a Telegram bot: /search finds
+// records and free-text questions are answered by the agent, with inline
+// buttons fetching the original file of each record found.
+package telegram
+
+import (
+	"context"
+	"io"
+	"time"
+)
+
+// Client calls the Telegram Bot API
+//
+//go:generate mockgen -destination=./mocks/mock_client.go -mock_names=Client=MockClient -package=mocks . Client
+type Client interface {
+	// GetUpdates waits up to timeout for updates with IDs from offset on
+	GetUpdates(ctx context.Context, offset int64, timeout time.Duration) ([]Update, error)
+
+	// SendMessage sends text to a chat with a row of inline buttons per button
+	SendMessage(ctx context.Context, chatID int64, text string, buttons []Button) error
+
+	// SendDocument sends a file to a chat
+	SendDocument(ctx context.Context, chatID int64, name string, content io.Reader) error
+
+	// AnswerCallback acknowledges a pressed inline button, showing text to the user
+	AnswerCallback(ctx context.Context, callbackID, text string) error
+}
+
+// Update represents an incoming message or a pressed inline button
+type Update struct {
+	ID            int64          `json:"update_id"`
+	Message       *Message       `json:"message"`
+	CallbackQuery *CallbackQuery `json:"callback_query"`
+}
+
+// Message represents a message sent to the bot
+type Message struct {
+	Chat Chat   `json:"chat"`
+	Text string `json:"text"`
+}
+
+// Chat represents the chat a message was sent in
+type Chat struct {
+	ID int64 `json:"id"`
+}
+
+// CallbackQuery represents a pressed inline button
+type CallbackQuery struct {
+	ID      string   `json:"id"`
+	Message *Message `json:"message"` // The message the button belongs to
+	Data    string   `json:"data"`
+}
+
+// Button represents an inline button sending data back when pressed
+type Button struct {
+	Text string
+	Data string // At most 64 bytes
+}
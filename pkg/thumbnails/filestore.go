@@ -0,0 +1,70 @@
+package thumbnails
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// FileStore keeps thumbnails as JPEG files in a directory
+type FileStore struct {
+	dir string
+}
+
+// NewFileStore creates a new thumbnail store in the given directory
+func NewFileStore(dir string) (*FileStore, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create thumbnail directory: %w", err)
+	}
+	return &FileStore{dir: dir}, nil
+}
+
+// Put implements Store
+func (s *FileStore) Put(_ context.Context, recordID string, thumbnail []byte) error {
+	path, err := s.path(recordID)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, thumbnail, 0600); err != nil {
+		return fmt.Errorf("failed to write thumbnail of %s: %w", recordID, err)
+	}
+	return nil
+}
+
+// Get implements Store
+func (s *FileStore) Get(_ context.Context, recordID string) ([]byte, error) {
+	path, err := s.path(recordID)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, recordID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read thumbnail of %s: %w", recordID, err)
+	}
+	return data, nil
+}
+
+// Delete implements Store
+func (s *FileStore) Delete(_ context.Context, recordID string) error {
+	path, err := s.path(recordID)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to delete thumbnail of %s: %w", recordID, err)
+	}
+	return nil
+}
+
+// path returns the file of a record's thumbnail, rejecting IDs that would escape the directory
+func (s *FileStore) path(recordID string) (string, error) {
+	if recordID == "" || recordID != filepath.Base(recordID) || recordID == "." || recordID == ".." {
+		return "", fmt.Errorf("invalid record ID for thumbnail: %q", recordID)
+	}
+	return filepath.Join(s.dir, recordID+".jpg"), nil
+}
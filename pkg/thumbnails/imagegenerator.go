@@ -0,0 +1,120 @@
+package thumbnails
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	// Register the GIF and PNG decoders with image.Decode
+	_ "image/gif"
+	_ "image/png"
+)
+
+// jpegQuality is the quality of the encoded thumbnails
+const jpegQuality = 80
+
+// ImageGenerator scales images down with the standard library and renders
+// the first page of PDFs with an external renderer such as poppler's pdftoppm
+type ImageGenerator struct {
+	maxSize     int
+	pdfRenderer string // Empty disables PDF thumbnails
+}
+
+// NewImageGenerator creates a generator of thumbnails at most maxSize pixels wide and tall
+func NewImageGenerator(maxSize int, pdfRenderer string) Generator {
+	return &ImageGenerator{
+		maxSize:     maxSize,
+		pdfRenderer: pdfRenderer,
+	}
+}
+
+// Generate implements Generator
+func (g *ImageGenerator) Generate(ctx context.Context, data []byte, mediaType string) ([]byte, error) {
+	if mediaType == "application/pdf" {
+		rendered, err := g.renderFirstPage(ctx, data)
+		if err != nil {
+			return nil, err
+		}
+		data = rendered
+	} else if !strings.HasPrefix(mediaType, "image/") {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupported, mediaType)
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if errors.Is(err, image.ErrFormat) {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupported, mediaType)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode image: %w", err)
+	}
+
+	var out bytes.Buffer
+	if err := jpeg.Encode(&out, scale(img, g.maxSize), &jpeg.Options{Quality: jpegQuality}); err != nil {
+		return nil, fmt.Errorf("failed to encode thumbnail: %w", err)
+	}
+	return out.Bytes(), nil
+}
+
+// renderFirstPage renders the first page of a PDF to PNG
+func (g *ImageGenerator) renderFirstPage(ctx context.Context, pdf []byte) ([]byte, error) {
+	if g.pdfRenderer == "" {
+		return nil, fmt.Errorf("%w: application/pdf", ErrUnsupported)
+	}
+	path, err := exec.LookPath(g.pdfRenderer)
+	if err != nil {
+		return nil, fmt.Errorf("%w: PDF renderer %s is not installed", ErrUnsupported, g.pdfRenderer)
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, path, "-png", "-f", "1", "-l", "1", "-singlefile", "-scale-to", strconv.Itoa(g.maxSize), "-")
+	cmd.Stdin = bytes.NewReader(pdf)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("failed to render PDF: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.Bytes(), nil
+}
+
+// scale shrinks an image to fit within maxSize by averaging the source pixels behind each target pixel
+func scale(src image.Image, maxSize int) image.Image {
+	bounds := src.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if width <= maxSize && height <= maxSize {
+		return src
+	}
+	targetWidth, targetHeight := maxSize, height*maxSize/width
+	if height > width {
+		targetWidth, targetHeight = width*maxSize/height, maxSize
+	}
+	targetWidth, targetHeight = max(targetWidth, 1), max(targetHeight, 1)
+
+	dst := image.NewRGBA(image.Rect(0, 0, targetWidth, targetHeight))
+	for y := 0; y < targetHeight; y++ {
+		y0, y1 := bounds.Min.Y+y*height/targetHeight, bounds.Min.Y+max((y+1)*height/targetHeight, y*height/targetHeight+1)
+		for x := 0; x < targetWidth; x++ {
+			x0, x1 := bounds.Min.X+x*width/targetWidth, bounds.Min.X+max((x+1)*width/targetWidth, x*width/targetWidth+1)
+			dst.Set(x, y, average(src, image.Rect(x0, y0, x1, y1)))
+		}
+	}
+	return dst
+}
+
+// average returns the mean color of a region of an image
+func average(src image.Image, region image.Rectangle) color.Color {
+	var r, g, b, a, n uint64
+	for y := region.Min.Y; y < region.Max.Y; y++ {
+		for x := region.Min.X; x < region.Max.X; x++ {
+			cr, cg, cb, ca := src.At(x, y).RGBA()
+			r, g, b, a, n = r+uint64(cr), g+uint64(cg), b+uint64(cb), a+uint64(ca), n+1
+		}
+	}
+	return color.RGBA64{R: uint16(r / n), G: uint16(g / n), B: uint16(b / n), A: uint16(a / n)}
+}
@@ -0,0 +1,64 @@
+package thumbnails_test
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+
+	"github.com/kazemisoroush/assistant/pkg/thumbnails"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImageGenerator_Generate(t *testing.T) {
+	// Arrange
+	src := image.NewRGBA(image.Rect(0, 0, 800, 400))
+	for y := 0; y < 400; y++ {
+		for x := 0; x < 800; x++ {
+			src.Set(x, y, color.RGBA{R: 200, G: 100, B: 50, A: 255})
+		}
+	}
+	var scan bytes.Buffer
+	require.NoError(t, png.Encode(&scan, src), "png.Encode() error should be nil")
+	generator := thumbnails.NewImageGenerator(200, "")
+
+	// Act
+	thumbnail, err := generator.Generate(context.Background(), scan.Bytes(), "image/png")
+
+	// Assert
+	require.NoError(t, err, "Generate() error should be nil")
+	img, err := jpeg.Decode(bytes.NewReader(thumbnail))
+	require.NoError(t, err, "Generate() should return a JPEG")
+	assert.Equal(t, image.Rect(0, 0, 200, 100), img.Bounds(), "Generate() should fit the image in the maximum size keeping its aspect ratio")
+	r, _, _, _ := img.At(100, 50).RGBA()
+	assert.InDelta(t, 200, r>>8, 5, "Generate() should keep the colors")
+}
+
+func TestImageGenerator_Generate_Unsupported(t *testing.T) {
+	tests := []struct {
+		name      string
+		data      []byte
+		mediaType string
+	}{
+		{name: "text", data: []byte("hello"), mediaType: "text/plain"},
+		{name: "pdf without renderer", data: []byte("%PDF-1.7"), mediaType: "application/pdf"},
+		{name: "undecodable image", data: []byte("RIFF....WEBP"), mediaType: "image/webp"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			generator := thumbnails.NewImageGenerator(200, "")
+
+			// Act
+			_, err := generator.Generate(context.Background(), tt.data, tt.mediaType)
+
+			// Assert
+			assert.ErrorIs(t, err, thumbnails.ErrUnsupported, "Generate() should report unsupported media")
+		})
+	}
+}
@@ -0,0 +1,39 @@
+// Package thumbnails renders small previews of scanned images and PDFs so
+// list views don't need to load the full documents.
+package thumbnails
+
+import (
+	"context"
+	"errors"
+)
+
+// MediaType is the media type of every generated thumbnail
+const MediaType = "image/jpeg"
+
+// ErrUnsupported is returned for media types no thumbnail can be rendered from
+var ErrUnsupported = errors.New("unsupported media type for thumbnails")
+
+// ErrNotFound is returned when a record has no stored thumbnail
+var ErrNotFound = errors.New("thumbnail not found")
+
+// Generator renders thumbnails
+//
+//go:generate mockgen -destination=./mocks/mock_generator.go -mock_names=Generator=MockGenerator -package=mocks . Generator
+type Generator interface {
+	// Generate returns a JPEG thumbnail of an image, or of the first page of a PDF
+	Generate(ctx context.Context, data []byte, mediaType string) ([]byte, error)
+}
+
+// Store keeps the thumbnails of records
+//
+//go:generate mockgen -destination=./mocks/mock_store.go -mock_names=Store=MockStore -package=mocks . Store
+type Store interface {
+	// Put saves the thumbnail of a record
+	Put(ctx context.Context, recordID string, thumbnail []byte) error
+
+	// Get returns the thumbnail of a record, or ErrNotFound
+	Get(ctx context.Context, recordID string) ([]byte, error)
+
+	// Delete removes the thumbnail of a record, if any
+	Delete(ctx context.Context, recordID string) error
+}
@@ -0,0 +1,85 @@
+// Package tokens estimates prompt sizes and fits text into a model's context window.
+package tokens
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	// DefaultContextWindow is assumed for models with an unknown context window
+	DefaultContextWindow = 4096
+
+	// charsPerToken approximates BPE tokenizers (Llama, Claude, GPT) on English text
+	charsPerToken = 4
+
+	// truncationMarker separates the kept head and tail of truncated text
+	truncationMarker = "\n[...]\n"
+)
+
+// contextWindows maps model name prefixes to their context window in tokens
+var contextWindows = []struct {
+	prefix string
+	window int
+}{
+	{"codellama", 16384},
+	{"llama2", 4096},
+	{"llama3.1", 131072},
+	{"llama3.2", 131072},
+	{"llama3", 8192},
+	{"mistral", 32768},
+	{"phi3", 4096},
+	{"gemma", 8192},
+	{"qwen2", 32768},
+	{"anthropic.claude", 200000},
+	{"claude", 200000},
+	{"amazon.titan", 8192},
+	{"gpt-4o", 128000},
+	{"gpt-3.5", 16385},
+}
+
+// ContextWindow returns the context window of the named model
+func ContextWindow(model string) int {
+	model = strings.ToLower(model)
+	for _, cw := range contextWindows {
+		if strings.HasPrefix(model, cw.prefix) {
+			return cw.window
+		}
+	}
+	return DefaultContextWindow
+}
+
+// Budgeter counts tokens and truncates text to fit a token budget
+type Budgeter struct {
+	budget int
+}
+
+// NewBudgeter creates a budgeter for content sent to the model. The budget is
+// the context window minus reserve, which is kept free for the prompt
+// template and the model's answer.
+func NewBudgeter(contextWindow, reserve int) *Budgeter {
+	return &Budgeter{
+		budget: max(contextWindow-reserve, 1),
+	}
+}
+
+// Count estimates the number of tokens in text
+func (b *Budgeter) Count(text string) int {
+	return (utf8.RuneCountInString(text) + charsPerToken - 1) / charsPerToken
+}
+
+// Fit returns text unchanged when it fits the budget. Otherwise it keeps the
+// beginning and the end of the text, where headers and totals usually are,
+// and reports that the text was truncated.
+func (b *Budgeter) Fit(text string) (string, bool) {
+	if b.Count(text) <= b.budget {
+		return text, false
+	}
+
+	runes := []rune(text)
+	keep := max(b.budget*charsPerToken-utf8.RuneCountInString(truncationMarker), 0)
+	head := keep * 2 / 3
+	tail := keep - head
+
+	return string(runes[:head]) + truncationMarker + string(runes[len(runes)-tail:]), true
+}
@@ -0,0 +1,46 @@
+package tokens
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBudgeter_Fit_ShortText(t *testing.T) {
+	// Arrange
+	budgeter := NewBudgeter(100, 10)
+
+	// Act
+	text, truncated := budgeter.Fit("short receipt")
+
+	// Assert
+	assert.False(t, truncated, "Fit() should not truncate text within budget")
+	assert.Equal(t, "short receipt", text, "Fit() should return the text unchanged")
+}
+
+func TestBudgeter_Fit_LongText(t *testing.T) {
+	// Arrange
+	budgeter := NewBudgeter(100, 50)
+	long := "HEADER " + strings.Repeat("x", 1000) + " TOTAL"
+
+	// Act
+	text, truncated := budgeter.Fit(long)
+
+	// Assert
+	assert.True(t, truncated, "Fit() should truncate text over budget")
+	assert.LessOrEqual(t, budgeter.Count(text), 50, "Fit() should respect the budget")
+	assert.True(t, strings.HasPrefix(text, "HEADER"), "Fit() should keep the beginning")
+	assert.True(t, strings.HasSuffix(text, "TOTAL"), "Fit() should keep the end")
+}
+
+func TestContextWindow(t *testing.T) {
+	// Arrange
+	model := "llama3:8b"
+
+	// Act
+	window := ContextWindow(model)
+
+	// Assert
+	assert.Equal(t, 8192, window, "ContextWindow() should match the model prefix")
+}
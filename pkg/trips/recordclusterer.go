@@ -0,0 +1,229 @@
+package trips
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/kazemisoroush/assistant/pkg/records"
+	"github.com/kazemisoroush/assistant/pkg/records/storage"
+)
+
+// Config represents how far apart bookings of one trip may be and where home is
+type Config struct {
+	GapDays     int    // Maximum days between the end of a trip and the next booking extending it
+	HomeCountry string // Bookings back to this country end the trip they follow instead of starting one
+}
+
+// RecordClusterer clusters the travel records kept in storage into trips.
+// Bookings to the same country, or the same destination when no country was
+// extracted, starting within the gap of a trip's end extend that trip.
+// Receipts dated within a trip in its country are attributed to it, as are
+// visas for its country still valid when it starts.
+type RecordClusterer struct {
+	storage storage.Storage
+	config  Config
+}
+
+// NewRecordClusterer creates a new trip clusterer
+func NewRecordClusterer(storage storage.Storage, config Config) Clusterer {
+	return &RecordClusterer{
+		storage: storage,
+		config:  config,
+	}
+}
+
+// booking is a travel record with its parsed dates
+type booking struct {
+	recordID string
+	meta     records.TravelMetadata
+	start    time.Time
+	end      time.Time
+}
+
+// trip is a Trip with its parsed dates
+type trip struct {
+	Trip
+	start time.Time
+	end   time.Time
+}
+
+// Trips implements Clusterer
+func (c *RecordClusterer) Trips(ctx context.Context) ([]Trip, error) {
+	bookings, err := c.bookings(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	clustered := c.cluster(bookings)
+	if err := c.attachReceipts(ctx, clustered); err != nil {
+		return nil, err
+	}
+	if err := c.attachVisas(ctx, clustered); err != nil {
+		return nil, err
+	}
+
+	trips := make([]Trip, len(clustered))
+	for i, t := range clustered {
+		t.StartDate = t.start.Format(time.DateOnly)
+		t.EndDate = t.end.Format(time.DateOnly)
+		trips[i] = t.Trip
+	}
+	return trips, nil
+}
+
+// bookings returns the travel records with a start date, oldest first
+func (c *RecordClusterer) bookings(ctx context.Context) ([]booking, error) {
+	recs, err := c.storage.List(ctx, records.RecordTypeTravel)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list travel records: %w", err)
+	}
+
+	var bookings []booking
+	for _, rec := range recs {
+		var meta records.TravelMetadata
+		if err := rec.DecodeMetadata(&meta); err != nil {
+			slog.WarnContext(ctx, "Skipping travel record with malformed metadata", "record_id", rec.ID, "error", err)
+			continue
+		}
+		start, err := time.Parse(time.DateOnly, meta.StartDate)
+		if err != nil {
+			continue
+		}
+		end := start
+		if parsed, err := time.Parse(time.DateOnly, meta.EndDate); err == nil && parsed.After(start) {
+			end = parsed
+		}
+		bookings = append(bookings, booking{recordID: rec.ID, meta: meta, start: start, end: end})
+	}
+	sort.SliceStable(bookings, func(i, j int) bool { return bookings[i].start.Before(bookings[j].start) })
+	return bookings, nil
+}
+
+// cluster groups the bookings, oldest first, into trips
+func (c *RecordClusterer) cluster(bookings []booking) []*trip {
+	trips := []*trip{}
+	open := map[string]*trip{}
+	var last *trip
+	for _, b := range bookings {
+		key := location(b.meta)
+		if key == "" {
+			continue
+		}
+		if c.config.HomeCountry != "" && strings.EqualFold(b.meta.Country, c.config.HomeCountry) {
+			if last != nil && c.within(last, b.start) {
+				last.add(b)
+			}
+			continue
+		}
+
+		t, ok := open[key]
+		if !ok || !c.within(t, b.start) {
+			t = newTrip(b)
+			open[key] = t
+			trips = append(trips, t)
+		}
+		t.add(b)
+		last = t
+	}
+	return trips
+}
+
+// within reports whether a booking starting on date extends the trip
+func (c *RecordClusterer) within(t *trip, date time.Time) bool {
+	return !date.After(t.end.AddDate(0, 0, c.config.GapDays))
+}
+
+// attachReceipts adds the receipts dated within a trip in its country to the trip
+func (c *RecordClusterer) attachReceipts(ctx context.Context, trips []*trip) error {
+	recs, err := c.storage.List(ctx, records.RecordTypeReceipt)
+	if err != nil {
+		return fmt.Errorf("failed to list receipts: %w", err)
+	}
+
+	for _, rec := range recs {
+		var receipt records.ReceiptMetadata
+		if err := rec.DecodeMetadata(&receipt); err != nil || receipt.Country == "" {
+			continue
+		}
+		date, err := time.Parse(time.DateOnly, receipt.Date)
+		if err != nil {
+			continue
+		}
+		for _, t := range trips {
+			if strings.EqualFold(t.Country, receipt.Country) && !date.Before(t.start) && !date.After(t.end) {
+				t.ReceiptIDs = append(t.ReceiptIDs, rec.ID)
+				t.addSpend(receipt.Currency, receipt.Total)
+				break
+			}
+		}
+	}
+	return nil
+}
+
+// attachVisas adds the visas for a trip's country still valid when it starts to the trip
+func (c *RecordClusterer) attachVisas(ctx context.Context, trips []*trip) error {
+	recs, err := c.storage.List(ctx, records.RecordTypeVisa)
+	if err != nil {
+		return fmt.Errorf("failed to list visas: %w", err)
+	}
+
+	for _, rec := range recs {
+		var visa records.VisaMetadata
+		if err := rec.DecodeMetadata(&visa); err != nil || visa.Country == "" {
+			continue
+		}
+		expires, err := time.Parse(time.DateOnly, visa.ExpiresAt)
+		for _, t := range trips {
+			if strings.EqualFold(t.Country, visa.Country) && (err != nil || !expires.Before(t.start)) {
+				t.VisaIDs = append(t.VisaIDs, rec.ID)
+			}
+		}
+	}
+	return nil
+}
+
+// newTrip starts a trip with the booking's destination
+func newTrip(b booking) *trip {
+	return &trip{
+		Trip: Trip{
+			ID:          b.recordID,
+			Destination: b.meta.Destination,
+			Country:     strings.ToUpper(b.meta.Country),
+			TravelIDs:   []string{},
+			ReceiptIDs:  []string{},
+			VisaIDs:     []string{},
+			Spend:       map[string]float64{},
+		},
+		start: b.start,
+		end:   b.end,
+	}
+}
+
+// add extends the trip with the booking
+func (t *trip) add(b booking) {
+	t.TravelIDs = append(t.TravelIDs, b.recordID)
+	if b.end.After(t.end) {
+		t.end = b.end
+	}
+	t.addSpend(b.meta.Currency, b.meta.Total)
+}
+
+// addSpend adds an amount to the trip's spend in its currency
+func (t *trip) addSpend(currency string, amount float64) {
+	if amount <= 0 || currency == "" {
+		return
+	}
+	t.Spend[strings.ToUpper(currency)] += amount
+}
+
+// location returns the key bookings to the same place share
+func location(meta records.TravelMetadata) string {
+	if meta.Country != "" {
+		return strings.ToUpper(meta.Country)
+	}
+	return strings.ToLower(strings.TrimSpace(meta.Destination))
+}
@@ -0,0 +1,103 @@
+package trips_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kazemisoroush/assistant/pkg/records"
+	"github.com/kazemisoroush/assistant/pkg/records/storage/mocks"
+	"github.com/kazemisoroush/assistant/pkg/trips"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+// travel builds a travel booking record with the given metadata
+func travel(id, destination, country, start, end string, total float64) records.Record {
+	return records.Record{ID: id, Type: records.RecordTypeTravel, Metadata: map[string]interface{}{
+		"destination": destination, "country": country, "start_date": start, "end_date": end, "total": total, "currency": "EUR",
+	}}
+}
+
+// receipt builds a receipt record with the given metadata
+func receipt(id, country, date string, total float64) records.Record {
+	return records.Record{ID: id, Type: records.RecordTypeReceipt, Metadata: map[string]interface{}{
+		"merchant": "Shop", "date": date, "total": total, "currency": "EUR", "country": country,
+	}}
+}
+
+// visa builds a visa record with the given metadata
+func visa(id, country, expiresAt string) records.Record {
+	return records.Record{ID: id, Type: records.RecordTypeVisa, Metadata: map[string]interface{}{
+		"document_type": "visa", "country": country, "expires_at": expiresAt,
+	}}
+}
+
+func newClusterer(t *testing.T, travels, receipts, visas []records.Record) trips.Clusterer {
+	ctrl := gomock.NewController(t)
+	storage := mocks.NewMockStorage(ctrl)
+	storage.EXPECT().List(gomock.Any(), records.RecordTypeTravel).Return(travels, nil)
+	storage.EXPECT().List(gomock.Any(), records.RecordTypeReceipt).Return(receipts, nil)
+	storage.EXPECT().List(gomock.Any(), records.RecordTypeVisa).Return(visas, nil)
+	return trips.NewRecordClusterer(storage, trips.Config{GapDays: 2, HomeCountry: "DE"})
+}
+
+func TestRecordClusterer_Trips(t *testing.T) {
+	// Arrange
+	clusterer := newClusterer(t,
+		[]records.Record{
+			travel("hotel", "Tokyo", "JP", "2024-04-02", "2024-04-08", 600),
+			travel("flight-out", "Tokyo", "jp", "2024-04-01", "2024-04-02", 900),
+			travel("flight-home", "Berlin", "DE", "2024-04-09", "", 0),
+			travel("rail", "Paris", "FR", "2024-06-10", "2024-06-12", 150),
+		},
+		[]records.Record{
+			receipt("ramen", "JP", "2024-04-03", 20),
+			receipt("souvenirs", "JP", "2024-05-01", 40),
+			receipt("bakery", "FR", "2024-06-11", 8),
+		},
+		[]records.Record{
+			visa("visa-jp", "JP", "2024-12-31"),
+			visa("visa-fr-expired", "FR", "2024-01-01"),
+		},
+	)
+
+	// Act
+	found, err := clusterer.Trips(context.Background())
+
+	// Assert
+	require.NoError(t, err, "Trips() error should be nil")
+	require.Len(t, found, 2, "Trips() should cluster bookings by destination and dates")
+
+	tokyo := found[0]
+	assert.Equal(t, "flight-out", tokyo.ID, "Trips() should identify a trip by its first booking")
+	assert.Equal(t, []string{"flight-out", "hotel", "flight-home"}, tokyo.TravelIDs, "Trips() should attach the return booking to the trip it ends")
+	assert.Equal(t, "2024-04-01", tokyo.StartDate, "Trips() should start a trip with its first booking")
+	assert.Equal(t, "2024-04-09", tokyo.EndDate, "Trips() should end a trip with its last booking")
+	assert.Equal(t, []string{"ramen"}, tokyo.ReceiptIDs, "Trips() should only attach receipts dated within the trip")
+	assert.Equal(t, []string{"visa-jp"}, tokyo.VisaIDs, "Trips() should attach valid visas for the country")
+	assert.InDelta(t, 1520, tokyo.Spend["EUR"], 0.001, "Trips() should total bookings and receipts")
+
+	paris := found[1]
+	assert.Equal(t, []string{"bakery"}, paris.ReceiptIDs, "Trips() should attach receipts by country")
+	assert.Empty(t, paris.VisaIDs, "Trips() should skip visas expired before the trip")
+}
+
+func TestRecordClusterer_Trips_SplitsDistantBookings(t *testing.T) {
+	// Arrange
+	clusterer := newClusterer(t,
+		[]records.Record{
+			travel("spring", "Rome", "IT", "2024-03-01", "2024-03-05", 0),
+			travel("autumn", "Rome", "IT", "2024-10-01", "2024-10-05", 0),
+		},
+		nil,
+		nil,
+	)
+
+	// Act
+	found, err := clusterer.Trips(context.Background())
+
+	// Assert
+	require.NoError(t, err, "Trips() error should be nil")
+	assert.Len(t, found, 2, "Trips() should split bookings further apart than the gap")
+}
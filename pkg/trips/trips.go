@@ -0,0 +1,29 @@
+// Package trips groups travel bookings into trips and attributes the
+// receipts and visas that belong to them.
+package trips
+
+import "context"
+
+// Trip represents bookings to one destination over a date range, with the
+// receipts and visas that belong to it
+type Trip struct {
+	ID          string   `json:"id"` // ID of the trip's first booking
+	Destination string   `json:"destination"`
+	Country     string   `json:"country"`    // ISO 3166-1 alpha-2 code
+	StartDate   string   `json:"start_date"` // YYYY-MM-DD
+	EndDate     string   `json:"end_date"`   // YYYY-MM-DD
+	TravelIDs   []string `json:"travel_ids"`
+	ReceiptIDs  []string `json:"receipt_ids"`
+	VisaIDs     []string `json:"visa_ids"`
+
+	// Spend totals the bookings and receipts of the trip per currency
+	Spend map[string]float64 `json:"spend"`
+}
+
+// Clusterer groups travel records into trips
+//
+//go:generate mockgen -destination=./mocks/mock_clusterer.go -mock_names=Clusterer=MockClusterer -package=mocks . Clusterer
+type Clusterer interface {
+	// Trips returns the trips found in the records, oldest first
+	Trips(ctx context.Context) ([]Trip, error)
+}
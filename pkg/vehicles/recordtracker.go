@@ -0,0 +1,340 @@
+package vehicles
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/kazemisoroush/assistant/pkg/records"
+	"github.com/kazemisoroush/assistant/pkg/records/storage"
+	"github.com/kazemisoroush/assistant/pkg/reminders"
+)
+
+// metaServiceReminded marks the service record whose follow-up service was reminded about
+const metaServiceReminded = "service_reminded"
+
+// Config represents the service intervals and how early services are reminded
+type Config struct {
+	Intervals map[string]Interval // Keyed by service type, e.g. oil_change
+	LeadDays  int                 // Days before a service is due that it is reminded
+}
+
+// RecordTracker builds vehicles from the car records kept in storage. Records
+// are grouped by VIN, or by license plate when no VIN was extracted. A
+// service is due once its interval has passed since the last service of its
+// type, in time or in mileage, where the mileage is extrapolated from the
+// vehicle's odometer readings.
+type RecordTracker struct {
+	storage   storage.Storage
+	config    Config
+	notifiers []reminders.Notifier
+}
+
+// NewRecordTracker creates a new vehicle maintenance tracker
+func NewRecordTracker(storage storage.Storage, config Config, notifiers ...reminders.Notifier) Tracker {
+	return &RecordTracker{
+		storage:   storage,
+		config:    config,
+		notifiers: notifiers,
+	}
+}
+
+// reading is a car record with its decoded metadata
+type reading struct {
+	record records.Record
+	meta   records.CarMetadata
+	date   time.Time
+}
+
+// vehicle is a Vehicle with the readings it was built from, oldest first
+type vehicle struct {
+	Vehicle
+	readings []reading
+}
+
+// Vehicles implements Tracker
+func (t *RecordTracker) Vehicles(ctx context.Context, now time.Time) ([]Vehicle, error) {
+	grouped, err := t.vehicles(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]Vehicle, 0, len(grouped))
+	for _, v := range grouped {
+		v.Upcoming = t.upcoming(v, now)
+		result = append(result, v.Vehicle)
+	}
+	return result, nil
+}
+
+// Run implements Tracker. Service records are only marked as reminded once
+// every notifier has delivered, so failed deliveries are retried on the next run.
+func (t *RecordTracker) Run(ctx context.Context, now time.Time) ([]reminders.Reminder, error) {
+	grouped, err := t.vehicles(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	due, dueRecs := t.due(grouped, now)
+	if len(due) == 0 {
+		return nil, nil
+	}
+
+	var errs []error
+	for _, notifier := range t.notifiers {
+		if err := notifier.Notify(ctx, due); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if err := errors.Join(errs...); err != nil {
+		return nil, fmt.Errorf("failed to send service reminders: %w", err)
+	}
+
+	for _, rec := range dueRecs {
+		if rec.Metadata == nil {
+			rec.Metadata = map[string]interface{}{}
+		}
+		rec.Metadata[metaServiceReminded] = true
+		rec.UpdatedAt = now
+		if err := t.storage.Update(ctx, rec); err != nil {
+			return nil, fmt.Errorf("failed to mark record %s as reminded: %w", rec.ID, err)
+		}
+	}
+	return due, nil
+}
+
+// due returns the reminders of services due within the lead time and the
+// service records they follow up on, skipping those already reminded
+func (t *RecordTracker) due(grouped []*vehicle, now time.Time) ([]reminders.Reminder, []records.Record) {
+	var due []reminders.Reminder
+	var dueRecs []records.Record
+	for _, v := range grouped {
+		for _, next := range t.upcoming(v, now) {
+			last := v.reading(next.LastRecordID)
+			if reminded, _ := last.record.Metadata[metaServiceReminded].(bool); reminded || next.DaysLeft > t.config.LeadDays {
+				continue
+			}
+			dueDate, _ := time.Parse(time.DateOnly, next.DueDate)
+			due = append(due, reminders.Reminder{
+				RecordID:   next.LastRecordID,
+				RecordType: records.RecordTypeCar,
+				Title:      fmt.Sprintf("%s of %s", strings.ReplaceAll(next.ServiceType, "_", " "), v.label()),
+				ExpiresAt:  dueDate,
+				DaysLeft:   next.DaysLeft,
+				LeadDays:   t.config.LeadDays,
+			})
+			dueRecs = append(dueRecs, last.record)
+		}
+	}
+	return due, dueRecs
+}
+
+// vehicles groups the car records into vehicles ordered by ID
+func (t *RecordTracker) vehicles(ctx context.Context) ([]*vehicle, error) {
+	recs, err := t.storage.List(ctx, records.RecordTypeCar)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list car records: %w", err)
+	}
+
+	var readings []reading
+	for _, rec := range recs {
+		var meta records.CarMetadata
+		if err := rec.DecodeMetadata(&meta); err != nil {
+			slog.WarnContext(ctx, "Skipping car record with malformed metadata", "record_id", rec.ID, "error", err)
+			continue
+		}
+		date, err := time.Parse(time.DateOnly, meta.Date)
+		if err != nil {
+			date = rec.CreatedAt
+		}
+		readings = append(readings, reading{record: rec, meta: meta, date: date})
+	}
+	sort.SliceStable(readings, func(i, j int) bool { return readings[i].date.Before(readings[j].date) })
+
+	grouped := group(readings)
+	sort.Slice(grouped, func(i, j int) bool { return grouped[i].ID < grouped[j].ID })
+	return grouped, nil
+}
+
+// group assigns the readings, oldest first, to vehicles by VIN or plate
+func group(readings []reading) []*vehicle {
+	var grouped []*vehicle
+	byKey := map[string]*vehicle{}
+	for _, r := range readings {
+		vin, plate := normalize(r.meta.VIN), normalize(r.meta.Plate)
+		if vin == "" && plate == "" {
+			continue
+		}
+		v, ok := byKey["vin:"+vin]
+		if !ok {
+			v, ok = byKey["plate:"+plate]
+		}
+		if !ok {
+			v = &vehicle{Vehicle: Vehicle{Services: []Service{}, Spend: map[string]float64{}, Upcoming: []Due{}}}
+			grouped = append(grouped, v)
+		}
+		v.add(r, vin, plate)
+		if vin != "" {
+			byKey["vin:"+vin] = v
+		}
+		if plate != "" {
+			byKey["plate:"+plate] = v
+		}
+	}
+	return grouped
+}
+
+// add records a reading of the vehicle
+func (v *vehicle) add(r reading, vin, plate string) {
+	v.readings = append(v.readings, r)
+	if v.VIN == "" {
+		v.VIN = vin
+	}
+	if plate != "" {
+		v.Plate = plate
+	}
+	if v.Name == "" {
+		v.Name = r.meta.Vehicle
+	}
+	v.ID = v.VIN
+	if v.ID == "" {
+		v.ID = v.Plate
+	}
+	v.Odometer = math.Max(v.Odometer, r.meta.Odometer)
+
+	if r.meta.ServiceType == "" {
+		return
+	}
+	v.Services = append(v.Services, Service{
+		RecordID: r.record.ID,
+		Date:     r.date.Format(time.DateOnly),
+		Type:     strings.ToLower(r.meta.ServiceType),
+		Odometer: r.meta.Odometer,
+		Cost:     r.meta.Cost,
+		Currency: strings.ToUpper(r.meta.Currency),
+	})
+	if r.meta.Cost > 0 && r.meta.Currency != "" {
+		v.Spend[strings.ToUpper(r.meta.Currency)] += r.meta.Cost
+	}
+}
+
+// upcoming returns the next service of every type with an interval the vehicle was serviced for
+func (t *RecordTracker) upcoming(v *vehicle, now time.Time) []Due {
+	upcoming := []Due{}
+	for serviceType, interval := range t.config.Intervals {
+		last, ok := v.lastService(serviceType)
+		if !ok {
+			continue
+		}
+		dueDate, dueOdometer, ok := v.next(last, interval)
+		if !ok {
+			continue
+		}
+		upcoming = append(upcoming, Due{
+			ServiceType:  serviceType,
+			LastRecordID: last.record.ID,
+			LastDate:     last.date.Format(time.DateOnly),
+			DueDate:      dueDate.Format(time.DateOnly),
+			DueOdometer:  dueOdometer,
+			DaysLeft:     reminders.DaysBetween(now, dueDate),
+		})
+	}
+	sort.Slice(upcoming, func(i, j int) bool {
+		if upcoming[i].DueDate != upcoming[j].DueDate {
+			return upcoming[i].DueDate < upcoming[j].DueDate
+		}
+		return upcoming[i].ServiceType < upcoming[j].ServiceType
+	})
+	return upcoming
+}
+
+// next returns when the service after last is due: the interval in time or
+// the date the mileage interval is expected to be reached, whichever is first
+func (v *vehicle) next(last reading, interval Interval) (time.Time, float64, bool) {
+	var dueDate time.Time
+	if interval.Months > 0 {
+		dueDate = last.date.AddDate(0, interval.Months, 0)
+	}
+
+	var dueOdometer float64
+	if interval.Distance > 0 && last.meta.Odometer > 0 {
+		dueOdometer = last.meta.Odometer + interval.Distance
+		if reached, ok := v.reaches(dueOdometer); ok && (dueDate.IsZero() || reached.Before(dueDate)) {
+			dueDate = reached
+		}
+	}
+	return dueDate, dueOdometer, !dueDate.IsZero()
+}
+
+// reaches estimates when the odometer reaches the reading, extrapolating the
+// distance driven per day between the first and latest readings
+func (v *vehicle) reaches(odometer float64) (time.Time, bool) {
+	var first, latest *reading
+	for i := range v.readings {
+		if v.readings[i].meta.Odometer <= 0 {
+			continue
+		}
+		if first == nil {
+			first = &v.readings[i]
+		}
+		latest = &v.readings[i]
+	}
+	if latest == nil {
+		return time.Time{}, false
+	}
+	if latest.meta.Odometer >= odometer {
+		return latest.date, true
+	}
+
+	days := latest.date.Sub(first.date).Hours() / 24
+	if days <= 0 {
+		return time.Time{}, false
+	}
+	perDay := (latest.meta.Odometer - first.meta.Odometer) / days
+	if perDay <= 0 {
+		return time.Time{}, false
+	}
+	return latest.date.AddDate(0, 0, int(math.Ceil((odometer-latest.meta.Odometer)/perDay))), true
+}
+
+// lastService returns the latest reading of a service of the type
+func (v *vehicle) lastService(serviceType string) (reading, bool) {
+	for i := len(v.readings) - 1; i >= 0; i-- {
+		if strings.EqualFold(v.readings[i].meta.ServiceType, serviceType) {
+			return v.readings[i], true
+		}
+	}
+	return reading{}, false
+}
+
+// reading returns the reading of the record
+func (v *vehicle) reading(recordID string) reading {
+	for _, r := range v.readings {
+		if r.record.ID == recordID {
+			return r
+		}
+	}
+	return reading{}
+}
+
+// label names the vehicle for reminders
+func (v *vehicle) label() string {
+	switch {
+	case v.Name != "" && v.Plate != "":
+		return fmt.Sprintf("%s (%s)", v.Name, v.Plate)
+	case v.Name != "":
+		return v.Name
+	default:
+		return v.ID
+	}
+}
+
+// normalize uppercases a VIN or plate and drops spaces and dashes
+func normalize(id string) string {
+	return strings.ToUpper(strings.NewReplacer(" ", "", "-", "").Replace(id))
+}
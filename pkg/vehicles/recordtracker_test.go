@@ -0,0 +1,116 @@
+package vehicles_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/kazemisoroush/assistant/pkg/records"
+	"github.com/kazemisoroush/assistant/pkg/records/storage/mocks"
+	remindermocks "github.com/kazemisoroush/assistant/pkg/reminders/mocks"
+	"github.com/kazemisoroush/assistant/pkg/vehicles"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+var (
+	now    = time.Date(2024, 4, 20, 9, 0, 0, 0, time.UTC)
+	config = vehicles.Config{
+		Intervals: map[string]vehicles.Interval{
+			"oil_change": {Months: 12, Distance: 15000},
+			"inspection": {Months: 24},
+		},
+		LeadDays: 30,
+	}
+)
+
+// car builds a car record with the given metadata
+func car(id string, metadata map[string]interface{}) records.Record {
+	return records.Record{ID: id, Type: records.RecordTypeCar, CreatedAt: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC), Metadata: metadata}
+}
+
+// carRecords are a golf with a registration and two services, and a second car without services
+func carRecords() []records.Record {
+	return []records.Record{
+		car("registration", map[string]interface{}{"document_type": "registration", "vin": "WVW123", "plate": "B-AB 123", "vehicle": "VW Golf"}),
+		car("oil", map[string]interface{}{"plate": "B AB123", "date": "2023-06-01", "odometer": 20000, "service_type": "oil_change", "cost": 150, "currency": "EUR"}),
+		car("inspection", map[string]interface{}{"vin": "wvw123", "date": "2024-01-16", "odometer": 30000, "service_type": "inspection", "cost": 100, "currency": "EUR"}),
+		car("van-registration", map[string]interface{}{"document_type": "registration", "plate": "M-XY 9"}),
+	}
+}
+
+func TestRecordTracker_Vehicles(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	storage := mocks.NewMockStorage(ctrl)
+	storage.EXPECT().List(gomock.Any(), records.RecordTypeCar).Return(carRecords(), nil)
+	tracker := vehicles.NewRecordTracker(storage, config)
+
+	// Act
+	found, err := tracker.Vehicles(context.Background(), now)
+
+	// Assert
+	require.NoError(t, err, "Vehicles() error should be nil")
+	require.Len(t, found, 2, "Vehicles() should group records by VIN and plate")
+	assert.Empty(t, found[0].Services, "Vehicles() should keep vehicles without services")
+
+	golf := found[1]
+	assert.Equal(t, "WVW123", golf.ID, "Vehicles() should identify a vehicle by its VIN")
+	assert.Equal(t, "VW Golf", golf.Name, "Vehicles() should name the vehicle")
+	assert.Equal(t, 30000.0, golf.Odometer, "Vehicles() should report the latest odometer reading")
+	require.Len(t, golf.Services, 2, "Vehicles() should list the services")
+	assert.InDelta(t, 250, golf.Spend["EUR"], 0.001, "Vehicles() should total the service costs")
+	require.Len(t, golf.Upcoming, 2, "Vehicles() should report the next service of every serviced type")
+	assert.Equal(t, vehicles.Due{
+		ServiceType:  "oil_change",
+		LastRecordID: "oil",
+		LastDate:     "2023-06-01",
+		DueDate:      "2024-05-10",
+		DueOdometer:  35000,
+		DaysLeft:     20,
+	}, golf.Upcoming[0], "Vehicles() should estimate when the mileage interval is reached before the time interval")
+	assert.Equal(t, "2026-01-16", golf.Upcoming[1].DueDate, "Vehicles() should schedule services by time")
+}
+
+func TestRecordTracker_Run(t *testing.T) {
+	tests := []struct {
+		name     string
+		reminded bool
+		wantDue  int
+	}{
+		{name: "due", wantDue: 1},
+		{name: "already reminded", reminded: true, wantDue: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			ctrl := gomock.NewController(t)
+			recs := carRecords()
+			if tt.reminded {
+				recs[1].Metadata["service_reminded"] = true
+			}
+			storage := mocks.NewMockStorage(ctrl)
+			storage.EXPECT().List(gomock.Any(), records.RecordTypeCar).Return(recs, nil)
+			notifier := remindermocks.NewMockNotifier(ctrl)
+			if tt.wantDue > 0 {
+				notifier.EXPECT().Notify(gomock.Any(), gomock.Len(tt.wantDue)).Return(nil)
+				storage.EXPECT().Update(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, rec records.Record) error {
+					assert.Equal(t, "oil", rec.ID, "Run() should mark the last service as reminded")
+					return nil
+				})
+			}
+			tracker := vehicles.NewRecordTracker(storage, config, notifier)
+
+			// Act
+			due, err := tracker.Run(context.Background(), now)
+
+			// Assert
+			require.NoError(t, err, "Run() error should be nil")
+			require.Len(t, due, tt.wantDue, "Run() should only remind services due within the lead time once")
+			if tt.wantDue > 0 {
+				assert.Equal(t, "oil change of VW Golf (BAB123)", due[0].Title, "Run() should name the service and vehicle")
+			}
+		})
+	}
+}
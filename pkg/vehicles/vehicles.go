@@ -0,0 +1,59 @@
+// Package vehicles groups car records per vehicle into a maintenance history
+// and reminds about services coming due by time or mileage.
+package vehicles
+
+import (
+	"context"
+	"time"
+
+	"github.com/kazemisoroush/assistant/pkg/reminders"
+)
+
+// Service represents one service or repair of a vehicle
+type Service struct {
+	RecordID string  `json:"record_id"`
+	Date     string  `json:"date"` // YYYY-MM-DD
+	Type     string  `json:"type"`
+	Odometer float64 `json:"odometer,omitempty"`
+	Cost     float64 `json:"cost,omitempty"`
+	Currency string  `json:"currency,omitempty"`
+}
+
+// Due represents the next service of one type
+type Due struct {
+	ServiceType  string  `json:"service_type"`
+	LastRecordID string  `json:"last_record_id"` // Record of the previous service of the type
+	LastDate     string  `json:"last_date"`
+	DueDate      string  `json:"due_date"`               // YYYY-MM-DD, estimated from mileage when that comes first
+	DueOdometer  float64 `json:"due_odometer,omitempty"` // 0 when the interval has no distance
+	DaysLeft     int     `json:"days_left"`              // Negative once overdue
+}
+
+// Vehicle represents one car with its maintenance history
+type Vehicle struct {
+	ID       string             `json:"id"` // VIN, or license plate when no VIN was extracted
+	VIN      string             `json:"vin,omitempty"`
+	Plate    string             `json:"plate,omitempty"`
+	Name     string             `json:"name,omitempty"`     // Make and model
+	Odometer float64            `json:"odometer,omitempty"` // Latest reading
+	Services []Service          `json:"services"`           // Oldest first
+	Spend    map[string]float64 `json:"spend"`              // Service costs per currency
+	Upcoming []Due              `json:"upcoming"`           // Soonest first
+}
+
+// Interval represents how often a type of service is due; zero fields do not apply
+type Interval struct {
+	Months   int
+	Distance float64 // In the odometer unit of the documents
+}
+
+// Tracker reports vehicle maintenance
+//
+//go:generate mockgen -destination=./mocks/mock_tracker.go -mock_names=Tracker=MockTracker -package=mocks . Tracker
+type Tracker interface {
+	// Vehicles returns every vehicle with its services and the services coming due as of now
+	Vehicles(ctx context.Context, now time.Time) ([]Vehicle, error)
+
+	// Run sends a reminder for every service due within the lead time not reminded yet and returns them
+	Run(ctx context.Context, now time.Time) ([]reminders.Reminder, error)
+}
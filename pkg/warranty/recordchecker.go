@@ -0,0 +1,81 @@
+package warranty
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/kazemisoroush/assistant/pkg/records"
+	"github.com/kazemisoroush/assistant/pkg/records/storage"
+)
+
+// RecordChecker checks warranty records kept in storage
+type RecordChecker struct {
+	storage storage.Storage
+}
+
+// NewRecordChecker creates a new warranty checker
+func NewRecordChecker(storage storage.Storage) Checker {
+	return &RecordChecker{
+		storage: storage,
+	}
+}
+
+// Check implements Checker. An empty query matches every warranty.
+func (c *RecordChecker) Check(ctx context.Context, query string, now time.Time) ([]Status, error) {
+	recs, err := c.storage.List(ctx, records.RecordTypeWarranty)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list warranties: %w", err)
+	}
+
+	terms := strings.Fields(strings.ToLower(query))
+	var statuses []Status
+	for _, rec := range recs {
+		if rec.ExpiresAt == nil {
+			continue
+		}
+		status := toStatus(rec, now)
+		if !matches(status, rec.Content, terms) {
+			continue
+		}
+		statuses = append(statuses, status)
+	}
+
+	sort.SliceStable(statuses, func(i, j int) bool { return statuses[i].ExpiresAt.Before(statuses[j].ExpiresAt) })
+	return statuses, nil
+}
+
+// toStatus describes the warranty record at the given time
+func toStatus(rec records.Record, now time.Time) Status {
+	product, _ := rec.Metadata["product"].(string)
+	if product == "" {
+		product, _ = rec.Metadata["document_type"].(string)
+	}
+	merchant, _ := rec.Metadata["merchant"].(string)
+
+	status := Status{
+		RecordID:  rec.ID,
+		Product:   product,
+		Merchant:  merchant,
+		ExpiresAt: *rec.ExpiresAt,
+		DaysLeft:  int(rec.ExpiresAt.Sub(now).Hours() / 24),
+		Active:    !rec.ExpiresAt.Before(now),
+	}
+	if links := rec.Links(); len(links) > 0 {
+		status.PurchaseRecordID = links[0]
+	}
+	return status
+}
+
+// matches reports whether every query term appears in the product, merchant or content
+func matches(status Status, content string, terms []string) bool {
+	haystack := strings.ToLower(strings.Join([]string{status.Product, status.Merchant, content}, " "))
+	for _, term := range terms {
+		if !strings.Contains(haystack, term) {
+			return false
+		}
+	}
+	return true
+}
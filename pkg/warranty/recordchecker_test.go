@@ -0,0 +1,40 @@
+package warranty_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/kazemisoroush/assistant/pkg/records"
+	"github.com/kazemisoroush/assistant/pkg/records/storage/mocks"
+	"github.com/kazemisoroush/assistant/pkg/warranty"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestRecordChecker_Check(t *testing.T) {
+	// Arrange
+	now := time.Date(2025, time.June, 1, 0, 0, 0, 0, time.UTC)
+	laptopExpiry := now.AddDate(0, 0, 30)
+	phoneExpiry := now.AddDate(0, 0, -10)
+	ctrl := gomock.NewController(t)
+	storage := mocks.NewMockStorage(ctrl)
+	storage.EXPECT().List(gomock.Any(), records.RecordTypeWarranty).Return([]records.Record{
+		{ID: "warranty-1", Type: records.RecordTypeWarranty, ExpiresAt: &laptopExpiry, Metadata: map[string]interface{}{
+			"product": "Laptop", "merchant": "Media Markt", records.MetaLinks: []interface{}{"ocr-1"},
+		}},
+		{ID: "warranty-2", Type: records.RecordTypeWarranty, ExpiresAt: &phoneExpiry, Metadata: map[string]interface{}{"product": "Phone"}},
+	}, nil)
+	checker := warranty.NewRecordChecker(storage)
+
+	// Act
+	statuses, err := checker.Check(context.Background(), "laptop", now)
+
+	// Assert
+	require.NoError(t, err, "Check() error should be nil")
+	require.Len(t, statuses, 1, "Check() should only return matching warranties")
+	assert.True(t, statuses[0].Active, "Check() should report an unexpired warranty as active")
+	assert.Equal(t, 30, statuses[0].DaysLeft, "Check() should count the days left")
+	assert.Equal(t, "ocr-1", statuses[0].PurchaseRecordID, "Check() should return the linked purchase")
+}
@@ -0,0 +1,26 @@
+// Package warranty answers whether purchases are still under warranty.
+package warranty
+
+import (
+	"context"
+	"time"
+)
+
+// Status represents the warranty of one product
+type Status struct {
+	RecordID         string    `json:"record_id"`
+	PurchaseRecordID string    `json:"purchase_record_id,omitempty"` // The receipt the warranty was derived from
+	Product          string    `json:"product"`
+	Merchant         string    `json:"merchant,omitempty"`
+	ExpiresAt        time.Time `json:"expires_at"`
+	DaysLeft         int       `json:"days_left"` // Negative once the warranty has ended
+	Active           bool      `json:"active"`
+}
+
+// Checker looks up warranties
+//
+//go:generate mockgen -destination=./mocks/mock_checker.go -mock_names=Checker=MockChecker -package=mocks . Checker
+type Checker interface {
+	// Check returns the warranties whose product or merchant matches the query, soonest expiry first
+	Check(ctx context.Context, query string, now time.Time) ([]Status, error)
+}
@@ -0,0 +1,172 @@
+package webdav
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+)
+
+// propfindBody requests the properties entries are listed with
+const propfindBody = `<?xml version="1.0" encoding="utf-8"?>
+<d:propfind xmlns:d="DAV:"><d:prop><d:resourcetype/><d:getetag/><d:getcontenttype/></d:prop></d:propfind>`
+
+// Config represents the server files are read from. Requests authenticate
+// with the bearer token when set, or else with the username and password.
+type Config struct {
+	URL      string // Collection listed when no href is given
+	Username string
+	Password string
+	Token    string
+}
+
+// HTTPClient calls a WebDAV server over HTTP
+type HTTPClient struct {
+	client *http.Client
+	cfg    Config
+}
+
+// NewHTTPClient creates a client calling the server with the given HTTP client
+func NewHTTPClient(client *http.Client, cfg Config) Client {
+	return &HTTPClient{
+		client: client,
+		cfg:    cfg,
+	}
+}
+
+// multistatus is the body of a PROPFIND response
+type multistatus struct {
+	Responses []struct {
+		Href      string     `xml:"DAV: href"`
+		Propstats []propstat `xml:"DAV: propstat"`
+	} `xml:"DAV: response"`
+}
+
+// propstat holds the properties of an entry sharing a status
+type propstat struct {
+	Status string `xml:"DAV: status"`
+	Prop   struct {
+		ResourceType struct {
+			Collection *struct{} `xml:"DAV: collection"`
+		} `xml:"DAV: resourcetype"`
+		ETag        string `xml:"DAV: getetag"`
+		ContentType string `xml:"DAV: getcontenttype"`
+	} `xml:"DAV: prop"`
+}
+
+// List implements Client
+func (c *HTTPClient) List(ctx context.Context, href string) ([]Entry, error) {
+	target, err := c.resolve(href)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.do(ctx, "PROPFIND", target, strings.NewReader(propfindBody), map[string]string{
+		"Depth":        "1",
+		"Content-Type": "application/xml; charset=utf-8",
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusMultiStatus {
+		return nil, fmt.Errorf("webdav PROPFIND %s failed with status %d", target.Path, resp.StatusCode)
+	}
+
+	var status multistatus
+	if err := xml.NewDecoder(resp.Body).Decode(&status); err != nil {
+		return nil, fmt.Errorf("failed to decode webdav PROPFIND %s response: %w", target.Path, err)
+	}
+	entries := make([]Entry, 0, len(status.Responses))
+	for _, r := range status.Responses {
+		entry := newEntry(r.Href, r.Propstats)
+		// The collection itself is listed first
+		if href, err := target.Parse(r.Href); err == nil && path.Clean(href.Path) == path.Clean(target.Path) {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// newEntry returns the entry of a PROPFIND response, reading the properties
+// the server found
+func newEntry(href string, propstats []propstat) Entry {
+	entry := Entry{Href: href}
+	for _, propstat := range propstats {
+		if !strings.Contains(propstat.Status, " 200 ") {
+			continue
+		}
+		entry.ETag = strings.Trim(propstat.Prop.ETag, `"`)
+		entry.ContentType = propstat.Prop.ContentType
+		entry.Collection = propstat.Prop.ResourceType.Collection != nil
+	}
+	name, err := url.PathUnescape(path.Base(href))
+	if err != nil {
+		name = path.Base(href)
+	}
+	entry.Name = name
+	return entry
+}
+
+// Get implements Client
+func (c *HTTPClient) Get(ctx context.Context, href string) (io.ReadCloser, error) {
+	target, err := c.resolve(href)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.do(ctx, http.MethodGet, target, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		_ = resp.Body.Close()
+		return nil, fmt.Errorf("webdav GET %s failed with status %d", target.Path, resp.StatusCode)
+	}
+	return resp.Body, nil
+}
+
+// resolve returns the URL of an href, which is the base URL's collection when empty
+func (c *HTTPClient) resolve(href string) (*url.URL, error) {
+	base, err := url.Parse(c.cfg.URL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse webdav URL: %w", err)
+	}
+	if !strings.HasSuffix(base.Path, "/") {
+		base.Path += "/"
+	}
+	target, err := base.Parse(href)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse webdav href %s: %w", href, err)
+	}
+	return target, nil
+}
+
+// do sends an authenticated request
+func (c *HTTPClient) do(ctx context.Context, method string, target *url.URL, body io.Reader, headers map[string]string) (*http.Response, error) {
+	if body == nil {
+		body = bytes.NewReader(nil)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, target.String(), body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create webdav request: %w", err)
+	}
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+	if c.cfg.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.cfg.Token)
+	} else if c.cfg.Username != "" {
+		req.SetBasicAuth(c.cfg.Username, c.cfg.Password)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call webdav %s %s: %w", method, target.Path, err)
+	}
+	return resp, nil
+}
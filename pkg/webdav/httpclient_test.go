@@ -0,0 +1,95 @@
+package webdav_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kazemisoroush/assistant/pkg/webdav"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// listing is a Nextcloud PROPFIND response for a folder holding a subfolder and a scan
+const listing = `<?xml version="1.0"?>
+<d:multistatus xmlns:d="DAV:" xmlns:oc="http://owncloud.org/ns">
+ <d:response>
+  <d:href>/dav/files/alice/Scans/</d:href>
+  <d:propstat><d:prop><d:resourcetype><d:collection/></d:resourcetype><d:getetag>"root"</d:getetag></d:prop><d:status>HTTP/1.1 200 OK</d:status></d:propstat>
+  <d:propstat><d:prop><d:getcontenttype/></d:prop><d:status>HTTP/1.1 404 Not Found</d:status></d:propstat>
+ </d:response>
+ <d:response>
+  <d:href>/dav/files/alice/Scans/2024/</d:href>
+  <d:propstat><d:prop><d:resourcetype><d:collection/></d:resourcetype><d:getetag>"sub"</d:getetag></d:prop><d:status>HTTP/1.1 200 OK</d:status></d:propstat>
+ </d:response>
+ <d:response>
+  <d:href>/dav/files/alice/Scans/tax%20return.pdf</d:href>
+  <d:propstat><d:prop><d:resourcetype/><d:getetag>"abc123"</d:getetag><d:getcontenttype>application/pdf</d:getcontenttype></d:prop><d:status>HTTP/1.1 200 OK</d:status></d:propstat>
+ </d:response>
+</d:multistatus>`
+
+func TestHTTPClient_List(t *testing.T) {
+	// Arrange
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "PROPFIND", r.Method, "List() should send a PROPFIND")
+		assert.Equal(t, "/dav/files/alice/Scans/", r.URL.Path, "List() should list the base collection")
+		assert.Equal(t, "1", r.Header.Get("Depth"), "List() should list one level")
+		username, password, ok := r.BasicAuth()
+		assert.True(t, ok, "List() should authenticate with basic auth")
+		assert.Equal(t, "alice", username, "List() should send the username")
+		assert.Equal(t, "app-password", password, "List() should send the password")
+		w.WriteHeader(http.StatusMultiStatus)
+		_, _ = io.WriteString(w, listing)
+	}))
+	defer server.Close()
+	client := webdav.NewHTTPClient(server.Client(), webdav.Config{URL: server.URL + "/dav/files/alice/Scans", Username: "alice", Password: "app-password"})
+
+	// Act
+	entries, err := client.List(context.Background(), "")
+
+	// Assert
+	require.NoError(t, err, "List() should succeed")
+	assert.Equal(t, []webdav.Entry{
+		{Href: "/dav/files/alice/Scans/2024/", Name: "2024", ETag: "sub", Collection: true},
+		{Href: "/dav/files/alice/Scans/tax%20return.pdf", Name: "tax return.pdf", ETag: "abc123", ContentType: "application/pdf"},
+	}, entries, "List() should return the entries without the collection itself")
+}
+
+func TestHTTPClient_Get(t *testing.T) {
+	// Arrange
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/dav/files/alice/Scans/tax return.pdf", r.URL.Path, "Get() should request the file")
+		assert.Equal(t, "Bearer TOKEN", r.Header.Get("Authorization"), "Get() should authenticate with the token")
+		_, _ = io.WriteString(w, "%PDF-1.7")
+	}))
+	defer server.Close()
+	client := webdav.NewHTTPClient(server.Client(), webdav.Config{URL: server.URL + "/dav/files/alice/Scans/", Token: "TOKEN"})
+
+	// Act
+	content, err := client.Get(context.Background(), "/dav/files/alice/Scans/tax%20return.pdf")
+
+	// Assert
+	require.NoError(t, err, "Get() should succeed")
+	defer func() { _ = content.Close() }()
+	data, err := io.ReadAll(content)
+	require.NoError(t, err, "ReadAll() error should be nil")
+	assert.Equal(t, "%PDF-1.7", string(data), "Get() should return the content")
+}
+
+func TestHTTPClient_List_Unauthorized(t *testing.T) {
+	// Arrange
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+	client := webdav.NewHTTPClient(server.Client(), webdav.Config{URL: server.URL})
+
+	// Act
+	_, err := client.List(context.Background(), "")
+
+	// Assert
+	require.Error(t, err, "List() should fail when the server rejects the credentials")
+	assert.Contains(t, err.Error(), "status 401", "List() should report the status")
+}
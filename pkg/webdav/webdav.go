@@ -0,0 +1,29 @@
+// Package webdav lists and reads the files of a WebDAV server, such as a
+// Nextcloud instance.
+package webdav
+
+import (
+	"context"
+	"io"
+)
+
+// Client calls a WebDAV server
+//
+//go:generate mockgen -destination=./mocks/mock_client.go -mock_names=Client=MockClient -package=mocks . Client
+type Client interface {
+	// List returns the entries of a collection, or of the base URL's
+	// collection when href is empty. The collection itself is not listed.
+	List(ctx context.Context, href string) ([]Entry, error)
+
+	// Get returns the content of a file
+	Get(ctx context.Context, href string) (io.ReadCloser, error)
+}
+
+// Entry represents a file or collection listed by a WebDAV server
+type Entry struct {
+	Href        string // Path on the server, escaped as the server sent it
+	Name        string
+	ETag        string // Changes whenever the file changes
+	ContentType string
+	Collection  bool
+}